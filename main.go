@@ -2,33 +2,149 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/azure"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/flux"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/health"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/notify"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/placement"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/pricing"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/registry"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/tools"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
 )
 
 func main() {
 	// All logging MUST go to stderr — stdout is reserved for MCP JSON-RPC
 	log.SetOutput(os.Stderr)
 
+	var enableExec bool
+	flag.BoolVar(&enableExec, "enable-exec", false, "Enable exec_in_pod and active checks that exec commands inside pods")
+	flag.BoolVar(&enableExec, "allow-exec", false, "Alias for --enable-exec")
+	enableWrite := flag.Bool("enable-write", false, "Enable remediation tools that change cluster state (restart_deployment, scale_deployment, delete_pod, cordon_node, uncordon_node)")
+	priceFile := flag.String("price-file", "", "JSON file mapping node instance types to hourly prices for estimate_cost_waste")
+	placementFile := flag.String("placement-policy-file", "", "JSON file of rules mapping priority classes and namespaces to allowed node pools for check_placement_policy")
+	watchInterval := flag.Duration("watch-interval", 0, "Run background health sweeps at this interval (e.g. 5m) and send new CRITICAL findings to MCP clients as log messages; 0 disables")
+	notifyWebhook := flag.String("notify-webhook", "", "Webhook URL that receives new CRITICAL findings from background sweeps")
+	notifyFormat := flag.String("notify-format", "", "Webhook payload format: slack, teams, or generic (default: detected from the URL)")
+	kubeconfig := flag.String("kubeconfig", "", "Path to kubeconfig file(s) (default: $KUBECONFIG or ~/.kube/config)")
+	kubeContext := flag.String("context", "", "Kubeconfig context to use (default: current-context)")
+	proxyURL := flag.String("proxy-url", "", "Connect through an existing kubectl proxy (e.g. http://127.0.0.1:8001) instead of kubeconfig credentials")
+	httpAddr := flag.String("http-addr", "", "Serve MCP over streamable HTTP on this address (e.g. :8080) instead of stdio, with /healthz and /readyz probes")
+	namespaces := flag.String("namespaces", "", "Comma-separated namespaces the server has access to; enables namespace-scoped mode where cluster-scope checks are skipped instead of failing")
+	prometheusURL := flag.String("prometheus-url", "", "Prometheus API URL (e.g. http://prometheus.monitoring:9090) for query_usage_history and p95 usage in resource analysis")
+	resourcePollInterval := flag.Duration("resource-poll-interval", 30*time.Second, "How often subscribed MCP resources (k8s:// URIs) are re-read to detect changes")
+	registryLookup := flag.Bool("registry-lookup", false, "Let check_image_freshness query container registries anonymously for image build dates and upstream tags")
+	azureAppGW := flag.Bool("azure-appgw", false, "Register check_appgw_backends, which reads Application Gateway backend health from Azure (credentials from AZURE_* env vars or managed identity)")
+	suppressFile := flag.String("suppress-file", "", "JSON file of suppression rules that hide accepted findings by rule ID, namespace, and message match")
+	suppress := flag.String("suppress", "", "Comma-separated finding rule IDs to hide, optionally per namespace (e.g. KD-RES-001@kube-system,KD-NET-001)")
+	namespaceAllowlist := flag.String("namespace-allowlist", "", "Comma-separated namespaces every tool is restricted to; other namespaces and cluster-scoped reads are rejected")
+	noRedact := flag.Bool("no-redact", false, "Show credential-like env var, annotation, and ConfigMap values in tool output instead of masking them; only for trusted use")
+	flag.Parse()
+
+	if *noRedact {
+		util.SetRedaction(false)
+		log.Printf("Output redaction disabled: credential-like values are shown in tool output")
+	}
+
+	var priceTable *pricing.Table
+	if *priceFile != "" {
+		pt, err := pricing.LoadFile(*priceFile)
+		if err != nil {
+			log.Fatalf("Failed to load price file: %v", err)
+		}
+		priceTable = pt
+	}
+
+	var placementPolicy *placement.Policy
+	if *placementFile != "" {
+		pp, err := placement.LoadFile(*placementFile)
+		if err != nil {
+			log.Fatalf("Failed to load placement policy: %v", err)
+		}
+		placementPolicy = pp
+	}
+
+	var suppressions *findings.Suppressions
+	if *suppressFile != "" {
+		s, err := findings.LoadSuppressions(*suppressFile)
+		if err != nil {
+			log.Fatalf("Failed to load suppressions: %v", err)
+		}
+		suppressions = s
+	}
+	for _, entry := range util.SplitList(*suppress) {
+		sup, err := findings.ParseSuppression(entry)
+		if err != nil {
+			log.Fatalf("Invalid suppression: %v", err)
+		}
+		if suppressions == nil {
+			suppressions = &findings.Suppressions{}
+		}
+		suppressions.Add(sup)
+	}
+	if suppressions != nil {
+		log.Printf("Suppressing findings for %d rule(s)", len(suppressions.Rules))
+	}
+
+	var appGateway *azure.AppGatewayClient
+	if *azureAppGW {
+		cred := azure.CredentialFromEnv()
+		appGateway = azure.NewAppGatewayClient(cred)
+		log.Printf("Azure Application Gateway checks enabled (%s credential)", cred.Kind())
+	}
+
+	var imageRegistry *registry.Client
+	if *registryLookup {
+		imageRegistry = registry.NewClient()
+		log.Printf("Container registry lookups enabled for check_image_freshness")
+	}
+
+	clientOpts := k8s.ClientOptions{
+		Kubeconfig:    *kubeconfig,
+		Context:       *kubeContext,
+		ProxyURL:      *proxyURL,
+		Namespaces:    util.SplitList(*namespaces),
+		PrometheusURL: *prometheusURL,
+	}
+	allowlist := util.SplitList(*namespaceAllowlist)
+	if len(allowlist) > 0 {
+		if len(clientOpts.Namespaces) > 0 {
+			log.Fatalf("--namespaces and --namespace-allowlist are mutually exclusive")
+		}
+		clientOpts.Namespaces = allowlist
+		clientOpts.EnforceNamespaces = true
+		log.Printf("Namespace allowlist active: %s", *namespaceAllowlist)
+	}
+
 	// Initialize the default Kubernetes client
-	client, err := k8s.NewClusterClient("")
+	client, err := k8s.NewClusterClientWithOptions(clientOpts)
 	if err != nil {
 		log.Fatalf("Failed to initialize Kubernetes client: %v", err)
 	}
 
-	// Create MCP server
+	// Create MCP server; resource subscriptions are served by polling
+	watcher := tools.NewResourceWatcher(client, *resourcePollInterval)
 	server := mcp.NewServer(
 		&mcp.Implementation{
 			Name:    "kube-doctor",
 			Version: "0.1.0",
 		},
-		nil,
+		&mcp.ServerOptions{
+			SubscribeHandler:   watcher.Subscribe,
+			UnsubscribeHandler: watcher.Unsubscribe,
+		},
 	)
+	watcher.Attach(server)
 
 	// Initialize the Flux client (optional — Flux CRDs may not be installed)
 	var fluxClient *flux.FluxClient
@@ -42,12 +158,69 @@ func main() {
 	}
 
 	// Register all tools
-	tools.RegisterAll(server, client, fluxClient)
+	tools.RegisterAll(server, client, fluxClient, tools.Options{
+		EnableExec:         enableExec,
+		EnableWrite:        *enableWrite,
+		PriceTable:         priceTable,
+		NamespaceAllowlist: allowlist,
+		PlacementPolicy:    placementPolicy,
+		AppGateway:         appGateway,
+		Registry:           imageRegistry,
+		Suppressions:       suppressions,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Start background sweeps (optional). New CRITICAL findings go to
+	// connected MCP clients as log messages and to the webhook, if any.
+	if *notifyWebhook != "" && *watchInterval <= 0 {
+		log.Fatalf("--notify-webhook requires --watch-interval")
+	}
+	if *watchInterval > 0 {
+		notifiers := notify.Multi{notify.NewMCPLogger(server)}
+		target := "MCP clients"
+		if *notifyWebhook != "" {
+			webhook, err := notify.NewWebhook(*notifyWebhook, *notifyFormat)
+			if err != nil {
+				log.Fatalf("Invalid notification webhook: %v", err)
+			}
+			notifiers = append(notifiers, webhook)
+			target += fmt.Sprintf(" and %s webhook", webhook.Format)
+		}
+		go tools.RunWatchdog(ctx, client, *watchInterval, notifiers)
+		log.Printf("Background sweeps every %s, notifying %s", *watchInterval, target)
+	}
+
+	if *httpAddr != "" {
+		serveHTTP(ctx, *httpAddr, server, client)
+		return
+	}
 
 	log.Println("kube-doctor MCP server starting on stdio...")
 
 	// Run on stdio transport
-	if err := server.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
+	if err := server.Run(ctx, &mcp.StdioTransport{}); err != nil {
+		log.Fatalf("Server error: %v", err)
+	}
+}
+
+// readinessInterval is how often the HTTP transport re-checks API server reachability.
+const readinessInterval = 30 * time.Second
+
+// serveHTTP serves MCP over streamable HTTP at /mcp alongside /healthz and
+// /readyz. The API server connection is warmed up in the background so
+// /readyz reports a broken kubeconfig before any client connects.
+func serveHTTP(ctx context.Context, addr string, server *mcp.Server, client *k8s.ClusterClient) {
+	checker := health.NewChecker(client.Ping, readinessInterval)
+	go checker.Run(ctx)
+
+	mux := http.NewServeMux()
+	checker.Register(mux)
+	mux.Handle("/mcp", mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+
+	log.Printf("kube-doctor MCP server listening on %s (MCP at /mcp, probes at /healthz and /readyz)", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
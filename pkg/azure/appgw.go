@@ -0,0 +1,289 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ResourceManagerURL is the Azure Resource Manager endpoint for the public cloud.
+const ResourceManagerURL = "https://management.azure.com"
+
+// appGatewayAPIVersion is the Microsoft.Network API version used for
+// Application Gateway reads.
+const appGatewayAPIVersion = "2023-09-01"
+
+// AppGatewayID identifies an Application Gateway in Azure Resource Manager.
+type AppGatewayID struct {
+	SubscriptionID string
+	ResourceGroup  string
+	Name           string
+}
+
+// ParseAppGatewayID parses an ARM resource ID of the form
+// /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.Network/applicationGateways/{name}.
+func ParseAppGatewayID(id string) (AppGatewayID, error) {
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	if len(parts) != 8 || !strings.EqualFold(parts[0], "subscriptions") || !strings.EqualFold(parts[2], "resourceGroups") ||
+		!strings.EqualFold(parts[4], "providers") || !strings.EqualFold(parts[5], "Microsoft.Network") ||
+		!strings.EqualFold(parts[6], "applicationGateways") {
+		return AppGatewayID{}, fmt.Errorf("%q is not an Application Gateway resource ID", id)
+	}
+	return AppGatewayID{SubscriptionID: parts[1], ResourceGroup: parts[3], Name: parts[7]}, nil
+}
+
+func (id AppGatewayID) String() string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/applicationGateways/%s",
+		id.SubscriptionID, id.ResourceGroup, id.Name)
+}
+
+// AppGateway is the part of an Application Gateway's configuration relevant
+// to routing traffic into a cluster.
+type AppGateway struct {
+	Name              string
+	OperationalState  string
+	ProvisioningState string
+	Listeners         []Listener
+	BackendPools      []BackendPool
+}
+
+// Listener is an HTTP(S) listener. An empty HostNames matches every host.
+type Listener struct {
+	Name      string
+	Protocol  string
+	Port      int32
+	HostNames []string
+}
+
+// BackendPool is a backend address pool; AGIC fills it with pod IPs.
+type BackendPool struct {
+	Name      string
+	Addresses []string
+}
+
+// BackendHealth is the result of the gateway's backend health check, one
+// entry per pool and backend settings pair.
+type BackendHealth struct {
+	Pools []PoolHealth
+}
+
+// PoolHealth is the probe status of every server in a pool under one backend setting.
+type PoolHealth struct {
+	Pool     string
+	Settings string
+	Servers  []ServerHealth
+}
+
+// ServerHealth is the probe status of one backend address.
+type ServerHealth struct {
+	Address string
+	// Health is Healthy, Unhealthy, Partial, Draining, or Unknown.
+	Health   string
+	ProbeLog string
+}
+
+// AppGatewayClient reads Application Gateway configuration and backend
+// health from Azure Resource Manager.
+type AppGatewayClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Credential Credential
+	// PollInterval is how often the asynchronous backend health operation is polled.
+	PollInterval time.Duration
+}
+
+// NewAppGatewayClient returns a client for the public-cloud Resource Manager endpoint.
+func NewAppGatewayClient(cred Credential) *AppGatewayClient {
+	return &AppGatewayClient{
+		BaseURL:      ResourceManagerURL,
+		HTTPClient:   &http.Client{Timeout: 30 * time.Second},
+		Credential:   cred,
+		PollInterval: 2 * time.Second,
+	}
+}
+
+type armRef struct {
+	ID string `json:"id"`
+}
+
+type armAppGateway struct {
+	Name       string `json:"name"`
+	Properties struct {
+		OperationalState  string `json:"operationalState"`
+		ProvisioningState string `json:"provisioningState"`
+		FrontendPorts     []struct {
+			ID         string `json:"id"`
+			Properties struct {
+				Port int32 `json:"port"`
+			} `json:"properties"`
+		} `json:"frontendPorts"`
+		HTTPListeners []struct {
+			Name       string `json:"name"`
+			Properties struct {
+				Protocol     string   `json:"protocol"`
+				HostName     string   `json:"hostName"`
+				HostNames    []string `json:"hostNames"`
+				FrontendPort armRef   `json:"frontendPort"`
+			} `json:"properties"`
+		} `json:"httpListeners"`
+		BackendAddressPools []struct {
+			Name       string `json:"name"`
+			Properties struct {
+				BackendAddresses []struct {
+					IPAddress string `json:"ipAddress"`
+					FQDN      string `json:"fqdn"`
+				} `json:"backendAddresses"`
+			} `json:"properties"`
+		} `json:"backendAddressPools"`
+	} `json:"properties"`
+}
+
+type armBackendHealth struct {
+	BackendAddressPools []struct {
+		BackendAddressPool            armRef `json:"backendAddressPool"`
+		BackendHTTPSettingsCollection []struct {
+			BackendHTTPSettings armRef `json:"backendHttpSettings"`
+			Servers             []struct {
+				Address        string `json:"address"`
+				Health         string `json:"health"`
+				HealthProbeLog string `json:"healthProbeLog"`
+			} `json:"servers"`
+		} `json:"backendHttpSettingsCollection"`
+	} `json:"backendAddressPools"`
+}
+
+// Get returns the gateway's listeners and backend pools.
+func (c *AppGatewayClient) Get(ctx context.Context, id AppGatewayID) (*AppGateway, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.BaseURL+id.String()+"?api-version="+appGatewayAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, armError(resp, "reading Application Gateway "+id.Name)
+	}
+	var raw armAppGateway
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decoding Application Gateway %s: %w", id.Name, err)
+	}
+
+	ports := make(map[string]int32)
+	for _, p := range raw.Properties.FrontendPorts {
+		ports[strings.ToLower(p.ID)] = p.Properties.Port
+	}
+	gw := &AppGateway{
+		Name:              raw.Name,
+		OperationalState:  raw.Properties.OperationalState,
+		ProvisioningState: raw.Properties.ProvisioningState,
+	}
+	for _, l := range raw.Properties.HTTPListeners {
+		hosts := l.Properties.HostNames
+		if len(hosts) == 0 && l.Properties.HostName != "" {
+			hosts = []string{l.Properties.HostName}
+		}
+		gw.Listeners = append(gw.Listeners, Listener{
+			Name: l.Name, Protocol: l.Properties.Protocol, HostNames: hosts,
+			Port: ports[strings.ToLower(l.Properties.FrontendPort.ID)],
+		})
+	}
+	for _, p := range raw.Properties.BackendAddressPools {
+		pool := BackendPool{Name: p.Name}
+		for _, a := range p.Properties.BackendAddresses {
+			if a.IPAddress != "" {
+				pool.Addresses = append(pool.Addresses, a.IPAddress)
+			} else if a.FQDN != "" {
+				pool.Addresses = append(pool.Addresses, a.FQDN)
+			}
+		}
+		gw.BackendPools = append(gw.BackendPools, pool)
+	}
+	return gw, nil
+}
+
+// BackendHealth runs the gateway's on-demand backend health check. The ARM
+// operation is asynchronous: the POST returns 202 with a Location header that
+// is polled until it returns the result or ctx expires.
+func (c *AppGatewayClient) BackendHealth(ctx context.Context, id AppGatewayID) (*BackendHealth, error) {
+	resp, err := c.do(ctx, http.MethodPost, c.BaseURL+id.String()+"/backendhealth?api-version="+appGatewayAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	for resp.StatusCode == http.StatusAccepted {
+		location := resp.Header.Get("Location")
+		resp.Body.Close()
+		if location == "" {
+			return nil, fmt.Errorf("backend health for %s was accepted without a Location to poll", id.Name)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for backend health of %s: %w", id.Name, ctx.Err())
+		case <-time.After(c.PollInterval):
+		}
+		if resp, err = c.do(ctx, http.MethodGet, location); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, armError(resp, "reading backend health of "+id.Name)
+	}
+
+	var raw armBackendHealth
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decoding backend health of %s: %w", id.Name, err)
+	}
+	health := &BackendHealth{}
+	for _, p := range raw.BackendAddressPools {
+		for _, s := range p.BackendHTTPSettingsCollection {
+			ph := PoolHealth{Pool: resourceName(p.BackendAddressPool.ID), Settings: resourceName(s.BackendHTTPSettings.ID)}
+			for _, srv := range s.Servers {
+				ph.Servers = append(ph.Servers, ServerHealth{Address: srv.Address, Health: srv.Health, ProbeLog: strings.TrimSpace(srv.HealthProbeLog)})
+			}
+			sort.Slice(ph.Servers, func(i, j int) bool { return ph.Servers[i].Address < ph.Servers[j].Address })
+			health.Pools = append(health.Pools, ph)
+		}
+	}
+	return health, nil
+}
+
+func (c *AppGatewayClient) do(ctx context.Context, method, target string) (*http.Response, error) {
+	token, err := c.Credential.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling Azure Resource Manager: %w", err)
+	}
+	return resp, nil
+}
+
+// armError turns an ARM error response into an error carrying its code and message.
+func armError(resp *http.Response, action string) error {
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Unmarshal(data, &body) == nil && body.Error.Code != "" {
+		return fmt.Errorf("%s: %s (%s): %s", action, resp.Status, body.Error.Code, body.Error.Message)
+	}
+	return fmt.Errorf("%s: %s", action, resp.Status)
+}
+
+// resourceName returns the last segment of an ARM resource ID.
+func resourceName(id string) string {
+	return id[strings.LastIndex(id, "/")+1:]
+}
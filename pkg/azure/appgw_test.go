@@ -0,0 +1,115 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type staticCredential string
+
+func (s staticCredential) Token(context.Context) (string, error) { return string(s), nil }
+func (s staticCredential) Kind() string                          { return "static" }
+
+func TestParseAppGatewayID(t *testing.T) {
+	id, err := ParseAppGatewayID("/subscriptions/sub-1/resourceGroups/rg-net/providers/Microsoft.Network/applicationGateways/appgw-prod")
+	if err != nil {
+		t.Fatalf("ParseAppGatewayID() error = %v", err)
+	}
+	if id.SubscriptionID != "sub-1" || id.ResourceGroup != "rg-net" || id.Name != "appgw-prod" {
+		t.Errorf("ParseAppGatewayID() = %+v", id)
+	}
+	if _, err := ParseAppGatewayID("/subscriptions/sub-1/resourceGroups/rg-net/providers/Microsoft.Network/loadBalancers/lb"); err == nil {
+		t.Error("expected error for a load balancer ID")
+	}
+}
+
+func TestAppGatewayClientGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		if !strings.HasSuffix(r.URL.Path, "/applicationGateways/appgw") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"name": "appgw", "properties": {
+			"operationalState": "Running",
+			"frontendPorts": [{"id": "/x/frontendPorts/port_443", "properties": {"port": 443}}],
+			"httpListeners": [{"name": "fl-shop", "properties": {"protocol": "Https", "hostName": "shop.example.com", "frontendPort": {"id": "/x/frontendPorts/port_443"}}}],
+			"backendAddressPools": [{"name": "pool-shop-web-80", "properties": {"backendAddresses": [{"ipAddress": "10.244.1.5"}, {"fqdn": "web.example.com"}]}}]
+		}}`))
+	}))
+	defer srv.Close()
+
+	c := NewAppGatewayClient(staticCredential("tok"))
+	c.BaseURL = srv.URL
+	gw, err := c.Get(context.Background(), AppGatewayID{SubscriptionID: "s", ResourceGroup: "rg", Name: "appgw"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(gw.Listeners) != 1 || gw.Listeners[0].Port != 443 || gw.Listeners[0].HostNames[0] != "shop.example.com" {
+		t.Errorf("Listeners = %+v", gw.Listeners)
+	}
+	if len(gw.BackendPools) != 1 || len(gw.BackendPools[0].Addresses) != 2 {
+		t.Errorf("BackendPools = %+v", gw.BackendPools)
+	}
+}
+
+func TestAppGatewayClientBackendHealthPolls(t *testing.T) {
+	polls := 0
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/backendhealth"):
+			w.Header().Set("Location", srv.URL+"/operations/1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.URL.Path == "/operations/1":
+			polls++
+			if polls < 2 {
+				w.Header().Set("Location", srv.URL+"/operations/1")
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			w.Write([]byte(`{"backendAddressPools": [{"backendAddressPool": {"id": "/x/backendAddressPools/pool-shop-web-80"},
+				"backendHttpSettingsCollection": [{"backendHttpSettings": {"id": "/x/backendHttpSettingsCollection/bp-shop-web-80"},
+				"servers": [{"address": "10.244.1.6", "health": "Unhealthy", "healthProbeLog": "Received invalid status code: 404 in the backend server's HTTP response. "},
+				            {"address": "10.244.1.5", "health": "Healthy"}]}]}]}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewAppGatewayClient(staticCredential("tok"))
+	c.BaseURL = srv.URL
+	c.PollInterval = time.Millisecond
+	health, err := c.BackendHealth(context.Background(), AppGatewayID{SubscriptionID: "s", ResourceGroup: "rg", Name: "appgw"})
+	if err != nil {
+		t.Fatalf("BackendHealth() error = %v", err)
+	}
+	if len(health.Pools) != 1 || health.Pools[0].Pool != "pool-shop-web-80" || health.Pools[0].Settings != "bp-shop-web-80" {
+		t.Fatalf("Pools = %+v", health.Pools)
+	}
+	servers := health.Pools[0].Servers
+	if len(servers) != 2 || servers[0].Address != "10.244.1.5" || servers[1].Health != "Unhealthy" || strings.HasSuffix(servers[1].ProbeLog, " ") {
+		t.Errorf("Servers = %+v", servers)
+	}
+}
+
+func TestAppGatewayClientARMError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"code": "AuthorizationFailed", "message": "The client does not have authorization"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewAppGatewayClient(staticCredential("tok"))
+	c.BaseURL = srv.URL
+	_, err := c.Get(context.Background(), AppGatewayID{SubscriptionID: "s", ResourceGroup: "rg", Name: "appgw"})
+	if err == nil || !strings.Contains(err.Error(), "AuthorizationFailed") {
+		t.Errorf("Get() error = %v, want AuthorizationFailed", err)
+	}
+}
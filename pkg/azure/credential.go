@@ -0,0 +1,204 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ARMScope is the OAuth scope for Azure Resource Manager tokens.
+const ARMScope = "https://management.azure.com/.default"
+
+// DefaultAuthorityHost is the Microsoft Entra ID endpoint for the public cloud.
+const DefaultAuthorityHost = "https://login.microsoftonline.com/"
+
+// IMDSTokenURL is the managed identity token endpoint of the Azure Instance
+// Metadata Service.
+const IMDSTokenURL = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// Credential returns bearer tokens for Azure Resource Manager.
+type Credential interface {
+	Token(ctx context.Context) (string, error)
+	// Kind names the credential source, e.g. "client secret" or "managed identity".
+	Kind() string
+}
+
+// CredentialFromEnv picks a credential the way the Azure SDK's
+// DefaultAzureCredential does for non-interactive processes:
+//
+//   - AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET: service principal
+//   - AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_FEDERATED_TOKEN_FILE: workload identity
+//   - otherwise: managed identity from IMDS (AZURE_CLIENT_ID selects a user-assigned identity)
+//
+// AZURE_AUTHORITY_HOST overrides the Entra ID endpoint for sovereign clouds.
+func CredentialFromEnv() Credential {
+	tenant := os.Getenv("AZURE_TENANT_ID")
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	authority := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authority == "" {
+		authority = DefaultAuthorityHost
+	}
+	httpClient := &http.Client{Timeout: 15 * time.Second}
+
+	if tenant != "" && clientID != "" {
+		if secret := os.Getenv("AZURE_CLIENT_SECRET"); secret != "" {
+			return &clientCredential{
+				kind: "client secret", tokenURL: entraTokenURL(authority, tenant), clientID: clientID, httpClient: httpClient,
+				form: func() (url.Values, error) { return url.Values{"client_secret": {secret}}, nil },
+			}
+		}
+		if file := os.Getenv("AZURE_FEDERATED_TOKEN_FILE"); file != "" {
+			return &clientCredential{
+				kind: "workload identity", tokenURL: entraTokenURL(authority, tenant), clientID: clientID, httpClient: httpClient,
+				form: func() (url.Values, error) {
+					// The projected token is rotated by the kubelet, so read it on every refresh.
+					assertion, err := os.ReadFile(file)
+					if err != nil {
+						return nil, fmt.Errorf("reading federated token: %w", err)
+					}
+					return url.Values{
+						"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+						"client_assertion":      {strings.TrimSpace(string(assertion))},
+					}, nil
+				},
+			}
+		}
+	}
+	return &ManagedIdentityCredential{Endpoint: IMDSTokenURL, ClientID: clientID, HTTPClient: httpClient}
+}
+
+func entraTokenURL(authority, tenant string) string {
+	return strings.TrimSuffix(authority, "/") + "/" + url.PathEscape(tenant) + "/oauth2/v2.0/token"
+}
+
+// tokenResponse is the token payload shared by Entra ID and IMDS. IMDS sends
+// expires_in as a string, Entra ID as a number.
+type tokenResponse struct {
+	AccessToken string          `json:"access_token"`
+	ExpiresIn   json.RawMessage `json:"expires_in"`
+	Error       string          `json:"error"`
+	Description string          `json:"error_description"`
+}
+
+func (t tokenResponse) lifetime() time.Duration {
+	secs, err := strconv.Atoi(strings.Trim(string(t.ExpiresIn), `"`))
+	if err != nil || secs <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// tokenCache holds a token until shortly before it expires.
+type tokenCache struct {
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func (c *tokenCache) get(ctx context.Context, fetch func(context.Context) (tokenResponse, error)) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Now().Before(c.expires) {
+		return c.token, nil
+	}
+	resp, err := fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token = resp.AccessToken
+	c.expires = time.Now().Add(resp.lifetime() - time.Minute)
+	return c.token, nil
+}
+
+func decodeToken(resp *http.Response, source string) (tokenResponse, error) {
+	var body tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return body, fmt.Errorf("decoding %s token: %w", source, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		if body.Error != "" {
+			return body, fmt.Errorf("%s token request failed: %s: %s", source, body.Error, body.Description)
+		}
+		return body, fmt.Errorf("%s token request returned %s", source, resp.Status)
+	}
+	return body, nil
+}
+
+// clientCredential is the OAuth client-credentials flow against Entra ID,
+// authenticated by a client secret or a federated client assertion.
+type clientCredential struct {
+	kind       string
+	tokenURL   string
+	clientID   string
+	form       func() (url.Values, error)
+	httpClient *http.Client
+	cache      tokenCache
+}
+
+func (c *clientCredential) Kind() string { return c.kind }
+
+func (c *clientCredential) Token(ctx context.Context) (string, error) {
+	return c.cache.get(ctx, func(ctx context.Context) (tokenResponse, error) {
+		form, err := c.form()
+		if err != nil {
+			return tokenResponse{}, err
+		}
+		form.Set("grant_type", "client_credentials")
+		form.Set("client_id", c.clientID)
+		form.Set("scope", ARMScope)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return tokenResponse{}, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return tokenResponse{}, fmt.Errorf("requesting %s token: %w", c.kind, err)
+		}
+		defer resp.Body.Close()
+		return decodeToken(resp, c.kind)
+	})
+}
+
+// ManagedIdentityCredential gets tokens for the VM or node's managed identity
+// from the Azure Instance Metadata Service.
+type ManagedIdentityCredential struct {
+	Endpoint string
+	// ClientID selects a user-assigned identity; empty uses the system-assigned one.
+	ClientID   string
+	HTTPClient *http.Client
+
+	cache tokenCache
+}
+
+func (c *ManagedIdentityCredential) Kind() string { return "managed identity" }
+
+func (c *ManagedIdentityCredential) Token(ctx context.Context) (string, error) {
+	return c.cache.get(ctx, func(ctx context.Context) (tokenResponse, error) {
+		q := url.Values{
+			"api-version": {"2018-02-01"},
+			"resource":    {strings.TrimSuffix(ARMScope, ".default")},
+		}
+		if c.ClientID != "" {
+			q.Set("client_id", c.ClientID)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.Endpoint+"?"+q.Encode(), nil)
+		if err != nil {
+			return tokenResponse{}, err
+		}
+		req.Header.Set("Metadata", "true")
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return tokenResponse{}, fmt.Errorf("requesting managed identity token (is the server running on Azure?): %w", err)
+		}
+		defer resp.Body.Close()
+		return decodeToken(resp, "managed identity")
+	})
+}
@@ -0,0 +1,99 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCredentialFromEnv(t *testing.T) {
+	t.Setenv("AZURE_TENANT_ID", "")
+	t.Setenv("AZURE_CLIENT_ID", "")
+	t.Setenv("AZURE_CLIENT_SECRET", "")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	if kind := CredentialFromEnv().Kind(); kind != "managed identity" {
+		t.Errorf("no env: Kind() = %q, want managed identity", kind)
+	}
+
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_CLIENT_ID", "client")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "/var/run/secrets/azure/tokens/azure-identity-token")
+	if kind := CredentialFromEnv().Kind(); kind != "workload identity" {
+		t.Errorf("federated token file: Kind() = %q, want workload identity", kind)
+	}
+
+	t.Setenv("AZURE_CLIENT_SECRET", "secret")
+	if kind := CredentialFromEnv().Kind(); kind != "client secret" {
+		t.Errorf("client secret: Kind() = %q, want client secret", kind)
+	}
+}
+
+func TestWorkloadIdentityToken(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/tenant/oauth2/v2.0/token" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("client_assertion") != "projected-jwt" || r.PostForm.Get("scope") != ARMScope || r.PostForm.Get("client_id") != "client" {
+			t.Errorf("unexpected form %v", r.PostForm)
+		}
+		w.Write([]byte(`{"access_token": "arm-token", "expires_in": 3599}`))
+	}))
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("projected-jwt\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_CLIENT_ID", "client")
+	t.Setenv("AZURE_CLIENT_SECRET", "")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", tokenFile)
+	t.Setenv("AZURE_AUTHORITY_HOST", srv.URL)
+
+	cred := CredentialFromEnv()
+	for i := 0; i < 2; i++ {
+		tok, err := cred.Token(context.Background())
+		if err != nil || tok != "arm-token" {
+			t.Fatalf("Token() = %q, %v", tok, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the token to be cached, got %d requests", calls)
+	}
+}
+
+func TestManagedIdentityToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" || r.URL.Query().Get("client_id") != "uami" {
+			t.Errorf("unexpected request %s headers %v", r.URL, r.Header)
+		}
+		w.Write([]byte(`{"access_token": "mi-token", "expires_in": "86399"}`))
+	}))
+	defer srv.Close()
+
+	cred := &ManagedIdentityCredential{Endpoint: srv.URL, ClientID: "uami", HTTPClient: srv.Client()}
+	if tok, err := cred.Token(context.Background()); err != nil || tok != "mi-token" {
+		t.Errorf("Token() = %q, %v", tok, err)
+	}
+}
+
+func TestManagedIdentityTokenError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "invalid_request", "error_description": "Identity not found"}`))
+	}))
+	defer srv.Close()
+
+	cred := &ManagedIdentityCredential{Endpoint: srv.URL, HTTPClient: srv.Client()}
+	if _, err := cred.Token(context.Background()); err == nil {
+		t.Error("expected error when IMDS has no identity")
+	}
+}
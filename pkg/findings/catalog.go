@@ -0,0 +1,926 @@
+package findings
+
+import (
+	"sort"
+	"strings"
+)
+
+// Rule describes a diagnostic check, why it matters, and how to fix it.
+type Rule struct {
+	ID          string
+	Title       string
+	Severity    string
+	Category    string
+	Explanation string
+	Remediation string
+	References  []string
+	// Keywords are matched against finding text so that a raw finding line
+	// can be mapped back to its rule.
+	Keywords []string
+}
+
+var catalog = []Rule{
+	// --- Pods ---
+	{
+		ID: "KD-POD-001", Title: "Container in CrashLoopBackOff", Severity: "CRITICAL", Category: "Pod",
+		Explanation: "The container starts and exits repeatedly; the kubelet backs off restarts exponentially (up to 5 minutes). The cause is almost always in the application: bad config, missing dependency, failing startup check, or an OOM kill.",
+		Remediation: "Read the previous container logs (get_pod_logs with previous=true), check the last exit code (1 = app error, 137 = OOMKilled/SIGKILL, 139 = segfault), and verify referenced ConfigMaps/Secrets exist. Fix the root cause, then let the Deployment roll new pods.",
+		References:  []string{"https://kubernetes.io/docs/tasks/debug/debug-application/debug-pods/"},
+		Keywords:    []string{"crashloopbackoff"},
+	},
+	{
+		ID: "KD-POD-002", Title: "Image pull failure", Severity: "CRITICAL", Category: "Pod",
+		Explanation: "The kubelet cannot pull the container image: the tag does not exist, the registry is unreachable, or credentials are missing or expired.",
+		Remediation: "Verify the image name and tag, check imagePullSecrets on the pod or its ServiceAccount, and confirm the node can reach the registry. For private registries, recreate the docker-registry secret.",
+		References:  []string{"https://kubernetes.io/docs/concepts/containers/images/#using-a-private-registry"},
+		Keywords:    []string{"imagepullbackoff", "errimagepull", "invalidimagename"},
+	},
+	{
+		ID: "KD-POD-003", Title: "Container OOMKilled", Severity: "CRITICAL", Category: "Pod",
+		Explanation: "The container exceeded its memory limit and was killed by the kernel OOM killer (exit code 137).",
+		Remediation: "Compare actual usage (get_pod_metrics) with the memory limit. Raise the limit if usage is legitimate, or fix the leak. For JVM/Node runtimes, align heap settings with the container limit.",
+		References:  []string{"https://kubernetes.io/docs/tasks/configure-pod-container/assign-memory-resource/"},
+		Keywords:    []string{"oomkilled", "out of memory"},
+	},
+	{
+		ID: "KD-POD-004", Title: "Pod stuck Pending", Severity: "WARNING", Category: "Pod",
+		Explanation: "The scheduler could not place the pod: insufficient CPU/memory, unsatisfiable node selectors/affinity, taints without tolerations, or an unbound PVC.",
+		Remediation: "Read the FailedScheduling event message, compare requests with node allocatable (analyze_node_capacity), and check PVC binding. Reduce requests, add capacity, or fix selectors/tolerations.",
+		References:  []string{"https://kubernetes.io/docs/concepts/scheduling-eviction/kube-scheduler/"},
+		Keywords:    []string{"pending", "failedscheduling", "unschedulable"},
+	},
+	{
+		ID: "KD-POD-005", Title: "High container restart count", Severity: "WARNING", Category: "Pod",
+		Explanation: "The container has restarted more times than the configured threshold, indicating intermittent crashes or liveness probe failures.",
+		Remediation: "Check whether restarts come from application exits or liveness probe kills (Killing events mentioning 'unhealthy'). Fix the crash or relax the liveness probe.",
+		Keywords:    []string{"restart"},
+	},
+	{
+		ID: "KD-POD-006", Title: "Container configuration error", Severity: "CRITICAL", Category: "Pod",
+		Explanation: "The container cannot be created because a referenced ConfigMap, Secret, or key does not exist, or the container spec is invalid.",
+		Remediation: "Run get_workload_dependencies to list referenced ConfigMaps/Secrets and create the missing ones, or mark the reference optional.",
+		Keywords:    []string{"createcontainerconfigerror", "createcontainererror"},
+	},
+	{
+		ID: "KD-POD-007", Title: "Container waiting to start", Severity: "WARNING", Category: "Pod",
+		Explanation: "The container is in a Waiting state other than CrashLoopBackOff or an image pull error, e.g. ContainerCreating stuck on a volume mount or sandbox setup.",
+		Remediation: "Read the pod's events (get_events) for the mount, sandbox, or init container error holding it back.",
+		Keywords:    []string{"is waiting", "containercreating"},
+	},
+	{
+		ID: "KD-POD-008", Title: "Container exited with an error", Severity: "WARNING", Category: "Pod",
+		Explanation: "The container's last run ended with a non-zero exit code. Exit code 1 is usually an application error, 137 a SIGKILL (often OOM), and 143 a SIGTERM that was not handled in time.",
+		Remediation: "Read the container logs (get_pod_logs, previous=true for restarted containers) around the exit and fix the failing code path or configuration.",
+		Keywords:    []string{"exit code", "terminated with"},
+	},
+	{
+		ID: "KD-POD-009", Title: "Pod not ready", Severity: "WARNING", Category: "Pod",
+		Explanation: "At least one container is failing its readiness probe or has not started, so the pod is removed from Service endpoints.",
+		Remediation: "Check the readiness probe configuration (analyze_probes) and the container logs for why the app does not report ready.",
+		Keywords:    []string{"pod not ready", "containersnotready"},
+	},
+	{
+		ID: "KD-POD-010", Title: "Unhealthy pods", Severity: "CRITICAL", Category: "Pod",
+		Explanation: "Pods are not Running and Ready (or Succeeded); their workloads are degraded or down.",
+		Remediation: "Run diagnose_pod on the listed pods; pods sharing a node or a workload usually share a root cause.",
+		Keywords:    []string{"unhealthy pods"},
+	},
+	{
+		ID: "KD-POD-011", Title: "Init container failing", Severity: "CRITICAL", Category: "Pod",
+		Explanation: "An init container exits non-zero (Init:Error) or crash-loops (Init:CrashLoopBackOff), or a sidecar container keeps crashing. Init containers run in order and must all succeed before any app container starts, so the pod never becomes Ready.",
+		Remediation: "Read the failing init container's logs (get_pod_logs with container set, previous=true if it restarted). Typical causes are a migration or config render failing, a dependency it waits on being unreachable, or a missing ConfigMap/Secret.",
+		References:  []string{"https://kubernetes.io/docs/concepts/workloads/pods/init-containers/"},
+		Keywords:    []string{"init container", "init:error", "init:crashloopbackoff", "sidecar"},
+	},
+	{
+		ID: "KD-POD-012", Title: "Pod stuck initializing", Severity: "WARNING", Category: "Pod",
+		Explanation: "An init container has been running for a long time without completing, so the pod stays in PodInitializing. It is usually waiting for a dependency (database, DNS name, another Service) that never becomes reachable.",
+		Remediation: "Read the blocking init container's logs to see what it waits for, and check that dependency's Service endpoints and NetworkPolicies.",
+		Keywords:    []string{"podinitializing", "stuck initializing"},
+	},
+	{
+		ID: "KD-POD-013", Title: "Pod stuck terminating", Severity: "CRITICAL", Category: "Pod",
+		Explanation: "The pod is past its deletion deadline but still exists. Finalizers that no controller removes, an unreachable node whose kubelet cannot confirm the containers stopped, or a volume that cannot detach keep it in Terminating; StatefulSets will not start its replacement until it is gone.",
+		Remediation: "Check which finalizers remain and whether the controller that owns them is running. For a lost node, confirm the VM is gone before force-deleting the pod (kubectl delete pod --force --grace-period=0).",
+		Keywords:    []string{"terminating", "finalizer"},
+	},
+	{
+		ID: "KD-POD-014", Title: "Finished pods left behind", Severity: "INFO", Category: "Pod",
+		Explanation: "Evicted, Completed, and Failed pods stay in the API until the pod garbage collector removes them, which it only does above --terminated-pod-gc-threshold (12500 by default). They clutter listings and hide the eviction history that explains them.",
+		Remediation: "Read why the pods were evicted first, then delete the leftovers (clean_pod_debris); pods owned by Jobs go away with their Job, so set ttlSecondsAfterFinished on Jobs.",
+		Keywords:    []string{"evicted pods", "completed pods", "pod debris"},
+	},
+
+	// --- Workloads ---
+	{
+		ID: "KD-WL-001", Title: "Deployment replicas unavailable", Severity: "WARNING", Category: "Workload",
+		Explanation: "Fewer replicas are available than desired; the workload is degraded or a rollout is stuck.",
+		Remediation: "Inspect the newest ReplicaSet's pods with diagnose_pod. A rollout exceeding progressDeadlineSeconds usually means the new pods never become Ready.",
+		Keywords:    []string{"unavailable", "replicas"},
+	},
+	{
+		ID: "KD-WL-002", Title: "Single-replica workload", Severity: "WARNING", Category: "Workload",
+		Explanation: "A workload with one replica has no redundancy: node drains, evictions, and rollouts cause downtime.",
+		Remediation: "Run at least two replicas with a PodDisruptionBudget and pod anti-affinity or topology spread.",
+		Keywords:    []string{"single replica", "1 replica"},
+	},
+	{
+		ID: "KD-WL-003", Title: "No disruption protection", Severity: "INFO", Category: "Workload",
+		Explanation: "Without a PodDisruptionBudget a node drain or cluster upgrade can evict every replica at once; a PDB that allows zero disruptions blocks drains entirely.",
+		Remediation: "Add a PodDisruptionBudget with maxUnavailable: 1 (or minAvailable below the replica count) for each multi-replica workload.",
+		References:  []string{"https://kubernetes.io/docs/tasks/run-application/configure-pdb/"},
+		Keywords:    []string{"pdb", "disruption"},
+	},
+	{
+		ID: "KD-WL-004", Title: "Ungraceful shutdown", Severity: "WARNING", Category: "Workload",
+		Explanation: "Endpoints are removed from Services and load balancers asynchronously, after the pod has already received SIGTERM. Without a preStop delay, or with terminationGracePeriodSeconds=0, a serving pod stops accepting connections while still receiving traffic, so every rollout and scale-down drops requests.",
+		Remediation: "Add a preStop hook that waits 5-10s (lifecycle.preStop.sleep.seconds on Kubernetes 1.30+, or exec sleep) so endpoint removal propagates first, handle SIGTERM by draining in-flight requests, and keep terminationGracePeriodSeconds above the preStop delay plus the drain time.",
+		References:  []string{"https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle/#pod-termination"},
+		Keywords:    []string{"prestop", "terminationgraceperiodseconds", "graceful"},
+	},
+	{
+		ID: "KD-WL-005", Title: "Progressive rollout failed", Severity: "CRITICAL", Category: "Workload",
+		Explanation: "An Argo Rollout or Flagger Canary aborted, degraded, or missed its progress deadline, usually because an analysis run failed its success condition; traffic is back on the stable version and the new version is not serving.",
+		Remediation: "Read the failing analysis metrics (diagnose_rollout), fix the regression in the new version, then retry the rollout.",
+		Keywords:    []string{"rollout", "canary", "analysis run"},
+	},
+	{
+		ID: "KD-WL-006", Title: "Progressive rollout paused", Severity: "INFO", Category: "Workload",
+		Explanation: "The rollout is waiting at a pause step, for a manual promotion, for a confirm webhook, or after an inconclusive analysis; it makes no progress until something resumes it.",
+		Remediation: "Check whether the pause is expected; promote or resume the rollout once the canary looks healthy, or abort it.",
+		Keywords:    []string{"paused at canary step", "waiting for the preview", "inconclusive"},
+	},
+	{
+		ID: "KD-WL-007", Title: "Rollout followed by new errors", Severity: "WARNING", Category: "Workload",
+		Explanation: "Warning events or error logs rose right after a Deployment rolled out a new revision, which makes the change a likely cause.",
+		Remediation: "Check which pods the errors come from (correlate_rollouts) and roll back the Deployment if they belong to the new revision.",
+		Keywords:    []string{"rolled out", "likely cause"},
+	},
+	{
+		ID: "KD-WL-008", Title: "Replicas not spread across failure domains", Severity: "WARNING", Category: "Workload",
+		Explanation: "All replicas run on one node or in one zone, or the workload has no topologySpreadConstraints or anti-affinity, so a single node or zone outage takes it down. The scheduler never rebalances running pods.",
+		Remediation: "Add topologySpreadConstraints on kubernetes.io/hostname and topology.kubernetes.io/zone (whenUnsatisfiable: ScheduleAnyway for soft spreading), then restart the workload so the pods are placed again.",
+		References:  []string{"https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/"},
+		Keywords:    []string{"topologyspreadconstraints", "anti-affinity", "zone skew"},
+	},
+	{
+		ID: "KD-WL-009", Title: "Job failing", Severity: "CRITICAL", Category: "Workload",
+		Explanation: "The Job exhausted backoffLimit, exceeded activeDeadlineSeconds, or ran past its SLA; its work is not done and CronJob runs may pile up.",
+		Remediation: "Read the failed attempts' logs (diagnose_job), fix the cause, and rerun the Job; raise the limits only when the work has genuinely grown.",
+		Keywords:    []string{"backofflimit", "activedeadlineseconds", "sla"},
+	},
+	{
+		ID: "KD-WL-010", Title: "Workload exposed to preemption", Severity: "WARNING", Category: "Workload",
+		Explanation: "Pods at default priority are the first to be preempted or evicted when higher-priority pods need room, and a global default PriorityClass with preemption lets every pod without a class displace others.",
+		Remediation: "Give business-critical workloads a PriorityClass above the default and Guaranteed or Burstable QoS; keep the global default at 0 with preemptionPolicy Never.",
+		References:  []string{"https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/"},
+		Keywords:    []string{"priorityclass", "preempted", "preempt"},
+	},
+	{
+		ID: "KD-WL-011", Title: "HPA cannot read its metrics", Severity: "WARNING", Category: "Workload",
+		Explanation: "The HorizontalPodAutoscaler scales on a metrics API that is not served or returns errors, so it stays at its current replica count under load.",
+		Remediation: "Restore the metrics API it uses (check_metrics_apis): metrics-server for Resource metrics, or the Prometheus adapter or KEDA for custom and external metrics.",
+		Keywords:    []string{"hpa", "cannot scale"},
+	},
+	{
+		ID: "KD-WL-012", Title: "PodDisruptionBudget blocks disruptions", Severity: "WARNING", Category: "Workload",
+		Explanation: "A PDB that currently allows zero disruptions blocks node drains, cluster upgrades, and evictions until more pods become ready.",
+		Remediation: "Fix the unready pods behind the PDB, or lower minAvailable / raise maxUnavailable so at least one disruption is allowed.",
+		Keywords:    []string{"0 disruptions allowed"},
+	},
+	{
+		ID: "KD-WL-013", Title: "Pods run stale configuration", Severity: "WARNING", Category: "Workload",
+		Explanation: "A ConfigMap or Secret the pods use changed after they started, or is missing, so running pods do not match what is stored in the cluster until they restart.",
+		Remediation: "Restart the workload to pick up the change, or roll config through a hashed name or checksum annotation so changes trigger a rollout.",
+		Keywords:    []string{"stale config", "changed after"},
+	},
+	{
+		ID: "KD-WL-014", Title: "Helm release not deployed", Severity: "WARNING", Category: "Workload",
+		Explanation: "The latest Helm release revision is failed or stuck in a pending state, so the cluster runs a mix of old and new chart resources and further upgrades are blocked.",
+		Remediation: "Read the release's last error (get_helm_release_detail), then roll back to the last deployed revision or fix the values and upgrade again.",
+		Keywords:    []string{"helm release", "pending-upgrade", "pending-install"},
+	},
+
+	// --- Resources ---
+	{
+		ID: "KD-RES-001", Title: "Missing resource limits", Severity: "WARNING", Category: "Resources",
+		Explanation: "Containers without memory limits can consume all node memory and trigger node-level OOM and evictions of unrelated pods.",
+		Remediation: "Set memory limits (and CPU requests) on every container, or apply a LimitRange with defaults in the namespace.",
+		References:  []string{"https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/"},
+		Keywords:    []string{"no resource limits", "missing limits", "no limits"},
+	},
+	{
+		ID: "KD-RES-002", Title: "Missing resource requests", Severity: "WARNING", Category: "Resources",
+		Explanation: "Without requests the scheduler treats the pod as needing zero resources, causing overcommitted nodes and BestEffort QoS (first to be evicted).",
+		Remediation: "Set CPU and memory requests based on observed usage (analyze_resource_efficiency).",
+		Keywords:    []string{"no resource requests", "missing requests", "no requests"},
+	},
+	{
+		ID: "KD-RES-003", Title: "ResourceQuota near limit", Severity: "WARNING", Category: "Resources",
+		Explanation: "Namespace usage is close to its ResourceQuota; new pods or scale-ups will be rejected with 'exceeded quota'.",
+		Remediation: "Raise the quota, reduce requests of existing workloads, or clean up unused objects.",
+		Keywords:    []string{"quota"},
+	},
+	{
+		ID: "KD-RES-004", Title: "Overprovisioned workload", Severity: "INFO", Category: "Resources",
+		Explanation: "Requests are far above observed usage, wasting schedulable capacity and money.",
+		Remediation: "Lower requests toward p95 usage plus headroom; see the right-sizing table of analyze_resource_efficiency.",
+		Keywords:    []string{"overprovisioned", "over-provisioned"},
+	},
+	{
+		ID: "KD-RES-005", Title: "No ResourceQuota", Severity: "INFO", Category: "Resources",
+		Explanation: "Without a ResourceQuota one namespace can consume the whole cluster's capacity.",
+		Remediation: "Add a ResourceQuota for requests, limits, and object counts to shared namespaces.",
+		Keywords:    []string{"no resource quotas"},
+	},
+	{
+		ID: "KD-RES-006", Title: "Usage near resource limits", Severity: "WARNING", Category: "Resources",
+		Explanation: "Usage is close to the container's limit or above its request: memory at the limit is OOMKilled, CPU at the limit is throttled, and usage above requests is what the scheduler does not reserve.",
+		Remediation: "Raise the limit or request above observed peak usage (recommend_resources), or reduce the application's footprint.",
+		Keywords:    []string{"of limit", "oom risk", "exceeds its"},
+	},
+	{
+		ID: "KD-RES-007", Title: "LimitRange conflicts with quota", Severity: "CRITICAL", Category: "Resources",
+		Explanation: "A LimitRange default falls outside its own min/max, or containers set no requests or limits that a ResourceQuota requires and no default supplies them, so the API server rejects the pods.",
+		Remediation: "Fix the LimitRange defaults to lie within min and max, or add the missing requests and limits to the pod templates.",
+		Keywords:    []string{"limitrange"},
+	},
+
+	// --- Probes ---
+	{
+		ID: "KD-PRB-001", Title: "Missing readiness probe", Severity: "WARNING", Category: "Probes",
+		Explanation: "Without a readinessProbe a pod receives Service traffic as soon as its containers start, before the app can serve requests.",
+		Remediation: "Add a readinessProbe against an endpoint that reports when the app can serve traffic.",
+		References:  []string{"https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-startup-probes/"},
+		Keywords:    []string{"no readinessprobe", "missing readiness"},
+	},
+	{
+		ID: "KD-PRB-002", Title: "Aggressive liveness probe", Severity: "WARNING", Category: "Probes",
+		Explanation: "A short timeout, short period, or low failureThreshold makes the kubelet kill containers during GC pauses or load spikes, often restarting every replica at once.",
+		Remediation: "Use timeoutSeconds >= 3 and a failure window (periodSeconds x failureThreshold) of at least 10-30s.",
+		Keywords:    []string{"aggressive livenessprobe", "restart storms"},
+	},
+	{
+		ID: "KD-PRB-003", Title: "Liveness and readiness share an endpoint", Severity: "WARNING", Category: "Probes",
+		Explanation: "If the shared endpoint checks dependencies, a database outage fails liveness and restarts all replicas, turning a partial outage into a full one.",
+		Remediation: "Point liveness at a lightweight process-health endpoint; keep dependency checks in readiness only.",
+		Keywords:    []string{"same endpoint"},
+	},
+	{
+		ID: "KD-PRB-004", Title: "Probe targets an unexposed port", Severity: "CRITICAL", Category: "Probes",
+		Explanation: "The probe references a named port the container does not define, or a port the app does not listen on, so it always fails.",
+		Remediation: "Align the probe port with the container's ports list and the port the application actually binds.",
+		Keywords:    []string{"named port", "not in the container's ports"},
+	},
+	{
+		ID: "KD-PRB-005", Title: "Slow-start app without startupProbe", Severity: "WARNING", Category: "Probes",
+		Explanation: "A long initialDelaySeconds on the liveness probe delays failure detection for the life of the pod and still kills apps that occasionally start slower.",
+		Remediation: "Add a startupProbe with a generous failureThreshold and drop initialDelaySeconds from the liveness probe.",
+		Keywords:    []string{"no startupprobe"},
+	},
+	{
+		ID: "KD-PRB-006", Title: "Missing liveness probe", Severity: "INFO", Category: "Probes",
+		Explanation: "Without a livenessProbe a hung process (deadlock, exhausted thread pool) keeps running and is never restarted.",
+		Remediation: "Add a livenessProbe against a lightweight endpoint that checks only the process itself, not its dependencies.",
+		Keywords:    []string{"no livenessprobe"},
+	},
+
+	// --- Services / networking ---
+	{
+		ID: "KD-SVC-001", Title: "Service has no endpoints", Severity: "CRITICAL", Category: "Service",
+		Explanation: "No ready pod matches the Service selector, so all traffic to it fails (502/503 at the ingress, connection refused in-cluster).",
+		Remediation: "Compare the Service selector with pod labels (analyze_service_connectivity) and check that the backing pods are Ready.",
+		References:  []string{"https://kubernetes.io/docs/tasks/debug/debug-application/debug-service/"},
+		Keywords:    []string{"0 endpoints", "no endpoints", "zero endpoints"},
+	},
+	{
+		ID: "KD-SVC-002", Title: "Service has not-ready endpoints", Severity: "WARNING", Category: "Service",
+		Explanation: "Some backing pods are failing readiness, reducing capacity and sometimes causing intermittent errors during rollouts.",
+		Remediation: "Diagnose the not-ready pods (diagnose_pod) and review their readiness probes.",
+		Keywords:    []string{"not-ready endpoints", "not ready endpoints"},
+	},
+	{
+		ID: "KD-SVC-003", Title: "Service targetPort mismatch", Severity: "CRITICAL", Category: "Service",
+		Explanation: "The Service targetPort does not match any containerPort of the selected pods, so connections are refused.",
+		Remediation: "Set targetPort to the port (or port name) the container listens on.",
+		Keywords:    []string{"targetport"},
+	},
+	{
+		ID: "KD-SVC-004", Title: "LoadBalancer IP released on deletion", Severity: "WARNING", Category: "Service",
+		Explanation: "Deleting a LoadBalancer Service deletes its cloud load balancer frontend. A dynamically allocated public IP is returned to the provider and cannot be recovered, so DNS records and firewall allowlists that point to it break.",
+		Remediation: "Reserve the address as a static IP (spec.loadBalancerIP or the provider's IP annotation) before deleting, or update DNS and allowlists to the replacement address.",
+		Keywords:    []string{"loadbalancer ip"},
+	},
+	{
+		ID: "KD-SVC-005", Title: "Service used from other namespaces", Severity: "WARNING", Category: "Service",
+		Explanation: "Workloads or configuration in other namespaces address the Service by its cross-namespace DNS name (svc.namespace), so removing it breaks them.",
+		Remediation: "Move or repoint the consumers before deleting the Service or its namespace.",
+		Keywords:    []string{"cross-namespace", "other namespaces"},
+	},
+	{
+		ID: "KD-SVC-006", Title: "Dual-stack endpoint mismatch", Severity: "WARNING", Category: "Service",
+		Explanation: "The Service is dual-stack but only some ready endpoints have an address of one IP family, so clients that resolve that family reach fewer backends or none.",
+		Remediation: "Make sure the pods get both address families (dual-stack CNI and node configuration) or set the Service's ipFamilyPolicy to SingleStack.",
+		Keywords:    []string{"dual-stack"},
+	},
+	{
+		ID: "KD-ING-001", Title: "Ingress backend service missing", Severity: "CRITICAL", Category: "Ingress",
+		Explanation: "The Ingress routes to a Service that does not exist; the controller returns 503/404 for that path.",
+		Remediation: "Create the Service or fix the backend service name/port in the Ingress rule.",
+		Keywords:    []string{"backend service", "not found"},
+	},
+	{
+		ID: "KD-ING-002", Title: "Ingress backend port not on Service", Severity: "WARNING", Category: "Ingress",
+		Explanation: "The Ingress names a port the backend Service does not define, so the controller cannot route that path.",
+		Remediation: "Use a port name or number that appears in the Service's spec.ports.",
+		Keywords:    []string{"does not match any service port", "not defined on service"},
+	},
+	{
+		ID: "KD-ING-003", Title: "No Ingress rule for host", Severity: "CRITICAL", Category: "Ingress",
+		Explanation: "No Ingress rule matches the host and path, so the request gets the controller's default backend (usually a 404) or the Ingress defaultBackend.",
+		Remediation: "Add a rule for the host and path, or check the hostname and path for typos and the Ingress class for a controller mismatch.",
+		Keywords:    []string{"no ingress found", "no rule matches"},
+	},
+	{
+		ID: "KD-ING-004", Title: "Ingress without TLS", Severity: "INFO", Category: "Ingress",
+		Explanation: "The host has no TLS section or no TLS secret, so traffic is served in plain HTTP or with the controller's default certificate.",
+		Remediation: "Add a tls entry with a certificate Secret for the host (cert-manager can issue one).",
+		Keywords:    []string{"no tls"},
+	},
+	{
+		ID: "KD-ING-005", Title: "Ingress controller unhealthy", Severity: "CRITICAL", Category: "Ingress",
+		Explanation: "The ingress controller (ingress-nginx, AGIC, Application Gateway, or another) is missing, crashing, or serving a stale configuration; every Ingress it owns is affected.",
+		Remediation: "Diagnose the controller pods and logs (check_ingress_controller_health) and fix reload or configuration errors before looking at individual Ingresses.",
+		Keywords:    []string{"ingress controller", "agic", "ingress-nginx"},
+	},
+	{
+		ID: "KD-ING-006", Title: "Ingress returning errors", Severity: "WARNING", Category: "Ingress",
+		Explanation: "A significant share of requests through the controller return 5xx (backend or controller failures) or 4xx (routing, auth, or client errors).",
+		Remediation: "Group the failing requests by upstream and path from the controller logs and diagnose the backends behind them.",
+		Keywords:    []string{"5xx", "4xx"},
+	},
+	{
+		ID: "KD-ING-007", Title: "Ingress annotation invalid", Severity: "WARNING", Category: "Ingress",
+		Explanation: "An annotation is unknown, has a value the controller rejects, or uses a feature disabled by default; it is ignored, or a controller reload fails.",
+		Remediation: "Correct the annotation key or value against the controller's documentation, and enable the admission webhook so invalid Ingresses are rejected up front.",
+		Keywords:    []string{"annotation"},
+	},
+	{
+		ID: "KD-ING-008", Title: "Application Gateway out of sync", Severity: "WARNING", Category: "Ingress",
+		Explanation: "The Application Gateway's backend pools, listeners, or health do not match the ready pods behind the Ingress, so requests get 502s from stale or unhealthy backends.",
+		Remediation: "Check AGIC's logs for ARM errors, throttling, or identity problems, and compare backend health with pod readiness (check_appgw_backends).",
+		Keywords:    []string{"application gateway", "backend pool"},
+	},
+	{
+		ID: "KD-ING-009", Title: "Conflicting Ingress paths", Severity: "WARNING", Category: "Ingress",
+		Explanation: "Two Ingresses claim overlapping paths on the same host; which one serves a request depends on the controller's merge order, so traffic can reach the wrong backend.",
+		Remediation: "Give each path a single owning Ingress, or make the paths distinct.",
+		Keywords:    []string{"overlaps with path", "conflict"},
+	},
+	{
+		ID: "KD-NET-001", Title: "No NetworkPolicies in namespace", Severity: "INFO", Category: "Network",
+		Explanation: "Without NetworkPolicies all pods accept traffic from anywhere in the cluster.",
+		Remediation: "Start with a default-deny ingress policy and add explicit allow rules per application.",
+		References:  []string{"https://kubernetes.io/docs/concepts/services-networking/network-policies/"},
+		Keywords:    []string{"no network policies", "no networkpolicies"},
+	},
+	{
+		ID: "KD-NET-002", Title: "CoreDNS unhealthy", Severity: "CRITICAL", Category: "Network",
+		Explanation: "DNS failures break service discovery cluster-wide and surface as timeouts in every application.",
+		Remediation: "Check CoreDNS pods and logs (check_dns_health), the kube-dns Service endpoints, and the Corefile ConfigMap.",
+		Keywords:    []string{"coredns", "dns"},
+	},
+	{
+		ID: "KD-NET-003", Title: "NetworkPolicy blocks traffic", Severity: "WARNING", Category: "Network",
+		Explanation: "A NetworkPolicy isolates the pods with no rule that admits the traffic (an empty ingress or egress section denies everything). Enforcement depends on the CNI plugin supporting NetworkPolicy.",
+		Remediation: "Add an allow rule for the peer and port (simulate_network_path shows which policy denies it), and remember egress policies must allow DNS.",
+		References:  []string{"https://kubernetes.io/docs/concepts/services-networking/network-policies/"},
+		Keywords:    []string{"denied", "deny all", "denies all"},
+	},
+	{
+		ID: "KD-NET-004", Title: "Cluster networking unhealthy", Severity: "CRITICAL", Category: "Network",
+		Explanation: "The CNI agents or kube-proxy are missing or failing, or a node reports NetworkUnavailable; pods on the affected nodes cannot reach Services or each other.",
+		Remediation: "Diagnose the CNI and kube-proxy pods on the affected nodes (check_cluster_networking_components) and restart or repair them.",
+		Keywords:    []string{"networkunavailable", "kube-proxy", "cni"},
+	},
+	{
+		ID: "KD-NET-005", Title: "Connectivity probe failed", Severity: "WARNING", Category: "Network",
+		Explanation: "An active request to the Service or pod failed or returned an error status, so the path is broken even if endpoints look ready.",
+		Remediation: "Probe from a pod in the client's namespace, then check NetworkPolicies, the target port, and the application's logs.",
+		Keywords:    []string{"probe", "no http response"},
+	},
+	{
+		ID: "KD-NET-006", Title: "Service mesh misconfigured", Severity: "WARNING", Category: "Network",
+		Explanation: "The mesh control plane is unhealthy, or sidecar injection, mTLS mode, or routing rules do not match the workloads, so traffic is dropped or bypasses the mesh.",
+		Remediation: "Check the control plane and the injection labels (check_service_mesh_health), then restart pods that were created before injection was enabled.",
+		Keywords:    []string{"sidecar", "mesh", "mtls"},
+	},
+	{
+		ID: "KD-NET-007", Title: "Pod DNS settings inefficient", Severity: "WARNING", Category: "Network",
+		Explanation: "The pod's dnsPolicy or ndots makes names resolve outside cluster DNS or walk every search domain first, causing failed Service lookups or several extra queries per external name.",
+		Remediation: "Use dnsPolicy ClusterFirst (ClusterFirstWithHostNet for hostNetwork pods), lower ndots in dnsConfig, or use fully qualified names with a trailing dot.",
+		Keywords:    []string{"ndots", "nxdomain", "dnspolicy"},
+	},
+	{
+		ID: "KD-NET-008", Title: "Timeouts inconsistent along the request path", Severity: "WARNING", Category: "Network",
+		Explanation: "A layer in front of the app (load balancer, ingress, mesh) times out before the layer behind it, so slow requests fail with 504s while the backend is still working.",
+		Remediation: "Order timeouts from the outside in: each outer layer should wait slightly longer than the layer behind it.",
+		Keywords:    []string{"timeout"},
+	},
+
+	// --- Nodes ---
+	{
+		ID: "KD-NODE-001", Title: "Node NotReady", Severity: "CRITICAL", Category: "Node",
+		Explanation: "The kubelet has stopped reporting; pods on the node are evicted after the toleration timeout and no new pods are scheduled there.",
+		Remediation: "Check the node's conditions and events (get_node_detail), kubelet/containerd health on the VM, and network connectivity to the API server.",
+		Keywords:    []string{"notready", "not ready"},
+	},
+	{
+		ID: "KD-NODE-002", Title: "Node under resource pressure", Severity: "WARNING", Category: "Node",
+		Explanation: "MemoryPressure, DiskPressure, or PIDPressure causes the kubelet to evict pods and refuse new ones.",
+		Remediation: "Identify top consumers on the node (top_resource_consumers), clean up images/logs for disk pressure, and rebalance workloads.",
+		Keywords:    []string{"memorypressure", "diskpressure", "pidpressure", "pressure"},
+	},
+	{
+		ID: "KD-NODE-003", Title: "Node cordoned", Severity: "INFO", Category: "Node",
+		Explanation: "The node is marked unschedulable; existing pods keep running but no new pods land on it. Forgotten cordons silently shrink cluster capacity.",
+		Remediation: "Uncordon the node (uncordon_node) once maintenance is done, or drain and remove it.",
+		Keywords:    []string{"cordoned"},
+	},
+	{
+		ID: "KD-NODE-004", Title: "Node problems reported", Severity: "WARNING", Category: "Node",
+		Explanation: "A node-problem-detector condition or a kubelet event reports kernel, runtime, or filesystem problems (kernel deadlocks, OOM kills, containerd restarts, image GC failures) on the node.",
+		Remediation: "Correlate the events with the affected pods (diagnose_node) and replace the node if kernel or runtime errors repeat.",
+		Keywords:    []string{"node-problem-detector", "times in the last"},
+	},
+	{
+		ID: "KD-NODE-005", Title: "Node at capacity", Severity: "WARNING", Category: "Node",
+		Explanation: "The node is close to its allocatable CPU or memory, or runs its maximum number of pods; pods are throttled, evicted, or cannot be scheduled there.",
+		Remediation: "Spread load across nodes (analyze_node_capacity), right-size heavy pods, or add nodes to the pool.",
+		Keywords:    []string{"allocatable", "its maximum"},
+	},
+	{
+		ID: "KD-NODE-006", Title: "Version skew out of policy", Severity: "CRITICAL", Category: "Node",
+		Explanation: "Node components must never be newer than the API server and may only trail it by a few minor versions. Skew beyond that is unsupported: API fields and behaviours the kubelet or kube-proxy rely on can disappear, and the next control-plane upgrade will push the nodes further out of policy.",
+		Remediation: "Upgrade the node pools (or the kube-proxy DaemonSet) to the control-plane version one minor version at a time, before upgrading the control plane again.",
+		Keywords:    []string{"skew", "minor version"},
+	},
+	{
+		ID: "KD-NODE-007", Title: "Mixed container runtimes", Severity: "WARNING", Category: "Node",
+		Explanation: "Nodes run different container runtimes or runtime versions, usually because a node image upgrade only reached some pools. Pods behave differently depending on where they land (cgroup driver, image pull, seccomp defaults), which makes failures hard to reproduce.",
+		Remediation: "Upgrade the remaining node pools to the current node image so every node runs the same runtime and version.",
+		Keywords:    []string{"container runtime"},
+	},
+	{
+		ID: "KD-NODE-008", Title: "Node disk nearly full", Severity: "WARNING", Category: "Node",
+		Explanation: "The node's filesystem is close to the kubelet's eviction threshold (10% free by default); the kubelet sets DiskPressure, garbage-collects images, and evicts pods.",
+		Remediation: "Find the pods using the most local disk (find_disk_hogs), set ephemeral-storage requests and limits, and enlarge the node's disk if usage is legitimate.",
+		Keywords:    []string{"filesystem", "full"},
+	},
+	{
+		ID: "KD-NODE-009", Title: "Node pool at risk", Severity: "WARNING", Category: "Node",
+		Explanation: "The node pool has a layout that turns one failure into an outage or blocks upgrades: mixed kubelet versions, all nodes in one zone, spot capacity for system pods, or an outdated node image.",
+		Remediation: "Spread the pool across zones, move system add-ons to on-demand capacity, and finish or restart stuck node image upgrades.",
+		Keywords:    []string{"pool", "node image"},
+	},
+	{
+		ID: "KD-NODE-010", Title: "Capacity fragmented or imbalanced", Severity: "WARNING", Category: "Node",
+		Explanation: "Free capacity is split into slivers across nodes, or requests are concentrated on some nodes, so pods stay Pending although the cluster total looks sufficient.",
+		Remediation: "Rebalance with the descheduler or by cordoning and draining hot nodes, and size requests so typical pods fit the node shape.",
+		Keywords:    []string{"fragmented", "imbalanced"},
+	},
+	{
+		ID: "KD-NODE-011", Title: "Workload on the wrong node pool", Severity: "WARNING", Category: "Node",
+		Explanation: "Pods run on a node pool that the placement policy forbids for their priority, so critical workloads can land on spot or user pools.",
+		Remediation: "Add nodeSelector, node affinity, or tolerations so the workload schedules only onto its allowed pools.",
+		Keywords:    []string{"placement", "requires it to"},
+	},
+
+	// --- Storage ---
+	{
+		ID: "KD-STO-001", Title: "PVC stuck Pending", Severity: "WARNING", Category: "Storage",
+		Explanation: "The claim is not bound: no matching PV, the StorageClass provisioner is failing, or WaitForFirstConsumer is waiting for a pod.",
+		Remediation: "Check PVC events for provisioning errors and confirm the StorageClass exists and its CSI driver pods are healthy.",
+		Keywords:    []string{"pvc", "pending"},
+	},
+	{
+		ID: "KD-STO-002", Title: "PVC stuck terminating", Severity: "WARNING", Category: "Storage",
+		Explanation: "The claim was deleted but kubernetes.io/pvc-protection keeps it until no pod uses it. A pod that still mounts it (often one stuck Terminating itself) blocks the deletion indefinitely.",
+		Remediation: "Delete or scale down the pods that mount the claim; the finalizer is released automatically once none remain.",
+		Keywords:    []string{"pvc-protection"},
+	},
+	{
+		ID: "KD-STO-003", Title: "Volume deleted with its claim", Severity: "WARNING", Category: "Storage",
+		Explanation: "The claim's PersistentVolume (or its StorageClass) has reclaimPolicy Delete, so deleting the claim, or the namespace that holds it, deletes the backing disk and its data.",
+		Remediation: "Back up the data, or patch the PV's persistentVolumeReclaimPolicy to Retain before deleting the claim.",
+		Keywords:    []string{"reclaimpolicy delete", "reclaim policy delete"},
+	},
+	{
+		ID: "KD-STO-004", Title: "Unbounded local disk use", Severity: "WARNING", Category: "Storage",
+		Explanation: "Pods write to emptyDir or container layers with no sizeLimit or ephemeral-storage limit, so one pod can fill the node's disk and trigger DiskPressure evictions for everyone on it.",
+		Remediation: "Set ephemeral-storage requests and limits and an emptyDir sizeLimit; move large data to a PersistentVolume.",
+		Keywords:    []string{"emptydir", "ephemeral-storage", "local storage"},
+	},
+	{
+		ID: "KD-STO-005", Title: "Volume failing", Severity: "CRITICAL", Category: "Storage",
+		Explanation: "A PersistentVolume is Failed, Released, or stuck terminating, or attaching it to a node failed, so pods that mount it stay in ContainerCreating or the disk is never reused.",
+		Remediation: "Check the CSI driver pods on the node and the cloud disk's state; detach the disk from the old node if it is still attached there, and reclaim or delete Released volumes.",
+		Keywords:    []string{"attach", "csi", "containercreating"},
+	},
+
+	// --- Security ---
+	{
+		ID: "KD-SEC-001", Title: "Privileged container", Severity: "CRITICAL", Category: "Security",
+		Explanation: "A privileged container has full access to the host and can trivially escape to the node.",
+		Remediation: "Remove privileged: true and grant only the specific capabilities required.",
+		References:  []string{"https://kubernetes.io/docs/concepts/security/pod-security-standards/"},
+		Keywords:    []string{"privileged"},
+	},
+	{
+		ID: "KD-SEC-002", Title: "Host namespace sharing", Severity: "CRITICAL", Category: "Security",
+		Explanation: "hostNetwork, hostPID, or hostIPC lets the pod see and interact with host processes and network interfaces.",
+		Remediation: "Remove host namespace sharing unless the pod is a node-level agent (CNI, monitoring).",
+		Keywords:    []string{"hostnetwork", "hostpid", "hostipc", "host namespaces"},
+	},
+	{
+		ID: "KD-SEC-003", Title: "Container runs as root", Severity: "WARNING", Category: "Security",
+		Explanation: "Running as UID 0 widens the blast radius of a container breakout or a vulnerable dependency.",
+		Remediation: "Set runAsNonRoot: true and a non-zero runAsUser; rebuild the image with a non-root USER if needed.",
+		Keywords:    []string{"root", "runasnonroot"},
+	},
+	{
+		ID: "KD-SEC-004", Title: "Privilege escalation allowed", Severity: "WARNING", Category: "Security",
+		Explanation: "allowPrivilegeEscalation defaults to true, letting setuid binaries gain more privileges than the parent process.",
+		Remediation: "Set allowPrivilegeEscalation: false on every container.",
+		Keywords:    []string{"allowprivilegeescalation", "privilege escalation"},
+	},
+	{
+		ID: "KD-SEC-005", Title: "Namespace without Pod Security Admission labels", Severity: "WARNING", Category: "Security",
+		Explanation: "Without pod-security.kubernetes.io labels the built-in Pod Security Admission controller does not enforce, audit, or warn on the namespace.",
+		Remediation: "Label the namespace, e.g. pod-security.kubernetes.io/enforce=baseline and pod-security.kubernetes.io/warn=restricted, then tighten enforce once workloads comply.",
+		References:  []string{"https://kubernetes.io/docs/concepts/security/pod-security-admission/"},
+		Keywords:    []string{"pod-security.kubernetes.io", "pod security admission"},
+	},
+	{
+		ID: "KD-SEC-006", Title: "hostPath volume mounted", Severity: "WARNING", Category: "Security",
+		Explanation: "hostPath volumes expose the node filesystem and are a common container escape vector.",
+		Remediation: "Replace hostPath with emptyDir, a PVC, or a CSI volume; if unavoidable, mount read-only and restrict the path.",
+		Keywords:    []string{"hostpath"},
+	},
+	{
+		ID: "KD-SEC-007", Title: "No pod SecurityContext", Severity: "INFO", Category: "Security",
+		Explanation: "The pod sets no SecurityContext, so its user, group, fsGroup, and seccomp profile all fall back to the image and runtime defaults, which often means running as root.",
+		Remediation: "Add spec.securityContext with runAsNonRoot: true, a non-zero runAsUser, and seccompProfile: RuntimeDefault.",
+		References:  []string{"https://kubernetes.io/docs/tasks/configure-pod-container/security-context/"},
+		Keywords:    []string{"pod-level securitycontext", "no securitycontext"},
+	},
+	{
+		ID: "KD-SEC-008", Title: "Pod Security Standards violation", Severity: "WARNING", Category: "Security",
+		Explanation: "The pod violates the baseline or restricted Pod Security Standard and would be rejected once the namespace enforces that level.",
+		Remediation: "Run check_pod_security_standards for the specific violations per pod and fix them before raising the namespace's enforce label.",
+		References:  []string{"https://kubernetes.io/docs/concepts/security/pod-security-standards/"},
+		Keywords:    []string{"violate the baseline", "violate the restricted"},
+	},
+	{
+		ID: "KD-SEC-009", Title: "Unneeded ServiceAccount token mounted", Severity: "INFO", Category: "Security",
+		Explanation: "Pods mount an API token for a ServiceAccount that no RoleBinding or ClusterRoleBinding grants anything, so the app most likely never calls the API. The token still lets anyone who compromises the pod authenticate to the API server and use whatever the default discovery roles allow.",
+		Remediation: "Set automountServiceAccountToken: false on the ServiceAccount (or the pod spec); pods that need the API can opt back in per pod.",
+		References:  []string{"https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/#opt-out-of-api-credential-automounting"},
+		Keywords:    []string{"automountserviceaccounttoken", "token is mounted"},
+	},
+	{
+		ID: "KD-SEC-010", Title: "Pods run as the default ServiceAccount", Severity: "WARNING", Category: "Security",
+		Explanation: "Every pod that does not name a ServiceAccount runs as the namespace's default one, so any permission granted to it is shared by all of them and no workload can be given least privilege.",
+		Remediation: "Create a ServiceAccount per workload, set serviceAccountName in its pod template, and move any RoleBindings off the default ServiceAccount.",
+		References:  []string{"https://kubernetes.io/docs/concepts/security/service-accounts/"},
+		Keywords:    []string{"default serviceaccount"},
+	},
+	{
+		ID: "KD-SEC-011", Title: "Long-lived ServiceAccount token Secret", Severity: "WARNING", Category: "Security",
+		Explanation: "A kubernetes.io/service-account-token Secret holds a token that never expires and is readable by anyone who can read Secrets in the namespace. Since Kubernetes 1.24 pods get short-lived projected tokens instead, so these Secrets are usually leftovers or manual CI credentials.",
+		Remediation: "Delete the Secret if nothing uses it (kubernetes.io/legacy-token-last-used shows the last use on 1.29+); otherwise switch the client to kubectl create token or a TokenRequest with an expiry.",
+		References:  []string{"https://kubernetes.io/docs/reference/access-authn-authz/service-accounts-admin/#legacy-serviceaccount-token-cleaner"},
+		Keywords:    []string{"long-lived token", "service-account-token"},
+	},
+	{
+		ID: "KD-SEC-012", Title: "Workload identity misconfigured", Severity: "WARNING", Category: "Security",
+		Explanation: "Azure Workload Identity needs both the azure.workload.identity/use=true pod label and an azure.workload.identity/client-id annotation on the pod's ServiceAccount; the mutating webhook then injects the federated token and AZURE_* env vars. With either half missing, or the webhook not running, the app's Azure SDK falls back to other credentials or fails to authenticate.",
+		Remediation: "Label the pod template with azure.workload.identity/use: \"true\", annotate the ServiceAccount with the managed identity's client ID, confirm the cluster has workload identity enabled (az aks update --enable-oidc-issuer --enable-workload-identity), and recreate the pods so the webhook mutates them.",
+		References:  []string{"https://learn.microsoft.com/azure/aks/workload-identity-overview"},
+		Keywords:    []string{"workload identity", "azure.workload.identity"},
+	},
+	{
+		ID: "KD-SEC-013", Title: "Secret hygiene", Severity: "WARNING", Category: "Security",
+		Explanation: "Secrets are unused, oversized, duplicated across namespaces, or hold credentials that are expired or about to expire; pulls and TLS fail when they lapse.",
+		Remediation: "Rotate expiring credentials, delete unused Secrets, and manage shared ones with an external secret store.",
+		Keywords:    []string{"expire", "unused secret"},
+	},
+
+	{
+		ID: "KD-SEC-014", Title: "No seccomp profile", Severity: "INFO", Category: "Security",
+		Explanation: "Without a seccomp profile the container can make every system call the kernel offers, including rarely needed ones used in kernel exploits.",
+		Remediation: "Set seccompProfile: {type: RuntimeDefault} in the pod's securityContext.",
+		References:  []string{"https://kubernetes.io/docs/tutorials/security/seccomp/"},
+		Keywords:    []string{"seccomp"},
+	},
+	{
+		ID: "KD-SEC-015", Title: "Container without a SecurityContext", Severity: "WARNING", Category: "Security",
+		Explanation: "The container sets no SecurityContext, so privilege escalation is allowed, the root filesystem is writable, and it keeps the runtime's default capabilities.",
+		Remediation: "Add a container securityContext with allowPrivilegeEscalation: false, readOnlyRootFilesystem: true, and capabilities.drop: [ALL].",
+		References:  []string{"https://kubernetes.io/docs/tasks/configure-pod-container/security-context/"},
+		Keywords:    []string{"securitycontext defined"},
+	},
+	{
+		ID: "KD-SEC-016", Title: "Writable root filesystem", Severity: "INFO", Category: "Security",
+		Explanation: "The container can write to its image filesystem, so an attacker can drop binaries or alter the app, and writes silently use node disk.",
+		Remediation: "Set readOnlyRootFilesystem: true and mount an emptyDir for the paths the app must write, such as /tmp.",
+		References:  []string{"https://kubernetes.io/docs/tasks/configure-pod-container/security-context/"},
+		Keywords:    []string{"readonlyrootfilesystem"},
+	},
+	{
+		ID: "KD-SEC-017", Title: "Added Linux capabilities", Severity: "WARNING", Category: "Security",
+		Explanation: "The container adds Linux capabilities beyond the runtime default. SYS_ADMIN, NET_ADMIN, and ALL grant close to root on the node.",
+		Remediation: "Remove capabilities the app does not need; for binding low ports NET_BIND_SERVICE is usually the only one required.",
+		References:  []string{"https://kubernetes.io/docs/tasks/configure-pod-container/security-context/#set-capabilities-for-a-container"},
+		Keywords:    []string{"added capabilities", "sys_admin", "net_admin"},
+	},
+	{
+		ID: "KD-SEC-018", Title: "Capabilities not dropped", Severity: "INFO", Category: "Security",
+		Explanation: "The container has no capabilities section, so it keeps every capability the runtime grants by default, such as NET_RAW.",
+		Remediation: "Set capabilities.drop: [ALL] and add back only what the app needs.",
+		References:  []string{"https://kubernetes.io/docs/tasks/configure-pod-container/security-context/#set-capabilities-for-a-container"},
+		Keywords:    []string{"capabilities configuration", "dropping all"},
+	},
+
+	// --- Images ---
+	{
+		ID: "KD-IMG-001", Title: "Mutable image tag", Severity: "WARNING", Category: "Image",
+		Explanation: "The image is referenced by a tag such as :latest that can be re-pushed, so pods of one workload can run different builds depending on when each node pulled it.",
+		Remediation: "Pin images by immutable version tags or digests, and use imagePullPolicy IfNotPresent.",
+		Keywords:    []string{"mutable tag", ":latest", "digests"},
+	},
+	{
+		ID: "KD-IMG-002", Title: "Image outdated or missing upstream", Severity: "WARNING", Category: "Image",
+		Explanation: "The running image is old, has a newer tag available, or no longer exists in the registry, so new nodes cannot pull it once cached copies are gone.",
+		Remediation: "Rebuild or upgrade to a current tag and redeploy; restore the image in the registry if it was deleted.",
+		Keywords:    []string{"no longer exists upstream", "newer tag", "days ago"},
+	},
+
+	// --- Config ---
+	{
+		ID: "KD-CFG-001", Title: "ConfigMap near size limit", Severity: "WARNING", Category: "Config",
+		Explanation: "ConfigMaps are capped at 1MiB; updates that exceed it are rejected by the API server.",
+		Remediation: "Split the data across ConfigMaps or move it to a volume or object storage.",
+		Keywords:    []string{"1mib"},
+	},
+	{
+		ID: "KD-CFG-002", Title: "Unused ConfigMaps", Severity: "INFO", Category: "Config",
+		Explanation: "ConfigMaps that no pod or workload references add clutter and can be mistaken for live configuration.",
+		Remediation: "Confirm nothing outside the cluster reads them, then delete them.",
+		Keywords:    []string{"not referenced"},
+	},
+	{
+		ID: "KD-CFG-003", Title: "Configuration differs between clusters", Severity: "WARNING", Category: "Config",
+		Explanation: "The same workload differs between contexts (image, replicas, env, resources, or a ConfigMap or Secret missing), which often explains why it works in one cluster and fails in the other.",
+		Remediation: "Reconcile the differing fields from the source of truth, starting with missing Secrets and ConfigMaps.",
+		Keywords:    []string{"differs", "missing in"},
+	},
+	{
+		ID: "KD-CFG-004", Title: "Live object drifted from manifest", Severity: "WARNING", Category: "Config",
+		Explanation: "The object on the cluster no longer matches the manifest, or does not exist, because it was edited by hand or changed by a controller.",
+		Remediation: "Re-apply the manifest, or update the manifest if the live change is intended.",
+		Keywords:    []string{"drifted", "not on the cluster"},
+	},
+	{
+		ID: "KD-CFG-005", Title: "Manifest rejected by the API server", Severity: "CRITICAL", Category: "Config",
+		Explanation: "A server-side dry run rejected the manifest (schema, admission webhook, quota, or policy) or accepted it with warnings such as deprecated fields.",
+		Remediation: "Fix the reported field or policy violation before applying.",
+		Keywords:    []string{"would be rejected", "api warning"},
+	},
+
+	// --- GitOps ---
+	{
+		ID: "KD-GIT-001", Title: "GitOps reconciliation failing", Severity: "CRITICAL", Category: "GitOps",
+		Explanation: "A Flux Kustomization or HelmRelease, or an Argo CD application, fails or stalls reconciling, so the cluster no longer follows its source.",
+		Remediation: "Read the Ready condition message (diagnose_flux_kustomization or diagnose_flux_helm_release), fix the manifest, values, or dependency, and reconcile again.",
+		Keywords:    []string{"reconciliation failed", "reconciliation stalled"},
+	},
+	{
+		ID: "KD-GIT-002", Title: "GitOps object out of sync", Severity: "WARNING", Category: "GitOps",
+		Explanation: "The applied revision differs from the one last attempted, or a dependency is not Ready, so the cluster lags behind the source.",
+		Remediation: "Check why the newer revision did not apply and whether its dependencies are healthy.",
+		Keywords:    []string{"out of sync", "applied revision"},
+	},
+	{
+		ID: "KD-GIT-003", Title: "Reconciliation suspended", Severity: "INFO", Category: "GitOps",
+		Explanation: "The object is suspended, so new commits are ignored until it is resumed.",
+		Remediation: "Resume reconciliation once the manual change or incident is over.",
+		Keywords:    []string{"suspended"},
+	},
+	{
+		ID: "KD-GIT-004", Title: "Flux source not ready", Severity: "WARNING", Category: "GitOps",
+		Explanation: "A GitRepository, OCIRepository, HelmRepository, or Bucket cannot be fetched (authentication, URL, or network), so everything built from it is stuck on the last fetched revision.",
+		Remediation: "Read the source's Ready message and fix its credentials Secret or URL.",
+		Keywords:    []string{"gitrepository", "helmrepository", "ocirepository"},
+	},
+	{
+		ID: "KD-GIT-005", Title: "Flux controllers unhealthy", Severity: "CRITICAL", Category: "GitOps",
+		Explanation: "The Flux controllers in flux-system are missing or failing, so no Kustomization or HelmRelease reconciles.",
+		Remediation: "Diagnose the controller pods (diagnose_flux_system) and reinstall Flux if it is missing.",
+		Keywords:    []string{"flux-system"},
+	},
+
+	// --- Events and cluster ---
+	{
+		ID: "KD-EVT-001", Title: "Warning events", Severity: "WARNING", Category: "Events",
+		Explanation: "Kubernetes recorded Warning events (failed probes, scheduling, mounts, pulls) for the object or namespace; they usually name the failing component directly.",
+		Remediation: "Group the warnings by reason (analyze_events) and fix the most frequent one first.",
+		Keywords:    []string{"warning events"},
+	},
+	{
+		ID: "KD-EVT-002", Title: "Correlated failures", Severity: "WARNING", Category: "Events",
+		Explanation: "Many restarts or warning events started together or share a reason across workloads, which points to a shared cause such as a node, quota, or dependency rather than each workload.",
+		Remediation: "Look for what the affected pods share (node, dependency, recent change) and fix that first.",
+		Keywords:    []string{"spike", "shared cause", "spiking"},
+	},
+	{
+		ID: "KD-EVT-003", Title: "Errors in application logs", Severity: "WARNING", Category: "Events",
+		Explanation: "Containers logged errors while handling the requests or period in question.",
+		Remediation: "Read the error lines in context (get_pod_logs) starting with the first service that logged them.",
+		Keywords:    []string{"error lines", "log line"},
+	},
+	{
+		ID: "KD-SYS-001", Title: "System pods unhealthy", Severity: "CRITICAL", Category: "Cluster",
+		Explanation: "Pods in kube-system (DNS, CNI, kube-proxy, CSI drivers) are failing; cluster-wide networking, DNS, or storage is affected.",
+		Remediation: "Diagnose the failing kube-system pods first (diagnose_namespace namespace=kube-system); application errors often clear once they recover.",
+		Keywords:    []string{"kube-system"},
+	},
+	{
+		ID: "KD-SYS-002", Title: "Resource stuck terminating", Severity: "WARNING", Category: "Cluster",
+		Explanation: "The object was deleted but still has finalizers, so the API server keeps it until the controllers that own them finish cleanup. A controller that was uninstalled, is crash-looping, or cannot reach an external system never removes its finalizer; a namespace stays Terminating while any object inside it does, or while an aggregated API it must enumerate is unavailable.",
+		Remediation: "Find the controller that owns each remaining finalizer and restore it. Remove a finalizer by hand only when its controller is gone for good, since the cleanup it guards (cloud resources, backups) is then skipped.",
+		Keywords:    []string{"namespace terminating", "stuck deleting"},
+	},
+	{
+		ID: "KD-SYS-003", Title: "Metrics API unavailable", Severity: "CRITICAL", Category: "Cluster",
+		Explanation: "The metrics.k8s.io (or a custom or external metrics) APIService is missing or unavailable, so kubectl top, resource-based HPAs, and usage-based checks have no data.",
+		Remediation: "Diagnose metrics-server (check_metrics_server) or the metrics adapter: its pods, its Service endpoints, and kubelet scrape errors.",
+		Keywords:    []string{"metrics server", "metrics-server", "apiservice"},
+	},
+	{
+		ID: "KD-SYS-004", Title: "Cluster add-on unhealthy", Severity: "CRITICAL", Category: "Cluster",
+		Explanation: "A cluster add-on (DNS, CNI, CSI, metrics, or a managed AKS component) is missing, scaled down, degraded, or not running on every node it should.",
+		Remediation: "Diagnose the add-on's pods in kube-system and restore its DaemonSet or Deployment; for managed add-ons, re-enable them through the cloud provider.",
+		Keywords:    []string{"add-on", "addon"},
+	},
+	{
+		ID: "KD-SYS-005", Title: "Cluster autoscaler unhealthy", Severity: "WARNING", Category: "Cluster",
+		Explanation: "The cluster autoscaler or Karpenter is failing, backing off, or absent, so pending pods do not get new nodes.",
+		Remediation: "Read the autoscaler's status and logs (check_autoscaler_health) for cloud quota, permission, or node group errors.",
+		Keywords:    []string{"autoscaler", "karpenter", "scale-up"},
+	},
+	{
+		ID: "KD-SYS-006", Title: "Webhook fails closed", Severity: "WARNING", Category: "Cluster",
+		Explanation: "An admission webhook with failurePolicy Fail rejects every matching request while its service is down, which can block deployments cluster-wide.",
+		Remediation: "Keep the webhook's backend highly available, narrow its rules and namespaceSelector, or use failurePolicy Ignore where safe.",
+		Keywords:    []string{"failurepolicy", "webhook"},
+	},
+	{
+		ID: "KD-SYS-007", Title: "Custom resources failing", Severity: "WARNING", Category: "Cluster",
+		Explanation: "Objects of an operator's custom resource report failing conditions or have not been reconciled since their last change, which usually points at the operator itself.",
+		Remediation: "Check the operator's pods and logs, then the most common failure message across the objects.",
+		Keywords:    []string{"failing conditions", "observedgeneration"},
+	},
+	{
+		ID: "KD-SYS-008", Title: "Check incomplete", Severity: "INFO", Category: "Cluster",
+		Explanation: "Part of the check could not run because data was unavailable: an API call failed or was forbidden, metrics or logs were missing, or an external service could not be reached. The report may miss problems in that area.",
+		Remediation: "Fix the access or dependency named in the message and run the check again.",
+		Keywords:    []string{"could not", "skipped", "unavailable"},
+	},
+	{
+		ID: "KD-SYS-009", Title: "Cloud provider throttled", Severity: "WARNING", Category: "Cluster",
+		Explanation: "The cloud provider API is throttling the cluster's requests, so load balancer, disk, and node operations are delayed or fail.",
+		Remediation: "Reduce the request rate (fewer controllers polling, larger sync intervals) and check the subscription's API limits.",
+		Keywords:    []string{"throttling", "throttled"},
+	},
+	{
+		ID: "KD-SYS-010", Title: "Cluster unhealthy in fleet", Severity: "WARNING", Category: "Cluster",
+		Explanation: "A cluster in the fleet is unreachable or reports NotReady nodes, dead Services, or unhealthy pods.",
+		Remediation: "Run the full diagnosis against that context to find the cause.",
+		Keywords:    []string{"unreachable"},
+	},
+	{
+		ID: "KD-SYS-011", Title: "System namespace deletion", Severity: "CRITICAL", Category: "Cluster",
+		Explanation: "The namespace holds cluster components. The API server refuses to delete default, kube-system, and kube-public, and deleting any other system namespace breaks the add-ons running in it.",
+		Remediation: "Do not delete the namespace; remove the individual objects you no longer need instead.",
+		Keywords:    []string{"system namespace"},
+	},
+	{
+		ID: "KD-SYS-012", Title: "Expensive tools on a large scope", Severity: "INFO", Category: "Cluster",
+		Explanation: "Some tools list every object in the cluster; on large clusters they are slow and load the API server.",
+		Remediation: "Pass a namespace to narrow heavy tools.",
+		Keywords:    []string{"heavy tools"},
+	},
+	{
+		ID: "KD-SYS-013", Title: "Pod held by finalizers", Severity: "WARNING", Category: "Cluster",
+		Explanation: "The pod is already being deleted but lists finalizers, so it stays Terminating until the controllers that own them finish their cleanup.",
+		Remediation: "Check the controllers named by the finalizers; remove a finalizer by hand only if its controller is gone for good.",
+		References:  []string{"https://kubernetes.io/docs/concepts/overview/working-with-objects/finalizers/"},
+		Keywords:    []string{"finalizers"},
+	},
+	{
+		ID: "KD-SYS-014", Title: "Force deletion", Severity: "WARNING", Category: "Cluster",
+		Explanation: "A grace period of 0 removes the pod from the API without waiting for the kubelet to confirm its containers stopped, so a StatefulSet can briefly run two pods with the same identity.",
+		Remediation: "Use the default grace period unless the node is known to be gone.",
+		References:  []string{"https://kubernetes.io/docs/tasks/run-application/force-delete-stateful-set-pod/"},
+		Keywords:    []string{"immediate deletion", "grace period"},
+	},
+	{
+		ID: "KD-SYS-015", Title: "Last schedulable node in pool", Severity: "WARNING", Category: "Cluster",
+		Explanation: "Cordoning the node leaves its pool with no schedulable nodes, so pods that select or tolerate only this pool stay Pending.",
+		Remediation: "Scale the pool up or uncordon another node in it before cordoning this one.",
+		Keywords:    []string{"last schedulable node"},
+	},
+	{
+		ID: "KD-SYS-016", Title: "No headroom to drain node", Severity: "WARNING", Category: "Cluster",
+		Explanation: "The remaining schedulable nodes lack the free CPU or memory requests to take the node's pods, so draining it leaves pods Pending.",
+		Remediation: "Add capacity first, or let the cluster autoscaler scale up before draining.",
+		Keywords:    []string{"lack headroom"},
+	},
+	{
+		ID: "KD-SYS-017", Title: "Unmanaged pods on node", Severity: "WARNING", Category: "Cluster",
+		Explanation: "Pods on the node have no controller, so nothing recreates them elsewhere when the node is drained.",
+		Remediation: "Move the pods under a Deployment or StatefulSet, or recreate them by hand after the drain.",
+		Keywords:    []string{"no controller"},
+	},
+	{
+		ID: "KD-SYS-018", Title: "HPA overrides manual scaling", Severity: "WARNING", Category: "Cluster",
+		Explanation: "A HorizontalPodAutoscaler manages the Deployment and resets its replica count on its next sync, so a manual scale does not last.",
+		Remediation: "Change the HPA's minReplicas or maxReplicas instead.",
+		Keywords:    []string{"override the replica count"},
+	},
+	{
+		ID: "KD-SYS-019", Title: "Deployment scaled to zero", Severity: "WARNING", Category: "Cluster",
+		Explanation: "Scaling to 0 replicas stops every pod, so the Deployment's Services have no endpoints until it is scaled up again.",
+		Remediation: "Confirm nothing depends on the Deployment, or scale it to at least 1.",
+		Keywords:    []string{"scaling to 0"},
+	},
+}
+
+// index maps upper-cased rule IDs to catalog entries.
+var index = func() map[string]Rule {
+	m := make(map[string]Rule, len(catalog))
+	for _, r := range catalog {
+		m[r.ID] = r
+	}
+	return m
+}()
+
+// Lookup returns the rule with the given ID (case-insensitive).
+func Lookup(id string) (Rule, bool) {
+	r, ok := index[strings.ToUpper(strings.TrimSpace(id))]
+	return r, ok
+}
+
+// All returns every rule in the catalog sorted by ID.
+func All() []Rule {
+	out := make([]Rule, len(catalog))
+	copy(out, catalog)
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Search returns rules whose ID, title, or keywords appear in text, best
+// matches first. It lets a raw finding line be mapped back to its rule.
+func Search(text string) []Rule {
+	text = strings.ToLower(text)
+	type scored struct {
+		rule  Rule
+		score int
+	}
+	var matches []scored
+	for _, r := range catalog {
+		score := 0
+		if strings.Contains(text, strings.ToLower(r.ID)) {
+			score += 100
+		}
+		if strings.Contains(text, strings.ToLower(r.Title)) {
+			score += 10
+		}
+		for _, k := range r.Keywords {
+			if strings.Contains(text, k) {
+				score += len(k)
+			}
+		}
+		if score > 0 {
+			matches = append(matches, scored{r, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	out := make([]Rule, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, m.rule)
+	}
+	return out
+}
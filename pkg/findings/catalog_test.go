@@ -0,0 +1,56 @@
+package findings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCatalogIDsUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, r := range All() {
+		if seen[r.ID] {
+			t.Errorf("duplicate rule ID %s", r.ID)
+		}
+		seen[r.ID] = true
+		if !strings.HasPrefix(r.ID, "KD-") {
+			t.Errorf("rule ID %s should start with KD-", r.ID)
+		}
+		if r.Explanation == "" || r.Remediation == "" {
+			t.Errorf("rule %s is missing explanation or remediation", r.ID)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	r, ok := Lookup("kd-pod-001")
+	if !ok {
+		t.Fatal("expected KD-POD-001 to be found case-insensitively")
+	}
+	if r.Title != "Container in CrashLoopBackOff" {
+		t.Errorf("unexpected title %q", r.Title)
+	}
+	if _, ok := Lookup("KD-NOPE-999"); ok {
+		t.Error("expected unknown ID to not be found")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"[CRITICAL] Container 'app' is in CrashLoopBackOff", "KD-POD-001"},
+		{"[CRITICAL] Service 'web' has 0 endpoints — no pods match its selector", "KD-SVC-001"},
+		{"see KD-SEC-005 for details", "KD-SEC-005"},
+	}
+	for _, tt := range tests {
+		got := Search(tt.text)
+		if len(got) == 0 || got[0].ID != tt.want {
+			ids := make([]string, 0, len(got))
+			for _, r := range got {
+				ids = append(ids, r.ID)
+			}
+			t.Errorf("Search(%q) top match = %v, want %s", tt.text, ids, tt.want)
+		}
+	}
+}
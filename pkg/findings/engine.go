@@ -0,0 +1,58 @@
+package findings
+
+import "fmt"
+
+// Finding is one result of a check, tied to a catalog rule by its ID so that
+// reports can be tracked, diffed, and suppressed across runs.
+type Finding struct {
+	ID       string
+	Severity string
+	Message  string
+}
+
+// New returns a finding for the rule with the given ID at the rule's default
+// severity. An unknown ID yields a WARNING.
+func New(id, message string) Finding {
+	severity := "WARNING"
+	if r, ok := Lookup(id); ok {
+		severity = r.Severity
+	}
+	return Finding{ID: id, Severity: severity, Message: message}
+}
+
+// WithSeverity returns a copy of f at the given severity, for checks whose
+// severity depends on impact (e.g. how many replicas are affected). An empty
+// severity keeps the current one.
+func (f Finding) WithSeverity(severity string) Finding {
+	if severity != "" {
+		f.Severity = severity
+	}
+	return f
+}
+
+// Format returns the report line for a check result under the rule with the
+// given ID. An empty severity uses the rule's default.
+func Format(id, severity, message string) string {
+	return New(id, message).WithSeverity(severity).String()
+}
+
+// Rule returns the catalog rule behind the finding.
+func (f Finding) Rule() (Rule, bool) {
+	return Lookup(f.ID)
+}
+
+// Remediation returns the rule's remediation text, or "" for an unknown rule.
+func (f Finding) Remediation() string {
+	r, _ := f.Rule()
+	return r.Remediation
+}
+
+// String formats the finding as a report line: "[SEVERITY] ID: message".
+// It keeps the "[SEVERITY] " prefix of util.FormatFinding so summaries and
+// severity counts work unchanged.
+func (f Finding) String() string {
+	if f.ID == "" {
+		return fmt.Sprintf("[%s] %s", f.Severity, f.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", f.Severity, f.ID, f.Message)
+}
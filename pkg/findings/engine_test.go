@@ -0,0 +1,50 @@
+package findings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewUsesRuleSeverity(t *testing.T) {
+	f := New("KD-POD-001", "Container 'app' is in CrashLoopBackOff")
+	if f.Severity != "CRITICAL" {
+		t.Errorf("severity = %s, want the rule's CRITICAL", f.Severity)
+	}
+	if got := f.WithSeverity("WARNING").Severity; got != "WARNING" {
+		t.Errorf("WithSeverity(WARNING) = %s", got)
+	}
+	if got := f.WithSeverity("").Severity; got != "CRITICAL" {
+		t.Errorf("WithSeverity(\"\") = %s, want severity unchanged", got)
+	}
+	if !strings.Contains(f.Remediation(), "previous container logs") {
+		t.Errorf("Remediation() = %q, want the KD-POD-001 remediation", f.Remediation())
+	}
+	if got := New("KD-NOPE-999", "x"); got.Severity != "WARNING" || got.Remediation() != "" {
+		t.Errorf("unknown rule = %+v, want WARNING and no remediation", got)
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	tests := []struct {
+		f    Finding
+		want string
+	}{
+		{New("KD-NODE-001", "Node 'n1' is NotReady"), "[CRITICAL] KD-NODE-001: Node 'n1' is NotReady"},
+		{Finding{Severity: "INFO", Message: "condition changed"}, "[INFO] condition changed"},
+	}
+	for _, tt := range tests {
+		if got := tt.f.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+	if got := Format("KD-SVC-002", "", "Service 'web' has 1 not-ready endpoint"); got != "[WARNING] KD-SVC-002: Service 'web' has 1 not-ready endpoint" {
+		t.Errorf("Format() with the default severity = %q", got)
+	}
+	if got := Format("KD-SVC-002", "CRITICAL", "all endpoints not ready"); got != "[CRITICAL] KD-SVC-002: all endpoints not ready" {
+		t.Errorf("Format() with a severity = %q", got)
+	}
+	// Finding lines map back to their rule by ID.
+	if got := Search(New("KD-SEC-006", "Volume 'host' mounts /var/run").String()); len(got) == 0 || got[0].ID != "KD-SEC-006" {
+		t.Errorf("Search() of a finding line did not return its rule first")
+	}
+}
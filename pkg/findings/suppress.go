@@ -0,0 +1,120 @@
+package findings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Suppression silences one rule's findings, optionally only in some
+// namespaces or only for findings whose message mentions a resource. It
+// records an accepted risk so it stops showing up in every report.
+type Suppression struct {
+	Rule       string   `json:"rule"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	Match      string   `json:"match,omitempty"`
+	Reason     string   `json:"reason,omitempty"`
+}
+
+// Suppressions is a set of suppression rules, loaded from --suppress-file
+// and --suppress.
+type Suppressions struct {
+	Rules []Suppression `json:"suppressions"`
+}
+
+// LoadSuppressions reads suppressions from a JSON file of the form
+// {"suppressions": [{"rule": "KD-RES-001", "namespaces": ["kube-system"], "match": "DaemonSet/", "reason": "..."}]}.
+func LoadSuppressions(path string) (*Suppressions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Suppressions
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing suppressions %s: %w", path, err)
+	}
+	for i := range s.Rules {
+		if err := s.Rules[i].validate(); err != nil {
+			return nil, fmt.Errorf("suppressions %s: entry %d: %w", path, i+1, err)
+		}
+	}
+	return &s, nil
+}
+
+// ParseSuppression parses the --suppress form RULE or RULE@NAMESPACE.
+func ParseSuppression(s string) (Suppression, error) {
+	rule, ns, _ := strings.Cut(strings.TrimSpace(s), "@")
+	sup := Suppression{Rule: rule}
+	if ns != "" {
+		sup.Namespaces = []string{ns}
+	}
+	if err := sup.validate(); err != nil {
+		return Suppression{}, fmt.Errorf("--suppress %q: %w", s, err)
+	}
+	return sup, nil
+}
+
+func (s *Suppression) validate() error {
+	s.Rule = strings.ToUpper(strings.TrimSpace(s.Rule))
+	if s.Rule == "" {
+		return fmt.Errorf("missing rule")
+	}
+	if _, ok := Lookup(s.Rule); !ok {
+		return fmt.Errorf("unknown rule %s", s.Rule)
+	}
+	return nil
+}
+
+// Add appends suppressions, e.g. those from --suppress to a loaded file.
+func (s *Suppressions) Add(rules ...Suppression) {
+	s.Rules = append(s.Rules, rules...)
+}
+
+// Suppressed returns the suppression that silences f, or nil. namespace is
+// the namespace the report was requested for; "" means all namespaces, in
+// which case a namespaced suppression applies when the message names an
+// object in one of its namespaces ("kube-system/coredns").
+func (s *Suppressions) Suppressed(f Finding, namespace string) *Suppression {
+	if s == nil || f.ID == "" {
+		return nil
+	}
+	for i := range s.Rules {
+		r := &s.Rules[i]
+		if r.Rule != f.ID {
+			continue
+		}
+		if r.Match != "" && !strings.Contains(strings.ToLower(f.Message), strings.ToLower(r.Match)) {
+			continue
+		}
+		if len(r.Namespaces) > 0 && !slices.Contains(r.Namespaces, namespace) && !mentionsNamespace(f.Message, r.Namespaces) {
+			continue
+		}
+		return r
+	}
+	return nil
+}
+
+func mentionsNamespace(message string, namespaces []string) bool {
+	for _, ns := range namespaces {
+		if strings.Contains(message, ns+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// findingLine matches a report line written by Finding.String.
+var findingLine = regexp.MustCompile(`^\s*\[(CRITICAL|WARNING|INFO|OK)\] (KD-[A-Z]+-\d+): (.*)$`)
+
+// Parse reads a finding back from a report line. It returns false for lines
+// that are not findings or carry no rule ID.
+func Parse(line string) (Finding, bool) {
+	m := findingLine.FindStringSubmatch(line)
+	if m == nil {
+		return Finding{}, false
+	}
+	return Finding{ID: m[2], Severity: m[1], Message: m[3]}, true
+}
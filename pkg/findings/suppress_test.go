@@ -0,0 +1,73 @@
+package findings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSuppressions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "suppress.json")
+	data := `{"suppressions": [{"rule": "kd-res-001", "namespaces": ["kube-system"], "match": "DaemonSet/", "reason": "node agents"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSuppressions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Rules) != 1 || s.Rules[0].Rule != "KD-RES-001" {
+		t.Errorf("rules = %+v, want KD-RES-001 normalized to upper case", s.Rules)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"suppressions": [{"rule": "KD-NOPE-001"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSuppressions(path); err == nil {
+		t.Error("expected an error for an unknown rule")
+	}
+}
+
+func TestParseSuppression(t *testing.T) {
+	s, err := ParseSuppression("KD-NET-001@kube-system")
+	if err != nil || s.Rule != "KD-NET-001" || len(s.Namespaces) != 1 || s.Namespaces[0] != "kube-system" {
+		t.Errorf("ParseSuppression() = %+v, %v", s, err)
+	}
+	if _, err := ParseSuppression("@kube-system"); err == nil {
+		t.Error("expected an error without a rule")
+	}
+}
+
+func TestSuppressed(t *testing.T) {
+	s := &Suppressions{Rules: []Suppression{
+		{Rule: "KD-RES-001", Namespaces: []string{"kube-system"}, Match: "daemonset/"},
+		{Rule: "KD-NET-001"},
+	}}
+	tests := []struct {
+		f         Finding
+		namespace string
+		want      bool
+	}{
+		{New("KD-RES-001", "DaemonSet/kube-proxy container 'proxy' has no memory limit"), "kube-system", true},
+		{New("KD-RES-001", "DaemonSet/kube-proxy container 'proxy' has no memory limit"), "default", false},
+		{New("KD-RES-001", "kube-system/DaemonSet/kube-proxy has no memory limit"), "", true},
+		{New("KD-RES-001", "Deployment/coredns has no memory limit"), "kube-system", false},
+		{New("KD-NET-001", "No network policies"), "shop", true},
+		{Finding{Severity: "WARNING", Message: "No network policies"}, "shop", false},
+	}
+	for _, tt := range tests {
+		if got := s.Suppressed(tt.f, tt.namespace) != nil; got != tt.want {
+			t.Errorf("Suppressed(%q, %q) = %v, want %v", tt.f, tt.namespace, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	f, ok := Parse("  [CRITICAL] KD-NODE-001: Node 'n1' is NotReady")
+	if !ok || f.ID != "KD-NODE-001" || f.Severity != "CRITICAL" || f.Message != "Node 'n1' is NotReady" {
+		t.Errorf("Parse() = %+v, %v", f, ok)
+	}
+	if _, ok := Parse("[WARNING] no rule ID here"); ok {
+		t.Error("expected a finding without an ID not to parse")
+	}
+}
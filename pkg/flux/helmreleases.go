@@ -5,13 +5,15 @@ import (
 	"time"
 
 	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ListHelmReleases returns all HelmReleases in the given namespace (empty = all namespaces).
 func (fc *FluxClient) ListHelmReleases(ctx context.Context, namespace string) ([]helmv2.HelmRelease, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var list helmv2.HelmReleaseList
@@ -26,9 +28,16 @@ func (fc *FluxClient) ListHelmReleases(ctx context.Context, namespace string) ([
 	return list.Items, nil
 }
 
+// ListHelmReleasesPage returns one page of HelmReleases in the given namespace (empty = all namespaces).
+func (fc *FluxClient) ListHelmReleasesPage(ctx context.Context, namespace string, opts metav1.ListOptions) (k8s.Page[helmv2.HelmRelease], error) {
+	return listPage(ctx, fc, namespace, opts, &helmv2.HelmReleaseList{}, func(l *helmv2.HelmReleaseList) []helmv2.HelmRelease {
+		return l.Items
+	})
+}
+
 // GetHelmRelease returns a single HelmRelease by namespace and name.
 func (fc *FluxClient) GetHelmRelease(ctx context.Context, namespace, name string) (*helmv2.HelmRelease, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var hr helmv2.HelmRelease
@@ -4,38 +4,20 @@ import (
 	"context"
 
 	imagev1beta2 "github.com/fluxcd/image-reflector-controller/api/v1beta2"
-	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// ListImageRepositories returns all ImageRepositories in the given namespace (empty = all).
-func (fc *FluxClient) ListImageRepositories(ctx context.Context, namespace string) ([]imagev1beta2.ImageRepository, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
-	defer cancel()
-
-	var list imagev1beta2.ImageRepositoryList
-	opts := []client.ListOption{}
-	if namespace != "" {
-		opts = append(opts, client.InNamespace(namespace))
-	}
-	if err := fc.Client.List(ctx, &list, opts...); err != nil {
-		return nil, err
-	}
-	return list.Items, nil
+// ListImageRepositoriesPage returns one page of ImageRepositories in the given namespace (empty = all).
+func (fc *FluxClient) ListImageRepositoriesPage(ctx context.Context, namespace string, opts metav1.ListOptions) (k8s.Page[imagev1beta2.ImageRepository], error) {
+	return listPage(ctx, fc, namespace, opts, &imagev1beta2.ImageRepositoryList{}, func(l *imagev1beta2.ImageRepositoryList) []imagev1beta2.ImageRepository {
+		return l.Items
+	})
 }
 
-// ListImagePolicies returns all ImagePolicies in the given namespace (empty = all).
-func (fc *FluxClient) ListImagePolicies(ctx context.Context, namespace string) ([]imagev1beta2.ImagePolicy, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
-	defer cancel()
-
-	var list imagev1beta2.ImagePolicyList
-	opts := []client.ListOption{}
-	if namespace != "" {
-		opts = append(opts, client.InNamespace(namespace))
-	}
-	if err := fc.Client.List(ctx, &list, opts...); err != nil {
-		return nil, err
-	}
-	return list.Items, nil
+// ListImagePoliciesPage returns one page of ImagePolicies in the given namespace (empty = all).
+func (fc *FluxClient) ListImagePoliciesPage(ctx context.Context, namespace string, opts metav1.ListOptions) (k8s.Page[imagev1beta2.ImagePolicy], error) {
+	return listPage(ctx, fc, namespace, opts, &imagev1beta2.ImagePolicyList{}, func(l *imagev1beta2.ImagePolicyList) []imagev1beta2.ImagePolicy {
+		return l.Items
+	})
 }
@@ -5,13 +5,15 @@ import (
 	"time"
 
 	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ListKustomizations returns all Kustomizations in the given namespace (empty = all namespaces).
 func (fc *FluxClient) ListKustomizations(ctx context.Context, namespace string) ([]kustomizev1.Kustomization, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var list kustomizev1.KustomizationList
@@ -26,9 +28,16 @@ func (fc *FluxClient) ListKustomizations(ctx context.Context, namespace string)
 	return list.Items, nil
 }
 
+// ListKustomizationsPage returns one page of Kustomizations in the given namespace (empty = all namespaces).
+func (fc *FluxClient) ListKustomizationsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (k8s.Page[kustomizev1.Kustomization], error) {
+	return listPage(ctx, fc, namespace, opts, &kustomizev1.KustomizationList{}, func(l *kustomizev1.KustomizationList) []kustomizev1.Kustomization {
+		return l.Items
+	})
+}
+
 // GetKustomization returns a single Kustomization by namespace and name.
 func (fc *FluxClient) GetKustomization(ctx context.Context, namespace, name string) (*kustomizev1.Kustomization, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var ks kustomizev1.Kustomization
@@ -0,0 +1,27 @@
+package flux
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// listPage lists one page of a Flux resource in the given namespace (empty =
+// all namespaces) into list, honouring opts.Limit and opts.Continue.
+func listPage[T any, L client.ObjectList](ctx context.Context, fc *FluxClient, namespace string, opts metav1.ListOptions, list L, items func(L) []T) (k8s.Page[T], error) {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	listOpts := []client.ListOption{client.Limit(opts.Limit), client.Continue(opts.Continue)}
+	if namespace != "" {
+		listOpts = append(listOpts, client.InNamespace(namespace))
+	}
+	if err := fc.Client.List(ctx, list, listOpts...); err != nil {
+		return k8s.Page[T]{}, err
+	}
+	return k8s.Page[T]{Items: items(list), Continue: list.GetContinue(), Remaining: list.GetRemainingItemCount()}, nil
+}
@@ -0,0 +1,49 @@
+package flux
+
+import (
+	"context"
+	"testing"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestListKustomizationsPage(t *testing.T) {
+	var got client.ListOptions
+	c := fakeclient.NewClientBuilder().
+		WithScheme(newScheme()).
+		WithObjects(
+			&kustomizev1.Kustomization{ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "flux-system"}},
+			&kustomizev1.Kustomization{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other-ns"}},
+		).
+		WithInterceptorFuncs(interceptor.Funcs{List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			got.ApplyOptions(opts)
+			if err := c.List(ctx, list, opts...); err != nil {
+				return err
+			}
+			// The fake client ignores limits; answer as a truncated API server page would.
+			remaining := int64(4)
+			list.SetContinue("next-page")
+			list.SetRemainingItemCount(&remaining)
+			return nil
+		}}).
+		Build()
+	fc := &FluxClient{Client: c}
+
+	page, err := fc.ListKustomizationsPage(context.Background(), "flux-system", metav1.ListOptions{Limit: 1, Continue: "this-page"})
+	if err != nil {
+		t.Fatalf("ListKustomizationsPage() error = %v", err)
+	}
+	if got.Limit != 1 || got.Continue != "this-page" || got.Namespace != "flux-system" {
+		t.Errorf("list options = limit %d, continue %q, namespace %q", got.Limit, got.Continue, got.Namespace)
+	}
+	if len(page.Items) != 1 || page.Items[0].Name != "apps" {
+		t.Errorf("expected only the flux-system Kustomization, got %d items", len(page.Items))
+	}
+	if page.Continue != "next-page" || page.Remaining == nil || *page.Remaining != 4 {
+		t.Errorf("page continue = %q, remaining = %v", page.Continue, page.Remaining)
+	}
+}
@@ -5,13 +5,15 @@ import (
 
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	sourcev1beta2 "github.com/fluxcd/source-controller/api/v1beta2"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ListGitRepositories returns all GitRepositories in the given namespace (empty = all).
 func (fc *FluxClient) ListGitRepositories(ctx context.Context, namespace string) ([]sourcev1.GitRepository, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var list sourcev1.GitRepositoryList
@@ -25,9 +27,16 @@ func (fc *FluxClient) ListGitRepositories(ctx context.Context, namespace string)
 	return list.Items, nil
 }
 
+// ListGitRepositoriesPage returns one page of GitRepositories in the given namespace (empty = all).
+func (fc *FluxClient) ListGitRepositoriesPage(ctx context.Context, namespace string, opts metav1.ListOptions) (k8s.Page[sourcev1.GitRepository], error) {
+	return listPage(ctx, fc, namespace, opts, &sourcev1.GitRepositoryList{}, func(l *sourcev1.GitRepositoryList) []sourcev1.GitRepository {
+		return l.Items
+	})
+}
+
 // GetGitRepository returns a single GitRepository by namespace and name.
 func (fc *FluxClient) GetGitRepository(ctx context.Context, namespace, name string) (*sourcev1.GitRepository, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var obj sourcev1.GitRepository
@@ -39,7 +48,7 @@ func (fc *FluxClient) GetGitRepository(ctx context.Context, namespace, name stri
 
 // ListOCIRepositories returns all OCIRepositories in the given namespace (empty = all).
 func (fc *FluxClient) ListOCIRepositories(ctx context.Context, namespace string) ([]sourcev1beta2.OCIRepository, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var list sourcev1beta2.OCIRepositoryList
@@ -53,9 +62,16 @@ func (fc *FluxClient) ListOCIRepositories(ctx context.Context, namespace string)
 	return list.Items, nil
 }
 
+// ListOCIRepositoriesPage returns one page of OCIRepositories in the given namespace (empty = all).
+func (fc *FluxClient) ListOCIRepositoriesPage(ctx context.Context, namespace string, opts metav1.ListOptions) (k8s.Page[sourcev1beta2.OCIRepository], error) {
+	return listPage(ctx, fc, namespace, opts, &sourcev1beta2.OCIRepositoryList{}, func(l *sourcev1beta2.OCIRepositoryList) []sourcev1beta2.OCIRepository {
+		return l.Items
+	})
+}
+
 // GetOCIRepository returns a single OCIRepository by namespace and name.
 func (fc *FluxClient) GetOCIRepository(ctx context.Context, namespace, name string) (*sourcev1beta2.OCIRepository, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var obj sourcev1beta2.OCIRepository
@@ -67,7 +83,7 @@ func (fc *FluxClient) GetOCIRepository(ctx context.Context, namespace, name stri
 
 // ListHelmRepositories returns all HelmRepositories in the given namespace (empty = all).
 func (fc *FluxClient) ListHelmRepositories(ctx context.Context, namespace string) ([]sourcev1.HelmRepository, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var list sourcev1.HelmRepositoryList
@@ -81,9 +97,16 @@ func (fc *FluxClient) ListHelmRepositories(ctx context.Context, namespace string
 	return list.Items, nil
 }
 
+// ListHelmRepositoriesPage returns one page of HelmRepositories in the given namespace (empty = all).
+func (fc *FluxClient) ListHelmRepositoriesPage(ctx context.Context, namespace string, opts metav1.ListOptions) (k8s.Page[sourcev1.HelmRepository], error) {
+	return listPage(ctx, fc, namespace, opts, &sourcev1.HelmRepositoryList{}, func(l *sourcev1.HelmRepositoryList) []sourcev1.HelmRepository {
+		return l.Items
+	})
+}
+
 // GetHelmRepository returns a single HelmRepository by namespace and name.
 func (fc *FluxClient) GetHelmRepository(ctx context.Context, namespace, name string) (*sourcev1.HelmRepository, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var obj sourcev1.HelmRepository
@@ -93,25 +116,16 @@ func (fc *FluxClient) GetHelmRepository(ctx context.Context, namespace, name str
 	return &obj, nil
 }
 
-// ListHelmCharts returns all HelmCharts in the given namespace (empty = all).
-func (fc *FluxClient) ListHelmCharts(ctx context.Context, namespace string) ([]sourcev1.HelmChart, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
-	defer cancel()
-
-	var list sourcev1.HelmChartList
-	opts := []client.ListOption{}
-	if namespace != "" {
-		opts = append(opts, client.InNamespace(namespace))
-	}
-	if err := fc.Client.List(ctx, &list, opts...); err != nil {
-		return nil, err
-	}
-	return list.Items, nil
+// ListHelmChartsPage returns one page of HelmCharts in the given namespace (empty = all).
+func (fc *FluxClient) ListHelmChartsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (k8s.Page[sourcev1.HelmChart], error) {
+	return listPage(ctx, fc, namespace, opts, &sourcev1.HelmChartList{}, func(l *sourcev1.HelmChartList) []sourcev1.HelmChart {
+		return l.Items
+	})
 }
 
 // GetHelmChart returns a single HelmChart by namespace and name.
 func (fc *FluxClient) GetHelmChart(ctx context.Context, namespace, name string) (*sourcev1.HelmChart, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var obj sourcev1.HelmChart
@@ -121,25 +135,16 @@ func (fc *FluxClient) GetHelmChart(ctx context.Context, namespace, name string)
 	return &obj, nil
 }
 
-// ListBuckets returns all Buckets in the given namespace (empty = all).
-func (fc *FluxClient) ListBuckets(ctx context.Context, namespace string) ([]sourcev1.Bucket, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
-	defer cancel()
-
-	var list sourcev1.BucketList
-	opts := []client.ListOption{}
-	if namespace != "" {
-		opts = append(opts, client.InNamespace(namespace))
-	}
-	if err := fc.Client.List(ctx, &list, opts...); err != nil {
-		return nil, err
-	}
-	return list.Items, nil
+// ListBucketsPage returns one page of Buckets in the given namespace (empty = all).
+func (fc *FluxClient) ListBucketsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (k8s.Page[sourcev1.Bucket], error) {
+	return listPage(ctx, fc, namespace, opts, &sourcev1.BucketList{}, func(l *sourcev1.BucketList) []sourcev1.Bucket {
+		return l.Items
+	})
 }
 
 // GetBucket returns a single Bucket by namespace and name.
 func (fc *FluxClient) GetBucket(ctx context.Context, namespace, name string) (*sourcev1.Bucket, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	var obj sourcev1.Bucket
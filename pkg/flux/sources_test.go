@@ -72,7 +72,7 @@ func TestListHelmRepositories(t *testing.T) {
 	}
 }
 
-func TestListHelmCharts(t *testing.T) {
+func TestListHelmChartsPage(t *testing.T) {
 	hc := &sourcev1.HelmChart{
 		ObjectMeta: metav1.ObjectMeta{Name: "flux-system-nginx", Namespace: "flux-system"},
 		Spec: sourcev1.HelmChartSpec{
@@ -84,16 +84,17 @@ func TestListHelmCharts(t *testing.T) {
 	fc := NewFluxClientForTesting(hc)
 	ctx := context.Background()
 
-	items, err := fc.ListHelmCharts(ctx, "flux-system")
+	page, err := fc.ListHelmChartsPage(ctx, "flux-system", metav1.ListOptions{})
 	if err != nil {
-		t.Fatalf("ListHelmCharts() error = %v", err)
+		t.Fatalf("ListHelmChartsPage() error = %v", err)
 	}
+	items := page.Items
 	if len(items) != 1 {
 		t.Errorf("expected 1 item, got %d", len(items))
 	}
 }
 
-func TestListBuckets(t *testing.T) {
+func TestListBucketsPage(t *testing.T) {
 	b := &sourcev1.Bucket{
 		ObjectMeta: metav1.ObjectMeta{Name: "my-bucket", Namespace: "flux-system"},
 		Spec: sourcev1.BucketSpec{
@@ -105,10 +106,11 @@ func TestListBuckets(t *testing.T) {
 	fc := NewFluxClientForTesting(b)
 	ctx := context.Background()
 
-	items, err := fc.ListBuckets(ctx, "")
+	page, err := fc.ListBucketsPage(ctx, "", metav1.ListOptions{})
 	if err != nil {
-		t.Fatalf("ListBuckets() error = %v", err)
+		t.Fatalf("ListBucketsPage() error = %v", err)
 	}
+	items := page.Items
 	if len(items) != 1 {
 		t.Errorf("expected 1 item, got %d", len(items))
 	}
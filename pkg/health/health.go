@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Probe checks a dependency, such as the Kubernetes API server.
+type Probe func(ctx context.Context) error
+
+// Checker runs a probe in the background and serves its latest result on
+// /healthz and /readyz. The first probe runs immediately so a broken
+// kubeconfig is reported before any MCP client connects.
+type Checker struct {
+	probe    Probe
+	interval time.Duration
+
+	mu        sync.Mutex
+	checked   bool
+	lastErr   error
+	lastCheck time.Time
+}
+
+// NewChecker creates a Checker that re-runs probe every interval.
+func NewChecker(probe Probe, interval time.Duration) *Checker {
+	return &Checker{probe: probe, interval: interval}
+}
+
+// Run probes immediately and then every interval until ctx is cancelled.
+// Each probe also keeps the API server connection and credentials warm.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check runs the probe once and records the result, logging state changes.
+func (c *Checker) check(ctx context.Context) {
+	err := c.probe(ctx)
+
+	c.mu.Lock()
+	wasReady := c.checked && c.lastErr == nil
+	first := !c.checked
+	c.checked, c.lastErr, c.lastCheck = true, err, time.Now()
+	c.mu.Unlock()
+
+	switch {
+	case err != nil && (first || wasReady):
+		log.Printf("Readiness check failed: %v", err)
+	case err == nil && !wasReady:
+		log.Printf("Kubernetes API server reachable; server is ready")
+	}
+}
+
+// Ready returns nil once the latest probe succeeded, or the reason the server is not ready.
+func (c *Checker) Ready() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checked {
+		return fmt.Errorf("warm-up in progress")
+	}
+	return c.lastErr
+}
+
+// Register adds /healthz (process liveness) and /readyz (API server
+// reachability) to mux.
+func (c *Checker) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := c.Ready(); err != nil {
+			http.Error(w, "not ready: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
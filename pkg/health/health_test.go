@@ -0,0 +1,42 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadyzReflectsProbe(t *testing.T) {
+	probeErr := errors.New("Unauthorized")
+	c := NewChecker(func(ctx context.Context) error { return probeErr }, time.Hour)
+	mux := http.NewServeMux()
+	c.Register(mux)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	if rec := get("/readyz"); rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "warm-up") {
+		t.Errorf("before warm-up: /readyz = %d %q, want 503 warm-up", rec.Code, rec.Body.String())
+	}
+
+	c.check(context.Background())
+	if rec := get("/readyz"); rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "Unauthorized") {
+		t.Errorf("after failed probe: /readyz = %d %q, want 503 with probe error", rec.Code, rec.Body.String())
+	}
+	if rec := get("/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("/healthz = %d, want 200 regardless of readiness", rec.Code)
+	}
+
+	probeErr = nil
+	c.check(context.Background())
+	if rec := get("/readyz"); rec.Code != http.StatusOK {
+		t.Errorf("after successful probe: /readyz = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,154 @@
+package k8s
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CallStats counts the Kubernetes API requests made under one context,
+// typically one tool call. It is safe for concurrent use.
+type CallStats struct {
+	calls   atomic.Int64
+	bytes   atomic.Int64
+	latency atomic.Int64 // nanoseconds, summed over requests
+	retries atomic.Int64
+}
+
+// Calls returns the number of API requests made.
+func (s *CallStats) Calls() int64 { return s.calls.Load() }
+
+// Bytes returns the response bytes read.
+func (s *CallStats) Bytes() int64 { return s.bytes.Load() }
+
+// Retries returns how many requests were repeated after a transient error.
+// Each attempt also counts as a call.
+func (s *CallStats) Retries() int64 { return s.retries.Load() }
+
+// Latency returns the summed time to first response byte of all requests.
+// Requests run concurrently, so it can exceed the wall-clock time of the call.
+func (s *CallStats) Latency() time.Duration { return time.Duration(s.latency.Load()) }
+
+type callStatsKey struct{}
+
+// WithCallStats returns a context whose API requests are counted in the
+// returned CallStats, for every client sharing this package's transport.
+func WithCallStats(ctx context.Context) (context.Context, *CallStats) {
+	s := &CallStats{}
+	return context.WithValue(ctx, callStatsKey{}, s), s
+}
+
+// callStatsFrom returns the CallStats of ctx, or nil when it is not counted.
+func callStatsFrom(ctx context.Context) *CallStats {
+	s, _ := ctx.Value(callStatsKey{}).(*CallStats)
+	return s
+}
+
+// accountingTransport records each request in the CallStats of its context.
+type accountingTransport struct {
+	base http.RoundTripper
+}
+
+// newAccountingTransport is a rest.Config transport wrapper for accounting.
+func newAccountingTransport(rt http.RoundTripper) http.RoundTripper {
+	return &accountingTransport{base: rt}
+}
+
+func (t *accountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	stats := callStatsFrom(req.Context())
+	if stats == nil {
+		return t.base.RoundTrip(req)
+	}
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	stats.calls.Add(1)
+	stats.latency.Add(int64(time.Since(start)))
+	if resp != nil && resp.Body != nil {
+		resp.Body = &countingBody{ReadCloser: resp.Body, bytes: &stats.bytes}
+	}
+	return resp, err
+}
+
+// countingBody adds the bytes read from a response body to a counter.
+type countingBody struct {
+	io.ReadCloser
+	bytes *atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes.Add(int64(n))
+	return n, err
+}
+
+// ToolUsage is the accumulated API usage of one tool.
+type ToolUsage struct {
+	Tool        string
+	Invocations int
+	Errors      int
+	APICalls    int64
+	Retries     int64
+	Bytes       int64
+	// Elapsed and MaxElapsed are wall-clock times of the tool calls.
+	Elapsed    time.Duration
+	MaxElapsed time.Duration
+}
+
+// APIUsage accumulates CallStats per tool over the server's lifetime.
+type APIUsage struct {
+	mu      sync.Mutex
+	started time.Time
+	tools   map[string]*ToolUsage
+}
+
+// NewAPIUsage returns an empty usage table starting now.
+func NewAPIUsage() *APIUsage {
+	return &APIUsage{started: time.Now(), tools: make(map[string]*ToolUsage)}
+}
+
+// Record adds one finished tool call.
+func (u *APIUsage) Record(tool string, stats *CallStats, elapsed time.Duration, failed bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	t := u.tools[tool]
+	if t == nil {
+		t = &ToolUsage{Tool: tool}
+		u.tools[tool] = t
+	}
+	t.Invocations++
+	if failed {
+		t.Errors++
+	}
+	t.APICalls += stats.Calls()
+	t.Retries += stats.Retries()
+	t.Bytes += stats.Bytes()
+	t.Elapsed += elapsed
+	t.MaxElapsed = max(t.MaxElapsed, elapsed)
+}
+
+// Started returns when accounting began.
+func (u *APIUsage) Started() time.Time {
+	return u.started
+}
+
+// Tools returns the usage of every tool called so far, heaviest (most API
+// calls) first.
+func (u *APIUsage) Tools() []ToolUsage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	out := make([]ToolUsage, 0, len(u.tools))
+	for _, t := range u.tools {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].APICalls != out[j].APICalls {
+			return out[i].APICalls > out[j].APICalls
+		}
+		return out[i].Tool < out[j].Tool
+	})
+	return out
+}
@@ -0,0 +1,64 @@
+package k8s
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAccountingTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("0123456789"))
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: newAccountingTransport(http.DefaultTransport)}
+
+	get := func(ctx context.Context) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	ctx, stats := WithCallStats(context.Background())
+	get(ctx)
+	get(ctx)
+	get(context.Background())
+	if stats.Calls() != 2 || stats.Bytes() != 20 {
+		t.Errorf("stats = %d calls, %d bytes; want 2 calls, 20 bytes", stats.Calls(), stats.Bytes())
+	}
+	if stats.Latency() <= 0 {
+		t.Error("expected latency to be recorded")
+	}
+}
+
+func TestAPIUsage(t *testing.T) {
+	usage := NewAPIUsage()
+	light := &CallStats{}
+	light.calls.Store(2)
+	heavy := &CallStats{}
+	heavy.calls.Store(40)
+	heavy.bytes.Store(1024)
+
+	usage.Record("get_pod", light, time.Second, false)
+	usage.Record("triage", heavy, 3*time.Second, false)
+	usage.Record("triage", heavy, time.Second, true)
+
+	got := usage.Tools()
+	if len(got) != 2 || got[0].Tool != "triage" {
+		t.Fatalf("Tools() = %+v, want triage first", got)
+	}
+	triage := got[0]
+	if triage.Invocations != 2 || triage.Errors != 1 || triage.APICalls != 80 || triage.Bytes != 2048 {
+		t.Errorf("triage usage = %+v", triage)
+	}
+	if triage.Elapsed != 4*time.Second || triage.MaxElapsed != 3*time.Second {
+		t.Errorf("triage elapsed = %s, max %s; want 4s, max 3s", triage.Elapsed, triage.MaxElapsed)
+	}
+}
@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// authRetryAttempts is how many times a request rejected with 401 or a
+	// credential plugin failure is retried.
+	authRetryAttempts = 2
+
+	// authRetryBackoff is the base delay between authentication retries.
+	authRetryBackoff = 500 * time.Millisecond
+)
+
+// authRetryTransport retries requests that fail authentication so that a
+// long-lived session survives token expiry. For exec credential plugins
+// (kubelogin for AAD-integrated AKS) client-go refreshes the token after the
+// first 401 but does not replay the request; this transport replays it. For
+// static bearer tokens, reload re-reads the kubeconfig so a token renewed
+// outside the server (e.g. by az aks get-credentials) is picked up.
+type authRetryTransport struct {
+	base    http.RoundTripper
+	reload  func() (string, error)
+	backoff time.Duration
+
+	mu    sync.Mutex
+	token string
+}
+
+func (t *authRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if token := t.currentToken(); token != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	for attempt := 1; attempt <= authRetryAttempts && needsAuthRetry(resp, err); attempt++ {
+		retry, ok := cloneForRetry(req)
+		if !ok {
+			break
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if t.reload != nil {
+			if token, rerr := t.reload(); rerr == nil && token != "" {
+				t.setToken(token)
+				retry.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		log.Printf("Authentication failed for %s %s; refreshing credentials (attempt %d/%d)", req.Method, req.URL.Path, attempt, authRetryAttempts)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.backoff * time.Duration(attempt)):
+		}
+		resp, err = t.base.RoundTrip(retry)
+	}
+	return resp, err
+}
+
+func (t *authRetryTransport) currentToken() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.token
+}
+
+func (t *authRetryTransport) setToken(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.token = token
+}
+
+// needsAuthRetry reports whether a response or error indicates expired or
+// unavailable credentials.
+func needsAuthRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return strings.Contains(err.Error(), "getting credentials")
+	}
+	return resp.StatusCode == http.StatusUnauthorized
+}
+
+// cloneForRetry copies a request for replay, rewinding its body. It returns
+// false when the body cannot be replayed.
+func cloneForRetry(req *http.Request) (*http.Request, bool) {
+	retry := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return retry, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	retry.Body = body
+	return retry, true
+}
@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAuthRetryTransportReplaysAfter401(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if string(body) != `{"a":1}` {
+			t.Errorf("expected replayed body, got %q", body)
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &authRetryTransport{base: http.DefaultTransport, backoff: time.Millisecond}}
+	resp, err := client.Post(srv.URL, "application/json", strings.NewReader(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 after retry, got %d", resp.StatusCode)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 calls, got %d", calls.Load())
+	}
+}
+
+func TestAuthRetryTransportReloadsStaticToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	rt := &authRetryTransport{
+		base:    http.DefaultTransport,
+		backoff: time.Millisecond,
+		reload:  func() (string, error) { return "fresh", nil },
+	}
+	client := &http.Client{Transport: rt}
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		req.Header.Set("Authorization", "Bearer expired")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("request %d: expected 200, got %d", i, resp.StatusCode)
+		}
+	}
+	if rt.currentToken() != "fresh" {
+		t.Errorf("expected reloaded token to be kept, got %q", rt.currentToken())
+	}
+}
+
+func TestAuthRetryTransportGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &authRetryTransport{base: http.DefaultTransport, backoff: time.Millisecond}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 to be returned after retries, got %d", resp.StatusCode)
+	}
+	if got := calls.Load(); got != authRetryAttempts+1 {
+		t.Errorf("expected %d calls, got %d", authRetryAttempts+1, got)
+	}
+}
@@ -1,15 +1,25 @@
 package k8s
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	metricsv "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
 )
 
 // ClusterClient wraps Kubernetes client interfaces for cluster access.
@@ -17,52 +27,236 @@ type ClusterClient struct {
 	Clientset           kubernetes.Interface
 	MetricsClient       metricsv.Interface
 	ApiextensionsClient apiextensionsclient.Interface
+	DynamicClient       dynamic.Interface
 	Config              *rest.Config
 	ContextName         string
+
+	// Namespaces limits the client to specific namespaces when RBAC does not
+	// allow cluster-wide access. All-namespace queries are split per namespace
+	// and cluster-scoped queries return a util.ScopeError. Empty means cluster-wide.
+	Namespaces []string
+
+	// Prometheus is an optional source of historical usage; nil when no
+	// Prometheus URL is configured.
+	Prometheus *PrometheusClient
+
+	// options are the settings the client was created with, reused by ForContext.
+	options ClientOptions
+}
+
+// ClientOptions controls how NewClusterClientWithOptions connects to the cluster.
+type ClientOptions struct {
+	// Kubeconfig overrides the kubeconfig path(s); KUBECONFIG-style lists are allowed.
+	Kubeconfig string
+	// Context selects a kubeconfig context instead of the current-context.
+	Context string
+	// ProxyURL sends all requests through an existing `kubectl proxy`
+	// (e.g. http://127.0.0.1:8001), which handles authentication itself.
+	ProxyURL string
+	// Namespaces enables namespace-scoped mode for the listed namespaces.
+	Namespaces []string
+	// EnforceNamespaces rejects every API request outside Namespaces, including
+	// cluster-scoped reads, instead of relying on RBAC.
+	EnforceNamespaces bool
+	// PrometheusURL enables historical usage queries against a Prometheus API.
+	PrometheusURL string
+}
+
+// execPluginSearchDirs are directories searched for kubeconfig exec plugins
+// (kubelogin, aws, gke-gcloud-auth-plugin) that are missing from PATH. MCP
+// hosts often start servers with a minimal PATH that omits them.
+var execPluginSearchDirs = []string{
+	"/usr/local/bin",
+	"/opt/homebrew/bin",
+	"~/.azure-kubelogin",
+	"~/.local/bin",
+	"~/go/bin",
+	"~/bin",
 }
 
 // NewClusterClient creates a client from kubeconfig or in-cluster config.
 // If contextName is empty, uses the current-context from kubeconfig.
 func NewClusterClient(contextName string) (*ClusterClient, error) {
-	// Try in-cluster first
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		// Fall back to kubeconfig
-		kubeconfig := kubeconfigPath()
+	return NewClusterClientWithOptions(ClientOptions{Context: contextName})
+}
 
-		loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig}
-		overrides := &clientcmd.ConfigOverrides{}
-		if contextName != "" {
-			overrides.CurrentContext = contextName
-		}
+// NewClusterClientWithOptions creates a client through kubectl proxy, an
+// explicit kubeconfig/context, or in-cluster config, in that order.
+func NewClusterClientWithOptions(opts ClientOptions) (*ClusterClient, error) {
+	config, err := buildRestConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	config.WarningHandlerWithContext = warningHandler{}
+	config.Wrap(newAccountingTransport)
+	config.Wrap(newRetryTransport)
+	if opts.EnforceNamespaces {
+		config.Wrap(newNamespaceGuard(opts.Namespaces))
+	}
 
-		clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
-		config, err = clientConfig.ClientConfig()
-		if err != nil {
-			return nil, fmt.Errorf("failed to build config: %w", err)
-		}
+	httpClient, err := newAuthRetryHTTPClient(config, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
+	clientset, err := kubernetes.NewForConfigAndClient(config, httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create clientset: %w", err)
 	}
 
 	// Metrics API may not be available; don't fail hard
-	metricsClient, _ := metricsv.NewForConfig(config)
+	metricsClient, _ := metricsv.NewForConfigAndClient(config, httpClient)
 
 	// API extensions client for CRDs; may not be available
-	apiextClient, _ := apiextensionsclient.NewForConfig(config)
+	apiextClient, _ := apiextensionsclient.NewForConfigAndClient(config, httpClient)
+
+	// Dynamic client for custom resources such as mesh policies
+	dynamicClient, _ := dynamic.NewForConfigAndClient(config, httpClient)
+
+	var prom *PrometheusClient
+	if opts.PrometheusURL != "" {
+		if prom, err = NewPrometheusClient(opts.PrometheusURL); err != nil {
+			return nil, err
+		}
+	}
 
 	return &ClusterClient{
 		Clientset:           clientset,
 		MetricsClient:       metricsClient,
 		ApiextensionsClient: apiextClient,
+		DynamicClient:       dynamicClient,
 		Config:              config,
-		ContextName:         contextName,
+		ContextName:         opts.Context,
+		Namespaces:          opts.Namespaces,
+		Prometheus:          prom,
+		options:             opts,
 	}, nil
 }
 
+// ForContext returns a client for another context of the same kubeconfig,
+// keeping the namespace scope and guards. The Prometheus URL belongs to the
+// current cluster and is not carried over; a kubectl proxy serves only one
+// cluster, so ForContext fails when the client was created through one.
+func (c *ClusterClient) ForContext(name string) (*ClusterClient, error) {
+	if c.options.ProxyURL != "" {
+		return nil, fmt.Errorf("context %s: not reachable through --proxy-url, which serves a single cluster", name)
+	}
+	opts := c.options
+	opts.Context = name
+	opts.PrometheusURL = ""
+	return NewClusterClientWithOptions(opts)
+}
+
+// AvailableContexts lists the contexts of the kubeconfig the client was
+// created from, and its current context.
+func (c *ClusterClient) AvailableContexts() ([]string, string, error) {
+	return listContexts(c.options.Kubeconfig)
+}
+
+// Ping checks that the API server is reachable and accepts the client's
+// credentials. It also warms up the connection and any exec credential plugin.
+func (c *ClusterClient) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	rc := c.Clientset.Discovery().RESTClient()
+	if rc == nil {
+		_, err := c.Clientset.Discovery().ServerVersion()
+		return err
+	}
+	return rc.Get().AbsPath("/version").Do(ctx).Error()
+}
+
+// buildRestConfig resolves the REST config for the given options.
+func buildRestConfig(opts ClientOptions) (*rest.Config, error) {
+	if opts.ProxyURL != "" {
+		u, err := url.Parse(opts.ProxyURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", opts.ProxyURL)
+		}
+		return &rest.Config{Host: opts.ProxyURL}, nil
+	}
+
+	// Use in-cluster config unless a kubeconfig or context was requested
+	if opts.Kubeconfig == "" && opts.Context == "" {
+		if config, err := rest.InClusterConfig(); err == nil {
+			return config, nil
+		}
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if opts.Kubeconfig != "" {
+		loadingRules.Precedence = filepath.SplitList(opts.Kubeconfig)
+	}
+	overrides := &clientcmd.ConfigOverrides{}
+	if opts.Context != "" {
+		overrides.CurrentContext = opts.Context
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config: %w", err)
+	}
+	resolveExecPlugin(config)
+	return config, nil
+}
+
+// newAuthRetryHTTPClient returns an HTTP client for config whose transport
+// retries requests that fail authentication. Static bearer tokens from a
+// kubeconfig are reloaded from disk on retry.
+func newAuthRetryHTTPClient(config *rest.Config, opts ClientOptions) (*http.Client, error) {
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	t := &authRetryTransport{base: httpClient.Transport, backoff: authRetryBackoff}
+	if config.BearerToken != "" && config.BearerTokenFile == "" && config.ExecProvider == nil && config.AuthProvider == nil {
+		t.reload = func() (string, error) {
+			fresh, err := buildRestConfig(opts)
+			if err != nil {
+				return "", err
+			}
+			return fresh.BearerToken, nil
+		}
+	}
+	httpClient.Transport = t
+	return httpClient, nil
+}
+
+// resolveExecPlugin rewrites a kubeconfig exec credential plugin command to an
+// absolute path when it is not on PATH but is installed in a well-known
+// directory. stdin is never handed to the plugin because it carries MCP traffic.
+func resolveExecPlugin(config *rest.Config) {
+	if config.ExecProvider == nil {
+		return
+	}
+	if config.ExecProvider.InteractiveMode == "" || config.ExecProvider.InteractiveMode == clientcmdapi.AlwaysExecInteractiveMode {
+		config.ExecProvider.InteractiveMode = clientcmdapi.IfAvailableExecInteractiveMode
+	}
+	cmd := config.ExecProvider.Command
+	if cmd == "" || filepath.IsAbs(cmd) {
+		return
+	}
+	if _, err := exec.LookPath(cmd); err == nil {
+		return
+	}
+	home, _ := os.UserHomeDir()
+	for _, dir := range execPluginSearchDirs {
+		if strings.HasPrefix(dir, "~/") {
+			if home == "" {
+				continue
+			}
+			dir = filepath.Join(home, dir[2:])
+		}
+		candidate := filepath.Join(dir, cmd)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() && info.Mode()&0o111 != 0 {
+			config.ExecProvider.Command = candidate
+			return
+		}
+	}
+}
+
 // NewClusterClientForTesting creates a ClusterClient with injected fakes for unit tests.
 func NewClusterClientForTesting(clientset kubernetes.Interface, metricsClient metricsv.Interface) *ClusterClient {
 	return &ClusterClient{
@@ -85,9 +279,17 @@ func NewClusterClientForTestingWithApiext(clientset kubernetes.Interface, metric
 // ListAvailableContexts returns all contexts from the kubeconfig file
 // and the name of the current context.
 func ListAvailableContexts() ([]string, string, error) {
-	kubeconfig := kubeconfigPath()
+	return listContexts("")
+}
 
-	config, err := clientcmd.LoadFromFile(kubeconfig)
+// listContexts loads contexts from kubeconfig, a KUBECONFIG-style path list,
+// or the default locations when it is empty. Contexts are sorted by name.
+func listContexts(kubeconfig string) ([]string, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		loadingRules.Precedence = filepath.SplitList(kubeconfig)
+	}
+	config, err := loadingRules.Load()
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
@@ -96,14 +298,6 @@ func ListAvailableContexts() ([]string, string, error) {
 	for name := range config.Contexts {
 		contexts = append(contexts, name)
 	}
+	sort.Strings(contexts)
 	return contexts, config.CurrentContext, nil
 }
-
-// kubeconfigPath returns the path to the kubeconfig file.
-func kubeconfigPath() string {
-	if p := os.Getenv("KUBECONFIG"); p != "" {
-		return p
-	}
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".kube", "config")
-}
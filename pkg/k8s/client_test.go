@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestBuildRestConfigProxy(t *testing.T) {
+	config, err := buildRestConfig(ClientOptions{ProxyURL: "http://127.0.0.1:8001"})
+	if err != nil {
+		t.Fatalf("buildRestConfig() error = %v", err)
+	}
+	if config.Host != "http://127.0.0.1:8001" || config.ExecProvider != nil || config.BearerToken != "" {
+		t.Errorf("expected plain proxy config, got %+v", config)
+	}
+
+	if _, err := buildRestConfig(ClientOptions{ProxyURL: "127.0.0.1:8001"}); err == nil {
+		t.Error("expected error for proxy URL without scheme")
+	}
+}
+
+func TestResolveExecPlugin(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", t.TempDir())
+
+	dir := filepath.Join(home, ".azure-kubelogin")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	plugin := filepath.Join(dir, "kubelogin")
+	if err := os.WriteFile(plugin, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &rest.Config{ExecProvider: &clientcmdapi.ExecConfig{
+		Command:         "kubelogin",
+		InteractiveMode: clientcmdapi.AlwaysExecInteractiveMode,
+	}}
+	resolveExecPlugin(config)
+	if config.ExecProvider.Command != plugin {
+		t.Errorf("Command = %q, want %q", config.ExecProvider.Command, plugin)
+	}
+	if config.ExecProvider.InteractiveMode != clientcmdapi.IfAvailableExecInteractiveMode {
+		t.Errorf("InteractiveMode = %q, want IfAvailable", config.ExecProvider.InteractiveMode)
+	}
+}
+
+func TestPing(t *testing.T) {
+	status := http.StatusUnauthorized
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{"major":"1","minor":"30"}`))
+	}))
+	defer srv.Close()
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatalf("NewForConfig() error = %v", err)
+	}
+	client := NewClusterClientForTesting(clientset, nil)
+
+	if err := client.Ping(context.Background()); err == nil {
+		t.Error("expected Ping() to fail when credentials are rejected")
+	}
+	status = http.StatusOK
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+}
+
+func TestListContexts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: c
+  cluster: {server: "https://127.0.0.1:6443"}
+users:
+- name: u
+  user: {token: abc}
+contexts:
+- name: prod
+  context: {cluster: c, user: u}
+- name: dev
+  context: {cluster: c, user: u}
+`
+	if err := os.WriteFile(path, []byte(kubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	contexts, current, err := listContexts(path)
+	if err != nil {
+		t.Fatalf("listContexts() error = %v", err)
+	}
+	if len(contexts) != 2 || contexts[0] != "dev" || contexts[1] != "prod" {
+		t.Errorf("contexts = %v, want [dev prod]", contexts)
+	}
+	if current != "prod" {
+		t.Errorf("current = %q, want prod", current)
+	}
+
+	client := &ClusterClient{options: ClientOptions{ProxyURL: "http://127.0.0.1:8001"}}
+	if _, err := client.ForContext("dev"); err == nil {
+		t.Error("expected ForContext() to fail when connected through a proxy")
+	}
+}
@@ -0,0 +1,43 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// GetConfigMap returns a single ConfigMap by name.
+func (c *ClusterClient) GetConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	return c.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListConfigMaps returns ConfigMaps in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListConfigMaps(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, error) {
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]corev1.ConfigMap, error) {
+			return c.ListConfigMaps(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	list, err := c.Clientset.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ListConfigMapsPage returns one page of ConfigMaps in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListConfigMapsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[corev1.ConfigMap], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.ConfigMapList, error) {
+		return c.Clientset.CoreV1().ConfigMaps(ns).List(ctx, opts)
+	}, func(l *corev1.ConfigMapList) []corev1.ConfigMap { return l.Items })
+}
@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetConfigMap(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress-nginx-controller", Namespace: "ingress-nginx"},
+			Data:       map[string]string{"proxy-read-timeout": "60"},
+		},
+	)
+
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	cm, err := client.GetConfigMap(context.Background(), "ingress-nginx", "ingress-nginx-controller")
+	if err != nil {
+		t.Fatalf("GetConfigMap() error = %v", err)
+	}
+	if cm.Data["proxy-read-timeout"] != "60" {
+		t.Errorf("expected proxy-read-timeout=60, got %q", cm.Data["proxy-read-timeout"])
+	}
+}
+
+func TestListConfigMaps(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "shop"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt", Namespace: "shop"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"}},
+	)
+
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	cms, err := client.ListConfigMaps(context.Background(), "shop", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListConfigMaps() error = %v", err)
+	}
+	if len(cms) != 2 {
+		t.Errorf("expected 2 ConfigMaps in shop, got %d", len(cms))
+	}
+}
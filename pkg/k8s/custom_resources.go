@@ -0,0 +1,173 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// ListCustomResources returns objects of a namespaced custom resource in the
+// given namespace (empty = all namespaces). A NotFound error means the
+// resource's CRD is not installed.
+func (c *ClusterClient) ListCustomResources(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	if c.DynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client not available")
+	}
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]unstructured.Unstructured, error) {
+			return c.ListCustomResources(ctx, gvr, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	list, err := c.DynamicClient.Resource(gvr).Namespace(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ListClusterCustomResources returns all objects of a cluster-scoped custom resource.
+func (c *ClusterClient) ListClusterCustomResources(ctx context.Context, gvr schema.GroupVersionResource, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	if err := c.clusterScope(gvr.Resource); err != nil {
+		return nil, err
+	}
+	if c.DynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client not available")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	list, err := c.DynamicClient.Resource(gvr).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// APIResourceRef is a listable API resource resolved through discovery.
+type APIResourceRef struct {
+	GVR        schema.GroupVersionResource
+	Kind       string
+	Namespaced bool
+}
+
+// ResolveResource finds the API resource for a kind. The kind also matches
+// case-insensitively against plural, singular, and short names. Group and
+// version narrow the search; without a version the group's preferred version
+// is used. A name served by several groups is an error listing the candidates.
+func (c *ClusterClient) ResolveResource(ctx context.Context, group, version, kind string) (APIResourceRef, error) {
+	groups, lists, err := c.Clientset.Discovery().ServerGroupsAndResources()
+	if err != nil && lists == nil {
+		return APIResourceRef{}, err
+	}
+	preferred := make(map[string]string, len(groups))
+	for _, g := range groups {
+		preferred[g.Name] = g.PreferredVersion.Version
+	}
+
+	var matches []APIResourceRef
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		if group != "" && gv.Group != group {
+			continue
+		}
+		if version != "" && gv.Version != version {
+			continue
+		}
+		if version == "" && preferred[gv.Group] != "" && preferred[gv.Group] != gv.Version {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || !resourceNameMatches(r, kind) {
+				continue
+			}
+			matches = append(matches, APIResourceRef{GVR: gv.WithResource(r.Name), Kind: r.Kind, Namespaced: r.Namespaced})
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return APIResourceRef{}, fmt.Errorf("no API resource matches kind %q (group %q, version %q); use get_api_resources to see what the cluster serves", kind, group, version)
+	case 1:
+		return matches[0], nil
+	}
+	var names []string
+	for _, m := range matches {
+		names = append(names, fmt.Sprintf("%s.%s/%s", m.Kind, m.GVR.Group, m.GVR.Version))
+	}
+	sort.Strings(names)
+	return APIResourceRef{}, fmt.Errorf("kind %q is ambiguous; set group to one of: %s", kind, strings.Join(names, ", "))
+}
+
+func resourceNameMatches(r metav1.APIResource, name string) bool {
+	if strings.EqualFold(r.Kind, name) || strings.EqualFold(r.Name, name) || strings.EqualFold(r.SingularName, name) {
+		return true
+	}
+	for _, short := range r.ShortNames {
+		if strings.EqualFold(short, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListResources lists objects of a resolved resource. The namespace is
+// ignored for cluster-scoped resources.
+func (c *ClusterClient) ListResources(ctx context.Context, ref APIResourceRef, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	if !ref.Namespaced {
+		return c.ListClusterCustomResources(ctx, ref.GVR, opts)
+	}
+	return c.ListCustomResources(ctx, ref.GVR, namespace, opts)
+}
+
+// ListResourcesPage returns one page of objects of a resolved resource. The
+// namespace is ignored for cluster-scoped resources.
+func (c *ClusterClient) ListResourcesPage(ctx context.Context, ref APIResourceRef, namespace string, opts metav1.ListOptions) (Page[unstructured.Unstructured], error) {
+	if c.DynamicClient == nil {
+		return Page[unstructured.Unstructured]{}, fmt.Errorf("dynamic client not available")
+	}
+	items := func(l *unstructured.UnstructuredList) []unstructured.Unstructured { return l.Items }
+	if !ref.Namespaced {
+		return clusterPage(ctx, c, ref.GVR.Resource, opts, func(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+			return c.DynamicClient.Resource(ref.GVR).List(ctx, opts)
+		}, items)
+	}
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+		return c.DynamicClient.Resource(ref.GVR).Namespace(ns).List(ctx, opts)
+	}, items)
+}
+
+// GetResource fetches one object of a resolved resource. The namespace is
+// ignored for cluster-scoped resources.
+func (c *ClusterClient) GetResource(ctx context.Context, ref APIResourceRef, namespace, name string) (*unstructured.Unstructured, error) {
+	if !ref.Namespaced {
+		if err := c.clusterScope(ref.GVR.Resource); err != nil {
+			return nil, err
+		}
+	}
+	if c.DynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client not available")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	if !ref.Namespaced {
+		return c.DynamicClient.Resource(ref.GVR).Get(ctx, name, metav1.GetOptions{})
+	}
+	return c.DynamicClient.Resource(ref.GVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
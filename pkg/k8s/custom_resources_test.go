@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestListCustomResources(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "security.istio.io", Version: "v1beta1", Resource: "peerauthentications"}
+	pa := func(ns, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("security.istio.io/v1beta1")
+		u.SetKind("PeerAuthentication")
+		u.SetNamespace(ns)
+		u.SetName(name)
+		return u
+	}
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "PeerAuthenticationList"},
+		pa("istio-system", "default"), pa("shop", "strict"))
+
+	client := NewClusterClientForTesting(fake.NewSimpleClientset(), nil)
+	if _, err := client.ListCustomResources(context.Background(), gvr, "", metav1.ListOptions{}); err == nil {
+		t.Error("expected an error without a dynamic client")
+	}
+
+	client.DynamicClient = dyn
+	items, err := client.ListCustomResources(context.Background(), gvr, "", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListCustomResources() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expected 2 objects across namespaces, got %d", len(items))
+	}
+
+	client.Namespaces = []string{"shop"}
+	items, err = client.ListCustomResources(context.Background(), gvr, "", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListCustomResources() scoped error = %v", err)
+	}
+	if len(items) != 1 || items[0].GetName() != "strict" {
+		t.Errorf("expected only the shop object in namespace-scoped mode, got %v", items)
+	}
+}
+
+func TestResolveResource(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "cert-manager.io/v1", APIResources: []metav1.APIResource{
+			{Name: "certificates", SingularName: "certificate", Kind: "Certificate", Namespaced: true, ShortNames: []string{"cert"}},
+			{Name: "certificates/status", Kind: "Certificate", Namespaced: true},
+			{Name: "clusterissuers", SingularName: "clusterissuer", Kind: "ClusterIssuer"},
+		}},
+		{GroupVersion: "networking.internal.knative.dev/v1alpha1", APIResources: []metav1.APIResource{
+			{Name: "certificates", SingularName: "certificate", Kind: "Certificate", Namespaced: true},
+		}},
+	}
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	ref, err := client.ResolveResource(context.Background(), "cert-manager.io", "", "cert")
+	if err != nil {
+		t.Fatalf("ResolveResource() error = %v", err)
+	}
+	if ref.GVR.Resource != "certificates" || ref.GVR.Version != "v1" || !ref.Namespaced || ref.Kind != "Certificate" {
+		t.Errorf("unexpected resolution: %+v", ref)
+	}
+
+	ref, err = client.ResolveResource(context.Background(), "", "", "clusterissuer")
+	if err != nil || ref.Namespaced {
+		t.Errorf("expected cluster-scoped ClusterIssuer, got %+v, %v", ref, err)
+	}
+
+	if _, err := client.ResolveResource(context.Background(), "", "", "Certificate"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected ambiguous kind error, got %v", err)
+	}
+	if _, err := client.ResolveResource(context.Background(), "", "", "Widget"); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
+
+func TestGetResource(t *testing.T) {
+	ref := APIResourceRef{GVR: schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "clusterissuers"}, Kind: "ClusterIssuer"}
+	issuer := &unstructured.Unstructured{}
+	issuer.SetAPIVersion("cert-manager.io/v1")
+	issuer.SetKind("ClusterIssuer")
+	issuer.SetName("letsencrypt")
+	client := NewClusterClientForTesting(fake.NewSimpleClientset(), nil)
+	client.DynamicClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), issuer)
+
+	got, err := client.GetResource(context.Background(), ref, "ignored", "letsencrypt")
+	if err != nil || got.GetName() != "letsencrypt" {
+		t.Errorf("GetResource() = %v, %v", got, err)
+	}
+
+	client.Namespaces = []string{"shop"}
+	if _, err := client.GetResource(context.Background(), ref, "", "letsencrypt"); err == nil {
+		t.Error("expected a scope error for a cluster-scoped resource in namespace-scoped mode")
+	}
+}
@@ -13,11 +13,15 @@ import (
 
 // ListCRDs returns custom resource definitions from the cluster.
 func (c *ClusterClient) ListCRDs(ctx context.Context) ([]apiextensionsv1.CustomResourceDefinition, error) {
+	if err := c.clusterScope("CustomResourceDefinitions"); err != nil {
+		return nil, err
+	}
+
 	if c.ApiextensionsClient == nil {
 		return nil, fmt.Errorf("apiextensions client not available")
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.ApiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
@@ -27,21 +31,25 @@ func (c *ClusterClient) ListCRDs(ctx context.Context) ([]apiextensionsv1.CustomR
 	return list.Items, nil
 }
 
-// ListMutatingWebhookConfigurations returns mutating webhook configurations.
-func (c *ClusterClient) ListMutatingWebhookConfigurations(ctx context.Context) ([]admissionregistrationv1.MutatingWebhookConfiguration, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
-	defer cancel()
-
-	list, err := c.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, err
+// ListCRDsPage returns one page of custom resource definitions.
+func (c *ClusterClient) ListCRDsPage(ctx context.Context, opts metav1.ListOptions) (Page[apiextensionsv1.CustomResourceDefinition], error) {
+	if c.ApiextensionsClient == nil {
+		return Page[apiextensionsv1.CustomResourceDefinition]{}, fmt.Errorf("apiextensions client not available")
 	}
-	return list.Items, nil
+	return clusterPage(ctx, c, "CustomResourceDefinitions", opts, func(ctx context.Context, opts metav1.ListOptions) (*apiextensionsv1.CustomResourceDefinitionList, error) {
+		return c.ApiextensionsClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, opts)
+	}, func(l *apiextensionsv1.CustomResourceDefinitionList) []apiextensionsv1.CustomResourceDefinition {
+		return l.Items
+	})
 }
 
 // ListValidatingWebhookConfigurations returns validating webhook configurations.
 func (c *ClusterClient) ListValidatingWebhookConfigurations(ctx context.Context) ([]admissionregistrationv1.ValidatingWebhookConfiguration, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if err := c.clusterScope("ValidatingWebhookConfigurations"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
@@ -51,9 +59,27 @@ func (c *ClusterClient) ListValidatingWebhookConfigurations(ctx context.Context)
 	return list.Items, nil
 }
 
+// ListMutatingWebhookConfigurationsPage returns one page of mutating webhook configurations.
+func (c *ClusterClient) ListMutatingWebhookConfigurationsPage(ctx context.Context, opts metav1.ListOptions) (Page[admissionregistrationv1.MutatingWebhookConfiguration], error) {
+	return clusterPage(ctx, c, "MutatingWebhookConfigurations", opts, func(ctx context.Context, opts metav1.ListOptions) (*admissionregistrationv1.MutatingWebhookConfigurationList, error) {
+		return c.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, opts)
+	}, func(l *admissionregistrationv1.MutatingWebhookConfigurationList) []admissionregistrationv1.MutatingWebhookConfiguration {
+		return l.Items
+	})
+}
+
+// ListValidatingWebhookConfigurationsPage returns one page of validating webhook configurations.
+func (c *ClusterClient) ListValidatingWebhookConfigurationsPage(ctx context.Context, opts metav1.ListOptions) (Page[admissionregistrationv1.ValidatingWebhookConfiguration], error) {
+	return clusterPage(ctx, c, "ValidatingWebhookConfigurations", opts, func(ctx context.Context, opts metav1.ListOptions) (*admissionregistrationv1.ValidatingWebhookConfigurationList, error) {
+		return c.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, opts)
+	}, func(l *admissionregistrationv1.ValidatingWebhookConfigurationList) []admissionregistrationv1.ValidatingWebhookConfiguration {
+		return l.Items
+	})
+}
+
 // GetAPIResources returns server API resources grouped by API group.
 func (c *ClusterClient) GetAPIResources(ctx context.Context) ([]*metav1.APIResourceList, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	_, resourceLists, err := c.Clientset.Discovery().ServerGroupsAndResources()
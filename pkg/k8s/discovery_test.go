@@ -9,7 +9,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 )
 
-func TestListMutatingWebhookConfigurations(t *testing.T) {
+func TestListMutatingWebhookConfigurationsPage(t *testing.T) {
 	failPolicy := admissionregistrationv1.Fail
 	fakeClient := fake.NewSimpleClientset(
 		&admissionregistrationv1.MutatingWebhookConfiguration{
@@ -33,10 +33,11 @@ func TestListMutatingWebhookConfigurations(t *testing.T) {
 
 	client := NewClusterClientForTesting(fakeClient, nil)
 
-	configs, err := client.ListMutatingWebhookConfigurations(context.Background())
+	page, err := client.ListMutatingWebhookConfigurationsPage(context.Background(), metav1.ListOptions{Limit: 10})
 	if err != nil {
-		t.Fatalf("ListMutatingWebhookConfigurations() error = %v", err)
+		t.Fatalf("ListMutatingWebhookConfigurationsPage() error = %v", err)
 	}
+	configs := page.Items
 	if len(configs) != 1 {
 		t.Errorf("expected 1 mutating webhook config, got %d", len(configs))
 	}
@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// Warnings collects the warning headers the API server returns for requests
+// made with a context from WithWarnings: deprecated APIs, Pod Security warn
+// mode, unknown fields under fieldValidation=Warn, and admission webhook
+// warnings.
+type Warnings struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+type warningsKey struct{}
+
+// WithWarnings returns a context whose API requests record their warnings in
+// the returned collector instead of the log.
+func WithWarnings(ctx context.Context) (context.Context, *Warnings) {
+	w := &Warnings{}
+	return context.WithValue(ctx, warningsKey{}, w), w
+}
+
+// Take returns the collected warnings, deduplicated, and resets the collector.
+func (w *Warnings) Take() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []string
+	for _, m := range w.msgs {
+		if !slices.Contains(out, m) {
+			out = append(out, m)
+		}
+	}
+	w.msgs = nil
+	return out
+}
+
+// warningHandler sends warnings to the context's collector, or logs them as
+// client-go does by default.
+type warningHandler struct{}
+
+func (warningHandler) HandleWarningHeaderWithContext(ctx context.Context, code int, agent, text string) {
+	if w, ok := ctx.Value(warningsKey{}).(*Warnings); ok && code == 299 && text != "" {
+		w.mu.Lock()
+		w.msgs = append(w.msgs, text)
+		w.mu.Unlock()
+		return
+	}
+	rest.WarningLogger{}.HandleWarningHeaderWithContext(ctx, code, agent, text)
+}
+
+// DryRunApply sends obj as a server-side apply with dryRun=All and strict
+// field validation. The API server defaults and validates it and runs
+// admission, including webhooks and ValidatingAdmissionPolicies, then returns
+// the object it would store without persisting anything. The namespace is
+// ignored for cluster-scoped resources.
+func (c *ClusterClient) DryRunApply(ctx context.Context, ref APIResourceRef, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if !ref.Namespaced {
+		if err := c.clusterScope(ref.GVR.Resource); err != nil {
+			return nil, err
+		}
+	} else if c.IsNamespaceScoped() && !slices.Contains(c.Namespaces, namespace) {
+		return nil, fmt.Errorf("namespace %q is outside this server's namespaces (%s)", namespace, strings.Join(c.Namespaces, ", "))
+	}
+	if c.DynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client not available")
+	}
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	force := true
+	opts := metav1.PatchOptions{
+		DryRun:          dryRunOpts(true),
+		FieldManager:    "kube-doctor",
+		FieldValidation: metav1.FieldValidationStrict,
+		// Take over fields owned by other managers, as kubectl apply would,
+		// so ownership conflicts do not hide validation results.
+		Force: &force,
+	}
+	if !ref.Namespaced {
+		return c.DynamicClient.Resource(ref.GVR).Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts)
+	}
+	return c.DynamicClient.Resource(ref.GVR).Namespace(namespace).Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts)
+}
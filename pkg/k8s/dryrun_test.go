@@ -0,0 +1,26 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	ctx, w := WithWarnings(context.Background())
+	h := warningHandler{}
+	h.HandleWarningHeaderWithContext(ctx, 299, "-", "apps/v1beta1 Deployment is deprecated")
+	h.HandleWarningHeaderWithContext(ctx, 299, "-", "apps/v1beta1 Deployment is deprecated")
+	h.HandleWarningHeaderWithContext(ctx, 199, "-", "not a warning")
+	if got := w.Take(); len(got) != 1 || got[0] != "apps/v1beta1 Deployment is deprecated" {
+		t.Errorf("expected one deduplicated warning, got %v", got)
+	}
+	if got := w.Take(); len(got) != 0 {
+		t.Errorf("expected Take to reset the collector, got %v", got)
+	}
+
+	// Without a collector the warning is logged, not recorded
+	h.HandleWarningHeaderWithContext(context.Background(), 299, "-", "logged")
+	if got := w.Take(); len(got) != 0 {
+		t.Errorf("expected no warnings from other contexts, got %v", got)
+	}
+}
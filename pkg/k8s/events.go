@@ -2,17 +2,66 @@ package k8s
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
 )
 
+// EventFilter narrows events by reason, involved object kind, repeat count, and
+// recency. Zero-valued fields match everything.
+type EventFilter struct {
+	Reasons  []string
+	Kind     string
+	MinCount int32
+	Since    time.Time
+
+	// Limit caps the number of events returned; 0 means util.MaxEvents.
+	Limit int
+}
+
+// Matches reports whether the event satisfies the filter.
+func (f EventFilter) Matches(e *corev1.Event) bool {
+	if f.Kind != "" && !strings.EqualFold(e.InvolvedObject.Kind, f.Kind) {
+		return false
+	}
+	if f.MinCount > 0 && e.Count < f.MinCount {
+		return false
+	}
+	if !f.Since.IsZero() && EventTime(e).Before(f.Since) {
+		return false
+	}
+	if len(f.Reasons) == 0 {
+		return true
+	}
+	for _, r := range f.Reasons {
+		if strings.EqualFold(e.Reason, r) {
+			return true
+		}
+	}
+	return false
+}
+
 // ListEvents returns events in the given namespace, optionally filtered.
 func (c *ClusterClient) ListEvents(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	return c.ListEventsMatching(ctx, namespace, opts, EventFilter{})
+}
+
+// ListEventsMatching returns the most recent events that satisfy filter.
+func (c *ClusterClient) ListEventsMatching(ctx context.Context, namespace string, opts metav1.ListOptions, filter EventFilter) ([]corev1.Event, error) {
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]corev1.Event, error) {
+			return c.ListEventsMatching(ctx, ns, opts, filter)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.CoreV1().Events(namespace).List(ctx, opts)
@@ -20,26 +69,45 @@ func (c *ClusterClient) ListEvents(ctx context.Context, namespace string, opts m
 		return nil, err
 	}
 
+	matched := list.Items[:0]
+	for i := range list.Items {
+		if filter.Matches(&list.Items[i]) {
+			matched = append(matched, list.Items[i])
+		}
+	}
+	list.Items = matched
+
 	// Sort by last timestamp (most recent first)
 	sort.Slice(list.Items, func(i, j int) bool {
-		ti := list.Items[i].LastTimestamp.Time
-		tj := list.Items[j].LastTimestamp.Time
-		if ti.IsZero() {
-			ti = list.Items[i].CreationTimestamp.Time
-		}
-		if tj.IsZero() {
-			tj = list.Items[j].CreationTimestamp.Time
-		}
-		return ti.After(tj)
+		return EventTime(&list.Items[i]).After(EventTime(&list.Items[j]))
 	})
 
-	// Truncate to MaxEvents
-	if len(list.Items) > util.MaxEvents {
-		return list.Items[:util.MaxEvents], nil
+	// Truncate to the filter limit (MaxEvents by default)
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = util.MaxEvents
+	}
+	if len(list.Items) > limit {
+		return list.Items[:limit], nil
 	}
 	return list.Items, nil
 }
 
+// EventTime returns when an event last occurred, falling back to its series
+// time, event time, and creation time for events that omit LastTimestamp.
+func EventTime(e *corev1.Event) time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if e.Series != nil && !e.Series.LastObservedTime.IsZero() {
+		return e.Series.LastObservedTime.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
 // GetEventsForObject returns events related to a specific object.
 func (c *ClusterClient) GetEventsForObject(ctx context.Context, namespace, objectName string) ([]corev1.Event, error) {
 	opts := metav1.ListOptions{
@@ -47,3 +115,50 @@ func (c *ClusterClient) GetEventsForObject(ctx context.Context, namespace, objec
 	}
 	return c.ListEvents(ctx, namespace, opts)
 }
+
+// FollowEvents watches for events that satisfy filter for the given duration and
+// returns every added or updated event observed, in arrival order. It returns
+// early without error if ctx is cancelled.
+func (c *ClusterClient) FollowEvents(ctx context.Context, namespace string, opts metav1.ListOptions, filter EventFilter, duration time.Duration) ([]corev1.Event, error) {
+	if c.fanOut(namespace) {
+		return nil, fmt.Errorf("following events across all namespaces is not available in namespace-scoped mode; specify one of: %s", strings.Join(c.Namespaces, ", "))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	// Start from the current resource version so existing events are not replayed
+	if opts.ResourceVersion == "" {
+		list, err := c.Clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: opts.FieldSelector, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		opts.ResourceVersion = list.ResourceVersion
+	}
+
+	w, err := c.Clientset.CoreV1().Events(namespace).Watch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Stop()
+
+	var observed []corev1.Event
+	for {
+		select {
+		case <-ctx.Done():
+			return observed, nil
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return observed, nil
+			}
+			if ev.Type != watch.Added && ev.Type != watch.Modified {
+				continue
+			}
+			e, isEvent := ev.Object.(*corev1.Event)
+			if !isEvent || !filter.Matches(e) {
+				continue
+			}
+			observed = append(observed, *e)
+		}
+	}
+}
@@ -2,11 +2,13 @@ package k8s
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -79,3 +81,102 @@ func TestGetEventsForObject(t *testing.T) {
 		t.Log("Note: fake clientset may not filter by field selector")
 	}
 }
+
+func TestListEventsMatching(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "mount", Namespace: "payments"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-0"},
+			Type:           "Warning",
+			Reason:         "FailedMount",
+			Count:          4,
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "backoff", Namespace: "payments"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-1"},
+			Type:           "Warning",
+			Reason:         "BackOff",
+			Count:          1,
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "pvc", Namespace: "payments"},
+			InvolvedObject: corev1.ObjectReference{Kind: "PersistentVolumeClaim", Name: "data"},
+			Type:           "Warning",
+			Reason:         "FailedMount",
+			Count:          1,
+		},
+	)
+
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	events, err := client.ListEventsMatching(context.Background(), "payments", metav1.ListOptions{},
+		EventFilter{Reasons: []string{"failedmount"}, Kind: "pod"})
+	if err != nil {
+		t.Fatalf("ListEventsMatching() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Name != "mount" {
+		t.Errorf("expected only the pod FailedMount event, got %d events", len(events))
+	}
+
+	events, err = client.ListEventsMatching(context.Background(), "payments", metav1.ListOptions{}, EventFilter{MinCount: 2})
+	if err != nil {
+		t.Fatalf("ListEventsMatching() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Errorf("expected 1 event with count >= 2, got %d", len(events))
+	}
+}
+
+func TestFollowEvents(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		for _, reason := range []string{"FailedMount", "Pulled"} {
+			_, _ = fakeClient.CoreV1().Events("payments").Create(context.Background(), &corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "ev-" + reason, Namespace: "payments"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-0"},
+				Reason:         reason,
+			}, metav1.CreateOptions{})
+		}
+	}()
+
+	events, err := client.FollowEvents(context.Background(), "payments", metav1.ListOptions{},
+		EventFilter{Reasons: []string{"FailedMount"}}, 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("FollowEvents() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Reason != "FailedMount" {
+		t.Errorf("expected 1 followed FailedMount event, got %d", len(events))
+	}
+}
+
+func TestListEventsMatchingSinceAndLimit(t *testing.T) {
+	now := time.Now()
+	var objects []runtime.Object
+	for i := 0; i < 60; i++ {
+		objects = append(objects, &corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Name: fmt.Sprintf("event-%d", i), Namespace: "default"},
+			LastTimestamp: metav1.NewTime(now.Add(-time.Duration(i) * time.Minute)),
+		})
+	}
+	client := NewClusterClientForTesting(fake.NewSimpleClientset(objects...), nil)
+
+	events, err := client.ListEventsMatching(context.Background(), "default", metav1.ListOptions{},
+		EventFilter{Since: now.Add(-30*time.Minute - time.Second)})
+	if err != nil {
+		t.Fatalf("ListEventsMatching() error = %v", err)
+	}
+	if len(events) != 31 {
+		t.Errorf("expected 31 events in the last 30m, got %d", len(events))
+	}
+
+	events, err = client.ListEventsMatching(context.Background(), "default", metav1.ListOptions{}, EventFilter{Limit: 100})
+	if err != nil {
+		t.Fatalf("ListEventsMatching() error = %v", err)
+	}
+	if len(events) != 60 {
+		t.Errorf("expected limit to lift the default cap, got %d", len(events))
+	}
+}
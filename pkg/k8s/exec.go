@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// ExecResult holds the captured output of a command run inside a container.
+type ExecResult struct {
+	Stdout string
+	Stderr string
+
+	// Truncated is set when output exceeded util.MaxLogBytes and was cut.
+	Truncated bool
+}
+
+// ExecInPod runs a non-interactive command in a pod container and captures its output.
+// A non-zero exit status is returned as an error alongside the captured output.
+func (c *ClusterClient) ExecInPod(ctx context.Context, namespace, pod, container string, command []string) (*ExecResult, error) {
+	if c.Config == nil {
+		return nil, fmt.Errorf("exec requires a live cluster connection")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	req := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	// Prefer WebSockets and fall back to SPDY for older API servers, as kubectl does
+	wsExec, err := remotecommand.NewWebSocketExecutor(c.Config, "GET", req.URL().String())
+	if err != nil {
+		return nil, err
+	}
+	spdyExec, err := remotecommand.NewSPDYExecutor(c.Config, "POST", req.URL())
+	if err != nil {
+		return nil, err
+	}
+	executor, err := remotecommand.NewFallbackExecutor(wsExec, spdyExec, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := &limitedWriter{limit: util.MaxLogBytes}
+	stderr := &limitedWriter{limit: util.MaxLogBytes}
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	return &ExecResult{
+		Stdout:    stdout.buf.String(),
+		Stderr:    stderr.buf.String(),
+		Truncated: stdout.truncated || stderr.truncated,
+	}, err
+}
+
+// limitedWriter buffers up to limit bytes and discards the rest, recording
+// that output was truncated.
+type limitedWriter struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	remaining := w.limit - w.buf.Len()
+	if len(p) > remaining {
+		w.truncated = true
+		if remaining > 0 {
+			w.buf.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	w.buf.Write(p)
+	return len(p), nil
+}
@@ -0,0 +1,195 @@
+package k8s
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HelmReleaseSecretType is the Secret type Helm v3 stores each release revision in.
+const HelmReleaseSecretType corev1.SecretType = "helm.sh/release.v1"
+
+// HelmRelease is one revision of a Helm v3 release, decoded from its storage Secret.
+type HelmRelease struct {
+	Name          string
+	Namespace     string
+	Revision      int
+	Status        string // deployed, failed, pending-upgrade, superseded, ...
+	Chart         string
+	ChartVersion  string
+	AppVersion    string
+	FirstDeployed time.Time
+	LastDeployed  time.Time
+	Description   string
+	Values        map[string]any // user-supplied values, as `helm get values` shows
+	Manifest      string
+}
+
+// helmReleaseRecord is the JSON layout of a Helm v3 release.
+type helmReleaseRecord struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		FirstDeployed time.Time `json:"first_deployed"`
+		LastDeployed  time.Time `json:"last_deployed"`
+		Description   string    `json:"description"`
+		Status        string    `json:"status"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+	Config   map[string]any `json:"config"`
+	Manifest string         `json:"manifest"`
+}
+
+// DecodeHelmRelease decodes a Helm v3 release Secret. Helm stores the release
+// as gzipped JSON, base64-encoded on top of the Secret's own encoding.
+func DecodeHelmRelease(s *corev1.Secret) (*HelmRelease, error) {
+	raw, ok := s.Data["release"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no release key", s.Namespace, s.Name)
+	}
+	data, err := base64.StdEncoding.DecodeString(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("decoding release %s/%s: %w", s.Namespace, s.Name, err)
+	}
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decompressing release %s/%s: %w", s.Namespace, s.Name, err)
+		}
+		defer zr.Close()
+		if data, err = io.ReadAll(zr); err != nil {
+			return nil, fmt.Errorf("decompressing release %s/%s: %w", s.Namespace, s.Name, err)
+		}
+	}
+
+	var rec helmReleaseRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("parsing release %s/%s: %w", s.Namespace, s.Name, err)
+	}
+	ns := rec.Namespace
+	if ns == "" {
+		ns = s.Namespace
+	}
+	return &HelmRelease{
+		Name:          rec.Name,
+		Namespace:     ns,
+		Revision:      rec.Version,
+		Status:        rec.Info.Status,
+		Chart:         rec.Chart.Metadata.Name,
+		ChartVersion:  rec.Chart.Metadata.Version,
+		AppVersion:    rec.Chart.Metadata.AppVersion,
+		FirstDeployed: rec.Info.FirstDeployed,
+		LastDeployed:  rec.Info.LastDeployed,
+		Description:   rec.Info.Description,
+		Values:        rec.Config,
+		Manifest:      rec.Manifest,
+	}, nil
+}
+
+// ListHelmReleaseRevisions returns every stored revision of the Helm v3
+// releases in the namespace (empty = all namespaces), optionally only those of
+// the named release, sorted by namespace, name, and revision. Secrets that
+// cannot be decoded are skipped and counted.
+func (c *ClusterClient) ListHelmReleaseRevisions(ctx context.Context, namespace, name string) ([]HelmRelease, int, error) {
+	selector := "owner=helm"
+	if name != "" {
+		selector += ",name=" + name
+	}
+	secrets, err := c.ListSecrets(ctx, namespace, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, 0, err
+	}
+	releases, skipped := decodeHelmReleases(secrets, nil)
+	sortHelmReleases(releases)
+	return releases, skipped, nil
+}
+
+// ListHelmReleaseRevisionsPage returns the revisions stored in one page of
+// Helm v3 release Secrets, sorted like ListHelmReleaseRevisions. A release's
+// revisions can straddle a page boundary, so the releases of the first and
+// last Secret on the page are read in full; such a release may appear on two
+// consecutive pages, but always with its true latest revision.
+func (c *ClusterClient) ListHelmReleaseRevisionsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[HelmRelease], int, error) {
+	opts.LabelSelector = "owner=helm"
+	secrets, err := listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.SecretList, error) {
+		return c.Clientset.CoreV1().Secrets(ns).List(ctx, opts)
+	}, func(l *corev1.SecretList) []corev1.Secret {
+		return l.Items
+	})
+	if err != nil {
+		return Page[HelmRelease]{}, 0, err
+	}
+
+	type releaseKey struct{ namespace, name string }
+	boundary := make(map[releaseKey]bool)
+	if n := len(secrets.Items); n > 0 {
+		if opts.Continue != "" {
+			boundary[releaseKey{secrets.Items[0].Namespace, secrets.Items[0].Labels["name"]}] = true
+		}
+		if secrets.Continue != "" {
+			boundary[releaseKey{secrets.Items[n-1].Namespace, secrets.Items[n-1].Labels["name"]}] = true
+		}
+	}
+
+	releases, skipped := decodeHelmReleases(secrets.Items, func(s *corev1.Secret) bool {
+		return boundary[releaseKey{s.Namespace, s.Labels["name"]}]
+	})
+	for key := range boundary {
+		history, n, err := c.ListHelmReleaseRevisions(ctx, key.namespace, key.name)
+		if err != nil {
+			return Page[HelmRelease]{}, 0, err
+		}
+		releases = append(releases, history...)
+		skipped += n
+	}
+	sortHelmReleases(releases)
+	return Page[HelmRelease]{Items: releases, Continue: secrets.Continue, Remaining: secrets.Remaining}, skipped, nil
+}
+
+// decodeHelmReleases decodes the Helm v3 release Secrets that skip does not
+// exclude, returning the releases and how many Secrets failed to decode.
+func decodeHelmReleases(secrets []corev1.Secret, skip func(*corev1.Secret) bool) ([]HelmRelease, int) {
+	var releases []HelmRelease
+	skipped := 0
+	for i := range secrets {
+		if secrets[i].Type != HelmReleaseSecretType || (skip != nil && skip(&secrets[i])) {
+			continue
+		}
+		rel, err := DecodeHelmRelease(&secrets[i])
+		if err != nil {
+			skipped++
+			continue
+		}
+		releases = append(releases, *rel)
+	}
+	return releases, skipped
+}
+
+func sortHelmReleases(releases []HelmRelease) {
+	sort.Slice(releases, func(i, j int) bool {
+		a, b := releases[i], releases[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Revision < b.Revision
+	})
+}
@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func helmReleaseSecret(t *testing.T, ns, name string, revision int, releaseJSON string) *corev1.Secret {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(releaseJSON)); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, revision),
+			Labels:    map[string]string{"owner": "helm", "name": name},
+		},
+		Type: HelmReleaseSecretType,
+		Data: map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(buf.Bytes()))},
+	}
+}
+
+func TestListHelmReleaseRevisions(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		helmReleaseSecret(t, "cache", "redis", 2, `{"name":"redis","namespace":"cache","version":2,
+			"info":{"status":"failed","description":"Upgrade \"redis\" failed: timed out","last_deployed":"2025-06-01T10:00:00Z"},
+			"chart":{"metadata":{"name":"redis","version":"18.1.0","appVersion":"7.2.4"}},
+			"config":{"replica":{"replicaCount":3}}}`),
+		helmReleaseSecret(t, "cache", "redis", 1, `{"name":"redis","namespace":"cache","version":1,
+			"info":{"status":"superseded"},"chart":{"metadata":{"name":"redis","version":"18.0.2"}}}`),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cache", Name: "sh.helm.release.v1.broken.v1", Labels: map[string]string{"owner": "helm", "name": "broken"}},
+			Type:       HelmReleaseSecretType,
+			Data:       map[string][]byte{"release": []byte("not base64!")},
+		},
+	)
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	releases, skipped, err := client.ListHelmReleaseRevisions(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("ListHelmReleaseRevisions() error = %v", err)
+	}
+	if skipped != 1 || len(releases) != 2 {
+		t.Fatalf("expected 2 decoded revisions and 1 skipped, got %d and %d", len(releases), skipped)
+	}
+	latest := releases[1]
+	if latest.Revision != 2 || latest.Status != "failed" || latest.ChartVersion != "18.1.0" || latest.AppVersion != "7.2.4" || latest.LastDeployed.IsZero() {
+		t.Errorf("unexpected latest revision: %+v", latest)
+	}
+	if replica, ok := latest.Values["replica"].(map[string]any); !ok || replica["replicaCount"] != float64(3) {
+		t.Errorf("expected user values to be decoded, got %v", latest.Values)
+	}
+
+	releases, _, err = client.ListHelmReleaseRevisions(context.Background(), "cache", "broken")
+	if err != nil || len(releases) != 0 {
+		t.Errorf("expected no decodable revisions for broken, got %v, %v", releases, err)
+	}
+}
+
+func TestListHelmReleaseRevisionsPage(t *testing.T) {
+	release := func(name string, revision int, status string) *corev1.Secret {
+		return helmReleaseSecret(t, "cache", name, revision, fmt.Sprintf(`{"name":%q,"namespace":"cache","version":%d,
+			"info":{"status":%q},"chart":{"metadata":{"name":%q,"version":"1.0.0"}}}`, name, revision, status, name))
+	}
+	// In API server order: redis's revisions sort as v1, v10, v2 and straddle the page boundary.
+	secrets := []*corev1.Secret{release("redis", 1, "superseded"), release("redis", 10, "deployed"), release("redis", 2, "superseded"), release("web", 1, "failed")}
+	fakeClient := fake.NewSimpleClientset(secrets[0], secrets[1], secrets[2], secrets[3])
+	fakeClient.PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		opts := action.(k8stesting.ListActionImpl).ListOptions
+		if opts.Limit == 0 {
+			return false, nil, nil
+		}
+		start, _ := strconv.Atoi(opts.Continue)
+		end := min(start+int(opts.Limit), len(secrets))
+		list := &corev1.SecretList{}
+		if end < len(secrets) {
+			list.Continue = strconv.Itoa(end)
+		}
+		for _, s := range secrets[start:end] {
+			list.Items = append(list.Items, *s)
+		}
+		return true, list, nil
+	})
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	latest := func(releases []HelmRelease) map[string]string {
+		got := make(map[string]string)
+		for _, r := range releases {
+			got[r.Name] = fmt.Sprintf("v%d %s", r.Revision, r.Status)
+		}
+		return got
+	}
+	page, _, err := client.ListHelmReleaseRevisionsPage(context.Background(), "", metav1.ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListHelmReleaseRevisionsPage() error = %v", err)
+	}
+	if got := latest(page.Items); page.Continue != "2" || len(got) != 1 || got["redis"] != "v10 deployed" || len(page.Items) != 3 {
+		t.Errorf("first page = %v (continue %q), want the full redis history ending at v10", got, page.Continue)
+	}
+
+	page, _, err = client.ListHelmReleaseRevisionsPage(context.Background(), "", metav1.ListOptions{Limit: 2, Continue: page.Continue})
+	if err != nil {
+		t.Fatalf("ListHelmReleaseRevisionsPage() error = %v", err)
+	}
+	if got := latest(page.Items); page.Continue != "" || got["redis"] != "v10 deployed" || got["web"] != "v1 failed" {
+		t.Errorf("last page = %v (continue %q), want redis at v10 and web", got, page.Continue)
+	}
+}
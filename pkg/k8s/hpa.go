@@ -11,7 +11,13 @@ import (
 
 // ListHPAs returns horizontal pod autoscalers in the given namespace.
 func (c *ClusterClient) ListHPAs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+			return c.ListHPAs(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, opts)
@@ -20,3 +26,12 @@ func (c *ClusterClient) ListHPAs(ctx context.Context, namespace string, opts met
 	}
 	return list.Items, nil
 }
+
+// ListHPAsPage returns one page of HPAs in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListHPAsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[autoscalingv2.HorizontalPodAutoscaler], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*autoscalingv2.HorizontalPodAutoscalerList, error) {
+		return c.Clientset.AutoscalingV2().HorizontalPodAutoscalers(ns).List(ctx, opts)
+	}, func(l *autoscalingv2.HorizontalPodAutoscalerList) []autoscalingv2.HorizontalPodAutoscaler {
+		return l.Items
+	})
+}
@@ -11,7 +11,13 @@ import (
 
 // ListLimitRanges returns limit ranges in the given namespace.
 func (c *ClusterClient) ListLimitRanges(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.LimitRange, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]corev1.LimitRange, error) {
+			return c.ListLimitRanges(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.CoreV1().LimitRanges(namespace).List(ctx, opts)
@@ -20,3 +26,10 @@ func (c *ClusterClient) ListLimitRanges(ctx context.Context, namespace string, o
 	}
 	return list.Items, nil
 }
+
+// ListLimitRangesPage returns one page of LimitRanges in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListLimitRangesPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[corev1.LimitRange], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.LimitRangeList, error) {
+		return c.Clientset.CoreV1().LimitRanges(ns).List(ctx, opts)
+	}, func(l *corev1.LimitRangeList) []corev1.LimitRange { return l.Items })
+}
@@ -1,8 +1,10 @@
 package k8s
 
 import (
+	"bufio"
 	"context"
 	"io"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -12,7 +14,7 @@ import (
 
 // GetPodLogs retrieves logs from a pod container.
 func (c *ClusterClient) GetPodLogs(ctx context.Context, namespace, name, container string, tailLines int64, previous bool, sinceDuration string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	opts := &corev1.PodLogOptions{
@@ -54,3 +56,114 @@ func (c *ClusterClient) GetPodLogs(ctx context.Context, namespace, name, contain
 
 	return result, nil
 }
+
+// LogStreamOptions bounds a followed log stream.
+type LogStreamOptions struct {
+	Container string
+	// TailLines is how many lines of history to include before following.
+	TailLines int64
+	// Duration is how long to follow the stream.
+	Duration time.Duration
+	// MaxBytes stops the stream once this many bytes have been captured.
+	MaxBytes int
+	// Timestamps prefixes every line with its RFC3339Nano timestamp.
+	Timestamps bool
+}
+
+// LogWindow is the part of a followed log stream captured by StreamPodLogs.
+type LogWindow struct {
+	Lines   []string
+	Bytes   int
+	Elapsed time.Duration
+	// BudgetReached is set when the stream was cut at MaxBytes.
+	BudgetReached bool
+	// Ended is set when the API server closed the stream before the window
+	// elapsed, usually because the container exited.
+	Ended bool
+}
+
+// StreamPodLogs follows a pod container's logs until the window elapses, the
+// byte budget is spent, or the stream ends, and returns the captured lines.
+func (c *ClusterClient) StreamPodLogs(ctx context.Context, namespace, name string, opts LogStreamOptions) (*LogWindow, error) {
+	window, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	logOpts := &corev1.PodLogOptions{
+		Container:  opts.Container,
+		Follow:     true,
+		Timestamps: opts.Timestamps,
+		TailLines:  &opts.TailLines,
+	}
+
+	start := time.Now()
+	stream, err := c.Clientset.CoreV1().Pods(namespace).GetLogs(name, logOpts).Stream(window)
+	if err != nil {
+		if ctx.Err() == nil && window.Err() != nil {
+			return &LogWindow{Elapsed: time.Since(start)}, nil
+		}
+		return nil, err
+	}
+	defer stream.Close()
+
+	result := &LogWindow{}
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if opts.MaxBytes > 0 && result.Bytes+len(line) > opts.MaxBytes {
+				result.BudgetReached = true
+				break
+			}
+			result.Bytes += len(line)
+			result.Lines = append(result.Lines, strings.TrimRight(line, "\r\n"))
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			result.Ended = window.Err() == nil
+			break
+		}
+	}
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+// SearchPodLogs scans the last tailLines lines of a pod container's logs, no
+// older than since (zero for no bound), and returns the lines containing
+// needle, each prefixed with its RFC3339Nano timestamp.
+func (c *ClusterClient) SearchPodLogs(ctx context.Context, namespace, name, container string, tailLines int64, since time.Duration, needle string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	opts := &corev1.PodLogOptions{
+		Container:  container,
+		Timestamps: true,
+		TailLines:  &tailLines,
+	}
+	if since > 0 {
+		sinceSeconds := int64(since.Seconds())
+		opts.SinceSeconds = &sinceSeconds
+	}
+
+	stream, err := c.Clientset.CoreV1().Pods(namespace).GetLogs(name, opts).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var matches []string
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.Contains(line, needle) {
+			matches = append(matches, strings.TrimRight(line, "\r\n"))
+		}
+		if err == io.EOF {
+			return matches, nil
+		}
+		if err != nil {
+			return matches, err
+		}
+	}
+}
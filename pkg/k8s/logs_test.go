@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStreamPodLogs(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}})
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	// The fake clientset serves "fake logs" and closes the stream.
+	window, err := client.StreamPodLogs(context.Background(), "shop", "web", LogStreamOptions{Duration: 5 * time.Second, MaxBytes: 1024})
+	if err != nil {
+		t.Fatalf("StreamPodLogs() error = %v", err)
+	}
+	if len(window.Lines) != 1 || window.Lines[0] != "fake logs" || !window.Ended || window.BudgetReached {
+		t.Errorf("window = %+v, want one line and an ended stream", window)
+	}
+
+	window, err = client.StreamPodLogs(context.Background(), "shop", "web", LogStreamOptions{Duration: 5 * time.Second, MaxBytes: 4})
+	if err != nil {
+		t.Fatalf("StreamPodLogs() error = %v", err)
+	}
+	if len(window.Lines) != 0 || !window.BudgetReached {
+		t.Errorf("window = %+v, want the byte budget reached before the first line", window)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.StreamPodLogs(ctx, "shop", "web", LogStreamOptions{Duration: 5 * time.Second}); err == nil {
+		t.Error("expected an error for a cancelled context")
+	}
+}
+
+func TestSearchPodLogs(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}})
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	lines, err := client.SearchPodLogs(context.Background(), "shop", "web", "", 100, time.Hour, "fake")
+	if err != nil {
+		t.Fatalf("SearchPodLogs() error = %v", err)
+	}
+	if len(lines) != 1 {
+		t.Errorf("got %d matches, want 1", len(lines))
+	}
+	lines, err = client.SearchPodLogs(context.Background(), "shop", "web", "", 100, 0, "trace-123")
+	if err != nil || len(lines) != 0 {
+		t.Errorf("SearchPodLogs() = %v, %v, want no matches", lines, err)
+	}
+}
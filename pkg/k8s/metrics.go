@@ -12,11 +12,15 @@ import (
 
 // GetNodeMetrics returns resource usage metrics for all nodes.
 func (c *ClusterClient) GetNodeMetrics(ctx context.Context) ([]metricsv1beta1.NodeMetrics, error) {
+	if err := c.clusterScope("Node metrics"); err != nil {
+		return nil, err
+	}
+
 	if c.MetricsClient == nil {
 		return nil, fmt.Errorf("metrics-server not available (MetricsClient is nil)")
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.MetricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
@@ -28,11 +32,17 @@ func (c *ClusterClient) GetNodeMetrics(ctx context.Context) ([]metricsv1beta1.No
 
 // GetPodMetrics returns resource usage metrics for pods in a namespace.
 func (c *ClusterClient) GetPodMetrics(ctx context.Context, namespace string, opts metav1.ListOptions) ([]metricsv1beta1.PodMetrics, error) {
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]metricsv1beta1.PodMetrics, error) {
+			return c.GetPodMetrics(ctx, ns, opts)
+		})
+	}
+
 	if c.MetricsClient == nil {
 		return nil, fmt.Errorf("metrics-server not available (MetricsClient is nil)")
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.MetricsClient.MetricsV1beta1().PodMetricses(namespace).List(ctx, opts)
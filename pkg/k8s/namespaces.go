@@ -2,21 +2,54 @@ package k8s
 
 import (
 	"context"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
 )
 
-// ListNamespaces returns all namespaces in the cluster.
+// ListNamespaces returns all namespaces in the cluster, or only the accessible
+// namespaces in namespace-scoped mode.
 func (c *ClusterClient) ListNamespaces(ctx context.Context) ([]corev1.Namespace, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
+	if c.IsNamespaceScoped() {
+		return c.scopedNamespaces(ctx)
+	}
+
 	list, err := c.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 	return list.Items, nil
 }
+
+// ListNamespacesPage returns one page of namespaces. In namespace-scoped mode
+// every accessible namespace is returned in a single page.
+func (c *ClusterClient) ListNamespacesPage(ctx context.Context, opts metav1.ListOptions) (Page[corev1.Namespace], error) {
+	if c.IsNamespaceScoped() {
+		ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+		defer cancel()
+		items, err := c.scopedNamespaces(ctx)
+		return Page[corev1.Namespace]{Items: items}, err
+	}
+	return clusterPage(ctx, c, "Namespaces", opts, func(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+		return c.Clientset.CoreV1().Namespaces().List(ctx, opts)
+	}, func(l *corev1.NamespaceList) []corev1.Namespace { return l.Items })
+}
+
+// GetNamespace returns a single namespace by name.
+func (c *ClusterClient) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	ns, err := c.Clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil && apierrors.IsForbidden(err) && slices.Contains(c.Namespaces, name) {
+		return syntheticNamespace(name), nil
+	}
+	return ns, err
+}
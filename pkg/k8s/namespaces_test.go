@@ -43,3 +43,27 @@ func TestListNamespacesEmpty(t *testing.T) {
 		t.Errorf("expected 0 namespaces, got %d", len(namespaces))
 	}
 }
+
+func TestGetNamespace(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "payments",
+				Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+			},
+		},
+	)
+
+	client := NewClusterClientForTesting(fakeClient, nil)
+	ns, err := client.GetNamespace(context.Background(), "payments")
+	if err != nil {
+		t.Fatalf("GetNamespace() error = %v", err)
+	}
+	if ns.Labels["pod-security.kubernetes.io/enforce"] != "restricted" {
+		t.Errorf("expected enforce label 'restricted', got %q", ns.Labels["pod-security.kubernetes.io/enforce"])
+	}
+
+	if _, err := client.GetNamespace(context.Background(), "missing"); err == nil {
+		t.Error("expected error for missing namespace")
+	}
+}
@@ -3,9 +3,11 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -15,7 +17,7 @@ import (
 
 // GetService returns a single service by name.
 func (c *ClusterClient) GetService(ctx context.Context, namespace, name string) (*corev1.Service, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	return c.Clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
@@ -23,7 +25,7 @@ func (c *ClusterClient) GetService(ctx context.Context, namespace, name string)
 
 // GetIngress returns a single ingress by name.
 func (c *ClusterClient) GetIngress(ctx context.Context, namespace, name string) (*networkingv1.Ingress, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	return c.Clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
@@ -38,56 +40,178 @@ type EndpointHealth struct {
 	NotReadyCount  int
 	ReadyAddresses []EndpointAddress
 	NotReadyPods   []EndpointAddress
+
+	// Source is "EndpointSlice" or "Endpoints" (legacy fallback).
+	Source string
+	// Slices has per-slice readiness; empty for the legacy Endpoints source.
+	Slices []EndpointSliceHealth
+	// Families has readiness per address family (IPv4, IPv6, FQDN).
+	Families []AddressFamilyHealth
 }
 
 // EndpointAddress is an individual endpoint address with pod info.
 type EndpointAddress struct {
-	IP       string
-	PodName  string
-	NodeName string
+	IP          string
+	PodName     string
+	NodeName    string
+	Terminating bool
+}
+
+// EndpointSliceHealth is the readiness of a single EndpointSlice.
+type EndpointSliceHealth struct {
+	Name        string
+	AddressType string
+	Ready       int
+	NotReady    int
+	Terminating int
 }
 
-// GetServiceEndpointHealth returns endpoint health for a service.
+// AddressFamilyHealth is the readiness of a service's endpoints for one address family.
+type AddressFamilyHealth struct {
+	AddressType string
+	Ready       int
+	NotReady    int
+}
+
+// GetServiceEndpointHealth returns endpoint health for a service. It reads
+// EndpointSlices, which cover services with more than 1000 endpoints and
+// dual-stack addresses, and falls back to the legacy Endpoints API when the
+// discovery API is unavailable or has no slices for the service.
 func (c *ClusterClient) GetServiceEndpointHealth(ctx context.Context, namespace, serviceName string) (*EndpointHealth, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
+	slices, err := c.Clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + serviceName,
+	})
+	if err == nil && len(slices.Items) > 0 {
+		return endpointHealthFromSlices(namespace, serviceName, slices.Items), nil
+	}
+
 	ep, err := c.Clientset.CoreV1().Endpoints(namespace).Get(ctx, serviceName, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
+	return endpointHealthFromEndpoints(namespace, serviceName, ep), nil
+}
 
+// endpointHealthFromSlices aggregates readiness across a service's EndpointSlices.
+// Endpoints listed in more than one slice (during slice rebalancing) are counted once.
+func endpointHealthFromSlices(namespace, serviceName string, slices []discoveryv1.EndpointSlice) *EndpointHealth {
 	health := &EndpointHealth{
 		ServiceName: serviceName,
 		ServiceNS:   namespace,
+		Source:      "EndpointSlice",
 	}
+	families := make(map[string]*AddressFamilyHealth)
+	var familyOrder []string
+	seenInFamily := make(map[string]bool)
+	seenEndpoint := make(map[string]bool)
+
+	sort.Slice(slices, func(i, j int) bool { return slices[i].Name < slices[j].Name })
+	for _, slice := range slices {
+		addressType := string(slice.AddressType)
+		sh := EndpointSliceHealth{Name: slice.Name, AddressType: addressType}
+		fh, ok := families[addressType]
+		if !ok {
+			fh = &AddressFamilyHealth{AddressType: addressType}
+			families[addressType] = fh
+			familyOrder = append(familyOrder, addressType)
+		}
 
-	for _, subset := range ep.Subsets {
-		for _, addr := range subset.Addresses {
-			ea := EndpointAddress{IP: addr.IP}
-			if addr.TargetRef != nil {
-				ea.PodName = addr.TargetRef.Name
+		for _, ep := range slice.Endpoints {
+			if len(ep.Addresses) == 0 {
+				continue
+			}
+			ea := EndpointAddress{IP: ep.Addresses[0]}
+			if ep.TargetRef != nil {
+				ea.PodName = ep.TargetRef.Name
+			}
+			if ep.NodeName != nil {
+				ea.NodeName = *ep.NodeName
 			}
-			if addr.NodeName != nil {
-				ea.NodeName = *addr.NodeName
+			ea.Terminating = ep.Conditions.Terminating != nil && *ep.Conditions.Terminating
+			// A nil ready condition means unknown and is interpreted as ready
+			ready := (ep.Conditions.Ready == nil || *ep.Conditions.Ready) && !ea.Terminating
+
+			if ea.Terminating {
+				sh.Terminating++
+			}
+			if ready {
+				sh.Ready++
+			} else {
+				sh.NotReady++
 			}
-			health.ReadyAddresses = append(health.ReadyAddresses, ea)
+
+			familyKey := addressType + "/" + ea.IP
+			if !seenInFamily[familyKey] {
+				seenInFamily[familyKey] = true
+				if ready {
+					fh.Ready++
+				} else {
+					fh.NotReady++
+				}
+			}
+
+			// Dual-stack pods appear once per family; list each pod once
+			endpointKey := familyKey
+			if ep.TargetRef != nil {
+				endpointKey = ep.TargetRef.Namespace + "/" + ep.TargetRef.Name
+			}
+			if seenEndpoint[endpointKey] {
+				continue
+			}
+			seenEndpoint[endpointKey] = true
+			if ready {
+				health.ReadyAddresses = append(health.ReadyAddresses, ea)
+			} else {
+				health.NotReadyPods = append(health.NotReadyPods, ea)
+			}
+		}
+		health.Slices = append(health.Slices, sh)
+	}
+
+	for _, t := range familyOrder {
+		health.Families = append(health.Families, *families[t])
+	}
+	health.ReadyCount = len(health.ReadyAddresses)
+	health.NotReadyCount = len(health.NotReadyPods)
+	health.TotalEndpoints = health.ReadyCount + health.NotReadyCount
+	return health
+}
+
+// endpointHealthFromEndpoints reads readiness from a legacy Endpoints object.
+func endpointHealthFromEndpoints(namespace, serviceName string, ep *corev1.Endpoints) *EndpointHealth {
+	health := &EndpointHealth{
+		ServiceName: serviceName,
+		ServiceNS:   namespace,
+		Source:      "Endpoints",
+	}
+
+	for _, subset := range ep.Subsets {
+		for _, addr := range subset.Addresses {
+			health.ReadyAddresses = append(health.ReadyAddresses, endpointAddress(addr))
 			health.ReadyCount++
 		}
 		for _, addr := range subset.NotReadyAddresses {
-			ea := EndpointAddress{IP: addr.IP}
-			if addr.TargetRef != nil {
-				ea.PodName = addr.TargetRef.Name
-			}
-			if addr.NodeName != nil {
-				ea.NodeName = *addr.NodeName
-			}
-			health.NotReadyPods = append(health.NotReadyPods, ea)
+			health.NotReadyPods = append(health.NotReadyPods, endpointAddress(addr))
 			health.NotReadyCount++
 		}
 	}
 	health.TotalEndpoints = health.ReadyCount + health.NotReadyCount
-	return health, nil
+	return health
+}
+
+// endpointAddress converts a legacy Endpoints address.
+func endpointAddress(addr corev1.EndpointAddress) EndpointAddress {
+	ea := EndpointAddress{IP: addr.IP}
+	if addr.TargetRef != nil {
+		ea.PodName = addr.TargetRef.Name
+	}
+	if addr.NodeName != nil {
+		ea.NodeName = *addr.NodeName
+	}
+	return ea
 }
 
 // ServiceDependency represents an inferred dependency from one service to another.
@@ -102,7 +226,13 @@ type ServiceDependency struct {
 
 // InferServiceDependencies infers inter-service dependencies from pod env vars.
 func (c *ClusterClient) InferServiceDependencies(ctx context.Context, namespace string) ([]ServiceDependency, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]ServiceDependency, error) {
+			return c.InferServiceDependencies(ctx, ns)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	// Get all services to build name lookup
@@ -212,7 +342,7 @@ func (c *ClusterClient) GetPodsForService(ctx context.Context, svc *corev1.Servi
 		return nil, nil
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	sel := labels.SelectorFromSet(svc.Spec.Selector)
@@ -224,47 +354,177 @@ func (c *ClusterClient) GetPodsForService(ctx context.Context, svc *corev1.Servi
 	return list.Items, nil
 }
 
-// FindIngressForHostPath searches ingresses for a matching host+path.
-func (c *ClusterClient) FindIngressForHostPath(ctx context.Context, namespace, host, path string) (*networkingv1.Ingress, *networkingv1.IngressRule, *networkingv1.HTTPIngressPath, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+// IngressPrecedence is the host-matching tier an Ingress rule was selected
+// by. Lower tiers win: an exact host beats a wildcard, which beats a rule
+// without a host, which beats an Ingress defaultBackend.
+type IngressPrecedence int
+
+const (
+	PrecedenceExactHost IngressPrecedence = iota
+	PrecedenceWildcardHost
+	PrecedenceAnyHost
+	PrecedenceDefaultBackend
+)
+
+func (p IngressPrecedence) String() string {
+	switch p {
+	case PrecedenceExactHost:
+		return "exact host"
+	case PrecedenceWildcardHost:
+		return "wildcard host"
+	case PrecedenceAnyHost:
+		return "rule without host"
+	}
+	return "defaultBackend"
+}
+
+// IngressMatch is the Ingress rule and path that serve a request. For a
+// defaultBackend match, Rule has no host and Path is a synthetic "/" path
+// pointing at the default backend.
+type IngressMatch struct {
+	Ingress    *networkingv1.Ingress
+	Rule       *networkingv1.IngressRule
+	Path       *networkingv1.HTTPIngressPath
+	Precedence IngressPrecedence
+}
+
+// Host returns the matched rule's host, or "(any host)" for rules without one.
+func (m *IngressMatch) Host() string {
+	if m.Rule.Host == "" {
+		return "(any host)"
+	}
+	return m.Rule.Host
+}
+
+// Explain describes how the match was chosen, e.g.
+// "wildcard host *.example.com, longest Prefix path /api".
+func (m *IngressMatch) Explain() string {
+	switch m.Precedence {
+	case PrecedenceDefaultBackend:
+		return "defaultBackend (no rule matched the host and path)"
+	case PrecedenceAnyHost:
+		return fmt.Sprintf("rule without host, %s", pathPrecedence(m.Path))
+	}
+	return fmt.Sprintf("%s %s, %s", m.Precedence, m.Rule.Host, pathPrecedence(m.Path))
+}
+
+func pathPrecedence(p *networkingv1.HTTPIngressPath) string {
+	if pathTypeOf(p) == networkingv1.PathTypeExact {
+		return fmt.Sprintf("Exact path %s", p.Path)
+	}
+	return fmt.Sprintf("longest %s path %s", pathTypeOf(p), p.Path)
+}
+
+func pathTypeOf(p *networkingv1.HTTPIngressPath) networkingv1.PathType {
+	if p.PathType != nil {
+		return *p.PathType
+	}
+	return networkingv1.PathTypePrefix
+}
+
+// HostMatches reports whether an Ingress or TLS host pattern matches host. A
+// wildcard pattern (*.example.com) matches exactly one leading DNS label.
+func HostMatches(pattern, host string) bool {
+	if pattern == host {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(pattern, "*")
+	if !ok || !strings.HasSuffix(host, suffix) {
+		return false
+	}
+	label := strings.TrimSuffix(host, suffix)
+	return label != "" && !strings.Contains(label, ".")
+}
+
+// FindIngressForHostPath finds the Ingress rule serving host+path, following
+// Ingress precedence: exact host, then wildcard host, then rules without a
+// host, then a defaultBackend. Within a tier the longest matching path wins,
+// and an Exact path beats a Prefix path of the same length.
+func (c *ClusterClient) FindIngressForHostPath(ctx context.Context, namespace, host, path string) (*IngressMatch, error) {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	ingresses, err := c.Clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
+	if m := MatchIngress(ingresses.Items, host, path); m != nil {
+		return m, nil
+	}
+	return nil, fmt.Errorf("no ingress found for %s%s", host, path)
+}
 
-	// Search all namespaces if needed
-	if namespace == "" {
-		ingresses, err = c.Clientset.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{})
-		if err != nil {
-			return nil, nil, nil, err
+// MatchIngress selects the rule serving host+path from ingresses, or nil.
+// See FindIngressForHostPath for the precedence order.
+func MatchIngress(ingresses []networkingv1.Ingress, host, path string) *IngressMatch {
+	var best *IngressMatch
+	better := func(m *IngressMatch) bool {
+		if best == nil {
+			return true
+		}
+		if m.Precedence != best.Precedence {
+			return m.Precedence < best.Precedence
 		}
+		if n, bestN := matchLength(m.Path), matchLength(best.Path); n != bestN {
+			return n > bestN
+		}
+		return pathTypeOf(m.Path) == networkingv1.PathTypeExact && pathTypeOf(best.Path) != networkingv1.PathTypeExact
 	}
 
-	for i := range ingresses.Items {
-		ing := &ingresses.Items[i]
+	for i := range ingresses {
+		ing := &ingresses[i]
 		for j := range ing.Spec.Rules {
 			rule := &ing.Spec.Rules[j]
-			if rule.Host != host {
+			if rule.HTTP == nil {
 				continue
 			}
-			if rule.HTTP == nil {
+			var precedence IngressPrecedence
+			switch {
+			case rule.Host == "":
+				precedence = PrecedenceAnyHost
+			case rule.Host == host:
+				precedence = PrecedenceExactHost
+			case HostMatches(rule.Host, host):
+				precedence = PrecedenceWildcardHost
+			default:
 				continue
 			}
 			for k := range rule.HTTP.Paths {
 				p := &rule.HTTP.Paths[k]
-				pathType := networkingv1.PathTypePrefix
-				if p.PathType != nil {
-					pathType = *p.PathType
+				if !matchPath(p.Path, path, pathTypeOf(p)) {
+					continue
 				}
-				if matchPath(p.Path, path, pathType) {
-					return ing, rule, p, nil
+				if m := (&IngressMatch{Ingress: ing, Rule: rule, Path: p, Precedence: precedence}); better(m) {
+					best = m
 				}
 			}
 		}
 	}
-	return nil, nil, nil, fmt.Errorf("no ingress found for %s%s", host, path)
+	if best != nil {
+		return best
+	}
+
+	for i := range ingresses {
+		ing := &ingresses[i]
+		if ing.Spec.DefaultBackend != nil {
+			return &IngressMatch{
+				Ingress:    ing,
+				Rule:       &networkingv1.IngressRule{},
+				Path:       &networkingv1.HTTPIngressPath{Path: "/", Backend: *ing.Spec.DefaultBackend},
+				Precedence: PrecedenceDefaultBackend,
+			}
+		}
+	}
+	return nil
+}
+
+// matchLength is the length a path competes on for the longest match: a
+// Prefix path's trailing slash does not count, so Exact /v2 ties Prefix /v2/.
+func matchLength(p *networkingv1.HTTPIngressPath) int {
+	if pathTypeOf(p) == networkingv1.PathTypePrefix {
+		return len(strings.TrimSuffix(p.Path, "/"))
+	}
+	return len(p.Path)
 }
 
 func matchPath(pattern, path string, pathType networkingv1.PathType) bool {
@@ -272,7 +532,9 @@ func matchPath(pattern, path string, pathType networkingv1.PathType) bool {
 	case networkingv1.PathTypeExact:
 		return path == pattern
 	case networkingv1.PathTypePrefix:
-		return strings.HasPrefix(path, pattern)
+		// Prefix matches whole path elements, ignoring a trailing slash.
+		prefix := strings.TrimSuffix(pattern, "/")
+		return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
 	default: // ImplementationSpecific
 		return strings.HasPrefix(path, pattern)
 	}
@@ -0,0 +1,195 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func sliceEndpoint(ip, pod string, ready bool) discoveryv1.Endpoint {
+	return discoveryv1.Endpoint{
+		Addresses:  []string{ip},
+		Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+		TargetRef:  &corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: pod},
+	}
+}
+
+func TestGetServiceEndpointHealthFromSlices(t *testing.T) {
+	labels := map[string]string{discoveryv1.LabelServiceName: "web"}
+	fakeClient := fake.NewSimpleClientset(
+		&discoveryv1.EndpointSlice{
+			ObjectMeta:  metav1.ObjectMeta{Name: "web-v4", Namespace: "default", Labels: labels},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				sliceEndpoint("10.0.0.1", "web-1", true),
+				sliceEndpoint("10.0.0.2", "web-2", false),
+			},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta:  metav1.ObjectMeta{Name: "web-v6", Namespace: "default", Labels: labels},
+			AddressType: discoveryv1.AddressTypeIPv6,
+			Endpoints:   []discoveryv1.Endpoint{sliceEndpoint("fd00::1", "web-1", true)},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta:  metav1.ObjectMeta{Name: "other", Namespace: "default", Labels: map[string]string{discoveryv1.LabelServiceName: "other"}},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints:   []discoveryv1.Endpoint{sliceEndpoint("10.0.0.9", "other-1", true)},
+		},
+	)
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	health, err := client.GetServiceEndpointHealth(context.Background(), "default", "web")
+	if err != nil {
+		t.Fatalf("GetServiceEndpointHealth() error = %v", err)
+	}
+	if health.Source != "EndpointSlice" {
+		t.Errorf("expected EndpointSlice source, got %q", health.Source)
+	}
+	if health.ReadyCount != 1 || health.NotReadyCount != 1 || health.TotalEndpoints != 2 {
+		t.Errorf("expected 1 ready and 1 not-ready pod, got %+v", health)
+	}
+	if len(health.Slices) != 2 {
+		t.Errorf("expected 2 slices, got %d", len(health.Slices))
+	}
+	if len(health.Families) != 2 || health.Families[1].AddressType != "IPv6" || health.Families[1].Ready != 1 {
+		t.Errorf("unexpected families %+v", health.Families)
+	}
+}
+
+func TestGetServiceEndpointHealthTerminating(t *testing.T) {
+	terminating := true
+	ep := sliceEndpoint("10.0.0.1", "web-1", true)
+	ep.Conditions.Terminating = &terminating
+	fakeClient := fake.NewSimpleClientset(&discoveryv1.EndpointSlice{
+		ObjectMeta:  metav1.ObjectMeta{Name: "web-abc", Namespace: "default", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   []discoveryv1.Endpoint{ep},
+	})
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	health, err := client.GetServiceEndpointHealth(context.Background(), "default", "web")
+	if err != nil {
+		t.Fatalf("GetServiceEndpointHealth() error = %v", err)
+	}
+	if health.ReadyCount != 0 || health.Slices[0].Terminating != 1 || !health.NotReadyPods[0].Terminating {
+		t.Errorf("expected terminating endpoint to be not ready, got %+v", health)
+	}
+}
+
+func TestGetServiceEndpointHealthFallsBackToEndpoints(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "default"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+			NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.2"}},
+		}},
+	})
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	health, err := client.GetServiceEndpointHealth(context.Background(), "default", "legacy")
+	if err != nil {
+		t.Fatalf("GetServiceEndpointHealth() error = %v", err)
+	}
+	if health.Source != "Endpoints" || health.ReadyCount != 1 || health.NotReadyCount != 1 {
+		t.Errorf("unexpected fallback health %+v", health)
+	}
+}
+
+func ingressRule(host string, paths ...string) networkingv1.IngressRule {
+	exact := networkingv1.PathTypeExact
+	rule := networkingv1.IngressRule{Host: host, IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{}}}
+	for _, p := range paths {
+		path := networkingv1.HTTPIngressPath{Path: strings.TrimPrefix(p, "="),
+			Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: host + p}}}
+		if strings.HasPrefix(p, "=") {
+			path.PathType = &exact
+		}
+		rule.HTTP.Paths = append(rule.HTTP.Paths, path)
+	}
+	return rule
+}
+
+func TestMatchIngress(t *testing.T) {
+	ingresses := []networkingv1.Ingress{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "catch-all", Namespace: "edge"},
+			Spec: networkingv1.IngressSpec{
+				DefaultBackend: &networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "fallback"}},
+				Rules:          []networkingv1.IngressRule{ingressRule("", "/healthz")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "shop"},
+			Spec: networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{
+				ingressRule("*.example.com", "/"),
+				ingressRule("api.example.com", "/", "/v1", "=/v1/status", "=/v2", "/v2/", "=/v3", "/v3"),
+			}},
+		},
+	}
+	tests := []struct {
+		host, path string
+		backend    string
+		precedence IngressPrecedence
+	}{
+		{"api.example.com", "/v1/orders", "api.example.com/v1", PrecedenceExactHost},
+		{"api.example.com", "/v1/status", "api.example.com=/v1/status", PrecedenceExactHost},
+		{"api.example.com", "/v2", "api.example.com=/v2", PrecedenceExactHost},
+		{"api.example.com", "/v2/orders", "api.example.com/v2/", PrecedenceExactHost},
+		{"api.example.com", "/v3", "api.example.com=/v3", PrecedenceExactHost},
+		{"api.example.com", "/v1beta", "api.example.com/", PrecedenceExactHost},
+		{"shop.example.com", "/cart", "*.example.com/", PrecedenceWildcardHost},
+		{"a.b.example.com", "/healthz", "/healthz", PrecedenceAnyHost},
+		{"other.org", "/", "fallback", PrecedenceDefaultBackend},
+	}
+	for _, tt := range tests {
+		m := MatchIngress(ingresses, tt.host, tt.path)
+		if m == nil {
+			t.Errorf("%s%s: no match", tt.host, tt.path)
+			continue
+		}
+		if got := m.Path.Backend.Service.Name; got != tt.backend || m.Precedence != tt.precedence {
+			t.Errorf("%s%s matched %q by %s, want %q by %s", tt.host, tt.path, got, m.Precedence, tt.backend, tt.precedence)
+		}
+	}
+	if got := MatchIngress(ingresses, "shop.example.com", "/cart").Explain(); got != "wildcard host *.example.com, longest Prefix path /" {
+		t.Errorf("Explain = %q", got)
+	}
+	if MatchIngress(ingresses[1:], "other.org", "/") != nil {
+		t.Error("expected no match without a defaultBackend")
+	}
+}
+
+func TestMatchIngressExactBeatsTrailingSlashPrefix(t *testing.T) {
+	// The Prefix path is listed first so that only the tie-break picks Exact.
+	ingresses := []networkingv1.Ingress{{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Spec:       networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{ingressRule("api.example.com", "/v2/", "=/v2")}},
+	}}
+	if got := MatchIngress(ingresses, "api.example.com", "/v2").Path.Backend.Service.Name; got != "api.example.com=/v2" {
+		t.Errorf("/v2 matched %q, want the Exact path", got)
+	}
+}
+
+func TestHostMatches(t *testing.T) {
+	tests := []struct {
+		pattern, host string
+		want          bool
+	}{
+		{"api.example.com", "api.example.com", true},
+		{"*.example.com", "api.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"*.example.com", "a.b.example.com", false},
+		{"api.example.com", "web.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := HostMatches(tt.pattern, tt.host); got != tt.want {
+			t.Errorf("HostMatches(%q, %q) = %v, want %v", tt.pattern, tt.host, got, tt.want)
+		}
+	}
+}
@@ -11,7 +11,13 @@ import (
 
 // ListNetworkPolicies returns network policies in the given namespace.
 func (c *ClusterClient) ListNetworkPolicies(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.NetworkPolicy, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]networkingv1.NetworkPolicy, error) {
+			return c.ListNetworkPolicies(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, opts)
@@ -23,3 +29,10 @@ func (c *ClusterClient) ListNetworkPolicies(ctx context.Context, namespace strin
 	}
 	return list.Items, nil
 }
+
+// ListNetworkPoliciesPage returns one page of network policies in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListNetworkPoliciesPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[networkingv1.NetworkPolicy], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*networkingv1.NetworkPolicyList, error) {
+		return c.Clientset.NetworkingV1().NetworkPolicies(ns).List(ctx, opts)
+	}, func(l *networkingv1.NetworkPolicyList) []networkingv1.NetworkPolicy { return l.Items })
+}
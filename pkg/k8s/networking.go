@@ -12,7 +12,13 @@ import (
 
 // ListServices returns services in the given namespace.
 func (c *ClusterClient) ListServices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]corev1.Service, error) {
+			return c.ListServices(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.CoreV1().Services(namespace).List(ctx, opts)
@@ -22,9 +28,22 @@ func (c *ClusterClient) ListServices(ctx context.Context, namespace string, opts
 	return list.Items, nil
 }
 
+// ListServicesPage returns one page of services in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListServicesPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[corev1.Service], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.ServiceList, error) {
+		return c.Clientset.CoreV1().Services(ns).List(ctx, opts)
+	}, func(l *corev1.ServiceList) []corev1.Service { return l.Items })
+}
+
 // ListIngresses returns ingresses in the given namespace.
 func (c *ClusterClient) ListIngresses(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.Ingress, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]networkingv1.Ingress, error) {
+			return c.ListIngresses(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.NetworkingV1().Ingresses(namespace).List(ctx, opts)
@@ -34,10 +53,33 @@ func (c *ClusterClient) ListIngresses(ctx context.Context, namespace string, opt
 	return list.Items, nil
 }
 
+// ListIngressesPage returns one page of ingresses in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListIngressesPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[networkingv1.Ingress], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*networkingv1.IngressList, error) {
+		return c.Clientset.NetworkingV1().Ingresses(ns).List(ctx, opts)
+	}, func(l *networkingv1.IngressList) []networkingv1.Ingress { return l.Items })
+}
+
 // GetEndpoints returns endpoints for a service.
 func (c *ClusterClient) GetEndpoints(ctx context.Context, namespace, name string) (*corev1.Endpoints, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	return c.Clientset.CoreV1().Endpoints(namespace).Get(ctx, name, metav1.GetOptions{})
 }
+
+// ListIngressClasses returns all IngressClasses in the cluster.
+func (c *ClusterClient) ListIngressClasses(ctx context.Context) ([]networkingv1.IngressClass, error) {
+	if err := c.clusterScope("IngressClasses"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	list, err := c.Clientset.NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
@@ -102,3 +102,26 @@ func TestGetEndpoints(t *testing.T) {
 		t.Errorf("expected 2 addresses, got %d", len(ep.Subsets[0].Addresses))
 	}
 }
+
+func TestListIngressClasses(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+			Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+		},
+		&networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure-application-gateway"},
+			Spec:       networkingv1.IngressClassSpec{Controller: "azure/application-gateway"},
+		},
+	)
+
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	classes, err := client.ListIngressClasses(context.Background())
+	if err != nil {
+		t.Fatalf("ListIngressClasses() error = %v", err)
+	}
+	if len(classes) != 2 {
+		t.Errorf("expected 2 ingress classes, got %d", len(classes))
+	}
+}
@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// FsStats is the usage of a filesystem or volume as the kubelet reports it.
+// Fields are nil when the kubelet could not measure them.
+type FsStats struct {
+	AvailableBytes *uint64 `json:"availableBytes,omitempty"`
+	CapacityBytes  *uint64 `json:"capacityBytes,omitempty"`
+	UsedBytes      *uint64 `json:"usedBytes,omitempty"`
+}
+
+// Used returns UsedBytes, or 0 when it was not measured.
+func (f *FsStats) Used() int64 {
+	if f == nil || f.UsedBytes == nil {
+		return 0
+	}
+	return int64(*f.UsedBytes)
+}
+
+// Capacity returns CapacityBytes, or 0 when it was not measured.
+func (f *FsStats) Capacity() int64 {
+	if f == nil || f.CapacityBytes == nil {
+		return 0
+	}
+	return int64(*f.CapacityBytes)
+}
+
+// VolumeStats is the usage of one pod volume. PVC-backed volumes carry a
+// PVCRef; emptyDir, configMap, secret, and projected volumes do not.
+type VolumeStats struct {
+	FsStats
+	Name   string `json:"name"`
+	PVCRef *struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"pvcRef,omitempty"`
+}
+
+// PodStats is a pod's entry in the kubelet stats summary.
+type PodStats struct {
+	PodRef struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+		UID       string `json:"uid"`
+	} `json:"podRef"`
+	VolumeStats []VolumeStats `json:"volume,omitempty"`
+	// EphemeralStorage is what the kubelet counts against the pod's
+	// ephemeral-storage limit: writable layers, logs, and local volumes.
+	EphemeralStorage *FsStats `json:"ephemeral-storage,omitempty"`
+}
+
+// NodeStatsSummary is the subset of the kubelet's /stats/summary (the
+// stats/v1alpha1 Summary type) that disk analysis uses.
+type NodeStatsSummary struct {
+	Node struct {
+		NodeName string   `json:"nodeName"`
+		Fs       *FsStats `json:"fs,omitempty"`
+		Runtime  *struct {
+			ImageFs *FsStats `json:"imageFs,omitempty"`
+		} `json:"runtime,omitempty"`
+	} `json:"node"`
+	Pods []PodStats `json:"pods"`
+}
+
+// ImageFs returns the container image filesystem stats, nil when unknown.
+func (s *NodeStatsSummary) ImageFs() *FsStats {
+	if s.Node.Runtime == nil {
+		return nil
+	}
+	return s.Node.Runtime.ImageFs
+}
+
+// GetNodeStatsSummary fetches the kubelet stats summary of a node through the
+// API server's node proxy. It needs get on nodes/proxy.
+func (c *ClusterClient) GetNodeStatsSummary(ctx context.Context, node string) (*NodeStatsSummary, error) {
+	if err := c.clusterScope("Node stats"); err != nil {
+		return nil, err
+	}
+	rc := c.Clientset.CoreV1().RESTClient()
+	if r, ok := rc.(*rest.RESTClient); rc == nil || (ok && r == nil) {
+		return nil, fmt.Errorf("kubelet stats not available")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	data, err := rc.Get().Resource("nodes").Name(node).SubResource("proxy").Suffix("stats", "summary").DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var summary NodeStatsSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("decoding stats summary of node %s: %w", node, err)
+	}
+	return &summary, nil
+}
@@ -0,0 +1,34 @@
+package k8s
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNodeStatsSummaryDecode(t *testing.T) {
+	data := `{
+		"node": {"nodeName": "n1", "fs": {"usedBytes": 100, "capacityBytes": 1000}, "runtime": {"imageFs": {"usedBytes": 50}}},
+		"pods": [{
+			"podRef": {"name": "web", "namespace": "shop"},
+			"volume": [{"name": "cache", "usedBytes": 40}, {"name": "data", "usedBytes": 5, "pvcRef": {"name": "data-web", "namespace": "shop"}}],
+			"ephemeral-storage": {"usedBytes": 60}
+		}]
+	}`
+	var s NodeStatsSummary
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Node.Fs.Used() != 100 || s.Node.Fs.Capacity() != 1000 || s.ImageFs().Used() != 50 || s.ImageFs().Capacity() != 0 {
+		t.Errorf("unexpected node stats: %+v", s.Node)
+	}
+	if len(s.Pods) != 1 || s.Pods[0].EphemeralStorage.Used() != 60 || len(s.Pods[0].VolumeStats) != 2 {
+		t.Fatalf("unexpected pod stats: %+v", s.Pods)
+	}
+	if v := s.Pods[0].VolumeStats; v[0].Used() != 40 || v[0].PVCRef != nil || v[1].PVCRef == nil {
+		t.Errorf("unexpected volume stats: %+v", v)
+	}
+	var empty NodeStatsSummary
+	if empty.ImageFs().Used() != 0 || empty.Node.Fs.Used() != 0 {
+		t.Error("expected zero use for missing stats")
+	}
+}
@@ -11,7 +11,11 @@ import (
 
 // ListNodes returns all nodes, optionally filtered by label selector.
 func (c *ClusterClient) ListNodes(ctx context.Context, opts metav1.ListOptions) ([]corev1.Node, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if err := c.clusterScope("Nodes"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.CoreV1().Nodes().List(ctx, opts)
@@ -21,9 +25,20 @@ func (c *ClusterClient) ListNodes(ctx context.Context, opts metav1.ListOptions)
 	return list.Items, nil
 }
 
+// ListNodesPage returns one page of Nodes.
+func (c *ClusterClient) ListNodesPage(ctx context.Context, opts metav1.ListOptions) (Page[corev1.Node], error) {
+	return clusterPage(ctx, c, "Nodes", opts, func(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
+		return c.Clientset.CoreV1().Nodes().List(ctx, opts)
+	}, func(l *corev1.NodeList) []corev1.Node { return l.Items })
+}
+
 // GetNode returns a single node by name.
 func (c *ClusterClient) GetNode(ctx context.Context, name string) (*corev1.Node, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if err := c.clusterScope("Nodes"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	return c.Clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
@@ -0,0 +1,110 @@
+package k8s
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// Page is one page of a list requested with ListOptions.Limit.
+type Page[T any] struct {
+	Items []T
+	// Continue fetches the next page when passed back as ListOptions.Continue.
+	// It is empty on the last page.
+	Continue string
+	// Remaining estimates how many items follow this page; nil when unknown.
+	Remaining *int64
+}
+
+// scopedContinue is the continue token of an all-namespace page in
+// namespace-scoped mode: the namespace to resume in and the API server's
+// token within it.
+type scopedContinue struct {
+	Index    int    `json:"i"`
+	Continue string `json:"c,omitempty"`
+}
+
+// listPage lists one page of a namespaced resource. In namespace-scoped mode
+// an all-namespace page walks the accessible namespaces in order until the
+// limit is filled.
+func listPage[T any, L metav1.ListInterface](ctx context.Context, c *ClusterClient, namespace string, opts metav1.ListOptions,
+	list func(ctx context.Context, namespace string, opts metav1.ListOptions) (L, error), items func(L) []T) (Page[T], error) {
+	if c.fanOut(namespace) {
+		return listPageAcross(ctx, c, opts, list, items)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	l, err := list(ctx, namespace, opts)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	return Page[T]{Items: items(l), Continue: l.GetContinue(), Remaining: l.GetRemainingItemCount()}, nil
+}
+
+func listPageAcross[T any, L metav1.ListInterface](ctx context.Context, c *ClusterClient, opts metav1.ListOptions,
+	list func(ctx context.Context, namespace string, opts metav1.ListOptions) (L, error), items func(L) []T) (Page[T], error) {
+	var pos scopedContinue
+	if opts.Continue != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(opts.Continue)
+		if err != nil || json.Unmarshal(raw, &pos) != nil || pos.Index < 0 || pos.Index >= len(c.Namespaces) {
+			return Page[T]{}, fmt.Errorf("invalid continue token %q", opts.Continue)
+		}
+	}
+
+	var page Page[T]
+	for i := pos.Index; i < len(c.Namespaces); i++ {
+		nsOpts := opts
+		nsOpts.Continue = ""
+		if i == pos.Index {
+			nsOpts.Continue = pos.Continue
+		}
+		if opts.Limit > 0 {
+			nsOpts.Limit = opts.Limit - int64(len(page.Items))
+		}
+		l, err := func() (L, error) {
+			ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+			defer cancel()
+			return list(ctx, c.Namespaces[i], nsOpts)
+		}()
+		if err != nil {
+			return Page[T]{}, err
+		}
+		page.Items = append(page.Items, items(l)...)
+
+		next := scopedContinue{Index: i, Continue: l.GetContinue()}
+		if next.Continue == "" {
+			if opts.Limit <= 0 || int64(len(page.Items)) < opts.Limit || i+1 == len(c.Namespaces) {
+				continue
+			}
+			next = scopedContinue{Index: i + 1}
+		}
+		raw, _ := json.Marshal(next)
+		page.Continue = base64.RawURLEncoding.EncodeToString(raw)
+		return page, nil
+	}
+	return page, nil
+}
+
+// clusterPage lists one page of a cluster-scoped resource.
+func clusterPage[T any, L metav1.ListInterface](ctx context.Context, c *ClusterClient, resource string, opts metav1.ListOptions,
+	list func(ctx context.Context, opts metav1.ListOptions) (L, error), items func(L) []T) (Page[T], error) {
+	if err := c.clusterScope(resource); err != nil {
+		return Page[T]{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	l, err := list(ctx, opts)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	return Page[T]{Items: items(l), Continue: l.GetContinue(), Remaining: l.GetRemainingItemCount()}, nil
+}
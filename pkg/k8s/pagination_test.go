@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// pagingClientset serves pod lists with the API server's limit and continue
+// semantics; the continue token is the offset of the next pod.
+func pagingClientset(pods map[string]int) *fake.Clientset {
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		opts := action.(k8stesting.ListActionImpl).ListOptions
+		ns := action.GetNamespace()
+		start, _ := strconv.Atoi(opts.Continue)
+		end := pods[ns]
+		list := &corev1.PodList{}
+		if opts.Limit > 0 && int64(end-start) > opts.Limit {
+			end = start + int(opts.Limit)
+			remaining := int64(pods[ns] - end)
+			list.Continue = strconv.Itoa(end)
+			list.RemainingItemCount = &remaining
+		}
+		for i := start; i < end; i++ {
+			list.Items = append(list.Items, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: ns + "-" + strconv.Itoa(i), Namespace: ns}})
+		}
+		return true, list, nil
+	})
+	return fakeClient
+}
+
+func TestListPodsPage(t *testing.T) {
+	client := NewClusterClientForTesting(pagingClientset(map[string]int{"shop": 5}), nil)
+
+	page, err := client.ListPodsPage(context.Background(), "shop", metav1.ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListPodsPage() error = %v", err)
+	}
+	if len(page.Items) != 2 || page.Continue != "2" || page.Remaining == nil || *page.Remaining != 3 {
+		t.Errorf("first page = %d items, continue %q, want 2 items and continue \"2\" with 3 remaining", len(page.Items), page.Continue)
+	}
+
+	page, err = client.ListPodsPage(context.Background(), "shop", metav1.ListOptions{Limit: 10, Continue: page.Continue})
+	if err != nil {
+		t.Fatalf("ListPodsPage() error = %v", err)
+	}
+	if len(page.Items) != 3 || page.Continue != "" {
+		t.Errorf("last page = %d items, continue %q, want 3 items and no token", len(page.Items), page.Continue)
+	}
+}
+
+func TestListPodsPageNamespaceScoped(t *testing.T) {
+	client := NewClusterClientForTesting(pagingClientset(map[string]int{"team-a": 1, "team-b": 3, "team-c": 2}), nil)
+	client.Namespaces = []string{"team-a", "team-b", "team-c"}
+
+	var names []string
+	opts := metav1.ListOptions{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("continue tokens did not terminate")
+		}
+		page, err := client.ListPodsPage(context.Background(), "", opts)
+		if err != nil {
+			t.Fatalf("ListPodsPage() error = %v", err)
+		}
+		if int64(len(page.Items)) > opts.Limit {
+			t.Errorf("page of %d items exceeds limit %d", len(page.Items), opts.Limit)
+		}
+		for _, p := range page.Items {
+			names = append(names, p.Name)
+		}
+		if page.Continue == "" {
+			break
+		}
+		opts.Continue = page.Continue
+	}
+
+	want := []string{"team-a-0", "team-b-0", "team-b-1", "team-b-2", "team-c-0", "team-c-1"}
+	if len(names) != len(want) {
+		t.Fatalf("walked %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("item %d = %s, want %s", i, names[i], want[i])
+		}
+	}
+
+	if _, err := client.ListPodsPage(context.Background(), "", metav1.ListOptions{Continue: "not-a-token"}); err == nil {
+		t.Error("expected an error for an invalid continue token")
+	}
+}
+
+func TestClusterPageNamespaceScoped(t *testing.T) {
+	client := NewClusterClientForTesting(fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}), nil)
+	client.Namespaces = []string{"team-a"}
+
+	if _, err := client.ListNodesPage(context.Background(), metav1.ListOptions{Limit: 10}); !util.IsScopeError(err) {
+		t.Errorf("ListNodesPage() error = %v, want ScopeError", err)
+	}
+	page, err := client.ListNamespacesPage(context.Background(), metav1.ListOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListNamespacesPage() error = %v", err)
+	}
+	if len(page.Items) != 1 || page.Continue != "" {
+		t.Errorf("ListNamespacesPage() = %d items, continue %q, want the one scoped namespace", len(page.Items), page.Continue)
+	}
+}
+
+func TestListAllPods(t *testing.T) {
+	client := NewClusterClientForTesting(pagingClientset(map[string]int{"shop": 1500}), nil)
+
+	pods, err := client.ListAllPods(context.Background(), "shop", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListAllPods() error = %v", err)
+	}
+	if len(pods) != 1500 || pods[1499].Name != "shop-1499" {
+		t.Errorf("ListAllPods() returned %d pods, want all 1500 across pages", len(pods))
+	}
+}
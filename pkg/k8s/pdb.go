@@ -11,7 +11,13 @@ import (
 
 // ListPodDisruptionBudgets returns PDBs in the given namespace.
 func (c *ClusterClient) ListPodDisruptionBudgets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]policyv1.PodDisruptionBudget, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]policyv1.PodDisruptionBudget, error) {
+			return c.ListPodDisruptionBudgets(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, opts)
@@ -20,3 +26,10 @@ func (c *ClusterClient) ListPodDisruptionBudgets(ctx context.Context, namespace
 	}
 	return list.Items, nil
 }
+
+// ListPodDisruptionBudgetsPage returns one page of PodDisruptionBudgets in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListPodDisruptionBudgetsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[policyv1.PodDisruptionBudget], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*policyv1.PodDisruptionBudgetList, error) {
+		return c.Clientset.PolicyV1().PodDisruptionBudgets(ns).List(ctx, opts)
+	}, func(l *policyv1.PodDisruptionBudgetList) []policyv1.PodDisruptionBudget { return l.Items })
+}
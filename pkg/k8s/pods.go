@@ -11,7 +11,13 @@ import (
 
 // ListPods returns pods in the given namespace (empty = all namespaces).
 func (c *ClusterClient) ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]corev1.Pod, error) {
+			return c.ListPods(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, opts)
@@ -26,9 +32,34 @@ func (c *ClusterClient) ListPods(ctx context.Context, namespace string, opts met
 	return list.Items, nil
 }
 
+// ListPodsPage returns one page of pods in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListPodsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[corev1.Pod], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.PodList, error) {
+		return c.Clientset.CoreV1().Pods(ns).List(ctx, opts)
+	}, func(l *corev1.PodList) []corev1.Pod { return l.Items })
+}
+
+// ListAllPods returns every pod in the given namespace (empty = all
+// namespaces), reading page by page instead of truncating at util.MaxPods.
+func (c *ClusterClient) ListAllPods(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+	opts.Limit = util.MaxPageSize
+	var pods []corev1.Pod
+	for {
+		page, err := c.ListPodsPage(ctx, namespace, opts)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, page.Items...)
+		if page.Continue == "" {
+			return pods, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
 // GetPod returns a single pod by name.
 func (c *ClusterClient) GetPod(ctx context.Context, namespace, name string) (*corev1.Pod, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	return c.Clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
@@ -82,3 +82,11 @@ func TestGetPodNotFound(t *testing.T) {
 		t.Fatal("expected error for nonexistent pod")
 	}
 }
+
+func TestExecInPodRequiresConfig(t *testing.T) {
+	client := NewClusterClientForTesting(fake.NewSimpleClientset(), nil)
+
+	if _, err := client.ExecInPod(context.Background(), "default", "web", "main", []string{"true"}); err == nil {
+		t.Errorf("expected ExecInPod() to fail without a rest config")
+	}
+}
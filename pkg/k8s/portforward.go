@@ -0,0 +1,163 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// HTTPProbe describes one HTTP GET sent to a pod through a port-forward.
+type HTTPProbe struct {
+	Port int32
+	// Path is the request path, including any query string.
+	Path string
+	// Host overrides the Host header for virtual-hosted apps.
+	Host string
+	// HTTPS speaks TLS to the pod without verifying its certificate, which
+	// never matches the forwarded localhost address.
+	HTTPS bool
+	// MaxBody caps the body bytes kept in the result.
+	MaxBody int
+}
+
+// HTTPProbeResult is the response to an HTTPProbe.
+type HTTPProbeResult struct {
+	StatusCode int
+	Status     string
+	// Latency is the time from sending the request to the response headers,
+	// excluding port-forward setup.
+	Latency       time.Duration
+	Header        http.Header
+	Body          string
+	BodyTruncated bool
+}
+
+// ProbePodHTTP port-forwards to a pod port through the API server, as kubectl
+// port-forward does, and sends one HTTP GET. WebSockets are tried first,
+// falling back to SPDY for older API servers. ctx bounds the whole probe.
+func (c *ClusterClient) ProbePodHTTP(ctx context.Context, namespace, pod string, probe HTTPProbe) (*HTTPProbeResult, error) {
+	if c.Config == nil {
+		return nil, fmt.Errorf("port-forward requires a live cluster connection")
+	}
+
+	req := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("portforward")
+	transport, upgrader, err := spdy.RoundTripperFor(c.Config)
+	if err != nil {
+		return nil, err
+	}
+	spdyDialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+	wsDialer, err := portforward.NewSPDYOverWebsocketDialer(req.URL(), c.Config)
+	if err != nil {
+		return nil, err
+	}
+	dialer := portforward.NewFallbackDialer(wsDialer, spdyDialer, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	ready := make(chan struct{})
+	errOut := &syncBuffer{}
+	fw, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", probe.Port)}, stop, ready, io.Discard, errOut)
+	if err != nil {
+		return nil, err
+	}
+	forwardErr := make(chan error, 1)
+	go func() { forwardErr <- fw.ForwardPorts() }()
+	select {
+	case <-ready:
+	case err := <-forwardErr:
+		return nil, fmt.Errorf("port-forward to %s/%s: %w", namespace, pod, err)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	ports, err := fw.GetPorts()
+	if err != nil || len(ports) == 0 {
+		return nil, fmt.Errorf("port-forward to %s/%s: no local port: %v", namespace, pod, err)
+	}
+
+	scheme := "http"
+	if probe.HTTPS {
+		scheme = "https"
+	}
+	path := probe.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://127.0.0.1:%d%s", scheme, ports[0].Local, path), nil)
+	if err != nil {
+		return nil, err
+	}
+	if probe.Host != "" {
+		httpReq.Host = probe.Host
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+		// Report redirects rather than following them out of the forward.
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	start := time.Now()
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if msg := strings.TrimSpace(errOut.String()); msg != "" {
+			return nil, fmt.Errorf("%w (port-forward: %s)", err, msg)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start)
+
+	maxBody := probe.MaxBody
+	if maxBody <= 0 {
+		maxBody = 2048
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, int64(maxBody)+1))
+	truncated := len(body) > maxBody
+	if truncated {
+		body = body[:maxBody]
+	}
+	return &HTTPProbeResult{
+		StatusCode:    resp.StatusCode,
+		Status:        resp.Status,
+		Latency:       latency,
+		Header:        resp.Header,
+		Body:          string(body),
+		BodyTruncated: truncated,
+	}, nil
+}
+
+// syncBuffer is a bytes.Buffer safe for the port-forwarder's concurrent writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,26 @@
+package k8s
+
+import (
+	"context"
+
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// ListPriorityClasses returns all PriorityClasses.
+func (c *ClusterClient) ListPriorityClasses(ctx context.Context) ([]schedulingv1.PriorityClass, error) {
+	if err := c.clusterScope("PriorityClasses"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	list, err := c.Clientset.SchedulingV1().PriorityClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
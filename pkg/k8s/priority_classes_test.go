@@ -0,0 +1,35 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+func TestListPriorityClasses(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "system-cluster-critical"}, Value: 2000000000},
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "business-critical"}, Value: 100000},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+	)
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	classes, err := client.ListPriorityClasses(context.Background())
+	if err != nil {
+		t.Fatalf("ListPriorityClasses() error = %v", err)
+	}
+	if len(classes) != 2 {
+		t.Errorf("expected 2 priority classes, got %d", len(classes))
+	}
+
+	client.Namespaces = []string{"team-a"}
+	if _, err := client.ListPriorityClasses(context.Background()); !util.IsScopeError(err) {
+		t.Errorf("ListPriorityClasses() in namespace-scoped mode error = %v, want ScopeError", err)
+	}
+}
@@ -0,0 +1,194 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// PrometheusClient runs PromQL queries against a Prometheus-compatible HTTP
+// API (Prometheus, Thanos, Mimir) for historical usage that metrics-server
+// cannot provide.
+type PrometheusClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewPrometheusClient returns a client for the Prometheus API at baseURL,
+// e.g. http://prometheus.monitoring:9090.
+func NewPrometheusClient(baseURL string) (*PrometheusClient, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid Prometheus URL %q", baseURL)
+	}
+	return &PrometheusClient{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{},
+	}, nil
+}
+
+// PromSample is one series of an instant-vector query result.
+type PromSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+type promResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]any            `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// Query runs an instant PromQL query and returns the resulting vector.
+// NaN and infinite samples are dropped.
+func (p *PrometheusClient) Query(ctx context.Context, query string) ([]PromSample, error) {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+"/api/v1/query?query="+url.QueryEscape(query), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying Prometheus: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body promResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding Prometheus response (%s): %w", resp.Status, err)
+	}
+	if body.Status != "success" {
+		return nil, fmt.Errorf("Prometheus query failed (%s): %s: %s", resp.Status, body.ErrorType, body.Error)
+	}
+	if body.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("Prometheus returned %q, expected an instant vector", body.Data.ResultType)
+	}
+
+	samples := make([]PromSample, 0, len(body.Data.Result))
+	for _, r := range body.Data.Result {
+		s, ok := r.Value[1].(string)
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		samples = append(samples, PromSample{Labels: r.Metric, Value: v})
+	}
+	return samples, nil
+}
+
+// UsageQuery selects the historical usage returned by UsageQuantile.
+type UsageQuery struct {
+	// Namespace limits the query to one namespace; empty means all namespaces.
+	Namespace string
+	// Window is how far back to look (default 7 days).
+	Window time.Duration
+	// Quantile is the usage quantile over the window (default 0.95).
+	Quantile float64
+	// PerContainer returns one result per container instead of per pod.
+	PerContainer bool
+}
+
+// Usage is the historical CPU and memory usage of a pod or container.
+type Usage struct {
+	Namespace   string
+	Pod         string
+	Container   string
+	CPUMillis   int64
+	MemoryBytes int64
+}
+
+// UsageQuantile returns the given quantile of CPU (5m rate) and working-set
+// memory over the window, per pod or per container, from cAdvisor metrics.
+func (p *PrometheusClient) UsageQuantile(ctx context.Context, q UsageQuery) ([]Usage, error) {
+	if q.Window <= 0 {
+		q.Window = 7 * 24 * time.Hour
+	}
+	if q.Quantile <= 0 || q.Quantile > 1 {
+		q.Quantile = 0.95
+	}
+
+	selector := `container!="",container!="POD"`
+	if q.Namespace != "" {
+		selector = fmt.Sprintf(`namespace=%q,%s`, q.Namespace, selector)
+	}
+	by := "namespace, pod"
+	if q.PerContainer {
+		by += ", container"
+	}
+	window := fmt.Sprintf("%dm", int(q.Window.Minutes()))
+	cpuQuery := fmt.Sprintf("quantile_over_time(%g, sum by (%s) (rate(container_cpu_usage_seconds_total{%s}[5m]))[%s:5m])",
+		q.Quantile, by, selector, window)
+	memQuery := fmt.Sprintf("quantile_over_time(%g, sum by (%s) (container_memory_working_set_bytes{%s})[%s:5m])",
+		q.Quantile, by, selector, window)
+
+	cpu, err := p.Query(ctx, cpuQuery)
+	if err != nil {
+		return nil, err
+	}
+	mem, err := p.Query(ctx, memQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*Usage)
+	var order []string
+	get := func(labels map[string]string) *Usage {
+		key := labels["namespace"] + "/" + labels["pod"] + "/" + labels["container"]
+		u, ok := byKey[key]
+		if !ok {
+			u = &Usage{Namespace: labels["namespace"], Pod: labels["pod"], Container: labels["container"]}
+			byKey[key] = u
+			order = append(order, key)
+		}
+		return u
+	}
+	for _, s := range cpu {
+		get(s.Labels).CPUMillis = int64(math.Round(s.Value * 1000))
+	}
+	for _, s := range mem {
+		get(s.Labels).MemoryBytes = int64(s.Value)
+	}
+
+	usage := make([]Usage, 0, len(order))
+	for _, key := range order {
+		usage = append(usage, *byKey[key])
+	}
+	return usage, nil
+}
+
+// GetUsageHistory returns historical usage from Prometheus. In namespace-scoped
+// mode all-namespace queries are split per accessible namespace, since
+// Prometheus does not enforce Kubernetes RBAC.
+func (c *ClusterClient) GetUsageHistory(ctx context.Context, q UsageQuery) ([]Usage, error) {
+	if c.Prometheus == nil {
+		return nil, fmt.Errorf("Prometheus not configured (start the server with --prometheus-url)")
+	}
+	if c.fanOut(q.Namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]Usage, error) {
+			scoped := q
+			scoped.Namespace = ns
+			return c.Prometheus.UsageQuantile(ctx, scoped)
+		})
+	}
+	return c.Prometheus.UsageQuantile(ctx, q)
+}
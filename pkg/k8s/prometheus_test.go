@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUsageQuantile(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("query")
+		queries = append(queries, q)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(q, "container_cpu_usage_seconds_total") {
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"namespace":"shop","pod":"web-1"},"value":[1700000000,"0.2504"]},
+				{"metric":{"namespace":"shop","pod":"web-2"},"value":[1700000000,"NaN"]}]}}`))
+			return
+		}
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"namespace":"shop","pod":"web-1"},"value":[1700000000,"134217728"]}]}}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewPrometheusClient(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	usage, err := p.UsageQuantile(context.Background(), UsageQuery{Namespace: "shop"})
+	if err != nil {
+		t.Fatalf("UsageQuantile() error = %v", err)
+	}
+	if len(usage) != 1 || usage[0].Pod != "web-1" || usage[0].CPUMillis != 250 || usage[0].MemoryBytes != 134217728 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	if len(queries) != 2 || !strings.HasPrefix(queries[0], "quantile_over_time(0.95,") ||
+		!strings.Contains(queries[0], `namespace="shop"`) || !strings.Contains(queries[0], "[10080m:5m]") {
+		t.Errorf("unexpected queries %v", queries)
+	}
+}
+
+func TestPrometheusQueryError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+	}))
+	defer srv.Close()
+
+	p, _ := NewPrometheusClient(srv.URL)
+	if _, err := p.Query(context.Background(), "up{"); err == nil || !strings.Contains(err.Error(), "parse error") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+	if _, err := NewPrometheusClient("prometheus:9090"); err == nil {
+		t.Error("expected error for URL without scheme")
+	}
+}
@@ -11,7 +11,13 @@ import (
 
 // ListRoles returns roles in the given namespace.
 func (c *ClusterClient) ListRoles(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.Role, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]rbacv1.Role, error) {
+			return c.ListRoles(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.RbacV1().Roles(namespace).List(ctx, opts)
@@ -23,7 +29,11 @@ func (c *ClusterClient) ListRoles(ctx context.Context, namespace string, opts me
 
 // ListClusterRoles returns all cluster roles.
 func (c *ClusterClient) ListClusterRoles(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRole, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if err := c.clusterScope("ClusterRoles"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.RbacV1().ClusterRoles().List(ctx, opts)
@@ -35,7 +45,13 @@ func (c *ClusterClient) ListClusterRoles(ctx context.Context, opts metav1.ListOp
 
 // ListRoleBindings returns role bindings in the given namespace.
 func (c *ClusterClient) ListRoleBindings(ctx context.Context, namespace string, opts metav1.ListOptions) ([]rbacv1.RoleBinding, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]rbacv1.RoleBinding, error) {
+			return c.ListRoleBindings(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.RbacV1().RoleBindings(namespace).List(ctx, opts)
@@ -50,7 +66,11 @@ func (c *ClusterClient) ListRoleBindings(ctx context.Context, namespace string,
 
 // ListClusterRoleBindings returns all cluster role bindings.
 func (c *ClusterClient) ListClusterRoleBindings(ctx context.Context, opts metav1.ListOptions) ([]rbacv1.ClusterRoleBinding, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if err := c.clusterScope("ClusterRoleBindings"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.RbacV1().ClusterRoleBindings().List(ctx, opts)
@@ -62,3 +82,21 @@ func (c *ClusterClient) ListClusterRoleBindings(ctx context.Context, opts metav1
 	}
 	return list.Items, nil
 }
+
+// ListRoleBindingsPage returns one page of role bindings in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListRoleBindingsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[rbacv1.RoleBinding], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*rbacv1.RoleBindingList, error) {
+		return c.Clientset.RbacV1().RoleBindings(ns).List(ctx, opts)
+	}, func(l *rbacv1.RoleBindingList) []rbacv1.RoleBinding {
+		return l.Items
+	})
+}
+
+// ListClusterRoleBindingsPage returns one page of cluster role bindings.
+func (c *ClusterClient) ListClusterRoleBindingsPage(ctx context.Context, opts metav1.ListOptions) (Page[rbacv1.ClusterRoleBinding], error) {
+	return clusterPage(ctx, c, "ClusterRoleBindings", opts, func(ctx context.Context, opts metav1.ListOptions) (*rbacv1.ClusterRoleBindingList, error) {
+		return c.Clientset.RbacV1().ClusterRoleBindings().List(ctx, opts)
+	}, func(l *rbacv1.ClusterRoleBindingList) []rbacv1.ClusterRoleBinding {
+		return l.Items
+	})
+}
@@ -0,0 +1,86 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// RestartedAtAnnotation is the pod template annotation `kubectl rollout restart` sets.
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// dryRunOpts returns the DryRun option that makes the API server validate and
+// admit a write without persisting it.
+func dryRunOpts(dryRun bool) []string {
+	if dryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
+// RestartPatch returns the strategic merge patch that restarts a workload's
+// pods the way `kubectl rollout restart` does.
+func RestartPatch(at time.Time) []byte {
+	return []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, RestartedAtAnnotation, at.Format(time.RFC3339)))
+}
+
+// RestartDeployment triggers a rolling restart of a Deployment by stamping its
+// pod template. With dryRun the API server validates the patch without applying it.
+func (c *ClusterClient) RestartDeployment(ctx context.Context, namespace, name string, patch []byte, dryRun bool) (*appsv1.Deployment, error) {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	return c.Clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch,
+		metav1.PatchOptions{DryRun: dryRunOpts(dryRun), FieldManager: "kube-doctor"})
+}
+
+// ScaleDeployment sets a Deployment's replica count through its scale
+// subresource. With dryRun the API server validates the change without applying it.
+func (c *ClusterClient) ScaleDeployment(ctx context.Context, namespace, name string, replicas int32, dryRun bool) (*autoscalingv1.Scale, error) {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	deployments := c.Clientset.AppsV1().Deployments(namespace)
+	scale, err := deployments.GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	scale.Spec.Replicas = replicas
+	return deployments.UpdateScale(ctx, name, scale, metav1.UpdateOptions{DryRun: dryRunOpts(dryRun), FieldManager: "kube-doctor"})
+}
+
+// DeletePod deletes a pod. A nil gracePeriod uses the pod's own
+// terminationGracePeriodSeconds; 0 removes it immediately without waiting for
+// the kubelet. With dryRun the API server validates the delete without applying it.
+func (c *ClusterClient) DeletePod(ctx context.Context, namespace, name string, gracePeriod *int64, dryRun bool) error {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	return c.Clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		GracePeriodSeconds: gracePeriod,
+		DryRun:             dryRunOpts(dryRun),
+	})
+}
+
+// SetNodeUnschedulable cordons (true) or uncordons (false) a node. With
+// dryRun the API server validates the change without applying it.
+func (c *ClusterClient) SetNodeUnschedulable(ctx context.Context, name string, unschedulable, dryRun bool) (*corev1.Node, error) {
+	if err := c.clusterScope("Nodes"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	patch := []byte(fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable))
+	return c.Clientset.CoreV1().Nodes().Patch(ctx, name, types.StrategicMergePatchType, patch,
+		metav1.PatchOptions{DryRun: dryRunOpts(dryRun), FieldManager: "kube-doctor"})
+}
@@ -0,0 +1,55 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRestartDeployment(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+	})
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	d, err := client.RestartDeployment(context.Background(), "shop", "web", RestartPatch(at), false)
+	if err != nil {
+		t.Fatalf("RestartDeployment() error = %v", err)
+	}
+	if got := d.Spec.Template.Annotations[RestartedAtAnnotation]; got != "2025-01-02T03:04:05Z" {
+		t.Errorf("expected restartedAt annotation, got %q", got)
+	}
+}
+
+func TestScaleDeploymentDryRun(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	var dryRun []string
+	fakeClient.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &autoscalingv1.Scale{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Spec: autoscalingv1.ScaleSpec{Replicas: 2}}, nil
+	})
+	fakeClient.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		update := action.(k8stesting.UpdateActionImpl)
+		dryRun = update.UpdateOptions.DryRun
+		return true, update.GetObject(), nil
+	})
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	scale, err := client.ScaleDeployment(context.Background(), "shop", "web", 5, true)
+	if err != nil {
+		t.Fatalf("ScaleDeployment() error = %v", err)
+	}
+	if scale.Spec.Replicas != 5 {
+		t.Errorf("expected 5 replicas, got %d", scale.Spec.Replicas)
+	}
+	if len(dryRun) != 1 || dryRun[0] != metav1.DryRunAll {
+		t.Errorf("expected server-side dry run, got %v", dryRun)
+	}
+}
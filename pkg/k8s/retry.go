@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// transientRetryAttempts is how many times a read failing with a transient
+	// error is retried.
+	transientRetryAttempts = 3
+
+	// transientRetryBackoff is the base delay of the exponential backoff
+	// between transient retries.
+	transientRetryBackoff = 250 * time.Millisecond
+
+	// transientRetryMaxBackoff caps a single backoff delay, including one
+	// requested by a Retry-After header.
+	transientRetryMaxBackoff = 4 * time.Second
+)
+
+// retryTransport retries reads (GET and HEAD, which back every list and get)
+// that fail with a transient error: API server throttling (429), an
+// unavailable or timed-out server (503, 504), a connection reset, or a network
+// timeout. Delays grow exponentially with full jitter and never outlast the
+// request's deadline, so one flaky call does not fail a whole composite tool.
+// Writes are never retried. Retries are counted in the request's CallStats.
+type retryTransport struct {
+	base       http.RoundTripper
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// newRetryTransport is a rest.Config transport wrapper for transient retries.
+func newRetryTransport(rt http.RoundTripper) http.RoundTripper {
+	return &retryTransport{base: rt, backoff: transientRetryBackoff, maxBackoff: transientRetryMaxBackoff}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return resp, err
+	}
+	for attempt := 1; attempt <= transientRetryAttempts && isTransient(resp, err) && req.Context().Err() == nil; attempt++ {
+		delay := t.delay(attempt, resp)
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < delay {
+			break
+		}
+		retry, ok := cloneForRetry(req)
+		if !ok {
+			break
+		}
+		reason := transientReason(resp, err)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		log.Printf("Transient error for %s %s (%s); retrying in %s (attempt %d/%d)", req.Method, req.URL.Path, reason, delay.Round(time.Millisecond), attempt, transientRetryAttempts)
+		if stats := callStatsFrom(req.Context()); stats != nil {
+			stats.retries.Add(1)
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		resp, err = t.base.RoundTrip(retry)
+	}
+	return resp, err
+}
+
+// delay returns the wait before retry attempt n: the server's Retry-After
+// when it sent one, otherwise a random duration up to backoff*2^(n-1), capped
+// at maxBackoff.
+func (t *retryTransport) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			return min(time.Duration(secs)*time.Second, t.maxBackoff)
+		}
+	}
+	ceiling := min(t.backoff<<(attempt-1), t.maxBackoff)
+	return time.Duration(rand.Int64N(int64(ceiling))) + 1
+}
+
+// isTransient reports whether a response or error is likely to succeed when
+// the request is repeated.
+func isTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return true
+		}
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// transientReason describes a transient failure for the retry log.
+func transientReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
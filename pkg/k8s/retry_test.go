@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryTransportRetriesTransientReads(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{base: newAccountingTransport(http.DefaultTransport), backoff: time.Millisecond, maxBackoff: 5 * time.Millisecond}}
+	ctx, stats := WithCallStats(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 after retries, got %d", resp.StatusCode)
+	}
+	if stats.Calls() != 3 || stats.Retries() != 2 {
+		t.Errorf("stats = %d calls, %d retries; want 3 calls, 2 retries", stats.Calls(), stats.Retries())
+	}
+}
+
+func TestRetryTransportSkipsWrites(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, backoff: time.Millisecond, maxBackoff: time.Millisecond}}
+	resp, err := client.Post(srv.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Errorf("expected a write not to be retried, got %d calls", calls.Load())
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, backoff: time.Millisecond, maxBackoff: time.Millisecond}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("expected final 504 to be returned, got %d", resp.StatusCode)
+	}
+	if want := int32(transientRetryAttempts + 1); calls.Load() != want {
+		t.Errorf("expected %d calls, got %d", want, calls.Load())
+	}
+}
+
+func TestRetryTransportDelay(t *testing.T) {
+	rt := &retryTransport{backoff: 100 * time.Millisecond, maxBackoff: time.Second}
+	for attempt := 1; attempt <= 6; attempt++ {
+		if d := rt.delay(attempt, nil); d <= 0 || d > time.Second {
+			t.Errorf("delay(%d) = %s, want within (0, 1s]", attempt, d)
+		}
+	}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"30"}}}
+	if d := rt.delay(1, resp); d != time.Second {
+		t.Errorf("delay with Retry-After 30 = %s, want the 1s cap", d)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *http.Response
+		err  error
+		want bool
+	}{
+		{"throttled", &http.Response{StatusCode: http.StatusTooManyRequests}, nil, true},
+		{"not found", &http.Response{StatusCode: http.StatusNotFound}, nil, false},
+		{"server error", &http.Response{StatusCode: http.StatusInternalServerError}, nil, false},
+		{"connection reset", nil, syscall.ECONNRESET, true},
+		{"truncated body", nil, io.ErrUnexpectedEOF, true},
+		{"refused", nil, syscall.ECONNREFUSED, false},
+	}
+	for _, tt := range tests {
+		if got := isTransient(tt.resp, tt.err); got != tt.want {
+			t.Errorf("%s: isTransient = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
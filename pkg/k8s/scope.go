@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// IsNamespaceScoped reports whether the client only has access to specific namespaces.
+func (c *ClusterClient) IsNamespaceScoped() bool {
+	return len(c.Namespaces) > 0
+}
+
+// fanOut reports whether an all-namespaces query must be split into one
+// query per accessible namespace.
+func (c *ClusterClient) fanOut(namespace string) bool {
+	return namespace == "" && c.IsNamespaceScoped()
+}
+
+// clusterScope returns a ScopeError for a cluster-scoped query in namespace-scoped mode.
+func (c *ClusterClient) clusterScope(resource string) error {
+	if !c.IsNamespaceScoped() {
+		return nil
+	}
+	return &util.ScopeError{Resource: resource, Namespaces: c.Namespaces}
+}
+
+// listAcross runs list once per accessible namespace and concatenates the results.
+func listAcross[T any](ctx context.Context, c *ClusterClient, list func(ctx context.Context, namespace string) ([]T, error)) ([]T, error) {
+	var all []T
+	for _, ns := range c.Namespaces {
+		items, err := list(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// scopedNamespaces returns the accessible namespaces, reading each one when
+// RBAC allows and synthesizing an Active namespace when it does not.
+// Namespaces that do not exist are left out.
+func (c *ClusterClient) scopedNamespaces(ctx context.Context) ([]corev1.Namespace, error) {
+	out := make([]corev1.Namespace, 0, len(c.Namespaces))
+	for _, name := range c.Namespaces {
+		ns, err := c.Clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			out = append(out, *ns)
+		case apierrors.IsNotFound(err):
+		case apierrors.IsForbidden(err):
+			out = append(out, *syntheticNamespace(name))
+		default:
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// syntheticNamespace stands in for an accessible namespace whose object the
+// credentials are not allowed to read.
+func syntheticNamespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+	}
+}
+
+// namespaceGuard rejects API requests outside an allowlist of namespaces with
+// a Forbidden status. Discovery and version endpoints, and reads of the
+// allowed Namespace objects themselves, are let through.
+type namespaceGuard struct {
+	base    http.RoundTripper
+	allowed map[string]bool
+}
+
+// newNamespaceGuard returns a transport wrapper enforcing the namespace allowlist.
+func newNamespaceGuard(namespaces []string) func(http.RoundTripper) http.RoundTripper {
+	allowed := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		allowed[ns] = true
+	}
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &namespaceGuard{base: rt, allowed: allowed}
+	}
+}
+
+func (g *namespaceGuard) RoundTrip(req *http.Request) (*http.Response, error) {
+	if reason := g.deny(req.URL.Path); reason != "" {
+		return forbiddenResponse(req, reason), nil
+	}
+	return g.base.RoundTrip(req)
+}
+
+// deny returns why path is outside the allowlist, or "" if it is allowed.
+func (g *namespaceGuard) deny(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	// Namespaced resources: /api/v1/namespaces/{ns}/... or /apis/{group}/{version}/namespaces/{ns}/...
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "namespaces" && (i == 2 && parts[0] == "api" || i == 3 && parts[0] == "apis") {
+			if g.allowed[parts[i+1]] {
+				return ""
+			}
+			return fmt.Sprintf("namespace %q is not in the kube-doctor namespace allowlist", parts[i+1])
+		}
+	}
+	// Discovery: /version, /api, /api/v1, /apis, /apis/{group}, /apis/{group}/{version}, /openapi/...
+	switch {
+	case parts[0] == "version" || parts[0] == "openapi":
+		return ""
+	case parts[0] == "api" && len(parts) <= 2, parts[0] == "apis" && len(parts) <= 3:
+		return ""
+	}
+	return "cluster-wide and cluster-scoped requests are not allowed by the kube-doctor namespace allowlist"
+}
+
+// forbiddenResponse builds a 403 Status response that client-go decodes into a Forbidden error.
+func forbiddenResponse(req *http.Request, reason string) *http.Response {
+	body, _ := json.Marshal(metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  reason,
+		Reason:   metav1.StatusReasonForbidden,
+		Code:     http.StatusForbidden,
+	})
+	return &http.Response{
+		StatusCode: http.StatusForbidden,
+		Status:     "403 Forbidden",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+	}
+}
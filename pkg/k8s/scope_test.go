@@ -0,0 +1,133 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+func TestListPodsNamespaceScoped(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team-a"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "team-b"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "other"}},
+	)
+	client := NewClusterClientForTesting(fakeClient, nil)
+	client.Namespaces = []string{"team-a", "team-b"}
+
+	pods, err := client.ListPods(context.Background(), "", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListPods() error = %v", err)
+	}
+	if len(pods) != 2 {
+		t.Errorf("expected 2 pods from accessible namespaces, got %d", len(pods))
+	}
+
+	pods, err = client.ListPods(context.Background(), "other", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListPods() error = %v", err)
+	}
+	if len(pods) != 1 {
+		t.Errorf("expected explicit namespace to be queried directly, got %d pods", len(pods))
+	}
+}
+
+func TestClusterScopedQueriesNamespaceScoped(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}},
+	)
+	client := NewClusterClientForTesting(fakeClient, nil)
+	client.Namespaces = []string{"team-a", "team-b"}
+
+	if _, err := client.ListNodes(context.Background(), metav1.ListOptions{}); !util.IsScopeError(err) {
+		t.Errorf("ListNodes() error = %v, want ScopeError", err)
+	}
+	if _, err := client.ListPVs(context.Background()); !util.IsScopeError(err) {
+		t.Errorf("ListPVs() error = %v, want ScopeError", err)
+	}
+
+	namespaces, err := client.ListNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("ListNamespaces() error = %v", err)
+	}
+	if len(namespaces) != 1 || namespaces[0].Name != "team-a" {
+		t.Errorf("expected only readable accessible namespaces, got %v", namespaces)
+	}
+}
+
+func TestListNamespacesSynthesizedWhenForbidden(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("get", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.GetAction).GetName()
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, name, nil)
+	})
+	client := NewClusterClientForTesting(fakeClient, nil)
+	client.Namespaces = []string{"team-a"}
+
+	namespaces, err := client.ListNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("ListNamespaces() error = %v", err)
+	}
+	if len(namespaces) != 1 || namespaces[0].Name != "team-a" || namespaces[0].Status.Phase != corev1.NamespaceActive {
+		t.Errorf("expected synthesized Active namespace team-a, got %v", namespaces)
+	}
+}
+
+func TestNamespaceGuard(t *testing.T) {
+	g := &namespaceGuard{allowed: map[string]bool{"team-a": true}}
+	tests := []struct {
+		path    string
+		allowed bool
+	}{
+		{"/api/v1/namespaces/team-a/pods", true},
+		{"/apis/apps/v1/namespaces/team-a/deployments/web", true},
+		{"/api/v1/namespaces/team-a", true},
+		{"/apis/metrics.k8s.io/v1beta1/namespaces/team-a/pods", true},
+		{"/version", true},
+		{"/apis/apps/v1", true},
+		{"/api/v1/namespaces/kube-system/pods", false},
+		{"/apis/apps/v1/namespaces/other/deployments", false},
+		{"/api/v1/pods", false},
+		{"/api/v1/nodes", false},
+		{"/api/v1/namespaces", false},
+		{"/apis/apiextensions.k8s.io/v1/customresourcedefinitions", false},
+	}
+	for _, tt := range tests {
+		if got := g.deny(tt.path) == ""; got != tt.allowed {
+			t.Errorf("deny(%q) allowed = %v, want %v", tt.path, got, tt.allowed)
+		}
+	}
+}
+
+func TestNamespaceGuardReturnsForbidden(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request to %s should have been blocked", r.URL.Path)
+	}))
+	defer srv.Close()
+
+	config := &rest.Config{Host: srv.URL}
+	config.Wrap(newNamespaceGuard([]string{"team-a"}))
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("NewForConfig() error = %v", err)
+	}
+
+	_, err = clientset.CoreV1().Pods("kube-system").List(context.Background(), metav1.ListOptions{})
+	if !apierrors.IsForbidden(err) {
+		t.Errorf("expected Forbidden, got %v", err)
+	}
+}
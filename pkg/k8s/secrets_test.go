@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetSecret(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Namespace: "payments"},
+			Type:       corev1.SecretTypeOpaque,
+		},
+	)
+
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	secret, err := client.GetSecret(context.Background(), "payments", "db-credentials")
+	if err != nil {
+		t.Fatalf("GetSecret() error = %v", err)
+	}
+	if secret.Type != corev1.SecretTypeOpaque {
+		t.Errorf("expected Opaque secret, got %q", secret.Type)
+	}
+}
+
+func TestListSecrets(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Namespace: "payments"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "payments"}, Type: corev1.SecretTypeTLS},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "shop"}, Type: corev1.SecretTypeTLS},
+	)
+
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	secrets, err := client.ListSecrets(context.Background(), "", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListSecrets() error = %v", err)
+	}
+	if len(secrets) != 3 {
+		t.Errorf("expected 3 secrets across namespaces, got %d", len(secrets))
+	}
+
+	client.Namespaces = []string{"shop"}
+	secrets, err = client.ListSecrets(context.Background(), "", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("ListSecrets() scoped error = %v", err)
+	}
+	if len(secrets) != 1 || secrets[0].Namespace != "shop" {
+		t.Errorf("expected only the shop secret in namespace-scoped mode, got %v", secrets)
+	}
+}
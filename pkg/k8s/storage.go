@@ -4,6 +4,7 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
@@ -11,7 +12,13 @@ import (
 
 // ListPVCs returns PersistentVolumeClaims in the given namespace.
 func (c *ClusterClient) ListPVCs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]corev1.PersistentVolumeClaim, error) {
+			return c.ListPVCs(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
@@ -21,9 +28,20 @@ func (c *ClusterClient) ListPVCs(ctx context.Context, namespace string, opts met
 	return list.Items, nil
 }
 
+// ListPVCsPage returns one page of PVCs in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListPVCsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[corev1.PersistentVolumeClaim], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.PersistentVolumeClaimList, error) {
+		return c.Clientset.CoreV1().PersistentVolumeClaims(ns).List(ctx, opts)
+	}, func(l *corev1.PersistentVolumeClaimList) []corev1.PersistentVolumeClaim { return l.Items })
+}
+
 // ListPVs returns all PersistentVolumes.
 func (c *ClusterClient) ListPVs(ctx context.Context) ([]corev1.PersistentVolume, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if err := c.clusterScope("PersistentVolumes"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
@@ -32,3 +50,58 @@ func (c *ClusterClient) ListPVs(ctx context.Context) ([]corev1.PersistentVolume,
 	}
 	return list.Items, nil
 }
+
+// ListPVsPage returns one page of PersistentVolumes.
+func (c *ClusterClient) ListPVsPage(ctx context.Context, opts metav1.ListOptions) (Page[corev1.PersistentVolume], error) {
+	return clusterPage(ctx, c, "PersistentVolumes", opts, func(ctx context.Context, opts metav1.ListOptions) (*corev1.PersistentVolumeList, error) {
+		return c.Clientset.CoreV1().PersistentVolumes().List(ctx, opts)
+	}, func(l *corev1.PersistentVolumeList) []corev1.PersistentVolume { return l.Items })
+}
+
+// ListStorageClasses returns all StorageClasses.
+func (c *ClusterClient) ListStorageClasses(ctx context.Context) ([]storagev1.StorageClass, error) {
+	if err := c.clusterScope("StorageClasses"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	list, err := c.Clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ListVolumeAttachments returns all CSI VolumeAttachments.
+func (c *ClusterClient) ListVolumeAttachments(ctx context.Context) ([]storagev1.VolumeAttachment, error) {
+	if err := c.clusterScope("VolumeAttachments"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	list, err := c.Clientset.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ListCSIDrivers returns all registered CSIDrivers.
+func (c *ClusterClient) ListCSIDrivers(ctx context.Context) ([]storagev1.CSIDriver, error) {
+	if err := c.clusterScope("CSIDrivers"); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	list, err := c.Clientset.StorageV1().CSIDrivers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -69,3 +70,43 @@ func TestListPVs(t *testing.T) {
 		t.Errorf("expected 1 PV, got %d", len(pvs))
 	}
 }
+
+func TestListStorageObjects(t *testing.T) {
+	attachErr := &storagev1.VolumeError{Message: "disk is attached to another node"}
+	fakeClient := fake.NewSimpleClientset(
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "managed-csi"}, Provisioner: "disk.csi.azure.com"},
+		&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "disk.csi.azure.com"}},
+		&storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: "csi-123"},
+			Spec:       storagev1.VolumeAttachmentSpec{Attacher: "disk.csi.azure.com", NodeName: "node-1"},
+			Status:     storagev1.VolumeAttachmentStatus{AttachError: attachErr},
+		},
+	)
+
+	client := NewClusterClientForTesting(fakeClient, nil)
+	ctx := context.Background()
+
+	classes, err := client.ListStorageClasses(ctx)
+	if err != nil {
+		t.Fatalf("ListStorageClasses() error = %v", err)
+	}
+	if len(classes) != 1 || classes[0].Provisioner != "disk.csi.azure.com" {
+		t.Errorf("unexpected storage classes %+v", classes)
+	}
+
+	drivers, err := client.ListCSIDrivers(ctx)
+	if err != nil {
+		t.Fatalf("ListCSIDrivers() error = %v", err)
+	}
+	if len(drivers) != 1 {
+		t.Errorf("expected 1 CSI driver, got %d", len(drivers))
+	}
+
+	attachments, err := client.ListVolumeAttachments(ctx)
+	if err != nil {
+		t.Fatalf("ListVolumeAttachments() error = %v", err)
+	}
+	if len(attachments) != 1 || attachments[0].Status.AttachError == nil {
+		t.Errorf("expected 1 attachment with an attach error, got %+v", attachments)
+	}
+}
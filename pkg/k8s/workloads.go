@@ -12,7 +12,13 @@ import (
 
 // ListDeployments returns deployments in the given namespace.
 func (c *ClusterClient) ListDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]appsv1.Deployment, error) {
+			return c.ListDeployments(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.AppsV1().Deployments(namespace).List(ctx, opts)
@@ -22,17 +28,54 @@ func (c *ClusterClient) ListDeployments(ctx context.Context, namespace string, o
 	return list.Items, nil
 }
 
+// ListDeploymentsPage returns one page of deployments in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListDeploymentsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[appsv1.Deployment], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*appsv1.DeploymentList, error) {
+		return c.Clientset.AppsV1().Deployments(ns).List(ctx, opts)
+	}, func(l *appsv1.DeploymentList) []appsv1.Deployment { return l.Items })
+}
+
 // GetDeployment returns a single deployment by name.
 func (c *ClusterClient) GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	return c.Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
+// GetStatefulSet returns a single StatefulSet by name.
+func (c *ClusterClient) GetStatefulSet(ctx context.Context, namespace, name string) (*appsv1.StatefulSet, error) {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	return c.Clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetDaemonSet returns a single DaemonSet by name.
+func (c *ClusterClient) GetDaemonSet(ctx context.Context, namespace, name string) (*appsv1.DaemonSet, error) {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	return c.Clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetJob returns a single Job by name.
+func (c *ClusterClient) GetJob(ctx context.Context, namespace, name string) (*batchv1.Job, error) {
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	return c.Clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
 // ListReplicaSets returns ReplicaSets in the given namespace.
 func (c *ClusterClient) ListReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]appsv1.ReplicaSet, error) {
+			return c.ListReplicaSets(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
@@ -44,7 +87,13 @@ func (c *ClusterClient) ListReplicaSets(ctx context.Context, namespace string, o
 
 // ListStatefulSets returns StatefulSets in the given namespace.
 func (c *ClusterClient) ListStatefulSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]appsv1.StatefulSet, error) {
+			return c.ListStatefulSets(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.AppsV1().StatefulSets(namespace).List(ctx, opts)
@@ -54,9 +103,22 @@ func (c *ClusterClient) ListStatefulSets(ctx context.Context, namespace string,
 	return list.Items, nil
 }
 
+// ListStatefulSetsPage returns one page of StatefulSets in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListStatefulSetsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[appsv1.StatefulSet], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*appsv1.StatefulSetList, error) {
+		return c.Clientset.AppsV1().StatefulSets(ns).List(ctx, opts)
+	}, func(l *appsv1.StatefulSetList) []appsv1.StatefulSet { return l.Items })
+}
+
 // ListDaemonSets returns DaemonSets in the given namespace.
 func (c *ClusterClient) ListDaemonSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]appsv1.DaemonSet, error) {
+			return c.ListDaemonSets(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.AppsV1().DaemonSets(namespace).List(ctx, opts)
@@ -66,9 +128,22 @@ func (c *ClusterClient) ListDaemonSets(ctx context.Context, namespace string, op
 	return list.Items, nil
 }
 
+// ListDaemonSetsPage returns one page of DaemonSets in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListDaemonSetsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[appsv1.DaemonSet], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*appsv1.DaemonSetList, error) {
+		return c.Clientset.AppsV1().DaemonSets(ns).List(ctx, opts)
+	}, func(l *appsv1.DaemonSetList) []appsv1.DaemonSet { return l.Items })
+}
+
 // ListJobs returns Jobs in the given namespace.
 func (c *ClusterClient) ListJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]batchv1.Job, error) {
+			return c.ListJobs(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.BatchV1().Jobs(namespace).List(ctx, opts)
@@ -78,9 +153,22 @@ func (c *ClusterClient) ListJobs(ctx context.Context, namespace string, opts met
 	return list.Items, nil
 }
 
+// ListJobsPage returns one page of jobs in the given namespace (empty = all namespaces).
+func (c *ClusterClient) ListJobsPage(ctx context.Context, namespace string, opts metav1.ListOptions) (Page[batchv1.Job], error) {
+	return listPage(ctx, c, namespace, opts, func(ctx context.Context, ns string, opts metav1.ListOptions) (*batchv1.JobList, error) {
+		return c.Clientset.BatchV1().Jobs(ns).List(ctx, opts)
+	}, func(l *batchv1.JobList) []batchv1.Job { return l.Items })
+}
+
 // ListCronJobs returns CronJobs in the given namespace.
 func (c *ClusterClient) ListCronJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, error) {
-	ctx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
+	if c.fanOut(namespace) {
+		return listAcross(ctx, c, func(ctx context.Context, ns string) ([]batchv1.CronJob, error) {
+			return c.ListCronJobs(ctx, ns, opts)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
 	defer cancel()
 
 	list, err := c.Clientset.BatchV1().CronJobs(namespace).List(ctx, opts)
@@ -71,6 +71,31 @@ func TestListStatefulSets(t *testing.T) {
 	}
 }
 
+func TestGetStatefulSetAndDaemonSet(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "kube-system"}},
+	)
+
+	client := NewClusterClientForTesting(fakeClient, nil)
+
+	sts, err := client.GetStatefulSet(context.Background(), "default", "db")
+	if err != nil {
+		t.Fatalf("GetStatefulSet() error = %v", err)
+	}
+	if sts.Name != "db" {
+		t.Errorf("expected statefulset 'db', got %q", sts.Name)
+	}
+
+	ds, err := client.GetDaemonSet(context.Background(), "kube-system", "agent")
+	if err != nil {
+		t.Fatalf("GetDaemonSet() error = %v", err)
+	}
+	if ds.Name != "agent" {
+		t.Errorf("expected daemonset 'agent', got %q", ds.Name)
+	}
+}
+
 func TestListJobs(t *testing.T) {
 	fakeClient := fake.NewSimpleClientset(
 		&batchv1.Job{
@@ -5,36 +5,137 @@ import (
 	"strings"
 )
 
-// Flowchart builds a Mermaid flowchart diagram.
+// Default diagram budget. Mermaid renderers slow down sharply and lay out
+// unreadably beyond roughly a hundred nodes.
+const (
+	DefaultMaxNodes = 80
+	DefaultMaxEdges = 150
+)
+
+// Flowchart builds a Mermaid flowchart diagram. Nodes and edges beyond the
+// budget are pruned: pruned nodes are aggregated into one "+N more" node per
+// subgraph, and edges to them are redirected to that node.
 type Flowchart struct {
 	direction Direction
 	lines     []string
 	styles    []string
+
+	maxNodes int
+	maxEdges int
+	nodes    int
+	edges    int
+
+	// pruned maps a pruned node ID to the aggregate node that replaces it.
+	pruned       map[string]string
+	prunedNodes  int
+	prunedEdges  int
+	edgeSeen     map[string]bool
+	rootOverflow overflow
+}
+
+// overflow counts the nodes pruned from one scope (the top level or a subgraph).
+type overflow struct {
+	id    string
+	noun  string
+	count int
 }
 
-// NewFlowchart creates a new Flowchart builder.
+// NewFlowchart creates a new Flowchart builder with the default budget.
 func NewFlowchart(dir Direction) *Flowchart {
-	return &Flowchart{direction: dir}
+	return &Flowchart{
+		direction:    dir,
+		maxNodes:     DefaultMaxNodes,
+		maxEdges:     DefaultMaxEdges,
+		pruned:       make(map[string]string),
+		edgeSeen:     make(map[string]bool),
+		rootOverflow: overflow{id: "pruned_more", noun: "items"},
+	}
+}
+
+// WithBudget sets the maximum number of nodes and edges to render. A value
+// of 0 or less disables that limit.
+func (f *Flowchart) WithBudget(maxNodes, maxEdges int) *Flowchart {
+	f.maxNodes = maxNodes
+	f.maxEdges = maxEdges
+	return f
+}
+
+// SetOverflowNoun names what top-level pruned nodes are (e.g. "pods") in the aggregate label.
+func (f *Flowchart) SetOverflowNoun(noun string) *Flowchart {
+	f.rootOverflow.noun = noun
+	return f
+}
+
+// PruneWarning returns a note describing what was pruned, or "" if nothing was.
+func (f *Flowchart) PruneWarning() string {
+	if f.prunedNodes == 0 && f.prunedEdges == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[WARNING] Diagram pruned to stay renderable: %d node(s) collapsed into \"+N more\" nodes and %d edge(s) omitted (budget: %d nodes, %d edges). Use the tables above for the full list.",
+		f.prunedNodes, f.prunedEdges, f.maxNodes, f.maxEdges)
+}
+
+// admitNode reports whether a node fits in the budget. A rejected node is
+// recorded against ov so edges to it are redirected to the aggregate node.
+func (f *Flowchart) admitNode(id string, ov *overflow) bool {
+	if f.maxNodes <= 0 || f.nodes < f.maxNodes {
+		f.nodes++
+		return true
+	}
+	f.pruned[id] = ov.id
+	f.prunedNodes++
+	ov.count++
+	return false
+}
+
+// edgeLine returns the rendered edge, or "" if the edge is pruned. Edges to
+// pruned nodes are redirected to their aggregate node once.
+func (f *Flowchart) edgeLine(from, to, label string, style EdgeStyle) string {
+	redirected := false
+	if agg, ok := f.pruned[from]; ok {
+		from, redirected = agg, true
+	}
+	if agg, ok := f.pruned[to]; ok {
+		to, redirected = agg, true
+	}
+	if redirected {
+		key := from + "->" + to
+		if from == to || f.edgeSeen[key] {
+			return ""
+		}
+		f.edgeSeen[key] = true
+		label = ""
+	}
+	if f.maxEdges > 0 && f.edges >= f.maxEdges {
+		f.prunedEdges++
+		return ""
+	}
+	f.edges++
+	return fmt.Sprintf("    %s %s %s", from, edgeArrow(style, EscapeLabel(label)), to)
 }
 
 // AddNode adds a node to the flowchart.
 func (f *Flowchart) AddNode(id, label string, shape Shape) *Flowchart {
-	f.lines = append(f.lines, "    "+nodeShape(id, EscapeLabel(label), shape))
+	if f.admitNode(id, &f.rootOverflow) {
+		f.lines = append(f.lines, "    "+nodeShape(id, EscapeLabel(label), shape))
+	}
 	return f
 }
 
 // AddEdge adds an edge between two nodes.
 func (f *Flowchart) AddEdge(from, to, label string, style EdgeStyle) *Flowchart {
-	f.lines = append(f.lines, fmt.Sprintf("    %s %s %s", from, edgeArrow(style, EscapeLabel(label)), to))
+	if line := f.edgeLine(from, to, label, style); line != "" {
+		f.lines = append(f.lines, line)
+	}
 	return f
 }
 
 // AddSubgraph adds a subgraph with a callback to populate it.
 func (f *Flowchart) AddSubgraph(id, label string, fn func(sg *Subgraph)) *Flowchart {
-	sg := &Subgraph{}
+	sg := newSubgraph(f, id)
 	fn(sg)
 	f.lines = append(f.lines, fmt.Sprintf("    subgraph %s[\"%s\"]", id, EscapeLabel(label)))
-	for _, line := range sg.lines {
+	for _, line := range sg.render() {
 		f.lines = append(f.lines, "    "+line)
 	}
 	f.lines = append(f.lines, "    end")
@@ -43,6 +144,9 @@ func (f *Flowchart) AddSubgraph(id, label string, fn func(sg *Subgraph)) *Flowch
 
 // AddStyle applies a severity-based style to a node.
 func (f *Flowchart) AddStyle(nodeID string, sev Severity) *Flowchart {
+	if _, ok := f.pruned[nodeID]; ok {
+		return f
+	}
 	if style, ok := severityStyles[sev]; ok {
 		f.styles = append(f.styles, fmt.Sprintf("    style %s %s", nodeID, style))
 	}
@@ -51,6 +155,9 @@ func (f *Flowchart) AddStyle(nodeID string, sev Severity) *Flowchart {
 
 // AddRawStyle applies a raw CSS-like style string to a node.
 func (f *Flowchart) AddRawStyle(nodeID, style string) *Flowchart {
+	if _, ok := f.pruned[nodeID]; ok {
+		return f
+	}
 	f.styles = append(f.styles, fmt.Sprintf("    style %s %s", nodeID, style))
 	return f
 }
@@ -68,31 +175,75 @@ func (f *Flowchart) Render() string {
 	for _, line := range f.lines {
 		sb.WriteString(line + "\n")
 	}
+	if line := f.rootOverflow.node(); line != "" {
+		sb.WriteString("    " + line + "\n")
+		sb.WriteString(fmt.Sprintf("    style %s %s\n", f.rootOverflow.id, overflowStyle))
+	}
 	for _, style := range f.styles {
 		sb.WriteString(style + "\n")
 	}
 	return sb.String()
 }
 
-// RenderBlock produces the Mermaid flowchart wrapped in a fenced code block.
+// RenderBlock produces the Mermaid flowchart wrapped in a fenced code block,
+// followed by the prune warning when nodes or edges were dropped.
 func (f *Flowchart) RenderBlock() string {
-	return WrapBlock(f.Render())
+	block := WrapBlock(f.Render())
+	if warning := f.PruneWarning(); warning != "" {
+		block += "\n" + warning
+	}
+	return block
+}
+
+// node returns the aggregate node line for the pruned nodes, or "" if none were pruned.
+func (ov *overflow) node() string {
+	if ov.count == 0 {
+		return ""
+	}
+	return nodeShape(ov.id, fmt.Sprintf("+%d more %s", ov.count, ov.noun), ShapeStadium)
 }
 
+// overflowStyle marks aggregate nodes so they read as placeholders.
+const overflowStyle = "stroke-dasharray:5 5"
+
 // Subgraph collects nodes and edges inside a subgraph.
 type Subgraph struct {
-	lines []string
+	f        *Flowchart
+	lines    []string
+	overflow overflow
+}
+
+func newSubgraph(f *Flowchart, id string) *Subgraph {
+	return &Subgraph{f: f, overflow: overflow{id: "pruned_" + id, noun: "items"}}
+}
+
+// render returns the subgraph lines, including the aggregate node for pruned nodes.
+func (sg *Subgraph) render() []string {
+	if line := sg.overflow.node(); line != "" {
+		return append(sg.lines, "    "+line, fmt.Sprintf("    style %s %s", sg.overflow.id, overflowStyle))
+	}
+	return sg.lines
+}
+
+// SetOverflowNoun names what pruned nodes in this subgraph are (e.g. "pods") in the aggregate label.
+func (sg *Subgraph) SetOverflowNoun(noun string) *Subgraph {
+	sg.overflow.noun = noun
+	return sg
 }
 
 // AddNode adds a node inside the subgraph.
 func (sg *Subgraph) AddNode(id, label string, shape Shape) *Subgraph {
-	sg.lines = append(sg.lines, "    "+nodeShape(id, EscapeLabel(label), shape))
+	if sg.f.admitNode(id, &sg.overflow) {
+		sg.lines = append(sg.lines, "    "+nodeShape(id, EscapeLabel(label), shape))
+	}
 	return sg
 }
 
 // AddEdge adds an edge inside the subgraph.
 func (sg *Subgraph) AddEdge(from, to, label string, style EdgeStyle) *Subgraph {
-	sg.lines = append(sg.lines, fmt.Sprintf("    %s %s %s", from, edgeArrow(style, EscapeLabel(label)), to))
+	if line := sg.f.edgeLine(from, to, label, style); line != "" {
+		sg.lines = append(sg.lines, line)
+	}
 	return sg
 }
 
@@ -104,10 +255,10 @@ func (sg *Subgraph) AddRaw(line string) *Subgraph {
 
 // AddNestedSubgraph adds a nested subgraph.
 func (sg *Subgraph) AddNestedSubgraph(id, label string, fn func(nested *Subgraph)) *Subgraph {
-	nested := &Subgraph{}
+	nested := newSubgraph(sg.f, id)
 	fn(nested)
 	sg.lines = append(sg.lines, fmt.Sprintf("    subgraph %s[\"%s\"]", id, EscapeLabel(label)))
-	for _, line := range nested.lines {
+	for _, line := range nested.render() {
 		sg.lines = append(sg.lines, "    "+line)
 	}
 	sg.lines = append(sg.lines, "    end")
@@ -0,0 +1,57 @@
+package mermaid
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFlowchartBudgetAggregatesPrunedNodes(t *testing.T) {
+	fc := NewFlowchart(DirectionTB).WithBudget(3, 0)
+	fc.AddNode("svc", "Service", ShapeRect)
+	fc.AddSubgraph("pods", "Pods", func(sg *Subgraph) {
+		sg.SetOverflowNoun("pods")
+		for i := 0; i < 5; i++ {
+			sg.AddNode(fmt.Sprintf("pod_%d", i), fmt.Sprintf("pod-%d", i), ShapeRound)
+		}
+	})
+	for i := 0; i < 5; i++ {
+		fc.AddEdge("svc", fmt.Sprintf("pod_%d", i), "", EdgeSolid)
+		fc.AddStyle(fmt.Sprintf("pod_%d", i), SeverityHealthy)
+	}
+
+	out := fc.Render()
+	if !strings.Contains(out, "pruned_pods([+3 more pods])") {
+		t.Errorf("expected aggregate node for 3 pruned pods, got:\n%s", out)
+	}
+	if strings.Contains(out, "pod_4") {
+		t.Errorf("expected pruned node pod_4 to be omitted, got:\n%s", out)
+	}
+	if got := strings.Count(out, "svc --> pruned_pods"); got != 1 {
+		t.Errorf("expected edges to pruned pods to collapse into one edge, got %d", got)
+	}
+	if fc.prunedNodes != 3 {
+		t.Errorf("pruned nodes = %d, want 3", fc.prunedNodes)
+	}
+	if !strings.Contains(fc.RenderBlock(), "Diagram pruned") {
+		t.Error("expected RenderBlock to include the prune warning")
+	}
+}
+
+func TestFlowchartEdgeBudget(t *testing.T) {
+	fc := NewFlowchart(DirectionLR).WithBudget(0, 2)
+	for i := 0; i < 4; i++ {
+		fc.AddEdge("a", fmt.Sprintf("n%d", i), "", EdgeSolid)
+	}
+	if fc.prunedEdges != 2 {
+		t.Errorf("pruned edges = %d, want 2", fc.prunedEdges)
+	}
+}
+
+func TestFlowchartWithinBudgetHasNoWarning(t *testing.T) {
+	fc := NewFlowchart(DirectionLR)
+	fc.AddNode("a", "A", ShapeRect).AddNode("b", "B", ShapeRect).AddEdge("a", "b", "", EdgeSolid)
+	if w := fc.PruneWarning(); w != "" {
+		t.Errorf("PruneWarning() = %q, want empty", w)
+	}
+}
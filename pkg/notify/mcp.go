@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MCPLogger sends findings as MCP log messages (notifications/message) to
+// every connected session. Clients only receive them after choosing a log
+// level with logging/setLevel, as the MCP spec requires.
+type MCPLogger struct {
+	Server *mcp.Server
+	Logger string
+}
+
+// NewMCPLogger returns a notifier that logs findings to the server's sessions.
+func NewMCPLogger(server *mcp.Server) *MCPLogger {
+	return &MCPLogger{Server: server, Logger: "kube-doctor/watchdog"}
+}
+
+// Notify sends one log message per finding to each session.
+func (l *MCPLogger) Notify(ctx context.Context, cluster string, findings []Finding) error {
+	var errs []error
+	for ss := range l.Server.Sessions() {
+		for _, f := range findings {
+			err := ss.Log(ctx, &mcp.LoggingMessageParams{
+				Level:  logLevel(f.Severity),
+				Logger: l.Logger,
+				Data: map[string]string{
+					"cluster":     cluster,
+					"severity":    f.Severity,
+					"fingerprint": f.Fingerprint,
+					"message":     f.Message,
+				},
+			})
+			if err != nil {
+				errs = append(errs, err)
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// logLevel maps a finding severity to an MCP log level.
+func logLevel(severity string) mcp.LoggingLevel {
+	switch severity {
+	case "CRITICAL":
+		return "critical"
+	case "WARNING":
+		return "warning"
+	}
+	return "info"
+}
+
+// Multi delivers findings to several notifiers, e.g. connected MCP clients
+// and a webhook. Every notifier is tried even if an earlier one fails.
+type Multi []Notifier
+
+// Notify calls each notifier in turn.
+func (m Multi) Notify(ctx context.Context, cluster string, findings []Finding) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, cluster, findings); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestMCPLoggerNotify(t *testing.T) {
+	ctx := context.Background()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	received := make(chan *mcp.LoggingMessageParams, 4)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) {
+			received <- req.Params
+		},
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	logger := NewMCPLogger(server)
+	findings := []Finding{{Severity: "CRITICAL", Fingerprint: "node-notready/n1", Message: "Node 'n1' is NotReady"}}
+
+	// Nothing is sent before the client sets a log level.
+	if err := logger.Notify(ctx, "prod", findings); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "warning"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Notify(ctx, "prod", findings); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-received:
+		data, _ := msg.Data.(map[string]any)
+		if msg.Level != "critical" || msg.Logger != "kube-doctor/watchdog" || data["fingerprint"] != "node-notready/n1" || data["cluster"] != "prod" {
+			t.Errorf("unexpected log message %+v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no log message received")
+	}
+	select {
+	case msg := <-received:
+		t.Errorf("unexpected second message %+v (sent before the level was set?)", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
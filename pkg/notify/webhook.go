@@ -0,0 +1,168 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Webhook payload formats.
+const (
+	FormatGeneric = "generic"
+	FormatSlack   = "slack"
+	FormatTeams   = "teams"
+)
+
+// Finding is a single diagnostic result reported by a background sweep.
+type Finding struct {
+	Severity string `json:"severity"`
+	// Fingerprint identifies the underlying problem across sweeps, so the
+	// same issue is only reported once while it persists.
+	Fingerprint string `json:"fingerprint"`
+	Message     string `json:"message"`
+}
+
+// Notifier delivers findings to an external destination.
+type Notifier interface {
+	Notify(ctx context.Context, cluster string, findings []Finding) error
+}
+
+// Webhook posts findings as JSON to a Slack, Microsoft Teams, or generic HTTP endpoint.
+type Webhook struct {
+	URL        string
+	Format     string
+	HTTPClient *http.Client
+}
+
+// NewWebhook returns a webhook notifier. An empty format is detected from the URL.
+func NewWebhook(url, format string) (*Webhook, error) {
+	if format == "" {
+		format = DetectFormat(url)
+	}
+	switch format {
+	case FormatGeneric, FormatSlack, FormatTeams:
+	default:
+		return nil, fmt.Errorf("unknown webhook format %q (use slack, teams, or generic)", format)
+	}
+	return &Webhook{
+		URL:        url,
+		Format:     format,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// DetectFormat guesses the payload format from a webhook URL.
+func DetectFormat(url string) string {
+	switch {
+	case strings.Contains(url, "hooks.slack.com"):
+		return FormatSlack
+	case strings.Contains(url, "webhook.office.com"), strings.Contains(url, "logic.azure.com"),
+		strings.Contains(url, "powerautomate.com"), strings.Contains(url, "powerplatform.com"):
+		return FormatTeams
+	}
+	return FormatGeneric
+}
+
+// Notify posts the findings in a single request.
+func (w *Webhook) Notify(ctx context.Context, cluster string, findings []Finding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(w.payload(cluster, findings))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// payload builds the request body for the webhook's format.
+func (w *Webhook) payload(cluster string, findings []Finding) any {
+	switch w.Format {
+	case FormatSlack:
+		return map[string]any{"text": formatText(cluster, findings)}
+	case FormatTeams:
+		return map[string]any{
+			"type": "message",
+			"attachments": []any{map[string]any{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]any{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body": []any{map[string]any{
+						"type": "TextBlock",
+						"text": formatText(cluster, findings),
+						"wrap": true,
+					}},
+				},
+			}},
+		}
+	}
+	return map[string]any{
+		"source":   "kube-doctor",
+		"cluster":  cluster,
+		"findings": findings,
+	}
+}
+
+// formatText renders findings as a plain-text message.
+func formatText(cluster string, findings []Finding) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("kube-doctor: %d new finding(s) in cluster %s\n", len(findings), cluster))
+	for _, f := range findings {
+		sb.WriteString(fmt.Sprintf("[%s] %s\n", f.Severity, f.Message))
+	}
+	return sb.String()
+}
+
+// Deduper remembers reported fingerprints so each problem is notified once.
+// A fingerprint that disappears from a sweep is forgotten, so the problem is
+// reported again if it recurs.
+type Deduper struct {
+	mu       sync.Mutex
+	reported map[string]bool
+}
+
+// NewDeduper returns an empty Deduper.
+func NewDeduper() *Deduper {
+	return &Deduper{reported: make(map[string]bool)}
+}
+
+// Filter returns the findings not reported by a previous sweep and records
+// the current set as reported.
+func (d *Deduper) Filter(findings []Finding) []Finding {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	current := make(map[string]bool, len(findings))
+	var fresh []Finding
+	for _, f := range findings {
+		if current[f.Fingerprint] {
+			continue
+		}
+		current[f.Fingerprint] = true
+		if !d.reported[f.Fingerprint] {
+			fresh = append(fresh, f)
+		}
+	}
+	d.reported = current
+	return fresh
+}
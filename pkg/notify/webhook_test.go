@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]string{
+		"https://hooks.slack.com/services/T000/B000/XXX":                      FormatSlack,
+		"https://contoso.webhook.office.com/webhookb2/abc":                    FormatTeams,
+		"https://prod-01.westus.logic.azure.com:443/workflows/abc/triggers/x": FormatTeams,
+		"https://alerts.example.com/hook":                                     FormatGeneric,
+	}
+	for url, want := range tests {
+		if got := DetectFormat(url); got != want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestNewWebhookRejectsUnknownFormat(t *testing.T) {
+	if _, err := NewWebhook("https://example.com", "pagerduty"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestWebhookNotify(t *testing.T) {
+	var got []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		got, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	findings := []Finding{{Severity: "CRITICAL", Fingerprint: "node-notready/n1", Message: "Node 'n1' is NotReady"}}
+
+	w, err := NewWebhook(srv.URL, FormatSlack)
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	if err := w.Notify(context.Background(), "prod", findings); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	var slack struct{ Text string }
+	if err := json.Unmarshal(got, &slack); err != nil {
+		t.Fatalf("invalid slack payload %s: %v", got, err)
+	}
+	if !strings.Contains(slack.Text, "[CRITICAL] Node 'n1' is NotReady") || !strings.Contains(slack.Text, "prod") {
+		t.Errorf("unexpected slack text %q", slack.Text)
+	}
+
+	w.Format = FormatGeneric
+	if err := w.Notify(context.Background(), "prod", findings); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	var generic struct {
+		Cluster  string
+		Findings []Finding
+	}
+	if err := json.Unmarshal(got, &generic); err != nil {
+		t.Fatalf("invalid generic payload %s: %v", got, err)
+	}
+	if generic.Cluster != "prod" || len(generic.Findings) != 1 || generic.Findings[0].Fingerprint != "node-notready/n1" {
+		t.Errorf("unexpected generic payload %+v", generic)
+	}
+}
+
+func TestWebhookNotifyErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	w, _ := NewWebhook(srv.URL, FormatGeneric)
+	if err := w.Notify(context.Background(), "prod", []Finding{{Severity: "CRITICAL", Fingerprint: "x"}}); err == nil {
+		t.Error("expected error for non-2xx response")
+	}
+}
+
+func TestDeduperFilter(t *testing.T) {
+	d := NewDeduper()
+	a := Finding{Fingerprint: "a"}
+	b := Finding{Fingerprint: "b"}
+
+	if got := d.Filter([]Finding{a, b, a}); len(got) != 2 {
+		t.Fatalf("first sweep: got %d new findings, want 2", len(got))
+	}
+	if got := d.Filter([]Finding{a, b}); len(got) != 0 {
+		t.Errorf("repeat sweep: got %d new findings, want 0", len(got))
+	}
+	// b resolves, then recurs: it is reported again.
+	d.Filter([]Finding{a})
+	if got := d.Filter([]Finding{a, b}); len(got) != 1 || got[0].Fingerprint != "b" {
+		t.Errorf("recurrence: got %+v, want [b]", got)
+	}
+}
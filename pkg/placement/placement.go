@@ -0,0 +1,164 @@
+package placement
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Policy maps workloads, selected by priority class or namespace, to the node
+// pools they must or must not run on.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule selects pods and constrains the node pools they may be scheduled on.
+// A pod is selected when it matches every selector that is set. Pools can be
+// constrained by name or by mode (e.g. AKS "system" and "user" pools).
+type Rule struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity,omitempty"`
+
+	PriorityClasses   []string `json:"priorityClasses,omitempty"`
+	MinPriority       *int32   `json:"minPriority,omitempty"`
+	Namespaces        []string `json:"namespaces,omitempty"`
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	AllowedPools   []string `json:"allowedPools,omitempty"`
+	AllowedModes   []string `json:"allowedModes,omitempty"`
+	ForbiddenPools []string `json:"forbiddenPools,omitempty"`
+	ForbiddenModes []string `json:"forbiddenModes,omitempty"`
+}
+
+// Default returns the built-in policy: system-critical priority classes must
+// run on system-mode pools, and application workloads should stay off them.
+func Default() *Policy {
+	return &Policy{Rules: []Rule{
+		{
+			Name:            "system-critical-on-system-pool",
+			Severity:        "CRITICAL",
+			PriorityClasses: []string{"system-node-critical", "system-cluster-critical"},
+			AllowedModes:    []string{"system"},
+		},
+		{
+			Name:              "user-workloads-off-system-pool",
+			Severity:          "WARNING",
+			ExcludeNamespaces: []string{"kube-system", "kube-node-lease", "kube-public", "gatekeeper-system", "calico-system", "tigera-operator", "flux-system"},
+			ForbiddenModes:    []string{"system"},
+		},
+	}}
+}
+
+// LoadFile reads a placement policy from a JSON file of the form
+// {"rules": [{"name": "...", "priorityClasses": ["..."], "allowedPools": ["..."]}]}.
+func LoadFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing placement policy %s: %w", path, err)
+	}
+	if len(p.Rules) == 0 {
+		return nil, fmt.Errorf("placement policy %s has no rules", path)
+	}
+	for i := range p.Rules {
+		if err := p.Rules[i].validate(); err != nil {
+			return nil, fmt.Errorf("placement policy %s: rule %d: %w", path, i+1, err)
+		}
+	}
+	return &p, nil
+}
+
+func (r *Rule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	if len(r.AllowedPools)+len(r.AllowedModes)+len(r.ForbiddenPools)+len(r.ForbiddenModes) == 0 {
+		return fmt.Errorf("%s: no allowed or forbidden pools or modes", r.Name)
+	}
+	switch strings.ToUpper(r.Severity) {
+	case "":
+		r.Severity = "WARNING"
+	case "CRITICAL", "WARNING", "INFO":
+		r.Severity = strings.ToUpper(r.Severity)
+	default:
+		return fmt.Errorf("%s: severity must be CRITICAL, WARNING, or INFO", r.Name)
+	}
+	return nil
+}
+
+// Selects reports whether the rule applies to a pod with the given namespace,
+// priority class name, and resolved priority (nil when unset).
+func (r Rule) Selects(namespace, priorityClass string, priority *int32) bool {
+	if len(r.PriorityClasses) > 0 && !slices.Contains(r.PriorityClasses, priorityClass) {
+		return false
+	}
+	if r.MinPriority != nil && (priority == nil || *priority < *r.MinPriority) {
+		return false
+	}
+	if len(r.Namespaces) > 0 && !slices.Contains(r.Namespaces, namespace) {
+		return false
+	}
+	return !slices.Contains(r.ExcludeNamespaces, namespace)
+}
+
+// Permits reports whether the rule allows a pod on the given pool and mode.
+// An empty mode means the pool's mode is unknown; mode constraints then
+// cannot be evaluated and evaluable is false.
+func (r Rule) Permits(pool, mode string) (ok, evaluable bool) {
+	if len(r.AllowedModes)+len(r.ForbiddenModes) > 0 && mode == "" {
+		return true, false
+	}
+	if containsFold(r.ForbiddenPools, pool) || containsFold(r.ForbiddenModes, mode) {
+		return false, true
+	}
+	if len(r.AllowedPools)+len(r.AllowedModes) > 0 && !containsFold(r.AllowedPools, pool) && !containsFold(r.AllowedModes, mode) {
+		return false, true
+	}
+	return true, true
+}
+
+// Describe summarizes the rule's pool constraint for reports.
+func (r Rule) Describe() string {
+	var parts []string
+	if len(r.AllowedPools) > 0 {
+		parts = append(parts, "pools "+strings.Join(r.AllowedPools, "|"))
+	}
+	if len(r.AllowedModes) > 0 {
+		parts = append(parts, "mode "+strings.Join(r.AllowedModes, "|"))
+	}
+	var s string
+	if len(parts) > 0 {
+		s = "must run on " + strings.Join(parts, " or ")
+	}
+	parts = nil
+	if len(r.ForbiddenPools) > 0 {
+		parts = append(parts, "pools "+strings.Join(r.ForbiddenPools, "|"))
+	}
+	if len(r.ForbiddenModes) > 0 {
+		parts = append(parts, "mode "+strings.Join(r.ForbiddenModes, "|"))
+	}
+	if len(parts) > 0 {
+		if s != "" {
+			s += "; "
+		}
+		s += "must not run on " + strings.Join(parts, " or ")
+	}
+	return s
+}
+
+func containsFold(list []string, s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package placement
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(`{"rules": [{"name": "payments-on-secure", "namespaces": ["payments"], "allowedPools": ["secure"]}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if len(p.Rules) != 1 || p.Rules[0].Severity != "WARNING" {
+		t.Errorf("expected one rule with default WARNING severity, got %+v", p.Rules)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"rules": [{"name": "no-constraint", "namespaces": ["payments"]}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "no allowed or forbidden") {
+		t.Errorf("expected a rule without constraints to be rejected, got %v", err)
+	}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	rules := Default().Rules
+	critical, user := rules[0], rules[1]
+
+	if !critical.Selects("kube-system", "system-node-critical", nil) {
+		t.Error("expected system-node-critical pods to be selected")
+	}
+	if ok, _ := critical.Permits("user1", "user"); ok {
+		t.Error("expected system-critical pod on a user pool to violate the policy")
+	}
+	if ok, _ := critical.Permits("system1", "system"); !ok {
+		t.Error("expected system-critical pod on a system pool to be permitted")
+	}
+	if _, evaluable := critical.Permits("pool-a", ""); evaluable {
+		t.Error("expected mode rules to be unevaluable when the pool mode is unknown")
+	}
+
+	if user.Selects("kube-system", "", nil) {
+		t.Error("expected kube-system to be excluded from the user workload rule")
+	}
+	if !user.Selects("shop", "", nil) {
+		t.Error("expected application namespaces to be selected")
+	}
+	if ok, _ := user.Permits("system1", "System"); ok {
+		t.Error("expected application pod on a system pool to violate the policy")
+	}
+}
+
+func TestRuleMinPriority(t *testing.T) {
+	min := int32(1000)
+	r := Rule{Name: "high", MinPriority: &min, AllowedPools: []string{"fast"}}
+	low, high := int32(10), int32(5000)
+	if r.Selects("shop", "", &low) || r.Selects("shop", "", nil) {
+		t.Error("expected pods below the minimum priority to be ignored")
+	}
+	if !r.Selects("shop", "", &high) {
+		t.Error("expected high priority pod to be selected")
+	}
+	if got := r.Describe(); got != "must run on pools fast" {
+		t.Errorf("Describe() = %q", got)
+	}
+}
@@ -0,0 +1,144 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HoursPerMonth is the average number of hours in a month used for monthly estimates.
+const HoursPerMonth = 730
+
+// AzureRetailPricesURL is the public Azure Retail Prices API endpoint.
+const AzureRetailPricesURL = "https://prices.azure.com/api/retail/prices"
+
+// Table maps node instance types to hourly on-demand prices.
+type Table struct {
+	Currency string             `json:"currency"`
+	Prices   map[string]float64 `json:"prices"`
+}
+
+// LoadFile reads a price table from a JSON file of the form
+// {"currency": "USD", "prices": {"Standard_D4s_v3": 0.192}}.
+func LoadFile(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parsing price file %s: %w", path, err)
+	}
+	if len(t.Prices) == 0 {
+		return nil, fmt.Errorf("price file %s has no prices", path)
+	}
+	if t.Currency == "" {
+		t.Currency = "USD"
+	}
+	return &t, nil
+}
+
+// Lookup returns the hourly price for an instance type, matching case-insensitively.
+func (t *Table) Lookup(instanceType string) (float64, bool) {
+	if t == nil {
+		return 0, false
+	}
+	if p, ok := t.Prices[instanceType]; ok {
+		return p, true
+	}
+	for k, p := range t.Prices {
+		if strings.EqualFold(k, instanceType) {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// AzureRetailClient looks up Linux pay-as-you-go VM prices from the Azure
+// Retail Prices API and caches the results for the life of the process.
+type AzureRetailClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]float64
+}
+
+// NewAzureRetailClient returns a client for the public Azure Retail Prices API.
+func NewAzureRetailClient() *AzureRetailClient {
+	return &AzureRetailClient{
+		BaseURL:    AzureRetailPricesURL,
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type azureRetailResponse struct {
+	Items []struct {
+		RetailPrice   float64 `json:"retailPrice"`
+		CurrencyCode  string  `json:"currencyCode"`
+		UnitOfMeasure string  `json:"unitOfMeasure"`
+		ProductName   string  `json:"productName"`
+		SkuName       string  `json:"skuName"`
+	} `json:"Items"`
+}
+
+// HourlyPrice returns the USD hourly price of a Linux VM SKU in a region.
+func (c *AzureRetailClient) HourlyPrice(ctx context.Context, region, sku string) (float64, error) {
+	key := strings.ToLower(region + "/" + sku)
+	c.mu.Lock()
+	if p, ok := c.cache[key]; ok {
+		c.mu.Unlock()
+		return p, nil
+	}
+	c.mu.Unlock()
+
+	filter := fmt.Sprintf("serviceName eq 'Virtual Machines' and priceType eq 'Consumption' and armRegionName eq '%s' and armSkuName eq '%s'",
+		strings.ToLower(region), sku)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"?$filter="+url.QueryEscape(filter), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("querying Azure retail prices: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("Azure retail prices returned %s", resp.Status)
+	}
+	var body azureRetailResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("decoding Azure retail prices: %w", err)
+	}
+
+	// Pick the cheapest Linux on-demand meter; Windows, Spot and Low Priority
+	// meters are listed under the same SKU.
+	price := 0.0
+	for _, item := range body.Items {
+		if item.UnitOfMeasure != "1 Hour" || item.RetailPrice <= 0 ||
+			strings.Contains(item.ProductName, "Windows") ||
+			strings.Contains(item.SkuName, "Spot") || strings.Contains(item.SkuName, "Low Priority") {
+			continue
+		}
+		if price == 0 || item.RetailPrice < price {
+			price = item.RetailPrice
+		}
+	}
+	if price == 0 {
+		return 0, fmt.Errorf("no Linux on-demand price for %s in %s", sku, region)
+	}
+
+	c.mu.Lock()
+	if c.cache == nil {
+		c.cache = make(map[string]float64)
+	}
+	c.cache[key] = price
+	c.mu.Unlock()
+	return price, nil
+}
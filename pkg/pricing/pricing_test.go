@@ -0,0 +1,73 @@
+package pricing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	if err := os.WriteFile(path, []byte(`{"prices": {"Standard_D4s_v3": 0.192, "m5.xlarge": 0.192}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if table.Currency != "USD" {
+		t.Errorf("expected default currency USD, got %q", table.Currency)
+	}
+	if p, ok := table.Lookup("standard_d4s_v3"); !ok || p != 0.192 {
+		t.Errorf("Lookup() = %v, %v; want 0.192, true", p, ok)
+	}
+	if _, ok := table.Lookup("n2-standard-4"); ok {
+		t.Error("expected unknown instance type to be missing")
+	}
+}
+
+func TestLoadFileEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	if err := os.WriteFile(path, []byte(`{"prices": {}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for empty price file")
+	}
+}
+
+func TestAzureRetailClientHourlyPrice(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if !strings.Contains(r.URL.Query().Get("$filter"), "armSkuName eq 'Standard_D4s_v3'") {
+			t.Errorf("unexpected filter %q", r.URL.Query().Get("$filter"))
+		}
+		w.Write([]byte(`{"Items": [
+			{"retailPrice": 0.384, "unitOfMeasure": "1 Hour", "productName": "Virtual Machines DSv3 Series Windows", "skuName": "D4s v3"},
+			{"retailPrice": 0.038, "unitOfMeasure": "1 Hour", "productName": "Virtual Machines DSv3 Series", "skuName": "D4s v3 Spot"},
+			{"retailPrice": 0.192, "unitOfMeasure": "1 Hour", "productName": "Virtual Machines DSv3 Series", "skuName": "D4s v3"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	c := NewAzureRetailClient()
+	c.BaseURL = srv.URL
+	for i := 0; i < 2; i++ {
+		p, err := c.HourlyPrice(context.Background(), "eastus", "Standard_D4s_v3")
+		if err != nil {
+			t.Fatalf("HourlyPrice() error = %v", err)
+		}
+		if p != 0.192 {
+			t.Errorf("HourlyPrice() = %v, want 0.192", p)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected cached second lookup, got %d API calls", calls)
+	}
+}
@@ -0,0 +1,393 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DockerHub is the registry host that image references without one resolve
+// to, and dockerHubAPI is where its registry API is served.
+const (
+	DockerHub    = "docker.io"
+	dockerHubAPI = "registry-1.docker.io"
+)
+
+// maxTagPages caps the pages of tags read for one repository.
+const maxTagPages = 10
+
+var (
+	// ErrNotFound is returned when the repository, tag, or digest does not exist.
+	ErrNotFound = errors.New("not found in registry")
+	// ErrUnauthorized is returned when the registry refuses anonymous access.
+	ErrUnauthorized = errors.New("registry requires credentials")
+)
+
+// manifestMediaTypes are the manifest formats requested from registries.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Reference is a parsed container image reference.
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses an image reference such as nginx:1.25,
+// ghcr.io/org/app@sha256:..., or registry:5000/team/app:v1. References
+// without a registry resolve to Docker Hub, and single-name Docker Hub
+// repositories to library/. A reference without tag or digest means latest.
+func ParseReference(image string) (Reference, error) {
+	if image == "" || strings.ContainsAny(image, " \t") {
+		return Reference{}, fmt.Errorf("invalid image reference %q", image)
+	}
+	var ref Reference
+	name := image
+	if before, digest, ok := strings.Cut(name, "@"); ok {
+		name, ref.Digest = before, digest
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, ref.Repository = first, rest
+	} else {
+		ref.Registry, ref.Repository = DockerHub, name
+	}
+	if ref.Registry == DockerHub && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Repository == "" {
+		return Reference{}, fmt.Errorf("invalid image reference %q", image)
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+// String returns the reference as registry/repository[:tag][@digest].
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// Client reads tags, digests, and image build dates from OCI distribution
+// registries, authenticating anonymously with the registry's token service
+// when it asks for one. Private repositories return ErrUnauthorized.
+type Client struct {
+	HTTPClient *http.Client
+	// Scheme is https except in tests.
+	Scheme string
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// NewClient returns a registry client with a 30s per-request timeout.
+func NewClient() *Client {
+	return &Client{HTTPClient: &http.Client{Timeout: 30 * time.Second}, Scheme: "https"}
+}
+
+// apiHost returns the host serving the registry API of a reference.
+func apiHost(registry string) string {
+	if registry == DockerHub {
+		return dockerHubAPI
+	}
+	return registry
+}
+
+// Tags lists the tags of the reference's repository.
+func (c *Client) Tags(ctx context.Context, ref Reference) ([]string, error) {
+	next := fmt.Sprintf("%s://%s/v2/%s/tags/list?n=1000", c.Scheme, apiHost(ref.Registry), ref.Repository)
+	var tags []string
+	for page := 0; next != "" && page < maxTagPages; page++ {
+		resp, err := c.get(ctx, ref, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		var body struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding tags of %s: %w", ref.Repository, err)
+		}
+		tags = append(tags, body.Tags...)
+		next = nextPage(resp, next)
+	}
+	return tags, nil
+}
+
+// nextPage resolves the rel="next" Link header of a paginated response.
+func nextPage(resp *http.Response, current string) string {
+	link := resp.Header.Get("Link")
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end < start || !strings.Contains(link, `rel="next"`) {
+		return ""
+	}
+	base, err := url.Parse(current)
+	if err != nil {
+		return ""
+	}
+	u, err := base.Parse(link[start+1 : end])
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
+
+// Digest returns the manifest digest a tag currently points to.
+func (c *Client) Digest(ctx context.Context, ref Reference, tag string) (string, error) {
+	resp, err := c.get(ctx, ref, http.MethodHead, c.manifestURL(ref, tag), manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if d := resp.Header.Get("Docker-Content-Digest"); d != "" {
+		return d, nil
+	}
+	// Some registries omit the header on HEAD; fall back to GET.
+	resp, err = c.get(ctx, ref, http.MethodGet, c.manifestURL(ref, tag), manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return resp.Header.Get("Docker-Content-Digest"), nil
+}
+
+// Created returns the build date recorded in the image config of a tag or
+// digest. For multi-platform images it reads the linux/amd64 image, else
+// the first one listed.
+func (c *Client) Created(ctx context.Context, ref Reference, tagOrDigest string) (time.Time, error) {
+	var manifest struct {
+		MediaType string `json:"mediaType"`
+		Config    struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+		Manifests []struct {
+			Digest   string `json:"digest"`
+			Platform struct {
+				OS           string `json:"os"`
+				Architecture string `json:"architecture"`
+			} `json:"platform"`
+		} `json:"manifests"`
+	}
+	if err := c.getJSON(ctx, ref, c.manifestURL(ref, tagOrDigest), manifestMediaTypes, &manifest); err != nil {
+		return time.Time{}, err
+	}
+	if len(manifest.Manifests) > 0 {
+		chosen := manifest.Manifests[0].Digest
+		for _, m := range manifest.Manifests {
+			if m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+				chosen = m.Digest
+				break
+			}
+		}
+		return c.Created(ctx, ref, chosen)
+	}
+	if manifest.Config.Digest == "" {
+		return time.Time{}, fmt.Errorf("manifest of %s has no image config", ref.Repository)
+	}
+	var config struct {
+		Created time.Time `json:"created"`
+	}
+	blob := fmt.Sprintf("%s://%s/v2/%s/blobs/%s", c.Scheme, apiHost(ref.Registry), ref.Repository, manifest.Config.Digest)
+	if err := c.getJSON(ctx, ref, blob, nil, &config); err != nil {
+		return time.Time{}, err
+	}
+	return config.Created, nil
+}
+
+func (c *Client) manifestURL(ref Reference, tagOrDigest string) string {
+	return fmt.Sprintf("%s://%s/v2/%s/manifests/%s", c.Scheme, apiHost(ref.Registry), ref.Repository, tagOrDigest)
+}
+
+func (c *Client) getJSON(ctx context.Context, ref Reference, u string, accept []string, out any) error {
+	resp, err := c.get(ctx, ref, http.MethodGet, u, accept)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s: %w", u, err)
+	}
+	return nil
+}
+
+// get sends a request, fetching an anonymous bearer token and retrying once
+// when the registry challenges for one. The caller closes the body of a
+// successful response.
+func (c *Client) get(ctx context.Context, ref Reference, method, u string, accept []string) (*http.Response, error) {
+	key := ref.Registry + "/" + ref.Repository
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range accept {
+			req.Header.Add("Accept", a)
+		}
+		c.mu.Lock()
+		token := c.tokens[key]
+		c.mu.Unlock()
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return resp, nil
+		case resp.StatusCode == http.StatusUnauthorized && attempt == 0:
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			token, err := c.fetchToken(ctx, challenge, ref)
+			if err != nil {
+				return nil, err
+			}
+			c.mu.Lock()
+			if c.tokens == nil {
+				c.tokens = make(map[string]string)
+			}
+			c.tokens[key] = token
+			c.mu.Unlock()
+			continue
+		}
+		resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return nil, ErrNotFound
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return nil, ErrUnauthorized
+		}
+		return nil, fmt.Errorf("%s %s: HTTP %d", method, u, resp.StatusCode)
+	}
+}
+
+// challengeParam matches key="value" pairs of a WWW-Authenticate header.
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// fetchToken requests an anonymous pull token from the realm named in a
+// Bearer challenge.
+func (c *Client) fetchToken(ctx context.Context, challenge string, ref Reference) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", ErrUnauthorized
+	}
+	params := make(map[string]string)
+	for _, m := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(m[1])] = m[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("registry sent an invalid token realm %q", params["realm"])
+	}
+	q := realm.Query()
+	if s := params["service"]; s != "" {
+		q.Set("service", s)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.Repository + ":pull"
+	}
+	q.Set("scope", scope)
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", ErrUnauthorized
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding registry token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", ErrUnauthorized
+}
+
+// versionTag matches version-like tags: an optional v, two to four numeric
+// components, and an optional suffix such as -alpine.
+var versionTag = regexp.MustCompile(`^(v?)(\d+(?:\.\d+){1,3})(-[A-Za-z][\w.-]*)?$`)
+
+// LatestTag returns the highest tag in tags that is newer than current and
+// has the same shape: the same v prefix, number of components, and suffix,
+// so 1.25.3-alpine is only compared with other x.y.z-alpine tags. It returns
+// "" when current is not a version tag or nothing newer exists. Pre-release
+// tags such as 2.0.0-rc1 only match currents with the same suffix.
+func LatestTag(current string, tags []string) string {
+	cm := versionTag.FindStringSubmatch(current)
+	if cm == nil {
+		return ""
+	}
+	best, bestParts := "", splitVersion(cm[2])
+	for _, t := range tags {
+		m := versionTag.FindStringSubmatch(t)
+		if m == nil || m[1] != cm[1] || m[3] != cm[3] {
+			continue
+		}
+		parts := splitVersion(m[2])
+		if len(parts) != len(bestParts) || !versionLess(bestParts, parts) {
+			continue
+		}
+		best, bestParts = t, parts
+	}
+	return best
+}
+
+func splitVersion(s string) []int {
+	fields := strings.Split(s, ".")
+	out := make([]int, len(fields))
+	for i, f := range fields {
+		out[i], _ = strconv.Atoi(f)
+	}
+	return out
+}
+
+func versionLess(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  Reference
+	}{
+		{"nginx", Reference{Registry: DockerHub, Repository: "library/nginx", Tag: "latest"}},
+		{"bitnami/redis:7.2", Reference{Registry: DockerHub, Repository: "bitnami/redis", Tag: "7.2"}},
+		{"ghcr.io/org/app@sha256:abc", Reference{Registry: "ghcr.io", Repository: "org/app", Digest: "sha256:abc"}},
+		{"localhost:5000/team/app:v1@sha256:def", Reference{Registry: "localhost:5000", Repository: "team/app", Tag: "v1", Digest: "sha256:def"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseReference(tt.image)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseReference(%q) = %+v, %v; want %+v", tt.image, got, err, tt.want)
+		}
+	}
+	if _, err := ParseReference(""); err == nil {
+		t.Error("expected an error for an empty reference")
+	}
+}
+
+func TestLatestTag(t *testing.T) {
+	tags := []string{"1.24.0", "1.25.3", "1.26.1", "1.26.1-alpine", "1.27.0-alpine", "latest", "v1.30.0", "1.26", "2.0.0-rc1"}
+	tests := map[string]string{
+		"1.25.3":        "1.26.1",
+		"1.25.3-alpine": "1.27.0-alpine",
+		"1.26.1":        "",
+		"v1.29.0":       "v1.30.0",
+		"1.25":          "1.26",
+		"latest":        "",
+	}
+	for current, want := range tests {
+		if got := LatestTag(current, tags); got != want {
+			t.Errorf("LatestTag(%q) = %q, want %q", current, got, want)
+		}
+	}
+}
+
+func TestClientAnonymousToken(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:team/app:pull" {
+				t.Errorf("unexpected token scope %q", r.URL.Query().Get("scope"))
+			}
+			fmt.Fprint(w, `{"token":"t0k"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer t0k" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:team/app:pull"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/team/app/tags/list":
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/team/app/tags/list?n=1000&last=v1>; rel="next"`)
+				fmt.Fprint(w, `{"tags":["v1"]}`)
+				return
+			}
+			fmt.Fprint(w, `{"tags":["v2"]}`)
+		case "/v2/team/app/manifests/v2":
+			w.Header().Set("Docker-Content-Digest", "sha256:index")
+			if r.Method == http.MethodGet {
+				fmt.Fprint(w, `{"manifests":[{"digest":"sha256:arm","platform":{"os":"linux","architecture":"arm64"}},{"digest":"sha256:amd","platform":{"os":"linux","architecture":"amd64"}}]}`)
+			}
+		case "/v2/team/app/manifests/sha256:amd":
+			fmt.Fprint(w, `{"config":{"digest":"sha256:cfg"}}`)
+		case "/v2/team/app/blobs/sha256:cfg":
+			fmt.Fprint(w, `{"created":"2024-03-01T10:00:00Z"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	c.Scheme = "http"
+	ref, err := ParseReference(strings.TrimPrefix(srv.URL, "http://") + "/team/app:v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	tags, err := c.Tags(ctx, ref)
+	if err != nil || strings.Join(tags, ",") != "v1,v2" {
+		t.Errorf("Tags = %v, %v; want both pages", tags, err)
+	}
+	if d, err := c.Digest(ctx, ref, "v2"); err != nil || d != "sha256:index" {
+		t.Errorf("Digest = %q, %v", d, err)
+	}
+	created, err := c.Created(ctx, ref, "v2")
+	if err != nil || !created.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Created = %v, %v; want the amd64 image's date", created, err)
+	}
+	if _, err := c.Digest(ctx, ref, "gone"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing tag, got %v", err)
+	}
+}
@@ -0,0 +1,328 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type checkAddonsInput struct {
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// addonType describes how to recognize one cluster add-on by the workloads
+// that run it.
+type addonType struct {
+	Key         string
+	DisplayName string
+	Category    string
+	// Selectors match the pod template labels of the add-on's workloads;
+	// Names match workload names exactly.
+	Selectors []string
+	Names     []string
+	// Image picks the container whose tag is the add-on version; the first
+	// container is used when none matches.
+	Image string
+	// Expected add-ons are reported when missing.
+	Expected bool
+	// Impact is what breaks when the add-on is down.
+	Impact string
+}
+
+// knownAddons lists the add-ons check_addons recognizes, besides the
+// ingress controllers in knownIngressControllers.
+var knownAddons = []addonType{
+	{Key: "coredns", DisplayName: "CoreDNS", Category: "dns", Selectors: []string{"k8s-app=kube-dns"}, Names: []string{"coredns", "kube-dns"},
+		Image: "coredns", Expected: true, Impact: "in-cluster DNS lookups fail"},
+	{Key: "node-local-dns", DisplayName: "NodeLocal DNSCache", Category: "dns", Selectors: []string{"k8s-app=node-local-dns"},
+		Image: "dns", Impact: "DNS lookups from pods on affected nodes fail"},
+	{Key: "metrics-server", DisplayName: "metrics-server", Category: "metrics", Selectors: []string{"k8s-app=metrics-server", "app.kubernetes.io/name=metrics-server"},
+		Names: []string{"metrics-server"}, Image: "metrics-server", Expected: true, Impact: "HPAs cannot scale and kubectl top fails"},
+	{Key: "kube-state-metrics", DisplayName: "kube-state-metrics", Category: "metrics", Selectors: []string{"app.kubernetes.io/name=kube-state-metrics"},
+		Image: "kube-state-metrics", Impact: "object-state metrics and alerts go stale"},
+	{Key: "cluster-autoscaler", DisplayName: "Cluster Autoscaler", Category: "autoscaling", Selectors: []string{"app.kubernetes.io/name=cluster-autoscaler", "app=cluster-autoscaler"},
+		Image: "cluster-autoscaler", Impact: "pending pods no longer trigger node scale-up"},
+	{Key: "keda", DisplayName: "KEDA", Category: "autoscaling", Selectors: []string{"app.kubernetes.io/name=keda-operator", "app=keda-operator"},
+		Image: "keda", Impact: "ScaledObjects stop scaling"},
+	{Key: "azuredisk-csi", DisplayName: "Azure Disk CSI", Category: "storage", Selectors: []string{"app=csi-azuredisk-controller", "app=csi-azuredisk-node"},
+		Image: "azuredisk-csi", Impact: "Azure Disk volumes cannot be provisioned, attached, or mounted"},
+	{Key: "azurefile-csi", DisplayName: "Azure File CSI", Category: "storage", Selectors: []string{"app=csi-azurefile-controller", "app=csi-azurefile-node"},
+		Image: "azurefile-csi", Impact: "Azure Files volumes cannot be provisioned or mounted"},
+	{Key: "blob-csi", DisplayName: "Azure Blob CSI", Category: "storage", Selectors: []string{"app=csi-blob-controller", "app=csi-blob-node"},
+		Image: "blob-csi", Impact: "Blob volumes cannot be mounted"},
+	{Key: "ebs-csi", DisplayName: "AWS EBS CSI", Category: "storage", Selectors: []string{"app.kubernetes.io/name=aws-ebs-csi-driver"},
+		Names: []string{"ebs-csi-controller", "ebs-csi-node"}, Image: "aws-ebs-csi-driver", Impact: "EBS volumes cannot be provisioned, attached, or mounted"},
+	{Key: "efs-csi", DisplayName: "AWS EFS CSI", Category: "storage", Selectors: []string{"app.kubernetes.io/name=aws-efs-csi-driver"},
+		Image: "aws-efs-csi-driver", Impact: "EFS volumes cannot be mounted"},
+	{Key: "gce-pd-csi", DisplayName: "GCE PD CSI", Category: "storage", Selectors: []string{"k8s-app=gcp-compute-persistent-disk-csi-driver"},
+		Image: "gcp-compute-persistent-disk-csi-driver", Impact: "persistent disks cannot be attached or mounted"},
+	{Key: "secrets-store-csi", DisplayName: "Secrets Store CSI", Category: "storage", Selectors: []string{"app=secrets-store-csi-driver", "app.kubernetes.io/name=secrets-store-csi-driver"},
+		Image: "secrets-store", Impact: "pods mounting secrets from external vaults cannot start"},
+	{Key: "cert-manager", DisplayName: "cert-manager", Category: "certificates", Selectors: []string{"app.kubernetes.io/instance=cert-manager", "app=cert-manager"},
+		Names: []string{"cert-manager", "cert-manager-cainjector", "cert-manager-webhook"}, Image: "cert-manager", Impact: "certificates stop renewing and expire"},
+	{Key: "external-dns", DisplayName: "ExternalDNS", Category: "dns", Selectors: []string{"app.kubernetes.io/name=external-dns", "app=external-dns"},
+		Image: "external-dns", Impact: "DNS records stop following Services and Ingresses"},
+	{Key: "kyverno", DisplayName: "Kyverno", Category: "policy", Selectors: []string{"app.kubernetes.io/part-of=kyverno", "app=kyverno"},
+		Image: "kyverno", Impact: "its webhooks reject or time out admission requests, depending on their failurePolicy"},
+	{Key: "gatekeeper", DisplayName: "OPA Gatekeeper", Category: "policy", Selectors: []string{"gatekeeper.sh/system=yes"},
+		Names: []string{"gatekeeper-controller-manager", "gatekeeper-audit"}, Image: "gatekeeper", Impact: "its webhook rejects or times out admission requests, depending on its failurePolicy"},
+}
+
+// allAddonTypes returns knownAddons followed by the ingress controllers.
+func allAddonTypes() []addonType {
+	types := append([]addonType(nil), knownAddons...)
+	for _, ic := range knownIngressControllers {
+		types = append(types, addonType{Key: ic.Key, DisplayName: ic.DisplayName, Category: "ingress", Selectors: ic.PodSelectors,
+			Image: "controller", Impact: "Ingress traffic stops being routed"})
+	}
+	return types
+}
+
+// addonWorkload is a Deployment, DaemonSet, or StatefulSet reduced to what
+// check_addons reports.
+type addonWorkload struct {
+	Kind, Namespace, Name string
+	Labels                map[string]string
+	Containers            []corev1.Container
+	Desired, Ready        int32
+}
+
+// addonWorkloads flattens the workloads check_addons matches against.
+func addonWorkloads(deploys []appsv1.Deployment, daemonSets []appsv1.DaemonSet, statefulSets []appsv1.StatefulSet) []addonWorkload {
+	var out []addonWorkload
+	for _, d := range deploys {
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		out = append(out, addonWorkload{Kind: "Deployment", Namespace: d.Namespace, Name: d.Name, Labels: d.Spec.Template.Labels,
+			Containers: d.Spec.Template.Spec.Containers, Desired: desired, Ready: d.Status.ReadyReplicas})
+	}
+	for _, ds := range daemonSets {
+		out = append(out, addonWorkload{Kind: "DaemonSet", Namespace: ds.Namespace, Name: ds.Name, Labels: ds.Spec.Template.Labels,
+			Containers: ds.Spec.Template.Spec.Containers, Desired: ds.Status.DesiredNumberScheduled, Ready: ds.Status.NumberReady})
+	}
+	for _, s := range statefulSets {
+		desired := int32(1)
+		if s.Spec.Replicas != nil {
+			desired = *s.Spec.Replicas
+		}
+		out = append(out, addonWorkload{Kind: "StatefulSet", Namespace: s.Namespace, Name: s.Name, Labels: s.Spec.Template.Labels,
+			Containers: s.Spec.Template.Spec.Containers, Desired: desired, Ready: s.Status.ReadyReplicas})
+	}
+	return out
+}
+
+// matches reports whether w runs add-on t.
+func (t addonType) matches(w addonWorkload) bool {
+	for _, n := range t.Names {
+		if w.Name == n {
+			return true
+		}
+	}
+	for _, s := range t.Selectors {
+		sel, err := labels.Parse(s)
+		if err == nil && sel.Matches(labels.Set(w.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// version returns the add-on version shown by w's image tag.
+func (t addonType) version(w addonWorkload) string {
+	if len(w.Containers) == 0 {
+		return ""
+	}
+	image := w.Containers[0].Image
+	for _, c := range w.Containers {
+		if t.Image != "" && strings.Contains(c.Image, t.Image) {
+			image = c.Image
+			break
+		}
+	}
+	// Prefer the tag of tag@digest references; it names the release.
+	if named, _, ok := strings.Cut(image, "@"); ok && imageTag(named) != "" {
+		return imageTag(named)
+	}
+	return imageTag(image)
+}
+
+// addonStatus is a detected add-on and the health of its workloads.
+type addonStatus struct {
+	Type           addonType
+	Namespaces     []string
+	Workloads      []string
+	Versions       []string
+	Desired, Ready int32
+	// Down lists workloads with no ready pods out of a non-zero desired count.
+	Down []addonWorkload
+}
+
+// Status is OK, Degraded, Down, or Scaled to 0.
+func (s addonStatus) Status() string {
+	switch {
+	case s.Desired == 0:
+		return "Scaled to 0"
+	case s.Ready == 0 || len(s.Down) > 0:
+		return "Down"
+	case s.Ready < s.Desired:
+		return "Degraded"
+	}
+	return "OK"
+}
+
+// detectAddons matches workloads to add-ons. A workload counts for the first
+// add-on it matches. It returns the detected add-ons in catalog order and
+// the expected add-ons that were not found.
+func detectAddons(types []addonType, workloads []addonWorkload) ([]addonStatus, []addonType) {
+	var found []addonStatus
+	var missing []addonType
+	claimed := make(map[string]bool)
+	for _, t := range types {
+		s := addonStatus{Type: t}
+		for _, w := range workloads {
+			key := w.Kind + "/" + w.Namespace + "/" + w.Name
+			if claimed[key] || !t.matches(w) {
+				continue
+			}
+			claimed[key] = true
+			s.Namespaces = append(s.Namespaces, w.Namespace)
+			s.Workloads = append(s.Workloads, strings.ToLower(w.Kind)+"/"+w.Name)
+			if v := t.version(w); v != "" {
+				s.Versions = append(s.Versions, v)
+			}
+			s.Desired += w.Desired
+			s.Ready += min(w.Ready, w.Desired)
+			if w.Desired > 0 && w.Ready == 0 {
+				s.Down = append(s.Down, w)
+			}
+		}
+		if len(s.Workloads) == 0 {
+			if t.Expected {
+				missing = append(missing, t)
+			}
+			continue
+		}
+		s.Namespaces, s.Versions = dedupe(s.Namespaces), dedupe(s.Versions)
+		found = append(found, s)
+	}
+	return found, missing
+}
+
+func registerAddonTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "check_addons",
+		Description: "Answer \"what is even running in this cluster\": detects common add-ons (CoreDNS, NodeLocal DNSCache, metrics-server, kube-state-metrics, " +
+			"cluster-autoscaler, KEDA, Azure/AWS/GCE CSI drivers, Secrets Store CSI, ingress controllers, cert-manager, ExternalDNS, Kyverno, Gatekeeper) " +
+			"from their Deployments, DaemonSets, and StatefulSets, and prints one table with each add-on's namespace, workloads, installed version " +
+			"(image tag), and ready replicas. Flags add-ons that are down or degraded with what breaks, and missing CoreDNS or metrics-server. " +
+			"Also lists registered CSIDrivers and IngressClasses.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkAddonsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		deploys, err := client.ListDeployments(ctx, "", metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing deployments", err), nil, nil
+		}
+		var gaps dataGaps
+		daemonSets, err := client.ListDaemonSets(ctx, "", metav1.ListOptions{})
+		gaps.record("DaemonSets", err)
+		statefulSets, err := client.ListStatefulSets(ctx, "", metav1.ListOptions{})
+		gaps.record("StatefulSets", err)
+		csiDrivers, err := client.ListCSIDrivers(ctx)
+		gaps.record("CSIDrivers", err)
+		ingressClasses, err := client.ListIngressClasses(ctx)
+		gaps.record("IngressClasses", err)
+
+		found, missing := detectAddons(allAddonTypes(), addonWorkloads(deploys, daemonSets, statefulSets))
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Cluster Add-ons"))
+		sb.WriteString("\n\n")
+		if len(found) > 0 {
+			rows := make([][]string, 0, len(found))
+			for _, s := range found {
+				rows = append(rows, []string{s.Type.DisplayName, s.Type.Category, strings.Join(s.Namespaces, ", "), joinLimited(s.Workloads, 3),
+					valueOrNone(strings.Join(s.Versions, ", ")), fmt.Sprintf("%d/%d", s.Ready, s.Desired), s.Status()})
+			}
+			sb.WriteString(util.FormatTable([]string{"ADD-ON", "CATEGORY", "NAMESPACE", "WORKLOADS", "VERSION", "READY", "STATUS"}, rows))
+		} else {
+			sb.WriteString("  No known add-ons detected.\n")
+		}
+		sb.WriteString("\n")
+		drivers := make([]string, 0, len(csiDrivers))
+		for _, d := range csiDrivers {
+			drivers = append(drivers, d.Name)
+		}
+		classes := make([]string, 0, len(ingressClasses))
+		for _, c := range ingressClasses {
+			classes = append(classes, c.Name+" ("+c.Spec.Controller+")")
+		}
+		sb.WriteString(util.FormatKeyValue("CSI drivers", valueOrNone(strings.Join(drivers, ", "))) + "\n")
+		sb.WriteString(util.FormatKeyValue("IngressClasses", valueOrNone(strings.Join(classes, ", "))) + "\n")
+		if len(gaps) > 0 {
+			sb.WriteString("\n" + gaps.assessment())
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		var actions []string
+		var steps []util.NextStep
+		count := 0
+		for _, s := range found {
+			switch s.Status() {
+			case "Scaled to 0":
+				sb.WriteString(findings.Format("KD-SYS-004", "WARNING", fmt.Sprintf("%s is scaled to 0: %s", s.Type.DisplayName, s.Type.Impact)))
+				sb.WriteString("\n")
+				count++
+			case "Down":
+				sb.WriteString(findings.Format("KD-SYS-004", "CRITICAL", fmt.Sprintf("%s is down (%d/%d ready): %s", s.Type.DisplayName, s.Ready, s.Desired, s.Type.Impact)))
+				sb.WriteString("\n")
+				count++
+				for _, w := range s.Down {
+					if w.Kind == "Deployment" {
+						steps = append(steps, nextStep("get_deployment_detail", s.Type.DisplayName+" has no ready replicas", "namespace", w.Namespace, "name", w.Name))
+					}
+				}
+				actions = append(actions, fmt.Sprintf("Restore %s: check its pods with find_unhealthy_pods namespace=%s and its events", s.Type.DisplayName, s.Namespaces[0]))
+			case "Degraded":
+				sb.WriteString(findings.Format("KD-SYS-004", "WARNING", fmt.Sprintf("%s is degraded: %d/%d replicas ready", s.Type.DisplayName, s.Ready, s.Desired)))
+				sb.WriteString("\n")
+				count++
+				actions = append(actions, fmt.Sprintf("Check the unready %s pods with find_unhealthy_pods namespace=%s", s.Type.DisplayName, s.Namespaces[0]))
+			}
+			if len(s.Versions) > 1 {
+				sb.WriteString(findings.Format("KD-SYS-004", "INFO", fmt.Sprintf("%s runs mixed versions (%s): a rollout in progress or components upgraded separately", s.Type.DisplayName, strings.Join(s.Versions, ", "))))
+				sb.WriteString("\n")
+				count++
+			}
+		}
+		for _, t := range missing {
+			sb.WriteString(findings.Format("KD-SYS-004", "WARNING", fmt.Sprintf("%s not found: without it %s", t.DisplayName, t.Impact)))
+			sb.WriteString("\n")
+			count++
+			actions = append(actions, fmt.Sprintf("Install %s, or confirm the cluster provides it another way", t.DisplayName))
+		}
+		if count == 0 {
+			sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("%d add-on(s) detected, all healthy", len(found))))
+			sb.WriteString("\n")
+		}
+		gaps.write(&sb)
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDetectAddons(t *testing.T) {
+	one := int32(1)
+	two := int32(2)
+	deploy := func(ns, name string, replicas *int32, ready int32, labels map[string]string, images ...string) appsv1.Deployment {
+		d := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+		d.Spec.Replicas = replicas
+		d.Spec.Template.Labels = labels
+		for _, img := range images {
+			d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Image: img})
+		}
+		d.Status.ReadyReplicas = ready
+		return d
+	}
+	deploys := []appsv1.Deployment{
+		deploy("kube-system", "coredns", &two, 1, map[string]string{"k8s-app": "kube-dns"}, "registry.k8s.io/coredns/coredns:v1.11.1"),
+		deploy("cert-manager", "cert-manager", &one, 1, map[string]string{"app.kubernetes.io/instance": "cert-manager"}, "quay.io/jetstack/cert-manager-controller:v1.14.4"),
+		deploy("cert-manager", "cert-manager-webhook", &one, 0, map[string]string{"app.kubernetes.io/instance": "cert-manager"}, "quay.io/jetstack/cert-manager-webhook:v1.14.4"),
+		deploy("ingress", "ingress-nginx-controller", &two, 2, map[string]string{"app.kubernetes.io/name": "ingress-nginx"}, "registry.k8s.io/ingress-nginx/controller:v1.10.0@sha256:abc"),
+		deploy("shop", "web", &two, 2, map[string]string{"app": "web"}, "web:1"),
+	}
+	ds := appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "csi-azuredisk-node"}}
+	ds.Spec.Template.Labels = map[string]string{"app": "csi-azuredisk-node"}
+	ds.Spec.Template.Spec.Containers = []corev1.Container{{Image: "mcr.microsoft.com/oss/kubernetes-csi/livenessprobe:v2.12.0"}, {Image: "mcr.microsoft.com/oss/kubernetes-csi/azuredisk-csi:v1.29.5"}}
+	ds.Status.DesiredNumberScheduled, ds.Status.NumberReady = 3, 3
+
+	found, missing := detectAddons(allAddonTypes(), addonWorkloads(deploys, []appsv1.DaemonSet{ds}, nil))
+	byKey := make(map[string]addonStatus)
+	for _, s := range found {
+		byKey[s.Type.Key] = s
+	}
+	if len(found) != 4 {
+		t.Fatalf("expected coredns, azure disk CSI, cert-manager, and nginx, got %d: %+v", len(found), found)
+	}
+	if s := byKey["coredns"]; s.Status() != "Degraded" || s.Versions[0] != "v1.11.1" {
+		t.Errorf("unexpected coredns status %s, versions %v", s.Status(), s.Versions)
+	}
+	if s := byKey["azuredisk-csi"]; s.Status() != "OK" || s.Versions[0] != "v1.29.5" {
+		t.Errorf("expected the driver container's version, got %v", s.Versions)
+	}
+	if s := byKey["cert-manager"]; s.Status() != "Down" || len(s.Workloads) != 2 || len(s.Versions) != 1 || len(s.Down) != 1 {
+		t.Errorf("expected cert-manager down with its webhook unready, got %+v", s)
+	}
+	if s := byKey["nginx"]; s.Type.Category != "ingress" || s.Versions[0] != "v1.10.0" {
+		t.Errorf("unexpected ingress controller status: %+v", s)
+	}
+	if len(missing) != 1 || missing[0].Key != "metrics-server" {
+		t.Errorf("expected metrics-server to be reported missing, got %+v", missing)
+	}
+}
@@ -0,0 +1,393 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// aksNodeImageLabel carries the node image version on AKS nodes.
+	aksNodeImageLabel = "kubernetes.azure.com/node-image-version"
+
+	// aksNodeImageMaxAge is how old a node image may be before it is flagged;
+	// AKS ships node images with OS security patches weekly.
+	aksNodeImageMaxAge = 90 * 24 * time.Hour
+
+	// aksThrottleLogPods caps how many cloud-provider pods have their logs scanned.
+	aksThrottleLogPods = 3
+)
+
+// aksDiskEventReasons are kubelet events that signal OS disk pressure.
+var aksDiskEventReasons = []string{"FreeDiskSpaceFailed", "ImageGCFailed", "EvictionThresholdMet"}
+
+type checkAKSHealthInput struct {
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// aksAddonType describes a kube-system component AKS installs.
+type aksAddonType struct {
+	Key          string
+	DisplayName  string
+	PerNode      bool
+	Required     bool
+	PodSelectors []string
+}
+
+// knownAKSAddons lists the AKS-managed components check_aks_health inspects.
+var knownAKSAddons = []aksAddonType{
+	{Key: "coredns", DisplayName: "CoreDNS", Required: true, PodSelectors: []string{"k8s-app=kube-dns"}},
+	{Key: "metrics-server", DisplayName: "metrics-server", Required: true, PodSelectors: []string{"k8s-app=metrics-server"}},
+	{Key: "konnectivity-agent", DisplayName: "konnectivity-agent (API server tunnel)", Required: true, PodSelectors: []string{"app=konnectivity-agent", "app=tunnelfront", "app=aks-link"}},
+	{Key: "azure-cns", DisplayName: "Azure CNS", PerNode: true, PodSelectors: []string{"k8s-app=azure-cns"}},
+	{Key: "azure-ip-masq-agent", DisplayName: "azure-ip-masq-agent", PerNode: true, PodSelectors: []string{"k8s-app=azure-ip-masq-agent"}},
+	{Key: "cloud-node-manager", DisplayName: "cloud-node-manager", PerNode: true, PodSelectors: []string{"k8s-app=cloud-node-manager"}},
+	{Key: "csi-azuredisk-node", DisplayName: "Azure Disk CSI driver", PerNode: true, PodSelectors: []string{"app=csi-azuredisk-node"}},
+	{Key: "csi-azurefile-node", DisplayName: "Azure File CSI driver", PerNode: true, PodSelectors: []string{"app=csi-azurefile-node"}},
+	{Key: "csi-blob-node", DisplayName: "Azure Blob CSI driver", PerNode: true, PodSelectors: []string{"app=csi-blob-node"}},
+}
+
+// aksThrottlePattern matches Azure Resource Manager throttling in event
+// messages and cloud-provider logs.
+var aksThrottlePattern = regexp.MustCompile(`(?i)(HTTPStatusCode:? ?429|status ?code:? ?429|StatusCode=429|TooManyRequests|throttl)`)
+
+// aksNodeImage is a parsed AKS node image version, e.g.
+// AKSUbuntu-2204gen2containerd-202405.03.0.
+type aksNodeImage struct {
+	Version string
+	Family  string
+	Date    time.Time
+}
+
+func registerAKSHealthTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// check_aks_health
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "check_aks_health",
+		Description: "AKS-specific health checks: kube-system add-ons (CoreDNS, metrics-server, konnectivity, Azure CNS, cloud-node-manager, Azure Disk/File/Blob CSI drivers) with per-node coverage, " +
+			"node image versions by pool against the newest image in the cluster and their age, OS disk pressure (DiskPressure and image GC failures), " +
+			"and Azure API throttling (HTTP 429) in events and cloud-provider logs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkAKSHealthInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing nodes", err), nil, nil
+		}
+		if !isAKSCluster(nodes) {
+			return util.SuccessResult("This does not look like an AKS cluster (no node has the kubernetes.azure.com/cluster label or an azure:// provider ID). Use check_ingress_controller_health, check_cluster_networking_components, and analyze_node_pools for provider-neutral checks.\n"), nil, nil
+		}
+		eligible := networkAgentNodes(nodes)
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("AKS Health"))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Node Resource Group", valueOrNone(nodes[0].Labels["kubernetes.azure.com/cluster"])) + "\n")
+		sb.WriteString(util.FormatKeyValue("Nodes", fmt.Sprintf("%d (%d Linux nodes expected to run per-node add-ons)", len(nodes), len(eligible))) + "\n")
+
+		// Add-ons
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("ADD-ONS (kube-system)"))
+		sb.WriteString("\n")
+		var rows [][]string
+		var cloudPods []corev1.Pod
+		for _, a := range knownAKSAddons {
+			pods, err := listPodsBySelectors(ctx, client, a.PodSelectors)
+			if err != nil {
+				return util.HandleK8sError(fmt.Sprintf("listing %s pods", a.Key), err), nil, nil
+			}
+			if len(pods) == 0 {
+				if a.Required {
+					rows = append(rows, []string{a.DisplayName, "-", "0", "-", "-", "-"})
+					findingLines = append(findingLines, findings.Format("KD-SYS-004", "WARNING", fmt.Sprintf("No %s pods found; AKS normally runs it in kube-system.", a.DisplayName)))
+					actions = append(actions, fmt.Sprintf("Check whether %s was removed or is failing to schedule (get_events namespace=kube-system).", a.Key))
+				}
+				continue
+			}
+			if a.Key == "cloud-node-manager" {
+				cloudPods = pods
+			}
+			cov := networkComponentCoverageFor(eligible, pods)
+			coverage := "-"
+			if a.PerNode {
+				coverage = fmt.Sprintf("%d/%d", len(eligible)-len(cov.Missing), len(eligible))
+			}
+			restarts := int32(0)
+			for i := range pods {
+				_, _, r := podContainerSummary(&pods[i])
+				restarts += r
+			}
+			rows = append(rows, []string{a.DisplayName, pods[0].Namespace, fmt.Sprintf("%d", len(pods)), fmt.Sprintf("%d", len(pods)-len(cov.Unhealthy)), coverage, fmt.Sprintf("%d", restarts)})
+
+			if a.PerNode && len(cov.Missing) > 0 {
+				findingLines = append(findingLines, findings.Format("KD-SYS-004", "CRITICAL", fmt.Sprintf("%s has no pod on %d node(s): %s", a.DisplayName, len(cov.Missing), joinLimited(cov.Missing, 5))))
+				actions = append(actions, fmt.Sprintf("Check the %s DaemonSet rollout and the missing nodes' taints; AKS reconciles managed add-ons, so a persistent gap usually means the node is unhealthy.", a.Key))
+			}
+			for _, p := range cov.Unhealthy {
+				findingLines = append(findingLines, findings.Format("KD-SYS-004", "CRITICAL", fmt.Sprintf("%s pod %s/%s is not healthy: %s", a.DisplayName, p.Namespace, p.Name, podPhaseReason(p))))
+				steps = append(steps, nextStep("diagnose_pod", fmt.Sprintf("%s is %s", a.DisplayName, podPhaseReason(p)), "namespace", p.Namespace, "name", p.Name))
+			}
+			for _, p := range cov.Restarted {
+				_, _, r := podContainerSummary(p)
+				findingLines = append(findingLines, findings.Format("KD-POD-005", "WARNING", fmt.Sprintf("%s pod %s/%s has restarted %d time(s).", a.DisplayName, p.Namespace, p.Name, r)))
+			}
+			if len(cov.Unhealthy) > 0 {
+				switch a.Key {
+				case "konnectivity-agent":
+					actions = append(actions, "konnectivity-agent is unhealthy: kubectl logs/exec and webhooks that call back into the cluster will fail. Check egress to the API server FQDN on port 443 from the node subnet.")
+				case "metrics-server":
+					actions = append(actions, "metrics-server is unhealthy: HPAs and kubectl top stop working. Check its pods' logs for kubelet TLS or connectivity errors.")
+				default:
+					actions = append(actions, fmt.Sprintf("Run diagnose_pod on the unhealthy %s pods.", a.Key))
+				}
+			}
+		}
+		sb.WriteString(util.FormatTable([]string{"ADD-ON", "NAMESPACE", "PODS", "READY", "NODE COVERAGE", "RESTARTS"}, rows))
+
+		// Node images
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("NODE IMAGES"))
+		sb.WriteString("\n")
+		imageRows, imageFindings := aksNodeImageReport(nodes, time.Now())
+		if len(imageRows) == 0 {
+			sb.WriteString("  No nodes carry the kubernetes.azure.com/node-image-version label.\n")
+		} else {
+			sb.WriteString(util.FormatTable([]string{"POOL", "NODE IMAGE", "NODES", "AGE", "NEWEST IN CLUSTER"}, imageRows))
+		}
+		for _, f := range imageFindings {
+			findingLines = append(findingLines, findings.Format("KD-NODE-009", "WARNING", f))
+		}
+		if len(imageFindings) > 0 {
+			actions = append(actions, "Upgrade node images (az aks nodepool upgrade --node-image-only) or enable the NodeImage auto-upgrade channel so pools get weekly security patches.")
+		}
+
+		// OS disk pressure
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("OS DISK PRESSURE"))
+		sb.WriteString("\n")
+		diskEvents, diskErr := client.ListEventsMatching(ctx, "", metav1.ListOptions{}, k8s.EventFilter{Reasons: aksDiskEventReasons, Kind: "Node"})
+		diskRows := aksDiskPressureRows(nodes, diskEvents)
+		if diskErr != nil {
+			sb.WriteString(fmt.Sprintf("  (could not list node events: %v)\n", diskErr))
+		}
+		if len(diskRows) == 0 {
+			sb.WriteString("  No node reports DiskPressure or image garbage collection failures.\n")
+		} else {
+			sb.WriteString(util.FormatTable([]string{"NODE", "SIGNAL", "DETAIL"}, diskRows))
+			seen := make(map[string]bool)
+			for _, r := range diskRows {
+				severity := "WARNING"
+				if r[1] == "DiskPressure" {
+					severity = "CRITICAL"
+				}
+				findingLines = append(findingLines, findings.Format("KD-NODE-004", severity, fmt.Sprintf("Node %s: %s (%s)", r[0], r[1], r[2])))
+				if !seen[r[0]] {
+					seen[r[0]] = true
+					steps = append(steps, nextStep("diagnose_node", "the node's OS disk is under pressure", "name", r[0]))
+				}
+			}
+			actions = append(actions, "Free OS disk space on the flagged nodes (unused images, large emptyDir or container logs), or move the pool to a larger OS disk or ephemeral OS disks.")
+		}
+
+		// Azure API throttling
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("AZURE API THROTTLING"))
+		sb.WriteString("\n")
+		warnings, warnErr := client.ListEventsMatching(ctx, "", metav1.ListOptions{FieldSelector: "type=Warning"}, k8s.EventFilter{})
+		if warnErr != nil {
+			sb.WriteString(fmt.Sprintf("  (could not list events: %v)\n", warnErr))
+		}
+		throttled := aksThrottleEvents(warnings)
+		for _, e := range throttled {
+			sb.WriteString(fmt.Sprintf("  %s ago %s %s/%s %s: %s", util.FormatAge(k8s.EventTime(&e)), e.InvolvedObject.Kind, e.Namespace, e.InvolvedObject.Name, e.Reason, truncateName(e.Message, 160)))
+			if e.Count > 1 {
+				sb.WriteString(fmt.Sprintf(" (x%d)", e.Count))
+			}
+			sb.WriteString("\n")
+		}
+		logHits := 0
+		for i := range cloudPods {
+			if i == aksThrottleLogPods {
+				break
+			}
+			p := &cloudPods[i]
+			logs, err := client.GetPodLogs(ctx, p.Namespace, p.Name, "", 500, false, "1h")
+			if err != nil {
+				continue
+			}
+			for _, line := range strings.Split(logs, "\n") {
+				if aksThrottlePattern.MatchString(line) {
+					logHits++
+					if logHits <= 5 {
+						sb.WriteString(fmt.Sprintf("  %s: %s\n", p.Name, truncateName(strings.TrimSpace(line), 200)))
+					}
+				}
+			}
+		}
+		if len(throttled) == 0 && logHits == 0 {
+			sb.WriteString("  No Azure API 429s in recent events or cloud-node-manager logs (the cloud-controller-manager runs in the managed control plane and its logs are only in Azure diagnostics).\n")
+		} else {
+			findingLines = append(findingLines, findings.Format("KD-SYS-009", "WARNING", fmt.Sprintf("Azure Resource Manager is throttling the cluster's cloud provider calls (%d event(s), %d log line(s)); load balancer, route, and disk operations are delayed.", len(throttled), logHits)))
+			actions = append(actions, "Reduce ARM call volume: avoid frequent Service/LoadBalancer churn and disk attach/detach storms, and check other tools sharing the subscription's read/write quota (see the AKS 'cluster-autoscaler' and 'control plane' diagnostics in the portal).")
+			steps = append(steps, nextStep("analyze_events", "look at other failures around the throttling", "namespace", "all"))
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  AKS add-ons, node images, OS disks, and Azure API calls look healthy.\n")
+		}
+		for _, f := range dedupe(findingLines) {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// isAKSCluster reports whether any node is an AKS node.
+func isAKSCluster(nodes []corev1.Node) bool {
+	for i := range nodes {
+		if nodes[i].Labels["kubernetes.azure.com/cluster"] != "" || strings.HasPrefix(nodes[i].Spec.ProviderID, "azure://") {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// Linux images end in YYYYMM.DD.patch, Windows images in build.revision.YYMMDD.
+	aksLinuxImageDate   = regexp.MustCompile(`^(\d{6}\.\d{2})\.\d+$`)
+	aksWindowsImageDate = regexp.MustCompile(`^\d+\.\d+\.(\d{6})$`)
+)
+
+// parseAKSNodeImage splits a node image version into its image family and build date.
+func parseAKSNodeImage(version string) (aksNodeImage, bool) {
+	i := strings.LastIndex(version, "-")
+	if i < 0 {
+		return aksNodeImage{}, false
+	}
+	img := aksNodeImage{Version: version, Family: version[:i]}
+	suffix := version[i+1:]
+	var err error
+	if m := aksLinuxImageDate.FindStringSubmatch(suffix); m != nil {
+		img.Date, err = time.Parse("200601.02", m[1])
+	} else if m := aksWindowsImageDate.FindStringSubmatch(suffix); m != nil {
+		img.Date, err = time.Parse("060102", m[1])
+	} else {
+		return aksNodeImage{}, false
+	}
+	return img, err == nil
+}
+
+// aksNodeImageReport returns one row per pool and image version, and
+// findings for pools behind the newest image of their family in the cluster
+// or older than aksNodeImageMaxAge.
+func aksNodeImageReport(nodes []corev1.Node, now time.Time) ([][]string, []string) {
+	type poolImage struct {
+		pool  string
+		image aksNodeImage
+	}
+	counts := make(map[poolImage]int)
+	newest := make(map[string]aksNodeImage)
+	for i := range nodes {
+		v := nodes[i].Labels[aksNodeImageLabel]
+		if v == "" {
+			continue
+		}
+		img, ok := parseAKSNodeImage(v)
+		if !ok {
+			img = aksNodeImage{Version: v, Family: v}
+		}
+		counts[poolImage{nodePoolName(&nodes[i]), img}]++
+		if n, seen := newest[img.Family]; !seen || img.Date.After(n.Date) {
+			newest[img.Family] = img
+		}
+	}
+
+	keys := make([]poolImage, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].pool != keys[j].pool {
+			return keys[i].pool < keys[j].pool
+		}
+		return keys[i].image.Version < keys[j].image.Version
+	})
+
+	var rows [][]string
+	var findingLines []string
+	for _, k := range keys {
+		age := "-"
+		if !k.image.Date.IsZero() {
+			age = fmt.Sprintf("%dd", int(now.Sub(k.image.Date).Hours()/24))
+		}
+		latest := newest[k.image.Family]
+		newestCol := "yes"
+		if latest.Version != k.image.Version {
+			newestCol = latest.Version
+			findingLines = append(findingLines, fmt.Sprintf("Pool %s has %d node(s) on %s, %d days behind %s running elsewhere in the cluster.",
+				k.pool, counts[k], k.image.Version, int(latest.Date.Sub(k.image.Date).Hours()/24), latest.Version))
+		}
+		if !k.image.Date.IsZero() && now.Sub(k.image.Date) > aksNodeImageMaxAge {
+			findingLines = append(findingLines, fmt.Sprintf("Pool %s runs node image %s, built %s ago; it is missing recent OS security patches.",
+				k.pool, k.image.Version, util.FormatAge(k.image.Date)))
+		}
+		rows = append(rows, []string{k.pool, k.image.Version, fmt.Sprintf("%d", counts[k]), age, newestCol})
+	}
+	return rows, findingLines
+}
+
+// aksDiskPressureRows returns {node, signal, detail} for nodes reporting
+// DiskPressure and for kubelet disk events about the node filesystem.
+func aksDiskPressureRows(nodes []corev1.Node, events []corev1.Event) [][]string {
+	var rows [][]string
+	for i := range nodes {
+		for _, c := range nodes[i].Status.Conditions {
+			if c.Type == corev1.NodeDiskPressure && c.Status == corev1.ConditionTrue {
+				rows = append(rows, []string{nodes[i].Name, "DiskPressure", c.Message})
+			}
+		}
+	}
+	seen := make(map[string]bool)
+	for _, e := range events {
+		if e.Reason == "EvictionThresholdMet" && !strings.Contains(e.Message, "ephemeral-storage") && !strings.Contains(e.Message, "nodefs") && !strings.Contains(e.Message, "imagefs") {
+			continue
+		}
+		key := e.InvolvedObject.Name + "/" + e.Reason
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		rows = append(rows, []string{e.InvolvedObject.Name, e.Reason, truncateName(e.Message, 100)})
+	}
+	return rows
+}
+
+// aksThrottleEvents returns events whose message shows Azure API throttling.
+func aksThrottleEvents(events []corev1.Event) []corev1.Event {
+	var result []corev1.Event
+	for _, e := range events {
+		if aksThrottlePattern.MatchString(e.Message) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
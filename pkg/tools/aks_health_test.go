@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// aksNodeLabels are the labels AKS puts on a node in pool running image.
+func aksNodeLabels(pool, image string) map[string]string {
+	return map[string]string{
+		"kubernetes.azure.com/cluster":   "MC_rg_aks_westeurope",
+		"kubernetes.azure.com/agentpool": pool,
+		aksNodeImageLabel:                image,
+	}
+}
+
+func TestParseAKSNodeImage(t *testing.T) {
+	tests := []struct {
+		version, family, date string
+	}{
+		{"AKSUbuntu-2204gen2containerd-202405.03.0", "AKSUbuntu-2204gen2containerd", "2024-05-03"},
+		{"AKSAzureLinux-V2gen2-202409.23.0", "AKSAzureLinux-V2gen2", "2024-09-23"},
+		{"AKSWindows-2022-containerd-20348.2402.240312", "AKSWindows-2022-containerd", "2024-03-12"},
+	}
+	for _, tt := range tests {
+		img, ok := parseAKSNodeImage(tt.version)
+		if !ok || img.Family != tt.family || img.Date.Format("2006-01-02") != tt.date {
+			t.Errorf("parseAKSNodeImage(%q) = %+v, %v; want family %s date %s", tt.version, img, ok, tt.family, tt.date)
+		}
+	}
+	if _, ok := parseAKSNodeImage("custom-image"); ok {
+		t.Error("expected a non-AKS image version not to parse")
+	}
+}
+
+func TestAKSNodeImageReport(t *testing.T) {
+	nodes := []corev1.Node{
+		testNode("sys-0", "4", "16Gi", aksNodeLabels("system", "AKSUbuntu-2204gen2containerd-202409.23.0")),
+		testNode("user-0", "4", "16Gi", aksNodeLabels("user", "AKSUbuntu-2204gen2containerd-202405.03.0")),
+		testNode("user-1", "4", "16Gi", aksNodeLabels("user", "AKSUbuntu-2204gen2containerd-202405.03.0")),
+	}
+	now := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	rows, findings := aksNodeImageReport(nodes, now)
+	if len(rows) != 2 || rows[0][0] != "system" || rows[0][4] != "yes" || rows[1][2] != "2" {
+		t.Errorf("rows = %v", rows)
+	}
+	joined := strings.Join(findings, "\n")
+	if !strings.Contains(joined, "Pool user has 2 node(s) on AKSUbuntu-2204gen2containerd-202405.03.0, 143 days behind") {
+		t.Errorf("findings = %q, want user pool behind system pool", findings)
+	}
+	if !strings.Contains(joined, "missing recent OS security patches") || strings.Contains(joined, "Pool system runs") {
+		t.Errorf("findings = %q, want only the user pool flagged as old", findings)
+	}
+}
+
+func TestAKSDiskPressureRows(t *testing.T) {
+	nodes := []corev1.Node{testNode("n1", "4", "16Gi", aksNodeLabels("user", "")), testNode("n2", "4", "16Gi", aksNodeLabels("user", ""))}
+	nodes[0].Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue, Message: "kubelet has disk pressure"}}
+	nodes[1].Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse}}
+	event := func(node, reason, msg string) corev1.Event {
+		return corev1.Event{InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: node}, Reason: reason, Message: msg}
+	}
+	events := []corev1.Event{
+		event("n2", "ImageGCFailed", "failed to garbage collect required amount of images"),
+		event("n2", "ImageGCFailed", "failed to garbage collect required amount of images"),
+		event("n2", "EvictionThresholdMet", "Attempting to reclaim memory"),
+	}
+
+	rows := aksDiskPressureRows(nodes, events)
+	if len(rows) != 2 || rows[0][1] != "DiskPressure" || rows[1][1] != "ImageGCFailed" {
+		t.Errorf("aksDiskPressureRows() = %v, want DiskPressure on n1 and one ImageGCFailed on n2", rows)
+	}
+}
+
+func TestAKSThrottleEvents(t *testing.T) {
+	events := []corev1.Event{
+		{Reason: "SyncLoadBalancerFailed", Message: "Error syncing load balancer: Retriable: true, RetryAfter: 10s, HTTPStatusCode: 429, RawError: too many requests"},
+		{Reason: "FailedAttachVolume", Message: "AttachVolume.Attach failed: azure - cloud provider rate limited(read) for operation VMGetCall, throttled"},
+		{Reason: "FailedScheduling", Message: "0/3 nodes are available: 3 Insufficient cpu."},
+	}
+	if got := aksThrottleEvents(events); len(got) != 2 {
+		t.Errorf("aksThrottleEvents() returned %d events, want 2", len(got))
+	}
+}
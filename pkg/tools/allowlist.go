@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// namespaceAllowlistMiddleware rejects tool calls whose namespace arguments
+// name a namespace outside allowed. Empty and "all" namespaces pass through;
+// the client scopes them to the allowlist.
+func namespaceAllowlistMiddleware(allowed []string) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			call, ok := req.(*mcp.CallToolRequest)
+			if !ok || method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			if ns := disallowedNamespace(call.Params.Arguments, allowed); ns != "" {
+				return util.ErrorResult("Namespace %q is not allowed: this server is restricted to namespaces %s.", ns, strings.Join(allowed, ", ")), nil
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// disallowedNamespace returns the first namespace named in tool arguments
+// ("namespace" or any "*_namespace" key) that is not in allowed.
+func disallowedNamespace(args json.RawMessage, allowed []string) string {
+	var fields map[string]any
+	if len(args) == 0 || json.Unmarshal(args, &fields) != nil {
+		return ""
+	}
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		if key != "namespace" && !strings.HasSuffix(key, "_namespace") {
+			continue
+		}
+		ns, _ := fields[key].(string)
+		if ns = util.NamespaceOrAll(strings.TrimSpace(ns)); ns != "" && !slices.Contains(allowed, ns) {
+			return ns
+		}
+	}
+	return ""
+}
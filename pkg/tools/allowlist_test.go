@@ -0,0 +1,27 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDisallowedNamespace(t *testing.T) {
+	allowed := []string{"team-a", "team-b"}
+	tests := []struct {
+		args string
+		want string
+	}{
+		{`{"namespace":"team-a","name":"web"}`, ""},
+		{`{"namespace":""}`, ""},
+		{`{"namespace":"all"}`, ""},
+		{`{"namespace":"kube-system"}`, "kube-system"},
+		{`{"source_namespace":"team-a","target_namespace":"prod"}`, "prod"},
+		{`{"name":"web"}`, ""},
+		{``, ""},
+	}
+	for _, tt := range tests {
+		if got := disallowedNamespace(json.RawMessage(tt.args), allowed); got != tt.want {
+			t.Errorf("disallowedNamespace(%s) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/azure"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// agicLegacyClass is the kubernetes.io/ingress.class annotation value AGIC serves.
+const agicLegacyClass = "azure/application-gateway"
+
+// 502 origins reported by check_appgw_backends.
+const (
+	originCluster = "Kubernetes"
+	originProbe   = "Azure (health probe)"
+	originSync    = "AGIC sync"
+)
+
+type checkAppGatewayBackendsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Only cross-check Ingresses in this namespace (default: all namespaces)"`
+	Ingress        string `json:"ingress,omitempty" jsonschema:"Only cross-check this Ingress (requires namespace)"`
+	AppGatewayID   string `json:"app_gateway_id,omitempty" jsonschema:"ARM resource ID of the Application Gateway (default: read from the AGIC ConfigMap or pod environment)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// appGWBackend is one Ingress path routed through AGIC and the Service
+// endpoints behind it.
+type appGWBackend struct {
+	Ingress     string
+	Host        string
+	Path        string
+	Service     string
+	Missing     bool
+	ReadyIPs    []string
+	NotReadyIPs []string
+}
+
+// appGWVerdict is the cross-check result for one backend.
+type appGWVerdict struct {
+	Backend   appGWBackend
+	Pools     []string
+	InPool    int
+	Healthy   int
+	Unhealthy int
+	Stale     []string
+	Origin    string
+	Severity  string
+	Message   string
+}
+
+func registerAppGatewayTools(server *mcp.Server, client *k8s.ClusterClient, opts Options) {
+	if opts.AppGateway == nil {
+		return
+	}
+
+	// check_appgw_backends
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "check_appgw_backends",
+		Description: "Cross-check an Azure Application Gateway used by AGIC against the cluster: reads the gateway's listeners, backend pools, and on-demand backend health from Azure Resource Manager " +
+			"and compares them with each AGIC Ingress's Service endpoints. Pinpoints whether 502s originate in Kubernetes (no ready pods), in Azure (health probes failing for ready pods), " +
+			"or in AGIC sync (pool addresses out of date with the endpoints). Requires --azure-appgw.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkAppGatewayBackendsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+		if input.Ingress != "" && ns == "" {
+			return util.ErrorResult("ingress requires a namespace"), nil, nil
+		}
+
+		classes, err := client.ListIngressClasses(ctx)
+		if err != nil && !util.IsScopeError(err) {
+			return util.HandleK8sError("listing ingress classes", err), nil, nil
+		}
+		var agic detectedIngressController
+		detected, err := detectIngressControllers(ctx, client, classes)
+		if err != nil {
+			return util.HandleK8sError("searching for AGIC pods", err), nil, nil
+		}
+		for _, d := range detected {
+			if d.Type.Key == "agic" {
+				agic = d
+			}
+		}
+
+		source := "app_gateway_id"
+		var id azure.AppGatewayID
+		if input.AppGatewayID != "" {
+			if id, err = azure.ParseAppGatewayID(input.AppGatewayID); err != nil {
+				return util.ErrorResult("%v", err), nil, nil
+			}
+		} else {
+			var ok bool
+			if id, source, ok = discoverAppGatewayID(ctx, client, agic); !ok {
+				return util.ErrorResult("could not find the Application Gateway: no APPGW_RESOURCE_ID or APPGW_SUBSCRIPTION_ID/APPGW_RESOURCE_GROUP/APPGW_NAME in the AGIC ConfigMap or pod environment; pass app_gateway_id"), nil, nil
+			}
+		}
+
+		gw, err := opts.AppGateway.Get(ctx, id)
+		if err != nil {
+			return util.ErrorResult("Azure Resource Manager (%s credential): %v", opts.AppGateway.Credential.Kind(), err), nil, nil
+		}
+		health, healthErr := opts.AppGateway.BackendHealth(ctx, id)
+
+		ingressOpts := metav1.ListOptions{}
+		if input.Ingress != "" {
+			ingressOpts.FieldSelector = "metadata.name=" + input.Ingress
+		}
+		ingresses, err := client.ListIngresses(ctx, ns, ingressOpts)
+		if err != nil {
+			return util.HandleK8sError("listing ingresses", err), nil, nil
+		}
+		backends := collectAppGWBackends(ctx, client, ingresses, agic.Classes)
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Application Gateway Backend Cross-Check (namespace: %s)", displayNS(ns))))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Application Gateway", id.String()) + "\n")
+		sb.WriteString(util.FormatKeyValue("Found Via", source) + "\n")
+		sb.WriteString(util.FormatKeyValue("Credential", opts.AppGateway.Credential.Kind()) + "\n")
+		sb.WriteString(util.FormatKeyValue("State", fmt.Sprintf("%s (provisioning %s)", valueOrNone(gw.OperationalState), valueOrNone(gw.ProvisioningState))) + "\n")
+		if gw.OperationalState != "" && gw.OperationalState != "Running" {
+			findingLines = append(findingLines, findings.Format("KD-ING-005", "CRITICAL", fmt.Sprintf("Application Gateway %s is %s, not Running; every request fails in Azure before reaching the cluster.", gw.Name, gw.OperationalState)))
+			actions = append(actions, fmt.Sprintf("Start the gateway: az network application-gateway start -g %s -n %s", id.ResourceGroup, id.Name))
+		}
+
+		// Listeners
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("LISTENERS"))
+		sb.WriteString("\n")
+		if len(gw.Listeners) == 0 {
+			sb.WriteString("  No listeners.\n")
+		} else {
+			var rows [][]string
+			for _, l := range gw.Listeners {
+				hosts := "*"
+				if len(l.HostNames) > 0 {
+					hosts = strings.Join(l.HostNames, ", ")
+				}
+				rows = append(rows, []string{truncateName(l.Name, 50), l.Protocol, fmt.Sprintf("%d", l.Port), hosts})
+			}
+			sb.WriteString(util.FormatTable([]string{"NAME", "PROTOCOL", "PORT", "HOSTS"}, rows))
+		}
+
+		// Backend health
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("BACKEND HEALTH (reported by Azure)"))
+		sb.WriteString("\n")
+		if healthErr != nil {
+			sb.WriteString(fmt.Sprintf("  (backend health unavailable: %v)\n", healthErr))
+			findingLines = append(findingLines, findings.Format("KD-SYS-008", "WARNING", "Could not read backend health from Azure; the cross-check below only compares pool membership."))
+			health = nil
+		} else {
+			var rows [][]string
+			for _, p := range health.Pools {
+				for _, s := range p.Servers {
+					rows = append(rows, []string{truncateName(p.Pool, 40), truncateName(p.Settings, 40), s.Address, s.Health, truncateName(valueOrNone(s.ProbeLog), 80)})
+				}
+			}
+			if len(rows) == 0 {
+				sb.WriteString("  No backend servers.\n")
+			} else {
+				sb.WriteString(util.FormatTable([]string{"POOL", "SETTINGS", "ADDRESS", "HEALTH", "PROBE LOG"}, rows))
+			}
+		}
+
+		// Cross-check
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("INGRESS CROSS-CHECK"))
+		sb.WriteString("\n")
+		if len(backends) == 0 {
+			sb.WriteString("  No Ingresses served by AGIC.\n")
+		}
+		verdicts := crossCheckAppGateway(backends, gw, health)
+		var rows [][]string
+		origins := make(map[string]int)
+		for _, v := range verdicts {
+			b := v.Backend
+			healthy := "-"
+			if health != nil && v.InPool > 0 {
+				healthy = fmt.Sprintf("%d/%d", v.Healthy, v.InPool)
+			}
+			rows = append(rows, []string{b.Ingress, b.Host + b.Path, b.Service, fmt.Sprintf("%d", len(b.ReadyIPs)), fmt.Sprintf("%d", v.InPool), healthy, valueOrNone(v.Origin)})
+			if v.Message == "" {
+				continue
+			}
+			origins[v.Origin]++
+			findingLines = append(findingLines, findings.Format("KD-ING-008", v.Severity, v.Message))
+			ingNS, ingName, _ := strings.Cut(b.Ingress, "/")
+			switch v.Origin {
+			case originCluster:
+				actions = append(actions, fmt.Sprintf("Fix the pods behind %s before looking at Azure; the gateway has nothing healthy to route to.", b.Service))
+				steps = append(steps, nextStep("list_endpoint_health", "see why the service has no ready endpoints", "namespace", ingNS))
+			case originProbe:
+				actions = append(actions, fmt.Sprintf("Align the gateway's health probe with %s's readiness: set appgw.ingress.kubernetes.io/health-probe-path (and health-probe-port) on Ingress %s, and check that NSG or route table rules let the gateway subnet reach the pod IPs.", b.Service, ingName))
+				steps = append(steps, nextStep("trace_ingress_to_backend", "compare the probe path with the routed path", "hostname", b.Host, "path", b.Path))
+			case originSync:
+				actions = append(actions, "Check AGIC: it has not pushed the current pod IPs or listeners to the gateway; look for ARM errors, throttling, or a lost identity in its logs.")
+				steps = append(steps, nextStep("check_agic_health", "AGIC has not synced the gateway with the cluster"))
+			}
+		}
+		if len(rows) > 0 {
+			sb.WriteString(util.FormatTable([]string{"INGRESS", "HOST/PATH", "SERVICE", "READY PODS", "IN POOL", "HEALTHY", "502 ORIGIN"}, rows))
+		}
+		for _, f := range appGWListenerFindings(backends, gw) {
+			findingLines = append(findingLines, findings.Format("KD-ING-008", "WARNING", f))
+			origins[originSync]++
+			actions = append(actions, "Check AGIC logs for why the listener was not created (e.g. a missing TLS secret or a conflicting Ingress).")
+			steps = append(steps, nextStep("check_agic_health", "an Ingress host has no gateway listener"))
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("WHERE 502s ORIGINATE"))
+		sb.WriteString("\n")
+		if len(origins) == 0 {
+			sb.WriteString("  No backend problems found in Azure or in the cluster.\n")
+		}
+		for _, o := range sortedKeysInt(origins) {
+			sb.WriteString(fmt.Sprintf("  %s: %d backend(s)\n", o, origins[o]))
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  Every AGIC backend is in its pool and healthy.\n")
+		}
+		for _, f := range dedupe(findingLines) {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// discoverAppGatewayID reads the gateway AGIC manages from its ConfigMap or
+// container environment. It returns where the ID was found.
+func discoverAppGatewayID(ctx context.Context, client *k8s.ClusterClient, agic detectedIngressController) (azure.AppGatewayID, string, bool) {
+	tried := make(map[string]bool)
+	for i := range agic.Pods {
+		p := &agic.Pods[i]
+		names := append([]string{}, agic.Type.ConfigMaps...)
+		for _, c := range p.Spec.Containers {
+			env := make(map[string]string)
+			for _, e := range c.Env {
+				env[e.Name] = e.Value
+			}
+			if id, ok := appGatewayIDFromConfig(env); ok {
+				return id, fmt.Sprintf("pod %s/%s environment", p.Namespace, p.Name), true
+			}
+			for _, from := range c.EnvFrom {
+				if from.ConfigMapRef != nil {
+					names = append(names, from.ConfigMapRef.Name)
+				}
+			}
+		}
+		for _, name := range names {
+			key := p.Namespace + "/" + name
+			if tried[key] {
+				continue
+			}
+			tried[key] = true
+			cm, err := client.GetConfigMap(ctx, p.Namespace, name)
+			if err != nil {
+				continue
+			}
+			if id, ok := appGatewayIDFromConfig(cm.Data); ok {
+				return id, "ConfigMap " + key, true
+			}
+		}
+	}
+	return azure.AppGatewayID{}, "", false
+}
+
+// appGatewayIDFromConfig reads the gateway from AGIC's configuration keys.
+func appGatewayIDFromConfig(data map[string]string) (azure.AppGatewayID, bool) {
+	if raw := data["APPGW_RESOURCE_ID"]; raw != "" {
+		if id, err := azure.ParseAppGatewayID(raw); err == nil {
+			return id, true
+		}
+	}
+	id := azure.AppGatewayID{SubscriptionID: data["APPGW_SUBSCRIPTION_ID"], ResourceGroup: data["APPGW_RESOURCE_GROUP"], Name: data["APPGW_NAME"]}
+	return id, id.SubscriptionID != "" && id.ResourceGroup != "" && id.Name != ""
+}
+
+// collectAppGWBackends lists the Service backends of every Ingress served by
+// AGIC, with their ready and not-ready endpoint IPs.
+func collectAppGWBackends(ctx context.Context, client *k8s.ClusterClient, ingresses []networkingv1.Ingress, agicClasses []string) []appGWBackend {
+	served := map[string]bool{agicLegacyClass: true}
+	for _, c := range agicClasses {
+		served[c] = true
+	}
+	endpoints := make(map[string]*k8s.EndpointHealth)
+	missing := make(map[string]bool)
+	var backends []appGWBackend
+	for i := range ingresses {
+		ing := &ingresses[i]
+		if !served[ingressClassName(ing)] {
+			continue
+		}
+		add := func(host, path, svc string) {
+			key := ing.Namespace + "/" + svc
+			if _, seen := endpoints[key]; !seen && !missing[key] {
+				h, err := client.GetServiceEndpointHealth(ctx, ing.Namespace, svc)
+				if err != nil {
+					missing[key] = apierrors.IsNotFound(err)
+				}
+				endpoints[key] = h
+			}
+			b := appGWBackend{Ingress: ing.Namespace + "/" + ing.Name, Host: host, Path: path, Service: key, Missing: missing[key]}
+			if h := endpoints[key]; h != nil {
+				for _, a := range h.ReadyAddresses {
+					b.ReadyIPs = append(b.ReadyIPs, a.IP)
+				}
+				for _, a := range h.NotReadyPods {
+					b.NotReadyIPs = append(b.NotReadyIPs, a.IP)
+				}
+			}
+			backends = append(backends, b)
+		}
+		if d := ing.Spec.DefaultBackend; d != nil && d.Service != nil {
+			add("*", "", d.Service.Name)
+		}
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			host := rule.Host
+			if host == "" {
+				host = "*"
+			}
+			for _, p := range rule.HTTP.Paths {
+				if p.Backend.Service != nil {
+					add(host, p.Path, p.Backend.Service.Name)
+				}
+			}
+		}
+	}
+	return backends
+}
+
+// crossCheckAppGateway compares each backend's ready pod IPs with the gateway's
+// pools and backend health and attributes any problem to Kubernetes, the
+// gateway's health probe, or AGIC sync. health may be nil.
+func crossCheckAppGateway(backends []appGWBackend, gw *azure.AppGateway, health *azure.BackendHealth) []appGWVerdict {
+	poolsByAddr := make(map[string][]string)
+	for _, p := range gw.BackendPools {
+		for _, a := range p.Addresses {
+			poolsByAddr[a] = append(poolsByAddr[a], p.Name)
+		}
+	}
+	serverHealth := make(map[string]azure.ServerHealth)
+	if health != nil {
+		for _, p := range health.Pools {
+			for _, s := range p.Servers {
+				// Keep the worst status when an address is probed under several settings.
+				if prev, ok := serverHealth[s.Address]; !ok || prev.Health == "Healthy" {
+					serverHealth[s.Address] = s
+				}
+			}
+		}
+	}
+
+	var verdicts []appGWVerdict
+	for _, b := range backends {
+		v := appGWVerdict{Backend: b}
+		ready := make(map[string]bool)
+		pools := make(map[string]bool)
+		var probeLogs []string
+		for _, ip := range b.ReadyIPs {
+			ready[ip] = true
+			if len(poolsByAddr[ip]) == 0 {
+				continue
+			}
+			v.InPool++
+			for _, p := range poolsByAddr[ip] {
+				pools[p] = true
+			}
+			switch s := serverHealth[ip]; s.Health {
+			case "Healthy":
+				v.Healthy++
+			case "Unhealthy":
+				v.Unhealthy++
+				if s.ProbeLog != "" {
+					probeLogs = append(probeLogs, s.ProbeLog)
+				}
+			}
+		}
+		v.Pools = sortedKeys(pools)
+		for _, p := range gw.BackendPools {
+			if !pools[p.Name] {
+				continue
+			}
+			for _, a := range p.Addresses {
+				if !ready[a] {
+					v.Stale = append(v.Stale, a)
+				}
+			}
+		}
+
+		switch {
+		case b.Missing:
+			v.Origin, v.Severity = originCluster, "CRITICAL"
+			v.Message = fmt.Sprintf("Ingress %s routes %s%s to Service %s, which does not exist; the gateway answers 502.", b.Ingress, b.Host, b.Path, b.Service)
+		case len(b.ReadyIPs) == 0:
+			v.Origin, v.Severity = originCluster, "CRITICAL"
+			v.Message = fmt.Sprintf("Service %s has no ready endpoints (%d not ready); the gateway has nothing to route %s%s to and answers 502.", b.Service, len(b.NotReadyIPs), b.Host, b.Path)
+		case v.InPool == 0:
+			v.Origin, v.Severity = originSync, "CRITICAL"
+			v.Message = fmt.Sprintf("None of the %d ready pod IPs of %s are in any gateway backend pool; AGIC has not applied the current endpoints.", len(b.ReadyIPs), b.Service)
+		case v.Unhealthy > 0:
+			v.Origin, v.Severity = originProbe, "WARNING"
+			if v.Healthy == 0 {
+				v.Severity = "CRITICAL"
+			}
+			v.Message = fmt.Sprintf("The gateway's health probe fails for %d of %d pods of %s that Kubernetes reports ready", v.Unhealthy, v.InPool, b.Service)
+			if len(probeLogs) > 0 {
+				v.Message += ": " + truncateName(probeLogs[0], 160)
+			}
+			v.Message += "."
+		case v.InPool < len(b.ReadyIPs):
+			v.Origin, v.Severity = originSync, "WARNING"
+			v.Message = fmt.Sprintf("%d of %d ready pod IPs of %s are missing from pool %s; AGIC is behind the endpoints, so new pods get no traffic.", len(b.ReadyIPs)-v.InPool, len(b.ReadyIPs), b.Service, strings.Join(v.Pools, ", "))
+		case len(v.Stale) > 0:
+			v.Origin, v.Severity = originSync, "WARNING"
+			v.Message = fmt.Sprintf("Pool %s still lists %d address(es) that are no longer ready pods of %s (%s); requests sent there before the probe marks them down fail with 502.",
+				strings.Join(v.Pools, ", "), len(v.Stale), b.Service, strings.Join(v.Stale, ", "))
+		}
+		verdicts = append(verdicts, v)
+	}
+	return verdicts
+}
+
+// appGWListenerFindings reports Ingress hosts that no gateway listener accepts.
+func appGWListenerFindings(backends []appGWBackend, gw *azure.AppGateway) []string {
+	catchAll := false
+	hosts := make(map[string]bool)
+	for _, l := range gw.Listeners {
+		if len(l.HostNames) == 0 {
+			catchAll = true
+		}
+		for _, h := range l.HostNames {
+			hosts[strings.ToLower(h)] = true
+		}
+	}
+	if catchAll {
+		return nil
+	}
+	missing := make(map[string]bool)
+	ingressFor := make(map[string]string)
+	for _, b := range backends {
+		host := strings.ToLower(b.Host)
+		if host == "*" || hosts[host] {
+			continue
+		}
+		if _, parent, ok := strings.Cut(host, "."); ok && hosts["*."+parent] {
+			continue
+		}
+		missing[host] = true
+		ingressFor[host] = b.Ingress
+	}
+	var findingLines []string
+	for _, h := range sortedKeys(missing) {
+		findingLines = append(findingLines, fmt.Sprintf("No gateway listener accepts host %s (Ingress %s); the gateway rejects these requests before they reach the cluster.", h, ingressFor[h]))
+	}
+	return findingLines
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/azure"
+)
+
+func TestAppGatewayIDFromConfig(t *testing.T) {
+	id, ok := appGatewayIDFromConfig(map[string]string{
+		"APPGW_RESOURCE_ID": "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/applicationGateways/appgw",
+	})
+	if !ok || id.Name != "appgw" || id.ResourceGroup != "rg" {
+		t.Errorf("APPGW_RESOURCE_ID: got %+v, %v", id, ok)
+	}
+	id, ok = appGatewayIDFromConfig(map[string]string{"APPGW_SUBSCRIPTION_ID": "s", "APPGW_RESOURCE_GROUP": "rg", "APPGW_NAME": "appgw"})
+	if !ok || id.SubscriptionID != "s" {
+		t.Errorf("APPGW_* triplet: got %+v, %v", id, ok)
+	}
+	if _, ok := appGatewayIDFromConfig(map[string]string{"APPGW_NAME": "appgw"}); ok {
+		t.Error("expected no ID without subscription and resource group")
+	}
+}
+
+func TestCrossCheckAppGateway(t *testing.T) {
+	gw := &azure.AppGateway{BackendPools: []azure.BackendPool{
+		{Name: "pool-shop-web-80", Addresses: []string{"10.0.0.1", "10.0.0.2"}},
+		{Name: "pool-shop-api-80", Addresses: []string{"10.0.1.1", "10.0.1.9"}},
+		{Name: "pool-shop-cart-80", Addresses: []string{"10.0.2.1"}},
+	}}
+	health := &azure.BackendHealth{Pools: []azure.PoolHealth{
+		{Pool: "pool-shop-web-80", Servers: []azure.ServerHealth{
+			{Address: "10.0.0.1", Health: "Unhealthy", ProbeLog: "Received invalid status code: 404"},
+			{Address: "10.0.0.2", Health: "Unhealthy", ProbeLog: "Received invalid status code: 404"},
+		}},
+		{Pool: "pool-shop-api-80", Servers: []azure.ServerHealth{{Address: "10.0.1.1", Health: "Healthy"}, {Address: "10.0.1.9", Health: "Unhealthy"}}},
+		{Pool: "pool-shop-cart-80", Servers: []azure.ServerHealth{{Address: "10.0.2.1", Health: "Healthy"}}},
+	}}
+	backends := []appGWBackend{
+		{Ingress: "shop/web", Host: "shop.example.com", Path: "/", Service: "shop/web", ReadyIPs: []string{"10.0.0.1", "10.0.0.2"}},
+		{Ingress: "shop/web", Host: "shop.example.com", Path: "/api", Service: "shop/api", ReadyIPs: []string{"10.0.1.1"}},
+		{Ingress: "shop/web", Host: "shop.example.com", Path: "/cart", Service: "shop/cart", ReadyIPs: []string{"10.0.2.1"}},
+		{Ingress: "shop/web", Host: "shop.example.com", Path: "/search", Service: "shop/search", NotReadyIPs: []string{"10.0.3.1"}},
+		{Ingress: "shop/web", Host: "shop.example.com", Path: "/new", Service: "shop/new", ReadyIPs: []string{"10.0.4.1"}},
+		{Ingress: "shop/web", Host: "shop.example.com", Path: "/old", Service: "shop/old", Missing: true},
+	}
+
+	verdicts := crossCheckAppGateway(backends, gw, health)
+	want := []struct{ origin, severity, message string }{
+		{originProbe, "CRITICAL", "fails for 2 of 2 pods of shop/web that Kubernetes reports ready: Received invalid status code: 404"},
+		{originSync, "WARNING", "still lists 1 address(es) that are no longer ready pods of shop/api (10.0.1.9)"},
+		{"", "", ""},
+		{originCluster, "CRITICAL", "has no ready endpoints (1 not ready)"},
+		{originSync, "CRITICAL", "None of the 1 ready pod IPs of shop/new are in any gateway backend pool"},
+		{originCluster, "CRITICAL", "which does not exist"},
+	}
+	if len(verdicts) != len(want) {
+		t.Fatalf("crossCheckAppGateway() returned %d verdicts, want %d", len(verdicts), len(want))
+	}
+	for i, w := range want {
+		v := verdicts[i]
+		if v.Origin != w.origin || v.Severity != w.severity || !strings.Contains(v.Message, w.message) {
+			t.Errorf("%s: got origin %q severity %q message %q; want %q %q containing %q", v.Backend.Service, v.Origin, v.Severity, v.Message, w.origin, w.severity, w.message)
+		}
+	}
+
+	// Without backend health only pool membership is compared.
+	if v := crossCheckAppGateway(backends[:1], gw, nil)[0]; v.Origin != "" || v.InPool != 2 {
+		t.Errorf("without health: got origin %q in pool %d, want no problem and 2 in pool", v.Origin, v.InPool)
+	}
+}
+
+func TestAppGWListenerFindings(t *testing.T) {
+	gw := &azure.AppGateway{Listeners: []azure.Listener{
+		{Name: "fl-shop", HostNames: []string{"shop.example.com"}},
+		{Name: "fl-wildcard", HostNames: []string{"*.apps.example.com"}},
+	}}
+	backends := []appGWBackend{
+		{Ingress: "shop/web", Host: "shop.example.com"},
+		{Ingress: "shop/admin", Host: "admin.apps.example.com"},
+		{Ingress: "shop/blog", Host: "blog.example.com"},
+	}
+
+	got := appGWListenerFindings(backends, gw)
+	if len(got) != 1 || !strings.Contains(got[0], "host blog.example.com (Ingress shop/blog)") {
+		t.Errorf("appGWListenerFindings() = %q, want only blog.example.com", got)
+	}
+
+	gw.Listeners = append(gw.Listeners, azure.Listener{Name: "fl-default"})
+	if got := appGWListenerFindings(backends, gw); len(got) != 0 {
+		t.Errorf("appGWListenerFindings() with a catch-all listener = %q, want none", got)
+	}
+}
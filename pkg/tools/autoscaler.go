@@ -0,0 +1,366 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// clusterAutoscalerStatusConfigMap is the status ConfigMap cluster-autoscaler
+// writes to kube-system, including when it runs as a managed component (AKS, GKE).
+const clusterAutoscalerStatusConfigMap = "cluster-autoscaler-status"
+
+type checkAutoscalerHealthInput struct {
+	TailLines int64 `json:"tail_lines,omitempty" jsonschema:"Autoscaler log lines to scan per pod (default 500)"`
+}
+
+// autoscalerLogPattern maps a log substring to a scale-up failure category.
+type autoscalerLogPattern struct {
+	Substring string
+	Category  string
+}
+
+// autoscalerType describes how to detect one kind of node autoscaler.
+type autoscalerType struct {
+	Key          string
+	DisplayName  string
+	PodSelectors []string
+	LogPatterns  []autoscalerLogPattern
+}
+
+// Scale-up failure categories reported by check_autoscaler_health.
+const (
+	autoscalerCategoryQuota    = "quota exceeded"
+	autoscalerCategoryNoGroup  = "no matching node group / NodePool"
+	autoscalerCategoryMaxSize  = "max size reached"
+	autoscalerCategoryCapacity = "cloud capacity / SKU unavailable"
+	autoscalerCategoryFailed   = "scale-up failed"
+)
+
+// knownAutoscalers lists the node autoscalers kube-doctor can detect.
+var knownAutoscalers = []autoscalerType{
+	{
+		Key:          "cluster-autoscaler",
+		DisplayName:  "Cluster Autoscaler",
+		PodSelectors: []string{"app=cluster-autoscaler", "app.kubernetes.io/name=cluster-autoscaler", "k8s-app=cluster-autoscaler"},
+		LogPatterns: []autoscalerLogPattern{
+			{"QuotaExceeded", autoscalerCategoryQuota},
+			{"quota exceeded", autoscalerCategoryQuota},
+			{"exceeding approved", autoscalerCategoryQuota},
+			{"max node group size reached", autoscalerCategoryMaxSize},
+			{"max cluster cpu", autoscalerCategoryMaxSize},
+			{"max cluster memory", autoscalerCategoryMaxSize},
+			{"no node group", autoscalerCategoryNoGroup},
+			{"didn't match", autoscalerCategoryNoGroup},
+			{"node(s) didn't match", autoscalerCategoryNoGroup},
+			{"SkuNotAvailable", autoscalerCategoryCapacity},
+			{"ZonalAllocationFailed", autoscalerCategoryCapacity},
+			{"AllocationFailed", autoscalerCategoryCapacity},
+			{"OutOfResource", autoscalerCategoryCapacity},
+			{"Failed to increase node group size", autoscalerCategoryFailed},
+			{"Scale-up failed", autoscalerCategoryFailed},
+			{"Failed to fix node group sizes", autoscalerCategoryFailed},
+		},
+	},
+	{
+		Key:          "karpenter",
+		DisplayName:  "Karpenter",
+		PodSelectors: []string{"app.kubernetes.io/name=karpenter"},
+		LogPatterns: []autoscalerLogPattern{
+			{"QuotaExceeded", autoscalerCategoryQuota},
+			{"quota", autoscalerCategoryQuota},
+			{"incompatible with nodepool", autoscalerCategoryNoGroup},
+			{"no instance type", autoscalerCategoryNoGroup},
+			{"did not tolerate", autoscalerCategoryNoGroup},
+			{"exceeds nodepool limits", autoscalerCategoryMaxSize},
+			{"all available instance types exceed limits", autoscalerCategoryMaxSize},
+			{"InsufficientCapacity", autoscalerCategoryCapacity},
+			{"insufficient capacity", autoscalerCategoryCapacity},
+			{"SkuNotAvailable", autoscalerCategoryCapacity},
+			{"AllocationFailed", autoscalerCategoryCapacity},
+			{"could not schedule pod", autoscalerCategoryFailed},
+			{"failed launching nodeclaim", autoscalerCategoryFailed},
+			{"launching nodeclaim", autoscalerCategoryFailed},
+		},
+	},
+}
+
+// autoscalerLogSummary counts categorized scale-up failures from autoscaler logs.
+type autoscalerLogSummary struct {
+	Counts  map[string]int
+	Samples map[string]string
+}
+
+func registerAutoscalerTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// check_autoscaler_health
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "check_autoscaler_health",
+		Description: "Detect cluster-autoscaler or Karpenter (including managed AKS/GKE autoscalers via the cluster-autoscaler-status ConfigMap), check autoscaler pods, parse recent logs for scale-up failures (quota exceeded, no matching node group, max size reached, capacity unavailable), and correlate unschedulable pods with autoscaler decisions from their events.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkAutoscalerHealthInput) (*mcp.CallToolResult, any, error) {
+		tailLines := input.TailLines
+		if tailLines <= 0 {
+			tailLines = 500
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Node Autoscaler Health"))
+		sb.WriteString("\n\n")
+
+		findingCount := 0
+		var actions []string
+
+		// --- Detection ---
+		sb.WriteString(util.FormatSubHeader("Detected Autoscalers"))
+		sb.WriteString("\n")
+		status, statusErr := client.GetConfigMap(ctx, "kube-system", clusterAutoscalerStatusConfigMap)
+		detected := 0
+		summary := autoscalerLogSummary{Counts: map[string]int{}, Samples: map[string]string{}}
+		for _, as := range knownAutoscalers {
+			pods, err := findPodsBySelectors(ctx, client, as.PodSelectors)
+			if err != nil {
+				return util.HandleK8sError("searching for autoscaler pods", err), nil, nil
+			}
+			managed := as.Key == "cluster-autoscaler" && statusErr == nil
+			if len(pods) == 0 && !managed {
+				continue
+			}
+			detected++
+			if len(pods) == 0 {
+				sb.WriteString(fmt.Sprintf("  %s: managed by the cloud provider (status ConfigMap present, no pods visible)\n", as.DisplayName))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("  %s: %d pod(s)\n", as.DisplayName, len(pods)))
+			for i := range pods {
+				p := &pods[i]
+				_, _, restarts := podContainerSummary(p)
+				if !isPodHealthy(p) {
+					sb.WriteString(findings.Format("KD-SYS-005", "CRITICAL", fmt.Sprintf("%s pod %s/%s is not healthy: %s", as.DisplayName, p.Namespace, p.Name, podPhaseReason(p))))
+					sb.WriteString("\n")
+					findingCount++
+					actions = append(actions, fmt.Sprintf("Investigate %s pod %s (use diagnose_pod) — no nodes will be added while it is down", as.DisplayName, p.Name))
+				} else if restarts > util.HighRestartThreshold {
+					sb.WriteString(findings.Format("KD-SYS-005", "WARNING", fmt.Sprintf("%s pod %s has restarted %d times", as.DisplayName, p.Name, restarts)))
+					sb.WriteString("\n")
+					findingCount++
+				}
+				if p.Status.Phase != corev1.PodRunning || len(p.Spec.Containers) == 0 {
+					continue
+				}
+				logs, logErr := client.GetPodLogs(ctx, p.Namespace, p.Name, p.Spec.Containers[0].Name, tailLines, false, "")
+				if logErr == nil {
+					categorizeAutoscalerLogs(logs, as.LogPatterns, &summary)
+				}
+			}
+		}
+		if detected == 0 {
+			sb.WriteString(findings.Format("KD-SYS-005", "INFO", "No cluster-autoscaler or Karpenter detected — node count is fixed unless scaled manually"))
+			sb.WriteString("\n")
+		}
+
+		// --- Managed status ConfigMap ---
+		if statusErr == nil {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("Cluster Autoscaler Status"))
+			sb.WriteString("\n")
+			for _, line := range parseAutoscalerStatus(status.Data["status"]) {
+				sb.WriteString(fmt.Sprintf("  %s\n", line))
+				if strings.Contains(line, "Health:") && !strings.Contains(line, "Healthy") {
+					sb.WriteString(findings.Format("KD-SYS-005", "CRITICAL", "Cluster autoscaler reports it is not healthy"))
+					sb.WriteString("\n")
+					findingCount++
+				}
+				if strings.Contains(line, "ScaleUp:") && strings.Contains(line, "Backoff") {
+					sb.WriteString(findings.Format("KD-SYS-005", "WARNING", "A node group is in scale-up backoff after failed attempts"))
+					sb.WriteString("\n")
+					findingCount++
+					actions = append(actions, "Check cloud provider quota and SKU availability for node groups in backoff")
+				}
+			}
+		}
+
+		// --- Log analysis ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Scale-Up Failures in Logs"))
+		sb.WriteString("\n")
+		if len(summary.Counts) == 0 {
+			sb.WriteString("  No scale-up failures in scanned logs.\n")
+		} else {
+			categories := make([]string, 0, len(summary.Counts))
+			for c := range summary.Counts {
+				categories = append(categories, c)
+			}
+			sort.Strings(categories)
+			for _, c := range categories {
+				sb.WriteString(findings.Format("KD-SYS-005", "WARNING", fmt.Sprintf("%s: %d log line(s)", c, summary.Counts[c])))
+				sb.WriteString("\n")
+				sb.WriteString(fmt.Sprintf("    e.g. %s\n", truncateName(summary.Samples[c], 200)))
+				findingCount++
+				actions = append(actions, autoscalerCategoryAction(c))
+			}
+		}
+
+		// --- Unschedulable pods ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Unschedulable Pods"))
+		sb.WriteString("\n")
+		pods, err := client.ListPods(ctx, "", metav1.ListOptions{FieldSelector: "status.phase=Pending"})
+		if err != nil {
+			return util.HandleK8sError("listing pending pods", err), nil, nil
+		}
+		headers := []string{"POD", "NAMESPACE", "AGE", "AUTOSCALER DECISION"}
+		var rows [][]string
+		for i := range pods {
+			p := &pods[i]
+			if !isPodUnschedulable(p) {
+				continue
+			}
+			decision := "no autoscaler event"
+			if events, evErr := client.GetEventsForObject(ctx, p.Namespace, p.Name); evErr == nil {
+				decision = autoscalerDecision(events)
+			}
+			rows = append(rows, []string{p.Name, p.Namespace, util.FormatAge(p.CreationTimestamp.Time), truncateName(decision, 90)})
+		}
+		if len(rows) == 0 {
+			sb.WriteString("  No unschedulable pods.\n")
+		} else {
+			sb.WriteString(util.FormatTable(headers, rows))
+			sb.WriteString(findings.Format("KD-POD-004", "WARNING", fmt.Sprintf("%d pod(s) unschedulable", len(rows))))
+			sb.WriteString("\n")
+			findingCount++
+			if detected == 0 {
+				actions = append(actions, "Enable a node autoscaler or add nodes for the unschedulable pods")
+			} else {
+				actions = append(actions, "For pods with NotTriggerScaleUp, compare their nodeSelector/affinity/tolerations with the node groups the autoscaler manages")
+			}
+		}
+
+		// --- Overall ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
+		sb.WriteString("\n")
+		if findingCount == 0 {
+			sb.WriteString("  Node autoscaling appears healthy. No issues found.\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findingCount))
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// findPodsBySelectors returns the pods in all namespaces matching any of the selectors.
+func findPodsBySelectors(ctx context.Context, client *k8s.ClusterClient, selectors []string) ([]corev1.Pod, error) {
+	var out []corev1.Pod
+	seen := make(map[string]bool)
+	for _, sel := range selectors {
+		pods, err := client.ListPods(ctx, "", metav1.ListOptions{LabelSelector: sel})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range pods {
+			key := p.Namespace + "/" + p.Name
+			if !seen[key] {
+				seen[key] = true
+				out = append(out, p)
+			}
+		}
+	}
+	return out, nil
+}
+
+// categorizeAutoscalerLogs counts log lines matching known scale-up failure patterns.
+func categorizeAutoscalerLogs(logs string, patterns []autoscalerLogPattern, summary *autoscalerLogSummary) {
+	for _, line := range strings.Split(logs, "\n") {
+		lower := strings.ToLower(line)
+		for _, p := range patterns {
+			if strings.Contains(lower, strings.ToLower(p.Substring)) {
+				summary.Counts[p.Category]++
+				if _, ok := summary.Samples[p.Category]; !ok {
+					summary.Samples[p.Category] = strings.TrimSpace(line)
+				}
+				break
+			}
+		}
+	}
+}
+
+// parseAutoscalerStatus extracts the Health and ScaleUp/ScaleDown lines from
+// the cluster-autoscaler-status ConfigMap, which is YAML in newer versions and
+// free text in older ones.
+func parseAutoscalerStatus(status string) []string {
+	var out []string
+	for _, line := range strings.Split(status, "\n") {
+		t := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(t, "Cluster-wide:"), strings.HasPrefix(t, "NodeGroups:"):
+			out = append(out, t)
+		case strings.HasPrefix(t, "Name:"), strings.HasPrefix(t, "Health:"),
+			strings.HasPrefix(t, "ScaleUp:"), strings.HasPrefix(t, "ScaleDown:"):
+			out = append(out, "  "+t)
+		case strings.HasPrefix(t, "health:"), strings.HasPrefix(t, "status:") && strings.Contains(t, "Backoff"):
+			out = append(out, "  "+t)
+		}
+	}
+	if len(out) > 40 {
+		out = append(out[:40], fmt.Sprintf("... %d more lines", len(out)-40))
+	}
+	return out
+}
+
+// isPodUnschedulable reports whether the scheduler marked a pod unschedulable.
+func isPodUnschedulable(p *corev1.Pod) bool {
+	for _, c := range p.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+			return true
+		}
+	}
+	return false
+}
+
+// autoscalerDecision summarizes the most recent autoscaler event on a pod.
+func autoscalerDecision(events []corev1.Event) string {
+	for _, e := range events {
+		switch e.Reason {
+		case "TriggeredScaleUp":
+			return "scale-up triggered: " + e.Message
+		case "NotTriggerScaleUp":
+			return "NOT scaling up: " + e.Message
+		case "Nominated":
+			return "Karpenter nominated: " + e.Message
+		}
+		if e.Source.Component == "karpenter" {
+			return "Karpenter: " + e.Message
+		}
+	}
+	return "no autoscaler event"
+}
+
+// autoscalerCategoryAction returns the suggested action for a failure category.
+func autoscalerCategoryAction(category string) string {
+	switch category {
+	case autoscalerCategoryQuota:
+		return "Request a cloud quota increase for the VM family / region used by the node groups"
+	case autoscalerCategoryNoGroup:
+		return "Pending pods' nodeSelector, affinity, or tolerations match no node group / NodePool — add one or relax the constraints"
+	case autoscalerCategoryMaxSize:
+		return "Raise the max node count of the saturated node group / NodePool limits"
+	case autoscalerCategoryCapacity:
+		return "Allow more VM sizes or zones for the node group — the requested SKU is out of capacity"
+	}
+	return "Review autoscaler logs for the failed scale-up attempts"
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCategorizeAutoscalerLogs(t *testing.T) {
+	logs := strings.Join([]string{
+		`I0101 scale_up.go:300] Pod default/web-1 is unschedulable`,
+		`W0101 scale_up.go:410] Failed to increase node group size: QuotaExceeded: Operation could not be completed as it results in exceeding approved standardDSv3Family Cores quota`,
+		`W0101 scale_up.go:420] Scale-up: max node group size reached for nodepool1`,
+		`W0101 orchestrator.go:120] Failed to increase node group size: SkuNotAvailable`,
+		`E0101 static_autoscaler.go:500] Failed to increase node group size: timeout`,
+	}, "\n")
+
+	var ca autoscalerType
+	for _, as := range knownAutoscalers {
+		if as.Key == "cluster-autoscaler" {
+			ca = as
+		}
+	}
+	summary := autoscalerLogSummary{Counts: map[string]int{}, Samples: map[string]string{}}
+	categorizeAutoscalerLogs(logs, ca.LogPatterns, &summary)
+
+	want := map[string]int{
+		autoscalerCategoryQuota:    1,
+		autoscalerCategoryMaxSize:  1,
+		autoscalerCategoryCapacity: 1,
+		autoscalerCategoryFailed:   1,
+	}
+	for category, count := range want {
+		if summary.Counts[category] != count {
+			t.Errorf("Counts[%q] = %d, want %d (all: %v)", category, summary.Counts[category], count, summary.Counts)
+		}
+	}
+	if !strings.Contains(summary.Samples[autoscalerCategoryQuota], "standardDSv3Family") {
+		t.Errorf("expected quota sample line, got %q", summary.Samples[autoscalerCategoryQuota])
+	}
+}
+
+func TestAutoscalerDecision(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []corev1.Event
+		want   string
+	}{
+		{
+			name:   "triggered",
+			events: []corev1.Event{{Reason: "FailedScheduling"}, {Reason: "TriggeredScaleUp", Message: "pod triggered scale-up: [{pool1 1->2 (max: 5)}]"}},
+			want:   "scale-up triggered",
+		},
+		{
+			name:   "not triggered",
+			events: []corev1.Event{{Reason: "NotTriggerScaleUp", Message: "pod didn't trigger scale-up: 1 node(s) didn't match Pod's node affinity"}},
+			want:   "NOT scaling up",
+		},
+		{
+			name:   "karpenter",
+			events: []corev1.Event{{Reason: "Nominated", Message: "Pod should schedule on: nodeclaim/default-abc"}},
+			want:   "Karpenter nominated",
+		},
+		{
+			name:   "none",
+			events: []corev1.Event{{Reason: "FailedScheduling"}},
+			want:   "no autoscaler event",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := autoscalerDecision(tt.events); !strings.HasPrefix(got, tt.want) {
+				t.Errorf("autoscalerDecision() = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPodUnschedulable(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{
+		Phase: corev1.PodPending,
+		Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable},
+		},
+	}}
+	if !isPodUnschedulable(pod) {
+		t.Error("expected pod to be unschedulable")
+	}
+	pod.Status.Conditions[0].Status = corev1.ConditionTrue
+	if isPodUnschedulable(pod) {
+		t.Error("expected scheduled pod not to be unschedulable")
+	}
+}
@@ -0,0 +1,384 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// capacityPlanMaxExtra caps how far the simulation counts the replicas that
+// would still fit after the plan.
+const capacityPlanMaxExtra = 1000
+
+// capacityPlanNode is one node's free room in a scheduling simulation.
+type capacityPlanNode struct {
+	Name     string
+	AllocCPU int64
+	AllocMem int64
+	FreeCPU  int64
+	FreeMem  int64
+	FreePods int64
+	Replicas int    // replicas of the planned workload already on the node
+	Blocked  string // why the node cannot take the workload at all, or ""
+	Added    int
+}
+
+// capacityPlan is the outcome of simulating extra replicas of a workload.
+type capacityPlan struct {
+	Nodes     []capacityPlanNode
+	CPU       int64 // per-replica CPU request in millicores
+	Mem       int64 // per-replica memory request in bytes
+	Requested int
+	Placed    int
+	MoreAfter int
+	// Unschedulable tallies why each node rejected the first replica that did
+	// not fit, worded like the scheduler's FailedScheduling message.
+	Unschedulable map[string]int
+}
+
+// writeCapacityPlan simulates adding replicas of the input's plan workload and
+// writes the plan section. It returns a finding when the plan does not fit,
+// or an error result when the workload cannot be resolved.
+func writeCapacityPlan(ctx context.Context, sb *strings.Builder, client *k8s.ClusterClient, input analyzeNodeCapacityInput, nodes []corev1.Node, pods []corev1.Pod) (string, *mcp.CallToolResult) {
+	if input.PlanNamespace == "" || input.PlanAddReplicas <= 0 {
+		return "", util.ErrorResult("plan_workload needs plan_namespace and a positive plan_add_replicas")
+	}
+	if k := strings.ToLower(input.PlanKind); k == "daemonset" || k == "ds" {
+		return "", util.ErrorResult("plan_kind DaemonSet is not supported: DaemonSets run one pod per node rather than a replica count")
+	}
+	kind, selector, template, err := workloadPodSelector(ctx, client, input.PlanNamespace, input.PlanWorkload, input.PlanKind)
+	if err != nil {
+		return "", util.HandleK8sError(fmt.Sprintf("getting %s %s/%s", kind, input.PlanNamespace, input.PlanWorkload), err)
+	}
+	sel, err := labels.Parse(selector)
+	if err != nil || selector == "" {
+		sel = labels.Nothing()
+	}
+	limitRanges, err := client.ListLimitRanges(ctx, input.PlanNamespace, metav1.ListOptions{})
+	if err == nil {
+		template.Spec = *applyLimitRangeDefaults(&template.Spec, limitRanges)
+	}
+
+	plan := simulateCapacityPlan(nodes, pods, input.PlanNamespace, template, sel, input.PlanAddReplicas)
+
+	name := fmt.Sprintf("%s %s/%s", kind, input.PlanNamespace, input.PlanWorkload)
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatSubHeader("Capacity Plan"))
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatKeyValue("Plan", fmt.Sprintf("add %d replicas of %s", input.PlanAddReplicas, name)) + "\n")
+	sb.WriteString(util.FormatKeyValue("Per Replica", fmt.Sprintf("cpu %dm, memory %s (requests, LimitRange defaults applied)", plan.CPU, formatBytes(plan.Mem))) + "\n")
+
+	var rows [][]string
+	for _, n := range plan.Nodes {
+		if n.Blocked != "" {
+			continue
+		}
+		added := "-"
+		if n.Added > 0 {
+			added = fmt.Sprintf("+%d", n.Added)
+		}
+		rows = append(rows, []string{n.Name, fmt.Sprintf("%d", n.Replicas-n.Added), added, fmt.Sprintf("%dm", n.FreeCPU), formatBytes(n.FreeMem), fmt.Sprintf("%d", n.FreePods)})
+	}
+	if len(rows) > 0 {
+		sb.WriteString(util.FormatTable([]string{"NODE", "EXISTING", "NEW", "CPU HEADROOM AFTER", "MEM HEADROOM AFTER", "POD SLOTS AFTER"}, rows))
+	}
+	blocked := make(map[string]int)
+	for _, n := range plan.Nodes {
+		if n.Blocked != "" {
+			blocked[n.Blocked]++
+		}
+	}
+	if len(blocked) > 0 {
+		sb.WriteString(fmt.Sprintf("  Ineligible nodes: %s\n", formatSchedulingTally(blocked)))
+	}
+
+	var finding string
+	if plan.Placed == plan.Requested {
+		sb.WriteString("  " + util.FormatFinding("OK", fmt.Sprintf("Fits: all %d replicas can be scheduled; about %s more would fit afterwards.", plan.Requested, formatPlanExtra(plan.MoreAfter))) + "\n")
+	} else {
+		finding = findings.Format("KD-NODE-005", "CRITICAL", fmt.Sprintf("Capacity plan does not fit: only %d of %d new replicas of %s can be scheduled; the rest stay Pending (0/%d nodes are available: %s).",
+			plan.Placed, plan.Requested, name, len(plan.Nodes), formatSchedulingTally(plan.Unschedulable)))
+		sb.WriteString("  " + finding + "\n")
+	}
+	if plan.CPU == 0 && plan.Mem == 0 {
+		sb.WriteString("  " + findings.Format("KD-RES-002", "WARNING", "The pods request no CPU or memory, so the scheduler places them regardless of load; this plan only checks pod slots.") + "\n")
+	}
+	sb.WriteString("  (approximates the default scheduler: spreads replicas across nodes, then prefers the least-allocated node; taints, nodeSelector, required node affinity, and required hostname anti-affinity are honored)\n")
+	return finding, nil
+}
+
+// simulateCapacityPlan places add replicas of the template one at a time
+// against the free requests of each node.
+func simulateCapacityPlan(nodes []corev1.Node, pods []corev1.Pod, namespace string, template corev1.PodTemplateSpec, selector labels.Selector, add int) capacityPlan {
+	usage := podQuotaUsage(&template.Spec)
+	cpu, mem := usage[corev1.ResourceRequestsCPU], usage[corev1.ResourceRequestsMemory]
+	plan := capacityPlan{CPU: cpu.MilliValue(), Mem: mem.Value(), Requested: add}
+
+	byName := make(map[string]int, len(nodes))
+	for i := range nodes {
+		n := &nodes[i]
+		byName[n.Name] = len(plan.Nodes)
+		plan.Nodes = append(plan.Nodes, capacityPlanNode{
+			Name:     n.Name,
+			AllocCPU: n.Status.Allocatable.Cpu().MilliValue(),
+			AllocMem: n.Status.Allocatable.Memory().Value(),
+			FreeCPU:  n.Status.Allocatable.Cpu().MilliValue(),
+			FreeMem:  n.Status.Allocatable.Memory().Value(),
+			FreePods: n.Status.Allocatable.Pods().Value(),
+			Blocked:  nodeSchedulingBlock(n, &template.Spec),
+		})
+	}
+	for i := range pods {
+		p := &pods[i]
+		idx, ok := byName[p.Spec.NodeName]
+		if !ok || p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		u := podQuotaUsage(&p.Spec)
+		c, m := u[corev1.ResourceRequestsCPU], u[corev1.ResourceRequestsMemory]
+		n := &plan.Nodes[idx]
+		n.FreeCPU -= c.MilliValue()
+		n.FreeMem -= m.Value()
+		n.FreePods--
+		if p.Namespace == namespace && selector.Matches(labels.Set(p.Labels)) {
+			n.Replicas++
+		}
+	}
+
+	antiAffinity := requiresHostnameAntiAffinity(template)
+	plan.Placed, plan.Unschedulable = placeReplicas(plan.Nodes, plan.CPU, plan.Mem, antiAffinity, add)
+
+	if plan.Placed == add {
+		extra := append([]capacityPlanNode(nil), plan.Nodes...)
+		plan.MoreAfter, _ = placeReplicas(extra, plan.CPU, plan.Mem, antiAffinity, capacityPlanMaxExtra)
+	}
+	return plan
+}
+
+// placeReplicas places up to n replicas, mutating the nodes' free room. It
+// returns how many were placed and, when it ran out of room, why each node
+// rejected the next replica.
+func placeReplicas(nodes []capacityPlanNode, cpu, mem int64, antiAffinity bool, n int) (int, map[string]int) {
+	for placed := 0; placed < n; placed++ {
+		best := -1
+		for i := range nodes {
+			if nodeRejectsReplica(&nodes[i], cpu, mem, antiAffinity) != "" {
+				continue
+			}
+			if best < 0 || betterPlacement(&nodes[i], &nodes[best], cpu, mem) {
+				best = i
+			}
+		}
+		if best < 0 {
+			reasons := make(map[string]int)
+			for i := range nodes {
+				reasons[nodeRejectsReplica(&nodes[i], cpu, mem, antiAffinity)]++
+			}
+			return placed, reasons
+		}
+		nodes[best].FreeCPU -= cpu
+		nodes[best].FreeMem -= mem
+		nodes[best].FreePods--
+		nodes[best].Replicas++
+		nodes[best].Added++
+	}
+	return n, nil
+}
+
+// nodeRejectsReplica returns the scheduler-style reason the node cannot take
+// one more replica, or "".
+func nodeRejectsReplica(n *capacityPlanNode, cpu, mem int64, antiAffinity bool) string {
+	switch {
+	case n.Blocked != "":
+		return n.Blocked
+	case n.FreePods < 1:
+		return "Too many pods"
+	case antiAffinity && n.Replicas > 0:
+		return "node(s) didn't match pod anti-affinity rules"
+	case n.FreeCPU < cpu:
+		return "Insufficient cpu"
+	case n.FreeMem < mem:
+		return "Insufficient memory"
+	}
+	return ""
+}
+
+// betterPlacement reports whether a is a better node than b for the next
+// replica: fewer replicas of the workload first, then the most room left.
+func betterPlacement(a, b *capacityPlanNode, cpu, mem int64) bool {
+	if a.Replicas != b.Replicas {
+		return a.Replicas < b.Replicas
+	}
+	sa, sb := leastAllocatedScore(a, cpu, mem), leastAllocatedScore(b, cpu, mem)
+	if sa != sb {
+		return sa > sb
+	}
+	return a.Name < b.Name
+}
+
+func leastAllocatedScore(n *capacityPlanNode, cpu, mem int64) float64 {
+	var score float64
+	if n.AllocCPU > 0 {
+		score += float64(n.FreeCPU-cpu) / float64(n.AllocCPU)
+	}
+	if n.AllocMem > 0 {
+		score += float64(n.FreeMem-mem) / float64(n.AllocMem)
+	}
+	return score / 2
+}
+
+// nodeSchedulingBlock returns why a node can never take pods with this spec
+// (not ready, cordoned, untolerated taint, selector or affinity mismatch), or "".
+func nodeSchedulingBlock(n *corev1.Node, spec *corev1.PodSpec) string {
+	if nodeStatus(n) != "Ready" {
+		return "node(s) were not ready"
+	}
+	if n.Spec.Unschedulable {
+		return "node(s) were unschedulable"
+	}
+	for i := range n.Spec.Taints {
+		taint := &n.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range spec.Tolerations {
+			if toleratesTaint(&spec.Tolerations[j], taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return fmt.Sprintf("node(s) had untolerated taint {%s: %s}", taint.Key, taint.Value)
+		}
+	}
+	for k, v := range spec.NodeSelector {
+		if n.Labels[k] != v {
+			return "node(s) didn't match Pod's node affinity/selector"
+		}
+	}
+	if a := spec.Affinity; a != nil && a.NodeAffinity != nil && a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		matched := false
+		for _, term := range a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			if nodeSelectorTermMatches(term, n.Labels) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return "node(s) didn't match Pod's node affinity/selector"
+		}
+	}
+	return ""
+}
+
+// toleratesTaint reports whether the toleration matches the taint's key,
+// value, and effect; an empty key with operator Exists tolerates everything.
+func toleratesTaint(t *corev1.Toleration, taint *corev1.Taint) bool {
+	if t.Effect != "" && t.Effect != taint.Effect {
+		return false
+	}
+	if t.Key != "" && t.Key != taint.Key {
+		return false
+	}
+	switch t.Operator {
+	case corev1.TolerationOpExists:
+		return true
+	case "", corev1.TolerationOpEqual:
+		return t.Key != "" && t.Value == taint.Value
+	}
+	return false
+}
+
+// nodeSelectorTermMatches evaluates a node selector term's label expressions.
+// Field expressions (metadata.name) are treated as matching.
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, nodeLabels map[string]string) bool {
+	for _, req := range term.MatchExpressions {
+		value, exists := nodeLabels[req.Key]
+		switch req.Operator {
+		case corev1.NodeSelectorOpIn:
+			if !exists || !slices.Contains(req.Values, value) {
+				return false
+			}
+		case corev1.NodeSelectorOpNotIn:
+			if exists && slices.Contains(req.Values, value) {
+				return false
+			}
+		case corev1.NodeSelectorOpExists:
+			if !exists {
+				return false
+			}
+		case corev1.NodeSelectorOpDoesNotExist:
+			if exists {
+				return false
+			}
+		case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+			if !exists || len(req.Values) != 1 {
+				return false
+			}
+			have, err1 := strconv.ParseInt(value, 10, 64)
+			want, err2 := strconv.ParseInt(req.Values[0], 10, 64)
+			if err1 != nil || err2 != nil {
+				return false
+			}
+			if (req.Operator == corev1.NodeSelectorOpGt && have <= want) || (req.Operator == corev1.NodeSelectorOpLt && have >= want) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// requiresHostnameAntiAffinity reports whether the template forbids two of its
+// own replicas on one node.
+func requiresHostnameAntiAffinity(template corev1.PodTemplateSpec) bool {
+	a := template.Spec.Affinity
+	if a == nil || a.PodAntiAffinity == nil {
+		return false
+	}
+	for _, term := range a.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		if term.TopologyKey == "kubernetes.io/hostname" && term.LabelSelector != nil && labelSelectorMatches(term.LabelSelector, template.Labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatSchedulingTally renders reason counts like the scheduler does:
+// "3 Insufficient cpu, 2 node(s) were unschedulable".
+func formatSchedulingTally(tally map[string]int) string {
+	reasons := make([]string, 0, len(tally))
+	for r := range tally {
+		reasons = append(reasons, r)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if tally[reasons[i]] != tally[reasons[j]] {
+			return tally[reasons[i]] > tally[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	parts := make([]string, 0, len(reasons))
+	for _, r := range reasons {
+		parts = append(parts, fmt.Sprintf("%d %s", tally[r], r))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatPlanExtra(n int) string {
+	if n >= capacityPlanMaxExtra {
+		return fmt.Sprintf("%d+", capacityPlanMaxExtra)
+	}
+	return fmt.Sprintf("%d", n)
+}
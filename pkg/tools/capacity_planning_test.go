@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func planTestTemplate(cpu string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse("256Mi")},
+		}}}},
+	}
+}
+
+func TestSimulateCapacityPlan(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	sel := labels.SelectorFromSet(web)
+	nodes := []corev1.Node{testNode("n1", "2", "8Gi", nil), testNode("n2", "2", "8Gi", nil), testNode("n3", "2", "8Gi", nil)}
+	nodes[2].Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	pods := []corev1.Pod{
+		withRequests(testPod("shop", "web-1", "n1", web), "500m", "256Mi"),
+		withRequests(testPod("shop", "batch-1", "n2", nil), "1", "256Mi"),
+	}
+
+	plan := simulateCapacityPlan(nodes, pods, "shop", planTestTemplate("500m"), sel, 3)
+	if plan.Placed != 3 {
+		t.Fatalf("placed %d of 3, want all", plan.Placed)
+	}
+	// n2 has no web replica so it takes the first; then the nodes tie on
+	// one replica each and n1 wins on headroom; n2 then has fewer replicas.
+	added := map[string]int{}
+	for _, n := range plan.Nodes {
+		added[n.Name] = n.Added
+	}
+	if added["n1"] != 1 || added["n2"] != 2 || added["n3"] != 0 {
+		t.Errorf("placement = %v, want n1:1 n2:2 and none on the tainted n3", added)
+	}
+	if plan.MoreAfter != 2 {
+		t.Errorf("MoreAfter = %d, want 2 (n1 has 1000m left, n2 none)", plan.MoreAfter)
+	}
+
+	plan = simulateCapacityPlan(nodes, pods, "shop", planTestTemplate("500m"), sel, 6)
+	if plan.Placed != 5 {
+		t.Errorf("placed %d of 6, want 5", plan.Placed)
+	}
+	if plan.Unschedulable["Insufficient cpu"] != 2 || plan.Unschedulable["node(s) had untolerated taint {dedicated: gpu}"] != 1 {
+		t.Errorf("unschedulable = %v, want 2 Insufficient cpu and 1 untolerated taint", plan.Unschedulable)
+	}
+
+	template := planTestTemplate("100m")
+	template.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+	template.Spec.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+		{TopologyKey: "kubernetes.io/hostname", LabelSelector: &metav1.LabelSelector{MatchLabels: web}},
+	}}}
+	plan = simulateCapacityPlan(nodes, pods, "shop", template, sel, 3)
+	if plan.Placed != 2 || plan.Unschedulable["node(s) didn't match pod anti-affinity rules"] != 3 {
+		t.Errorf("anti-affinity plan placed %d, unschedulable %v; want 2 placed and 3 anti-affinity rejections", plan.Placed, plan.Unschedulable)
+	}
+}
+
+func TestNodeSchedulingBlock(t *testing.T) {
+	node := testNode("n1", "2", "8Gi", nil)
+	node.Labels["pool"] = "apps"
+	node.Labels["generation"] = "5"
+
+	tests := []struct {
+		name string
+		spec corev1.PodSpec
+		want string
+	}{
+		{name: "no constraints"},
+		{name: "selector mismatch", spec: corev1.PodSpec{NodeSelector: map[string]string{"pool": "gpu"}}, want: "node(s) didn't match Pod's node affinity/selector"},
+		{
+			name: "affinity Gt",
+			spec: corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "generation", Operator: corev1.NodeSelectorOpGt, Values: []string{"3"}}}}},
+			}}}},
+		},
+		{
+			name: "affinity NotIn",
+			spec: corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"apps"}}}}},
+			}}}},
+			want: "node(s) didn't match Pod's node affinity/selector",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeSchedulingBlock(&node, &tt.spec); got != tt.want {
+				t.Errorf("nodeSchedulingBlock() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	node.Spec.Unschedulable = true
+	if got := nodeSchedulingBlock(&node, &corev1.PodSpec{}); got != "node(s) were unschedulable" {
+		t.Errorf("nodeSchedulingBlock(cordoned) = %q", got)
+	}
+}
+
+func TestFormatSchedulingTally(t *testing.T) {
+	got := formatSchedulingTally(map[string]int{"Insufficient cpu": 3, "node(s) were unschedulable": 1, "Insufficient memory": 3})
+	if want := "3 Insufficient cpu, 3 Insufficient memory, 1 node(s) were unschedulable"; got != want {
+		t.Errorf("formatSchedulingTally() = %q, want %q", got, want)
+	}
+}
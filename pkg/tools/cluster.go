@@ -18,7 +18,10 @@ type listContextsInput struct{}
 
 // --- list_namespaces ---
 
-type listNamespacesInput struct{}
+type listNamespacesInput struct {
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
+}
 
 // --- cluster_info ---
 
@@ -57,10 +60,11 @@ func registerClusterTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Name:        "list_namespaces",
 		Description: "List all namespaces in the cluster with their status and age. Use this to discover what namespaces exist before inspecting resources.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listNamespacesInput) (*mcp.CallToolResult, any, error) {
-		namespaces, err := client.ListNamespaces(ctx)
+		page, err := client.ListNamespacesPage(ctx, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing namespaces", err), nil, nil
 		}
+		namespaces := page.Items
 
 		headers := []string{"NAME", "STATUS", "AGE", "LABELS"}
 		rows := make([][]string, 0, len(namespaces))
@@ -78,6 +82,7 @@ func registerClusterTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\nTotal: %d namespaces\n", len(namespaces)))
+		sb.WriteString(pageFooter("list_namespaces", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -87,67 +92,73 @@ func registerClusterTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Name:        "cluster_info",
 		Description: "Get cluster version, node count, namespace count, and overall resource summary. Use this for a quick cluster overview.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input clusterInfoInput) (*mcp.CallToolResult, any, error) {
-		timeoutCtx, cancel := context.WithTimeout(ctx, util.DefaultTimeout)
-		defer cancel()
-
-		var sb strings.Builder
-		sb.WriteString(util.FormatHeader("Cluster Information"))
-		sb.WriteString("\n")
-
-		// Server version
-		version, err := client.Clientset.Discovery().ServerVersion()
-		if err != nil {
-			sb.WriteString(fmt.Sprintf("Server Version: error (%v)\n", err))
-		} else {
-			sb.WriteString(fmt.Sprintf("Server Version: %s\n", version.GitVersion))
-		}
+		return util.SuccessResult(clusterOverview(ctx, client)), nil, nil
+	})
+}
 
-		// Node count
-		nodes, err := client.Clientset.CoreV1().Nodes().List(timeoutCtx, metav1.ListOptions{})
-		if err != nil {
-			sb.WriteString(fmt.Sprintf("Nodes: error (%v)\n", err))
-		} else {
-			readyCount := 0
-			for _, n := range nodes.Items {
-				for _, c := range n.Status.Conditions {
-					if c.Type == "Ready" && c.Status == "True" {
-						readyCount++
-					}
+// clusterOverview summarizes server version and node, namespace, pod, and
+// service counts. It backs cluster_info and the k8s://cluster/overview resource.
+func clusterOverview(ctx context.Context, client *k8s.ClusterClient) string {
+	timeoutCtx, cancel := context.WithTimeout(ctx, util.Timeout(ctx))
+	defer cancel()
+
+	var sb strings.Builder
+	sb.WriteString(util.FormatHeader("Cluster Information"))
+	sb.WriteString("\n")
+
+	// Server version
+	version, err := client.Clientset.Discovery().ServerVersion()
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("Server Version: error (%v)\n", err))
+	} else {
+		sb.WriteString(fmt.Sprintf("Server Version: %s\n", version.GitVersion))
+	}
+
+	// Node count
+	nodes, err := client.Clientset.CoreV1().Nodes().List(timeoutCtx, metav1.ListOptions{})
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("Nodes: error (%v)\n", err))
+	} else {
+		readyCount := 0
+		for _, n := range nodes.Items {
+			for _, c := range n.Status.Conditions {
+				if c.Type == "Ready" && c.Status == "True" {
+					readyCount++
 				}
 			}
-			sb.WriteString(fmt.Sprintf("Nodes: %d total, %d ready\n", len(nodes.Items), readyCount))
-		}
-
-		// Namespace count
-		namespaces, err := client.ListNamespaces(ctx)
-		if err != nil {
-			sb.WriteString(fmt.Sprintf("Namespaces: error (%v)\n", err))
-		} else {
-			sb.WriteString(fmt.Sprintf("Namespaces: %d\n", len(namespaces)))
 		}
-
-		// Pod count (all namespaces)
-		pods, err := client.Clientset.CoreV1().Pods("").List(timeoutCtx, metav1.ListOptions{})
-		if err != nil {
-			sb.WriteString(fmt.Sprintf("Pods: error (%v)\n", err))
-		} else {
-			running := 0
-			for _, p := range pods.Items {
-				if p.Status.Phase == "Running" {
-					running++
-				}
+		sb.WriteString(fmt.Sprintf("Nodes: %d total, %d ready\n", len(nodes.Items), readyCount))
+	}
+
+	// Namespace count
+	namespaces, err := client.ListNamespaces(ctx)
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("Namespaces: error (%v)\n", err))
+	} else {
+		sb.WriteString(fmt.Sprintf("Namespaces: %d\n", len(namespaces)))
+	}
+
+	// Pod count (all namespaces)
+	pods, err := client.Clientset.CoreV1().Pods("").List(timeoutCtx, metav1.ListOptions{})
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("Pods: error (%v)\n", err))
+	} else {
+		running := 0
+		for _, p := range pods.Items {
+			if p.Status.Phase == "Running" {
+				running++
 			}
-			sb.WriteString(fmt.Sprintf("Pods: %d total, %d running\n", len(pods.Items), running))
 		}
-
-		// Service count
-		services, err := client.Clientset.CoreV1().Services("").List(timeoutCtx, metav1.ListOptions{})
-		if err != nil {
-			sb.WriteString(fmt.Sprintf("Services: error (%v)\n", err))
-		} else {
-			sb.WriteString(fmt.Sprintf("Services: %d\n", len(services.Items)))
-		}
-
-		return util.SuccessResult(sb.String()), nil, nil
-	})
+		sb.WriteString(fmt.Sprintf("Pods: %d total, %d running\n", len(pods.Items), running))
+	}
+
+	// Service count
+	services, err := client.Clientset.CoreV1().Services("").List(timeoutCtx, metav1.ListOptions{})
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("Services: error (%v)\n", err))
+	} else {
+		sb.WriteString(fmt.Sprintf("Services: %d\n", len(services.Items)))
+	}
+
+	return sb.String()
 }
@@ -0,0 +1,425 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type compareWorkloadInput struct {
+	Deployment     string `json:"deployment" jsonschema:"required,Deployment name"`
+	Namespace      string `json:"namespace" jsonschema:"required,Namespace of the Deployment"`
+	ContextA       string `json:"context_a,omitempty" jsonschema:"First kubeconfig context (default: the server's current context)"`
+	ContextB       string `json:"context_b" jsonschema:"required,Second kubeconfig context to compare against"`
+	NamespaceB     string `json:"namespace_b,omitempty" jsonschema:"Namespace in context_b when it differs (default: namespace)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 15, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// Categories of compared workload facts, in report order.
+const (
+	factReplicas  = "Replicas"
+	factImage     = "Image"
+	factEnv       = "Env"
+	factResources = "Resources"
+	factConfig    = "Config"
+	factOther     = "Other"
+)
+
+// factMissing is the value of a ConfigMap or Secret that does not exist.
+const factMissing = "missing"
+
+// workloadFact is one comparable property of a workload in one cluster.
+type workloadFact struct {
+	Category string
+	Field    string
+	Value    string
+}
+
+// workloadSnapshot is a Deployment and the config it references, as read
+// from one context.
+type workloadSnapshot struct {
+	Context    string
+	Namespace  string
+	Deployment *appsv1.Deployment
+	Facts      []workloadFact
+	Err        error
+}
+
+// workloadDiff is a field whose value differs between the two contexts.
+// An empty side means the field does not exist there.
+type workloadDiff struct {
+	Category string
+	Field    string
+	A, B     string
+}
+
+func registerCompareContextTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "compare_workload_across_contexts",
+		Description: "Compare one Deployment between two kubeconfig contexts (clusters): replicas and readiness, images, env vars, resources, " +
+			"and the ConfigMaps and Secrets it references (existence and per-key content hashes; secret values are never shown). " +
+			"The fastest way to answer \"why does it work in cluster A but not in B\".",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input compareWorkloadInput) (*mcp.CallToolResult, any, error) {
+		if input.Deployment == "" || input.Namespace == "" || input.ContextB == "" {
+			return util.ErrorResult("deployment, namespace, and context_b are required"), nil, nil
+		}
+		timeout := input.TimeoutSeconds
+		if timeout <= 0 {
+			timeout = fleetDefaultTimeout
+		}
+		ctx = util.WithTimeoutSeconds(ctx, timeout)
+
+		available, current, err := client.AvailableContexts()
+		if err != nil {
+			return util.HandleK8sError("listing contexts", err), nil, nil
+		}
+		own := client.ContextName
+		if own == "" {
+			own = current
+		}
+		ctxA := input.ContextA
+		if ctxA == "" {
+			ctxA = own
+		}
+		for _, name := range []string{ctxA, input.ContextB} {
+			if name != own && !slices.Contains(available, name) {
+				return util.ErrorResult("unknown context %s; run list_contexts to see the available ones", name), nil, nil
+			}
+		}
+		nsB := input.NamespaceB
+		if nsB == "" {
+			nsB = input.Namespace
+		}
+		if ctxA == input.ContextB && nsB == input.Namespace {
+			return util.ErrorResult("context_a and context_b are both %s; pick two different contexts or namespaces", ctxA), nil, nil
+		}
+
+		sides := []struct{ context, namespace string }{{ctxA, input.Namespace}, {input.ContextB, nsB}}
+		snaps := make([]workloadSnapshot, len(sides))
+		var wg sync.WaitGroup
+		for i, s := range sides {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c := client
+				var err error
+				if s.context != own {
+					c, err = client.ForContext(s.context)
+				}
+				if err != nil {
+					snaps[i] = workloadSnapshot{Context: s.context, Namespace: s.namespace, Err: err}
+					return
+				}
+				snaps[i] = snapshotWorkload(ctx, c, s.context, s.namespace, input.Deployment)
+			}()
+		}
+		wg.Wait()
+		a, b := snaps[0], snaps[1]
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Workload Comparison: Deployment %s", input.Deployment)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("A", fmt.Sprintf("%s (namespace %s)", a.Context, a.Namespace)) + "\n")
+		sb.WriteString(util.FormatKeyValue("B", fmt.Sprintf("%s (namespace %s)", b.Context, b.Namespace)) + "\n\n")
+
+		for _, s := range snaps {
+			if s.Err == nil {
+				continue
+			}
+			sb.WriteString("FINDINGS:\n")
+			if apierrors.IsNotFound(s.Err) {
+				sb.WriteString(findings.Format("KD-CFG-003", "CRITICAL", fmt.Sprintf("Deployment %s/%s does not exist in context %s", s.Namespace, input.Deployment, s.Context)))
+			} else {
+				sb.WriteString(findings.Format("KD-SYS-008", "CRITICAL", fmt.Sprintf("Could not read Deployment %s/%s from context %s: %s", s.Namespace, input.Deployment, s.Context, gapReason(s.Err))))
+			}
+			sb.WriteString("\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		diffs := diffWorkloadFacts(a.Facts, b.Facts)
+		if len(diffs) == 0 {
+			sb.WriteString(fmt.Sprintf("No differences in %d compared fields.\n", len(a.Facts)))
+		} else {
+			rows := make([][]string, 0, len(diffs))
+			for _, d := range diffs {
+				rows = append(rows, []string{d.Category, d.Field, truncateName(valueOrAbsent(d.A), 50), truncateName(valueOrAbsent(d.B), 50)})
+			}
+			sb.WriteString(fmt.Sprintf("%d of %d fields differ:\n", len(diffs), max(len(a.Facts), len(b.Facts))))
+			sb.WriteString(util.FormatTable([]string{"CATEGORY", "FIELD", "A: " + a.Context, "B: " + b.Context}, rows))
+		}
+
+		findingLines, actions := workloadDiffFindings(diffs, a, b)
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "The Deployment is identical in both contexts; look at cluster-level differences (nodes, network policies, ingress) instead"))
+			sb.WriteString("\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString(f + "\n")
+		}
+		if actions = dedupe(actions); len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, act := range actions {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, act))
+			}
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// snapshotWorkload reads a Deployment and the ConfigMaps and Secrets it
+// references from one cluster.
+func snapshotWorkload(ctx context.Context, client *k8s.ClusterClient, contextName, namespace, name string) workloadSnapshot {
+	snap := workloadSnapshot{Context: contextName, Namespace: namespace}
+	d, err := client.GetDeployment(ctx, namespace, name)
+	if err != nil {
+		snap.Err = err
+		return snap
+	}
+	snap.Deployment = d
+	snap.Facts = deploymentFacts(d)
+	for _, ref := range podConfigRefs(d.Spec.Template.Spec) {
+		var data map[string][]byte
+		var err error
+		switch ref.Kind {
+		case "ConfigMap":
+			var cm *corev1.ConfigMap
+			if cm, err = client.GetConfigMap(ctx, namespace, ref.Name); err == nil {
+				data = make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+				for k, v := range cm.Data {
+					data[k] = []byte(v)
+				}
+				for k, v := range cm.BinaryData {
+					data[k] = v
+				}
+			}
+		case "Secret":
+			var s *corev1.Secret
+			if s, err = client.GetSecret(ctx, namespace, ref.Name); err == nil {
+				data = s.Data
+			}
+		}
+		snap.Facts = append(snap.Facts, configFacts(ref, data, err)...)
+	}
+	return snap
+}
+
+// deploymentFacts flattens the comparable parts of a Deployment. Literal env
+// values that look sensitive are redacted before they are compared.
+func deploymentFacts(d *appsv1.Deployment) []workloadFact {
+	replicas := derefReplicas(d)
+	spec := d.Spec.Template.Spec
+	facts := []workloadFact{
+		{factReplicas, "replicas", fmt.Sprintf("%d", replicas)},
+		{factReplicas, "ready", fmt.Sprintf("%d/%d", d.Status.ReadyReplicas, replicas)},
+		{factOther, "serviceAccount", valueOrNone(spec.ServiceAccountName)},
+	}
+	for _, c := range allContainers(spec) {
+		prefix := c.Name + " "
+		facts = append(facts,
+			workloadFact{factImage, prefix + "image", c.Image},
+			workloadFact{factResources, prefix + "requests", formatResourceQuantities(c.Resources.Requests)},
+			workloadFact{factResources, prefix + "limits", formatResourceQuantities(c.Resources.Limits)},
+		)
+		if len(c.Command) > 0 {
+			facts = append(facts, workloadFact{factOther, prefix + "command", strings.Join(c.Command, " ")})
+		}
+		if len(c.Args) > 0 {
+			facts = append(facts, workloadFact{factOther, prefix + "args", strings.Join(c.Args, " ")})
+		}
+		for _, e := range c.Env {
+			facts = append(facts, workloadFact{factEnv, prefix + "env " + e.Name, envSource(e)})
+		}
+		for _, ef := range c.EnvFrom {
+			switch {
+			case ef.ConfigMapRef != nil:
+				facts = append(facts, workloadFact{factEnv, prefix + "envFrom ConfigMap/" + ef.ConfigMapRef.Name, "prefix " + valueOrNone(ef.Prefix)})
+			case ef.SecretRef != nil:
+				facts = append(facts, workloadFact{factEnv, prefix + "envFrom Secret/" + ef.SecretRef.Name, "prefix " + valueOrNone(ef.Prefix)})
+			}
+		}
+	}
+	return facts
+}
+
+// envSource describes where an env var's value comes from.
+func envSource(e corev1.EnvVar) string {
+	if e.ValueFrom == nil {
+		return util.RedactValue(e.Name, e.Value)
+	}
+	switch vf := e.ValueFrom; {
+	case vf.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("ConfigMap %s key %s", vf.ConfigMapKeyRef.Name, vf.ConfigMapKeyRef.Key)
+	case vf.SecretKeyRef != nil:
+		return fmt.Sprintf("Secret %s key %s", vf.SecretKeyRef.Name, vf.SecretKeyRef.Key)
+	case vf.FieldRef != nil:
+		return "field " + vf.FieldRef.FieldPath
+	case vf.ResourceFieldRef != nil:
+		return "resource " + vf.ResourceFieldRef.Resource
+	}
+	return "valueFrom"
+}
+
+// formatResourceQuantities renders requests or limits as "cpu=100m, memory=128Mi".
+func formatResourceQuantities(rl corev1.ResourceList) string {
+	if len(rl) == 0 {
+		return "<none>"
+	}
+	parts := make([]string, 0, len(rl))
+	for name, q := range rl {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, q.String()))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// configFacts describes a referenced ConfigMap or Secret: whether it exists
+// and a short content hash per key, so differences show without values.
+func configFacts(ref configRef, data map[string][]byte, err error) []workloadFact {
+	switch {
+	case apierrors.IsNotFound(err):
+		return []workloadFact{{factConfig, ref.Key(), factMissing}}
+	case err != nil:
+		return []workloadFact{{factConfig, ref.Key(), "unreadable: " + gapReason(err)}}
+	}
+	facts := []workloadFact{{factConfig, ref.Key(), fmt.Sprintf("%d key(s)", len(data))}}
+	for k, v := range data {
+		sum := sha256.Sum256(v)
+		facts = append(facts, workloadFact{factConfig, fmt.Sprintf("%s[%s]", ref.Key(), k), fmt.Sprintf("sha256:%x", sum[:4])})
+	}
+	return facts
+}
+
+// diffWorkloadFacts returns the fields whose values differ, including fields
+// present on one side only, ordered by category and field.
+func diffWorkloadFacts(a, b []workloadFact) []workloadDiff {
+	byField := make(map[string]*workloadDiff)
+	for _, f := range a {
+		byField[f.Field] = &workloadDiff{Category: f.Category, Field: f.Field, A: f.Value}
+	}
+	for _, f := range b {
+		if d, ok := byField[f.Field]; ok {
+			d.B = f.Value
+			continue
+		}
+		byField[f.Field] = &workloadDiff{Category: f.Category, Field: f.Field, B: f.Value}
+	}
+	order := map[string]int{factReplicas: 0, factImage: 1, factConfig: 2, factEnv: 3, factResources: 4, factOther: 5}
+	var diffs []workloadDiff
+	for _, d := range byField {
+		if d.A != d.B {
+			diffs = append(diffs, *d)
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if order[diffs[i].Category] != order[diffs[j].Category] {
+			return order[diffs[i].Category] < order[diffs[j].Category]
+		}
+		return diffs[i].Field < diffs[j].Field
+	})
+	return diffs
+}
+
+// workloadDiffFindings turns the differences into findings, most likely
+// causes of "works in A, not in B" first.
+func workloadDiffFindings(diffs []workloadDiff, a, b workloadSnapshot) (findingLines, actions []string) {
+	contextOf := func(side string) (workloadSnapshot, workloadSnapshot) {
+		if side == "A" {
+			return a, b
+		}
+		return b, a
+	}
+	changedKeys := make(map[string][]string) // Kind/Name -> keys that differ
+	var env, resources, other []string
+	for _, d := range diffs {
+		switch d.Category {
+		case factReplicas:
+			if d.Field != "ready" {
+				findingLines = append(findingLines, findings.Format("KD-CFG-003", "INFO", fmt.Sprintf("Replica count differs: %s in %s, %s in %s", d.A, a.Context, d.B, b.Context)))
+				continue
+			}
+			for _, side := range []string{"A", "B"} {
+				s, _ := contextOf(side)
+				if s.Deployment.Status.ReadyReplicas < derefReplicas(s.Deployment) {
+					findingLines = append(findingLines, findings.Format("KD-WL-001", "WARNING", fmt.Sprintf("Only %s replicas are ready in %s", factValue(d, side), s.Context)))
+				}
+			}
+		case factImage:
+			findingLines = append(findingLines, findings.Format("KD-CFG-003", "WARNING", fmt.Sprintf("%s: %s in %s but %s in %s", d.Field, valueOrAbsent(d.A), a.Context, valueOrAbsent(d.B), b.Context)))
+			actions = append(actions, "Align the images, or confirm the difference is an intended staged rollout")
+		case factConfig:
+			if ref, key, isKey := strings.Cut(d.Field, "["); isKey {
+				changedKeys[ref] = append(changedKeys[ref], strings.TrimSuffix(key, "]"))
+				continue
+			}
+			for _, side := range []string{"A", "B"} {
+				s, other := contextOf(side)
+				if factValue(d, side) == factMissing {
+					findingLines = append(findingLines, findings.Format("KD-CFG-003", "CRITICAL", fmt.Sprintf("%s exists in %s but is missing in %s; pods that need it fail with CreateContainerConfigError or stay ContainerCreating", d.Field, other.Context, s.Context)))
+					actions = append(actions, fmt.Sprintf("Create %s in namespace %s on context %s", d.Field, s.Namespace, s.Context))
+				}
+			}
+		case factEnv:
+			env = append(env, d.Field)
+		case factResources:
+			resources = append(resources, d.Field)
+		default:
+			other = append(other, d.Field)
+		}
+	}
+	for _, key := range sortedMapKeys(changedKeys) {
+		severity := "WARNING"
+		if strings.HasPrefix(key, "Secret/") {
+			// Per-cluster credentials are expected to differ.
+			severity = "INFO"
+		}
+		findingLines = append(findingLines, findings.Format("KD-CFG-003", severity, fmt.Sprintf("%s content differs in key(s): %s", key, joinLimited(changedKeys[key], 8))))
+	}
+	if len(changedKeys) > 0 {
+		actions = append(actions, "Diff the differing ConfigMap keys between the clusters (get_configmap_detail on each context)")
+	}
+	if len(env) > 0 {
+		findingLines = append(findingLines, findings.Format("KD-CFG-003", "WARNING", fmt.Sprintf("%d env setting(s) differ: %s", len(env), joinLimited(env, 6))))
+	}
+	if len(resources) > 0 {
+		findingLines = append(findingLines, findings.Format("KD-CFG-003", "INFO", fmt.Sprintf("Resources differ: %s", joinLimited(resources, 6))))
+	}
+	if len(other) > 0 {
+		findingLines = append(findingLines, findings.Format("KD-CFG-003", "INFO", fmt.Sprintf("Other differences: %s", joinLimited(other, 6))))
+	}
+	return findingLines, actions
+}
+
+func factValue(d workloadDiff, side string) string {
+	if side == "A" {
+		return d.A
+	}
+	return d.B
+}
+
+func derefReplicas(d *appsv1.Deployment) int32 {
+	if d.Spec.Replicas == nil {
+		return 1
+	}
+	return *d.Spec.Replicas
+}
+
+func valueOrAbsent(s string) string {
+	if s == "" {
+		return "<absent>"
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+func compareDeployment(image, logLevel, memory string, ready int32) *appsv1.Deployment {
+	replicas := int32(3)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "api",
+				Image: image,
+				Env: []corev1.EnvVar{
+					{Name: "LOG_LEVEL", Value: logLevel},
+					{Name: "DB_PASSWORD", Value: "hunter2-" + logLevel},
+				},
+				EnvFrom:      []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "api-config"}}}},
+				Resources:    corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)}},
+				VolumeMounts: []corev1.VolumeMount{{Name: "tls", MountPath: "/tls"}},
+			}},
+				Volumes: []corev1.Volume{{Name: "tls", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "api-tls"}}}},
+			}},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: ready},
+	}
+}
+
+func compareSnapshot(t *testing.T, name string, objects ...runtime.Object) workloadSnapshot {
+	t.Helper()
+	client := k8s.NewClusterClientForTesting(fake.NewSimpleClientset(objects...), nil)
+	snap := snapshotWorkload(context.Background(), client, name, "shop", "api")
+	if snap.Err != nil {
+		t.Fatalf("%s: %v", name, snap.Err)
+	}
+	return snap
+}
+
+func TestCompareWorkloadAcrossContexts(t *testing.T) {
+	a := compareSnapshot(t, "prod-east",
+		compareDeployment("api:1.4.0", "info", "512Mi", 3),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "api-config", Namespace: "shop"}, Data: map[string]string{"FEATURE_X": "on", "REGION": "east"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "api-tls", Namespace: "shop"}, Data: map[string][]byte{"tls.crt": []byte("east")}},
+	)
+	b := compareSnapshot(t, "prod-west",
+		compareDeployment("api:1.5.0", "debug", "512Mi", 1),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "api-config", Namespace: "shop"}, Data: map[string]string{"FEATURE_X": "off", "REGION": "east"}},
+	)
+
+	diffs := diffWorkloadFacts(a.Facts, b.Facts)
+	fields := make(map[string]workloadDiff)
+	for _, d := range diffs {
+		fields[d.Field] = d
+	}
+	for _, want := range []string{"ready", "api image", "api env LOG_LEVEL", "ConfigMap/api-config[FEATURE_X]", "Secret/api-tls", "Secret/api-tls[tls.crt]"} {
+		if _, ok := fields[want]; !ok {
+			t.Errorf("expected a difference in %q, got %+v", want, diffs)
+		}
+	}
+	for _, same := range []string{"api limits", "ConfigMap/api-config[REGION]", "replicas"} {
+		if _, ok := fields[same]; ok {
+			t.Errorf("%q is identical and should not be reported", same)
+		}
+	}
+	if d := fields["api env DB_PASSWORD"]; strings.Contains(d.A+d.B, "hunter2") {
+		t.Errorf("sensitive env value leaked: %+v", d)
+	}
+	if diffs[0].Category != factReplicas {
+		t.Errorf("replica differences should sort first, got %+v", diffs[0])
+	}
+
+	findings, actions := workloadDiffFindings(diffs, a, b)
+	got := strings.Join(findings, "\n")
+	for _, want := range []string{
+		"[WARNING] KD-WL-001: Only 1/3 replicas are ready in prod-west",
+		"[WARNING] KD-CFG-003: api image: api:1.4.0 in prod-east but api:1.5.0 in prod-west",
+		"[CRITICAL] KD-CFG-003: Secret/api-tls exists in prod-east but is missing in prod-west",
+		"[WARNING] KD-CFG-003: ConfigMap/api-config content differs in key(s): FEATURE_X",
+		"env setting(s) differ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("findings missing %q:\n%s", want, got)
+		}
+	}
+	if !strings.Contains(strings.Join(actions, "\n"), "Create Secret/api-tls in namespace shop on context prod-west") {
+		t.Errorf("actions = %v", actions)
+	}
+}
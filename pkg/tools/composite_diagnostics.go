@@ -12,29 +12,45 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/mermaid"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
 )
 
 type diagnoseRequestPathInput struct {
-	Hostname  string `json:"hostname" jsonschema:"required,Hostname to trace (e.g. api.example.com)"`
-	Path      string `json:"path,omitempty" jsonschema:"URL path to trace (e.g. /payments/v1/charge). Default: /"`
-	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace to search for Ingress (empty = all)"`
+	Hostname        string `json:"hostname" jsonschema:"required,Hostname to trace (e.g. api.example.com)"`
+	Path            string `json:"path,omitempty" jsonschema:"URL path to trace (e.g. /payments/v1/charge). Default: /"`
+	Namespace       string `json:"namespace,omitempty" jsonschema:"Namespace to search for Ingress (empty = all)"`
+	MaxDiagramNodes int    `json:"max_diagram_nodes,omitempty" jsonschema:"Maximum nodes in the request path diagram before the rest are collapsed into '+N more' nodes (default 80)"`
+	SummaryOnly     bool   `json:"summary_only,omitempty" jsonschema:"Same as detail_level=summary"`
+	DetailLevel     string `json:"detail_level,omitempty" jsonschema:"How much to return: summary (about 10 lines: status, top findings, next action), standard (the report without Mermaid diagrams), or full (everything, the default)"`
 }
 
 type diagnoseServiceInput struct {
-	Namespace   string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
-	ServiceName string `json:"service_name" jsonschema:"required,Service name to diagnose"`
+	Namespace       string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	ServiceName     string `json:"service_name" jsonschema:"required,Service name to diagnose"`
+	MaxDiagramNodes int    `json:"max_diagram_nodes,omitempty" jsonschema:"Maximum nodes in the service diagram before the rest are collapsed into '+N more' nodes (default 80)"`
+	SummaryOnly     bool   `json:"summary_only,omitempty" jsonschema:"Same as detail_level=summary"`
+	DetailLevel     string `json:"detail_level,omitempty" jsonschema:"How much to return: summary (about 10 lines: status, top findings, next action), standard (the report without Mermaid diagrams), or full (everything, the default)"`
 }
 
-type clusterHealthOverviewInput struct{}
+type clusterHealthOverviewInput struct {
+	SummaryOnly     bool   `json:"summary_only,omitempty" jsonschema:"Same as detail_level=summary"`
+	DetailLevel     string `json:"detail_level,omitempty" jsonschema:"How much to return: summary (about 10 lines: status, top findings, next action), standard (the report without Mermaid diagrams), or full (everything, the default)"`
+	MaxDiagramNodes int    `json:"max_diagram_nodes,omitempty" jsonschema:"Maximum nodes in the cluster diagram before the rest are collapsed into '+N more' nodes (default 80)"`
+	TimeoutSeconds  int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// overviewStages is the number of progress steps cluster_health_overview reports.
+const overviewStages = 7
 
 type analyzeServiceLogsInput struct {
 	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
 	DeploymentName string `json:"deployment_name" jsonschema:"required,Deployment name"`
 	Pattern        string `json:"pattern,omitempty" jsonschema:"Search pattern (regex). Default: error|exception|fatal|panic|timeout|refused"`
 	TailLines      int64  `json:"tail_lines,omitempty" jsonschema:"Lines per pod (default 200)"`
+	Filter         string `json:"filter,omitempty" jsonschema:"For JSON logs, comma-separated field conditions that lines must all match (e.g. level=error or status>=500,path!=/healthz)"`
 }
 
 func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
@@ -42,9 +58,14 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 	mcp.AddTool(server, &mcp.Tool{
 		Name: "diagnose_request_path",
 		Description: "Trace and diagnose the full request path from a hostname through Ingress → Service → Endpoints → Pods. " +
-			"Checks health at every layer, validates AGIC/Ingress annotations, analyzes resource usage, " +
+			"Checks health at every layer, validates AGIC/Ingress annotations, checks Istio/Linkerd sidecars and mTLS when a mesh is installed, analyzes resource usage, " +
+			"compares gateway, app, and readiness probe timeouts to explain intermittent 504s, " +
 			"and generates Mermaid topology + sequence diagrams. THE PRIMARY tool for debugging why a URL is not working.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input diagnoseRequestPathInput) (*mcp.CallToolResult, any, error) {
+		detail, err := reportDetail(input.DetailLevel, input.SummaryOnly)
+		if err != nil {
+			return util.ErrorResult("%v", err), nil, nil
+		}
 		path := input.Path
 		if path == "" {
 			path = "/"
@@ -57,25 +78,30 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Request Path: https://%s%s", input.Hostname, path)))
 		sb.WriteString("\n\n")
-		findings := 0
+		findingCount := 0
 		actions := []string{}
 
 		// --- [1] FIND INGRESS ---
-		ing, rule, matchedPath, err := client.FindIngressForHostPath(ctx, ns, input.Hostname, path)
+		match, err := client.FindIngressForHostPath(ctx, ns, input.Hostname, path)
 		if err != nil {
-			sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("No Ingress found for %s%s", input.Hostname, path)))
+			sb.WriteString(findings.Format("KD-ING-003", "CRITICAL", fmt.Sprintf("No Ingress found for %s%s", input.Hostname, path)))
 			sb.WriteString("\n")
-			sb.WriteString("  Searched all namespaces for matching Ingress host+path rules.\n")
+			sb.WriteString("  Searched all namespaces for matching Ingress host+path rules, wildcard hosts, and default backends.\n")
 			sb.WriteString("\nSUGGESTED ACTIONS:\n")
 			sb.WriteString("1. Create an Ingress resource with host: " + input.Hostname + " and path: " + path + "\n")
 			sb.WriteString("2. Use list_ingresses to see existing Ingress resources\n")
-			return util.SuccessResult(sb.String()), nil, nil
+			return finishReport(sb.String(), detail), nil, nil
 		}
 
+		ing, matchedPath := match.Ingress, match.Path
 		sb.WriteString("[1] INGRESS\n")
 		sb.WriteString(fmt.Sprintf("    Name: %s/%s\n", ing.Namespace, ing.Name))
-		sb.WriteString(fmt.Sprintf("    Host: %s\n", rule.Host))
+		sb.WriteString(fmt.Sprintf("    Host: %s\n", match.Host()))
 		sb.WriteString(fmt.Sprintf("    Path: %s\n", matchedPath.Path))
+		sb.WriteString(fmt.Sprintf("    Matched By: %s\n", match.Explain()))
+		if match.Precedence == k8s.PrecedenceDefaultBackend {
+			sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-ING-003", "INFO", fmt.Sprintf("No rule matches %s%s; the request falls through to the Ingress defaultBackend", input.Hostname, path))))
+		}
 		if matchedPath.PathType != nil {
 			sb.WriteString(fmt.Sprintf("    Path Type: %s\n", *matchedPath.PathType))
 		}
@@ -90,7 +116,7 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 		if len(agicAnnotations) > 0 {
 			sb.WriteString("    AGIC Annotations:\n")
 			for _, a := range agicAnnotations {
-				sb.WriteString(fmt.Sprintf("      %s: %s\n", a.Key, a.Value))
+				sb.WriteString(fmt.Sprintf("      %s: %s\n", a.Key, util.RedactAnnotation(a.Key, a.Value)))
 			}
 		}
 
@@ -98,16 +124,16 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 		hasTLS := false
 		for _, tls := range ing.Spec.TLS {
 			for _, h := range tls.Hosts {
-				if h == input.Hostname {
+				if k8s.HostMatches(h, input.Hostname) {
 					hasTLS = true
 					sb.WriteString(fmt.Sprintf("    TLS: %s\n", tls.SecretName))
 				}
 			}
 		}
 		if !hasTLS {
-			sb.WriteString(util.FormatFinding("WARNING", "No TLS configured for this host"))
+			sb.WriteString(findings.Format("KD-ING-004", "WARNING", "No TLS configured for this host"))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 			actions = append(actions, "Configure TLS for "+input.Hostname)
 		}
 
@@ -120,13 +146,13 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 			}
 		}
 		if warningIngEvents > 0 {
-			sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d warning events on Ingress", warningIngEvents))))
+			sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-EVT-001", "WARNING", fmt.Sprintf("%d warning events on Ingress", warningIngEvents))))
 			for _, e := range ingEvents {
 				if e.Type == "Warning" {
 					sb.WriteString(fmt.Sprintf("      - %s: %s\n", e.Reason, e.Message))
 				}
 			}
-			findings++
+			findingCount++
 		}
 		sb.WriteString("\n")
 
@@ -143,22 +169,22 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 		}
 
 		if backendSvcName == "" {
-			sb.WriteString(util.FormatFinding("CRITICAL", "No backend service configured in Ingress path"))
+			sb.WriteString(findings.Format("KD-ING-001", "CRITICAL", "No backend service configured in Ingress path"))
 			sb.WriteString("\n")
-			return util.SuccessResult(sb.String()), nil, nil
+			return finishReport(sb.String(), detail), nil, nil
 		}
 
 		svc, err := client.GetService(ctx, ing.Namespace, backendSvcName)
 		if err != nil {
 			sb.WriteString("[2] SERVICE\n")
-			sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("Backend service '%s' not found in namespace '%s'", backendSvcName, ing.Namespace))))
-			findings++
+			sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-ING-001", "CRITICAL", fmt.Sprintf("Backend service '%s' not found in namespace '%s'", backendSvcName, ing.Namespace))))
+			findingCount++
 			actions = append(actions, fmt.Sprintf("Create service '%s' in namespace '%s'", backendSvcName, ing.Namespace))
 			sb.WriteString("\nSUGGESTED ACTIONS:\n")
 			for i, a := range actions {
 				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
 			}
-			return util.SuccessResult(sb.String()), nil, nil
+			return finishReport(sb.String(), detail), nil, nil
 		}
 
 		sb.WriteString("[2] SERVICE\n")
@@ -177,8 +203,8 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 			}
 		}
 		if warningSvcEvents > 0 {
-			sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d warning events on Service", warningSvcEvents))))
-			findings++
+			sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-EVT-001", "WARNING", fmt.Sprintf("%d warning events on Service", warningSvcEvents))))
+			findingCount++
 		}
 		sb.WriteString("\n")
 
@@ -186,21 +212,21 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 		sb.WriteString("[3] ENDPOINTS\n")
 		epHealth, err := client.GetServiceEndpointHealth(ctx, ing.Namespace, backendSvcName)
 		if err != nil {
-			sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("CRITICAL", "Could not get endpoints: "+err.Error())))
-			findings++
+			sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-SYS-008", "CRITICAL", "Could not get endpoints: "+err.Error())))
+			findingCount++
 		} else {
 			sb.WriteString(fmt.Sprintf("    Ready: %d/%d\n", epHealth.ReadyCount, epHealth.TotalEndpoints))
 
 			if epHealth.TotalEndpoints == 0 {
-				sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("CRITICAL", "Service has 0 endpoints — no pods match the selector")))
-				findings++
+				sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-SVC-001", "CRITICAL", "Service has 0 endpoints — no pods match the selector")))
+				findingCount++
 				actions = append(actions, fmt.Sprintf("Check that pods with labels %s exist in namespace %s", util.FormatLabels(svc.Spec.Selector), ing.Namespace))
 			} else if epHealth.NotReadyCount > 0 {
-				sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d endpoint(s) not ready", epHealth.NotReadyCount))))
+				sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-SVC-002", "WARNING", fmt.Sprintf("%d endpoint(s) not ready", epHealth.NotReadyCount))))
 				for _, nr := range epHealth.NotReadyPods {
 					sb.WriteString(fmt.Sprintf("      - %s (%s)\n", nr.PodName, nr.IP))
 				}
-				findings++
+				findingCount++
 			}
 		}
 
@@ -229,43 +255,53 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 			for i := range pods {
 				p := &pods[i]
 				if !isPodHealthy(p) {
-					sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("Pod '%s' is unhealthy: %s", p.Name, podPhaseReason(p)))))
-					findings++
+					sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-POD-010", "CRITICAL", fmt.Sprintf("Pod '%s' is unhealthy: %s", p.Name, podPhaseReason(p)))))
+					findingCount++
 					actions = append(actions, fmt.Sprintf("Diagnose pod '%s' with diagnose_pod tool", p.Name))
 				}
 				_, _, restarts := podContainerSummary(p)
 				if restarts > util.HighRestartThreshold {
-					sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("WARNING", fmt.Sprintf("Pod '%s' has %d restarts", p.Name, restarts))))
-					findings++
+					sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-POD-005", "WARNING", fmt.Sprintf("Pod '%s' has %d restarts", p.Name, restarts))))
+					findingCount++
 				}
 
 				// Check resource limits
 				for _, c := range p.Spec.Containers {
 					if c.Resources.Limits == nil || (c.Resources.Limits.Cpu().IsZero() && c.Resources.Limits.Memory().IsZero()) {
-						sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("INFO", fmt.Sprintf("Pod '%s' container '%s' has no resource limits", p.Name, c.Name))))
-						findings++
+						sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-RES-001", "INFO", fmt.Sprintf("Pod '%s' container '%s' has no resource limits", p.Name, c.Name))))
+						findingCount++
 					}
 				}
 
 				// Check probe config
 				for _, c := range p.Spec.Containers {
 					if c.ReadinessProbe == nil {
-						sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("WARNING", fmt.Sprintf("Pod '%s' container '%s' has no readiness probe", p.Name, c.Name))))
-						findings++
+						sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-PRB-001", "WARNING", fmt.Sprintf("Pod '%s' container '%s' has no readiness probe", p.Name, c.Name))))
+						findingCount++
 						actions = append(actions, fmt.Sprintf("Add readiness probe to container '%s'", c.Name))
 					}
 					if c.LivenessProbe == nil {
-						sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("INFO", fmt.Sprintf("Pod '%s' container '%s' has no liveness probe", p.Name, c.Name))))
+						sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-PRB-006", "INFO", fmt.Sprintf("Pod '%s' container '%s' has no liveness probe", p.Name, c.Name))))
 					}
 				}
 			}
 		}
 
+		// --- MESH (only when Istio or Linkerd is installed) ---
+		meshFindings, meshActions := writeRequestPathMesh(ctx, client, &sb, ing, svc, pods)
+		findingCount += meshFindings
+		actions = append(actions, meshActions...)
+
+		// --- TIMEOUT CHAIN ---
+		timeoutFindings, timeoutActions := writeRequestPathTimeouts(&sb, ing, svc, pods)
+		findingCount += timeoutFindings
+		actions = append(actions, timeoutActions...)
+
 		// --- [4] RESOURCE USAGE ---
 		sb.WriteString("\n[4] RESOURCE USAGE\n")
 		podMetrics, metricsErr := client.GetPodMetrics(ctx, ing.Namespace, metav1.ListOptions{})
 		if metricsErr != nil {
-			sb.WriteString("    (metrics-server not available)\n")
+			sb.WriteString("    (metrics-server not available: run check_metrics_server to find out why)\n")
 		} else if len(pods) > 0 {
 			podMetricsMap := make(map[string]map[string][2]int64) // pod -> container -> [cpu_milli, mem_bytes]
 			for _, pm := range podMetrics {
@@ -302,23 +338,23 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 						pct := float64(cpuUsage) / float64(cpuLimit) * 100
 						cpuPct = fmt.Sprintf("%.0f%%", pct)
 						if pct >= 90 {
-							sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("%s/%s: CPU at %.0f%% of limit", p.Name, c.Name, pct))))
-							findings++
+							sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-RES-006", "CRITICAL", fmt.Sprintf("%s/%s: CPU at %.0f%% of limit", p.Name, c.Name, pct))))
+							findingCount++
 						} else if pct >= 70 {
-							sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%s/%s: CPU at %.0f%% of limit", p.Name, c.Name, pct))))
-							findings++
+							sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-RES-006", "WARNING", fmt.Sprintf("%s/%s: CPU at %.0f%% of limit", p.Name, c.Name, pct))))
+							findingCount++
 						}
 					}
 					if memLimit > 0 {
 						pct := float64(memUsage) / float64(memLimit) * 100
 						memPct = fmt.Sprintf("%.0f%%", pct)
 						if pct >= 90 {
-							sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("%s/%s: Memory at %.0f%% of limit — OOM risk", p.Name, c.Name, pct))))
-							findings++
+							sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-RES-006", "CRITICAL", fmt.Sprintf("%s/%s: Memory at %.0f%% of limit — OOM risk", p.Name, c.Name, pct))))
+							findingCount++
 							actions = append(actions, fmt.Sprintf("Increase memory limit for %s/%s", p.Name, c.Name))
 						} else if pct >= 70 {
-							sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%s/%s: Memory at %.0f%% of limit", p.Name, c.Name, pct))))
-							findings++
+							sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-RES-006", "WARNING", fmt.Sprintf("%s/%s: Memory at %.0f%% of limit", p.Name, c.Name, pct))))
+							findingCount++
 						}
 					}
 					sb.WriteString(fmt.Sprintf("    %s/%s: CPU %dm/%dm (%s)  Mem %s/%s (%s)\n",
@@ -333,10 +369,10 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Summary"))
 		sb.WriteString("\n")
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  Request path appears healthy. All layers operational.\n")
 		} else {
-			sb.WriteString(fmt.Sprintf("  %d finding(s) across the request path.\n", findings))
+			sb.WriteString(fmt.Sprintf("  %d finding(s) across the request path.\n", findingCount))
 		}
 
 		// --- SUGGESTED ACTIONS ---
@@ -355,30 +391,26 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 
 		// --- MERMAID TOPOLOGY DIAGRAM ---
 		sb.WriteString("\nTOPOLOGY:\n")
-		fc := mermaid.NewFlowchart(mermaid.DirectionTB)
+		fc := newBudgetedFlowchart(mermaid.DirectionTB, input.MaxDiagramNodes).SetOverflowNoun("pods")
 		fc.AddNode("internet", "Internet", mermaid.ShapeCircle)
-		fc.AddNode("agw", fmt.Sprintf("Ingress: %s%s%s: %s  Path: %s", ing.Name, mermaid.BR(), mermaid.BR(), rule.Host, matchedPath.Path), mermaid.ShapeTrapAlt)
+		fc.AddNode("agw", fmt.Sprintf("Ingress: %s%s%s: %s  Path: %s", ing.Name, mermaid.BR(), mermaid.BR(), match.Host(), matchedPath.Path), mermaid.ShapeTrapAlt)
 		fc.AddNode("svc", fmt.Sprintf("Service: %s%sClusterIP:%s", svc.Name, mermaid.BR(), backendSvcPort), mermaid.ShapeRect)
 
 		fc.AddEdge("internet", "agw", "HTTPS", mermaid.EdgeSolid)
 		fc.AddEdge("agw", "svc", "", mermaid.EdgeSolid)
 		fc.AddRawStyle("agw", "fill:#cce5ff,stroke:#4a90d9,stroke-width:2px")
 
-		for i := range pods {
-			p := &pods[i]
-			podID := mermaid.SafeID("pod_" + p.Name)
+		podNodes := summarizePodsForDiagram(pods, func(p *corev1.Pod) string {
 			label := p.Name
 			if len(label) > 30 {
 				label = label[:30] + "..."
 			}
-			healthy := isPodHealthy(p)
-			fc.AddNode(podID, label, mermaid.ShapeRound)
-			fc.AddEdge("svc", podID, "", mermaid.EdgeSolid)
-			if !healthy {
-				fc.AddStyle(podID, mermaid.SeverityCritical)
-			} else {
-				fc.AddStyle(podID, mermaid.SeverityHealthy)
-			}
+			return label
+		})
+		for _, n := range podNodes {
+			fc.AddNode(n.ID, n.Label, mermaid.ShapeRound)
+			fc.AddEdge("svc", n.ID, "", mermaid.EdgeSolid)
+			styleDiagramPodNode(fc, n)
 		}
 		sb.WriteString(fc.RenderBlock())
 
@@ -429,7 +461,7 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 
 		sb.WriteString(seq.RenderBlock())
 
-		return util.SuccessResult(sb.String()), nil, nil
+		return finishReport(sb.String(), detail), nil, nil
 	})
 
 	// diagnose_service — comprehensive service diagnosis
@@ -439,6 +471,10 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 			"Ingress exposure, network policies, events, and Mermaid dependency diagram. " +
 			"Use this as the primary tool for investigating service-level issues.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input diagnoseServiceInput) (*mcp.CallToolResult, any, error) {
+		detail, err := reportDetail(input.DetailLevel, input.SummaryOnly)
+		if err != nil {
+			return util.ErrorResult("%v", err), nil, nil
+		}
 		svc, err := client.GetService(ctx, input.Namespace, input.ServiceName)
 		if err != nil {
 			return util.HandleK8sError(fmt.Sprintf("getting service %s/%s", input.Namespace, input.ServiceName), err), nil, nil
@@ -447,7 +483,8 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Service Diagnosis: %s (namespace: %s)", svc.Name, svc.Namespace)))
 		sb.WriteString("\n\n")
-		findings := 0
+		findingCount := 0
+		var steps []util.NextStep
 
 		// 1. Service spec
 		sb.WriteString(util.FormatSubHeader("Service Configuration"))
@@ -465,16 +502,18 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 		sb.WriteString("\n")
 		epHealth, err := client.GetServiceEndpointHealth(ctx, input.Namespace, input.ServiceName)
 		if err != nil {
-			sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("CRITICAL", "Could not get endpoints: "+err.Error())))
-			findings++
+			sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-SYS-008", "CRITICAL", "Could not get endpoints: "+err.Error())))
+			findingCount++
 		} else {
 			sb.WriteString(fmt.Sprintf("  Total: %d, Ready: %d, NotReady: %d\n", epHealth.TotalEndpoints, epHealth.ReadyCount, epHealth.NotReadyCount))
 			if epHealth.TotalEndpoints == 0 {
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("CRITICAL", "Service has 0 endpoints — no pods match the selector")))
-				findings++
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-SVC-001", "CRITICAL", "Service has 0 endpoints — no pods match the selector")))
+				findingCount++
+				steps = append(steps, nextStep("analyze_service_connectivity", "Service has no endpoints; compare its selector and ports with candidate pods",
+					"namespace", svc.Namespace, "service_name", svc.Name))
 			} else if epHealth.NotReadyCount > 0 {
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d endpoint(s) not ready", epHealth.NotReadyCount))))
-				findings++
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-SVC-002", "WARNING", fmt.Sprintf("%d endpoint(s) not ready", epHealth.NotReadyCount))))
+				findingCount++
 			} else {
 				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("OK", "All endpoints ready")))
 			}
@@ -500,7 +539,9 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 					util.FormatAge(p.CreationTimestamp.Time),
 				})
 				if !isPodHealthy(p) {
-					findings++
+					findingCount++
+					steps = append(steps, nextStep("diagnose_pod", fmt.Sprintf("Backing pod is %s", podPhaseReason(p)),
+						"namespace", p.Namespace, "name", p.Name))
 				}
 			}
 			sb.WriteString(util.FormatTable(headers, rows))
@@ -512,7 +553,7 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 				}
 			}
 			if unhealthyPods > 0 {
-				sb.WriteString(fmt.Sprintf("\n  %s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("%d/%d pods are unhealthy", unhealthyPods, len(pods)))))
+				sb.WriteString(fmt.Sprintf("\n  %s\n", findings.Format("KD-POD-010", "CRITICAL", fmt.Sprintf("%d/%d pods are unhealthy", unhealthyPods, len(pods)))))
 			}
 		}
 
@@ -628,37 +669,34 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Assessment"))
 		sb.WriteString("\n")
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  Service appears healthy. All endpoints ready, pods running.\n")
 		} else {
-			sb.WriteString(fmt.Sprintf("  %d finding(s) identified. Review details above.\n", findings))
+			sb.WriteString(fmt.Sprintf("  %d finding(s) identified. Review details above.\n", findingCount))
 		}
 
 		// 9. Mermaid diagram
 		sb.WriteString("\nSERVICE CONTEXT:\n")
-		fc := mermaid.NewFlowchart(mermaid.DirectionLR)
+		fc := newBudgetedFlowchart(mermaid.DirectionLR, input.MaxDiagramNodes).SetOverflowNoun("pods")
 		svcID := mermaid.SafeID("svc_" + svc.Name)
 		fc.AddNode(svcID, fmt.Sprintf("Service: %s%s%s", svc.Name, mermaid.BR(), formatServicePorts(svc)), mermaid.ShapeRect)
 		fc.AddRawStyle(svcID, "fill:#cce5ff,stroke:#4a90d9,stroke-width:2px")
 
-		for i := range pods {
-			p := &pods[i]
-			podID := mermaid.SafeID("pod_" + p.Name)
+		podNodes := summarizePodsForDiagram(pods, func(p *corev1.Pod) string {
 			podLabel := p.Name
 			if len(podLabel) > 25 {
 				podLabel = podLabel[:25] + "..."
 			}
-			fc.AddNode(podID, podLabel, mermaid.ShapeRound)
-			fc.AddEdge(svcID, podID, "", mermaid.EdgeSolid)
-			if isPodHealthy(p) {
-				fc.AddStyle(podID, mermaid.SeverityHealthy)
-			} else {
-				fc.AddStyle(podID, mermaid.SeverityCritical)
-			}
+			return podLabel
+		})
+		for _, n := range podNodes {
+			fc.AddNode(n.ID, n.Label, mermaid.ShapeRound)
+			fc.AddEdge(svcID, n.ID, "", mermaid.EdgeSolid)
+			styleDiagramPodNode(fc, n)
 		}
 		sb.WriteString(fc.RenderBlock())
 
-		return util.SuccessResult(sb.String()), nil, nil
+		return util.WithNextSteps(finishReport(sb.String(), detail), steps), nil, nil
 	})
 
 	// cluster_health_overview — enhanced cluster dashboard
@@ -668,39 +706,54 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 			"Ingress audit, resource utilization, top consumers, events, and Mermaid cluster topology diagram. " +
 			"Use this for a complete picture of cluster health in one call.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input clusterHealthOverviewInput) (*mcp.CallToolResult, any, error) {
+		detail, err := reportDetail(input.DetailLevel, input.SummaryOnly)
+		if err != nil {
+			return util.ErrorResult("%v", err), nil, nil
+		}
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader("Cluster Health Overview"))
 		sb.WriteString("\n\n")
-		findings := 0
+		findingCount := 0
+		var steps []util.NextStep
+		var gaps dataGaps
+		progress := util.NewProgress(req)
 
 		// 1. Node health
 		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
-		if err != nil {
-			return util.HandleK8sError("listing nodes", err), nil, nil
-		}
 
 		sb.WriteString(util.FormatSubHeader("Nodes"))
 		sb.WriteString("\n")
+		writeScopeSkipped(&sb, err)
+		if gaps.record("Nodes", err) {
+			sb.WriteString(fmt.Sprintf("  Not collected: %s\n", gapReason(err)))
+		}
 		readyNodes := 0
 		for _, n := range nodes {
 			status := nodeStatus(&n)
 			if status == "Ready" {
 				readyNodes++
 			} else {
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("Node '%s' is %s", n.Name, status))))
-				findings++
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-NODE-001", "CRITICAL", fmt.Sprintf("Node '%s' is %s", n.Name, status))))
+				findingCount++
+				steps = append(steps, nextStep("get_node_detail", fmt.Sprintf("Node is %s", status), "name", n.Name))
 			}
 			for _, cond := range n.Status.Conditions {
 				if (cond.Type == corev1.NodeMemoryPressure || cond.Type == corev1.NodeDiskPressure || cond.Type == corev1.NodePIDPressure) && cond.Status == corev1.ConditionTrue {
-					sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("Node '%s' has %s", n.Name, cond.Type))))
-					findings++
+					sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-NODE-002", "WARNING", fmt.Sprintf("Node '%s' has %s", n.Name, cond.Type))))
+					findingCount++
+					steps = append(steps, nextStep("get_node_detail", fmt.Sprintf("Node has %s", cond.Type), "name", n.Name))
 				}
 			}
 		}
-		sb.WriteString(fmt.Sprintf("  %d/%d nodes ready\n", readyNodes, len(nodes)))
+		if err == nil {
+			sb.WriteString(fmt.Sprintf("  %d/%d nodes ready\n", readyNodes, len(nodes)))
+		}
+		progress.Report(ctx, 1, overviewStages, fmt.Sprintf("Checked %d nodes", len(nodes)))
 
 		// 2. Resource utilization
 		nodeMetrics, metricsErr := client.GetNodeMetrics(ctx)
+		gaps.record("Resource Utilization", metricsErr)
 		if metricsErr == nil && len(nodeMetrics) > 0 {
 			sb.WriteString("\n")
 			sb.WriteString(util.FormatSubHeader("Resource Utilization"))
@@ -725,17 +778,20 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 			sb.WriteString(fmt.Sprintf("  CPU:    %dm / %dm (%.1f%%)\n", totalCPU, capCPU, cpuPct))
 			sb.WriteString(fmt.Sprintf("  Memory: %s / %s (%.1f%%)\n", formatBytes(totalMem), formatBytes(capMem), memPct))
 			if cpuPct > 85 {
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", "Cluster CPU utilization above 85%")))
-				findings++
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-NODE-005", "WARNING", "Cluster CPU utilization above 85%")))
+				findingCount++
 			}
 			if memPct > 85 {
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", "Cluster memory utilization above 85%")))
-				findings++
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-NODE-005", "WARNING", "Cluster memory utilization above 85%")))
+				findingCount++
 			}
 		}
 
+		progress.Report(ctx, 2, overviewStages, "Collected node metrics")
+
 		// 3. Pod health by namespace
 		allPods, err := client.ListPods(ctx, "", metav1.ListOptions{})
+		gaps.record("Pod Health", err)
 		if err == nil {
 			sb.WriteString("\n")
 			sb.WriteString(util.FormatSubHeader("Pod Health by Namespace"))
@@ -772,22 +828,35 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 				for _, e := range nsPods {
 					totalUnhealthy += e.unhealthy
 				}
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d unhealthy pods cluster-wide", totalUnhealthy))))
-				findings++
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-POD-010", "WARNING", fmt.Sprintf("%d unhealthy pods cluster-wide", totalUnhealthy))))
+				findingCount++
+				steps = append(steps, nextStep("cluster_crashloops", "Group failing pods cluster-wide by root cause"))
+				for _, row := range rows {
+					steps = append(steps, nextStep("diagnose_namespace", fmt.Sprintf("%s unhealthy pod(s)", row[2]), "namespace", row[0]))
+				}
 			} else {
 				sb.WriteString(fmt.Sprintf("  All %d pods healthy across %d namespaces\n", len(allPods), len(nsPods)))
 			}
 		}
 
+		progress.Report(ctx, 3, overviewStages, fmt.Sprintf("Checked %d pods", len(allPods)))
+
 		// 4. Service endpoint health
 		services, err := client.ListServices(ctx, "", metav1.ListOptions{})
+		gaps.record("Service Endpoint Health", err)
 		if err == nil {
 			sb.WriteString("\n")
 			sb.WriteString(util.FormatSubHeader("Service Endpoint Health"))
 			sb.WriteString("\n")
 			deadServices := 0
 			degradedServices := 0
-			for _, svc := range services {
+			failedLookups := 0
+			var lookupErr error
+			for i, svc := range services {
+				if ctx.Err() != nil {
+					return util.HandleK8sError("checking service endpoints", ctx.Err()), nil, nil
+				}
+				progress.Report(ctx, 3+float64(i)/float64(len(services)), overviewStages, fmt.Sprintf("Services scanned %d/%d", i, len(services)))
 				if svc.Spec.Type == corev1.ServiceTypeExternalName {
 					continue
 				}
@@ -796,25 +865,34 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 				}
 				epHealth, err := client.GetServiceEndpointHealth(ctx, svc.Namespace, svc.Name)
 				if err != nil {
+					failedLookups++
+					lookupErr = err
 					continue
 				}
 				if epHealth.TotalEndpoints == 0 {
-					sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("%s/%s: 0 endpoints (DEAD)", svc.Namespace, svc.Name))))
+					sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-SVC-001", "CRITICAL", fmt.Sprintf("%s/%s: 0 endpoints (DEAD)", svc.Namespace, svc.Name))))
 					deadServices++
-					findings++
+					findingCount++
+					steps = append(steps, nextStep("diagnose_service", "Service has 0 endpoints", "namespace", svc.Namespace, "service_name", svc.Name))
 				} else if epHealth.NotReadyCount > 0 {
-					sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%s/%s: %d/%d not ready (DEGRADED)", svc.Namespace, svc.Name, epHealth.NotReadyCount, epHealth.TotalEndpoints))))
+					sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-SVC-002", "WARNING", fmt.Sprintf("%s/%s: %d/%d not ready (DEGRADED)", svc.Namespace, svc.Name, epHealth.NotReadyCount, epHealth.TotalEndpoints))))
 					degradedServices++
-					findings++
+					findingCount++
 				}
 			}
-			if deadServices == 0 && degradedServices == 0 {
+			if failedLookups > 0 {
+				gaps.record("Service Endpoint Health", fmt.Errorf("endpoints of %d service(s) could not be read: %w", failedLookups, lookupErr))
+			}
+			if deadServices == 0 && degradedServices == 0 && failedLookups == 0 {
 				sb.WriteString(fmt.Sprintf("  All %d services with selectors have healthy endpoints\n", len(services)))
 			}
 		}
 
+		progress.Report(ctx, 4, overviewStages, fmt.Sprintf("Services scanned %d/%d", len(services), len(services)))
+
 		// 5. Warning events (last hour)
 		events, err := client.ListEvents(ctx, "", metav1.ListOptions{})
+		gaps.record("Recent Warnings", err)
 		if err == nil {
 			sb.WriteString("\n")
 			sb.WriteString(util.FormatSubHeader("Recent Warnings (last hour)"))
@@ -837,14 +915,19 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 				for reason, count := range reasonCounts {
 					sb.WriteString(fmt.Sprintf("    %s: %d\n", reason, count))
 				}
-				findings++
+				sb.WriteString("  Run analyze_events for grouping by workload and spike detection\n")
+				findingCount++
+				steps = append(steps, nextStep("analyze_events", "Group the warning events by workload and detect spikes"))
 			} else {
 				sb.WriteString("  No warning events in the last hour\n")
 			}
 		}
 
+		progress.Report(ctx, 5, overviewStages, "Checked warning events")
+
 		// 6. kube-system check
 		ksPods, err := client.ListPods(ctx, "kube-system", metav1.ListOptions{})
+		gaps.record("kube-system Health", err)
 		if err == nil {
 			sb.WriteString("\n")
 			sb.WriteString(util.FormatSubHeader("kube-system Health"))
@@ -856,27 +939,44 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 				}
 			}
 			if ksUnhealthy > 0 {
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("%d/%d unhealthy", ksUnhealthy, len(ksPods)))))
-				findings++
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-SYS-001", "CRITICAL", fmt.Sprintf("%d/%d unhealthy", ksUnhealthy, len(ksPods)))))
+				findingCount++
+				steps = append(steps, nextStep("diagnose_namespace", "System pods are unhealthy", "namespace", "kube-system"))
 			} else {
 				sb.WriteString(fmt.Sprintf("  All %d pods healthy\n", len(ksPods)))
 			}
 		}
 
+		if ctx.Err() != nil {
+			return util.HandleK8sError("building cluster health overview", ctx.Err()), nil, nil
+		}
+		progress.Report(ctx, 6, overviewStages, "Checked kube-system")
+
+		// The ingress layer of the topology is fetched before the gaps are
+		// written so a failure there is reported too.
+		ingresses, ingressErr := client.ListIngresses(ctx, "", metav1.ListOptions{})
+		gaps.record("Ingresses (topology)", ingressErr)
+		gaps.write(&sb)
+
 		// 7. Overall
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
 		sb.WriteString("\n")
-		if findings == 0 {
+		switch {
+		case findingCount > 0:
+			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findingCount))
+		case len(gaps) > 0:
+			sb.WriteString("  No issues found in the sections that could be collected.\n")
+		default:
 			sb.WriteString("  Cluster is healthy. No issues found.\n")
-		} else {
-			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findings))
 		}
+		sb.WriteString(gaps.assessment())
 
 		// 8. Mermaid cluster topology
 		sb.WriteString("\nCLUSTER TOPOLOGY:\n")
-		fc := mermaid.NewFlowchart(mermaid.DirectionTB)
+		fc := newBudgetedFlowchart(mermaid.DirectionTB, input.MaxDiagramNodes)
 		fc.AddSubgraph("cluster", "AKS Cluster", func(sg *mermaid.Subgraph) {
+			sg.SetOverflowNoun("nodes")
 			for i, n := range nodes {
 				nodeID := mermaid.SafeID(fmt.Sprintf("node_%d", i))
 				status := nodeStatus(&n)
@@ -885,8 +985,7 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 		})
 
 		// Add ingress layer if present
-		ingresses, err := client.ListIngresses(ctx, "", metav1.ListOptions{})
-		if err == nil && len(ingresses) > 0 {
+		if ingressErr == nil && len(ingresses) > 0 {
 			fc.AddNode("internet", "Internet", mermaid.ShapeCircle)
 			for i, ing := range ingresses {
 				if i >= 5 {
@@ -904,15 +1003,17 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 		}
 
 		sb.WriteString(fc.RenderBlock())
+		progress.Report(ctx, overviewStages, overviewStages, "Done")
 
-		return util.SuccessResult(sb.String()), nil, nil
+		return util.WithNextSteps(finishReport(sb.String(), detail), steps), nil, nil
 	})
 
 	// analyze_service_logs — search pod logs for error patterns
 	mcp.AddTool(server, &mcp.Tool{
 		Name: "analyze_service_logs",
 		Description: "Search pod logs for a deployment for error patterns (errors, exceptions, timeouts, stack traces). " +
-			"Aggregates error counts by type across all pods. Use this when investigating application-level issues.",
+			"Aggregates error counts by type across all pods. JSON logs are detected automatically and summarized by level and error message with trace IDs; " +
+			"filter narrows them by field (e.g. level=error, status>=500). Use this when investigating application-level issues.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeServiceLogsInput) (*mcp.CallToolResult, any, error) {
 		// Find pods for the deployment
 		deployments, err := client.ListDeployments(ctx, input.Namespace, metav1.ListOptions{})
@@ -950,23 +1051,29 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 
 		pattern := input.Pattern
 		if pattern == "" {
-			pattern = `(?i)(error|exception|fatal|panic|timeout|refused|failed|crash|oom)`
+			pattern = logErrorPattern.String()
 		}
 		re, err := regexp.Compile(pattern)
 		if err != nil {
 			return util.ErrorResult("Invalid pattern: %v", err), nil, nil
 		}
 
+		filters, err := parseLogFilter(input.Filter)
+		if err != nil {
+			return util.ErrorResult("%v", err), nil, nil
+		}
+
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Service Log Analysis: %s (namespace: %s)", input.DeploymentName, input.Namespace)))
 		sb.WriteString("\n")
 		sb.WriteString(fmt.Sprintf("Pattern: %s\n", pattern))
 		sb.WriteString(fmt.Sprintf("Pods: %d, Lines per pod: %d\n\n", len(pods), tailLines))
 
-		totalMatches := 0
-		errorCounts := make(map[string]int)
-		podMatches := make(map[string]int)
-
+		type podLogs struct {
+			pod   string
+			lines []string
+		}
+		var fetched []podLogs
 		for i := range pods {
 			p := &pods[i]
 			for _, c := range p.Spec.Containers {
@@ -975,16 +1082,23 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 					sb.WriteString(fmt.Sprintf("[%s/%s] Could not get logs: %v\n", p.Name, c.Name, err))
 					continue
 				}
-				lines := strings.Split(logs, "\n")
-				for _, line := range lines {
-					if re.MatchString(line) {
-						totalMatches++
-						podMatches[p.Name]++
-						// Categorize by matched word
-						matches := re.FindStringSubmatch(line)
-						if len(matches) > 0 {
-							errorCounts[strings.ToLower(matches[0])]++
-						}
+				fetched = append(fetched, podLogs{pod: p.Name, lines: strings.Split(logs, "\n")})
+			}
+		}
+
+		totalMatches := 0
+		errorCounts := make(map[string]int)
+		podMatches := make(map[string]int)
+
+		for _, f := range fetched {
+			for _, line := range f.lines {
+				if re.MatchString(line) {
+					totalMatches++
+					podMatches[f.pod]++
+					// Categorize by matched word
+					matches := re.FindStringSubmatch(line)
+					if len(matches) > 0 {
+						errorCounts[strings.ToLower(matches[0])]++
 					}
 				}
 			}
@@ -1019,38 +1133,108 @@ func registerCompositeDiagnosticTools(server *mcp.Server, client *k8s.ClusterCli
 
 			// Sample lines
 			sb.WriteString("\nSample Matching Lines (first 5 per pod):\n")
-			for i := range pods {
-				p := &pods[i]
-				shown := 0
-				for _, c := range p.Spec.Containers {
-					if shown >= 5 {
+			shown := make(map[string]int)
+			for _, f := range fetched {
+				for _, line := range f.lines {
+					if shown[f.pod] >= 5 {
 						break
 					}
-					logs, err := client.GetPodLogs(ctx, input.Namespace, p.Name, c.Name, tailLines, false, "")
-					if err != nil {
-						continue
-					}
-					for _, line := range strings.Split(logs, "\n") {
-						if shown >= 5 {
-							break
-						}
-						if re.MatchString(line) {
-							truncated := line
-							if len(truncated) > 200 {
-								truncated = truncated[:200] + "..."
-							}
-							sb.WriteString(fmt.Sprintf("  [%s] %s\n", p.Name, truncated))
-							shown++
+					if re.MatchString(line) {
+						truncated := line
+						if len(truncated) > 200 {
+							truncated = truncated[:200] + "..."
 						}
+						sb.WriteString(fmt.Sprintf("  [%s] %s\n", f.pod, truncated))
+						shown[f.pod]++
 					}
 				}
 			}
 		}
 
+		// Structured (JSON) logs
+		var parsed []jsonLogLine
+		nonEmpty := 0
+		for _, f := range fetched {
+			for _, line := range f.lines {
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				nonEmpty++
+				if l, ok := parseJSONLogLine(f.pod, line); ok {
+					parsed = append(parsed, l)
+				}
+			}
+		}
+		if nonEmpty == 0 || float64(len(parsed)) < jsonLogDetectRatio*float64(nonEmpty) {
+			if len(filters) > 0 {
+				sb.WriteString(fmt.Sprintf("\nFilter %q ignored: %d of %d lines are JSON, too few for structured analysis.\n", input.Filter, len(parsed), nonEmpty))
+			}
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		summary := summarizeJSONLogs(parsed)
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader(fmt.Sprintf("Structured Logs (%d of %d lines are JSON)", len(parsed), nonEmpty)))
+		sb.WriteString("\n\nBy Level:\n")
+		for _, level := range sortedKeysInt(summary.Levels) {
+			sb.WriteString(fmt.Sprintf("  %-20s %d\n", level, summary.Levels[level]))
+		}
+		if len(summary.Errors) > 0 {
+			sb.WriteString("\nTop Error Messages:\n")
+			for i, g := range summary.Errors {
+				if i == 10 {
+					sb.WriteString(fmt.Sprintf("  ... and %d more distinct messages\n", len(summary.Errors)-i))
+					break
+				}
+				sb.WriteString(fmt.Sprintf("  %5dx %s (pods: %d)\n", g.Count, truncateName(g.Message, 160), len(g.Pods)))
+				if len(g.TraceIDs) > 0 {
+					sb.WriteString(fmt.Sprintf("         trace_ids: %s\n", strings.Join(g.TraceIDs, ", ")))
+				}
+			}
+		}
+
+		if len(filters) > 0 {
+			var matched []jsonLogLine
+			for _, l := range parsed {
+				ok := true
+				for _, f := range filters {
+					if !f.Matches(l) {
+						ok = false
+						break
+					}
+				}
+				if ok {
+					matched = append(matched, l)
+				}
+			}
+			sb.WriteString(fmt.Sprintf("\nFilter %s: %d matching lines\n", input.Filter, len(matched)))
+			for i, l := range matched {
+				if i == 20 {
+					sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(matched)-i))
+					break
+				}
+				sb.WriteString(fmt.Sprintf("  [%s] %s\n", l.Pod, formatJSONLogLine(l)))
+			}
+		}
+
 		return util.SuccessResult(sb.String()), nil, nil
 	})
 }
 
+// reportDetail resolves a report tool's detail_level; summary_only selects the
+// summary when detail_level is not set.
+func reportDetail(detail string, summaryOnly bool) (util.DetailLevel, error) {
+	if detail == "" && summaryOnly {
+		return util.DetailSummary, nil
+	}
+	return util.ParseDetailLevel(detail)
+}
+
+// finishReport returns the report reduced to the requested detail level.
+func finishReport(report string, detail util.DetailLevel) *mcp.CallToolResult {
+	return util.SuccessResult(util.ApplyDetailLevel(report, detail))
+}
+
 // formatServicePorts returns a summary of service ports.
 func formatServicePorts(svc *corev1.Service) string {
 	if len(svc.Spec.Ports) == 0 {
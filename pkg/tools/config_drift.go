@@ -0,0 +1,349 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type checkConfigDriftInput struct {
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Deployment     string `json:"deployment,omitempty" jsonschema:"Only check this Deployment (default: all Deployments in the namespace)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// configRef is a ConfigMap or Secret consumed by a pod template.
+type configRef struct {
+	Kind string // ConfigMap or Secret
+	Name string
+	// Refreshes is true when the only consumption is a whole-volume mount,
+	// which the kubelet updates in place. Env vars and subPath mounts are
+	// fixed at container start.
+	Refreshes bool
+}
+
+// Key returns the Kind/Name identifier of the reference.
+func (r configRef) Key() string {
+	return r.Kind + "/" + r.Name
+}
+
+// configDriftFinding is a single config drift problem for a workload.
+type configDriftFinding struct {
+	severity string
+	message  string
+	action   string
+}
+
+func registerConfigDriftTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// check_config_drift
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "check_config_drift",
+		Description: "Detect replicas of the same Deployment running with different ConfigMap/Secret content. Compares each pod's container start time against the last modification time of the ConfigMaps and Secrets it consumes, and compares checksum/* pod annotations across replicas. A common cause of 'works on some pods only'. Secret values are never read.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkConfigDriftInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if input.Namespace == "" {
+			return util.ErrorResult("namespace is required"), nil, nil
+		}
+
+		deployments, err := client.ListDeployments(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing deployments", err), nil, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Config Drift: %s", input.Namespace)))
+		sb.WriteString("\n\n")
+
+		headers := []string{"DEPLOYMENT", "RUNNING PODS", "CONFIGS", "STATUS"}
+		var rows [][]string
+		var findingLines []string
+		var actions []string
+		modifiedCache := make(map[string]time.Time)
+
+		for _, d := range deployments {
+			if input.Deployment != "" && d.Name != input.Deployment {
+				continue
+			}
+			refs := podConfigRefs(d.Spec.Template.Spec)
+
+			selector, selErr := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+			if selErr != nil {
+				continue
+			}
+			pods, podErr := client.ListPods(ctx, d.Namespace, metav1.ListOptions{LabelSelector: selector.String()})
+			if podErr != nil {
+				return util.HandleK8sError("listing pods", podErr), nil, nil
+			}
+			var running []corev1.Pod
+			for _, p := range pods {
+				if p.Status.Phase == corev1.PodRunning && p.DeletionTimestamp == nil {
+					running = append(running, p)
+				}
+			}
+
+			modified := make(map[string]time.Time)
+			for _, ref := range refs {
+				if t, ok := modifiedCache[ref.Key()]; ok {
+					modified[ref.Key()] = t
+					continue
+				}
+				var meta *metav1.ObjectMeta
+				if ref.Kind == "ConfigMap" {
+					if cm, cmErr := client.GetConfigMap(ctx, d.Namespace, ref.Name); cmErr == nil {
+						meta = &cm.ObjectMeta
+					}
+				} else if s, sErr := client.GetSecret(ctx, d.Namespace, ref.Name); sErr == nil {
+					meta = &s.ObjectMeta
+				}
+				if meta != nil {
+					modifiedCache[ref.Key()] = objectLastModified(*meta)
+					modified[ref.Key()] = modifiedCache[ref.Key()]
+				}
+			}
+
+			found := analyzeConfigDrift(running, refs, modified)
+			status := "OK"
+			for _, f := range found {
+				if f.severity == "CRITICAL" {
+					status = "DRIFT"
+				} else if status == "OK" && f.severity == "WARNING" {
+					status = "STALE"
+				}
+				findingLines = append(findingLines, findings.Format("KD-WL-013", f.severity, fmt.Sprintf("%s: %s", d.Name, f.message)))
+				if f.action != "" {
+					actions = append(actions, fmt.Sprintf("%s (%s)", f.action, d.Name))
+				}
+			}
+			rows = append(rows, []string{d.Name, fmt.Sprintf("%d", len(running)), fmt.Sprintf("%d", len(refs)), status})
+		}
+
+		if len(rows) == 0 {
+			if input.Deployment != "" {
+				return util.ErrorResult("deployment %s not found in namespace %s", input.Deployment, input.Namespace), nil, nil
+			}
+			sb.WriteString("No Deployments found.\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		sb.WriteString(util.FormatTable(headers, rows))
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "All replicas were started after their ConfigMaps/Secrets were last changed"))
+			sb.WriteString("\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString(f)
+			sb.WriteString("\n")
+		}
+
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// podConfigRefs returns the ConfigMaps and Secrets a pod spec consumes, sorted by key.
+func podConfigRefs(spec corev1.PodSpec) []configRef {
+	refs := make(map[string]*configRef)
+	add := func(kind, name string, refreshes bool) {
+		key := kind + "/" + name
+		if r, ok := refs[key]; ok {
+			// Any non-refreshing consumer pins the content at container start
+			r.Refreshes = r.Refreshes && refreshes
+			return
+		}
+		refs[key] = &configRef{Kind: kind, Name: name, Refreshes: refreshes}
+	}
+
+	subPathVolumes := make(map[string]bool)
+	for _, c := range allContainers(spec) {
+		for _, m := range c.VolumeMounts {
+			if m.SubPath != "" || m.SubPathExpr != "" {
+				subPathVolumes[m.Name] = true
+			}
+		}
+	}
+
+	for _, vol := range spec.Volumes {
+		refreshes := !subPathVolumes[vol.Name]
+		if vol.ConfigMap != nil {
+			add("ConfigMap", vol.ConfigMap.Name, refreshes)
+		}
+		if vol.Secret != nil {
+			add("Secret", vol.Secret.SecretName, refreshes)
+		}
+		if vol.Projected != nil {
+			for _, src := range vol.Projected.Sources {
+				if src.ConfigMap != nil {
+					add("ConfigMap", src.ConfigMap.Name, refreshes)
+				}
+				if src.Secret != nil {
+					add("Secret", src.Secret.Name, refreshes)
+				}
+			}
+		}
+	}
+
+	for _, c := range allContainers(spec) {
+		for _, envFrom := range c.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				add("ConfigMap", envFrom.ConfigMapRef.Name, false)
+			}
+			if envFrom.SecretRef != nil {
+				add("Secret", envFrom.SecretRef.Name, false)
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				add("ConfigMap", env.ValueFrom.ConfigMapKeyRef.Name, false)
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				add("Secret", env.ValueFrom.SecretKeyRef.Name, false)
+			}
+		}
+	}
+
+	out := make([]configRef, 0, len(refs))
+	for _, r := range refs {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key() < out[j].Key() })
+	return out
+}
+
+// objectLastModified returns the most recent managed-fields update time of an
+// object, falling back to its creation time.
+func objectLastModified(meta metav1.ObjectMeta) time.Time {
+	latest := meta.CreationTimestamp.Time
+	for _, mf := range meta.ManagedFields {
+		if mf.Time != nil && mf.Time.After(latest) {
+			latest = mf.Time.Time
+		}
+	}
+	return latest
+}
+
+// configLoadTime returns when the pod's oldest running container started,
+// which is when its env and subPath config were last resolved.
+func configLoadTime(p *corev1.Pod) time.Time {
+	var oldest time.Time
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.State.Running == nil {
+			continue
+		}
+		if t := cs.State.Running.StartedAt.Time; oldest.IsZero() || t.Before(oldest) {
+			oldest = t
+		}
+	}
+	if oldest.IsZero() && p.Status.StartTime != nil {
+		oldest = p.Status.StartTime.Time
+	}
+	return oldest
+}
+
+// isChecksumAnnotation reports whether an annotation key carries a config hash
+// (Helm's checksum/config convention and similar).
+func isChecksumAnnotation(key string) bool {
+	k := strings.ToLower(key)
+	return strings.Contains(k, "checksum") || strings.Contains(k, "config-hash") || strings.Contains(k, "confighash")
+}
+
+// analyzeConfigDrift compares running replicas against config modification
+// times and checksum annotations.
+func analyzeConfigDrift(pods []corev1.Pod, refs []configRef, modified map[string]time.Time) []configDriftFinding {
+	var out []configDriftFinding
+	if len(pods) == 0 {
+		return out
+	}
+
+	// Checksum annotations that differ between replicas
+	values := make(map[string]map[string][]string)
+	for _, p := range pods {
+		for k, v := range p.Annotations {
+			if !isChecksumAnnotation(k) {
+				continue
+			}
+			if values[k] == nil {
+				values[k] = make(map[string][]string)
+			}
+			values[k][v] = append(values[k][v], p.Name)
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if len(values[k]) < 2 {
+			continue
+		}
+		var parts []string
+		for v, names := range values[k] {
+			parts = append(parts, fmt.Sprintf("%s on %d pod(s)", truncateName(v, 12), len(names)))
+		}
+		sort.Strings(parts)
+		out = append(out, configDriftFinding{"CRITICAL",
+			fmt.Sprintf("replicas have %d different %s values (%s) — a rollout is incomplete or stuck", len(values[k]), k, strings.Join(parts, ", ")),
+			"Check rollout status with get_deployment_detail and finish or roll back the rollout"})
+	}
+
+	// Pods started before a config change
+	for _, ref := range refs {
+		changed, ok := modified[ref.Key()]
+		if !ok {
+			continue
+		}
+		var stale []string
+		for i := range pods {
+			if t := configLoadTime(&pods[i]); !t.IsZero() && t.Before(changed) {
+				stale = append(stale, pods[i].Name)
+			}
+		}
+		if len(stale) == 0 {
+			continue
+		}
+		when := fmt.Sprintf("%s ago", util.FormatAge(changed))
+		mixed := len(stale) < len(pods)
+		switch {
+		case mixed && !ref.Refreshes:
+			out = append(out, configDriftFinding{"CRITICAL",
+				fmt.Sprintf("%d of %d replicas started before %s changed (%s) and still use the old values via env/subPath: %s",
+					len(stale), len(pods), ref.Key(), when, strings.Join(stale, ", ")),
+				fmt.Sprintf("Restart the stale replicas (kubectl rollout restart) so all pods load the current %s", ref.Key())})
+		case mixed:
+			out = append(out, configDriftFinding{"WARNING",
+				fmt.Sprintf("%d of %d replicas started before %s changed (%s); the mounted files refresh, but apps that read config only at startup differ: %s",
+					len(stale), len(pods), ref.Key(), when, strings.Join(stale, ", ")),
+				fmt.Sprintf("Confirm the app reloads %s on change, or restart the stale replicas", ref.Key())})
+		case !ref.Refreshes:
+			out = append(out, configDriftFinding{"WARNING",
+				fmt.Sprintf("all %d replicas started before %s changed (%s) and still use the old values via env/subPath", len(pods), ref.Key(), when),
+				fmt.Sprintf("Run kubectl rollout restart to pick up the current %s, and add a checksum annotation to roll pods automatically", ref.Key())})
+		default:
+			out = append(out, configDriftFinding{"INFO",
+				fmt.Sprintf("all replicas started before %s changed (%s); mounted files refresh in place, verify the app reloads them", ref.Key(), when), ""})
+		}
+	}
+
+	return out
+}
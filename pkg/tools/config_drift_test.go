@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodConfigRefs(t *testing.T) {
+	spec := corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{Name: "app-config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app"}}}},
+			{Name: "nginx-conf", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "nginx"}}}},
+		},
+		Containers: []corev1.Container{{
+			Name:         "app",
+			VolumeMounts: []corev1.VolumeMount{{Name: "app-config", MountPath: "/etc/app"}, {Name: "nginx-conf", MountPath: "/etc/nginx/nginx.conf", SubPath: "nginx.conf"}},
+			EnvFrom:      []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}}}},
+		}},
+	}
+
+	refs := make(map[string]bool)
+	for _, r := range podConfigRefs(spec) {
+		refs[r.Key()] = r.Refreshes
+	}
+	want := map[string]bool{"ConfigMap/app": true, "ConfigMap/nginx": false, "Secret/db": false}
+	for key, refreshes := range want {
+		got, ok := refs[key]
+		if !ok {
+			t.Errorf("missing reference %s", key)
+		} else if got != refreshes {
+			t.Errorf("%s: Refreshes = %v, want %v", key, got, refreshes)
+		}
+	}
+}
+
+func TestAnalyzeConfigDrift(t *testing.T) {
+	changed := time.Now().Add(-time.Hour)
+	pod := func(name string, started time.Time, checksum string) corev1.Pod {
+		p := testPod("", name, "", nil)
+		p.Annotations = map[string]string{"checksum/config": checksum}
+		p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name: "app", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(started)}},
+		}}
+		return p
+	}
+	pods := []corev1.Pod{
+		pod("api-a", changed.Add(-time.Hour), "aaa"),
+		pod("api-b", changed.Add(time.Minute), "bbb"),
+	}
+	refs := []configRef{{Kind: "Secret", Name: "db", Refreshes: false}}
+
+	findings := analyzeConfigDrift(pods, refs, map[string]time.Time{"Secret/db": changed})
+	critical := 0
+	for _, f := range findings {
+		if f.severity == "CRITICAL" {
+			critical++
+		}
+	}
+	if critical != 2 {
+		t.Errorf("expected checksum and stale-env drift to be CRITICAL, got %+v", findings)
+	}
+
+	fresh := []corev1.Pod{pod("api-c", changed.Add(time.Minute), "bbb")}
+	if f := analyzeConfigDrift(fresh, refs, map[string]time.Time{"Secret/db": changed}); len(f) != 0 {
+		t.Errorf("expected no drift for pods started after the change, got %+v", f)
+	}
+}
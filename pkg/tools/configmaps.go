@@ -0,0 +1,398 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// configMapMaxBytes is the API server's size limit for a ConfigMap.
+	configMapMaxBytes = 1 << 20
+
+	// configMapValuePreview caps each value shown by get_configmap_detail.
+	configMapValuePreview = 2048
+)
+
+// configMapSystemNamespaces hold ConfigMaps read by control-plane components
+// through the API rather than mounts, so find_unused_configmaps skips them by default.
+var configMapSystemNamespaces = map[string]bool{"kube-system": true, "kube-public": true, "kube-node-lease": true}
+
+type listConfigMapsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+	Limit          int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken  string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
+}
+
+type getConfigMapDetailInput struct {
+	Namespace  string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Name       string `json:"name" jsonschema:"required,ConfigMap name"`
+	ShowValues bool   `json:"show_values,omitempty" jsonschema:"Include values (first 2KB of each; credential-like keys and values stay redacted unless the server runs with --no-redact). Default shows keys and sizes only"`
+}
+
+type findUnusedConfigMapsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces)"`
+	IncludeSystem  bool   `json:"include_system,omitempty" jsonschema:"Also check kube-system, kube-public, and kube-node-lease, where control-plane components read ConfigMaps through the API"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+func registerConfigMapTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// list_configmaps
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_configmaps",
+		Description: "List ConfigMaps with key count, total size, and age. Values are not shown.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input listConfigMapsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+		page, err := client.ListConfigMapsPage(ctx, ns, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
+		if err != nil {
+			return util.HandleK8sError("listing configmaps", err), nil, nil
+		}
+		cms := page.Items
+		sortConfigMaps(cms)
+
+		headers := []string{"NAMESPACE", "NAME", "KEYS", "SIZE", "AGE"}
+		rows := make([][]string, 0, len(cms))
+		for _, cm := range cms {
+			rows = append(rows, []string{
+				cm.Namespace, cm.Name,
+				fmt.Sprintf("%d", len(cm.Data)+len(cm.BinaryData)),
+				formatBytes(configMapSize(&cm)),
+				util.FormatAge(cm.CreationTimestamp.Time),
+			})
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("ConfigMaps (%d) in %s", len(cms), displayNS(input.Namespace))))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatTable(headers, rows))
+		sb.WriteString(pageFooter("list_configmaps", page.Continue, page.Remaining))
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+
+	// get_configmap_detail
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "get_configmap_detail",
+		Description: "Show a ConfigMap's keys with per-key sizes, total size against the 1MiB limit, labels, owner, and the pods and " +
+			"workloads that consume it. Values are hidden unless show_values=true, and credential-like keys and values are redacted unless the server runs with --no-redact.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input getConfigMapDetailInput) (*mcp.CallToolResult, any, error) {
+		if input.Namespace == "" || input.Name == "" {
+			return util.ErrorResult("namespace and name are required"), nil, nil
+		}
+		cm, err := client.GetConfigMap(ctx, input.Namespace, input.Name)
+		if err != nil {
+			return util.HandleK8sError("getting configmap", err), nil, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("ConfigMap: %s/%s", cm.Namespace, cm.Name)))
+		sb.WriteString("\n")
+		size := configMapSize(cm)
+		sb.WriteString(util.FormatKeyValue("Size", fmt.Sprintf("%s (%.1f%% of the 1MiB limit)", formatBytes(size), float64(size)/configMapMaxBytes*100)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Age", util.FormatAge(cm.CreationTimestamp.Time)))
+		sb.WriteString("\n")
+		if cm.Immutable != nil && *cm.Immutable {
+			sb.WriteString(util.FormatKeyValue("Immutable", "true"))
+			sb.WriteString("\n")
+		}
+		if owner := metav1.GetControllerOf(cm); owner != nil {
+			sb.WriteString(util.FormatKeyValue("Owner", owner.Kind+"/"+owner.Name))
+			sb.WriteString("\n")
+		}
+		if len(cm.Labels) > 0 {
+			sb.WriteString(util.FormatKeyValue("Labels", formatLabels(cm.Labels)))
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Keys"))
+		sb.WriteString("\n")
+		headers := []string{"KEY", "TYPE", "SIZE"}
+		rows := make([][]string, 0, len(cm.Data)+len(cm.BinaryData))
+		for _, k := range sortedStringKeys(cm.Data) {
+			rows = append(rows, []string{k, "text", formatBytes(int64(len(cm.Data[k])))})
+		}
+		binaryKeys := make([]string, 0, len(cm.BinaryData))
+		for k := range cm.BinaryData {
+			binaryKeys = append(binaryKeys, k)
+		}
+		sort.Strings(binaryKeys)
+		for _, k := range binaryKeys {
+			rows = append(rows, []string{k, "binary", formatBytes(int64(len(cm.BinaryData[k])))})
+		}
+		sb.WriteString(util.FormatTable(headers, rows))
+
+		if input.ShowValues && len(cm.Data) > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("Values"))
+			sb.WriteString("\n")
+			for _, k := range sortedStringKeys(cm.Data) {
+				sb.WriteString(fmt.Sprintf("%s:\n", k))
+				sb.WriteString(indentBlock(configMapValue(k, cm.Data[k]), "  "))
+			}
+		}
+
+		consumers, err := collectConfigConsumers(ctx, client, cm.Namespace)
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Consumers"))
+		sb.WriteString("\n")
+		switch users := consumers.of(cm.Namespace, "ConfigMap", cm.Name); {
+		case err != nil:
+			sb.WriteString(fmt.Sprintf("  Could not determine consumers: %v\n", err))
+		case len(users) == 0:
+			sb.WriteString("  No pod or workload references this ConfigMap (it may still be read through the API).\n")
+		default:
+			for _, u := range users {
+				sb.WriteString(fmt.Sprintf("  - %s\n", u))
+			}
+		}
+
+		if size > configMapMaxBytes*9/10 {
+			sb.WriteString("\n")
+			sb.WriteString(findings.Format("KD-CFG-001", "WARNING", "ConfigMap is over 90% of the 1MiB limit; further growth will be rejected by the API server"))
+			sb.WriteString("\n")
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+
+	// find_unused_configmaps
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "find_unused_configmaps",
+		Description: "Find orphaned ConfigMaps: ones no pod, Deployment, StatefulSet, DaemonSet, Job, or CronJob references through volumes, " +
+			"projected volumes, envFrom, env valueFrom, or container args (e.g. --configmap=ns/name). Skips kube-root-ca.crt, leader-election " +
+			"locks, ConfigMaps owned by another object, and system namespaces unless include_system=true.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input findUnusedConfigMapsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+		cms, err := client.ListConfigMaps(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing configmaps", err), nil, nil
+		}
+		consumers, err := collectConfigConsumers(ctx, client, ns)
+		if err != nil {
+			return util.HandleK8sError("listing configmap consumers", err), nil, nil
+		}
+
+		unused, skipped := unusedConfigMaps(cms, consumers, input.IncludeSystem || configMapSystemNamespaces[ns])
+		sortConfigMaps(unused)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Unused ConfigMaps in %s", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+		sb.WriteString(fmt.Sprintf("Checked %d ConfigMaps (%d skipped as system, owned, or leader-election).\n\n", len(cms)-skipped, skipped))
+
+		sb.WriteString("FINDINGS:\n")
+		if len(unused) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "Every ConfigMap is referenced by a pod or workload"))
+			sb.WriteString("\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+		var total int64
+		headers := []string{"NAMESPACE", "NAME", "KEYS", "SIZE", "AGE"}
+		rows := make([][]string, 0, len(unused))
+		for _, cm := range unused {
+			size := configMapSize(&cm)
+			total += size
+			rows = append(rows, []string{cm.Namespace, cm.Name, fmt.Sprintf("%d", len(cm.Data)+len(cm.BinaryData)), formatBytes(size), util.FormatAge(cm.CreationTimestamp.Time)})
+		}
+		sb.WriteString(findings.Format("KD-CFG-002", "INFO", fmt.Sprintf("%d ConfigMaps (%s) are not referenced by any pod or workload", len(unused), formatBytes(total))))
+		sb.WriteString("\n\n")
+		sb.WriteString(util.FormatTable(headers, rows))
+
+		sb.WriteString("\nSUGGESTED ACTIONS:\n")
+		sb.WriteString("  1. Confirm no application reads these ConfigMaps through the Kubernetes API (operators, feature-flag clients, Helm hooks)\n")
+		sb.WriteString("  2. Check whether a GitOps source or Helm release still renders them before deleting, or they will be recreated\n")
+		sb.WriteString("  3. Delete confirmed orphans with `kubectl -n <namespace> delete configmap <name>`\n")
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// configConsumers records which pods and workload templates reference each
+// ConfigMap and Secret, plus the container args of each namespace for
+// controllers that take ConfigMap names as flags.
+type configConsumers struct {
+	refs map[string][]string // namespace/Kind/name -> consumers
+	args map[string][]string // namespace -> "consumer\x00arg"
+}
+
+// of returns the consumers of a ConfigMap or Secret, sorted.
+func (c configConsumers) of(namespace, kind, name string) []string {
+	users := append([]string(nil), c.refs[namespace+"/"+kind+"/"+name]...)
+	if kind == "ConfigMap" {
+		for _, entry := range c.args[namespace] {
+			consumer, arg, _ := strings.Cut(entry, "\x00")
+			if strings.HasSuffix(arg, "/"+name) || strings.HasSuffix(arg, "="+name) {
+				users = append(users, consumer+" (container args)")
+			}
+		}
+	}
+	sort.Strings(users)
+	return dedupe(users)
+}
+
+// add records the references of one pod spec.
+func (c *configConsumers) add(namespace, consumer string, spec corev1.PodSpec) {
+	for _, ref := range podConfigRefs(spec) {
+		key := namespace + "/" + ref.Key()
+		c.refs[key] = append(c.refs[key], consumer)
+	}
+	for _, s := range spec.ImagePullSecrets {
+		key := namespace + "/Secret/" + s.Name
+		c.refs[key] = append(c.refs[key], consumer+" (imagePullSecrets)")
+	}
+	for _, ctr := range allContainers(spec) {
+		for _, arg := range append(append([]string(nil), ctr.Command...), ctr.Args...) {
+			c.args[namespace] = append(c.args[namespace], consumer+"\x00"+arg)
+		}
+	}
+}
+
+// collectConfigConsumers gathers ConfigMap and Secret references from pods and
+// from the templates of Deployments, StatefulSets, DaemonSets, Jobs, and
+// CronJobs, so workloads scaled to zero or between runs still count.
+func collectConfigConsumers(ctx context.Context, client *k8s.ClusterClient, namespace string) (configConsumers, error) {
+	c := configConsumers{refs: make(map[string][]string), args: make(map[string][]string)}
+
+	pods, err := client.ListPods(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return c, err
+	}
+	for _, p := range pods {
+		c.add(p.Namespace, "Pod/"+p.Name, p.Spec)
+	}
+	deployments, err := client.ListDeployments(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return c, err
+	}
+	for _, d := range deployments {
+		c.add(d.Namespace, "Deployment/"+d.Name, d.Spec.Template.Spec)
+	}
+	statefulSets, err := client.ListStatefulSets(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return c, err
+	}
+	for _, s := range statefulSets {
+		c.add(s.Namespace, "StatefulSet/"+s.Name, s.Spec.Template.Spec)
+	}
+	daemonSets, err := client.ListDaemonSets(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return c, err
+	}
+	for _, d := range daemonSets {
+		c.add(d.Namespace, "DaemonSet/"+d.Name, d.Spec.Template.Spec)
+	}
+	jobs, err := client.ListJobs(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return c, err
+	}
+	for _, j := range jobs {
+		c.add(j.Namespace, "Job/"+j.Name, j.Spec.Template.Spec)
+	}
+	cronJobs, err := client.ListCronJobs(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return c, err
+	}
+	for _, cj := range cronJobs {
+		c.add(cj.Namespace, "CronJob/"+cj.Name, cj.Spec.JobTemplate.Spec.Template.Spec)
+	}
+	return c, nil
+}
+
+// unusedConfigMaps returns the ConfigMaps with no consumers and the number
+// skipped because they are system, owned, or leader-election objects.
+func unusedConfigMaps(cms []corev1.ConfigMap, consumers configConsumers, includeSystem bool) ([]corev1.ConfigMap, int) {
+	var unused []corev1.ConfigMap
+	skipped := 0
+	for _, cm := range cms {
+		if cm.Name == "kube-root-ca.crt" || len(cm.OwnerReferences) > 0 ||
+			cm.Annotations["control-plane.alpha.kubernetes.io/leader"] != "" ||
+			(!includeSystem && configMapSystemNamespaces[cm.Namespace]) {
+			skipped++
+			continue
+		}
+		if len(consumers.of(cm.Namespace, "ConfigMap", cm.Name)) == 0 {
+			unused = append(unused, cm)
+		}
+	}
+	return unused, skipped
+}
+
+// configMapSize returns the bytes of keys and values stored in a ConfigMap.
+func configMapSize(cm *corev1.ConfigMap) int64 {
+	var size int64
+	for k, v := range cm.Data {
+		size += int64(len(k) + len(v))
+	}
+	for k, v := range cm.BinaryData {
+		size += int64(len(k) + len(v))
+	}
+	return size
+}
+
+// configMapValue returns a value for display, redacting credentials under
+// the redaction policy and truncating long values.
+func configMapValue(key, value string) string {
+	if util.IsSensitive(key, value) {
+		return util.Redacted + "\n"
+	}
+	if len(value) > configMapValuePreview {
+		value = value[:configMapValuePreview] + fmt.Sprintf("\n... [%s more]", formatBytes(int64(len(value)-configMapValuePreview)))
+	}
+	if !strings.HasSuffix(value, "\n") {
+		value += "\n"
+	}
+	return value
+}
+
+// sortConfigMaps sorts ConfigMaps by namespace and name.
+func sortConfigMaps(cms []corev1.ConfigMap) {
+	sort.Slice(cms, func(i, j int) bool {
+		if cms[i].Namespace != cms[j].Namespace {
+			return cms[i].Namespace < cms[j].Namespace
+		}
+		return cms[i].Name < cms[j].Name
+	})
+}
+
+// sortedStringKeys returns the keys of a string map in sorted order.
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// indentBlock prefixes every line of s with indent.
+func indentBlock(s, indent string) string {
+	lines := strings.SplitAfter(s, "\n")
+	var sb strings.Builder
+	for _, l := range lines {
+		if l == "" {
+			continue
+		}
+		sb.WriteString(indent + l)
+	}
+	return sb.String()
+}
+
+// formatLabels renders labels as sorted key=value pairs.
+func formatLabels(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for _, k := range sortedStringKeys(m) {
+		pairs = append(pairs, k+"="+m[k])
+	}
+	return strings.Join(pairs, ", ")
+}
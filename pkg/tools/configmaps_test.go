@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUnusedConfigMaps(t *testing.T) {
+	consumers := configConsumers{refs: make(map[string][]string), args: make(map[string][]string)}
+	consumers.add("shop", "Deployment/web", corev1.PodSpec{
+		Volumes: []corev1.Volume{{Name: "cfg", VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"}},
+		}}},
+		Containers: []corev1.Container{{
+			Name: "app",
+			Args: []string{"--configmap=$(POD_NAMESPACE)/ingress-config"},
+		}},
+	})
+
+	cm := func(ns, name string) corev1.ConfigMap {
+		return corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+	owned := cm("shop", "operator-state")
+	owned.OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: "operator"}}
+	cms := []corev1.ConfigMap{
+		cm("shop", "web-config"),
+		cm("shop", "ingress-config"),
+		cm("shop", "kube-root-ca.crt"),
+		cm("shop", "old-config"),
+		cm("kube-system", "coredns-custom"),
+		owned,
+	}
+
+	unused, skipped := unusedConfigMaps(cms, consumers, false)
+	if len(unused) != 1 || unused[0].Name != "old-config" {
+		t.Errorf("expected only old-config to be unused, got %v", unused)
+	}
+	if skipped != 3 {
+		t.Errorf("expected root CA, system, and owned ConfigMaps to be skipped, got %d", skipped)
+	}
+	if users := consumers.of("shop", "ConfigMap", "ingress-config"); len(users) != 1 || !strings.Contains(users[0], "container args") {
+		t.Errorf("expected args reference to count as a consumer, got %v", users)
+	}
+}
+
+func TestConfigMapValue(t *testing.T) {
+	if got := configMapValue("DB_PASSWORD", "hunter2"); got != "<redacted>\n" {
+		t.Errorf("expected credential-like key to be redacted, got %q", got)
+	}
+	if got := configMapValue("app.yaml", strings.Repeat("x", 3000)); !strings.Contains(got, "more]") || len(got) > 2100 {
+		t.Errorf("expected long value to be truncated, got %d bytes", len(got))
+	}
+}
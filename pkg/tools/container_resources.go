@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// Container roles reported by the per-container resource breakdown.
+const (
+	containerRoleApp           = "app"
+	containerRoleSidecar       = "sidecar"
+	containerRoleNativeSidecar = "native-sidecar"
+	containerRoleInit          = "init"
+)
+
+// knownSidecarNames are container names injected by meshes, log shippers,
+// and secret agents that run next to the application container.
+var knownSidecarNames = map[string]bool{
+	"istio-proxy":             true,
+	"linkerd-proxy":           true,
+	"envoy":                   true,
+	"daprd":                   true,
+	"vault-agent":             true,
+	"cloud-sql-proxy":         true,
+	"cloudsql-proxy":          true,
+	"fluent-bit":              true,
+	"fluentd":                 true,
+	"otel-collector":          true,
+	"datadog-agent":           true,
+	"azure-workload-identity": true,
+}
+
+// podContainer is one container of a pod with its role.
+type podContainer struct {
+	Name      string
+	Role      string
+	Resources corev1.ResourceRequirements
+}
+
+// containerFinding is a check result under its catalog rule ID.
+type containerFinding struct {
+	rule     string
+	severity string
+	message  string
+}
+
+// containerUsage is the metrics-server usage of one container.
+type containerUsage struct {
+	CPUMillis int64
+	MemBytes  int64
+}
+
+// isNativeSidecar reports whether an init container is a native sidecar
+// (restartPolicy: Always), which keeps running for the life of the pod.
+func isNativeSidecar(c *corev1.Container) bool {
+	return c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+// podContainers lists a pod's init containers followed by its regular
+// containers, each classified as app, sidecar, native-sidecar, or init.
+func podContainers(p *corev1.Pod) []podContainer {
+	out := make([]podContainer, 0, len(p.Spec.InitContainers)+len(p.Spec.Containers))
+	for i := range p.Spec.InitContainers {
+		c := &p.Spec.InitContainers[i]
+		role := containerRoleInit
+		if isNativeSidecar(c) {
+			role = containerRoleNativeSidecar
+		}
+		out = append(out, podContainer{Name: c.Name, Role: role, Resources: c.Resources})
+	}
+	for i := range p.Spec.Containers {
+		c := &p.Spec.Containers[i]
+		role := containerRoleApp
+		if knownSidecarNames[c.Name] || strings.HasSuffix(c.Name, "-sidecar") {
+			role = containerRoleSidecar
+		}
+		out = append(out, podContainer{Name: c.Name, Role: role, Resources: c.Resources})
+	}
+	return out
+}
+
+// runsWithPod reports whether a container runs for the life of the pod and
+// so counts toward its steady-state resource footprint.
+func (c podContainer) runsWithPod() bool {
+	return c.Role != containerRoleInit
+}
+
+// containerUsageByPod indexes pod metrics by pod name and container name.
+func containerUsageByPod(metrics []metricsv1beta1.PodMetrics) map[string]map[string]containerUsage {
+	out := make(map[string]map[string]containerUsage, len(metrics))
+	for _, pm := range metrics {
+		byContainer := make(map[string]containerUsage, len(pm.Containers))
+		for _, c := range pm.Containers {
+			byContainer[c.Name] = containerUsage{CPUMillis: c.Usage.Cpu().MilliValue(), MemBytes: c.Usage.Memory().Value()}
+		}
+		out[pm.Name] = byContainer
+	}
+	return out
+}
+
+// containerResourceRow formats one row of the per-container breakdown and
+// returns any findings for the container.
+func containerResourceRow(pod string, c podContainer, usage containerUsage, hasUsage bool) ([]string, []containerFinding) {
+	cpuReq := c.Resources.Requests.Cpu().MilliValue()
+	cpuLim := c.Resources.Limits.Cpu().MilliValue()
+	memReq := c.Resources.Requests.Memory().Value()
+	memLim := c.Resources.Limits.Memory().Value()
+
+	cpuUse, memUse := "-", "-"
+	if hasUsage {
+		cpuUse = fmt.Sprintf("%dm", usage.CPUMillis)
+		memUse = formatBytes(usage.MemBytes)
+	}
+	row := []string{
+		truncateName(pod, 35),
+		c.Name,
+		c.Role,
+		fmt.Sprintf("%s/%dm/%dm", cpuUse, cpuReq, cpuLim),
+		fmt.Sprintf("%s/%s/%s", memUse, formatBytes(memReq), formatBytes(memLim)),
+	}
+
+	// Regular init containers finish before the app starts; limits and usage do not apply.
+	if !c.runsWithPod() {
+		return row, nil
+	}
+	var found []containerFinding
+	var missing []string
+	if cpuLim == 0 {
+		missing = append(missing, "CPU limit")
+	}
+	if memLim == 0 {
+		missing = append(missing, "memory limit")
+	}
+	if cpuReq == 0 {
+		missing = append(missing, "CPU request")
+	}
+	if memReq == 0 {
+		missing = append(missing, "memory request")
+	}
+	if len(missing) > 0 {
+		rule := "KD-RES-001"
+		if cpuReq == 0 || memReq == 0 {
+			rule = "KD-RES-002"
+		}
+		found = append(found, containerFinding{rule, "WARNING", fmt.Sprintf("%s container '%s' in pod '%s' has no %s", c.Role, c.Name, pod, strings.Join(missing, ", "))})
+	}
+	if hasUsage && memLim > 0 && float64(usage.MemBytes)/float64(memLim)*100 > 90 {
+		found = append(found, containerFinding{"KD-RES-006", "CRITICAL", fmt.Sprintf("%s container '%s' in pod '%s' memory at %.1f%% of limit (%s/%s) - OOM risk",
+			c.Role, c.Name, pod, float64(usage.MemBytes)/float64(memLim)*100, formatBytes(usage.MemBytes), formatBytes(memLim))})
+	}
+	if hasUsage && cpuLim > 0 && float64(usage.CPUMillis)/float64(cpuLim)*100 > 90 {
+		found = append(found, containerFinding{"KD-RES-006", "CRITICAL", fmt.Sprintf("%s container '%s' in pod '%s' CPU at %.1f%% of limit (%dm/%dm) - throttling",
+			c.Role, c.Name, pod, float64(usage.CPUMillis)/float64(cpuLim)*100, usage.CPUMillis, cpuLim)})
+	}
+	return row, found
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPodContainersRoles(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "migrate"},
+			{Name: "log-shipper", RestartPolicy: &always},
+		},
+		Containers: []corev1.Container{
+			{Name: "app"},
+			{Name: "istio-proxy"},
+		},
+	}}
+
+	want := map[string]string{
+		"migrate":     containerRoleInit,
+		"log-shipper": containerRoleNativeSidecar,
+		"app":         containerRoleApp,
+		"istio-proxy": containerRoleSidecar,
+	}
+	containers := podContainers(pod)
+	if len(containers) != len(want) {
+		t.Fatalf("expected %d containers, got %d", len(want), len(containers))
+	}
+	for _, c := range containers {
+		if c.Role != want[c.Name] {
+			t.Errorf("container %s role = %s, want %s", c.Name, c.Role, want[c.Name])
+		}
+	}
+}
+
+func TestContainerResourceRowFindings(t *testing.T) {
+	hot := podContainer{Name: "app", Role: containerRoleApp, Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("100Mi")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m"), corev1.ResourceMemory: resource.MustParse("100Mi")},
+	}}
+	_, findings := containerResourceRow("web-1", hot, containerUsage{CPUMillis: 50, MemBytes: 98 * 1024 * 1024}, true)
+	if len(findings) != 1 || findings[0].severity != "CRITICAL" {
+		t.Errorf("expected one CRITICAL memory finding, got %+v", findings)
+	}
+
+	_, findings = containerResourceRow("web-1", podContainer{Name: "istio-proxy", Role: containerRoleSidecar}, containerUsage{}, false)
+	if len(findings) != 1 || findings[0].severity != "WARNING" {
+		t.Errorf("expected missing-limits WARNING for sidecar, got %+v", findings)
+	}
+
+	_, findings = containerResourceRow("web-1", podContainer{Name: "migrate", Role: containerRoleInit}, containerUsage{}, false)
+	if len(findings) != 0 {
+		t.Errorf("expected no findings for completed init container, got %+v", findings)
+	}
+}
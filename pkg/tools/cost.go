@@ -0,0 +1,318 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/pricing"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// costCPUShare is the fraction of a node's price attributed to CPU; the rest
+// is attributed to memory.
+const costCPUShare = 0.5
+
+type estimateCostWasteInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace to analyze (empty for all namespaces)"`
+	AzurePrices    bool   `json:"azure_prices,omitempty" jsonschema:"Look up instance types missing from the price file in the public Azure Retail Prices API (requires outbound network access)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// nodeRates is the hourly cost of one CPU core and one GiB of memory on a node.
+type nodeRates struct {
+	InstanceType  string
+	Hourly        float64
+	CPUCoreHourly float64
+	MemGiBHourly  float64
+}
+
+// costWaste accumulates requested-but-unused resources and their monthly cost.
+type costWaste struct {
+	CPUMillis int64
+	MemBytes  int64
+	Monthly   float64
+	Pods      int
+}
+
+func registerCostTools(server *mcp.Server, client *k8s.ClusterClient, opts Options) {
+	azure := pricing.NewAzureRetailClient()
+
+	// estimate_cost_waste
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "estimate_cost_waste",
+		Description: "Estimate the monthly cost of over-provisioned CPU/memory. Maps node instance types (node.kubernetes.io/instance-type) to hourly prices from the server's --price-file or the Azure Retail Prices API, splits each node's price across its allocatable CPU and memory, and converts waste (requests - actual usage) into monthly figures per namespace and per workload. Requires metrics-server.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input estimateCostWasteInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+		currency := "USD"
+		if opts.PriceTable != nil {
+			currency = opts.PriceTable.Currency
+		}
+		if opts.PriceTable == nil && !input.AzurePrices {
+			return util.ErrorResult("no price source: start the server with --price-file or set azure_prices=true"), nil, nil
+		}
+
+		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing nodes", err), nil, nil
+		}
+		pods, err := client.ListPods(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		podMetrics, err := client.GetPodMetrics(ctx, ns, metav1.ListOptions{})
+		if err != nil || len(podMetrics) == 0 {
+			return util.ErrorResult("metrics-server is required to measure waste: %v", err), nil, nil
+		}
+		usage := make(map[string][2]int64)
+		for _, pm := range podMetrics {
+			var cpu, mem int64
+			for _, c := range pm.Containers {
+				cpu += c.Usage.Cpu().MilliValue()
+				mem += c.Usage.Memory().Value()
+			}
+			usage[pm.Namespace+"/"+pm.Name] = [2]int64{cpu, mem}
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Cost Waste Estimate (scope: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+
+		// --- Node pricing ---
+		sb.WriteString(util.FormatSubHeader("Node Pricing"))
+		sb.WriteString("\n")
+		rates := make(map[string]nodeRates)
+		unpriced := make(map[string]string)
+		var clusterMonthly float64
+		nodeRows := make([][]string, 0, len(nodes))
+		for i := range nodes {
+			n := &nodes[i]
+			instanceType := nodeInstanceType(n)
+			hourly, source, perr := nodeHourlyPrice(ctx, n, opts.PriceTable, azure, input.AzurePrices)
+			if perr != nil {
+				unpriced[n.Name] = fmt.Sprintf("%s (%v)", instanceType, perr)
+				nodeRows = append(nodeRows, []string{n.Name, instanceType, "-", "-", "unpriced"})
+				continue
+			}
+			r := computeNodeRates(n, instanceType, hourly)
+			rates[n.Name] = r
+			clusterMonthly += hourly * pricing.HoursPerMonth
+			nodeRows = append(nodeRows, []string{
+				n.Name, instanceType,
+				fmt.Sprintf("%.4f", hourly),
+				formatMoney(hourly*pricing.HoursPerMonth, currency),
+				source,
+			})
+		}
+		sb.WriteString(util.FormatTable([]string{"NODE", "INSTANCE TYPE", "HOURLY", "MONTHLY", "SOURCE"}, nodeRows))
+		sb.WriteString(util.FormatKeyValue("Priced node cost / month", formatMoney(clusterMonthly, currency)))
+		sb.WriteString("\n")
+
+		// --- Waste attribution ---
+		byNamespace := make(map[string]*costWaste)
+		byWorkload := make(map[string]*costWaste)
+		total := &costWaste{}
+		for i := range pods {
+			p := &pods[i]
+			if p.Status.Phase != corev1.PodRunning {
+				continue
+			}
+			r, ok := rates[p.Spec.NodeName]
+			if !ok {
+				continue
+			}
+			u, ok := usage[p.Namespace+"/"+p.Name]
+			if !ok {
+				continue
+			}
+			cpuReq, memReq := podRequests(p)
+			cpuWaste := max(cpuReq-u[0], 0)
+			memWaste := max(memReq-u[1], 0)
+			if cpuWaste == 0 && memWaste == 0 {
+				continue
+			}
+			monthly := wasteMonthlyCost(cpuWaste, memWaste, r)
+			for _, w := range []*costWaste{
+				costBucket(byNamespace, p.Namespace),
+				costBucket(byWorkload, p.Namespace+"/"+podWorkloadName(p)),
+				total,
+			} {
+				w.CPUMillis += cpuWaste
+				w.MemBytes += memWaste
+				w.Monthly += monthly
+				w.Pods++
+			}
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Waste by Namespace"))
+		sb.WriteString("\n")
+		sb.WriteString(formatCostWasteTable("NAMESPACE", byNamespace, currency, 0))
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Top Workloads by Waste"))
+		sb.WriteString("\n")
+		sb.WriteString(formatCostWasteTable("WORKLOAD", byWorkload, currency, 15))
+
+		// --- Findings ---
+		sb.WriteString("\nFINDINGS:\n")
+		findingCount := 0
+		if len(unpriced) > 0 {
+			names := make([]string, 0, len(unpriced))
+			for n := range unpriced {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			sb.WriteString(findings.Format("KD-SYS-008", "INFO", fmt.Sprintf("%d node(s) have no price; pods on them are excluded", len(unpriced))))
+			sb.WriteString("\n")
+			for _, n := range names {
+				sb.WriteString(fmt.Sprintf("  - %s: %s\n", n, unpriced[n]))
+			}
+			findingCount++
+		}
+		if clusterMonthly > 0 && total.Monthly/clusterMonthly >= 0.3 {
+			sb.WriteString(findings.Format("KD-RES-004", "WARNING", fmt.Sprintf("Over-provisioned requests cost an estimated %s/month (%.0f%% of priced node cost)",
+				formatMoney(total.Monthly, currency), total.Monthly/clusterMonthly*100)))
+			sb.WriteString("\n")
+			findingCount++
+		} else if total.Monthly > 0 {
+			sb.WriteString(findings.Format("KD-RES-004", "INFO", fmt.Sprintf("Over-provisioned requests cost an estimated %s/month", formatMoney(total.Monthly, currency))))
+			sb.WriteString("\n")
+			findingCount++
+		}
+		if findingCount == 0 {
+			sb.WriteString("  No measurable waste.\n")
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Summary"))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("  Estimated waste: %s/month across %d pod(s) (%dm CPU, %s memory requested but unused).\n",
+			formatMoney(total.Monthly, currency), total.Pods, total.CPUMillis, formatBytes(total.MemBytes)))
+		sb.WriteString(fmt.Sprintf("  Node prices are split %.0f%% CPU / %.0f%% memory. Estimates use current usage, not peaks — verify with analyze_resource_efficiency before lowering requests.\n",
+			costCPUShare*100, (1-costCPUShare)*100))
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// nodeInstanceType returns the node's instance type label.
+func nodeInstanceType(n *corev1.Node) string {
+	if t := n.Labels["node.kubernetes.io/instance-type"]; t != "" {
+		return t
+	}
+	if t := n.Labels["beta.kubernetes.io/instance-type"]; t != "" {
+		return t
+	}
+	return "unknown"
+}
+
+// nodeHourlyPrice resolves a node's hourly price from the price table, falling
+// back to the Azure Retail Prices API when allowed.
+func nodeHourlyPrice(ctx context.Context, n *corev1.Node, table *pricing.Table, azure *pricing.AzureRetailClient, useAzure bool) (float64, string, error) {
+	instanceType := nodeInstanceType(n)
+	if p, ok := table.Lookup(instanceType); ok {
+		return p, "price file", nil
+	}
+	if !useAzure {
+		return 0, "", fmt.Errorf("not in price file")
+	}
+	region := n.Labels["topology.kubernetes.io/region"]
+	if region == "" {
+		region = n.Labels["failure-domain.beta.kubernetes.io/region"]
+	}
+	if region == "" || instanceType == "unknown" {
+		return 0, "", fmt.Errorf("missing region or instance type label")
+	}
+	p, err := azure.HourlyPrice(ctx, region, instanceType)
+	if err != nil {
+		return 0, "", err
+	}
+	return p, "Azure retail", nil
+}
+
+// computeNodeRates splits a node's hourly price across its allocatable CPU and memory.
+func computeNodeRates(n *corev1.Node, instanceType string, hourly float64) nodeRates {
+	r := nodeRates{InstanceType: instanceType, Hourly: hourly}
+	if cores := float64(n.Status.Allocatable.Cpu().MilliValue()) / 1000; cores > 0 {
+		r.CPUCoreHourly = hourly * costCPUShare / cores
+	}
+	if gib := float64(n.Status.Allocatable.Memory().Value()) / (1 << 30); gib > 0 {
+		r.MemGiBHourly = hourly * (1 - costCPUShare) / gib
+	}
+	return r
+}
+
+// wasteMonthlyCost converts unused CPU millicores and memory bytes into a monthly cost.
+func wasteMonthlyCost(cpuMillis, memBytes int64, r nodeRates) float64 {
+	hourly := float64(cpuMillis)/1000*r.CPUCoreHourly + float64(memBytes)/(1<<30)*r.MemGiBHourly
+	return hourly * pricing.HoursPerMonth
+}
+
+// podRequests sums the CPU (millicores) and memory (bytes) requests of a pod's containers.
+func podRequests(p *corev1.Pod) (int64, int64) {
+	var cpu, mem int64
+	for _, c := range p.Spec.Containers {
+		cpu += c.Resources.Requests.Cpu().MilliValue()
+		mem += c.Resources.Requests.Memory().Value()
+	}
+	return cpu, mem
+}
+
+// costBucket returns the accumulator for key, creating it if needed.
+func costBucket(m map[string]*costWaste, key string) *costWaste {
+	w, ok := m[key]
+	if !ok {
+		w = &costWaste{}
+		m[key] = w
+	}
+	return w
+}
+
+// formatCostWasteTable renders waste buckets sorted by monthly cost; limit 0 shows all.
+func formatCostWasteTable(label string, m map[string]*costWaste, currency string, limit int) string {
+	if len(m) == 0 {
+		return "  No waste measured.\n"
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if m[keys[i]].Monthly != m[keys[j]].Monthly {
+			return m[keys[i]].Monthly > m[keys[j]].Monthly
+		}
+		return keys[i] < keys[j]
+	})
+	if limit > 0 && len(keys) > limit {
+		keys = keys[:limit]
+	}
+	rows := make([][]string, 0, len(keys))
+	for _, k := range keys {
+		w := m[k]
+		rows = append(rows, []string{
+			truncateName(k, 50),
+			fmt.Sprintf("%d", w.Pods),
+			fmt.Sprintf("%dm", w.CPUMillis),
+			formatBytes(w.MemBytes),
+			formatMoney(w.Monthly, currency),
+		})
+	}
+	return util.FormatTable([]string{label, "PODS", "CPU WASTE", "MEM WASTE", "COST/MONTH"}, rows)
+}
+
+// formatMoney formats an amount with its currency code.
+func formatMoney(amount float64, currency string) string {
+	if currency == "USD" {
+		return fmt.Sprintf("$%.2f", amount)
+	}
+	return fmt.Sprintf("%.2f %s", amount, currency)
+}
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"math"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/pricing"
+)
+
+func TestComputeNodeRatesAndWasteCost(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "n1", Labels: map[string]string{"node.kubernetes.io/instance-type": "Standard_D4s_v3"}},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("16Gi"),
+		}},
+	}
+	if got := nodeInstanceType(node); got != "Standard_D4s_v3" {
+		t.Fatalf("nodeInstanceType() = %q", got)
+	}
+
+	r := computeNodeRates(node, "Standard_D4s_v3", 0.2)
+	if math.Abs(r.CPUCoreHourly-0.025) > 1e-9 {
+		t.Errorf("CPUCoreHourly = %v, want 0.025", r.CPUCoreHourly)
+	}
+	if math.Abs(r.MemGiBHourly-0.00625) > 1e-9 {
+		t.Errorf("MemGiBHourly = %v, want 0.00625", r.MemGiBHourly)
+	}
+
+	// Wasting the whole node costs the whole node price.
+	monthly := wasteMonthlyCost(4000, 16<<30, r)
+	if want := 0.2 * pricing.HoursPerMonth; math.Abs(monthly-want) > 1e-6 {
+		t.Errorf("wasteMonthlyCost() = %v, want %v", monthly, want)
+	}
+}
+
+func TestNodeHourlyPriceFromTable(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"node.kubernetes.io/instance-type": "m5.xlarge"}}}
+	table := &pricing.Table{Currency: "USD", Prices: map[string]float64{"m5.xlarge": 0.192}}
+
+	p, source, err := nodeHourlyPrice(t.Context(), node, table, nil, false)
+	if err != nil || p != 0.192 || source != "price file" {
+		t.Errorf("nodeHourlyPrice() = %v, %q, %v", p, source, err)
+	}
+	if _, _, err := nodeHourlyPrice(t.Context(), &corev1.Node{}, table, nil, false); err == nil {
+		t.Error("expected error for node without a price")
+	}
+}
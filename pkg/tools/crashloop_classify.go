@@ -0,0 +1,428 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// Crash loop root-cause categories reported by classify_crashloop.
+const (
+	crashCauseOOM        = "out of memory"
+	crashCauseLiveness   = "failing liveness probe"
+	crashCauseConfig     = "configuration error"
+	crashCauseDependency = "missing dependency"
+	crashCauseSegfault   = "native crash (segfault/abort)"
+	crashCauseApp        = "application error"
+)
+
+// crashLogTailLines is how many previous-container log lines classify_crashloop shows.
+const crashLogTailLines = 10
+
+type classifyCrashLoopInput struct {
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Name           string `json:"name" jsonschema:"required,Pod name"`
+	Container      string `json:"container,omitempty" jsonschema:"Container to classify (default: the crash-looping container with the most restarts)"`
+	LogLines       int64  `json:"log_lines,omitempty" jsonschema:"Previous-container log lines to scan (default 100)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// crashEvidence is one signal pointing at a crash cause. Weight is 3 for a
+// signal that is decisive on its own, 2 for a strong hint, and 1 for a weak one.
+type crashEvidence struct {
+	Cause  string
+	Weight int
+	Detail string
+}
+
+// crashLogPattern maps a lowercase substring of a log line to a cause.
+type crashLogPattern struct {
+	substr string
+	cause  string
+	weight int
+}
+
+// crashLogPatterns are matched against the previous container's logs. A line
+// counts for its first matching pattern, so more specific patterns come
+// first (a Go nil dereference mentions SIGSEGV but is an application bug),
+// and each cause is credited once, for its first matching line.
+var crashLogPatterns = []crashLogPattern{
+	{"invalid memory address or nil pointer dereference", crashCauseApp, 3},
+	{"[signal sigsegv", crashCauseApp, 2},
+	{"outofmemoryerror", crashCauseOOM, 3},
+	{"javascript heap out of memory", crashCauseOOM, 3},
+	{"cannot allocate memory", crashCauseOOM, 2},
+	{"out of memory", crashCauseOOM, 2},
+	{"segmentation fault", crashCauseSegfault, 3},
+	{"sigsegv", crashCauseSegfault, 3},
+	{"core dumped", crashCauseSegfault, 2},
+	{"sigabrt", crashCauseSegfault, 2},
+	{"no such host", crashCauseDependency, 3},
+	{"connection refused", crashCauseDependency, 3},
+	{"econnrefused", crashCauseDependency, 3},
+	{"enotfound", crashCauseDependency, 3},
+	{"could not connect", crashCauseDependency, 2},
+	{"unable to connect", crashCauseDependency, 2},
+	{"dial tcp", crashCauseDependency, 2},
+	{"i/o timeout", crashCauseDependency, 2},
+	{"connection reset", crashCauseDependency, 1},
+	{"environment variable", crashCauseConfig, 3},
+	{"missing required", crashCauseConfig, 3},
+	{"flag provided but not defined", crashCauseConfig, 3},
+	{"unknown flag", crashCauseConfig, 3},
+	{"invalid configuration", crashCauseConfig, 3},
+	{"no such file or directory", crashCauseConfig, 2},
+	{"permission denied", crashCauseConfig, 2},
+	{"is not set", crashCauseConfig, 2},
+	{"panic:", crashCauseApp, 2},
+	{"traceback (most recent call last)", crashCauseApp, 2},
+	{"unhandled exception", crashCauseApp, 2},
+	{"exception in thread", crashCauseApp, 2},
+}
+
+// crashClassification is the verdict for one crash-looping container.
+type crashClassification struct {
+	Cause      string
+	Confidence string
+	Evidence   []crashEvidence
+}
+
+func registerCrashLoopClassifierTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// classify_crashloop
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "classify_crashloop",
+		Description: "Classify why a container crash-loops by combining its exit code, last termination reason, previous-container logs, liveness probe config, " +
+			"and the pod's recent events. Reports the most likely cause (out of memory, failing liveness probe, configuration error, missing dependency, " +
+			"native crash, or application error) with a confidence level, the evidence behind it, and targeted next steps.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input classifyCrashLoopInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		logLines := input.LogLines
+		if logLines <= 0 {
+			logLines = 100
+		}
+
+		pod, err := client.GetPod(ctx, input.Namespace, input.Name)
+		if err != nil {
+			return util.HandleK8sError("getting pod", err), nil, nil
+		}
+		cs, spec, ok := crashLoopContainer(pod, input.Container)
+		if !ok {
+			if input.Container != "" {
+				return util.ErrorResult("pod %s/%s has no container named %q", pod.Namespace, pod.Name, input.Container), nil, nil
+			}
+			return util.ErrorResult("pod %s/%s has no containers that have restarted or exited with an error", pod.Namespace, pod.Name), nil, nil
+		}
+
+		var gaps dataGaps
+		logs := ""
+		if cs.RestartCount > 0 || cs.State.Terminated != nil {
+			var logErr error
+			logs, logErr = client.GetPodLogs(ctx, pod.Namespace, pod.Name, cs.Name, logLines, cs.State.Terminated == nil, "")
+			gaps.record("Previous logs", logErr)
+		}
+		events, evErr := client.GetEventsForObject(ctx, pod.Namespace, pod.Name)
+		gaps.record("Events", evErr)
+
+		result := classifyCrash(cs, spec, logs, events)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Crash Loop Classification: %s/%s (%s)", pod.Namespace, pod.Name, cs.Name)))
+		sb.WriteString("\n")
+		term := crashTermination(cs)
+		sb.WriteString(util.FormatKeyValue("State", containerStateLabel(cs)) + "\n")
+		sb.WriteString(util.FormatKeyValue("Restarts", fmt.Sprintf("%d", cs.RestartCount)) + "\n")
+		if term != nil {
+			sb.WriteString(util.FormatKeyValue("Last Exit", formatExitCode(term.ExitCode, term.Reason)) + "\n")
+			if meaning := exitCodeMeaning(term.ExitCode); meaning != "" {
+				sb.WriteString(util.FormatKeyValue("Meaning", meaning) + "\n")
+			}
+			if !term.StartedAt.IsZero() && !term.FinishedAt.IsZero() {
+				sb.WriteString(util.FormatKeyValue("Last Run", fmt.Sprintf("%s, ended %s ago",
+					term.FinishedAt.Sub(term.StartedAt.Time).Round(time.Second), util.FormatAge(term.FinishedAt.Time))) + "\n")
+			}
+		}
+		sb.WriteString(util.FormatKeyValue("Liveness Probe", livenessSummary(spec)) + "\n")
+		if limit, ok := spec.Resources.Limits[corev1.ResourceMemory]; ok {
+			sb.WriteString(util.FormatKeyValue("Memory Limit", limit.String()) + "\n")
+		} else {
+			sb.WriteString(util.FormatKeyValue("Memory Limit", "none") + "\n")
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("CLASSIFICATION"))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Cause", result.Cause) + "\n")
+		sb.WriteString(util.FormatKeyValue("Confidence", result.Confidence) + "\n")
+		if len(result.Evidence) > 0 {
+			sb.WriteString("  Evidence:\n")
+			for _, e := range result.Evidence {
+				sb.WriteString(fmt.Sprintf("    - [%s] %s\n", e.Cause, e.Detail))
+			}
+		}
+
+		if tail := lastLogLines(logs, crashLogTailLines); tail != "" {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader(fmt.Sprintf("PREVIOUS LOGS (last %d lines)", crashLogTailLines)))
+			sb.WriteString("\n")
+			for _, line := range strings.Split(tail, "\n") {
+				sb.WriteString("  " + truncateName(line, 200) + "\n")
+			}
+		}
+
+		findingLines, actions, steps := crashClassificationFindings(pod, cs, spec, result)
+		sb.WriteString("\nFINDINGS:\n")
+		for _, f := range findingLines {
+			sb.WriteString("  " + f + "\n")
+		}
+		sb.WriteString("\nSUGGESTED ACTIONS:\n")
+		for i, a := range dedupe(actions) {
+			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+		}
+		gaps.write(&sb)
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// crashLoopContainer picks the container to classify: the named one, or else
+// the crash-looping container (init containers included) with the most
+// restarts, falling back to any container that restarted or failed.
+func crashLoopContainer(pod *corev1.Pod, name string) (corev1.ContainerStatus, corev1.Container, bool) {
+	specs := make(map[string]corev1.Container)
+	for _, c := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		specs[c.Name] = c
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+
+	var best *corev1.ContainerStatus
+	bestLooping := false
+	for i := range statuses {
+		cs := &statuses[i]
+		if name != "" {
+			if cs.Name == name {
+				return *cs, specs[cs.Name], true
+			}
+			continue
+		}
+		failed := cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0
+		if cs.RestartCount == 0 && !failed {
+			continue
+		}
+		looping := isCrashLooping(*cs)
+		if best == nil || looping && !bestLooping || looping == bestLooping && cs.RestartCount > best.RestartCount {
+			best, bestLooping = cs, looping
+		}
+	}
+	if best == nil {
+		return corev1.ContainerStatus{}, corev1.Container{}, false
+	}
+	return *best, specs[best.Name], true
+}
+
+// crashTermination returns the termination that ended the last run: the
+// current state when the container is stopped, else the last state.
+func crashTermination(cs corev1.ContainerStatus) *corev1.ContainerStateTerminated {
+	if cs.State.Terminated != nil {
+		return cs.State.Terminated
+	}
+	return cs.LastTerminationState.Terminated
+}
+
+// classifyCrash weighs every signal for the container and picks the cause
+// with the most evidence.
+func classifyCrash(cs corev1.ContainerStatus, spec corev1.Container, logs string, events []corev1.Event) crashClassification {
+	var evidence []crashEvidence
+	add := func(cause string, weight int, format string, args ...any) {
+		evidence = append(evidence, crashEvidence{Cause: cause, Weight: weight, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	if w := cs.State.Waiting; w != nil && (w.Reason == "CreateContainerConfigError" || w.Reason == "CreateContainerError") {
+		add(crashCauseConfig, 3, "container cannot be created: %s", truncateName(w.Message, 150))
+	}
+
+	probeFailures, probeKills, _ := livenessEventCounts(events, cs.Name)
+	if probeKills > 0 {
+		add(crashCauseLiveness, 3, "kubelet restarted the container %d time(s) for failing its liveness probe", probeKills)
+	} else if probeFailures > 0 {
+		add(crashCauseLiveness, 2, "%d liveness probe failure(s) recorded in events", probeFailures)
+	}
+
+	if term := crashTermination(cs); term != nil {
+		exit := formatExitCode(term.ExitCode, term.Reason)
+		switch {
+		case term.Reason == "OOMKilled":
+			add(crashCauseOOM, 3, "last termination reason is OOMKilled")
+		case term.ExitCode == 137 && probeFailures+probeKills == 0:
+			add(crashCauseOOM, 1, "exit %s is SIGKILL with no liveness failures in events", exit)
+		case term.ExitCode == 137 || term.ExitCode == 143:
+			add(crashCauseLiveness, 1, "exit %s is the signal the kubelet sends when a liveness probe fails", exit)
+		case term.ExitCode == 139:
+			add(crashCauseSegfault, 3, "exit %s is SIGSEGV", exit)
+		case term.ExitCode == 134:
+			add(crashCauseSegfault, 2, "exit %s is SIGABRT", exit)
+		case term.ExitCode == 126 || term.ExitCode == 127:
+			add(crashCauseConfig, 3, "exit %s: %s", exit, exitCodeMeaning(term.ExitCode))
+		case term.ExitCode == 2:
+			add(crashCauseConfig, 1, "exit %s often means invalid arguments", exit)
+		case term.ExitCode != 0:
+			add(crashCauseApp, 1, "exit %s", exit)
+		}
+		if term.Reason == "ContainerCannotRun" || term.Reason == "StartError" {
+			add(crashCauseConfig, 3, "runtime could not start the process: %s", truncateName(term.Message, 150))
+		}
+	}
+
+	credited := make(map[string]bool)
+	for _, line := range strings.Split(logs, "\n") {
+		lower := strings.ToLower(line)
+		for _, p := range crashLogPatterns {
+			if !strings.Contains(lower, p.substr) {
+				continue
+			}
+			if !credited[p.cause] {
+				credited[p.cause] = true
+				add(p.cause, p.weight, "log: %s", truncateName(strings.TrimSpace(line), 150))
+			}
+			break
+		}
+	}
+
+	return weighCrashEvidence(evidence)
+}
+
+// weighCrashEvidence sums evidence per cause. Confidence is high when the
+// leader has a decisive signal and clearly outweighs the runner-up, medium
+// when it merely leads, and low otherwise.
+func weighCrashEvidence(evidence []crashEvidence) crashClassification {
+	if len(evidence) == 0 {
+		return crashClassification{Cause: crashCauseApp, Confidence: "low"}
+	}
+	scores := make(map[string]int)
+	for _, e := range evidence {
+		scores[e.Cause] += e.Weight
+	}
+	causes := sortedMapKeys(scores)
+	sort.SliceStable(causes, func(i, j int) bool { return scores[causes[i]] > scores[causes[j]] })
+	lead, runnerUp := scores[causes[0]], 0
+	if len(causes) > 1 {
+		runnerUp = scores[causes[1]]
+	}
+
+	confidence := "low"
+	switch {
+	case lead >= 3 && lead-runnerUp >= 2:
+		confidence = "high"
+	case lead >= 2 && lead > runnerUp:
+		confidence = "medium"
+	}
+
+	sort.SliceStable(evidence, func(i, j int) bool {
+		if (evidence[i].Cause == causes[0]) != (evidence[j].Cause == causes[0]) {
+			return evidence[i].Cause == causes[0]
+		}
+		return evidence[i].Weight > evidence[j].Weight
+	})
+	return crashClassification{Cause: causes[0], Confidence: confidence, Evidence: evidence}
+}
+
+// livenessSummary describes a container's liveness probe timing.
+func livenessSummary(c corev1.Container) string {
+	p := c.LivenessProbe
+	if p == nil {
+		return "none"
+	}
+	period, timeout, failure := probeTimings(p)
+	summary := fmt.Sprintf("initialDelay %ds, period %ds, timeout %ds, failureThreshold %d (restarts after ~%ds failing)",
+		p.InitialDelaySeconds, period, timeout, failure, period*failure)
+	if c.StartupProbe != nil {
+		summary += "; startupProbe set"
+	}
+	return summary
+}
+
+// lastLogLines returns the last n non-empty lines of a log.
+func lastLogLines(logs string, n int) string {
+	var lines []string
+	for _, l := range strings.Split(strings.TrimRight(logs, "\n"), "\n") {
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// containerStateLabel renders a container's current state.
+func containerStateLabel(cs corev1.ContainerStatus) string {
+	switch {
+	case cs.State.Waiting != nil:
+		return "Waiting: " + cs.State.Waiting.Reason
+	case cs.State.Terminated != nil:
+		return "Terminated: " + formatExitCode(cs.State.Terminated.ExitCode, cs.State.Terminated.Reason)
+	case cs.State.Running != nil:
+		return "Running since " + util.FormatAge(cs.State.Running.StartedAt.Time) + " ago"
+	}
+	return "Unknown"
+}
+
+// crashClassificationFindings turns the verdict into findings, cause-specific
+// actions, and next steps.
+func crashClassificationFindings(pod *corev1.Pod, cs corev1.ContainerStatus, spec corev1.Container, result crashClassification) ([]string, []string, []util.NextStep) {
+	target := fmt.Sprintf("%s/%s (%s)", pod.Namespace, pod.Name, cs.Name)
+	findingLines := []string{findings.Format("KD-POD-001", "CRITICAL",
+		fmt.Sprintf("Container %s crash-loops: most likely %s (%s confidence)", target, result.Cause, result.Confidence))}
+	var actions []string
+	steps := []util.NextStep{nextStep("get_pod_logs", "read the full output of the crashed run",
+		"namespace", pod.Namespace, "name", pod.Name, "container", cs.Name, "previous", true)}
+
+	switch result.Cause {
+	case crashCauseOOM:
+		limit := "no limit"
+		if l, ok := spec.Resources.Limits[corev1.ResourceMemory]; ok {
+			limit = "the " + l.String() + " limit"
+		}
+		findingLines = append(findingLines, findings.Format("KD-POD-003", "CRITICAL", fmt.Sprintf("Container %s runs out of memory against %s", target, limit)))
+		actions = append(actions,
+			fmt.Sprintf("Raise the memory limit of %s above its peak usage, or cap the runtime's heap (e.g. -XX:MaxRAMPercentage, --max-old-space-size) below %s.", cs.Name, limit),
+			"If usage grows steadily until the kill, look for a memory leak rather than only raising the limit.")
+		steps = append(steps, nextStep("get_pod_metrics", "compare memory usage with the limit", "namespace", pod.Namespace))
+	case crashCauseLiveness:
+		findingLines = append(findingLines, findings.Format("KD-PRB-002", "WARNING", fmt.Sprintf("The liveness probe of %s restarts the container: %s", target, livenessSummary(spec))))
+		actions = append(actions,
+			"Check that the liveness endpoint answers within the probe timeout under load and does not depend on downstream services.")
+		if spec.StartupProbe == nil {
+			actions = append(actions, fmt.Sprintf("Add a startupProbe to %s so slow starts are not killed by the liveness probe.", cs.Name))
+		}
+		steps = append(steps, nextStep("analyze_probes", "review the probe timings of this namespace", "namespace", pod.Namespace))
+	case crashCauseConfig:
+		findingLines = append(findingLines, findings.Format("KD-POD-006", "CRITICAL", fmt.Sprintf("Container %s fails on its configuration, command, or mounted files", target)))
+		actions = append(actions,
+			fmt.Sprintf("Compare the command, args, env, and mounted ConfigMaps/Secrets of %s with what the app expects; the evidence above quotes the failing line.", cs.Name),
+			"If a recent rollout changed the configuration, roll back while fixing it.")
+		steps = append(steps, nextStep("check_config_drift", "check whether the pods run the current ConfigMaps and Secrets", "namespace", pod.Namespace))
+	case crashCauseDependency:
+		actions = append(actions,
+			"Check that the dependency named in the evidence exists, has ready endpoints, and is reachable from this namespace (DNS name, port, NetworkPolicies).",
+			fmt.Sprintf("Make %s retry its connection with backoff instead of exiting, or gate startup on the dependency with an init container.", cs.Name))
+		steps = append(steps, nextStep("analyze_pod_connectivity", "check the network policies between this pod and its dependencies",
+			"namespace", pod.Namespace, "pod_name", pod.Name))
+	case crashCauseSegfault:
+		actions = append(actions,
+			"Check for a native library or base image change in the latest image; segfaults rarely come from configuration.",
+			"Compare with the previous image tag and roll back if the crash started with the new image.")
+	default:
+		actions = append(actions, fmt.Sprintf("Read the stack trace or last error in the previous logs of %s; no signal pointed at an infrastructure cause.", cs.Name))
+	}
+	return findingLines, actions, steps
+}
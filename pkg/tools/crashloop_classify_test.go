@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func crashedStatus(exitCode int32, reason string) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name:                 "api",
+		RestartCount:         6,
+		State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Reason: reason}},
+	}
+}
+
+func TestClassifyCrash(t *testing.T) {
+	livenessEvents := []corev1.Event{
+		{Reason: "Unhealthy", Message: "Liveness probe failed: HTTP probe failed with statuscode: 500", Count: 9,
+			InvolvedObject: corev1.ObjectReference{FieldPath: "spec.containers{api}"}},
+		{Reason: "Killing", Message: "Container api failed liveness probe, will be restarted", Count: 3,
+			InvolvedObject: corev1.ObjectReference{FieldPath: "spec.containers{api}"}},
+		{Reason: "Killing", Message: "Container sidecar failed liveness probe, will be restarted", Count: 4,
+			InvolvedObject: corev1.ObjectReference{FieldPath: "spec.containers{sidecar}"}},
+	}
+	tests := []struct {
+		name       string
+		status     corev1.ContainerStatus
+		logs       string
+		events     []corev1.Event
+		cause      string
+		confidence string
+	}{
+		{"oom killed", crashedStatus(137, "OOMKilled"), "", nil, crashCauseOOM, "high"},
+		{"liveness kills", crashedStatus(137, "Error"), "GET /healthz took 4.2s", livenessEvents, crashCauseLiveness, "high"},
+		{"missing env", crashedStatus(1, "Error"), "starting\nfatal: environment variable DATABASE_URL is not set\n", nil, crashCauseConfig, "high"},
+		{"bad entrypoint", crashedStatus(127, "Error"), "", nil, crashCauseConfig, "high"},
+		{"dependency", crashedStatus(1, "Error"), "dial tcp: lookup postgres.db.svc.cluster.local: no such host", nil, crashCauseDependency, "high"},
+		{"segfault", crashedStatus(139, "Error"), "", nil, crashCauseSegfault, "high"},
+		{"go nil dereference", crashedStatus(2, "Error"),
+			"panic: runtime error: invalid memory address or nil pointer dereference\n[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x4a2b1c]",
+			nil, crashCauseApp, "high"},
+		{"ambiguous sigkill", crashedStatus(137, "Error"), "", nil, crashCauseOOM, "low"},
+		{"no signal", crashedStatus(0, "Completed"), "", nil, crashCauseApp, "low"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyCrash(tt.status, corev1.Container{Name: "api"}, tt.logs, tt.events)
+			if got.Cause != tt.cause || got.Confidence != tt.confidence {
+				t.Errorf("classifyCrash = %s (%s), want %s (%s); evidence %+v", got.Cause, got.Confidence, tt.cause, tt.confidence, got.Evidence)
+			}
+			if len(got.Evidence) > 0 && got.Evidence[0].Cause != got.Cause {
+				t.Errorf("evidence for the verdict should come first: %+v", got.Evidence)
+			}
+		})
+	}
+}
+
+func TestCrashLoopContainer(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "shop"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "migrate"}},
+			Containers:     []corev1.Container{{Name: "api"}, {Name: "proxy"}},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{Name: "migrate", RestartCount: 0,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}}},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "proxy", RestartCount: 9, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				crashedStatus(1, "Error"),
+			},
+		},
+	}
+	if cs, spec, ok := crashLoopContainer(pod, ""); !ok || cs.Name != "api" || spec.Name != "api" {
+		t.Errorf("default pick = %q, want the crash-looping api container over the restarted proxy", cs.Name)
+	}
+	if cs, _, ok := crashLoopContainer(pod, "proxy"); !ok || cs.Name != "proxy" {
+		t.Errorf("named pick = %q", cs.Name)
+	}
+	if _, _, ok := crashLoopContainer(pod, "missing"); ok {
+		t.Error("an unknown container name should not match")
+	}
+}
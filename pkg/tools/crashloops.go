@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type clusterCrashLoopsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
+	LogLines       int64  `json:"log_lines,omitempty" jsonschema:"Previous-container log lines to fingerprint per pod (default 20)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// crashLoopSignature identifies a distinct failure: same image, same exit code, same log fingerprint.
+type crashLoopSignature struct {
+	Image       string
+	ExitCode    int32
+	Reason      string
+	Fingerprint string
+}
+
+// crashLoopCluster is a group of crash-looping containers sharing a signature.
+type crashLoopCluster struct {
+	Signature  crashLoopSignature
+	Sample     string // an un-normalized log line from the first pod
+	Pods       []string
+	Workloads  map[string]bool
+	Namespaces map[string]bool
+	Restarts   int32
+}
+
+// Volatile tokens replaced when fingerprinting log lines, most specific first.
+var crashLoopFingerprintReplacements = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<ts>"},
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`), "<hex>"},
+	{regexp.MustCompile(`\b[0-9a-f]{12,}\b`), "<hex>"},
+	{regexp.MustCompile(`\d+`), "<n>"},
+}
+
+func registerCrashLoopTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// cluster_crashloops
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "cluster_crashloops",
+		Description: "Group crash-looping pods by failure signature (image + exit code + fingerprint of the last log lines before the crash) so many replicas failing for the same reason are reported as one root cause. Use this instead of diagnosing crashing pods one at a time.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input clusterCrashLoopsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		logLines := input.LogLines
+		if logLines <= 0 {
+			logLines = 20
+		}
+
+		pods, err := client.ListPods(ctx, util.NamespaceOrAll(input.Namespace), metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+
+		clusters := make(map[crashLoopSignature]*crashLoopCluster)
+		crashing := 0
+		for i := range pods {
+			p := &pods[i]
+			for _, cs := range p.Status.ContainerStatuses {
+				if !isCrashLooping(cs) {
+					continue
+				}
+				crashing++
+				sig := crashLoopSignature{Image: cs.Image}
+				if t := cs.LastTerminationState.Terminated; t != nil {
+					sig.ExitCode = t.ExitCode
+					sig.Reason = t.Reason
+				}
+				sample := ""
+				if logs, logErr := client.GetPodLogs(ctx, p.Namespace, p.Name, cs.Name, logLines, true, ""); logErr == nil {
+					sample = lastLogLine(logs)
+					sig.Fingerprint = fingerprintLogLine(sample)
+				}
+
+				c, ok := clusters[sig]
+				if !ok {
+					c = &crashLoopCluster{Signature: sig, Sample: sample, Workloads: map[string]bool{}, Namespaces: map[string]bool{}}
+					clusters[sig] = c
+				}
+				c.Pods = append(c.Pods, fmt.Sprintf("%s/%s (%s)", p.Namespace, p.Name, cs.Name))
+				c.Workloads[podWorkloadName(p)] = true
+				c.Namespaces[p.Namespace] = true
+				c.Restarts += cs.RestartCount
+			}
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Crash Loop Clusters (namespace: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+
+		if crashing == 0 {
+			sb.WriteString(util.FormatFinding("OK", "No crash-looping containers found"))
+			sb.WriteString("\n")
+			return util.WithNextSteps(util.SuccessResult(sb.String()), nil), nil, nil
+		}
+
+		ordered := make([]*crashLoopCluster, 0, len(clusters))
+		for _, c := range clusters {
+			ordered = append(ordered, c)
+		}
+		sort.Slice(ordered, func(i, j int) bool {
+			if len(ordered[i].Pods) != len(ordered[j].Pods) {
+				return len(ordered[i].Pods) > len(ordered[j].Pods)
+			}
+			return ordered[i].Restarts > ordered[j].Restarts
+		})
+
+		headers := []string{"#", "CONTAINERS", "WORKLOADS", "IMAGE", "EXIT", "RESTARTS"}
+		rows := make([][]string, 0, len(ordered))
+		for i, c := range ordered {
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", i+1),
+				fmt.Sprintf("%d", len(c.Pods)),
+				strings.Join(sortedKeys(c.Workloads), ", "),
+				truncateName(c.Signature.Image, 50),
+				formatExitCode(c.Signature.ExitCode, c.Signature.Reason),
+				fmt.Sprintf("%d", c.Restarts),
+			})
+		}
+		sb.WriteString(util.FormatTable(headers, rows))
+		sb.WriteString(fmt.Sprintf("\n%d crash-looping container(s) collapse into %d distinct failure signature(s).\n", crashing, len(ordered)))
+
+		var actions []string
+		var steps []util.NextStep
+		for i, c := range ordered {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader(fmt.Sprintf("Signature %d: %d container(s)", i+1, len(c.Pods))))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatKeyValue("Image", c.Signature.Image))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatKeyValue("Exit", formatExitCode(c.Signature.ExitCode, c.Signature.Reason)))
+			sb.WriteString("\n")
+			if meaning := exitCodeMeaning(c.Signature.ExitCode); meaning != "" {
+				sb.WriteString(util.FormatKeyValue("Meaning", meaning))
+				sb.WriteString("\n")
+			}
+			if c.Sample != "" {
+				sb.WriteString(util.FormatKeyValue("Last log line", truncateName(c.Sample, 200)))
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString(util.FormatKeyValue("Last log line", "<no previous logs>"))
+				sb.WriteString("\n")
+			}
+			sb.WriteString(util.FormatKeyValue("Namespaces", strings.Join(sortedKeys(c.Namespaces), ", ")))
+			sb.WriteString("\n")
+			sb.WriteString("  Affected:\n")
+			for j, pod := range c.Pods {
+				if j >= 5 {
+					sb.WriteString(fmt.Sprintf("    ... and %d more\n", len(c.Pods)-5))
+					break
+				}
+				sb.WriteString(fmt.Sprintf("    - %s\n", pod))
+			}
+
+			severity := "WARNING"
+			if len(c.Pods) > 1 {
+				severity = "CRITICAL"
+			}
+			sb.WriteString(findings.Format("KD-POD-001", severity, fmt.Sprintf("%d container(s) crash with the same signature — treat as one root cause", len(c.Pods))))
+			sb.WriteString("\n")
+			first := strings.SplitN(c.Pods[0], " ", 2)[0]
+			actions = append(actions, fmt.Sprintf("Signature %d: run diagnose_pod on %s (representative of %d)", i+1, first, len(c.Pods)))
+			if ns, name, ok := strings.Cut(first, "/"); ok {
+				steps = append(steps, nextStep("diagnose_pod", fmt.Sprintf("Representative of signature %d (%d containers)", i+1, len(c.Pods)),
+					"namespace", ns, "name", name))
+			}
+		}
+
+		sb.WriteString("\nSUGGESTED ACTIONS:\n")
+		for i, a := range actions {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// isCrashLooping reports whether a container is in CrashLoopBackOff or keeps
+// terminating with an error.
+func isCrashLooping(cs corev1.ContainerStatus) bool {
+	if w := cs.State.Waiting; w != nil && w.Reason == "CrashLoopBackOff" {
+		return true
+	}
+	if t := cs.State.Terminated; t != nil && t.ExitCode != 0 && cs.RestartCount > 0 {
+		return true
+	}
+	return false
+}
+
+// lastLogLine returns the last non-empty line of a log.
+func lastLogLine(logs string) string {
+	lines := strings.Split(strings.TrimRight(logs, "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if l := strings.TrimSpace(lines[i]); l != "" {
+			return l
+		}
+	}
+	return ""
+}
+
+// fingerprintLogLine normalizes volatile tokens (timestamps, IDs, IPs, numbers)
+// so the same error from different pods produces the same fingerprint.
+func fingerprintLogLine(line string) string {
+	for _, r := range crashLoopFingerprintReplacements {
+		line = r.re.ReplaceAllString(line, r.repl)
+	}
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// formatExitCode renders an exit code with its termination reason.
+func formatExitCode(code int32, reason string) string {
+	if reason == "" {
+		return fmt.Sprintf("%d", code)
+	}
+	return fmt.Sprintf("%d (%s)", code, reason)
+}
+
+// exitCodeMeaning explains well-known container exit codes.
+func exitCodeMeaning(code int32) string {
+	switch code {
+	case 1:
+		return "application error (unhandled exception or explicit exit 1)"
+	case 2:
+		return "misuse of shell builtin or invalid arguments"
+	case 126:
+		return "command not executable (permissions or wrong binary format)"
+	case 127:
+		return "command not found (bad entrypoint or missing binary)"
+	case 134:
+		return "SIGABRT — the process aborted (assertion or runtime panic)"
+	case 137:
+		return "SIGKILL — usually OOMKilled or killed after failing liveness probes"
+	case 139:
+		return "SIGSEGV — segmentation fault"
+	case 143:
+		return "SIGTERM — terminated, often by a failing liveness probe or shutdown"
+	}
+	return ""
+}
+
+// sortedKeys returns the keys of a string set in sorted order.
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestFingerprintLogLine(t *testing.T) {
+	a := fingerprintLogLine(`2024-05-01T10:00:00.123Z ERROR failed to connect to 10.0.3.7:5432 after 3 attempts (req 9f1c2d3e-1111-2222-3333-444455556666)`)
+	b := fingerprintLogLine(`2024-05-01T10:07:12.999Z ERROR failed to connect to 10.0.9.12:5432 after 5 attempts (req 0a1b2c3d-aaaa-bbbb-cccc-ddddeeeeffff)`)
+	if a != b {
+		t.Errorf("expected identical fingerprints, got\n  %q\n  %q", a, b)
+	}
+
+	c := fingerprintLogLine(`panic: runtime error: invalid memory address or nil pointer dereference`)
+	if a == c {
+		t.Errorf("expected different errors to have different fingerprints")
+	}
+}
+
+func TestIsCrashLooping(t *testing.T) {
+	tests := []struct {
+		name string
+		cs   corev1.ContainerStatus
+		want bool
+	}{
+		{"backoff", corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}, true},
+		{"errored after restart", corev1.ContainerStatus{RestartCount: 2, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}}, true},
+		{"running", corev1.ContainerStatus{RestartCount: 4, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}, false},
+		{"image pull", corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}}, false},
+	}
+	for _, tt := range tests {
+		if got := isCrashLooping(tt.cs); got != tt.want {
+			t.Errorf("%s: isCrashLooping() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// customResourceDefaultLimit is the default page size of list_custom_resources.
+	customResourceDefaultLimit = 50
+	// crFailureGroupLimit caps how many distinct failure messages check_crd_conditions prints.
+	crFailureGroupLimit = 10
+)
+
+// crFailureConditionSuffixes mark condition types that signal a problem when True
+// (Flux Stalled, Argo CD ComparisonError/SyncError, generic Degraded/Failed).
+var crFailureConditionSuffixes = []string{"Error", "Failed", "Failure", "Degraded", "Stalled"}
+
+// crMessageVolatile matches hashes and numbers that make otherwise identical
+// failure messages differ between objects.
+var crMessageVolatile = regexp.MustCompile(`[0-9a-f]{7,}|\d+`)
+
+type listCustomResourcesInput struct {
+	Kind           string `json:"kind" jsonschema:"required,Resource kind, plural, or short name (e.g. Certificate, certificates, cert)"`
+	Group          string `json:"group,omitempty" jsonschema:"API group (e.g. cert-manager.io); needed when the kind exists in several groups"`
+	Version        string `json:"version,omitempty" jsonschema:"API version (default: the group's preferred version)"`
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces; ignored for cluster-scoped kinds)"`
+	Limit          int64  `json:"limit,omitempty" jsonschema:"Maximum objects per page (default 50, max 1000)"`
+	ContinueToken  string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+type checkCRDConditionsInput struct {
+	Kind           string `json:"kind" jsonschema:"required,Resource kind, plural, or short name (e.g. Kustomization, Application, Certificate)"`
+	Group          string `json:"group,omitempty" jsonschema:"API group (e.g. kustomize.toolkit.fluxcd.io); needed when the kind exists in several groups"`
+	Version        string `json:"version,omitempty" jsonschema:"API version (default: the group's preferred version)"`
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces; ignored for cluster-scoped kinds)"`
+	Condition      string `json:"condition,omitempty" jsonschema:"Condition type that means healthy (default Ready)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// crHealth is how one custom resource looks through its conditions.
+type crHealth struct {
+	Ref      string
+	Status   string        // status of the health condition, or "" when it is missing
+	Failures []crCondition // health condition not True, plus True failure-type conditions
+	Stale    bool          // status.observedGeneration is behind metadata.generation
+}
+
+// crFailureGroup is a failure reason and message shared by several objects.
+type crFailureGroup struct {
+	Reason  string
+	Message string
+	Refs    []string
+}
+
+// crCondition is one entry of a custom resource's status.conditions.
+type crCondition struct {
+	Type           string
+	Status         string
+	Reason         string
+	Message        string
+	LastTransition time.Time
+}
+
+func registerCustomResourceTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// list_custom_resources
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_custom_resources",
+		Description: "List instances of any custom resource (or other API kind) resolved through API discovery from kind plus optional group/version: instance counts per namespace, each object's Ready state and age, and its status.conditions. Makes operator-managed resources (cert-manager, Argo, Crossplane, KEDA, ...) diagnosable.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input listCustomResourcesInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if input.Kind == "" {
+			return util.ErrorResult("kind is required"), nil, nil
+		}
+		ref, err := client.ResolveResource(ctx, input.Group, input.Version, input.Kind)
+		if err != nil {
+			return util.HandleK8sError("resolving kind "+input.Kind, err), nil, nil
+		}
+		limit := input.Limit
+		if limit <= 0 {
+			limit = customResourceDefaultLimit
+		}
+		page, err := client.ListResourcesPage(ctx, ref, input.Namespace, util.PageOptions(metav1.ListOptions{}, limit, input.ContinueToken))
+		if err != nil {
+			return util.HandleK8sError("listing "+ref.GVR.Resource, err), nil, nil
+		}
+		items := page.Items
+		sortUnstructured(items)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Custom Resources: %s", ref.Kind)))
+		sb.WriteString(util.FormatKeyValue("Resource", ref.GVR.String()))
+		scope := "Namespaced"
+		if !ref.Namespaced {
+			scope = "Cluster"
+		} else {
+			sb.WriteString(util.FormatKeyValue("Namespace", displayNS(input.Namespace)))
+		}
+		sb.WriteString(util.FormatKeyValue("Scope", scope))
+		sb.WriteString(util.FormatKeyValue("Total", fmt.Sprintf("%d", len(items))))
+		sb.WriteString("\n")
+		if len(items) == 0 {
+			sb.WriteString(fmt.Sprintf("No %s objects found.\n", ref.Kind))
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		if ref.Namespaced {
+			counts := make(map[string]int)
+			for i := range items {
+				counts[items[i].GetNamespace()]++
+			}
+			var rows [][]string
+			for _, ns := range sortedKeysInt(counts) {
+				rows = append(rows, []string{ns, fmt.Sprintf("%d", counts[ns])})
+			}
+			sb.WriteString(util.FormatSubHeader("INSTANCES PER NAMESPACE"))
+			sb.WriteString(util.FormatTable([]string{"NAMESPACE", "COUNT"}, rows))
+			sb.WriteString("\n")
+		}
+
+		var rows [][]string
+		for i := range items {
+			u := &items[i]
+			ready := "-"
+			if c := findCRCondition(resourceConditions(u), "Ready"); c != nil {
+				ready = c.Status
+			}
+			row := []string{u.GetName(), ready, util.FormatAge(u.GetCreationTimestamp().Time)}
+			if ref.Namespaced {
+				row = append([]string{u.GetNamespace()}, row...)
+			}
+			rows = append(rows, row)
+		}
+		headers := []string{"NAME", "READY", "AGE"}
+		if ref.Namespaced {
+			headers = append([]string{"NAMESPACE"}, headers...)
+		}
+		sb.WriteString(util.FormatSubHeader("INSTANCES"))
+		sb.WriteString(util.FormatTable(headers, rows))
+		sb.WriteString("\n")
+
+		sb.WriteString(util.FormatSubHeader("CONDITIONS"))
+		withConditions := false
+		for i := range items {
+			u := &items[i]
+			conds := resourceConditions(u)
+			if len(conds) == 0 {
+				continue
+			}
+			withConditions = true
+			sb.WriteString(fmt.Sprintf("  %s:\n", objectRef(u)))
+			for _, c := range conds {
+				line := fmt.Sprintf("    %s=%s", c.Type, c.Status)
+				if c.Reason != "" {
+					line += " (" + c.Reason + ")"
+				}
+				if !c.LastTransition.IsZero() {
+					line += " " + util.FormatAge(c.LastTransition) + " ago"
+				}
+				if c.Message != "" {
+					line += ": " + truncateName(c.Message, 160)
+				}
+				sb.WriteString(line + "\n")
+			}
+		}
+		if !withConditions {
+			sb.WriteString(fmt.Sprintf("  No %s objects report status.conditions.\n", ref.Kind))
+		}
+		sb.WriteString(pageFooter("list_custom_resources", page.Continue, page.Remaining))
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+
+	// check_crd_conditions
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "check_crd_conditions",
+		Description: "Summarize status.conditions across every custom resource of a kind: how many are Ready=True/False/Unknown, which condition types fail, objects not yet reconciled (observedGeneration behind generation), and the most common failure reasons and messages. Works for any condition-driven operator (Flux, Argo CD, Crossplane, cert-manager, KEDA, ...).",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkCRDConditionsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if input.Kind == "" {
+			return util.ErrorResult("kind is required"), nil, nil
+		}
+		condType := input.Condition
+		if condType == "" {
+			condType = "Ready"
+		}
+		ref, err := client.ResolveResource(ctx, input.Group, input.Version, input.Kind)
+		if err != nil {
+			return util.HandleK8sError("resolving kind "+input.Kind, err), nil, nil
+		}
+		items, err := client.ListResources(ctx, ref, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing "+ref.GVR.Resource, err), nil, nil
+		}
+		sortUnstructured(items)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Condition Health: %s", ref.Kind)))
+		sb.WriteString(util.FormatKeyValue("Resource", ref.GVR.String()))
+		if ref.Namespaced {
+			sb.WriteString(util.FormatKeyValue("Namespace", displayNS(input.Namespace)))
+		}
+		sb.WriteString(util.FormatKeyValue("Health condition", condType))
+		sb.WriteString(util.FormatKeyValue("Total", fmt.Sprintf("%d", len(items))))
+		sb.WriteString("\n")
+		if len(items) == 0 {
+			sb.WriteString(fmt.Sprintf("No %s objects found.\n", ref.Kind))
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		healths := make([]crHealth, 0, len(items))
+		byStatus := make(map[string]int)
+		typeCounts := make(map[string]map[string]int)
+		var stale []string
+		for i := range items {
+			h := classifyCR(&items[i], condType)
+			healths = append(healths, h)
+			status := h.Status
+			if status == "" {
+				status = "(missing)"
+			}
+			byStatus[status]++
+			if h.Stale {
+				stale = append(stale, h.Ref)
+			}
+			for _, c := range resourceConditions(&items[i]) {
+				if typeCounts[c.Type] == nil {
+					typeCounts[c.Type] = make(map[string]int)
+				}
+				typeCounts[c.Type][c.Status]++
+			}
+		}
+
+		sb.WriteString(util.FormatSubHeader(condType + " STATUS"))
+		var statusRows [][]string
+		for _, st := range sortedKeysInt(byStatus) {
+			statusRows = append(statusRows, []string{st, fmt.Sprintf("%d", byStatus[st])})
+		}
+		sb.WriteString(util.FormatTable([]string{"STATUS", "COUNT"}, statusRows))
+		sb.WriteString("\n")
+
+		if len(typeCounts) > 0 {
+			sb.WriteString(util.FormatSubHeader("CONDITION TYPES"))
+			types := make([]string, 0, len(typeCounts))
+			for t := range typeCounts {
+				types = append(types, t)
+			}
+			sort.Strings(types)
+			var rows [][]string
+			for _, t := range types {
+				c := typeCounts[t]
+				rows = append(rows, []string{t, fmt.Sprintf("%d", c["True"]), fmt.Sprintf("%d", c["False"]), fmt.Sprintf("%d", c["Unknown"])})
+			}
+			sb.WriteString(util.FormatTable([]string{"TYPE", "TRUE", "FALSE", "UNKNOWN"}, rows))
+			sb.WriteString("\n")
+		}
+
+		groups := groupCRFailures(healths)
+		if len(groups) > 0 {
+			sb.WriteString(util.FormatSubHeader("COMMON FAILURES"))
+			var rows [][]string
+			for i, g := range groups {
+				if i == crFailureGroupLimit {
+					break
+				}
+				rows = append(rows, []string{fmt.Sprintf("%d", len(g.Refs)), g.Reason, truncateName(g.Message, 100), joinLimited(g.Refs, 3)})
+			}
+			sb.WriteString(util.FormatTable([]string{"COUNT", "REASON", "MESSAGE", "OBJECTS"}, rows))
+			if len(groups) > crFailureGroupLimit {
+				sb.WriteString(fmt.Sprintf("  ... and %d more distinct failures\n", len(groups)-crFailureGroupLimit))
+			}
+			sb.WriteString("\n")
+		}
+
+		var findingLines, actions []string
+		failing := 0
+		for _, h := range healths {
+			if len(h.Failures) > 0 {
+				failing++
+			}
+		}
+		if failing > 0 {
+			severity := "WARNING"
+			if failing == len(healths) {
+				severity = "CRITICAL"
+			}
+			findingLines = append(findingLines, findings.Format("KD-SYS-007", severity, fmt.Sprintf("%d/%d %s object(s) report failing conditions", failing, len(healths), ref.Kind)))
+			if len(groups) > 0 {
+				g := groups[0]
+				findingLines = append(findingLines, findings.Format("KD-SYS-007", "INFO", fmt.Sprintf("Most common failure (%d object(s)): %s: %s", len(g.Refs), g.Reason, truncateName(g.Message, 160))))
+			}
+			actions = append(actions, fmt.Sprintf("Inspect a failing object with list_custom_resources kind=%s and fix the most common failure first", ref.Kind))
+			actions = append(actions, "Check the operator's controller pods and logs (get_pod_logs) for reconcile errors")
+			if failing == len(healths) && len(healths) > 1 {
+				actions = append(actions, "Every object is failing, which usually points at the operator itself, its credentials, or a shared source rather than individual objects")
+			}
+		}
+		if byStatus["(missing)"] == len(healths) {
+			findingLines = append(findingLines, findings.Format("KD-SYS-007", "INFO", fmt.Sprintf("No %s object has a %s condition; set condition to the type this operator reports", ref.Kind, condType)))
+		}
+		if len(stale) > 0 {
+			findingLines = append(findingLines, findings.Format("KD-SYS-007", "WARNING", fmt.Sprintf("%d object(s) have not been reconciled since their last change (observedGeneration behind generation): %s", len(stale), joinLimited(stale, 5))))
+			actions = append(actions, "Objects stuck on an old generation mean the controller is down, suspended, or backlogged; check its pods and leader election")
+		}
+
+		sb.WriteString("FINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString(fmt.Sprintf("  All %d %s object(s) are healthy and reconciled.\n", len(healths), ref.Kind))
+		}
+		for _, f := range findingLines {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// classifyCR reads an object's health from its conditions. condType names the
+// condition that means healthy; it fails when not True, and any True
+// condition whose type ends in a failure suffix fails too.
+func classifyCR(u *unstructured.Unstructured, condType string) crHealth {
+	h := crHealth{Ref: objectRef(u)}
+	for _, c := range resourceConditions(u) {
+		switch {
+		case c.Type == condType:
+			h.Status = c.Status
+			if c.Status != "True" {
+				h.Failures = append(h.Failures, c)
+			}
+		case c.Status == "True" && hasFailureSuffix(c.Type):
+			h.Failures = append(h.Failures, c)
+		}
+	}
+	if observed, found, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration"); found && observed < u.GetGeneration() {
+		h.Stale = true
+	}
+	return h
+}
+
+func hasFailureSuffix(condType string) bool {
+	for _, suffix := range crFailureConditionSuffixes {
+		if strings.HasSuffix(condType, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupCRFailures groups failing conditions by reason and message, ignoring
+// numbers and hashes, most common first.
+func groupCRFailures(healths []crHealth) []crFailureGroup {
+	index := make(map[string]int)
+	var groups []crFailureGroup
+	for _, h := range healths {
+		seen := make(map[string]bool)
+		for _, c := range h.Failures {
+			reason := c.Reason
+			if reason == "" {
+				reason = c.Type + "=" + c.Status
+			}
+			key := reason + "\x00" + crMessageVolatile.ReplaceAllString(c.Message, "*")
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			i, ok := index[key]
+			if !ok {
+				i = len(groups)
+				index[key] = i
+				groups = append(groups, crFailureGroup{Reason: reason, Message: c.Message})
+			}
+			groups[i].Refs = append(groups[i].Refs, h.Ref)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i].Refs) > len(groups[j].Refs) })
+	return groups
+}
+
+// resourceConditions reads status.conditions from an unstructured object.
+// Entries without a type are skipped.
+func resourceConditions(u *unstructured.Unstructured) []crCondition {
+	raw, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	var out []crCondition
+	for _, r := range raw {
+		m, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		c := crCondition{
+			Type:    stringField(m, "type"),
+			Status:  stringField(m, "status"),
+			Reason:  stringField(m, "reason"),
+			Message: strings.TrimSpace(stringField(m, "message")),
+		}
+		if c.Type == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, stringField(m, "lastTransitionTime")); err == nil {
+			c.LastTransition = t
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func findCRCondition(conds []crCondition, condType string) *crCondition {
+	for i := range conds {
+		if conds[i].Type == condType {
+			return &conds[i]
+		}
+	}
+	return nil
+}
+
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// objectRef returns namespace/name, or just the name for cluster-scoped objects.
+func objectRef(u *unstructured.Unstructured) string {
+	if u.GetNamespace() == "" {
+		return u.GetName()
+	}
+	return u.GetNamespace() + "/" + u.GetName()
+}
+
+func sortUnstructured(items []unstructured.Unstructured) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+}
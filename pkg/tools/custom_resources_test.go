@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestResourceConditions(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"namespace": "shop", "name": "shop-tls"},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Ready", "status": "False", "reason": "DoesNotExist", "message": " Issuing certificate \n", "lastTransitionTime": "2025-06-01T10:00:00Z"},
+			map[string]any{"status": "True"},
+			"garbage",
+		}},
+	}}
+
+	conds := resourceConditions(u)
+	if len(conds) != 1 {
+		t.Fatalf("expected 1 valid condition, got %+v", conds)
+	}
+	c := findCRCondition(conds, "Ready")
+	if c == nil || c.Status != "False" || c.Reason != "DoesNotExist" || c.Message != "Issuing certificate" || c.LastTransition.IsZero() {
+		t.Errorf("unexpected Ready condition: %+v", c)
+	}
+	if findCRCondition(conds, "Issuing") != nil {
+		t.Error("expected no Issuing condition")
+	}
+	if got := objectRef(u); got != "shop/shop-tls" {
+		t.Errorf("objectRef() = %q", got)
+	}
+	if resourceConditions(&unstructured.Unstructured{Object: map[string]any{}}) != nil {
+		t.Error("expected no conditions for an object without status")
+	}
+}
+
+func testCR(name string, generation, observed int64, conds ...map[string]any) unstructured.Unstructured {
+	raw := make([]any, 0, len(conds))
+	for _, c := range conds {
+		raw = append(raw, c)
+	}
+	u := unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"namespace": "flux-system", "name": name},
+		"status":   map[string]any{"conditions": raw, "observedGeneration": observed},
+	}}
+	u.SetGeneration(generation)
+	return u
+}
+
+func TestClassifyCRAndGroupFailures(t *testing.T) {
+	items := []unstructured.Unstructured{
+		testCR("apps", 3, 3, map[string]any{"type": "Ready", "status": "True"}),
+		testCR("infra", 5, 4,
+			map[string]any{"type": "Ready", "status": "False", "reason": "BuildFailed", "message": "kustomize build failed: revision 4f2a9c1e missing"},
+			map[string]any{"type": "Stalled", "status": "True", "reason": "BuildFailed", "message": "kustomize build failed: revision 4f2a9c1e missing"}),
+		testCR("tenants", 2, 2,
+			map[string]any{"type": "Ready", "status": "False", "reason": "BuildFailed", "message": "kustomize build failed: revision 77b03de1 missing"}),
+		testCR("argo", 1, 1, map[string]any{"type": "ComparisonError", "status": "True", "message": "repo unreachable"}),
+	}
+
+	var healths []crHealth
+	for i := range items {
+		healths = append(healths, classifyCR(&items[i], "Ready"))
+	}
+	if len(healths[0].Failures) != 0 || healths[0].Status != "True" {
+		t.Errorf("expected apps to be healthy, got %+v", healths[0])
+	}
+	if !healths[1].Stale || len(healths[1].Failures) != 2 {
+		t.Errorf("expected infra to be stale with two failing conditions, got %+v", healths[1])
+	}
+	if healths[3].Status != "" || len(healths[3].Failures) != 1 {
+		t.Errorf("expected True ComparisonError to count as a failure, got %+v", healths[3])
+	}
+
+	groups := groupCRFailures(healths)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 failure groups, got %+v", groups)
+	}
+	if groups[0].Reason != "BuildFailed" || strings.Join(groups[0].Refs, ",") != "flux-system/infra,flux-system/tenants" {
+		t.Errorf("expected messages differing only by revision to group together, got %+v", groups[0])
+	}
+	if groups[1].Reason != "ComparisonError=True" {
+		t.Errorf("expected reason to fall back to type=status, got %q", groups[1].Reason)
+	}
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// dataGap is a report section that could not be collected.
+type dataGap struct {
+	Section string
+	Err     error
+}
+
+// dataGaps collects the sections of a composite report whose requests
+// failed, so the report carries on with the remaining sections and says what
+// it could not see. Scope errors are not gaps: namespace-scoped mode skips
+// cluster-scoped sections by design and reports them inline.
+type dataGaps []dataGap
+
+// record notes that section failed with err. It ignores nil and scope errors
+// and reports whether a gap was recorded.
+func (g *dataGaps) record(section string, err error) bool {
+	if err == nil || util.IsScopeError(err) {
+		return false
+	}
+	*g = append(*g, dataGap{Section: section, Err: err})
+	return true
+}
+
+// write appends the DATA GAPS section; it writes nothing without gaps.
+func (g dataGaps) write(sb *strings.Builder) {
+	if len(g) == 0 {
+		return
+	}
+	sb.WriteString("\nDATA GAPS:\n")
+	for _, gap := range g {
+		sb.WriteString(fmt.Sprintf("  - %s: %s\n", gap.Section, gapReason(gap.Err)))
+	}
+	sb.WriteString("  Sections above are incomplete; findings in them are unknown, not healthy.\n")
+}
+
+// assessment is the Overall Assessment line for a partial report, "" when
+// every section was collected. It keeps the gaps visible in summaries.
+func (g dataGaps) assessment() string {
+	if len(g) == 0 {
+		return ""
+	}
+	sections := make([]string, 0, len(g))
+	for _, gap := range g {
+		sections = append(sections, gap.Section)
+	}
+	return fmt.Sprintf("  Partial report: %d section(s) could not be collected (%s); see DATA GAPS.\n", len(g), strings.Join(dedupe(sections), ", "))
+}
+
+// gapReason describes why a section could not be collected.
+func gapReason(err error) string {
+	var status apierrors.APIStatus
+	switch {
+	case apierrors.IsForbidden(err):
+		return "permission denied; check RBAC"
+	case apierrors.IsUnauthorized(err):
+		return "credentials rejected"
+	case apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) || errors.Is(err, context.DeadlineExceeded):
+		return "timed out; retry with a higher timeout_seconds"
+	case apierrors.IsNotFound(err):
+		return "API not available on this cluster"
+	case errors.As(err, &status):
+		return truncateName(status.Status().Message, 120)
+	}
+	return truncateName(err.Error(), 120)
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+func TestDataGaps(t *testing.T) {
+	var gaps dataGaps
+	if gaps.record("Nodes", nil) || gaps.record("Nodes", &util.ScopeError{Resource: "nodes"}) {
+		t.Error("nil and scope errors should not be recorded as gaps")
+	}
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "", errors.New("denied"))
+	if !gaps.record("Nodes", forbidden) {
+		t.Error("expected a forbidden error to be recorded")
+	}
+	gaps.record("Recent Warnings", context.DeadlineExceeded)
+
+	var sb strings.Builder
+	gaps.write(&sb)
+	for _, want := range []string{"DATA GAPS:", "Nodes: permission denied", "Recent Warnings: timed out"} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("gaps section missing %q:\n%s", want, sb.String())
+		}
+	}
+	if got := gaps.assessment(); !strings.Contains(got, "2 section(s) could not be collected (Nodes, Recent Warnings)") {
+		t.Errorf("assessment = %q", got)
+	}
+	if (dataGaps{}).assessment() != "" {
+		t.Error("expected no assessment line without gaps")
+	}
+}
+
+func TestClusterHealthOverviewPartialResults(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	})
+	fakeClient.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "", errors.New("denied"))
+	})
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "test"}, nil)
+	registerCompositeDiagnosticTools(server, k8s.NewClusterClientForTesting(fakeClient, nil))
+
+	ctx := context.Background()
+	t1, t2 := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, t1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer serverSession.Close()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "test"}, nil).Connect(ctx, t2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "cluster_health_overview"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Fatalf("expected a partial report, got error: %v", res.Content)
+	}
+	text := res.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"Not collected: permission denied", "Pod Health by Namespace", "DATA GAPS:", "Partial report:"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -10,20 +10,27 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
 )
 
 type diagnosePodInput struct {
-	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace"`
-	Name      string `json:"name" jsonschema:"Pod name"`
+	Namespace   string `json:"namespace" jsonschema:"Kubernetes namespace"`
+	Name        string `json:"name" jsonschema:"Pod name"`
+	DetailLevel string `json:"detail_level,omitempty" jsonschema:"How much to return: summary (about 10 lines: status, top findings, next action), standard (the report without Mermaid diagrams), or full (everything, the default)"`
 }
 
 type diagnoseNamespaceInput struct {
-	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace to diagnose"`
+	Namespace      string `json:"namespace" jsonschema:"Kubernetes namespace to diagnose"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+	DetailLevel    string `json:"detail_level,omitempty" jsonschema:"How much to return: summary (about 10 lines: status, top findings, next action), standard (the report without Mermaid diagrams), or full (everything, the default)"`
 }
 
-type diagnoseClusterInput struct{}
+type diagnoseClusterInput struct {
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+	DetailLevel    string `json:"detail_level,omitempty" jsonschema:"How much to return: summary (about 10 lines: status, top findings, next action), standard (the report without Mermaid diagrams), or full (everything, the default)"`
+}
 
 type findUnhealthyPodsInput struct {
 	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
@@ -37,8 +44,12 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 	// diagnose_pod
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "diagnose_pod",
-		Description: "Run a comprehensive diagnosis on a specific pod. Checks status, conditions, events, container states, restart reasons, resource limits, and fetches logs from failing containers. Use this when a pod is unhealthy.",
+		Description: "Run a comprehensive diagnosis on a specific pod. Checks status, conditions, events, container states, restart reasons (telling liveness probe kills apart from application crashes), init containers and sidecars (Init:Error, Init:CrashLoopBackOff, and which init container blocks PodInitializing), resource limits, the hosting node's conditions and events during the failure window, and fetches logs from failing containers. Use this when a pod is unhealthy.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input diagnosePodInput) (*mcp.CallToolResult, any, error) {
+		detail, err := util.ParseDetailLevel(input.DetailLevel)
+		if err != nil {
+			return util.ErrorResult("%v", err), nil, nil
+		}
 		pod, err := client.GetPod(ctx, input.Namespace, input.Name)
 		if err != nil {
 			return util.HandleK8sError(fmt.Sprintf("getting pod %s/%s", input.Namespace, input.Name), err), nil, nil
@@ -60,17 +71,48 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString(util.FormatKeyValue("AGE", util.FormatAge(pod.CreationTimestamp.Time)))
 		sb.WriteString("\n")
 
+		if len(pod.Spec.InitContainers) > 0 {
+			sb.WriteString("\nINIT CONTAINERS:\n")
+			sb.WriteString(util.FormatTable([]string{"#", "NAME", "TYPE", "STATE", "EXIT", "RESTARTS"}, initContainerRows(pod)))
+		}
+
+		// Events are read once: restart attribution and the Warning events
+		// section below both use them.
+		events, eventsErr := client.GetEventsForObject(ctx, input.Namespace, input.Name)
+
+		// A liveness probe killing a working app and an app crashing need
+		// opposite fixes, so attribute every restart before reporting it.
+		restartCauses := make(map[string]restartAttribution)
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.RestartCount == 0 {
+				continue
+			}
+			if a, ok := attributeRestart(cs, events); ok {
+				restartCauses[cs.Name] = a
+			}
+		}
+
 		// Findings
 		sb.WriteString("\nFINDINGS:\n")
-		findings := 0
+		findingCount := 0
+
+		// Init containers run before the app containers, so a failing one
+		// explains why the app containers are stuck in PodInitializing.
+		initIssues := analyzeInitContainers(pod, time.Now())
+		for _, issue := range initIssues {
+			sb.WriteString(findings.Format(issue.Rule, issue.Severity, issue.Message))
+			sb.WriteString("\n")
+			findingCount++
+		}
 
 		// Check container statuses
 		for _, cs := range pod.Status.ContainerStatuses {
+			reported := findingCount
 			if cs.State.Waiting != nil {
 				reason := cs.State.Waiting.Reason
 				switch reason {
 				case "CrashLoopBackOff":
-					sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Container '%s' is in CrashLoopBackOff", cs.Name)))
+					sb.WriteString(findings.Format("KD-POD-001", "CRITICAL", fmt.Sprintf("Container '%s' is in CrashLoopBackOff", cs.Name)))
 					sb.WriteString("\n")
 					if cs.LastTerminationState.Terminated != nil {
 						t := cs.LastTerminationState.Terminated
@@ -80,64 +122,83 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 							sb.WriteString("  - Container was killed due to out-of-memory\n")
 						}
 					}
-					findings++
+					findingCount++
 				case "ImagePullBackOff", "ErrImagePull":
-					sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Container '%s' cannot pull image: %s", cs.Name, cs.State.Waiting.Message)))
+					sb.WriteString(findings.Format("KD-POD-002", "CRITICAL", fmt.Sprintf("Container '%s' cannot pull image: %s", cs.Name, cs.State.Waiting.Message)))
 					sb.WriteString("\n")
-					findings++
+					findingCount++
+				case "PodInitializing":
+					if len(initIssues) == 0 {
+						sb.WriteString(findings.Format("KD-POD-012", "INFO", fmt.Sprintf("Container '%s' is waiting for init containers to complete", cs.Name)))
+						sb.WriteString("\n")
+						findingCount++
+					}
 				default:
-					sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Container '%s' is waiting: %s", cs.Name, reason)))
+					sb.WriteString(findings.Format("KD-POD-007", "WARNING", fmt.Sprintf("Container '%s' is waiting: %s", cs.Name, reason)))
 					sb.WriteString("\n")
-					findings++
+					findingCount++
 				}
 			}
 			if cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0 {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Container '%s' terminated with exit code %d (%s)", cs.Name, cs.State.Terminated.ExitCode, cs.State.Terminated.Reason)))
+				rule := "KD-POD-008"
+				if cs.State.Terminated.Reason == "OOMKilled" {
+					rule = "KD-POD-003"
+				}
+				sb.WriteString(findings.Format(rule, "WARNING", fmt.Sprintf("Container '%s' terminated with exit code %d (%s)", cs.Name, cs.State.Terminated.ExitCode, cs.State.Terminated.Reason)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			}
 			if cs.RestartCount > util.HighRestartThreshold {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Container '%s' has high restart count: %d", cs.Name, cs.RestartCount)))
+				sb.WriteString(findings.Format("KD-POD-005", "WARNING", fmt.Sprintf("Container '%s' has high restart count: %d", cs.Name, cs.RestartCount)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
+			}
+			if a, ok := restartCauses[cs.Name]; ok {
+				if a.Cause == restartCauseLiveness {
+					sb.WriteString(findings.Format("KD-PRB-002", "WARNING", fmt.Sprintf("Container '%s' restarts because its liveness probe fails, not because it crashes", cs.Name)))
+					sb.WriteString("\n")
+					findingCount++
+				}
+				if findingCount > reported {
+					sb.WriteString(fmt.Sprintf("  - Restart cause: %s (%s)\n", a.Cause, a.Detail()))
+				}
 			}
 		}
 
 		// Check pod conditions
 		for _, cond := range pod.Status.Conditions {
 			if cond.Status == corev1.ConditionFalse && cond.Type == corev1.PodScheduled {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Pod not scheduled: %s", cond.Message)))
+				sb.WriteString(findings.Format("KD-POD-004", "CRITICAL", fmt.Sprintf("Pod not scheduled: %s", cond.Message)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			}
 			if cond.Status == corev1.ConditionFalse && cond.Type == corev1.PodReady {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Pod not ready: %s", cond.Message)))
+				sb.WriteString(findings.Format("KD-POD-009", "WARNING", fmt.Sprintf("Pod not ready: %s", cond.Message)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			}
 		}
 
 		// Check resource limits
 		for _, c := range pod.Spec.Containers {
 			if c.Resources.Limits == nil || c.Resources.Limits.Cpu().IsZero() {
-				sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("Container '%s' has no CPU limit set", c.Name)))
+				sb.WriteString(findings.Format("KD-RES-001", "INFO", fmt.Sprintf("Container '%s' has no CPU limit set", c.Name)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			}
 			if c.Resources.Limits == nil || c.Resources.Limits.Memory().IsZero() {
-				sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("Container '%s' has no memory limit set", c.Name)))
+				sb.WriteString(findings.Format("KD-RES-001", "INFO", fmt.Sprintf("Container '%s' has no memory limit set", c.Name)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			}
 		}
 
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  No issues found - pod appears healthy.\n")
 		}
 
 		// Warning events
-		events, err := client.GetEventsForObject(ctx, input.Namespace, input.Name)
-		if err == nil {
+		if eventsErr == nil {
 			warningEvents := 0
 			for _, e := range events {
 				if e.Type == "Warning" {
@@ -145,7 +206,7 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 				}
 			}
 			if warningEvents > 0 {
-				sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d Warning events in recent history", warningEvents))))
+				sb.WriteString(fmt.Sprintf("\n%s\n", findings.Format("KD-EVT-001", "WARNING", fmt.Sprintf("%d Warning events in recent history", warningEvents))))
 				for _, e := range events {
 					if e.Type == "Warning" {
 						sb.WriteString(fmt.Sprintf("  - %s: %s", e.Reason, e.Message))
@@ -158,6 +219,37 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 			}
 		}
 
+		// Hosting node conditions and events in the failure window
+		var nodeActions []string
+		if pod.Spec.NodeName != "" {
+			nodeActions = writePodNodeCorrelation(ctx, &sb, client, pod)
+		}
+
+		// Fetch logs from the failing or blocking init containers
+		for _, issue := range initIssues {
+			if !issue.Logs {
+				continue
+			}
+			kind := "init container"
+			if issue.Sidecar {
+				kind = "sidecar"
+			}
+			instance := "current instance"
+			if issue.Previous {
+				instance = "previous instance"
+			}
+			sb.WriteString(fmt.Sprintf("\nRECENT LOGS (%s '%s', %s):\n", kind, issue.Container, instance))
+			logs, err := client.GetPodLogs(ctx, input.Namespace, input.Name, issue.Container, 50, issue.Previous, "")
+			if err != nil {
+				sb.WriteString(fmt.Sprintf("  (could not fetch logs: %v)\n", err))
+			} else if logs == "" {
+				sb.WriteString("  (no logs available)\n")
+			} else {
+				sb.WriteString(logs)
+				sb.WriteString("\n")
+			}
+		}
+
 		// Fetch logs from crashing containers
 		for _, cs := range pod.Status.ContainerStatuses {
 			if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
@@ -177,6 +269,7 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 		// Suggested actions
 		sb.WriteString("\nSUGGESTED ACTIONS:\n")
 		actionNum := 1
+		var steps []util.NextStep
 		for _, cs := range pod.Status.ContainerStatuses {
 			if cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.Reason == "OOMKilled" {
 				for _, c := range pod.Spec.Containers {
@@ -186,27 +279,69 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 						actionNum++
 					}
 				}
+				steps = append(steps, nextStep("get_pod_metrics", fmt.Sprintf("Compare memory usage with the limit of OOMKilled container '%s'", cs.Name),
+					"namespace", pod.Namespace))
 			}
 			if cs.State.Waiting != nil {
 				switch cs.State.Waiting.Reason {
 				case "ImagePullBackOff", "ErrImagePull":
 					sb.WriteString(fmt.Sprintf("%d. Check image name and registry credentials for container '%s'\n", actionNum, cs.Name))
 					actionNum++
+					steps = append(steps, nextStep("get_events", "Read the image pull error messages",
+						"namespace", pod.Namespace, "involved_object", pod.Name, "event_type", "Warning"))
 				case "CrashLoopBackOff":
+					if a, ok := restartCauses[cs.Name]; ok && a.Cause == restartCauseLiveness {
+						sb.WriteString(fmt.Sprintf("%d. %s\n", actionNum, a.Remediation(cs.Name)))
+						actionNum++
+						steps = append(steps, nextStep("analyze_probes", fmt.Sprintf("Review the liveness probe that keeps restarting '%s'", cs.Name),
+							"namespace", pod.Namespace))
+						continue
+					}
 					sb.WriteString(fmt.Sprintf("%d. Check application logs for container '%s' (use get_pod_logs with previous=true)\n", actionNum, cs.Name))
 					actionNum++
+					steps = append(steps, nextStep("get_pod_logs", fmt.Sprintf("Read why container '%s' crashed", cs.Name),
+						"namespace", pod.Namespace, "name", pod.Name, "container", cs.Name, "previous", true))
 				}
+			} else if a, ok := restartCauses[cs.Name]; ok && a.Cause == restartCauseLiveness && cs.RestartCount > util.HighRestartThreshold {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", actionNum, a.Remediation(cs.Name)))
+				actionNum++
+				steps = append(steps, nextStep("analyze_probes", fmt.Sprintf("Review the liveness probe that keeps restarting '%s'", cs.Name),
+					"namespace", pod.Namespace))
 			}
 		}
+		for _, issue := range initIssues {
+			if issue.Severity == "INFO" {
+				continue
+			}
+			switch {
+			case issue.Sidecar:
+				sb.WriteString(fmt.Sprintf("%d. Fix sidecar '%s' using its logs above; it restarts alongside the app containers\n", actionNum, issue.Container))
+			case issue.Rule == "KD-POD-012":
+				sb.WriteString(fmt.Sprintf("%d. Find out what init container '%s' is waiting for (its logs above) and check that dependency's Service endpoints\n", actionNum, issue.Container))
+			default:
+				sb.WriteString(fmt.Sprintf("%d. Fix init container '%s' first; the app containers only start once it succeeds\n", actionNum, issue.Container))
+			}
+			actionNum++
+			steps = append(steps, nextStep("get_pod_logs", fmt.Sprintf("Read more of the logs of '%s'", issue.Container),
+				"namespace", pod.Namespace, "name", pod.Name, "container", issue.Container, "previous", issue.Previous))
+		}
+		for _, a := range nodeActions {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", actionNum, a))
+			actionNum++
+		}
+		if len(nodeActions) > 0 {
+			steps = append(steps, nextStep("get_node_detail", "The hosting node had problems during the failure window", "name", pod.Spec.NodeName))
+		}
 		if pod.Status.Phase == corev1.PodPending {
 			sb.WriteString(fmt.Sprintf("%d. Check cluster capacity and node selectors/tolerations\n", actionNum))
 			actionNum++
+			steps = append(steps, nextStep("analyze_node_capacity", "Check whether any node can fit the pod's requests"))
 		}
 		if actionNum == 1 {
 			sb.WriteString("  No specific actions needed - pod is healthy.\n")
 		}
 
-		return util.SuccessResult(sb.String()), nil, nil
+		return util.WithNextSteps(finishReport(sb.String(), detail), steps), nil, nil
 	})
 
 	// diagnose_namespace
@@ -214,17 +349,23 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Name:        "diagnose_namespace",
 		Description: "Health check an entire namespace. Finds unhealthy pods, failing deployments, pending PVCs, warning events, and pods with high restart counts. Use this to quickly assess namespace health.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input diagnoseNamespaceInput) (*mcp.CallToolResult, any, error) {
+		detail, err := util.ParseDetailLevel(input.DetailLevel)
+		if err != nil {
+			return util.ErrorResult("%v", err), nil, nil
+		}
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Namespace Diagnosis: %s", input.Namespace)))
 		sb.WriteString("\n\n")
 
-		findings := 0
+		findingCount := 0
+		var steps []util.NextStep
+		var gaps dataGaps
 
 		// 1. Check pods
 		pods, err := client.ListPods(ctx, input.Namespace, metav1.ListOptions{})
-		if err != nil {
-			return util.HandleK8sError("listing pods", err), nil, nil
-		}
+		podsErr := err
+		gaps.record("Pods", err)
 
 		unhealthyPods := 0
 		highRestartPods := 0
@@ -241,22 +382,28 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 
 		sb.WriteString(util.FormatSubHeader("Pod Summary"))
 		sb.WriteString("\n")
-		sb.WriteString(fmt.Sprintf("  Total: %d, Unhealthy: %d, High Restarts: %d\n", len(pods), unhealthyPods, highRestartPods))
+		if podsErr != nil {
+			sb.WriteString(fmt.Sprintf("  Not collected: %s\n", gapReason(podsErr)))
+		} else {
+			sb.WriteString(fmt.Sprintf("  Total: %d, Unhealthy: %d, High Restarts: %d\n", len(pods), unhealthyPods, highRestartPods))
+		}
 
 		if unhealthyPods > 0 {
-			sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("%d unhealthy pods", unhealthyPods))))
+			sb.WriteString(fmt.Sprintf("\n%s\n", findings.Format("KD-POD-010", "CRITICAL", fmt.Sprintf("%d unhealthy pods", unhealthyPods))))
 			for i := range pods {
 				p := &pods[i]
 				if !isPodHealthy(p) {
 					_, _, restarts := podContainerSummary(p)
 					sb.WriteString(fmt.Sprintf("  - %s: %s (restarts: %d)\n", p.Name, podPhaseReason(p), restarts))
+					steps = append(steps, nextStep("diagnose_pod", fmt.Sprintf("Pod is %s", podPhaseReason(p)),
+						"namespace", p.Namespace, "name", p.Name))
 				}
 			}
-			findings++
+			findingCount++
 		}
 
 		if highRestartPods > 0 {
-			sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d pods with >%d restarts", highRestartPods, util.HighRestartThreshold))))
+			sb.WriteString(fmt.Sprintf("\n%s\n", findings.Format("KD-POD-005", "WARNING", fmt.Sprintf("%d pods with >%d restarts", highRestartPods, util.HighRestartThreshold))))
 			for i := range pods {
 				p := &pods[i]
 				_, _, restarts := podContainerSummary(p)
@@ -264,11 +411,12 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 					sb.WriteString(fmt.Sprintf("  - %s: %d restarts\n", p.Name, restarts))
 				}
 			}
-			findings++
+			findingCount++
 		}
 
 		// 2. Check deployments
 		deployments, err := client.ListDeployments(ctx, input.Namespace, metav1.ListOptions{})
+		gaps.record("Deployments", err)
 		if err == nil {
 			failingDeploys := 0
 			for _, d := range deployments {
@@ -281,7 +429,7 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 				}
 			}
 			if failingDeploys > 0 {
-				sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d deployments with unavailable replicas", failingDeploys))))
+				sb.WriteString(fmt.Sprintf("\n%s\n", findings.Format("KD-WL-001", "WARNING", fmt.Sprintf("%d deployments with unavailable replicas", failingDeploys))))
 				for _, d := range deployments {
 					desired := int32(0)
 					if d.Spec.Replicas != nil {
@@ -289,14 +437,17 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 					}
 					if d.Status.AvailableReplicas < desired {
 						sb.WriteString(fmt.Sprintf("  - %s: %d/%d available\n", d.Name, d.Status.AvailableReplicas, desired))
+						steps = append(steps, nextStep("get_deployment_detail", fmt.Sprintf("%d/%d replicas available", d.Status.AvailableReplicas, desired),
+							"namespace", d.Namespace, "name", d.Name))
 					}
 				}
-				findings++
+				findingCount++
 			}
 		}
 
 		// 3. Warning events in last hour
 		events, err := client.ListEvents(ctx, input.Namespace, metav1.ListOptions{})
+		gaps.record("Warning Events", err)
 		if err == nil {
 			oneHourAgo := time.Now().Add(-1 * time.Hour)
 			warningCount := 0
@@ -310,13 +461,15 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 				}
 			}
 			if warningCount > 0 {
-				sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d warning events in the last hour", warningCount))))
-				findings++
+				sb.WriteString(fmt.Sprintf("\n%s\n", findings.Format("KD-EVT-001", "WARNING", fmt.Sprintf("%d warning events in the last hour", warningCount))))
+				findingCount++
+				steps = append(steps, nextStep("analyze_events", "Group the warning events by workload and reason", "namespace", input.Namespace))
 			}
 		}
 
 		// 4. Pending PVCs
 		pvcs, err := client.ListPVCs(ctx, input.Namespace, metav1.ListOptions{})
+		gaps.record("PVCs", err)
 		if err == nil {
 			pendingPVCs := 0
 			for _, pvc := range pvcs {
@@ -325,27 +478,34 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 				}
 			}
 			if pendingPVCs > 0 {
-				sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d PVCs not bound", pendingPVCs))))
+				sb.WriteString(fmt.Sprintf("\n%s\n", findings.Format("KD-STO-001", "WARNING", fmt.Sprintf("%d PVCs not bound", pendingPVCs))))
 				for _, pvc := range pvcs {
 					if pvc.Status.Phase != corev1.ClaimBound {
 						sb.WriteString(fmt.Sprintf("  - %s: %s\n", pvc.Name, pvc.Status.Phase))
 					}
 				}
-				findings++
+				findingCount++
+				steps = append(steps, nextStep("diagnose_storage", "PVCs are not bound", "namespace", input.Namespace))
 			}
 		}
 
+		gaps.write(&sb)
+
 		// Overall assessment
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
 		sb.WriteString("\n")
-		if findings == 0 {
+		switch {
+		case findingCount > 0:
+			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findingCount))
+		case len(gaps) > 0:
+			sb.WriteString("  No issues found in the sections that could be collected.\n")
+		default:
 			sb.WriteString("  Namespace appears healthy. No issues found.\n")
-		} else {
-			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findings))
 		}
+		sb.WriteString(gaps.assessment())
 
-		return util.SuccessResult(sb.String()), nil, nil
+		return util.WithNextSteps(finishReport(sb.String(), detail), steps), nil, nil
 	})
 
 	// diagnose_cluster
@@ -353,42 +513,53 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Name:        "diagnose_cluster",
 		Description: "Cluster-wide health check. Checks node conditions, pod health across all namespaces, kube-system health, and warning events. Use this for a broad cluster health overview.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input diagnoseClusterInput) (*mcp.CallToolResult, any, error) {
+		detail, err := util.ParseDetailLevel(input.DetailLevel)
+		if err != nil {
+			return util.ErrorResult("%v", err), nil, nil
+		}
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader("Cluster Health Report"))
 		sb.WriteString("\n\n")
 
-		findings := 0
+		findingCount := 0
+		var steps []util.NextStep
+		var gaps dataGaps
 
 		// 1. Node health
 		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
-		if err != nil {
-			return util.HandleK8sError("listing nodes", err), nil, nil
-		}
 
 		sb.WriteString(util.FormatSubHeader("Node Health"))
 		sb.WriteString("\n")
+		writeScopeSkipped(&sb, err)
+		if gaps.record("Node Health", err) {
+			sb.WriteString(fmt.Sprintf("  Not collected: %s\n", gapReason(err)))
+		}
 		notReadyNodes := 0
 		pressureNodes := 0
 		for _, n := range nodes {
 			for _, cond := range n.Status.Conditions {
 				if cond.Type == corev1.NodeReady && cond.Status != corev1.ConditionTrue {
-					sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("Node '%s' is NotReady", n.Name))))
+					sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-NODE-001", "CRITICAL", fmt.Sprintf("Node '%s' is NotReady", n.Name))))
 					notReadyNodes++
-					findings++
+					findingCount++
+					steps = append(steps, nextStep("get_node_detail", "Node is NotReady", "name", n.Name))
 				}
 				if (cond.Type == corev1.NodeMemoryPressure || cond.Type == corev1.NodeDiskPressure || cond.Type == corev1.NodePIDPressure) && cond.Status == corev1.ConditionTrue {
-					sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("Node '%s' has %s", n.Name, cond.Type))))
+					sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-NODE-002", "WARNING", fmt.Sprintf("Node '%s' has %s", n.Name, cond.Type))))
 					pressureNodes++
-					findings++
+					findingCount++
+					steps = append(steps, nextStep("get_node_detail", fmt.Sprintf("Node has %s", cond.Type), "name", n.Name))
 				}
 			}
 		}
-		if notReadyNodes == 0 && pressureNodes == 0 {
+		if err == nil && notReadyNodes == 0 && pressureNodes == 0 {
 			sb.WriteString(fmt.Sprintf("  All %d nodes healthy.\n", len(nodes)))
 		}
 
 		// 2. Pod summary across all namespaces
 		pods, err := client.ListPods(ctx, "", metav1.ListOptions{})
+		gaps.record("Pod Summary", err)
 		if err == nil {
 			phases := make(map[string]int)
 			unhealthy := 0
@@ -407,13 +578,15 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 				sb.WriteString(fmt.Sprintf("  %s: %d\n", phase, count))
 			}
 			if unhealthy > 0 {
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d unhealthy pods cluster-wide", unhealthy))))
-				findings++
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-POD-010", "WARNING", fmt.Sprintf("%d unhealthy pods cluster-wide", unhealthy))))
+				findingCount++
+				steps = append(steps, nextStep("cluster_crashloops", "Group failing pods cluster-wide by root cause"))
 			}
 		}
 
 		// 3. Warning events cluster-wide in last hour
 		events, err := client.ListEvents(ctx, "", metav1.ListOptions{})
+		gaps.record("Recent Events", err)
 		if err == nil {
 			oneHourAgo := time.Now().Add(-1 * time.Hour)
 			warningCount := 0
@@ -430,8 +603,9 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 			sb.WriteString(util.FormatSubHeader("Recent Events"))
 			sb.WriteString("\n")
 			if warningCount > 0 {
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d warning events in the last hour", warningCount))))
-				findings++
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-EVT-001", "WARNING", fmt.Sprintf("%d warning events in the last hour", warningCount))))
+				findingCount++
+				steps = append(steps, nextStep("analyze_events", "Group the warning events by workload and detect spikes"))
 			} else {
 				sb.WriteString("  No warning events in the last hour.\n")
 			}
@@ -439,6 +613,7 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 
 		// 4. kube-system health
 		kubeSystemPods, err := client.ListPods(ctx, "kube-system", metav1.ListOptions{})
+		gaps.record("kube-system Health", err)
 		if err == nil {
 			kubeUnhealthy := 0
 			for i := range kubeSystemPods {
@@ -450,14 +625,15 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 			sb.WriteString(util.FormatSubHeader("kube-system Health"))
 			sb.WriteString("\n")
 			if kubeUnhealthy > 0 {
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("%d unhealthy pods in kube-system", kubeUnhealthy))))
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-SYS-001", "CRITICAL", fmt.Sprintf("%d unhealthy pods in kube-system", kubeUnhealthy))))
 				for i := range kubeSystemPods {
 					p := &kubeSystemPods[i]
 					if !isPodHealthy(p) {
 						sb.WriteString(fmt.Sprintf("  - %s: %s\n", p.Name, podPhaseReason(p)))
 					}
 				}
-				findings++
+				findingCount++
+				steps = append(steps, nextStep("diagnose_namespace", "System pods are unhealthy", "namespace", "kube-system"))
 			} else {
 				sb.WriteString(fmt.Sprintf("  All %d kube-system pods healthy.\n", len(kubeSystemPods)))
 			}
@@ -465,6 +641,7 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 
 		// 5. Resource utilization (if metrics available)
 		nodeMetrics, err := client.GetNodeMetrics(ctx)
+		gaps.record("Resource Utilization", err)
 		if err == nil && len(nodeMetrics) > 0 {
 			sb.WriteString("\n")
 			sb.WriteString(util.FormatSubHeader("Resource Utilization"))
@@ -487,17 +664,23 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 			}
 		}
 
+		gaps.write(&sb)
+
 		// Overall
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
 		sb.WriteString("\n")
-		if findings == 0 {
+		switch {
+		case findingCount > 0:
+			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findingCount))
+		case len(gaps) > 0:
+			sb.WriteString("  No issues found in the sections that could be collected.\n")
+		default:
 			sb.WriteString("  Cluster appears healthy. No issues found.\n")
-		} else {
-			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findings))
 		}
+		sb.WriteString(gaps.assessment())
 
-		return util.SuccessResult(sb.String()), nil, nil
+		return util.WithNextSteps(finishReport(sb.String(), detail), steps), nil, nil
 	})
 
 	// find_unhealthy_pods
@@ -614,6 +797,14 @@ func registerDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
 	})
 }
 
+// writeScopeSkipped notes that a cluster-scope section was skipped because the
+// client runs in namespace-scoped mode. It writes nothing for other errors.
+func writeScopeSkipped(sb *strings.Builder, err error) {
+	if util.IsScopeError(err) {
+		sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-SYS-008", "INFO", "Skipped: "+err.Error())))
+	}
+}
+
 // isPodHealthy returns true if the pod is in a healthy state.
 func isPodHealthy(p *corev1.Pod) bool {
 	// Running and all containers ready
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/mermaid"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// diagramPodNode is a pod, or a collapsed group of healthy pods, to draw in a flowchart.
+type diagramPodNode struct {
+	ID       string
+	Label    string
+	Healthy  bool
+	PodNames []string
+}
+
+// summarizePodsForDiagram groups pods by their owning workload. When a group has
+// more than util.DiagramPodCollapseThreshold pods, its healthy pods are collapsed
+// into one node with a replica badge; unhealthy pods always stay expanded so
+// problems remain visible. label renders an individual pod node.
+func summarizePodsForDiagram(pods []corev1.Pod, label func(*corev1.Pod) string) []diagramPodNode {
+	var order []string
+	groups := make(map[string][]int)
+	for i := range pods {
+		owner := podWorkloadName(&pods[i])
+		if _, ok := groups[owner]; !ok {
+			order = append(order, owner)
+		}
+		groups[owner] = append(groups[owner], i)
+	}
+
+	var nodes []diagramPodNode
+	for _, owner := range order {
+		idx := groups[owner]
+		collapse := len(idx) > util.DiagramPodCollapseThreshold
+
+		var collapsed []string
+		for _, i := range idx {
+			p := &pods[i]
+			healthy := isPodHealthy(p)
+			if healthy && collapse {
+				collapsed = append(collapsed, p.Name)
+				continue
+			}
+			nodes = append(nodes, diagramPodNode{
+				ID:       mermaid.SafeID("pod_" + p.Name),
+				Label:    label(p),
+				Healthy:  healthy,
+				PodNames: []string{p.Name},
+			})
+		}
+
+		if len(collapsed) > 0 {
+			nodes = append(nodes, diagramPodNode{
+				ID:       mermaid.SafeID("pods_" + owner),
+				Label:    fmt.Sprintf("%s%s[x%d healthy of %d]", owner, mermaid.BR(), len(collapsed), len(idx)),
+				Healthy:  true,
+				PodNames: collapsed,
+			})
+		}
+	}
+	return nodes
+}
+
+// podWorkloadName returns the name of the workload that owns a pod. Pods owned by
+// a ReplicaSet are attributed to the Deployment by trimming the pod-template-hash.
+// Standalone pods are their own group.
+func podWorkloadName(pod *corev1.Pod) string {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if ref.Kind == "ReplicaSet" {
+			if hash := pod.Labels["pod-template-hash"]; hash != "" {
+				return strings.TrimSuffix(ref.Name, "-"+hash)
+			}
+		}
+		return ref.Name
+	}
+	return pod.Name
+}
+
+// diagramNodeIDForPod returns the ID of the node that represents the named pod.
+func diagramNodeIDForPod(nodes []diagramPodNode, podName string) string {
+	for _, n := range nodes {
+		for _, name := range n.PodNames {
+			if name == podName {
+				return n.ID
+			}
+		}
+	}
+	return mermaid.SafeID("pod_" + podName)
+}
+
+// styleDiagramPodNode applies the health style for a pod node.
+func styleDiagramPodNode(fc *mermaid.Flowchart, n diagramPodNode) {
+	if n.Healthy {
+		fc.AddStyle(n.ID, mermaid.SeverityHealthy)
+	} else {
+		fc.AddStyle(n.ID, mermaid.SeverityCritical)
+	}
+}
+
+// newBudgetedFlowchart creates a flowchart limited to maxNodes nodes and twice
+// as many edges, or with the default budget when maxNodes is 0 or less.
+func newBudgetedFlowchart(dir mermaid.Direction, maxNodes int) *mermaid.Flowchart {
+	fc := mermaid.NewFlowchart(dir)
+	if maxNodes > 0 {
+		fc.WithBudget(maxNodes, 2*maxNodes)
+	}
+	return fc
+}
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/mermaid"
+)
+
+func TestSummarizePodsForDiagram(t *testing.T) {
+	newPod := func(name string, ready bool) corev1.Pod {
+		return withReady(withOwner(testPod("", name, "", map[string]string{"pod-template-hash": "7d9f"}), "ReplicaSet", "web-7d9f"), ready)
+	}
+
+	var pods []corev1.Pod
+	for i := 0; i < 10; i++ {
+		pods = append(pods, newPod(fmt.Sprintf("web-7d9f-%d", i), i != 3))
+	}
+
+	nodes := summarizePodsForDiagram(pods, func(p *corev1.Pod) string { return p.Name })
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes (1 unhealthy + 1 collapsed), got %d", len(nodes))
+	}
+	if nodes[0].Healthy || nodes[0].PodNames[0] != "web-7d9f-3" {
+		t.Errorf("expected unhealthy pod web-7d9f-3 to stay expanded, got %+v", nodes[0])
+	}
+	if len(nodes[1].PodNames) != 9 {
+		t.Errorf("expected 9 pods in collapsed node, got %d", len(nodes[1].PodNames))
+	}
+	if got := diagramNodeIDForPod(nodes, "web-7d9f-5"); got != nodes[1].ID {
+		t.Errorf("expected web-7d9f-5 to map to collapsed node %s, got %s", nodes[1].ID, got)
+	}
+
+	small := summarizePodsForDiagram(pods[:3], func(p *corev1.Pod) string { return p.Name })
+	if len(small) != 3 {
+		t.Errorf("expected small workloads to stay expanded, got %d nodes", len(small))
+	}
+}
+
+func TestNewBudgetedFlowchart(t *testing.T) {
+	for _, tt := range []struct {
+		maxNodes int
+		pruned   bool
+	}{{0, false}, {3, true}} {
+		fc := newBudgetedFlowchart(mermaid.DirectionLR, tt.maxNodes)
+		for i := 0; i < 5; i++ {
+			fc.AddNode(fmt.Sprintf("n%d", i), "node", mermaid.ShapeRect)
+		}
+		if got := fc.PruneWarning() != ""; got != tt.pruned {
+			t.Errorf("maxNodes %d: pruned = %v, want %v", tt.maxNodes, got, tt.pruned)
+		}
+	}
+}
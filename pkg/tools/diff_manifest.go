@@ -0,0 +1,425 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// maxDriftRows caps the differences listed per object.
+const maxDriftRows = 40
+
+// driftUnset stands for a field missing from one side of a diff.
+const driftUnset = "<unset>"
+
+type diffManifestInput struct {
+	Manifest       string `json:"manifest" jsonschema:"required,YAML or JSON manifest of the objects as you expect them to run; several documents separated by --- and List objects are accepted"`
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace for namespaced objects that do not set metadata.namespace (default: default)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// fieldDrift is one field whose live value differs from the manifest.
+// Category is image, env, resources, replicas, or other; Note explains the
+// difference using the last-applied configuration when there is one.
+type fieldDrift struct {
+	Path     string
+	Category string
+	Manifest string
+	Live     string
+	Note     string
+}
+
+func registerDiffManifestTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "diff_manifest",
+		Description: "Compare a manifest with the live objects it describes to answer \"is what's running what we think is running\". Every field set in the " +
+			"manifest is compared with the live object, so server defaults are not reported; containers, env vars, ports, and volumes are matched by name. " +
+			"Highlights drift in images, env, resources, and replica counts, and uses kubectl's last-applied-configuration to tell out-of-band edits from " +
+			"manifest changes that were never applied and fields apply would remove. Objects missing from the cluster are listed. Credential-like values are redacted.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input diffManifestInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if strings.TrimSpace(input.Manifest) == "" {
+			return util.ErrorResult("manifest is required"), nil, nil
+		}
+		docs, err := parseManifest(input.Manifest)
+		if err != nil {
+			return util.ErrorResult("cannot parse manifest: %v", err), nil, nil
+		}
+		if len(docs) == 0 {
+			return util.ErrorResult("manifest contains no objects"), nil, nil
+		}
+		if len(docs) > maxManifestDocs {
+			return util.ErrorResult("manifest has %d objects; diff at most %d per call", len(docs), maxManifestDocs), nil, nil
+		}
+		defaultNS := input.Namespace
+		if defaultNS == "" {
+			defaultNS = "default"
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Manifest Diff (manifest vs live)"))
+		sb.WriteString("\n")
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		refs := make(map[schema.GroupVersionKind]k8s.APIResourceRef)
+		inSync := 0
+		for _, doc := range docs {
+			obj := doc.Obj
+			label := fmt.Sprintf("[%d] %s %s", doc.Index, valueOrNone(obj.GetKind()), valueOrNone(obj.GetName()))
+			if problems := manifestObjectProblems(obj); len(problems) > 0 {
+				sb.WriteString(fmt.Sprintf("\n%s: skipped, %s\n", label, strings.Join(problems, "; ")))
+				continue
+			}
+			gvk := obj.GroupVersionKind()
+			ref, ok := refs[gvk]
+			if !ok {
+				if ref, err = client.ResolveResource(ctx, gvk.Group, gvk.Version, gvk.Kind); err != nil {
+					sb.WriteString(fmt.Sprintf("\n%s: skipped, %v\n", label, err))
+					continue
+				}
+				refs[gvk] = ref
+			}
+			if ref.Namespaced && obj.GetNamespace() == "" {
+				obj.SetNamespace(defaultNS)
+			} else if !ref.Namespaced {
+				obj.SetNamespace("")
+			}
+			name := fmt.Sprintf("%s %s", gvk.Kind, manifestObjectName(obj))
+
+			live, err := client.GetResource(ctx, ref, obj.GetNamespace(), obj.GetName())
+			if apierrors.IsNotFound(err) {
+				sb.WriteString(fmt.Sprintf("\n%s: not found on the cluster\n", name))
+				findingLines = append(findingLines, findings.Format("KD-CFG-004", "WARNING", fmt.Sprintf("%s is in the manifest but not on the cluster", name)))
+				actions = append(actions, "Apply the manifest (validate_manifest first) or check it targets the right cluster and namespace")
+				continue
+			}
+			if err != nil {
+				sb.WriteString(fmt.Sprintf("\n%s: not compared, %s\n", name, gapReason(err)))
+				continue
+			}
+
+			drifts := diffManifestObject(obj, live)
+			if len(drifts) == 0 {
+				inSync++
+				sb.WriteString(fmt.Sprintf("\n%s: in sync\n", name))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("\n%s: %d difference(s)\n", name, len(drifts)))
+			rows := make([][]string, 0, min(len(drifts), maxDriftRows))
+			for _, d := range drifts[:min(len(drifts), maxDriftRows)] {
+				rows = append(rows, []string{d.Path, d.Manifest, d.Live, d.Note})
+			}
+			sb.WriteString(util.FormatTable([]string{"FIELD", "MANIFEST", "LIVE", "NOTE"}, rows))
+			if len(drifts) > maxDriftRows {
+				sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(drifts)-maxDriftRows))
+			}
+
+			counts := make(map[string]int)
+			for _, d := range drifts {
+				counts[d.Category]++
+			}
+			var parts []string
+			for _, c := range []string{"image", "env", "resources", "replicas", "other"} {
+				if counts[c] > 0 {
+					parts = append(parts, fmt.Sprintf("%s (%d)", c, counts[c]))
+				}
+			}
+			severity := "INFO"
+			if counts["image"]+counts["env"]+counts["resources"]+counts["replicas"] > 0 {
+				severity = "WARNING"
+			}
+			findingLines = append(findingLines, findings.Format("KD-CFG-004", severity, fmt.Sprintf("%s has drifted from the manifest: %s", name, strings.Join(parts, ", "))))
+			if counts["replicas"] > 0 && len(drifts) == counts["replicas"] {
+				actions = append(actions, fmt.Sprintf("Check whether an HPA or a manual scale owns %s's replicas; if so, drop replicas from the manifest instead of reapplying it", name))
+			}
+			for _, d := range drifts {
+				switch {
+				case strings.HasPrefix(d.Note, "changed on the cluster"):
+					actions = append(actions, "Find who changed the live objects out of band (kubectl edit/set/scale, another controller) and either commit the change to the manifest or reapply it")
+				case strings.HasPrefix(d.Note, "not applied"):
+					actions = append(actions, "Apply the pending manifest changes, or revert them if the live state is what should run")
+				}
+			}
+			if gvk.Kind == "Deployment" {
+				steps = append(steps, nextStep("correlate_rollouts", "see when the live Deployment last rolled out", "namespace", obj.GetNamespace()))
+			}
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("All %d compared object(s) match the manifest", inSync)))
+			sb.WriteString("\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+		for _, f := range findingLines {
+			sb.WriteString(f + "\n")
+		}
+		if actions = dedupe(actions); len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range actions {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// diffManifestObject compares the fields set in a manifest object with the
+// live object, ignoring status and server-managed metadata. When the live
+// object carries kubectl's last-applied-configuration, each difference is
+// attributed to the cluster or the manifest, and fields the manifest dropped
+// since the last apply are reported too.
+func diffManifestObject(desired, live *unstructured.Unstructured) []fieldDrift {
+	want := make(map[string]any)
+	flattenObject("", driftComparable(desired), want)
+	have := make(map[string]any)
+	flattenObject("", driftComparable(live), have)
+
+	var applied map[string]any
+	if raw := live.GetAnnotations()[corev1.LastAppliedConfigAnnotation]; raw != "" {
+		var obj map[string]any
+		if json.Unmarshal([]byte(raw), &obj) == nil {
+			applied = make(map[string]any)
+			flattenObject("", driftComparable(&unstructured.Unstructured{Object: obj}), applied)
+		}
+	}
+
+	secret := desired.GetKind() == "Secret" && desired.GroupVersionKind().Group == ""
+	var drifts []fieldDrift
+	for _, path := range sortedMapKeys(want) {
+		m, l := want[path], have[path]
+		_, inLive := have[path]
+		if inLive && driftEqual(path, m, l) {
+			continue
+		}
+		d := fieldDrift{Path: path, Category: driftCategory(path), Manifest: driftValue(path, m, secret), Live: driftUnset}
+		if inLive {
+			d.Live = driftValue(path, l, secret)
+		}
+		if applied != nil {
+			a, inApplied := applied[path]
+			switch {
+			case inApplied && driftEqual(path, a, m):
+				d.Note = "changed on the cluster since the last apply"
+			case inApplied && inLive && driftEqual(path, a, l), !inApplied && !inLive:
+				d.Note = "not applied yet: the manifest changed since the last apply"
+			}
+		}
+		drifts = append(drifts, d)
+	}
+	for _, path := range sortedMapKeys(applied) {
+		if _, ok := want[path]; ok || hasFieldUnder(want, path) {
+			continue
+		}
+		if l, ok := have[path]; ok {
+			drifts = append(drifts, fieldDrift{Path: path, Category: driftCategory(path), Manifest: driftUnset, Live: driftValue(path, l, secret),
+				Note: "removed from the manifest; kubectl apply would delete it"})
+		}
+	}
+	return drifts
+}
+
+// driftComparable returns the parts of an object a manifest diff compares:
+// everything but status and metadata, plus labels and annotations. Secret
+// stringData is folded into data as the API server stores it.
+func driftComparable(obj *unstructured.Unstructured) map[string]any {
+	out := make(map[string]any, len(obj.Object))
+	for k, v := range obj.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		out[k] = v
+	}
+	meta := make(map[string]any)
+	if labels := obj.GetLabels(); len(labels) > 0 {
+		meta["labels"] = toAnyMap(labels)
+	}
+	annotations := obj.GetAnnotations()
+	delete(annotations, corev1.LastAppliedConfigAnnotation)
+	if len(annotations) > 0 {
+		meta["annotations"] = toAnyMap(annotations)
+	}
+	if len(meta) > 0 {
+		out["metadata"] = meta
+	}
+	if sd, ok := out["stringData"].(map[string]any); ok && obj.GetKind() == "Secret" {
+		data, _ := out["data"].(map[string]any)
+		if data == nil {
+			data = make(map[string]any)
+		}
+		for k, v := range sd {
+			if s, ok := v.(string); ok {
+				data[k] = base64.StdEncoding.EncodeToString([]byte(s))
+			}
+		}
+		out["data"] = data
+		delete(out, "stringData")
+	}
+	return out
+}
+
+func toAnyMap(m map[string]string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// flattenObject writes each leaf of v under its dotted path. Lists of maps
+// are keyed by a unique name field (containers, env, ports, volumes) or else
+// by index, so items match across objects regardless of order; lists of
+// scalars are compared whole. Nulls and empty maps are not leaves.
+func flattenObject(path string, v any, out map[string]any) {
+	switch val := v.(type) {
+	case nil:
+		return
+	case map[string]any:
+		for k, child := range val {
+			key := k
+			if path != "" {
+				key = path + "." + k
+			}
+			flattenObject(key, child, out)
+		}
+	case []any:
+		keys := listItemKeys(val)
+		if keys == nil {
+			if len(val) > 0 || path != "" {
+				out[path] = val
+			}
+			return
+		}
+		for i, item := range val {
+			flattenObject(fmt.Sprintf("%s[%s]", path, keys[i]), item, out)
+		}
+	default:
+		out[path] = val
+	}
+}
+
+// listItemKeys returns the key of each item of a list of maps: its name or
+// mountPath when every item has a unique one, otherwise its index. It
+// returns nil for lists of scalars.
+func listItemKeys(items []any) []string {
+	if len(items) == 0 {
+		return nil
+	}
+	for _, field := range []string{"name", "mountPath"} {
+		keys := make([]string, 0, len(items))
+		seen := make(map[string]bool)
+		for _, item := range items {
+			m, ok := item.(map[string]any)
+			if !ok {
+				return nil
+			}
+			k, _ := m[field].(string)
+			if k == "" || seen[k] {
+				break
+			}
+			seen[k] = true
+			keys = append(keys, k)
+		}
+		if len(keys) == len(items) {
+			return keys
+		}
+	}
+	keys := make([]string, len(items))
+	for i, item := range items {
+		if _, ok := item.(map[string]any); !ok {
+			return nil
+		}
+		keys[i] = strconv.Itoa(i)
+	}
+	return keys
+}
+
+// driftEqual compares two leaves. Resource quantities compare by value, so
+// "0.5" equals the "500m" the API server stores; numbers compare regardless
+// of their decoded type.
+func driftEqual(path string, a, b any) bool {
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if aok && bok && strings.Contains(path, "resources.") {
+		qa, errA := resource.ParseQuantity(as)
+		qb, errB := resource.ParseQuantity(bs)
+		if errA == nil && errB == nil {
+			return qa.Cmp(qb) == 0
+		}
+	}
+	return driftJSON(a) == driftJSON(b)
+}
+
+func driftJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// driftCategory groups a field path for the findings summary.
+func driftCategory(path string) string {
+	switch {
+	case path == "spec.replicas":
+		return "replicas"
+	case strings.HasSuffix(path, ".image") && strings.Contains(path, "ontainers["):
+		return "image"
+	case strings.Contains(path, ".env[") || strings.Contains(path, ".envFrom"):
+		return "env"
+	case strings.Contains(path, ".resources."):
+		return "resources"
+	}
+	return "other"
+}
+
+// driftValue formats a leaf for display. Secret data and values under
+// credential-like env vars, keys, or annotations are redacted.
+func driftValue(path string, v any, secret bool) string {
+	s, ok := v.(string)
+	if !ok {
+		s = driftJSON(v)
+	}
+	if secret && (strings.HasPrefix(path, "data.") || strings.HasPrefix(path, "stringData.")) {
+		return util.Redacted
+	}
+	return truncateName(util.RedactValue(driftFieldName(path), s), 60)
+}
+
+// driftFieldName returns the name that decides whether a leaf is sensitive:
+// the env var name for env values, otherwise the last path element.
+func driftFieldName(path string) string {
+	if i := strings.LastIndex(path, ".env["); i >= 0 {
+		if name, _, ok := strings.Cut(path[i+len(".env["):], "]"); ok {
+			return name
+		}
+	}
+	return path[strings.LastIndexAny(path, ".[")+1:]
+}
+
+// hasFieldUnder reports whether any key of m lies under path, so a field the
+// manifest still sets in part is not reported as removed.
+func hasFieldUnder(m map[string]any, path string) bool {
+	for k := range m {
+		if strings.HasPrefix(k, path+".") || strings.HasPrefix(k, path+"[") {
+			return true
+		}
+	}
+	return false
+}
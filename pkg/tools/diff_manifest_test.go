@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDiffManifestObject(t *testing.T) {
+	docs, err := parseManifest(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: web
+        image: shop/web:1.5.0
+        env:
+        - name: LOG_LEVEL
+          value: info
+        - name: DB_PASSWORD
+          value: hunter2
+        resources:
+          requests:
+            cpu: "0.5"
+            memory: 256Mi
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired := docs[0].Obj
+
+	applied := map[string]any{
+		"apiVersion": "apps/v1", "kind": "Deployment",
+		"metadata": map[string]any{"name": "web", "namespace": "shop"},
+		"spec": map[string]any{
+			"replicas": 3,
+			"paused":   false,
+			"template": map[string]any{"spec": map[string]any{"containers": []any{map[string]any{
+				"name": "web", "image": "shop/web:1.4.0",
+				"env": []any{
+					map[string]any{"name": "LOG_LEVEL", "value": "info"},
+					map[string]any{"name": "DB_PASSWORD", "value": "hunter2"},
+				},
+			}}}},
+		},
+	}
+	raw, _ := json.Marshal(applied)
+	live := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1", "kind": "Deployment",
+		"metadata": map[string]any{
+			"name": "web", "namespace": "shop",
+			"annotations": map[string]any{corev1.LastAppliedConfigAnnotation: string(raw)},
+		},
+		"spec": map[string]any{
+			"replicas":             int64(5),
+			"paused":               false,
+			"progressDeadlineSecs": int64(600),
+			"template": map[string]any{"spec": map[string]any{"containers": []any{
+				map[string]any{"name": "istio-proxy", "image": "istio/proxyv2:1.22"},
+				map[string]any{
+					"name": "web", "image": "shop/web:1.4.0", "imagePullPolicy": "IfNotPresent",
+					"env": []any{
+						map[string]any{"name": "DB_PASSWORD", "value": "changed"},
+						map[string]any{"name": "LOG_LEVEL", "value": "info"},
+					},
+					"resources": map[string]any{"requests": map[string]any{"cpu": "500m", "memory": "256Mi"}},
+				},
+			}}},
+		},
+		"status": map[string]any{"replicas": int64(5)},
+	}}
+
+	byPath := make(map[string]fieldDrift)
+	for _, d := range diffManifestObject(desired, live) {
+		byPath[d.Path] = d
+	}
+
+	if d, ok := byPath["spec.replicas"]; !ok || d.Category != "replicas" || d.Live != "5" || d.Note != "changed on the cluster since the last apply" {
+		t.Errorf("expected out-of-band replica drift, got %+v", d)
+	}
+	image := "spec.template.spec.containers[web].image"
+	if d, ok := byPath[image]; !ok || d.Category != "image" || d.Manifest != "shop/web:1.5.0" || d.Note != "not applied yet: the manifest changed since the last apply" {
+		t.Errorf("expected pending image change, got %+v", d)
+	}
+	secret := "spec.template.spec.containers[web].env[DB_PASSWORD].value"
+	if d, ok := byPath[secret]; !ok || d.Category != "env" || d.Manifest != "<redacted>" || d.Live != "<redacted>" {
+		t.Errorf("expected redacted env drift, got %+v", d)
+	}
+	if d, ok := byPath["spec.paused"]; !ok || d.Manifest != driftUnset || d.Note != "removed from the manifest; kubectl apply would delete it" {
+		t.Errorf("expected field dropped from the manifest, got %+v", d)
+	}
+	for _, quiet := range []string{
+		"spec.template.spec.containers[web].env[LOG_LEVEL].value",
+		"spec.template.spec.containers[web].resources.requests.cpu",
+		"spec.template.spec.containers[istio-proxy].image",
+		"spec.progressDeadlineSecs",
+	} {
+		if d, ok := byPath[quiet]; ok {
+			t.Errorf("expected no drift for %s, got %+v", quiet, d)
+		}
+	}
+	if len(byPath) != 4 {
+		t.Errorf("expected 4 differences, got %d: %+v", len(byPath), byPath)
+	}
+}
+
+func TestListItemKeys(t *testing.T) {
+	mounts := []any{
+		map[string]any{"name": "data", "mountPath": "/data"},
+		map[string]any{"name": "data", "mountPath": "/backup"},
+	}
+	if keys := listItemKeys(mounts); len(keys) != 2 || keys[0] != "/data" {
+		t.Errorf("expected mountPath keys for duplicate names, got %v", keys)
+	}
+	if keys := listItemKeys([]any{map[string]any{"port": 80}}); len(keys) != 1 || keys[0] != "0" {
+		t.Errorf("expected index keys, got %v", keys)
+	}
+	if keys := listItemKeys([]any{"--verbose"}); keys != nil {
+		t.Errorf("expected scalar lists to be compared whole, got %v", keys)
+	}
+}
@@ -7,20 +7,27 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
 )
 
 type listCRDsInput struct {
-	GroupFilter string `json:"group_filter,omitempty" jsonschema:"Filter by API group (substring match)"`
+	GroupFilter   string `json:"group_filter,omitempty" jsonschema:"Filter by API group (substring match)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type getAPIResourcesInput struct {
 	GroupFilter string `json:"group_filter,omitempty" jsonschema:"Filter by API group (substring match)"`
 }
 
-type listWebhookConfigsInput struct{}
+type listWebhookConfigsInput struct {
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum webhook configurations per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
+}
 
 func registerDiscoveryTools(server *mcp.Server, client *k8s.ClusterClient) {
 	// list_crds
@@ -28,10 +35,11 @@ func registerDiscoveryTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Name:        "list_crds",
 		Description: "List Custom Resource Definitions with group, version, scope, and age. Optional group filter to narrow results. Useful for discovering what CRDs are installed in the cluster.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listCRDsInput) (*mcp.CallToolResult, any, error) {
-		crds, err := client.ListCRDs(ctx)
+		page, err := client.ListCRDsPage(ctx, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing CRDs", err), nil, nil
 		}
+		crds := page.Items
 
 		headers := []string{"NAME", "GROUP", "VERSION", "SCOPE", "AGE"}
 		rows := make([][]string, 0, len(crds))
@@ -68,6 +76,7 @@ func registerDiscoveryTools(server *mcp.Server, client *k8s.ClusterClient) {
 		}
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("CRDs", len(rows))))
+		sb.WriteString(pageFooter("list_crds", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -126,102 +135,117 @@ func registerDiscoveryTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Name:        "list_webhook_configs",
 		Description: "List mutating and validating webhook configurations with service endpoints, failure policies, rules, and timeouts. Warns when failurePolicy is Fail, which can block cluster operations if the webhook is down.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listWebhookConfigsInput) (*mcp.CallToolResult, any, error) {
-		var sb strings.Builder
-		sb.WriteString(util.FormatHeader("Webhook Configurations"))
-		sb.WriteString("\n\n")
-
-		// Mutating webhooks
-		sb.WriteString(util.FormatSubHeader("Mutating Webhooks"))
-		sb.WriteString("\n")
-		mutating, err := client.ListMutatingWebhookConfigurations(ctx)
-		if err != nil {
-			sb.WriteString(fmt.Sprintf("  (could not list: %v)\n", err))
-		} else if len(mutating) == 0 {
-			sb.WriteString("  (none)\n")
-		} else {
-			for _, mwc := range mutating {
-				sb.WriteString(fmt.Sprintf("\n  %s:\n", mwc.Name))
-				for _, wh := range mwc.Webhooks {
-					failPolicy := "Ignore"
-					if wh.FailurePolicy != nil {
-						failPolicy = string(*wh.FailurePolicy)
-					}
-					timeout := int32(10)
-					if wh.TimeoutSeconds != nil {
-						timeout = *wh.TimeoutSeconds
-					}
-					endpoint := formatWebhookEndpoint(wh.ClientConfig.Service, wh.ClientConfig.URL)
-					sb.WriteString(fmt.Sprintf("    Webhook: %s\n", wh.Name))
-					sb.WriteString(fmt.Sprintf("      Endpoint: %s\n", endpoint))
-					sb.WriteString(fmt.Sprintf("      Failure Policy: %s\n", failPolicy))
-					sb.WriteString(fmt.Sprintf("      Timeout: %ds\n", timeout))
-					if len(wh.Rules) > 0 {
-						for _, rule := range wh.Rules {
-							ops := operationStrings(rule.Operations)
-							sb.WriteString(fmt.Sprintf("      Rule: %s %s %s\n",
-								strings.Join(ops, ","),
-								strings.Join(rule.APIGroups, ","),
-								strings.Join(rule.Resources, ",")))
+		// Mutating configurations are paged first, then validating ones; a
+		// section not reached on this page says so.
+		var mutSB, valSB strings.Builder
+		mutSB.WriteString("  (listed on another page)\n")
+		valSB.WriteString("  (listed on another page)\n")
+		totalMut, totalVal := 0, 0
+		next, remaining, err := pagePhases(util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken), []pagePhase{
+			{name: "mutating", list: func(opts metav1.ListOptions) (int, string, *int64, error) {
+				mutSB.Reset()
+				page, err := client.ListMutatingWebhookConfigurationsPage(ctx, opts)
+				if err != nil {
+					mutSB.WriteString(fmt.Sprintf("  (could not list: %v)\n", err))
+					return 0, "", nil, nil
+				}
+				if len(page.Items) == 0 {
+					mutSB.WriteString("  (none)\n")
+				}
+				for _, mwc := range page.Items {
+					mutSB.WriteString(fmt.Sprintf("\n  %s:\n", mwc.Name))
+					totalMut += len(mwc.Webhooks)
+					for _, wh := range mwc.Webhooks {
+						failPolicy := "Ignore"
+						if wh.FailurePolicy != nil {
+							failPolicy = string(*wh.FailurePolicy)
+						}
+						timeout := int32(10)
+						if wh.TimeoutSeconds != nil {
+							timeout = *wh.TimeoutSeconds
+						}
+						endpoint := formatWebhookEndpoint(wh.ClientConfig.Service, wh.ClientConfig.URL)
+						mutSB.WriteString(fmt.Sprintf("    Webhook: %s\n", wh.Name))
+						mutSB.WriteString(fmt.Sprintf("      Endpoint: %s\n", endpoint))
+						mutSB.WriteString(fmt.Sprintf("      Failure Policy: %s\n", failPolicy))
+						mutSB.WriteString(fmt.Sprintf("      Timeout: %ds\n", timeout))
+						if len(wh.Rules) > 0 {
+							for _, rule := range wh.Rules {
+								ops := operationStrings(rule.Operations)
+								mutSB.WriteString(fmt.Sprintf("      Rule: %s %s %s\n",
+									strings.Join(ops, ","),
+									strings.Join(rule.APIGroups, ","),
+									strings.Join(rule.Resources, ",")))
+							}
+						}
+						if failPolicy == "Fail" {
+							mutSB.WriteString(fmt.Sprintf("      %s\n", findings.Format("KD-SYS-006", "WARNING", "failurePolicy=Fail — webhook outage will block matching API requests")))
 						}
 					}
-					if failPolicy == "Fail" {
-						sb.WriteString(fmt.Sprintf("      %s\n", util.FormatFinding("WARNING", "failurePolicy=Fail — webhook outage will block matching API requests")))
+				}
+				return len(page.Items), page.Continue, page.Remaining, nil
+			}},
+			{name: "validating", list: func(opts metav1.ListOptions) (int, string, *int64, error) {
+				valSB.Reset()
+				page, err := client.ListValidatingWebhookConfigurationsPage(ctx, opts)
+				if err != nil {
+					valSB.WriteString(fmt.Sprintf("  (could not list: %v)\n", err))
+					return 0, "", nil, nil
+				}
+				if len(page.Items) == 0 {
+					valSB.WriteString("  (none)\n")
+				}
+				for _, vwc := range page.Items {
+					valSB.WriteString(fmt.Sprintf("\n  %s:\n", vwc.Name))
+					totalVal += len(vwc.Webhooks)
+					for _, wh := range vwc.Webhooks {
+						failPolicy := "Ignore"
+						if wh.FailurePolicy != nil {
+							failPolicy = string(*wh.FailurePolicy)
+						}
+						timeout := int32(10)
+						if wh.TimeoutSeconds != nil {
+							timeout = *wh.TimeoutSeconds
+						}
+						endpoint := formatWebhookEndpoint(wh.ClientConfig.Service, wh.ClientConfig.URL)
+						valSB.WriteString(fmt.Sprintf("    Webhook: %s\n", wh.Name))
+						valSB.WriteString(fmt.Sprintf("      Endpoint: %s\n", endpoint))
+						valSB.WriteString(fmt.Sprintf("      Failure Policy: %s\n", failPolicy))
+						valSB.WriteString(fmt.Sprintf("      Timeout: %ds\n", timeout))
+						if len(wh.Rules) > 0 {
+							for _, rule := range wh.Rules {
+								ops := operationStrings(rule.Operations)
+								valSB.WriteString(fmt.Sprintf("      Rule: %s %s %s\n",
+									strings.Join(ops, ","),
+									strings.Join(rule.APIGroups, ","),
+									strings.Join(rule.Resources, ",")))
+							}
+						}
+						if failPolicy == "Fail" {
+							valSB.WriteString(fmt.Sprintf("      %s\n", findings.Format("KD-SYS-006", "WARNING", "failurePolicy=Fail — webhook outage will block matching API requests")))
+						}
 					}
 				}
-			}
+				return len(page.Items), page.Continue, page.Remaining, nil
+			}},
+		})
+		if err != nil {
+			return util.ErrorResult("%v (list again without continue_token)", err), nil, nil
 		}
 
-		// Validating webhooks
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Webhook Configurations"))
+		sb.WriteString("\n\n")
+		sb.WriteString(util.FormatSubHeader("Mutating Webhooks"))
+		sb.WriteString("\n")
+		sb.WriteString(mutSB.String())
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Validating Webhooks"))
 		sb.WriteString("\n")
-		validating, err := client.ListValidatingWebhookConfigurations(ctx)
-		if err != nil {
-			sb.WriteString(fmt.Sprintf("  (could not list: %v)\n", err))
-		} else if len(validating) == 0 {
-			sb.WriteString("  (none)\n")
-		} else {
-			for _, vwc := range validating {
-				sb.WriteString(fmt.Sprintf("\n  %s:\n", vwc.Name))
-				for _, wh := range vwc.Webhooks {
-					failPolicy := "Ignore"
-					if wh.FailurePolicy != nil {
-						failPolicy = string(*wh.FailurePolicy)
-					}
-					timeout := int32(10)
-					if wh.TimeoutSeconds != nil {
-						timeout = *wh.TimeoutSeconds
-					}
-					endpoint := formatWebhookEndpoint(wh.ClientConfig.Service, wh.ClientConfig.URL)
-					sb.WriteString(fmt.Sprintf("    Webhook: %s\n", wh.Name))
-					sb.WriteString(fmt.Sprintf("      Endpoint: %s\n", endpoint))
-					sb.WriteString(fmt.Sprintf("      Failure Policy: %s\n", failPolicy))
-					sb.WriteString(fmt.Sprintf("      Timeout: %ds\n", timeout))
-					if len(wh.Rules) > 0 {
-						for _, rule := range wh.Rules {
-							ops := operationStrings(rule.Operations)
-							sb.WriteString(fmt.Sprintf("      Rule: %s %s %s\n",
-								strings.Join(ops, ","),
-								strings.Join(rule.APIGroups, ","),
-								strings.Join(rule.Resources, ",")))
-						}
-					}
-					if failPolicy == "Fail" {
-						sb.WriteString(fmt.Sprintf("      %s\n", util.FormatFinding("WARNING", "failurePolicy=Fail — webhook outage will block matching API requests")))
-					}
-				}
-			}
-		}
+		sb.WriteString(valSB.String())
 
-		totalMut := 0
-		for _, m := range mutating {
-			totalMut += len(m.Webhooks)
-		}
-		totalVal := 0
-		for _, v := range validating {
-			totalVal += len(v.Webhooks)
-		}
 		sb.WriteString(fmt.Sprintf("\nTotal: %d mutating, %d validating webhooks\n", totalMut, totalVal))
+		sb.WriteString(pageFooter("list_webhook_configs", next, remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
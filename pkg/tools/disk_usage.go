@@ -0,0 +1,497 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// diskStatsMaxNodes caps the kubelet stats summaries fetched per call.
+	diskStatsMaxNodes = 50
+	// diskStatsParallelism is how many stats summaries are fetched at once.
+	diskStatsParallelism = 8
+	// diskHogBytes is the local disk use at which a pod counts as a hog.
+	diskHogBytes = 1 << 30
+	// nodeFsWarnPct and nodeFsCriticalPct are node filesystem use levels;
+	// the kubelet's default hard eviction threshold is nodefs.available<10%.
+	nodeFsWarnPct     = 80
+	nodeFsCriticalPct = 90
+)
+
+type findDiskHogsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces)"`
+	Node           string `json:"node,omitempty" jsonschema:"Only look at pods on this node"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"Maximum pods to list (default 20)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+func registerDiskHogTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "find_disk_hogs",
+		Description: "Find pods filling node disks: pods with disk-backed emptyDir volumes, their emptyDir and total ephemeral-storage use " +
+			"(from the kubelet stats summary), whether a sizeLimit or ephemeral-storage limit bounds them, and which sit on nodes reporting DiskPressure. " +
+			"Also lists pods evicted for ephemeral storage or node disk pressure. Reading usage needs get on nodes/proxy.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input findDiskHogsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+		limit := input.Limit
+		if limit <= 0 {
+			limit = 20
+		}
+
+		opts := metav1.ListOptions{}
+		if input.Node != "" {
+			opts.FieldSelector = "spec.nodeName=" + input.Node
+		}
+		pods, err := client.ListPods(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		var gaps dataGaps
+		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+		gaps.record("Nodes", err)
+		events, err := client.ListEvents(ctx, ns, metav1.ListOptions{})
+		gaps.record("Events", err)
+
+		pressure := diskPressureNodes(nodes)
+		stats, statsErr := fetchNodeStats(ctx, client, diskStatsNodes(pods, pressure, input.Node))
+		gaps.record("Kubelet stats summary", statsErr)
+
+		hogs := findDiskHogs(pods, pressure, stats)
+		evictions := diskEvictions(pods, events)
+
+		var sb strings.Builder
+		scope := displayNS(input.Namespace)
+		if input.Node != "" {
+			scope += ", node: " + input.Node
+		}
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Disk Hogs (scope: %s)", scope)))
+		sb.WriteString("\n\n")
+		sb.WriteString(util.FormatKeyValue("Pods", fmt.Sprintf("%d", len(pods))) + "\n")
+		sb.WriteString(util.FormatKeyValue("Pods with emptyDir on disk", fmt.Sprintf("%d", len(hogs))) + "\n")
+		sb.WriteString(util.FormatKeyValue("Nodes with DiskPressure", valueOrNone(strings.Join(sortedKeys(pressure), ", "))) + "\n")
+		sb.WriteString(util.FormatKeyValue("Nodes measured", fmt.Sprintf("%d", len(stats))) + "\n")
+		if len(gaps) > 0 {
+			sb.WriteString("\n" + gaps.assessment())
+		}
+
+		if len(hogs) > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("Local Disk Use by Pod"))
+			sb.WriteString("\n")
+			rows := make([][]string, 0, min(len(hogs), limit))
+			for _, h := range hogs[:min(len(hogs), limit)] {
+				rows = append(rows, h.row())
+			}
+			sb.WriteString(util.FormatTable([]string{"POD", "NODE", "EMPTYDIR (SIZE LIMIT)", "EMPTYDIR USED", "EPHEMERAL USED", "EPHEMERAL LIMIT", "% NODEFS"}, rows))
+			if len(hogs) > limit {
+				sb.WriteString(fmt.Sprintf("  ... and %d more (raise limit to see them)\n", len(hogs)-limit))
+			}
+		}
+		if len(evictions) > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("Disk Evictions"))
+			sb.WriteString("\n")
+			rows := make([][]string, 0, len(evictions))
+			for _, e := range evictions {
+				rows = append(rows, []string{e.Namespace + "/" + e.Pod, valueOrNone(e.Node), e.Source, truncateName(e.Message, 100)})
+			}
+			sb.WriteString(util.FormatTable([]string{"POD", "NODE", "SOURCE", "MESSAGE"}, rows))
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		findingLines, actions, steps := diskHogFindings(hogs, evictions, pressure)
+		if len(findingLines) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "No pods found filling node disks and no disk evictions"))
+			sb.WriteString("\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString(f)
+			sb.WriteString("\n")
+		}
+		gaps.write(&sb)
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// fetchNodeStats fetches the kubelet stats summary of each node, a few at a
+// time. It returns the summaries it got and the first error.
+func fetchNodeStats(ctx context.Context, client *k8s.ClusterClient, nodes []string) (map[string]*k8s.NodeStatsSummary, error) {
+	stats := make(map[string]*k8s.NodeStatsSummary, len(nodes))
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, diskStatsParallelism)
+	for _, name := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			s, err := client.GetNodeStatsSummary(ctx, name)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("node %s: %w", name, err)
+				}
+				return
+			}
+			stats[name] = s
+		}()
+	}
+	wg.Wait()
+	return stats, firstErr
+}
+
+// diskPressureNodes returns the nodes reporting DiskPressure.
+func diskPressureNodes(nodes []corev1.Node) map[string]bool {
+	out := make(map[string]bool)
+	for i := range nodes {
+		for _, c := range nodes[i].Status.Conditions {
+			if c.Type == corev1.NodeDiskPressure && c.Status == corev1.ConditionTrue {
+				out[nodes[i].Name] = true
+			}
+		}
+	}
+	return out
+}
+
+// diskStatsNodes picks the nodes whose stats summary find_disk_hogs reads:
+// the requested node, else nodes running pods in scope, DiskPressure nodes
+// first, up to diskStatsMaxNodes.
+func diskStatsNodes(pods []corev1.Pod, pressure map[string]bool, node string) []string {
+	if node != "" {
+		return []string{node}
+	}
+	seen := make(map[string]bool)
+	for i := range pods {
+		if n := pods[i].Spec.NodeName; n != "" && !isFinishedPod(&pods[i]) {
+			seen[n] = true
+		}
+	}
+	names := sortedKeys(seen)
+	sort.SliceStable(names, func(i, j int) bool { return pressure[names[i]] && !pressure[names[j]] })
+	return names[:min(len(names), diskStatsMaxNodes)]
+}
+
+// isFinishedPod reports whether a pod has Succeeded or Failed.
+func isFinishedPod(p *corev1.Pod) bool {
+	return p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed
+}
+
+// podEphemeralStorage sums the ephemeral-storage requests and limits of a
+// pod's containers.
+func podEphemeralStorage(p *corev1.Pod) (request, limit int64) {
+	for _, c := range p.Spec.Containers {
+		request += c.Resources.Requests.StorageEphemeral().Value()
+		limit += c.Resources.Limits.StorageEphemeral().Value()
+	}
+	return request, limit
+}
+
+// diskHog is a pod's local disk use.
+type diskHog struct {
+	Namespace, Pod, Node string
+	// EmptyDirs are the pod's disk-backed emptyDir volumes as "name (limit)".
+	EmptyDirs []string
+	// Unbounded is set when an emptyDir has no sizeLimit and the pod has no
+	// ephemeral-storage limit, so nothing evicts it before the node fills.
+	Unbounded      bool
+	Measured       bool
+	EmptyDirUsed   int64
+	EphemeralUsed  int64
+	EphemeralLimit int64
+	NodeFsCapacity int64
+	OnPressureNode bool
+}
+
+// NodeFsPct is the share of the node filesystem the pod uses, 0 when unknown.
+func (h diskHog) NodeFsPct() float64 {
+	if h.NodeFsCapacity == 0 {
+		return 0
+	}
+	return float64(h.EphemeralUsed) / float64(h.NodeFsCapacity) * 100
+}
+
+func (h diskHog) row() []string {
+	emptyDirUsed, ephemeralUsed, pct := "N/A", "N/A", "N/A"
+	if h.Measured {
+		emptyDirUsed, ephemeralUsed = formatBytes(h.EmptyDirUsed), formatBytes(h.EphemeralUsed)
+		if h.NodeFsCapacity > 0 {
+			pct = fmt.Sprintf("%.1f%%", h.NodeFsPct())
+		}
+	}
+	limit := "none"
+	if h.EphemeralLimit > 0 {
+		limit = formatBytes(h.EphemeralLimit)
+	}
+	node := h.Node
+	if h.OnPressureNode {
+		node += " (DiskPressure)"
+	}
+	return []string{h.Namespace + "/" + h.Pod, node, valueOrNone(strings.Join(h.EmptyDirs, ", ")), emptyDirUsed, ephemeralUsed, limit, pct}
+}
+
+// findDiskHogs lists running pods with disk-backed emptyDir volumes, and pods
+// without them whose measured ephemeral storage reaches diskHogBytes, with
+// their usage from stats. Pods on DiskPressure nodes come first, then by use.
+func findDiskHogs(pods []corev1.Pod, pressure map[string]bool, stats map[string]*k8s.NodeStatsSummary) []diskHog {
+	usage := make(map[string]k8s.PodStats)
+	nodeFs := make(map[string]int64)
+	for node, s := range stats {
+		nodeFs[node] = s.Node.Fs.Capacity()
+		for _, ps := range s.Pods {
+			usage[ps.PodRef.Namespace+"/"+ps.PodRef.Name] = ps
+		}
+	}
+
+	var hogs []diskHog
+	for i := range pods {
+		p := &pods[i]
+		if isFinishedPod(p) || p.Spec.NodeName == "" {
+			continue
+		}
+		h := diskHog{Namespace: p.Namespace, Pod: p.Name, Node: p.Spec.NodeName, OnPressureNode: pressure[p.Spec.NodeName]}
+		_, h.EphemeralLimit = podEphemeralStorage(p)
+		emptyDirs := make(map[string]bool)
+		for _, v := range p.Spec.Volumes {
+			if v.EmptyDir == nil || v.EmptyDir.Medium == corev1.StorageMediumMemory {
+				continue
+			}
+			emptyDirs[v.Name] = true
+			size := "no limit"
+			if v.EmptyDir.SizeLimit != nil {
+				size = v.EmptyDir.SizeLimit.String()
+			} else if h.EphemeralLimit == 0 {
+				h.Unbounded = true
+			}
+			h.EmptyDirs = append(h.EmptyDirs, fmt.Sprintf("%s (%s)", v.Name, size))
+		}
+		if ps, ok := usage[p.Namespace+"/"+p.Name]; ok {
+			h.Measured = true
+			h.EphemeralUsed = ps.EphemeralStorage.Used()
+			h.NodeFsCapacity = nodeFs[p.Spec.NodeName]
+			for _, vs := range ps.VolumeStats {
+				if emptyDirs[vs.Name] {
+					h.EmptyDirUsed += vs.Used()
+				}
+			}
+		}
+		if len(h.EmptyDirs) == 0 && h.EphemeralUsed < diskHogBytes {
+			continue
+		}
+		hogs = append(hogs, h)
+	}
+	sort.SliceStable(hogs, func(i, j int) bool {
+		if hogs[i].OnPressureNode != hogs[j].OnPressureNode {
+			return hogs[i].OnPressureNode
+		}
+		if hogs[i].EphemeralUsed != hogs[j].EphemeralUsed {
+			return hogs[i].EphemeralUsed > hogs[j].EphemeralUsed
+		}
+		return hogs[i].Namespace+"/"+hogs[i].Pod < hogs[j].Namespace+"/"+hogs[j].Pod
+	})
+	return hogs
+}
+
+// diskEviction is a pod evicted for local storage or node disk pressure.
+type diskEviction struct {
+	Namespace, Pod, Node string
+	// Source is "pod status" for evicted pods still present, else "event".
+	Source  string
+	Message string
+}
+
+// isDiskEvictionMessage reports whether an eviction message is about local
+// storage rather than memory or PIDs.
+func isDiskEvictionMessage(msg string) bool {
+	lower := strings.ToLower(msg)
+	for _, s := range []string{"ephemeral-storage", "ephemeral local storage", "emptydir", "nodefs", "imagefs", "diskpressure", "disk pressure"} {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// diskEvictions returns pods evicted for local storage, from pod status and
+// from Evicted events, one entry per pod.
+func diskEvictions(pods []corev1.Pod, events []corev1.Event) []diskEviction {
+	var out []diskEviction
+	seen := make(map[string]bool)
+	for i := range pods {
+		p := &pods[i]
+		if p.Status.Reason != "Evicted" || !isDiskEvictionMessage(p.Status.Message) {
+			continue
+		}
+		seen[p.Namespace+"/"+p.Name] = true
+		out = append(out, diskEviction{Namespace: p.Namespace, Pod: p.Name, Node: p.Spec.NodeName, Source: "pod status", Message: p.Status.Message})
+	}
+	for _, e := range events {
+		obj := e.InvolvedObject
+		if e.Reason != "Evicted" || obj.Kind != "Pod" || !isDiskEvictionMessage(e.Message) || seen[obj.Namespace+"/"+obj.Name] {
+			continue
+		}
+		seen[obj.Namespace+"/"+obj.Name] = true
+		out = append(out, diskEviction{Namespace: obj.Namespace, Pod: obj.Name, Node: e.Source.Host, Source: "event", Message: e.Message})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Namespace+"/"+out[i].Pod < out[j].Namespace+"/"+out[j].Pod })
+	return out
+}
+
+// diskHogFindings turns hogs and evictions into findings, actions, and next
+// steps. Unbounded emptyDirs without measured use are summarized in one
+// INFO finding rather than one per pod.
+func diskHogFindings(hogs []diskHog, evictions []diskEviction, pressure map[string]bool) ([]string, []string, []util.NextStep) {
+	var findingLines, actions []string
+	var steps []util.NextStep
+	var idle []string
+	for _, h := range hogs {
+		name := h.Namespace + "/" + h.Pod
+		switch {
+		case h.OnPressureNode && h.EphemeralUsed >= diskHogBytes:
+			findingLines = append(findingLines, findings.Format("KD-STO-004", "CRITICAL", fmt.Sprintf("%s uses %s of local disk (%s in emptyDir) on %s, which reports DiskPressure", name, formatBytes(h.EphemeralUsed), formatBytes(h.EmptyDirUsed), h.Node)))
+			steps = append(steps, nextStep("get_pod_detail", "see which containers write to "+name+"'s volumes", "namespace", h.Namespace, "name", h.Pod))
+		case h.Unbounded && h.EphemeralUsed >= diskHogBytes:
+			findingLines = append(findingLines, findings.Format("KD-STO-004", "WARNING", fmt.Sprintf("%s uses %s of local disk with no emptyDir sizeLimit or ephemeral-storage limit; it can fill %s and trigger DiskPressure", name, formatBytes(h.EphemeralUsed), h.Node)))
+		case h.Unbounded:
+			idle = append(idle, name)
+		}
+		if h.Unbounded {
+			actions = append(actions, "Set emptyDir.sizeLimit and ephemeral-storage requests and limits on pods writing to local disk, so the kubelet evicts the pod instead of the node filling up")
+		}
+	}
+	if len(idle) > 0 {
+		findingLines = append(findingLines, findings.Format("KD-STO-004", "INFO", fmt.Sprintf("%d pod(s) have emptyDir volumes with no sizeLimit or ephemeral-storage limit: %s", len(idle), summarizeNames(idle, 5))))
+	}
+	if len(evictions) > 0 {
+		names := make([]string, 0, len(evictions))
+		for _, e := range evictions {
+			names = append(names, e.Namespace+"/"+e.Pod)
+		}
+		findingLines = append(findingLines, findings.Format("KD-STO-004", "WARNING", fmt.Sprintf("%d pod(s) were evicted for local storage: %s", len(evictions), summarizeNames(names, 5))))
+		actions = append(actions, "Size ephemeral-storage requests from measured use so evicted pods schedule onto nodes with room, and move large scratch data to a PersistentVolume")
+	}
+	for _, node := range sortedKeys(pressure) {
+		steps = append(steps, nextStep("diagnose_node", node+" reports DiskPressure", "name", node))
+	}
+	if len(pressure) > 0 {
+		steps = append(steps, nextStep("analyze_node_capacity", "compare ephemeral-storage requests with node disk use"))
+		actions = append(actions, "Free disk on DiskPressure nodes: remove the largest emptyDir users, prune unused images, and size the OS disk for image and log volume")
+	}
+	return findingLines, actions, steps
+}
+
+// nodeDisk is a node's ephemeral-storage allocation and filesystem use.
+type nodeDisk struct {
+	Name        string
+	Allocatable int64
+	Requests    int64
+	Limits      int64
+	Measured    bool
+	FsUsed      int64
+	FsCapacity  int64
+	ImageUsed   int64
+	ImageCap    int64
+	Pressure    bool
+}
+
+// FsPct is the share of the node filesystem in use.
+func (d nodeDisk) FsPct() float64 {
+	if d.FsCapacity == 0 {
+		return 0
+	}
+	return float64(d.FsUsed) / float64(d.FsCapacity) * 100
+}
+
+// nodeDisks sums ephemeral-storage requests and limits of running pods per
+// node and adds filesystem use from stats where available.
+func nodeDisks(nodes []corev1.Node, pods []corev1.Pod, stats map[string]*k8s.NodeStatsSummary) []nodeDisk {
+	idx := make(map[string]int, len(nodes))
+	disks := make([]nodeDisk, 0, len(nodes))
+	pressure := diskPressureNodes(nodes)
+	for i := range nodes {
+		n := &nodes[i]
+		idx[n.Name] = len(disks)
+		d := nodeDisk{Name: n.Name, Allocatable: n.Status.Allocatable.StorageEphemeral().Value(), Pressure: pressure[n.Name]}
+		if s, ok := stats[n.Name]; ok {
+			d.Measured = true
+			d.FsUsed, d.FsCapacity = s.Node.Fs.Used(), s.Node.Fs.Capacity()
+			d.ImageUsed, d.ImageCap = s.ImageFs().Used(), s.ImageFs().Capacity()
+		}
+		disks = append(disks, d)
+	}
+	for i := range pods {
+		p := &pods[i]
+		j, ok := idx[p.Spec.NodeName]
+		if !ok || isFinishedPod(p) {
+			continue
+		}
+		req, lim := podEphemeralStorage(p)
+		disks[j].Requests += req
+		disks[j].Limits += lim
+	}
+	return disks
+}
+
+// writeEphemeralStorage writes the ephemeral storage section of
+// analyze_node_capacity and returns its findings. statsErr explains missing
+// filesystem use.
+func writeEphemeralStorage(sb *strings.Builder, disks []nodeDisk, statsErr error) []string {
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatSubHeader("Ephemeral Storage"))
+	sb.WriteString("\n")
+	rows := make([][]string, 0, len(disks))
+	var findingLines []string
+	for _, d := range disks {
+		reqPct, fs, image := "N/A", "N/A", "N/A"
+		if d.Allocatable > 0 {
+			reqPct = fmt.Sprintf("%.1f%%", float64(d.Requests)/float64(d.Allocatable)*100)
+		}
+		if d.Measured && d.FsCapacity > 0 {
+			fs = fmt.Sprintf("%s / %s (%.1f%%)", formatBytes(d.FsUsed), formatBytes(d.FsCapacity), d.FsPct())
+		}
+		if d.Measured && d.ImageCap > 0 {
+			image = fmt.Sprintf("%s / %s", formatBytes(d.ImageUsed), formatBytes(d.ImageCap))
+		}
+		rows = append(rows, []string{d.Name, formatBytes(d.Allocatable), formatBytes(d.Requests), reqPct, formatBytes(d.Limits), fs, image})
+
+		switch {
+		case d.Measured && d.FsPct() >= nodeFsCriticalPct:
+			findingLines = append(findingLines, findings.Format("KD-NODE-008", "CRITICAL", fmt.Sprintf("Node '%s' filesystem is %.1f%% full — at the kubelet's default eviction threshold", d.Name, d.FsPct())))
+		case d.Measured && d.FsPct() >= nodeFsWarnPct:
+			findingLines = append(findingLines, findings.Format("KD-NODE-008", "WARNING", fmt.Sprintf("Node '%s' filesystem is %.1f%% full", d.Name, d.FsPct())))
+		}
+		if d.Pressure && d.Requests == 0 {
+			findingLines = append(findingLines, findings.Format("KD-NODE-008", "WARNING", fmt.Sprintf("Node '%s' has DiskPressure but none of its pods request ephemeral-storage, so the scheduler cannot steer disk use away from it; run find_disk_hogs", d.Name)))
+		}
+	}
+	sb.WriteString(util.FormatTable([]string{"NODE", "EPH ALLOC", "EPH REQ", "EPH REQ%", "EPH LIMITS", "NODEFS USED", "IMAGEFS USED"}, rows))
+	if statsErr != nil {
+		sb.WriteString(fmt.Sprintf("  Node filesystem use unavailable for some nodes: %s\n", gapReason(statsErr)))
+	}
+	if len(disks) > diskStatsMaxNodes {
+		sb.WriteString(fmt.Sprintf("  Filesystem use is read for the first %d nodes only.\n", diskStatsMaxNodes))
+	}
+	return findingLines
+}
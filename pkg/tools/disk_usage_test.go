@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+func diskTestStats(node string, used, capacity uint64, pods ...k8s.PodStats) *k8s.NodeStatsSummary {
+	s := &k8s.NodeStatsSummary{Pods: pods}
+	s.Node.NodeName = node
+	s.Node.Fs = &k8s.FsStats{UsedBytes: &used, CapacityBytes: &capacity}
+	return s
+}
+
+func diskTestPodStats(name string, ephemeral uint64, volumes map[string]uint64) k8s.PodStats {
+	ps := k8s.PodStats{EphemeralStorage: &k8s.FsStats{UsedBytes: &ephemeral}}
+	ps.PodRef.Namespace, ps.PodRef.Name = "shop", name
+	for v, used := range volumes {
+		ps.VolumeStats = append(ps.VolumeStats, k8s.VolumeStats{Name: v, FsStats: k8s.FsStats{UsedBytes: &used}})
+	}
+	return ps
+}
+
+func TestFindDiskHogs(t *testing.T) {
+	limit := resource.MustParse("2Gi")
+	cache := corev1.Volume{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+	bounded := corev1.Volume{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &limit}}}
+	shm := corev1.Volume{Name: "shm", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}}}
+	pods := []corev1.Pod{
+		withVolumes(testPod("shop", "quiet", "n1", nil), cache),
+		withVolumes(testPod("shop", "writer", "n2", nil), cache),
+		withVolumes(testPod("shop", "bounded", "n1", nil), bounded),
+		withVolumes(testPod("shop", "memory", "n1", nil), shm),
+		testPod("shop", "logs", "n1", nil),
+	}
+	stats := map[string]*k8s.NodeStatsSummary{
+		"n1": diskTestStats("n1", 40<<30, 100<<30,
+			diskTestPodStats("quiet", 1<<20, map[string]uint64{"cache": 1 << 20}),
+			diskTestPodStats("logs", 3<<30, nil)),
+		"n2": diskTestStats("n2", 92<<30, 100<<30,
+			diskTestPodStats("writer", 20<<30, map[string]uint64{"cache": 19 << 30})),
+	}
+	hogs := findDiskHogs(pods, map[string]bool{"n2": true}, stats)
+
+	var names []string
+	for _, h := range hogs {
+		names = append(names, h.Pod)
+	}
+	if strings.Join(names, ",") != "writer,logs,quiet,bounded" {
+		t.Fatalf("expected pressure node first then by use, memory emptyDirs skipped, got %v", names)
+	}
+	w := hogs[0]
+	if !w.Unbounded || !w.OnPressureNode || w.EmptyDirUsed != 19<<30 || w.NodeFsPct() != 20 {
+		t.Errorf("unexpected writer hog: %+v", w)
+	}
+	if hogs[3].Unbounded || hogs[3].Measured {
+		t.Errorf("expected bounded, unmeasured emptyDir, got %+v", hogs[3])
+	}
+
+	findings, actions, _ := diskHogFindings(hogs, nil, map[string]bool{"n2": true})
+	out := strings.Join(findings, "\n")
+	if !strings.Contains(out, "[CRITICAL] KD-STO-004: shop/writer uses 20.0Gi") {
+		t.Errorf("expected critical finding for writer, got:\n%s", out)
+	}
+	if !strings.Contains(out, "1 pod(s) have emptyDir volumes with no sizeLimit") || !strings.Contains(out, "shop/quiet") {
+		t.Errorf("expected summarized unbounded emptyDir finding, got:\n%s", out)
+	}
+	if len(actions) == 0 {
+		t.Error("expected suggested actions")
+	}
+}
+
+func TestDiskEvictions(t *testing.T) {
+	evicted := testPod("shop", "evicted", "n1", nil)
+	evicted.Status = corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: "The node was low on resource: ephemeral-storage. Container app was using 12Gi, which exceeds its request of 0."}
+	oom := testPod("shop", "memory", "n1", nil)
+	oom.Status = corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: "The node was low on resource: memory."}
+	events := []corev1.Event{
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "evicted"},
+			Reason:         "Evicted",
+			Message:        "The node was low on resource: ephemeral-storage.",
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "gone"},
+			Reason:         "Evicted",
+			Message:        `Usage of EmptyDir volume "cache" exceeds the limit "1Gi".`,
+			Source:         corev1.EventSource{Host: "n2"},
+		},
+	}
+	got := diskEvictions([]corev1.Pod{evicted, oom}, events)
+	if len(got) != 2 || got[0].Pod != "evicted" || got[0].Source != "pod status" || got[1].Pod != "gone" || got[1].Node != "n2" {
+		t.Errorf("expected one entry per disk-evicted pod, got %+v", got)
+	}
+}
+
+func TestWriteEphemeralStorage(t *testing.T) {
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("100Gi")},
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue}},
+		},
+	}
+	pod := testPod("shop", "app", "n1", nil)
+	pod.Spec.Containers[0].Resources.Limits = corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("4Gi")}
+	stats := map[string]*k8s.NodeStatsSummary{"n1": diskTestStats("n1", 95<<30, 100<<30)}
+
+	disks := nodeDisks([]corev1.Node{node}, []corev1.Pod{pod}, stats)
+	if len(disks) != 1 || disks[0].Limits != 4<<30 || disks[0].Requests != 0 || !disks[0].Pressure {
+		t.Fatalf("unexpected node disks: %+v", disks)
+	}
+	var sb strings.Builder
+	findings := writeEphemeralStorage(&sb, disks, errors.New("forbidden"))
+	out := strings.Join(findings, "\n")
+	if !strings.Contains(out, "[CRITICAL] KD-NODE-008: Node 'n1' filesystem is 95.0% full") || !strings.Contains(out, "none of its pods request ephemeral-storage") {
+		t.Errorf("expected full filesystem and missing request findings, got:\n%s", out)
+	}
+	if !strings.Contains(sb.String(), "95.0Gi / 100.0Gi (95.0%)") || !strings.Contains(sb.String(), "unavailable for some nodes") {
+		t.Errorf("unexpected section:\n%s", sb.String())
+	}
+}
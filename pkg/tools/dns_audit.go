@@ -0,0 +1,526 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// coreDNSLogWindow is how far back CoreDNS logs are scanned.
+	coreDNSLogWindow = "1h"
+	// dnsAuditDefaultLogLines is how many CoreDNS log lines per pod
+	// audit_pod_dns_config scans for query logs.
+	dnsAuditDefaultLogLines = 2000
+	// defaultClusterDomain is the cluster DNS domain unless overridden.
+	defaultClusterDomain = "cluster.local"
+	// defaultNdots is the ndots value the kubelet writes for ClusterFirst pods.
+	defaultNdots = 5
+	// dnsSearchNXDomainWarning is the search-path NXDOMAIN count per workload
+	// in the scanned window above which audit_pod_dns_config warns.
+	dnsSearchNXDomainWarning = 50
+	// nodeLocalDNSAddress is the link-local address of NodeLocal DNSCache.
+	nodeLocalDNSAddress = "169.254.20.10"
+)
+
+type auditPodDNSConfigInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace to audit (empty for all namespaces)"`
+	ClusterDomain  string `json:"cluster_domain,omitempty" jsonschema:"Cluster DNS domain (default cluster.local)"`
+	LogLines       int64  `json:"log_lines,omitempty" jsonschema:"CoreDNS log lines per CoreDNS pod to scan for NXDOMAIN answers (default 2000)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// coreDNSQuery is one query from the CoreDNS log plugin.
+type coreDNSQuery struct {
+	Client string
+	Type   string
+	Name   string
+	Rcode  string
+}
+
+// coreDNSQueryLine matches a CoreDNS log plugin line, e.g.
+// [INFO] 10.244.0.5:43210 - 12345 "A IN api.github.com.shop.svc.cluster.local. udp 58 false 512" NXDOMAIN qr,aa,rd 151 0.000123s
+var coreDNSQueryLine = regexp.MustCompile(`\[INFO\] (\S+):\d+ - \d+ "(\S+) IN (\S+) \S+ .*?" (\S+) `)
+
+// dnsHostValue matches a bare host or host:port value.
+var dnsHostValue = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?\.)+[a-zA-Z][a-zA-Z0-9-]*\.?(:\d+)?$`)
+
+// fileExtensions are last labels that mark a value as a file name, not a host.
+var fileExtensions = map[string]bool{
+	"yaml": true, "yml": true, "json": true, "conf": true, "cfg": true, "ini": true, "toml": true, "xml": true,
+	"properties": true, "txt": true, "log": true, "py": true, "js": true, "sh": true, "jar": true, "war": true,
+	"pem": true, "crt": true, "key": true, "html": true, "sock": true, "db": true, "sql": true, "env": true,
+}
+
+// dnsWorkload is the DNS setup of one workload, audited from its first pod.
+type dnsWorkload struct {
+	Namespace   string
+	Name        string
+	Policy      corev1.DNSPolicy
+	HostNetwork bool
+	Nameservers []string
+	Searches    []string
+	Ndots       int
+	// ExternalHosts are external names in env and args that are shorter
+	// than ndots, so every lookup walks the search path first.
+	ExternalHosts []string
+	// SearchNXDomain counts NXDOMAIN answers to search-path expansions of
+	// names the workload's pods queried; SearchNames samples the names.
+	SearchNXDomain int
+	SearchNames    map[string]int
+	Issues         []dnsIssue
+}
+
+// dnsIssue is one DNS configuration problem.
+type dnsIssue struct {
+	Severity string
+	Message  string
+}
+
+func registerDNSAuditTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// audit_pod_dns_config
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "audit_pod_dns_config",
+		Description: "Audit pod DNS configuration per workload: pods overriding dnsPolicy or dnsConfig (Default or None policies that cannot resolve Services, " +
+			"hostNetwork pods without ClusterFirstWithHostNet), ndots settings that send names through the search path, and workloads resolving external " +
+			"names through the cluster search domains. Cross-references NXDOMAIN answers in CoreDNS query logs (when the log plugin is enabled) to the pods " +
+			"that caused them. Use this after check_dns_health reports a high NXDOMAIN count or DNS latency.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input auditPodDNSConfigInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		domain := strings.Trim(input.ClusterDomain, ".")
+		if domain == "" {
+			domain = defaultClusterDomain
+		}
+		logLines := input.LogLines
+		if logLines <= 0 {
+			logLines = dnsAuditDefaultLogLines
+		}
+		ns := util.NamespaceOrAll(input.Namespace)
+
+		pods, err := client.ListPods(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+
+		var gaps dataGaps
+		clusterDNS := []string{nodeLocalDNSAddress}
+		if svc, svcErr := client.GetService(ctx, "kube-system", "kube-dns"); !gaps.record("kube-dns Service", svcErr) {
+			clusterDNS = append(clusterDNS, svc.Spec.ClusterIP)
+		}
+
+		var queries []coreDNSQuery
+		nxdomain, queryLogging := 0, false
+		coreDNSPods, dnsErr := findCoreDNSPods(ctx, client)
+		if !gaps.record("CoreDNS pods", dnsErr) {
+			for i := range coreDNSPods {
+				p := &coreDNSPods[i]
+				logs, logErr := client.GetPodLogs(ctx, p.Namespace, p.Name, coreDNSContainer(p), logLines, false, coreDNSLogWindow)
+				if gaps.record("CoreDNS logs ("+p.Name+")", logErr) {
+					continue
+				}
+				nxdomain += strings.Count(logs, "NXDOMAIN")
+				parsed := parseCoreDNSQueries(logs)
+				queryLogging = queryLogging || len(parsed) > 0
+				queries = append(queries, parsed...)
+			}
+		}
+
+		workloads := auditDNSWorkloads(pods, clusterDNS, domain)
+		attributed := attributeSearchNXDomain(workloads, pods, queries, domain)
+		for _, w := range workloads {
+			w.Issues = append(w.Issues, dnsWorkloadIssues(w)...)
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Pod DNS Config Audit (namespace: %s)", displayNS(ns))))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Workloads", fmt.Sprintf("%d (%d pods)", len(workloads), len(pods))) + "\n")
+		sb.WriteString(util.FormatKeyValue("Cluster DNS", strings.Join(clusterDNS, ", ")) + "\n")
+		if dnsErr == nil {
+			logging := "off: enable the CoreDNS log plugin to attribute NXDOMAIN answers to pods"
+			if queryLogging {
+				logging = fmt.Sprintf("on (%d queries parsed)", len(queries))
+			}
+			sb.WriteString(util.FormatKeyValue("Query logging", logging) + "\n")
+			sb.WriteString(util.FormatKeyValue("NXDOMAIN (last "+coreDNSLogWindow+")", fmt.Sprintf("%d, %d attributed to search-path expansion by audited pods", nxdomain, attributed)) + "\n")
+		}
+
+		var overrides, searchRows, externalRows [][]string
+		for _, w := range workloads {
+			if w.Policy != corev1.DNSClusterFirst || w.HostNetwork || len(w.Nameservers)+len(w.Searches) > 0 || w.Ndots != defaultNdots {
+				overrides = append(overrides, []string{w.Namespace, w.Name, string(w.Policy), valueOrNone(strings.Join(w.Nameservers, ",")),
+					valueOrNone(strings.Join(w.Searches, ",")), fmt.Sprintf("%d", w.Ndots)})
+			}
+			if w.SearchNXDomain > 0 {
+				searchRows = append(searchRows, []string{w.Namespace, w.Name, fmt.Sprintf("%d", w.SearchNXDomain), topSearchNames(w.SearchNames, 3)})
+			}
+			if len(w.ExternalHosts) > 0 {
+				externalRows = append(externalRows, []string{w.Namespace, w.Name, fmt.Sprintf("%d", w.Ndots), joinLimited(w.ExternalHosts, 4)})
+			}
+		}
+		writeDNSTable(&sb, "DNS OVERRIDES", []string{"NAMESPACE", "WORKLOAD", "POLICY", "NAMESERVERS", "SEARCHES", "NDOTS"}, overrides,
+			"All workloads use ClusterFirst with the default resolver settings.")
+		if queryLogging {
+			writeDNSTable(&sb, "SEARCH-PATH NXDOMAIN", []string{"NAMESPACE", "WORKLOAD", "NXDOMAIN", "TOP NAMES"}, searchRows,
+				"No NXDOMAIN answers in the scanned logs came from search-path expansion.")
+		}
+		writeDNSTable(&sb, "EXTERNAL NAMES VIA SEARCH PATH", []string{"NAMESPACE", "WORKLOAD", "NDOTS", "HOSTS"}, externalRows,
+			"No external hosts in env or args resolve through the search path.")
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		for _, w := range workloads {
+			for _, issue := range w.Issues {
+				findingLines = append(findingLines, findings.Format("KD-NET-007", issue.Severity, fmt.Sprintf("%s/%s: %s", w.Namespace, w.Name, issue.Message)))
+			}
+			if w.SearchNXDomain >= dnsSearchNXDomainWarning || len(w.ExternalHosts) > 0 && w.SearchNXDomain > 0 {
+				actions = append(actions, fmt.Sprintf("Lower ndots for %s/%s with dnsConfig.options [{name: ndots, value: \"2\"}], or write external names as FQDNs with a trailing dot (%s.), so they skip the search path.",
+					w.Namespace, w.Name, firstOr(w.ExternalHosts, "api.example.com")))
+			}
+		}
+		if nxdomain > 0 && !queryLogging && dnsErr == nil {
+			actions = append(actions, "Enable the CoreDNS log plugin briefly (add 'log' to the Corefile server block) and rerun to see which pods cause the NXDOMAIN answers.")
+		}
+		if len(findingLines) > 0 {
+			steps = append(steps, nextStep("check_dns_health", "check CoreDNS health and error rates"))
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  No DNS configuration problems found.\n")
+		}
+		for _, f := range dedupe(findingLines) {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+		gaps.write(&sb)
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// findCoreDNSPods finds the CoreDNS pods in kube-system by the kube-dns
+// label, then the coredns app label, and finally the coredns- name prefix.
+func findCoreDNSPods(ctx context.Context, client *k8s.ClusterClient) ([]corev1.Pod, error) {
+	for _, selector := range []string{"k8s-app=kube-dns", "app.kubernetes.io/name=coredns"} {
+		pods, err := client.ListPods(ctx, "kube-system", metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+		if len(pods) > 0 {
+			return pods, nil
+		}
+	}
+	all, err := client.ListPods(ctx, "kube-system", metav1.ListOptions{})
+	if err != nil {
+		return nil, nil
+	}
+	var pods []corev1.Pod
+	for _, p := range all {
+		if strings.HasPrefix(p.Name, "coredns-") {
+			pods = append(pods, p)
+		}
+	}
+	return pods, nil
+}
+
+// coreDNSContainer returns the name of the DNS server container in a CoreDNS pod.
+func coreDNSContainer(p *corev1.Pod) string {
+	for _, c := range p.Spec.Containers {
+		if strings.Contains(c.Name, "coredns") || strings.Contains(c.Name, "dns") {
+			return c.Name
+		}
+	}
+	if len(p.Spec.Containers) > 0 {
+		return p.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+// parseCoreDNSQueries extracts the queries logged by the CoreDNS log plugin.
+func parseCoreDNSQueries(logs string) []coreDNSQuery {
+	var out []coreDNSQuery
+	for _, line := range strings.Split(logs, "\n") {
+		m := coreDNSQueryLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		out = append(out, coreDNSQuery{Client: strings.Trim(m[1], "[]"), Type: m[2], Name: strings.ToLower(m[3]), Rcode: m[4]})
+	}
+	return out
+}
+
+// auditDNSWorkloads collects the DNS setup of each workload, keyed by its
+// first pod; hostNetwork pods share the node's address and are audited
+// for their policy only.
+func auditDNSWorkloads(pods []corev1.Pod, clusterDNS []string, domain string) []*dnsWorkload {
+	seen := make(map[string]bool)
+	var out []*dnsWorkload
+	for i := range pods {
+		p := &pods[i]
+		key := p.Namespace + "/" + podWorkloadName(p)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		w := &dnsWorkload{Namespace: p.Namespace, Name: podWorkloadName(p), Policy: p.Spec.DNSPolicy, HostNetwork: p.Spec.HostNetwork, Ndots: defaultNdots}
+		if w.Policy == "" {
+			w.Policy = corev1.DNSClusterFirst
+		}
+		if cfg := p.Spec.DNSConfig; cfg != nil {
+			w.Nameservers = cfg.Nameservers
+			w.Searches = cfg.Searches
+			for _, opt := range cfg.Options {
+				if opt.Name == "ndots" && opt.Value != nil {
+					if n, err := strconv.Atoi(*opt.Value); err == nil {
+						w.Ndots = n
+					}
+				}
+			}
+		}
+		if usesClusterSearch(w) {
+			w.ExternalHosts = externalHosts(p.Spec, domain, w.Ndots)
+		}
+		w.Issues = dnsPolicyIssues(w, clusterDNS)
+		out = append(out, w)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// usesClusterSearch reports whether a workload's resolv.conf carries the
+// cluster search domains, so short names walk them before going upstream.
+func usesClusterSearch(w *dnsWorkload) bool {
+	switch w.Policy {
+	case corev1.DNSClusterFirst:
+		return !w.HostNetwork
+	case corev1.DNSClusterFirstWithHostNet:
+		return true
+	case corev1.DNSNone:
+		return len(w.Searches) > 0
+	}
+	return false
+}
+
+// dnsPolicyIssues flags dnsPolicy and dnsConfig overrides that break or
+// bypass cluster DNS.
+func dnsPolicyIssues(w *dnsWorkload, clusterDNS []string) []dnsIssue {
+	var issues []dnsIssue
+	switch {
+	case w.Policy == corev1.DNSDefault && !w.HostNetwork:
+		issues = append(issues, dnsIssue{"WARNING", "dnsPolicy Default uses the node's resolver, so Service names do not resolve; use ClusterFirst unless the pod only needs external names"})
+	case w.Policy == corev1.DNSClusterFirst && w.HostNetwork:
+		issues = append(issues, dnsIssue{"WARNING", "hostNetwork pod with dnsPolicy ClusterFirst falls back to the node's resolver and cannot resolve Service names; use ClusterFirstWithHostNet"})
+	case w.Policy == corev1.DNSNone:
+		if !sharesAny(w.Nameservers, clusterDNS) {
+			issues = append(issues, dnsIssue{"WARNING", fmt.Sprintf("dnsPolicy None with nameservers %s bypasses cluster DNS, so Service names do not resolve", valueOrNone(strings.Join(w.Nameservers, ", ")))})
+		} else {
+			issues = append(issues, dnsIssue{"INFO", "dnsPolicy None with a hand-written resolv.conf; keep it in sync with the cluster DNS address and search domains"})
+		}
+	}
+	if w.Ndots > defaultNdots {
+		issues = append(issues, dnsIssue{"WARNING", fmt.Sprintf("ndots %d is above the default %d, so even more names walk the search path", w.Ndots, defaultNdots)})
+	}
+	return issues
+}
+
+// dnsWorkloadIssues flags workloads whose external lookups go through the
+// cluster search domains, using the NXDOMAIN counts from CoreDNS when known.
+func dnsWorkloadIssues(w *dnsWorkload) []dnsIssue {
+	var issues []dnsIssue
+	switch {
+	case w.SearchNXDomain >= dnsSearchNXDomainWarning:
+		issues = append(issues, dnsIssue{"WARNING", fmt.Sprintf("%d NXDOMAIN answers from search-path expansion (ndots %d), e.g. %s; every external lookup costs several extra round trips",
+			w.SearchNXDomain, w.Ndots, topSearchNames(w.SearchNames, 1))})
+	case len(w.ExternalHosts) > 0:
+		issues = append(issues, dnsIssue{"INFO", fmt.Sprintf("resolves %d external name(s) (%s) through the cluster search domains first: with ndots %d each lookup tries every search domain (A and AAAA) before the real name",
+			len(w.ExternalHosts), joinLimited(w.ExternalHosts, 3), w.Ndots)})
+	}
+	return issues
+}
+
+// attributeSearchNXDomain counts, per workload, NXDOMAIN answers to names
+// formed by appending one of the pod's search domains to a name that
+// already had a dot (a search-path expansion of an external or
+// cross-namespace name). Clients are matched to pods by IP. It returns the
+// total attributed.
+func attributeSearchNXDomain(workloads []*dnsWorkload, pods []corev1.Pod, queries []coreDNSQuery, domain string) int {
+	byKey := make(map[string]*dnsWorkload, len(workloads))
+	for _, w := range workloads {
+		byKey[w.Namespace+"/"+w.Name] = w
+	}
+	byIP := make(map[string]*corev1.Pod)
+	for i := range pods {
+		if ip := pods[i].Status.PodIP; ip != "" && !pods[i].Spec.HostNetwork {
+			byIP[ip] = &pods[i]
+		}
+	}
+
+	total := 0
+	for _, q := range queries {
+		if q.Rcode != "NXDOMAIN" {
+			continue
+		}
+		p := byIP[q.Client]
+		if p == nil {
+			continue
+		}
+		original, ok := searchExpansion(q.Name, p.Namespace, domain)
+		if !ok {
+			continue
+		}
+		w := byKey[p.Namespace+"/"+podWorkloadName(p)]
+		if w == nil {
+			continue
+		}
+		if w.SearchNames == nil {
+			w.SearchNames = make(map[string]int)
+		}
+		w.SearchNXDomain++
+		w.SearchNames[original]++
+		total++
+	}
+	return total
+}
+
+// searchExpansion reports whether name is a cluster search domain appended
+// to a name that already contained a dot, and returns that original name.
+// "api.github.com.shop.svc.cluster.local." from a pod in shop returns
+// "api.github.com"; "redis.shop.svc.cluster.local." and
+// "redis.cache.svc.cluster.local." have the <service>.<namespace>.svc form
+// of a Service lookup and are not expansions.
+func searchExpansion(name, namespace, domain string) (string, bool) {
+	name = strings.TrimSuffix(name, ".")
+	if original, ok := strings.CutSuffix(name, "."+namespace+".svc."+domain); ok {
+		return original, strings.Contains(original, ".")
+	}
+	if original, ok := strings.CutSuffix(name, ".svc."+domain); ok {
+		return original, strings.Count(original, ".") > 1
+	}
+	if original, ok := strings.CutSuffix(name, "."+domain); ok {
+		return original, strings.Contains(original, ".") && !strings.HasSuffix(original, ".svc")
+	}
+	return "", false
+}
+
+// externalHosts returns the external host names in a pod's env values,
+// command, and args that have fewer dots than ndots and no trailing dot, so
+// every lookup tries the search domains first.
+func externalHosts(spec corev1.PodSpec, domain string, ndots int) []string {
+	hosts := make(map[string]bool)
+	check := func(v string) {
+		host := hostFromValue(v)
+		if host == "" || strings.HasSuffix(host, ".") || strings.Count(host, ".") >= ndots {
+			return
+		}
+		if strings.HasSuffix(host, "."+domain) || strings.HasSuffix(host, ".svc") || strings.Contains(host, ".svc.") {
+			return
+		}
+		hosts[strings.ToLower(host)] = true
+	}
+	for _, c := range allContainers(spec) {
+		for _, e := range c.Env {
+			check(e.Value)
+		}
+		for _, arg := range append(append([]string{}, c.Command...), c.Args...) {
+			if _, v, ok := strings.Cut(arg, "="); ok && strings.HasPrefix(arg, "-") {
+				arg = v
+			}
+			check(arg)
+		}
+	}
+	return sortedKeys(hosts)
+}
+
+// hostFromValue returns the host of a URL or a bare host[:port] value with
+// at least one dot, or "" when the value is neither. File names are skipped.
+func hostFromValue(v string) string {
+	v = strings.TrimSpace(v)
+	if strings.Contains(v, "://") {
+		u, err := url.Parse(v)
+		if err != nil || u.Hostname() == "" {
+			return ""
+		}
+		v = u.Hostname()
+	} else if !dnsHostValue.MatchString(v) {
+		return ""
+	} else if h, _, ok := strings.Cut(v, ":"); ok {
+		v = h
+	}
+	trimmed := strings.TrimSuffix(v, ".")
+	labels := strings.Split(trimmed, ".")
+	last := strings.ToLower(labels[len(labels)-1])
+	if len(labels) < 2 || fileExtensions[last] || last == "local" || last == "localhost" || !dnsHostValue.MatchString(trimmed) {
+		return ""
+	}
+	return v
+}
+
+// topSearchNames renders the most frequent expanded names with their counts.
+func topSearchNames(names map[string]int, n int) string {
+	keys := sortedMapKeys(names)
+	sort.SliceStable(keys, func(i, j int) bool { return names[keys[i]] > names[keys[j]] })
+	parts := make([]string, 0, n)
+	for i, k := range keys {
+		if i == n {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d)", k, names[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeDNSTable writes a titled table, or the empty message when there are no rows.
+func writeDNSTable(sb *strings.Builder, title string, headers []string, rows [][]string, empty string) {
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatSubHeader(title))
+	sb.WriteString("\n")
+	if len(rows) == 0 {
+		sb.WriteString("  " + empty + "\n")
+		return
+	}
+	sb.WriteString(util.FormatTable(headers, rows))
+}
+
+// sharesAny reports whether a and b have a common element.
+func sharesAny(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// firstOr returns the first element of s, or def when s is empty.
+func firstOr(s []string, def string) string {
+	if len(s) == 0 {
+		return def
+	}
+	return s[0]
+}
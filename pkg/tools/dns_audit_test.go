@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseCoreDNSQueries(t *testing.T) {
+	logs := `[INFO] plugin/reload: Running configuration SHA512 = abc
+[INFO] 10.244.0.5:43210 - 12345 "A IN api.github.com.shop.svc.cluster.local. udp 58 false 512" NXDOMAIN qr,aa,rd 151 0.000123s
+[INFO] [fd00::5]:5353 - 7 "AAAA IN redis.shop.svc.cluster.local. udp 46 false 512" NOERROR qr,aa,rd 139 0.0001s`
+	got := parseCoreDNSQueries(logs)
+	if len(got) != 2 {
+		t.Fatalf("parsed %d queries, want 2: %+v", len(got), got)
+	}
+	want := coreDNSQuery{Client: "10.244.0.5", Type: "A", Name: "api.github.com.shop.svc.cluster.local.", Rcode: "NXDOMAIN"}
+	if got[0] != want {
+		t.Errorf("first query = %+v, want %+v", got[0], want)
+	}
+	if got[1].Client != "fd00::5" || got[1].Rcode != "NOERROR" {
+		t.Errorf("IPv6 query = %+v", got[1])
+	}
+}
+
+func TestSearchExpansion(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		ok       bool
+	}{
+		{"api.github.com.shop.svc.cluster.local.", "api.github.com", true},
+		{"api.github.com.svc.cluster.local.", "api.github.com", true},
+		{"github.com.svc.cluster.local.", "", false},
+		{"api.github.com.cluster.local.", "api.github.com", true},
+		{"redis.shop.svc.cluster.local.", "", false},
+		{"redis.cache.svc.cluster.local.", "", false},
+		{"github.com.", "", false},
+	}
+	for _, tt := range tests {
+		original, ok := searchExpansion(tt.name, "shop", "cluster.local")
+		if ok != tt.ok || (ok && original != tt.original) {
+			t.Errorf("searchExpansion(%q) = %q, %v; want %q, %v", tt.name, original, ok, tt.original, tt.ok)
+		}
+	}
+}
+
+func TestHostFromValue(t *testing.T) {
+	tests := map[string]string{
+		"https://api.stripe.com/v1/charges": "api.stripe.com",
+		"db.example.com:5432":               "db.example.com",
+		"postgres://u:p@pg.internal.io/db":  "pg.internal.io",
+		"config.yaml":                       "",
+		"/etc/app/settings.json":            "",
+		"10.0.0.1":                          "",
+		"redis":                             "",
+		"printer.local":                     "",
+		"hello world":                       "",
+	}
+	for in, want := range tests {
+		if got := hostFromValue(in); got != want {
+			t.Errorf("hostFromValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAuditDNSWorkloads(t *testing.T) {
+	two := "2"
+	dnsPod := func(ns, name, ip string, spec corev1.PodSpec) corev1.Pod {
+		p := testPod(ns, name, "", nil)
+		p.Spec, p.Status.PodIP = spec, ip
+		return p
+	}
+	pods := []corev1.Pod{
+		dnsPod("shop", "checkout", "10.244.0.5", corev1.PodSpec{Containers: []corev1.Container{{Name: "app",
+			Env:  []corev1.EnvVar{{Name: "STRIPE_URL", Value: "https://api.stripe.com"}, {Name: "REDIS", Value: "redis.shop.svc.cluster.local:6379"}},
+			Args: []string{"--config=app.yaml", "--upstream=payments.example.com:443"}}}}),
+		dnsPod("shop", "legacy", "10.244.0.6", corev1.PodSpec{DNSPolicy: corev1.DNSDefault, Containers: []corev1.Container{{Name: "app"}}}),
+		dnsPod("kube-system", "agent", "192.168.1.10", corev1.PodSpec{HostNetwork: true, DNSPolicy: corev1.DNSClusterFirst, Containers: []corev1.Container{{Name: "agent"}}}),
+		dnsPod("shop", "custom", "10.244.0.7", corev1.PodSpec{DNSPolicy: corev1.DNSNone,
+			DNSConfig:  &corev1.PodDNSConfig{Nameservers: []string{"8.8.8.8"}},
+			Containers: []corev1.Container{{Name: "app", Env: []corev1.EnvVar{{Name: "API", Value: "api.example.com"}}}}}),
+		dnsPod("shop", "tuned", "10.244.0.8", corev1.PodSpec{
+			DNSConfig:  &corev1.PodDNSConfig{Options: []corev1.PodDNSConfigOption{{Name: "ndots", Value: &two}}},
+			Containers: []corev1.Container{{Name: "app", Env: []corev1.EnvVar{{Name: "API", Value: "https://api.example.com"}}}}}),
+	}
+	workloads := auditDNSWorkloads(pods, []string{nodeLocalDNSAddress, "10.96.0.10"}, "cluster.local")
+	byName := make(map[string]*dnsWorkload)
+	for _, w := range workloads {
+		byName[w.Name] = w
+	}
+
+	checkout := byName["checkout"]
+	if got := strings.Join(checkout.ExternalHosts, ","); got != "api.stripe.com,payments.example.com" {
+		t.Errorf("checkout external hosts = %q", got)
+	}
+	if len(checkout.Issues) != 0 {
+		t.Errorf("checkout has default DNS settings, got policy issues %+v", checkout.Issues)
+	}
+	if len(byName["legacy"].Issues) != 1 || !strings.Contains(byName["legacy"].Issues[0].Message, "dnsPolicy Default") {
+		t.Errorf("legacy issues = %+v", byName["legacy"].Issues)
+	}
+	if len(byName["agent"].Issues) != 1 || !strings.Contains(byName["agent"].Issues[0].Message, "ClusterFirstWithHostNet") {
+		t.Errorf("agent issues = %+v", byName["agent"].Issues)
+	}
+	if custom := byName["custom"]; len(custom.Issues) != 1 || custom.Issues[0].Severity != "WARNING" || len(custom.ExternalHosts) != 0 {
+		t.Errorf("custom without search domains should warn about bypassing cluster DNS only: %+v", custom)
+	}
+	if tuned := byName["tuned"]; tuned.Ndots != 2 || len(tuned.ExternalHosts) != 0 {
+		t.Errorf("ndots 2 should let api.example.com skip the search path: %+v", tuned)
+	}
+
+	var queries []coreDNSQuery
+	for i := 0; i < dnsSearchNXDomainWarning; i++ {
+		queries = append(queries, coreDNSQuery{Client: "10.244.0.5", Type: "A", Name: "api.stripe.com.shop.svc.cluster.local.", Rcode: "NXDOMAIN"})
+	}
+	queries = append(queries,
+		coreDNSQuery{Client: "10.244.0.5", Type: "A", Name: "redis.shop.svc.cluster.local.", Rcode: "NXDOMAIN"},
+		coreDNSQuery{Client: "10.244.0.9", Type: "A", Name: "api.stripe.com.shop.svc.cluster.local.", Rcode: "NXDOMAIN"},
+		coreDNSQuery{Client: "10.244.0.5", Type: "A", Name: "api.stripe.com.", Rcode: "NOERROR"},
+	)
+	if got := attributeSearchNXDomain(workloads, pods, queries, "cluster.local"); got != dnsSearchNXDomainWarning {
+		t.Errorf("attributed %d NXDOMAIN answers, want %d (unknown clients and Service lookups skipped)", got, dnsSearchNXDomainWarning)
+	}
+	issues := dnsWorkloadIssues(checkout)
+	if len(issues) != 1 || issues[0].Severity != "WARNING" || !strings.Contains(issues[0].Message, "api.stripe.com") {
+		t.Errorf("checkout workload issues = %+v", issues)
+	}
+}
@@ -0,0 +1,354 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// eventAnalysisDefaultWindow is how far back analyze_events looks by default.
+	eventAnalysisDefaultWindow = time.Hour
+
+	// eventAnalysisDefaultGroups is the number of event groups shown by default.
+	eventAnalysisDefaultGroups = 20
+
+	// eventAnalysisWideWorkloads is the number of workloads sharing an event group that suggests a common cause.
+	eventAnalysisWideWorkloads = 3
+)
+
+type analyzeEventsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
+	Window         string `json:"window,omitempty" jsonschema:"How far back to analyze, as a Go duration (default 1h)"`
+	EventType      string `json:"event_type,omitempty" jsonschema:"Event type to analyze: Warning (default), Normal, or all"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"Max event groups to show (default 20)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// eventGroup is every event with the same reason and involved object kind.
+type eventGroup struct {
+	Reason      string
+	Kind        string
+	Events      []corev1.Event
+	Occurrences int32
+	Objects     map[string]bool
+	Workloads   map[string]int
+	Messages    map[string]int32 // fingerprinted message -> occurrences
+	Samples     map[string]string
+	LastSeen    time.Time
+	Spikes      []warningSpike
+}
+
+// topMessage returns the most frequent message in the group, un-normalized.
+func (g *eventGroup) topMessage() string {
+	best, bestCount := "", int32(-1)
+	for fp, n := range g.Messages {
+		if n > bestCount || (n == bestCount && fp < best) {
+			best, bestCount = fp, n
+		}
+	}
+	return g.Samples[best]
+}
+
+func registerEventAnalysisTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// analyze_events
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "analyze_events",
+		Description: "Group events by reason and involved object kind, collapse repeats, detect spikes against the baseline " +
+			"within the window, and link each group to the owning workloads (Deployment, StatefulSet, Job, ...). Use this " +
+			"instead of get_events to see which problems dominate and whether they are new.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeEventsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		window := eventAnalysisDefaultWindow
+		if input.Window != "" {
+			d, err := time.ParseDuration(input.Window)
+			if err != nil || d <= 0 {
+				return util.ErrorResult("invalid window %q: use a Go duration such as 1h or 30m", input.Window), nil, nil
+			}
+			window = d
+		}
+		eventType := input.EventType
+		if eventType == "" {
+			eventType = corev1.EventTypeWarning
+		}
+		limit := input.Limit
+		if limit <= 0 {
+			limit = eventAnalysisDefaultGroups
+		}
+		ns := util.NamespaceOrAll(input.Namespace)
+		now := time.Now()
+		since := now.Add(-window)
+
+		opts := metav1.ListOptions{}
+		if !strings.EqualFold(eventType, "all") {
+			opts.FieldSelector = "type=" + eventType
+		}
+		events, err := client.ListEventsMatching(ctx, ns, opts, k8s.EventFilter{Since: since, Limit: 5000})
+		if err != nil {
+			return util.HandleK8sError("listing events", err), nil, nil
+		}
+
+		// Owners for linking pod and ReplicaSet events to workloads; best effort
+		owners := make(map[string]string)
+		if pods, err := client.ListPods(ctx, ns, metav1.ListOptions{}); err == nil {
+			for i := range pods {
+				owners["Pod/"+pods[i].Namespace+"/"+pods[i].Name] = podWorkloadName(&pods[i])
+			}
+		}
+		if rsList, err := client.ListReplicaSets(ctx, ns, metav1.ListOptions{}); err == nil {
+			for _, rs := range rsList {
+				for _, ref := range rs.OwnerReferences {
+					if ref.Controller != nil && *ref.Controller {
+						owners["ReplicaSet/"+rs.Namespace+"/"+rs.Name] = ref.Name
+					}
+				}
+			}
+		}
+
+		groups := groupEvents(events, owners)
+		for _, g := range groups {
+			g.Spikes = findWarningSpikes(g.Events, since, now)
+		}
+		overallSpikes := findWarningSpikes(events, since, now)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Event Analysis (namespace: %s, type: %s, last %s)", displayNS(input.Namespace), eventType, window)))
+		sb.WriteString("\n\n")
+
+		if len(events) == 0 {
+			sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("No %s events in the last %s", eventType, window)))
+			sb.WriteString("\n")
+			return util.WithNextSteps(util.SuccessResult(sb.String()), nil), nil, nil
+		}
+
+		var occurrences int32
+		for _, g := range groups {
+			occurrences += g.Occurrences
+		}
+		sb.WriteString(fmt.Sprintf("%d event(s) (%d occurrences including repeats) collapse into %d group(s).\n\n", len(events), occurrences, len(groups)))
+
+		shown := groups
+		if len(shown) > limit {
+			shown = shown[:limit]
+		}
+		headers := []string{"#", "REASON", "KIND", "OBJECTS", "OCCURRENCES", "WORKLOADS", "TREND", "LAST SEEN"}
+		rows := make([][]string, 0, len(shown))
+		for i, g := range shown {
+			trend := "steady"
+			if len(g.Spikes) > 0 {
+				trend = "SPIKE"
+			}
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", i+1),
+				g.Reason,
+				g.Kind,
+				fmt.Sprintf("%d", len(g.Objects)),
+				fmt.Sprintf("%d", g.Occurrences),
+				truncateName(formatCounts(g.Workloads), 50),
+				trend,
+				util.FormatAge(g.LastSeen),
+			})
+		}
+		sb.WriteString(util.FormatTable(headers, rows))
+		if len(groups) > limit {
+			sb.WriteString(fmt.Sprintf("... and %d more group(s)\n", len(groups)-limit))
+		}
+
+		for i, g := range shown {
+			if i >= 5 {
+				break
+			}
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader(fmt.Sprintf("%d. %s (%s)", i+1, g.Reason, g.Kind)))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatKeyValue("Message", truncateName(g.topMessage(), 200)))
+			sb.WriteString("\n")
+			if len(g.Messages) > 1 {
+				sb.WriteString(util.FormatKeyValue("Variants", fmt.Sprintf("%d distinct messages", len(g.Messages))))
+				sb.WriteString("\n")
+			}
+			sb.WriteString(util.FormatKeyValue("Workloads", formatCounts(g.Workloads)))
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		var actions []string
+		var steps []util.NextStep
+		findingCount := 0
+		for _, s := range overallSpikes {
+			sb.WriteString(findings.Format("KD-EVT-002", "CRITICAL", fmt.Sprintf("Event spike: %d new event series in the %s starting %s, well above the baseline",
+				s.Count, rolloutSpikeBucket, s.Start.UTC().Format("15:04"))))
+			sb.WriteString("\n")
+			findingCount++
+			actions = append(actions, fmt.Sprintf("Run correlate_rollouts to check whether a deploy around %s caused the spike", s.Start.UTC().Format("15:04")))
+			if ns != "" {
+				steps = append(steps, nextStep("correlate_rollouts", fmt.Sprintf("Event spike starting %s", s.Start.UTC().Format("15:04")), "namespace", ns))
+			}
+		}
+		for _, g := range shown {
+			if len(g.Spikes) > 0 {
+				sb.WriteString(findings.Format("KD-EVT-002", "WARNING", fmt.Sprintf("%s (%s) is spiking: %d new series in the %s starting %s",
+					g.Reason, g.Kind, g.Spikes[len(g.Spikes)-1].Count, rolloutSpikeBucket, g.Spikes[len(g.Spikes)-1].Start.UTC().Format("15:04"))))
+				sb.WriteString("\n")
+				findingCount++
+			}
+			if len(g.Workloads) >= eventAnalysisWideWorkloads {
+				sb.WriteString(findings.Format("KD-EVT-002", "WARNING", fmt.Sprintf("%s affects %d workloads - look for a shared cause (node, quota, dependency) rather than per-workload fixes",
+					g.Reason, len(g.Workloads))))
+				sb.WriteString("\n")
+				findingCount++
+			}
+			if a := eventReasonAction(g.Reason, ns); a != "" {
+				actions = append(actions, a)
+			}
+			if step, ok := eventReasonStep(g.Reason, ns); ok {
+				steps = append(steps, step)
+			}
+		}
+		if findingCount == 0 {
+			sb.WriteString(util.FormatFinding("OK", "No spikes; events are steady against the baseline"))
+			sb.WriteString("\n")
+		}
+
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// groupEvents groups events by reason and involved object kind, most
+// occurrences first. owners maps Kind/namespace/name to the owning workload.
+func groupEvents(events []corev1.Event, owners map[string]string) []*eventGroup {
+	byKey := make(map[string]*eventGroup)
+	for i := range events {
+		e := &events[i]
+		key := e.Reason + "|" + e.InvolvedObject.Kind
+		g, ok := byKey[key]
+		if !ok {
+			g = &eventGroup{
+				Reason:    e.Reason,
+				Kind:      e.InvolvedObject.Kind,
+				Objects:   make(map[string]bool),
+				Workloads: make(map[string]int),
+				Messages:  make(map[string]int32),
+				Samples:   make(map[string]string),
+			}
+			byKey[key] = g
+		}
+		n := eventOccurrences(e)
+		g.Events = append(g.Events, *e)
+		g.Occurrences += n
+		g.Objects[e.InvolvedObject.Namespace+"/"+e.InvolvedObject.Name] = true
+		g.Workloads[eventWorkload(e, owners)] += int(n)
+		fp := fingerprintLogLine(e.Message)
+		g.Messages[fp] += n
+		if _, ok := g.Samples[fp]; !ok {
+			g.Samples[fp] = e.Message
+		}
+		if t := k8s.EventTime(e); t.After(g.LastSeen) {
+			g.LastSeen = t
+		}
+	}
+
+	out := make([]*eventGroup, 0, len(byKey))
+	for _, g := range byKey {
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Occurrences != out[j].Occurrences {
+			return out[i].Occurrences > out[j].Occurrences
+		}
+		return out[i].Reason+out[i].Kind < out[j].Reason+out[j].Kind
+	})
+	return out
+}
+
+// eventOccurrences returns how many times an event happened, including
+// repeats recorded in its count or series.
+func eventOccurrences(e *corev1.Event) int32 {
+	n := e.Count
+	if e.Series != nil && e.Series.Count > n {
+		n = e.Series.Count
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// eventWorkload returns the workload that owns an event's involved object:
+// pods and ReplicaSets resolve to their controller, other objects to themselves.
+func eventWorkload(e *corev1.Event, owners map[string]string) string {
+	obj := e.InvolvedObject
+	if owner, ok := owners[obj.Kind+"/"+obj.Namespace+"/"+obj.Name]; ok {
+		return ownerPrefix(obj.Namespace) + owner
+	}
+	return ownerPrefix(obj.Namespace) + strings.ToLower(obj.Kind) + "/" + obj.Name
+}
+
+// ownerPrefix returns "namespace/" for namespaced objects.
+func ownerPrefix(namespace string) string {
+	if namespace == "" {
+		return ""
+	}
+	return namespace + "/"
+}
+
+// eventReasonAction suggests a follow-up tool for common event reasons.
+func eventReasonAction(reason, ns string) string {
+	switch reason {
+	case "FailedScheduling":
+		return "FailedScheduling: run analyze_node_capacity and check node selectors, taints, and quotas"
+	case "BackOff", "CrashLoopBackOff":
+		return fmt.Sprintf("BackOff: run cluster_crashloops on namespace %s to group crashing containers by cause", displayNS(ns))
+	case "FailedMount", "FailedAttachVolume":
+		return fmt.Sprintf("%s: run diagnose_storage on namespace %s", reason, displayNS(ns))
+	case "Unhealthy":
+		return fmt.Sprintf("Unhealthy: run analyze_probes on namespace %s to check probe configuration", displayNS(ns))
+	case "FailedCreate":
+		return fmt.Sprintf("FailedCreate: run check_resource_quotas on namespace %s", displayNS(ns))
+	case "Evicted", "EvictionThresholdMet":
+		return "Evictions: run get_node_detail on the affected nodes to check memory and disk pressure"
+	case "Failed", "ErrImagePull":
+		return "Failed: check image names and registry credentials for image pull errors"
+	case "OOMKilling", "SystemOOM":
+		return fmt.Sprintf("OOM: run analyze_resource_usage on namespace %s to compare memory usage with limits", displayNS(ns))
+	}
+	return ""
+}
+
+// eventReasonStep returns the follow-up tool call matching eventReasonAction.
+// Tools that require a namespace are only suggested for a namespaced analysis.
+func eventReasonStep(reason, ns string) (util.NextStep, bool) {
+	why := reason + " events"
+	switch reason {
+	case "FailedScheduling":
+		return nextStep("analyze_node_capacity", why), true
+	case "BackOff", "CrashLoopBackOff":
+		return nextStep("cluster_crashloops", why, "namespace", ns), true
+	case "FailedMount", "FailedAttachVolume":
+		return nextStep("diagnose_storage", why, "namespace", ns), true
+	case "Unhealthy":
+		return nextStep("analyze_probes", why, "namespace", ns), ns != ""
+	case "FailedCreate":
+		return nextStep("check_resource_quotas", why, "namespace", ns), true
+	case "OOMKilling", "SystemOOM":
+		return nextStep("analyze_resource_usage", why, "namespace", ns), ns != ""
+	}
+	return util.NextStep{}, false
+}
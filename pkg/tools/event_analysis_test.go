@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGroupEvents(t *testing.T) {
+	now := metav1.NewTime(time.Now())
+	event := func(name, reason, kind, object, msg string, count int32) corev1.Event {
+		return corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Namespace: "shop", Name: object},
+			Reason:         reason,
+			Message:        msg,
+			Count:          count,
+			Type:           corev1.EventTypeWarning,
+			LastTimestamp:  now,
+		}
+	}
+	events := []corev1.Event{
+		event("e1", "BackOff", "Pod", "cart-abc-1", "Back-off restarting failed container app in pod cart-abc-1", 12),
+		event("e2", "BackOff", "Pod", "cart-abc-2", "Back-off restarting failed container app in pod cart-abc-2", 8),
+		event("e3", "FailedScheduling", "Pod", "web-xyz-1", "0/3 nodes are available", 1),
+	}
+	owners := map[string]string{
+		"Pod/shop/cart-abc-1": "cart",
+		"Pod/shop/cart-abc-2": "cart",
+	}
+
+	groups := groupEvents(events, owners)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	g := groups[0]
+	if g.Reason != "BackOff" || g.Occurrences != 20 || len(g.Objects) != 2 {
+		t.Errorf("expected BackOff group with 20 occurrences on 2 pods first, got %+v", g)
+	}
+	if g.Workloads["shop/cart"] != 20 {
+		t.Errorf("expected BackOff linked to workload shop/cart, got %v", g.Workloads)
+	}
+	if len(g.Messages) != 1 {
+		t.Errorf("expected pod-specific messages to collapse into one variant, got %v", g.Messages)
+	}
+	if w := groups[1].Workloads; w["shop/pod/web-xyz-1"] != 1 {
+		t.Errorf("expected unowned pod to link to itself, got %v", w)
+	}
+}
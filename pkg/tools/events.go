@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
@@ -17,12 +19,19 @@ type getEventsInput struct {
 	InvolvedObject string `json:"involved_object,omitempty" jsonschema:"Filter events by object name"`
 	EventType      string `json:"event_type,omitempty" jsonschema:"Filter by event type: Normal or Warning"`
 	Limit          int    `json:"limit,omitempty" jsonschema:"Max events to return (default 50)"`
+	Reasons        string `json:"reasons,omitempty" jsonschema:"Comma-separated event reasons to include (e.g. FailedMount,BackOff)"`
+	InvolvedKind   string `json:"involved_kind,omitempty" jsonschema:"Filter by involved object kind (e.g. Pod, Node, PersistentVolumeClaim)"`
+	MinCount       int32  `json:"min_count,omitempty" jsonschema:"Only include events that repeated at least this many times"`
+	FollowSeconds  int    `json:"follow_seconds,omitempty" jsonschema:"Watch for new matching events for this many seconds after listing (max 300)"`
 }
 
+// maxEventFollowSeconds caps how long get_events will watch in follow mode.
+const maxEventFollowSeconds = 300
+
 func registerEventTools(server *mcp.Server, client *k8s.ClusterClient) {
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "get_events",
-		Description: "Get Kubernetes events, optionally filtered by namespace, resource name, or event type (Normal/Warning). Events are sorted by most recent first. Use event_type='Warning' to find problems.",
+		Description: "Get Kubernetes events, optionally filtered by namespace, resource name, event type (Normal/Warning), reasons, involved object kind, or minimum repeat count. Events are sorted by most recent first. Use event_type='Warning' to find problems. Set follow_seconds to keep watching for new matching events (e.g. FailedMount in a namespace for 2 minutes).",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input getEventsInput) (*mcp.CallToolResult, any, error) {
 		ns := util.NamespaceOrAll(input.Namespace)
 
@@ -40,7 +49,14 @@ func registerEventTools(server *mcp.Server, client *k8s.ClusterClient) {
 			opts.FieldSelector = strings.Join(selectors, ",")
 		}
 
-		events, err := client.ListEvents(ctx, ns, opts)
+		filter := k8s.EventFilter{Kind: input.InvolvedKind, MinCount: input.MinCount}
+		for _, r := range strings.Split(input.Reasons, ",") {
+			if r = strings.TrimSpace(r); r != "" {
+				filter.Reasons = append(filter.Reasons, r)
+			}
+		}
+
+		events, err := client.ListEventsMatching(ctx, ns, opts, filter)
 		if err != nil {
 			return util.HandleK8sError("listing events", err), nil, nil
 		}
@@ -53,36 +69,63 @@ func registerEventTools(server *mcp.Server, client *k8s.ClusterClient) {
 			events = events[:limit]
 		}
 
-		headers := []string{"TYPE", "REASON", "OBJECT", "MESSAGE", "COUNT", "LAST SEEN"}
-		rows := make([][]string, 0, len(events))
-		for _, e := range events {
-			obj := fmt.Sprintf("%s/%s", strings.ToLower(e.InvolvedObject.Kind), e.InvolvedObject.Name)
-			lastSeen := util.FormatAge(e.LastTimestamp.Time)
-			if e.LastTimestamp.IsZero() {
-				lastSeen = util.FormatAge(e.CreationTimestamp.Time)
-			}
-
-			msg := e.Message
-			if len(msg) > 80 {
-				msg = msg[:77] + "..."
-			}
-
-			rows = append(rows, []string{
-				e.Type,
-				e.Reason,
-				obj,
-				msg,
-				fmt.Sprintf("%d", e.Count),
-				lastSeen,
-			})
-		}
-
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Events (namespace: %s)", displayNS(input.Namespace))))
 		sb.WriteString("\n")
-		sb.WriteString(util.FormatTable(headers, rows))
+		sb.WriteString(formatEventTable(events))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("events", len(events))))
 
+		if input.FollowSeconds > 0 {
+			seconds := input.FollowSeconds
+			if seconds > maxEventFollowSeconds {
+				seconds = maxEventFollowSeconds
+			}
+			followed, err := client.FollowEvents(ctx, ns, opts, filter, time.Duration(seconds)*time.Second)
+			if err != nil {
+				return util.HandleK8sError("watching events", err), nil, nil
+			}
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader(fmt.Sprintf("New Events (followed for %ds)", seconds)))
+			sb.WriteString("\n")
+			if len(followed) == 0 {
+				sb.WriteString("  No new matching events observed.\n")
+			} else {
+				if len(followed) > limit {
+					followed = followed[len(followed)-limit:]
+				}
+				sb.WriteString(formatEventTable(followed))
+				sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("new events", len(followed))))
+			}
+		}
+
 		return util.SuccessResult(sb.String()), nil, nil
 	})
 }
+
+// formatEventTable renders events as a TYPE/REASON/OBJECT/MESSAGE/COUNT/LAST SEEN table.
+func formatEventTable(events []corev1.Event) string {
+	headers := []string{"TYPE", "REASON", "OBJECT", "MESSAGE", "COUNT", "LAST SEEN"}
+	rows := make([][]string, 0, len(events))
+	for _, e := range events {
+		obj := fmt.Sprintf("%s/%s", strings.ToLower(e.InvolvedObject.Kind), e.InvolvedObject.Name)
+		lastSeen := util.FormatAge(e.LastTimestamp.Time)
+		if e.LastTimestamp.IsZero() {
+			lastSeen = util.FormatAge(e.CreationTimestamp.Time)
+		}
+
+		msg := e.Message
+		if len(msg) > 80 {
+			msg = msg[:77] + "..."
+		}
+
+		rows = append(rows, []string{
+			e.Type,
+			e.Reason,
+			obj,
+			msg,
+			fmt.Sprintf("%d", e.Count),
+			lastSeen,
+		})
+	}
+	return util.FormatTable(headers, rows)
+}
@@ -0,0 +1,411 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// execDefaultTimeoutSeconds is the default exec_in_pod command timeout.
+	execDefaultTimeoutSeconds = 10
+
+	// execMaxTimeoutSeconds caps the exec_in_pod command timeout.
+	execMaxTimeoutSeconds = 30
+)
+
+type execInPodInput struct {
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Pod            string `json:"pod" jsonschema:"required,Pod name"`
+	Container      string `json:"container,omitempty" jsonschema:"Container name (default: first container)"`
+	Command        string `json:"command" jsonschema:"required,Command to run. Allowed: env | ls [-la] <path> | cat <config file> | curl [-sSiIvk] [-m N] <http(s) URL>"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Command timeout in seconds (default 10, max 30)"`
+}
+
+// execCatPrefixes are the directories cat may read from, in addition to the
+// container's ConfigMap mount paths.
+var execCatPrefixes = []string{"/etc/", "/config/", "/conf/", "/app/config/", "/usr/local/etc/"}
+
+// execCatDenied are files cat must never read even under an allowed prefix.
+var execCatDenied = []string{"/etc/shadow", "/etc/gshadow", "/etc/sudoers", "/var/run/secrets/", "/run/secrets/"}
+
+// execLsFlags are the flags ls accepts.
+var execLsFlags = map[string]bool{"-l": true, "-a": true, "-la": true, "-al": true, "-lh": true, "-lah": true, "-R": true, "-1": true}
+
+// execCurlFlags are the curl flags that take no value. -L is left out: a
+// redirect would reach hosts the URL check never saw.
+var execCurlFlags = map[string]bool{"-s": true, "-S": true, "-sS": true, "-i": true, "-I": true, "-v": true, "-k": true, "-sI": true, "-sv": true}
+
+// execCurlValueFlags are the curl flags that take a numeric value.
+var execCurlValueFlags = map[string]bool{"-m": true, "--max-time": true, "--connect-timeout": true}
+
+// execMetadataHosts are cloud instance metadata hostnames curl must not reach;
+// they hand out node credentials to anything that can send a GET.
+var execMetadataHosts = map[string]bool{
+	"metadata":                   true,
+	"metadata.google.internal":   true,
+	"metadata.goog":              true,
+	"metadata.azure.com":         true,
+	"instance-data":              true,
+	"instance-data.ec2.internal": true,
+}
+
+// execMetadataNets are the link-local ranges that serve instance metadata
+// (169.254.169.254 on every major cloud, fd00:ec2::254 on AWS IPv6).
+var execMetadataNets = []*net.IPNet{
+	{IP: net.IPv4(169, 254, 0, 0), Mask: net.CIDRMask(16, 32)},
+	{IP: net.ParseIP("fe80::"), Mask: net.CIDRMask(10, 128)},
+	{IP: net.ParseIP("fd00:ec2::"), Mask: net.CIDRMask(64, 128)},
+}
+
+// execNumericHost matches hosts curl reads as an IP in decimal, octal, or hex
+// notation (2852039166, 0xa9fea9fe, 0251.0376.0251.0376).
+var execNumericHost = regexp.MustCompile(`(?i)^(0x[0-9a-f.x]+|[0-9][0-9a-fx.]*)$`)
+
+func registerExecTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// exec_in_pod
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "exec_in_pod",
+		Description: "Run an allowlisted read-only command inside a container and return its output: env (secret values masked), ls, cat of config files (/etc, /config, ConfigMap mounts; never Secret mounts; credential values redacted), or curl of an http(s) URL (never the cloud instance metadata service). No shell, pipes, or write operations. Output is capped at 50KB and commands time out after at most 30s. Only available when the server runs with --enable-exec.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input execInPodInput) (*mcp.CallToolResult, any, error) {
+		if input.Namespace == "" || input.Pod == "" || input.Command == "" {
+			return util.ErrorResult("namespace, pod, and command are required"), nil, nil
+		}
+
+		pod, err := client.GetPod(ctx, input.Namespace, input.Pod)
+		if err != nil {
+			return util.HandleK8sError("getting pod", err), nil, nil
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return util.ErrorResult("pod %s/%s is %s; exec needs a Running pod", input.Namespace, input.Pod, pod.Status.Phase), nil, nil
+		}
+		container, ok := findContainer(pod, input.Container)
+		if !ok {
+			return util.ErrorResult("container %q not found in pod %s", input.Container, input.Pod), nil, nil
+		}
+
+		args := strings.Fields(input.Command)
+		if err := validateExecCommand(args, container, pod.Spec.Volumes); err != nil {
+			return util.ErrorResult("command rejected: %v", err), nil, nil
+		}
+
+		timeout := input.TimeoutSeconds
+		if timeout <= 0 {
+			timeout = execDefaultTimeoutSeconds
+		}
+		if timeout > execMaxTimeoutSeconds {
+			timeout = execMaxTimeoutSeconds
+		}
+		execCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+
+		start := time.Now()
+		res, execErr := client.ExecInPod(execCtx, input.Namespace, input.Pod, container.Name, args)
+		elapsed := time.Since(start)
+		if res == nil {
+			return util.HandleK8sError("executing command", execErr), nil, nil
+		}
+
+		stdout := res.Stdout
+		switch args[0] {
+		case "env":
+			stdout = maskSecretEnv(stdout, secretEnvNames(ctx, client, input.Namespace, container))
+		case "cat":
+			stdout = redactConfigOutput(stdout)
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("exec %s/%s [%s]: %s", input.Namespace, input.Pod, container.Name, strings.Join(args, " "))))
+		sb.WriteString("\n")
+		status := "exit 0"
+		if execErr != nil {
+			status = execErr.Error()
+		}
+		sb.WriteString(util.FormatKeyValue("Status", status))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Duration", elapsed.Round(time.Millisecond).String()))
+		sb.WriteString("\n\n")
+		sb.WriteString(util.FormatSubHeader("stdout"))
+		sb.WriteString("\n")
+		if stdout == "" {
+			sb.WriteString("<empty>\n")
+		} else {
+			sb.WriteString(stdout)
+			if !strings.HasSuffix(stdout, "\n") {
+				sb.WriteString("\n")
+			}
+		}
+		if res.Stderr != "" {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("stderr"))
+			sb.WriteString("\n")
+			sb.WriteString(res.Stderr)
+			if !strings.HasSuffix(res.Stderr, "\n") {
+				sb.WriteString("\n")
+			}
+		}
+		if res.Truncated {
+			sb.WriteString("\n... [output truncated at 50KB]\n")
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// findContainer returns the named container, or the first container when name is empty.
+func findContainer(pod *corev1.Pod, name string) (corev1.Container, bool) {
+	if len(pod.Spec.Containers) == 0 {
+		return corev1.Container{}, false
+	}
+	if name == "" {
+		return pod.Spec.Containers[0], true
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return corev1.Container{}, false
+}
+
+// validateExecCommand checks a command against the exec_in_pod allowlist.
+func validateExecCommand(args []string, container corev1.Container, volumes []corev1.Volume) error {
+	if len(args) == 0 {
+		return fmt.Errorf("empty command")
+	}
+	for _, a := range args {
+		if strings.ContainsAny(a, ";|&`$<>") {
+			return fmt.Errorf("shell metacharacters are not allowed (%q)", a)
+		}
+	}
+
+	switch args[0] {
+	case "env":
+		if len(args) > 1 {
+			return fmt.Errorf("env takes no arguments")
+		}
+	case "ls":
+		for _, a := range args[1:] {
+			if strings.HasPrefix(a, "-") {
+				if !execLsFlags[a] {
+					return fmt.Errorf("ls flag %s is not allowed", a)
+				}
+				continue
+			}
+			if err := checkExecPath(a); err != nil {
+				return err
+			}
+		}
+	case "cat":
+		if len(args) < 2 || len(args) > 6 {
+			return fmt.Errorf("cat takes 1 to 5 file paths")
+		}
+		allowed, denied := execMountPrefixes(container, volumes)
+		for _, a := range args[1:] {
+			if err := checkExecPath(a); err != nil {
+				return err
+			}
+			if !hasPathPrefix(a, append(append([]string{}, execCatPrefixes...), allowed...)) {
+				return fmt.Errorf("cat of %s is not allowed; only config paths (%s or ConfigMap mounts) can be read", a, strings.Join(execCatPrefixes, ", "))
+			}
+			if hasPathPrefix(a, append(append([]string{}, execCatDenied...), denied...)) {
+				return fmt.Errorf("%s is a secret or credential path and cannot be read", a)
+			}
+		}
+	case "curl":
+		urls := 0
+		for i := 1; i < len(args); i++ {
+			a := args[i]
+			switch {
+			case execCurlFlags[a]:
+			case execCurlValueFlags[a]:
+				if i+1 >= len(args) {
+					return fmt.Errorf("%s requires a value", a)
+				}
+				if _, err := strconv.ParseFloat(args[i+1], 64); err != nil {
+					return fmt.Errorf("%s value must be a number", a)
+				}
+				i++
+			case a == "-o":
+				if i+1 >= len(args) || args[i+1] != "/dev/null" {
+					return fmt.Errorf("-o may only write to /dev/null")
+				}
+				i++
+			case strings.HasPrefix(a, "http://") || strings.HasPrefix(a, "https://"):
+				if err := checkCurlURL(a); err != nil {
+					return err
+				}
+				urls++
+			default:
+				return fmt.Errorf("curl argument %s is not allowed (GET requests only)", a)
+			}
+		}
+		if urls != 1 {
+			return fmt.Errorf("curl needs exactly one http(s) URL")
+		}
+	default:
+		return fmt.Errorf("%s is not an allowed command (env, ls, cat, curl)", args[0])
+	}
+	return nil
+}
+
+// checkCurlURL rejects URLs that point at a cloud instance metadata service,
+// by hostname, link-local address, or a numeric IP spelling curl accepts.
+// Names that resolve to a metadata address inside the pod are not caught.
+func checkCurlURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("curl URL %s is not valid", raw)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if execMetadataHosts[host] {
+		return fmt.Errorf("curl to the instance metadata service (%s) is not allowed", host)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		if execNumericHost.MatchString(host) {
+			return fmt.Errorf("curl host %s must be a hostname or a dotted IP address", host)
+		}
+		return nil
+	}
+	for _, n := range execMetadataNets {
+		if n.Contains(ip) {
+			return fmt.Errorf("curl to link-local address %s is not allowed (instance metadata)", host)
+		}
+	}
+	return nil
+}
+
+// redactConfigOutput applies the redaction policy to config files read with
+// cat: values of credential-like keys in key=value, key: value, and JSON
+// lines, and whole PEM private key blocks.
+func redactConfigOutput(out string) string {
+	if !util.RedactionEnabled() {
+		return out
+	}
+	var lines []string
+	inKey := false
+	for _, line := range strings.Split(out, "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		switch {
+		case inKey:
+			inKey = !strings.HasPrefix(trimmed, "-----END ")
+			continue
+		case strings.HasPrefix(trimmed, "-----BEGIN ") && strings.Contains(trimmed, "PRIVATE KEY-----"):
+			inKey = !strings.Contains(trimmed, "-----END ")
+			lines = append(lines, indent+util.Redacted)
+			continue
+		}
+		if sep := strings.IndexAny(trimmed, "=:"); sep > 0 {
+			name := strings.Trim(strings.TrimPrefix(strings.TrimSpace(trimmed[:sep]), "export "), `"' `)
+			value := strings.TrimSpace(trimmed[sep+1:])
+			if value != "" && util.RedactValue(name, value) == util.Redacted {
+				line = indent + trimmed[:sep+1] + " " + util.Redacted
+			}
+		} else if util.IsSensitive("", trimmed) {
+			line = indent + util.Redacted
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// checkExecPath rejects relative paths and path traversal.
+func checkExecPath(p string) error {
+	if !strings.HasPrefix(p, "/") {
+		return fmt.Errorf("path %s must be absolute", p)
+	}
+	if clean := path.Clean(p); strings.Contains(p, "..") || (clean != p && clean+"/" != p) {
+		return fmt.Errorf("path %s must not contain traversal", p)
+	}
+	return nil
+}
+
+// hasPathPrefix reports whether p equals or is inside any of the prefixes.
+func hasPathPrefix(p string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		dir := strings.TrimSuffix(prefix, "/")
+		if p == dir || strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// execMountPrefixes returns the container's ConfigMap mount paths (readable)
+// and Secret mount paths (never readable).
+func execMountPrefixes(container corev1.Container, volumes []corev1.Volume) (allowed, denied []string) {
+	kinds := make(map[string]string)
+	for _, v := range volumes {
+		switch {
+		case v.Secret != nil:
+			kinds[v.Name] = "secret"
+		case v.Projected != nil:
+			kinds[v.Name] = "secret" // may contain secrets or service account tokens
+		case v.ConfigMap != nil:
+			kinds[v.Name] = "configmap"
+		}
+	}
+	for _, m := range container.VolumeMounts {
+		switch kinds[m.Name] {
+		case "configmap":
+			allowed = append(allowed, m.MountPath)
+		case "secret":
+			denied = append(denied, m.MountPath)
+		}
+	}
+	return allowed, denied
+}
+
+// secretEnvNames returns the names of env vars the container sources from Secrets.
+// Secret key names (never values) are read to resolve envFrom references.
+func secretEnvNames(ctx context.Context, client *k8s.ClusterClient, namespace string, container corev1.Container) map[string]bool {
+	names := make(map[string]bool)
+	for _, env := range container.Env {
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+			names[env.Name] = true
+		}
+	}
+	for _, envFrom := range container.EnvFrom {
+		if envFrom.SecretRef == nil {
+			continue
+		}
+		secret, err := client.GetSecret(ctx, namespace, envFrom.SecretRef.Name)
+		if err != nil {
+			continue
+		}
+		for key := range secret.Data {
+			names[envFrom.Prefix+key] = true
+		}
+	}
+	return names
+}
+
+// maskSecretEnv replaces values of secret-sourced variables, and of
+// credential-like variables under the redaction policy, in env output.
+func maskSecretEnv(out string, secretNames map[string]bool) string {
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		name, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		if secretNames[name] || util.IsSensitive(name, value) {
+			lines[i] = name + "=" + util.Redacted
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+// connectivityProbeTimeoutSeconds bounds each in-pod connectivity check.
+const connectivityProbeTimeoutSeconds = 5
+
+// connectivityProbeResult is the outcome of an exec-based connectivity check.
+type connectivityProbeResult struct {
+	Tool        string // curl, wget, nc, or none
+	Reachable   bool   // TCP connection established
+	HTTPStatus  string // HTTP status when the target answered HTTP (curl only)
+	Latency     time.Duration
+	Detail      string
+	LatencyNote string
+}
+
+// buildConnectivityProbeCommand returns a shell command that tests TCP/HTTP
+// reachability of host:port with whichever of curl, wget, or nc exists in the image.
+func buildConnectivityProbeCommand(host string, port int32) []string {
+	target := fmt.Sprintf("%s:%d", host, port)
+	t := connectivityProbeTimeoutSeconds
+	script := fmt.Sprintf(`if command -v curl >/dev/null 2>&1; then curl -s -o /dev/null -m %[2]d -w 'curl %%{http_code} %%{time_connect} %%{time_total}' http://%[1]s/; echo " exit=$?";
+elif command -v wget >/dev/null 2>&1; then wget -q -T %[2]d -O /dev/null http://%[1]s/ >/dev/null 2>&1; echo "wget exit=$?";
+elif command -v nc >/dev/null 2>&1; then nc -z -w %[2]d %[3]s %[4]d; echo "nc exit=$?";
+else echo none; fi`, target, t, host, port)
+	return []string{"sh", "-c", script}
+}
+
+// parseConnectivityProbeOutput interprets the output of buildConnectivityProbeCommand.
+// elapsed is the wall-clock duration of the exec call, used when the tool
+// cannot report its own timing.
+func parseConnectivityProbeOutput(out string, elapsed time.Duration) connectivityProbeResult {
+	fields := strings.Fields(strings.TrimSpace(out))
+	if len(fields) == 0 || fields[0] == "none" {
+		return connectivityProbeResult{Tool: "none", Detail: "no curl, wget, or nc in the source container"}
+	}
+
+	exitCode := -1
+	for _, f := range fields {
+		if strings.HasPrefix(f, "exit=") {
+			exitCode, _ = strconv.Atoi(strings.TrimPrefix(f, "exit="))
+		}
+	}
+
+	r := connectivityProbeResult{Tool: fields[0], Latency: elapsed, LatencyNote: "exec round-trip"}
+	switch fields[0] {
+	case "curl":
+		// curl <code> <time_connect> <time_total> exit=N
+		if len(fields) >= 4 {
+			connect, _ := strconv.ParseFloat(fields[2], 64)
+			total, _ := strconv.ParseFloat(fields[3], 64)
+			r.Reachable = connect > 0
+			if fields[1] != "000" {
+				r.HTTPStatus = fields[1]
+			}
+			if total > 0 {
+				r.Latency = time.Duration(total * float64(time.Second))
+				r.LatencyNote = "measured by curl"
+			}
+		}
+		switch {
+		case r.HTTPStatus != "":
+			r.Detail = fmt.Sprintf("HTTP %s", r.HTTPStatus)
+		case r.Reachable:
+			r.Detail = fmt.Sprintf("TCP connected but no HTTP response (curl exit %d) — port may speak a non-HTTP protocol", exitCode)
+		case exitCode == 28:
+			r.Detail = "connection timed out"
+		case exitCode == 7:
+			r.Detail = "connection refused"
+		default:
+			r.Detail = fmt.Sprintf("curl failed with exit code %d", exitCode)
+		}
+	case "wget":
+		switch exitCode {
+		case 0:
+			r.Reachable, r.Detail = true, "HTTP 2xx/3xx"
+		case 8:
+			r.Reachable, r.Detail = true, "server returned an HTTP error status"
+		case 1:
+			// busybox wget uses 1 for every failure
+			r.Detail = "wget failed (busybox wget cannot distinguish refused, timeout, or HTTP error)"
+		default:
+			r.Detail = fmt.Sprintf("network failure (wget exit %d)", exitCode)
+		}
+	case "nc":
+		r.Reachable = exitCode == 0
+		if r.Reachable {
+			r.Detail = "TCP port open"
+		} else {
+			r.Detail = "TCP connection failed"
+		}
+	default:
+		r.Detail = fmt.Sprintf("unexpected probe output: %s", strings.TrimSpace(out))
+	}
+	return r
+}
+
+// runConnectivityProbe execs a connectivity check from the first container of
+// the source pod to host:port.
+func runConnectivityProbe(ctx context.Context, client *k8s.ClusterClient, source *corev1.Pod, host string, port int32) (connectivityProbeResult, error) {
+	container := source.Spec.Containers[0].Name
+	start := time.Now()
+	res, err := client.ExecInPod(ctx, source.Namespace, source.Name, container, buildConnectivityProbeCommand(host, port))
+	elapsed := time.Since(start)
+	if res == nil || (err != nil && res.Stdout == "") {
+		return connectivityProbeResult{}, err
+	}
+	return parseConnectivityProbeOutput(res.Stdout, elapsed), nil
+}
+
+// selectProbeSourcePod picks a running pod to probe from: the named pod if set,
+// otherwise the first running pod in the namespace that is not a backend of the
+// target, falling back to a backend pod.
+func selectProbeSourcePod(pods []corev1.Pod, name string, backends []corev1.Pod) (*corev1.Pod, error) {
+	isBackend := make(map[string]bool, len(backends))
+	for _, p := range backends {
+		isBackend[p.Name] = true
+	}
+	var fallback *corev1.Pod
+	for i := range pods {
+		p := &pods[i]
+		if name != "" {
+			if p.Name == name {
+				if p.Status.Phase != corev1.PodRunning {
+					return nil, fmt.Errorf("source pod %s is %s, not Running", name, p.Status.Phase)
+				}
+				return p, nil
+			}
+			continue
+		}
+		if p.Status.Phase != corev1.PodRunning || len(p.Spec.Containers) == 0 {
+			continue
+		}
+		if !isBackend[p.Name] {
+			return p, nil
+		}
+		if fallback == nil {
+			fallback = p
+		}
+	}
+	if name != "" {
+		return nil, fmt.Errorf("source pod %s not found", name)
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("no running pod available to probe from")
+	}
+	return fallback, nil
+}
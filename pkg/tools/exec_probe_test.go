@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseConnectivityProbeOutput(t *testing.T) {
+	tests := []struct {
+		name      string
+		out       string
+		reachable bool
+		status    string
+	}{
+		{"curl http ok", "curl 200 0.001 0.004 exit=0", true, "200"},
+		{"curl tcp only", "curl 000 0.001 0.002 exit=52", true, ""},
+		{"curl refused", "curl 000 0.000 0.001 exit=7", false, ""},
+		{"wget server error", "wget exit=8", true, ""},
+		{"nc open", "nc exit=0", true, ""},
+		{"nc closed", "nc exit=1", false, ""},
+		{"no tools", "none", false, ""},
+	}
+	for _, tt := range tests {
+		r := parseConnectivityProbeOutput(tt.out, 10*time.Millisecond)
+		if r.Reachable != tt.reachable || r.HTTPStatus != tt.status {
+			t.Errorf("%s: got reachable=%v status=%q, want %v %q", tt.name, r.Reachable, r.HTTPStatus, tt.reachable, tt.status)
+		}
+	}
+
+	if r := parseConnectivityProbeOutput("curl 200 0.001 0.250 exit=0", time.Second); r.Latency != 250*time.Millisecond {
+		t.Errorf("expected curl-reported latency of 250ms, got %v", r.Latency)
+	}
+}
+
+func TestSelectProbeSourcePod(t *testing.T) {
+	running := func(name string) corev1.Pod {
+		return testPod("", name, "", nil)
+	}
+	backend := running("api-0")
+	client := running("web-0")
+	pods := []corev1.Pod{backend, client}
+
+	p, err := selectProbeSourcePod(pods, "", []corev1.Pod{backend})
+	if err != nil || p.Name != "web-0" {
+		t.Errorf("expected non-backend pod web-0, got %v (err %v)", p, err)
+	}
+
+	p, err = selectProbeSourcePod([]corev1.Pod{backend}, "", []corev1.Pod{backend})
+	if err != nil || p.Name != "api-0" {
+		t.Errorf("expected fallback to backend pod, got %v (err %v)", p, err)
+	}
+
+	if _, err := selectProbeSourcePod(pods, "missing", nil); err == nil {
+		t.Errorf("expected error for unknown source pod")
+	}
+}
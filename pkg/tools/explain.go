@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type explainFindingInput struct {
+	FindingID string `json:"finding_id,omitempty" jsonschema:"Finding rule ID (e.g. KD-POD-001)"`
+	Text      string `json:"text,omitempty" jsonschema:"Raw finding line to match against the rule catalog when no ID is known"`
+}
+
+func registerFindingTools(server *mcp.Server) {
+	// explain_finding
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "explain_finding",
+		Description: "Expand a finding code (e.g. KD-POD-001, printed after the severity of a finding in diagnostic reports) or a raw finding line into an explanation, remediation steps, and reference docs from kube-doctor's rule catalog. Call with no arguments to list all rule IDs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input explainFindingInput) (*mcp.CallToolResult, any, error) {
+		var sb strings.Builder
+
+		if input.FindingID == "" && input.Text == "" {
+			sb.WriteString(util.FormatHeader("Finding Rule Catalog"))
+			sb.WriteString("\n\n")
+			headers := []string{"ID", "SEVERITY", "CATEGORY", "TITLE"}
+			rules := findings.All()
+			rows := make([][]string, 0, len(rules))
+			for _, r := range rules {
+				rows = append(rows, []string{r.ID, r.Severity, r.Category, r.Title})
+			}
+			sb.WriteString(util.FormatTable(headers, rows))
+			sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("rules", len(rules))))
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		var matches []findings.Rule
+		if input.FindingID != "" {
+			r, ok := findings.Lookup(input.FindingID)
+			if !ok {
+				return util.ErrorResult("unknown finding ID %q (call explain_finding with no arguments to list rules)", input.FindingID), nil, nil
+			}
+			matches = append(matches, r)
+		} else {
+			matches = findings.Search(input.Text)
+			if len(matches) == 0 {
+				return util.ErrorResult("no catalog rule matches %q", input.Text), nil, nil
+			}
+			if len(matches) > 3 {
+				matches = matches[:3]
+			}
+		}
+
+		for i, r := range matches {
+			if i > 0 {
+				sb.WriteString("\n\n")
+			}
+			sb.WriteString(util.FormatHeader(fmt.Sprintf("%s: %s", r.ID, r.Title)))
+			sb.WriteString("\n\n")
+			sb.WriteString(util.FormatKeyValue("Severity", r.Severity))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatKeyValue("Category", r.Category))
+			sb.WriteString("\n\n")
+			sb.WriteString(util.FormatSubHeader("Explanation"))
+			sb.WriteString("\n")
+			sb.WriteString(r.Explanation)
+			sb.WriteString("\n\n")
+			sb.WriteString(util.FormatSubHeader("Remediation"))
+			sb.WriteString("\n")
+			sb.WriteString(r.Remediation)
+			sb.WriteString("\n")
+			if len(r.References) > 0 {
+				sb.WriteString("\n")
+				sb.WriteString(util.FormatSubHeader("References"))
+				sb.WriteString("\n")
+				for _, ref := range r.References {
+					sb.WriteString(fmt.Sprintf("  - %s\n", ref))
+				}
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
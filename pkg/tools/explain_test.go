@@ -0,0 +1,31 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+)
+
+// TestRuleIDsInCatalog catches findings emitted under an ID that
+// explain_finding cannot expand.
+func TestRuleIDsInCatalog(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ruleID := regexp.MustCompile(`"(KD-[A-Z]+-\d{3})"`)
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range ruleID.FindAllStringSubmatch(string(src), -1) {
+			if _, ok := findings.Lookup(m[1]); !ok {
+				t.Errorf("%s uses rule %s, which is not in the catalog", file, m[1])
+			}
+		}
+	}
+}
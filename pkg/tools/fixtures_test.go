@@ -0,0 +1,77 @@
+package tools
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testPod returns a Running pod with a single "app" container, scheduled on
+// node unless node is empty.
+func testPod(ns, name, node string, labels map[string]string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: node, Containers: []corev1.Container{{Name: "app"}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+// withRequests sets the CPU and memory requests of the pod's first container.
+// An empty value leaves that resource unset.
+func withRequests(pod corev1.Pod, cpu, mem string) corev1.Pod {
+	requests := corev1.ResourceList{}
+	if cpu != "" {
+		requests[corev1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if mem != "" {
+		requests[corev1.ResourceMemory] = resource.MustParse(mem)
+	}
+	pod.Spec.Containers[0].Resources.Requests = requests
+	return pod
+}
+
+// withVolumes adds volumes to the pod's spec.
+func withVolumes(pod corev1.Pod, volumes ...corev1.Volume) corev1.Pod {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, volumes...)
+	return pod
+}
+
+// withOwner makes the pod controlled by the named kind, such as a ReplicaSet.
+func withOwner(pod corev1.Pod, kind, name string) corev1.Pod {
+	controller := true
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+	return pod
+}
+
+// withReady sets the pod's Ready condition and the readiness of its running
+// first container.
+func withReady(pod corev1.Pod, ready bool) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name: pod.Spec.Containers[0].Name, Ready: ready, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}}
+	return pod
+}
+
+// testNode returns a Ready node with the given allocatable CPU and memory and
+// the default 110 pod slots. Like a real node it carries its
+// kubernetes.io/hostname label in addition to labels.
+func testNode(name, cpu, mem string, labels map[string]string) corev1.Node {
+	nodeLabels := map[string]string{"kubernetes.io/hostname": name}
+	for k, v := range labels {
+		nodeLabels[k] = v
+	}
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(mem), corev1.ResourcePods: resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
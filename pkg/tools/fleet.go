@@ -0,0 +1,316 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// fleetParallelism caps how many clusters fleet_overview checks at once.
+	fleetParallelism = 4
+	// fleetMaxContexts caps how many contexts one fleet_overview call checks.
+	fleetMaxContexts = 30
+	// fleetDefaultTimeout is the per-request timeout for fleet checks, lower
+	// than the server default so one unreachable cluster does not stall the call.
+	fleetDefaultTimeout = 15
+)
+
+type fleetOverviewInput struct {
+	Contexts       []string `json:"contexts,omitempty" jsonschema:"Kubeconfig contexts to check (default: every context in the kubeconfig, up to 30)"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 15, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// fleetHealth is the condensed health of one cluster.
+type fleetHealth struct {
+	Context       string
+	Version       string
+	Nodes         int
+	ReadyNodes    int
+	Pods          int
+	UnhealthyPods int
+	Services      int
+	DeadServices  []string // namespace/name of selector Services with no ready pod
+	WorstNS       string   // namespace with the most unhealthy pods
+	Err           error
+	Duration      time.Duration
+}
+
+// status rates a cluster: UNREACHABLE, CRITICAL for NotReady nodes or dead
+// Services, WARNING for unhealthy pods, otherwise OK.
+func (h fleetHealth) status() string {
+	switch {
+	case h.Err != nil:
+		return "UNREACHABLE"
+	case h.ReadyNodes < h.Nodes || len(h.DeadServices) > 0:
+		return "CRITICAL"
+	case h.UnhealthyPods > 0:
+		return "WARNING"
+	}
+	return "OK"
+}
+
+func registerFleetTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "fleet_overview",
+		Description: "Run a condensed health check against several kubeconfig contexts at once (default: all of them) and compare the clusters side by side: " +
+			"API version, nodes ready, unhealthy pods, and dead Services (selector matches no ready pod). Use it to triage a fleet and pick the cluster to " +
+			"investigate; unreachable clusters are reported, not fatal.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input fleetOverviewInput) (*mcp.CallToolResult, any, error) {
+		timeout := input.TimeoutSeconds
+		if timeout <= 0 {
+			timeout = fleetDefaultTimeout
+		}
+		ctx = util.WithTimeoutSeconds(ctx, timeout)
+
+		available, current, err := client.AvailableContexts()
+		if err != nil {
+			return util.HandleK8sError("listing contexts", err), nil, nil
+		}
+		contexts := input.Contexts
+		if len(contexts) == 0 {
+			contexts = available
+		}
+		var unknown []string
+		for _, name := range contexts {
+			if !slices.Contains(available, name) {
+				unknown = append(unknown, name)
+			}
+		}
+		if len(unknown) > 0 {
+			return util.ErrorResult("unknown context(s) %s; run list_contexts to see the available ones", strings.Join(unknown, ", ")), nil, nil
+		}
+		if len(contexts) == 0 {
+			return util.ErrorResult("the kubeconfig has no contexts"), nil, nil
+		}
+		truncated := 0
+		if len(contexts) > fleetMaxContexts {
+			truncated = len(contexts) - fleetMaxContexts
+			contexts = contexts[:fleetMaxContexts]
+		}
+
+		// The server's own context reuses its client.
+		own := client.ContextName
+		if own == "" {
+			own = current
+		}
+		progress := util.NewProgress(req)
+		results := make([]fleetHealth, len(contexts))
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		done := 0
+		sem := make(chan struct{}, fleetParallelism)
+		for i, name := range contexts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				start := time.Now()
+				var h fleetHealth
+				c := client
+				var err error
+				if name != own {
+					c, err = client.ForContext(name)
+				}
+				if err != nil {
+					h.Err = err
+				} else {
+					h = checkFleetCluster(ctx, c)
+				}
+				h.Context, h.Duration = name, time.Since(start)
+				results[i] = h
+
+				mu.Lock()
+				done++
+				progress.Report(ctx, float64(done), float64(len(contexts)), fmt.Sprintf("checked %s (%d/%d)", name, done, len(contexts)))
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Fleet Overview"))
+		sb.WriteString("\n")
+		healthy := 0
+		for _, h := range results {
+			if h.status() == "OK" {
+				healthy++
+			}
+		}
+		sb.WriteString(util.FormatKeyValue("Clusters", fmt.Sprintf("%d checked, %d healthy", len(results), healthy)) + "\n")
+		if truncated > 0 {
+			sb.WriteString(fmt.Sprintf("  (%d more context(s) not checked; pass contexts to choose which)\n", truncated))
+		}
+		sb.WriteString("\n")
+
+		var rows [][]string
+		for _, h := range results {
+			name := h.Context
+			if name == own {
+				name += " (current)"
+			}
+			if h.Err != nil {
+				rows = append(rows, []string{name, h.status(), "-", "-", "-", "-", truncateName(h.Err.Error(), 60)})
+				continue
+			}
+			rows = append(rows, []string{
+				name,
+				h.status(),
+				h.Version,
+				fmt.Sprintf("%d/%d", h.ReadyNodes, h.Nodes),
+				fmt.Sprintf("%d/%d", h.UnhealthyPods, h.Pods),
+				fmt.Sprintf("%d/%d", len(h.DeadServices), h.Services),
+				h.Duration.Round(100 * time.Millisecond).String(),
+			})
+		}
+		sb.WriteString(util.FormatTable([]string{"CONTEXT", "STATUS", "VERSION", "NODES READY", "UNHEALTHY PODS", "DEAD SERVICES", "TOOK"}, rows))
+
+		sb.WriteString("\nFINDINGS:\n")
+		var actions []string
+		for _, h := range results {
+			if f := fleetFinding(h); f != "" {
+				sb.WriteString(f)
+				sb.WriteString("\n")
+				if h.Err == nil && h.Context != own {
+					actions = append(actions, fmt.Sprintf("Investigate %s by restarting kube-doctor with --context %s, then run cluster_health_overview", h.Context, h.Context))
+				}
+			}
+		}
+		if healthy == len(results) {
+			sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("All %d clusters have ready nodes, healthy pods, and live Services", len(results))))
+			sb.WriteString("\n")
+		}
+		var steps []util.NextStep
+		for _, h := range results {
+			if h.Context == own && h.status() != "OK" && h.Err == nil {
+				steps = append(steps, nextStep("cluster_health_overview", "the current cluster has problems; see them in detail"))
+			}
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range actions {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// checkFleetCluster runs the condensed health check against one cluster. It
+// stops at the first failing request, since that usually means the cluster is
+// unreachable or the credentials are rejected.
+func checkFleetCluster(ctx context.Context, client *k8s.ClusterClient) fleetHealth {
+	if err := client.Ping(ctx); err != nil {
+		return fleetHealth{Err: err}
+	}
+	version := ""
+	if v, err := client.Clientset.Discovery().ServerVersion(); err == nil {
+		version = v.GitVersion
+	}
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+	if err != nil && !util.IsScopeError(err) {
+		return fleetHealth{Err: err}
+	}
+	pods, err := client.ListPods(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		return fleetHealth{Err: err}
+	}
+	services, err := client.ListServices(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		return fleetHealth{Err: err}
+	}
+	h := summarizeFleetCluster(nodes, pods, services)
+	h.Version = version
+	return h
+}
+
+// summarizeFleetCluster condenses a cluster's nodes, pods, and Services into
+// the fleet comparison. A Service is dead when its selector matches no
+// running, ready pod.
+func summarizeFleetCluster(nodes []corev1.Node, pods []corev1.Pod, services []corev1.Service) fleetHealth {
+	h := fleetHealth{Nodes: len(nodes), Pods: len(pods)}
+	for i := range nodes {
+		if nodeStatus(&nodes[i]) == "Ready" {
+			h.ReadyNodes++
+		}
+	}
+
+	unhealthyByNS := make(map[string]int)
+	readyByNS := make(map[string][]labels.Set)
+	for i := range pods {
+		p := &pods[i]
+		if p.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+		if isPodHealthy(p) {
+			readyByNS[p.Namespace] = append(readyByNS[p.Namespace], labels.Set(p.Labels))
+		} else {
+			h.UnhealthyPods++
+			unhealthyByNS[p.Namespace]++
+		}
+	}
+	for ns, n := range unhealthyByNS {
+		if n > unhealthyByNS[h.WorstNS] || (n == unhealthyByNS[h.WorstNS] && ns < h.WorstNS) {
+			h.WorstNS = ns
+		}
+	}
+
+	for i := range services {
+		svc := &services[i]
+		if svc.Spec.Type == corev1.ServiceTypeExternalName || len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		h.Services++
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		live := false
+		for _, set := range readyByNS[svc.Namespace] {
+			if selector.Matches(set) {
+				live = true
+				break
+			}
+		}
+		if !live {
+			h.DeadServices = append(h.DeadServices, svc.Namespace+"/"+svc.Name)
+		}
+	}
+	return h
+}
+
+// fleetFinding describes a cluster that is not OK, "" for a healthy one.
+func fleetFinding(h fleetHealth) string {
+	switch h.status() {
+	case "UNREACHABLE":
+		return findings.Format("KD-SYS-010", "CRITICAL", fmt.Sprintf("%s: unreachable: %v", h.Context, h.Err))
+	case "OK":
+		return ""
+	}
+	var problems []string
+	if h.ReadyNodes < h.Nodes {
+		problems = append(problems, fmt.Sprintf("%d of %d node(s) not Ready", h.Nodes-h.ReadyNodes, h.Nodes))
+	}
+	if len(h.DeadServices) > 0 {
+		problems = append(problems, fmt.Sprintf("%d dead Service(s) (%s)", len(h.DeadServices), summarizeNames(h.DeadServices, 3)))
+	}
+	if h.UnhealthyPods > 0 {
+		problems = append(problems, fmt.Sprintf("%d unhealthy pod(s), most in %s", h.UnhealthyPods, h.WorstNS))
+	}
+	severity := "WARNING"
+	if h.status() == "CRITICAL" {
+		severity = "CRITICAL"
+	}
+	return findings.Format("KD-SYS-010", severity, fmt.Sprintf("%s: %s", h.Context, strings.Join(problems, "; ")))
+}
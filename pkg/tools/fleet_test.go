@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSummarizeFleetCluster(t *testing.T) {
+	nodes := []corev1.Node{
+		{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}}},
+		{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}}}},
+	}
+	running := corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{Ready: true}}}
+	pending := corev1.PodStatus{Phase: corev1.PodPending}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: map[string]string{"app": "web"}}, Status: running},
+		{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", Labels: map[string]string{"app": "api"}}, Status: pending},
+		{ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "batch"}, Status: corev1.PodStatus{Phase: corev1.PodSucceeded}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "batch"}, Status: pending},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "other", Labels: map[string]string{"app": "web"}}, Status: running},
+	}
+	services := []corev1.Service{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "web"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}, Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "api"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "staging"}, Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "web"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"}, Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "db.example.com"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "headless", Namespace: "shop"}},
+	}
+
+	h := summarizeFleetCluster(nodes, pods, services)
+	if h.Nodes != 2 || h.ReadyNodes != 1 {
+		t.Errorf("nodes = %d/%d, want 1/2 ready", h.ReadyNodes, h.Nodes)
+	}
+	if h.UnhealthyPods != 2 {
+		t.Errorf("UnhealthyPods = %d, want 2 (Succeeded pods are not unhealthy)", h.UnhealthyPods)
+	}
+	if h.WorstNS != "batch" {
+		t.Errorf("WorstNS = %q, want batch (ties broken by name)", h.WorstNS)
+	}
+	if h.Services != 3 {
+		t.Errorf("Services = %d, want 3 selector Services", h.Services)
+	}
+	if want := "shop/api,staging/web"; strings.Join(h.DeadServices, ",") != want {
+		t.Errorf("DeadServices = %v, want %s", h.DeadServices, want)
+	}
+}
+
+func TestFleetHealthStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		h    fleetHealth
+		want string
+	}{
+		{"unreachable", fleetHealth{Err: errors.New("dial tcp: timeout")}, "UNREACHABLE"},
+		{"node down", fleetHealth{Nodes: 3, ReadyNodes: 2}, "CRITICAL"},
+		{"dead service", fleetHealth{Nodes: 1, ReadyNodes: 1, DeadServices: []string{"shop/api"}}, "CRITICAL"},
+		{"unhealthy pods", fleetHealth{Nodes: 1, ReadyNodes: 1, UnhealthyPods: 2}, "WARNING"},
+		{"healthy", fleetHealth{Nodes: 1, ReadyNodes: 1}, "OK"},
+	}
+	for _, tt := range tests {
+		if got := tt.h.status(); got != tt.want {
+			t.Errorf("%s: status() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFleetFinding(t *testing.T) {
+	if got := fleetFinding(fleetHealth{Context: "prod", Nodes: 1, ReadyNodes: 1}); got != "" {
+		t.Errorf("healthy cluster finding = %q, want empty", got)
+	}
+	got := fleetFinding(fleetHealth{Context: "prod", Nodes: 3, ReadyNodes: 2, UnhealthyPods: 4, WorstNS: "shop"})
+	for _, want := range []string{"CRITICAL", "prod", "1 of 3 node(s) not Ready", "4 unhealthy pod(s), most in shop"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("finding %q missing %q", got, want)
+		}
+	}
+	if got := fleetFinding(fleetHealth{Context: "dev", Err: errors.New("connection refused")}); !strings.Contains(got, "unreachable: connection refused") {
+		t.Errorf("unreachable finding = %q", got)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/flux"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
@@ -21,20 +22,28 @@ import (
 // --- input structs ---
 
 type listFluxKustomizationsInput struct {
-	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
+	Namespace     string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type listFluxHelmReleasesInput struct {
-	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
+	Namespace     string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type listFluxSourcesInput struct {
-	Namespace  string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
-	SourceType string `json:"source_type,omitempty" jsonschema:"Filter by source type: git, oci, helm, helmchart, bucket (empty for all)"`
+	Namespace     string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
+	SourceType    string `json:"source_type,omitempty" jsonschema:"Filter by source type: git, oci, helm, helmchart, bucket (empty for all)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type listFluxImagePoliciesInput struct {
-	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
+	Namespace     string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type diagnoseFluxKustomizationInput struct {
@@ -76,10 +85,11 @@ func registerListFluxKustomizations(server *mcp.Server, fluxClient *flux.FluxCli
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listFluxKustomizationsInput) (*mcp.CallToolResult, any, error) {
 		ns := util.NamespaceOrAll(input.Namespace)
 
-		items, err := fluxClient.ListKustomizations(ctx, ns)
+		page, err := fluxClient.ListKustomizationsPage(ctx, ns, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
 		if err != nil {
 			return handleFluxError("listing Flux Kustomizations", err), nil, nil
 		}
+		items := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "SOURCE", "PATH", "STATUS", "REVISION", "SUSPENDED", "AGE"}
 		rows := make([][]string, 0, len(items))
@@ -112,6 +122,7 @@ func registerListFluxKustomizations(server *mcp.Server, fluxClient *flux.FluxCli
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("Kustomizations", len(items))))
+		sb.WriteString(pageFooter("list_flux_kustomizations", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -126,10 +137,11 @@ func registerListFluxHelmReleases(server *mcp.Server, fluxClient *flux.FluxClien
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listFluxHelmReleasesInput) (*mcp.CallToolResult, any, error) {
 		ns := util.NamespaceOrAll(input.Namespace)
 
-		items, err := fluxClient.ListHelmReleases(ctx, ns)
+		page, err := fluxClient.ListHelmReleasesPage(ctx, ns, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
 		if err != nil {
 			return handleFluxError("listing Flux HelmReleases", err), nil, nil
 		}
+		items := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "CHART", "VERSION", "STATUS", "REMEDIATION", "SUSPENDED", "AGE"}
 		rows := make([][]string, 0, len(items))
@@ -159,6 +171,7 @@ func registerListFluxHelmReleases(server *mcp.Server, fluxClient *flux.FluxClien
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("HelmReleases", len(items))))
+		sb.WriteString(pageFooter("list_flux_helm_releases", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -177,89 +190,119 @@ func registerListFluxSources(server *mcp.Server, fluxClient *flux.FluxClient) {
 		rows := make([][]string, 0)
 		filter := strings.ToLower(input.SourceType)
 
-		if filter == "" || filter == "git" {
-			gitRepos, err := fluxClient.ListGitRepositories(ctx, ns)
-			if err == nil {
-				for i := range gitRepos {
-					gr := &gitRepos[i]
-					revision := "<none>"
-					if gr.Status.Artifact != nil {
-						revision = truncateRevision(gr.Status.Artifact.Revision)
+		// Each source kind is one phase of the page; kinds whose CRDs are not
+		// installed list as empty.
+		var phases []pagePhase
+		addSource := func(sourceType string, list func(opts metav1.ListOptions) (int, string, *int64, error)) {
+			if filter == "" || filter == sourceType {
+				phases = append(phases, pagePhase{name: sourceType, list: func(opts metav1.ListOptions) (int, string, *int64, error) {
+					n, next, remaining, err := list(opts)
+					if err != nil {
+						return 0, "", nil, nil
 					}
-					health := flux.GetFluxHealth(gr.Status.Conditions, gr.Generation, gr.Status.ObservedGeneration, gr.Spec.Suspend)
-					rows = append(rows, []string{
-						"GitRepository", gr.Name, gr.Namespace, gr.Spec.URL, revision, string(health), util.FormatAge(gr.CreationTimestamp.Time),
-					})
-				}
+					return n, next, remaining, nil
+				}})
 			}
 		}
 
-		if filter == "" || filter == "oci" {
-			ociRepos, err := fluxClient.ListOCIRepositories(ctx, ns)
-			if err == nil {
-				for i := range ociRepos {
-					or := &ociRepos[i]
-					revision := "<none>"
-					if or.Status.Artifact != nil {
-						revision = truncateRevision(or.Status.Artifact.Revision)
-					}
-					health := flux.GetFluxHealth(or.Status.Conditions, or.Generation, or.Status.ObservedGeneration, or.Spec.Suspend)
-					rows = append(rows, []string{
-						"OCIRepository", or.Name, or.Namespace, or.Spec.URL, revision, string(health), util.FormatAge(or.CreationTimestamp.Time),
-					})
+		addSource("git", func(opts metav1.ListOptions) (int, string, *int64, error) {
+			page, err := fluxClient.ListGitRepositoriesPage(ctx, ns, opts)
+			if err != nil {
+				return 0, "", nil, err
+			}
+			for i := range page.Items {
+				gr := &page.Items[i]
+				revision := "<none>"
+				if gr.Status.Artifact != nil {
+					revision = truncateRevision(gr.Status.Artifact.Revision)
 				}
+				health := flux.GetFluxHealth(gr.Status.Conditions, gr.Generation, gr.Status.ObservedGeneration, gr.Spec.Suspend)
+				rows = append(rows, []string{
+					"GitRepository", gr.Name, gr.Namespace, gr.Spec.URL, revision, string(health), util.FormatAge(gr.CreationTimestamp.Time),
+				})
 			}
-		}
+			return len(page.Items), page.Continue, page.Remaining, nil
+		})
 
-		if filter == "" || filter == "helm" {
-			helmRepos, err := fluxClient.ListHelmRepositories(ctx, ns)
-			if err == nil {
-				for i := range helmRepos {
-					hr := &helmRepos[i]
-					revision := "<none>"
-					if hr.Status.Artifact != nil {
-						revision = truncateRevision(hr.Status.Artifact.Revision)
-					}
-					health := flux.GetFluxHealth(hr.Status.Conditions, hr.Generation, hr.Status.ObservedGeneration, hr.Spec.Suspend)
-					rows = append(rows, []string{
-						"HelmRepository", hr.Name, hr.Namespace, hr.Spec.URL, revision, string(health), util.FormatAge(hr.CreationTimestamp.Time),
-					})
+		addSource("oci", func(opts metav1.ListOptions) (int, string, *int64, error) {
+			page, err := fluxClient.ListOCIRepositoriesPage(ctx, ns, opts)
+			if err != nil {
+				return 0, "", nil, err
+			}
+			for i := range page.Items {
+				or := &page.Items[i]
+				revision := "<none>"
+				if or.Status.Artifact != nil {
+					revision = truncateRevision(or.Status.Artifact.Revision)
 				}
+				health := flux.GetFluxHealth(or.Status.Conditions, or.Generation, or.Status.ObservedGeneration, or.Spec.Suspend)
+				rows = append(rows, []string{
+					"OCIRepository", or.Name, or.Namespace, or.Spec.URL, revision, string(health), util.FormatAge(or.CreationTimestamp.Time),
+				})
 			}
-		}
+			return len(page.Items), page.Continue, page.Remaining, nil
+		})
 
-		if filter == "" || filter == "helmchart" {
-			helmCharts, err := fluxClient.ListHelmCharts(ctx, ns)
-			if err == nil {
-				for i := range helmCharts {
-					hc := &helmCharts[i]
-					revision := "<none>"
-					if hc.Status.Artifact != nil {
-						revision = truncateRevision(hc.Status.Artifact.Revision)
-					}
-					health := flux.GetFluxHealth(hc.Status.Conditions, hc.Generation, hc.Status.ObservedGeneration, hc.Spec.Suspend)
-					rows = append(rows, []string{
-						"HelmChart", hc.Name, hc.Namespace, hc.Spec.Chart, revision, string(health), util.FormatAge(hc.CreationTimestamp.Time),
-					})
+		addSource("helm", func(opts metav1.ListOptions) (int, string, *int64, error) {
+			page, err := fluxClient.ListHelmRepositoriesPage(ctx, ns, opts)
+			if err != nil {
+				return 0, "", nil, err
+			}
+			for i := range page.Items {
+				hr := &page.Items[i]
+				revision := "<none>"
+				if hr.Status.Artifact != nil {
+					revision = truncateRevision(hr.Status.Artifact.Revision)
 				}
+				health := flux.GetFluxHealth(hr.Status.Conditions, hr.Generation, hr.Status.ObservedGeneration, hr.Spec.Suspend)
+				rows = append(rows, []string{
+					"HelmRepository", hr.Name, hr.Namespace, hr.Spec.URL, revision, string(health), util.FormatAge(hr.CreationTimestamp.Time),
+				})
 			}
-		}
+			return len(page.Items), page.Continue, page.Remaining, nil
+		})
 
-		if filter == "" || filter == "bucket" {
-			buckets, err := fluxClient.ListBuckets(ctx, ns)
-			if err == nil {
-				for i := range buckets {
-					b := &buckets[i]
-					revision := "<none>"
-					if b.Status.Artifact != nil {
-						revision = truncateRevision(b.Status.Artifact.Revision)
-					}
-					health := flux.GetFluxHealth(b.Status.Conditions, b.Generation, b.Status.ObservedGeneration, b.Spec.Suspend)
-					rows = append(rows, []string{
-						"Bucket", b.Name, b.Namespace, b.Spec.Endpoint, revision, string(health), util.FormatAge(b.CreationTimestamp.Time),
-					})
+		addSource("helmchart", func(opts metav1.ListOptions) (int, string, *int64, error) {
+			page, err := fluxClient.ListHelmChartsPage(ctx, ns, opts)
+			if err != nil {
+				return 0, "", nil, err
+			}
+			for i := range page.Items {
+				hc := &page.Items[i]
+				revision := "<none>"
+				if hc.Status.Artifact != nil {
+					revision = truncateRevision(hc.Status.Artifact.Revision)
+				}
+				health := flux.GetFluxHealth(hc.Status.Conditions, hc.Generation, hc.Status.ObservedGeneration, hc.Spec.Suspend)
+				rows = append(rows, []string{
+					"HelmChart", hc.Name, hc.Namespace, hc.Spec.Chart, revision, string(health), util.FormatAge(hc.CreationTimestamp.Time),
+				})
+			}
+			return len(page.Items), page.Continue, page.Remaining, nil
+		})
+
+		addSource("bucket", func(opts metav1.ListOptions) (int, string, *int64, error) {
+			page, err := fluxClient.ListBucketsPage(ctx, ns, opts)
+			if err != nil {
+				return 0, "", nil, err
+			}
+			for i := range page.Items {
+				b := &page.Items[i]
+				revision := "<none>"
+				if b.Status.Artifact != nil {
+					revision = truncateRevision(b.Status.Artifact.Revision)
 				}
+				health := flux.GetFluxHealth(b.Status.Conditions, b.Generation, b.Status.ObservedGeneration, b.Spec.Suspend)
+				rows = append(rows, []string{
+					"Bucket", b.Name, b.Namespace, b.Spec.Endpoint, revision, string(health), util.FormatAge(b.CreationTimestamp.Time),
+				})
 			}
+			return len(page.Items), page.Continue, page.Remaining, nil
+		})
+
+		next, remaining, err := pagePhases(util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken), phases)
+		if err != nil {
+			return util.ErrorResult("%v (list again without continue_token, keeping the same source_type)", err), nil, nil
 		}
 
 		var sb strings.Builder
@@ -270,6 +313,7 @@ func registerListFluxSources(server *mcp.Server, fluxClient *flux.FluxClient) {
 		}
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("sources", len(rows))))
+		sb.WriteString(pageFooter("list_flux_sources", next, remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -287,53 +331,66 @@ func registerListFluxImagePolicies(server *mcp.Server, fluxClient *flux.FluxClie
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Flux Image Automation (namespace: %s)", displayNS(input.Namespace))))
 		sb.WriteString("\n\n")
 
+		irRows := make([][]string, 0)
+		ipRows := make([][]string, 0)
+		action := "listing Flux ImageRepositories"
+		next, remaining, err := pagePhases(util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken), []pagePhase{
+			{name: "repositories", list: func(opts metav1.ListOptions) (int, string, *int64, error) {
+				page, err := fluxClient.ListImageRepositoriesPage(ctx, ns, opts)
+				if err != nil {
+					return 0, "", nil, err
+				}
+				for i := range page.Items {
+					ir := &page.Items[i]
+					health := flux.GetFluxHealth(ir.Status.Conditions, ir.Generation, ir.Status.ObservedGeneration, ir.Spec.Suspend)
+					latestTag := "<none>"
+					if ir.Status.LastScanResult != nil && ir.Status.LastScanResult.LatestTags != nil && len(ir.Status.LastScanResult.LatestTags) > 0 {
+						latestTag = ir.Status.LastScanResult.LatestTags[0]
+					}
+					irRows = append(irRows, []string{
+						ir.Name, ir.Namespace, ir.Spec.Image, latestTag, string(health), util.FormatAge(ir.CreationTimestamp.Time),
+					})
+				}
+				return len(page.Items), page.Continue, page.Remaining, nil
+			}},
+			{name: "policies", list: func(opts metav1.ListOptions) (int, string, *int64, error) {
+				action = "listing Flux ImagePolicies"
+				page, err := fluxClient.ListImagePoliciesPage(ctx, ns, opts)
+				if err != nil {
+					return 0, "", nil, err
+				}
+				for i := range page.Items {
+					ip := &page.Items[i]
+					health := flux.GetFluxHealth(ip.Status.Conditions, ip.Generation, ip.Status.ObservedGeneration, false)
+					latestImage := "<none>"
+					if ip.Status.LatestRef != nil {
+						latestImage = fmt.Sprintf("%s:%s", ip.Status.LatestRef.Name, ip.Status.LatestRef.Tag)
+					}
+					repoRef := ip.Spec.ImageRepositoryRef.Name
+					ipRows = append(ipRows, []string{
+						ip.Name, ip.Namespace, repoRef, latestImage, string(health), util.FormatAge(ip.CreationTimestamp.Time),
+					})
+				}
+				return len(page.Items), page.Continue, page.Remaining, nil
+			}},
+		})
+		if err != nil {
+			return handleFluxError(action, err), nil, nil
+		}
+
 		// ImageRepositories
 		sb.WriteString(util.FormatSubHeader("Image Repositories"))
 		sb.WriteString("\n")
-		imageRepos, err := fluxClient.ListImageRepositories(ctx, ns)
-		if err != nil {
-			return handleFluxError("listing Flux ImageRepositories", err), nil, nil
-		}
-		irHeaders := []string{"NAME", "NAMESPACE", "IMAGE", "LATEST TAG", "STATUS", "AGE"}
-		irRows := make([][]string, 0, len(imageRepos))
-		for i := range imageRepos {
-			ir := &imageRepos[i]
-			health := flux.GetFluxHealth(ir.Status.Conditions, ir.Generation, ir.Status.ObservedGeneration, ir.Spec.Suspend)
-			latestTag := "<none>"
-			if ir.Status.LastScanResult != nil && ir.Status.LastScanResult.LatestTags != nil && len(ir.Status.LastScanResult.LatestTags) > 0 {
-				latestTag = ir.Status.LastScanResult.LatestTags[0]
-			}
-			irRows = append(irRows, []string{
-				ir.Name, ir.Namespace, ir.Spec.Image, latestTag, string(health), util.FormatAge(ir.CreationTimestamp.Time),
-			})
-		}
-		sb.WriteString(util.FormatTable(irHeaders, irRows))
+		sb.WriteString(util.FormatTable([]string{"NAME", "NAMESPACE", "IMAGE", "LATEST TAG", "STATUS", "AGE"}, irRows))
 
 		// ImagePolicies
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Image Policies"))
 		sb.WriteString("\n")
-		policies, err := fluxClient.ListImagePolicies(ctx, ns)
-		if err != nil {
-			return handleFluxError("listing Flux ImagePolicies", err), nil, nil
-		}
-		ipHeaders := []string{"NAME", "NAMESPACE", "IMAGE REPO", "LATEST IMAGE", "STATUS", "AGE"}
-		ipRows := make([][]string, 0, len(policies))
-		for i := range policies {
-			ip := &policies[i]
-			health := flux.GetFluxHealth(ip.Status.Conditions, ip.Generation, ip.Status.ObservedGeneration, false)
-			latestImage := "<none>"
-			if ip.Status.LatestRef != nil {
-				latestImage = fmt.Sprintf("%s:%s", ip.Status.LatestRef.Name, ip.Status.LatestRef.Tag)
-			}
-			repoRef := ip.Spec.ImageRepositoryRef.Name
-			ipRows = append(ipRows, []string{
-				ip.Name, ip.Namespace, repoRef, latestImage, string(health), util.FormatAge(ip.CreationTimestamp.Time),
-			})
-		}
-		sb.WriteString(util.FormatTable(ipHeaders, ipRows))
+		sb.WriteString(util.FormatTable([]string{"NAME", "NAMESPACE", "IMAGE REPO", "LATEST IMAGE", "STATUS", "AGE"}, ipRows))
 
-		sb.WriteString(fmt.Sprintf("\nTotal: %d image repositories, %d image policies\n", len(imageRepos), len(policies)))
+		sb.WriteString(fmt.Sprintf("\nTotal: %d image repositories, %d image policies\n", len(irRows), len(ipRows)))
+		sb.WriteString(pageFooter("list_flux_image_policies", next, remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -388,40 +445,41 @@ func registerDiagnoseFluxKustomization(server *mcp.Server, fluxClient *flux.Flux
 
 		// Findings
 		sb.WriteString("\nFINDINGS:\n")
-		findings := 0
+		findingCount := 0
+		var steps []util.NextStep
 
 		if ks.Spec.Suspend {
-			sb.WriteString(util.FormatFinding("INFO", "Kustomization is suspended — reconciliation paused"))
+			sb.WriteString(findings.Format("KD-GIT-003", "INFO", "Kustomization is suspended — reconciliation paused"))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		}
 
 		if health == flux.HealthFailed {
 			msg := flux.GetConditionMessage(ks.Status.Conditions, fluxmeta.ReadyCondition)
-			sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Reconciliation failed: %s", msg)))
+			sb.WriteString(findings.Format("KD-GIT-001", "CRITICAL", fmt.Sprintf("Reconciliation failed: %s", msg)))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		}
 
 		if health == flux.HealthStalled {
 			msg := flux.GetConditionMessage(ks.Status.Conditions, fluxmeta.StalledCondition)
-			sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Reconciliation stalled: %s", msg)))
+			sb.WriteString(findings.Format("KD-GIT-001", "CRITICAL", fmt.Sprintf("Reconciliation stalled: %s", msg)))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		}
 
 		if ks.Status.LastAppliedRevision != ks.Status.LastAttemptedRevision && ks.Status.LastAttemptedRevision != "" {
-			sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Applied revision (%s) differs from attempted revision (%s)",
+			sb.WriteString(findings.Format("KD-GIT-002", "WARNING", fmt.Sprintf("Applied revision (%s) differs from attempted revision (%s)",
 				truncateRevision(ks.Status.LastAppliedRevision), truncateRevision(ks.Status.LastAttemptedRevision))))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		}
 
 		// Check source health
 		sourceHealth := checkSourceHealth(ctx, fluxClient, ks.Spec.SourceRef.Kind, ks.Spec.SourceRef.Name, resolveNamespace(ks.Spec.SourceRef.Namespace, ks.Namespace))
 		if sourceHealth != "" {
 			sb.WriteString(sourceHealth)
-			findings++
+			findingCount++
 		}
 
 		// Check dependencies
@@ -437,13 +495,14 @@ func registerDiagnoseFluxKustomization(server *mcp.Server, fluxClient *flux.Flux
 				depKs, err := fluxClient.GetKustomization(ctx, depNS, dep.Name)
 				if err != nil {
 					sb.WriteString(fmt.Sprintf("  %s/%s: (could not fetch: %v)\n", depNS, dep.Name, err))
-					findings++
+					findingCount++
 				} else {
 					depHealth := flux.KustomizationHealth(depKs)
 					sb.WriteString(fmt.Sprintf("  %s/%s: %s\n", depNS, dep.Name, depHealth))
 					if depHealth != flux.HealthReady {
-						sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("Dependency %s is not Ready", dep.Name))))
-						findings++
+						sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-GIT-002", "WARNING", fmt.Sprintf("Dependency %s is not Ready", dep.Name))))
+						findingCount++
+						steps = append(steps, nextStep("diagnose_flux_kustomization", fmt.Sprintf("Dependency is %s", depHealth), "namespace", depNS, "name", dep.Name))
 					}
 				}
 			}
@@ -481,7 +540,7 @@ func registerDiagnoseFluxKustomization(server *mcp.Server, fluxClient *flux.Flux
 			}
 		}
 
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  No issues found — Kustomization appears healthy.\n")
 		}
 
@@ -489,6 +548,7 @@ func registerDiagnoseFluxKustomization(server *mcp.Server, fluxClient *flux.Flux
 		sb.WriteString("\nSUGGESTED ACTIONS:\n")
 		actionNum := 1
 		if health == flux.HealthFailed {
+			steps = append(steps, nextStep("get_flux_resource_tree", "Find the managed resources that are not ready", "namespace", ks.Namespace, "name", ks.Name))
 			reason := flux.GetConditionReason(ks.Status.Conditions, fluxmeta.ReadyCondition)
 			switch reason {
 			case "BuildFailed":
@@ -513,7 +573,7 @@ func registerDiagnoseFluxKustomization(server *mcp.Server, fluxClient *flux.Flux
 			sb.WriteString("  No specific actions needed — Kustomization is healthy.\n")
 		}
 
-		return util.SuccessResult(sb.String()), nil, nil
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
 	})
 }
 
@@ -579,44 +639,45 @@ func registerDiagnoseFluxHelmRelease(server *mcp.Server, fluxClient *flux.FluxCl
 
 		// Findings
 		sb.WriteString("\nFINDINGS:\n")
-		findings := 0
+		findingCount := 0
+		var steps []util.NextStep
 
 		if hr.Spec.Suspend {
-			sb.WriteString(util.FormatFinding("INFO", "HelmRelease is suspended — reconciliation paused"))
+			sb.WriteString(findings.Format("KD-GIT-003", "INFO", "HelmRelease is suspended — reconciliation paused"))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		}
 
 		if health == flux.HealthFailed {
 			msg := flux.GetConditionMessage(hr.Status.Conditions, fluxmeta.ReadyCondition)
-			sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Reconciliation failed: %s", msg)))
+			sb.WriteString(findings.Format("KD-GIT-001", "CRITICAL", fmt.Sprintf("Reconciliation failed: %s", msg)))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		}
 
 		if health == flux.HealthStalled {
 			msg := flux.GetConditionMessage(hr.Status.Conditions, fluxmeta.StalledCondition)
-			sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Reconciliation stalled: %s", msg)))
+			sb.WriteString(findings.Format("KD-GIT-001", "CRITICAL", fmt.Sprintf("Reconciliation stalled: %s", msg)))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		}
 
 		// Check Released condition
 		releasedMsg := flux.GetConditionMessage(hr.Status.Conditions, "Released")
 		releasedReason := flux.GetConditionReason(hr.Status.Conditions, "Released")
 		if releasedReason != "" && releasedReason != "Succeeded" {
-			sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Release issue: %s — %s", releasedReason, releasedMsg)))
+			sb.WriteString(findings.Format("KD-GIT-001", "WARNING", fmt.Sprintf("Release issue: %s — %s", releasedReason, releasedMsg)))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		}
 
 		// Check test condition
 		testMsg := flux.GetConditionMessage(hr.Status.Conditions, "TestSuccess")
 		testReason := flux.GetConditionReason(hr.Status.Conditions, "TestSuccess")
 		if testReason == "Failed" {
-			sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Helm tests failed: %s", testMsg)))
+			sb.WriteString(findings.Format("KD-GIT-001", "WARNING", fmt.Sprintf("Helm tests failed: %s", testMsg)))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		}
 
 		// Release history
@@ -645,7 +706,7 @@ func registerDiagnoseFluxHelmRelease(server *mcp.Server, fluxClient *flux.FluxCl
 			sourceCheck := checkSourceHealth(ctx, fluxClient, sourceKind, sourceName, sourceNS)
 			if sourceCheck != "" {
 				sb.WriteString(sourceCheck)
-				findings++
+				findingCount++
 			}
 		}
 
@@ -666,7 +727,7 @@ func registerDiagnoseFluxHelmRelease(server *mcp.Server, fluxClient *flux.FluxCl
 			}
 		}
 
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  No issues found — HelmRelease appears healthy.\n")
 		}
 
@@ -674,6 +735,7 @@ func registerDiagnoseFluxHelmRelease(server *mcp.Server, fluxClient *flux.FluxCl
 		sb.WriteString("\nSUGGESTED ACTIONS:\n")
 		actionNum := 1
 		if health == flux.HealthFailed {
+			steps = append(steps, nextStep("get_flux_resource_tree", "Find the managed resources that are not ready", "namespace", hr.Namespace, "name", hr.Name))
 			reason := flux.GetConditionReason(hr.Status.Conditions, fluxmeta.ReadyCondition)
 			switch {
 			case strings.Contains(reason, "Install"):
@@ -695,7 +757,7 @@ func registerDiagnoseFluxHelmRelease(server *mcp.Server, fluxClient *flux.FluxCl
 			sb.WriteString("  No specific actions needed — HelmRelease is healthy.\n")
 		}
 
-		return util.SuccessResult(sb.String()), nil, nil
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
 	})
 }
 
@@ -710,7 +772,8 @@ func registerDiagnoseFluxSystem(server *mcp.Server, fluxClient *flux.FluxClient,
 		sb.WriteString(util.FormatHeader("FluxCD System Health Report"))
 		sb.WriteString("\n\n")
 
-		findings := 0
+		findingCount := 0
+		var steps []util.NextStep
 
 		// 1. Flux controller pods
 		sb.WriteString(util.FormatSubHeader("Flux Controllers (flux-system namespace)"))
@@ -720,9 +783,9 @@ func registerDiagnoseFluxSystem(server *mcp.Server, fluxClient *flux.FluxClient,
 			if err != nil {
 				sb.WriteString(fmt.Sprintf("  (could not list pods: %v)\n", err))
 			} else if len(pods) == 0 {
-				sb.WriteString(util.FormatFinding("CRITICAL", "No pods found in flux-system namespace — FluxCD may not be installed"))
+				sb.WriteString(findings.Format("KD-GIT-005", "CRITICAL", "No pods found in flux-system namespace — FluxCD may not be installed"))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			} else {
 				healthy := 0
 				for i := range pods {
@@ -735,8 +798,9 @@ func registerDiagnoseFluxSystem(server *mcp.Server, fluxClient *flux.FluxClient,
 					for i := range pods {
 						p := &pods[i]
 						if !isPodHealthy(p) {
-							sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("CRITICAL", fmt.Sprintf("Controller pod '%s' is unhealthy: %s", p.Name, podPhaseReason(p)))))
-							findings++
+							sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-GIT-005", "CRITICAL", fmt.Sprintf("Controller pod '%s' is unhealthy: %s", p.Name, podPhaseReason(p)))))
+							findingCount++
+							steps = append(steps, nextStep("diagnose_pod", "Flux controller is "+podPhaseReason(p), "namespace", p.Namespace, "name", p.Name))
 						}
 					}
 				}
@@ -761,14 +825,15 @@ func registerDiagnoseFluxSystem(server *mcp.Server, fluxClient *flux.FluxClient,
 			sb.WriteString("\n")
 			failedCount := ksTally[flux.HealthFailed] + ksTally[flux.HealthStalled]
 			if failedCount > 0 {
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d Kustomizations not healthy", failedCount))))
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-GIT-001", "WARNING", fmt.Sprintf("%d Kustomizations not healthy", failedCount))))
 				for i := range ksList {
 					h := flux.KustomizationHealth(&ksList[i])
 					if h == flux.HealthFailed || h == flux.HealthStalled {
 						sb.WriteString(fmt.Sprintf("    - %s/%s: %s\n", ksList[i].Namespace, ksList[i].Name, h))
+						steps = append(steps, nextStep("diagnose_flux_kustomization", fmt.Sprintf("Kustomization is %s", h), "namespace", ksList[i].Namespace, "name", ksList[i].Name))
 					}
 				}
-				findings++
+				findingCount++
 			}
 		}
 
@@ -790,14 +855,15 @@ func registerDiagnoseFluxSystem(server *mcp.Server, fluxClient *flux.FluxClient,
 			sb.WriteString("\n")
 			failedCount := hrTally[flux.HealthFailed] + hrTally[flux.HealthStalled]
 			if failedCount > 0 {
-				sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d HelmReleases not healthy", failedCount))))
+				sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-GIT-001", "WARNING", fmt.Sprintf("%d HelmReleases not healthy", failedCount))))
 				for i := range hrList {
 					h := flux.HelmReleaseHealth(&hrList[i])
 					if h == flux.HealthFailed || h == flux.HealthStalled {
 						sb.WriteString(fmt.Sprintf("    - %s/%s: %s\n", hrList[i].Namespace, hrList[i].Name, h))
+						steps = append(steps, nextStep("diagnose_flux_helm_release", fmt.Sprintf("HelmRelease is %s", h), "namespace", hrList[i].Namespace, "name", hrList[i].Name))
 					}
 				}
-				findings++
+				findingCount++
 			}
 		}
 
@@ -814,7 +880,7 @@ func registerDiagnoseFluxSystem(server *mcp.Server, fluxClient *flux.FluxClient,
 				h := flux.GetFluxHealth(gitRepos[i].Status.Conditions, gitRepos[i].Generation, gitRepos[i].Status.ObservedGeneration, gitRepos[i].Spec.Suspend)
 				if h == flux.HealthFailed || h == flux.HealthStalled {
 					srcFailed++
-					sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("GitRepository %s/%s: %s", gitRepos[i].Namespace, gitRepos[i].Name, h))))
+					sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-GIT-004", "WARNING", fmt.Sprintf("GitRepository %s/%s: %s", gitRepos[i].Namespace, gitRepos[i].Name, h))))
 				}
 			}
 		}
@@ -825,7 +891,7 @@ func registerDiagnoseFluxSystem(server *mcp.Server, fluxClient *flux.FluxClient,
 				h := flux.GetFluxHealth(helmRepos[i].Status.Conditions, helmRepos[i].Generation, helmRepos[i].Status.ObservedGeneration, helmRepos[i].Spec.Suspend)
 				if h == flux.HealthFailed || h == flux.HealthStalled {
 					srcFailed++
-					sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("HelmRepository %s/%s: %s", helmRepos[i].Namespace, helmRepos[i].Name, h))))
+					sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-GIT-004", "WARNING", fmt.Sprintf("HelmRepository %s/%s: %s", helmRepos[i].Namespace, helmRepos[i].Name, h))))
 				}
 			}
 		}
@@ -836,12 +902,12 @@ func registerDiagnoseFluxSystem(server *mcp.Server, fluxClient *flux.FluxClient,
 				h := flux.GetFluxHealth(ociRepos[i].Status.Conditions, ociRepos[i].Generation, ociRepos[i].Status.ObservedGeneration, ociRepos[i].Spec.Suspend)
 				if h == flux.HealthFailed || h == flux.HealthStalled {
 					srcFailed++
-					sb.WriteString(fmt.Sprintf("  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("OCIRepository %s/%s: %s", ociRepos[i].Namespace, ociRepos[i].Name, h))))
+					sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-GIT-004", "WARNING", fmt.Sprintf("OCIRepository %s/%s: %s", ociRepos[i].Namespace, ociRepos[i].Name, h))))
 				}
 			}
 		}
 		if srcFailed > 0 {
-			findings++
+			findingCount++
 		}
 		sb.WriteString(fmt.Sprintf("  Total sources: %d, Unhealthy: %d\n", srcCount, srcFailed))
 
@@ -861,8 +927,8 @@ func registerDiagnoseFluxSystem(server *mcp.Server, fluxClient *flux.FluxClient,
 					}
 				}
 				if warningCount > 0 {
-					sb.WriteString(fmt.Sprintf("\n  %s\n", util.FormatFinding("WARNING", fmt.Sprintf("%d warning events in flux-system in the last hour", warningCount))))
-					findings++
+					sb.WriteString(fmt.Sprintf("\n  %s\n", findings.Format("KD-EVT-001", "WARNING", fmt.Sprintf("%d warning events in flux-system in the last hour", warningCount))))
+					findingCount++
 				}
 			}
 		}
@@ -871,10 +937,10 @@ func registerDiagnoseFluxSystem(server *mcp.Server, fluxClient *flux.FluxClient,
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
 		sb.WriteString("\n")
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  FluxCD system appears healthy. No issues found.\n")
 		} else {
-			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findings))
+			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findingCount))
 		}
 
 		// Mermaid diagram
@@ -885,7 +951,7 @@ func registerDiagnoseFluxSystem(server *mcp.Server, fluxClient *flux.FluxClient,
 		sb.WriteString(util.FormatMermaidBlock(mermaid))
 		sb.WriteString("\n")
 
-		return util.SuccessResult(sb.String()), nil, nil
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
 	})
 }
 
@@ -996,15 +1062,20 @@ func registerGetFluxResourceTree(server *mcp.Server, fluxClient *flux.FluxClient
 
 // handleFluxError handles errors from Flux API calls, including CRD-not-found detection.
 func handleFluxError(action string, err error) *mcp.CallToolResult {
-	errStr := err.Error()
-	if strings.Contains(errStr, "no matches for kind") ||
-		strings.Contains(errStr, "no kind is registered") ||
-		strings.Contains(errStr, "the server could not find the requested resource") {
+	if fluxCRDsMissing(err) {
 		return util.SuccessResult("FluxCD is not installed in this cluster. The required Custom Resource Definitions were not found.\n\nTo install FluxCD: https://fluxcd.io/flux/installation/")
 	}
 	return util.HandleK8sError(action, err)
 }
 
+// fluxCRDsMissing reports whether a Flux API error means the CRDs are not installed.
+func fluxCRDsMissing(err error) bool {
+	errStr := err.Error()
+	return strings.Contains(errStr, "no matches for kind") ||
+		strings.Contains(errStr, "no kind is registered") ||
+		strings.Contains(errStr, "the server could not find the requested resource")
+}
+
 // truncateRevision shortens a Flux revision string for display.
 func truncateRevision(rev string) string {
 	if rev == "" {
@@ -1067,7 +1138,7 @@ func checkSourceHealth(ctx context.Context, fluxClient *flux.FluxClient, kind, n
 	case "GitRepository":
 		gr, err := fluxClient.GetGitRepository(ctx, namespace, name)
 		if err != nil {
-			return fmt.Sprintf("%s\n", util.FormatFinding("WARNING", fmt.Sprintf("Cannot fetch source %s/%s: %v", kind, name, err)))
+			return fmt.Sprintf("%s\n", findings.Format("KD-GIT-004", "WARNING", fmt.Sprintf("Cannot fetch source %s/%s: %v", kind, name, err)))
 		}
 		conditions = gr.Status.Conditions
 		generation = gr.Generation
@@ -1076,7 +1147,7 @@ func checkSourceHealth(ctx context.Context, fluxClient *flux.FluxClient, kind, n
 	case "OCIRepository":
 		or, err := fluxClient.GetOCIRepository(ctx, namespace, name)
 		if err != nil {
-			return fmt.Sprintf("%s\n", util.FormatFinding("WARNING", fmt.Sprintf("Cannot fetch source %s/%s: %v", kind, name, err)))
+			return fmt.Sprintf("%s\n", findings.Format("KD-GIT-004", "WARNING", fmt.Sprintf("Cannot fetch source %s/%s: %v", kind, name, err)))
 		}
 		conditions = or.Status.Conditions
 		generation = or.Generation
@@ -1085,7 +1156,7 @@ func checkSourceHealth(ctx context.Context, fluxClient *flux.FluxClient, kind, n
 	case "HelmRepository":
 		hr, err := fluxClient.GetHelmRepository(ctx, namespace, name)
 		if err != nil {
-			return fmt.Sprintf("%s\n", util.FormatFinding("WARNING", fmt.Sprintf("Cannot fetch source %s/%s: %v", kind, name, err)))
+			return fmt.Sprintf("%s\n", findings.Format("KD-GIT-004", "WARNING", fmt.Sprintf("Cannot fetch source %s/%s: %v", kind, name, err)))
 		}
 		conditions = hr.Status.Conditions
 		generation = hr.Generation
@@ -1094,7 +1165,7 @@ func checkSourceHealth(ctx context.Context, fluxClient *flux.FluxClient, kind, n
 	case "Bucket":
 		b, err := fluxClient.GetBucket(ctx, namespace, name)
 		if err != nil {
-			return fmt.Sprintf("%s\n", util.FormatFinding("WARNING", fmt.Sprintf("Cannot fetch source %s/%s: %v", kind, name, err)))
+			return fmt.Sprintf("%s\n", findings.Format("KD-GIT-004", "WARNING", fmt.Sprintf("Cannot fetch source %s/%s: %v", kind, name, err)))
 		}
 		conditions = b.Status.Conditions
 		generation = b.Generation
@@ -1107,7 +1178,7 @@ func checkSourceHealth(ctx context.Context, fluxClient *flux.FluxClient, kind, n
 	health := flux.GetFluxHealth(conditions, generation, observedGeneration, suspended)
 	if health != flux.HealthReady && health != flux.HealthSuspended {
 		msg := flux.GetConditionMessage(conditions, fluxmeta.ReadyCondition)
-		return fmt.Sprintf("%s\n", util.FormatFinding("WARNING", fmt.Sprintf("Source %s/%s is %s: %s", kind, name, health, msg)))
+		return fmt.Sprintf("%s\n", findings.Format("KD-GIT-004", "WARNING", fmt.Sprintf("Source %s/%s is %s: %s", kind, name, health, msg)))
 	}
 	return ""
 }
@@ -236,6 +236,36 @@ func TestListFluxSources_Filtered(t *testing.T) {
 	}
 }
 
+func TestListFluxSources_Paged(t *testing.T) {
+	objs := []client.Object{
+		&sourcev1.GitRepository{ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "flux-system"}},
+		&sourcev1.GitRepository{ObjectMeta: metav1.ObjectMeta{Name: "infra", Namespace: "flux-system"}},
+		&sourcev1.HelmRepository{ObjectMeta: metav1.ObjectMeta{Name: "bitnami", Namespace: "flux-system"}},
+	}
+
+	// The GitRepositories fill the first page; the remaining kinds follow on the next.
+	text, isErr := callFluxTool(t, objs, nil, "list_flux_sources", map[string]any{"limit": 2})
+	if isErr {
+		t.Fatalf("expected success, got error: %s", text)
+	}
+	if strings.Contains(text, "HelmRepository") || !strings.Contains(text, `continue_token="oci:"`) {
+		t.Errorf("first page should hold only the GitRepositories:\n%s", text)
+	}
+
+	text, isErr = callFluxTool(t, objs, nil, "list_flux_sources", map[string]any{"limit": 2, "continue_token": "oci:"})
+	if isErr {
+		t.Fatalf("expected success, got error: %s", text)
+	}
+	if strings.Contains(text, "GitRepository") || !strings.Contains(text, "bitnami") || strings.Contains(text, "TRUNCATED") {
+		t.Errorf("second page should hold only the HelmRepository:\n%s", text)
+	}
+
+	text, isErr = callFluxTool(t, objs, nil, "list_flux_sources", map[string]any{"source_type": "git", "continue_token": "oci:"})
+	if !isErr {
+		t.Errorf("expected a token for a filtered-out kind to be rejected, got:\n%s", text)
+	}
+}
+
 // --- list_flux_image_policies tests ---
 
 func TestListFluxImagePolicies(t *testing.T) {
@@ -0,0 +1,234 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// fragmentationWarnPct is the share of on-paper capacity for typical pods
+// that must be stranded in slivers before fragmentation is a WARNING.
+const fragmentationWarnPct = 25
+
+// podSize is a CPU (millicores) and memory (bytes) request used to measure
+// how much free capacity is usable.
+type podSize struct {
+	Name string
+	CPU  int64
+	Mem  int64
+}
+
+// standardPodSizes are the reference sizes fragmentation is reported for,
+// besides the cluster's own median pod.
+var standardPodSizes = []podSize{
+	{Name: "small", CPU: 250, Mem: 512 << 20},
+	{Name: "medium", CPU: 500, Mem: 1 << 30},
+	{Name: "large", CPU: 1000, Mem: 2 << 30},
+	{Name: "xlarge", CPU: 2000, Mem: 4 << 30},
+}
+
+// nodeRoom is the free requestable room on a schedulable node.
+type nodeRoom struct {
+	Name     string
+	FreeCPU  int64
+	FreeMem  int64
+	FreePods int64
+}
+
+// fits returns how many pods of size s fit in the room.
+func (r nodeRoom) fits(s podSize) int64 {
+	n := max(r.FreePods, 0)
+	if s.CPU > 0 {
+		n = min(n, max(r.FreeCPU, 0)/s.CPU)
+	}
+	if s.Mem > 0 {
+		n = min(n, max(r.FreeMem, 0)/s.Mem)
+	}
+	return n
+}
+
+// sizeFragmentation compares how many pods of one size fit node by node
+// with how many the summed free capacity suggests.
+type sizeFragmentation struct {
+	Size    podSize
+	Fit     int64
+	OnPaper int64
+}
+
+// StrandedPct is the share of on-paper pods that do not fit on any node.
+func (f sizeFragmentation) StrandedPct() float64 {
+	if f.OnPaper == 0 {
+		return 0
+	}
+	return float64(f.OnPaper-f.Fit) / float64(f.OnPaper) * 100
+}
+
+// fragmentation is the fragmentation analysis of a cluster's free capacity.
+type fragmentation struct {
+	Nodes []nodeRoom
+	// Skipped counts nodes left out because they are not Ready or cordoned.
+	Skipped int
+	Typical podSize
+	Sizes   []sizeFragmentation
+	// StrandedCPU and StrandedMem are the free requests left on nodes after
+	// packing as many typical pods as fit: capacity on paper that no typical
+	// pod can use.
+	StrandedCPU int64
+	StrandedMem int64
+}
+
+// analyzeFragmentation measures the free room on each schedulable node and
+// how much of the cluster's free capacity is usable for the median pod and
+// each standard size. Requests follow the scheduler's effective pod request.
+func analyzeFragmentation(nodes []corev1.Node, pods []corev1.Pod) fragmentation {
+	var frag fragmentation
+	byName := make(map[string]int, len(nodes))
+	for i := range nodes {
+		n := &nodes[i]
+		if nodeStatus(n) != "Ready" || n.Spec.Unschedulable {
+			frag.Skipped++
+			continue
+		}
+		byName[n.Name] = len(frag.Nodes)
+		frag.Nodes = append(frag.Nodes, nodeRoom{
+			Name:     n.Name,
+			FreeCPU:  n.Status.Allocatable.Cpu().MilliValue(),
+			FreeMem:  n.Status.Allocatable.Memory().Value(),
+			FreePods: n.Status.Allocatable.Pods().Value(),
+		})
+	}
+
+	var cpus, mems []int64
+	for i := range pods {
+		p := &pods[i]
+		if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed || p.Spec.NodeName == "" {
+			continue
+		}
+		u := podQuotaUsage(&p.Spec)
+		cpu, mem := u[corev1.ResourceRequestsCPU], u[corev1.ResourceRequestsMemory]
+		if cpu.MilliValue() > 0 || mem.Value() > 0 {
+			cpus = append(cpus, cpu.MilliValue())
+			mems = append(mems, mem.Value())
+		}
+		if idx, ok := byName[p.Spec.NodeName]; ok {
+			r := &frag.Nodes[idx]
+			r.FreeCPU -= cpu.MilliValue()
+			r.FreeMem -= mem.Value()
+			r.FreePods--
+		}
+	}
+
+	sizes := standardPodSizes
+	if len(cpus) > 0 {
+		frag.Typical = podSize{Name: "typical", CPU: median(cpus), Mem: median(mems)}
+		sizes = append([]podSize{frag.Typical}, sizes...)
+	}
+	var totalCPU, totalMem, totalPods int64
+	for _, r := range frag.Nodes {
+		totalCPU += max(r.FreeCPU, 0)
+		totalMem += max(r.FreeMem, 0)
+		totalPods += max(r.FreePods, 0)
+	}
+	for _, s := range sizes {
+		sf := sizeFragmentation{Size: s, OnPaper: nodeRoom{FreeCPU: totalCPU, FreeMem: totalMem, FreePods: totalPods}.fits(s)}
+		for _, r := range frag.Nodes {
+			sf.Fit += r.fits(s)
+		}
+		frag.Sizes = append(frag.Sizes, sf)
+	}
+	if frag.Typical.Name != "" {
+		for _, r := range frag.Nodes {
+			n := r.fits(frag.Typical)
+			frag.StrandedCPU += max(r.FreeCPU, 0) - n*frag.Typical.CPU
+			frag.StrandedMem += max(r.FreeMem, 0) - n*frag.Typical.Mem
+		}
+	}
+	return frag
+}
+
+// median returns the median of values, which it sorts.
+func median(values []int64) int64 {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values[len(values)/2]
+}
+
+// largestFitLabel describes the largest pod a node can still take: its free
+// requests, and the largest standard size within them.
+func largestFitLabel(r nodeRoom) string {
+	if r.FreePods <= 0 {
+		return "none (pod limit reached)"
+	}
+	if r.FreeCPU <= 0 || r.FreeMem <= 0 {
+		return "none (requests full)"
+	}
+	label := fmt.Sprintf("%dm / %s", r.FreeCPU, formatBytes(r.FreeMem))
+	fitting := ""
+	for _, s := range standardPodSizes {
+		if r.fits(s) > 0 {
+			fitting = s.Name
+		}
+	}
+	if fitting == "" {
+		return label + " (below small)"
+	}
+	return label + " (" + fitting + ")"
+}
+
+// writeFragmentation writes the fragmentation section and returns a finding
+// and a recommendation, both "" when fragmentation is negligible.
+func writeFragmentation(sb *strings.Builder, frag fragmentation) (string, string) {
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatSubHeader("Fragmentation"))
+	sb.WriteString("\n")
+	if len(frag.Nodes) == 0 {
+		sb.WriteString("  No Ready, schedulable nodes to analyze.\n")
+		return "", ""
+	}
+
+	rows := make([][]string, 0, len(frag.Nodes))
+	for _, r := range frag.Nodes {
+		rows = append(rows, []string{r.Name, fmt.Sprintf("%d", max(r.FreePods, 0)), largestFitLabel(r)})
+	}
+	sb.WriteString(util.FormatTable([]string{"NODE", "FREE POD SLOTS", "LARGEST POD THAT FITS (CPU / MEM)"}, rows))
+	if frag.Skipped > 0 {
+		sb.WriteString(fmt.Sprintf("  %d node(s) not Ready or cordoned are excluded.\n", frag.Skipped))
+	}
+
+	sb.WriteString("\n")
+	rows = rows[:0]
+	for _, sf := range frag.Sizes {
+		rows = append(rows, []string{
+			sf.Size.Name,
+			fmt.Sprintf("%dm / %s", sf.Size.CPU, formatBytes(sf.Size.Mem)),
+			fmt.Sprintf("%d", sf.Fit),
+			fmt.Sprintf("%d", sf.OnPaper),
+			fmt.Sprintf("%.0f%%", sf.StrandedPct()),
+		})
+	}
+	sb.WriteString(util.FormatTable([]string{"POD SIZE", "REQUESTS", "FIT ON NODES", "ON PAPER", "STRANDED"}, rows))
+	sb.WriteString("  (on paper: the cluster's summed free requests divided by the pod size; fit: pods that fit node by node)\n")
+
+	if frag.Typical.Name == "" {
+		return "", ""
+	}
+	typical := frag.Sizes[0]
+	sb.WriteString(fmt.Sprintf("  Free capacity in slivers too small for a typical pod: %dm CPU, %s memory\n", frag.StrandedCPU, formatBytes(frag.StrandedMem)))
+	if typical.OnPaper == 0 || typical.StrandedPct() == 0 {
+		return "", ""
+	}
+	severity := "INFO"
+	if typical.StrandedPct() >= fragmentationWarnPct && typical.OnPaper-typical.Fit >= 2 {
+		severity = "WARNING"
+	}
+	finding := findings.Format("KD-NODE-010", severity, fmt.Sprintf("Free capacity is fragmented: %d typical pods (%dm / %s) fit on paper but only %d fit on actual nodes (%.0f%% stranded in slivers: %dm CPU, %s memory)",
+		typical.OnPaper, frag.Typical.CPU, formatBytes(frag.Typical.Mem), typical.Fit, typical.StrandedPct(), frag.StrandedCPU, formatBytes(frag.StrandedMem)))
+	if severity == "INFO" {
+		return finding, ""
+	}
+	return finding, "Reclaim stranded capacity: balance CPU:memory request ratios to the node shape, consolidate nodes (cluster-autoscaler scale-down or descheduler), or use a node size whose ratio matches the typical pod."
+}
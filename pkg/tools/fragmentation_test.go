@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestAnalyzeFragmentation(t *testing.T) {
+	nodes := []corev1.Node{
+		testNode("n1", "2", "8Gi", nil), testNode("n2", "2", "8Gi", nil), testNode("n3", "2", "8Gi", nil), testNode("n4", "2", "8Gi", nil),
+		testNode("cordoned", "8", "32Gi", nil),
+	}
+	nodes[4].Spec.Unschedulable = true
+	var pods []corev1.Pod
+	for _, n := range []string{"n1", "n2", "n3", "n4"} {
+		for i := 0; i < 3; i++ {
+			pods = append(pods, withRequests(testPod("shop", fmt.Sprintf("web-%s-%d", n, i), n, nil), "500m", "256Mi"))
+		}
+		if n != "n1" {
+			pods = append(pods, withRequests(testPod("shop", "sidecar-"+n, n, nil), "100m", "256Mi"))
+		}
+	}
+
+	frag := analyzeFragmentation(nodes, pods)
+	if len(frag.Nodes) != 4 || frag.Skipped != 1 {
+		t.Fatalf("expected 4 schedulable nodes and 1 skipped, got %d/%d", len(frag.Nodes), frag.Skipped)
+	}
+	if frag.Typical.CPU != 500 || frag.Typical.Mem != 256<<20 {
+		t.Errorf("expected a 500m/256Mi typical pod, got %+v", frag.Typical)
+	}
+	typical := frag.Sizes[0]
+	if typical.OnPaper != 3 || typical.Fit != 1 {
+		t.Errorf("expected 3 typical pods on paper and 1 that fits, got %+v", typical)
+	}
+	if frag.StrandedCPU != 1200 {
+		t.Errorf("expected 1200m stranded CPU, got %dm", frag.StrandedCPU)
+	}
+	if got := largestFitLabel(frag.Nodes[1]); got != "400m / 7.0Gi (small)" {
+		t.Errorf("unexpected largest fit label %q", got)
+	}
+
+	var sb strings.Builder
+	finding, rec := writeFragmentation(&sb, frag)
+	if !strings.Contains(finding, "WARNING") || !strings.Contains(finding, "only 1 fit") || rec == "" {
+		t.Errorf("expected a fragmentation warning and recommendation, got %q / %q", finding, rec)
+	}
+	if !strings.Contains(sb.String(), "1 node(s) not Ready or cordoned are excluded") {
+		t.Errorf("expected the cordoned node to be noted:\n%s", sb.String())
+	}
+}
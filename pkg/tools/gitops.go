@@ -0,0 +1,356 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/flux"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	gitopsFlux = "Flux"
+	gitopsArgo = "Argo CD"
+)
+
+var argoApplicationGVR = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+
+type checkGitOpsStatusInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace holding the Flux or Argo CD objects (empty for all namespaces)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// gitopsObject is a Flux Kustomization/HelmRelease or Argo CD Application
+// reduced to what check_gitops_status reports.
+type gitopsObject struct {
+	Tool      string
+	Kind      string
+	Namespace string
+	Name      string
+	Status    string
+	Revision  string
+	Message   string // last reconcile or sync error
+	Suspended bool
+	Failed    bool
+	Drift     string   // why the cluster is not at the desired revision
+	Resources []string // managed resources that are out of sync or unhealthy
+}
+
+func (o gitopsObject) ref() string {
+	return fmt.Sprintf("%s %s/%s", o.Kind, o.Namespace, o.Name)
+}
+
+func registerGitOpsTools(server *mcp.Server, client *k8s.ClusterClient, fluxClient *flux.FluxClient) {
+	// check_gitops_status
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "check_gitops_status",
+		Description: "Detect Flux (Kustomizations, HelmReleases) and Argo CD (Applications) and report delivery health: failed or stalled reconciles with their last error, objects whose latest revision did not apply (drift), out-of-sync or degraded managed resources, and suspended objects. Use it to tie cluster problems back to the GitOps pipeline.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkGitOpsStatusInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+
+		var objects []gitopsObject
+		var detected []string
+		if fluxClient != nil {
+			fluxObjects, installed, err := collectFluxObjects(ctx, fluxClient, input.Namespace)
+			if err != nil {
+				return handleFluxError("listing Flux objects", err), nil, nil
+			}
+			if installed {
+				detected = append(detected, fmt.Sprintf("%s (%d objects)", gitopsFlux, len(fluxObjects)))
+				objects = append(objects, fluxObjects...)
+			}
+		}
+		argoObjects, installed, err := collectArgoApplications(ctx, client, input.Namespace)
+		if err != nil {
+			return util.HandleK8sError("listing Argo CD Applications", err), nil, nil
+		}
+		if installed {
+			detected = append(detected, fmt.Sprintf("%s (%d Applications)", gitopsArgo, len(argoObjects)))
+			objects = append(objects, argoObjects...)
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("GitOps Status"))
+		if len(detected) == 0 {
+			sb.WriteString("Neither Flux nor Argo CD custom resources were found in this cluster.\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+		sb.WriteString(util.FormatKeyValue("Detected", strings.Join(detected, ", ")))
+		sb.WriteString(util.FormatKeyValue("Namespace", displayNS(input.Namespace)))
+		sb.WriteString("\n")
+
+		var failed, drifted, suspended []gitopsObject
+		for _, o := range objects {
+			switch {
+			case o.Suspended:
+				suspended = append(suspended, o)
+			case o.Failed:
+				failed = append(failed, o)
+			case o.Drift != "" || len(o.Resources) > 0:
+				drifted = append(drifted, o)
+			}
+		}
+
+		var rows [][]string
+		for _, o := range objects {
+			rows = append(rows, []string{o.Tool, o.Kind, o.Namespace + "/" + o.Name, o.Status, truncateRevision(o.Revision)})
+		}
+		sb.WriteString(util.FormatSubHeader("OBJECTS"))
+		sb.WriteString(util.FormatTable([]string{"TOOL", "KIND", "NAME", "STATUS", "REVISION"}, rows))
+		sb.WriteString("\n")
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		if len(failed) > 0 {
+			sb.WriteString(util.FormatSubHeader("FAILED"))
+			for _, o := range failed {
+				sb.WriteString(fmt.Sprintf("  %s [%s]\n", o.ref(), o.Status))
+				if o.Message != "" {
+					sb.WriteString("    Last error: " + truncateName(o.Message, 300) + "\n")
+				}
+				writeGitOpsResources(&sb, o.Resources)
+				findingLines = append(findingLines, findings.Format("KD-GIT-001", "CRITICAL", fmt.Sprintf("%s is %s: %s", o.ref(), o.Status, truncateName(valueOrNone(o.Message), 160))))
+				steps = append(steps, gitopsNextStep(o, fluxClient != nil))
+			}
+			sb.WriteString("\n")
+			actions = append(actions, "Fix the last reconcile error on each failed object; changes merged after it will not reach the cluster until it reconciles")
+		}
+		if len(drifted) > 0 {
+			sb.WriteString(util.FormatSubHeader("OUT OF SYNC"))
+			for _, o := range drifted {
+				sb.WriteString(fmt.Sprintf("  %s [%s]\n", o.ref(), o.Status))
+				if o.Drift != "" {
+					sb.WriteString("    " + o.Drift + "\n")
+				}
+				writeGitOpsResources(&sb, o.Resources)
+				findingLines = append(findingLines, findings.Format("KD-GIT-002", "WARNING", fmt.Sprintf("%s is out of sync with its source%s", o.ref(), gitopsDriftSuffix(o))))
+			}
+			sb.WriteString("\n")
+			actions = append(actions, "Out-of-sync objects mean the cluster differs from Git: sync them, or find who changed the listed resources by hand")
+		}
+		if len(suspended) > 0 {
+			var names []string
+			for _, o := range suspended {
+				names = append(names, o.ref())
+			}
+			sb.WriteString(util.FormatSubHeader("SUSPENDED"))
+			for _, n := range names {
+				sb.WriteString("  " + n + "\n")
+			}
+			sb.WriteString("\n")
+			findingLines = append(findingLines, findings.Format("KD-GIT-003", "INFO", fmt.Sprintf("%d object(s) are suspended and ignore new commits: %s", len(names), joinLimited(names, 5))))
+			actions = append(actions, "Resume suspended objects once the change freeze or incident that required the suspension is over")
+		}
+
+		sb.WriteString("FINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  Every GitOps object is reconciled and in sync with its source.\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// collectFluxObjects lists Flux Kustomizations and HelmReleases. installed is
+// false when the Flux CRDs are missing.
+func collectFluxObjects(ctx context.Context, fluxClient *flux.FluxClient, namespace string) ([]gitopsObject, bool, error) {
+	ns := util.NamespaceOrAll(namespace)
+	kss, err := fluxClient.ListKustomizations(ctx, ns)
+	if err != nil {
+		if fluxCRDsMissing(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	hrs, err := fluxClient.ListHelmReleases(ctx, ns)
+	if err != nil && !fluxCRDsMissing(err) {
+		return nil, false, err
+	}
+
+	objects := make([]gitopsObject, 0, len(kss)+len(hrs))
+	for i := range kss {
+		objects = append(objects, fluxKustomizationObject(&kss[i]))
+	}
+	for i := range hrs {
+		objects = append(objects, fluxHelmReleaseObject(&hrs[i]))
+	}
+	return objects, true, nil
+}
+
+func fluxKustomizationObject(ks *kustomizev1.Kustomization) gitopsObject {
+	health := flux.KustomizationHealth(ks)
+	o := gitopsObject{
+		Tool:      gitopsFlux,
+		Kind:      "Kustomization",
+		Namespace: ks.Namespace,
+		Name:      ks.Name,
+		Status:    string(health),
+		Revision:  ks.Status.LastAppliedRevision,
+		Suspended: health == flux.HealthSuspended,
+		Failed:    health == flux.HealthFailed || health == flux.HealthStalled,
+	}
+	if o.Failed {
+		o.Message = flux.GetConditionMessage(ks.Status.Conditions, fluxmeta.ReadyCondition)
+	}
+	if attempted := ks.Status.LastAttemptedRevision; attempted != "" && attempted != ks.Status.LastAppliedRevision {
+		o.Drift = fmt.Sprintf("Revision %s was attempted but the cluster is still at %s", truncateRevision(attempted), truncateRevision(ks.Status.LastAppliedRevision))
+	}
+	return o
+}
+
+func fluxHelmReleaseObject(hr *helmv2.HelmRelease) gitopsObject {
+	health := flux.HelmReleaseHealth(hr)
+	o := gitopsObject{
+		Tool:      gitopsFlux,
+		Kind:      "HelmRelease",
+		Namespace: hr.Namespace,
+		Name:      hr.Name,
+		Status:    string(health),
+		Suspended: health == flux.HealthSuspended,
+		Failed:    health == flux.HealthFailed || health == flux.HealthStalled,
+	}
+	latest := hr.Status.History.Latest()
+	if latest != nil {
+		o.Revision = latest.ChartVersion
+	}
+	if o.Failed {
+		o.Message = flux.GetConditionMessage(hr.Status.Conditions, fluxmeta.ReadyCondition)
+		if hr.Status.Failures > 0 {
+			o.Message = fmt.Sprintf("%s (%d failures)", o.Message, hr.Status.Failures)
+		}
+	}
+	if attempted := hr.Status.LastAttemptedRevision; attempted != "" && latest != nil && attempted != latest.ChartVersion {
+		o.Drift = fmt.Sprintf("Chart version %s was attempted but the release is still at %s", attempted, latest.ChartVersion)
+	}
+	return o
+}
+
+// collectArgoApplications lists Argo CD Applications. installed is false when
+// the Application CRD is missing.
+func collectArgoApplications(ctx context.Context, client *k8s.ClusterClient, namespace string) ([]gitopsObject, bool, error) {
+	items, err := client.ListCustomResources(ctx, argoApplicationGVR, namespace, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	sortUnstructured(items)
+	objects := make([]gitopsObject, 0, len(items))
+	for i := range items {
+		objects = append(objects, argoApplicationObject(&items[i]))
+	}
+	return objects, true, nil
+}
+
+func argoApplicationObject(u *unstructured.Unstructured) gitopsObject {
+	str := func(fields ...string) string {
+		s, _, _ := unstructured.NestedString(u.Object, fields...)
+		return s
+	}
+	sync := valueOrNone(str("status", "sync", "status"))
+	health := valueOrNone(str("status", "health", "status"))
+	o := gitopsObject{
+		Tool:      gitopsArgo,
+		Kind:      "Application",
+		Namespace: u.GetNamespace(),
+		Name:      u.GetName(),
+		Status:    sync + "/" + health,
+		Revision:  str("status", "sync", "revision"),
+		Suspended: health == "Suspended",
+		Failed:    health == "Degraded" || health == "Missing",
+	}
+
+	if phase := str("status", "operationState", "phase"); phase == "Failed" || phase == "Error" {
+		o.Failed = true
+		o.Message = "Sync " + strings.ToLower(phase) + ": " + str("status", "operationState", "message")
+	}
+	for _, c := range resourceConditions(u) {
+		if strings.HasSuffix(c.Type, "Error") {
+			o.Failed = true
+			if o.Message == "" {
+				o.Message = c.Type + ": " + c.Message
+			}
+		}
+	}
+
+	resources, _, _ := unstructured.NestedSlice(u.Object, "status", "resources")
+	for _, r := range resources {
+		m, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		state := stringField(m, "status")
+		if h, ok := m["health"].(map[string]any); ok {
+			if hs := stringField(h, "status"); hs == "Degraded" || hs == "Missing" {
+				state = hs
+			}
+		}
+		if state != "OutOfSync" && state != "Degraded" && state != "Missing" {
+			continue
+		}
+		name := stringField(m, "name")
+		if ns := stringField(m, "namespace"); ns != "" {
+			name = ns + "/" + name
+		}
+		o.Resources = append(o.Resources, fmt.Sprintf("%s %s (%s)", stringField(m, "kind"), name, state))
+	}
+
+	if sync == "OutOfSync" {
+		o.Drift = "Live state differs from Git"
+		if _, automated, _ := unstructured.NestedMap(u.Object, "spec", "syncPolicy", "automated"); !automated {
+			o.Drift += "; automated sync is off, so it stays that way until someone syncs"
+		}
+	}
+	return o
+}
+
+func writeGitOpsResources(sb *strings.Builder, resources []string) {
+	const limit = 10
+	for i, r := range resources {
+		if i == limit {
+			sb.WriteString(fmt.Sprintf("    ... and %d more resources\n", len(resources)-limit))
+			break
+		}
+		sb.WriteString("    - " + r + "\n")
+	}
+}
+
+func gitopsDriftSuffix(o gitopsObject) string {
+	if len(o.Resources) > 0 {
+		return fmt.Sprintf(" (%d resource(s): %s)", len(o.Resources), joinLimited(o.Resources, 3))
+	}
+	return ""
+}
+
+// gitopsNextStep points at the tool that digs into one failed object.
+func gitopsNextStep(o gitopsObject, fluxTools bool) util.NextStep {
+	switch {
+	case fluxTools && o.Kind == "Kustomization":
+		return nextStep("diagnose_flux_kustomization", "Kustomization is "+o.Status, "namespace", o.Namespace, "name", o.Name)
+	case fluxTools && o.Kind == "HelmRelease":
+		return nextStep("diagnose_flux_helm_release", "HelmRelease is "+o.Status, "namespace", o.Namespace, "name", o.Name)
+	}
+	return nextStep("list_custom_resources", o.Kind+" is "+o.Status, "kind", o.Kind, "namespace", o.Namespace)
+}
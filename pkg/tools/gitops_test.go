@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2"
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	fluxmeta "github.com/fluxcd/pkg/apis/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFluxGitOpsObjects(t *testing.T) {
+	ks := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "flux-system", Generation: 2},
+		Status: kustomizev1.KustomizationStatus{
+			ObservedGeneration:    2,
+			LastAppliedRevision:   "main@sha1:1111111111111111",
+			LastAttemptedRevision: "main@sha1:2222222222222222",
+			Conditions: []metav1.Condition{{
+				Type: fluxmeta.ReadyCondition, Status: metav1.ConditionFalse, Reason: "ReconciliationFailed",
+				Message: "Deployment/shop/web dry-run failed: field is immutable",
+			}},
+		},
+	}
+	o := fluxKustomizationObject(ks)
+	if !o.Failed || !strings.Contains(o.Message, "immutable") {
+		t.Errorf("expected failed Kustomization with its Ready message, got %+v", o)
+	}
+	if !strings.Contains(o.Drift, "main@sha1:222222222222") || !strings.Contains(o.Drift, "still at main@sha1:111111111111") {
+		t.Errorf("expected drift between attempted and applied revisions, got %q", o.Drift)
+	}
+
+	hr := &helmv2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "redis", Namespace: "cache", Generation: 1},
+		Spec:       helmv2.HelmReleaseSpec{Suspend: true},
+		Status: helmv2.HelmReleaseStatus{
+			ObservedGeneration:    1,
+			LastAttemptedRevision: "18.1.0",
+			History:               helmv2.Snapshots{{ChartVersion: "18.0.2"}},
+		},
+	}
+	o = fluxHelmReleaseObject(hr)
+	if !o.Suspended || o.Failed || o.Revision != "18.0.2" {
+		t.Errorf("expected suspended HelmRelease at 18.0.2, got %+v", o)
+	}
+	if !strings.Contains(o.Drift, "18.1.0") {
+		t.Errorf("expected chart version drift, got %q", o.Drift)
+	}
+}
+
+func TestArgoApplicationObject(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"namespace": "argocd", "name": "shop"},
+		"spec":     map[string]any{"syncPolicy": map[string]any{}},
+		"status": map[string]any{
+			"sync":   map[string]any{"status": "OutOfSync", "revision": "abc123def456789"},
+			"health": map[string]any{"status": "Degraded"},
+			"operationState": map[string]any{
+				"phase":   "Failed",
+				"message": "one or more objects failed to apply",
+			},
+			"resources": []any{
+				map[string]any{"kind": "Deployment", "namespace": "shop", "name": "web", "status": "Synced", "health": map[string]any{"status": "Degraded"}},
+				map[string]any{"kind": "ConfigMap", "namespace": "shop", "name": "web-config", "status": "OutOfSync"},
+				map[string]any{"kind": "Service", "namespace": "shop", "name": "web", "status": "Synced", "health": map[string]any{"status": "Healthy"}},
+			},
+		},
+	}}
+
+	o := argoApplicationObject(u)
+	if o.Status != "OutOfSync/Degraded" || !o.Failed {
+		t.Errorf("expected failed OutOfSync/Degraded application, got %+v", o)
+	}
+	if !strings.HasPrefix(o.Message, "Sync failed: one or more") {
+		t.Errorf("expected the failed sync operation message, got %q", o.Message)
+	}
+	if len(o.Resources) != 2 || o.Resources[0] != "Deployment shop/web (Degraded)" || o.Resources[1] != "ConfigMap shop/web-config (OutOfSync)" {
+		t.Errorf("unexpected unhealthy resources: %v", o.Resources)
+	}
+	if !strings.Contains(o.Drift, "automated sync is off") {
+		t.Errorf("expected manual sync to be called out, got %q", o.Drift)
+	}
+}
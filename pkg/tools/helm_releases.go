@@ -0,0 +1,348 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// helmPendingStuckAfter is how long a release may sit in a pending-* status
+	// before it is reported as stuck rather than in progress.
+	helmPendingStuckAfter = 15 * time.Minute
+	// helmValueDisplayLen caps how much of a single changed value is printed.
+	helmValueDisplayLen = 80
+)
+
+type listHelmReleasesInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces)"`
+	Limit          int64  `json:"limit,omitempty" jsonschema:"Maximum release secrets (one per revision) read per page (default 200, max 1000)"`
+	ContinueToken  string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+type getHelmReleaseDetailInput struct {
+	Namespace      string `json:"namespace" jsonschema:"required,Namespace the release is installed in"`
+	Name           string `json:"name" jsonschema:"required,Helm release name"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// helmValueChange is one user-supplied value that differs between two revisions.
+type helmValueChange struct {
+	Key string
+	Old string // "" when added
+	New string // "" when removed
+}
+
+func registerHelmTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// list_helm_releases
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_helm_releases",
+		Description: "List Helm v3 releases read from their release Secrets (no helm binary needed): chart and app version, latest revision and status, and when it was last deployed. Flags failed releases and releases stuck in pending-install/upgrade/rollback, which block further helm upgrades.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input listHelmReleasesInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		page, skipped, err := client.ListHelmReleaseRevisionsPage(ctx, input.Namespace, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
+		if err != nil {
+			return util.HandleK8sError("listing Helm release secrets", err), nil, nil
+		}
+		histories := groupHelmReleases(page.Items)
+		now := time.Now()
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Helm Releases (namespace: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n")
+		if len(histories) == 0 {
+			sb.WriteString("No Helm v3 releases found.\n")
+			sb.WriteString(pageFooter("list_helm_releases", page.Continue, page.Remaining))
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		var rows [][]string
+		var findingLines, actions []string
+		var steps []util.NextStep
+		for _, h := range histories {
+			latest := h[len(h)-1]
+			rows = append(rows, []string{
+				latest.Namespace, latest.Name, fmt.Sprintf("%d", latest.Revision),
+				latest.Chart + "-" + latest.ChartVersion, valueOrNone(latest.AppVersion),
+				latest.Status, util.FormatAge(latest.LastDeployed),
+			})
+			if severity, msg, action := helmReleaseProblem(h, now); severity != "" {
+				findingLines = append(findingLines, findings.Format("KD-WL-014", severity, msg))
+				actions = append(actions, action)
+				steps = append(steps, nextStep("get_helm_release_detail", fmt.Sprintf("Release is %s", latest.Status), "namespace", latest.Namespace, "name", latest.Name))
+			}
+		}
+		sb.WriteString(util.FormatTable([]string{"NAMESPACE", "NAME", "REVISION", "CHART", "APP VERSION", "STATUS", "UPDATED"}, rows))
+		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("releases", len(histories))))
+		if skipped > 0 {
+			sb.WriteString(fmt.Sprintf("(%d release secret(s) could not be decoded and were skipped)\n", skipped))
+		}
+
+		if len(findingLines) > 0 {
+			sb.WriteString("\nFINDINGS:\n")
+			for _, f := range findingLines {
+				sb.WriteString("  " + f + "\n")
+			}
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+		sb.WriteString(pageFooter("list_helm_releases", page.Continue, page.Remaining))
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+
+	// get_helm_release_detail
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_helm_release_detail",
+		Description: "Show one Helm v3 release: revision history with statuses and descriptions, the resources in the current manifest, and a diff of user-supplied values between the last two revisions (credential-looking values are redacted). Answers \"what changed in the last upgrade\".",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input getHelmReleaseDetailInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if input.Namespace == "" || input.Name == "" {
+			return util.ErrorResult("namespace and name are required"), nil, nil
+		}
+		history, _, err := client.ListHelmReleaseRevisions(ctx, input.Namespace, input.Name)
+		if err != nil {
+			return util.HandleK8sError(fmt.Sprintf("listing Helm release %s/%s", input.Namespace, input.Name), err), nil, nil
+		}
+		if len(history) == 0 {
+			return util.ErrorResult("no Helm v3 release %q found in namespace %q (use list_helm_releases)", input.Name, input.Namespace), nil, nil
+		}
+		latest := history[len(history)-1]
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Helm Release: %s/%s", latest.Namespace, latest.Name)))
+		sb.WriteString(util.FormatKeyValue("Chart", latest.Chart+"-"+latest.ChartVersion))
+		sb.WriteString(util.FormatKeyValue("App Version", valueOrNone(latest.AppVersion)))
+		sb.WriteString(util.FormatKeyValue("Revision", fmt.Sprintf("%d", latest.Revision)))
+		sb.WriteString(util.FormatKeyValue("Status", latest.Status))
+		sb.WriteString(util.FormatKeyValue("First Deployed", formatHelmTime(latest.FirstDeployed)))
+		sb.WriteString(util.FormatKeyValue("Last Deployed", formatHelmTime(latest.LastDeployed)))
+		sb.WriteString("\n")
+
+		sb.WriteString(util.FormatSubHeader("HISTORY"))
+		var rows [][]string
+		for i := len(history) - 1; i >= 0; i-- {
+			r := history[i]
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", r.Revision), r.Status, r.Chart + "-" + r.ChartVersion,
+				valueOrNone(r.AppVersion), util.FormatAge(r.LastDeployed), truncateName(r.Description, 80),
+			})
+		}
+		sb.WriteString(util.FormatTable([]string{"REVISION", "STATUS", "CHART", "APP VERSION", "UPDATED", "DESCRIPTION"}, rows))
+		sb.WriteString("\n")
+
+		if len(history) > 1 {
+			prev := history[len(history)-2]
+			sb.WriteString(util.FormatSubHeader(fmt.Sprintf("CHANGES: REVISION %d -> %d", prev.Revision, latest.Revision)))
+			if prev.ChartVersion != latest.ChartVersion || prev.Chart != latest.Chart {
+				sb.WriteString(fmt.Sprintf("  Chart: %s-%s -> %s-%s\n", prev.Chart, prev.ChartVersion, latest.Chart, latest.ChartVersion))
+			}
+			if prev.AppVersion != latest.AppVersion {
+				sb.WriteString(fmt.Sprintf("  App version: %s -> %s\n", valueOrNone(prev.AppVersion), valueOrNone(latest.AppVersion)))
+			}
+			changes := diffHelmValues(prev.Values, latest.Values)
+			if len(changes) == 0 {
+				sb.WriteString("  User-supplied values are unchanged.\n")
+			}
+			for _, c := range changes {
+				switch {
+				case c.Old == "":
+					sb.WriteString(fmt.Sprintf("  + %s: %s\n", c.Key, c.New))
+				case c.New == "":
+					sb.WriteString(fmt.Sprintf("  - %s: %s\n", c.Key, c.Old))
+				default:
+					sb.WriteString(fmt.Sprintf("  ~ %s: %s -> %s\n", c.Key, c.Old, c.New))
+				}
+			}
+			sb.WriteString("\n")
+		}
+
+		resources := manifestResources(latest.Manifest)
+		sb.WriteString(util.FormatSubHeader(fmt.Sprintf("RESOURCES (%d)", len(resources))))
+		for _, r := range resources {
+			sb.WriteString("  " + r + "\n")
+		}
+		sb.WriteString("\n")
+
+		sb.WriteString("FINDINGS:\n")
+		severity, msg, action := helmReleaseProblem(history, time.Now())
+		if severity == "" {
+			sb.WriteString(fmt.Sprintf("  Release is %s at revision %d.\n", latest.Status, latest.Revision))
+		} else {
+			sb.WriteString("  " + findings.Format("KD-WL-014", severity, msg) + "\n")
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			sb.WriteString("  1. " + action + "\n")
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// groupHelmReleases splits revisions sorted by namespace, name, and revision
+// into one history per release.
+func groupHelmReleases(revisions []k8s.HelmRelease) [][]k8s.HelmRelease {
+	var out [][]k8s.HelmRelease
+	for i, r := range revisions {
+		if i == 0 || r.Namespace != revisions[i-1].Namespace || r.Name != revisions[i-1].Name {
+			out = append(out, nil)
+		}
+		out[len(out)-1] = append(out[len(out)-1], r)
+	}
+	return out
+}
+
+// lastDeployedRevision returns the newest revision in status deployed, or nil.
+func lastDeployedRevision(history []k8s.HelmRelease) *k8s.HelmRelease {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Status == "deployed" {
+			return &history[i]
+		}
+	}
+	return nil
+}
+
+// helmReleaseProblem returns a finding and action for a release whose latest
+// revision failed, is stuck pending, or is being uninstalled. severity is ""
+// for a healthy release.
+func helmReleaseProblem(history []k8s.HelmRelease, now time.Time) (severity, message, action string) {
+	latest := history[len(history)-1]
+	ref := latest.Namespace + "/" + latest.Name
+	deployed := lastDeployedRevision(history)
+	rollback := fmt.Sprintf("helm rollback %s -n %s", latest.Name, latest.Namespace)
+	if deployed != nil {
+		rollback = fmt.Sprintf("helm rollback %s %d -n %s", latest.Name, deployed.Revision, latest.Namespace)
+	}
+
+	switch {
+	case latest.Status == "failed":
+		message = fmt.Sprintf("Helm release %s revision %d failed: %s", ref, latest.Revision, valueOrNone(latest.Description))
+		if deployed != nil {
+			message += fmt.Sprintf(" (revision %d, %s-%s, is still deployed)", deployed.Revision, deployed.Chart, deployed.ChartVersion)
+		}
+		return "CRITICAL", message, fmt.Sprintf("Fix the cause in the failure description and upgrade %s again, or roll back with `%s`", ref, rollback)
+	case strings.HasPrefix(latest.Status, "pending-"):
+		age := now.Sub(latest.LastDeployed)
+		if latest.LastDeployed.IsZero() || age < helmPendingStuckAfter {
+			return "INFO", fmt.Sprintf("Helm release %s is %s (revision %d)", ref, latest.Status, latest.Revision), fmt.Sprintf("Wait for the %s operation on %s to finish", latest.Status, ref)
+		}
+		return "CRITICAL", fmt.Sprintf("Helm release %s has been stuck in %s for %s; every further helm upgrade fails with \"another operation is in progress\"", ref, latest.Status, util.FormatAge(latest.LastDeployed)),
+			fmt.Sprintf("Unblock %s with `%s`, or delete the pending release secret sh.helm.release.v1.%s.v%d if no rollback target exists", ref, rollback, latest.Name, latest.Revision)
+	case latest.Status == "uninstalling":
+		return "WARNING", fmt.Sprintf("Helm release %s is stuck uninstalling (revision %d)", ref, latest.Revision), fmt.Sprintf("Check for resources of %s blocked by finalizers, then rerun helm uninstall", ref)
+	}
+	return "", "", ""
+}
+
+// diffHelmValues compares two user-supplied values trees leaf by leaf.
+// Lists are compared whole. Values under credential-looking keys are redacted.
+func diffHelmValues(old, new map[string]any) []helmValueChange {
+	before := make(map[string]string)
+	after := make(map[string]string)
+	flattenHelmValues("", old, before)
+	flattenHelmValues("", new, after)
+
+	keys := make(map[string]bool)
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	var changes []helmValueChange
+	for _, k := range sortedKeys(keys) {
+		o, n := before[k], after[k]
+		if o == n {
+			continue
+		}
+		if leaf := k[strings.LastIndex(k, ".")+1:]; util.IsSensitive(leaf, o) || util.IsSensitive(leaf, n) {
+			if o != "" {
+				o = "<redacted>"
+			}
+			if n != "" {
+				n = "<redacted>"
+			}
+			if o == n {
+				n = "<redacted, changed>"
+			}
+		}
+		changes = append(changes, helmValueChange{Key: k, Old: o, New: n})
+	}
+	return changes
+}
+
+// flattenHelmValues writes each leaf of a values tree as dotted key -> JSON value.
+func flattenHelmValues(prefix string, v any, out map[string]string) {
+	if m, ok := v.(map[string]any); ok {
+		for k, child := range m {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenHelmValues(key, child, out)
+		}
+		return
+	}
+	if prefix == "" {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		b = []byte(fmt.Sprintf("%v", v))
+	}
+	out[prefix] = truncateName(string(b), helmValueDisplayLen)
+}
+
+// manifestResources lists "Kind namespace/name" for each object in a rendered
+// Helm manifest.
+func manifestResources(manifest string) []string {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	var out []string
+	for {
+		var obj struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		if err := decoder.Decode(&obj); err != nil {
+			if !errors.Is(err, io.EOF) {
+				out = append(out, "(rest of manifest could not be parsed)")
+			}
+			break
+		}
+		if obj.Kind == "" {
+			continue
+		}
+		name := obj.Metadata.Name
+		if obj.Metadata.Namespace != "" {
+			name = obj.Metadata.Namespace + "/" + name
+		}
+		out = append(out, obj.Kind+" "+name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func formatHelmTime(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return fmt.Sprintf("%s (%s ago)", t.UTC().Format(time.RFC3339), util.FormatAge(t))
+}
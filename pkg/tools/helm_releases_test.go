@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+func TestDiffHelmValues(t *testing.T) {
+	old := map[string]any{
+		"replicaCount": float64(2),
+		"image":        map[string]any{"tag": "1.4.0", "pullPolicy": "IfNotPresent"},
+		"auth":         map[string]any{"password": "hunter2"},
+		"legacy":       true,
+	}
+	new := map[string]any{
+		"replicaCount": float64(3),
+		"image":        map[string]any{"tag": "1.5.0", "pullPolicy": "IfNotPresent"},
+		"auth":         map[string]any{"password": "correct-horse"},
+		"ingress":      map[string]any{"enabled": true},
+	}
+
+	changes := diffHelmValues(old, new)
+	got := make(map[string]helmValueChange)
+	for _, c := range changes {
+		got[c.Key] = c
+	}
+	if len(changes) != 5 {
+		t.Fatalf("expected 5 changes, got %+v", changes)
+	}
+	if c := got["image.tag"]; c.Old != `"1.4.0"` || c.New != `"1.5.0"` {
+		t.Errorf("unexpected image.tag change: %+v", c)
+	}
+	if c := got["legacy"]; c.New != "" || c.Old != "true" {
+		t.Errorf("expected legacy to be removed, got %+v", c)
+	}
+	if c := got["ingress.enabled"]; c.Old != "" || c.New != "true" {
+		t.Errorf("expected ingress.enabled to be added, got %+v", c)
+	}
+	for _, c := range changes {
+		if strings.Contains(c.Old+c.New, "hunter2") || strings.Contains(c.Old+c.New, "correct-horse") {
+			t.Errorf("password leaked in diff: %+v", c)
+		}
+	}
+	if c := got["auth.password"]; c.New != "<redacted, changed>" {
+		t.Errorf("expected redacted password change, got %+v", c)
+	}
+}
+
+func TestHelmReleaseProblem(t *testing.T) {
+	now := time.Now()
+	history := []k8s.HelmRelease{
+		{Namespace: "cache", Name: "redis", Revision: 4, Status: "deployed", Chart: "redis", ChartVersion: "18.0.2"},
+		{Namespace: "cache", Name: "redis", Revision: 5, Status: "failed", Description: "timed out waiting for the condition"},
+	}
+	severity, msg, action := helmReleaseProblem(history, now)
+	if severity != "CRITICAL" || !strings.Contains(msg, "revision 4, redis-18.0.2, is still deployed") || !strings.Contains(action, "helm rollback redis 4 -n cache") {
+		t.Errorf("unexpected failed release finding: %s %q %q", severity, msg, action)
+	}
+
+	history[1].Status = "pending-upgrade"
+	history[1].LastDeployed = now.Add(-time.Hour)
+	severity, msg, _ = helmReleaseProblem(history, now)
+	if severity != "CRITICAL" || !strings.Contains(msg, "another operation is in progress") {
+		t.Errorf("expected stuck pending-upgrade, got %s %q", severity, msg)
+	}
+
+	history[1].LastDeployed = now.Add(-time.Minute)
+	if severity, _, _ = helmReleaseProblem(history, now); severity != "INFO" {
+		t.Errorf("expected a recent pending-upgrade to be INFO, got %s", severity)
+	}
+
+	history[1].Status = "deployed"
+	if severity, _, _ = helmReleaseProblem(history, now); severity != "" {
+		t.Errorf("expected no finding for a deployed release, got %s", severity)
+	}
+}
+
+func TestManifestResources(t *testing.T) {
+	manifest := `---
+# Source: redis/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: redis
+  namespace: cache
+---
+# Source: redis/templates/empty.yaml
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: redis-reader
+`
+	got := manifestResources(manifest)
+	if len(got) != 2 || got[0] != "ClusterRole redis-reader" || got[1] != "Service cache/redis" {
+		t.Errorf("unexpected manifest resources: %v", got)
+	}
+}
+
+func TestGroupHelmReleases(t *testing.T) {
+	groups := groupHelmReleases([]k8s.HelmRelease{
+		{Namespace: "a", Name: "x", Revision: 1},
+		{Namespace: "a", Name: "x", Revision: 2},
+		{Namespace: "b", Name: "x", Revision: 1},
+	})
+	if len(groups) != 2 || len(groups[0]) != 2 || groups[1][0].Namespace != "b" {
+		t.Errorf("unexpected grouping: %+v", groups)
+	}
+}
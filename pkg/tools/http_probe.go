@@ -0,0 +1,217 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// httpProbeDefaultTimeout and httpProbeMaxTimeout bound a probe,
+	// including port-forward setup, in seconds.
+	httpProbeDefaultTimeout = 10
+	httpProbeMaxTimeout     = 30
+
+	// httpProbeBodyBytes is how much of the response body is shown.
+	httpProbeBodyBytes = 1024
+
+	// httpProbeSlow is the latency above which a response is flagged.
+	httpProbeSlow = time.Second
+)
+
+type probeServiceHTTPInput struct {
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Service        string `json:"service,omitempty" jsonschema:"Service to probe; a ready backend pod is chosen and the Service's target port resolved on it"`
+	Pod            string `json:"pod,omitempty" jsonschema:"Pod to probe directly instead of a Service"`
+	Port           int    `json:"port,omitempty" jsonschema:"Service port, or container port when probing a pod (default: the first port)"`
+	Path           string `json:"path,omitempty" jsonschema:"HTTP path to GET, with optional query string (default /)"`
+	Host           string `json:"host,omitempty" jsonschema:"Host header to send, for apps that route by virtual host"`
+	HTTPS          bool   `json:"https,omitempty" jsonschema:"Speak TLS to the pod (the certificate is not verified)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Probe timeout in seconds, including port-forward setup (default 10, max 30)"`
+}
+
+func registerHTTPProbeTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "probe_service_http",
+		Description: "Port-forward to a Service's backend pod (or a given pod) through the API server and send an HTTP GET to a path, returning the status code, " +
+			"latency, and the start of the body. Validates the application layer that endpoint checks cannot see: a pod can be Ready and still return 500s or 404s. " +
+			"Needs RBAC create on pods/portforward. Only available when the server runs with --enable-exec.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input probeServiceHTTPInput) (*mcp.CallToolResult, any, error) {
+		if input.Namespace == "" || (input.Service == "") == (input.Pod == "") {
+			return util.ErrorResult("namespace and exactly one of service or pod are required"), nil, nil
+		}
+		path := input.Path
+		if path == "" {
+			path = "/"
+		}
+		timeout := input.TimeoutSeconds
+		if timeout <= 0 {
+			timeout = httpProbeDefaultTimeout
+		}
+		timeout = min(timeout, httpProbeMaxTimeout)
+
+		var pod *corev1.Pod
+		var port int32
+		target := "pod/" + input.Pod
+		if input.Service != "" {
+			target = "service/" + input.Service
+			svc, err := client.GetService(ctx, input.Namespace, input.Service)
+			if err != nil {
+				return util.HandleK8sError(fmt.Sprintf("getting service %s/%s", input.Namespace, input.Service), err), nil, nil
+			}
+			pods, err := client.GetPodsForService(ctx, svc)
+			if err != nil {
+				return util.HandleK8sError("listing backend pods", err), nil, nil
+			}
+			if pod, port, err = serviceProbeTarget(svc, pods, int32(input.Port)); err != nil {
+				return util.ErrorResult("cannot probe service %s/%s: %v", input.Namespace, input.Service, err), nil, nil
+			}
+		} else {
+			p, err := client.GetPod(ctx, input.Namespace, input.Pod)
+			if err != nil {
+				return util.HandleK8sError(fmt.Sprintf("getting pod %s/%s", input.Namespace, input.Pod), err), nil, nil
+			}
+			if p.Status.Phase != corev1.PodRunning {
+				return util.ErrorResult("pod %s/%s is %s; port-forward needs a Running pod", input.Namespace, input.Pod, p.Status.Phase), nil, nil
+			}
+			pod, port = p, int32(input.Port)
+			if port == 0 {
+				if port = firstContainerPort(p, corev1.ProtocolTCP); port == 0 {
+					return util.ErrorResult("pod %s/%s declares no container ports; pass port", input.Namespace, input.Pod), nil, nil
+				}
+			}
+		}
+
+		probeCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+		res, probeErr := client.ProbePodHTTP(probeCtx, pod.Namespace, pod.Name, k8s.HTTPProbe{
+			Port: port, Path: path, Host: input.Host, HTTPS: input.HTTPS, MaxBody: httpProbeBodyBytes,
+		})
+
+		scheme := "http"
+		if input.HTTPS {
+			scheme = "https"
+		}
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("HTTP Probe: GET %s on %s/%s", path, input.Namespace, target)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Pod", fmt.Sprintf("%s (port %d, %s)", pod.Name, port, scheme)) + "\n")
+		if input.Host != "" {
+			sb.WriteString(util.FormatKeyValue("Host", input.Host) + "\n")
+		}
+		if probeErr == nil {
+			sb.WriteString(util.FormatKeyValue("Status", res.Status) + "\n")
+			sb.WriteString(util.FormatKeyValue("Latency", res.Latency.Round(time.Millisecond).String()) + "\n")
+			if ct := res.Header.Get("Content-Type"); ct != "" {
+				sb.WriteString(util.FormatKeyValue("Content-Type", ct) + "\n")
+			}
+			if loc := res.Header.Get("Location"); loc != "" {
+				sb.WriteString(util.FormatKeyValue("Location", loc) + "\n")
+			}
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("Body"))
+			sb.WriteString("\n")
+			body := strings.TrimRight(res.Body, "\n")
+			if body == "" {
+				body = "<empty>"
+			}
+			sb.WriteString(body + "\n")
+			if res.BodyTruncated {
+				sb.WriteString(fmt.Sprintf("... [body truncated at %d bytes]\n", httpProbeBodyBytes))
+			}
+		}
+
+		severity, message, action := httpProbeVerdict(res, probeErr, port)
+		sb.WriteString("\nFINDINGS:\n")
+		if severity == "OK" {
+			sb.WriteString(util.FormatFinding(severity, message))
+		} else {
+			sb.WriteString(findings.Format("KD-NET-005", severity, message))
+		}
+		sb.WriteString("\n")
+		var steps []util.NextStep
+		if action != "" {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			sb.WriteString(fmt.Sprintf("  1. %s\n", action))
+			steps = append(steps, nextStep("get_pod_logs", "see how the app handled the request", "namespace", pod.Namespace, "name", pod.Name))
+			if input.Service != "" {
+				steps = append(steps, nextStep("diagnose_service", "check the Service's other backends and endpoints", "namespace", input.Namespace, "service_name", input.Service))
+			}
+		}
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// serviceProbeTarget picks a ready backend pod of svc and resolves the
+// Service port (0 for the first) to the container port on that pod.
+func serviceProbeTarget(svc *corev1.Service, pods []corev1.Pod, port int32) (*corev1.Pod, int32, error) {
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return nil, 0, fmt.Errorf("ExternalName services have no pods")
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return nil, 0, fmt.Errorf("the service has no selector; probe one of its pods with pod instead")
+	}
+	if len(svc.Spec.Ports) == 0 {
+		return nil, 0, fmt.Errorf("the service exposes no ports")
+	}
+	sp := &svc.Spec.Ports[0]
+	if port != 0 {
+		sp = nil
+		for i := range svc.Spec.Ports {
+			if svc.Spec.Ports[i].Port == port {
+				sp = &svc.Spec.Ports[i]
+			}
+		}
+		if sp == nil {
+			ports := make([]string, 0, len(svc.Spec.Ports))
+			for _, p := range svc.Spec.Ports {
+				ports = append(ports, strconv.Itoa(int(p.Port)))
+			}
+			return nil, 0, fmt.Errorf("no service port %d (ports: %s)", port, strings.Join(ports, ", "))
+		}
+	}
+	for i := range pods {
+		p := &pods[i]
+		if p.DeletionTimestamp != nil || p.Status.Phase != corev1.PodRunning || !isPodHealthy(p) {
+			continue
+		}
+		target := serviceTargetPort(sp, p)
+		if target == 0 {
+			continue
+		}
+		return p, target, nil
+	}
+	return nil, 0, fmt.Errorf("no ready backend pod serves port %d (%d pod(s) match the selector)", sp.Port, len(pods))
+}
+
+// httpProbeVerdict rates a probe and suggests what to check when it failed.
+func httpProbeVerdict(res *k8s.HTTPProbeResult, err error, port int32) (severity, message, action string) {
+	switch {
+	case err != nil:
+		return "CRITICAL", fmt.Sprintf("No HTTP response on port %d: %v", port, err),
+			fmt.Sprintf("Confirm the app listens on port %d on all interfaces (not 127.0.0.1) and speaks the expected protocol (try https=true for TLS)", port)
+	case res.StatusCode >= 500:
+		return "CRITICAL", fmt.Sprintf("The app returned %s", res.Status),
+			"Check the pod logs for the error behind the 5xx response"
+	case res.StatusCode == 401 || res.StatusCode == 403:
+		return "INFO", fmt.Sprintf("The app responded but requires authentication (%s)", res.Status), ""
+	case res.StatusCode >= 400:
+		return "WARNING", fmt.Sprintf("The app returned %s for this path", res.Status),
+			"Check the path and Host header against the app's routes; Ingress path rewrites can change the path the app sees"
+	case res.StatusCode >= 300:
+		return "INFO", fmt.Sprintf("The app redirected (%s) to %s", res.Status, valueOrNone(res.Header.Get("Location"))), ""
+	case res.Latency > httpProbeSlow:
+		return "WARNING", fmt.Sprintf("The app returned %s but took %s", res.Status, res.Latency.Round(time.Millisecond)),
+			"Check the app's resource usage and downstream dependencies for the slow response"
+	}
+	return "OK", fmt.Sprintf("The app returned %s in %s", res.Status, res.Latency.Round(time.Millisecond)), ""
+}
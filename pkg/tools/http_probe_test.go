@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+func TestServiceProbeTarget(t *testing.T) {
+	ready := func(name string) corev1.Pod {
+		p := withReady(testPod("shop", name, "", nil), true)
+		p.Spec.Containers[0].Ports = []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}, {Name: "metrics", ContainerPort: 9090}}
+		return p
+	}
+	pending := ready("web-pending")
+	pending.Status.Phase = corev1.PodPending
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "web"},
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+				{Name: "metrics", Port: 9090, TargetPort: intstr.FromInt32(9090)},
+			},
+		},
+	}
+
+	// Default port: the first service port, resolved by name on the first ready pod
+	pod, port, err := serviceProbeTarget(svc, []corev1.Pod{pending, ready("web-1")}, 0)
+	if err != nil || pod.Name != "web-1" || port != 8080 {
+		t.Errorf("expected web-1:8080, got %v:%d (%v)", pod, port, err)
+	}
+
+	// Explicit service port
+	if _, port, err = serviceProbeTarget(svc, []corev1.Pod{ready("web-1")}, 9090); err != nil || port != 9090 {
+		t.Errorf("expected port 9090, got %d (%v)", port, err)
+	}
+
+	// Unknown service port lists the valid ones
+	if _, _, err = serviceProbeTarget(svc, []corev1.Pod{ready("web-1")}, 443); err == nil || !strings.Contains(err.Error(), "80, 9090") {
+		t.Errorf("expected unknown-port error listing ports, got %v", err)
+	}
+
+	// No ready backends
+	if _, _, err = serviceProbeTarget(svc, []corev1.Pod{pending}, 0); err == nil || !strings.Contains(err.Error(), "no ready backend") {
+		t.Errorf("expected no-ready-backend error, got %v", err)
+	}
+
+	// Selectorless services cannot be probed by name
+	bare := svc.DeepCopy()
+	bare.Spec.Selector = nil
+	if _, _, err = serviceProbeTarget(bare, nil, 0); err == nil {
+		t.Error("expected selectorless service to be rejected")
+	}
+}
+
+func TestHTTPProbeVerdict(t *testing.T) {
+	result := func(code int, latency time.Duration) *k8s.HTTPProbeResult {
+		return &k8s.HTTPProbeResult{StatusCode: code, Status: http.StatusText(code), Latency: latency, Header: http.Header{}}
+	}
+	cases := []struct {
+		res        *k8s.HTTPProbeResult
+		err        error
+		severity   string
+		wantAction bool
+	}{
+		{result(200, 20*time.Millisecond), nil, "OK", false},
+		{result(200, 3*time.Second), nil, "WARNING", true},
+		{result(302, 0), nil, "INFO", false},
+		{result(403, 0), nil, "INFO", false},
+		{result(404, 0), nil, "WARNING", true},
+		{result(503, 0), nil, "CRITICAL", true},
+		{nil, errors.New("connection refused"), "CRITICAL", true},
+	}
+	for _, c := range cases {
+		severity, _, action := httpProbeVerdict(c.res, c.err, 8080)
+		if severity != c.severity || (action != "") != c.wantAction {
+			t.Errorf("%+v/%v: got %s action=%q, want %s action=%v", c.res, c.err, severity, action, c.severity, c.wantAction)
+		}
+	}
+}
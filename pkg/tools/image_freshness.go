@@ -0,0 +1,326 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/registry"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// freshnessMaxImages caps the images looked up in registries per call.
+	freshnessMaxImages = 40
+	// freshnessParallelism is how many images are looked up at once.
+	freshnessParallelism = 6
+)
+
+type checkImageFreshnessInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces)"`
+	MaxAgeDays     int    `json:"max_age_days,omitempty" jsonschema:"Flag images built more than this many days ago (default 180)"`
+	IncludeSystem  bool   `json:"include_system,omitempty" jsonschema:"Include kube-system, kube-public, and kube-node-lease in all-namespace scans"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// imageUse is an image reference and the workloads running it.
+type imageUse struct {
+	Image     string
+	Workloads []string
+	// Digests are the distinct digests the kubelet resolved the image to.
+	Digests []string
+	Pods    int
+}
+
+// collectImageUses groups the containers of running pods by image.
+func collectImageUses(pods []corev1.Pod, includeSystem bool) []imageUse {
+	byImage := make(map[string]*imageUse)
+	for i := range pods {
+		p := &pods[i]
+		if p.Status.Phase != corev1.PodRunning || (!includeSystem && configMapSystemNamespaces[p.Namespace]) {
+			continue
+		}
+		statuses := make(map[string]corev1.ContainerStatus, len(p.Status.ContainerStatuses))
+		for _, cs := range p.Status.ContainerStatuses {
+			statuses[cs.Name] = cs
+		}
+		for _, c := range p.Spec.Containers {
+			u, ok := byImage[c.Image]
+			if !ok {
+				u = &imageUse{Image: c.Image}
+				byImage[c.Image] = u
+			}
+			u.Pods++
+			u.Workloads = append(u.Workloads, p.Namespace+"/"+podWorkloadName(p))
+			if _, digest, ok := strings.Cut(statuses[c.Name].ImageID, "@"); ok {
+				u.Digests = append(u.Digests, digest)
+			}
+		}
+	}
+	out := make([]imageUse, 0, len(byImage))
+	for _, image := range sortedMapKeys(byImage) {
+		u := byImage[image]
+		u.Workloads, u.Digests = dedupe(u.Workloads), dedupe(u.Digests)
+		out = append(out, *u)
+	}
+	return out
+}
+
+// imageFreshness is what the registry says about an image in use.
+type imageFreshness struct {
+	imageUse
+	Ref registry.Reference
+	// Looked is set once the registry was asked about the image.
+	Looked bool
+	// Err is why the image could not be checked; nil when it was.
+	Err error
+	// TagMissing is set when the running tag no longer exists upstream.
+	TagMissing bool
+	// Moved is set when the tag now points to a digest no pod runs.
+	Moved   bool
+	Created time.Time
+	Latest  string
+}
+
+// lookupImageFreshness asks the registry about each image: whether its tag
+// still exists and where it points, when the running build was created, and
+// the newest tag of the same shape.
+func lookupImageFreshness(ctx context.Context, reg *registry.Client, uses []imageUse) []imageFreshness {
+	out := make([]imageFreshness, len(uses))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, freshnessParallelism)
+	for i := range uses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			out[i] = lookupImage(ctx, reg, uses[i])
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+func lookupImage(ctx context.Context, reg *registry.Client, u imageUse) imageFreshness {
+	f := imageFreshness{imageUse: u, Looked: true}
+	ref, err := registry.ParseReference(u.Image)
+	if err != nil {
+		f.Err = err
+		return f
+	}
+	f.Ref = ref
+	build := ref.Digest
+	if ref.Tag != "" {
+		upstream, err := reg.Digest(ctx, ref, ref.Tag)
+		switch {
+		case errors.Is(err, registry.ErrNotFound):
+			f.TagMissing = true
+		case err != nil:
+			f.Err = err
+			return f
+		default:
+			f.Moved = ref.Digest == "" && len(u.Digests) > 0 && !slices.Contains(u.Digests, upstream)
+			if build == "" {
+				build = upstream
+			}
+		}
+		if tags, err := reg.Tags(ctx, ref); err == nil {
+			f.Latest = registry.LatestTag(ref.Tag, tags)
+		}
+	}
+	// Date the build the pods actually run when the kubelet reported it.
+	if len(u.Digests) == 1 {
+		build = u.Digests[0]
+	}
+	if build != "" {
+		if created, err := reg.Created(ctx, ref, build); err == nil {
+			f.Created = created
+		}
+	}
+	return f
+}
+
+// status summarizes an image for the table.
+func (f imageFreshness) status(now time.Time, maxAge time.Duration) string {
+	var parts []string
+	switch {
+	case f.Err != nil:
+		return "not checked: " + freshnessErrReason(f.Err)
+	case f.TagMissing:
+		parts = append(parts, "tag gone upstream")
+	case f.Moved:
+		parts = append(parts, "tag moved")
+	}
+	if !f.Created.IsZero() && now.Sub(f.Created) > maxAge {
+		parts = append(parts, "stale")
+	}
+	if f.Latest != "" {
+		parts = append(parts, "update available")
+	}
+	if len(parts) == 0 {
+		return "OK"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// freshnessErrReason describes a registry lookup failure.
+func freshnessErrReason(err error) string {
+	switch {
+	case errors.Is(err, registry.ErrUnauthorized):
+		return "private registry"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timed out"
+	}
+	return truncateName(err.Error(), 60)
+}
+
+// imageFreshnessFindings returns findings and actions for the images,
+// including registry results for the images that were looked up.
+func imageFreshnessFindings(results []imageFreshness, now time.Time, maxAge time.Duration) ([]string, []string) {
+	var findingLines, actions []string
+	var private []string
+	for _, f := range results {
+		who := joinLimited(f.Workloads, 3)
+		if len(f.Digests) > 1 {
+			findingLines = append(findingLines, findings.Format("KD-IMG-001", "WARNING", fmt.Sprintf("%s resolves to %d different digests across pods of %s: the tag was re-pushed and pods run different builds", f.Image, len(f.Digests), who)))
+			actions = append(actions, "Pin images by digest or immutable version tags so every replica runs the same build")
+		}
+		if tag := imageTag(f.Image); tag == "" || tag == "latest" {
+			findingLines = append(findingLines, findings.Format("KD-IMG-001", "INFO", fmt.Sprintf("%s uses a mutable tag (%s); what runs depends on when each node pulled it", f.Image, who)))
+		}
+		if !f.Looked {
+			continue
+		}
+		switch {
+		case f.Err != nil && errors.Is(f.Err, registry.ErrUnauthorized):
+			private = append(private, f.Image)
+			continue
+		case f.Err != nil:
+			continue
+		case f.TagMissing:
+			findingLines = append(findingLines, findings.Format("KD-IMG-002", "WARNING", fmt.Sprintf("%s no longer exists upstream; %s cannot be pulled onto new nodes once cached copies are gone", f.Image, who)))
+			actions = append(actions, "Move workloads whose tag was deleted upstream to a tag that exists, or mirror the image into a registry you control")
+		case f.Moved:
+			findingLines = append(findingLines, findings.Format("KD-IMG-001", "INFO", fmt.Sprintf("%s now points to a newer build than %s runs; the next restart picks it up", f.Image, who)))
+		}
+		if !f.Created.IsZero() && now.Sub(f.Created) > maxAge {
+			findingLines = append(findingLines, findings.Format("KD-IMG-002", "WARNING", fmt.Sprintf("%s was built %d days ago (%s)", f.Image, int(now.Sub(f.Created).Hours()/24), who)))
+			actions = append(actions, "Rebuild or update stale images so they pick up base image and dependency security fixes")
+		}
+		if f.Latest != "" {
+			findingLines = append(findingLines, findings.Format("KD-IMG-002", "INFO", fmt.Sprintf("%s: newer tag %s is available (%s)", f.Image, f.Latest, who)))
+		}
+	}
+	if len(private) > 0 {
+		findingLines = append(findingLines, findings.Format("KD-SYS-008", "INFO", fmt.Sprintf("%d image(s) in private registries were not checked (anonymous access refused): %s", len(private), summarizeNames(private, 3))))
+	}
+	return findingLines, actions
+}
+
+func registerImageFreshnessTools(server *mcp.Server, client *k8s.ClusterClient, reg *registry.Client) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "check_image_freshness",
+		Description: "Check the provenance and age of running container images. Always flags tags that resolve to different digests across replicas " +
+			"(re-pushed tags) and mutable latest/untagged images. When the server runs with --registry-lookup, also queries each image's registry " +
+			"anonymously for the digest its tag points to now, the build date of the running image, and newer version tags, flagging images older " +
+			"than max_age_days, tags deleted upstream, and tags that moved since the pods pulled them. Private registries are reported as not checked.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkImageFreshnessInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+		maxAgeDays := input.MaxAgeDays
+		if maxAgeDays <= 0 {
+			maxAgeDays = 180
+		}
+		maxAge := time.Duration(maxAgeDays) * 24 * time.Hour
+
+		pods, err := client.ListPods(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		uses := collectImageUses(pods, input.IncludeSystem || ns != "")
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Image Freshness (scope: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+		sb.WriteString(util.FormatKeyValue("Images", fmt.Sprintf("%d", len(uses))) + "\n")
+		sb.WriteString(util.FormatKeyValue("Max age", fmt.Sprintf("%d days", maxAgeDays)) + "\n")
+
+		results := make([]imageFreshness, len(uses))
+		for i, u := range uses {
+			results[i] = imageFreshness{imageUse: u}
+		}
+		checked := reg != nil
+		if checked {
+			n := min(len(uses), freshnessMaxImages)
+			copy(results, lookupImageFreshness(ctx, reg, uses[:n]))
+			sb.WriteString(util.FormatKeyValue("Registry lookups", fmt.Sprintf("%d image(s)", n)) + "\n")
+			if len(uses) > n {
+				sb.WriteString(fmt.Sprintf("  Only the first %d images were looked up; narrow the namespace to check the rest.\n", n))
+			}
+		} else {
+			sb.WriteString(util.FormatKeyValue("Registry lookups", "off (start the server with --registry-lookup to check build dates and upstream tags)") + "\n")
+		}
+
+		if len(uses) > 0 {
+			now := time.Now()
+			sb.WriteString("\n")
+			rows := make([][]string, 0, len(results))
+			sorted := append([]imageFreshness(nil), results...)
+			// Oldest builds first; undated images last.
+			sort.SliceStable(sorted, func(i, j int) bool {
+				a, b := sorted[i].Created, sorted[j].Created
+				return !a.IsZero() && (b.IsZero() || a.Before(b))
+			})
+			for _, f := range sorted {
+				digest, built, latest, status := "unknown", "unknown", "-", "-"
+				if len(f.Digests) > 0 {
+					digest = strings.Join(f.Digests, ", ")
+					if len(digest) > 19 {
+						digest = digest[:19] + "..."
+					}
+				}
+				if !f.Created.IsZero() {
+					built = util.FormatAge(f.Created)
+				}
+				if f.Latest != "" {
+					latest = f.Latest
+				}
+				if f.Looked {
+					status = f.status(now, maxAge)
+				}
+				rows = append(rows, []string{truncateName(f.Image, 60), joinLimited(f.Workloads, 2), digest, built, latest, status})
+			}
+			sb.WriteString(util.FormatTable([]string{"IMAGE", "WORKLOADS", "RUNNING DIGEST", "BUILT", "NEWER TAG", "STATUS"}, rows))
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		findingLines, actions := imageFreshnessFindings(results, time.Now(), maxAge)
+		if len(findingLines) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "No stale, re-pushed, or missing images found"))
+			sb.WriteString("\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString(f)
+			sb.WriteString("\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
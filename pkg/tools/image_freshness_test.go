@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/registry"
+)
+
+func TestCollectImageUses(t *testing.T) {
+	pod := func(ns, name, image, imageID string) corev1.Pod {
+		p := withOwner(testPod(ns, name, "", map[string]string{"pod-template-hash": "abc"}), "ReplicaSet", "web-abc")
+		p.Spec.Containers[0].Image = image
+		p.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app", ImageID: imageID}}
+		return p
+	}
+	pods := []corev1.Pod{
+		pod("shop", "web-abc-1", "nginx:1.25", "docker.io/library/nginx@sha256:aaa"),
+		pod("shop", "web-abc-2", "nginx:1.25", "docker.io/library/nginx@sha256:bbb"),
+		pod("kube-system", "proxy", "kube-proxy:v1.30", "registry.k8s.io/kube-proxy@sha256:ccc"),
+	}
+	uses := collectImageUses(pods, false)
+	if len(uses) != 1 || uses[0].Pods != 2 || len(uses[0].Digests) != 2 || uses[0].Workloads[0] != "shop/web" {
+		t.Fatalf("unexpected image uses: %+v", uses)
+	}
+	if len(collectImageUses(pods, true)) != 2 {
+		t.Error("expected system namespaces to be included on request")
+	}
+}
+
+func TestImageFreshnessFindings(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	maxAge := 180 * 24 * time.Hour
+	results := []imageFreshness{
+		{imageUse: imageUse{Image: "nginx:1.25", Workloads: []string{"shop/web"}, Digests: []string{"sha256:aaa", "sha256:bbb"}}},
+		{imageUse: imageUse{Image: "redis:6.0", Workloads: []string{"shop/cache"}}, Looked: true, Created: now.Add(-400 * 24 * time.Hour), Latest: "6.2"},
+		{imageUse: imageUse{Image: "acme/api:v3", Workloads: []string{"shop/api"}}, Looked: true, TagMissing: true},
+		{imageUse: imageUse{Image: "private.example.com/app:v1", Workloads: []string{"shop/app"}}, Looked: true, Err: registry.ErrUnauthorized},
+		{imageUse: imageUse{Image: "busybox", Workloads: []string{"shop/debug"}}},
+	}
+	findings, actions := imageFreshnessFindings(results, now, maxAge)
+	out := strings.Join(findings, "\n")
+	for _, want := range []string{
+		"nginx:1.25 resolves to 2 different digests",
+		"redis:6.0 was built 400 days ago",
+		"redis:6.0: newer tag 6.2 is available",
+		"acme/api:v3 no longer exists upstream",
+		"1 image(s) in private registries were not checked",
+		"busybox uses a mutable tag",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected finding %q, got:\n%s", want, out)
+		}
+	}
+	if len(actions) != 3 {
+		t.Errorf("expected digest pinning, missing tag, and rebuild actions, got %v", actions)
+	}
+	if s := results[1].status(now, maxAge); s != "stale, update available" {
+		t.Errorf("unexpected status %q", s)
+	}
+	if s := results[3].status(now, maxAge); s != "not checked: private registry" {
+		t.Errorf("unexpected status %q", s)
+	}
+}
@@ -0,0 +1,326 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type checkIngressControllerHealthInput struct {
+	Controller string `json:"controller,omitempty" jsonschema:"Only check this controller: nginx, traefik, agic, aws-alb, azure-alb, or istio (default: all detected)"`
+}
+
+// ingressControllerType describes how to detect and health-check one kind of ingress controller.
+type ingressControllerType struct {
+	Key          string
+	DisplayName  string
+	ControllerID []string // IngressClass spec.controller values
+	PodSelectors []string // label selectors for controller pods
+	ConfigMaps   []string // ConfigMap names to look for in the controller namespace
+}
+
+// knownIngressControllers lists the ingress controllers kube-doctor can detect.
+var knownIngressControllers = []ingressControllerType{
+	{
+		Key:          "nginx",
+		DisplayName:  "NGINX Ingress Controller",
+		ControllerID: []string{"k8s.io/ingress-nginx"},
+		PodSelectors: []string{"app.kubernetes.io/name=ingress-nginx", "app=ingress-nginx"},
+		ConfigMaps:   []string{"ingress-nginx-controller", "nginx-configuration"},
+	},
+	{
+		Key:          "traefik",
+		DisplayName:  "Traefik",
+		ControllerID: []string{"traefik.io/ingress-controller"},
+		PodSelectors: []string{"app.kubernetes.io/name=traefik", "app=traefik"},
+	},
+	{
+		Key:          "agic",
+		DisplayName:  "Azure Application Gateway Ingress Controller",
+		ControllerID: []string{"azure/application-gateway"},
+		PodSelectors: []string{"app=ingress-azure", "app.kubernetes.io/name=ingress-azure", "app=ingress-appgw"},
+		ConfigMaps:   []string{"ingress-azure", "agic-config", "ingress-appgw-cm"},
+	},
+	{
+		Key:          "aws-alb",
+		DisplayName:  "AWS Load Balancer Controller",
+		ControllerID: []string{"ingress.k8s.aws/alb"},
+		PodSelectors: []string{"app.kubernetes.io/name=aws-load-balancer-controller"},
+	},
+	{
+		Key:          "azure-alb",
+		DisplayName:  "Application Gateway for Containers (ALB Controller)",
+		ControllerID: []string{"alb.networking.azure.io/alb-controller"},
+		PodSelectors: []string{"app=alb-controller"},
+	},
+	{
+		Key:          "istio",
+		DisplayName:  "Istio Ingress Gateway",
+		ControllerID: []string{"istio.io/ingress-controller"},
+		PodSelectors: []string{"istio=ingressgateway", "app=istio-ingressgateway"},
+	},
+}
+
+// detectedIngressController is a controller found in the cluster together with its evidence.
+type detectedIngressController struct {
+	Type    ingressControllerType
+	Classes []string
+	Pods    []corev1.Pod
+}
+
+func registerIngressControllerTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// check_ingress_controller_health
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "check_ingress_controller_health",
+		Description: "Detect which ingress controllers are installed (nginx, traefik, AGIC, AWS/Azure ALB, istio gateway) from IngressClasses and controller pods, then run health checks for each: pod status and restarts, configuration ConfigMaps, and recent error logs. Also flags Ingresses that reference a class with no running controller.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkIngressControllerHealthInput) (*mcp.CallToolResult, any, error) {
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Ingress Controller Health"))
+		sb.WriteString("\n\n")
+
+		classes, err := client.ListIngressClasses(ctx)
+		if err != nil {
+			return util.HandleK8sError("listing ingress classes", err), nil, nil
+		}
+
+		detected, err := detectIngressControllers(ctx, client, classes)
+		if err != nil {
+			return util.HandleK8sError("searching for ingress controller pods", err), nil, nil
+		}
+		if input.Controller != "" {
+			var filtered []detectedIngressController
+			for _, d := range detected {
+				if d.Type.Key == strings.ToLower(input.Controller) {
+					filtered = append(filtered, d)
+				}
+			}
+			detected = filtered
+		}
+
+		// --- Detection summary ---
+		sb.WriteString(util.FormatSubHeader("Detected Controllers"))
+		sb.WriteString("\n")
+		if len(detected) == 0 {
+			sb.WriteString(findings.Format("KD-ING-005", "WARNING", "No known ingress controller detected (checked IngressClasses and controller pod labels)"))
+			sb.WriteString("\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+		headers := []string{"CONTROLLER", "INGRESS CLASSES", "PODS", "READY"}
+		rows := make([][]string, 0, len(detected))
+		for _, d := range detected {
+			healthy := 0
+			for i := range d.Pods {
+				if isPodHealthy(&d.Pods[i]) {
+					healthy++
+				}
+			}
+			classList := strings.Join(d.Classes, ", ")
+			if classList == "" {
+				classList = "-"
+			}
+			rows = append(rows, []string{d.Type.DisplayName, classList, fmt.Sprintf("%d", len(d.Pods)), fmt.Sprintf("%d/%d", healthy, len(d.Pods))})
+		}
+		sb.WriteString(util.FormatTable(headers, rows))
+
+		findingCount := 0
+		var actions []string
+
+		// --- Per-controller checks ---
+		for _, d := range detected {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader(d.Type.DisplayName))
+			sb.WriteString("\n")
+			n, a := checkIngressControllerPods(ctx, client, &sb, d)
+			findingCount += n
+			actions = append(actions, a...)
+		}
+
+		// --- Ingresses pointing at classes without a controller ---
+		ingresses, ingErr := client.ListIngresses(ctx, "", metav1.ListOptions{})
+		if ingErr == nil {
+			served := make(map[string]bool)
+			for _, d := range detected {
+				if len(d.Pods) == 0 {
+					continue
+				}
+				for _, c := range d.Classes {
+					served[c] = true
+				}
+			}
+			var orphaned []string
+			for i := range ingresses {
+				class := ingressClassName(&ingresses[i])
+				if class == "<none>" || served[class] {
+					continue
+				}
+				orphaned = append(orphaned, fmt.Sprintf("%s/%s (class %s)", ingresses[i].Namespace, ingresses[i].Name, class))
+			}
+			if len(orphaned) > 0 && input.Controller == "" {
+				sb.WriteString("\n")
+				sb.WriteString(util.FormatSubHeader("Ingresses Without a Running Controller"))
+				sb.WriteString("\n")
+				for _, o := range orphaned {
+					sb.WriteString(findings.Format("KD-ING-005", "WARNING", o))
+					sb.WriteString("\n")
+				}
+				findingCount += len(orphaned)
+				actions = append(actions, "Install a controller for the orphaned ingress classes or fix their ingressClassName")
+			}
+		}
+
+		// --- Overall ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
+		sb.WriteString("\n")
+		if findingCount == 0 {
+			sb.WriteString("  All detected ingress controllers appear healthy.\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findingCount))
+		}
+
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// detectIngressControllers finds installed ingress controllers from IngressClass
+// controller names and from well-known controller pod labels.
+func detectIngressControllers(ctx context.Context, client *k8s.ClusterClient, classes []networkingv1.IngressClass) ([]detectedIngressController, error) {
+	var detected []detectedIngressController
+	for _, t := range knownIngressControllers {
+		d := detectedIngressController{Type: t}
+		for _, c := range classes {
+			for _, id := range t.ControllerID {
+				if c.Spec.Controller == id {
+					d.Classes = append(d.Classes, c.Name)
+				}
+			}
+		}
+		seen := make(map[string]bool)
+		for _, sel := range t.PodSelectors {
+			pods, err := client.ListPods(ctx, "", metav1.ListOptions{LabelSelector: sel})
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range pods {
+				key := p.Namespace + "/" + p.Name
+				if !seen[key] {
+					seen[key] = true
+					d.Pods = append(d.Pods, p)
+				}
+			}
+		}
+		if len(d.Classes) > 0 || len(d.Pods) > 0 {
+			detected = append(detected, d)
+		}
+	}
+	return detected, nil
+}
+
+// checkIngressControllerPods runs the shared pod, config, and log checks for a
+// detected controller, writing results to sb. It returns the number of findings
+// and suggested actions.
+func checkIngressControllerPods(ctx context.Context, client *k8s.ClusterClient, sb *strings.Builder, d detectedIngressController) (int, []string) {
+	findingCount := 0
+	var actions []string
+
+	if len(d.Pods) == 0 {
+		sb.WriteString(findings.Format("KD-ING-005", "CRITICAL", fmt.Sprintf("IngressClass %s exists but no controller pods were found", strings.Join(d.Classes, ", "))))
+		sb.WriteString("\n")
+		actions = append(actions, fmt.Sprintf("Verify the %s deployment is installed and running", d.Type.DisplayName))
+		return 1, actions
+	}
+
+	for i := range d.Pods {
+		p := &d.Pods[i]
+		_, _, restarts := podContainerSummary(p)
+		if !isPodHealthy(p) {
+			sb.WriteString(findings.Format("KD-ING-005", "CRITICAL", fmt.Sprintf("Pod %s/%s is not healthy: %s", p.Namespace, p.Name, podPhaseReason(p))))
+			sb.WriteString("\n")
+			actions = append(actions, fmt.Sprintf("Investigate %s pod '%s' (use diagnose_pod)", d.Type.Key, p.Name))
+			findingCount++
+		}
+		if restarts > util.HighRestartThreshold {
+			sb.WriteString(findings.Format("KD-POD-005", "WARNING", fmt.Sprintf("Pod %s/%s has high restart count: %d", p.Namespace, p.Name, restarts)))
+			sb.WriteString("\n")
+			findingCount++
+		}
+		for _, cs := range p.Status.ContainerStatuses {
+			if t := cs.LastTerminationState.Terminated; t != nil && t.Reason == "OOMKilled" {
+				sb.WriteString(findings.Format("KD-POD-003", "CRITICAL", fmt.Sprintf("Container '%s' in %s was OOMKilled", cs.Name, p.Name)))
+				sb.WriteString("\n")
+				actions = append(actions, fmt.Sprintf("Increase memory limits for the %s controller", d.Type.Key))
+				findingCount++
+			}
+		}
+	}
+
+	// Config maps in the controller namespace
+	if len(d.Type.ConfigMaps) > 0 {
+		ns := d.Pods[0].Namespace
+		found := ""
+		for _, name := range d.Type.ConfigMaps {
+			if _, err := client.GetConfigMap(ctx, ns, name); err == nil {
+				found = name
+				break
+			}
+		}
+		if found != "" {
+			sb.WriteString(fmt.Sprintf("  Config: ConfigMap %s/%s present\n", ns, found))
+		} else {
+			sb.WriteString(findings.Format("KD-ING-005", "INFO", fmt.Sprintf("No controller ConfigMap found in %s (looked for %s)", ns, strings.Join(d.Type.ConfigMaps, ", "))))
+			sb.WriteString("\n")
+		}
+	}
+
+	// Error logs from the first running pod
+	for i := range d.Pods {
+		p := &d.Pods[i]
+		if p.Status.Phase != corev1.PodRunning || len(p.Spec.Containers) == 0 {
+			continue
+		}
+		logs, err := client.GetPodLogs(ctx, p.Namespace, p.Name, p.Spec.Containers[0].Name, 200, false, "5m")
+		if err != nil {
+			sb.WriteString(fmt.Sprintf("  Could not fetch logs from %s: %v\n", p.Name, err))
+			break
+		}
+		errorLines := extractLogErrors(logs)
+		if len(errorLines) == 0 {
+			sb.WriteString(fmt.Sprintf("  No errors in the last 5m of logs from %s.\n", p.Name))
+			break
+		}
+		sb.WriteString(findings.Format("KD-EVT-003", "WARNING", fmt.Sprintf("%d error/warning line(s) in the last 5m of logs from %s", len(errorLines), p.Name)))
+		sb.WriteString("\n")
+		for j, line := range errorLines {
+			if j >= 10 {
+				sb.WriteString(fmt.Sprintf("    ... and %d more\n", len(errorLines)-10))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("    %s\n", truncateName(line, 200)))
+		}
+		findingCount++
+		actions = append(actions, fmt.Sprintf("Review %s controller logs (use get_pod_logs on %s/%s)", d.Type.Key, p.Namespace, p.Name))
+		break
+	}
+
+	if findingCount == 0 {
+		sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("%d controller pod(s) healthy", len(d.Pods))))
+		sb.WriteString("\n")
+	}
+	return findingCount, actions
+}
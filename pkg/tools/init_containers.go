@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// initStuckAfter is how long an init container may run before the pod is
+// reported as stuck initializing.
+const initStuckAfter = 5 * time.Minute
+
+// initContainerIssue is a problem with an init or sidecar container that
+// keeps the pod's app containers from starting or running.
+type initContainerIssue struct {
+	Container string
+	Sidecar   bool
+	Rule      string
+	Severity  string
+	Message   string
+	// Logs is set when the container's logs explain the issue; Previous
+	// reads them from the last terminated instance.
+	Logs     bool
+	Previous bool
+}
+
+// isSidecar reports whether an init container is a native sidecar
+// (restartPolicy: Always), which keeps running alongside the app containers.
+func isSidecar(c *corev1.Container) bool {
+	return c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+// initContainerRows summarizes each init container's state for a table.
+func initContainerRows(pod *corev1.Pod) [][]string {
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.InitContainerStatuses))
+	for _, cs := range pod.Status.InitContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+	rows := make([][]string, 0, len(pod.Spec.InitContainers))
+	for i := range pod.Spec.InitContainers {
+		c := &pod.Spec.InitContainers[i]
+		kind := "init"
+		if isSidecar(c) {
+			kind = "sidecar"
+		}
+		state, exit, restarts := "Pending", "-", "0"
+		if cs, ok := statuses[c.Name]; ok {
+			restarts = fmt.Sprintf("%d", cs.RestartCount)
+			switch {
+			case cs.State.Running != nil:
+				state = "Running"
+				if cs.Ready {
+					state += " (ready)"
+				}
+			case cs.State.Terminated != nil:
+				state = cs.State.Terminated.Reason
+				exit = fmt.Sprintf("%d", cs.State.Terminated.ExitCode)
+			case cs.State.Waiting != nil:
+				state = cs.State.Waiting.Reason
+				if t := cs.LastTerminationState.Terminated; t != nil {
+					exit = fmt.Sprintf("%d", t.ExitCode)
+				}
+			}
+		}
+		rows = append(rows, []string{fmt.Sprintf("%d", i+1), c.Name, kind, state, exit, restarts})
+	}
+	return rows
+}
+
+// analyzeInitContainers finds the init container blocking the pod, if any,
+// and sidecars that are failing. Init containers run in order, so only the
+// first one that has not completed is examined.
+func analyzeInitContainers(pod *corev1.Pod, now time.Time) []initContainerIssue {
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.InitContainerStatuses))
+	for _, cs := range pod.Status.InitContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+	total := len(pod.Spec.InitContainers)
+
+	var issues []initContainerIssue
+	for i := range pod.Spec.InitContainers {
+		c := &pod.Spec.InitContainers[i]
+		cs, ok := statuses[c.Name]
+		if !ok {
+			break
+		}
+		position := fmt.Sprintf("%d of %d", i+1, total)
+
+		if isSidecar(c) {
+			if issue, failing := sidecarIssue(cs); failing {
+				issues = append(issues, issue)
+			}
+			if cs.Started == nil || !*cs.Started {
+				break // a sidecar that has not started blocks the containers after it
+			}
+			continue
+		}
+
+		if t := cs.State.Terminated; t != nil && t.ExitCode == 0 {
+			continue
+		}
+		switch {
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff":
+			msg := fmt.Sprintf("Init container '%s' (%s) is in CrashLoopBackOff after %d restart(s)", c.Name, position, cs.RestartCount)
+			if t := cs.LastTerminationState.Terminated; t != nil {
+				msg += fmt.Sprintf("; last exit code %d (%s)", t.ExitCode, t.Reason)
+			}
+			issues = append(issues, initContainerIssue{Container: c.Name, Rule: "KD-POD-011", Severity: "CRITICAL",
+				Message: msg + " — app containers cannot start", Logs: true, Previous: true})
+		case cs.State.Terminated != nil:
+			t := cs.State.Terminated
+			issues = append(issues, initContainerIssue{Container: c.Name, Rule: "KD-POD-011", Severity: "CRITICAL",
+				Message: fmt.Sprintf("Init container '%s' (%s) failed with exit code %d (%s)", c.Name, position, t.ExitCode, t.Reason), Logs: true})
+		case cs.State.Waiting != nil && (cs.State.Waiting.Reason == "ImagePullBackOff" || cs.State.Waiting.Reason == "ErrImagePull"):
+			issues = append(issues, initContainerIssue{Container: c.Name, Rule: "KD-POD-002", Severity: "CRITICAL",
+				Message: fmt.Sprintf("Init container '%s' (%s) cannot pull image %s: %s", c.Name, position, c.Image, cs.State.Waiting.Message)})
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "PodInitializing":
+			issues = append(issues, initContainerIssue{Container: c.Name, Rule: "KD-POD-011", Severity: "CRITICAL",
+				Message: fmt.Sprintf("Init container '%s' (%s) is waiting: %s %s", c.Name, position, cs.State.Waiting.Reason, cs.State.Waiting.Message)})
+		case cs.State.Running != nil:
+			running := now.Sub(cs.State.Running.StartedAt.Time).Round(time.Second)
+			severity := "INFO"
+			if running >= initStuckAfter {
+				severity = "WARNING"
+			}
+			issues = append(issues, initContainerIssue{Container: c.Name, Rule: "KD-POD-012", Severity: severity,
+				Message: fmt.Sprintf("Pod is initializing: init container '%s' (%s) has been running for %s and blocks the app containers", c.Name, position, running), Logs: true})
+		}
+		break
+	}
+	return issues
+}
+
+// sidecarIssue reports a sidecar that is crash-looping or restarting often.
+func sidecarIssue(cs corev1.ContainerStatus) (initContainerIssue, bool) {
+	switch {
+	case cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff":
+		msg := fmt.Sprintf("Sidecar container '%s' is in CrashLoopBackOff after %d restart(s)", cs.Name, cs.RestartCount)
+		if t := cs.LastTerminationState.Terminated; t != nil {
+			msg += fmt.Sprintf("; last exit code %d (%s)", t.ExitCode, t.Reason)
+		}
+		return initContainerIssue{Container: cs.Name, Sidecar: true, Rule: "KD-POD-011", Severity: "CRITICAL", Message: msg, Logs: true, Previous: true}, true
+	case cs.RestartCount > util.HighRestartThreshold:
+		return initContainerIssue{Container: cs.Name, Sidecar: true, Rule: "KD-POD-005", Severity: "WARNING",
+			Message: fmt.Sprintf("Sidecar container '%s' has restarted %d times", cs.Name, cs.RestartCount), Logs: true, Previous: true}, true
+	}
+	return initContainerIssue{}, false
+}
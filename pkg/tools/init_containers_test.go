@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func initTestPod(statuses ...corev1.ContainerStatus) *corev1.Pod {
+	always := corev1.ContainerRestartPolicyAlways
+	pod := testPod("shop", "web", "", nil)
+	pod.Spec.InitContainers = []corev1.Container{
+		{Name: "proxy", RestartPolicy: &always},
+		{Name: "migrate", Image: "app:1.2"},
+		{Name: "wait-for-db"},
+	}
+	pod.Status = corev1.PodStatus{
+		Phase:                 corev1.PodPending,
+		InitContainerStatuses: statuses,
+		ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "app",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}},
+		}},
+	}
+	return &pod
+}
+
+func TestAnalyzeInitContainers(t *testing.T) {
+	now := time.Now()
+	started := true
+	proxyOK := corev1.ContainerStatus{Name: "proxy", Started: &started, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+	completed := corev1.ContainerStatus{Name: "migrate", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}}
+	waiting := corev1.ContainerStatus{Name: "wait-for-db", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}}
+
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		rule     string
+		severity string
+		contains string
+		previous bool
+	}{
+		{
+			name: "Init:CrashLoopBackOff",
+			pod: initTestPod(proxyOK, corev1.ContainerStatus{
+				Name: "migrate", RestartCount: 4,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+			}, waiting),
+			rule: "KD-POD-011", severity: "CRITICAL", previous: true,
+			contains: "Init container 'migrate' (2 of 3) is in CrashLoopBackOff after 4 restart(s); last exit code 1 (Error)",
+		},
+		{
+			name: "Init:Error",
+			pod: initTestPod(proxyOK, corev1.ContainerStatus{
+				Name: "migrate", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2, Reason: "Error"}},
+			}, waiting),
+			rule: "KD-POD-011", severity: "CRITICAL",
+			contains: "failed with exit code 2 (Error)",
+		},
+		{
+			name: "stuck initializing",
+			pod: initTestPod(proxyOK, completed, corev1.ContainerStatus{
+				Name: "wait-for-db", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(now.Add(-20 * time.Minute))}},
+			}),
+			rule: "KD-POD-012", severity: "WARNING",
+			contains: "init container 'wait-for-db' (3 of 3) has been running for 20m0s",
+		},
+		{
+			name: "crash-looping sidecar",
+			pod: initTestPod(corev1.ContainerStatus{
+				Name: "proxy", RestartCount: 7,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}, waiting, waiting),
+			rule: "KD-POD-011", severity: "CRITICAL", previous: true,
+			contains: "Sidecar container 'proxy' is in CrashLoopBackOff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := analyzeInitContainers(tt.pod, now)
+			if len(issues) != 1 {
+				t.Fatalf("got %d issues, want 1: %+v", len(issues), issues)
+			}
+			got := issues[0]
+			if got.Rule != tt.rule || got.Severity != tt.severity || got.Previous != tt.previous || !got.Logs || !strings.Contains(got.Message, tt.contains) {
+				t.Errorf("issue = %+v; want rule %s severity %s previous %v containing %q", got, tt.rule, tt.severity, tt.previous, tt.contains)
+			}
+		})
+	}
+
+	if issues := analyzeInitContainers(initTestPod(proxyOK, completed, completed), now); len(issues) != 0 {
+		t.Errorf("completed init containers: got %+v, want no issues", issues)
+	}
+}
+
+func TestPodPhaseReasonInitContainers(t *testing.T) {
+	pod := initTestPod(corev1.ContainerStatus{Name: "proxy"}, corev1.ContainerStatus{
+		Name: "migrate", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	})
+	if got := podPhaseReason(pod); got != "Init:CrashLoopBackOff" {
+		t.Errorf("podPhaseReason() = %q, want Init:CrashLoopBackOff rather than PodInitializing", got)
+	}
+
+	running := &corev1.Pod{Status: corev1.PodStatus{
+		Phase:                 corev1.PodRunning,
+		InitContainerStatuses: []corev1.ContainerStatus{{Name: "migrate", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}}},
+		ContainerStatuses:     []corev1.ContainerStatus{{Name: "app", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+	}}
+	if got := podPhaseReason(running); got != "Running" {
+		t.Errorf("podPhaseReason() = %q, want Running for a pod whose init containers completed", got)
+	}
+}
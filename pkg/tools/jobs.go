@@ -0,0 +1,699 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// jobDefaultBackoffLimit is the backoffLimit the API server defaults to.
+	jobDefaultBackoffLimit int32 = 6
+
+	// jobMaxLoggedAttempts is how many of the most recent failed attempts have their logs fetched.
+	jobMaxLoggedAttempts = 3
+
+	// jobSLAAnnotation sets the expected run duration (a Go duration such as
+	// "15m") on a CronJob, its job template, or a standalone Job.
+	jobSLAAnnotation = "kube-doctor.io/expected-duration"
+
+	// jobSLADefaultTolerancePercent is how far past its SLA a finished Job may run before it is reported.
+	jobSLADefaultTolerancePercent = 20
+)
+
+type diagnoseJobInput struct {
+	Namespace string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Name      string `json:"name" jsonschema:"required,Job name"`
+	LogLines  int64  `json:"log_lines,omitempty" jsonschema:"Log lines to fetch from each failed attempt (default 30)"`
+}
+
+type checkJobSLAsInput struct {
+	Namespace        string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
+	TolerancePercent int    `json:"tolerance_percent,omitempty" jsonschema:"How far past its SLA a finished Job may run before it is reported, in percent (default 20). Running Jobs are reported as soon as they pass the SLA"`
+	TimeoutSeconds   int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// jobSLABreach is a Job that ran, or is running, longer than its SLA.
+type jobSLABreach struct {
+	Job      string
+	Owner    string
+	SLA      time.Duration
+	Duration time.Duration
+	Running  bool
+}
+
+// jobAttempt is one pod created by a Job, summarized by its failing container.
+type jobAttempt struct {
+	Pod       string
+	Container string
+	Node      string
+	Created   time.Time
+	Duration  time.Duration
+	Status    string
+	ExitCode  int32
+	Reason    string // termination or waiting reason of the failing container, or the pod reason
+	Restarts  int32
+	Failed    bool
+	Previous  bool // the failure is from the previous container instance (restartPolicy OnFailure)
+	Pending   string
+}
+
+func registerJobTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// diagnose_job
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "diagnose_job",
+		Description: "Forensic diagnosis of a Job: backoffLimit and activeDeadlineSeconds usage, a per-attempt table of pods " +
+			"with exit codes, logs from the most recent failed attempts, and the most probable failure cause with suggested actions.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input diagnoseJobInput) (*mcp.CallToolResult, any, error) {
+		job, err := client.GetJob(ctx, input.Namespace, input.Name)
+		if err != nil {
+			return util.HandleK8sError(fmt.Sprintf("getting job %s/%s", input.Namespace, input.Name), err), nil, nil
+		}
+		logLines := input.LogLines
+		if logLines <= 0 {
+			logLines = 30
+		}
+
+		selector := "job-name=" + job.Name
+		if job.Spec.Selector != nil {
+			if s, err := metav1.LabelSelectorAsSelector(job.Spec.Selector); err == nil {
+				selector = s.String()
+			}
+		}
+		pods, err := client.ListPods(ctx, job.Namespace, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return util.HandleK8sError("listing job pods", err), nil, nil
+		}
+		attempts := jobAttempts(pods)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Job Diagnosis: %s (namespace: %s)", job.Name, job.Namespace)))
+		sb.WriteString("\n\n")
+
+		completions := int32(1)
+		if job.Spec.Completions != nil {
+			completions = *job.Spec.Completions
+		}
+		backoffLimit := jobBackoffLimit(job)
+		sb.WriteString(util.FormatKeyValue("STATUS", jobStatus(job)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("COMPLETIONS", fmt.Sprintf("%d/%d", job.Status.Succeeded, completions)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("ACTIVE", fmt.Sprintf("%d", job.Status.Active)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("BACKOFF", fmt.Sprintf("%d failed of backoffLimit %d", job.Status.Failed, backoffLimit)))
+		sb.WriteString("\n")
+		if job.Spec.ActiveDeadlineSeconds != nil {
+			sb.WriteString(util.FormatKeyValue("DEADLINE", fmt.Sprintf("%s elapsed of activeDeadlineSeconds %ds",
+				jobElapsed(job).Round(time.Second), *job.Spec.ActiveDeadlineSeconds)))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(util.FormatKeyValue("AGE", util.FormatAge(job.CreationTimestamp.Time)))
+		sb.WriteString("\n")
+
+		// Attempts
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader(fmt.Sprintf("Attempts (%d pods)", len(attempts))))
+		sb.WriteString("\n")
+		if len(attempts) == 0 {
+			sb.WriteString("  No pods found for this Job (they may have been cleaned up by ttlSecondsAfterFinished or a pod failure policy).\n")
+		} else {
+			headers := []string{"#", "POD", "NODE", "STATUS", "EXIT", "RESTARTS", "DURATION", "AGE"}
+			rows := make([][]string, 0, len(attempts))
+			for i, a := range attempts {
+				exit := "-"
+				if a.Failed && a.ExitCode != 0 {
+					exit = formatExitCode(a.ExitCode, a.Reason)
+				}
+				rows = append(rows, []string{
+					fmt.Sprintf("%d", i+1),
+					truncateName(a.Pod, 45),
+					a.Node,
+					a.Status,
+					exit,
+					fmt.Sprintf("%d", a.Restarts),
+					a.Duration.Round(time.Second).String(),
+					util.FormatAge(a.Created),
+				})
+			}
+			sb.WriteString(util.FormatTable(headers, rows))
+		}
+
+		// Logs from the most recent failed attempts
+		var failed []jobAttempt
+		for _, a := range attempts {
+			if a.Failed && a.Container != "" {
+				failed = append(failed, a)
+			}
+		}
+		lastLines := make(map[string]string)
+		for i := len(failed) - 1; i >= 0 && i >= len(failed)-jobMaxLoggedAttempts; i-- {
+			a := failed[i]
+			sb.WriteString(fmt.Sprintf("\nLOGS (pod '%s', container '%s'", a.Pod, a.Container))
+			if a.Previous {
+				sb.WriteString(", previous instance")
+			}
+			sb.WriteString("):\n")
+			logs, err := client.GetPodLogs(ctx, job.Namespace, a.Pod, a.Container, logLines, a.Previous, "")
+			switch {
+			case err != nil:
+				sb.WriteString(fmt.Sprintf("  (could not fetch logs: %v)\n", err))
+			case strings.TrimSpace(logs) == "":
+				sb.WriteString("  (no logs available)\n")
+			default:
+				sb.WriteString(logs)
+				if !strings.HasSuffix(logs, "\n") {
+					sb.WriteString("\n")
+				}
+				lastLines[a.Pod] = lastLogLine(logs)
+			}
+		}
+
+		// Findings
+		sb.WriteString("\nFINDINGS:\n")
+		found := jobFindings(job, attempts)
+		if len(found) == 0 {
+			sb.WriteString("  No issues found.\n")
+		}
+		for _, f := range found {
+			sb.WriteString(findings.Format(f.rule, f.severity, f.message))
+			sb.WriteString("\n")
+		}
+
+		if events, err := client.GetEventsForObject(ctx, job.Namespace, job.Name); err == nil {
+			for _, e := range events {
+				if e.Type == corev1.EventTypeWarning {
+					sb.WriteString(fmt.Sprintf("  - Event %s: %s", e.Reason, e.Message))
+					if e.Count > 1 {
+						sb.WriteString(fmt.Sprintf(" (x%d)", e.Count))
+					}
+					sb.WriteString("\n")
+				}
+			}
+		}
+
+		cause, actions := jobFailureCause(job, attempts)
+		if len(failed) > 0 {
+			if line := lastLines[failed[len(failed)-1].Pod]; line != "" {
+				cause += fmt.Sprintf(". Last log line: %q", truncateName(line, 200))
+			}
+		}
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("MOST PROBABLE CAUSE", cause))
+		sb.WriteString("\n")
+
+		sb.WriteString("\nSUGGESTED ACTIONS:\n")
+		if len(actions) == 0 {
+			sb.WriteString("  No specific actions needed.\n")
+		}
+		for i, a := range dedupe(actions) {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), jobNextSteps(job, attempts)), nil, nil
+	})
+
+	// check_job_slas
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "check_job_slas",
+		Description: "Check Job run times against expected-duration SLAs set with the " + jobSLAAnnotation + " annotation " +
+			"(e.g. \"15m\") on a CronJob, its job template, or a standalone Job. Reports Jobs that ran significantly longer " +
+			"than their SLA and Jobs currently running past it.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkJobSLAsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+		tolerance := input.TolerancePercent
+		if tolerance <= 0 {
+			tolerance = jobSLADefaultTolerancePercent
+		}
+
+		cronJobs, err := client.ListCronJobs(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing cronjobs", err), nil, nil
+		}
+		jobs, err := client.ListJobs(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing jobs", err), nil, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Job SLA Check (namespace: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+
+		var found []containerFinding
+		cronSLAs := make(map[string]time.Duration)
+		for i := range cronJobs {
+			cj := &cronJobs[i]
+			sla, ok, err := parseJobSLA(cj.Annotations)
+			if !ok && err == nil {
+				sla, ok, err = parseJobSLA(cj.Spec.JobTemplate.Annotations)
+			}
+			if err != nil {
+				found = append(found, containerFinding{"KD-WL-009", "WARNING", fmt.Sprintf("CronJob %s/%s: %v", cj.Namespace, cj.Name, err)})
+				continue
+			}
+			if ok {
+				cronSLAs[cj.Namespace+"/"+cj.Name] = sla
+			}
+		}
+
+		var breaches []jobSLABreach
+		checked := 0
+		runs := make(map[string]int)
+		for i := range jobs {
+			j := &jobs[i]
+			owner := jobCronJobOwner(j)
+			sla, ok, err := parseJobSLA(j.Annotations)
+			if err != nil {
+				found = append(found, containerFinding{"KD-WL-009", "WARNING", fmt.Sprintf("Job %s/%s: %v", j.Namespace, j.Name, err)})
+				continue
+			}
+			if !ok && owner != "" {
+				sla, ok = cronSLAs[j.Namespace+"/"+owner]
+			}
+			if !ok || j.Status.StartTime == nil {
+				continue
+			}
+			checked++
+			if owner != "" {
+				runs[j.Namespace+"/"+owner]++
+			}
+			if b, breached := checkJobSLA(j, owner, sla, tolerance); breached {
+				breaches = append(breaches, b)
+			}
+		}
+
+		if len(cronSLAs) > 0 {
+			headers := []string{"CRONJOB", "SLA", "RUNS CHECKED", "BREACHES"}
+			breachCount := make(map[string]int)
+			for _, b := range breaches {
+				if b.Owner != "" {
+					breachCount[b.Owner]++
+				}
+			}
+			keys := make([]string, 0, len(cronSLAs))
+			for k := range cronSLAs {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			rows := make([][]string, 0, len(keys))
+			for _, k := range keys {
+				rows = append(rows, []string{k, cronSLAs[k].String(), fmt.Sprintf("%d", runs[k]), fmt.Sprintf("%d", breachCount[k])})
+			}
+			sb.WriteString(util.FormatTable(headers, rows))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("%d Job run(s) checked against an SLA (tolerance %d%% for finished runs)\n", checked, tolerance))
+
+		sort.Slice(breaches, func(i, j int) bool {
+			if breaches[i].Running != breaches[j].Running {
+				return breaches[i].Running
+			}
+			return breaches[i].Duration-breaches[i].SLA > breaches[j].Duration-breaches[j].SLA
+		})
+		var actions []string
+		for _, b := range breaches {
+			over := float64(b.Duration-b.SLA) / float64(b.SLA) * 100
+			if b.Running {
+				found = append(found, containerFinding{"KD-WL-009", "CRITICAL", fmt.Sprintf("Job %s is still running after %s, past its %s SLA (+%.0f%%)",
+					b.Job, b.Duration.Round(time.Second), b.SLA, over)})
+				actions = append(actions, fmt.Sprintf("Run diagnose_job on %s to see whether it is stuck or just slow", b.Job))
+				continue
+			}
+			found = append(found, containerFinding{"KD-WL-009", "WARNING", fmt.Sprintf("Job %s ran %s, %.0f%% over its %s SLA",
+				b.Job, b.Duration.Round(time.Second), over, b.SLA)})
+		}
+		if len(breaches) > 0 {
+			actions = append(actions, "Compare slow runs with get_pod_metrics and get_events for CPU throttling, node pressure, or slow dependencies")
+			actions = append(actions, "If the workload has grown, raise the "+jobSLAAnnotation+" annotation or split the work across more parallel pods")
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(found) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "All checked Job runs finished within their SLA"))
+			sb.WriteString("\n")
+		}
+		for _, f := range found {
+			sb.WriteString(findings.Format(f.rule, f.severity, f.message))
+			sb.WriteString("\n")
+		}
+		if checked == 0 {
+			sb.WriteString(fmt.Sprintf("\nNo Jobs have an SLA. Annotate a CronJob with %s: \"<duration>\" (e.g. \"15m\") to enable SLA checks.\n", jobSLAAnnotation))
+		}
+
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// parseJobSLA reads the expected-duration SLA annotation. ok is false when
+// the annotation is absent.
+func parseJobSLA(annotations map[string]string) (sla time.Duration, ok bool, err error) {
+	v, ok := annotations[jobSLAAnnotation]
+	if !ok {
+		return 0, false, nil
+	}
+	sla, err = time.ParseDuration(strings.TrimSpace(v))
+	if err != nil || sla <= 0 {
+		return 0, false, fmt.Errorf("invalid %s annotation %q: want a positive Go duration such as \"15m\"", jobSLAAnnotation, v)
+	}
+	return sla, true, nil
+}
+
+// jobCronJobOwner returns the name of the CronJob that created a Job, if any.
+func jobCronJobOwner(j *batchv1.Job) string {
+	for _, ref := range j.OwnerReferences {
+		if ref.Kind == "CronJob" {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+// checkJobSLA reports whether a Job breached its SLA. Running Jobs breach as
+// soon as they pass the SLA; finished Jobs only beyond the tolerance.
+func checkJobSLA(j *batchv1.Job, owner string, sla time.Duration, tolerancePercent int) (jobSLABreach, bool) {
+	b := jobSLABreach{
+		Job:      j.Namespace + "/" + j.Name,
+		Owner:    owner,
+		SLA:      sla,
+		Duration: jobElapsed(j),
+		Running:  jobStatus(j) == "Running",
+	}
+	if owner != "" {
+		b.Owner = j.Namespace + "/" + owner
+	}
+	limit := sla
+	if !b.Running {
+		limit = sla + sla*time.Duration(tolerancePercent)/100
+	}
+	return b, b.Duration > limit
+}
+
+// jobAttempts summarizes a Job's pods, oldest first.
+func jobAttempts(pods []corev1.Pod) []jobAttempt {
+	sort.SliceStable(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+	})
+	attempts := make([]jobAttempt, 0, len(pods))
+	for i := range pods {
+		attempts = append(attempts, podJobAttempt(&pods[i]))
+	}
+	return attempts
+}
+
+// podJobAttempt summarizes one Job pod by the first container that failed.
+func podJobAttempt(p *corev1.Pod) jobAttempt {
+	a := jobAttempt{
+		Pod:     p.Name,
+		Node:    p.Spec.NodeName,
+		Created: p.CreationTimestamp.Time,
+		Status:  string(p.Status.Phase),
+		Reason:  p.Status.Reason,
+		Failed:  p.Status.Phase == corev1.PodFailed,
+	}
+	if p.Status.StartTime != nil {
+		end := time.Now()
+		for _, cs := range p.Status.ContainerStatuses {
+			if t := cs.State.Terminated; t != nil && !t.FinishedAt.IsZero() {
+				end = t.FinishedAt.Time
+			}
+		}
+		a.Duration = end.Sub(p.Status.StartTime.Time)
+	}
+	for _, cond := range p.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+			a.Pending = cond.Message
+		}
+	}
+
+	for _, cs := range p.Status.ContainerStatuses {
+		a.Restarts += cs.RestartCount
+		if a.Container != "" {
+			continue
+		}
+		switch {
+		case cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0:
+			a.Container, a.ExitCode, a.Reason = cs.Name, cs.State.Terminated.ExitCode, cs.State.Terminated.Reason
+			a.Failed = true
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "ContainerCreating":
+			a.Container, a.Reason = cs.Name, cs.State.Waiting.Reason
+			a.Failed = true
+			if t := cs.LastTerminationState.Terminated; t != nil && t.ExitCode != 0 {
+				a.ExitCode, a.Previous = t.ExitCode, true
+				if t.Reason != "" {
+					a.Reason = t.Reason
+				}
+			}
+		}
+	}
+	if a.Failed && a.Reason != "" && a.Status != string(corev1.PodFailed) {
+		a.Status = a.Reason
+	}
+	if a.Pending != "" {
+		a.Status = "Unschedulable"
+	}
+	return a
+}
+
+// jobBackoffLimit returns the Job's backoffLimit, applying the API default.
+func jobBackoffLimit(job *batchv1.Job) int32 {
+	if job.Spec.BackoffLimit != nil {
+		return *job.Spec.BackoffLimit
+	}
+	return jobDefaultBackoffLimit
+}
+
+// jobElapsed returns how long the Job has been active, which is what activeDeadlineSeconds limits.
+func jobElapsed(job *batchv1.Job) time.Duration {
+	if job.Status.StartTime == nil {
+		return 0
+	}
+	end := time.Now()
+	if job.Status.CompletionTime != nil {
+		end = job.Status.CompletionTime.Time
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			end = cond.LastTransitionTime.Time
+		}
+	}
+	return end.Sub(job.Status.StartTime.Time)
+}
+
+// jobFailedCondition returns the Job's Failed condition if it is true.
+func jobFailedCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		cond := &job.Status.Conditions[i]
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			return cond
+		}
+	}
+	return nil
+}
+
+// jobStatus renders a one-word Job status.
+func jobStatus(job *batchv1.Job) string {
+	if cond := jobFailedCondition(job); cond != nil {
+		return fmt.Sprintf("Failed (%s)", cond.Reason)
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobComplete && cond.Status == corev1.ConditionTrue {
+			return "Complete"
+		}
+	}
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		return "Suspended"
+	}
+	return "Running"
+}
+
+// jobFindings reports backoff, deadline, and per-attempt problems for a Job.
+func jobFindings(job *batchv1.Job, attempts []jobAttempt) []containerFinding {
+	var found []containerFinding
+	backoffLimit := jobBackoffLimit(job)
+
+	if cond := jobFailedCondition(job); cond != nil {
+		switch cond.Reason {
+		case "BackoffLimitExceeded":
+			found = append(found, containerFinding{"KD-WL-009", "CRITICAL", fmt.Sprintf("backoffLimit exhausted: %d failed attempts (limit %d)", job.Status.Failed, backoffLimit)})
+		case "DeadlineExceeded":
+			found = append(found, containerFinding{"KD-WL-009", "CRITICAL", fmt.Sprintf("activeDeadlineSeconds (%ds) exceeded: %s", derefInt64(job.Spec.ActiveDeadlineSeconds), cond.Message)})
+		default:
+			found = append(found, containerFinding{"KD-WL-009", "CRITICAL", fmt.Sprintf("Job failed (%s): %s", cond.Reason, cond.Message)})
+		}
+	} else if jobStatus(job) == "Running" {
+		if job.Status.Failed > 0 && job.Status.Failed >= backoffLimit-1 {
+			found = append(found, containerFinding{"KD-WL-009", "WARNING", fmt.Sprintf("%d failed attempts, one more failure exhausts backoffLimit %d", job.Status.Failed, backoffLimit)})
+		}
+		if d := job.Spec.ActiveDeadlineSeconds; d != nil && jobElapsed(job) > time.Duration(float64(*d)*0.8)*time.Second {
+			found = append(found, containerFinding{"KD-WL-009", "WARNING", fmt.Sprintf("Job has used %s of its %ds activeDeadlineSeconds", jobElapsed(job).Round(time.Second), *d)})
+		}
+	}
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		found = append(found, containerFinding{"KD-WL-009", "INFO", "Job is suspended; no pods will be created until spec.suspend is false"})
+	}
+	if job.Status.FailedIndexes != nil && *job.Status.FailedIndexes != "" {
+		found = append(found, containerFinding{"KD-WL-009", "WARNING", fmt.Sprintf("Failed completion indexes: %s", *job.Status.FailedIndexes)})
+	}
+
+	for i, a := range attempts {
+		switch {
+		case a.Pending != "":
+			found = append(found, containerFinding{"KD-POD-004", "WARNING", fmt.Sprintf("Attempt %d (%s) cannot be scheduled: %s", i+1, a.Pod, a.Pending)})
+		case a.Failed && a.Container == "":
+			found = append(found, containerFinding{"KD-WL-009", "WARNING", fmt.Sprintf("Attempt %d (%s) failed: %s", i+1, a.Pod, util.JoinNonEmpty(" ", a.Reason, "(pod-level failure)"))})
+		case a.Failed:
+			msg := fmt.Sprintf("Attempt %d (%s) container '%s' failed: %s", i+1, a.Pod, a.Container, a.Reason)
+			if a.ExitCode != 0 {
+				msg = fmt.Sprintf("Attempt %d (%s) container '%s' exited %s", i+1, a.Pod, a.Container, formatExitCode(a.ExitCode, a.Reason))
+				if meaning := exitCodeMeaning(a.ExitCode); meaning != "" {
+					msg += " - " + meaning
+				}
+			}
+			found = append(found, containerFinding{"KD-WL-009", "WARNING", msg})
+		}
+	}
+	return found
+}
+
+// jobNextSteps returns the follow-up tool calls for a Job's failed or
+// unschedulable attempts: logs of the latest failure and, depending on its
+// reason, its node or resource usage.
+func jobNextSteps(job *batchv1.Job, attempts []jobAttempt) []util.NextStep {
+	var steps []util.NextStep
+	for i := len(attempts) - 1; i >= 0; i-- {
+		a := attempts[i]
+		switch {
+		case a.Pending != "":
+			steps = append(steps, nextStep("diagnose_pod", "Attempt is "+a.Pending, "namespace", job.Namespace, "name", a.Pod))
+		case a.Failed:
+			steps = append(steps, nextStep("get_pod_logs", fmt.Sprintf("Latest failed attempt (%s)", formatExitCode(a.ExitCode, a.Reason)),
+				"namespace", job.Namespace, "name", a.Pod, "container", a.Container, "previous", a.Previous))
+			switch {
+			case a.Reason == "OOMKilled":
+				steps = append(steps, nextStep("analyze_resource_usage", "Attempt was OOMKilled", "namespace", job.Namespace, "per_container", true))
+			case a.Reason == "Evicted" && a.Node != "":
+				steps = append(steps, nextStep("get_node_detail", "Attempt was evicted by node pressure", "name", a.Node))
+			}
+		default:
+			continue
+		}
+		break
+	}
+	return steps
+}
+
+// jobFailureCause summarizes the most probable reason a Job failed, or is
+// failing, and the actions that address it.
+func jobFailureCause(job *batchv1.Job, attempts []jobAttempt) (string, []string) {
+	reasons := make(map[string]int)
+	exitCodes := make(map[int32]int)
+	failed, pending := 0, 0
+	for _, a := range attempts {
+		if a.Pending != "" {
+			pending++
+		}
+		if !a.Failed {
+			continue
+		}
+		failed++
+		reasons[a.Reason]++
+		if a.ExitCode != 0 {
+			exitCodes[a.ExitCode]++
+		}
+	}
+
+	if cond := jobFailedCondition(job); cond != nil && cond.Reason == "DeadlineExceeded" {
+		return fmt.Sprintf("The Job ran longer than activeDeadlineSeconds (%ds) and was terminated", derefInt64(job.Spec.ActiveDeadlineSeconds)), []string{
+			"Compare attempt durations with activeDeadlineSeconds; raise the deadline if the work legitimately takes longer",
+			"If attempts hang, check the logs for a stuck dependency (database, API, lock) and add client-side timeouts",
+		}
+	}
+	switch {
+	case reasons["OOMKilled"] > 0:
+		return fmt.Sprintf("%d attempt(s) were OOMKilled", reasons["OOMKilled"]), []string{
+			"Increase the memory limit of the Job container or reduce the batch size it processes",
+			"Run analyze_resource_usage with per_container=true on a running attempt to see actual memory usage",
+		}
+	case reasons["ErrImagePull"]+reasons["ImagePullBackOff"]+reasons["InvalidImageName"] > 0:
+		return "The Job image cannot be pulled", []string{
+			"Check the image name and tag in the Job template",
+			"Check imagePullSecrets and registry credentials",
+		}
+	case reasons["CreateContainerConfigError"] > 0:
+		return "Containers cannot be created because of a missing ConfigMap, Secret, or key", []string{
+			fmt.Sprintf("Run get_events for namespace %s to find the missing ConfigMap or Secret", job.Namespace),
+		}
+	case reasons["Evicted"] > 0:
+		return fmt.Sprintf("%d attempt(s) were evicted by node pressure", reasons["Evicted"]), []string{
+			"Run get_node_detail on the attempt's node to check for memory or disk pressure",
+			"Set resource requests so the Job is not the first to be evicted",
+		}
+	case pending > 0 && failed == 0:
+		return "Job pods cannot be scheduled", []string{
+			"Check node selectors, tolerations, and affinity in the Job template",
+			"Run analyze_node_capacity to check for free CPU and memory",
+		}
+	case len(exitCodes) == 1:
+		for code := range exitCodes {
+			cause := fmt.Sprintf("Every failed attempt exited with code %d", code)
+			if meaning := exitCodeMeaning(code); meaning != "" {
+				cause += " (" + meaning + ")"
+			}
+			cause += " - a deterministic failure that retries will not fix"
+			return cause, []string{
+				"Read the logs above for the error; check the Job's arguments, configuration, and input data",
+				"Fix the cause before re-running; raising backoffLimit only repeats the same failure",
+			}
+		}
+	case len(exitCodes) > 1:
+		return fmt.Sprintf("Attempts failed with %d different exit codes - likely an intermittent dependency or flaky workload", len(exitCodes)), []string{
+			"Compare the logs of the failed attempts for a common dependency error",
+			"Add retries inside the workload, or raise backoffLimit if failures are transient",
+		}
+	case failed > 0:
+		return fmt.Sprintf("%d attempt(s) failed", failed), []string{
+			fmt.Sprintf("Run diagnose_pod on %s for details", attempts[len(attempts)-1].Pod),
+		}
+	}
+
+	if cond := jobFailedCondition(job); cond != nil {
+		return fmt.Sprintf("The Job failed (%s) but no failed pods remain to inspect", cond.Reason), []string{
+			"Set ttlSecondsAfterFinished high enough to keep failed pods for inspection",
+			fmt.Sprintf("Run get_events for namespace %s to see what happened to the pods", job.Namespace),
+		}
+	}
+	switch jobStatus(job) {
+	case "Complete":
+		return "The Job completed successfully", nil
+	case "Suspended":
+		return "The Job is suspended", []string{"Set spec.suspend to false to resume the Job"}
+	}
+	return fmt.Sprintf("The Job is still running with %d active pod(s) and no failures", job.Status.Active), nil
+}
+
+// derefInt64 returns the value of p, or 0 if it is nil.
+func derefInt64(p *int64) int64 {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func failedJobPod(name string, exitCode int32, reason string) corev1.Pod {
+	pod := testPod("batch", name, "", nil)
+	pod.Status = corev1.PodStatus{
+		Phase: corev1.PodFailed,
+		ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "app",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Reason: reason}},
+		}},
+	}
+	return pod
+}
+
+func TestJobFailureCause(t *testing.T) {
+	backoffExceeded := &batchv1.Job{Status: batchv1.JobStatus{
+		Failed:     3,
+		Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"}},
+	}}
+
+	tests := []struct {
+		name string
+		job  *batchv1.Job
+		pods []corev1.Pod
+		want string
+	}{
+		{
+			name: "deterministic exit code",
+			job:  backoffExceeded,
+			pods: []corev1.Pod{failedJobPod("a", 2, "Error"), failedJobPod("b", 2, "Error"), failedJobPod("c", 2, "Error")},
+			want: "Every failed attempt exited with code 2",
+		},
+		{
+			name: "oom",
+			job:  backoffExceeded,
+			pods: []corev1.Pod{failedJobPod("a", 137, "OOMKilled"), failedJobPod("b", 1, "Error")},
+			want: "OOMKilled",
+		},
+		{
+			name: "flaky",
+			job:  backoffExceeded,
+			pods: []corev1.Pod{failedJobPod("a", 1, "Error"), failedJobPod("b", 143, "Error")},
+			want: "different exit codes",
+		},
+		{
+			name: "deadline",
+			job: &batchv1.Job{Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "DeadlineExceeded"}},
+			}},
+			pods: []corev1.Pod{failedJobPod("a", 143, "Error")},
+			want: "activeDeadlineSeconds",
+		},
+	}
+	for _, tt := range tests {
+		cause, actions := jobFailureCause(tt.job, jobAttempts(tt.pods))
+		if !strings.Contains(cause, tt.want) {
+			t.Errorf("%s: cause = %q, want it to contain %q", tt.name, cause, tt.want)
+		}
+		if len(actions) == 0 {
+			t.Errorf("%s: expected suggested actions", tt.name)
+		}
+	}
+}
+
+func TestJobFindingsBackoffExhausted(t *testing.T) {
+	limit := int32(2)
+	job := &batchv1.Job{
+		Spec: batchv1.JobSpec{BackoffLimit: &limit},
+		Status: batchv1.JobStatus{
+			Failed:     3,
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"}},
+		},
+	}
+	findings := jobFindings(job, nil)
+	if len(findings) != 1 || findings[0].severity != "CRITICAL" || !strings.Contains(findings[0].message, "limit 2") {
+		t.Errorf("expected one CRITICAL backoffLimit finding, got %+v", findings)
+	}
+}
+
+func TestCheckJobSLA(t *testing.T) {
+	start := metav1.NewTime(time.Now().Add(-20 * time.Minute))
+	done := metav1.NewTime(start.Add(11 * time.Minute))
+	finished := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "report-1", Namespace: "batch"},
+		Status: batchv1.JobStatus{
+			StartTime:      &start,
+			CompletionTime: &done,
+			Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+		},
+	}
+	if _, breached := checkJobSLA(finished, "report", 10*time.Minute, 20); breached {
+		t.Error("expected a run 10% over its SLA to be within the 20% tolerance")
+	}
+	if b, breached := checkJobSLA(finished, "report", 5*time.Minute, 20); !breached || b.Owner != "batch/report" {
+		t.Errorf("expected breach owned by batch/report, got %+v (breached=%v)", b, breached)
+	}
+
+	running := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "report-2", Namespace: "batch"},
+		Status:     batchv1.JobStatus{StartTime: &start, Active: 1},
+	}
+	if b, breached := checkJobSLA(running, "", 18*time.Minute, 20); !breached || !b.Running {
+		t.Errorf("expected running Job past its SLA to breach regardless of tolerance, got %+v", b)
+	}
+}
+
+func TestParseJobSLA(t *testing.T) {
+	if _, ok, err := parseJobSLA(nil); ok || err != nil {
+		t.Errorf("parseJobSLA(nil) = ok %v, err %v; want absent", ok, err)
+	}
+	if sla, ok, err := parseJobSLA(map[string]string{jobSLAAnnotation: "15m"}); !ok || err != nil || sla != 15*time.Minute {
+		t.Errorf("parseJobSLA(15m) = %v, %v, %v", sla, ok, err)
+	}
+	if _, _, err := parseJobSLA(map[string]string{jobSLAAnnotation: "soon"}); err == nil {
+		t.Error("expected an error for an invalid duration")
+	}
+}
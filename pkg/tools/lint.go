@@ -0,0 +1,310 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type lintWorkloadsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all non-system namespaces)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// lintTarget is a replicated workload to lint.
+type lintTarget struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Replicas  int32
+	Selector  *metav1.LabelSelector
+	Template  corev1.PodTemplateSpec
+}
+
+// lintViolation is a single best-practice rule a workload fails. ID is the
+// catalog rule it reports under.
+type lintViolation struct {
+	Rule     string
+	ID       string
+	Severity string
+	Detail   string
+	Penalty  int
+}
+
+func registerLintTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// lint_workloads
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "lint_workloads",
+		Description: "Score Deployments and StatefulSets against production best practices: single replica, :latest or untagged images, no pod anti-affinity or topology spread, no PodDisruptionBudget, no priorityClass, missing probes, missing resource requests, and hostPath mounts. Returns a per-workload grade (A-F) and a summary table.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input lintWorkloadsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+
+		var targets []lintTarget
+		deployments, err := client.ListDeployments(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing deployments", err), nil, nil
+		}
+		for _, d := range deployments {
+			targets = append(targets, lintTarget{Kind: "Deployment", Namespace: d.Namespace, Name: d.Name,
+				Replicas: replicasOrDefault(d.Spec.Replicas), Selector: d.Spec.Selector, Template: d.Spec.Template})
+		}
+		statefulsets, err := client.ListStatefulSets(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing statefulsets", err), nil, nil
+		}
+		for _, s := range statefulsets {
+			targets = append(targets, lintTarget{Kind: "StatefulSet", Namespace: s.Namespace, Name: s.Name,
+				Replicas: replicasOrDefault(s.Spec.Replicas), Selector: s.Spec.Selector, Template: s.Spec.Template})
+		}
+		if input.Namespace == "" {
+			filtered := targets[:0]
+			for _, t := range targets {
+				if !isSystemNamespace(t.Namespace) {
+					filtered = append(filtered, t)
+				}
+			}
+			targets = filtered
+		}
+
+		pdbs, err := client.ListPodDisruptionBudgets(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pod disruption budgets", err), nil, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Workload Lint (namespace: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+
+		if len(targets) == 0 {
+			sb.WriteString("No Deployments or StatefulSets found.\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		sort.Slice(targets, func(i, j int) bool {
+			if targets[i].Namespace != targets[j].Namespace {
+				return targets[i].Namespace < targets[j].Namespace
+			}
+			return targets[i].Name < targets[j].Name
+		})
+
+		headers := []string{"WORKLOAD", "NAMESPACE", "REPLICAS", "SCORE", "GRADE", "ISSUES"}
+		rows := make([][]string, 0, len(targets))
+		gradeCounts := make(map[string]int)
+		ruleCounts := make(map[string]int)
+		var details strings.Builder
+		totalScore := 0
+
+		for _, t := range targets {
+			violations := lintWorkload(t, pdbs)
+			score := 100
+			var rules []string
+			for _, v := range violations {
+				score -= v.Penalty
+				rules = append(rules, v.Rule)
+				ruleCounts[v.Rule]++
+			}
+			if score < 0 {
+				score = 0
+			}
+			grade := scoreGrade(score)
+			gradeCounts[grade]++
+			totalScore += score
+
+			issues := "-"
+			if len(rules) > 0 {
+				issues = strings.Join(rules, ", ")
+			}
+			rows = append(rows, []string{
+				fmt.Sprintf("%s/%s", t.Kind, truncateName(t.Name, 40)),
+				t.Namespace,
+				fmt.Sprintf("%d", t.Replicas),
+				fmt.Sprintf("%d", score),
+				grade,
+				truncateName(issues, 60),
+			})
+
+			if len(violations) > 0 {
+				details.WriteString(fmt.Sprintf("\n%s/%s/%s (grade %s):\n", t.Namespace, t.Kind, t.Name, grade))
+				for _, v := range violations {
+					details.WriteString(findings.Format(v.ID, v.Severity, fmt.Sprintf("%s: %s", v.Rule, v.Detail)))
+					details.WriteString("\n")
+				}
+			}
+		}
+
+		sb.WriteString(util.FormatTable(headers, rows))
+
+		if details.Len() > 0 {
+			sb.WriteString("\nFINDINGS:")
+			sb.WriteString(details.String())
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Summary"))
+		sb.WriteString("\n")
+		avg := totalScore / len(targets)
+		sb.WriteString(fmt.Sprintf("  Workloads linted: %d\n", len(targets)))
+		sb.WriteString(fmt.Sprintf("  Average score: %d/100 (Grade: %s)\n", avg, scoreGrade(avg)))
+		var gradeParts []string
+		for _, g := range []string{"A", "B", "C", "D", "F"} {
+			gradeParts = append(gradeParts, fmt.Sprintf("%s=%d", g, gradeCounts[g]))
+		}
+		sb.WriteString(fmt.Sprintf("  Grades: %s\n", strings.Join(gradeParts, " ")))
+
+		if len(ruleCounts) > 0 {
+			sb.WriteString("\n")
+			ruleHeaders := []string{"RULE", "WORKLOADS"}
+			ruleRows := make([][]string, 0, len(ruleCounts))
+			for _, rc := range topCounts(ruleCounts, len(ruleCounts)) {
+				ruleRows = append(ruleRows, []string{rc.key, fmt.Sprintf("%d", rc.count)})
+			}
+			sb.WriteString(util.FormatTable(ruleHeaders, ruleRows))
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// lintWorkload evaluates a workload against the best-practice rule set.
+func lintWorkload(t lintTarget, pdbs []policyv1.PodDisruptionBudget) []lintViolation {
+	var out []lintViolation
+	spec := t.Template.Spec
+
+	if t.Replicas <= 1 {
+		out = append(out, lintViolation{"single-replica", "KD-WL-002", "WARNING", fmt.Sprintf("%d replica(s) — any restart or node drain causes downtime", t.Replicas), 15})
+	}
+
+	for _, c := range allContainers(spec) {
+		if tag := imageTag(c.Image); tag == "" || tag == "latest" {
+			out = append(out, lintViolation{"mutable-image", "KD-IMG-001", "WARNING", fmt.Sprintf("container '%s' uses %s (untagged or :latest)", c.Name, c.Image), 15})
+			break
+		}
+	}
+
+	if t.Replicas > 1 && !hasSpreadConstraints(spec) {
+		out = append(out, lintViolation{"no-spread", "KD-WL-008", "WARNING", "no pod anti-affinity or topologySpreadConstraints — replicas may land on the same node", 10})
+	}
+
+	if !hasMatchingPDB(t, pdbs) {
+		out = append(out, lintViolation{"no-pdb", "KD-WL-003", "WARNING", "no PodDisruptionBudget selects these pods", 10})
+	}
+
+	if spec.PriorityClassName == "" {
+		out = append(out, lintViolation{"no-priority-class", "KD-WL-010", "INFO", "no priorityClassName — pods get default priority under resource pressure", 5})
+	}
+
+	var noProbes, noRequests []string
+	for _, c := range spec.Containers {
+		if c.ReadinessProbe == nil && c.LivenessProbe == nil {
+			noProbes = append(noProbes, c.Name)
+		}
+		if c.Resources.Requests.Cpu().IsZero() && c.Resources.Requests.Memory().IsZero() {
+			noRequests = append(noRequests, c.Name)
+		}
+	}
+	if len(noProbes) > 0 {
+		out = append(out, lintViolation{"missing-probes", "KD-PRB-001", "WARNING", fmt.Sprintf("no readiness or liveness probe on: %s", strings.Join(noProbes, ", ")), 10})
+	}
+	if len(noRequests) > 0 {
+		out = append(out, lintViolation{"no-requests", "KD-RES-002", "WARNING", fmt.Sprintf("no CPU/memory requests on: %s", strings.Join(noRequests, ", ")), 15})
+	}
+
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			out = append(out, lintViolation{"hostpath-mount", "KD-SEC-006", "CRITICAL", fmt.Sprintf("volume '%s' mounts host path %s", v.Name, v.HostPath.Path), 20})
+		}
+	}
+
+	return out
+}
+
+// allContainers returns init and regular containers of a pod spec.
+func allContainers(spec corev1.PodSpec) []corev1.Container {
+	out := make([]corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	out = append(out, spec.InitContainers...)
+	return append(out, spec.Containers...)
+}
+
+// imageTag returns the tag of an image reference, "" if untagged. Digest-pinned
+// images return the digest.
+func imageTag(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[i+1:]
+	}
+	// A colon after the last slash is a tag; before it, a registry port.
+	name := image
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
+
+// hasSpreadConstraints reports whether the pod spec spreads replicas via
+// anti-affinity or topology spread constraints.
+func hasSpreadConstraints(spec corev1.PodSpec) bool {
+	if len(spec.TopologySpreadConstraints) > 0 {
+		return true
+	}
+	if a := spec.Affinity; a != nil && a.PodAntiAffinity != nil {
+		return len(a.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) > 0 ||
+			len(a.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) > 0
+	}
+	return false
+}
+
+// hasMatchingPDB reports whether any PDB in the workload's namespace selects its pods.
+func hasMatchingPDB(t lintTarget, pdbs []policyv1.PodDisruptionBudget) bool {
+	podLabels := labels.Set(t.Template.Labels)
+	for _, pdb := range pdbs {
+		if pdb.Namespace != t.Namespace || pdb.Spec.Selector == nil {
+			continue
+		}
+		sel, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || sel.Empty() {
+			continue
+		}
+		if sel.Matches(podLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+// replicasOrDefault returns the replica count, defaulting to 1 when unset.
+func replicasOrDefault(r *int32) int32 {
+	if r == nil {
+		return 1
+	}
+	return *r
+}
+
+// scoreGrade converts a 0-100 score to a letter grade.
+func scoreGrade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestImageTag(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                           "",
+		"nginx:latest":                    "latest",
+		"registry.local:5000/app":         "",
+		"registry.local:5000/app:1.2.3":   "1.2.3",
+		"ghcr.io/org/app@sha256:abcdef01": "sha256:abcdef01",
+	}
+	for image, want := range tests {
+		if got := imageTag(image); got != want {
+			t.Errorf("imageTag(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestLintWorkload(t *testing.T) {
+	podLabels := map[string]string{"app": "api"}
+
+	bad := lintTarget{
+		Kind: "Deployment", Namespace: "prod", Name: "api", Replicas: 1,
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "api", Image: "api:latest"}},
+				Volumes: []corev1.Volume{
+					{Name: "docker", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/docker.sock"}}},
+				},
+			},
+		},
+	}
+	rules := make(map[string]bool)
+	for _, v := range lintWorkload(bad, nil) {
+		rules[v.Rule] = true
+	}
+	for _, want := range []string{"single-replica", "mutable-image", "no-pdb", "no-priority-class", "missing-probes", "no-requests", "hostpath-mount"} {
+		if !rules[want] {
+			t.Errorf("expected rule %q to fire, got %v", want, rules)
+		}
+	}
+
+	good := lintTarget{
+		Kind: "Deployment", Namespace: "prod", Name: "api", Replicas: 3,
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+			Spec: corev1.PodSpec{
+				PriorityClassName: "high",
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", LabelSelector: &metav1.LabelSelector{MatchLabels: podLabels}},
+				},
+				Containers: []corev1.Container{{
+					Name:           "api",
+					Image:          "api:1.4.2",
+					ReadinessProbe: &corev1.Probe{},
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("100m"),
+					}},
+				}},
+			},
+		},
+	}
+	pdbs := []policyv1.PodDisruptionBudget{{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "prod"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: podLabels}},
+	}}
+	if v := lintWorkload(good, pdbs); len(v) != 0 {
+		t.Errorf("expected no violations, got %+v", v)
+	}
+}
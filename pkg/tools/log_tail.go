@@ -0,0 +1,390 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// logTailDefaultHistory is how many earlier lines a tail includes for context.
+	logTailDefaultHistory int64 = 10
+	// logTailMaxPods caps how many pods tail_workload_logs follows at once.
+	logTailMaxPods = 20
+	// logTailMinPodBytes is the smallest per-pod share of the byte budget.
+	logTailMinPodBytes = 4 * 1024
+)
+
+// logErrorPattern matches log lines that look like errors.
+var logErrorPattern = regexp.MustCompile(`(?i)(error|exception|fatal|panic|timeout|refused|failed|crash|oom)`)
+
+type tailPodLogsInput struct {
+	Namespace       string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Name            string `json:"name" jsonschema:"required,Pod name"`
+	Container       string `json:"container,omitempty" jsonschema:"Container name (required for multi-container pods)"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" jsonschema:"How long to follow the logs (default 15, max 120)"`
+	MaxBytes        int    `json:"max_bytes,omitempty" jsonschema:"Stop once this many bytes are captured (default 51200, max 262144)"`
+	TailLines       *int64 `json:"tail_lines,omitempty" jsonschema:"Lines of history to include before following (default 10; 0 for new lines only)"`
+	Timestamps      bool   `json:"timestamps,omitempty" jsonschema:"Prefix each line with its timestamp"`
+}
+
+type tailWorkloadLogsInput struct {
+	Namespace       string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	WorkloadName    string `json:"workload_name" jsonschema:"required,Name of the Deployment, StatefulSet, or DaemonSet"`
+	WorkloadKind    string `json:"workload_kind,omitempty" jsonschema:"Kind: Deployment, StatefulSet, or DaemonSet (default: Deployment)"`
+	Container       string `json:"container,omitempty" jsonschema:"Container to follow in every pod (default: the first container)"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" jsonschema:"How long to follow the logs (default 15, max 120)"`
+	MaxBytes        int    `json:"max_bytes,omitempty" jsonschema:"Byte budget shared by all pods (default 51200, max 262144)"`
+	TailLines       *int64 `json:"tail_lines,omitempty" jsonschema:"Lines of history per pod to include before following (default 10; 0 for new lines only)"`
+	ErrorsOnly      bool   `json:"errors_only,omitempty" jsonschema:"Only show lines that look like errors"`
+}
+
+// podLogLine is one timestamped line from a workload's pods.
+type podLogLine struct {
+	Time  time.Time
+	Pod   string
+	Text  string
+	Error bool
+}
+
+// podLogTail is what tail_workload_logs captured from one pod.
+type podLogTail struct {
+	Pod    string
+	Window *k8s.LogWindow
+	Err    error
+}
+
+func registerLogTailTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// tail_pod_logs
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "tail_pod_logs",
+		Description: "Follow a pod container's logs live for a bounded window (default 15s) or byte budget and return what was written, " +
+			"to watch a crash or a request failure as it happens. Reports whether the stream ended early because the container exited, with its termination reason.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input tailPodLogsInput) (*mcp.CallToolResult, any, error) {
+		if input.Namespace == "" || input.Name == "" {
+			return util.ErrorResult("namespace and name are required"), nil, nil
+		}
+		pod, err := client.GetPod(ctx, input.Namespace, input.Name)
+		if err != nil {
+			return util.HandleK8sError(fmt.Sprintf("getting pod %s/%s", input.Namespace, input.Name), err), nil, nil
+		}
+		container := input.Container
+		if container == "" {
+			if len(pod.Spec.Containers) > 1 {
+				names := make([]string, 0, len(pod.Spec.Containers))
+				for _, c := range pod.Spec.Containers {
+					names = append(names, c.Name)
+				}
+				return util.ErrorResult("pod %s/%s has several containers; set container to one of: %s", pod.Namespace, pod.Name, strings.Join(names, ", ")), nil, nil
+			}
+			container = pod.Spec.Containers[0].Name
+		}
+
+		opts := logTailOptions(input.DurationSeconds, input.MaxBytes, input.TailLines, input.Timestamps)
+		opts.Container = container
+		window, err := client.StreamPodLogs(ctx, pod.Namespace, pod.Name, opts)
+		if err != nil {
+			return util.HandleK8sError(fmt.Sprintf("following logs for %s/%s", pod.Namespace, pod.Name), err), nil, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Log Tail: %s/%s (container: %s)", pod.Namespace, pod.Name, container)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Window", fmt.Sprintf("%s requested, %s followed", opts.Duration, window.Elapsed.Round(100*time.Millisecond))) + "\n")
+		sb.WriteString(util.FormatKeyValue("Stopped", logTailStopReason(window, opts)) + "\n")
+		sb.WriteString(util.FormatKeyValue("Captured", fmt.Sprintf("%d lines, %s", len(window.Lines), formatBytes(int64(window.Bytes)))) + "\n")
+
+		var steps []util.NextStep
+		if window.Ended {
+			if latest, err := client.GetPod(ctx, pod.Namespace, pod.Name); err == nil {
+				pod = latest
+			}
+			if state := containerStateSummary(pod, container); state != "" {
+				sb.WriteString(util.FormatKeyValue("Container State", state) + "\n")
+			}
+			steps = append(steps,
+				nextStep("get_pod_logs", "read the output of the container run that just exited", "namespace", pod.Namespace, "name", pod.Name, "container", container, "previous", true),
+				nextStep("diagnose_pod", "explain why the container exited", "namespace", pod.Namespace, "name", pod.Name))
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("LOGS"))
+		sb.WriteString("\n")
+		if len(window.Lines) == 0 {
+			sb.WriteString("(no log lines written during the window)\n")
+		}
+		for _, line := range window.Lines {
+			sb.WriteString(line + "\n")
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+
+	// tail_workload_logs
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "tail_workload_logs",
+		Description: "Follow the logs of every pod of a Deployment, StatefulSet, or DaemonSet at once for a bounded window (default 15s), " +
+			"interleaved by timestamp with a pod prefix on each line and error lines marked with '!!'. A quick stern-style view for incident triage.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input tailWorkloadLogsInput) (*mcp.CallToolResult, any, error) {
+		if input.Namespace == "" || input.WorkloadName == "" {
+			return util.ErrorResult("namespace and workload_name are required"), nil, nil
+		}
+		kind, selector, template, err := workloadPodSelector(ctx, client, input.Namespace, input.WorkloadName, input.WorkloadKind)
+		if err != nil {
+			return util.HandleK8sError(fmt.Sprintf("getting %s %s/%s", kind, input.Namespace, input.WorkloadName), err), nil, nil
+		}
+		if selector == "" {
+			return util.ErrorResult("%s %s/%s has no pod selector", kind, input.Namespace, input.WorkloadName), nil, nil
+		}
+		container := input.Container
+		if container == "" && len(template.Spec.Containers) > 0 {
+			container = template.Spec.Containers[0].Name
+		}
+
+		pods, err := client.ListPods(ctx, input.Namespace, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		var running []corev1.Pod
+		for _, p := range pods {
+			if p.Status.Phase == corev1.PodRunning || p.Status.Phase == corev1.PodPending {
+				running = append(running, p)
+			}
+		}
+		sort.Slice(running, func(i, j int) bool { return running[i].Name < running[j].Name })
+		skipped := 0
+		if len(running) > logTailMaxPods {
+			skipped = len(running) - logTailMaxPods
+			running = running[:logTailMaxPods]
+		}
+
+		ref := fmt.Sprintf("%s %s/%s", kind, input.Namespace, input.WorkloadName)
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Workload Log Tail: %s (container: %s)", ref, container)))
+		sb.WriteString("\n")
+		if len(running) == 0 {
+			sb.WriteString(fmt.Sprintf("No running or pending pods match %s.\n", ref))
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		opts := logTailOptions(input.DurationSeconds, input.MaxBytes, input.TailLines, true)
+		opts.Container = container
+		opts.MaxBytes = max(opts.MaxBytes/len(running), logTailMinPodBytes)
+		tails := tailPods(ctx, client, running, opts)
+		if err := ctx.Err(); err != nil {
+			return util.HandleK8sError("following workload logs", err), nil, nil
+		}
+		lines := mergePodLogLines(tails, input.WorkloadName)
+
+		sb.WriteString(util.FormatKeyValue("Window", opts.Duration.String()) + "\n")
+		sb.WriteString(util.FormatKeyValue("Pods", fmt.Sprintf("%d followed", len(running))))
+		if skipped > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d more not followed)", skipped))
+		}
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Budget Per Pod", formatBytes(int64(opts.MaxBytes))) + "\n\n")
+
+		errorCount := 0
+		rows := make([][]string, 0, len(tails))
+		var steps []util.NextStep
+		for _, t := range tails {
+			if t.Err != nil {
+				rows = append(rows, []string{t.Pod, "-", "-", "error: " + truncateName(t.Err.Error(), 60)})
+				continue
+			}
+			errs := 0
+			for _, l := range t.Window.Lines {
+				if logErrorPattern.MatchString(l) {
+					errs++
+				}
+			}
+			errorCount += errs
+			rows = append(rows, []string{t.Pod, fmt.Sprintf("%d", len(t.Window.Lines)), fmt.Sprintf("%d", errs), logTailStopReason(t.Window, opts)})
+			if t.Window.Ended {
+				steps = append(steps, nextStep("tail_pod_logs", "its log stream ended early; the container may have exited", "namespace", input.Namespace, "name", t.Pod, "container", container))
+			}
+		}
+		sb.WriteString(util.FormatSubHeader("PODS"))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatTable([]string{"POD", "LINES", "ERRORS", "STOPPED"}, rows))
+		sb.WriteString("\n")
+
+		title := "LOGS"
+		if input.ErrorsOnly {
+			title = "ERROR LINES"
+		}
+		sb.WriteString(util.FormatSubHeader(fmt.Sprintf("%s (%d lines, %d errors)", title, len(lines), errorCount)))
+		sb.WriteString("\n")
+		shown := 0
+		for _, l := range lines {
+			if input.ErrorsOnly && !l.Error {
+				continue
+			}
+			marker := "  "
+			if l.Error {
+				marker = "!!"
+			}
+			stamp := "--:--:--.---"
+			if !l.Time.IsZero() {
+				stamp = l.Time.UTC().Format("15:04:05.000")
+			}
+			sb.WriteString(fmt.Sprintf("%s %s [%s] %s\n", marker, stamp, l.Pod, l.Text))
+			shown++
+		}
+		if shown == 0 {
+			sb.WriteString("(no matching log lines written during the window)\n")
+		}
+		if errorCount > 0 && kind == "Deployment" {
+			steps = append(steps, nextStep("analyze_service_logs", "aggregate the error patterns over a longer history", "namespace", input.Namespace, "deployment_name", input.WorkloadName))
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// workloadPodSelector resolves a Deployment, StatefulSet, or DaemonSet to its
+// kind, pod label selector, and pod template.
+func workloadPodSelector(ctx context.Context, client *k8s.ClusterClient, namespace, name, kind string) (string, string, corev1.PodTemplateSpec, error) {
+	var selector *metav1.LabelSelector
+	var template corev1.PodTemplateSpec
+	switch strings.ToLower(kind) {
+	case "", "deployment", "deploy":
+		kind = "Deployment"
+		d, err := client.GetDeployment(ctx, namespace, name)
+		if err != nil {
+			return kind, "", template, err
+		}
+		selector, template = d.Spec.Selector, d.Spec.Template
+	case "statefulset", "sts":
+		kind = "StatefulSet"
+		s, err := client.GetStatefulSet(ctx, namespace, name)
+		if err != nil {
+			return kind, "", template, err
+		}
+		selector, template = s.Spec.Selector, s.Spec.Template
+	case "daemonset", "ds":
+		kind = "DaemonSet"
+		d, err := client.GetDaemonSet(ctx, namespace, name)
+		if err != nil {
+			return kind, "", template, err
+		}
+		selector, template = d.Spec.Selector, d.Spec.Template
+	default:
+		return kind, "", template, fmt.Errorf("unsupported workload_kind %q (use Deployment, StatefulSet, or DaemonSet)", kind)
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return kind, "", template, err
+	}
+	if sel.Empty() {
+		return kind, "", template, nil
+	}
+	return kind, sel.String(), template, nil
+}
+
+// tailPods follows the logs of several pods concurrently.
+func tailPods(ctx context.Context, client *k8s.ClusterClient, pods []corev1.Pod, opts k8s.LogStreamOptions) []podLogTail {
+	tails := make([]podLogTail, len(pods))
+	var wg sync.WaitGroup
+	for i := range pods {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w, err := client.StreamPodLogs(ctx, pods[i].Namespace, pods[i].Name, opts)
+			tails[i] = podLogTail{Pod: pods[i].Name, Window: w, Err: err}
+		}(i)
+	}
+	wg.Wait()
+	return tails
+}
+
+// mergePodLogLines interleaves timestamped lines from several pods in time
+// order. Pod names are shortened by the workload name prefix they share.
+func mergePodLogLines(tails []podLogTail, workload string) []podLogLine {
+	var lines []podLogLine
+	for _, t := range tails {
+		if t.Window == nil {
+			continue
+		}
+		pod := strings.TrimPrefix(t.Pod, workload+"-")
+		for _, raw := range t.Window.Lines {
+			l := podLogLine{Pod: pod, Text: raw}
+			if stamp, text, ok := strings.Cut(raw, " "); ok {
+				if ts, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+					l.Time, l.Text = ts, text
+				}
+			}
+			l.Error = logErrorPattern.MatchString(l.Text)
+			lines = append(lines, l)
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+	return lines
+}
+
+// logTailOptions applies the defaults and caps shared by the log tailing tools.
+func logTailOptions(durationSeconds, maxBytes int, tailLines *int64, timestamps bool) k8s.LogStreamOptions {
+	opts := k8s.LogStreamOptions{
+		Duration:   util.DefaultLogFollowWindow,
+		MaxBytes:   util.MaxLogBytes,
+		TailLines:  logTailDefaultHistory,
+		Timestamps: timestamps,
+	}
+	if durationSeconds > 0 {
+		opts.Duration = min(time.Duration(durationSeconds)*time.Second, util.MaxLogFollowWindow)
+	}
+	if maxBytes > 0 {
+		opts.MaxBytes = min(maxBytes, util.MaxLogFollowBytes)
+	}
+	if tailLines != nil && *tailLines >= 0 {
+		opts.TailLines = *tailLines
+	}
+	return opts
+}
+
+// logTailStopReason explains why a followed log stream stopped.
+func logTailStopReason(w *k8s.LogWindow, opts k8s.LogStreamOptions) string {
+	switch {
+	case w.BudgetReached:
+		return fmt.Sprintf("byte budget of %s reached", formatBytes(int64(opts.MaxBytes)))
+	case w.Ended:
+		return "log stream ended before the window elapsed (the container exited or restarted)"
+	default:
+		return "window elapsed"
+	}
+}
+
+// containerStateSummary describes a container's current state and last
+// termination, or "" when the pod has no status for it.
+func containerStateSummary(pod *corev1.Pod, container string) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != container {
+			continue
+		}
+		var state string
+		switch {
+		case cs.State.Running != nil:
+			state = "Running for " + util.FormatAge(cs.State.Running.StartedAt.Time)
+		case cs.State.Waiting != nil:
+			state = "Waiting: " + cs.State.Waiting.Reason
+		case cs.State.Terminated != nil:
+			state = fmt.Sprintf("Terminated: %s (exit code %d)", cs.State.Terminated.Reason, cs.State.Terminated.ExitCode)
+		}
+		if t := cs.LastTerminationState.Terminated; t != nil && cs.State.Terminated == nil {
+			state += fmt.Sprintf("; last terminated: %s (exit code %d)", t.Reason, t.ExitCode)
+		}
+		return fmt.Sprintf("%s, %d restarts", state, cs.RestartCount)
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+func TestLogTailOptions(t *testing.T) {
+	opts := logTailOptions(0, 0, nil, false)
+	if opts.Duration != util.DefaultLogFollowWindow || opts.MaxBytes != util.MaxLogBytes || opts.TailLines != logTailDefaultHistory {
+		t.Errorf("defaults = %+v", opts)
+	}
+	zero := int64(0)
+	opts = logTailOptions(3600, 10<<20, &zero, true)
+	if opts.Duration != util.MaxLogFollowWindow || opts.MaxBytes != util.MaxLogFollowBytes || opts.TailLines != 0 || !opts.Timestamps {
+		t.Errorf("capped = %+v", opts)
+	}
+	if got := logTailOptions(5, 0, nil, false).Duration; got != 5*time.Second {
+		t.Errorf("Duration = %s, want 5s", got)
+	}
+}
+
+func TestLogTailStopReason(t *testing.T) {
+	opts := k8s.LogStreamOptions{MaxBytes: 1024}
+	if got := logTailStopReason(&k8s.LogWindow{BudgetReached: true}, opts); !strings.Contains(got, "byte budget of 1.0Ki") {
+		t.Errorf("budget reason = %q", got)
+	}
+	if got := logTailStopReason(&k8s.LogWindow{Ended: true}, opts); !strings.Contains(got, "container exited") {
+		t.Errorf("ended reason = %q", got)
+	}
+	if got := logTailStopReason(&k8s.LogWindow{}, opts); got != "window elapsed" {
+		t.Errorf("default reason = %q", got)
+	}
+}
+
+func TestContainerStateSummary(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		Name:                 "app",
+		RestartCount:         4,
+		State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+	}}}}
+	want := "Waiting: CrashLoopBackOff; last terminated: OOMKilled (exit code 137), 4 restarts"
+	if got := containerStateSummary(pod, "app"); got != want {
+		t.Errorf("containerStateSummary() = %q, want %q", got, want)
+	}
+	if got := containerStateSummary(pod, "sidecar"); got != "" {
+		t.Errorf("unknown container = %q, want empty", got)
+	}
+}
+
+func TestMergePodLogLines(t *testing.T) {
+	tails := []podLogTail{
+		{Pod: "web-abc", Window: &k8s.LogWindow{Lines: []string{
+			"2026-10-15T10:00:01.000000000Z GET /cart 200",
+			"2026-10-15T10:00:03.000000000Z connection refused by db",
+		}}},
+		{Pod: "web-def", Window: &k8s.LogWindow{Lines: []string{
+			"2026-10-15T10:00:02.000000000Z GET /pay 500",
+			"not timestamped",
+		}}},
+		{Pod: "web-ghi", Err: context.DeadlineExceeded},
+	}
+	lines := mergePodLogLines(tails, "web")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4", len(lines))
+	}
+	// The untimestamped line sorts first; the rest interleave in time order.
+	want := []string{"def:not timestamped", "abc:GET /cart 200", "def:GET /pay 500", "abc:connection refused by db"}
+	for i, l := range lines {
+		if got := l.Pod + ":" + l.Text; got != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if !lines[3].Error || lines[1].Error {
+		t.Error("expected only the refused connection to be marked as an error")
+	}
+}
+
+func TestWorkloadPodSelector(t *testing.T) {
+	replicas := int32(2)
+	client := k8s.NewClusterClientForTesting(fake.NewSimpleClientset(&appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "postgres"}}}},
+		},
+	}), nil)
+
+	kind, selector, template, err := workloadPodSelector(context.Background(), client, "shop", "db", "sts")
+	if err != nil {
+		t.Fatalf("workloadPodSelector() error = %v", err)
+	}
+	if kind != "StatefulSet" || selector != "app=db" || template.Spec.Containers[0].Name != "postgres" {
+		t.Errorf("got kind %q, selector %q", kind, selector)
+	}
+	if _, _, _, err := workloadPodSelector(context.Background(), client, "shop", "db", "CronJob"); err == nil {
+		t.Error("expected an error for an unsupported kind")
+	}
+}
@@ -0,0 +1,346 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// clusterOverviewURI is the static resource backed by cluster_info.
+	clusterOverviewURI = "k8s://cluster/overview"
+	// clusterScopeSegment stands in for the namespace of cluster-scoped objects,
+	// as in k8s://cluster/node/aks-pool-0/yaml.
+	clusterScopeSegment = "cluster"
+	// resourceListLimit caps how many objects a list resource returns.
+	resourceListLimit = 500
+)
+
+// resourceURI is a parsed k8s:// resource URI.
+type resourceURI struct {
+	Namespace string // "" for all namespaces or cluster-scoped objects
+	Kind      string
+	Name      string // "" for list resources
+}
+
+func registerMCPResources(server *mcp.Server, client *k8s.ClusterClient) {
+	server.AddResource(&mcp.Resource{
+		URI:         clusterOverviewURI,
+		Name:        "cluster-overview",
+		Title:       "Cluster overview",
+		Description: "Server version and node, namespace, pod, and service counts (the cluster_info report).",
+		MIMEType:    "text/plain",
+	}, resourceHandler(client))
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "k8s://{namespace}/{kind}/{name}/yaml",
+		Name:        "object-yaml",
+		Title:       "Object manifest",
+		Description: "Live manifest of one object as YAML, without managedFields. kind is any kind, plural, or short name the cluster serves (pod, deploy, certificate). Use namespace \"cluster\" for cluster-scoped kinds. Secret values are redacted.",
+		MIMEType:    "application/yaml",
+	}, resourceHandler(client))
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "k8s://{namespace}/{kind}",
+		Name:        "object-list",
+		Title:       "Object list",
+		Description: fmt.Sprintf("Objects of a kind in a namespace (\"all\" for every namespace, \"cluster\" for cluster-scoped kinds), each with its manifest resource URI. Lists at most %d objects.", resourceListLimit),
+		MIMEType:    "text/plain",
+	}, resourceHandler(client))
+}
+
+func resourceHandler(client *k8s.ClusterClient) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		text, err := readClusterResource(ctx, client, req.Params.URI)
+		if apierrors.IsNotFound(err) {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: text}}}, nil
+	}
+}
+
+// parseResourceURI parses k8s://{namespace}/{kind}[/{name}/yaml].
+func parseResourceURI(raw string) (resourceURI, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "k8s" || u.Host == "" {
+		return resourceURI{}, fmt.Errorf("invalid resource URI %q: expected k8s://{namespace}/{kind}[/{name}/yaml]", raw)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	ref := resourceURI{Namespace: util.NamespaceOrAll(u.Host), Kind: parts[0]}
+	if ref.Namespace == clusterScopeSegment {
+		ref.Namespace = ""
+	}
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+	case len(parts) == 3 && parts[1] != "" && parts[2] == "yaml":
+		ref.Name = parts[1]
+	default:
+		return resourceURI{}, fmt.Errorf("invalid resource URI %q: expected k8s://{namespace}/{kind}[/{name}/yaml]", raw)
+	}
+	return ref, nil
+}
+
+// readClusterResource renders the content behind a k8s:// URI. A missing
+// object is returned as the API server's NotFound error.
+func readClusterResource(ctx context.Context, client *k8s.ClusterClient, uri string) (string, error) {
+	if uri == clusterOverviewURI {
+		return clusterOverview(ctx, client), nil
+	}
+	ref, err := parseResourceURI(uri)
+	if err != nil {
+		return "", err
+	}
+	api, err := client.ResolveResource(ctx, "", "", ref.Kind)
+	if err != nil {
+		return "", err
+	}
+
+	if ref.Name == "" {
+		items, err := client.ListResources(ctx, api, ref.Namespace, metav1.ListOptions{Limit: resourceListLimit})
+		if err != nil {
+			return "", fmt.Errorf("listing %s: %w", api.GVR.Resource, err)
+		}
+		return formatResourceList(api, items), nil
+	}
+
+	if api.Namespaced && ref.Namespace == "" {
+		return "", fmt.Errorf("%s is namespaced: name its namespace in the URI instead of %q", api.Kind, clusterScopeSegment)
+	}
+	obj, err := client.GetResource(ctx, api, ref.Namespace, ref.Name)
+	if err != nil {
+		return "", fmt.Errorf("getting %s %s: %w", api.Kind, ref.Name, err)
+	}
+	out, err := yaml.Marshal(sanitizeManifest(obj).Object)
+	if err != nil {
+		return "", fmt.Errorf("encoding %s %s: %w", api.Kind, ref.Name, err)
+	}
+	return string(out), nil
+}
+
+// sanitizeManifest drops managedFields and replaces Secret values with their
+// sizes. The last-applied annotation of a Secret is dropped too, since it
+// holds the applied data verbatim. Other objects go through the redaction
+// policy: credential-like annotation values, ConfigMap values, and container
+// env values are masked.
+func sanitizeManifest(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	obj.SetManagedFields(nil)
+	if obj.GetKind() != "Secret" || obj.GetAPIVersion() != "v1" {
+		redactManifest(obj)
+		return obj
+	}
+	for _, field := range []string{"data", "stringData"} {
+		values, ok := obj.Object[field].(map[string]any)
+		if !ok {
+			continue
+		}
+		for k, v := range values {
+			s, _ := v.(string)
+			values[k] = fmt.Sprintf("<redacted, %d chars>", len(s))
+		}
+	}
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		obj.SetAnnotations(annotations)
+	}
+	return obj
+}
+
+// redactManifest applies the redaction policy to an object's annotations,
+// ConfigMap data, and the env of any containers it holds.
+func redactManifest(obj *unstructured.Unstructured) {
+	if !util.RedactionEnabled() {
+		return
+	}
+	if annotations := obj.GetAnnotations(); len(annotations) > 0 {
+		for k, v := range annotations {
+			annotations[k] = util.RedactAnnotation(k, v)
+		}
+		obj.SetAnnotations(annotations)
+	}
+	if obj.GetKind() == "ConfigMap" && obj.GetAPIVersion() == "v1" {
+		if data, ok := obj.Object["data"].(map[string]any); ok {
+			for k, v := range data {
+				if s, ok := v.(string); ok {
+					data[k] = util.RedactValue(k, s)
+				}
+			}
+		}
+	}
+	redactEnvValues(obj.Object)
+}
+
+// redactEnvValues masks credential-like values in every "env" list of
+// name/value pairs below v, covering pods, pod templates, and custom
+// resources that embed them.
+func redactEnvValues(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		if env, ok := v["env"].([]any); ok {
+			for _, e := range env {
+				if m, ok := e.(map[string]any); ok {
+					name, _ := m["name"].(string)
+					if value, ok := m["value"].(string); ok {
+						m["value"] = util.RedactValue(name, value)
+					}
+				}
+			}
+		}
+		for _, child := range v {
+			redactEnvValues(child)
+		}
+	case []any:
+		for _, child := range v {
+			redactEnvValues(child)
+		}
+	}
+}
+
+func formatResourceList(api k8s.APIResourceRef, items []unstructured.Unstructured) string {
+	sortUnstructured(items)
+	var rows [][]string
+	for i := range items {
+		ns := items[i].GetNamespace()
+		segment := ns
+		if ns == "" {
+			segment = clusterScopeSegment
+		}
+		rows = append(rows, []string{
+			valueOrNone(ns), items[i].GetName(), util.FormatAge(items[i].GetCreationTimestamp().Time),
+			fmt.Sprintf("k8s://%s/%s/%s/yaml", segment, strings.ToLower(api.Kind), items[i].GetName()),
+		})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(util.FormatHeader(fmt.Sprintf("%s (%s)", api.Kind, api.GVR.GroupVersion())))
+	sb.WriteString("\n")
+	if len(rows) == 0 {
+		sb.WriteString("No objects found.\n")
+		return sb.String()
+	}
+	sb.WriteString(util.FormatTable([]string{"NAMESPACE", "NAME", "AGE", "URI"}, rows))
+	sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount(strings.ToLower(api.Kind)+" objects", len(rows))))
+	return sb.String()
+}
+
+// ResourceWatcher serves resource subscriptions by re-reading each subscribed
+// URI on an interval and sending notifications/resources/updated to its
+// subscribers when the content changes. Pass Subscribe and Unsubscribe as the
+// server's subscription handlers, then Attach the server.
+type ResourceWatcher struct {
+	client   *k8s.ClusterClient
+	interval time.Duration
+
+	mu     sync.Mutex
+	server *mcp.Server
+	subs   map[string]*resourceSubscription
+}
+
+type resourceSubscription struct {
+	sessions int
+	cancel   context.CancelFunc
+}
+
+// NewResourceWatcher creates a watcher that polls subscribed resources every interval.
+func NewResourceWatcher(client *k8s.ClusterClient, interval time.Duration) *ResourceWatcher {
+	return &ResourceWatcher{client: client, interval: interval, subs: make(map[string]*resourceSubscription)}
+}
+
+// Attach sets the server that update notifications are sent through.
+func (w *ResourceWatcher) Attach(server *mcp.Server) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.server = server
+}
+
+// Subscribe starts polling the requested URI unless another session already
+// subscribed to it. The resource must be readable now.
+func (w *ResourceWatcher) Subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	uri := req.Params.URI
+	text, err := readClusterResource(ctx, w.client, uri)
+	if apierrors.IsNotFound(err) {
+		return mcp.ResourceNotFoundError(uri)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if sub, ok := w.subs[uri]; ok {
+		sub.sessions++
+		return nil
+	}
+	pollCtx, cancel := context.WithCancel(context.Background())
+	w.subs[uri] = &resourceSubscription{sessions: 1, cancel: cancel}
+	go w.poll(pollCtx, uri, sha256.Sum256([]byte(text)))
+	return nil
+}
+
+// Unsubscribe stops polling the URI once no session is subscribed to it.
+func (w *ResourceWatcher) Unsubscribe(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	sub, ok := w.subs[req.Params.URI]
+	if !ok {
+		return nil
+	}
+	if sub.sessions--; sub.sessions <= 0 {
+		sub.cancel()
+		delete(w.subs, req.Params.URI)
+	}
+	return nil
+}
+
+func (w *ResourceWatcher) poll(ctx context.Context, uri string, last [sha256.Size]byte) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		sum, ok := w.checksum(ctx, uri)
+		if !ok || sum == last {
+			continue
+		}
+		last = sum
+		w.mu.Lock()
+		server := w.server
+		w.mu.Unlock()
+		if server != nil {
+			if err := server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+				log.Printf("Resource update notification for %s failed: %v", uri, err)
+			}
+		}
+	}
+}
+
+// checksum hashes the current content of uri. A deleted object hashes as
+// empty content so subscribers hear about the deletion; other read errors
+// are skipped until the next poll.
+func (w *ResourceWatcher) checksum(ctx context.Context, uri string) ([sha256.Size]byte, bool) {
+	text, err := readClusterResource(ctx, w.client, uri)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return [sha256.Size]byte{}, false
+	}
+	return sha256.Sum256([]byte(text)), true
+}
@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+func TestParseResourceURI(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want resourceURI
+		ok   bool
+	}{
+		{"k8s://shop/deploy/web/yaml", resourceURI{Namespace: "shop", Kind: "deploy", Name: "web"}, true},
+		{"k8s://cluster/node/aks-pool-0/yaml", resourceURI{Kind: "node", Name: "aks-pool-0"}, true},
+		{"k8s://all/pods", resourceURI{Kind: "pods"}, true},
+		{"k8s://shop/pod/web/json", resourceURI{}, false},
+		{"https://shop/pod", resourceURI{}, false},
+		{"k8s://shop", resourceURI{}, false},
+	}
+	for _, tt := range tests {
+		got, err := parseResourceURI(tt.uri)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseResourceURI(%q) = %+v, %v", tt.uri, got, err)
+		}
+	}
+}
+
+func TestMCPResources(t *testing.T) {
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	secretsGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	obj := func(kind, name string, fields map[string]any) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: fields}
+		u.SetAPIVersion("v1")
+		u.SetKind(kind)
+		u.SetNamespace("shop")
+		u.SetName(name)
+		u.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+		return u
+	}
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podsGVR: "PodList", secretsGVR: "SecretList"},
+		obj("Pod", "web", map[string]any{"spec": map[string]any{"nodeName": "node-a"}}),
+		obj("Secret", "db", map[string]any{"data": map[string]any{"password": "aHVudGVyMg=="}}))
+
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.Resources = []*metav1.APIResourceList{{GroupVersion: "v1", APIResources: []metav1.APIResource{
+		{Name: "pods", SingularName: "pod", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"}},
+		{Name: "secrets", SingularName: "secret", Kind: "Secret", Namespaced: true},
+	}}}
+	client := k8s.NewClusterClientForTesting(fakeClient, nil)
+	client.DynamicClient = dyn
+
+	watcher := NewResourceWatcher(client, 10*time.Millisecond)
+	server := mcp.NewServer(&mcp.Implementation{Name: "kube-doctor-test", Version: "test"},
+		&mcp.ServerOptions{SubscribeHandler: watcher.Subscribe, UnsubscribeHandler: watcher.Unsubscribe})
+	watcher.Attach(server)
+	registerMCPResources(server, client)
+
+	ctx := context.Background()
+	t1, t2 := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, t1, nil)
+	if err != nil {
+		t.Fatalf("Server connect: %v", err)
+	}
+	defer serverSession.Close()
+
+	updated := make(chan string, 4)
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+	clientSession, err := mcpClient.Connect(ctx, t2, nil)
+	if err != nil {
+		t.Fatalf("Client connect: %v", err)
+	}
+	defer clientSession.Close()
+
+	read := func(uri string) string {
+		t.Helper()
+		res, err := clientSession.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		if err != nil {
+			t.Fatalf("ReadResource(%s) error: %v", uri, err)
+		}
+		return res.Contents[0].Text
+	}
+
+	templates, err := clientSession.ListResourceTemplates(ctx, nil)
+	if err != nil || len(templates.ResourceTemplates) != 2 {
+		t.Fatalf("expected 2 resource templates, got %v, %v", templates, err)
+	}
+
+	pod := read("k8s://shop/po/web/yaml")
+	if !strings.Contains(pod, "nodeName: node-a") || strings.Contains(pod, "managedFields") {
+		t.Errorf("unexpected pod manifest:\n%s", pod)
+	}
+	secret := read("k8s://shop/secret/db/yaml")
+	if strings.Contains(secret, "aHVudGVyMg==") || !strings.Contains(secret, "<redacted, 12 chars>") {
+		t.Errorf("expected redacted secret data:\n%s", secret)
+	}
+	if list := read("k8s://all/pods"); !strings.Contains(list, "k8s://shop/pod/web/yaml") {
+		t.Errorf("expected the pod URI in the list:\n%s", list)
+	}
+	if _, err := clientSession.ReadResource(ctx, &mcp.ReadResourceParams{URI: "k8s://shop/pod/missing/yaml"}); err == nil {
+		t.Error("expected an error reading a missing pod")
+	}
+
+	uri := "k8s://shop/pod/web/yaml"
+	if err := clientSession.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+	changed := obj("Pod", "web", map[string]any{"spec": map[string]any{"nodeName": "node-b"}})
+	if _, err := dyn.Resource(podsGVR).Namespace("shop").Update(ctx, changed, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-updated:
+		if got != uri {
+			t.Errorf("expected an update for %s, got %s", uri, got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no resource update notification after the pod changed")
+	}
+
+	if err := clientSession.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: uri}); err != nil {
+		t.Fatalf("Unsubscribe error: %v", err)
+	}
+	watcher.mu.Lock()
+	remaining := len(watcher.subs)
+	watcher.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("expected polling to stop after unsubscribe, %d subscriptions left", remaining)
+	}
+}
+
+func TestSanitizeManifestRedacts(t *testing.T) {
+	pod := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]any{
+			"name":        "web",
+			"annotations": map[string]any{"vault.example.com/token": "s.abc", "team": "shop"},
+		},
+		"spec": map[string]any{"containers": []any{map[string]any{
+			"name": "web",
+			"env": []any{
+				map[string]any{"name": "DB_PASSWORD", "value": "hunter2"},
+				map[string]any{"name": "LOG_LEVEL", "value": "debug"},
+			},
+		}}},
+	}}
+	out := sanitizeManifest(pod)
+	env := out.Object["spec"].(map[string]any)["containers"].([]any)[0].(map[string]any)["env"].([]any)
+	if got := env[0].(map[string]any)["value"]; got != util.Redacted {
+		t.Errorf("DB_PASSWORD = %v, want redacted", got)
+	}
+	if got := env[1].(map[string]any)["value"]; got != "debug" {
+		t.Errorf("LOG_LEVEL = %v, want debug", got)
+	}
+	if got := out.GetAnnotations(); got["vault.example.com/token"] != util.Redacted || got["team"] != "shop" {
+		t.Errorf("annotations = %v", got)
+	}
+
+	cm := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "settings"},
+		"data":       map[string]any{"api_key": "abc", "url": "https://example.com"},
+	}}
+	data := sanitizeManifest(cm).Object["data"].(map[string]any)
+	if data["api_key"] != util.Redacted || data["url"] != "https://example.com" {
+		t.Errorf("ConfigMap data = %v", data)
+	}
+}
@@ -34,7 +34,7 @@ func registerMetricsTools(server *mcp.Server, client *k8s.ClusterClient) {
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input getNodeMetricsInput) (*mcp.CallToolResult, any, error) {
 		metrics, err := client.GetNodeMetrics(ctx)
 		if err != nil {
-			return util.ErrorResult("Error getting node metrics: %v", err), nil, nil
+			return util.ErrorResult("Error getting node metrics: %v\nRun check_metrics_server to find out why the metrics API is failing.", err), nil, nil
 		}
 
 		// Get node capacity for utilization %
@@ -91,7 +91,7 @@ func registerMetricsTools(server *mcp.Server, client *k8s.ClusterClient) {
 
 		metrics, err := client.GetPodMetrics(ctx, ns, opts)
 		if err != nil {
-			return util.ErrorResult("Error getting pod metrics: %v", err), nil, nil
+			return util.ErrorResult("Error getting pod metrics: %v\nRun check_metrics_server to find out why the metrics API is failing.", err), nil, nil
 		}
 
 		headers := []string{"POD", "NAMESPACE", "CONTAINER", "CPU", "MEMORY"}
@@ -130,7 +130,7 @@ func registerMetricsTools(server *mcp.Server, client *k8s.ClusterClient) {
 
 		metrics, err := client.GetPodMetrics(ctx, ns, metav1.ListOptions{})
 		if err != nil {
-			return util.ErrorResult("Error getting pod metrics: %v", err), nil, nil
+			return util.ErrorResult("Error getting pod metrics: %v\nRun check_metrics_server to find out why the metrics API is failing.", err), nil, nil
 		}
 
 		type podUsage struct {
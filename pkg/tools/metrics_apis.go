@@ -0,0 +1,373 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// The aggregated metrics APIs an HPA can read from.
+const (
+	resourceMetricsGroup = "metrics.k8s.io"
+	customMetricsGroup   = "custom.metrics.k8s.io"
+	externalMetricsGroup = "external.metrics.k8s.io"
+)
+
+var metricsGroups = []string{resourceMetricsGroup, customMetricsGroup, externalMetricsGroup}
+
+// metricsAdapter identifies the component serving a metrics API from the
+// name of its Service or the image of its pods.
+type metricsAdapter struct {
+	Name     string
+	Keywords []string
+}
+
+// knownMetricsAdapters is checked in order; the first keyword found in the
+// Service name or pod image wins.
+var knownMetricsAdapters = []metricsAdapter{
+	{Name: "KEDA", Keywords: []string{"keda"}},
+	{Name: "Prometheus Adapter", Keywords: []string{"prometheus-adapter", "k8s-prometheus-adapter"}},
+	{Name: "Datadog Cluster Agent", Keywords: []string{"datadog"}},
+	{Name: "Stackdriver Adapter", Keywords: []string{"stackdriver"}},
+	{Name: "Azure Metrics Adapter", Keywords: []string{"azure-k8s-metrics-adapter"}},
+	{Name: "kube-metrics-adapter", Keywords: []string{"kube-metrics-adapter"}},
+	{Name: "metrics-server", Keywords: []string{"metrics-server"}},
+}
+
+type checkMetricsAPIsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace whose HPAs to check (empty for all namespaces); the metrics APIs are always listed cluster-wide"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// metricsAPI is one registered version of a metrics API group.
+type metricsAPI struct {
+	apiServiceStatus
+	Adapter string
+}
+
+// hpaMetricDependency is what one HPA reads from the metrics APIs.
+type hpaMetricDependency struct {
+	Namespace string
+	Name      string
+	// Metrics are the HPA's metrics as "name (type)".
+	Metrics []string
+	// Groups are the metrics API groups the metrics are read from.
+	Groups []string
+	// FailedReason and FailedMessage are from a ScalingActive=False
+	// condition caused by a failed metrics read.
+	FailedReason  string
+	FailedMessage string
+}
+
+func registerMetricsAPITools(server *mcp.Server, client *k8s.ClusterClient) {
+	// check_metrics_apis
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "check_metrics_apis",
+		Description: "Discover the resource, custom, and external metrics APIs (metrics.k8s.io, custom.metrics.k8s.io, external.metrics.k8s.io), " +
+			"the adapters serving them (metrics-server, Prometheus Adapter, KEDA, Datadog, Stackdriver) and whether they are available, then report " +
+			"which HPAs depend on a metrics API that is missing or unavailable and which HPAs fail to read their metrics. " +
+			"Use this when HPAs show <unknown> targets or do not scale on custom or external metrics.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkMetricsAPIsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+
+		apiServices, err := client.ListClusterCustomResources(ctx, apiServiceGVR, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing APIServices", err), nil, nil
+		}
+		hpas, err := client.ListHPAs(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing HPAs", err), nil, nil
+		}
+
+		var gaps dataGaps
+		var apis []metricsAPI
+		for i := range apiServices {
+			status := describeAPIService(&apiServices[i])
+			if !isMetricsGroup(status.Group) {
+				continue
+			}
+			api := metricsAPI{apiServiceStatus: status, Adapter: "kube-apiserver"}
+			if status.ServiceName != "" {
+				api.Adapter = identifyMetricsAdapter(ctx, client, status.ServiceNS, status.ServiceName, &gaps)
+			}
+			apis = append(apis, api)
+		}
+		sort.Slice(apis, func(i, j int) bool { return apis[i].Name < apis[j].Name })
+		available := metricsGroupAvailability(apis)
+
+		deps := make([]hpaMetricDependency, 0, len(hpas))
+		for i := range hpas {
+			deps = append(deps, hpaMetricDependencies(&hpas[i]))
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Metrics APIs (HPAs in namespace: %s)", displayNS(ns))))
+		sb.WriteString("\n\n")
+		sb.WriteString(util.FormatSubHeader("METRICS APIS"))
+		sb.WriteString("\n")
+		var rows [][]string
+		for _, api := range apis {
+			service := "local"
+			if api.ServiceName != "" {
+				service = api.ServiceNS + "/" + api.ServiceName
+			}
+			rows = append(rows, []string{api.Group + "/" + api.Version, service, api.Adapter, fmt.Sprintf("%t", api.Available), valueOrNone(api.Reason)})
+		}
+		for _, group := range metricsGroups {
+			if _, ok := available[group]; !ok {
+				rows = append(rows, []string{group, "-", "-", "not registered", "-"})
+			}
+		}
+		sb.WriteString(util.FormatTable([]string{"API", "SERVICE", "ADAPTER", "AVAILABLE", "REASON"}, rows))
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("HPA DEPENDENCIES"))
+		sb.WriteString("\n")
+		if len(deps) == 0 {
+			sb.WriteString("  No HPAs found.\n")
+		} else {
+			rows = rows[:0]
+			for _, d := range deps {
+				status := "ok"
+				if missing := unavailableGroups(d.Groups, available); len(missing) > 0 {
+					status = "API unavailable: " + strings.Join(missing, ", ")
+				} else if d.FailedReason != "" {
+					status = d.FailedReason
+				}
+				rows = append(rows, []string{d.Namespace, d.Name, joinLimited(d.Metrics, 3), strings.Join(d.Groups, ", "), status})
+			}
+			sb.WriteString(util.FormatTable([]string{"NAMESPACE", "HPA", "METRICS", "APIS", "STATUS"}, rows))
+		}
+
+		findingLines, actions, steps := metricsAPIFindings(apis, available, deps)
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  Every metrics API the HPAs depend on is available.\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+		gaps.write(&sb)
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+func isMetricsGroup(group string) bool {
+	for _, g := range metricsGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// identifyMetricsAdapter names the adapter behind a metrics APIService from
+// its Service name and namespace, falling back to the images of the pods the
+// Service selects. Unknown adapters are shown by their Service name.
+func identifyMetricsAdapter(ctx context.Context, client *k8s.ClusterClient, ns, name string, gaps *dataGaps) string {
+	if adapter := matchMetricsAdapter(ns + "/" + name); adapter != "" {
+		return adapter
+	}
+	svc, err := client.GetService(ctx, ns, name)
+	if err != nil || len(svc.Spec.Selector) == 0 {
+		return name
+	}
+	pods, err := client.ListPods(ctx, ns, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String()})
+	if gaps.record("adapter pods ("+ns+"/"+name+")", err) {
+		return name
+	}
+	for i := range pods {
+		for _, c := range pods[i].Spec.Containers {
+			if adapter := matchMetricsAdapter(c.Image); adapter != "" {
+				return adapter
+			}
+		}
+	}
+	return name
+}
+
+func matchMetricsAdapter(s string) string {
+	s = strings.ToLower(s)
+	for _, a := range knownMetricsAdapters {
+		for _, k := range a.Keywords {
+			if strings.Contains(s, k) {
+				return a.Name
+			}
+		}
+	}
+	return ""
+}
+
+// metricsGroupAvailability reports, for each registered metrics API group,
+// whether any of its versions is available.
+func metricsGroupAvailability(apis []metricsAPI) map[string]bool {
+	out := make(map[string]bool)
+	for _, api := range apis {
+		out[api.Group] = out[api.Group] || api.Available
+	}
+	return out
+}
+
+func unavailableGroups(groups []string, available map[string]bool) []string {
+	var out []string
+	for _, g := range groups {
+		if !available[g] {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// hpaMetricDependencies maps an HPA's metrics to the API groups the HPA
+// controller reads them from: Resource and ContainerResource metrics from
+// metrics.k8s.io, Pods and Object metrics from custom.metrics.k8s.io, and
+// External metrics from external.metrics.k8s.io. An HPA without metrics
+// defaults to 80% CPU.
+func hpaMetricDependencies(hpa *autoscalingv2.HorizontalPodAutoscaler) hpaMetricDependency {
+	d := hpaMetricDependency{Namespace: hpa.Namespace, Name: hpa.Name}
+	groups := make(map[string]bool)
+	for _, m := range hpa.Spec.Metrics {
+		var name, group string
+		switch m.Type {
+		case autoscalingv2.ResourceMetricSourceType:
+			if m.Resource != nil {
+				name = string(m.Resource.Name)
+			}
+			group = resourceMetricsGroup
+		case autoscalingv2.ContainerResourceMetricSourceType:
+			if m.ContainerResource != nil {
+				name = m.ContainerResource.Container + "/" + string(m.ContainerResource.Name)
+			}
+			group = resourceMetricsGroup
+		case autoscalingv2.PodsMetricSourceType:
+			if m.Pods != nil {
+				name = m.Pods.Metric.Name
+			}
+			group = customMetricsGroup
+		case autoscalingv2.ObjectMetricSourceType:
+			if m.Object != nil {
+				name = m.Object.Metric.Name
+			}
+			group = customMetricsGroup
+		case autoscalingv2.ExternalMetricSourceType:
+			if m.External != nil {
+				name = m.External.Metric.Name
+			}
+			group = externalMetricsGroup
+		default:
+			continue
+		}
+		d.Metrics = append(d.Metrics, fmt.Sprintf("%s (%s)", valueOrNone(name), strings.ToLower(string(m.Type))))
+		groups[group] = true
+	}
+	if len(hpa.Spec.Metrics) == 0 {
+		d.Metrics = []string{"cpu (resource, default)"}
+		groups[resourceMetricsGroup] = true
+	}
+	for _, g := range metricsGroups {
+		if groups[g] {
+			d.Groups = append(d.Groups, g)
+		}
+	}
+	for _, c := range hpa.Status.Conditions {
+		if c.Type == autoscalingv2.ScalingActive && c.Status == corev1.ConditionFalse && strings.HasPrefix(c.Reason, "FailedGet") {
+			d.FailedReason, d.FailedMessage = c.Reason, c.Message
+		}
+	}
+	return d
+}
+
+// metricsAPIFindings reports unavailable metrics APIs, HPAs that depend on
+// them, and HPAs that cannot read their metrics from an available API
+// (usually a metric the adapter does not expose).
+func metricsAPIFindings(apis []metricsAPI, available map[string]bool, deps []hpaMetricDependency) (findingLines, actions []string, steps []util.NextStep) {
+	dependents := make(map[string][]string)
+	for _, d := range deps {
+		for _, g := range d.Groups {
+			dependents[g] = append(dependents[g], d.Namespace+"/"+d.Name)
+		}
+	}
+
+	for _, api := range apis {
+		if api.Available {
+			continue
+		}
+		sev := "WARNING"
+		if len(dependents[api.Group]) > 0 {
+			sev = "CRITICAL"
+		}
+		findingLines = append(findingLines, findings.Format("KD-SYS-003", sev, fmt.Sprintf("APIService %s (%s) is unavailable (%s): %s; %d HPA(s) depend on it",
+			api.Name, api.Adapter, valueOrNone(api.Reason), apiServiceReasonMeaning(api.Reason), len(dependents[api.Group]))))
+		if a := apiServiceReasonAction(api.Reason, api.ServiceNS, api.ServiceName); a != "" {
+			actions = append(actions, a)
+		}
+		switch {
+		case api.Group == resourceMetricsGroup:
+			steps = append(steps, nextStep("check_metrics_server", "the resource metrics API is unavailable"))
+		case api.ServiceName != "":
+			steps = append(steps, nextStep("diagnose_service", "the metrics adapter's Service backs an unavailable API", "namespace", api.ServiceNS, "service_name", api.ServiceName))
+		}
+	}
+
+	for _, group := range metricsGroups {
+		if _, registered := available[group]; registered || len(dependents[group]) == 0 {
+			continue
+		}
+		findingLines = append(findingLines, findings.Format("KD-WL-011", "CRITICAL", fmt.Sprintf("%d HPA(s) use %s metrics but no adapter serves %s: %s",
+			len(dependents[group]), metricsGroupKind(group), group, joinLimited(dependents[group], 5))))
+		actions = append(actions, missingMetricsGroupAction(group))
+	}
+
+	for _, d := range deps {
+		missing := unavailableGroups(d.Groups, available)
+		switch {
+		case len(missing) > 0:
+			findingLines = append(findingLines, findings.Format("KD-WL-011", "WARNING", fmt.Sprintf("HPA %s/%s cannot scale on %s: %s is unavailable",
+				d.Namespace, d.Name, joinLimited(d.Metrics, 3), strings.Join(missing, ", "))))
+		case d.FailedReason != "":
+			findingLines = append(findingLines, findings.Format("KD-WL-011", "WARNING", fmt.Sprintf("HPA %s/%s fails to read its metrics (%s) although the API is available: %s",
+				d.Namespace, d.Name, d.FailedReason, truncateName(d.FailedMessage, 200))))
+			actions = append(actions, fmt.Sprintf("Check that the adapter exposes the metrics HPA %s/%s asks for (kubectl get --raw /apis/<group>/v1beta1 lists them) and that their names and selectors match", d.Namespace, d.Name))
+		}
+	}
+	return findingLines, actions, steps
+}
+
+func metricsGroupKind(group string) string {
+	switch group {
+	case customMetricsGroup:
+		return "Pods/Object (custom)"
+	case externalMetricsGroup:
+		return "External"
+	}
+	return "Resource"
+}
+
+func missingMetricsGroupAction(group string) string {
+	switch group {
+	case customMetricsGroup:
+		return "Install a custom metrics adapter such as Prometheus Adapter so custom.metrics.k8s.io is served, or switch the HPAs to Resource metrics"
+	case externalMetricsGroup:
+		return "Install an external metrics adapter such as KEDA or Prometheus Adapter so external.metrics.k8s.io is served; only one adapter can own the API at a time"
+	}
+	return "Install metrics-server so metrics.k8s.io is served (run check_metrics_server for details)"
+}
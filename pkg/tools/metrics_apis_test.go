@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHPAMetricDependencies(t *testing.T) {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "worker"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{Metrics: []autoscalingv2.MetricSpec{
+			{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricSource{Name: corev1.ResourceCPU}},
+			{Type: autoscalingv2.ExternalMetricSourceType, External: &autoscalingv2.ExternalMetricSource{Metric: autoscalingv2.MetricIdentifier{Name: "s0-rabbitmq-orders"}}},
+			{Type: autoscalingv2.PodsMetricSourceType, Pods: &autoscalingv2.PodsMetricSource{Metric: autoscalingv2.MetricIdentifier{Name: "http_requests_per_second"}}},
+		}},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+			{Type: autoscalingv2.AbleToScale, Status: corev1.ConditionTrue, Reason: "ReadyForNewScale"},
+			{Type: autoscalingv2.ScalingActive, Status: corev1.ConditionFalse, Reason: "FailedGetExternalMetric",
+				Message: "the HPA was unable to compute the replica count: unable to get external metric shop/s0-rabbitmq-orders"},
+		}},
+	}
+	d := hpaMetricDependencies(hpa)
+	if got := strings.Join(d.Groups, ","); got != "metrics.k8s.io,custom.metrics.k8s.io,external.metrics.k8s.io" {
+		t.Errorf("groups = %q", got)
+	}
+	if got := strings.Join(d.Metrics, ", "); got != "cpu (resource), s0-rabbitmq-orders (external), http_requests_per_second (pods)" {
+		t.Errorf("metrics = %q", got)
+	}
+	if d.FailedReason != "FailedGetExternalMetric" {
+		t.Errorf("failed reason = %q", d.FailedReason)
+	}
+
+	defaulted := hpaMetricDependencies(&autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "web"}})
+	if strings.Join(defaulted.Groups, ",") != resourceMetricsGroup || defaulted.FailedReason != "" {
+		t.Errorf("an HPA without metrics should depend on CPU from metrics.k8s.io: %+v", defaulted)
+	}
+}
+
+func TestMatchMetricsAdapter(t *testing.T) {
+	tests := map[string]string{
+		"keda/keda-operator-metrics-apiserver":                        "KEDA",
+		"monitoring/prometheus-adapter":                               "Prometheus Adapter",
+		"registry.k8s.io/prometheus-adapter/prometheus-adapter:v0.12": "Prometheus Adapter",
+		"kube-system/metrics-server":                                  "metrics-server",
+		"datadog/datadog-cluster-agent-metrics-api":                   "Datadog Cluster Agent",
+		"custom/my-adapter":                                           "",
+	}
+	for in, want := range tests {
+		if got := matchMetricsAdapter(in); got != want {
+			t.Errorf("matchMetricsAdapter(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMetricsAPIFindings(t *testing.T) {
+	apis := []metricsAPI{
+		{apiServiceStatus: apiServiceStatus{Name: "v1beta1.metrics.k8s.io", Group: resourceMetricsGroup, Version: "v1beta1",
+			ServiceNS: "kube-system", ServiceName: "metrics-server", Available: true}, Adapter: "metrics-server"},
+		{apiServiceStatus: apiServiceStatus{Name: "v1beta1.external.metrics.k8s.io", Group: externalMetricsGroup, Version: "v1beta1",
+			ServiceNS: "keda", ServiceName: "keda-operator-metrics-apiserver", Reason: "MissingEndpoints"}, Adapter: "KEDA"},
+	}
+	deps := []hpaMetricDependency{
+		{Namespace: "shop", Name: "web", Metrics: []string{"cpu (resource)"}, Groups: []string{resourceMetricsGroup}},
+		{Namespace: "shop", Name: "worker", Metrics: []string{"s0-rabbitmq-orders (external)"}, Groups: []string{externalMetricsGroup}},
+		{Namespace: "shop", Name: "api", Metrics: []string{"http_requests_per_second (pods)"}, Groups: []string{customMetricsGroup}},
+		{Namespace: "shop", Name: "batch", Metrics: []string{"memory (resource)"}, Groups: []string{resourceMetricsGroup},
+			FailedReason: "FailedGetResourceMetric", FailedMessage: "did not receive metrics for targeted pods"},
+	}
+	findings, actions, steps := metricsAPIFindings(apis, metricsGroupAvailability(apis), deps)
+	text := strings.Join(findings, "\n")
+	for _, want := range []string{
+		"[CRITICAL] KD-SYS-003: APIService v1beta1.external.metrics.k8s.io (KEDA) is unavailable (MissingEndpoints)",
+		"[CRITICAL] KD-WL-011: 1 HPA(s) use Pods/Object (custom) metrics but no adapter serves custom.metrics.k8s.io: shop/api",
+		"HPA shop/worker cannot scale on s0-rabbitmq-orders (external): external.metrics.k8s.io is unavailable",
+		"HPA shop/batch fails to read its metrics (FailedGetResourceMetric)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("findings missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "shop/web") {
+		t.Errorf("HPA on an available API should not be reported:\n%s", text)
+	}
+	if len(actions) != 2 || len(steps) != 1 || steps[0].Tool != "diagnose_service" {
+		t.Errorf("actions = %v, steps = %+v", actions, steps)
+	}
+}
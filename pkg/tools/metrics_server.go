@@ -0,0 +1,445 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+var apiServiceGVR = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+
+const (
+	// metricsAPIServiceName is the APIService that routes metrics.k8s.io
+	// requests from the API server to metrics-server.
+	metricsAPIServiceName = "v1beta1.metrics.k8s.io"
+	// metricsServerLogLines is how many log lines per metrics-server pod are
+	// scanned for kubelet scrape errors.
+	metricsServerLogLines = 500
+	// metricsServerLogWindow is how far back metrics-server logs are scanned.
+	metricsServerLogWindow = "1h"
+)
+
+// Kinds of kubelet scrape failures in metrics-server logs, as told apart by
+// classifyScrapeError.
+const (
+	scrapeErrCertSAN       = "certificate SAN mismatch"
+	scrapeErrCertAuthority = "untrusted kubelet certificate"
+	scrapeErrConnect       = "kubelet unreachable"
+	scrapeErrAuth          = "kubelet rejected credentials"
+	scrapeErrOther         = "other scrape error"
+)
+
+// metricsServerSelectors find metrics-server pods when its APIService names
+// no Service to take a selector from.
+var metricsServerSelectors = []string{"k8s-app=metrics-server", "app.kubernetes.io/name=metrics-server"}
+
+type checkMetricsServerInput struct {
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// scrapeFailure groups the kubelet scrape errors of one kind.
+type scrapeFailure struct {
+	Kind   string
+	Count  int
+	Nodes  map[string]bool
+	Sample string
+}
+
+var (
+	// scrapeNodePatterns pull the node name out of scrape errors: the
+	// structured node="..." field of current releases, and the
+	// kubelet_summary:<node> source of releases before 0.4.
+	scrapeNodePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`node="([^"]+)"`),
+		regexp.MustCompile(`kubelet_summary:([^: ]+)`),
+	}
+	scrapeErrText = regexp.MustCompile(`err="((?:[^"\\]|\\.)*)"`)
+)
+
+func registerMetricsServerTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// check_metrics_server
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "check_metrics_server",
+		Description: "Diagnose why the metrics API (kubectl top, HPAs, get_node_metrics) is failing: checks the v1beta1.metrics.k8s.io APIService " +
+			"availability and its reason, the metrics-server Service endpoints and pod health, kubelet scrape errors in metrics-server logs " +
+			"(unreachable kubelets, rejected credentials, certificates without the node's IP in their SANs), and which nodes have no metrics. " +
+			"Use this when metrics tools report that metrics-server is not available.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkMetricsServerInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+
+		var sb strings.Builder
+		var gaps dataGaps
+		var findingLines, actions []string
+		var steps []util.NextStep
+		sb.WriteString(util.FormatHeader("Metrics Server Diagnosis"))
+		sb.WriteString("\n")
+
+		// --- APIService ---
+		svcNS, svcName := "kube-system", "metrics-server"
+		apiSvc, err := client.GetResource(ctx, k8s.APIResourceRef{GVR: apiServiceGVR, Kind: "APIService"}, "", metricsAPIServiceName)
+		available := false
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return util.HandleK8sError("getting APIService "+metricsAPIServiceName, err), nil, nil
+			}
+			sb.WriteString(util.FormatKeyValue("APIService", metricsAPIServiceName+" not registered") + "\n")
+			findingLines = append(findingLines, findings.Format("KD-SYS-003", "CRITICAL", "APIService "+metricsAPIServiceName+" is not registered: metrics-server is not installed, so kubectl top and resource-based HPAs cannot work"))
+			actions = append(actions, "Install metrics-server (kubectl apply -f https://github.com/kubernetes-sigs/metrics-server/releases/latest/download/components.yaml, or the cloud provider's add-on)")
+		} else {
+			status := describeAPIService(apiSvc)
+			available = status.Available
+			if status.ServiceName != "" {
+				svcNS, svcName = status.ServiceNS, status.ServiceName
+			}
+			sb.WriteString(util.FormatKeyValue("APIService", metricsAPIServiceName) + "\n")
+			sb.WriteString(util.FormatKeyValue("Service", valueOrNone(strings.Trim(status.ServiceNS+"/"+status.ServiceName, "/"))) + "\n")
+			sb.WriteString(util.FormatKeyValue("Available", fmt.Sprintf("%t (%s)", status.Available, valueOrNone(status.Reason))) + "\n")
+			if status.Message != "" {
+				sb.WriteString(util.FormatKeyValue("Message", truncateName(status.Message, 200)) + "\n")
+			}
+			if status.InsecureSkipTLSVerify {
+				sb.WriteString(util.FormatKeyValue("TLS to metrics-server", "not verified (insecureSkipTLSVerify)") + "\n")
+			}
+			if !status.Available {
+				findingLines = append(findingLines, findings.Format("KD-SYS-003", "CRITICAL", fmt.Sprintf("APIService %s is unavailable (%s): %s", metricsAPIServiceName, valueOrNone(status.Reason), apiServiceReasonMeaning(status.Reason))))
+				if a := apiServiceReasonAction(status.Reason, svcNS, svcName); a != "" {
+					actions = append(actions, a)
+				}
+			}
+		}
+
+		// --- Service endpoints ---
+		svc, svcErr := client.GetService(ctx, svcNS, svcName)
+		if svcErr != nil {
+			svc = nil
+			if !apierrors.IsNotFound(svcErr) {
+				gaps.record("metrics-server Service", svcErr)
+			}
+		}
+		if svc != nil {
+			if health, epErr := client.GetServiceEndpointHealth(ctx, svcNS, svcName); !gaps.record("metrics-server endpoints", epErr) {
+				sb.WriteString(util.FormatKeyValue("Endpoints", fmt.Sprintf("%d ready, %d not ready", health.ReadyCount, health.NotReadyCount)) + "\n")
+				if health.ReadyCount == 0 {
+					findingLines = append(findingLines, findings.Format("KD-SVC-001", "CRITICAL", fmt.Sprintf("Service %s/%s has no ready endpoints, so the API server has nowhere to send metrics requests", svcNS, svcName)))
+				}
+			}
+		}
+
+		// --- Pods ---
+		pods, podErr := findMetricsServerPods(ctx, client, svcNS, svc)
+		gaps.record("metrics-server pods", podErr)
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("METRICS-SERVER PODS"))
+		sb.WriteString("\n")
+		var flags []string
+		if len(pods) == 0 {
+			sb.WriteString("  No metrics-server pods found.\n")
+			if podErr == nil && apiSvc != nil {
+				findingLines = append(findingLines, findings.Format("KD-SYS-003", "CRITICAL", fmt.Sprintf("No metrics-server pods found in %s: the APIService points at a Service with nothing behind it", svcNS)))
+				actions = append(actions, fmt.Sprintf("Check the metrics-server Deployment in %s (kubectl -n %s get deploy,rs -l k8s-app=metrics-server) for scaling or admission errors", svcNS, svcNS))
+			}
+		} else {
+			var rows [][]string
+			for i := range pods {
+				p := &pods[i]
+				ready, total, restarts := podContainerSummary(p)
+				rows = append(rows, []string{p.Name, podPhaseReason(p), fmt.Sprintf("%d/%d", ready, total), fmt.Sprintf("%d", restarts), valueOrNone(p.Spec.NodeName)})
+				if !isPodHealthy(p) {
+					findingLines = append(findingLines, findings.Format("KD-SYS-001", "CRITICAL", fmt.Sprintf("metrics-server pod %s is %s (%d/%d ready, %d restarts)", p.Name, podPhaseReason(p), ready, total, restarts)))
+					steps = append(steps, nextStep("diagnose_pod", "find out why metrics-server is unhealthy", "namespace", p.Namespace, "name", p.Name))
+				}
+			}
+			sb.WriteString(util.FormatTable([]string{"NAME", "STATUS", "READY", "RESTARTS", "NODE"}, rows))
+			flags = metricsServerFlags(&pods[0])
+			if len(flags) > 0 {
+				sb.WriteString(util.FormatKeyValue("Kubelet flags", strings.Join(flags, " ")) + "\n")
+			}
+		}
+
+		// --- Kubelet scrape errors ---
+		var logs strings.Builder
+		for i := range pods {
+			p := &pods[i]
+			out, logErr := client.GetPodLogs(ctx, p.Namespace, p.Name, metricsServerContainer(p), metricsServerLogLines, false, metricsServerLogWindow)
+			if gaps.record("metrics-server logs ("+p.Name+")", logErr) {
+				continue
+			}
+			logs.WriteString(out)
+			logs.WriteString("\n")
+		}
+		failures := classifyScrapeErrors(logs.String())
+		if len(pods) > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("KUBELET SCRAPE ERRORS (last " + metricsServerLogWindow + ")"))
+			sb.WriteString("\n")
+			if len(failures) == 0 {
+				sb.WriteString("  None in the scanned logs.\n")
+			} else {
+				var rows [][]string
+				for _, f := range failures {
+					rows = append(rows, []string{f.Kind, fmt.Sprintf("%d", f.Count), valueOrNone(joinLimited(sortedKeys(f.Nodes), 3)), truncateName(f.Sample, 100)})
+				}
+				sb.WriteString(util.FormatTable([]string{"KIND", "COUNT", "NODES", "SAMPLE"}, rows))
+			}
+		}
+		for _, f := range failures {
+			findingLines = append(findingLines, findings.Format("KD-SYS-003", scrapeFailureSeverity(f.Kind), fmt.Sprintf("%d kubelet scrape error(s): %s on %s (%s)",
+				f.Count, f.Kind, valueOrNone(joinLimited(sortedKeys(f.Nodes), 3)), truncateName(f.Sample, 160))))
+			actions = append(actions, scrapeFailureAction(f.Kind, flags))
+		}
+
+		// --- Node coverage ---
+		if available {
+			nodes, nodeErr := client.ListNodes(ctx, metav1.ListOptions{})
+			metrics, metricsErr := client.GetNodeMetrics(ctx)
+			if metricsErr != nil {
+				findingLines = append(findingLines, findings.Format("KD-SYS-003", "CRITICAL", "The APIService reports Available but listing node metrics failed: "+metricsErr.Error()))
+			} else if !gaps.record("nodes", nodeErr) {
+				reported := make(map[string]bool, len(metrics))
+				for _, m := range metrics {
+					reported[m.Name] = true
+				}
+				var missing []string
+				for _, n := range nodes {
+					if !reported[n.Name] {
+						missing = append(missing, n.Name)
+					}
+				}
+				sb.WriteString("\n")
+				sb.WriteString(util.FormatKeyValue("Node coverage", fmt.Sprintf("%d of %d nodes have metrics", len(nodes)-len(missing), len(nodes))) + "\n")
+				if len(missing) > 0 {
+					findingLines = append(findingLines, findings.Format("KD-SYS-003", "WARNING", fmt.Sprintf("%d node(s) have no metrics (%s): kubectl top and HPAs ignore pods on them", len(missing), joinLimited(missing, 5))))
+					steps = append(steps, nextStep("diagnose_node", "a node is missing from the metrics API", "name", missing[0]))
+				}
+			}
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  metrics-server is healthy and serving metrics for every node.\n")
+		}
+		for _, f := range dedupe(findingLines) {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+		gaps.write(&sb)
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// apiServiceStatus is the part of an APIService that explains whether the
+// API server can reach the aggregated API behind it. ServiceName is empty
+// for APIs served by the API server itself.
+type apiServiceStatus struct {
+	Name                  string
+	Group                 string
+	Version               string
+	ServiceNS             string
+	ServiceName           string
+	InsecureSkipTLSVerify bool
+	Available             bool
+	Reason                string
+	Message               string
+}
+
+func describeAPIService(u *unstructured.Unstructured) apiServiceStatus {
+	s := apiServiceStatus{Name: u.GetName()}
+	s.Group, _, _ = unstructured.NestedString(u.Object, "spec", "group")
+	s.Version, _, _ = unstructured.NestedString(u.Object, "spec", "version")
+	s.ServiceNS, _, _ = unstructured.NestedString(u.Object, "spec", "service", "namespace")
+	s.ServiceName, _, _ = unstructured.NestedString(u.Object, "spec", "service", "name")
+	s.InsecureSkipTLSVerify, _, _ = unstructured.NestedBool(u.Object, "spec", "insecureSkipTLSVerify")
+	if c := findCRCondition(resourceConditions(u), "Available"); c != nil {
+		s.Available = c.Status == "True"
+		s.Reason = c.Reason
+		s.Message = c.Message
+	}
+	return s
+}
+
+// apiServiceReasonMeaning explains an Available=False reason of an APIService.
+func apiServiceReasonMeaning(reason string) string {
+	switch reason {
+	case "ServiceNotFound":
+		return "the Service it points at does not exist"
+	case "MissingEndpoints":
+		return "the Service has no ready endpoints, so the pods serving the API are missing or not ready"
+	case "FailedDiscoveryCheck":
+		return "the API server could not get a valid response from metrics-server; usually the control plane cannot reach the pod (firewall, NetworkPolicy, or the pod port) or the backend is failing its own requests"
+	case "ServiceAccessError":
+		return "the API server could not resolve the Service's endpoints"
+	}
+	return "the API server cannot serve metrics.k8s.io"
+}
+
+func apiServiceReasonAction(reason, ns, name string) string {
+	switch reason {
+	case "ServiceNotFound":
+		return fmt.Sprintf("Recreate Service %s/%s or reinstall metrics-server so the APIService has a backend", ns, name)
+	case "FailedDiscoveryCheck":
+		return fmt.Sprintf("Allow traffic from the control plane to the pods behind Service %s/%s on their secure port, including in NetworkPolicies and node security groups, then check the pod logs", ns, name)
+	}
+	return ""
+}
+
+// findMetricsServerPods returns the pods behind the metrics-server Service,
+// or those matching the well-known metrics-server labels when the Service
+// is missing.
+func findMetricsServerPods(ctx context.Context, client *k8s.ClusterClient, ns string, svc *corev1.Service) ([]corev1.Pod, error) {
+	if svc != nil && len(svc.Spec.Selector) > 0 {
+		return client.ListPods(ctx, svc.Namespace, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String()})
+	}
+	for _, selector := range metricsServerSelectors {
+		pods, err := client.ListPods(ctx, ns, metav1.ListOptions{LabelSelector: selector})
+		if err != nil || len(pods) > 0 {
+			return pods, err
+		}
+	}
+	return nil, nil
+}
+
+// metricsServerContainer returns the metrics-server container of a pod,
+// skipping sidecars such as addon-resizer.
+func metricsServerContainer(p *corev1.Pod) string {
+	for _, c := range p.Spec.Containers {
+		if strings.Contains(c.Name, "metrics-server") {
+			return c.Name
+		}
+	}
+	if len(p.Spec.Containers) > 0 {
+		return p.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+// metricsServerFlags returns the --kubelet-* flags of the metrics-server
+// container, which decide how it connects to kubelets.
+func metricsServerFlags(p *corev1.Pod) []string {
+	name := metricsServerContainer(p)
+	var flags []string
+	for _, c := range p.Spec.Containers {
+		if c.Name != name {
+			continue
+		}
+		for _, arg := range append(append([]string{}, c.Command...), c.Args...) {
+			if strings.HasPrefix(arg, "--kubelet-") {
+				flags = append(flags, arg)
+			}
+		}
+	}
+	return flags
+}
+
+// classifyScrapeErrors groups the kubelet scrape errors in metrics-server
+// logs by kind, most frequent first.
+func classifyScrapeErrors(logs string) []*scrapeFailure {
+	byKind := make(map[string]*scrapeFailure)
+	for _, line := range strings.Split(logs, "\n") {
+		if !strings.Contains(line, "Failed to scrape node") && !strings.Contains(line, "unable to fully scrape metrics") &&
+			!strings.Contains(line, "unable to fetch metrics from") {
+			continue
+		}
+		msg := line
+		if m := scrapeErrText.FindStringSubmatch(line); m != nil {
+			msg = strings.ReplaceAll(m[1], `\"`, `"`)
+		}
+		kind := classifyScrapeError(msg)
+		f := byKind[kind]
+		if f == nil {
+			f = &scrapeFailure{Kind: kind, Nodes: make(map[string]bool), Sample: strings.TrimSpace(msg)}
+			byKind[kind] = f
+		}
+		f.Count++
+		for _, re := range scrapeNodePatterns {
+			if m := re.FindStringSubmatch(line); m != nil {
+				f.Nodes[m[1]] = true
+				break
+			}
+		}
+	}
+	out := make([]*scrapeFailure, 0, len(byKind))
+	for _, f := range byKind {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Kind < out[j].Kind
+	})
+	return out
+}
+
+// classifyScrapeError names the kind of a single kubelet scrape error.
+func classifyScrapeError(msg string) string {
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(lower, "doesn't contain any ip sans") || strings.Contains(lower, "x509: certificate is valid for"):
+		return scrapeErrCertSAN
+	case strings.Contains(lower, "x509:"):
+		return scrapeErrCertAuthority
+	case strings.Contains(lower, "connection refused") || strings.Contains(lower, "i/o timeout") || strings.Contains(lower, "no route to host") ||
+		strings.Contains(lower, "context deadline exceeded") || strings.Contains(lower, "no such host"):
+		return scrapeErrConnect
+	case strings.Contains(lower, "unauthorized") || strings.Contains(lower, "forbidden") || strings.Contains(lower, "401") || strings.Contains(lower, "403"):
+		return scrapeErrAuth
+	}
+	return scrapeErrOther
+}
+
+func scrapeFailureSeverity(kind string) string {
+	if kind == scrapeErrOther {
+		return "WARNING"
+	}
+	return "CRITICAL"
+}
+
+// scrapeFailureAction returns the fix for a kind of scrape error, given the
+// metrics-server --kubelet-* flags.
+func scrapeFailureAction(kind string, flags []string) string {
+	switch kind {
+	case scrapeErrCertSAN:
+		action := "Kubelet serving certificates do not include the address metrics-server dials. Enable kubelet serving certificates signed by the cluster CA " +
+			"(serverTLSBootstrap: true in the kubelet config, then approve the kubelet-serving CSRs)"
+		if !hasFlagPrefix(flags, "--kubelet-preferred-address-types=Hostname") {
+			action += ", or set --kubelet-preferred-address-types=Hostname when the certificates carry node names"
+		}
+		return action + "; --kubelet-insecure-tls hides the error but disables verification"
+	case scrapeErrCertAuthority:
+		return "Kubelet certificates are self-signed or from another CA: enable serverTLSBootstrap and approve the kubelet-serving CSRs, or point --kubelet-certificate-authority at the CA that signs them"
+	case scrapeErrConnect:
+		return "metrics-server cannot reach kubelets on port 10250: allow pod-to-node traffic on 10250 in NetworkPolicies and node firewalls, and check --kubelet-preferred-address-types resolves to reachable addresses"
+	case scrapeErrAuth:
+		return "Kubelets reject metrics-server's credentials: make sure its ServiceAccount is bound to the system:metrics-server ClusterRole (nodes/metrics get) and kubelet webhook authorization is enabled"
+	}
+	return "Read the metrics-server logs (get_pod_logs) for the full scrape errors"
+}
+
+func hasFlagPrefix(flags []string, prefix string) bool {
+	for _, f := range flags {
+		if strings.HasPrefix(f, prefix) {
+			return true
+		}
+	}
+	return false
+}
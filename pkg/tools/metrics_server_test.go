@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+func TestClassifyScrapeErrors(t *testing.T) {
+	logs := `I0915 10:00:00.000000       1 serving.go:374] Generated self-signed cert
+E0915 10:00:15.000000       1 scraper.go:149] "Failed to scrape node" err="Get \"https://10.0.1.4:10250/metrics/resource\": x509: cannot validate certificate for 10.0.1.4 because it doesn't contain any IP SANs" node="node-a"
+E0915 10:00:30.000000       1 scraper.go:149] "Failed to scrape node" err="Get \"https://10.0.1.5:10250/metrics/resource\": x509: cannot validate certificate for 10.0.1.5 because it doesn't contain any IP SANs" node="node-b"
+E0915 10:00:45.000000       1 scraper.go:149] "Failed to scrape node" err="Get \"https://10.0.1.4:10250/metrics/resource\": x509: cannot validate certificate for 10.0.1.4 because it doesn't contain any IP SANs" node="node-a"
+E0915 10:01:00.000000       1 scraper.go:149] "Failed to scrape node" err="Get \"https://10.0.1.6:10250/metrics/resource\": dial tcp 10.0.1.6:10250: i/o timeout" node="node-c"
+E0915 10:01:15.000000       1 manager.go:111] unable to fully scrape metrics: unable to fully scrape metrics from source kubelet_summary:node-d: unable to fetch metrics from Kubelet node-d (10.0.1.7): request failed - "401 Unauthorized"`
+	failures := classifyScrapeErrors(logs)
+	if len(failures) != 3 {
+		t.Fatalf("got %d failure kinds, want 3: %+v", len(failures), failures)
+	}
+	san := failures[0]
+	if san.Kind != scrapeErrCertSAN || san.Count != 3 || strings.Join(sortedKeys(san.Nodes), ",") != "node-a,node-b" {
+		t.Errorf("most frequent failure = %+v, want 3 SAN errors on node-a and node-b", san)
+	}
+	if !strings.HasPrefix(san.Sample, `Get "https://10.0.1.4:10250`) {
+		t.Errorf("sample should be the unescaped err field, got %q", san.Sample)
+	}
+	kinds := map[string]string{}
+	for _, f := range failures[1:] {
+		kinds[f.Kind] = strings.Join(sortedKeys(f.Nodes), ",")
+	}
+	if kinds[scrapeErrConnect] != "node-c" || kinds[scrapeErrAuth] != "node-d" {
+		t.Errorf("other failures = %v", kinds)
+	}
+}
+
+func TestClassifyScrapeError(t *testing.T) {
+	tests := map[string]string{
+		"x509: certificate is valid for node-a, not 10.0.1.4":                      scrapeErrCertSAN,
+		"x509: certificate signed by unknown authority":                            scrapeErrCertAuthority,
+		"dial tcp 10.0.1.6:10250: connect: connection refused":                     scrapeErrConnect,
+		"request failed, status: \"403 Forbidden\"":                                scrapeErrAuth,
+		"unable to decode response: unexpected end of JSON input":                  scrapeErrOther,
+		"Get \"https://node-a:10250/metrics/resource\": context deadline exceeded": scrapeErrConnect,
+	}
+	for msg, want := range tests {
+		if got := classifyScrapeError(msg); got != want {
+			t.Errorf("classifyScrapeError(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
+
+func TestDescribeAPIService(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"service":               map[string]any{"namespace": "monitoring", "name": "metrics-server"},
+			"insecureSkipTLSVerify": true,
+		},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Available", "status": "False", "reason": "FailedDiscoveryCheck",
+				"message": "failing or missing response from https://10.244.1.9:4443/apis/metrics.k8s.io/v1beta1: context deadline exceeded"},
+		}},
+	}}
+	got := describeAPIService(u)
+	if got.ServiceNS != "monitoring" || got.ServiceName != "metrics-server" || !got.InsecureSkipTLSVerify {
+		t.Errorf("spec = %+v", got)
+	}
+	if got.Available || got.Reason != "FailedDiscoveryCheck" || !strings.Contains(got.Message, "4443") {
+		t.Errorf("condition = %+v", got)
+	}
+	if !strings.Contains(apiServiceReasonAction(got.Reason, got.ServiceNS, got.ServiceName), "monitoring") {
+		t.Error("FailedDiscoveryCheck action should name the metrics-server namespace")
+	}
+}
+
+func TestMetricsServerFlags(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics-server-1"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "addon-resizer", Args: []string{"--kubelet-ignored=true"}},
+			{Name: "metrics-server", Args: []string{"--secure-port=4443", "--kubelet-preferred-address-types=InternalIP", "--kubelet-insecure-tls"}},
+		}},
+	}
+	if got := strings.Join(metricsServerFlags(pod), " "); got != "--kubelet-preferred-address-types=InternalIP --kubelet-insecure-tls" {
+		t.Errorf("metricsServerFlags = %q", got)
+	}
+	if a := scrapeFailureAction(scrapeErrCertSAN, metricsServerFlags(pod)); !strings.Contains(a, "serverTLSBootstrap") || !strings.Contains(a, "Hostname") {
+		t.Errorf("SAN action = %q", a)
+	}
+}
+
+func TestCheckMetricsServerTool(t *testing.T) {
+	apiService := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{"service": map[string]any{"namespace": "kube-system", "name": "metrics-server"}},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Available", "status": "False", "reason": "MissingEndpoints", "message": "endpoints for service/metrics-server in \"kube-system\" have no addresses"},
+		}},
+	}}
+	apiService.SetAPIVersion("apiregistration.k8s.io/v1")
+	apiService.SetKind("APIService")
+	apiService.SetName(metricsAPIServiceName)
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{apiServiceGVR: "APIServiceList"}, apiService)
+
+	crashing := crashedStatus(1, "Error")
+	crashing.Name = "metrics-server"
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "metrics-server", Namespace: "kube-system"},
+			Spec: corev1.ServiceSpec{Selector: map[string]string{"k8s-app": "metrics-server"}}},
+		&corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "metrics-server", Namespace: "kube-system"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "metrics-server-abc", Namespace: "kube-system", Labels: map[string]string{"k8s-app": "metrics-server"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "metrics-server", Args: []string{"--kubelet-insecure-tls"}}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{crashing}},
+		},
+	)
+	client := k8s.NewClusterClientForTesting(fakeClient, nil)
+	client.DynamicClient = dyn
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "test"}, nil)
+	registerMetricsServerTools(server, client)
+
+	ctx := context.Background()
+	t1, t2 := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, t1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer serverSession.Close()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "test"}, nil).Connect(ctx, t2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "check_metrics_server"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := res.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"false (MissingEndpoints)", "KD-SVC-001", "0/1", "KD-SYS-001", "metrics-server-abc", "--kubelet-insecure-tls"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+}
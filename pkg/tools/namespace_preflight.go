@@ -0,0 +1,511 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// systemNamespaces cannot be deleted, or break the cluster when they are.
+var systemNamespaces = []string{"default", "kube-system", "kube-public", "kube-node-lease"}
+
+// staticIPAnnotations pin a LoadBalancer Service to a pre-created address.
+var staticIPAnnotations = []string{
+	"service.beta.kubernetes.io/azure-load-balancer-ipv4",
+	"service.beta.kubernetes.io/azure-pip-name",
+	"service.beta.kubernetes.io/aws-load-balancer-eip-allocations",
+}
+
+// routineFinalizers are released by built-in controllers as part of every
+// namespace deletion and are not reported as stall risks.
+var routineFinalizers = []string{"kubernetes.io/pvc-protection", "kubernetes.io/pv-protection", "batch.kubernetes.io/job-tracking"}
+
+type preflightNamespaceDeletionInput struct {
+	Namespace           string `json:"namespace" jsonschema:"required,Namespace that would be deleted"`
+	SkipCustomResources bool   `json:"skip_custom_resources,omitempty" jsonschema:"Skip scanning custom resources for finalizers (faster on clusters with many CRDs)"`
+	TimeoutSeconds      int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// nsConsumer is an object in another namespace that addresses a Service by
+// its cross-namespace DNS name.
+type nsConsumer struct {
+	Namespace string
+	Object    string // kind/name
+	Service   string
+	Via       string
+}
+
+func registerNamespacePreflightTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "preflight_namespace_deletion",
+		Description: "Report what deleting a namespace would destroy before you do it: workloads and config, PVCs whose volumes are deleted (reclaimPolicy Delete) " +
+			"or retained, LoadBalancer IPs that would be released, workloads and config in other namespaces that use its Services, and finalizers or " +
+			"unavailable APIs likely to stall the deletion. Read-only.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input preflightNamespaceDeletionInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if input.Namespace == "" {
+			return util.ErrorResult("namespace is required"), nil, nil
+		}
+		ns := input.Namespace
+		namespace, err := client.GetNamespace(ctx, ns)
+		if err != nil {
+			return util.HandleK8sError("getting namespace "+ns, err), nil, nil
+		}
+
+		opts := metav1.ListOptions{}
+		deployments, err := client.ListDeployments(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing deployments", err), nil, nil
+		}
+		statefulSets, err := client.ListStatefulSets(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing statefulsets", err), nil, nil
+		}
+		daemonSets, err := client.ListDaemonSets(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing daemonsets", err), nil, nil
+		}
+		jobs, err := client.ListJobs(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing jobs", err), nil, nil
+		}
+		cronJobs, err := client.ListCronJobs(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing cronjobs", err), nil, nil
+		}
+		pods, err := client.ListAllPods(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		services, err := client.ListServices(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing services", err), nil, nil
+		}
+		ingresses, err := client.ListIngresses(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing ingresses", err), nil, nil
+		}
+		pvcs, err := client.ListPVCs(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing PVCs", err), nil, nil
+		}
+		configMaps, err := client.ListConfigMaps(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing configmaps", err), nil, nil
+		}
+		secrets, err := client.ListSecrets(ctx, ns, opts)
+		if err != nil {
+			return util.HandleK8sError("listing secrets", err), nil, nil
+		}
+
+		var sb strings.Builder
+		var findingLines, actions []string
+		var steps []util.NextStep
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Namespace Deletion Preflight: %s", ns)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Status", string(namespace.Status.Phase)) + "\n")
+		sb.WriteString(util.FormatKeyValue("Age", util.FormatAge(namespace.CreationTimestamp.Time)) + "\n\n")
+		if slices.Contains(systemNamespaces, ns) {
+			findingLines = append(findingLines, findings.Format("KD-SYS-011", "CRITICAL", fmt.Sprintf("%s is a system namespace; the API server refuses to delete default, kube-system, and kube-public, and deleting %s breaks cluster components", ns, ns)))
+		}
+		if namespace.DeletionTimestamp != nil {
+			findingLines = append(findingLines, findings.Format("KD-SYS-002", "INFO", fmt.Sprintf("Namespace %s is already Terminating (since %s ago)", ns, util.FormatAge(namespace.DeletionTimestamp.Time))))
+			steps = append(steps, nextStep("find_stuck_resources", "see what is holding up the deletion", "namespace", ns))
+		}
+
+		// --- What would be deleted ---
+		sb.WriteString(util.FormatSubHeader("Would Be Deleted"))
+		sb.WriteString("\n")
+		names := func(n int, name func(int) string) []string {
+			out := make([]string, 0, n)
+			for i := range n {
+				out = append(out, name(i))
+			}
+			return out
+		}
+		inventory := []struct {
+			kind  string
+			names []string
+		}{
+			{"Deployment", names(len(deployments), func(i int) string { return deployments[i].Name })},
+			{"StatefulSet", names(len(statefulSets), func(i int) string { return statefulSets[i].Name })},
+			{"DaemonSet", names(len(daemonSets), func(i int) string { return daemonSets[i].Name })},
+			{"Job", names(len(jobs), func(i int) string { return jobs[i].Name })},
+			{"CronJob", names(len(cronJobs), func(i int) string { return cronJobs[i].Name })},
+			{"Pod", names(len(pods), func(i int) string { return pods[i].Name })},
+			{"Service", names(len(services), func(i int) string { return services[i].Name })},
+			{"Ingress", names(len(ingresses), func(i int) string { return ingresses[i].Name })},
+			{"PersistentVolumeClaim", names(len(pvcs), func(i int) string { return pvcs[i].Name })},
+			{"ConfigMap", names(len(configMaps), func(i int) string { return configMaps[i].Name })},
+			{"Secret", names(len(secrets), func(i int) string { return secrets[i].Name })},
+		}
+		var rows [][]string
+		for _, inv := range inventory {
+			if len(inv.names) > 0 {
+				rows = append(rows, []string{inv.kind, fmt.Sprintf("%d", len(inv.names)), summarizeNames(inv.names, 5)})
+			}
+		}
+		if len(rows) == 0 {
+			sb.WriteString("  The namespace is empty.\n")
+		} else {
+			sb.WriteString(util.FormatTable([]string{"KIND", "COUNT", "NAMES"}, rows))
+		}
+
+		// --- Persistent volumes ---
+		if len(pvcs) > 0 {
+			pvs, pvErr := client.ListPVs(ctx)
+			classes, classErr := client.ListStorageClasses(ctx)
+			pvByName := make(map[string]*corev1.PersistentVolume, len(pvs))
+			for i := range pvs {
+				pvByName[pvs[i].Name] = &pvs[i]
+			}
+			classByName := make(map[string]*storagev1.StorageClass, len(classes))
+			defaultClass := ""
+			for i := range classes {
+				classByName[classes[i].Name] = &classes[i]
+				if classes[i].Annotations[defaultStorageClassAnnotation] == "true" {
+					defaultClass = classes[i].Name
+				}
+			}
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("Persistent Volumes"))
+			sb.WriteString("\n")
+			if pvErr != nil || classErr != nil {
+				sb.WriteString(fmt.Sprintf("  Reclaim policies may be incomplete: %v\n", errors.Join(pvErr, classErr)))
+			}
+			var pvRows [][]string
+			deleted := 0
+			for i := range pvcs {
+				pvc := &pvcs[i]
+				policy := pvcReclaimPolicy(pvc, pvByName, classByName, defaultClass)
+				capacity := "-"
+				if q, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+					capacity = q.String()
+				} else if q, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+					capacity = q.String()
+				}
+				effect := "unknown"
+				switch policy {
+				case string(corev1.PersistentVolumeReclaimDelete):
+					effect = "disk and data deleted"
+					deleted++
+					findingLines = append(findingLines, findings.Format("KD-STO-003", "WARNING", fmt.Sprintf("PVC %s/%s (%s) has reclaimPolicy Delete: deleting the namespace deletes its disk and data", ns, pvc.Name, capacity)))
+				case string(corev1.PersistentVolumeReclaimRetain):
+					effect = "PV kept as Released"
+				}
+				pvRows = append(pvRows, []string{pvc.Name, capacity, valueOrNone(pvc.Spec.VolumeName), valueOrNone(policy), effect})
+			}
+			sb.WriteString(util.FormatTable([]string{"PVC", "CAPACITY", "PV", "RECLAIM", "ON DELETE"}, pvRows))
+			if deleted > 0 {
+				actions = append(actions, fmt.Sprintf("Back up the %d volume(s) with reclaimPolicy Delete, or patch their PVs to persistentVolumeReclaimPolicy=Retain before deleting", deleted))
+			}
+		}
+
+		// --- LoadBalancer addresses ---
+		var lbRows [][]string
+		for i := range services {
+			svc := &services[i]
+			if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+				continue
+			}
+			addresses := strings.Join(loadBalancerAddresses(svc), ", ")
+			static := loadBalancerStatic(svc)
+			lbRows = append(lbRows, []string{svc.Name, valueOrNone(addresses), fmt.Sprintf("%t", static)})
+			if addresses == "" {
+				continue
+			}
+			if static {
+				findingLines = append(findingLines, findings.Format("KD-SVC-004", "INFO", fmt.Sprintf("LoadBalancer Service %s/%s uses a static address (%s); the frontend is removed but the address stays reserved", ns, svc.Name, addresses)))
+			} else {
+				findingLines = append(findingLines, findings.Format("KD-SVC-004", "WARNING", fmt.Sprintf("LoadBalancer Service %s/%s has a dynamically allocated address (%s) that is released on deletion and cannot be recovered", ns, svc.Name, addresses)))
+				actions = append(actions, fmt.Sprintf("Reserve %s as a static IP or update DNS records and allowlists that point to it (Service %s)", addresses, svc.Name))
+			}
+		}
+		if len(lbRows) > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("LoadBalancer Addresses"))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatTable([]string{"SERVICE", "ADDRESS", "STATIC"}, lbRows))
+		}
+
+		// --- Cross-namespace consumers ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Cross-Namespace Consumers"))
+		sb.WriteString("\n")
+		if len(services) == 0 {
+			sb.WriteString("  No Services to consume.\n")
+		} else {
+			svcNames := make([]string, 0, len(services))
+			for i := range services {
+				svcNames = append(svcNames, services[i].Name)
+			}
+			otherPods, podErr := client.ListAllPods(ctx, "", opts)
+			otherServices, svcErr := client.ListServices(ctx, "", opts)
+			otherConfigMaps, cmErr := client.ListConfigMaps(ctx, "", opts)
+			if err := errors.Join(podErr, svcErr, cmErr); err != nil {
+				sb.WriteString(fmt.Sprintf("  Consumers may be incomplete: %v\n", err))
+			}
+			consumers := crossNamespaceConsumers(ns, svcNames, otherPods, otherServices, otherConfigMaps)
+			if len(consumers) == 0 {
+				sb.WriteString("  No workloads or config in other namespaces reference this namespace's Services.\n")
+			} else {
+				var consumerRows [][]string
+				bySvc := make(map[string][]string)
+				for _, c := range consumers {
+					consumerRows = append(consumerRows, []string{c.Namespace, c.Object, c.Service, c.Via})
+					bySvc[c.Service] = append(bySvc[c.Service], fmt.Sprintf("%s/%s", c.Namespace, c.Object))
+				}
+				sb.WriteString(util.FormatTable([]string{"NAMESPACE", "OBJECT", "SERVICE", "VIA"}, consumerRows))
+				for _, svc := range sortedMapKeys(bySvc) {
+					refs := dedupe(bySvc[svc])
+					findingLines = append(findingLines, findings.Format("KD-SVC-005", "WARNING", fmt.Sprintf("Service %s/%s is used from other namespaces by %s", ns, svc, summarizeNames(refs, 5))))
+				}
+				actions = append(actions, "Repoint or move the cross-namespace consumers listed above before deleting the namespace")
+			}
+		}
+
+		// --- Finalizers and APIs that stall deletion ---
+		var stallRows [][]string
+		byFinalizer := make(map[string][]string)
+		owners := make(map[string]string)
+		addFinalizers := func(kind, name, group string, finalizers []string) {
+			for _, f := range finalizers {
+				if slices.Contains(routineFinalizers, f) {
+					continue
+				}
+				owner := finalizerOwner(f, group)
+				stallRows = append(stallRows, []string{kind + "/" + name, f, owner})
+				byFinalizer[f] = append(byFinalizer[f], kind+"/"+name)
+				owners[f] = owner
+			}
+		}
+		for i := range pods {
+			addFinalizers("Pod", pods[i].Name, "", pods[i].Finalizers)
+		}
+		for i := range services {
+			addFinalizers("Service", services[i].Name, "", services[i].Finalizers)
+		}
+		for i := range pvcs {
+			addFinalizers("PersistentVolumeClaim", pvcs[i].Name, "", pvcs[i].Finalizers)
+		}
+		crNote := ""
+		if !input.SkipCustomResources {
+			crds, err := client.ListCRDs(ctx)
+			if err != nil {
+				crNote = fmt.Sprintf("Custom resources not checked: %v", err)
+			} else {
+				for i := range crds {
+					if crds[i].Spec.Scope != "Namespaced" {
+						continue
+					}
+					items, err := listCRDObjects(ctx, client, &crds[i], ns)
+					if err != nil {
+						continue
+					}
+					for j := range items {
+						addFinalizers(items[j].GetKind(), items[j].GetName(), crds[i].Spec.Group, items[j].GetFinalizers())
+					}
+				}
+			}
+		}
+		_, _, discoveryErr := client.Clientset.Discovery().ServerGroupsAndResources()
+		var unavailable []string
+		var groupErr *discovery.ErrGroupDiscoveryFailed
+		if errors.As(discoveryErr, &groupErr) {
+			for gv := range groupErr.Groups {
+				unavailable = append(unavailable, gv.String())
+			}
+			sort.Strings(unavailable)
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Deletion Blockers"))
+		sb.WriteString("\n")
+		if crNote != "" {
+			sb.WriteString("  " + crNote + "\n")
+		}
+		if len(stallRows) == 0 && len(unavailable) == 0 {
+			sb.WriteString("  No finalizers beyond the built-in ones and no unavailable APIs.\n")
+		}
+		if len(stallRows) > 0 {
+			sb.WriteString(util.FormatTable([]string{"OBJECT", "FINALIZER", "OWNER"}, stallRows))
+		}
+		for _, f := range sortedMapKeys(byFinalizer) {
+			severity := "INFO"
+			if owners[f] == "unknown controller" {
+				severity = "WARNING"
+			}
+			findingLines = append(findingLines, findings.Format("KD-SYS-002", severity, fmt.Sprintf("%d object(s) in %s carry finalizer %s (owner: %s); deletion waits until that controller removes it: %s",
+				len(byFinalizer[f]), ns, f, owners[f], summarizeNames(byFinalizer[f], 5))))
+		}
+		if len(byFinalizer) > 0 {
+			actions = append(actions, "Keep the controllers that own these finalizers installed and healthy until the namespace is gone; uninstall operators only afterwards")
+		}
+		if len(unavailable) > 0 {
+			sb.WriteString(fmt.Sprintf("  Unavailable APIs: %s\n", strings.Join(unavailable, ", ")))
+			findingLines = append(findingLines, findings.Format("KD-SYS-002", "WARNING", fmt.Sprintf("API group(s) %s are unavailable; namespace deletion stalls (NamespaceDeletionDiscoveryFailure) until they are served again", strings.Join(unavailable, ", "))))
+			actions = append(actions, "Fix or remove the unavailable APIServices (kubectl get apiservice | grep False) before deleting")
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "Nothing outside the namespace depends on it and no data volumes or public addresses would be lost"))
+			sb.WriteString("\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString(f)
+			sb.WriteString("\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// summarizeNames joins up to limit names and counts the rest.
+func summarizeNames(names []string, limit int) string {
+	if len(names) <= limit {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s, +%d more", strings.Join(names[:limit], ", "), len(names)-limit)
+}
+
+// pvcReclaimPolicy returns what happens to a claim's volume when the claim is
+// deleted: the bound PV's reclaim policy, or for an unbound claim its
+// StorageClass's. It returns "" when neither can be read.
+func pvcReclaimPolicy(pvc *corev1.PersistentVolumeClaim, pvByName map[string]*corev1.PersistentVolume, classByName map[string]*storagev1.StorageClass, defaultClass string) string {
+	if pv, ok := pvByName[pvc.Spec.VolumeName]; ok && pvc.Spec.VolumeName != "" {
+		return string(pv.Spec.PersistentVolumeReclaimPolicy)
+	}
+	className := defaultClass
+	if pvc.Spec.StorageClassName != nil {
+		className = *pvc.Spec.StorageClassName
+	}
+	if sc, ok := classByName[className]; ok {
+		if sc.ReclaimPolicy == nil {
+			return string(corev1.PersistentVolumeReclaimDelete)
+		}
+		return string(*sc.ReclaimPolicy)
+	}
+	return ""
+}
+
+// loadBalancerAddresses returns the IPs or hostnames assigned to a LoadBalancer Service.
+func loadBalancerAddresses(svc *corev1.Service) []string {
+	var out []string
+	for _, ing := range svc.Status.LoadBalancer.Ingress {
+		if ing.IP != "" {
+			out = append(out, ing.IP)
+		} else if ing.Hostname != "" {
+			out = append(out, ing.Hostname)
+		}
+	}
+	return out
+}
+
+// loadBalancerStatic reports whether a LoadBalancer Service requests a
+// pre-allocated address that outlives it.
+func loadBalancerStatic(svc *corev1.Service) bool {
+	if svc.Spec.LoadBalancerIP != "" {
+		return true
+	}
+	for _, a := range staticIPAnnotations {
+		if svc.Annotations[a] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceRefPattern matches cross-namespace DNS names of the given Services
+// (svc.ns, svc.ns.svc, svc.ns.svc.cluster.local), capturing the Service name.
+func serviceRefPattern(namespace string, services []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(services))
+	for _, s := range services {
+		quoted = append(quoted, regexp.QuoteMeta(s))
+	}
+	return regexp.MustCompile(`(?:^|[^a-z0-9.-])(` + strings.Join(quoted, "|") + `)\.` + regexp.QuoteMeta(namespace) + `(?:\.svc(?:\.[a-z0-9.-]*)?)?(?:[^a-z0-9.-]|$)`)
+}
+
+// crossNamespaceConsumers finds pods, ExternalName Services, and ConfigMaps
+// outside namespace that reference its Services by DNS name. Pods are
+// reported by their owning controller.
+func crossNamespaceConsumers(namespace string, services []string, pods []corev1.Pod, externalServices []corev1.Service, configMaps []corev1.ConfigMap) []nsConsumer {
+	pattern := serviceRefPattern(namespace, services)
+	seen := make(map[nsConsumer]bool)
+	var out []nsConsumer
+	add := func(c nsConsumer) {
+		if !seen[c] {
+			seen[c] = true
+			out = append(out, c)
+		}
+	}
+	for i := range pods {
+		p := &pods[i]
+		if p.Namespace == namespace {
+			continue
+		}
+		object := "pod/" + p.Name
+		if ref := metav1.GetControllerOf(p); ref != nil {
+			object = strings.ToLower(ref.Kind) + "/" + ref.Name
+		}
+		for _, c := range allContainers(p.Spec) {
+			for _, e := range c.Env {
+				if m := pattern.FindStringSubmatch(e.Value); m != nil {
+					add(nsConsumer{p.Namespace, object, m[1], "env " + e.Name})
+				}
+			}
+			for _, arg := range append(slices.Clone(c.Command), c.Args...) {
+				if m := pattern.FindStringSubmatch(arg); m != nil {
+					add(nsConsumer{p.Namespace, object, m[1], "args"})
+				}
+			}
+		}
+	}
+	for i := range externalServices {
+		s := &externalServices[i]
+		if s.Namespace == namespace || s.Spec.Type != corev1.ServiceTypeExternalName {
+			continue
+		}
+		if m := pattern.FindStringSubmatch(s.Spec.ExternalName); m != nil {
+			add(nsConsumer{s.Namespace, "service/" + s.Name, m[1], "externalName"})
+		}
+	}
+	for i := range configMaps {
+		cm := &configMaps[i]
+		if cm.Namespace == namespace {
+			continue
+		}
+		for _, key := range sortedMapKeys(cm.Data) {
+			if m := pattern.FindStringSubmatch(cm.Data[key]); m != nil {
+				add(nsConsumer{cm.Namespace, "configmap/" + cm.Name, m[1], "key " + key})
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Object < out[j].Object
+	})
+	return out
+}
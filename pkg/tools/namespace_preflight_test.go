@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPVCReclaimPolicy(t *testing.T) {
+	retain := corev1.PersistentVolumeReclaimRetain
+	fast := "fast"
+	pvByName := map[string]*corev1.PersistentVolume{
+		"pv-retained": {Spec: corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain}},
+	}
+	classByName := map[string]*storagev1.StorageClass{
+		"standard": {},
+		"fast":     {ReclaimPolicy: &retain},
+	}
+	tests := []struct {
+		name string
+		pvc  corev1.PersistentVolumeClaim
+		want string
+	}{
+		{"bound PV", corev1.PersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "pv-retained"}}, "Retain"},
+		{"default class", corev1.PersistentVolumeClaim{}, "Delete"},
+		{"explicit class", corev1.PersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &fast}}, "Retain"},
+		{"unknown PV", corev1.PersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "pv-missing", StorageClassName: &fast}}, "Retain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pvcReclaimPolicy(&tt.pvc, pvByName, classByName, "standard"); got != tt.want {
+				t.Errorf("pvcReclaimPolicy = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := pvcReclaimPolicy(&corev1.PersistentVolumeClaim{}, nil, nil, ""); got != "" {
+		t.Errorf("pvcReclaimPolicy without PVs or classes = %q, want empty", got)
+	}
+}
+
+func TestLoadBalancerStatic(t *testing.T) {
+	dynamic := &corev1.Service{Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "20.1.2.3"}}}}}
+	if loadBalancerStatic(dynamic) {
+		t.Error("Service without a requested address should not be static")
+	}
+	if got := loadBalancerAddresses(dynamic); len(got) != 1 || got[0] != "20.1.2.3" {
+		t.Errorf("loadBalancerAddresses = %v, want [20.1.2.3]", got)
+	}
+	pinned := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"service.beta.kubernetes.io/azure-pip-name": "shop-ip"}}}
+	if !loadBalancerStatic(pinned) {
+		t.Error("Service with azure-pip-name should be static")
+	}
+}
+
+func TestCrossNamespaceConsumers(t *testing.T) {
+	controller := true
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "shop", OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-7d9", Controller: &controller}}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Env: []corev1.EnvVar{
+				{Name: "DB_HOST", Value: "postgres.data.svc.cluster.local:5432"},
+				{Name: "OTHER", Value: "postgres.data-replica:5432"},
+			}}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "data"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "m", Env: []corev1.EnvVar{{Name: "DB", Value: "postgres.data"}}}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cli", Namespace: "ops"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "c", Args: []string{"--cache=redis.data:6379"}}}},
+		},
+	}
+	services := []corev1.Service{
+		{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "legacy"}, Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "postgres.data.svc.cluster.local"}},
+	}
+	configMaps := []corev1.ConfigMap{
+		{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "shop"}, Data: map[string]string{"url": "http://redis.data/", "unrelated": "redisx.data"}},
+	}
+
+	got := crossNamespaceConsumers("data", []string{"postgres", "redis"}, pods, services, configMaps)
+	want := []nsConsumer{
+		{"legacy", "service/db", "postgres", "externalName"},
+		{"ops", "pod/cli", "redis", "args"},
+		{"shop", "configmap/settings", "redis", "key url"},
+		{"shop", "replicaset/web-7d9", "postgres", "env DB_HOST"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d consumers, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("consumer %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSummarizeNames(t *testing.T) {
+	if got := summarizeNames([]string{"a", "b"}, 5); got != "a, b" {
+		t.Errorf("summarizeNames = %q, want %q", got, "a, b")
+	}
+	if got := summarizeNames([]string{"a", "b", "c"}, 2); got != "a, b, +1 more" {
+		t.Errorf("summarizeNames = %q, want %q", got, "a, b, +1 more")
+	}
+}
@@ -0,0 +1,289 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type fullNamespaceReportInput struct {
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace to report on"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+	DetailLevel    string `json:"detail_level,omitempty" jsonschema:"How much to return: summary (about 10 lines: status, top findings, next action), standard (the report without Mermaid diagrams), or full (everything, the default)"`
+}
+
+// reportSection is one analyzer run by full_namespace_report.
+type reportSection struct {
+	Name string
+	Tool string
+	// Timeout is set for tools that accept timeout_seconds.
+	Timeout bool
+}
+
+// namespaceReportSections are the analyzers behind full_namespace_report, in
+// report order.
+var namespaceReportSections = []reportSection{
+	{Name: "Namespace Health", Tool: "diagnose_namespace", Timeout: true},
+	{Name: "Resource Usage", Tool: "analyze_resource_usage", Timeout: true},
+	{Name: "Endpoint Health", Tool: "list_endpoint_health"},
+	{Name: "Network Policies", Tool: "analyze_network_policies", Timeout: true},
+	{Name: "Workload Lint", Tool: "lint_workloads", Timeout: true},
+	{Name: "Probes", Tool: "analyze_probes"},
+}
+
+// sectionResult is the outcome of one analyzer.
+type sectionResult struct {
+	Section  reportSection
+	Report   string
+	Steps    []util.NextStep
+	Err      error
+	Duration time.Duration
+}
+
+// reportFinding is a deduplicated finding with the sections that raised it.
+type reportFinding struct {
+	Finding  findings.Finding
+	Sections []string
+}
+
+func registerNamespaceReportTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "full_namespace_report",
+		Description: "Complete health report for one namespace. Runs diagnose_namespace, analyze_resource_usage, list_endpoint_health, " +
+			"analyze_network_policies, lint_workloads, and analyze_probes, merges their findings with duplicates removed, and " +
+			"returns one prioritized list with the combined suggested actions. Use this for a namespace review or hand-off " +
+			"instead of calling the analyzers one by one.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input fullNamespaceReportInput) (*mcp.CallToolResult, any, error) {
+		detail, err := util.ParseDetailLevel(input.DetailLevel)
+		if err != nil {
+			return util.ErrorResult("%v", err), nil, nil
+		}
+		ns := util.NamespaceOrAll(input.Namespace)
+		if ns == "" {
+			return util.ErrorResult("namespace is required (a single namespace, not 'all')"), nil, nil
+		}
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+
+		results, err := runNamespaceReport(ctx, client, ns, input.TimeoutSeconds)
+		if err != nil {
+			return util.ErrorResult("failed to run namespace analyzers: %v", err), nil, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Full Namespace Report: %s", ns)))
+		sb.WriteString("\n\n")
+
+		var gaps dataGaps
+		var reports []string
+		var steps []util.NextStep
+		rows := make([][]string, 0, len(results))
+		for _, r := range results {
+			if gaps.record(r.Section.Name, r.Err) {
+				rows = append(rows, []string{r.Section.Name, r.Section.Tool, "Not collected", r.Duration.Round(time.Millisecond).String()})
+				continue
+			}
+			reports = append(reports, r.Report)
+			steps = append(steps, r.Steps...)
+			status := "OK"
+			if n := len(mergeReportFindings([]string{r.Report}, []string{r.Section.Name})); n > 0 {
+				status = fmt.Sprintf("%d finding(s)", n)
+			}
+			rows = append(rows, []string{r.Section.Name, r.Section.Tool, status, r.Duration.Round(time.Millisecond).String()})
+		}
+		sb.WriteString(util.FormatTable([]string{"SECTION", "TOOL", "STATUS", "TOOK"}, rows))
+		sb.WriteString("\n")
+
+		var names []string
+		for _, r := range results {
+			if r.Err == nil {
+				names = append(names, r.Section.Name)
+			}
+		}
+		merged := mergeReportFindings(reports, names)
+		counts := make(map[string]int)
+		sb.WriteString("FINDINGS:\n")
+		if len(merged) == 0 {
+			sb.WriteString("  No issues found by the namespace analyzers.\n")
+		}
+		for _, f := range merged {
+			counts[f.Finding.Severity]++
+			sb.WriteString(fmt.Sprintf("%s (%s)\n", f.Finding, strings.Join(f.Sections, ", ")))
+		}
+
+		if actions := mergeReportActions(reports); len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range actions {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
+		sb.WriteString("\n")
+		switch {
+		case counts["CRITICAL"] > 0:
+			sb.WriteString(fmt.Sprintf("  %d critical and %d warning finding(s) across %d analyzer(s). Work through the actions in order.\n", counts["CRITICAL"], counts["WARNING"], len(names)))
+		case counts["WARNING"] > 0:
+			sb.WriteString(fmt.Sprintf("  No critical findings; %d warning(s) to review.\n", counts["WARNING"]))
+		default:
+			sb.WriteString(fmt.Sprintf("  Namespace %s looks healthy to every analyzer that ran.\n", ns))
+		}
+		sb.WriteString(gaps.assessment())
+		gaps.write(&sb)
+
+		return util.WithNextSteps(finishReport(sb.String(), detail), steps), nil, nil
+	})
+}
+
+// runNamespaceReport calls every analyzer in namespaceReportSections through
+// an in-memory MCP session, so each section is exactly the report the
+// standalone tool would return. Analyzers run concurrently and results come
+// back in section order.
+func runNamespaceReport(ctx context.Context, client *k8s.ClusterClient, ns string, timeoutSeconds int) ([]sectionResult, error) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "full_namespace_report", Version: "internal"}, nil)
+	registerDiagnosticTools(server, client)
+	registerResourceAnalysisTools(server, client)
+	registerNetworkAnalysisTools(server, client, Options{})
+	registerLintTools(server, client)
+	registerProbeTools(server, client)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer serverSession.Close()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "full_namespace_report", Version: "internal"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	results := make([]sectionResult, len(namespaceReportSections))
+	var wg sync.WaitGroup
+	for i, s := range namespaceReportSections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			args := map[string]any{"namespace": ns}
+			if s.Timeout && timeoutSeconds > 0 {
+				args["timeout_seconds"] = timeoutSeconds
+			}
+			start := time.Now()
+			res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: s.Tool, Arguments: args})
+			results[i] = sectionResult{Section: s, Duration: time.Since(start)}
+			results[i].Report, results[i].Steps, results[i].Err = sectionOutput(res, err)
+		}()
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// sectionOutput extracts the report text and next steps from an analyzer
+// result. A tool error becomes the section's error.
+func sectionOutput(res *mcp.CallToolResult, err error) (string, []util.NextStep, error) {
+	if err != nil {
+		return "", nil, err
+	}
+	var text string
+	if len(res.Content) > 0 {
+		if tc, ok := res.Content[0].(*mcp.TextContent); ok {
+			text = tc.Text
+		}
+	}
+	if res.IsError {
+		return "", nil, errors.New(strings.TrimPrefix(text, "Error: "))
+	}
+	var structured struct {
+		NextSteps []util.NextStep `json:"next_steps"`
+	}
+	if raw, err := json.Marshal(res.StructuredContent); err == nil {
+		_ = json.Unmarshal(raw, &structured)
+	}
+	return text, structured.NextSteps, nil
+}
+
+// plainFindingLine matches a finding line without a rule ID, as written by
+// util.FormatFinding.
+var plainFindingLine = regexp.MustCompile(`^\s*\[(CRITICAL|WARNING|INFO)\] (.*)$`)
+
+// mergeReportFindings collects the findings of each report, merges duplicates
+// by rule ID and message (keeping the highest severity), and orders them
+// critical first. names[i] labels the section reports[i] came from.
+func mergeReportFindings(reports, names []string) []reportFinding {
+	rank := map[string]int{"CRITICAL": 0, "WARNING": 1, "INFO": 2}
+	var merged []reportFinding
+	index := make(map[string]int)
+	for i, report := range reports {
+		for _, line := range strings.Split(report, "\n") {
+			f, ok := findings.Parse(line)
+			if !ok {
+				m := plainFindingLine.FindStringSubmatch(line)
+				if m == nil {
+					continue
+				}
+				f = findings.Finding{Severity: m[1], Message: strings.TrimSpace(m[2])}
+			}
+			if f.Severity == "OK" {
+				continue
+			}
+			key := f.ID + "|" + f.Message
+			j, seen := index[key]
+			if !seen {
+				index[key] = len(merged)
+				merged = append(merged, reportFinding{Finding: f, Sections: []string{names[i]}})
+				continue
+			}
+			if rank[f.Severity] < rank[merged[j].Finding.Severity] {
+				merged[j].Finding.Severity = f.Severity
+			}
+			merged[j].Sections = dedupe(append(merged[j].Sections, names[i]))
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return rank[merged[i].Finding.Severity] < rank[merged[j].Finding.Severity]
+	})
+	return merged
+}
+
+// reportActionLine matches a numbered suggested action.
+var reportActionLine = regexp.MustCompile(`^\s*\d+\.\s+(.*)$`)
+
+// mergeReportActions collects the SUGGESTED ACTIONS of each report in order,
+// without duplicates.
+func mergeReportActions(reports []string) []string {
+	var actions []string
+	for _, report := range reports {
+		inActions := false
+		for _, line := range strings.Split(report, "\n") {
+			if strings.TrimSpace(line) == "SUGGESTED ACTIONS:" {
+				inActions = true
+				continue
+			}
+			if !inActions {
+				continue
+			}
+			m := reportActionLine.FindStringSubmatch(line)
+			if m == nil {
+				if strings.TrimSpace(line) != "" {
+					inActions = false
+				}
+				continue
+			}
+			actions = append(actions, strings.TrimSpace(m[1]))
+		}
+	}
+	return dedupe(actions)
+}
@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+func TestMergeReportFindings(t *testing.T) {
+	reports := []string{
+		"[WARNING] KD-POD-005: 2 pods with >5 restarts\n[INFO] Namespace has 3 services\n[OK] All probes configured",
+		"  [CRITICAL] KD-POD-005: 2 pods with >5 restarts\n[WARNING] Service web has no endpoints\n[INFO] Namespace has 3 services",
+	}
+	got := mergeReportFindings(reports, []string{"Namespace Health", "Probes"})
+	if len(got) != 3 {
+		t.Fatalf("expected 3 merged findings, got %+v", got)
+	}
+	first := got[0]
+	if first.Finding.ID != "KD-POD-005" || first.Finding.Severity != "CRITICAL" || strings.Join(first.Sections, ",") != "Namespace Health,Probes" {
+		t.Errorf("duplicate should merge at the highest severity with both sections, got %+v", first)
+	}
+	if got[1].Finding.Severity != "WARNING" || got[2].Finding.Severity != "INFO" || len(got[2].Sections) != 2 {
+		t.Errorf("unexpected order or sections: %+v", got)
+	}
+}
+
+func TestMergeReportActions(t *testing.T) {
+	reports := []string{
+		"FINDINGS:\n[WARNING] x\n\nSUGGESTED ACTIONS:\n1. Add readiness probes\n2. Set memory limits\n\n=== Other ===\n1. not an action",
+		"SUGGESTED ACTIONS:\n  1. Set memory limits\n  2. Add a default-deny policy",
+	}
+	got := strings.Join(mergeReportActions(reports), "|")
+	if got != "Add readiness probes|Set memory limits|Add a default-deny policy" {
+		t.Errorf("mergeReportActions = %q", got)
+	}
+}
+
+func TestFullNamespaceReport(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "web:latest"}}},
+			Status: corev1.PodStatus{Phase: corev1.PodPending, ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			}}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}, Ports: []corev1.ServicePort{{Port: 80}}},
+		},
+	)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "test"}, nil)
+	registerNamespaceReportTools(server, k8s.NewClusterClientForTesting(fakeClient, nil))
+
+	ctx := context.Background()
+	t1, t2 := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, t1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer serverSession.Close()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "test"}, nil).Connect(ctx, t2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "full_namespace_report", Arguments: map[string]any{"namespace": "shop"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Fatalf("unexpected error result: %v", res.Content)
+	}
+	text := res.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"Full Namespace Report: shop", "diagnose_namespace", "analyze_probes", "[CRITICAL] KD-POD-010", "(Namespace Health)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "[CRITICAL]") > strings.Index(text, "[WARNING]") && strings.Contains(text, "[WARNING]") {
+		t.Errorf("critical findings should come first:\n%s", text)
+	}
+
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "full_namespace_report", Arguments: map[string]any{"namespace": "all"}})
+	if err != nil || !res.IsError {
+		t.Errorf("expected an error result for namespace=all, got %v %v", res, err)
+	}
+}
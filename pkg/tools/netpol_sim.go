@@ -0,0 +1,518 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/mermaid"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// netpolMaxBackends caps how many Service backend pods are evaluated.
+const netpolMaxBackends = 10
+
+type simulateNetworkPathInput struct {
+	SourceNamespace      string `json:"source_namespace" jsonschema:"required,Namespace of the source pod"`
+	SourcePod            string `json:"source_pod" jsonschema:"required,Source pod name"`
+	DestinationNamespace string `json:"destination_namespace,omitempty" jsonschema:"Namespace of the destination (default: source_namespace)"`
+	DestinationPod       string `json:"destination_pod,omitempty" jsonschema:"Destination pod name (set this or destination_service)"`
+	DestinationService   string `json:"destination_service,omitempty" jsonschema:"Destination Service name; its backend pods are evaluated (set this or destination_pod)"`
+	Port                 int32  `json:"port,omitempty" jsonschema:"Destination port: the Service port for a Service, or the container port for a pod (default: the first declared port)"`
+	Protocol             string `json:"protocol,omitempty" jsonschema:"TCP, UDP, or SCTP (default TCP)"`
+	MaxDiagramNodes      int    `json:"max_diagram_nodes,omitempty" jsonschema:"Maximum nodes in the decision diagram before the rest are collapsed into '+N more' nodes (default 80)"`
+}
+
+// netpolEndpoint is one side of a simulated connection.
+type netpolEndpoint struct {
+	Pod      *corev1.Pod
+	NSLabels map[string]string
+}
+
+// netpolVerdict is the outcome of NetworkPolicy evaluation for one direction:
+// egress from the source or ingress to the destination.
+type netpolVerdict struct {
+	Direction string
+	Isolating []string // policies that select the pod for this direction
+	AllowedBy []string // rules that allow the connection
+}
+
+// Allowed reports whether the direction permits the connection. A pod no
+// policy isolates for the direction allows all traffic.
+func (v netpolVerdict) Allowed() bool {
+	return len(v.Isolating) == 0 || len(v.AllowedBy) > 0
+}
+
+// netpolPath is the simulated decision for one destination pod.
+type netpolPath struct {
+	Destination *corev1.Pod
+	Port        int32
+	Egress      netpolVerdict
+	Ingress     netpolVerdict
+}
+
+// Allowed reports whether both directions permit the connection.
+func (p netpolPath) Allowed() bool {
+	return p.Egress.Allowed() && p.Ingress.Allowed()
+}
+
+func registerNetpolSimTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// simulate_network_path
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "simulate_network_path",
+		Description: "Answer 'can pod A talk to pod/Service B on port P?' by evaluating every NetworkPolicy that applies " +
+			"(pod and namespace selectors, IP blocks, ports, policyTypes) for egress from the source and ingress to the destination. " +
+			"Returns ALLOWED or DENIED with the exact policies and rules that decided it, plus a Mermaid decision diagram.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input simulateNetworkPathInput) (*mcp.CallToolResult, any, error) {
+		dstNS := input.DestinationNamespace
+		if dstNS == "" {
+			dstNS = input.SourceNamespace
+		}
+		if (input.DestinationPod == "") == (input.DestinationService == "") {
+			return util.ErrorResult("set exactly one of destination_pod or destination_service"), nil, nil
+		}
+		protocol := corev1.Protocol(strings.ToUpper(input.Protocol))
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+
+		srcPod, err := client.GetPod(ctx, input.SourceNamespace, input.SourcePod)
+		if err != nil {
+			return util.HandleK8sError(fmt.Sprintf("getting pod %s/%s", input.SourceNamespace, input.SourcePod), err), nil, nil
+		}
+
+		// Resolve the destination to pods and the port each one receives on
+		var dstPods []corev1.Pod
+		var target string
+		var svcPort *corev1.ServicePort
+		if input.DestinationService != "" {
+			svc, err := client.GetService(ctx, dstNS, input.DestinationService)
+			if err != nil {
+				return util.HandleK8sError(fmt.Sprintf("getting service %s/%s", dstNS, input.DestinationService), err), nil, nil
+			}
+			for i := range svc.Spec.Ports {
+				if input.Port == 0 || svc.Spec.Ports[i].Port == input.Port {
+					svcPort = &svc.Spec.Ports[i]
+					break
+				}
+			}
+			if svcPort == nil {
+				return util.ErrorResult("service %s/%s has no port %d (ports: %s)", dstNS, svc.Name, input.Port, formatServicePorts(svc)), nil, nil
+			}
+			if input.Protocol == "" && svcPort.Protocol != "" {
+				protocol = svcPort.Protocol
+			}
+			dstPods, err = client.GetPodsForService(ctx, svc)
+			if err != nil {
+				return util.HandleK8sError("listing service backends", err), nil, nil
+			}
+			if len(dstPods) == 0 {
+				return util.ErrorResult("service %s/%s has no backend pods to evaluate (no selector or no matching pods)", dstNS, svc.Name), nil, nil
+			}
+			if len(dstPods) > netpolMaxBackends {
+				dstPods = dstPods[:netpolMaxBackends]
+			}
+			target = fmt.Sprintf("service %s/%s port %d/%s", dstNS, svc.Name, svcPort.Port, protocol)
+		} else {
+			pod, err := client.GetPod(ctx, dstNS, input.DestinationPod)
+			if err != nil {
+				return util.HandleK8sError(fmt.Sprintf("getting pod %s/%s", dstNS, input.DestinationPod), err), nil, nil
+			}
+			dstPods = []corev1.Pod{*pod}
+			target = fmt.Sprintf("pod %s/%s", dstNS, pod.Name)
+		}
+
+		srcNSLabels := namespaceLabels(ctx, client, srcPod.Namespace)
+		dstNSLabels := srcNSLabels
+		if dstNS != srcPod.Namespace {
+			dstNSLabels = namespaceLabels(ctx, client, dstNS)
+		}
+		srcPolicies, err := client.ListNetworkPolicies(ctx, srcPod.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing network policies", err), nil, nil
+		}
+		dstPolicies := srcPolicies
+		if dstNS != srcPod.Namespace {
+			dstPolicies, err = client.ListNetworkPolicies(ctx, dstNS, metav1.ListOptions{})
+			if err != nil {
+				return util.HandleK8sError("listing network policies", err), nil, nil
+			}
+		}
+
+		src := netpolEndpoint{Pod: srcPod, NSLabels: srcNSLabels}
+		var paths []netpolPath
+		for i := range dstPods {
+			dst := netpolEndpoint{Pod: &dstPods[i], NSLabels: dstNSLabels}
+			port := input.Port
+			if svcPort != nil {
+				port = serviceTargetPort(svcPort, dst.Pod)
+			} else if port == 0 {
+				port = firstContainerPort(dst.Pod, protocol)
+			}
+			if port == 0 {
+				return util.ErrorResult("could not determine the destination port on pod %s; set port explicitly", dst.Pod.Name), nil, nil
+			}
+			paths = append(paths, simulateNetpolPath(src, dst, port, protocol, srcPolicies, dstPolicies))
+		}
+
+		// Report the first denied path in detail, or the first path if all are allowed
+		allowed := 0
+		detail := paths[0]
+		for _, p := range paths {
+			if p.Allowed() {
+				allowed++
+			} else if detail.Allowed() {
+				detail = p
+			}
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Network Path Simulation: %s/%s -> %s", srcPod.Namespace, srcPod.Name, target)))
+		sb.WriteString("\n\n")
+		verdict := "ALLOWED"
+		switch {
+		case allowed == 0:
+			verdict = "DENIED"
+		case allowed < len(paths):
+			verdict = fmt.Sprintf("PARTIAL (%d of %d backends allowed)", allowed, len(paths))
+		}
+		sb.WriteString(util.FormatKeyValue("VERDICT", verdict))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Source", fmt.Sprintf("%s/%s (%s) labels: %s", srcPod.Namespace, srcPod.Name, podIPOrUnknown(srcPod), util.FormatLabels(srcPod.Labels))))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Destination", fmt.Sprintf("%s/%s (%s) port %d/%s labels: %s",
+			detail.Destination.Namespace, detail.Destination.Name, podIPOrUnknown(detail.Destination), detail.Port, protocol, util.FormatLabels(detail.Destination.Labels))))
+		sb.WriteString("\n")
+
+		if len(paths) > 1 {
+			sb.WriteString("\n")
+			headers := []string{"BACKEND", "PORT", "EGRESS", "INGRESS", "RESULT"}
+			rows := make([][]string, 0, len(paths))
+			for _, p := range paths {
+				rows = append(rows, []string{p.Destination.Name, fmt.Sprintf("%d", p.Port), allowedWord(p.Egress.Allowed()), allowedWord(p.Ingress.Allowed()), allowedWord(p.Allowed())})
+			}
+			sb.WriteString(util.FormatTable(headers, rows))
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		writeNetpolVerdict(&sb, detail.Egress, srcPod)
+		writeNetpolVerdict(&sb, detail.Ingress, detail.Destination)
+		sb.WriteString(findings.Format("KD-NET-003", "INFO", "NetworkPolicies are only enforced when the cluster's CNI plugin supports them"))
+		sb.WriteString("\n")
+
+		sb.WriteString("\nDECISION DIAGRAM:\n")
+		sb.WriteString(netpolDecisionDiagram(srcPod, detail, input.MaxDiagramNodes))
+		sb.WriteString("\n")
+
+		var actions []string
+		if !detail.Egress.Allowed() {
+			actions = append(actions, fmt.Sprintf("Add an egress rule to one of [%s] in namespace %s allowing pods %s in namespace %s on port %d/%s",
+				strings.Join(detail.Egress.Isolating, ", "), srcPod.Namespace, util.FormatLabels(detail.Destination.Labels), detail.Destination.Namespace, detail.Port, protocol))
+		}
+		if !detail.Ingress.Allowed() {
+			actions = append(actions, fmt.Sprintf("Add an ingress rule to one of [%s] in namespace %s allowing pods %s from namespace %s on port %d/%s",
+				strings.Join(detail.Ingress.Isolating, ", "), detail.Destination.Namespace, util.FormatLabels(srcPod.Labels), srcPod.Namespace, detail.Port, protocol))
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range actions {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// simulateNetpolPath evaluates egress policies in the source namespace and
+// ingress policies in the destination namespace for one connection.
+func simulateNetpolPath(src, dst netpolEndpoint, port int32, protocol corev1.Protocol, srcPolicies, dstPolicies []networkingv1.NetworkPolicy) netpolPath {
+	return netpolPath{
+		Destination: dst.Pod,
+		Port:        port,
+		Egress:      evaluateNetpolDirection(srcPolicies, src, dst, dst.Pod, port, protocol, true),
+		Ingress:     evaluateNetpolDirection(dstPolicies, dst, src, dst.Pod, port, protocol, false),
+	}
+}
+
+// evaluateNetpolDirection finds the policies that isolate self for the
+// direction and the rules among them that allow traffic with peer. target
+// is the destination pod, against which named ports are resolved.
+func evaluateNetpolDirection(policies []networkingv1.NetworkPolicy, self, peer netpolEndpoint, target *corev1.Pod, port int32, protocol corev1.Protocol, egress bool) netpolVerdict {
+	v := netpolVerdict{Direction: "Ingress"}
+	if egress {
+		v.Direction = "Egress"
+	}
+	for i := range policies {
+		np := &policies[i]
+		ingressType, egressType := effectivePolicyTypes(np)
+		if (egress && !egressType) || (!egress && !ingressType) {
+			continue
+		}
+		if !labelSelectorMatches(&np.Spec.PodSelector, self.Pod.Labels) {
+			continue
+		}
+		v.Isolating = append(v.Isolating, np.Name)
+		if egress {
+			for r, rule := range np.Spec.Egress {
+				if netpolPeersMatch(rule.To, np.Namespace, peer) && netpolPortsMatch(rule.Ports, target, port, protocol) {
+					v.AllowedBy = append(v.AllowedBy, fmt.Sprintf("policy '%s' egress rule #%d (%s)", np.Name, r+1, strings.Join(describeEgressRule(rule), "; ")))
+				}
+			}
+			continue
+		}
+		for r, rule := range np.Spec.Ingress {
+			if netpolPeersMatch(rule.From, np.Namespace, peer) && netpolPortsMatch(rule.Ports, target, port, protocol) {
+				v.AllowedBy = append(v.AllowedBy, fmt.Sprintf("policy '%s' ingress rule #%d (%s)", np.Name, r+1, strings.Join(describeIngressRule(rule), "; ")))
+			}
+		}
+	}
+	return v
+}
+
+// effectivePolicyTypes applies the API defaults for policyTypes: Ingress
+// always, and Egress when the policy has egress rules.
+func effectivePolicyTypes(np *networkingv1.NetworkPolicy) (ingress, egress bool) {
+	if len(np.Spec.PolicyTypes) == 0 {
+		return true, len(np.Spec.Egress) > 0
+	}
+	for _, pt := range np.Spec.PolicyTypes {
+		switch pt {
+		case networkingv1.PolicyTypeIngress:
+			ingress = true
+		case networkingv1.PolicyTypeEgress:
+			egress = true
+		}
+	}
+	return ingress, egress
+}
+
+// labelSelectorMatches reports whether a label selector matches a label set.
+// An invalid selector matches nothing.
+func labelSelectorMatches(sel *metav1.LabelSelector, set map[string]string) bool {
+	selector, err := metav1.LabelSelectorAsSelector(sel)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(set))
+}
+
+// netpolPeersMatch reports whether any peer of a rule matches the endpoint.
+// A rule without peers matches everything.
+func netpolPeersMatch(peers []networkingv1.NetworkPolicyPeer, policyNamespace string, peer netpolEndpoint) bool {
+	if len(peers) == 0 {
+		return true
+	}
+	for _, p := range peers {
+		if p.IPBlock != nil {
+			if ipBlockContains(p.IPBlock, peer.Pod.Status.PodIP) {
+				return true
+			}
+			continue
+		}
+		nsMatch := peer.Pod.Namespace == policyNamespace
+		if p.NamespaceSelector != nil {
+			nsMatch = labelSelectorMatches(p.NamespaceSelector, peer.NSLabels)
+		}
+		podMatch := p.PodSelector == nil || labelSelectorMatches(p.PodSelector, peer.Pod.Labels)
+		if nsMatch && podMatch {
+			return true
+		}
+	}
+	return false
+}
+
+// ipBlockContains reports whether ip is inside the block's CIDR and outside
+// all of its exceptions.
+func ipBlockContains(block *networkingv1.IPBlock, ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	_, cidr, err := net.ParseCIDR(block.CIDR)
+	if err != nil || !cidr.Contains(addr) {
+		return false
+	}
+	for _, except := range block.Except {
+		if _, ex, err := net.ParseCIDR(except); err == nil && ex.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// netpolPortsMatch reports whether a rule's ports admit port/protocol on the
+// target pod. A rule without ports matches every port.
+func netpolPortsMatch(ports []networkingv1.NetworkPolicyPort, target *corev1.Pod, port int32, protocol corev1.Protocol) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, p := range ports {
+		proto := corev1.ProtocolTCP
+		if p.Protocol != nil {
+			proto = *p.Protocol
+		}
+		if proto != protocol {
+			continue
+		}
+		if p.Port == nil {
+			return true
+		}
+		if p.Port.Type == intstr.String {
+			if containerPortByName(target, p.Port.StrVal, protocol) == port {
+				return true
+			}
+			continue
+		}
+		end := p.Port.IntVal
+		if p.EndPort != nil {
+			end = *p.EndPort
+		}
+		if port >= p.Port.IntVal && port <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// containerPortByName resolves a named container port on a pod, or returns 0.
+func containerPortByName(pod *corev1.Pod, name string, protocol corev1.Protocol) int32 {
+	for _, c := range pod.Spec.Containers {
+		for _, cp := range c.Ports {
+			proto := cp.Protocol
+			if proto == "" {
+				proto = corev1.ProtocolTCP
+			}
+			if cp.Name == name && proto == protocol {
+				return cp.ContainerPort
+			}
+		}
+	}
+	return 0
+}
+
+// firstContainerPort returns the first declared container port with the protocol, or 0.
+func firstContainerPort(pod *corev1.Pod, protocol corev1.Protocol) int32 {
+	for _, c := range pod.Spec.Containers {
+		for _, cp := range c.Ports {
+			if cp.Protocol == protocol || (cp.Protocol == "" && protocol == corev1.ProtocolTCP) {
+				return cp.ContainerPort
+			}
+		}
+	}
+	return 0
+}
+
+// serviceTargetPort resolves the port a Service port forwards to on a backend pod.
+func serviceTargetPort(sp *corev1.ServicePort, pod *corev1.Pod) int32 {
+	switch {
+	case sp.TargetPort.Type == intstr.String && sp.TargetPort.StrVal != "":
+		protocol := sp.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		return containerPortByName(pod, sp.TargetPort.StrVal, protocol)
+	case sp.TargetPort.IntVal != 0:
+		return sp.TargetPort.IntVal
+	}
+	return sp.Port
+}
+
+// namespaceLabels returns a namespace's labels, including the
+// kubernetes.io/metadata.name label the API server sets automatically.
+func namespaceLabels(ctx context.Context, client *k8s.ClusterClient, name string) map[string]string {
+	out := map[string]string{"kubernetes.io/metadata.name": name}
+	if ns, err := client.GetNamespace(ctx, name); err == nil {
+		for k, v := range ns.Labels {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// writeNetpolVerdict writes the findings for one direction of a simulated path.
+func writeNetpolVerdict(sb *strings.Builder, v netpolVerdict, pod *corev1.Pod) {
+	switch {
+	case len(v.Isolating) == 0:
+		sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("%s: no NetworkPolicy selects %s/%s for %s - allowed by default",
+			v.Direction, pod.Namespace, pod.Name, strings.ToLower(v.Direction))))
+		sb.WriteString("\n")
+	case len(v.AllowedBy) > 0:
+		sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("%s: allowed; %s/%s is isolated by [%s]",
+			v.Direction, pod.Namespace, pod.Name, strings.Join(v.Isolating, ", "))))
+		sb.WriteString("\n")
+		for _, r := range v.AllowedBy {
+			sb.WriteString(fmt.Sprintf("  - allowed by %s\n", r))
+		}
+	default:
+		sb.WriteString(findings.Format("KD-NET-003", "CRITICAL", fmt.Sprintf("%s: DENIED; %s/%s is isolated by [%s] and no rule in them matches this peer and port",
+			v.Direction, pod.Namespace, pod.Name, strings.Join(v.Isolating, ", "))))
+		sb.WriteString("\n")
+	}
+}
+
+// netpolDecisionDiagram renders the source, both policy decisions, and the
+// destination as a Mermaid flowchart.
+func netpolDecisionDiagram(src *corev1.Pod, p netpolPath, maxNodes int) string {
+	fc := newBudgetedFlowchart(mermaid.DirectionLR, maxNodes)
+	fc.AddNode("src", fmt.Sprintf("Pod: %s%s%s", src.Name, mermaid.BR(), src.Namespace), mermaid.ShapeRound)
+	fc.AddNode("egress", netpolDecisionLabel(p.Egress), mermaid.ShapeDiamond)
+	fc.AddNode("ingress", netpolDecisionLabel(p.Ingress), mermaid.ShapeDiamond)
+	fc.AddNode("dst", fmt.Sprintf("Pod: %s%s%s:%d", p.Destination.Name, mermaid.BR(), p.Destination.Namespace, p.Port), mermaid.ShapeRound)
+
+	fc.AddEdge("src", "egress", "", mermaid.EdgeSolid)
+	if p.Egress.Allowed() {
+		fc.AddEdge("egress", "ingress", "allowed", mermaid.EdgeSolid)
+		fc.AddStyle("egress", mermaid.SeverityHealthy)
+	} else {
+		fc.AddEdge("egress", "ingress", "denied", mermaid.EdgeDotted)
+		fc.AddStyle("egress", mermaid.SeverityCritical)
+	}
+	if p.Ingress.Allowed() {
+		fc.AddEdge("ingress", "dst", "allowed", mermaid.EdgeSolid)
+		fc.AddStyle("ingress", mermaid.SeverityHealthy)
+	} else {
+		fc.AddEdge("ingress", "dst", "denied", mermaid.EdgeDotted)
+		fc.AddStyle("ingress", mermaid.SeverityCritical)
+	}
+	if p.Allowed() {
+		fc.AddStyle("dst", mermaid.SeverityHealthy)
+	} else {
+		fc.AddStyle("dst", mermaid.SeverityCritical)
+	}
+	return fc.RenderBlock()
+}
+
+// netpolDecisionLabel summarizes one direction's decision for a diagram node.
+func netpolDecisionLabel(v netpolVerdict) string {
+	if len(v.Isolating) == 0 {
+		return fmt.Sprintf("%s: no policies", v.Direction)
+	}
+	return fmt.Sprintf("%s: %s", v.Direction, strings.Join(v.Isolating, ", "))
+}
+
+// allowedWord renders a decision for tables.
+func allowedWord(allowed bool) string {
+	if allowed {
+		return "ALLOWED"
+	}
+	return "DENIED"
+}
+
+// podIPOrUnknown returns the pod IP or a placeholder before one is assigned.
+func podIPOrUnknown(p *corev1.Pod) string {
+	if p.Status.PodIP == "" {
+		return "no IP"
+	}
+	return p.Status.PodIP
+}
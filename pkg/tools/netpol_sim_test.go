@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestSimulateNetpolPath(t *testing.T) {
+	web := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: map[string]string{"app": "web"}},
+		Status:     corev1.PodStatus{PodIP: "10.0.0.5"},
+	}
+	db := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "data", Labels: map[string]string{"app": "db"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Ports: []corev1.ContainerPort{{Name: "pg", ContainerPort: 5432}},
+		}}},
+	}
+	src := netpolEndpoint{Pod: web, NSLabels: map[string]string{"kubernetes.io/metadata.name": "shop", "team": "shop"}}
+	dst := netpolEndpoint{Pod: db, NSLabels: map[string]string{"kubernetes.io/metadata.name": "data"}}
+
+	tcp := corev1.ProtocolTCP
+	pg := intstr.FromString("pg")
+	dbIngress := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-from-shop", Namespace: "data"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "shop"}},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				}},
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &pg}},
+			}},
+		},
+	}
+	denyEgress := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-deny-egress", Namespace: "shop"},
+		Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}},
+	}
+
+	// Named port resolves to 5432 on the destination, namespace and pod selectors both match
+	p := simulateNetpolPath(src, dst, 5432, tcp, nil, []networkingv1.NetworkPolicy{dbIngress})
+	if !p.Allowed() || len(p.Ingress.AllowedBy) != 1 {
+		t.Errorf("expected ingress allowed by db-from-shop, got %+v", p)
+	}
+
+	// Wrong port is denied by the isolating ingress policy
+	p = simulateNetpolPath(src, dst, 80, tcp, nil, []networkingv1.NetworkPolicy{dbIngress})
+	if p.Ingress.Allowed() {
+		t.Errorf("expected ingress on port 80 to be denied, got %+v", p.Ingress)
+	}
+
+	// Default-deny egress in the source namespace blocks the path
+	p = simulateNetpolPath(src, dst, 5432, tcp, []networkingv1.NetworkPolicy{denyEgress}, []networkingv1.NetworkPolicy{dbIngress})
+	if p.Allowed() || p.Egress.Allowed() || len(p.Egress.Isolating) != 1 {
+		t.Errorf("expected egress denied by default-deny-egress, got %+v", p.Egress)
+	}
+}
+
+func TestIPBlockContains(t *testing.T) {
+	block := &networkingv1.IPBlock{CIDR: "10.0.0.0/16", Except: []string{"10.0.1.0/24"}}
+	if !ipBlockContains(block, "10.0.0.5") {
+		t.Error("expected 10.0.0.5 to be inside the block")
+	}
+	if ipBlockContains(block, "10.0.1.5") {
+		t.Error("expected 10.0.1.5 to be excluded")
+	}
+	if ipBlockContains(block, "") {
+		t.Error("expected a pod without an IP to never match")
+	}
+}
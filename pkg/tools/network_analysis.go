@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	corev1 "k8s.io/api/core/v1"
@@ -11,6 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/mermaid"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
@@ -19,7 +21,9 @@ import (
 // --- Input structs ---
 
 type mapServiceTopologyInput struct {
-	Namespace string `json:"namespace" jsonschema:"required,Kubernetes namespace to map (use a specific namespace, not 'all')"`
+	Namespace       string `json:"namespace" jsonschema:"required,Kubernetes namespace to map (use a specific namespace, not 'all')"`
+	MaxDiagramNodes int    `json:"max_diagram_nodes,omitempty" jsonschema:"Maximum nodes in the topology diagram before the rest are collapsed into '+N more' nodes (default 80)"`
+	TimeoutSeconds  int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
 }
 
 type traceIngressToBackendInput struct {
@@ -28,21 +32,27 @@ type traceIngressToBackendInput struct {
 }
 
 type listEndpointHealthInput struct {
-	Namespace string `json:"namespace" jsonschema:"required,Kubernetes namespace to check endpoint health"`
+	Namespace     string `json:"namespace" jsonschema:"required,Kubernetes namespace to check endpoint health"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum services checked per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type analyzeServiceConnectivityInput struct {
-	Namespace   string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
-	ServiceName string `json:"service_name" jsonschema:"required,Service name to analyze"`
+	Namespace       string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	ServiceName     string `json:"service_name" jsonschema:"required,Service name to analyze"`
+	Active          bool   `json:"active,omitempty" jsonschema:"Also exec a curl/wget/nc check from a pod to the service ClusterIP (requires the server to run with --enable-exec)"`
+	SourcePod       string `json:"source_pod,omitempty" jsonschema:"Pod in the same namespace to probe from in active mode (default: first running non-backend pod)"`
+	MaxDiagramNodes int    `json:"max_diagram_nodes,omitempty" jsonschema:"Maximum nodes in the connectivity diagram before the rest are collapsed into '+N more' nodes (default 80)"`
 }
 
 type analyzeAllIngressesInput struct {
-	Namespace string `json:"namespace" jsonschema:"required,Kubernetes namespace to audit ingresses"`
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace to audit ingresses"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
 }
 
 type checkAGICHealthInput struct{}
 
-func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient) {
+func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient, opts Options) {
 
 	// =========================================================================
 	// 1. map_service_topology
@@ -51,6 +61,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		Name:        "map_service_topology",
 		Description: "Map the full network topology for a namespace: services, their backing pods, ingresses exposing them, and inferred inter-service dependencies from pod environment variables. Produces structured text plus a Mermaid flowchart showing Internet -> Ingresses -> Services -> Pods with dependency edges.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input mapServiceTopologyInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
 		ns := input.Namespace
 		if ns == "" || ns == "all" || ns == "*" {
 			return util.ErrorResult("map_service_topology requires a specific namespace, not 'all'"), nil, nil
@@ -128,7 +139,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		ingresses, err := client.ListIngresses(ctx, ns, metav1.ListOptions{})
 		if err != nil {
 			// Non-fatal: just note the error
-			sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatFinding("WARNING", fmt.Sprintf("Could not list ingresses: %v", err))))
+			sb.WriteString(fmt.Sprintf("\n%s\n", findings.Format("KD-SYS-008", "WARNING", fmt.Sprintf("Could not list ingresses: %v", err))))
 		}
 
 		if len(ingresses) > 0 {
@@ -172,32 +183,32 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		// --- Findings ---
 		sb.WriteString("\n")
 		sb.WriteString("FINDINGS:\n")
-		findings := 0
+		findingCount := 0
 		for _, info := range svcMap {
 			if info.HealthOK && info.Health.TotalEndpoints == 0 {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Service '%s' has 0 endpoints — no pods match its selector", info.Service.Name)))
+				sb.WriteString(findings.Format("KD-SVC-001", "CRITICAL", fmt.Sprintf("Service '%s' has 0 endpoints — no pods match its selector", info.Service.Name)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			} else if info.HealthOK && info.Health.NotReadyCount > 0 {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Service '%s' has %d not-ready endpoints out of %d total", info.Service.Name, info.Health.NotReadyCount, info.Health.TotalEndpoints)))
+				sb.WriteString(findings.Format("KD-SVC-002", "WARNING", fmt.Sprintf("Service '%s' has %d not-ready endpoints out of %d total", info.Service.Name, info.Health.NotReadyCount, info.Health.TotalEndpoints)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			}
 			if info.Service.Spec.Selector == nil || len(info.Service.Spec.Selector) == 0 {
 				if info.Service.Spec.Type != corev1.ServiceTypeExternalName {
-					sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("Service '%s' has no selector (headless/external)", info.Service.Name)))
+					sb.WriteString(findings.Format("KD-SVC-001", "INFO", fmt.Sprintf("Service '%s' has no selector (headless/external)", info.Service.Name)))
 					sb.WriteString("\n")
-					findings++
+					findingCount++
 				}
 			}
 		}
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  No issues found.\n")
 		}
 
 		// --- Mermaid Flowchart ---
 		sb.WriteString("\nTOPOLOGY DIAGRAM:\n")
-		fc := mermaid.NewFlowchart(mermaid.DirectionTB)
+		fc := newBudgetedFlowchart(mermaid.DirectionTB, input.MaxDiagramNodes)
 
 		// Internet node
 		hasIngress := len(ingresses) > 0
@@ -209,6 +220,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		// Ingress subgraph
 		if hasIngress {
 			fc.AddSubgraph("ingresses", "Ingresses", func(sg *mermaid.Subgraph) {
+				sg.SetOverflowNoun("ingresses")
 				for _, ing := range ingresses {
 					nodeID := mermaid.SafeID("ing_" + ing.Name)
 					hosts, _, _ := extractIngressDetails(&ing)
@@ -225,6 +237,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 
 		// Services subgraph
 		fc.AddSubgraph("svc_sub", fmt.Sprintf("Services (%s)", ns), func(sg *mermaid.Subgraph) {
+			sg.SetOverflowNoun("services")
 			for _, info := range svcMap {
 				svcNodeID := mermaid.SafeID("svc_" + info.Service.Name)
 				label := info.Service.Name + mermaid.BR() + string(info.Service.Spec.Type)
@@ -261,36 +274,34 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 			}
 		}
 
-		// Pods subgraph
+		// Pods subgraph — large workloads collapse healthy replicas into one node
+		podNodes := make(map[string][]diagramPodNode, len(svcMap))
+		for name, info := range svcMap {
+			podNodes[name] = summarizePodsForDiagram(info.Pods, func(p *corev1.Pod) string {
+				return p.Name + mermaid.BR() + podPhaseReason(p)
+			})
+		}
 		fc.AddSubgraph("pods_sub", "Pods", func(sg *mermaid.Subgraph) {
-			for _, info := range svcMap {
-				for _, pod := range info.Pods {
-					podNodeID := mermaid.SafeID("pod_" + pod.Name)
-					status := podPhaseReason(&pod)
-					label := pod.Name + mermaid.BR() + status
-					sg.AddNode(podNodeID, label, mermaid.ShapeRect)
+			sg.SetOverflowNoun("pods")
+			for _, nodes := range podNodes {
+				for _, n := range nodes {
+					sg.AddNode(n.ID, n.Label, mermaid.ShapeRect)
 				}
 			}
 		})
 
 		// Style pods based on health
-		for _, info := range svcMap {
-			for i := range info.Pods {
-				podNodeID := mermaid.SafeID("pod_" + info.Pods[i].Name)
-				if isPodHealthy(&info.Pods[i]) {
-					fc.AddStyle(podNodeID, mermaid.SeverityHealthy)
-				} else {
-					fc.AddStyle(podNodeID, mermaid.SeverityCritical)
-				}
+		for _, nodes := range podNodes {
+			for _, n := range nodes {
+				styleDiagramPodNode(fc, n)
 			}
 		}
 
 		// Service -> Pod edges
-		for _, info := range svcMap {
-			svcNodeID := mermaid.SafeID("svc_" + info.Service.Name)
-			for _, pod := range info.Pods {
-				podNodeID := mermaid.SafeID("pod_" + pod.Name)
-				fc.AddEdge(svcNodeID, podNodeID, "", mermaid.EdgeSolid)
+		for name, nodes := range podNodes {
+			svcNodeID := mermaid.SafeID("svc_" + name)
+			for _, n := range nodes {
+				fc.AddEdge(svcNodeID, n.ID, "", mermaid.EdgeSolid)
 			}
 		}
 
@@ -329,15 +340,15 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Ingress-to-Backend Trace: %s%s", hostname, path)))
 		sb.WriteString("\n\n")
 
-		findings := 0
+		findingCount := 0
 
 		// --- [1] INGRESS layer ---
 		sb.WriteString(util.FormatSubHeader("[1] INGRESS"))
 		sb.WriteString("\n")
 
-		ing, matchedRule, matchedPath, err := client.FindIngressForHostPath(ctx, "", hostname, path)
+		match, err := client.FindIngressForHostPath(ctx, "", hostname, path)
 		if err != nil {
-			sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("No ingress found for %s%s: %v", hostname, path, err)))
+			sb.WriteString(findings.Format("KD-ING-003", "CRITICAL", fmt.Sprintf("No ingress found for %s%s: %v", hostname, path, err)))
 			sb.WriteString("\n")
 			sb.WriteString("\nSUGGESTED ACTIONS:\n")
 			sb.WriteString("1. Verify the hostname and path are correct\n")
@@ -346,11 +357,12 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 			return util.SuccessResult(sb.String()), nil, nil
 		}
 
+		ing, matchedPath := match.Ingress, match.Path
 		sb.WriteString(util.FormatKeyValue("Ingress", fmt.Sprintf("%s/%s", ing.Namespace, ing.Name)))
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatKeyValue("Ingress Class", ingressClassName(ing)))
 		sb.WriteString("\n")
-		sb.WriteString(util.FormatKeyValue("Matched Host", matchedRule.Host))
+		sb.WriteString(util.FormatKeyValue("Matched Host", match.Host()))
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatKeyValue("Matched Path", matchedPath.Path))
 		sb.WriteString("\n")
@@ -358,12 +370,18 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 			sb.WriteString(util.FormatKeyValue("Path Type", string(*matchedPath.PathType)))
 			sb.WriteString("\n")
 		}
+		sb.WriteString(util.FormatKeyValue("Matched By", match.Explain()))
+		sb.WriteString("\n")
+		if match.Precedence == k8s.PrecedenceDefaultBackend {
+			sb.WriteString(findings.Format("KD-ING-003", "INFO", fmt.Sprintf("No rule matches %s%s; the request falls through to the defaultBackend of %s/%s", hostname, path, ing.Namespace, ing.Name)))
+			sb.WriteString("\n")
+		}
 
 		// TLS
 		hasTLS := false
 		for _, tls := range ing.Spec.TLS {
 			for _, h := range tls.Hosts {
-				if h == hostname {
+				if k8s.HostMatches(h, hostname) {
 					hasTLS = true
 					sb.WriteString(util.FormatKeyValue("TLS Secret", tls.SecretName))
 					sb.WriteString("\n")
@@ -375,7 +393,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 			}
 		}
 		if !hasTLS {
-			sb.WriteString(util.FormatFinding("INFO", "No TLS configured for this host"))
+			sb.WriteString(findings.Format("KD-ING-004", "INFO", "No TLS configured for this host"))
 			sb.WriteString("\n")
 		}
 
@@ -384,7 +402,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		if len(agicAnnotations) > 0 {
 			sb.WriteString("\n  AGIC Annotations:\n")
 			for _, a := range agicAnnotations {
-				sb.WriteString(fmt.Sprintf("    %s: %s\n", a.Key, a.Value))
+				sb.WriteString(fmt.Sprintf("    %s: %s\n", a.Key, util.RedactAnnotation(a.Key, a.Value)))
 			}
 		}
 
@@ -394,9 +412,9 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		sb.WriteString("\n")
 
 		if matchedPath.Backend.Service == nil {
-			sb.WriteString(util.FormatFinding("CRITICAL", "Ingress path has no service backend configured"))
+			sb.WriteString(findings.Format("KD-ING-001", "CRITICAL", "Ingress path has no service backend configured"))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 			return util.SuccessResult(sb.String()), nil, nil
 		}
 
@@ -415,9 +433,9 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 
 		svc, svcErr := client.GetService(ctx, ing.Namespace, backendSvcName)
 		if svcErr != nil {
-			sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Backend service '%s' not found: %v", backendSvcName, svcErr)))
+			sb.WriteString(findings.Format("KD-ING-001", "CRITICAL", fmt.Sprintf("Backend service '%s' not found: %v", backendSvcName, svcErr)))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		} else {
 			sb.WriteString(util.FormatKeyValue("Service Type", string(svc.Spec.Type)))
 			sb.WriteString("\n")
@@ -437,9 +455,9 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 				}
 			}
 			if !portValid && backendPort != "" {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Backend port '%s' does not match any service port", backendPort)))
+				sb.WriteString(findings.Format("KD-ING-002", "WARNING", fmt.Sprintf("Backend port '%s' does not match any service port", backendPort)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			}
 		}
 
@@ -450,9 +468,9 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 
 		health, healthErr := client.GetServiceEndpointHealth(ctx, ing.Namespace, backendSvcName)
 		if healthErr != nil {
-			sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Could not get endpoint health: %v", healthErr)))
+			sb.WriteString(findings.Format("KD-SYS-008", "WARNING", fmt.Sprintf("Could not get endpoint health: %v", healthErr)))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		} else {
 			sb.WriteString(util.FormatKeyValue("Total Endpoints", fmt.Sprintf("%d", health.TotalEndpoints)))
 			sb.WriteString("\n")
@@ -462,13 +480,13 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 			sb.WriteString("\n")
 
 			if health.TotalEndpoints == 0 {
-				sb.WriteString(util.FormatFinding("CRITICAL", "Service has 0 endpoints — requests will fail with 502/503"))
+				sb.WriteString(findings.Format("KD-SVC-001", "CRITICAL", "Service has 0 endpoints — requests will fail with 502/503"))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			} else if health.NotReadyCount > 0 {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("%d endpoints are not ready — partial availability", health.NotReadyCount)))
+				sb.WriteString(findings.Format("KD-SVC-002", "WARNING", fmt.Sprintf("%d endpoints are not ready — partial availability", health.NotReadyCount)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			}
 
 			// Detail ready endpoints
@@ -517,14 +535,14 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 						util.FormatAge(p.CreationTimestamp.Time),
 					})
 					if !isPodHealthy(p) {
-						sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("  Backend pod '%s' is unhealthy: %s", p.Name, podPhaseReason(p))))
+						sb.WriteString(findings.Format("KD-POD-010", "WARNING", fmt.Sprintf("  Backend pod '%s' is unhealthy: %s", p.Name, podPhaseReason(p))))
 						sb.WriteString("\n")
-						findings++
+						findingCount++
 					}
 					if restarts > util.HighRestartThreshold {
-						sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("  Backend pod '%s' has high restart count: %d", p.Name, restarts)))
+						sb.WriteString(findings.Format("KD-POD-005", "WARNING", fmt.Sprintf("  Backend pod '%s' has high restart count: %d", p.Name, restarts)))
 						sb.WriteString("\n")
-						findings++
+						findingCount++
 					}
 				}
 				sb.WriteString(util.FormatTable(podHeaders, podRows))
@@ -553,10 +571,10 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Assessment"))
 		sb.WriteString("\n")
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  Trace looks healthy. Requests to " + hostname + path + " should be routed correctly.\n")
 		} else {
-			sb.WriteString(fmt.Sprintf("  %d issue(s) found along the request path. Review findings above.\n", findings))
+			sb.WriteString(fmt.Sprintf("  %d issue(s) found along the request path. Review findings above.\n", findingCount))
 		}
 
 		// --- Mermaid Sequence Diagram ---
@@ -618,10 +636,11 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 			return util.ErrorResult("namespace is required"), nil, nil
 		}
 
-		services, err := client.ListServices(ctx, ns, metav1.ListOptions{})
+		page, err := client.ListServicesPage(ctx, ns, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing services", err), nil, nil
 		}
+		services := page.Items
 
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Endpoint Health Report (namespace: %s)", ns)))
@@ -638,7 +657,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		deadServices := 0
 		degradedServices := 0
 		healthyServices := 0
-		findings := 0
+		findingCount := 0
 
 		for _, svc := range services {
 			health, healthErr := client.GetServiceEndpointHealth(ctx, ns, svc.Name)
@@ -690,22 +709,23 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		for _, row := range rows {
 			switch row[5] {
 			case "DEAD":
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Service '%s' has 0 ready endpoints — all traffic will fail", row[0])))
+				sb.WriteString(findings.Format("KD-SVC-001", "CRITICAL", fmt.Sprintf("Service '%s' has 0 ready endpoints — all traffic will fail", row[0])))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			case "DEGRADED":
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Service '%s' has not-ready endpoints (%s/%s ready) — partial availability", row[0], row[3], row[2])))
+				sb.WriteString(findings.Format("KD-SVC-002", "WARNING", fmt.Sprintf("Service '%s' has not-ready endpoints (%s/%s ready) — partial availability", row[0], row[3], row[2])))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			case "ERROR":
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Could not check endpoint health for service '%s'", row[0])))
+				sb.WriteString(findings.Format("KD-SYS-008", "WARNING", fmt.Sprintf("Could not check endpoint health for service '%s'", row[0])))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			}
 		}
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  All services have healthy endpoints.\n")
 		}
+		sb.WriteString(pageFooter("list_endpoint_health", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -715,7 +735,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 	// =========================================================================
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "analyze_service_connectivity",
-		Description: "Run a comprehensive connectivity analysis for a specific service. Checks: service exists, selector matches pods, endpoints are ready, port mappings are valid, NetworkPolicies that affect it, and Ingress exposure. Produces a full connectivity report with a Mermaid flowchart. Use this to debug why a service is unreachable.",
+		Description: "Run a comprehensive connectivity analysis for a specific service. Checks: service exists, selector matches pods, endpoints are ready, port mappings are valid, NetworkPolicies that affect it, and Ingress exposure. Produces a full connectivity report with a Mermaid flowchart. Set active=true to also verify L4/L7 reachability and latency by exec'ing curl/wget/nc from a pod (requires --enable-exec). Use this to debug why a service is unreachable.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeServiceConnectivityInput) (*mcp.CallToolResult, any, error) {
 		ns := input.Namespace
 		svcName := input.ServiceName
@@ -727,14 +747,14 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Service Connectivity Analysis: %s (namespace: %s)", svcName, ns)))
 		sb.WriteString("\n\n")
 
-		findings := 0
+		findingCount := 0
 
 		// --- Check 1: Service exists ---
 		sb.WriteString(util.FormatSubHeader("Check 1: Service Exists"))
 		sb.WriteString("\n")
 		svc, err := client.GetService(ctx, ns, svcName)
 		if err != nil {
-			sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Service '%s/%s' not found: %v", ns, svcName, err)))
+			sb.WriteString(findings.Format("KD-SYS-008", "CRITICAL", fmt.Sprintf("Service '%s/%s' not found: %v", ns, svcName, err)))
 			sb.WriteString("\n")
 			sb.WriteString("\nSUGGESTED ACTIONS:\n")
 			sb.WriteString("1. Verify the service name and namespace\n")
@@ -750,7 +770,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatKeyValue("Ports", formatServicePorts(svc)))
 		sb.WriteString("\n")
-		sb.WriteString(util.FormatFinding("INFO", "Service exists"))
+		sb.WriteString(util.FormatFinding("OK", "Service exists"))
 		sb.WriteString("\n")
 
 		// --- Check 2: Selector matches pods ---
@@ -760,20 +780,20 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 
 		var matchedPods []corev1.Pod
 		if svc.Spec.Selector == nil || len(svc.Spec.Selector) == 0 {
-			sb.WriteString(util.FormatFinding("INFO", "Service has no selector (headless/ExternalName)"))
+			sb.WriteString(findings.Format("KD-SVC-001", "INFO", "Service has no selector (headless/ExternalName)"))
 			sb.WriteString("\n")
 		} else {
 			pods, podErr := client.GetPodsForService(ctx, svc)
 			if podErr != nil {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Could not list pods matching selector: %v", podErr)))
+				sb.WriteString(findings.Format("KD-SYS-008", "WARNING", fmt.Sprintf("Could not list pods matching selector: %v", podErr)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			} else {
 				matchedPods = pods
 				if len(pods) == 0 {
-					sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("No pods match selector %s — service cannot route traffic", util.FormatLabels(svc.Spec.Selector))))
+					sb.WriteString(findings.Format("KD-SVC-001", "CRITICAL", fmt.Sprintf("No pods match selector %s — service cannot route traffic", util.FormatLabels(svc.Spec.Selector))))
 					sb.WriteString("\n")
-					findings++
+					findingCount++
 				} else {
 					healthyCount := 0
 					for i := range pods {
@@ -783,15 +803,15 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 					}
 					sb.WriteString(fmt.Sprintf("  %d pods match selector (%d healthy, %d unhealthy)\n", len(pods), healthyCount, len(pods)-healthyCount))
 					if healthyCount == 0 {
-						sb.WriteString(util.FormatFinding("CRITICAL", "All matching pods are unhealthy"))
+						sb.WriteString(findings.Format("KD-POD-010", "CRITICAL", "All matching pods are unhealthy"))
 						sb.WriteString("\n")
-						findings++
+						findingCount++
 					} else if healthyCount < len(pods) {
-						sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("%d of %d matching pods are unhealthy", len(pods)-healthyCount, len(pods))))
+						sb.WriteString(findings.Format("KD-POD-010", "WARNING", fmt.Sprintf("%d of %d matching pods are unhealthy", len(pods)-healthyCount, len(pods))))
 						sb.WriteString("\n")
-						findings++
+						findingCount++
 					} else {
-						sb.WriteString(util.FormatFinding("INFO", "All matching pods are healthy"))
+						sb.WriteString(util.FormatFinding("OK", "All matching pods are healthy"))
 						sb.WriteString("\n")
 					}
 				}
@@ -805,9 +825,9 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 
 		health, healthErr := client.GetServiceEndpointHealth(ctx, ns, svcName)
 		if healthErr != nil {
-			sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Could not check endpoints: %v", healthErr)))
+			sb.WriteString(findings.Format("KD-SYS-008", "WARNING", fmt.Sprintf("Could not check endpoints: %v", healthErr)))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		} else {
 			sb.WriteString(util.FormatKeyValue("Total Endpoints", fmt.Sprintf("%d", health.TotalEndpoints)))
 			sb.WriteString("\n")
@@ -815,21 +835,24 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 			sb.WriteString("\n")
 			sb.WriteString(util.FormatKeyValue("Not Ready", fmt.Sprintf("%d", health.NotReadyCount)))
 			sb.WriteString("\n")
+			sb.WriteString(util.FormatKeyValue("Source", health.Source))
+			sb.WriteString("\n")
+			findingCount += writeEndpointFamilies(&sb, health)
 
 			if health.TotalEndpoints == 0 && svc.Spec.Selector != nil && len(svc.Spec.Selector) > 0 {
-				sb.WriteString(util.FormatFinding("CRITICAL", "No endpoints — service has no backends to route to"))
+				sb.WriteString(findings.Format("KD-SVC-001", "CRITICAL", "No endpoints — service has no backends to route to"))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			} else if health.ReadyCount == 0 && health.TotalEndpoints > 0 {
-				sb.WriteString(util.FormatFinding("CRITICAL", "All endpoints are not-ready — service is effectively down"))
+				sb.WriteString(findings.Format("KD-SVC-002", "CRITICAL", "All endpoints are not-ready — service is effectively down"))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			} else if health.NotReadyCount > 0 {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("%d of %d endpoints not ready", health.NotReadyCount, health.TotalEndpoints)))
+				sb.WriteString(findings.Format("KD-SVC-002", "WARNING", fmt.Sprintf("%d of %d endpoints not ready", health.NotReadyCount, health.TotalEndpoints)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			} else if health.ReadyCount > 0 {
-				sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("All %d endpoints are ready", health.ReadyCount)))
+				sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("All %d endpoints are ready", health.ReadyCount)))
 				sb.WriteString("\n")
 			}
 		}
@@ -856,9 +879,9 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 					targetPort = sp.Port
 				}
 				if len(containerPorts) > 0 && !containerPorts[targetPort] {
-					sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Service port %d/%s targets port %d, but no container declares this port", sp.Port, sp.Protocol, targetPort)))
+					sb.WriteString(findings.Format("KD-SVC-003", "WARNING", fmt.Sprintf("Service port %d/%s targets port %d, but no container declares this port", sp.Port, sp.Protocol, targetPort)))
 					sb.WriteString("\n")
-					findings++
+					findingCount++
 				} else {
 					sb.WriteString(fmt.Sprintf("  Port %d/%s -> target %d: OK\n", sp.Port, sp.Protocol, targetPort))
 				}
@@ -876,7 +899,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		if npErr != nil {
 			sb.WriteString(fmt.Sprintf("  Could not check network policies: %v\n", npErr))
 		} else if len(policies) == 0 {
-			sb.WriteString(util.FormatFinding("INFO", "No network policies in namespace — all traffic allowed by default"))
+			sb.WriteString(findings.Format("KD-NET-001", "INFO", "No network policies in namespace — all traffic allowed by default"))
 			sb.WriteString("\n")
 		} else {
 			matchingPolicies := 0
@@ -895,9 +918,9 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 						sb.WriteString(fmt.Sprintf("  Policy '%s' affects service pods\n", np.Name))
 						for _, pt := range np.Spec.PolicyTypes {
 							if pt == networkingv1.PolicyTypeIngress && len(np.Spec.Ingress) == 0 {
-								sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("    Policy '%s' denies all ingress — may block traffic to this service", np.Name)))
+								sb.WriteString(findings.Format("KD-NET-003", "WARNING", fmt.Sprintf("    Policy '%s' denies all ingress — may block traffic to this service", np.Name)))
 								sb.WriteString("\n")
-								findings++
+								findingCount++
 							}
 						}
 						break // One match per policy is enough
@@ -905,7 +928,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 				}
 			}
 			if matchingPolicies == 0 {
-				sb.WriteString(util.FormatFinding("INFO", "No network policies target this service's pods"))
+				sb.WriteString(findings.Format("KD-NET-001", "INFO", "No network policies target this service's pods"))
 				sb.WriteString("\n")
 			}
 		}
@@ -934,24 +957,31 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 				}
 			}
 			if exposingIngresses == 0 {
-				sb.WriteString(util.FormatFinding("INFO", "Service is not exposed via any Ingress (internal only)"))
-				sb.WriteString("\n")
+				sb.WriteString("  Service is not exposed via any Ingress (internal only)\n")
 			}
 		}
 
+		// --- Check 7: Active probe (optional) ---
+		if input.Active {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("Check 7: Active Connectivity Probe"))
+			sb.WriteString("\n")
+			findingCount += activeServiceProbe(ctx, client, opts, &sb, svc, input.SourcePod, matchedPods)
+		}
+
 		// --- Overall assessment ---
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
 		sb.WriteString("\n")
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  Service connectivity looks healthy. No issues found.\n")
 		} else {
-			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findings))
+			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findingCount))
 		}
 
 		// --- Mermaid Flowchart ---
 		sb.WriteString("\nCONNECTIVITY DIAGRAM:\n")
-		fc := mermaid.NewFlowchart(mermaid.DirectionTB)
+		fc := newBudgetedFlowchart(mermaid.DirectionTB, input.MaxDiagramNodes)
 
 		// Ingress nodes (if any)
 		if ingErr == nil {
@@ -990,18 +1020,13 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		}
 
 		// Pod nodes
-		for i := range matchedPods {
-			pod := &matchedPods[i]
-			podID := mermaid.SafeID("pod_" + pod.Name)
-			podLabel := fmt.Sprintf("%s%s%s", pod.Name, mermaid.BR(), podPhaseReason(pod))
-			fc.AddNode(podID, podLabel, mermaid.ShapeRect)
-			fc.AddEdge(svcID, podID, "", mermaid.EdgeSolid)
-
-			if isPodHealthy(pod) {
-				fc.AddStyle(podID, mermaid.SeverityHealthy)
-			} else {
-				fc.AddStyle(podID, mermaid.SeverityCritical)
-			}
+		podNodes := summarizePodsForDiagram(matchedPods, func(p *corev1.Pod) string {
+			return fmt.Sprintf("%s%s%s", p.Name, mermaid.BR(), podPhaseReason(p))
+		})
+		for _, n := range podNodes {
+			fc.AddNode(n.ID, n.Label, mermaid.ShapeRect)
+			fc.AddEdge(svcID, n.ID, "", mermaid.EdgeSolid)
+			styleDiagramPodNode(fc, n)
 		}
 
 		// NetworkPolicy nodes
@@ -1019,8 +1044,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 						npID := mermaid.SafeID("np_" + np.Name)
 						fc.AddNode(npID, fmt.Sprintf("NetPol: %s", np.Name), mermaid.ShapeDiamond)
 						fc.AddStyle(npID, mermaid.SeverityWarning)
-						podID := mermaid.SafeID("pod_" + pod.Name)
-						fc.AddEdge(npID, podID, "restricts", mermaid.EdgeDotted)
+						fc.AddEdge(npID, diagramNodeIDForPod(podNodes, pod.Name), "restricts", mermaid.EdgeDotted)
 						break
 					}
 				}
@@ -1040,6 +1064,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		Name:        "analyze_all_ingresses",
 		Description: "Audit every Ingress in a namespace: AGIC annotations, backend service existence and endpoint health, TLS configuration, and conflicting host/path rules across ingresses. Use this for a pre-deployment or post-incident ingress review.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeAllIngressesInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
 		ns := input.Namespace
 		if ns == "" {
 			return util.ErrorResult("namespace is required"), nil, nil
@@ -1084,7 +1109,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 			if len(agicAnnotations) > 0 {
 				sb.WriteString(fmt.Sprintf("  AGIC Annotations (%d):\n", len(agicAnnotations)))
 				for _, a := range agicAnnotations {
-					sb.WriteString(fmt.Sprintf("    %s: %s\n", a.Key, a.Value))
+					sb.WriteString(fmt.Sprintf("    %s: %s\n", a.Key, util.RedactAnnotation(a.Key, a.Value)))
 				}
 			}
 
@@ -1097,7 +1122,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 				sb.WriteString(fmt.Sprintf("\n  Host: %s\n", host))
 
 				if rule.HTTP == nil {
-					sb.WriteString(util.FormatFinding("WARNING", "    Rule has no HTTP paths defined"))
+					sb.WriteString(findings.Format("KD-ING-001", "WARNING", "    Rule has no HTTP paths defined"))
 					sb.WriteString("\n")
 					totalFindings++
 					continue
@@ -1119,7 +1144,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 					sb.WriteString(fmt.Sprintf("    Path: %s (type: %s)\n", path.Path, pathType))
 
 					if path.Backend.Service == nil {
-						sb.WriteString(util.FormatFinding("CRITICAL", "      No service backend configured"))
+						sb.WriteString(findings.Format("KD-ING-001", "CRITICAL", "      No service backend configured"))
 						sb.WriteString("\n")
 						totalFindings++
 						continue
@@ -1137,7 +1162,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 					// Verify backend service exists
 					backendSvc, svcErr := client.GetService(ctx, ns, backendName)
 					if svcErr != nil {
-						sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("      Backend service '%s' NOT FOUND", backendName)))
+						sb.WriteString(findings.Format("KD-ING-001", "CRITICAL", fmt.Sprintf("      Backend service '%s' NOT FOUND", backendName)))
 						sb.WriteString("\n")
 						totalFindings++
 						continue
@@ -1152,7 +1177,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 						}
 					}
 					if !portFound && backendPort != "" {
-						sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("      Port '%s' not defined on service '%s'", backendPort, backendName)))
+						sb.WriteString(findings.Format("KD-ING-002", "WARNING", fmt.Sprintf("      Port '%s' not defined on service '%s'", backendPort, backendName)))
 						sb.WriteString("\n")
 						totalFindings++
 					}
@@ -1162,11 +1187,11 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 					if epErr != nil {
 						sb.WriteString(fmt.Sprintf("      Endpoints: could not check (%v)\n", epErr))
 					} else if epHealth.TotalEndpoints == 0 {
-						sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("      Service '%s' has 0 endpoints — this path will return 502/503", backendName)))
+						sb.WriteString(findings.Format("KD-SVC-001", "CRITICAL", fmt.Sprintf("      Service '%s' has 0 endpoints — this path will return 502/503", backendName)))
 						sb.WriteString("\n")
 						totalFindings++
 					} else if epHealth.NotReadyCount > 0 {
-						sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("      Service '%s' has %d/%d ready endpoints", backendName, epHealth.ReadyCount, epHealth.TotalEndpoints)))
+						sb.WriteString(findings.Format("KD-SVC-002", "WARNING", fmt.Sprintf("      Service '%s' has %d/%d ready endpoints", backendName, epHealth.ReadyCount, epHealth.TotalEndpoints)))
 						sb.WriteString("\n")
 						totalFindings++
 					} else {
@@ -1181,7 +1206,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 				for _, tls := range ing.Spec.TLS {
 					sb.WriteString(fmt.Sprintf("    Hosts: %s\n", strings.Join(tls.Hosts, ", ")))
 					if tls.SecretName == "" {
-						sb.WriteString(util.FormatFinding("WARNING", "    No TLS secret specified — may use default certificate"))
+						sb.WriteString(findings.Format("KD-ING-004", "WARNING", "    No TLS secret specified — may use default certificate"))
 						sb.WriteString("\n")
 						totalFindings++
 					} else {
@@ -1189,7 +1214,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 					}
 				}
 			} else {
-				sb.WriteString(util.FormatFinding("INFO", "\n  No TLS configured — traffic is unencrypted"))
+				sb.WriteString(findings.Format("KD-ING-004", "INFO", "\n  No TLS configured — traffic is unencrypted"))
 				sb.WriteString("\n")
 			}
 
@@ -1211,7 +1236,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 						continue // Same ingress, not a conflict
 					}
 					if pathsOverlap(entries[i].Path, entries[j].Path) {
-						sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf(
+						sb.WriteString(findings.Format("KD-ING-009", "WARNING", fmt.Sprintf(
 							"Potential conflict: host '%s' path '%s' (ingress: %s) overlaps with path '%s' (ingress: %s)",
 							host, entries[i].Path, entries[i].IngressName, entries[j].Path, entries[j].IngressName,
 						)))
@@ -1251,7 +1276,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		sb.WriteString(util.FormatHeader("AGIC Health Check"))
 		sb.WriteString("\n\n")
 
-		findings := 0
+		findingCount := 0
 
 		// --- Find AGIC pod ---
 		sb.WriteString(util.FormatSubHeader("AGIC Pod Status"))
@@ -1266,7 +1291,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		}
 
 		if len(agicPods) == 0 {
-			sb.WriteString(util.FormatFinding("CRITICAL", "No AGIC pods found with label 'app=ingress-azure'"))
+			sb.WriteString(findings.Format("KD-ING-005", "CRITICAL", "No AGIC pods found with label 'app=ingress-azure'"))
 			sb.WriteString("\n")
 			sb.WriteString("\nSUGGESTED ACTIONS:\n")
 			sb.WriteString("1. Verify AGIC is installed in the cluster\n")
@@ -1300,22 +1325,22 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 
 			// Check health
 			if !isPodHealthy(p) {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("AGIC pod '%s' is not healthy: %s", p.Name, podPhaseReason(p))))
+				sb.WriteString(findings.Format("KD-ING-005", "CRITICAL", fmt.Sprintf("AGIC pod '%s' is not healthy: %s", p.Name, podPhaseReason(p))))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			} else {
-				sb.WriteString(util.FormatFinding("INFO", "Pod is running and healthy"))
+				sb.WriteString(util.FormatFinding("OK", "Pod is running and healthy"))
 				sb.WriteString("\n")
 			}
 
 			// Check restarts
 			_, _, restarts := podContainerSummary(p)
 			if restarts > util.HighRestartThreshold {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("AGIC pod has high restart count: %d", restarts)))
+				sb.WriteString(findings.Format("KD-POD-005", "WARNING", fmt.Sprintf("AGIC pod has high restart count: %d", restarts)))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			} else if restarts > 0 {
-				sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("Pod has restarted %d time(s)", restarts)))
+				sb.WriteString(findings.Format("KD-POD-005", "INFO", fmt.Sprintf("Pod has restarted %d time(s)", restarts)))
 				sb.WriteString("\n")
 			}
 
@@ -1324,15 +1349,15 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 				if cs.LastTerminationState.Terminated != nil {
 					t := cs.LastTerminationState.Terminated
 					if t.Reason == "OOMKilled" {
-						sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Container '%s' was OOMKilled — consider increasing memory limits", cs.Name)))
+						sb.WriteString(findings.Format("KD-POD-003", "CRITICAL", fmt.Sprintf("Container '%s' was OOMKilled — consider increasing memory limits", cs.Name)))
 						sb.WriteString("\n")
-						findings++
+						findingCount++
 					}
 				}
 				if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
-					sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Container '%s' is in CrashLoopBackOff", cs.Name)))
+					sb.WriteString(findings.Format("KD-POD-001", "CRITICAL", fmt.Sprintf("Container '%s' is in CrashLoopBackOff", cs.Name)))
 					sb.WriteString("\n")
-					findings++
+					findingCount++
 				}
 			}
 
@@ -1356,7 +1381,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 							sb.WriteString(msg + "\n")
 						}
 					}
-					findings++
+					findingCount++
 				}
 			}
 
@@ -1390,7 +1415,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 					if len(errorLines) > 20 {
 						sb.WriteString(fmt.Sprintf("    ... and %d more error lines\n", len(errorLines)-20))
 					}
-					findings++
+					findingCount++
 				} else {
 					sb.WriteString("  No errors or warnings in recent logs.\n")
 				}
@@ -1407,7 +1432,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		configMapNames := []string{"ingress-azure", "agic-config"}
 		configMapFound := false
 		for _, cmName := range configMapNames {
-			cm, cmErr := client.Clientset.CoreV1().ConfigMaps(agicNS).Get(ctx, cmName, metav1.GetOptions{})
+			cm, cmErr := client.GetConfigMap(ctx, agicNS, cmName)
 			if cmErr != nil {
 				continue
 			}
@@ -1424,7 +1449,7 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 			break
 		}
 		if !configMapFound {
-			sb.WriteString(util.FormatFinding("INFO", "No AGIC ConfigMap found (may be using Helm values or pod-identity)"))
+			sb.WriteString(findings.Format("KD-ING-005", "INFO", "No AGIC ConfigMap found (may be using Helm values or pod-identity)"))
 			sb.WriteString("\n")
 		}
 
@@ -1432,10 +1457,10 @@ func registerNetworkAnalysisTools(server *mcp.Server, client *k8s.ClusterClient)
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
 		sb.WriteString("\n")
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  AGIC appears healthy. No issues found.\n")
 		} else {
-			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findings))
+			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findingCount))
 		}
 
 		sb.WriteString("\nSUGGESTED ACTIONS:\n")
@@ -1531,3 +1556,93 @@ func extractLogErrors(logs string) []string {
 	}
 	return errors
 }
+
+// activeServiceProbe execs a reachability check against each service port from a
+// source pod and writes the results to sb. It returns the number of findings.
+func activeServiceProbe(ctx context.Context, client *k8s.ClusterClient, opts Options, sb *strings.Builder, svc *corev1.Service, sourcePod string, backends []corev1.Pod) int {
+	if !opts.EnableExec {
+		sb.WriteString(findings.Format("KD-SYS-008", "INFO", "Active probe skipped — the server was started without --enable-exec"))
+		sb.WriteString("\n")
+		return 0
+	}
+	if svc.Spec.ClusterIP == "" || svc.Spec.ClusterIP == corev1.ClusterIPNone {
+		sb.WriteString(findings.Format("KD-SYS-008", "INFO", "Active probe skipped — headless service has no ClusterIP"))
+		sb.WriteString("\n")
+		return 0
+	}
+
+	pods, err := client.ListPods(ctx, svc.Namespace, metav1.ListOptions{})
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("  Could not list source pods: %v\n", err))
+		return 0
+	}
+	source, err := selectProbeSourcePod(pods, sourcePod, backends)
+	if err != nil {
+		sb.WriteString(findings.Format("KD-SYS-008", "WARNING", fmt.Sprintf("Active probe skipped — %v", err)))
+		sb.WriteString("\n")
+		return 0
+	}
+	sb.WriteString(util.FormatKeyValue("Source Pod", fmt.Sprintf("%s (container %s)", source.Name, source.Spec.Containers[0].Name)))
+	sb.WriteString("\n")
+
+	findingCount := 0
+	for _, sp := range svc.Spec.Ports {
+		if sp.Protocol != "" && sp.Protocol != corev1.ProtocolTCP {
+			sb.WriteString(fmt.Sprintf("  Port %d/%s: skipped (only TCP can be probed)\n", sp.Port, sp.Protocol))
+			continue
+		}
+		res, probeErr := runConnectivityProbe(ctx, client, source, svc.Spec.ClusterIP, sp.Port)
+		if probeErr != nil {
+			sb.WriteString(findings.Format("KD-NET-005", "WARNING", fmt.Sprintf("Port %d: exec failed: %v", sp.Port, probeErr)))
+			sb.WriteString("\n")
+			findingCount++
+			continue
+		}
+		if res.Tool == "none" {
+			sb.WriteString(findings.Format("KD-SYS-008", "INFO", fmt.Sprintf("Cannot probe from %s — %s; choose another source_pod", source.Name, res.Detail)))
+			sb.WriteString("\n")
+			return findingCount
+		}
+		msg := fmt.Sprintf("Port %d via %s: %s (%s, %s)", sp.Port, res.Tool, res.Detail, res.Latency.Round(time.Millisecond), res.LatencyNote)
+		switch {
+		case !res.Reachable:
+			sb.WriteString(findings.Format("KD-NET-005", "CRITICAL", msg))
+			findingCount++
+		case strings.HasPrefix(res.HTTPStatus, "5"):
+			sb.WriteString(findings.Format("KD-NET-005", "WARNING", msg))
+			findingCount++
+		default:
+			sb.WriteString(util.FormatFinding("OK", msg))
+		}
+		sb.WriteString("\n")
+	}
+	return findingCount
+}
+
+// writeEndpointFamilies writes per-slice and per-address-family readiness for
+// services backed by more than one EndpointSlice or address family, and flags
+// dual-stack services whose families disagree. It returns the number of findings.
+func writeEndpointFamilies(sb *strings.Builder, health *k8s.EndpointHealth) int {
+	if len(health.Slices) <= 1 && len(health.Families) <= 1 {
+		return 0
+	}
+	findingCount := 0
+	rows := make([][]string, 0, len(health.Slices))
+	for _, s := range health.Slices {
+		rows = append(rows, []string{s.Name, s.AddressType, fmt.Sprintf("%d", s.Ready), fmt.Sprintf("%d", s.NotReady), fmt.Sprintf("%d", s.Terminating)})
+	}
+	sb.WriteString(util.FormatTable([]string{"SLICE", "FAMILY", "READY", "NOT READY", "TERMINATING"}, rows))
+	for _, f := range health.Families {
+		sb.WriteString(fmt.Sprintf("  %s: %d ready, %d not ready\n", f.AddressType, f.Ready, f.NotReady))
+	}
+	if len(health.Families) > 1 {
+		for _, f := range health.Families {
+			if f.Ready < health.ReadyCount {
+				sb.WriteString(findings.Format("KD-SVC-006", "WARNING", fmt.Sprintf("Dual-stack mismatch: only %d of %d ready endpoints have an %s address — clients using %s may fail", f.Ready, health.ReadyCount, f.AddressType, f.AddressType)))
+				sb.WriteString("\n")
+				findingCount++
+			}
+		}
+	}
+	return findingCount
+}
@@ -14,10 +14,14 @@ import (
 type listServicesInput struct {
 	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
 	LabelSelector string `json:"label_selector,omitempty" jsonschema:"Label selector filter"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type listIngressesInput struct {
-	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type getEndpointsInput struct {
@@ -34,10 +38,11 @@ func registerNetworkingTools(server *mcp.Server, client *k8s.ClusterClient) {
 		ns := util.NamespaceOrAll(input.Namespace)
 		opts := util.ListOptions(input.LabelSelector, "")
 
-		services, err := client.ListServices(ctx, ns, opts)
+		page, err := client.ListServicesPage(ctx, ns, util.PageOptions(opts, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing services", err), nil, nil
 		}
+		services := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "TYPE", "CLUSTER-IP", "EXTERNAL-IP", "PORTS", "AGE"}
 		rows := make([][]string, 0, len(services))
@@ -84,6 +89,7 @@ func registerNetworkingTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("services", len(services))))
+		sb.WriteString(pageFooter("list_services", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -96,10 +102,11 @@ func registerNetworkingTools(server *mcp.Server, client *k8s.ClusterClient) {
 		ns := util.NamespaceOrAll(input.Namespace)
 		opts := util.ListOptions("", "")
 
-		ingresses, err := client.ListIngresses(ctx, ns, opts)
+		page, err := client.ListIngressesPage(ctx, ns, util.PageOptions(opts, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing ingresses", err), nil, nil
 		}
+		ingresses := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "HOSTS", "PATHS", "TLS", "AGE"}
 		rows := make([][]string, 0, len(ingresses))
@@ -134,6 +141,7 @@ func registerNetworkingTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("ingresses", len(ingresses))))
+		sb.WriteString(pageFooter("list_ingresses", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
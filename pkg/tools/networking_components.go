@@ -0,0 +1,301 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// networkComponentLogPods caps how many unhealthy pods per component have
+// their logs scanned.
+const networkComponentLogPods = 3
+
+type checkClusterNetworkingComponentsInput struct {
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// networkComponentType describes a per-node networking agent kube-doctor can detect.
+type networkComponentType struct {
+	Key          string
+	DisplayName  string
+	CNI          bool
+	PodSelectors []string
+}
+
+// knownNetworkComponents lists kube-proxy and the CNI agents that run one pod per node.
+var knownNetworkComponents = []networkComponentType{
+	{Key: "kube-proxy", DisplayName: "kube-proxy", PodSelectors: []string{"k8s-app=kube-proxy", "component=kube-proxy"}},
+	{Key: "azure-cni", DisplayName: "Azure CNI (azure-cns)", CNI: true, PodSelectors: []string{"k8s-app=azure-cns"}},
+	{Key: "calico", DisplayName: "Calico", CNI: true, PodSelectors: []string{"k8s-app=calico-node"}},
+	{Key: "cilium", DisplayName: "Cilium", CNI: true, PodSelectors: []string{"k8s-app=cilium"}},
+	{Key: "flannel", DisplayName: "Flannel", CNI: true, PodSelectors: []string{"app=flannel", "k8s-app=flannel"}},
+}
+
+// networkComponentCoverage is how a per-node agent covers the eligible nodes.
+type networkComponentCoverage struct {
+	Missing   []string
+	Unhealthy []*corev1.Pod
+	Restarted []*corev1.Pod
+}
+
+func registerNetworkingComponentTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// check_cluster_networking_components
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "check_cluster_networking_components",
+		Description: "Check the per-node networking layer: kube-proxy DaemonSet coverage (a healthy pod on every node), CNI agent pods (Azure CNI, Calico, Cilium, Flannel) and their coverage, recent crash and error logs from unhealthy or restarting agents, and nodes reporting the NetworkUnavailable condition.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkClusterNetworkingComponentsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing nodes", err), nil, nil
+		}
+		eligible := networkAgentNodes(nodes)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Cluster Networking Components"))
+		sb.WriteString(util.FormatKeyValue("Nodes", fmt.Sprintf("%d (%d Linux nodes expected to run agents)", len(nodes), len(eligible))))
+		sb.WriteString("\n")
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		detected := make(map[string]bool)
+		var summaryRows [][]string
+		var logTargets []*corev1.Pod
+		for _, t := range knownNetworkComponents {
+			pods, err := listPodsBySelectors(ctx, client, t.PodSelectors)
+			if err != nil {
+				return util.HandleK8sError(fmt.Sprintf("listing %s pods", t.Key), err), nil, nil
+			}
+			if len(pods) == 0 {
+				continue
+			}
+			detected[t.Key] = true
+			cov := networkComponentCoverageFor(eligible, pods)
+			summaryRows = append(summaryRows, []string{
+				t.DisplayName,
+				pods[0].Namespace,
+				fmt.Sprintf("%d", len(pods)),
+				fmt.Sprintf("%d/%d", len(eligible)-len(cov.Missing), len(eligible)),
+				fmt.Sprintf("%d", len(cov.Unhealthy)),
+			})
+
+			if len(cov.Missing) > 0 {
+				findingLines = append(findingLines, findings.Format("KD-NET-004", "CRITICAL",
+					fmt.Sprintf("%s has no pod on %d node(s): %s", t.DisplayName, len(cov.Missing), joinLimited(cov.Missing, 5))))
+				actions = append(actions, fmt.Sprintf("Check the %s DaemonSet's nodeSelector, tolerations, and rollout status (use get_events on the missing nodes)", t.Key))
+			}
+			for _, p := range cov.Unhealthy {
+				findingLines = append(findingLines, findings.Format("KD-NET-004", "CRITICAL",
+					fmt.Sprintf("%s pod %s/%s on node %s is not healthy: %s", t.DisplayName, p.Namespace, p.Name, p.Spec.NodeName, podPhaseReason(p))))
+				actions = append(actions, fmt.Sprintf("Run diagnose_pod on the unhealthy %s pods; pods on their nodes may have no working network", t.Key))
+				steps = append(steps, nextStep("diagnose_pod", fmt.Sprintf("%s agent is %s", t.DisplayName, podPhaseReason(p)), "namespace", p.Namespace, "name", p.Name))
+			}
+			for _, p := range cov.Restarted {
+				_, _, restarts := podContainerSummary(p)
+				findingLines = append(findingLines, findings.Format("KD-NET-004", "WARNING",
+					fmt.Sprintf("%s pod %s/%s on node %s has restarted %d time(s)", t.DisplayName, p.Namespace, p.Name, p.Spec.NodeName, restarts)))
+			}
+			logTargets = append(logTargets, truncatePods(append(cov.Unhealthy, cov.Restarted...), networkComponentLogPods)...)
+		}
+
+		sb.WriteString(util.FormatSubHeader("DETECTED COMPONENTS"))
+		if len(summaryRows) > 0 {
+			sb.WriteString(util.FormatTable([]string{"COMPONENT", "NAMESPACE", "PODS", "NODE COVERAGE", "UNHEALTHY"}, summaryRows))
+		} else {
+			sb.WriteString("  No kube-proxy or known CNI agent pods found.\n")
+		}
+		sb.WriteString("\n")
+
+		if !detected["kube-proxy"] {
+			if detected["cilium"] {
+				findingLines = append(findingLines, findings.Format("KD-NET-004", "INFO", "No kube-proxy pods found; Cilium is installed and is likely running as the kube-proxy replacement"))
+			} else {
+				findingLines = append(findingLines, findings.Format("KD-NET-004", "WARNING", "No kube-proxy pods found and no kube-proxy replacement detected; Service ClusterIPs may not be routed"))
+				actions = append(actions, "Verify kube-proxy is deployed (list_daemonsets namespace=kube-system) or that the CNI replaces it")
+				steps = append(steps, nextStep("list_daemonsets", "No kube-proxy pods found", "namespace", "kube-system"))
+			}
+		}
+		cniFound := false
+		for _, t := range knownNetworkComponents {
+			if t.CNI && detected[t.Key] {
+				cniFound = true
+			}
+		}
+		if !cniFound {
+			findingLines = append(findingLines, findings.Format("KD-NET-004", "INFO", "No known CNI agent pods detected (the cluster may use kubenet or an unrecognized plugin)"))
+		}
+
+		// --- Nodes with NetworkUnavailable ---
+		if unavailable := nodesNetworkUnavailable(nodes); len(unavailable) > 0 {
+			sb.WriteString(util.FormatSubHeader("NODES WITH NetworkUnavailable"))
+			var rows [][]string
+			for _, u := range unavailable {
+				rows = append(rows, []string{u[0], u[1], truncateName(u[2], 80)})
+				findingLines = append(findingLines, findings.Format("KD-NET-004", "CRITICAL", fmt.Sprintf("Node %s reports NetworkUnavailable: %s", u[0], u[1])))
+				steps = append(steps, nextStep("get_node_detail", "Node reports NetworkUnavailable", "name", u[0]))
+			}
+			sb.WriteString(util.FormatTable([]string{"NODE", "REASON", "MESSAGE"}, rows))
+			sb.WriteString("\n")
+			actions = append(actions, "Check the CNI agent and route configuration on nodes reporting NetworkUnavailable (use get_node_detail)")
+		}
+
+		// --- Logs from unhealthy or restarting agents ---
+		if len(logTargets) > 0 {
+			sb.WriteString(util.FormatSubHeader("AGENT LOGS"))
+			for _, p := range logTargets {
+				writeNetworkAgentLogs(ctx, &sb, client, p)
+			}
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("FINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  kube-proxy and CNI agents are running and healthy on every node.\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// listPodsBySelectors lists pods in all namespaces matching any of the label
+// selectors, without duplicates.
+func listPodsBySelectors(ctx context.Context, client *k8s.ClusterClient, selectors []string) ([]corev1.Pod, error) {
+	var result []corev1.Pod
+	seen := make(map[string]bool)
+	for _, sel := range selectors {
+		pods, err := client.ListPods(ctx, "", metav1.ListOptions{LabelSelector: sel})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range pods {
+			key := p.Namespace + "/" + p.Name
+			if !seen[key] {
+				seen[key] = true
+				result = append(result, p)
+			}
+		}
+	}
+	return result, nil
+}
+
+// networkAgentNodes returns the names of nodes expected to run Linux
+// networking agents, skipping Windows and virtual-kubelet nodes.
+func networkAgentNodes(nodes []corev1.Node) []string {
+	var names []string
+	for i := range nodes {
+		l := nodes[i].Labels
+		if l["kubernetes.io/os"] == "windows" || l["type"] == "virtual-kubelet" {
+			continue
+		}
+		names = append(names, nodes[i].Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// networkComponentCoverageFor reports which eligible nodes have no agent pod,
+// and which agent pods are unhealthy or have restarted.
+func networkComponentCoverageFor(eligible []string, pods []corev1.Pod) networkComponentCoverage {
+	var cov networkComponentCoverage
+	onNode := make(map[string]bool)
+	for i := range pods {
+		p := &pods[i]
+		if p.Spec.NodeName != "" {
+			onNode[p.Spec.NodeName] = true
+		}
+		_, _, restarts := podContainerSummary(p)
+		switch {
+		case !isPodHealthy(p):
+			cov.Unhealthy = append(cov.Unhealthy, p)
+		case restarts > util.HighRestartThreshold:
+			cov.Restarted = append(cov.Restarted, p)
+		}
+	}
+	for _, n := range eligible {
+		if !onNode[n] {
+			cov.Missing = append(cov.Missing, n)
+		}
+	}
+	return cov
+}
+
+// nodesNetworkUnavailable returns {node, reason, message} for nodes whose
+// NetworkUnavailable condition is True.
+func nodesNetworkUnavailable(nodes []corev1.Node) [][3]string {
+	var result [][3]string
+	for i := range nodes {
+		for _, c := range nodes[i].Status.Conditions {
+			if c.Type == corev1.NodeNetworkUnavailable && c.Status == corev1.ConditionTrue {
+				result = append(result, [3]string{nodes[i].Name, c.Reason, c.Message})
+			}
+		}
+	}
+	return result
+}
+
+// writeNetworkAgentLogs writes error lines from an agent pod's logs, using the
+// previous container's logs when it has crashed.
+func writeNetworkAgentLogs(ctx context.Context, sb *strings.Builder, client *k8s.ClusterClient, p *corev1.Pod) {
+	if len(p.Spec.Containers) == 0 {
+		return
+	}
+	container := p.Spec.Containers[0].Name
+	previous := false
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.Name == container && cs.LastTerminationState.Terminated != nil {
+			previous = true
+		}
+	}
+	logs, err := client.GetPodLogs(ctx, p.Namespace, p.Name, container, 200, previous, "")
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("  %s: could not fetch logs: %v\n", p.Name, err))
+		return
+	}
+	source := "current"
+	if previous {
+		source = "previous (crashed)"
+	}
+	errorLines := extractLogErrors(logs)
+	if len(errorLines) == 0 {
+		sb.WriteString(fmt.Sprintf("  %s: no errors in the last 200 lines of %s logs\n", p.Name, source))
+		return
+	}
+	sb.WriteString(fmt.Sprintf("  %s: %d error/warning line(s) in %s logs\n", p.Name, len(errorLines), source))
+	for _, line := range errorLines[max(0, len(errorLines)-5):] {
+		sb.WriteString(fmt.Sprintf("    %s\n", truncateName(line, 200)))
+	}
+}
+
+// joinLimited joins at most n items, noting how many were left out.
+func joinLimited(items []string, n int) string {
+	if len(items) <= n {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s ... and %d more", strings.Join(items[:n], ", "), len(items)-n)
+}
+
+// truncatePods returns at most n pods.
+func truncatePods(pods []*corev1.Pod, n int) []*corev1.Pod {
+	if len(pods) > n {
+		return pods[:n]
+	}
+	return pods
+}
@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNetworkComponentCoverage(t *testing.T) {
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "n1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "n2"}, Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeNetworkUnavailable, Status: corev1.ConditionTrue, Reason: "NoRouteCreated"},
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "n3"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "win1", Labels: map[string]string{"kubernetes.io/os": "windows"}}},
+	}
+	eligible := networkAgentNodes(nodes)
+	if len(eligible) != 3 {
+		t.Fatalf("expected Windows node to be skipped, got %v", eligible)
+	}
+
+	agent := func(name, node string, ready bool, restarts int32) corev1.Pod {
+		p := testPod("kube-system", name, node, nil)
+		p.Spec.Containers[0].Name = "kube-proxy"
+		p.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "kube-proxy", Ready: ready, RestartCount: restarts}}
+		return p
+	}
+	pods := []corev1.Pod{
+		agent("kube-proxy-a", "n1", true, 0),
+		agent("kube-proxy-b", "n2", false, 0),
+	}
+	cov := networkComponentCoverageFor(eligible, pods)
+	if len(cov.Missing) != 1 || cov.Missing[0] != "n3" {
+		t.Errorf("expected n3 to be missing kube-proxy, got %v", cov.Missing)
+	}
+	if len(cov.Unhealthy) != 1 || cov.Unhealthy[0].Name != "kube-proxy-b" {
+		t.Errorf("expected kube-proxy-b to be unhealthy, got %v", cov.Unhealthy)
+	}
+
+	unavailable := nodesNetworkUnavailable(nodes)
+	if len(unavailable) != 1 || unavailable[0][0] != "n2" || unavailable[0][1] != "NoRouteCreated" {
+		t.Errorf("expected n2 NetworkUnavailable, got %v", unavailable)
+	}
+}
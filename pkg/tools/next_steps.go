@@ -0,0 +1,23 @@
+package tools
+
+import (
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// nextStep builds a follow-up tool call from alternating argument names and
+// values, e.g. nextStep("diagnose_pod", reason, "namespace", ns, "name", name).
+// Empty string values are omitted.
+func nextStep(tool, reason string, kv ...any) util.NextStep {
+	s := util.NextStep{Tool: tool, Reason: reason}
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, _ := kv[i].(string)
+		if v, ok := kv[i+1].(string); ok && v == "" {
+			continue
+		}
+		if s.Arguments == nil {
+			s.Arguments = make(map[string]any)
+		}
+		s.Arguments[key] = kv[i+1]
+	}
+	return s
+}
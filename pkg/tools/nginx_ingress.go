@@ -0,0 +1,482 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type checkNginxIngressHealthInput struct {
+	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace whose Ingresses to audit for nginx annotations (empty for all)"`
+	TailLines int64  `json:"tail_lines,omitempty" jsonschema:"Controller log lines to scan per pod (default 1000)"`
+}
+
+// nginxAnnotationPrefix is the prefix of all ingress-nginx annotations.
+const nginxAnnotationPrefix = "nginx.ingress.kubernetes.io/"
+
+// nginxReloadFailurePatterns match controller log lines reporting a failed config reload.
+var nginxReloadFailurePatterns = []string{
+	"Error reloading NGINX",
+	"Unexpected failure reloading the backend",
+	"nginx: [emerg]",
+	"Error: exit status 1",
+}
+
+// nginxAccessLogRegexp matches the request and status of the default ingress-nginx access log format.
+var nginxAccessLogRegexp = regexp.MustCompile(`"[A-Z]+ (\S+) HTTP/[\d.]+" (\d{3}) `)
+
+// nginxAnnotationKind is how an annotation value is validated.
+type nginxAnnotationKind int
+
+const (
+	nginxAnnotationAny nginxAnnotationKind = iota
+	nginxAnnotationBool
+	nginxAnnotationInt
+	nginxAnnotationSize
+	nginxAnnotationURL
+	nginxAnnotationSnippet
+)
+
+// nginxKnownAnnotations maps commonly used ingress-nginx annotations to their value type.
+var nginxKnownAnnotations = func() map[string]nginxAnnotationKind {
+	m := make(map[string]nginxAnnotationKind)
+	for kind, names := range map[nginxAnnotationKind][]string{
+		nginxAnnotationBool: {
+			"use-regex", "ssl-redirect", "force-ssl-redirect", "ssl-passthrough", "enable-cors",
+			"cors-allow-credentials", "auth-tls-pass-certificate-to-upstream", "session-cookie-secure", "canary",
+			"service-upstream", "from-to-www-redirect", "enable-access-log", "enable-rewrite-log",
+			"enable-opentelemetry", "enable-modsecurity", "enable-owasp-core-rules", "grpc-backend",
+		},
+		nginxAnnotationInt: {
+			"proxy-buffers-number", "proxy-connect-timeout", "proxy-read-timeout", "proxy-send-timeout",
+			"proxy-next-upstream-tries", "proxy-next-upstream-timeout", "cors-max-age", "auth-tls-verify-depth",
+			"limit-rps", "limit-rpm", "limit-connections", "limit-burst-multiplier", "session-cookie-max-age",
+			"session-cookie-expires", "canary-weight", "canary-weight-total", "permanent-redirect-code",
+		},
+		nginxAnnotationSize: {
+			"proxy-body-size", "proxy-buffer-size", "client-body-buffer-size",
+		},
+		nginxAnnotationURL: {
+			"auth-url", "auth-signin", "permanent-redirect", "temporal-redirect",
+		},
+		nginxAnnotationSnippet: {
+			"modsecurity-snippet", "configuration-snippet", "server-snippet", "auth-snippet", "stream-snippet",
+		},
+		nginxAnnotationAny: {
+			"rewrite-target", "app-root", "backend-protocol", "proxy-buffering", "proxy-request-buffering",
+			"proxy-next-upstream", "proxy-http-version", "proxy-ssl-secret", "proxy-ssl-verify", "proxy-redirect-from",
+			"proxy-redirect-to", "cors-allow-origin", "cors-allow-methods", "cors-allow-headers",
+			"cors-expose-headers", "auth-type", "auth-secret", "auth-realm", "auth-response-headers",
+			"auth-tls-secret", "auth-tls-verify-client", "limit-whitelist", "whitelist-source-range",
+			"denylist-source-range", "affinity", "affinity-mode", "session-cookie-name", "session-cookie-path",
+			"session-cookie-samesite", "canary-by-header", "canary-by-header-value", "canary-by-cookie",
+			"upstream-vhost", "upstream-hash-by", "load-balance", "default-backend", "custom-http-errors",
+			"server-alias", "x-forwarded-prefix", "satisfy",
+		},
+	} {
+		for _, n := range names {
+			m[n] = kind
+		}
+	}
+	return m
+}()
+
+// nginxBackendProtocols are the valid values for the backend-protocol annotation.
+var nginxBackendProtocols = map[string]bool{"HTTP": true, "HTTPS": true, "GRPC": true, "GRPCS": true, "AJP": true, "FCGI": true}
+
+var nginxSizeRegexp = regexp.MustCompile(`^\d+[kKmMgG]?$`)
+
+// nginxLogStats summarizes controller access and error logs.
+type nginxLogStats struct {
+	StatusCounts   map[string]int // "2xx", "4xx", "5xx"
+	ErrorPaths     map[string]int // path -> 5xx count
+	ReloadFailures []string
+	ReloadSuccess  int
+}
+
+func registerNginxIngressTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// check_nginx_ingress_health
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "check_nginx_ingress_health",
+		Description: "Deep diagnostics for the ingress-nginx controller: controller pod health, config reload failures in logs, admission webhook availability, 4xx/5xx patterns from access logs, and validation of nginx.ingress.kubernetes.io annotations on Ingresses using the nginx class.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkNginxIngressHealthInput) (*mcp.CallToolResult, any, error) {
+		tailLines := input.TailLines
+		if tailLines <= 0 {
+			tailLines = 1000
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("NGINX Ingress Controller Health"))
+		sb.WriteString("\n\n")
+
+		classes, err := client.ListIngressClasses(ctx)
+		if err != nil {
+			return util.HandleK8sError("listing ingress classes", err), nil, nil
+		}
+		detected, err := detectIngressControllers(ctx, client, classes)
+		if err != nil {
+			return util.HandleK8sError("searching for ingress-nginx pods", err), nil, nil
+		}
+		var nginx *detectedIngressController
+		for i := range detected {
+			if detected[i].Type.Key == "nginx" {
+				nginx = &detected[i]
+			}
+		}
+		if nginx == nil {
+			sb.WriteString(findings.Format("KD-ING-005", "WARNING", "ingress-nginx not detected (no IngressClass with controller k8s.io/ingress-nginx and no controller pods)"))
+			sb.WriteString("\n")
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			sb.WriteString("1. Use check_ingress_controller_health to see which controllers are installed\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		findingCount := 0
+		var actions []string
+
+		// --- 1. Controller pods ---
+		sb.WriteString(util.FormatSubHeader("Controller Pods"))
+		sb.WriteString("\n")
+		podHeaders := []string{"POD", "NAMESPACE", "STATUS", "READY", "RESTARTS", "AGE", "NODE"}
+		podRows := make([][]string, 0, len(nginx.Pods))
+		for i := range nginx.Pods {
+			p := &nginx.Pods[i]
+			ready, total, restarts := podContainerSummary(p)
+			podRows = append(podRows, []string{p.Name, p.Namespace, podPhaseReason(p), fmt.Sprintf("%d/%d", ready, total),
+				fmt.Sprintf("%d", restarts), util.FormatAge(p.CreationTimestamp.Time), p.Spec.NodeName})
+		}
+		sb.WriteString(util.FormatTable(podHeaders, podRows))
+		n, a := checkIngressControllerPods(ctx, client, &sb, *nginx)
+		findingCount += n
+		actions = append(actions, a...)
+
+		// --- 2. Logs: reloads and status codes ---
+		stats := nginxLogStats{StatusCounts: map[string]int{}, ErrorPaths: map[string]int{}}
+		for i := range nginx.Pods {
+			p := &nginx.Pods[i]
+			if p.Status.Phase != corev1.PodRunning || len(p.Spec.Containers) == 0 {
+				continue
+			}
+			logs, logErr := client.GetPodLogs(ctx, p.Namespace, p.Name, p.Spec.Containers[0].Name, tailLines, false, "")
+			if logErr != nil {
+				continue
+			}
+			analyzeNginxLogs(logs, &stats)
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Configuration Reloads"))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("  Successful reloads in scanned logs: %d\n", stats.ReloadSuccess))
+		if len(stats.ReloadFailures) > 0 {
+			sb.WriteString(findings.Format("KD-ING-005", "CRITICAL", fmt.Sprintf("%d config reload failure line(s) — the controller is serving a stale configuration", len(stats.ReloadFailures))))
+			sb.WriteString("\n")
+			for i, line := range stats.ReloadFailures {
+				if i >= 5 {
+					sb.WriteString(fmt.Sprintf("    ... and %d more\n", len(stats.ReloadFailures)-5))
+					break
+				}
+				sb.WriteString(fmt.Sprintf("    %s\n", truncateName(line, 200)))
+			}
+			findingCount++
+			actions = append(actions, "Fix the Ingress or snippet that breaks nginx.conf (the [emerg] line names the directive); recent Ingress changes are the usual cause")
+		} else {
+			sb.WriteString(util.FormatFinding("OK", "No reload failures in scanned logs"))
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Access Log Status Codes"))
+		sb.WriteString("\n")
+		total := 0
+		for _, c := range stats.StatusCounts {
+			total += c
+		}
+		if total == 0 {
+			sb.WriteString("  No access log lines found (access logging may be disabled).\n")
+		} else {
+			for _, class := range []string{"2xx", "3xx", "4xx", "5xx"} {
+				sb.WriteString(fmt.Sprintf("  %s: %d (%.1f%%)\n", class, stats.StatusCounts[class], float64(stats.StatusCounts[class])/float64(total)*100))
+			}
+			if pct := float64(stats.StatusCounts["5xx"]) / float64(total) * 100; pct >= 5 {
+				sb.WriteString(findings.Format("KD-ING-006", "CRITICAL", fmt.Sprintf("%.1f%% of requests returned 5xx", pct)))
+				sb.WriteString("\n")
+				findingCount++
+				actions = append(actions, "Run diagnose_request_path for the top 5xx paths to find dead backends")
+			} else if pct > 0 {
+				sb.WriteString(findings.Format("KD-ING-006", "WARNING", fmt.Sprintf("%.1f%% of requests returned 5xx", pct)))
+				sb.WriteString("\n")
+				findingCount++
+			}
+			if pct := float64(stats.StatusCounts["4xx"]) / float64(total) * 100; pct >= 25 {
+				sb.WriteString(findings.Format("KD-ING-006", "WARNING", fmt.Sprintf("%.1f%% of requests returned 4xx — check routing rules and auth annotations", pct)))
+				sb.WriteString("\n")
+				findingCount++
+			}
+			if len(stats.ErrorPaths) > 0 {
+				sb.WriteString("  Top 5xx paths:\n")
+				for _, pc := range topCounts(stats.ErrorPaths, 5) {
+					sb.WriteString(fmt.Sprintf("    %-50s %d\n", truncateName(pc.key, 50), pc.count))
+				}
+			}
+		}
+
+		// --- 3. Admission webhook ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Admission Webhook"))
+		sb.WriteString("\n")
+		webhooks, whErr := client.ListValidatingWebhookConfigurations(ctx)
+		if whErr != nil {
+			sb.WriteString(fmt.Sprintf("  Could not list webhook configurations: %v\n", whErr))
+		} else {
+			n, a := checkNginxAdmissionWebhook(ctx, client, &sb, webhooks)
+			findingCount += n
+			actions = append(actions, a...)
+		}
+
+		// --- 4. Annotation validation ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Ingress Annotation Audit"))
+		sb.WriteString("\n")
+		ingresses, ingErr := client.ListIngresses(ctx, util.NamespaceOrAll(input.Namespace), metav1.ListOptions{})
+		if ingErr != nil {
+			sb.WriteString(fmt.Sprintf("  Could not list ingresses: %v\n", ingErr))
+		} else {
+			nginxClasses := make(map[string]bool)
+			for _, c := range nginx.Classes {
+				nginxClasses[c] = true
+			}
+			nginxClasses["nginx"] = true
+			audited := 0
+			for i := range ingresses {
+				ing := &ingresses[i]
+				if !nginxClasses[ingressClassName(ing)] {
+					continue
+				}
+				audited++
+				for _, problem := range validateNginxAnnotations(ing) {
+					sb.WriteString(findings.Format("KD-ING-007", problem.severity, fmt.Sprintf("%s/%s: %s", ing.Namespace, ing.Name, problem.message)))
+					sb.WriteString("\n")
+					findingCount++
+					if problem.action != "" {
+						actions = append(actions, problem.action)
+					}
+				}
+			}
+			sb.WriteString(fmt.Sprintf("  Audited %d Ingress(es) using the nginx class.\n", audited))
+		}
+
+		// --- Overall ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
+		sb.WriteString("\n")
+		if findingCount == 0 {
+			sb.WriteString("  ingress-nginx appears healthy. No issues found.\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findingCount))
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// analyzeNginxLogs accumulates reload results and access log status codes from controller logs.
+func analyzeNginxLogs(logs string, stats *nginxLogStats) {
+	for _, line := range strings.Split(logs, "\n") {
+		if line == "" {
+			continue
+		}
+		if strings.Contains(line, "Backend successfully reloaded") {
+			stats.ReloadSuccess++
+			continue
+		}
+		for _, pattern := range nginxReloadFailurePatterns {
+			if strings.Contains(line, pattern) {
+				stats.ReloadFailures = append(stats.ReloadFailures, strings.TrimSpace(line))
+				break
+			}
+		}
+		if m := nginxAccessLogRegexp.FindStringSubmatch(line); m != nil {
+			class := m[2][:1] + "xx"
+			stats.StatusCounts[class]++
+			if class == "5xx" {
+				path := m[1]
+				if i := strings.IndexByte(path, '?'); i >= 0 {
+					path = path[:i]
+				}
+				stats.ErrorPaths[path]++
+			}
+		}
+	}
+}
+
+// checkNginxAdmissionWebhook verifies the ingress-nginx validating webhook has a
+// backing service with ready endpoints.
+func checkNginxAdmissionWebhook(ctx context.Context, client *k8s.ClusterClient, sb *strings.Builder, webhooks []admissionregistrationv1.ValidatingWebhookConfiguration) (int, []string) {
+	findingCount := 0
+	var actions []string
+	found := false
+	for _, cfg := range webhooks {
+		for _, wh := range cfg.Webhooks {
+			if !strings.Contains(wh.Name, "ingress") || !strings.Contains(cfg.Name, "nginx") {
+				continue
+			}
+			found = true
+			policy := "Fail"
+			if wh.FailurePolicy != nil {
+				policy = string(*wh.FailurePolicy)
+			}
+			svc := wh.ClientConfig.Service
+			if svc == nil {
+				sb.WriteString(fmt.Sprintf("  %s: URL-based webhook (failurePolicy %s)\n", wh.Name, policy))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("  %s -> service %s/%s (failurePolicy %s)\n", wh.Name, svc.Namespace, svc.Name, policy))
+			health, err := client.GetServiceEndpointHealth(ctx, svc.Namespace, svc.Name)
+			severity := "WARNING"
+			if policy == "Fail" {
+				severity = "CRITICAL"
+			}
+			if err != nil || health.ReadyCount == 0 {
+				sb.WriteString(findings.Format("KD-SYS-006", severity, fmt.Sprintf("Admission webhook service %s/%s has no ready endpoints — Ingress create/update requests will be rejected", svc.Namespace, svc.Name)))
+				sb.WriteString("\n")
+				findingCount++
+				actions = append(actions, "Restore the ingress-nginx controller pods backing the admission webhook, or temporarily set failurePolicy=Ignore")
+			} else {
+				sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("Webhook service has %d ready endpoint(s)", health.ReadyCount)))
+				sb.WriteString("\n")
+			}
+		}
+	}
+	if !found {
+		sb.WriteString(findings.Format("KD-ING-007", "INFO", "No ingress-nginx admission webhook configured (invalid Ingresses will only fail at reload time)"))
+		sb.WriteString("\n")
+	}
+	return findingCount, actions
+}
+
+// nginxAnnotationProblem is a single invalid or risky nginx annotation.
+type nginxAnnotationProblem struct {
+	severity string
+	message  string
+	action   string
+}
+
+// validateNginxAnnotations checks nginx.ingress.kubernetes.io annotations on an Ingress.
+func validateNginxAnnotations(ing *networkingv1.Ingress) []nginxAnnotationProblem {
+	var out []nginxAnnotationProblem
+	keys := make([]string, 0, len(ing.Annotations))
+	for k := range ing.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, nginxAnnotationPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, nginxAnnotationPrefix)
+		value := ing.Annotations[key]
+		shown := util.RedactAnnotation(key, value)
+		kind, known := nginxKnownAnnotations[name]
+		if !known {
+			out = append(out, nginxAnnotationProblem{"WARNING", fmt.Sprintf("unknown annotation %s (typo? it will be ignored)", key), ""})
+			continue
+		}
+		switch kind {
+		case nginxAnnotationBool:
+			if value != "true" && value != "false" {
+				out = append(out, nginxAnnotationProblem{"WARNING", fmt.Sprintf("%s=%q must be \"true\" or \"false\"", name, shown), ""})
+			}
+		case nginxAnnotationInt:
+			if _, err := strconv.Atoi(value); err != nil {
+				out = append(out, nginxAnnotationProblem{"WARNING", fmt.Sprintf("%s=%q must be an integer (no units)", name, shown), ""})
+			}
+		case nginxAnnotationSize:
+			if !nginxSizeRegexp.MatchString(value) {
+				out = append(out, nginxAnnotationProblem{"WARNING", fmt.Sprintf("%s=%q is not a valid size (e.g. 8m, 512k)", name, shown), ""})
+			}
+		case nginxAnnotationURL:
+			if u, err := url.Parse(value); err != nil || u.Scheme == "" {
+				out = append(out, nginxAnnotationProblem{"WARNING", fmt.Sprintf("%s=%q is not an absolute URL", name, shown), ""})
+			}
+		case nginxAnnotationSnippet:
+			out = append(out, nginxAnnotationProblem{"WARNING", fmt.Sprintf("%s is used — snippets are disabled by default since ingress-nginx 1.9 and allow config injection", name),
+				"Replace snippet annotations with dedicated annotations or controller ConfigMap settings"})
+		}
+		if name == "backend-protocol" && !nginxBackendProtocols[strings.ToUpper(value)] {
+			out = append(out, nginxAnnotationProblem{"WARNING", fmt.Sprintf("backend-protocol=%q is not one of HTTP, HTTPS, GRPC, GRPCS, AJP, FCGI", shown), ""})
+		}
+		if name == "canary-weight" {
+			if w, err := strconv.Atoi(value); err == nil && (w < 0 || w > 100) && ing.Annotations[nginxAnnotationPrefix+"canary-weight-total"] == "" {
+				out = append(out, nginxAnnotationProblem{"WARNING", fmt.Sprintf("canary-weight=%d is outside 0-100", w), ""})
+			}
+		}
+	}
+
+	if target, ok := ing.Annotations[nginxAnnotationPrefix+"rewrite-target"]; ok && strings.Contains(target, "$") &&
+		ing.Annotations[nginxAnnotationPrefix+"use-regex"] != "true" && !ingressPathsHaveCaptureGroups(ing) {
+		out = append(out, nginxAnnotationProblem{"WARNING", fmt.Sprintf("rewrite-target %q references capture groups but no path defines one", util.RedactAnnotation(nginxAnnotationPrefix+"rewrite-target", target)),
+			"Add a regex capture group to the Ingress path (e.g. /api(/|$)(.*)) when using rewrite-target with $N"})
+	}
+	return out
+}
+
+// ingressPathsHaveCaptureGroups reports whether any path in the Ingress contains a regex group.
+func ingressPathsHaveCaptureGroups(ing *networkingv1.Ingress) bool {
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, p := range rule.HTTP.Paths {
+			if strings.Contains(p.Path, "(") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keyCount is a key with its occurrence count.
+type keyCount struct {
+	key   string
+	count int
+}
+
+// topCounts returns the n most frequent keys, highest first.
+func topCounts(m map[string]int, n int) []keyCount {
+	out := make([]keyCount, 0, len(m))
+	for k, v := range m {
+		out = append(out, keyCount{k, v})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].count != out[j].count {
+			return out[i].count > out[j].count
+		}
+		return out[i].key < out[j].key
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
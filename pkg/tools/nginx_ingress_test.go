@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAnalyzeNginxLogs(t *testing.T) {
+	logs := strings.Join([]string{
+		`I0101 00:00:00.000000 7 controller.go:190] "Backend successfully reloaded"`,
+		`E0101 00:00:01.000000 7 controller.go:205] Unexpected failure reloading the backend: exit status 1`,
+		`10.0.0.1 - - [01/Jan/2024:00:00:02 +0000] "GET /api/users?id=1 HTTP/1.1" 502 150 "-" "curl/8.0" 80 0.001 [default-api-80] [] - - - - abc`,
+		`10.0.0.1 - - [01/Jan/2024:00:00:03 +0000] "GET /api/users HTTP/1.1" 503 150 "-" "curl/8.0" 80 0.001 [default-api-80] [] - - - - abc`,
+		`10.0.0.1 - - [01/Jan/2024:00:00:04 +0000] "POST /login HTTP/2.0" 401 10 "-" "curl/8.0" 80 0.001 [default-web-80] [] - - - - abc`,
+		`10.0.0.1 - - [01/Jan/2024:00:00:05 +0000] "GET / HTTP/1.1" 200 10 "-" "curl/8.0" 80 0.001 [default-web-80] [] - - - - abc`,
+	}, "\n")
+
+	stats := nginxLogStats{StatusCounts: map[string]int{}, ErrorPaths: map[string]int{}}
+	analyzeNginxLogs(logs, &stats)
+
+	if stats.ReloadSuccess != 1 {
+		t.Errorf("ReloadSuccess = %d, want 1", stats.ReloadSuccess)
+	}
+	if len(stats.ReloadFailures) != 1 {
+		t.Errorf("ReloadFailures = %d, want 1", len(stats.ReloadFailures))
+	}
+	if stats.StatusCounts["5xx"] != 2 || stats.StatusCounts["4xx"] != 1 || stats.StatusCounts["2xx"] != 1 {
+		t.Errorf("unexpected status counts: %v", stats.StatusCounts)
+	}
+	if stats.ErrorPaths["/api/users"] != 2 {
+		t.Errorf("expected query strings stripped from 5xx paths, got %v", stats.ErrorPaths)
+	}
+}
+
+func TestValidateNginxAnnotations(t *testing.T) {
+	ing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "web",
+			Annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/ssl-redirect":          "yes",
+				"nginx.ingress.kubernetes.io/proxy-read-timeout":    "60s",
+				"nginx.ingress.kubernetes.io/proxy-body-size":       "10mb",
+				"nginx.ingress.kubernetes.io/backend-protocol":      "TCP",
+				"nginx.ingress.kubernetes.io/rewrite-taget":         "/",
+				"nginx.ingress.kubernetes.io/configuration-snippet": "more_set_headers \"X: y\";",
+				"nginx.ingress.kubernetes.io/canary-weight":         "150",
+				"nginx.ingress.kubernetes.io/auth-url":              "://user:s3cret@auth.example.com/check",
+				"kubernetes.io/ingress.class":                       "nginx",
+			},
+		},
+	}
+
+	problems := validateNginxAnnotations(ing)
+	var messages []string
+	for _, p := range problems {
+		messages = append(messages, p.message)
+	}
+	joined := strings.Join(messages, "\n")
+	for _, want := range []string{"ssl-redirect", "proxy-read-timeout", "proxy-body-size", "backend-protocol", "rewrite-taget", "configuration-snippet", "canary-weight", "auth-url"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected a problem mentioning %q, got:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "s3cret") {
+		t.Errorf("expected the auth-url credentials to be redacted, got:\n%s", joined)
+	}
+
+	valid := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ok",
+			Annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/ssl-redirect":       "true",
+				"nginx.ingress.kubernetes.io/proxy-read-timeout": "60",
+				"nginx.ingress.kubernetes.io/proxy-body-size":    "8m",
+				"nginx.ingress.kubernetes.io/backend-protocol":   "GRPC",
+			},
+		},
+	}
+	if p := validateNginxAnnotations(valid); len(p) != 0 {
+		t.Errorf("expected no problems for valid annotations, got %+v", p)
+	}
+}
@@ -0,0 +1,331 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// nodeDiagnosisDefaultSince is how far back diagnose_node reads events and
+	// condition transitions by default.
+	nodeDiagnosisDefaultSince = time.Hour
+	// nodeDiagnosisMaxPods caps the unhealthy pods listed by diagnose_node.
+	nodeDiagnosisMaxPods = 15
+	// nodeDiagnosisMaxEvents caps the node events listed by diagnose_node.
+	nodeDiagnosisMaxEvents = 30
+	// nodeUsageWarningPercent is the actual CPU or memory usage of allocatable
+	// above which diagnose_node warns.
+	nodeUsageWarningPercent = 90
+)
+
+type diagnoseNodeInput struct {
+	Name           string `json:"name" jsonschema:"required,Node name"`
+	Since          string `json:"since,omitempty" jsonschema:"How far back to read node events and condition transitions (default 1h)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+	DetailLevel    string `json:"detail_level,omitempty" jsonschema:"How much to return: summary (about 10 lines: status, top findings, next action), standard (the report without Mermaid diagrams), or full (everything, the default)"`
+}
+
+func registerNodeDiagnosisTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// diagnose_node
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "diagnose_node",
+		Description: "Diagnose a single node: conditions with their recent transitions (including node-problem-detector conditions such as KernelDeadlock, " +
+			"ReadonlyFilesystem, and FrequentContainerdRestart), cordon and taints, requests and actual usage against allocatable, unhealthy pods on the node, " +
+			"and recent node events including kernel and runtime problems. Use this when pods fail on one node or a node goes NotReady.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input diagnoseNodeInput) (*mcp.CallToolResult, any, error) {
+		detail, err := util.ParseDetailLevel(input.DetailLevel)
+		if err != nil {
+			return util.ErrorResult("%v", err), nil, nil
+		}
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if input.Name == "" {
+			return util.ErrorResult("name is required"), nil, nil
+		}
+		since := nodeDiagnosisDefaultSince
+		if input.Since != "" {
+			d, err := time.ParseDuration(input.Since)
+			if err != nil || d <= 0 {
+				return util.ErrorResult("invalid since %q: use a duration like 30m or 6h", input.Since), nil, nil
+			}
+			since = d
+		}
+		windowStart := time.Now().Add(-since)
+
+		node, err := client.GetNode(ctx, input.Name)
+		if err != nil {
+			return util.HandleK8sError(fmt.Sprintf("getting node %s", input.Name), err), nil, nil
+		}
+		pods, err := client.ListPods(ctx, "", metav1.ListOptions{FieldSelector: "spec.nodeName=" + node.Name})
+		if err != nil {
+			return util.HandleK8sError(fmt.Sprintf("listing pods on node %s", node.Name), err), nil, nil
+		}
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Node Diagnosis: %s", node.Name)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Status", nodeStatus(node)) + "\n")
+		sb.WriteString(util.FormatKeyValue("Roles", nodeRoles(node)) + "\n")
+		sb.WriteString(util.FormatKeyValue("Age", util.FormatAge(node.CreationTimestamp.Time)) + "\n")
+		sb.WriteString(util.FormatKeyValue("Kubelet", node.Status.NodeInfo.KubeletVersion) + "\n")
+		sb.WriteString(util.FormatKeyValue("Container Runtime", node.Status.NodeInfo.ContainerRuntimeVersion) + "\n")
+		sb.WriteString(util.FormatKeyValue("Kernel", node.Status.NodeInfo.KernelVersion) + "\n")
+		schedulable := "yes"
+		if node.Spec.Unschedulable {
+			schedulable = "no (cordoned)"
+			findingLines = append(findingLines, findings.Format("KD-NODE-003", "INFO", fmt.Sprintf("Node '%s' is cordoned; no new pods are scheduled on it.", node.Name)))
+		}
+		sb.WriteString(util.FormatKeyValue("Schedulable", schedulable) + "\n")
+		if len(node.Spec.Taints) > 0 {
+			taints := make([]string, 0, len(node.Spec.Taints))
+			for _, t := range node.Spec.Taints {
+				taints = append(taints, fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect))
+			}
+			sb.WriteString(util.FormatKeyValue("Taints", strings.Join(taints, ", ")) + "\n")
+		}
+
+		// Conditions
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("CONDITIONS"))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatTable([]string{"TYPE", "STATUS", "SINCE", "REASON", "MESSAGE"}, nodeConditionRows(node, windowStart)))
+		if hasCondition(node, corev1.NodeReady) && !hasProblemDetectorConditions(node) {
+			sb.WriteString("  (no node-problem-detector conditions; kernel and runtime problems are only visible as events if it is installed)\n")
+		}
+		for _, f := range nodeConditionFindings(node, windowStart) {
+			if f.Severity == "INFO" {
+				continue // transitions are marked "(changed)" in the table
+			}
+			findingLines = append(findingLines, f.String())
+		}
+		for _, cond := range node.Status.Conditions {
+			if cond.Status != corev1.ConditionTrue || cond.Type == corev1.NodeReady {
+				continue
+			}
+			switch {
+			case cond.Type == corev1.NodeMemoryPressure || cond.Type == corev1.NodeDiskPressure || cond.Type == corev1.NodePIDPressure:
+				actions = append(actions, fmt.Sprintf("Find what consumes the resource behind %s on %s and evict or resize it; the kubelet keeps evicting pods until it clears.", cond.Type, node.Name))
+				steps = append(steps, nextStep("top_resource_consumers", fmt.Sprintf("Node has %s", cond.Type), "resource", triagePressureResource(cond.Type)))
+			case nodeProblemDetectorConditions[cond.Type] != "":
+				actions = append(actions, fmt.Sprintf("Cordon and drain %s, then reboot or replace it: %s persists until the node is repaired.", node.Name, cond.Type))
+			}
+		}
+		if nodeStatus(node) != "Ready" {
+			actions = append(actions, fmt.Sprintf("Check kubelet and container runtime health on %s (node shell or cloud console); reimage or replace the node if it does not recover.", node.Name))
+		}
+
+		// Resources
+		usage, metricsErr := nodeUsage(ctx, client, node.Name)
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("RESOURCES"))
+		sb.WriteString("\n")
+		rows, resourceFindings := nodeResourceRows(node, pods, usage)
+		sb.WriteString(util.FormatTable([]string{"RESOURCE", "ALLOCATABLE", "REQUESTED", "USED"}, rows))
+		if metricsErr != nil {
+			sb.WriteString("  (actual usage unavailable: metrics-server not reachable; run check_metrics_server to find out why)\n")
+		}
+		findingLines = append(findingLines, resourceFindings...)
+		if len(resourceFindings) > 0 {
+			steps = append(steps, nextStep("analyze_node_capacity", "compare this node's load with the rest of the cluster"))
+		}
+
+		// Pods
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("PODS"))
+		sb.WriteString("\n")
+		phases := make(map[string]int)
+		var unhealthy []corev1.Pod
+		for _, p := range pods {
+			phases[string(p.Status.Phase)]++
+			if p.Status.Phase != corev1.PodSucceeded && !isPodHealthy(&p) {
+				unhealthy = append(unhealthy, p)
+			}
+		}
+		var counts []string
+		for _, phase := range sortedKeysInt(phases) {
+			counts = append(counts, fmt.Sprintf("%s=%d", phase, phases[phase]))
+		}
+		sb.WriteString(fmt.Sprintf("  %d pods (%s), %d unhealthy\n", len(pods), strings.Join(counts, ", "), len(unhealthy)))
+		if len(unhealthy) > 0 {
+			sort.Slice(unhealthy, func(i, j int) bool {
+				return unhealthy[i].Namespace+"/"+unhealthy[i].Name < unhealthy[j].Namespace+"/"+unhealthy[j].Name
+			})
+			var podRows [][]string
+			for i, p := range unhealthy {
+				if i == nodeDiagnosisMaxPods {
+					break
+				}
+				_, _, restarts := podContainerSummary(&p)
+				podRows = append(podRows, []string{p.Namespace, truncateName(p.Name, 50), podPhaseReason(&p), fmt.Sprintf("%d", restarts)})
+			}
+			sb.WriteString(util.FormatTable([]string{"NAMESPACE", "POD", "STATUS", "RESTARTS"}, podRows))
+			if len(unhealthy) > nodeDiagnosisMaxPods {
+				sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(unhealthy)-nodeDiagnosisMaxPods))
+			}
+			severity := "WARNING"
+			if len(pods) > 0 && len(unhealthy)*2 >= len(pods) {
+				severity = "CRITICAL"
+			}
+			findingLines = append(findingLines, findings.Format("KD-POD-010", severity, fmt.Sprintf("%d of %d pods on '%s' are unhealthy.", len(unhealthy), len(pods), node.Name)))
+			steps = append(steps, nextStep("diagnose_pod", "explain one of the unhealthy pods on this node", "namespace", unhealthy[0].Namespace, "name", unhealthy[0].Name))
+		}
+
+		// Events
+		events, err := client.ListEventsMatching(ctx, "", metav1.ListOptions{
+			FieldSelector: "involvedObject.kind=Node,involvedObject.name=" + node.Name,
+		}, k8s.EventFilter{Since: windowStart, Limit: nodeDiagnosisMaxEvents})
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader(fmt.Sprintf("EVENTS (last %s)", since)))
+		sb.WriteString("\n")
+		if err != nil {
+			sb.WriteString(fmt.Sprintf("  (could not list node events: %v)\n", err))
+		} else if len(events) == 0 {
+			sb.WriteString("  No node events in the window.\n")
+		}
+		problemEvents := make(map[string]int)
+		for _, e := range events {
+			line := fmt.Sprintf("  %-8s %s ago %s: %s", e.Type, util.FormatAge(k8s.EventTime(&e)), e.Reason, truncateName(e.Message, 160))
+			if meaning, known := nodeProblemEventReasons[e.Reason]; known {
+				line += fmt.Sprintf(" [%s]", meaning)
+				problemEvents[e.Reason] += int(max(e.Count, 1))
+			}
+			if e.Count > 1 {
+				line += fmt.Sprintf(" (x%d)", e.Count)
+			}
+			sb.WriteString(line + "\n")
+		}
+		for _, reason := range sortedKeysInt(problemEvents) {
+			findingLines = append(findingLines, findings.Format("KD-NODE-004", "WARNING", fmt.Sprintf("%s reported %d times in the last %s (%s).", reason, problemEvents[reason], since, nodeProblemEventReasons[reason])))
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString(fmt.Sprintf("  Node '%s' is healthy: no pressure, problem conditions, or problem events in the last %s.\n", node.Name, since))
+		}
+		for _, f := range dedupe(findingLines) {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(finishReport(sb.String(), detail), steps), nil, nil
+	})
+}
+
+// nodeConditionRows renders node conditions, marking transitions since windowStart.
+func nodeConditionRows(node *corev1.Node, windowStart time.Time) [][]string {
+	rows := make([][]string, 0, len(node.Status.Conditions))
+	for _, cond := range node.Status.Conditions {
+		since := "-"
+		if !cond.LastTransitionTime.IsZero() {
+			since = util.FormatAge(cond.LastTransitionTime.Time)
+			if cond.LastTransitionTime.After(windowStart) {
+				since += " (changed)"
+			}
+		}
+		rows = append(rows, []string{string(cond.Type), string(cond.Status), since, cond.Reason, truncateName(cond.Message, 80)})
+	}
+	return rows
+}
+
+func hasCondition(node *corev1.Node, t corev1.NodeConditionType) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+func hasProblemDetectorConditions(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if _, ok := nodeProblemDetectorConditions[cond.Type]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeMetricsUsage is a node's actual usage reported by metrics-server.
+type nodeMetricsUsage struct {
+	CPUMillis int64
+	MemBytes  int64
+}
+
+// nodeUsage returns a node's actual usage, or nil when it has no metrics.
+func nodeUsage(ctx context.Context, client *k8s.ClusterClient, name string) (*nodeMetricsUsage, error) {
+	metrics, err := client.GetNodeMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range metrics {
+		if m.Name == name {
+			return &nodeMetricsUsage{CPUMillis: m.Usage.Cpu().MilliValue(), MemBytes: m.Usage.Memory().Value()}, nil
+		}
+	}
+	return nil, nil
+}
+
+// nodeResourceRows compares a node's allocatable CPU, memory, and pod slots
+// with the requests of its pods and actual usage, and flags overload.
+func nodeResourceRows(node *corev1.Node, pods []corev1.Pod, usage *nodeMetricsUsage) ([][]string, []string) {
+	var cpuReq, memReq int64
+	active := 0
+	for i := range pods {
+		if pods[i].Status.Phase == corev1.PodSucceeded || pods[i].Status.Phase == corev1.PodFailed {
+			continue
+		}
+		active++
+		cpu, mem := podRequests(&pods[i])
+		cpuReq += cpu
+		memReq += mem
+	}
+	cpuAlloc := node.Status.Allocatable.Cpu().MilliValue()
+	memAlloc := node.Status.Allocatable.Memory().Value()
+	podAlloc := node.Status.Allocatable.Pods().Value()
+
+	percent := func(v, of int64) string {
+		if of <= 0 {
+			return ""
+		}
+		return fmt.Sprintf(" (%d%%)", v*100/of)
+	}
+	cpuUsed, memUsed := "-", "-"
+	var findingLines []string
+	if usage != nil {
+		cpuUsed = fmt.Sprintf("%dm%s", usage.CPUMillis, percent(usage.CPUMillis, cpuAlloc))
+		memUsed = formatBytes(usage.MemBytes) + percent(usage.MemBytes, memAlloc)
+		if cpuAlloc > 0 && usage.CPUMillis*100/cpuAlloc >= nodeUsageWarningPercent {
+			findingLines = append(findingLines, findings.Format("KD-NODE-005", "WARNING", fmt.Sprintf("Node '%s' uses %d%% of its allocatable CPU; pods are being throttled.", node.Name, usage.CPUMillis*100/cpuAlloc)))
+		}
+		if memAlloc > 0 && usage.MemBytes*100/memAlloc >= nodeUsageWarningPercent {
+			findingLines = append(findingLines, findings.Format("KD-NODE-005", "WARNING", fmt.Sprintf("Node '%s' uses %d%% of its allocatable memory; evictions and OOM kills are likely.", node.Name, usage.MemBytes*100/memAlloc)))
+		}
+	}
+	if podAlloc > 0 && int64(active) >= podAlloc {
+		findingLines = append(findingLines, findings.Format("KD-NODE-005", "WARNING", fmt.Sprintf("Node '%s' runs %d pods, its maximum; new pods cannot be scheduled here.", node.Name, active)))
+	}
+
+	rows := [][]string{
+		{"cpu", fmt.Sprintf("%dm", cpuAlloc), fmt.Sprintf("%dm%s", cpuReq, percent(cpuReq, cpuAlloc)), cpuUsed},
+		{"memory", formatBytes(memAlloc), formatBytes(memReq) + percent(memReq, memAlloc), memUsed},
+		{"pods", fmt.Sprintf("%d", podAlloc), fmt.Sprintf("%d%s", active, percent(int64(active), podAlloc)), "-"},
+	}
+	return rows, findingLines
+}
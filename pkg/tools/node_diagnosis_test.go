@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeConditionFindingsProblemDetector(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			{Type: "KernelDeadlock", Status: corev1.ConditionTrue, Message: "task docker:7 blocked for more than 300 seconds"},
+			{Type: "FrequentContainerdRestart", Status: corev1.ConditionTrue, Message: "containerd restarted 5 times"},
+		}},
+	}
+
+	findings := nodeConditionFindings(node, time.Now().Add(-time.Hour))
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2: %+v", len(findings), findings)
+	}
+	if findings[0].Severity != "CRITICAL" || findings[0].ID != "KD-NODE-004" || !strings.Contains(findings[0].Message, "node-problem-detector") {
+		t.Errorf("KernelDeadlock finding = %+v, want CRITICAL with the node-problem-detector meaning", findings[0])
+	}
+	if findings[1].Severity != "WARNING" {
+		t.Errorf("FrequentContainerdRestart severity = %s, want WARNING", findings[1].Severity)
+	}
+}
+
+func TestNodeConditionRowsMarksTransitions(t *testing.T) {
+	now := time.Now()
+	node := &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+		{Type: corev1.NodeReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(-48 * time.Hour))},
+		{Type: "ReadonlyFilesystem", Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(-10 * time.Minute))},
+	}}}
+
+	rows := nodeConditionRows(node, now.Add(-time.Hour))
+	if strings.Contains(rows[0][2], "(changed)") {
+		t.Errorf("Ready row since = %q, want no transition marker", rows[0][2])
+	}
+	if !strings.Contains(rows[1][2], "(changed)") {
+		t.Errorf("ReadonlyFilesystem row since = %q, want a transition marker", rows[1][2])
+	}
+}
+
+func TestNodeResourceRows(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+			corev1.ResourcePods:   resource.MustParse("2"),
+		}},
+	}
+	pod := func(name string, phase corev1.PodPhase) corev1.Pod {
+		p := withRequests(testPod("", name, "", nil), "500m", "1Gi")
+		p.Status.Phase = phase
+		return p
+	}
+	pods := []corev1.Pod{pod("a", corev1.PodRunning), pod("b", corev1.PodRunning), pod("done", corev1.PodSucceeded)}
+
+	rows, findings := nodeResourceRows(node, pods, &nodeMetricsUsage{CPUMillis: 1900, MemBytes: 1 << 30})
+	if rows[0][2] != "1000m (50%)" {
+		t.Errorf("cpu requested = %q, want 1000m (50%%), excluding completed pods", rows[0][2])
+	}
+	if rows[0][3] != "1900m (95%)" {
+		t.Errorf("cpu used = %q, want 1900m (95%%)", rows[0][3])
+	}
+	joined := strings.Join(findings, "\n")
+	if !strings.Contains(joined, "95% of its allocatable CPU") {
+		t.Errorf("findings %q, want a CPU usage warning", joined)
+	}
+	if strings.Contains(joined, "allocatable memory") {
+		t.Errorf("findings %q, want no memory warning at 25%% usage", joined)
+	}
+	if !strings.Contains(joined, "runs 2 pods, its maximum") {
+		t.Errorf("findings %q, want a pod capacity warning", joined)
+	}
+
+	_, findings = nodeResourceRows(node, pods[:1], nil)
+	if len(findings) != 0 {
+		t.Errorf("findings without metrics = %v, want none", findings)
+	}
+}
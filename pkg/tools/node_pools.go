@@ -0,0 +1,371 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// nodePoolLowUtilization is the request percentage below which a pool is
+	// considered over-provisioned.
+	nodePoolLowUtilization = 40.0
+
+	// nodePoolTargetUtilization is the request percentage used when estimating
+	// how many nodes a pool actually needs.
+	nodePoolTargetUtilization = 0.75
+)
+
+// nodePoolLabels are the labels that name a node's pool, by provider.
+var nodePoolLabels = []string{
+	"kubernetes.azure.com/agentpool",
+	"agentpool",
+	"eks.amazonaws.com/nodegroup",
+	"cloud.google.com/gke-nodepool",
+	"karpenter.sh/nodepool",
+	"node.kubernetes.io/pool",
+}
+
+type analyzeNodePoolsInput struct {
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// nodePool aggregates capacity and usage for the nodes in one pool.
+type nodePool struct {
+	Name          string
+	Mode          string
+	Nodes         int
+	Ready         int
+	InstanceTypes map[string]bool
+	CapacityTypes map[string]bool
+	Zones         map[string]int
+	Versions      map[string]bool
+	AllocCPU      int64
+	AllocMem      int64
+	ReqCPU        int64
+	ReqMem        int64
+	UseCPU        int64
+	UseMem        int64
+	HasMetrics    bool
+}
+
+func registerNodePoolTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// analyze_node_pools
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "analyze_node_pools",
+		Description: "Group nodes by node pool (AKS agentpool, EKS nodegroup, GKE nodepool, Karpenter NodePool) and compare request/usage utilization, spot vs on-demand capacity, zone distribution, and kubelet version skew. Surfaces pools that could be downsized or consolidated.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeNodePoolsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing nodes", err), nil, nil
+		}
+		pods, err := client.ListPods(ctx, "", metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		nodeMetrics, _ := client.GetNodeMetrics(ctx)
+
+		pools := groupNodePools(nodes, pods)
+		poolByNode := make(map[string]string, len(nodes))
+		for i := range nodes {
+			poolByNode[nodes[i].Name] = nodePoolName(&nodes[i])
+		}
+		for _, m := range nodeMetrics {
+			name, ok := poolByNode[m.Name]
+			if !ok {
+				continue
+			}
+			p := pools[name]
+			p.UseCPU += m.Usage.Cpu().MilliValue()
+			p.UseMem += m.Usage.Memory().Value()
+			p.HasMetrics = true
+		}
+		names := make([]string, 0, len(pools))
+		for name := range pools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Node Pool Analysis"))
+		sb.WriteString("\n\n")
+
+		headers := []string{"POOL", "MODE", "NODES", "VM SIZE", "CAPACITY", "ZONES", "KUBELET", "CPU REQ", "MEM REQ", "CPU USE", "MEM USE"}
+		rows := make([][]string, 0, len(pools))
+		for _, name := range names {
+			p := pools[name]
+			cpuUse, memUse := "-", "-"
+			if p.HasMetrics {
+				cpuUse = fmt.Sprintf("%.0f%%", percent(p.UseCPU, p.AllocCPU))
+				memUse = fmt.Sprintf("%.0f%%", percent(p.UseMem, p.AllocMem))
+			}
+			rows = append(rows, []string{
+				name,
+				p.Mode,
+				fmt.Sprintf("%d/%d", p.Ready, p.Nodes),
+				strings.Join(sortedKeys(p.InstanceTypes), ","),
+				strings.Join(sortedKeys(p.CapacityTypes), ","),
+				formatZoneCounts(p.Zones),
+				strings.Join(sortedKeys(p.Versions), ","),
+				fmt.Sprintf("%.0f%%", percent(p.ReqCPU, p.AllocCPU)),
+				fmt.Sprintf("%.0f%%", percent(p.ReqMem, p.AllocMem)),
+				cpuUse,
+				memUse,
+			})
+		}
+		sb.WriteString(util.FormatTable(headers, rows))
+
+		// --- Findings ---
+		sb.WriteString("\nFINDINGS:\n")
+		findingCount := 0
+		var actions []string
+		finding := func(rule, sev, msg string) {
+			sb.WriteString(findings.Format(rule, sev, msg))
+			sb.WriteString("\n")
+			findingCount++
+		}
+
+		newest := newestKubeletVersion(nodes)
+		for _, name := range names {
+			p := pools[name]
+			if p.Ready < p.Nodes {
+				finding("KD-NODE-001", "CRITICAL", fmt.Sprintf("Pool %s: %d of %d node(s) not Ready", name, p.Nodes-p.Ready, p.Nodes))
+			}
+			if len(p.Versions) > 1 {
+				finding("KD-NODE-009", "WARNING", fmt.Sprintf("Pool %s runs mixed kubelet versions (%s) — an upgrade may be stuck", name, strings.Join(sortedKeys(p.Versions), ", ")))
+				actions = append(actions, fmt.Sprintf("Check the upgrade status of pool %s", name))
+			}
+			if skew := kubeletMinorSkew(p.Versions, newest); skew > 0 {
+				sev := "INFO"
+				if skew >= 2 {
+					sev = "WARNING"
+				}
+				finding("KD-NODE-006", sev, fmt.Sprintf("Pool %s is %d minor version(s) behind the newest kubelet (%s)", name, skew, newest))
+				actions = append(actions, fmt.Sprintf("Upgrade pool %s to %s", name, newest))
+			}
+			if p.Nodes >= 2 && len(p.Zones) == 1 {
+				finding("KD-NODE-009", "WARNING", fmt.Sprintf("Pool %s has all %d nodes in one zone — a zone outage takes the whole pool down", name, p.Nodes))
+				actions = append(actions, fmt.Sprintf("Spread pool %s across availability zones", name))
+			}
+			if p.Mode == "system" && p.CapacityTypes["spot"] {
+				finding("KD-NODE-009", "WARNING", fmt.Sprintf("System pool %s uses spot capacity — evictions can take down cluster add-ons", name))
+			}
+			if needed := nodePoolNodesNeeded(p); p.Nodes > 1 && needed < p.Nodes &&
+				percent(p.ReqCPU, p.AllocCPU) < nodePoolLowUtilization && percent(p.ReqMem, p.AllocMem) < nodePoolLowUtilization {
+				finding("KD-RES-004", "INFO", fmt.Sprintf("Pool %s is under-utilized (CPU %.0f%%, memory %.0f%% requested) — requests fit on ~%d of %d nodes",
+					name, percent(p.ReqCPU, p.AllocCPU), percent(p.ReqMem, p.AllocMem), needed, p.Nodes))
+				actions = append(actions, fmt.Sprintf("Downsize pool %s toward %d node(s), or lower its autoscaler minimum", name, needed))
+			}
+		}
+		for _, pair := range consolidationCandidates(pools) {
+			finding("KD-RES-004", "INFO", fmt.Sprintf("Pools %s and %s share VM size, capacity type, and mode and are both under-utilized — consider consolidating", pair[0], pair[1]))
+			actions = append(actions, fmt.Sprintf("Move workloads from %s to %s and delete the empty pool", pair[1], pair[0]))
+		}
+		if findingCount == 0 {
+			sb.WriteString("  No node pool issues found.\n")
+		}
+
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Summary"))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("  %d pool(s), %d node(s). %d finding(s).\n", len(pools), len(nodes), findingCount))
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// nodePoolName returns the pool a node belongs to, or "(none)".
+func nodePoolName(n *corev1.Node) string {
+	for _, l := range nodePoolLabels {
+		if v := n.Labels[l]; v != "" {
+			return v
+		}
+	}
+	return "(none)"
+}
+
+// nodePoolMode returns the pool mode (AKS "system" or "user"), or "" when unknown.
+func nodePoolMode(n *corev1.Node) string {
+	return n.Labels["kubernetes.azure.com/mode"]
+}
+
+// nodeCapacityType returns "spot" or "on-demand" from provider labels.
+func nodeCapacityType(n *corev1.Node) string {
+	l := n.Labels
+	switch {
+	case strings.EqualFold(l["kubernetes.azure.com/scalesetpriority"], "spot"),
+		strings.EqualFold(l["eks.amazonaws.com/capacityType"], "SPOT"),
+		strings.EqualFold(l["karpenter.sh/capacity-type"], "spot"),
+		l["cloud.google.com/gke-spot"] == "true",
+		l["cloud.google.com/gke-preemptible"] == "true":
+		return "spot"
+	}
+	return "on-demand"
+}
+
+// groupNodePools aggregates nodes and the requests of pods scheduled on them by pool.
+func groupNodePools(nodes []corev1.Node, pods []corev1.Pod) map[string]*nodePool {
+	pools := make(map[string]*nodePool)
+	nodeToPool := make(map[string]*nodePool)
+	for i := range nodes {
+		n := &nodes[i]
+		name := nodePoolName(n)
+		p, ok := pools[name]
+		if !ok {
+			p = &nodePool{
+				Name:          name,
+				Mode:          "-",
+				InstanceTypes: make(map[string]bool),
+				CapacityTypes: make(map[string]bool),
+				Zones:         make(map[string]int),
+				Versions:      make(map[string]bool),
+			}
+			pools[name] = p
+		}
+		nodeToPool[n.Name] = p
+		p.Nodes++
+		if nodeStatus(n) == "Ready" {
+			p.Ready++
+		}
+		if mode := nodePoolMode(n); mode != "" {
+			p.Mode = mode
+		}
+		p.InstanceTypes[nodeInstanceType(n)] = true
+		p.CapacityTypes[nodeCapacityType(n)] = true
+		zone := n.Labels["topology.kubernetes.io/zone"]
+		if zone == "" {
+			zone = "none"
+		}
+		p.Zones[zone]++
+		p.Versions[n.Status.NodeInfo.KubeletVersion] = true
+		p.AllocCPU += n.Status.Allocatable.Cpu().MilliValue()
+		p.AllocMem += n.Status.Allocatable.Memory().Value()
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		p, ok := nodeToPool[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		cpu, mem := podRequests(pod)
+		p.ReqCPU += cpu
+		p.ReqMem += mem
+	}
+	return pools
+}
+
+// nodePoolNodesNeeded estimates how many nodes the pool's requests need at the
+// target utilization, assuming equally sized nodes. It never returns less than 1.
+func nodePoolNodesNeeded(p *nodePool) int {
+	if p.Nodes == 0 || p.AllocCPU == 0 || p.AllocMem == 0 {
+		return p.Nodes
+	}
+	perNodeCPU := float64(p.AllocCPU) / float64(p.Nodes) * nodePoolTargetUtilization
+	perNodeMem := float64(p.AllocMem) / float64(p.Nodes) * nodePoolTargetUtilization
+	needed := int(math.Ceil(math.Max(float64(p.ReqCPU)/perNodeCPU, float64(p.ReqMem)/perNodeMem)))
+	return max(needed, 1)
+}
+
+// consolidationCandidates returns pairs of under-utilized pools with the same
+// VM size, capacity type, and mode.
+func consolidationCandidates(pools map[string]*nodePool) [][2]string {
+	byShape := make(map[string][]string)
+	for name, p := range pools {
+		if name == "(none)" || percent(p.ReqCPU, p.AllocCPU) >= nodePoolLowUtilization || percent(p.ReqMem, p.AllocMem) >= nodePoolLowUtilization {
+			continue
+		}
+		shape := strings.Join([]string{
+			strings.Join(sortedKeys(p.InstanceTypes), ","),
+			strings.Join(sortedKeys(p.CapacityTypes), ","),
+			p.Mode,
+		}, "|")
+		byShape[shape] = append(byShape[shape], name)
+	}
+	var pairs [][2]string
+	for _, names := range byShape {
+		sort.Strings(names)
+		for i := 1; i < len(names); i++ {
+			pairs = append(pairs, [2]string{names[0], names[i]})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i][0]+pairs[i][1] < pairs[j][0]+pairs[j][1] })
+	return pairs
+}
+
+// newestKubeletVersion returns the highest kubelet version among the nodes.
+func newestKubeletVersion(nodes []corev1.Node) string {
+	var newest *version.Version
+	raw := ""
+	for _, n := range nodes {
+		v, err := version.ParseGeneric(n.Status.NodeInfo.KubeletVersion)
+		if err != nil {
+			continue
+		}
+		if newest == nil || newest.LessThan(v) {
+			newest = v
+			raw = n.Status.NodeInfo.KubeletVersion
+		}
+	}
+	return raw
+}
+
+// kubeletMinorSkew returns how many minor versions the oldest of versions is behind newest.
+func kubeletMinorSkew(versions map[string]bool, newest string) int {
+	nv, err := version.ParseGeneric(newest)
+	if err != nil {
+		return 0
+	}
+	skew := 0
+	for v := range versions {
+		pv, err := version.ParseGeneric(v)
+		if err != nil || pv.Major() != nv.Major() {
+			continue
+		}
+		skew = max(skew, int(nv.Minor())-int(pv.Minor()))
+	}
+	return skew
+}
+
+// formatZoneCounts renders zone:count pairs sorted by zone.
+func formatZoneCounts(zones map[string]int) string {
+	keys := make([]string, 0, len(zones))
+	for z := range zones {
+		keys = append(keys, z)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, z := range keys {
+		parts = append(parts, fmt.Sprintf("%s:%d", z, zones[z]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// percent returns part as a percentage of whole, or 0 when whole is 0.
+func percent(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func poolNode(name, pool, zone, kubelet string, extra map[string]string) corev1.Node {
+	labels := map[string]string{
+		"kubernetes.azure.com/agentpool":   pool,
+		"topology.kubernetes.io/zone":      zone,
+		"node.kubernetes.io/instance-type": "Standard_D4s_v3",
+	}
+	for k, v := range extra {
+		labels[k] = v
+	}
+	node := testNode(name, "4", "16Gi", labels)
+	node.Status.NodeInfo.KubeletVersion = kubelet
+	return node
+}
+
+func TestGroupNodePools(t *testing.T) {
+	nodes := []corev1.Node{
+		poolNode("a1", "user1", "eastus-1", "v1.30.4", nil),
+		poolNode("a2", "user1", "eastus-1", "v1.30.4", nil),
+		poolNode("a3", "user1", "eastus-1", "v1.30.4", nil),
+		poolNode("s1", "spot1", "eastus-2", "v1.28.9", map[string]string{"kubernetes.azure.com/scalesetpriority": "spot"}),
+	}
+	pods := []corev1.Pod{{
+		Spec: corev1.PodSpec{NodeName: "a1", Containers: []corev1.Container{{
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			}},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}}
+
+	pools := groupNodePools(nodes, pods)
+	if len(pools) != 2 {
+		t.Fatalf("expected 2 pools, got %d", len(pools))
+	}
+	user := pools["user1"]
+	if user.Nodes != 3 || user.Ready != 3 || user.ReqCPU != 1000 || user.AllocCPU != 12000 {
+		t.Errorf("unexpected user1 pool %+v", user)
+	}
+	if !pools["spot1"].CapacityTypes["spot"] {
+		t.Error("expected spot1 to be spot capacity")
+	}
+	if got := nodePoolNodesNeeded(user); got != 1 {
+		t.Errorf("nodePoolNodesNeeded() = %d, want 1", got)
+	}
+
+	newest := newestKubeletVersion(nodes)
+	if newest != "v1.30.4" {
+		t.Errorf("newestKubeletVersion() = %q", newest)
+	}
+	if skew := kubeletMinorSkew(pools["spot1"].Versions, newest); skew != 2 {
+		t.Errorf("kubeletMinorSkew() = %d, want 2", skew)
+	}
+}
+
+func TestConsolidationCandidates(t *testing.T) {
+	nodes := []corev1.Node{
+		poolNode("a1", "user1", "1", "v1.30.0", nil),
+		poolNode("b1", "user2", "1", "v1.30.0", nil),
+		poolNode("c1", "spot", "1", "v1.30.0", map[string]string{"kubernetes.azure.com/scalesetpriority": "spot"}),
+	}
+	pairs := consolidationCandidates(groupNodePools(nodes, nil))
+	if len(pairs) != 1 || pairs[0] != [2]string{"user1", "user2"} {
+		t.Errorf("consolidationCandidates() = %v, want [[user1 user2]]", pairs)
+	}
+}
@@ -14,6 +14,8 @@ import (
 
 type listNodesInput struct {
 	LabelSelector string `json:"label_selector,omitempty" jsonschema:"Label selector filter (e.g. node-role.kubernetes.io/control-plane)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type getNodeDetailInput struct {
@@ -28,10 +30,11 @@ func registerNodeTools(server *mcp.Server, client *k8s.ClusterClient) {
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listNodesInput) (*mcp.CallToolResult, any, error) {
 		opts := util.ListOptions(input.LabelSelector, "")
 
-		nodes, err := client.ListNodes(ctx, opts)
+		page, err := client.ListNodesPage(ctx, util.PageOptions(opts, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing nodes", err), nil, nil
 		}
+		nodes := page.Items
 
 		headers := []string{"NAME", "STATUS", "ROLES", "VERSION", "CPU", "MEMORY", "AGE"}
 		rows := make([][]string, 0, len(nodes))
@@ -52,6 +55,7 @@ func registerNodeTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("nodes", len(nodes))))
+		sb.WriteString(pageFooter("list_nodes", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
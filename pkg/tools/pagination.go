@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pageFooter tells the caller that a list_* response holds only one page and
+// how to fetch the next. It is empty on the last page.
+func pageFooter(tool string, continueToken string, remaining *int64) string {
+	if continueToken == "" {
+		return ""
+	}
+	more := "more items remain"
+	if remaining != nil && *remaining > 0 {
+		more = fmt.Sprintf("about %d more items remain", *remaining)
+	}
+	return fmt.Sprintf("\nTRUNCATED: %s. Call %s again with the same filters and continue_token=%q for the next page.\n", more, tool, continueToken)
+}
+
+// pagePhase is one of several lists a tool pages through in turn, such as
+// RoleBindings and then ClusterRoleBindings. list fetches one page with the
+// given options and reports how many items it returned, the list's own
+// continue token, and its remaining-item estimate.
+type pagePhase struct {
+	name string
+	list func(opts metav1.ListOptions) (n int, continueToken string, remaining *int64, err error)
+}
+
+// pagePhases fills one page of opts.Limit items from phases in order. The
+// continue token it returns is "<phase>:<that phase's token>", so the next
+// call resumes mid-list or at the start of the following phase.
+func pagePhases(opts metav1.ListOptions, phases []pagePhase) (string, *int64, error) {
+	start, continueToken := 0, ""
+	if opts.Continue != "" {
+		name, token, _ := strings.Cut(opts.Continue, ":")
+		start = -1
+		for i, p := range phases {
+			if p.name == name {
+				start, continueToken = i, token
+			}
+		}
+		if start < 0 {
+			return "", nil, fmt.Errorf("invalid continue token %q", opts.Continue)
+		}
+	}
+
+	limit := opts.Limit
+	for i := start; i < len(phases); i++ {
+		phaseOpts := opts
+		phaseOpts.Limit, phaseOpts.Continue = limit, ""
+		if i == start {
+			phaseOpts.Continue = continueToken
+		}
+		n, next, remaining, err := phases[i].list(phaseOpts)
+		if err != nil {
+			return "", nil, err
+		}
+		if next != "" {
+			return phases[i].name + ":" + next, remaining, nil
+		}
+		if limit -= int64(n); limit <= 0 && i+1 < len(phases) {
+			return phases[i+1].name + ":", nil, nil
+		}
+	}
+	return "", nil, nil
+}
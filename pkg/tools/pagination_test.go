@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPageFooter(t *testing.T) {
+	if got := pageFooter("list_pods", "", nil); got != "" {
+		t.Errorf("pageFooter() on the last page = %q, want empty", got)
+	}
+
+	remaining := int64(4800)
+	got := pageFooter("list_pods", "tok", &remaining)
+	for _, want := range []string{"TRUNCATED: about 4800 more items remain", `list_pods again with the same filters and continue_token="tok"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("pageFooter() = %q, want it to contain %q", got, want)
+		}
+	}
+	if got := pageFooter("list_pods", "tok", nil); !strings.Contains(got, "TRUNCATED: more items remain") {
+		t.Errorf("pageFooter() without a count = %q", got)
+	}
+}
+
+func TestPagePhases(t *testing.T) {
+	// Two lists of 3 and 2 items that honour limit and continue like the API server.
+	sizes := map[string]int{"a": 3, "b": 2}
+	var listed []string
+	phase := func(name string) pagePhase {
+		return pagePhase{name: name, list: func(opts metav1.ListOptions) (int, string, *int64, error) {
+			start, _ := strconv.Atoi(opts.Continue)
+			end := min(start+int(opts.Limit), sizes[name])
+			for i := start; i < end; i++ {
+				listed = append(listed, name+strconv.Itoa(i))
+			}
+			if end < sizes[name] {
+				return end - start, strconv.Itoa(end), nil, nil
+			}
+			return end - start, "", nil, nil
+		}}
+	}
+	phases := []pagePhase{phase("a"), phase("b")}
+
+	var tokens []string
+	opts := metav1.ListOptions{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("continue tokens did not terminate")
+		}
+		next, _, err := pagePhases(opts, phases)
+		if err != nil {
+			t.Fatalf("pagePhases() error = %v", err)
+		}
+		if next == "" {
+			break
+		}
+		tokens = append(tokens, next)
+		opts.Continue = next
+	}
+	if got := strings.Join(listed, ","); got != "a0,a1,a2,b0,b1" {
+		t.Errorf("listed %s, want every item once in order", got)
+	}
+	if got := strings.Join(tokens, " "); got != "a:2 b:1" {
+		t.Errorf("tokens = %s", got)
+	}
+
+	if _, _, err := pagePhases(metav1.ListOptions{Limit: 2, Continue: "c:1"}, phases); err == nil {
+		t.Error("expected an error for a token naming an unknown phase")
+	}
+}
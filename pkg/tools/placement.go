@@ -0,0 +1,228 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/placement"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type checkPlacementPolicyInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace to check (empty for all namespaces)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// placementViolation is one workload whose pods run on a pool a rule forbids.
+type placementViolation struct {
+	Rule          placement.Rule
+	Namespace     string
+	Workload      string
+	PriorityClass string
+	Pools         map[string]bool
+	Pods          int
+}
+
+func registerPlacementTools(server *mcp.Server, client *k8s.ClusterClient, opts Options) {
+	policy, source := opts.PlacementPolicy, "--placement-policy-file"
+	if policy == nil {
+		policy, source = placement.Default(), "built-in default"
+	}
+
+	// check_placement_policy
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "check_placement_policy",
+		Description: "Check that pods run on the node pools their placement policy allows, matching pods by priority class, priority, or namespace and pools by name or mode (AKS system/user). Uses the server's --placement-policy-file, or a built-in default requiring system-node-critical and system-cluster-critical pods on system pools and keeping application workloads off them. DaemonSet pods are skipped.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkPlacementPolicyInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+
+		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing nodes", err), nil, nil
+		}
+		pods, err := client.ListPods(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+
+		violations, checked, unevaluable := checkPlacement(policy, nodes, pods)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Placement Policy Check: %s", displayNS(ns))))
+		sb.WriteString(util.FormatKeyValue("Policy", fmt.Sprintf("%s (%d rules)", source, len(policy.Rules))))
+		sb.WriteString(util.FormatKeyValue("Pods Checked", fmt.Sprintf("%d", checked)))
+		sb.WriteString("\n")
+
+		sb.WriteString(util.FormatSubHeader("RULES"))
+		ruleHeaders := []string{"RULE", "SEVERITY", "SELECTS", "CONSTRAINT"}
+		var ruleRows [][]string
+		for _, r := range policy.Rules {
+			ruleRows = append(ruleRows, []string{r.Name, r.Severity, placementRuleSelector(r), r.Describe()})
+		}
+		sb.WriteString(util.FormatTable(ruleHeaders, ruleRows))
+		sb.WriteString("\n")
+
+		var findingLines, actions []string
+		if len(violations) > 0 {
+			sb.WriteString(util.FormatSubHeader("VIOLATIONS"))
+			headers := []string{"NAMESPACE", "WORKLOAD", "PRIORITY CLASS", "PODS", "POOLS", "RULE"}
+			var rows [][]string
+			for _, v := range violations {
+				pc := v.PriorityClass
+				if pc == "" {
+					pc = "-"
+				}
+				rows = append(rows, []string{v.Namespace, truncateName(v.Workload, 40), pc, fmt.Sprintf("%d", v.Pods), strings.Join(sortedKeys(v.Pools), ","), v.Rule.Name})
+				findingLines = append(findingLines, findings.Format("KD-NODE-011", v.Rule.Severity,
+					fmt.Sprintf("%s/%s: %d pod(s) on pool %s, but rule %s requires it to %s",
+						v.Namespace, v.Workload, v.Pods, strings.Join(sortedKeys(v.Pools), ","), v.Rule.Name, strings.TrimPrefix(v.Rule.Describe(), "must "))))
+				actions = append(actions, placementAction(v.Rule))
+			}
+			sb.WriteString(util.FormatTable(headers, rows))
+			sb.WriteString("\n")
+		}
+		if unevaluable > 0 {
+			findingLines = append(findingLines, findings.Format("KD-SYS-008", "INFO",
+				fmt.Sprintf("%d pod(s) matched a mode rule but run on nodes without a pool mode label (kubernetes.azure.com/mode); use allowedPools/forbiddenPools in the policy for other providers", unevaluable)))
+		}
+
+		sb.WriteString("FINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  No placement policy violations found.\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// checkPlacement evaluates every scheduled, non-DaemonSet pod against the
+// policy and groups violations by workload and rule. It also returns the
+// number of pods checked and the number that matched a mode rule on a node
+// with no known pool mode.
+func checkPlacement(policy *placement.Policy, nodes []corev1.Node, pods []corev1.Pod) ([]placementViolation, int, int) {
+	nodeByName := make(map[string]*corev1.Node, len(nodes))
+	for i := range nodes {
+		nodeByName[nodes[i].Name] = &nodes[i]
+	}
+
+	byKey := make(map[string]*placementViolation)
+	checked, unevaluable := 0, 0
+	for i := range pods {
+		pod := &pods[i]
+		node := nodeByName[pod.Spec.NodeName]
+		if node == nil || isDaemonSetPod(pod) ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		checked++
+		pool, mode := nodePoolName(node), nodePoolMode(node)
+		for _, rule := range policy.Rules {
+			if !rule.Selects(pod.Namespace, pod.Spec.PriorityClassName, pod.Spec.Priority) {
+				continue
+			}
+			ok, evaluable := rule.Permits(pool, mode)
+			if !evaluable {
+				unevaluable++
+				continue
+			}
+			if ok {
+				continue
+			}
+			workload := podWorkloadName(pod)
+			key := pod.Namespace + "/" + workload + "/" + rule.Name
+			v, exists := byKey[key]
+			if !exists {
+				v = &placementViolation{
+					Rule:          rule,
+					Namespace:     pod.Namespace,
+					Workload:      workload,
+					PriorityClass: pod.Spec.PriorityClassName,
+					Pools:         make(map[string]bool),
+				}
+				byKey[key] = v
+			}
+			v.Pods++
+			v.Pools[pool] = true
+		}
+	}
+
+	violations := make([]placementViolation, 0, len(byKey))
+	for _, v := range byKey {
+		violations = append(violations, *v)
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Rule.Severity != b.Rule.Severity {
+			return a.Rule.Severity == "CRITICAL"
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Workload < b.Workload
+	})
+	return violations, checked, unevaluable
+}
+
+// isDaemonSetPod reports whether a pod is controlled by a DaemonSet, which
+// places one pod per node by design.
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller && ref.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// placementRuleSelector summarizes which pods a rule selects.
+func placementRuleSelector(r placement.Rule) string {
+	var parts []string
+	if len(r.PriorityClasses) > 0 {
+		parts = append(parts, "class "+strings.Join(r.PriorityClasses, "|"))
+	}
+	if r.MinPriority != nil {
+		parts = append(parts, fmt.Sprintf("priority>=%d", *r.MinPriority))
+	}
+	if len(r.Namespaces) > 0 {
+		parts = append(parts, "ns "+strings.Join(r.Namespaces, "|"))
+	}
+	if len(r.ExcludeNamespaces) > 0 {
+		parts = append(parts, fmt.Sprintf("all but %d system namespaces", len(r.ExcludeNamespaces)))
+	}
+	if len(parts) == 0 {
+		return "all pods"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// placementAction suggests how to move a workload onto the pools a rule allows.
+func placementAction(r placement.Rule) string {
+	switch {
+	case len(r.AllowedModes) > 0:
+		return fmt.Sprintf("Add a nodeSelector (kubernetes.azure.com/mode: %s) and the matching toleration (e.g. CriticalAddonsOnly) to workloads violating %s", r.AllowedModes[0], r.Name)
+	case len(r.AllowedPools) > 0:
+		return fmt.Sprintf("Add a nodeSelector or required node affinity for pool %s to workloads violating %s", strings.Join(r.AllowedPools, "/"), r.Name)
+	case len(r.ForbiddenModes) > 0:
+		return fmt.Sprintf("Taint %s-mode pools (e.g. CriticalAddonsOnly=true:NoSchedule) so workloads violating %s schedule elsewhere", r.ForbiddenModes[0], r.Name)
+	default:
+		return fmt.Sprintf("Add node anti-affinity or taint pool %s so workloads violating %s schedule elsewhere", strings.Join(r.ForbiddenPools, "/"), r.Name)
+	}
+}
@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/placement"
+)
+
+func TestCheckPlacement(t *testing.T) {
+	node := func(name, pool, mode string) corev1.Node {
+		return testNode(name, "4", "16Gi", map[string]string{"kubernetes.azure.com/agentpool": pool, "kubernetes.azure.com/mode": mode})
+	}
+	nodes := []corev1.Node{node("sys-0", "system", "system"), node("usr-0", "user1", "user")}
+
+	pod := func(name, ns, nodeName, class, ownerKind string) corev1.Pod {
+		p := testPod(ns, name, nodeName, nil)
+		p.Spec.PriorityClassName = class
+		if ownerKind != "" {
+			p = withOwner(p, ownerKind, name+"-owner")
+		}
+		return p
+	}
+	pods := []corev1.Pod{
+		pod("coredns-1", "kube-system", "sys-0", "system-cluster-critical", ""),
+		pod("metrics-1", "kube-system", "usr-0", "system-cluster-critical", ""),
+		pod("kube-proxy-1", "kube-system", "usr-0", "system-node-critical", "DaemonSet"),
+		pod("web-1", "shop", "sys-0", "", ""),
+		pod("web-2", "shop", "usr-0", "", ""),
+	}
+
+	violations, checked, _ := checkPlacement(placement.Default(), nodes, pods)
+	if checked != 4 {
+		t.Errorf("expected DaemonSet pod to be skipped and 4 pods checked, got %d", checked)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", violations)
+	}
+	if v := violations[0]; v.Workload != "metrics-1" || v.Rule.Severity != "CRITICAL" {
+		t.Errorf("expected critical violation for metrics-1 first, got %+v", v)
+	}
+	if v := violations[1]; v.Workload != "web-1" || !v.Pools["system"] {
+		t.Errorf("expected web-1 on the system pool to violate, got %+v", v)
+	}
+}
@@ -0,0 +1,470 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// debrisDefaultMaxPods and debrisMaxPods cap the pods clean_pod_debris
+	// deletes per call.
+	debrisDefaultMaxPods = 100
+	debrisMaxPods        = 500
+	// debrisEvictedWarn is the number of evicted pods in one namespace that
+	// points at recurring node pressure rather than a one-off.
+	debrisEvictedWarn = 10
+)
+
+// debrisKinds are the kinds of finished pod find_pod_debris reports.
+var debrisKinds = []string{"Evicted", "Completed", "Failed"}
+
+// evictionResourcePattern extracts the resource from kubelet eviction
+// messages such as "The node was low on resource: memory."
+var evictionResourcePattern = regexp.MustCompile(`low on resource: ([a-z-]+)`)
+
+type findPodDebrisInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces)"`
+	OlderThan      string `json:"older_than,omitempty" jsonschema:"Only count pods finished longer ago than this duration (e.g. 1h, 24h; default: all finished pods)"`
+	Limit          int    `json:"limit,omitempty" jsonschema:"Maximum pods to list (default 30)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+type cleanPodDebrisInput struct {
+	Namespace string `json:"namespace" jsonschema:"required,Kubernetes namespace, or all for every namespace"`
+	Kinds     string `json:"kinds,omitempty" jsonschema:"Comma-separated kinds to delete: evicted, completed, failed (default evicted,failed)"`
+	OlderThan string `json:"older_than,omitempty" jsonschema:"Only delete pods finished longer ago than this duration (default 1h)"`
+	MaxPods   int    `json:"max_pods,omitempty" jsonschema:"Maximum pods to delete (default 100, max 500)"`
+	DryRun    bool   `json:"dry_run,omitempty" jsonschema:"Validate the deletes with a server-side dry run without applying them"`
+}
+
+func registerPodDebrisTools(server *mcp.Server, client *k8s.ClusterClient, opts Options) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "find_pod_debris",
+		Description: "List finished pods left behind: Evicted, Completed, and Failed pods, totaled per namespace with their age, eviction reasons " +
+			"(the resource the node was low on), and owners. Pods owned by Jobs are flagged separately since their Job's ttlSecondsAfterFinished " +
+			"or CronJob history limits should remove them. With --enable-write, points to clean_pod_debris to delete the rest.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input findPodDebrisInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		var olderThan time.Duration
+		if input.OlderThan != "" {
+			d, err := time.ParseDuration(input.OlderThan)
+			if err != nil || d < 0 {
+				return util.ErrorResult("invalid older_than %q: use a duration like 1h or 24h", input.OlderThan), nil, nil
+			}
+			olderThan = d
+		}
+		limit := input.Limit
+		if limit <= 0 {
+			limit = 30
+		}
+
+		pods, err := client.ListPods(ctx, util.NamespaceOrAll(input.Namespace), metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		now := time.Now()
+		debris := findPodDebris(pods, now, olderThan)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Pod Debris (scope: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+		totals := make(map[string]int)
+		jobOwned := 0
+		for _, d := range debris {
+			totals[d.Kind]++
+			if d.JobOwned {
+				jobOwned++
+			}
+		}
+		sb.WriteString(util.FormatKeyValue("Pods scanned", fmt.Sprintf("%d", len(pods))) + "\n")
+		for _, kind := range debrisKinds {
+			sb.WriteString(util.FormatKeyValue(kind, fmt.Sprintf("%d", totals[kind])) + "\n")
+		}
+		sb.WriteString(util.FormatKeyValue("Owned by Jobs", fmt.Sprintf("%d", jobOwned)) + "\n")
+		if olderThan > 0 {
+			sb.WriteString(util.FormatKeyValue("Finished more than", olderThan.String()+" ago") + "\n")
+		}
+
+		if len(debris) == 0 {
+			sb.WriteString("\nFINDINGS:\n")
+			sb.WriteString(util.FormatFinding("OK", "No Evicted, Completed, or Failed pods left behind"))
+			sb.WriteString("\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("By Namespace"))
+		sb.WriteString("\n")
+		byNS := debrisByNamespace(debris)
+		rows := make([][]string, 0, len(byNS))
+		for _, ns := range sortedMapKeys(byNS) {
+			s := byNS[ns]
+			rows = append(rows, []string{ns, fmt.Sprintf("%d", s.counts["Evicted"]), fmt.Sprintf("%d", s.counts["Completed"]),
+				fmt.Sprintf("%d", s.counts["Failed"]), fmt.Sprintf("%d", s.total), util.FormatAge(s.oldest)})
+		}
+		sb.WriteString(util.FormatTable([]string{"NAMESPACE", "EVICTED", "COMPLETED", "FAILED", "TOTAL", "OLDEST"}, rows))
+
+		reasons := make(map[string]int)
+		for _, d := range debris {
+			if d.Kind != "Completed" {
+				reasons[d.Kind+": "+d.Reason]++
+			}
+		}
+		if len(reasons) > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("Eviction and Failure Reasons"))
+			sb.WriteString("\n")
+			keys := sortedMapKeys(reasons)
+			sort.SliceStable(keys, func(i, j int) bool { return reasons[keys[i]] > reasons[keys[j]] })
+			rows = rows[:0]
+			for _, k := range keys {
+				rows = append(rows, []string{k, fmt.Sprintf("%d", reasons[k])})
+			}
+			sb.WriteString(util.FormatTable([]string{"REASON", "PODS"}, rows))
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Pods"))
+		sb.WriteString("\n")
+		rows = rows[:0]
+		for _, d := range debris[:min(len(debris), limit)] {
+			rows = append(rows, []string{d.Namespace + "/" + d.Name, d.Kind, valueOrNone(d.Owner), util.FormatAge(d.Finished), truncateName(d.Reason, 60)})
+		}
+		sb.WriteString(util.FormatTable([]string{"POD", "KIND", "OWNER", "FINISHED", "REASON"}, rows))
+		if len(debris) > limit {
+			sb.WriteString(fmt.Sprintf("  ... and %d more (raise limit to see them)\n", len(debris)-limit))
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		var actions []string
+		var steps []util.NextStep
+		for _, ns := range sortedMapKeys(byNS) {
+			s := byNS[ns]
+			if n := s.counts["Evicted"]; n >= debrisEvictedWarn {
+				sb.WriteString(findings.Format("KD-NODE-002", "WARNING", fmt.Sprintf("%d evicted pods in %s point to recurring node pressure", n, ns)))
+				sb.WriteString("\n")
+				steps = append(steps, nextStep("analyze_events", "find the node pressure behind the evictions in "+ns, "namespace", ns))
+			}
+		}
+		sb.WriteString(findings.Format("KD-POD-014", "INFO", fmt.Sprintf("%d finished pod(s) left behind: %d Evicted, %d Completed, %d Failed; the pod garbage collector only removes them above --terminated-pod-gc-threshold (default 12500)",
+			len(debris), totals["Evicted"], totals["Completed"], totals["Failed"])))
+		sb.WriteString("\n")
+		if jobOwned > 0 {
+			sb.WriteString(findings.Format("KD-POD-014", "INFO", fmt.Sprintf("%d of them belong to Jobs; they go away with their Job", jobOwned)))
+			sb.WriteString("\n")
+			actions = append(actions, "Set ttlSecondsAfterFinished on Jobs and successfulJobsHistoryLimit/failedJobsHistoryLimit on CronJobs so finished Job pods are removed automatically")
+		}
+		if len(debris) > jobOwned {
+			if opts.EnableWrite {
+				cleanNS := input.Namespace
+				if util.NamespaceOrAll(cleanNS) == "" {
+					cleanNS = "all"
+				}
+				actions = append(actions, "Delete the remaining finished pods with clean_pod_debris (start with dry_run=true)")
+				steps = append(steps, nextStep("clean_pod_debris", "preview deleting the finished pods", "namespace", cleanNS, "dry_run", true))
+			} else {
+				actions = append(actions, fmt.Sprintf("Delete the remaining finished pods: kubectl delete pods %s --field-selector=status.phase=Failed (and status.phase=Succeeded)", debrisNamespaceFlag(input.Namespace)))
+			}
+		}
+		if totals["Evicted"] > 0 {
+			actions = append(actions, "Fix what caused the evictions before cleaning up: set memory and ephemeral-storage requests close to real use, and check node pressure with analyze_node_capacity")
+		}
+		sb.WriteString("\nSUGGESTED ACTIONS:\n")
+		for i, a := range dedupe(actions) {
+			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+		}
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// registerPodDebrisCleanupTools registers clean_pod_debris, which deletes
+// pods and so is only available with --enable-write.
+func registerPodDebrisCleanupTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "clean_pod_debris",
+		Description: fmt.Sprintf("Delete finished pods that find_pod_debris reports: Evicted and Failed pods by default, Completed pods on request. "+
+			"Skips pods owned by Jobs (delete the Job instead) and pods finished less than older_than (default 1h) ago. Deletes at most %d pods per call. "+
+			"Set dry_run=true to have the API server validate the deletes without applying them. "+
+			"Only available when the server runs with --enable-write.", debrisMaxPods),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input cleanPodDebrisInput) (*mcp.CallToolResult, any, error) {
+		if input.Namespace == "" {
+			return util.ErrorResult("namespace is required (use all for every namespace)"), nil, nil
+		}
+		kinds, err := parseDebrisKinds(input.Kinds)
+		if err != nil {
+			return util.ErrorResult("%v", err), nil, nil
+		}
+		olderThan := time.Hour
+		if input.OlderThan != "" {
+			d, err := time.ParseDuration(input.OlderThan)
+			if err != nil || d < 0 {
+				return util.ErrorResult("invalid older_than %q: use a duration like 1h or 24h", input.OlderThan), nil, nil
+			}
+			olderThan = d
+		}
+		maxPods := input.MaxPods
+		if maxPods <= 0 {
+			maxPods = debrisDefaultMaxPods
+		}
+		if maxPods > debrisMaxPods {
+			return util.ErrorResult("max_pods must be at most %d", debrisMaxPods), nil, nil
+		}
+
+		pods, err := client.ListPods(ctx, util.NamespaceOrAll(input.Namespace), metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		targets, skippedJobs := debrisTargets(findPodDebris(pods, time.Now(), olderThan), kinds)
+		remaining := 0
+		if len(targets) > maxPods {
+			remaining = len(targets) - maxPods
+			targets = targets[:maxPods]
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Clean Pod Debris (scope: %s)%s", displayNS(input.Namespace), dryRunSuffix(input.DryRun))))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Kinds", strings.Join(kinds, ", ")))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Finished more than", olderThan.String()+" ago"))
+		sb.WriteString("\n")
+		if len(targets) == 0 {
+			sb.WriteString(util.FormatKeyValue("Result", "No change — no matching finished pods"))
+			sb.WriteString("\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		var deleted int
+		var failed []string
+		for _, d := range targets {
+			if err := client.DeletePod(ctx, d.Namespace, d.Name, nil, input.DryRun); err != nil {
+				failed = append(failed, fmt.Sprintf("%s/%s: %s", d.Namespace, d.Name, gapReason(err)))
+				continue
+			}
+			deleted++
+		}
+		result := fmt.Sprintf("%d pod(s) deleted", deleted)
+		if input.DryRun {
+			result = fmt.Sprintf("%d pod(s) would be deleted (server-side dry run, nothing was changed)", deleted)
+		}
+		sb.WriteString(util.FormatKeyValue("Result", result))
+		sb.WriteString("\n")
+		var similar []string
+		for _, phase := range debrisPhases(kinds) {
+			similar = append(similar, fmt.Sprintf("kubectl delete pods %s --field-selector=status.phase=%s", debrisNamespaceFlag(input.Namespace), phase))
+		}
+		sb.WriteString(util.FormatKeyValue("Similar to", strings.Join(similar, "; ")+" (which also deletes Job pods and recent ones)"))
+		sb.WriteString("\n\n")
+		rows := make([][]string, 0, len(targets))
+		for _, d := range targets {
+			rows = append(rows, []string{d.Namespace + "/" + d.Name, d.Kind, util.FormatAge(d.Finished)})
+		}
+		sb.WriteString(util.FormatTable([]string{"POD", "KIND", "FINISHED"}, rows))
+		if remaining > 0 {
+			sb.WriteString(fmt.Sprintf("  %d more matching pod(s) left; run again to continue.\n", remaining))
+		}
+		if skippedJobs > 0 {
+			sb.WriteString(fmt.Sprintf("  Skipped %d pod(s) owned by Jobs; delete the Job or set ttlSecondsAfterFinished instead.\n", skippedJobs))
+		}
+		for _, f := range failed {
+			sb.WriteString(findings.Format("KD-SYS-008", "WARNING", "Could not delete "+f))
+			sb.WriteString("\n")
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// podDebris is a finished pod still present in the API.
+type podDebris struct {
+	Namespace, Name string
+	// Kind is Evicted, Completed, or Failed.
+	Kind   string
+	Reason string
+	// Owner is the controller as Kind/Name, "" for bare pods.
+	Owner    string
+	JobOwned bool
+	Finished time.Time
+}
+
+// findPodDebris returns the Succeeded and Failed pods that finished at least
+// olderThan before now, sorted by namespace and then oldest first.
+func findPodDebris(pods []corev1.Pod, now time.Time, olderThan time.Duration) []podDebris {
+	var out []podDebris
+	for i := range pods {
+		p := &pods[i]
+		if !isFinishedPod(p) {
+			continue
+		}
+		d := podDebris{Namespace: p.Namespace, Name: p.Name, Finished: podFinishedAt(p)}
+		if now.Sub(d.Finished) < olderThan {
+			continue
+		}
+		switch {
+		case p.Status.Phase == corev1.PodSucceeded:
+			d.Kind, d.Reason = "Completed", "Completed"
+		case p.Status.Reason == "Evicted":
+			d.Kind, d.Reason = "Evicted", evictionReason(p.Status.Message)
+		default:
+			d.Kind, d.Reason = "Failed", podFailureReason(p)
+		}
+		if owner := metav1.GetControllerOf(p); owner != nil {
+			d.Owner = owner.Kind + "/" + owner.Name
+			d.JobOwned = owner.Kind == "Job"
+		}
+		out = append(out, d)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Finished.Before(out[j].Finished)
+	})
+	return out
+}
+
+// podFinishedAt estimates when a pod finished: the last container
+// termination, else its start time, else its creation.
+func podFinishedAt(p *corev1.Pod) time.Time {
+	var t time.Time
+	for _, cs := range p.Status.ContainerStatuses {
+		if term := cs.State.Terminated; term != nil && term.FinishedAt.After(t) {
+			t = term.FinishedAt.Time
+		}
+	}
+	if t.IsZero() && p.Status.StartTime != nil {
+		t = p.Status.StartTime.Time
+	}
+	if t.IsZero() {
+		t = p.CreationTimestamp.Time
+	}
+	return t
+}
+
+// evictionReason shortens a kubelet eviction message to the resource the
+// node was low on, or the message itself.
+func evictionReason(msg string) string {
+	if m := evictionResourcePattern.FindStringSubmatch(msg); m != nil {
+		return "node low on " + m[1]
+	}
+	if strings.Contains(strings.ToLower(msg), "ephemeral local storage") || strings.Contains(msg, "EmptyDir") {
+		return "ephemeral-storage limit exceeded"
+	}
+	if msg == "" {
+		return "Evicted"
+	}
+	return msg
+}
+
+// podFailureReason names why a Failed pod failed: its status reason, else
+// the first container's termination reason.
+func podFailureReason(p *corev1.Pod) string {
+	if p.Status.Reason != "" {
+		return p.Status.Reason
+	}
+	for _, cs := range p.Status.ContainerStatuses {
+		if term := cs.State.Terminated; term != nil && term.ExitCode != 0 {
+			if term.Reason != "" {
+				return fmt.Sprintf("%s (exit %d)", term.Reason, term.ExitCode)
+			}
+			return fmt.Sprintf("exit %d", term.ExitCode)
+		}
+	}
+	return "Failed"
+}
+
+// debrisNamespace is a namespace's debris totals.
+type debrisNamespace struct {
+	counts map[string]int
+	total  int
+	oldest time.Time
+}
+
+func debrisByNamespace(debris []podDebris) map[string]*debrisNamespace {
+	out := make(map[string]*debrisNamespace)
+	for _, d := range debris {
+		s, ok := out[d.Namespace]
+		if !ok {
+			s = &debrisNamespace{counts: make(map[string]int), oldest: d.Finished}
+			out[d.Namespace] = s
+		}
+		s.counts[d.Kind]++
+		s.total++
+		if d.Finished.Before(s.oldest) {
+			s.oldest = d.Finished
+		}
+	}
+	return out
+}
+
+// parseDebrisKinds parses the kinds argument of clean_pod_debris.
+func parseDebrisKinds(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return []string{"Evicted", "Failed"}, nil
+	}
+	var kinds []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		found := false
+		for _, k := range debrisKinds {
+			if strings.EqualFold(part, k) {
+				kinds = append(kinds, k)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown kind %q: use evicted, completed, or failed", part)
+		}
+	}
+	return dedupe(kinds), nil
+}
+
+// debrisTargets selects the debris of the given kinds that clean_pod_debris
+// deletes, and counts the Job-owned pods it leaves alone.
+func debrisTargets(debris []podDebris, kinds []string) ([]podDebris, int) {
+	var targets []podDebris
+	skipped := 0
+	for _, d := range debris {
+		if !slices.Contains(kinds, d.Kind) {
+			continue
+		}
+		if d.JobOwned {
+			skipped++
+			continue
+		}
+		targets = append(targets, d)
+	}
+	return targets, skipped
+}
+
+// debrisPhases returns the pod phases that hold the given debris kinds.
+func debrisPhases(kinds []string) []string {
+	var phases []string
+	if slices.Contains(kinds, "Evicted") || slices.Contains(kinds, "Failed") {
+		phases = append(phases, string(corev1.PodFailed))
+	}
+	if slices.Contains(kinds, "Completed") {
+		phases = append(phases, string(corev1.PodSucceeded))
+	}
+	return phases
+}
+
+// debrisNamespaceFlag is the kubectl namespace flag for a namespace argument.
+func debrisNamespaceFlag(ns string) string {
+	if ns == "" || ns == "all" {
+		return "-A"
+	}
+	return "-n " + ns
+}
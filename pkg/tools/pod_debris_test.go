@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindPodDebris(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	pod := func(ns, name string, status corev1.PodStatus, finished time.Duration, owner string) corev1.Pod {
+		p := testPod(ns, name, "", nil)
+		p.CreationTimestamp = metav1.NewTime(now.Add(-48 * time.Hour))
+		p.Status = status
+		if finished > 0 {
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 1, FinishedAt: metav1.NewTime(now.Add(-finished)),
+			}}}}
+		}
+		if owner != "" {
+			p = withOwner(p, owner, name+"-owner")
+		}
+		return p
+	}
+	pods := []corev1.Pod{
+		pod("shop", "web-evicted", corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: "The node was low on resource: memory. Threshold quantity: 100Mi."}, 0, "ReplicaSet"),
+		pod("shop", "migrate", corev1.PodStatus{Phase: corev1.PodSucceeded}, 3*time.Hour, "Job"),
+		pod("shop", "crashed", corev1.PodStatus{Phase: corev1.PodFailed}, 2*time.Hour, ""),
+		pod("shop", "fresh", corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Shutdown"}, 10*time.Minute, ""),
+		pod("shop", "running", corev1.PodStatus{Phase: corev1.PodRunning}, 0, ""),
+		pod("batch", "scratch", corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: "Pod ephemeral local storage usage exceeds the total limit of containers 1Gi."}, 0, ""),
+	}
+	debris := findPodDebris(pods, now, time.Hour)
+
+	byName := make(map[string]podDebris)
+	for _, d := range debris {
+		byName[d.Name] = d
+	}
+	if len(debris) != 4 || debris[0].Namespace != "batch" {
+		t.Fatalf("expected 4 pods older than 1h sorted by namespace, got %+v", debris)
+	}
+	if d := byName["web-evicted"]; d.Kind != "Evicted" || d.Reason != "node low on memory" || d.Owner != "ReplicaSet/web-evicted-owner" {
+		t.Errorf("unexpected evicted pod: %+v", d)
+	}
+	if d := byName["scratch"]; d.Reason != "ephemeral-storage limit exceeded" {
+		t.Errorf("unexpected ephemeral storage eviction: %+v", d)
+	}
+	if d := byName["migrate"]; d.Kind != "Completed" || !d.JobOwned {
+		t.Errorf("unexpected completed pod: %+v", d)
+	}
+	if d := byName["crashed"]; d.Kind != "Failed" || d.Reason != "exit 1" {
+		t.Errorf("unexpected failed pod: %+v", d)
+	}
+
+	kinds, err := parseDebrisKinds("completed, Evicted")
+	if err != nil || len(kinds) != 2 {
+		t.Fatalf("unexpected kinds %v: %v", kinds, err)
+	}
+	targets, skipped := debrisTargets(debris, kinds)
+	if len(targets) != 2 || skipped != 1 {
+		t.Errorf("expected both evicted pods and the Job pod skipped, got %+v (skipped %d)", targets, skipped)
+	}
+	if _, err := parseDebrisKinds("running"); err == nil {
+		t.Error("expected unknown kind to be rejected")
+	}
+	if phases := debrisPhases([]string{"Evicted", "Completed"}); len(phases) != 2 {
+		t.Errorf("expected Failed and Succeeded phases, got %v", phases)
+	}
+}
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// podNodeEventLookback is how long before a pod's most recent failure node
+// events and condition changes are considered related.
+const podNodeEventLookback = 30 * time.Minute
+
+// nodeProblemEventReasons are node event reasons that commonly surface as pod
+// failures, mapped to what they mean for the pods on the node.
+var nodeProblemEventReasons = map[string]string{
+	"NodeNotReady":              "node went NotReady",
+	"NodeHasDiskPressure":       "disk pressure",
+	"NodeHasInsufficientMemory": "memory pressure",
+	"NodeHasInsufficientPID":    "PID pressure",
+	"EvictionThresholdMet":      "kubelet is evicting pods",
+	"SystemOOM":                 "system OOM killer ran",
+	"OOMKilling":                "kernel OOM killer ran",
+	"Rebooted":                  "node rebooted",
+	"Starting":                  "kubelet restarted",
+	"KernelDeadlock":            "kernel deadlock",
+	"ContainerRuntimeUnhealthy": "container runtime unhealthy",
+	"FreeDiskSpaceFailed":       "image garbage collection could not free disk",
+	"ImageGCFailed":             "image garbage collection failed",
+	"Preempting":                "scheduler preempted pods on the node",
+
+	// Reported by node-problem-detector's kernel, filesystem, and systemd monitors.
+	"KernelOops":                "kernel oops",
+	"TaskHung":                  "kernel task hung",
+	"UnregisterNetDevice":       "kernel network device leak",
+	"Ext4Error":                 "filesystem error",
+	"IOError":                   "disk I/O error",
+	"FilesystemIsReadOnly":      "filesystem remounted read-only",
+	"DockerHung":                "container runtime hung",
+	"CorruptDockerOverlay2":     "container runtime storage corrupt",
+	"ContainerdStart":           "containerd restarted",
+	"DockerStart":               "docker restarted",
+	"KubeletStart":              "kubelet restarted",
+	"FrequentContainerdRestart": "containerd restarting repeatedly",
+	"FrequentKubeletRestart":    "kubelet restarting repeatedly",
+}
+
+// nodeProblemDetectorConditions are the node conditions set by
+// node-problem-detector (and the AKS/GKE variants of it), mapped to what a True
+// status means. nodeCriticalProblemConditions make the node unfit for pods.
+var nodeProblemDetectorConditions = map[corev1.NodeConditionType]string{
+	"KernelDeadlock":              "kernel deadlock: processes are stuck in uninterruptible sleep",
+	"ReadonlyFilesystem":          "a filesystem was remounted read-only after disk errors",
+	"FrequentKubeletRestart":      "kubelet is crash-looping",
+	"FrequentDockerRestart":       "docker is crash-looping",
+	"FrequentContainerdRestart":   "containerd is crash-looping",
+	"FrequentUnregisterNetDevice": "the kernel is leaking network devices",
+	"CorruptDockerOverlay2":       "container runtime overlay storage is corrupt",
+	"ContainerRuntimeProblem":     "the container runtime is unhealthy",
+	"KubeletProblem":              "kubelet is unhealthy",
+	"FilesystemCorruptionProblem": "filesystem corruption detected",
+	"VMEventScheduled":            "the cloud provider scheduled maintenance (reboot or redeploy) for this VM",
+}
+
+var nodeCriticalProblemConditions = map[corev1.NodeConditionType]bool{
+	"KernelDeadlock":              true,
+	"ReadonlyFilesystem":          true,
+	"CorruptDockerOverlay2":       true,
+	"FilesystemCorruptionProblem": true,
+}
+
+// podFailureWindowStart returns the start of the window in which node
+// problems are correlated with a pod's failures: podNodeEventLookback before
+// its most recent container termination or readiness loss, or before now.
+func podFailureWindowStart(pod *corev1.Pod, now time.Time) time.Time {
+	var latest time.Time
+	for _, cs := range pod.Status.ContainerStatuses {
+		for _, t := range []*corev1.ContainerStateTerminated{cs.State.Terminated, cs.LastTerminationState.Terminated} {
+			if t != nil && t.ExitCode != 0 && t.FinishedAt.After(latest) {
+				latest = t.FinishedAt.Time
+			}
+		}
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue && cond.LastTransitionTime.After(latest) {
+			latest = cond.LastTransitionTime.Time
+		}
+	}
+	if latest.IsZero() {
+		latest = now
+	}
+	return latest.Add(-podNodeEventLookback)
+}
+
+// nodeConditionFindings reports unhealthy node conditions and conditions that
+// changed since windowStart.
+func nodeConditionFindings(node *corev1.Node, windowStart time.Time) []findings.Finding {
+	var out []findings.Finding
+	for _, cond := range node.Status.Conditions {
+		switch {
+		case cond.Type == corev1.NodeReady && cond.Status != corev1.ConditionTrue:
+			out = append(out, findings.New("KD-NODE-001", fmt.Sprintf("Node '%s' is NotReady: %s", node.Name, cond.Message)))
+		case cond.Type != corev1.NodeReady && cond.Status == corev1.ConditionTrue:
+			rule, severity := "KD-NODE-002", "WARNING"
+			if nodeCriticalProblemConditions[cond.Type] {
+				severity = "CRITICAL"
+			}
+			msg := fmt.Sprintf("Node '%s' has %s: %s", node.Name, cond.Type, cond.Message)
+			if meaning, ok := nodeProblemDetectorConditions[cond.Type]; ok {
+				rule = "KD-NODE-004"
+				msg += fmt.Sprintf(" [node-problem-detector: %s]", meaning)
+			}
+			out = append(out, findings.New(rule, msg).WithSeverity(severity))
+		case cond.LastTransitionTime.After(windowStart):
+			out = append(out, findings.Finding{Severity: "INFO", Message: fmt.Sprintf("Node condition %s changed to %s %s ago, within the pod's failure window",
+				cond.Type, cond.Status, time.Since(cond.LastTransitionTime.Time).Round(time.Second))})
+		}
+	}
+	return out
+}
+
+// writePodNodeCorrelation writes the hosting node's conditions and the node
+// events in the pod's failure window, and returns actions for node problems.
+func writePodNodeCorrelation(ctx context.Context, sb *strings.Builder, client *k8s.ClusterClient, pod *corev1.Pod) []string {
+	windowStart := podFailureWindowStart(pod, time.Now())
+	sb.WriteString(fmt.Sprintf("\nNODE CORRELATION (node '%s', since %s):\n", pod.Spec.NodeName, windowStart.UTC().Format(time.RFC3339)))
+
+	node, err := client.GetNode(ctx, pod.Spec.NodeName)
+	if err != nil {
+		if util.IsScopeError(err) {
+			writeScopeSkipped(sb, err)
+		} else {
+			sb.WriteString(fmt.Sprintf("  (could not get node: %v)\n", err))
+		}
+		return nil
+	}
+
+	problems := 0
+	for _, f := range nodeConditionFindings(node, windowStart) {
+		sb.WriteString(fmt.Sprintf("  %s\n", f))
+		if f.Severity != "INFO" {
+			problems++
+		}
+	}
+
+	events, err := client.ListEventsMatching(ctx, "", metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=Node,involvedObject.name=" + node.Name,
+	}, k8s.EventFilter{Since: windowStart})
+	if err != nil {
+		sb.WriteString(fmt.Sprintf("  (could not list node events: %v)\n", err))
+	}
+	sort.Slice(events, func(i, j int) bool { return k8s.EventTime(&events[i]).Before(k8s.EventTime(&events[j])) })
+	for _, e := range events {
+		meaning, known := nodeProblemEventReasons[e.Reason]
+		if !known && e.Type != corev1.EventTypeWarning {
+			continue
+		}
+		if known {
+			problems++
+		}
+		line := fmt.Sprintf("  - %s ago %s: %s", time.Since(k8s.EventTime(&e)).Round(time.Second), e.Reason, e.Message)
+		if meaning != "" {
+			line += fmt.Sprintf(" [%s]", meaning)
+		}
+		if e.Count > 1 {
+			line += fmt.Sprintf(" (x%d)", e.Count)
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	if problems == 0 {
+		sb.WriteString("  Node is healthy with no problem events in the window - the failure is likely in the pod itself.\n")
+		return nil
+	}
+	sb.WriteString(fmt.Sprintf("  %s\n", findings.Format("KD-NODE-004", "WARNING",
+		fmt.Sprintf("%d node problem(s) coincide with the pod's failures - this may be a node issue rather than an application issue", problems))))
+	return []string{fmt.Sprintf("Investigate node '%s' with get_node_detail before changing the pod (node problems coincide with its failures)", node.Name)}
+}
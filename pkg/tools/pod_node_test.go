@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+func TestPodFailureWindowStart(t *testing.T) {
+	now := time.Now()
+	crashed := now.Add(-2 * time.Hour)
+	pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, FinishedAt: metav1.NewTime(crashed)}},
+	}}}}
+	if got, want := podFailureWindowStart(pod, now), crashed.Add(-podNodeEventLookback); !got.Equal(want) {
+		t.Errorf("podFailureWindowStart() = %v, want %v", got, want)
+	}
+	if got, want := podFailureWindowStart(&corev1.Pod{}, now), now.Add(-podNodeEventLookback); !got.Equal(want) {
+		t.Errorf("podFailureWindowStart() for healthy pod = %v, want %v", got, want)
+	}
+}
+
+func TestWritePodNodeCorrelation(t *testing.T) {
+	now := time.Now()
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue, Message: "kubelet has insufficient memory"},
+			}},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "n1.evict", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "n1"},
+			Reason:         "EvictionThresholdMet",
+			Message:        "Attempting to reclaim memory",
+			Type:           corev1.EventTypeWarning,
+			LastTimestamp:  metav1.NewTime(now.Add(-5 * time.Minute)),
+		},
+	)
+	client := k8s.NewClusterClientForTesting(fakeClient, nil)
+	pod := &corev1.Pod{Spec: corev1.PodSpec{NodeName: "n1"}}
+
+	var sb strings.Builder
+	actions := writePodNodeCorrelation(context.Background(), &sb, client, pod)
+	out := sb.String()
+	if !strings.Contains(out, "MemoryPressure") || !strings.Contains(out, "EvictionThresholdMet") {
+		t.Errorf("expected node condition and event in output, got:\n%s", out)
+	}
+	if len(actions) != 1 {
+		t.Errorf("expected one node action, got %v", actions)
+	}
+}
@@ -0,0 +1,317 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/mermaid"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// placementHotPercent is the CPU or memory request share of allocatable
+	// at which a node is drawn as hot.
+	placementHotPercent = 90
+	// placementWarmPercent is the request share at which a node is drawn as warm.
+	placementWarmPercent = 75
+	// placementImbalancePoints is the spread between the most and least
+	// requested Ready nodes, in percentage points, reported as imbalance.
+	placementImbalancePoints = 50
+	// placementUnscheduled groups pods the scheduler has not bound to a node.
+	placementUnscheduled = "(unscheduled)"
+)
+
+type mapPodPlacementInput struct {
+	Namespace       string `json:"namespace,omitempty" jsonschema:"Only draw pods in this namespace (empty for all namespaces); node load always counts every pod"`
+	LabelSelector   string `json:"label_selector,omitempty" jsonschema:"Only draw pods matching this label selector (e.g. app=web)"`
+	MaxDiagramNodes int    `json:"max_diagram_nodes,omitempty" jsonschema:"Maximum nodes in the placement diagram before the rest are collapsed into '+N more' nodes (default 80)"`
+	TimeoutSeconds  int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// nodePlacement is one node's load and the matching pods placed on it.
+type nodePlacement struct {
+	Name        string
+	Status      string
+	Cordoned    bool
+	AllocCPU    int64
+	AllocMem    int64
+	ReqCPU      int64
+	ReqMem      int64
+	TotalPods   int
+	Matched     []corev1.Pod
+	Unhealthy   int
+	Unscheduled bool
+}
+
+// RequestPercent returns the larger of the node's CPU and memory request
+// share of allocatable.
+func (n nodePlacement) RequestPercent() float64 {
+	return max(percent(n.ReqCPU, n.AllocCPU), percent(n.ReqMem, n.AllocMem))
+}
+
+// Severity returns how the node is colored in the placement diagram.
+func (n nodePlacement) Severity() mermaid.Severity {
+	switch {
+	case n.Status != "Ready" && !n.Unscheduled:
+		return mermaid.SeverityCritical
+	case n.RequestPercent() >= placementHotPercent:
+		return mermaid.SeverityCritical
+	case n.RequestPercent() >= placementWarmPercent || n.Unhealthy > 0:
+		return mermaid.SeverityWarning
+	}
+	return mermaid.SeverityHealthy
+}
+
+func registerPodPlacementTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// map_pod_placement
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "map_pod_placement",
+		Description: "Render a Mermaid diagram of nodes as subgraphs containing their pods, optionally filtered by namespace or label selector. " +
+			"Pods are colored by health and annotated with their requests; nodes are labeled with CPU and memory requests against allocatable and colored when hot, " +
+			"so overloaded, idle, or imbalanced nodes and nodes collecting failing pods stand out. Also lists unscheduled pods.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input mapPodPlacementInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		selector := labels.Everything()
+		if input.LabelSelector != "" {
+			var err error
+			if selector, err = labels.Parse(input.LabelSelector); err != nil {
+				return util.ErrorResult("invalid label_selector %q: %v", input.LabelSelector, err), nil, nil
+			}
+		}
+
+		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing nodes", err), nil, nil
+		}
+		// Node load counts every pod, so list cluster-wide and filter in memory.
+		pods, err := client.ListPods(ctx, "", metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+
+		placements, matched := buildPodPlacement(nodes, pods, input.Namespace, selector)
+		filtered := input.Namespace != "" || input.LabelSelector != ""
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Pod Placement"))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Namespace", displayNS(input.Namespace)) + "\n")
+		if input.LabelSelector != "" {
+			sb.WriteString(util.FormatKeyValue("Selector", input.LabelSelector) + "\n")
+		}
+		sb.WriteString(util.FormatKeyValue("Pods Drawn", fmt.Sprintf("%d on %d node(s)", matched, len(nodes))) + "\n")
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("NODES"))
+		sb.WriteString("\n")
+		var rows [][]string
+		for _, n := range placements {
+			if n.Unscheduled {
+				continue
+			}
+			status := n.Status
+			if n.Cordoned {
+				status += ",SchedulingDisabled"
+			}
+			rows = append(rows, []string{
+				n.Name, status, fmt.Sprintf("%d", n.TotalPods), fmt.Sprintf("%d", len(n.Matched)), fmt.Sprintf("%d", n.Unhealthy),
+				fmt.Sprintf("%s/%s (%.0f%%)", formatMillis(n.ReqCPU), formatMillis(n.AllocCPU), percent(n.ReqCPU, n.AllocCPU)),
+				fmt.Sprintf("%s/%s (%.0f%%)", formatBytesOrNone(n.ReqMem), formatBytesOrNone(n.AllocMem), percent(n.ReqMem, n.AllocMem)),
+			})
+		}
+		if len(rows) == 0 {
+			sb.WriteString("  No nodes found.\n")
+		} else {
+			sb.WriteString(util.FormatTable([]string{"NODE", "STATUS", "PODS", "MATCHED", "UNHEALTHY", "CPU REQUESTS", "MEMORY REQUESTS"}, rows))
+		}
+
+		if matched > 0 || !filtered {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("PLACEMENT"))
+			sb.WriteString("\n")
+			sb.WriteString(podPlacementDiagram(placements, filtered, input.MaxDiagramNodes))
+			sb.WriteString("\n")
+		}
+
+		findingLines, actions, steps := podPlacementFindings(placements)
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  No hot, imbalanced, or failing nodes found.\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// buildPodPlacement groups pods by node. Requests are summed over every
+// running pod on a node; only pods in namespace matching selector are kept
+// for drawing. Pods without a node are collected in a trailing unscheduled
+// entry. It returns the nodes sorted by name and the number of matched pods.
+func buildPodPlacement(nodes []corev1.Node, pods []corev1.Pod, namespace string, selector labels.Selector) ([]nodePlacement, int) {
+	byName := make(map[string]*nodePlacement, len(nodes))
+	placements := make([]nodePlacement, 0, len(nodes)+1)
+	for _, n := range nodes {
+		placements = append(placements, nodePlacement{
+			Name:     n.Name,
+			Status:   nodeStatus(&n),
+			Cordoned: n.Spec.Unschedulable,
+			AllocCPU: n.Status.Allocatable.Cpu().MilliValue(),
+			AllocMem: n.Status.Allocatable.Memory().Value(),
+		})
+	}
+	sort.Slice(placements, func(i, j int) bool { return placements[i].Name < placements[j].Name })
+	placements = append(placements, nodePlacement{Name: placementUnscheduled, Unscheduled: true})
+	for i := range placements {
+		byName[placements[i].Name] = &placements[i]
+	}
+
+	matched := 0
+	for i := range pods {
+		p := &pods[i]
+		if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		n := byName[p.Spec.NodeName]
+		if p.Spec.NodeName == "" {
+			n = byName[placementUnscheduled]
+		}
+		if n == nil {
+			continue
+		}
+		if !n.Unscheduled {
+			cpu, mem := podRequests(p)
+			n.ReqCPU += cpu
+			n.ReqMem += mem
+			n.TotalPods++
+		}
+		if (namespace != "" && p.Namespace != namespace) || !selector.Matches(labels.Set(p.Labels)) {
+			continue
+		}
+		matched++
+		n.Matched = append(n.Matched, *p)
+		if !isPodHealthy(p) {
+			n.Unhealthy++
+		}
+	}
+	return placements, matched
+}
+
+// podPlacementDiagram draws each node as a subgraph of its matched pods.
+// Healthy pods of one workload are collapsed into a replica badge. When
+// filtered, nodes without matching pods are left out; otherwise idle nodes
+// are drawn empty so imbalance is visible.
+func podPlacementDiagram(placements []nodePlacement, filtered bool, maxNodes int) string {
+	fc := newBudgetedFlowchart(mermaid.DirectionLR, maxNodes).SetOverflowNoun("nodes")
+	var styled []diagramPodNode
+	for _, n := range placements {
+		if len(n.Matched) == 0 && (filtered || n.Unscheduled) {
+			continue
+		}
+		id := mermaid.SafeID("node_" + n.Name)
+		var label string
+		if n.Unscheduled {
+			label = fmt.Sprintf("Unscheduled (%d pod(s))", len(n.Matched))
+		} else {
+			label = fmt.Sprintf("%s%s%s · CPU %.0f%% · mem %.0f%% · %d pod(s)", n.Name, mermaid.BR(), n.Status,
+				percent(n.ReqCPU, n.AllocCPU), percent(n.ReqMem, n.AllocMem), n.TotalPods)
+		}
+		fc.AddSubgraph(id, label, func(sg *mermaid.Subgraph) {
+			sg.SetOverflowNoun("pods")
+			if len(n.Matched) == 0 {
+				sg.AddNode(mermaid.SafeID("idle_"+n.Name), "no pods", mermaid.ShapeStadium)
+				return
+			}
+			for _, pn := range summarizePodsForDiagram(n.Matched, placementPodLabel) {
+				sg.AddNode(pn.ID, pn.Label, mermaid.ShapeRound)
+				styled = append(styled, pn)
+			}
+		})
+		if sev := n.Severity(); sev != mermaid.SeverityHealthy {
+			fc.AddStyle(id, sev)
+		}
+	}
+	for _, pn := range styled {
+		styleDiagramPodNode(fc, pn)
+	}
+	return fc.RenderBlock()
+}
+
+// placementPodLabel renders a pod with its namespace, phase, and requests.
+func placementPodLabel(p *corev1.Pod) string {
+	cpu, mem := podRequests(p)
+	return fmt.Sprintf("%s/%s%s%s · %s / %s", p.Namespace, truncateName(p.Name, 40), mermaid.BR(),
+		podPhaseReason(p), formatMillis(cpu), formatBytesOrNone(mem))
+}
+
+// podPlacementFindings flags hot and NotReady nodes, request imbalance
+// across Ready nodes, nodes where most drawn pods are failing, and
+// unscheduled pods.
+func podPlacementFindings(placements []nodePlacement) ([]string, []string, []util.NextStep) {
+	var findingLines, actions []string
+	var steps []util.NextStep
+	var ready []nodePlacement
+	for _, n := range placements {
+		if n.Unscheduled {
+			if len(n.Matched) > 0 {
+				findingLines = append(findingLines, findings.Format("KD-POD-004", "WARNING", fmt.Sprintf("%d pod(s) are not scheduled to any node", len(n.Matched))))
+				actions = append(actions, "Check the FailedScheduling events of the unscheduled pods for the missing resource or unmatched constraint.")
+				p := n.Matched[0]
+				steps = append(steps, nextStep("diagnose_pod", "pod is waiting for a node", "namespace", p.Namespace, "name", p.Name))
+			}
+			continue
+		}
+		if n.Status != "Ready" {
+			findingLines = append(findingLines, findings.Format("KD-NODE-001", "CRITICAL", fmt.Sprintf("Node %s is %s with %d pod(s) on it", n.Name, n.Status, n.TotalPods)))
+			steps = append(steps, nextStep("diagnose_node", n.Name+" is "+n.Status, "name", n.Name))
+			continue
+		}
+		if !n.Cordoned {
+			ready = append(ready, n)
+		}
+		if pct := n.RequestPercent(); pct >= placementHotPercent {
+			findingLines = append(findingLines, findings.Format("KD-NODE-005", "WARNING", fmt.Sprintf("Node %s is hot: requests are %.0f%% of allocatable CPU and %.0f%% of memory",
+				n.Name, percent(n.ReqCPU, n.AllocCPU), percent(n.ReqMem, n.AllocMem))))
+		}
+		if len(n.Matched) >= 2 && n.Unhealthy*2 >= len(n.Matched) {
+			findingLines = append(findingLines, findings.Format("KD-POD-010", "WARNING", fmt.Sprintf("%d of %d drawn pod(s) on node %s are unhealthy; the node itself may be the cause", n.Unhealthy, len(n.Matched), n.Name)))
+			steps = append(steps, nextStep("diagnose_node", "unhealthy pods are concentrated on "+n.Name, "name", n.Name))
+		}
+	}
+
+	if len(ready) >= 2 {
+		sort.SliceStable(ready, func(i, j int) bool { return ready[i].RequestPercent() > ready[j].RequestPercent() })
+		hot, cold := ready[0], ready[len(ready)-1]
+		if hot.RequestPercent()-cold.RequestPercent() >= placementImbalancePoints {
+			findingLines = append(findingLines, findings.Format("KD-NODE-010", "WARNING", fmt.Sprintf("Requests are imbalanced across nodes: %s is at %.0f%% while %s is at %.0f%%",
+				hot.Name, hot.RequestPercent(), cold.Name, cold.RequestPercent())))
+			actions = append(actions, fmt.Sprintf("Spread load off %s: add topologySpreadConstraints or anti-affinity to its largest workloads, check for nodeSelectors or taints pinning pods to it, and consider the descheduler's LowNodeUtilization strategy.", hot.Name))
+			steps = append(steps, nextStep("analyze_topology_spread", "check how the workloads on the hot node are spread"))
+		}
+	}
+	for _, n := range ready {
+		if n.RequestPercent() >= placementHotPercent {
+			actions = append(actions, "Scale out the node pool or right-size requests on hot nodes; new pods that do not fit will stay Pending.")
+			steps = append(steps, nextStep("analyze_node_capacity", "nodes are close to fully requested"))
+			break
+		}
+	}
+	return findingLines, actions, steps
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestBuildPodPlacement(t *testing.T) {
+	placementPod := func(ns, name, node, app, cpu string, phase corev1.PodPhase) corev1.Pod {
+		p := withRequests(testPod(ns, name, node, map[string]string{"app": app}), cpu, "")
+		p.Status.Phase = phase
+		return p
+	}
+	nodes := []corev1.Node{testNode("node-b", "4", "8Gi", nil), testNode("node-a", "4", "8Gi", nil)}
+	pods := []corev1.Pod{
+		placementPod("shop", "web-1", "node-a", "web", "2", corev1.PodRunning),
+		placementPod("shop", "web-2", "node-a", "web", "1800m", corev1.PodRunning),
+		placementPod("kube-system", "dns-1", "node-b", "dns", "100m", corev1.PodRunning),
+		placementPod("shop", "job-1", "node-b", "job", "3", corev1.PodSucceeded),
+		placementPod("shop", "web-3", "", "web", "1", corev1.PodPending),
+	}
+	sel, _ := labels.Parse("app=web")
+	placements, matched := buildPodPlacement(nodes, pods, "shop", sel)
+	if matched != 3 || len(placements) != 3 {
+		t.Fatalf("matched = %d, placements = %+v", matched, placements)
+	}
+	a, b, pending := placements[0], placements[1], placements[2]
+	if a.Name != "node-a" || a.ReqCPU != 3800 || len(a.Matched) != 2 || a.RequestPercent() != 95 {
+		t.Errorf("node-a = %+v (%.0f%%)", a, a.RequestPercent())
+	}
+	if b.ReqCPU != 100 || b.TotalPods != 1 || len(b.Matched) != 0 {
+		t.Errorf("node-b should count dns but not the completed job or draw any pod: %+v", b)
+	}
+	if !pending.Unscheduled || len(pending.Matched) != 1 {
+		t.Errorf("unscheduled = %+v", pending)
+	}
+
+	findings, actions, steps := podPlacementFindings(placements)
+	got := strings.Join(findings, "\n")
+	for _, want := range []string{
+		"1 pod(s) are not scheduled",
+		"Node node-a is hot: requests are 95% of allocatable CPU",
+		"imbalanced across nodes: node-a is at 95% while node-b is at 2%",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("findings missing %q:\n%s", want, got)
+		}
+	}
+	if len(actions) != 3 || len(steps) != 3 {
+		t.Errorf("actions = %v, steps = %+v", actions, steps)
+	}
+
+	diagram := podPlacementDiagram(placements, true, 0)
+	for _, want := range []string{"subgraph node_node_a", "Unscheduled (1 pod(s))", "style node_node_a fill:#ffcccc", "2000m / none"} {
+		if !strings.Contains(diagram, want) {
+			t.Errorf("diagram missing %q:\n%s", want, diagram)
+		}
+	}
+	if strings.Contains(diagram, "node_node_b") {
+		t.Errorf("filtered diagram should leave out node-b:\n%s", diagram)
+	}
+	if !strings.Contains(podPlacementDiagram(placements, false, 0), "no pods") {
+		t.Error("unfiltered diagram should draw idle nodes")
+	}
+}
@@ -18,6 +18,8 @@ type listPodsInput struct {
 	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
 	LabelSelector string `json:"label_selector,omitempty" jsonschema:"Label selector filter (e.g. app=nginx)"`
 	FieldSelector string `json:"field_selector,omitempty" jsonschema:"Field selector filter (e.g. status.phase=Running)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 // --- get_pod_detail ---
@@ -47,10 +49,11 @@ func registerPodTools(server *mcp.Server, client *k8s.ClusterClient) {
 		ns := util.NamespaceOrAll(input.Namespace)
 		opts := util.ListOptions(input.LabelSelector, input.FieldSelector)
 
-		pods, err := client.ListPods(ctx, ns, opts)
+		page, err := client.ListPodsPage(ctx, ns, util.PageOptions(opts, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing pods", err), nil, nil
 		}
+		pods := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "STATUS", "READY", "RESTARTS", "AGE", "NODE"}
 		rows := make([][]string, 0, len(pods))
@@ -72,6 +75,7 @@ func registerPodTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("pods", len(pods))))
+		sb.WriteString(pageFooter("list_pods", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -218,22 +222,27 @@ func registerPodTools(server *mcp.Server, client *k8s.ClusterClient) {
 func podPhaseReason(p *corev1.Pod) string {
 	// Check container statuses for more specific reasons
 	for _, cs := range p.Status.ContainerStatuses {
-		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "PodInitializing" {
 			return cs.State.Waiting.Reason
 		}
 		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
 			return cs.State.Terminated.Reason
 		}
 	}
-	// Check init container statuses
+	// Check init container statuses; completed init containers are not a reason
 	for _, cs := range p.Status.InitContainerStatuses {
 		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
 			return "Init:" + cs.State.Waiting.Reason
 		}
-		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" && cs.State.Terminated.ExitCode != 0 {
 			return "Init:" + cs.State.Terminated.Reason
 		}
 	}
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "PodInitializing" {
+			return "PodInitializing"
+		}
+	}
 	if p.Status.Reason != "" {
 		return p.Status.Reason
 	}
@@ -11,12 +11,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
 )
 
 type listNetworkPoliciesInput struct {
-	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type analyzePodConnectivityInput struct {
@@ -25,11 +28,15 @@ type analyzePodConnectivityInput struct {
 }
 
 type listHPAsInput struct {
-	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type listPDBsInput struct {
-	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 func registerPolicyTools(server *mcp.Server, client *k8s.ClusterClient) {
@@ -39,10 +46,11 @@ func registerPolicyTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Description: "List network policies with pod selectors, ingress/egress rule counts, and policy types. Use namespace='all' for all namespaces. Useful for understanding network segmentation.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listNetworkPoliciesInput) (*mcp.CallToolResult, any, error) {
 		ns := util.NamespaceOrAll(input.Namespace)
-		policies, err := client.ListNetworkPolicies(ctx, ns, metav1.ListOptions{})
+		page, err := client.ListNetworkPoliciesPage(ctx, ns, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing network policies", err), nil, nil
 		}
+		policies := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "POD-SELECTOR", "INGRESS-RULES", "EGRESS-RULES", "POLICY-TYPES", "AGE"}
 		rows := make([][]string, 0, len(policies))
@@ -94,6 +102,7 @@ func registerPolicyTools(server *mcp.Server, client *k8s.ClusterClient) {
 				}
 			}
 		}
+		sb.WriteString(pageFooter("list_network_policies", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -133,7 +142,7 @@ func registerPolicyTools(server *mcp.Server, client *k8s.ClusterClient) {
 		}
 
 		if len(matchingPolicies) == 0 {
-			sb.WriteString(util.FormatFinding("INFO", "No network policies select this pod — all traffic is allowed by default"))
+			sb.WriteString(findings.Format("KD-NET-001", "INFO", "No network policies select this pod — all traffic is allowed by default"))
 			sb.WriteString("\n\n")
 			sb.WriteString("CONNECTIVITY DIAGRAM:\n")
 			mermaid := fmt.Sprintf("graph LR\n    ANY[Any Source] -->|allowed| POD[Pod: %s]\n    POD -->|allowed| ANY2[Any Destination]", pod.Name)
@@ -181,7 +190,7 @@ func registerPolicyTools(server *mcp.Server, client *k8s.ClusterClient) {
 			for _, pt := range np.Spec.PolicyTypes {
 				if pt == networkingv1.PolicyTypeIngress {
 					if len(np.Spec.Ingress) == 0 {
-						sb.WriteString(util.FormatFinding("WARNING", "  Ingress policy with no rules — all ingress DENIED"))
+						sb.WriteString(findings.Format("KD-NET-003", "WARNING", "  Ingress policy with no rules — all ingress DENIED"))
 						sb.WriteString("\n")
 					} else {
 						sb.WriteString(fmt.Sprintf("    %d ingress rules defined\n", len(np.Spec.Ingress)))
@@ -189,7 +198,7 @@ func registerPolicyTools(server *mcp.Server, client *k8s.ClusterClient) {
 				}
 				if pt == networkingv1.PolicyTypeEgress {
 					if len(np.Spec.Egress) == 0 {
-						sb.WriteString(util.FormatFinding("WARNING", "  Egress policy with no rules — all egress DENIED"))
+						sb.WriteString(findings.Format("KD-NET-003", "WARNING", "  Egress policy with no rules — all egress DENIED"))
 						sb.WriteString("\n")
 					} else {
 						sb.WriteString(fmt.Sprintf("    %d egress rules defined\n", len(np.Spec.Egress)))
@@ -242,10 +251,11 @@ func registerPolicyTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Description: "List Horizontal Pod Autoscalers with target reference, current/target metrics, min/max/current replicas, and conditions. Use namespace='all' for all namespaces.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listHPAsInput) (*mcp.CallToolResult, any, error) {
 		ns := util.NamespaceOrAll(input.Namespace)
-		hpas, err := client.ListHPAs(ctx, ns, metav1.ListOptions{})
+		page, err := client.ListHPAsPage(ctx, ns, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing HPAs", err), nil, nil
 		}
+		hpas := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "REFERENCE", "MIN", "MAX", "CURRENT", "AGE"}
 		rows := make([][]string, 0, len(hpas))
@@ -304,6 +314,7 @@ func registerPolicyTools(server *mcp.Server, client *k8s.ClusterClient) {
 				}
 			}
 		}
+		sb.WriteString(pageFooter("list_hpas", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -314,10 +325,11 @@ func registerPolicyTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Description: "List Pod Disruption Budgets with min-available, max-unavailable, current/expected pods, and disruptions allowed. Warns when disruptions allowed is 0. Use namespace='all' for all namespaces.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listPDBsInput) (*mcp.CallToolResult, any, error) {
 		ns := util.NamespaceOrAll(input.Namespace)
-		pdbs, err := client.ListPodDisruptionBudgets(ctx, ns, metav1.ListOptions{})
+		page, err := client.ListPodDisruptionBudgetsPage(ctx, ns, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing PDBs", err), nil, nil
 		}
+		pdbs := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "MIN-AVAILABLE", "MAX-UNAVAILABLE", "CURRENT", "EXPECTED", "ALLOWED-DISRUPTIONS", "AGE"}
 		rows := make([][]string, 0, len(pdbs))
@@ -351,9 +363,10 @@ func registerPolicyTools(server *mcp.Server, client *k8s.ClusterClient) {
 		// Warn on zero disruptions allowed
 		for _, pdb := range pdbs {
 			if pdb.Status.DisruptionsAllowed == 0 && pdb.Status.ExpectedPods > 0 {
-				sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatFinding("WARNING", fmt.Sprintf("PDB '%s/%s' has 0 disruptions allowed — voluntary disruptions (node drains, rolling updates) will be blocked", pdb.Namespace, pdb.Name))))
+				sb.WriteString(fmt.Sprintf("\n%s\n", findings.Format("KD-WL-012", "WARNING", fmt.Sprintf("PDB '%s/%s' has 0 disruptions allowed — voluntary disruptions (node drains, rolling updates) will be blocked", pdb.Namespace, pdb.Name))))
 			}
 		}
+		sb.WriteString(pageFooter("list_pdbs", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
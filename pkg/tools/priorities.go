@@ -0,0 +1,290 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// defaultCriticalLabels are the label conventions analyze_priorities treats as
+// marking a business-critical workload when no critical_labels are given.
+var defaultCriticalLabels = []string{
+	"criticality=critical", "criticality=high",
+	"tier=critical", "tier=tier-1", "tier=tier1",
+	"business-critical=true", "critical=true",
+	"priority=critical", "priority=high",
+}
+
+// prioritiesMaxWorkloads caps the workload assignment table.
+const prioritiesMaxWorkloads = 40
+
+type analyzePrioritiesInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace to analyze (empty for all namespaces)"`
+	CriticalLabels string `json:"critical_labels,omitempty" jsonschema:"Comma-separated key=value pod labels that mark business-critical workloads (default: criticality=critical|high, tier=critical|tier-1, business-critical=true, critical=true, priority=critical|high)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// priorityAssignment is the priority of one workload's running pods.
+type priorityAssignment struct {
+	Namespace string
+	Workload  string
+	Class     string
+	Priority  int32
+	QOS       corev1.PodQOSClass
+	Pods      int
+	Critical  string
+}
+
+func registerPriorityTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// analyze_priorities
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "analyze_priorities",
+		Description: "Report PriorityClasses (value, global default, preemption policy, pods using each), the priority and QoS class of every workload, " +
+			"business-critical workloads (by label convention) running at default priority, and recent Preempted events. " +
+			"Use this to see who is evicted or preempted first under resource pressure.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzePrioritiesInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+		criticalLabels := defaultCriticalLabels
+		if input.CriticalLabels != "" {
+			criticalLabels = util.SplitList(input.CriticalLabels)
+		}
+
+		pods, err := client.ListPods(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		classes, classErr := client.ListPriorityClasses(ctx)
+		if classErr != nil && !util.IsScopeError(classErr) {
+			return util.HandleK8sError("listing priority classes", classErr), nil, nil
+		}
+		preemptions, eventsErr := client.ListEventsMatching(ctx, ns, metav1.ListOptions{}, k8s.EventFilter{Reasons: []string{"Preempted"}, Kind: "Pod"})
+
+		var defaultPriority int32
+		defaultClass := ""
+		for _, pc := range classes {
+			if pc.GlobalDefault {
+				defaultPriority, defaultClass = pc.Value, pc.Name
+			}
+		}
+		assignments := priorityAssignments(pods, criticalLabels)
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Priorities and Preemption (namespace: %s)", displayNS(ns))))
+		sb.WriteString("\n")
+		if defaultClass != "" {
+			sb.WriteString(util.FormatKeyValue("Default Priority", fmt.Sprintf("%d (globalDefault class %s)", defaultPriority, defaultClass)) + "\n")
+		} else if classErr == nil {
+			sb.WriteString(util.FormatKeyValue("Default Priority", "0 (no globalDefault PriorityClass)") + "\n")
+		}
+
+		// Priority classes
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("PRIORITY CLASSES"))
+		sb.WriteString("\n")
+		if classErr != nil {
+			writeScopeSkipped(&sb, classErr)
+		} else {
+			classPods := make(map[string]int)
+			for _, a := range assignments {
+				classPods[a.Class] += a.Pods
+			}
+			sb.WriteString(util.FormatTable([]string{"NAME", "VALUE", "GLOBAL DEFAULT", "PREEMPTION", "PODS", "DESCRIPTION"}, priorityClassRows(classes, classPods)))
+		}
+
+		// Workload assignments
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("WORKLOADS (lowest priority first: evicted and preempted first)"))
+		sb.WriteString("\n")
+		if len(assignments) == 0 {
+			sb.WriteString("  No running pods.\n")
+		} else {
+			var rows [][]string
+			for i, a := range assignments {
+				if i == prioritiesMaxWorkloads {
+					break
+				}
+				rows = append(rows, []string{a.Namespace, truncateName(a.Workload, 40), valueOrNone(a.Class), fmt.Sprintf("%d", a.Priority), string(a.QOS), fmt.Sprintf("%d", a.Pods), valueOrNone(a.Critical)})
+			}
+			sb.WriteString(util.FormatTable([]string{"NAMESPACE", "WORKLOAD", "PRIORITY CLASS", "PRIORITY", "QOS", "PODS", "CRITICAL LABEL"}, rows))
+			if len(assignments) > prioritiesMaxWorkloads {
+				sb.WriteString(fmt.Sprintf("  ... and %d more workloads\n", len(assignments)-prioritiesMaxWorkloads))
+			}
+		}
+
+		// At risk
+		var atRisk []priorityAssignment
+		for _, a := range assignments {
+			if a.Critical != "" && a.Priority <= defaultPriority {
+				atRisk = append(atRisk, a)
+			}
+		}
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("BUSINESS-CRITICAL AT DEFAULT PRIORITY"))
+		sb.WriteString("\n")
+		if len(atRisk) == 0 {
+			sb.WriteString(fmt.Sprintf("  None (critical labels: %s).\n", strings.Join(criticalLabels, ", ")))
+		}
+		for _, a := range atRisk {
+			sb.WriteString(fmt.Sprintf("  %s/%s (%s, priority %d, %s)\n", a.Namespace, a.Workload, a.Critical, a.Priority, a.QOS))
+			severity := "WARNING"
+			if a.QOS == corev1.PodQOSBestEffort {
+				severity = "CRITICAL"
+			}
+			findingLines = append(findingLines, findings.Format("KD-WL-010", severity, fmt.Sprintf("Business-critical workload %s/%s (%s) runs at default priority %d with %s QoS; higher-priority pods can preempt it and the kubelet evicts it as early as any other pod.",
+				a.Namespace, a.Workload, a.Critical, a.Priority, a.QOS)))
+			actions = append(actions, fmt.Sprintf("Set priorityClassName on %s/%s to a class above the default (create one, e.g. business-critical with value 100000, if none exists).", a.Namespace, a.Workload))
+		}
+
+		// Preemptions
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("RECENT PREEMPTIONS"))
+		sb.WriteString("\n")
+		if eventsErr != nil {
+			sb.WriteString(fmt.Sprintf("  (could not list events: %v)\n", eventsErr))
+		} else if len(preemptions) == 0 {
+			sb.WriteString("  No Preempted events.\n")
+		}
+		preemptedByNS := make(map[string]int)
+		for _, e := range preemptions {
+			count := int(max(e.Count, 1))
+			preemptedByNS[e.Namespace] += count
+			sb.WriteString(fmt.Sprintf("  %s ago %s/%s: %s", util.FormatAge(k8s.EventTime(&e)), e.Namespace, e.InvolvedObject.Name, truncateName(e.Message, 160)))
+			if count > 1 {
+				sb.WriteString(fmt.Sprintf(" (x%d)", count))
+			}
+			sb.WriteString("\n")
+		}
+		for _, n := range sortedKeysInt(preemptedByNS) {
+			findingLines = append(findingLines, findings.Format("KD-WL-010", "WARNING", fmt.Sprintf("%d pods in %s were preempted by higher-priority pods.", preemptedByNS[n], n)))
+		}
+		if len(preemptedByNS) > 0 {
+			steps = append(steps, nextStep("analyze_node_capacity", "preemption means the cluster ran out of room; check node capacity"))
+			actions = append(actions, "Add node capacity (or enable the cluster autoscaler) so high-priority pods stop displacing others; preemption only happens when no node has room.")
+		}
+
+		if classErr == nil {
+			findingLines = append(findingLines, priorityClassFindings(classes)...)
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  No priority or preemption risks found.\n")
+		}
+		for _, f := range dedupe(findingLines) {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// priorityAssignments groups running pods by workload, sorted from the lowest
+// priority (first to be evicted or preempted) to the highest.
+func priorityAssignments(pods []corev1.Pod, criticalLabels []string) []priorityAssignment {
+	byKey := make(map[string]*priorityAssignment)
+	for i := range pods {
+		p := &pods[i]
+		if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		var priority int32
+		if p.Spec.Priority != nil {
+			priority = *p.Spec.Priority
+		}
+		workload := podWorkloadName(p)
+		key := fmt.Sprintf("%s/%s/%s/%d", p.Namespace, workload, p.Spec.PriorityClassName, priority)
+		a, ok := byKey[key]
+		if !ok {
+			a = &priorityAssignment{Namespace: p.Namespace, Workload: workload, Class: p.Spec.PriorityClassName, Priority: priority, QOS: p.Status.QOSClass}
+			byKey[key] = a
+		}
+		a.Pods++
+		if a.Critical == "" {
+			a.Critical = matchCriticalLabel(p.Labels, criticalLabels)
+		}
+	}
+	assignments := make([]priorityAssignment, 0, len(byKey))
+	for _, a := range byKey {
+		assignments = append(assignments, *a)
+	}
+	sort.Slice(assignments, func(i, j int) bool {
+		a, b := assignments[i], assignments[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Workload < b.Workload
+	})
+	return assignments
+}
+
+// matchCriticalLabel returns the first key=value convention the labels carry,
+// compared case-insensitively on the value.
+func matchCriticalLabel(labels map[string]string, conventions []string) string {
+	for _, c := range conventions {
+		key, value, _ := strings.Cut(c, "=")
+		if v, ok := labels[key]; ok && strings.EqualFold(v, value) {
+			return key + "=" + v
+		}
+	}
+	return ""
+}
+
+// priorityClassRows renders PriorityClasses from highest to lowest value.
+func priorityClassRows(classes []schedulingv1.PriorityClass, pods map[string]int) [][]string {
+	sorted := append([]schedulingv1.PriorityClass(nil), classes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Value > sorted[j].Value })
+	rows := make([][]string, 0, len(sorted))
+	for _, pc := range sorted {
+		globalDefault := ""
+		if pc.GlobalDefault {
+			globalDefault = "yes"
+		}
+		preemption := string(corev1.PreemptLowerPriority)
+		if pc.PreemptionPolicy != nil {
+			preemption = string(*pc.PreemptionPolicy)
+		}
+		rows = append(rows, []string{pc.Name, fmt.Sprintf("%d", pc.Value), globalDefault, preemption, fmt.Sprintf("%d", pods[pc.Name]), truncateName(pc.Description, 60)})
+	}
+	return rows
+}
+
+// priorityClassFindings flags cluster-wide PriorityClass setup problems.
+func priorityClassFindings(classes []schedulingv1.PriorityClass) []string {
+	var findingLines []string
+	user := 0
+	for _, pc := range classes {
+		if strings.HasPrefix(pc.Name, "system-") {
+			continue
+		}
+		user++
+		if pc.GlobalDefault && pc.Value > 0 && (pc.PreemptionPolicy == nil || *pc.PreemptionPolicy != corev1.PreemptNever) {
+			findingLines = append(findingLines, findings.Format("KD-WL-010", "WARNING", fmt.Sprintf("Global default PriorityClass %s has value %d and may preempt: every pod without a class can preempt pods below it, which defeats tiering.", pc.Name, pc.Value)))
+		}
+	}
+	if user == 0 {
+		findingLines = append(findingLines, findings.Format("KD-WL-010", "INFO", "No application PriorityClasses exist; every workload outside kube-system runs at the same priority, so preemption and eviction cannot favor critical services."))
+	}
+	return findingLines
+}
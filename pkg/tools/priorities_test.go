@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPriorityAssignments(t *testing.T) {
+	high := int32(100000)
+	pod := func(name, workload, class string, priority *int32, labels map[string]string, phase corev1.PodPhase) corev1.Pod {
+		p := withOwner(testPod("shop", name, "", labels), "StatefulSet", workload)
+		p.Spec.PriorityClassName, p.Spec.Priority = class, priority
+		p.Status.Phase, p.Status.QOSClass = phase, corev1.PodQOSBurstable
+		return p
+	}
+	pods := []corev1.Pod{
+		pod("payments-0", "payments", "", nil, map[string]string{"tier": "Tier-1"}, corev1.PodRunning),
+		pod("payments-1", "payments", "", nil, map[string]string{"tier": "Tier-1"}, corev1.PodRunning),
+		pod("db-0", "db", "business-critical", &high, map[string]string{"criticality": "critical"}, corev1.PodRunning),
+		pod("batch-0", "batch", "", nil, nil, corev1.PodSucceeded),
+	}
+
+	got := priorityAssignments(pods, defaultCriticalLabels)
+	if len(got) != 2 {
+		t.Fatalf("priorityAssignments() = %+v, want 2 workloads (completed pods skipped)", got)
+	}
+	if got[0].Workload != "payments" || got[0].Pods != 2 || got[0].Critical != "tier=Tier-1" {
+		t.Errorf("first assignment = %+v, want payments with 2 pods and critical label tier=Tier-1", got[0])
+	}
+	if got[1].Workload != "db" || got[1].Priority != high || got[1].Class != "business-critical" {
+		t.Errorf("second assignment = %+v, want db at priority %d", got[1], high)
+	}
+}
+
+func TestMatchCriticalLabel(t *testing.T) {
+	conventions := []string{"team.example.com/critical=yes"}
+	if got := matchCriticalLabel(map[string]string{"team.example.com/critical": "YES"}, conventions); got != "team.example.com/critical=YES" {
+		t.Errorf("matchCriticalLabel() = %q, want a case-insensitive value match", got)
+	}
+	if got := matchCriticalLabel(map[string]string{"critical": "true"}, conventions); got != "" {
+		t.Errorf("matchCriticalLabel() = %q, want no match outside the given conventions", got)
+	}
+}
+
+func TestPriorityClassFindings(t *testing.T) {
+	system := schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "system-node-critical"}, Value: 2000001000}
+	if got := strings.Join(priorityClassFindings([]schedulingv1.PriorityClass{system}), "\n"); !strings.Contains(got, "No application PriorityClasses") {
+		t.Errorf("priorityClassFindings() = %q, want the no-application-classes finding", got)
+	}
+
+	defaultClass := schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "standard"}, Value: 1000, GlobalDefault: true}
+	if got := strings.Join(priorityClassFindings([]schedulingv1.PriorityClass{system, defaultClass}), "\n"); !strings.Contains(got, "Global default PriorityClass standard") {
+		t.Errorf("priorityClassFindings() = %q, want a preempting global default finding", got)
+	}
+
+	never := corev1.PreemptNever
+	defaultClass.PreemptionPolicy = &never
+	if got := priorityClassFindings([]schedulingv1.PriorityClass{system, defaultClass}); len(got) != 0 {
+		t.Errorf("priorityClassFindings() = %q, want none for a non-preempting default", got)
+	}
+}
@@ -0,0 +1,329 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type analyzeProbesInput struct {
+	Namespace    string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	WorkloadName string `json:"workload_name,omitempty" jsonschema:"Name of a single Deployment, StatefulSet, or DaemonSet (empty for all workloads in the namespace)"`
+	WorkloadKind string `json:"workload_kind,omitempty" jsonschema:"Kind: Deployment, StatefulSet, or DaemonSet (default: Deployment)"`
+}
+
+// Probe thresholds used to flag configurations likely to cause restart storms.
+const (
+	// probeMinFailureWindowSeconds is the smallest period*failureThreshold window
+	// considered safe for a liveness probe.
+	probeMinFailureWindowSeconds = 10
+	// probeSlowStartDelaySeconds is the initialDelaySeconds above which an app is
+	// treated as slow-starting and should use a startupProbe instead.
+	probeSlowStartDelaySeconds = 60
+)
+
+// probeWorkload is a pod template together with the workload that owns it.
+type probeWorkload struct {
+	Kind     string
+	Name     string
+	Template corev1.PodTemplateSpec
+}
+
+func registerProbeTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// analyze_probes
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "analyze_probes",
+		Description: "Audit readiness, liveness, and startup probes for a namespace or a single workload. Flags missing probes, probes pointing at ports the container does not expose, aggressive timeouts/periods likely to cause restart storms, identical liveness/readiness endpoints, and slow-start apps without a startupProbe.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeProbesInput) (*mcp.CallToolResult, any, error) {
+		workloads, err := collectProbeWorkloads(ctx, client, input.Namespace, input.WorkloadName, input.WorkloadKind)
+		if err != nil {
+			return util.HandleK8sError("listing workloads", err), nil, nil
+		}
+
+		scope := input.Namespace
+		if input.WorkloadName != "" {
+			scope = fmt.Sprintf("%s/%s", input.Namespace, input.WorkloadName)
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Probe Analysis: %s", scope)))
+		sb.WriteString("\n\n")
+
+		if len(workloads) == 0 {
+			sb.WriteString("No Deployments, StatefulSets, or DaemonSets found.\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		headers := []string{"WORKLOAD", "CONTAINER", "LIVENESS", "READINESS", "STARTUP"}
+		var rows [][]string
+		var findingLines []string
+		var actions []string
+		critical, warning := 0, 0
+
+		for _, w := range workloads {
+			ref := fmt.Sprintf("%s/%s", w.Kind, w.Name)
+			for _, c := range w.Template.Spec.Containers {
+				rows = append(rows, []string{
+					truncateName(ref, 40),
+					c.Name,
+					describeProbe(c.LivenessProbe),
+					describeProbe(c.ReadinessProbe),
+					describeProbe(c.StartupProbe),
+				})
+
+				for _, f := range checkContainerProbes(c) {
+					findingLines = append(findingLines, findings.Format(f.rule, f.severity, fmt.Sprintf("%s container '%s': %s", ref, c.Name, f.message)))
+					if f.action != "" {
+						actions = append(actions, fmt.Sprintf("%s (%s, container '%s')", f.action, ref, c.Name))
+					}
+					switch f.severity {
+					case "CRITICAL":
+						critical++
+					case "WARNING":
+						warning++
+					}
+				}
+			}
+		}
+
+		sb.WriteString(util.FormatSubHeader("Probe Configuration"))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatTable(headers, rows))
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "All containers have sensible probe configuration"))
+			sb.WriteString("\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString(f)
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Summary"))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("  Workloads analyzed: %d\n", len(workloads)))
+		sb.WriteString(fmt.Sprintf("  Containers analyzed: %d\n", len(rows)))
+		sb.WriteString(fmt.Sprintf("  Critical: %d, Warnings: %d, Total findings: %d\n", critical, warning, len(findingLines)))
+
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, action := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, action))
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// collectProbeWorkloads returns the pod templates to analyze. If name is set,
+// only that workload is returned; otherwise all Deployments, StatefulSets, and
+// DaemonSets in the namespace are returned.
+func collectProbeWorkloads(ctx context.Context, client *k8s.ClusterClient, namespace, name, kind string) ([]probeWorkload, error) {
+	var workloads []probeWorkload
+
+	if name != "" {
+		switch strings.ToLower(kind) {
+		case "", "deployment":
+			d, err := client.GetDeployment(ctx, namespace, name)
+			if err != nil {
+				return nil, err
+			}
+			workloads = append(workloads, probeWorkload{Kind: "Deployment", Name: d.Name, Template: d.Spec.Template})
+		case "statefulset":
+			sts, err := client.GetStatefulSet(ctx, namespace, name)
+			if err != nil {
+				return nil, err
+			}
+			workloads = append(workloads, probeWorkload{Kind: "StatefulSet", Name: sts.Name, Template: sts.Spec.Template})
+		case "daemonset":
+			ds, err := client.GetDaemonSet(ctx, namespace, name)
+			if err != nil {
+				return nil, err
+			}
+			workloads = append(workloads, probeWorkload{Kind: "DaemonSet", Name: ds.Name, Template: ds.Spec.Template})
+		default:
+			return nil, fmt.Errorf("unsupported workload kind %q (use Deployment, StatefulSet, or DaemonSet)", kind)
+		}
+		return workloads, nil
+	}
+
+	deployments, err := client.ListDeployments(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deployments {
+		workloads = append(workloads, probeWorkload{Kind: "Deployment", Name: d.Name, Template: d.Spec.Template})
+	}
+
+	statefulsets, err := client.ListStatefulSets(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statefulsets {
+		workloads = append(workloads, probeWorkload{Kind: "StatefulSet", Name: s.Name, Template: s.Spec.Template})
+	}
+
+	daemonsets, err := client.ListDaemonSets(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ds := range daemonsets {
+		workloads = append(workloads, probeWorkload{Kind: "DaemonSet", Name: ds.Name, Template: ds.Spec.Template})
+	}
+
+	return workloads, nil
+}
+
+// probeFinding is a single probe misconfiguration for a container.
+type probeFinding struct {
+	rule     string
+	severity string
+	message  string
+	action   string
+}
+
+// checkContainerProbes runs all probe checks against a container spec.
+func checkContainerProbes(c corev1.Container) []probeFinding {
+	var out []probeFinding
+
+	if c.ReadinessProbe == nil {
+		out = append(out, probeFinding{"KD-PRB-001", "WARNING", "no readinessProbe — traffic is sent before the app is ready",
+			"Add a readinessProbe so Services only route to ready pods"})
+	}
+	if c.LivenessProbe == nil {
+		out = append(out, probeFinding{"KD-PRB-006", "INFO", "no livenessProbe — hung processes will not be restarted",
+			"Consider a livenessProbe that checks process health only (not dependencies)"})
+	}
+
+	probes := []struct {
+		kind  string
+		probe *corev1.Probe
+	}{
+		{"liveness", c.LivenessProbe},
+		{"readiness", c.ReadinessProbe},
+		{"startup", c.StartupProbe},
+	}
+	for _, p := range probes {
+		if p.probe == nil {
+			continue
+		}
+		port, ok := probePort(p.probe)
+		if !ok {
+			continue
+		}
+		if port.Type == intstr.String {
+			if !containerHasNamedPort(c, port.StrVal) {
+				out = append(out, probeFinding{"KD-PRB-004", "CRITICAL", fmt.Sprintf("%s probe references named port '%s' which the container does not define", p.kind, port.StrVal),
+					fmt.Sprintf("Fix the %s probe port or add a container port named '%s'", p.kind, port.StrVal)})
+			}
+		} else if len(c.Ports) > 0 && !containerHasPortNumber(c, port.IntVal) {
+			out = append(out, probeFinding{"KD-PRB-004", "WARNING", fmt.Sprintf("%s probe targets port %d which is not in the container's ports list", p.kind, port.IntVal),
+				fmt.Sprintf("Verify the app listens on port %d or point the %s probe at an exposed port", port.IntVal, p.kind)})
+		}
+	}
+
+	if lp := c.LivenessProbe; lp != nil {
+		period, timeout, failure := probeTimings(lp)
+		window := period * failure
+		if window < probeMinFailureWindowSeconds {
+			out = append(out, probeFinding{"KD-PRB-002", "WARNING", fmt.Sprintf("aggressive livenessProbe (period %ds x failureThreshold %d = %ds) — brief stalls will trigger restarts", period, failure, window),
+				"Increase livenessProbe periodSeconds or failureThreshold so the failure window is at least 10s"})
+		}
+		if timeout <= 1 && period <= 5 {
+			out = append(out, probeFinding{"KD-PRB-002", "WARNING", fmt.Sprintf("livenessProbe timeout %ds with period %ds — GC pauses or load spikes can cause restart storms", timeout, period),
+				"Raise livenessProbe timeoutSeconds (e.g. 3-5s)"})
+		}
+		if c.ReadinessProbe != nil && probeHandlerKey(lp) == probeHandlerKey(c.ReadinessProbe) {
+			out = append(out, probeFinding{"KD-PRB-003", "WARNING", fmt.Sprintf("liveness and readiness probes use the same endpoint (%s) — a dependency outage restarts every replica", probeHandlerKey(lp)),
+				"Use a lightweight liveness endpoint that does not check downstream dependencies"})
+		}
+		if c.StartupProbe == nil && lp.InitialDelaySeconds >= probeSlowStartDelaySeconds {
+			out = append(out, probeFinding{"KD-PRB-005", "WARNING", fmt.Sprintf("livenessProbe initialDelaySeconds is %ds but no startupProbe is set", lp.InitialDelaySeconds),
+				"Replace the long initialDelaySeconds with a startupProbe for the slow-start phase"})
+		}
+	}
+
+	return out
+}
+
+// probeTimings returns period, timeout, and failureThreshold with Kubernetes defaults applied.
+func probeTimings(p *corev1.Probe) (period, timeout, failure int32) {
+	period, timeout, failure = p.PeriodSeconds, p.TimeoutSeconds, p.FailureThreshold
+	if period == 0 {
+		period = 10
+	}
+	if timeout == 0 {
+		timeout = 1
+	}
+	if failure == 0 {
+		failure = 3
+	}
+	return period, timeout, failure
+}
+
+// probePort returns the port a probe targets, if it uses a network handler.
+func probePort(p *corev1.Probe) (intstr.IntOrString, bool) {
+	switch {
+	case p.HTTPGet != nil:
+		return p.HTTPGet.Port, true
+	case p.TCPSocket != nil:
+		return p.TCPSocket.Port, true
+	case p.GRPC != nil:
+		return intstr.FromInt32(p.GRPC.Port), true
+	}
+	return intstr.IntOrString{}, false
+}
+
+// probeHandlerKey returns a comparable description of a probe's handler.
+func probeHandlerKey(p *corev1.Probe) string {
+	switch {
+	case p.HTTPGet != nil:
+		return fmt.Sprintf("http %s%s", p.HTTPGet.Port.String(), p.HTTPGet.Path)
+	case p.TCPSocket != nil:
+		return fmt.Sprintf("tcp %s", p.TCPSocket.Port.String())
+	case p.GRPC != nil:
+		return fmt.Sprintf("grpc %d", p.GRPC.Port)
+	case p.Exec != nil:
+		return fmt.Sprintf("exec %s", strings.Join(p.Exec.Command, " "))
+	}
+	return "unknown"
+}
+
+// describeProbe returns a short summary of a probe for table output.
+func describeProbe(p *corev1.Probe) string {
+	if p == nil {
+		return "-"
+	}
+	period, timeout, failure := probeTimings(p)
+	return fmt.Sprintf("%s (p=%ds t=%ds f=%d)", truncateName(probeHandlerKey(p), 30), period, timeout, failure)
+}
+
+func containerHasNamedPort(c corev1.Container, name string) bool {
+	for _, p := range c.Ports {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containerHasPortNumber(c corev1.Container, port int32) bool {
+	for _, p := range c.Ports {
+		if p.ContainerPort == port {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestCheckContainerProbes(t *testing.T) {
+	httpProbe := func(port intstr.IntOrString, path string) *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler:     corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Port: port, Path: path}},
+			PeriodSeconds:    10,
+			TimeoutSeconds:   1,
+			FailureThreshold: 3,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		container corev1.Container
+		want      []string
+	}{
+		{
+			name:      "missing probes",
+			container: corev1.Container{Name: "app"},
+			want:      []string{"no readinessProbe", "no livenessProbe"},
+		},
+		{
+			name: "unknown named port",
+			container: corev1.Container{
+				Name:           "app",
+				Ports:          []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+				ReadinessProbe: httpProbe(intstr.FromString("metrics"), "/ready"),
+				LivenessProbe:  httpProbe(intstr.FromInt32(8080), "/live"),
+			},
+			want: []string{"named port 'metrics'"},
+		},
+		{
+			name: "identical endpoints",
+			container: corev1.Container{
+				Name:           "app",
+				Ports:          []corev1.ContainerPort{{ContainerPort: 8080}},
+				ReadinessProbe: httpProbe(intstr.FromInt32(8080), "/health"),
+				LivenessProbe:  httpProbe(intstr.FromInt32(8080), "/health"),
+			},
+			want: []string{"same endpoint"},
+		},
+		{
+			name: "aggressive liveness",
+			container: corev1.Container{
+				Name:           "app",
+				ReadinessProbe: httpProbe(intstr.FromInt32(8080), "/ready"),
+				LivenessProbe: &corev1.Probe{
+					ProbeHandler:     corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(8080)}},
+					PeriodSeconds:    2,
+					TimeoutSeconds:   1,
+					FailureThreshold: 1,
+				},
+			},
+			want: []string{"aggressive livenessProbe", "restart storms"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, f := range checkContainerProbes(tt.container) {
+				messages = append(messages, f.message)
+			}
+			joined := strings.Join(messages, "\n")
+			for _, w := range tt.want {
+				if !strings.Contains(joined, w) {
+					t.Errorf("expected finding containing %q, got:\n%s", w, joined)
+				}
+			}
+		})
+	}
+}
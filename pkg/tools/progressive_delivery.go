@@ -0,0 +1,556 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+var (
+	argoRolloutGVR   = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+	argoAnalysisGVR  = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "analysisruns"}
+	flaggerCanaryGVR = schema.GroupVersionResource{Group: "flagger.app", Version: "v1beta1", Resource: "canaries"}
+)
+
+const (
+	// argoPodHashLabel links an AnalysisRun to the ReplicaSet it analyzed.
+	argoPodHashLabel = "rollouts-pod-template-hash"
+	// maxAnalysisRuns is how many recent analysis runs are shown per rollout.
+	maxAnalysisRuns = 3
+)
+
+type diagnoseRolloutInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace (empty = all namespaces)"`
+	Name           string `json:"name,omitempty" jsonschema:"Argo Rollout or Flagger Canary name, or the Deployment a Canary targets (empty = every rollout in scope)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// argoRollout is the part of an Argo Rollout that explains where a
+// progressive rollout stands.
+type argoRollout struct {
+	Namespace string
+	Name      string
+	Strategy  string // canary or blueGreen
+	Replicas  int64
+	Ready     int64
+	Updated   int64
+	Phase     string
+	Message   string
+	Paused    bool // spec.paused, set by a manual pause
+	Aborted   bool
+	// PauseReasons are the controller's pause conditions, e.g. CanaryPauseStep.
+	PauseReasons []string
+	PausedSince  time.Time
+	// Step is the current canary step index; Steps is the step count.
+	Step, Steps int64
+	// PauseIndefinite is set when the current step is a pause without a
+	// duration, which only a promotion ends.
+	PauseIndefinite bool
+	// CanaryWeight and StableWeight are the traffic split; WeightSource
+	// says whether the router reported them or they come from setWeight.
+	CanaryWeight, StableWeight int64
+	WeightSource               string
+	TrafficRouter              string
+	ActiveService              string
+	PreviewService             string
+	PodHash                    string
+	DeadlineExceeded           string // Progressing condition message when the deadline passed
+}
+
+// analysisMetric is one metric of an Argo AnalysisRun.
+type analysisMetric struct {
+	Name         string
+	Phase        string
+	Count        int64
+	Failed       int64
+	Inconclusive int64
+	LastValue    string
+	LastMessage  string
+}
+
+// analysisRun is an Argo AnalysisRun started by a rollout.
+type analysisRun struct {
+	Name    string
+	Rollout string
+	PodHash string
+	Phase   string
+	Message string
+	Created time.Time
+	Metrics []analysisMetric
+}
+
+// flaggerCanary is the part of a Flagger Canary that explains its analysis.
+type flaggerCanary struct {
+	Namespace      string
+	Name           string
+	TargetKind     string
+	TargetName     string
+	Provider       string
+	Phase          string
+	Weight         int64
+	FailedChecks   int64
+	Threshold      int64
+	MaxWeight      int64
+	StepWeight     int64
+	Iterations     int64
+	Message        string
+	LastTransition time.Time
+}
+
+func registerProgressiveDeliveryTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "diagnose_rollout",
+		Description: "Diagnose progressive delivery with Argo Rollouts (canary and blue/green) and Flagger canaries. Shows each rollout's " +
+			"phase, current step, traffic split weights, and recent analysis run results, and explains why a rollout is paused, " +
+			"aborted, or degraded with the command that moves it forward. Use this when a Deployment looks stuck but is managed by a Rollout or Canary.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input diagnoseRolloutInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+
+		var gaps dataGaps
+		list := func(section string, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, bool) {
+			items, err := client.ListCustomResources(ctx, gvr, ns, metav1.ListOptions{})
+			if apierrors.IsNotFound(err) {
+				return nil, false
+			}
+			gaps.record(section, err)
+			return items, true
+		}
+		rolloutItems, argoInstalled := list("Argo Rollouts", argoRolloutGVR)
+		canaryItems, flaggerInstalled := list("Flagger Canaries", flaggerCanaryGVR)
+		if !argoInstalled && !flaggerInstalled {
+			return util.SuccessResult("Neither Argo Rollouts nor Flagger is installed (no rollouts.argoproj.io or canaries.flagger.app CRD). " +
+				"Use correlate_rollouts or get_deployment_detail for plain Deployments.\n"), nil, nil
+		}
+
+		var rollouts []argoRollout
+		for i := range rolloutItems {
+			r := parseArgoRollout(&rolloutItems[i])
+			if input.Name == "" || r.Name == input.Name {
+				rollouts = append(rollouts, r)
+			}
+		}
+		var canaries []flaggerCanary
+		for i := range canaryItems {
+			c := parseFlaggerCanary(&canaryItems[i])
+			if input.Name == "" || c.Name == input.Name || c.TargetName == input.Name {
+				canaries = append(canaries, c)
+			}
+		}
+		if input.Name != "" && len(rollouts) == 0 && len(canaries) == 0 && len(gaps) == 0 {
+			return util.ErrorResult("no Argo Rollout or Flagger Canary named %q in %s", input.Name, displayNS(ns)), nil, nil
+		}
+
+		runs := make(map[string][]analysisRun) // namespace/rollout -> runs, newest first
+		if len(rollouts) > 0 {
+			runItems, _ := list("Analysis Runs", argoAnalysisGVR)
+			for i := range runItems {
+				run := parseAnalysisRun(&runItems[i])
+				key := runItems[i].GetNamespace() + "/" + run.Rollout
+				runs[key] = append(runs[key], run)
+			}
+			for key := range runs {
+				sort.Slice(runs[key], func(i, j int) bool { return runs[key][i].Created.After(runs[key][j].Created) })
+			}
+		}
+
+		title := displayNS(ns)
+		if input.Name != "" {
+			title += "/" + input.Name
+		}
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Rollout Diagnosis: %s", title)))
+		sb.WriteString("\n\n")
+
+		var rows [][]string
+		for _, r := range rollouts {
+			rows = append(rows, []string{"Rollout", r.Namespace + "/" + r.Name, r.Strategy, valueOrNone(r.Phase), r.stepLabel(), r.weightLabel(),
+				fmt.Sprintf("%d/%d", r.Ready, r.Replicas)})
+		}
+		for _, c := range canaries {
+			rows = append(rows, []string{"Canary", c.Namespace + "/" + c.Name, "canary (Flagger)", valueOrNone(c.Phase), fmt.Sprintf("%d", c.Iterations),
+				fmt.Sprintf("canary %d%% / primary %d%%", c.Weight, 100-c.Weight), "-"})
+		}
+		if len(rows) == 0 {
+			sb.WriteString("No Argo Rollouts or Flagger Canaries found.\n")
+		} else {
+			sb.WriteString(util.FormatTable([]string{"KIND", "NAME", "STRATEGY", "PHASE", "STEP", "TRAFFIC", "READY"}, rows))
+		}
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		for _, r := range rollouts {
+			rs := runs[r.Namespace+"/"+r.Name]
+			writeArgoRollout(&sb, r, rs)
+			f, a := rolloutFindings(r, rs)
+			findingLines = append(findingLines, f...)
+			actions = append(actions, a...)
+			if len(f) > 0 {
+				steps = append(steps, nextStep("get_events", "Controller events explain the rollout's state", "namespace", r.Namespace, "involved_object", r.Name))
+			}
+		}
+		for _, c := range canaries {
+			writeFlaggerCanary(&sb, c)
+			f, a := canaryFindings(c)
+			findingLines = append(findingLines, f...)
+			actions = append(actions, a...)
+			if len(f) > 0 {
+				steps = append(steps,
+					nextStep("get_events", "Flagger records each analysis check as an event", "namespace", c.Namespace, "involved_object", c.Name),
+					nextStep("get_deployment_detail", "Inspect the canary's target workload", "namespace", c.Namespace, "name", c.TargetName))
+			}
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  No paused, aborted, or degraded rollouts.\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString(f + "\n")
+		}
+		if actions = dedupe(actions); len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range actions {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+		if a := gaps.assessment(); a != "" {
+			sb.WriteString("\n")
+			sb.WriteString(a)
+		}
+		gaps.write(&sb)
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+func parseArgoRollout(u *unstructured.Unstructured) argoRollout {
+	r := argoRollout{Namespace: u.GetNamespace(), Name: u.GetName(), Strategy: "canary", Replicas: 1}
+	obj := u.Object
+	if n, ok, _ := unstructured.NestedInt64(obj, "spec", "replicas"); ok {
+		r.Replicas = n
+	}
+	r.Ready, _, _ = unstructured.NestedInt64(obj, "status", "readyReplicas")
+	r.Updated, _, _ = unstructured.NestedInt64(obj, "status", "updatedReplicas")
+	r.Phase, _, _ = unstructured.NestedString(obj, "status", "phase")
+	r.Message, _, _ = unstructured.NestedString(obj, "status", "message")
+	r.Paused, _, _ = unstructured.NestedBool(obj, "spec", "paused")
+	r.Aborted, _, _ = unstructured.NestedBool(obj, "status", "abort")
+	r.PodHash, _, _ = unstructured.NestedString(obj, "status", "currentPodHash")
+
+	pauses, _, _ := unstructured.NestedSlice(obj, "status", "pauseConditions")
+	for _, p := range pauses {
+		pm, _ := p.(map[string]any)
+		reason, _, _ := unstructured.NestedString(pm, "reason")
+		r.PauseReasons = append(r.PauseReasons, reason)
+		start, _, _ := unstructured.NestedString(pm, "startTime")
+		if t, err := time.Parse(time.RFC3339, start); err == nil && (r.PausedSince.IsZero() || t.Before(r.PausedSince)) {
+			r.PausedSince = t
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, c := range conditions {
+		cm, _ := c.(map[string]any)
+		if cm["type"] == "Progressing" && cm["reason"] == "ProgressDeadlineExceeded" {
+			r.DeadlineExceeded, _ = cm["message"].(string)
+			if r.DeadlineExceeded == "" {
+				r.DeadlineExceeded = "ProgressDeadlineExceeded"
+			}
+		}
+	}
+
+	if bg, ok, _ := unstructured.NestedMap(obj, "spec", "strategy", "blueGreen"); ok {
+		r.Strategy = "blueGreen"
+		r.ActiveService, _, _ = unstructured.NestedString(bg, "activeService")
+		r.PreviewService, _, _ = unstructured.NestedString(bg, "previewService")
+		return r
+	}
+
+	canarySteps, _, _ := unstructured.NestedSlice(obj, "spec", "strategy", "canary", "steps")
+	r.Steps = int64(len(canarySteps))
+	r.Step, _, _ = unstructured.NestedInt64(obj, "status", "currentStepIndex")
+	for i, s := range canarySteps {
+		if int64(i) > r.Step {
+			break
+		}
+		sm, _ := s.(map[string]any)
+		if w, ok, _ := unstructured.NestedInt64(sm, "setWeight"); ok {
+			r.CanaryWeight, r.StableWeight, r.WeightSource = w, 100-w, "setWeight"
+		}
+		if pause, ok, _ := unstructured.NestedMap(sm, "pause"); ok && int64(i) == r.Step {
+			_, hasDuration := pause["duration"]
+			r.PauseIndefinite = !hasDuration
+		}
+	}
+	if routing, ok, _ := unstructured.NestedMap(obj, "spec", "strategy", "canary", "trafficRouting"); ok {
+		r.TrafficRouter = strings.Join(sortedMapKeys(routing), ", ")
+	}
+	if w, ok, _ := unstructured.NestedInt64(obj, "status", "canary", "weights", "canary", "weight"); ok {
+		r.CanaryWeight, r.WeightSource = w, "traffic router"
+		r.StableWeight, _, _ = unstructured.NestedInt64(obj, "status", "canary", "weights", "stable", "weight")
+	}
+	return r
+}
+
+func parseAnalysisRun(u *unstructured.Unstructured) analysisRun {
+	run := analysisRun{Name: u.GetName(), PodHash: u.GetLabels()[argoPodHashLabel], Created: u.GetCreationTimestamp().Time}
+	for _, ref := range u.GetOwnerReferences() {
+		if ref.Kind == "Rollout" {
+			run.Rollout = ref.Name
+		}
+	}
+	run.Phase, _, _ = unstructured.NestedString(u.Object, "status", "phase")
+	run.Message, _, _ = unstructured.NestedString(u.Object, "status", "message")
+	results, _, _ := unstructured.NestedSlice(u.Object, "status", "metricResults")
+	for _, res := range results {
+		rm, _ := res.(map[string]any)
+		m := analysisMetric{}
+		m.Name, _, _ = unstructured.NestedString(rm, "name")
+		m.Phase, _, _ = unstructured.NestedString(rm, "phase")
+		m.Count, _, _ = unstructured.NestedInt64(rm, "count")
+		m.Failed, _, _ = unstructured.NestedInt64(rm, "failed")
+		m.Inconclusive, _, _ = unstructured.NestedInt64(rm, "inconclusive")
+		if measurements, _, _ := unstructured.NestedSlice(rm, "measurements"); len(measurements) > 0 {
+			last, _ := measurements[len(measurements)-1].(map[string]any)
+			m.LastValue, _, _ = unstructured.NestedString(last, "value")
+			m.LastMessage, _, _ = unstructured.NestedString(last, "message")
+		}
+		run.Metrics = append(run.Metrics, m)
+	}
+	return run
+}
+
+func parseFlaggerCanary(u *unstructured.Unstructured) flaggerCanary {
+	c := flaggerCanary{Namespace: u.GetNamespace(), Name: u.GetName()}
+	obj := u.Object
+	c.TargetKind, _, _ = unstructured.NestedString(obj, "spec", "targetRef", "kind")
+	c.TargetName, _, _ = unstructured.NestedString(obj, "spec", "targetRef", "name")
+	c.Provider, _, _ = unstructured.NestedString(obj, "spec", "provider")
+	c.Threshold, _, _ = unstructured.NestedInt64(obj, "spec", "analysis", "threshold")
+	c.MaxWeight, _, _ = unstructured.NestedInt64(obj, "spec", "analysis", "maxWeight")
+	c.StepWeight, _, _ = unstructured.NestedInt64(obj, "spec", "analysis", "stepWeight")
+	c.Phase, _, _ = unstructured.NestedString(obj, "status", "phase")
+	c.Weight, _, _ = unstructured.NestedInt64(obj, "status", "canaryWeight")
+	c.FailedChecks, _, _ = unstructured.NestedInt64(obj, "status", "failedChecks")
+	c.Iterations, _, _ = unstructured.NestedInt64(obj, "status", "iterations")
+	if ts, _, _ := unstructured.NestedString(obj, "status", "lastTransitionTime"); ts != "" {
+		c.LastTransition, _ = time.Parse(time.RFC3339, ts)
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, cond := range conditions {
+		cm, _ := cond.(map[string]any)
+		if cm["type"] == "Promoted" {
+			c.Message, _ = cm["message"].(string)
+		}
+	}
+	return c
+}
+
+// stepLabel is the current canary step, e.g. "3/5", or "-" for blue/green.
+func (r argoRollout) stepLabel() string {
+	if r.Strategy != "canary" || r.Steps == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", min(r.Step, r.Steps), r.Steps)
+}
+
+// weightLabel is the traffic split between canary and stable.
+func (r argoRollout) weightLabel() string {
+	switch {
+	case r.Strategy == "blueGreen":
+		return "active/preview"
+	case r.WeightSource == "":
+		return "-"
+	}
+	return fmt.Sprintf("canary %d%% / stable %d%%", r.CanaryWeight, r.StableWeight)
+}
+
+// writeArgoRollout appends the detail section for one rollout.
+func writeArgoRollout(sb *strings.Builder, r argoRollout, runs []analysisRun) {
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatSubHeader(fmt.Sprintf("Rollout %s/%s", r.Namespace, r.Name)))
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatKeyValue("Strategy", r.Strategy) + "\n")
+	phase := valueOrNone(r.Phase)
+	if r.Message != "" {
+		phase += " — " + r.Message
+	}
+	sb.WriteString(util.FormatKeyValue("Phase", phase) + "\n")
+	sb.WriteString(util.FormatKeyValue("Replicas", fmt.Sprintf("%d desired, %d updated, %d ready", r.Replicas, r.Updated, r.Ready)) + "\n")
+	if r.Strategy == "blueGreen" {
+		sb.WriteString(util.FormatKeyValue("Services", fmt.Sprintf("active %s, preview %s", valueOrNone(r.ActiveService), valueOrNone(r.PreviewService))) + "\n")
+	} else {
+		sb.WriteString(util.FormatKeyValue("Step", r.stepLabel()) + "\n")
+		traffic := r.weightLabel()
+		if r.WeightSource == "setWeight" && r.TrafficRouter == "" {
+			traffic += " (approximated by replica count; no traffic router)"
+		} else if r.TrafficRouter != "" {
+			traffic += fmt.Sprintf(" (via %s)", r.TrafficRouter)
+		}
+		sb.WriteString(util.FormatKeyValue("Traffic", traffic) + "\n")
+	}
+	if len(r.PauseReasons) > 0 || r.Paused {
+		reasons := r.PauseReasons
+		if r.Paused {
+			reasons = append(reasons, "spec.paused")
+		}
+		since := ""
+		if !r.PausedSince.IsZero() {
+			since = fmt.Sprintf(" (for %s)", util.FormatAge(r.PausedSince))
+		}
+		sb.WriteString(util.FormatKeyValue("Paused", strings.Join(reasons, ", ")+since) + "\n")
+	}
+
+	if len(runs) == 0 {
+		return
+	}
+	var rows [][]string
+	for i, run := range runs {
+		if i == maxAnalysisRuns {
+			break
+		}
+		rows = append(rows, []string{run.Name, valueOrNone(run.Phase), analysisRunMetrics(run), util.FormatAge(run.Created)})
+	}
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatTable([]string{"ANALYSIS RUN", "PHASE", "METRICS", "AGE"}, rows))
+}
+
+// analysisRunMetrics summarizes metric phases, e.g. "error-rate Failed (2/5 failed), latency Successful".
+func analysisRunMetrics(run analysisRun) string {
+	parts := make([]string, 0, len(run.Metrics))
+	for _, m := range run.Metrics {
+		part := fmt.Sprintf("%s %s", m.Name, valueOrNone(m.Phase))
+		if m.Failed > 0 || m.Inconclusive > 0 {
+			part += fmt.Sprintf(" (%d/%d failed, %d inconclusive)", m.Failed, m.Count, m.Inconclusive)
+		}
+		parts = append(parts, part)
+	}
+	return valueOrNone(strings.Join(parts, ", "))
+}
+
+// rolloutFindings explains why an Argo Rollout is not progressing.
+func rolloutFindings(r argoRollout, runs []analysisRun) (findingLines, actions []string) {
+	ref := r.Namespace + "/" + r.Name
+	cli := fmt.Sprintf("kubectl argo rollouts %%s %s -n %s", r.Name, r.Namespace)
+	switch {
+	case r.Aborted:
+		findingLines = append(findingLines, findings.Format("KD-WL-005", "CRITICAL", fmt.Sprintf("Rollout %s was aborted and is back on the stable version: %s", ref, valueOrNone(r.Message))))
+		actions = append(actions, fmt.Sprintf("Fix the cause of the abort, then retry: %s", fmt.Sprintf(cli, "retry rollout")))
+	case r.Phase == "Degraded":
+		findingLines = append(findingLines, findings.Format("KD-WL-005", "CRITICAL", fmt.Sprintf("Rollout %s is Degraded: %s", ref, valueOrNone(r.Message))))
+		actions = append(actions, fmt.Sprintf("Check the new pods of %s (hash %s) for crashes or failing readiness", ref, valueOrNone(r.PodHash)))
+	}
+	if r.DeadlineExceeded != "" {
+		findingLines = append(findingLines, findings.Format("KD-WL-005", "CRITICAL", fmt.Sprintf("Rollout %s exceeded its progress deadline: %s", ref, r.DeadlineExceeded)))
+	}
+	if r.Paused {
+		findingLines = append(findingLines, findings.Format("KD-WL-006", "WARNING", fmt.Sprintf("Rollout %s was paused manually (spec.paused=true)", ref)))
+		actions = append(actions, fmt.Sprintf("Resume it when ready: %s", fmt.Sprintf(cli, "resume")))
+	}
+	for _, reason := range r.PauseReasons {
+		switch reason {
+		case "CanaryPauseStep":
+			wait := "for its pause duration"
+			if r.PauseIndefinite {
+				wait = "until it is promoted (the step has no duration)"
+			}
+			findingLines = append(findingLines, findings.Format("KD-WL-006", "INFO", fmt.Sprintf("Rollout %s is paused at canary step %s %s", ref, r.stepLabel(), wait)))
+			if r.PauseIndefinite {
+				actions = append(actions, fmt.Sprintf("Promote once the canary looks healthy: %s", fmt.Sprintf(cli, "promote")))
+			}
+		case "BlueGreenPause":
+			findingLines = append(findingLines, findings.Format("KD-WL-006", "INFO", fmt.Sprintf("Rollout %s is waiting for the preview (%s) to be promoted", ref, valueOrNone(r.PreviewService))))
+			actions = append(actions, fmt.Sprintf("Verify the preview service, then promote: %s", fmt.Sprintf(cli, "promote")))
+		case "InconclusiveAnalysisRun", "InconclusiveExperiment":
+			findingLines = append(findingLines, findings.Format("KD-WL-006", "WARNING", fmt.Sprintf("Rollout %s is paused because an analysis was inconclusive", ref)))
+			actions = append(actions, fmt.Sprintf("Review the analysis results, then promote or abort: %s", fmt.Sprintf(cli, "promote")))
+		default:
+			findingLines = append(findingLines, findings.Format("KD-WL-006", "INFO", fmt.Sprintf("Rollout %s is paused: %s", ref, reason)))
+		}
+	}
+
+	if len(runs) > 0 {
+		// Judge the run for the revision being rolled out; older runs are history.
+		latest := runs[0]
+		for _, run := range runs {
+			if r.PodHash != "" && run.PodHash == r.PodHash {
+				latest = run
+				break
+			}
+		}
+		var failing []string
+		for _, m := range latest.Metrics {
+			if m.Phase == "Failed" || m.Phase == "Error" || m.Phase == "Inconclusive" {
+				detail := fmt.Sprintf("%s %s", m.Name, m.Phase)
+				if m.LastValue != "" {
+					detail += " (last value " + m.LastValue + ")"
+				} else if m.LastMessage != "" {
+					detail += " (" + truncateName(m.LastMessage, 80) + ")"
+				}
+				failing = append(failing, detail)
+			}
+		}
+		if len(failing) == 0 && latest.Message != "" {
+			failing = append(failing, latest.Message)
+		}
+		switch latest.Phase {
+		case "Failed", "Error":
+			findingLines = append(findingLines, findings.Format("KD-WL-005", "CRITICAL", fmt.Sprintf("Analysis run %s for %s %s: %s", latest.Name, ref, latest.Phase, valueOrNone(strings.Join(failing, "; ")))))
+			actions = append(actions, "Check the failing metric's query and the canary's behaviour; the rollout aborts on failed analysis")
+		case "Inconclusive":
+			findingLines = append(findingLines, findings.Format("KD-WL-006", "WARNING", fmt.Sprintf("Analysis run %s for %s was inconclusive: %s", latest.Name, ref, valueOrNone(strings.Join(failing, "; ")))))
+		}
+	}
+	return findingLines, actions
+}
+
+// writeFlaggerCanary appends the detail section for one Flagger canary.
+func writeFlaggerCanary(sb *strings.Builder, c flaggerCanary) {
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatSubHeader(fmt.Sprintf("Canary %s/%s", c.Namespace, c.Name)))
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatKeyValue("Target", fmt.Sprintf("%s/%s", valueOrNone(c.TargetKind), valueOrNone(c.TargetName))) + "\n")
+	if c.Provider != "" {
+		sb.WriteString(util.FormatKeyValue("Provider", c.Provider) + "\n")
+	}
+	phase := valueOrNone(c.Phase)
+	if !c.LastTransition.IsZero() {
+		phase += fmt.Sprintf(" (for %s)", util.FormatAge(c.LastTransition))
+	}
+	sb.WriteString(util.FormatKeyValue("Phase", phase) + "\n")
+	sb.WriteString(util.FormatKeyValue("Traffic", fmt.Sprintf("canary %d%% / primary %d%% (step %d%%, max %d%%)", c.Weight, 100-c.Weight, c.StepWeight, c.MaxWeight)) + "\n")
+	sb.WriteString(util.FormatKeyValue("Failed Checks", fmt.Sprintf("%d of threshold %d", c.FailedChecks, c.Threshold)) + "\n")
+	if c.Message != "" {
+		sb.WriteString(util.FormatKeyValue("Message", c.Message) + "\n")
+	}
+}
+
+// canaryFindings explains why a Flagger canary failed or is waiting.
+func canaryFindings(c flaggerCanary) (findingLines, actions []string) {
+	ref := c.Namespace + "/" + c.Name
+	switch c.Phase {
+	case "Failed":
+		findingLines = append(findingLines, findings.Format("KD-WL-005", "CRITICAL", fmt.Sprintf("Canary %s failed and was rolled back to the primary: %s", ref, valueOrNone(c.Message))))
+		actions = append(actions, fmt.Sprintf("Find the failing check in the events of canary %s, fix %s, and push a new revision to restart the analysis", ref, valueOrNone(c.TargetName)))
+	case "Progressing":
+		if c.FailedChecks > 0 {
+			findingLines = append(findingLines, findings.Format("KD-WL-005", "WARNING", fmt.Sprintf("Canary %s has %d failed check(s); it rolls back at %d", ref, c.FailedChecks, c.Threshold)))
+		}
+	case "Waiting", "WaitingPromotion":
+		findingLines = append(findingLines, findings.Format("KD-WL-006", "INFO", fmt.Sprintf("Canary %s is %s for a confirm webhook to approve the next stage", ref, c.Phase)))
+		actions = append(actions, fmt.Sprintf("Check the confirm-rollout/confirm-promotion webhooks of canary %s", ref))
+	}
+	return findingLines, actions
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseArgoRolloutCanary(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "api", "namespace": "shop"},
+		"spec": map[string]any{
+			"replicas": int64(5),
+			"strategy": map[string]any{"canary": map[string]any{
+				"trafficRouting": map[string]any{"nginx": map[string]any{"stableIngress": "api"}},
+				"steps": []any{
+					map[string]any{"setWeight": int64(20)},
+					map[string]any{"pause": map[string]any{}},
+					map[string]any{"setWeight": int64(50)},
+					map[string]any{"pause": map[string]any{"duration": "10m"}},
+				},
+			}},
+		},
+		"status": map[string]any{
+			"phase":            "Paused",
+			"currentStepIndex": int64(1),
+			"currentPodHash":   "abc123",
+			"readyReplicas":    int64(5),
+			"pauseConditions":  []any{map[string]any{"reason": "CanaryPauseStep", "startTime": "2026-10-15T10:00:00Z"}},
+			"canary":           map[string]any{"weights": map[string]any{"canary": map[string]any{"weight": int64(20)}, "stable": map[string]any{"weight": int64(80)}}},
+		},
+	}}
+	r := parseArgoRollout(u)
+	if r.Strategy != "canary" || r.stepLabel() != "1/4" || !r.PauseIndefinite {
+		t.Errorf("rollout = %+v", r)
+	}
+	if r.weightLabel() != "canary 20% / stable 80%" || r.WeightSource != "traffic router" || r.TrafficRouter != "nginx" {
+		t.Errorf("traffic = %q from %q via %q", r.weightLabel(), r.WeightSource, r.TrafficRouter)
+	}
+	if !r.PausedSince.Equal(time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("paused since = %v", r.PausedSince)
+	}
+
+	findings, actions := rolloutFindings(r, nil)
+	if len(findings) != 1 || !strings.Contains(findings[0], "paused at canary step 1/4 until it is promoted") {
+		t.Errorf("findings = %v", findings)
+	}
+	if len(actions) != 1 || !strings.Contains(actions[0], "kubectl argo rollouts promote api -n shop") {
+		t.Errorf("actions = %v", actions)
+	}
+}
+
+func TestRolloutFindingsAnalysisFailure(t *testing.T) {
+	run := func(name, hash, phase string, created time.Time) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{
+			"status": map[string]any{
+				"phase": phase,
+				"metricResults": []any{map[string]any{
+					"name": "error-rate", "phase": phase, "count": int64(5), "failed": int64(3),
+					"measurements": []any{map[string]any{"value": "[0.12]"}},
+				}},
+			},
+		}}
+		u.SetName(name)
+		u.SetLabels(map[string]string{argoPodHashLabel: hash})
+		u.SetCreationTimestamp(metav1.NewTime(created))
+		u.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Rollout", Name: "api"}})
+		return u
+	}
+	now := time.Now()
+	current := parseAnalysisRun(run("api-abc-2", "abc", "Failed", now.Add(-time.Hour)))
+	newer := parseAnalysisRun(run("api-old-9", "old", "Successful", now))
+	if current.Rollout != "api" || analysisRunMetrics(current) != "error-rate Failed (3/5 failed, 0 inconclusive)" {
+		t.Errorf("run = %+v, metrics %q", current, analysisRunMetrics(current))
+	}
+
+	r := argoRollout{Namespace: "shop", Name: "api", Strategy: "canary", Aborted: true, Message: "RolloutAborted: metric error-rate assessed Failed", PodHash: "abc"}
+	findings, actions := rolloutFindings(r, []analysisRun{newer, current})
+	got := strings.Join(findings, "\n")
+	for _, want := range []string{"[CRITICAL] KD-WL-005: Rollout shop/api was aborted", "[CRITICAL] KD-WL-005: Analysis run api-abc-2 for shop/api Failed: error-rate Failed (last value [0.12])"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("findings missing %q:\n%s", want, got)
+		}
+	}
+	if !strings.Contains(strings.Join(actions, "\n"), "kubectl argo rollouts retry rollout api -n shop") {
+		t.Errorf("actions = %v", actions)
+	}
+}
+
+func TestParseArgoRolloutBlueGreen(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "web", "namespace": "shop"},
+		"spec": map[string]any{"strategy": map[string]any{"blueGreen": map[string]any{
+			"activeService": "web", "previewService": "web-preview",
+		}}},
+		"status": map[string]any{"pauseConditions": []any{map[string]any{"reason": "BlueGreenPause"}}},
+	}}
+	r := parseArgoRollout(u)
+	if r.Strategy != "blueGreen" || r.stepLabel() != "-" || r.PreviewService != "web-preview" {
+		t.Errorf("rollout = %+v", r)
+	}
+	findings, _ := rolloutFindings(r, nil)
+	if len(findings) != 1 || !strings.Contains(findings[0], "waiting for the preview (web-preview) to be promoted") {
+		t.Errorf("findings = %v", findings)
+	}
+}
+
+func TestFlaggerCanaryFindings(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "podinfo", "namespace": "test"},
+		"spec": map[string]any{
+			"targetRef": map[string]any{"kind": "Deployment", "name": "podinfo"},
+			"analysis":  map[string]any{"threshold": int64(5), "maxWeight": int64(50), "stepWeight": int64(10)},
+		},
+		"status": map[string]any{
+			"phase": "Failed", "canaryWeight": int64(0), "failedChecks": int64(5),
+			"conditions": []any{map[string]any{"type": "Promoted", "status": "False", "message": "Canary analysis failed, Deployment scaled to zero."}},
+		},
+	}}
+	c := parseFlaggerCanary(u)
+	if c.TargetName != "podinfo" || c.Threshold != 5 || c.FailedChecks != 5 {
+		t.Errorf("canary = %+v", c)
+	}
+	findings, actions := canaryFindings(c)
+	if len(findings) != 1 || !strings.Contains(findings[0], "[CRITICAL] KD-WL-005: Canary test/podinfo failed and was rolled back to the primary: Canary analysis failed") || len(actions) != 1 {
+		t.Errorf("findings = %v, actions = %v", findings, actions)
+	}
+
+	c.Phase, c.FailedChecks = "Progressing", 2
+	if findings, _ := canaryFindings(c); len(findings) != 1 || !strings.Contains(findings[0], "2 failed check(s); it rolls back at 5") {
+		t.Errorf("progressing findings = %v", findings)
+	}
+}
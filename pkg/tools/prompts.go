@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// diagnosticPrompt is a guided workflow exposed as an MCP prompt. render turns
+// the caller's arguments into the instructions sent to the model.
+type diagnosticPrompt struct {
+	prompt *mcp.Prompt
+	render func(args map[string]string) (string, error)
+}
+
+var diagnosticPrompts = []diagnosticPrompt{
+	{
+		prompt: &mcp.Prompt{
+			Name:        "debug-failing-url",
+			Title:       "Debug a failing URL",
+			Description: "Trace a URL that returns errors or times out from DNS and the ingress controller down to the backend pods.",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "url", Description: "The failing URL, e.g. https://api.example.com/payments/v1", Required: true},
+				{Name: "namespace", Description: "Namespace of the Ingress, if known"},
+				{Name: "symptom", Description: "What the client sees, e.g. 502, 504, timeout, TLS error"},
+			},
+		},
+		render: renderDebugFailingURL,
+	},
+	{
+		prompt: &mcp.Prompt{
+			Name:        "investigate-oomkills",
+			Title:       "Investigate OOMKills in a namespace",
+			Description: "Find containers killed for exceeding memory, tell container-limit OOMs from node memory pressure, and size memory limits from observed usage.",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "namespace", Description: "Namespace to investigate", Required: true},
+				{Name: "workload", Description: "Limit the investigation to this Deployment, StatefulSet, or DaemonSet"},
+			},
+		},
+		render: renderInvestigateOOMKills,
+	},
+	{
+		prompt: &mcp.Prompt{
+			Name:        "pre-deployment-review",
+			Title:       "Pre-deployment readiness review",
+			Description: "Review a namespace before a release: workload lint, probes, security, disruption budgets, quotas, placement, and resource sizing, ending in a go/no-go list.",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "namespace", Description: "Namespace being deployed to", Required: true},
+				{Name: "workload", Description: "Focus on this Deployment, StatefulSet, or DaemonSet"},
+			},
+		},
+		render: renderPreDeploymentReview,
+	},
+}
+
+func registerPrompts(server *mcp.Server) {
+	for _, p := range diagnosticPrompts {
+		server.AddPrompt(p.prompt, promptHandler(p))
+	}
+}
+
+func promptHandler(p diagnosticPrompt) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := make(map[string]string, len(req.Params.Arguments))
+		for k, v := range req.Params.Arguments {
+			args[k] = strings.TrimSpace(v)
+		}
+		for _, a := range p.prompt.Arguments {
+			if a.Required && args[a.Name] == "" {
+				return nil, fmt.Errorf("prompt %s requires argument %q", p.prompt.Name, a.Name)
+			}
+		}
+		text, err := p.render(args)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.GetPromptResult{
+			Description: p.prompt.Description,
+			Messages:    []*mcp.PromptMessage{{Role: "user", Content: &mcp.TextContent{Text: text}}},
+		}, nil
+	}
+}
+
+// promptSteps formats a numbered workflow under an introductory line.
+func promptSteps(intro string, steps []string, closing string) string {
+	var sb strings.Builder
+	sb.WriteString(intro + "\n\n")
+	for i, s := range steps {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, s))
+	}
+	sb.WriteString("\n" + closing + "\n")
+	return sb.String()
+}
+
+// namespaceArg renders a namespace tool argument, or nothing when unset.
+func namespaceArg(ns string) string {
+	if ns == "" {
+		return ""
+	}
+	return fmt.Sprintf(", namespace=%q", ns)
+}
+
+func renderDebugFailingURL(args map[string]string) (string, error) {
+	raw := args["url"]
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("url %q has no hostname", args["url"])
+	}
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	ns := args["namespace"]
+
+	intro := fmt.Sprintf("Requests to %s are failing", u.Redacted())
+	if s := args["symptom"]; s != "" {
+		intro += fmt.Sprintf(" (client sees: %s)", s)
+	}
+	intro += ". Find the broken hop between the client and the backend pods using the kube-doctor tools below, in order. Stop digging once a step explains the symptom, but finish the step you are on."
+
+	steps := []string{
+		fmt.Sprintf("Call diagnose_request_path with hostname=%q, path=%q%s. It follows Ingress -> Service -> Endpoints -> Pods and names the first broken hop.", u.Hostname(), path, namespaceArg(ns)),
+		"If the report shows no matching Ingress rule, call analyze_all_ingresses to look for a typo in the host or path, a missing ingressClassName, or another Ingress shadowing the rule.",
+		"If the backend Service has no ready endpoints, call diagnose_service for it, then diagnose_pod on one of its failing pods.",
+		"If the hops look healthy but the symptom is a 502/503/504 or timeout, call check_ingress_controller_health and analyze_service_connectivity for the backend Service.",
+		"If the symptom is a TLS error, check the certificate findings in the diagnose_request_path report and call check_crd_conditions with kind=\"Certificate\" when cert-manager is installed.",
+		"If the hostname does not resolve from inside the cluster, call check_dns_health.",
+		fmt.Sprintf("Call get_events with event_type=\"Warning\"%s for anything the previous steps did not explain.", namespaceArg(ns)),
+	}
+	return promptSteps(intro, steps, "Finish with: the root cause, the evidence for it (quote the tool output), and the exact kubectl or manifest change that fixes it."), nil
+}
+
+func renderInvestigateOOMKills(args map[string]string) (string, error) {
+	ns, workload := args["namespace"], args["workload"]
+	target := "namespace " + ns
+	workloadArg := ""
+	if workload != "" {
+		target = fmt.Sprintf("workload %s in namespace %s", workload, ns)
+		workloadArg = fmt.Sprintf(", workload=%q", workload)
+	}
+
+	intro := fmt.Sprintf("Containers in %s are being OOMKilled. Work out which containers, why, and what their memory requests and limits should be, using the kube-doctor tools below in order.", target)
+	steps := []string{
+		fmt.Sprintf("Call find_unhealthy_pods with namespace=%q and note every container whose last termination reason is OOMKilled, with its restart count.", ns),
+		fmt.Sprintf("Call cluster_crashloops with namespace=%q to group the OOMKilled containers by image and confirm they share one failure signature.", ns),
+		fmt.Sprintf("Call get_events with namespace=%q, reasons=\"OOMKilling,Evicted\". OOMKilling events come from the kernel on the node; Evicted pods point to node memory pressure rather than a container limit.", ns),
+		"For one affected pod, call get_pod_detail to read its memory request and limit, then get_pod_logs with previous=true to see what it was doing when it was killed.",
+		"If pods were evicted or several workloads on the same node were killed, call analyze_node_capacity to check for overcommitted node memory.",
+		fmt.Sprintf("Call query_usage_history with namespace=%q%s to see peak memory over the last week. If Prometheus is not configured, call analyze_resource_usage with namespace=%q, per_container=true instead.", ns, workloadArg, ns),
+		fmt.Sprintf("Call recommend_resources with namespace=%q%s for memory requests and limits based on observed usage.", ns, workloadArg),
+	}
+	return promptSteps(intro, steps, "Finish with: each affected container, whether it hit its own limit or was a node-pressure victim, the evidence, and the recommended memory request and limit (or the code-side fix if usage grows without bound, which suggests a leak)."), nil
+}
+
+func renderPreDeploymentReview(args map[string]string) (string, error) {
+	ns, workload := args["namespace"], args["workload"]
+	target := "namespace " + ns
+	workloadArg, probeArg := "", ""
+	if workload != "" {
+		target = fmt.Sprintf("workload %s in namespace %s", workload, ns)
+		workloadArg = fmt.Sprintf(", workload=%q", workload)
+		probeArg = fmt.Sprintf(", workload_name=%q", workload)
+	}
+
+	intro := fmt.Sprintf("Review %s for production readiness before the next deployment. Run the kube-doctor checks below and collect every finding.", target)
+	steps := []string{
+		fmt.Sprintf("Call lint_workloads with namespace=%q for missing requests and limits, single replicas, and missing anti-affinity.", ns),
+		fmt.Sprintf("Call analyze_probes with namespace=%q%s for missing or misconfigured readiness, liveness, and startup probes.", ns, probeArg),
+		fmt.Sprintf("Call check_pod_security_standards with namespace=%q, profile=\"restricted\".", ns),
+		fmt.Sprintf("Call list_pdbs with namespace=%q and confirm every multi-replica workload has a PodDisruptionBudget that still allows a disruption.", ns),
+		fmt.Sprintf("Call check_resource_quotas with namespace=%q to make sure the rollout's surge pods fit in the remaining quota.", ns),
+		fmt.Sprintf("Call check_placement_policy with namespace=%q.", ns),
+		fmt.Sprintf("Call recommend_resources with namespace=%q%s to compare requests with observed usage.", ns, workloadArg),
+		fmt.Sprintf("Call find_unhealthy_pods with namespace=%q; anything already failing should be fixed before deploying on top of it.", ns),
+	}
+	return promptSteps(intro, steps, "Finish with a go/no-go verdict: a BLOCKERS list (CRITICAL findings), a SHOULD FIX list (WARNING findings), and the manifest change for each item."), nil
+}
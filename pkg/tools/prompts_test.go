@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+func TestRenderDebugFailingURL(t *testing.T) {
+	text, err := renderDebugFailingURL(map[string]string{"url": "api.example.com/payments/v1?x=1", "namespace": "shop", "symptom": "504"})
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if !strings.Contains(text, `hostname="api.example.com", path="/payments/v1", namespace="shop"`) {
+		t.Errorf("expected diagnose_request_path arguments from the URL:\n%s", text)
+	}
+	if !strings.Contains(text, "client sees: 504") {
+		t.Errorf("expected the symptom in the intro:\n%s", text)
+	}
+	if _, err := renderDebugFailingURL(map[string]string{"url": "https://"}); err == nil {
+		t.Error("expected an error for a URL without a hostname")
+	}
+}
+
+func TestPromptsReferenceRegisteredTools(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "kube-doctor-test", Version: "test"}, nil)
+	RegisterAll(server, k8s.NewClusterClientForTesting(fake.NewSimpleClientset(), nil), nil, Options{})
+
+	ctx := context.Background()
+	t1, t2 := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, t1, nil)
+	if err != nil {
+		t.Fatalf("Server connect: %v", err)
+	}
+	defer serverSession.Close()
+	clientSession, err := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, nil).Connect(ctx, t2, nil)
+	if err != nil {
+		t.Fatalf("Client connect: %v", err)
+	}
+	defer clientSession.Close()
+
+	registered := make(map[string]bool)
+	for tool, err := range clientSession.Tools(ctx, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		registered[tool.Name] = true
+	}
+
+	toolCall := regexp.MustCompile(`\bcall ([a-z]+(?:_[a-z]+)+)`)
+	for _, p := range diagnosticPrompts {
+		if _, err := clientSession.GetPrompt(ctx, &mcp.GetPromptParams{Name: p.prompt.Name}); err == nil {
+			t.Errorf("%s: expected an error without required arguments", p.prompt.Name)
+		}
+		res, err := clientSession.GetPrompt(ctx, &mcp.GetPromptParams{
+			Name:      p.prompt.Name,
+			Arguments: map[string]string{"url": "https://shop.example.com/cart", "namespace": "shop", "workload": "web"},
+		})
+		if err != nil {
+			t.Fatalf("GetPrompt(%s) error: %v", p.prompt.Name, err)
+		}
+		text := res.Messages[0].Content.(*mcp.TextContent).Text
+		matches := toolCall.FindAllStringSubmatch(strings.ToLower(text), -1)
+		if len(matches) == 0 {
+			t.Errorf("%s: no tool calls in prompt", p.prompt.Name)
+		}
+		for _, m := range matches {
+			if !registered[m[1]] {
+				t.Errorf("%s: references unregistered tool %s", p.prompt.Name, m[1])
+			}
+		}
+	}
+}
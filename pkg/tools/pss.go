@@ -0,0 +1,367 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+type checkPodSecurityStandardsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces)"`
+	Profile        string `json:"profile,omitempty" jsonschema:"PSS profile to evaluate against: baseline or restricted (default: restricted)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// Pod Security Admission namespace label keys.
+const (
+	pssEnforceLabel = "pod-security.kubernetes.io/enforce"
+	pssAuditLabel   = "pod-security.kubernetes.io/audit"
+	pssWarnLabel    = "pod-security.kubernetes.io/warn"
+)
+
+// pssBaselineCapabilities are the capabilities the baseline profile allows to be added.
+var pssBaselineCapabilities = map[corev1.Capability]bool{
+	"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true, "FSETID": true,
+	"KILL": true, "MKNOD": true, "NET_BIND_SERVICE": true, "SETFCAP": true, "SETGID": true,
+	"SETPCAP": true, "SETUID": true, "SYS_CHROOT": true,
+}
+
+// pssSafeSysctls are the sysctls the baseline profile allows.
+var pssSafeSysctls = map[string]bool{
+	"kernel.shm_rmid_forced": true, "net.ipv4.ip_local_port_range": true, "net.ipv4.ip_unprivileged_port_start": true,
+	"net.ipv4.tcp_syncookies": true, "net.ipv4.ping_group_range": true, "net.ipv4.ip_local_reserved_ports": true,
+	"net.ipv4.tcp_keepalive_time": true, "net.ipv4.tcp_fin_timeout": true, "net.ipv4.tcp_keepalive_intvl": true,
+	"net.ipv4.tcp_keepalive_probes": true,
+}
+
+// pssViolation is a single failed PSS control.
+type pssViolation struct {
+	Profile string // "baseline" or "restricted"
+	Control string
+	Detail  string
+}
+
+// registerCheckPodSecurityStandards registers the check_pod_security_standards tool.
+func registerCheckPodSecurityStandards(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "check_pod_security_standards",
+		Description: "Evaluate pods against the Kubernetes Pod Security Standards (baseline and restricted profiles). Reports which pods violate which controls and whether pod-security.kubernetes.io enforce/audit/warn labels are set on each namespace. Use namespace for one namespace or leave empty for all.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkPodSecurityStandardsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		profile := strings.ToLower(input.Profile)
+		if profile == "" {
+			profile = "restricted"
+		}
+		if profile != "baseline" && profile != "restricted" {
+			return util.ErrorResult("invalid profile %q (use baseline or restricted)", input.Profile), nil, nil
+		}
+
+		var namespaces []corev1.Namespace
+		if input.Namespace != "" {
+			ns, err := client.GetNamespace(ctx, input.Namespace)
+			if err != nil {
+				return util.HandleK8sError(fmt.Sprintf("getting namespace %s", input.Namespace), err), nil, nil
+			}
+			namespaces = append(namespaces, *ns)
+		} else {
+			list, err := client.ListNamespaces(ctx)
+			if err != nil {
+				return util.HandleK8sError("listing namespaces", err), nil, nil
+			}
+			namespaces = list
+		}
+
+		pods, err := client.ListPods(ctx, util.NamespaceOrAll(input.Namespace), metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Pod Security Standards Report (profile: %s, scope: %s)", profile, displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+
+		// Namespace labels
+		sb.WriteString(util.FormatSubHeader("Namespace Pod Security Labels"))
+		sb.WriteString("\n")
+		nsHeaders := []string{"NAMESPACE", "ENFORCE", "AUDIT", "WARN"}
+		nsRows := make([][]string, 0, len(namespaces))
+		var unlabeled []string
+		for _, ns := range namespaces {
+			enforce := pssLabelValue(ns.Labels, pssEnforceLabel)
+			nsRows = append(nsRows, []string{
+				ns.Name,
+				enforce,
+				pssLabelValue(ns.Labels, pssAuditLabel),
+				pssLabelValue(ns.Labels, pssWarnLabel),
+			})
+			if enforce == "-" && !isSystemNamespace(ns.Name) {
+				unlabeled = append(unlabeled, ns.Name)
+			}
+		}
+		sb.WriteString(util.FormatTable(nsHeaders, nsRows))
+
+		// Pod violations
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Pod Violations"))
+		sb.WriteString("\n")
+		violHeaders := []string{"NAMESPACE", "POD", "PROFILE", "CONTROL", "DETAIL"}
+		var violRows [][]string
+		controlCounts := make(map[string]int)
+		violatingPods := make(map[string]bool)
+		baselinePods := 0
+		for i := range pods {
+			p := &pods[i]
+			if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			violations := evaluatePodSecurityStandards(p.Spec)
+			podFailsBaseline := false
+			for _, v := range violations {
+				if v.Profile == "restricted" && profile == "baseline" {
+					continue
+				}
+				if v.Profile == "baseline" {
+					podFailsBaseline = true
+				}
+				violRows = append(violRows, []string{p.Namespace, truncateName(p.Name, 40), v.Profile, v.Control, v.Detail})
+				controlCounts[fmt.Sprintf("%s: %s", v.Profile, v.Control)]++
+				violatingPods[p.Namespace+"/"+p.Name] = true
+			}
+			if podFailsBaseline {
+				baselinePods++
+			}
+		}
+		if len(violRows) > 200 {
+			violRows = violRows[:200]
+			sb.WriteString(util.FormatTable(violHeaders, violRows))
+			sb.WriteString("... (truncated to 200 rows)\n")
+		} else {
+			sb.WriteString(util.FormatTable(violHeaders, violRows))
+		}
+
+		// Findings
+		sb.WriteString("\nFINDINGS:\n")
+		if baselinePods > 0 {
+			sb.WriteString(findings.Format("KD-SEC-008", "CRITICAL", fmt.Sprintf("%d pod(s) violate the baseline profile (known privilege escalation paths)", baselinePods)))
+			sb.WriteString("\n")
+		}
+		if n := len(violatingPods) - baselinePods; n > 0 && profile == "restricted" {
+			sb.WriteString(findings.Format("KD-SEC-008", "WARNING", fmt.Sprintf("%d pod(s) pass baseline but violate the restricted profile", n)))
+			sb.WriteString("\n")
+		}
+		for _, ns := range unlabeled {
+			sb.WriteString(findings.Format("KD-SEC-005", "WARNING", fmt.Sprintf("Namespace '%s' has no %s label — Pod Security Admission is not enforced", ns, pssEnforceLabel)))
+			sb.WriteString("\n")
+		}
+		if len(violatingPods) == 0 && len(unlabeled) == 0 {
+			sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("All pods comply with the %s profile and namespaces are labeled", profile)))
+			sb.WriteString("\n")
+		}
+
+		if len(controlCounts) > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("Violations by Control"))
+			sb.WriteString("\n")
+			keys := make([]string, 0, len(controlCounts))
+			for k := range controlCounts {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return controlCounts[keys[i]] > controlCounts[keys[j]] })
+			for _, k := range keys {
+				sb.WriteString(fmt.Sprintf("  %-50s %d\n", k, controlCounts[k]))
+			}
+		}
+
+		var actions []string
+		if len(unlabeled) > 0 {
+			actions = append(actions, fmt.Sprintf("Label namespaces with PSA modes, e.g. kubectl label ns <name> %s=%s %s=restricted", pssEnforceLabel, "baseline", pssWarnLabel))
+		}
+		if baselinePods > 0 {
+			actions = append(actions, "Remove privileged mode, host namespaces, hostPath volumes, and extra capabilities from baseline-violating pods")
+		}
+		if len(violatingPods) > baselinePods && profile == "restricted" {
+			actions = append(actions, "For restricted compliance set runAsNonRoot=true, allowPrivilegeEscalation=false, seccompProfile RuntimeDefault, and drop ALL capabilities")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range actions {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// evaluatePodSecurityStandards checks a pod spec against the baseline and
+// restricted Pod Security Standards and returns every failed control.
+func evaluatePodSecurityStandards(spec corev1.PodSpec) []pssViolation {
+	var out []pssViolation
+	add := func(profile, control, detail string) {
+		out = append(out, pssViolation{Profile: profile, Control: control, Detail: detail})
+	}
+
+	// --- Baseline ---
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		var ns []string
+		if spec.HostNetwork {
+			ns = append(ns, "hostNetwork")
+		}
+		if spec.HostPID {
+			ns = append(ns, "hostPID")
+		}
+		if spec.HostIPC {
+			ns = append(ns, "hostIPC")
+		}
+		add("baseline", "Host Namespaces", strings.Join(ns, ", "))
+	}
+	if sc := spec.SecurityContext; sc != nil {
+		if sc.WindowsOptions != nil && sc.WindowsOptions.HostProcess != nil && *sc.WindowsOptions.HostProcess {
+			add("baseline", "HostProcess", "pod windowsOptions.hostProcess=true")
+		}
+		if sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			add("baseline", "Seccomp", "pod seccompProfile Unconfined")
+		}
+		if sc.SELinuxOptions != nil && (sc.SELinuxOptions.User != "" || sc.SELinuxOptions.Role != "") {
+			add("baseline", "SELinux", "pod sets custom SELinux user/role")
+		}
+		for _, s := range sc.Sysctls {
+			if !pssSafeSysctls[s.Name] {
+				add("baseline", "Sysctls", fmt.Sprintf("unsafe sysctl %s", s.Name))
+			}
+		}
+	}
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			add("baseline", "HostPath Volumes", fmt.Sprintf("volume '%s' mounts %s", v.Name, v.HostPath.Path))
+		}
+	}
+
+	allContainers := make([]corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	allContainers = append(allContainers, spec.InitContainers...)
+	allContainers = append(allContainers, spec.Containers...)
+
+	for _, c := range allContainers {
+		for _, p := range c.Ports {
+			if p.HostPort != 0 {
+				add("baseline", "Host Ports", fmt.Sprintf("container '%s' hostPort %d", c.Name, p.HostPort))
+			}
+		}
+		sc := c.SecurityContext
+		if sc == nil {
+			continue
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			add("baseline", "Privileged Containers", fmt.Sprintf("container '%s' privileged", c.Name))
+		}
+		if sc.WindowsOptions != nil && sc.WindowsOptions.HostProcess != nil && *sc.WindowsOptions.HostProcess {
+			add("baseline", "HostProcess", fmt.Sprintf("container '%s' hostProcess=true", c.Name))
+		}
+		if sc.Capabilities != nil {
+			for _, cap := range sc.Capabilities.Add {
+				if !pssBaselineCapabilities[cap] {
+					add("baseline", "Capabilities", fmt.Sprintf("container '%s' adds %s", c.Name, cap))
+				}
+			}
+		}
+		if sc.ProcMount != nil && *sc.ProcMount == corev1.UnmaskedProcMount {
+			add("baseline", "/proc Mount Type", fmt.Sprintf("container '%s' procMount Unmasked", c.Name))
+		}
+		if sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			add("baseline", "Seccomp", fmt.Sprintf("container '%s' seccompProfile Unconfined", c.Name))
+		}
+		if sc.AppArmorProfile != nil && sc.AppArmorProfile.Type == corev1.AppArmorProfileTypeUnconfined {
+			add("baseline", "AppArmor", fmt.Sprintf("container '%s' AppArmor Unconfined", c.Name))
+		}
+		if sc.SELinuxOptions != nil && (sc.SELinuxOptions.User != "" || sc.SELinuxOptions.Role != "") {
+			add("baseline", "SELinux", fmt.Sprintf("container '%s' sets custom SELinux user/role", c.Name))
+		}
+	}
+
+	// --- Restricted ---
+	for _, v := range spec.Volumes {
+		if !pssRestrictedVolumeAllowed(v) {
+			add("restricted", "Volume Types", fmt.Sprintf("volume '%s' uses a disallowed type", v.Name))
+		}
+	}
+
+	podSC := spec.SecurityContext
+	podRunAsNonRoot := podSC != nil && podSC.RunAsNonRoot != nil && *podSC.RunAsNonRoot
+	podSeccompSet := podSC != nil && podSC.SeccompProfile != nil &&
+		(podSC.SeccompProfile.Type == corev1.SeccompProfileTypeRuntimeDefault || podSC.SeccompProfile.Type == corev1.SeccompProfileTypeLocalhost)
+	if podSC != nil && podSC.RunAsUser != nil && *podSC.RunAsUser == 0 {
+		add("restricted", "Running as Non-root user", "pod runAsUser=0")
+	}
+
+	for _, c := range allContainers {
+		sc := c.SecurityContext
+		if sc == nil || sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			add("restricted", "Privilege Escalation", fmt.Sprintf("container '%s' allowPrivilegeEscalation not false", c.Name))
+		}
+		containerNonRoot := sc != nil && sc.RunAsNonRoot != nil && *sc.RunAsNonRoot
+		containerRootFalse := sc != nil && sc.RunAsNonRoot != nil && !*sc.RunAsNonRoot
+		if (!podRunAsNonRoot && !containerNonRoot) || containerRootFalse {
+			add("restricted", "Running as Non-root", fmt.Sprintf("container '%s' runAsNonRoot not true", c.Name))
+		}
+		if sc != nil && sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			add("restricted", "Running as Non-root user", fmt.Sprintf("container '%s' runAsUser=0", c.Name))
+		}
+		containerSeccompSet := sc != nil && sc.SeccompProfile != nil &&
+			(sc.SeccompProfile.Type == corev1.SeccompProfileTypeRuntimeDefault || sc.SeccompProfile.Type == corev1.SeccompProfileTypeLocalhost)
+		if !podSeccompSet && !containerSeccompSet {
+			add("restricted", "Seccomp", fmt.Sprintf("container '%s' has no RuntimeDefault/Localhost seccomp profile", c.Name))
+		}
+
+		dropsAll := false
+		if sc != nil && sc.Capabilities != nil {
+			for _, cap := range sc.Capabilities.Drop {
+				if cap == "ALL" {
+					dropsAll = true
+				}
+			}
+			for _, cap := range sc.Capabilities.Add {
+				if cap != "NET_BIND_SERVICE" {
+					add("restricted", "Capabilities", fmt.Sprintf("container '%s' adds %s (only NET_BIND_SERVICE allowed)", c.Name, cap))
+				}
+			}
+		}
+		if !dropsAll {
+			add("restricted", "Capabilities", fmt.Sprintf("container '%s' does not drop ALL", c.Name))
+		}
+	}
+
+	return out
+}
+
+// pssRestrictedVolumeAllowed reports whether a volume type is permitted by the restricted profile.
+func pssRestrictedVolumeAllowed(v corev1.Volume) bool {
+	vs := v.VolumeSource
+	return vs.ConfigMap != nil || vs.CSI != nil || vs.DownwardAPI != nil || vs.EmptyDir != nil ||
+		vs.Ephemeral != nil || vs.PersistentVolumeClaim != nil || vs.Projected != nil || vs.Secret != nil
+}
+
+// pssLabelValue returns a PSA label value with its pinned version, or "-" if unset.
+func pssLabelValue(labels map[string]string, key string) string {
+	v, ok := labels[key]
+	if !ok {
+		return "-"
+	}
+	if version := labels[key+"-version"]; version != "" {
+		return fmt.Sprintf("%s (%s)", v, version)
+	}
+	return v
+}
+
+// isSystemNamespace reports whether a namespace is managed by Kubernetes itself.
+func isSystemNamespace(name string) bool {
+	return name == "kube-system" || name == "kube-public" || name == "kube-node-lease"
+}
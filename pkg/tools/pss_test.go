@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestEvaluatePodSecurityStandards(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+
+	privileged := corev1.PodSpec{
+		HostNetwork: true,
+		Volumes: []corev1.Volume{
+			{Name: "host", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run"}}},
+		},
+		Containers: []corev1.Container{
+			{Name: "app", SecurityContext: &corev1.SecurityContext{Privileged: boolPtr(true)}},
+		},
+	}
+	controls := make(map[string]bool)
+	for _, v := range evaluatePodSecurityStandards(privileged) {
+		if v.Profile == "baseline" {
+			controls[v.Control] = true
+		}
+	}
+	for _, want := range []string{"Host Namespaces", "HostPath Volumes", "Privileged Containers"} {
+		if !controls[want] {
+			t.Errorf("expected baseline violation %q, got %v", want, controls)
+		}
+	}
+
+	restricted := corev1.PodSpec{
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot:   boolPtr(true),
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+		Containers: []corev1.Container{
+			{
+				Name: "app",
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: boolPtr(false),
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				},
+			},
+		},
+	}
+	if v := evaluatePodSecurityStandards(restricted); len(v) != 0 {
+		t.Errorf("expected restricted-compliant pod to have no violations, got %+v", v)
+	}
+}
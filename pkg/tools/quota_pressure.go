@@ -0,0 +1,719 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// quotaPressureMaxWorkloads caps the workloads listed per namespace.
+const quotaPressureMaxWorkloads = 20
+
+// quotaComputeResource is the container resource behind a quota resource name
+// and whether quota counts its limit rather than its request.
+type quotaComputeResource struct {
+	Resource corev1.ResourceName
+	Limit    bool
+}
+
+// quotaComputeResources are the compute resources ResourceQuota tracks per
+// pod. Quota admission rejects pods whose containers leave any of them unset.
+var quotaComputeResources = map[corev1.ResourceName]quotaComputeResource{
+	corev1.ResourceCPU:            {corev1.ResourceCPU, false},
+	corev1.ResourceMemory:         {corev1.ResourceMemory, false},
+	corev1.ResourceRequestsCPU:    {corev1.ResourceCPU, false},
+	corev1.ResourceRequestsMemory: {corev1.ResourceMemory, false},
+	corev1.ResourceLimitsCPU:      {corev1.ResourceCPU, true},
+	corev1.ResourceLimitsMemory:   {corev1.ResourceMemory, true},
+}
+
+type analyzeQuotaPressureInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace to analyze (empty or 'all' for every namespace with a ResourceQuota or LimitRange)"`
+	Workload       string `json:"workload,omitempty" jsonschema:"Deployment or StatefulSet to predict a scale-up for (requires namespace and replicas)"`
+	WorkloadKind   string `json:"workload_kind,omitempty" jsonschema:"Kind of the workload: Deployment (default) or StatefulSet"`
+	Replicas       int    `json:"replicas,omitempty" jsonschema:"Target replica count for the scale-up prediction"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// quotaWorkload is a Deployment or StatefulSet with what analyze_quota_pressure
+// needs to price its replicas against quota.
+type quotaWorkload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Replicas  int32
+	MaxSurge  int
+	Template  corev1.PodSpec
+}
+
+// quotaRejection is a FailedCreate event caused by quota or LimitRange admission.
+type quotaRejection struct {
+	Cause   string
+	Object  string
+	Message string
+	Count   int32
+}
+
+func registerQuotaPressureTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// analyze_quota_pressure
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "analyze_quota_pressure",
+		Description: "Analyze how ResourceQuotas and LimitRanges interact in a namespace: LimitRange defaults applied to each workload's pods, " +
+			"workloads whose pods quota would reject for missing requests or limits, pods already rejected by quota or LimitRange (from events), " +
+			"remaining quota headroom, how many more replicas of each workload fit, and whether their next rolling update fits. " +
+			"Pass workload and replicas to predict whether a specific scale-up would be admitted.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeQuotaPressureInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+		if input.Workload != "" && (ns == "" || input.Replicas <= 0) {
+			return util.ErrorResult("a scale-up prediction needs namespace, workload, and replicas"), nil, nil
+		}
+
+		quotas, err := client.ListResourceQuotas(ctx, ns)
+		if err != nil {
+			return util.HandleK8sError("listing resource quotas", err), nil, nil
+		}
+		limitRanges, err := client.ListLimitRanges(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing limit ranges", err), nil, nil
+		}
+		workloads, err := listQuotaWorkloads(ctx, client, ns)
+		if err != nil {
+			return util.HandleK8sError("listing workloads", err), nil, nil
+		}
+		events, eventsErr := client.ListEventsMatching(ctx, ns, metav1.ListOptions{}, k8s.EventFilter{Reasons: []string{"FailedCreate"}})
+
+		quotasByNS := make(map[string][]corev1.ResourceQuota)
+		for _, q := range quotas {
+			quotasByNS[q.Namespace] = append(quotasByNS[q.Namespace], q)
+		}
+		rangesByNS := make(map[string][]corev1.LimitRange)
+		for _, lr := range limitRanges {
+			rangesByNS[lr.Namespace] = append(rangesByNS[lr.Namespace], lr)
+		}
+		workloadsByNS := make(map[string][]quotaWorkload)
+		for _, w := range workloads {
+			workloadsByNS[w.Namespace] = append(workloadsByNS[w.Namespace], w)
+		}
+		rejectionsByNS := make(map[string][]quotaRejection)
+		for _, e := range events {
+			if cause := classifyQuotaRejection(e.Message); cause != "" {
+				rejectionsByNS[e.Namespace] = append(rejectionsByNS[e.Namespace], quotaRejection{
+					Cause:   cause,
+					Object:  e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name,
+					Message: e.Message,
+					Count:   max(e.Count, 1),
+				})
+			}
+		}
+
+		nsSet := make(map[string]bool)
+		if ns != "" {
+			nsSet[ns] = true
+		}
+		for n := range quotasByNS {
+			nsSet[n] = true
+		}
+		for n := range rangesByNS {
+			nsSet[n] = true
+		}
+		for n := range rejectionsByNS {
+			nsSet[n] = true
+		}
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Quota Pressure (namespace: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n")
+		if len(nsSet) == 0 {
+			sb.WriteString("\nNo ResourceQuotas or LimitRanges found; pods are only limited by node capacity.\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		for _, n := range sortedKeys(nsSet) {
+			nsQuotas, nsRanges := quotasByNS[n], rangesByNS[n]
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader(fmt.Sprintf("NAMESPACE %s", n)))
+			sb.WriteString("\n")
+
+			// LimitRange defaults
+			if len(nsRanges) == 0 {
+				sb.WriteString("  LimitRanges: none (containers without requests or limits get no defaults)\n")
+			} else {
+				sb.WriteString("  LimitRange container defaults:\n")
+				sb.WriteString(util.FormatTable([]string{"LIMITRANGE", "RESOURCE", "DEFAULT REQUEST", "DEFAULT LIMIT", "MIN", "MAX"}, limitRangeDefaultRows(nsRanges)))
+				findingLines = append(findingLines, limitRangeFindings(n, nsRanges, nsQuotas)...)
+			}
+
+			// Quota headroom
+			if len(nsQuotas) == 0 {
+				sb.WriteString("  ResourceQuotas: none\n")
+			} else {
+				sb.WriteString("  Quota headroom:\n")
+				var rows [][]string
+				for _, q := range nsQuotas {
+					scope := quotaScopeSummary(&q)
+					for _, name := range sortedResourceNames(q.Status.Hard) {
+						hard, used := q.Status.Hard[name], q.Status.Used[name]
+						remaining := hard.DeepCopy()
+						remaining.Sub(used)
+						if remaining.Sign() < 0 {
+							remaining = resource.Quantity{}
+						}
+						pct := quotaPercent(used, hard)
+						pctStr := fmt.Sprintf("%d%%", pct)
+						switch {
+						case pct >= 100:
+							pctStr += " [FULL]"
+							findingLines = append(findingLines, findings.Format("KD-RES-003", "CRITICAL", fmt.Sprintf("Quota %s/%s is exhausted for %s (%s of %s); new pods needing it are rejected.", n, q.Name, name, used.String(), hard.String())))
+						case pct >= util.ResourceUsageWarningPercent:
+							pctStr += " [WARNING]"
+							findingLines = append(findingLines, findings.Format("KD-RES-003", "WARNING", fmt.Sprintf("Quota %s/%s is at %d%% for %s (%s of %s).", n, q.Name, pct, name, used.String(), hard.String())))
+						}
+						rows = append(rows, []string{q.Name + scope, string(name), used.String(), hard.String(), remaining.String(), pctStr})
+					}
+				}
+				sb.WriteString(util.FormatTable([]string{"QUOTA", "RESOURCE", "USED", "HARD", "REMAINING", "USAGE"}, rows))
+			}
+
+			// Workload headroom
+			nsWorkloads := workloadsByNS[n]
+			if len(nsQuotas) > 0 && len(nsWorkloads) > 0 {
+				type fit struct {
+					w         quotaWorkload
+					usage     corev1.ResourceList
+					fits      int
+					limitedBy string
+					missing   []string
+				}
+				var fits []fit
+				for _, w := range nsWorkloads {
+					spec := applyLimitRangeDefaults(&w.Template, nsRanges)
+					usage := podQuotaUsage(spec)
+					count, limitedBy := replicasThatFit(nsQuotas, spec, usage)
+					fits = append(fits, fit{w, usage, count, limitedBy, missingQuotaValues(nsQuotas, spec)})
+				}
+				sort.SliceStable(fits, func(i, j int) bool {
+					return fitRank(fits[i].fits, fits[i].missing) < fitRank(fits[j].fits, fits[j].missing)
+				})
+				sb.WriteString("  Workload headroom (LimitRange defaults applied):\n")
+				var rows [][]string
+				for i, f := range fits {
+					name := f.w.Kind + "/" + f.w.Name
+					if len(f.missing) > 0 {
+						findingLines = append(findingLines, findings.Format("KD-RES-007", "CRITICAL", fmt.Sprintf("%s in %s: quota rejects its pods because containers do not set %s and no LimitRange default supplies it.", name, n, strings.Join(f.missing, ", "))))
+						actions = append(actions, fmt.Sprintf("Set %s on every container of %s in %s, or add a LimitRange with defaults for them.", strings.Join(f.missing, ", "), name, n))
+					} else if f.fits == 0 {
+						findingLines = append(findingLines, findings.Format("KD-RES-003", "WARNING", fmt.Sprintf("%s in %s cannot add a single replica: quota %s has no room left.", name, n, f.limitedBy)))
+					} else if f.fits >= 0 && f.w.MaxSurge > f.fits {
+						findingLines = append(findingLines, findings.Format("KD-RES-003", "WARNING", fmt.Sprintf("%s in %s: its next rolling update needs %d surge pods but only %d fit in quota %s; the rollout will stall.", name, n, f.w.MaxSurge, f.fits, f.limitedBy)))
+					}
+					if i == quotaPressureMaxWorkloads {
+						continue
+					}
+					more := "unlimited"
+					switch {
+					case len(f.missing) > 0:
+						more = "rejected"
+					case f.fits >= 0:
+						more = fmt.Sprintf("+%d", f.fits)
+					}
+					rows = append(rows, []string{name, fmt.Sprintf("%d", f.w.Replicas), formatQuotaUsage(f.usage), more, valueOrNone(f.limitedBy)})
+				}
+				sb.WriteString(util.FormatTable([]string{"WORKLOAD", "REPLICAS", "PER REPLICA", "MORE FIT", "LIMITED BY"}, rows))
+				if len(fits) > quotaPressureMaxWorkloads {
+					sb.WriteString(fmt.Sprintf("  ... and %d more workloads\n", len(fits)-quotaPressureMaxWorkloads))
+				}
+			}
+
+			// Rejections
+			if rejections := rejectionsByNS[n]; len(rejections) > 0 {
+				sb.WriteString("  Pods rejected at admission (FailedCreate events):\n")
+				for _, r := range rejections {
+					sb.WriteString(fmt.Sprintf("    %s (x%d) %s: %s\n", r.Object, r.Count, r.Cause, truncateName(r.Message, 200)))
+					findingLines = append(findingLines, findings.Format("KD-RES-003", "CRITICAL", fmt.Sprintf("%s in %s cannot create pods: %s.", r.Object, n, r.Cause)))
+				}
+				steps = append(steps, nextStep("analyze_events", "see the full admission failures and when they started", "namespace", n))
+			}
+
+			for _, q := range nsQuotas {
+				if quotaPercent(q.Status.Used[corev1.ResourcePods], q.Status.Hard[corev1.ResourcePods]) >= 100 {
+					actions = append(actions, fmt.Sprintf("Delete completed or failed pods in %s, or raise the pods limit of quota %s.", n, q.Name))
+				}
+			}
+		}
+		if eventsErr != nil {
+			sb.WriteString(fmt.Sprintf("\n  (could not list events for admission rejections: %v)\n", eventsErr))
+		}
+
+		// Scale-up prediction
+		if input.Workload != "" {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("SCALE-UP PREDICTION"))
+			sb.WriteString("\n")
+			w, ok := findQuotaWorkload(workloads, input.Workload, input.WorkloadKind)
+			if !ok {
+				return util.ErrorResult("workload %q not found in namespace %s (Deployments and StatefulSets are supported)", input.Workload, ns), nil, nil
+			}
+			spec := applyLimitRangeDefaults(&w.Template, rangesByNS[ns])
+			verdict, detail := predictScaleUp(quotasByNS[ns], spec, w, input.Replicas)
+			sb.WriteString(util.FormatKeyValue("Workload", fmt.Sprintf("%s/%s", w.Kind, w.Name)) + "\n")
+			sb.WriteString(util.FormatKeyValue("Replicas", fmt.Sprintf("%d -> %d", w.Replicas, input.Replicas)) + "\n")
+			sb.WriteString(util.FormatKeyValue("Per Replica", formatQuotaUsage(podQuotaUsage(spec))) + "\n")
+			sb.WriteString(util.FormatKeyValue("Verdict", verdict) + "\n")
+			for _, d := range detail {
+				sb.WriteString("  " + d + "\n")
+			}
+			if verdict != "fits" {
+				findingLines = append(findingLines, findings.Format("KD-RES-003", "WARNING", fmt.Sprintf("Scaling %s/%s to %d replicas %s.", w.Kind, w.Name, input.Replicas, verdict)))
+				actions = append(actions, fmt.Sprintf("Raise the quota in %s, lower the per-replica requests of %s, or scale to fewer replicas.", ns, w.Name))
+			}
+			steps = append(steps, nextStep("analyze_node_capacity", "check that the nodes, not just the quota, have room for the new replicas"))
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  No quota pressure: every workload fits and nothing was rejected at admission.\n")
+		}
+		for _, f := range dedupe(findingLines) {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// listQuotaWorkloads returns the Deployments and StatefulSets in a namespace
+// (empty = all namespaces).
+func listQuotaWorkloads(ctx context.Context, client *k8s.ClusterClient, namespace string) ([]quotaWorkload, error) {
+	deployments, err := client.ListDeployments(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	statefulSets, err := client.ListStatefulSets(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	workloads := make([]quotaWorkload, 0, len(deployments)+len(statefulSets))
+	for _, d := range deployments {
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		workloads = append(workloads, quotaWorkload{
+			Kind: "Deployment", Namespace: d.Namespace, Name: d.Name, Replicas: replicas,
+			MaxSurge: deploymentMaxSurge(&d, replicas), Template: d.Spec.Template.Spec,
+		})
+	}
+	for _, s := range statefulSets {
+		replicas := int32(1)
+		if s.Spec.Replicas != nil {
+			replicas = *s.Spec.Replicas
+		}
+		workloads = append(workloads, quotaWorkload{
+			Kind: "StatefulSet", Namespace: s.Namespace, Name: s.Name, Replicas: replicas, Template: s.Spec.Template.Spec,
+		})
+	}
+	return workloads, nil
+}
+
+// deploymentMaxSurge resolves how many extra pods a rolling update creates.
+func deploymentMaxSurge(d *appsv1.Deployment, replicas int32) int {
+	if d.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType || replicas == 0 {
+		return 0
+	}
+	surge := intstr.FromString("25%")
+	if d.Spec.Strategy.RollingUpdate != nil && d.Spec.Strategy.RollingUpdate.MaxSurge != nil {
+		surge = *d.Spec.Strategy.RollingUpdate.MaxSurge
+	}
+	n, err := intstr.GetScaledValueFromIntOrPercent(&surge, int(replicas), true)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func findQuotaWorkload(workloads []quotaWorkload, name, kind string) (quotaWorkload, bool) {
+	switch strings.ToLower(kind) {
+	case "", "deploy":
+		kind = "Deployment"
+	case "sts":
+		kind = "StatefulSet"
+	}
+	for _, w := range workloads {
+		if w.Name == name && strings.EqualFold(w.Kind, kind) {
+			return w, true
+		}
+	}
+	return quotaWorkload{}, false
+}
+
+// applyLimitRangeDefaults returns a copy of the pod spec with the defaults the
+// LimitRanger admission plugin would set, and requests defaulted to limits as
+// the API server does.
+func applyLimitRangeDefaults(spec *corev1.PodSpec, ranges []corev1.LimitRange) *corev1.PodSpec {
+	out := spec.DeepCopy()
+	containers := make([]*corev1.Container, 0, len(out.InitContainers)+len(out.Containers))
+	for i := range out.InitContainers {
+		containers = append(containers, &out.InitContainers[i])
+	}
+	for i := range out.Containers {
+		containers = append(containers, &out.Containers[i])
+	}
+	for _, c := range containers {
+		for _, lr := range ranges {
+			for _, item := range lr.Spec.Limits {
+				if item.Type != corev1.LimitTypeContainer {
+					continue
+				}
+				for res, q := range item.Default {
+					if _, ok := c.Resources.Limits[res]; !ok {
+						if c.Resources.Limits == nil {
+							c.Resources.Limits = corev1.ResourceList{}
+						}
+						c.Resources.Limits[res] = q.DeepCopy()
+					}
+				}
+				for res, q := range item.DefaultRequest {
+					if _, ok := c.Resources.Requests[res]; !ok {
+						if c.Resources.Requests == nil {
+							c.Resources.Requests = corev1.ResourceList{}
+						}
+						c.Resources.Requests[res] = q.DeepCopy()
+					}
+				}
+			}
+		}
+		for res, q := range c.Resources.Limits {
+			if _, ok := c.Resources.Requests[res]; !ok {
+				if c.Resources.Requests == nil {
+					c.Resources.Requests = corev1.ResourceList{}
+				}
+				c.Resources.Requests[res] = q.DeepCopy()
+			}
+		}
+	}
+	return out
+}
+
+// podQuotaUsage returns what one pod charges against quota: the sum over its
+// containers and sidecars, or the largest init container if that is more,
+// plus pod overhead.
+func podQuotaUsage(spec *corev1.PodSpec) corev1.ResourceList {
+	usage := corev1.ResourceList{
+		corev1.ResourcePods:               resource.MustParse("1"),
+		corev1.ResourceName("count/pods"): resource.MustParse("1"),
+	}
+	for name, cr := range quotaComputeResources {
+		values := func(c *corev1.Container) corev1.ResourceList {
+			if cr.Limit {
+				return c.Resources.Limits
+			}
+			return c.Resources.Requests
+		}
+		var total, initMax resource.Quantity
+		for i := range spec.Containers {
+			total.Add(values(&spec.Containers[i])[cr.Resource])
+		}
+		for i := range spec.InitContainers {
+			c := &spec.InitContainers[i]
+			if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+				total.Add(values(c)[cr.Resource])
+			} else if q := values(c)[cr.Resource]; q.Cmp(initMax) > 0 {
+				initMax = q
+			}
+		}
+		if initMax.Cmp(total) > 0 {
+			total = initMax
+		}
+		if q, ok := spec.Overhead[cr.Resource]; ok {
+			total.Add(q)
+		}
+		if !total.IsZero() {
+			usage[name] = total
+		}
+	}
+	return usage
+}
+
+// quotaApplies reports whether a quota's scopes select pods with this spec.
+// Scopes it cannot evaluate from a pod spec are assumed to match.
+func quotaApplies(q *corev1.ResourceQuota, spec *corev1.PodSpec) bool {
+	bestEffort := true
+	for _, c := range append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...) {
+		if len(c.Resources.Requests) > 0 || len(c.Resources.Limits) > 0 {
+			bestEffort = false
+		}
+	}
+	matches := func(scope corev1.ResourceQuotaScope, op corev1.ScopeSelectorOperator, values []string) bool {
+		switch scope {
+		case corev1.ResourceQuotaScopeTerminating:
+			return spec.ActiveDeadlineSeconds != nil
+		case corev1.ResourceQuotaScopeNotTerminating:
+			return spec.ActiveDeadlineSeconds == nil
+		case corev1.ResourceQuotaScopeBestEffort:
+			return bestEffort
+		case corev1.ResourceQuotaScopeNotBestEffort:
+			return !bestEffort
+		case corev1.ResourceQuotaScopePriorityClass:
+			listed := false
+			for _, v := range values {
+				listed = listed || v == spec.PriorityClassName
+			}
+			switch op {
+			case corev1.ScopeSelectorOpIn:
+				return listed
+			case corev1.ScopeSelectorOpNotIn:
+				return !listed
+			case corev1.ScopeSelectorOpDoesNotExist:
+				return spec.PriorityClassName == ""
+			default:
+				return spec.PriorityClassName != ""
+			}
+		}
+		return true
+	}
+	for _, s := range q.Spec.Scopes {
+		if !matches(s, corev1.ScopeSelectorOpExists, nil) {
+			return false
+		}
+	}
+	if q.Spec.ScopeSelector != nil {
+		for _, e := range q.Spec.ScopeSelector.MatchExpressions {
+			if !matches(e.ScopeName, e.Operator, e.Values) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// replicasThatFit returns how many more pods with this usage fit in every
+// applicable quota, and the quota/resource that runs out first; -1 means no
+// quota limits them.
+func replicasThatFit(quotas []corev1.ResourceQuota, spec *corev1.PodSpec, usage corev1.ResourceList) (int, string) {
+	fits, limitedBy := -1, ""
+	for i := range quotas {
+		q := &quotas[i]
+		if !quotaApplies(q, spec) {
+			continue
+		}
+		for _, name := range sortedResourceNames(q.Status.Hard) {
+			need, ok := usage[name]
+			if !ok || need.IsZero() {
+				continue
+			}
+			hard, used := q.Status.Hard[name], q.Status.Used[name]
+			n := 0
+			if remaining := hard.MilliValue() - used.MilliValue(); remaining > 0 {
+				n = int(remaining / need.MilliValue())
+			}
+			if fits < 0 || n < fits {
+				fits, limitedBy = n, q.Name+"/"+string(name)
+			}
+		}
+	}
+	return fits, limitedBy
+}
+
+// missingQuotaValues returns the quota-tracked compute resources some
+// container leaves unset, which makes quota admission reject the pod.
+func missingQuotaValues(quotas []corev1.ResourceQuota, spec *corev1.PodSpec) []string {
+	missing := make(map[string]bool)
+	for i := range quotas {
+		if !quotaApplies(&quotas[i], spec) {
+			continue
+		}
+		for name := range quotas[i].Status.Hard {
+			cr, ok := quotaComputeResources[name]
+			if !ok {
+				continue
+			}
+			for _, c := range append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...) {
+				values := c.Resources.Requests
+				if cr.Limit {
+					values = c.Resources.Limits
+				}
+				if _, set := values[cr.Resource]; !set {
+					missing[string(name)] = true
+				}
+			}
+		}
+	}
+	return sortedKeys(missing)
+}
+
+// predictScaleUp decides whether scaling a workload to replicas stays within
+// quota, including the surge pods of its next rolling update.
+func predictScaleUp(quotas []corev1.ResourceQuota, spec *corev1.PodSpec, w quotaWorkload, replicas int) (string, []string) {
+	if missing := missingQuotaValues(quotas, spec); len(missing) > 0 {
+		return "rejected", []string{fmt.Sprintf("Quota admission rejects every new pod: containers do not set %s.", strings.Join(missing, ", "))}
+	}
+	added := replicas - int(w.Replicas)
+	if added <= 0 {
+		return "fits", []string{fmt.Sprintf("Scaling down or to the same count (%d -> %d) releases quota rather than consuming it.", w.Replicas, replicas)}
+	}
+	fits, limitedBy := replicasThatFit(quotas, spec, podQuotaUsage(spec))
+	if fits < 0 {
+		return "fits", []string{"No ResourceQuota in the namespace applies to these pods."}
+	}
+	if added > fits {
+		return fmt.Sprintf("does not fit (%d of %d new replicas fit)", fits, added),
+			[]string{fmt.Sprintf("Quota %s runs out after %d more replicas; the remaining pods fail with FailedCreate 'exceeded quota'.", limitedBy, fits)}
+	}
+	detail := []string{fmt.Sprintf("All %d new replicas fit; %d more would still fit afterwards (limited by %s).", added, fits-added, limitedBy)}
+	if surge := w.MaxSurge; w.Kind == "Deployment" && surge > 0 {
+		if w.Replicas > 0 {
+			surge = surge * replicas / int(w.Replicas)
+		}
+		if surge > fits-added {
+			return "fits, but the next rolling update stalls", append(detail, fmt.Sprintf("A rolling update at %d replicas creates about %d surge pods, more than the %d that would remain.", replicas, surge, fits-added))
+		}
+	}
+	return "fits", detail
+}
+
+// classifyQuotaRejection names the admission failure behind a FailedCreate
+// event message, or returns "" when quota and LimitRange are not the cause.
+func classifyQuotaRejection(msg string) string {
+	switch {
+	case strings.Contains(msg, "exceeded quota"):
+		return "exceeded quota"
+	case strings.Contains(msg, "failed quota") && strings.Contains(msg, "must specify"):
+		return "missing requests or limits required by quota"
+	case strings.Contains(msg, "per Container is") || strings.Contains(msg, "per Pod is") || strings.Contains(msg, "limit to request ratio"):
+		return "violates LimitRange"
+	}
+	return ""
+}
+
+// limitRangeDefaultRows renders the container defaults and bounds of LimitRanges.
+func limitRangeDefaultRows(ranges []corev1.LimitRange) [][]string {
+	var rows [][]string
+	for _, lr := range ranges {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			resources := collectLimitRangeResources(item)
+			sort.Strings(resources)
+			for _, res := range resources {
+				name := corev1.ResourceName(res)
+				rows = append(rows, []string{lr.Name, res, quantityStr(item.DefaultRequest, name), quantityStr(item.Default, name), quantityStr(item.Min, name), quantityStr(item.Max, name)})
+			}
+		}
+	}
+	return rows
+}
+
+// limitRangeFindings flags LimitRange defaults that conflict with the range's
+// own bounds, and defaults that make pods consume request quota at limit size.
+func limitRangeFindings(namespace string, ranges []corev1.LimitRange, quotas []corev1.ResourceQuota) []string {
+	tracksRequests := make(map[corev1.ResourceName]bool)
+	for _, q := range quotas {
+		for name := range q.Status.Hard {
+			if cr, ok := quotaComputeResources[name]; ok && !cr.Limit {
+				tracksRequests[cr.Resource] = true
+			}
+		}
+	}
+	var findingLines []string
+	for _, lr := range ranges {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for res, def := range item.Default {
+				if hi, ok := item.Max[res]; ok && def.Cmp(hi) > 0 {
+					findingLines = append(findingLines, findings.Format("KD-RES-007", "CRITICAL", fmt.Sprintf("LimitRange %s/%s defaults %s limits to %s, above its max %s; containers relying on the default are rejected.", namespace, lr.Name, res, def.String(), hi.String())))
+				}
+				if req, ok := item.DefaultRequest[res]; ok && req.Cmp(def) == 0 && tracksRequests[res] {
+					findingLines = append(findingLines, findings.Format("KD-RES-007", "INFO", fmt.Sprintf("LimitRange %s/%s defaults %s requests to the limit (%s); containers without requests are charged against quota at their limit.", namespace, lr.Name, res, def.String())))
+				}
+			}
+			for res, req := range item.DefaultRequest {
+				if lo, ok := item.Min[res]; ok && req.Cmp(lo) < 0 {
+					findingLines = append(findingLines, findings.Format("KD-RES-007", "CRITICAL", fmt.Sprintf("LimitRange %s/%s defaults %s requests to %s, below its min %s; containers relying on the default are rejected.", namespace, lr.Name, res, req.String(), lo.String())))
+				}
+			}
+		}
+	}
+	sort.Strings(findingLines)
+	return findingLines
+}
+
+func quotaScopeSummary(q *corev1.ResourceQuota) string {
+	var scopes []string
+	for _, s := range q.Spec.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	if q.Spec.ScopeSelector != nil {
+		for _, e := range q.Spec.ScopeSelector.MatchExpressions {
+			scopes = append(scopes, fmt.Sprintf("%s %s %s", e.ScopeName, e.Operator, strings.Join(e.Values, ",")))
+		}
+	}
+	if len(scopes) == 0 {
+		return ""
+	}
+	return " (" + strings.TrimSpace(strings.Join(scopes, "; ")) + ")"
+}
+
+func quotaPercent(used, hard resource.Quantity) int {
+	if hard.MilliValue() <= 0 {
+		return 0
+	}
+	return int(used.MilliValue() * 100 / hard.MilliValue())
+}
+
+// formatQuotaUsage summarizes a pod's quota charge as requests/limits for CPU and memory.
+func formatQuotaUsage(usage corev1.ResourceList) string {
+	var parts []string
+	for _, name := range []corev1.ResourceName{corev1.ResourceRequestsCPU, corev1.ResourceRequestsMemory, corev1.ResourceLimitsCPU, corev1.ResourceLimitsMemory} {
+		if q, ok := usage[name]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%s", name, q.String()))
+		}
+	}
+	if len(parts) == 0 {
+		return "no requests/limits"
+	}
+	return strings.Join(parts, " ")
+}
+
+// fitRank orders workloads from most to least constrained.
+func fitRank(fits int, missing []string) int {
+	switch {
+	case len(missing) > 0:
+		return -1
+	case fits < 0:
+		return int(^uint(0) >> 1)
+	}
+	return fits
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testQuota(name string, hard, used map[corev1.ResourceName]string) corev1.ResourceQuota {
+	q := corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Status:     corev1.ResourceQuotaStatus{Hard: corev1.ResourceList{}, Used: corev1.ResourceList{}},
+	}
+	for k, v := range hard {
+		q.Status.Hard[k] = resource.MustParse(v)
+	}
+	for k, v := range used {
+		q.Status.Used[k] = resource.MustParse(v)
+	}
+	return q
+}
+
+func TestApplyLimitRangeDefaults(t *testing.T) {
+	ranges := []corev1.LimitRange{{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults"},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+			Type:           corev1.LimitTypeContainer,
+			Default:        corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		}}},
+	}}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "bare"},
+		{Name: "explicit", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")}}},
+	}}
+
+	got := applyLimitRangeDefaults(spec, ranges)
+	if len(spec.Containers[0].Resources.Limits) != 0 {
+		t.Error("applyLimitRangeDefaults modified its input")
+	}
+	bare := got.Containers[0].Resources
+	if bare.Requests.Cpu().String() != "100m" || bare.Limits.Memory().String() != "512Mi" {
+		t.Errorf("bare container = requests %v limits %v, want the LimitRange defaults", bare.Requests, bare.Limits)
+	}
+	if bare.Requests.Memory().String() != "512Mi" {
+		t.Errorf("bare memory request = %s, want it defaulted to the 512Mi limit", bare.Requests.Memory())
+	}
+	if got.Containers[1].Resources.Requests.Cpu().String() != "250m" {
+		t.Errorf("explicit cpu request = %s, want 250m kept", got.Containers[1].Resources.Requests.Cpu())
+	}
+}
+
+func TestPodQuotaUsage(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	req := func(cpu string) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}}
+	}
+	spec := &corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "migrate", Resources: req("2")},
+			{Name: "proxy", RestartPolicy: &always, Resources: req("100m")},
+		},
+		Containers: []corev1.Container{{Name: "app", Resources: req("500m")}, {Name: "worker", Resources: req("500m")}},
+	}
+
+	usage := podQuotaUsage(spec)
+	if cpu := usage[corev1.ResourceRequestsCPU]; cpu.String() != "2" {
+		t.Errorf("requests.cpu = %s, want 2 (the init container outweighs 1100m of app containers and sidecar)", cpu.String())
+	}
+
+	spec.InitContainers[0].Resources = req("1")
+	usage = podQuotaUsage(spec)
+	if cpu := usage[corev1.ResourceRequestsCPU]; cpu.String() != "1100m" {
+		t.Errorf("requests.cpu = %s, want 1100m", cpu.String())
+	}
+	if _, ok := usage[corev1.ResourceLimitsCPU]; ok {
+		t.Error("limits.cpu charged for a pod without limits")
+	}
+}
+
+func TestReplicasThatFit(t *testing.T) {
+	quotas := []corev1.ResourceQuota{
+		testQuota("compute", map[corev1.ResourceName]string{"requests.cpu": "4", "requests.memory": "8Gi"}, map[corev1.ResourceName]string{"requests.cpu": "3", "requests.memory": "2Gi"}),
+		testQuota("objects", map[corev1.ResourceName]string{"pods": "10"}, map[corev1.ResourceName]string{"pods": "2"}),
+	}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}}}}}
+
+	fits, limitedBy := replicasThatFit(quotas, spec, podQuotaUsage(spec))
+	if fits != 4 || limitedBy != "compute/requests.cpu" {
+		t.Errorf("replicasThatFit() = %d, %s; want 4, compute/requests.cpu", fits, limitedBy)
+	}
+
+	if fits, _ := replicasThatFit(nil, spec, podQuotaUsage(spec)); fits != -1 {
+		t.Errorf("replicasThatFit() without quotas = %d, want -1", fits)
+	}
+}
+
+func TestQuotaAppliesScopes(t *testing.T) {
+	bestEffort := testQuota("best-effort", map[corev1.ResourceName]string{"pods": "5"}, nil)
+	bestEffort.Spec.Scopes = []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}
+	highPriority := testQuota("high", map[corev1.ResourceName]string{"pods": "5"}, nil)
+	highPriority.Spec.ScopeSelector = &corev1.ScopeSelector{MatchExpressions: []corev1.ScopedResourceSelectorRequirement{
+		{ScopeName: corev1.ResourceQuotaScopePriorityClass, Operator: corev1.ScopeSelectorOpIn, Values: []string{"high"}},
+	}}
+
+	spec := &corev1.PodSpec{PriorityClassName: "high", Containers: []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+	}}}}
+	if quotaApplies(&bestEffort, spec) {
+		t.Error("BestEffort quota applies to a pod with requests")
+	}
+	if !quotaApplies(&highPriority, spec) {
+		t.Error("PriorityClass In [high] quota does not apply to a high-priority pod")
+	}
+	spec.PriorityClassName = "low"
+	if quotaApplies(&highPriority, spec) {
+		t.Error("PriorityClass In [high] quota applies to a low-priority pod")
+	}
+}
+
+func TestMissingQuotaValues(t *testing.T) {
+	quotas := []corev1.ResourceQuota{testQuota("compute", map[corev1.ResourceName]string{"limits.memory": "8Gi", "requests.cpu": "4", "pods": "10"}, nil)}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+	}}}}
+
+	if got := missingQuotaValues(quotas, spec); len(got) != 1 || got[0] != "limits.memory" {
+		t.Errorf("missingQuotaValues() = %v, want [limits.memory]", got)
+	}
+}
+
+func TestPredictScaleUp(t *testing.T) {
+	quotas := []corev1.ResourceQuota{testQuota("compute", map[corev1.ResourceName]string{"requests.cpu": "4"}, map[corev1.ResourceName]string{"requests.cpu": "2"})}
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+	}}}}
+	w := quotaWorkload{Kind: "Deployment", Name: "web", Replicas: 4, MaxSurge: 1}
+
+	tests := []struct {
+		replicas int
+		want     string
+	}{
+		{replicas: 6, want: "fits"},
+		{replicas: 8, want: "fits, but the next rolling update stalls"},
+		{replicas: 10, want: "does not fit (4 of 6 new replicas fit)"},
+		{replicas: 2, want: "fits"},
+	}
+	for _, tt := range tests {
+		if got, _ := predictScaleUp(quotas, spec, w, tt.replicas); got != tt.want {
+			t.Errorf("predictScaleUp(%d) = %q, want %q", tt.replicas, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyQuotaRejection(t *testing.T) {
+	tests := map[string]string{
+		`pods "web-1" is forbidden: exceeded quota: compute, requested: requests.cpu=500m, used: requests.cpu=4, limited: requests.cpu=4`: "exceeded quota",
+		`pods "web-1" is forbidden: failed quota: compute: must specify limits.memory for: app`:                                           "missing requests or limits required by quota",
+		`pods "web-1" is forbidden: maximum cpu usage per Container is 2, but limit is 4`:                                                 "violates LimitRange",
+		`Error creating: pods "web-1" is forbidden: error looking up service account shop/web: serviceaccount "web" not found`:            "",
+	}
+	for msg, want := range tests {
+		if got := classifyQuotaRejection(msg); got != want {
+			t.Errorf("classifyQuotaRejection(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
+
+func TestLimitRangeFindings(t *testing.T) {
+	ranges := []corev1.LimitRange{{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults"},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+			Type:    corev1.LimitTypeContainer,
+			Default: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			Max:     corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}}},
+	}}
+
+	findings := strings.Join(limitRangeFindings("shop", ranges, nil), "\n")
+	if !strings.Contains(findings, "above its max 1Gi") {
+		t.Errorf("limitRangeFindings() = %q, want a default-above-max finding", findings)
+	}
+}
@@ -2,13 +2,63 @@ package tools
 
 import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/azure"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/flux"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/placement"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/pricing"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/registry"
 )
 
+// Options holds server-level switches that change which tools are registered
+// or how they behave.
+type Options struct {
+	// EnableExec permits tools to exec commands inside pods: active
+	// connectivity checks and the exec_in_pod tool.
+	EnableExec bool
+
+	// EnableWrite registers remediation tools that change cluster state,
+	// such as restart_deployment, delete_pod, and cordon_node.
+	EnableWrite bool
+
+	// PriceTable maps node instance types to hourly prices for
+	// estimate_cost_waste. Nil when no --price-file was given.
+	PriceTable *pricing.Table
+
+	// NamespaceAllowlist restricts every tool to these namespaces. Tool
+	// calls naming another namespace are rejected. Empty means unrestricted.
+	NamespaceAllowlist []string
+
+	// PlacementPolicy maps priority classes and namespaces to the node pools
+	// they may run on for check_placement_policy. Nil uses placement.Default.
+	PlacementPolicy *placement.Policy
+
+	// AppGateway reads Application Gateway configuration and backend health
+	// from Azure for check_appgw_backends. Nil unless --azure-appgw is set.
+	AppGateway *azure.AppGatewayClient
+
+	// Registry queries container registries for check_image_freshness.
+	// Nil unless --registry-lookup is set.
+	Registry *registry.Client
+
+	// Suppressions hides findings for accepted risks from every tool result.
+	// Nil when neither --suppress-file nor --suppress was given.
+	Suppressions *findings.Suppressions
+}
+
 // RegisterAll registers all MCP tools with the server.
 // fluxClient may be nil if FluxCD is not available.
-func RegisterAll(server *mcp.Server, client *k8s.ClusterClient, fluxClient *flux.FluxClient) {
+func RegisterAll(server *mcp.Server, client *k8s.ClusterClient, fluxClient *flux.FluxClient, opts Options) {
+	if len(opts.NamespaceAllowlist) > 0 {
+		server.AddReceivingMiddleware(namespaceAllowlistMiddleware(opts.NamespaceAllowlist))
+	}
+	if opts.Suppressions != nil {
+		server.AddReceivingMiddleware(suppressionMiddleware(opts.Suppressions))
+	}
+	// Added last so it runs outermost and its footer follows every other edit.
+	usage := k8s.NewAPIUsage()
+	server.AddReceivingMiddleware(apiAccountingMiddleware(usage))
 	registerClusterTools(server, client)
 	registerPodTools(server, client)
 	registerEventTools(server, client)
@@ -22,9 +72,76 @@ func RegisterAll(server *mcp.Server, client *k8s.ClusterClient, fluxClient *flux
 	registerSecurityTools(server, client)
 	registerResourceTools(server, client)
 	registerDiscoveryTools(server, client)
-	registerNetworkAnalysisTools(server, client)
+	registerNetworkAnalysisTools(server, client, opts)
+	registerAppGatewayTools(server, client, opts)
 	registerResourceAnalysisTools(server, client)
+	registerDNSAuditTools(server, client)
+	registerMetricsServerTools(server, client)
+	registerMetricsAPITools(server, client)
 	registerCompositeDiagnosticTools(server, client)
+	registerProbeTools(server, client)
+	registerIngressControllerTools(server, client)
+	registerNginxIngressTools(server, client)
+	registerCrashLoopTools(server, client)
+	registerCrashLoopClassifierTools(server, client)
+	registerLintTools(server, client)
+	registerRolloutTools(server, client)
+	registerConfigDriftTools(server, client)
+	registerAutoscalerTools(server, client)
+	registerCostTools(server, client, opts)
+	registerNodePoolTools(server, client)
+	registerStorageDiagnosticTools(server, client)
+	registerTriageTools(server, client)
+	registerJobTools(server, client)
+	registerNetpolSimTools(server, client)
+	registerRestartStormTools(server, client)
+	registerEventAnalysisTools(server, client)
+	registerPlacementTools(server, client, opts)
+	registerNetworkingComponentTools(server, client)
+	registerUsageHistoryTools(server, client)
+	registerRightSizingTools(server, client)
+	registerConfigMapTools(server, client)
+	registerSecretAuditTools(server, client)
+	registerServiceAccountTools(server, client)
+	registerValidateManifestTools(server, client)
+	registerDiffManifestTools(server, client)
+	registerDiskHogTools(server, client)
+	registerPodDebrisTools(server, client, opts)
+	registerAddonTools(server, client)
+	registerImageFreshnessTools(server, client, opts.Registry)
+	registerNamespaceReportTools(server, client)
+	registerProgressiveDeliveryTools(server, client)
+	registerCompareContextTools(server, client)
+	registerServiceMeshTools(server, client)
+	registerCustomResourceTools(server, client)
+	registerGitOpsTools(server, client, fluxClient)
+	registerHelmTools(server, client)
+	registerMCPResources(server, client)
+	registerPrompts(server)
+	registerLogTailTools(server, client)
+	registerTraceSearchTools(server, client)
+	registerNodeDiagnosisTools(server, client)
+	registerQuotaPressureTools(server, client)
+	registerTopologySpreadTools(server, client)
+	registerPodPlacementTools(server, client)
+	registerPriorityTools(server, client)
+	registerAKSHealthTools(server, client)
+	registerWatchEventsTools(server, client)
+	registerShutdownTools(server, client)
+	registerStuckResourceTools(server, client)
+	registerVersionSkewTools(server, client)
+	registerNamespacePreflightTools(server, client)
+	registerFleetTools(server, client)
+	registerServerStatsTools(server, usage)
+	registerFindingTools(server)
+	if opts.EnableExec {
+		registerExecTools(server, client)
+		registerHTTPProbeTools(server, client)
+	}
+	if opts.EnableWrite {
+		registerRemediationTools(server, client)
+		registerPodDebrisCleanupTools(server, client)
+	}
 	if fluxClient != nil {
 		registerFluxTools(server, fluxClient, client)
 	}
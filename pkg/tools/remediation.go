@@ -0,0 +1,493 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// scaleMaxReplicas caps the replica count scale_deployment will set.
+const scaleMaxReplicas = 100
+
+type restartDeploymentInput struct {
+	Namespace string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Name      string `json:"name" jsonschema:"required,Deployment name"`
+	DryRun    bool   `json:"dry_run,omitempty" jsonschema:"Validate the change with a server-side dry run without applying it"`
+}
+
+type deletePodInput struct {
+	Namespace          string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Name               string `json:"name" jsonschema:"required,Pod name"`
+	GracePeriodSeconds *int64 `json:"grace_period_seconds,omitempty" jsonschema:"Seconds the pod has to shut down (default: the pod's terminationGracePeriodSeconds; 0 for pods stuck Terminating)"`
+	DryRun             bool   `json:"dry_run,omitempty" jsonschema:"Validate the change with a server-side dry run without applying it"`
+}
+
+type cordonNodeInput struct {
+	Name   string `json:"name" jsonschema:"required,Node name"`
+	DryRun bool   `json:"dry_run,omitempty" jsonschema:"Validate the change with a server-side dry run without applying it"`
+}
+
+type scaleDeploymentInput struct {
+	Namespace string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Name      string `json:"name" jsonschema:"required,Deployment name"`
+	Replicas  *int32 `json:"replicas" jsonschema:"required,Desired replica count (0-100)"`
+	DryRun    bool   `json:"dry_run,omitempty" jsonschema:"Validate the change with a server-side dry run without applying it"`
+}
+
+func registerRemediationTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// restart_deployment
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "restart_deployment",
+		Description: "Trigger a rolling restart of a Deployment, exactly like `kubectl rollout restart`, by stamping the " +
+			"kubectl.kubernetes.io/restartedAt annotation on its pod template. Set dry_run=true to have the API server validate " +
+			"the change without applying it. Reports the exact patch sent. Only available when the server runs with --enable-write.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input restartDeploymentInput) (*mcp.CallToolResult, any, error) {
+		if input.Namespace == "" || input.Name == "" {
+			return util.ErrorResult("namespace and name are required"), nil, nil
+		}
+		deploy, err := client.GetDeployment(ctx, input.Namespace, input.Name)
+		if err != nil {
+			return util.HandleK8sError("getting deployment", err), nil, nil
+		}
+		if deploy.Spec.Paused {
+			return util.ErrorResult("deployment %s/%s is paused; resume it before restarting (kubectl -n %s rollout resume deployment/%s)",
+				input.Namespace, input.Name, input.Namespace, input.Name), nil, nil
+		}
+
+		patch := k8s.RestartPatch(time.Now().UTC())
+		if _, err := client.RestartDeployment(ctx, input.Namespace, input.Name, patch, input.DryRun); err != nil {
+			return util.HandleK8sError("restarting deployment", err), nil, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Rollout Restart: %s/%s%s", input.Namespace, input.Name, dryRunSuffix(input.DryRun))))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Result", writeResult(input.DryRun)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Patch", fmt.Sprintf("deployment/%s (strategic merge) %s", input.Name, patch)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Equivalent", fmt.Sprintf("kubectl -n %s rollout restart deployment/%s", input.Namespace, input.Name)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Rollout", restartRolloutSummary(deploy)))
+		sb.WriteString("\n")
+		if !input.DryRun {
+			sb.WriteString(fmt.Sprintf("\nFollow progress with get_deployment_detail or `kubectl -n %s rollout status deployment/%s`.\n", input.Namespace, input.Name))
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+
+	// delete_pod
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "delete_pod",
+		Description: "Delete a pod so its controller replaces it, e.g. to break a CrashLoopBackOff stuck on bad state or clear a pod stuck " +
+			"Terminating (grace_period_seconds=0). Refuses pods without a controller owner and Ready pods covered by a PodDisruptionBudget " +
+			"that allows no disruptions. Set dry_run=true to have the API server validate the delete without applying it. " +
+			"Only available when the server runs with --enable-write.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input deletePodInput) (*mcp.CallToolResult, any, error) {
+		if input.Namespace == "" || input.Name == "" {
+			return util.ErrorResult("namespace and name are required"), nil, nil
+		}
+		if input.GracePeriodSeconds != nil && *input.GracePeriodSeconds < 0 {
+			return util.ErrorResult("grace_period_seconds must be 0 or more"), nil, nil
+		}
+		pod, err := client.GetPod(ctx, input.Namespace, input.Name)
+		if err != nil {
+			return util.HandleK8sError("getting pod", err), nil, nil
+		}
+		pdbs, err := client.ListPodDisruptionBudgets(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pod disruption budgets", err), nil, nil
+		}
+		if blockers := podDeleteBlockers(pod, pdbs); len(blockers) > 0 {
+			return util.ErrorResult("refusing to delete %s/%s: %s", input.Namespace, input.Name, strings.Join(blockers, "; ")), nil, nil
+		}
+
+		if err := client.DeletePod(ctx, input.Namespace, input.Name, input.GracePeriodSeconds, input.DryRun); err != nil {
+			return util.HandleK8sError("deleting pod", err), nil, nil
+		}
+
+		grace := "pod default"
+		if pod.Spec.TerminationGracePeriodSeconds != nil {
+			grace = fmt.Sprintf("pod default (%ds)", *pod.Spec.TerminationGracePeriodSeconds)
+		}
+		equivalent := fmt.Sprintf("kubectl -n %s delete pod %s", input.Namespace, input.Name)
+		if input.GracePeriodSeconds != nil {
+			grace = fmt.Sprintf("%ds", *input.GracePeriodSeconds)
+			equivalent += fmt.Sprintf(" --grace-period=%d", *input.GracePeriodSeconds)
+			if *input.GracePeriodSeconds == 0 {
+				equivalent += " --force"
+			}
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Delete Pod: %s/%s%s", input.Namespace, input.Name, dryRunSuffix(input.DryRun))))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Result", writeResult(input.DryRun)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Change", fmt.Sprintf("delete pod/%s (grace period: %s)", input.Name, grace)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Equivalent", equivalent))
+		sb.WriteString("\n")
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			sb.WriteString(util.FormatKeyValue("Replaced by", fmt.Sprintf("%s/%s", owner.Kind, owner.Name)))
+			sb.WriteString("\n")
+		}
+		if pod.DeletionTimestamp != nil && len(pod.Finalizers) > 0 {
+			sb.WriteString(findings.Format("KD-SYS-013", "WARNING", fmt.Sprintf("Pod has finalizers %s; it stays Terminating until the controllers owning them finish or the finalizers are removed",
+				strings.Join(pod.Finalizers, ", "))))
+			sb.WriteString("\n")
+		}
+		if input.GracePeriodSeconds != nil && *input.GracePeriodSeconds == 0 && pod.Spec.NodeName != "" {
+			sb.WriteString(findings.Format("KD-SYS-014", "WARNING", fmt.Sprintf("Immediate deletion does not wait for the kubelet on %s to confirm the containers stopped; "+
+				"for StatefulSets this can briefly run two pods with the same identity", pod.Spec.NodeName)))
+			sb.WriteString("\n")
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+
+	// cordon_node / uncordon_node
+	for _, unschedulable := range []bool{true, false} {
+		verb, name, desc := "Cordon", "cordon_node", "Mark a node unschedulable so no new pods land on it (running pods are not evicted), like `kubectl cordon`."
+		if !unschedulable {
+			verb, name, desc = "Uncordon", "uncordon_node", "Mark a node schedulable again, like `kubectl uncordon`."
+		}
+		mcp.AddTool(server, &mcp.Tool{
+			Name: name,
+			Description: desc + " Prints an impact summary: pods on the node and the cluster's schedulable nodes, allocatable CPU/memory, " +
+				"and request headroom before and after, warning when the node is the last schedulable one in its pool or the rest of the " +
+				"cluster could not absorb its pods. Set dry_run=true to have the API server validate the change without applying it. " +
+				"Only available when the server runs with --enable-write.",
+		}, func(ctx context.Context, req *mcp.CallToolRequest, input cordonNodeInput) (*mcp.CallToolResult, any, error) {
+			if input.Name == "" {
+				return util.ErrorResult("name is required"), nil, nil
+			}
+			node, err := client.GetNode(ctx, input.Name)
+			if err != nil {
+				return util.HandleK8sError("getting node", err), nil, nil
+			}
+			nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+			if err != nil {
+				return util.HandleK8sError("listing nodes", err), nil, nil
+			}
+			nodePods, err := client.ListAllPods(ctx, "", metav1.ListOptions{FieldSelector: "spec.nodeName=" + input.Name})
+			if err != nil {
+				return util.HandleK8sError("listing pods on node", err), nil, nil
+			}
+			otherPods, err := client.ListAllPods(ctx, "", metav1.ListOptions{FieldSelector: "spec.nodeName!=" + input.Name})
+			if err != nil {
+				return util.HandleK8sError("listing pods", err), nil, nil
+			}
+
+			changed := node.Spec.Unschedulable != unschedulable
+			if changed {
+				if _, err := client.SetNodeUnschedulable(ctx, input.Name, unschedulable, input.DryRun); err != nil {
+					return util.HandleK8sError(strings.ToLower(verb)+"ing node", err), nil, nil
+				}
+			}
+
+			var sb strings.Builder
+			sb.WriteString(util.FormatHeader(fmt.Sprintf("%s Node: %s%s", verb, input.Name, dryRunSuffix(input.DryRun))))
+			sb.WriteString("\n")
+			if changed {
+				sb.WriteString(util.FormatKeyValue("Result", writeResult(input.DryRun)))
+			} else {
+				sb.WriteString(util.FormatKeyValue("Result", fmt.Sprintf("No change — spec.unschedulable is already %t", unschedulable)))
+			}
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatKeyValue("Change", fmt.Sprintf("node/%s spec.unschedulable: %t → %t", input.Name, node.Spec.Unschedulable, unschedulable)))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatKeyValue("Equivalent", fmt.Sprintf("kubectl %s %s", strings.ToLower(verb), input.Name)))
+			sb.WriteString("\n\n")
+
+			for _, line := range writeCordonImpact(&sb, node, cordonImpact(node, nodes, nodePods, otherPods, unschedulable), unschedulable) {
+				sb.WriteString(line)
+				sb.WriteString("\n")
+			}
+			return util.SuccessResult(sb.String()), nil, nil
+		})
+	}
+
+	// scale_deployment
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scale_deployment",
+		Description: fmt.Sprintf("Set a Deployment's replica count through its scale subresource, like `kubectl scale`. "+
+			"Replicas are capped at %d. Warns when an HPA manages the Deployment and will override the change. Set dry_run=true "+
+			"to have the API server validate the change without applying it. Reports the exact change made. "+
+			"Only available when the server runs with --enable-write.", scaleMaxReplicas),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input scaleDeploymentInput) (*mcp.CallToolResult, any, error) {
+		if input.Namespace == "" || input.Name == "" || input.Replicas == nil {
+			return util.ErrorResult("namespace, name, and replicas are required"), nil, nil
+		}
+		replicas := *input.Replicas
+		if replicas < 0 || replicas > scaleMaxReplicas {
+			return util.ErrorResult("replicas must be between 0 and %d", scaleMaxReplicas), nil, nil
+		}
+		deploy, err := client.GetDeployment(ctx, input.Namespace, input.Name)
+		if err != nil {
+			return util.HandleK8sError("getting deployment", err), nil, nil
+		}
+		current := int32(1)
+		if deploy.Spec.Replicas != nil {
+			current = *deploy.Spec.Replicas
+		}
+
+		var warnings []string
+		hpas, err := client.ListHPAs(ctx, input.Namespace, metav1.ListOptions{})
+		if err == nil {
+			for _, h := range hpas {
+				if h.Spec.ScaleTargetRef.Kind == "Deployment" && h.Spec.ScaleTargetRef.Name == input.Name {
+					minReplicas := int32(1)
+					if h.Spec.MinReplicas != nil {
+						minReplicas = *h.Spec.MinReplicas
+					}
+					warnings = append(warnings, findings.Format("KD-SYS-018", "WARNING", fmt.Sprintf("HPA %s manages this Deployment (min %d, max %d) and will override the replica count; change the HPA instead for a lasting change",
+						h.Name, minReplicas, h.Spec.MaxReplicas)))
+				}
+			}
+		}
+		if replicas == 0 {
+			warnings = append(warnings, findings.Format("KD-SYS-019", "WARNING", "Scaling to 0 stops every pod; the Deployment serves no traffic until scaled up again"))
+		}
+
+		if replicas != current {
+			if _, err := client.ScaleDeployment(ctx, input.Namespace, input.Name, replicas, input.DryRun); err != nil {
+				return util.HandleK8sError("scaling deployment", err), nil, nil
+			}
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Scale: %s/%s%s", input.Namespace, input.Name, dryRunSuffix(input.DryRun))))
+		sb.WriteString("\n")
+		if replicas == current {
+			sb.WriteString(util.FormatKeyValue("Result", fmt.Sprintf("No change — already at %d replicas", current)))
+		} else {
+			sb.WriteString(util.FormatKeyValue("Result", writeResult(input.DryRun)))
+		}
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Change", fmt.Sprintf("deployment/%s spec.replicas: %d → %d", input.Name, current, replicas)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Equivalent", fmt.Sprintf("kubectl -n %s scale deployment/%s --replicas=%d", input.Namespace, input.Name, replicas)))
+		sb.WriteString("\n")
+		for _, w := range warnings {
+			sb.WriteString(w)
+			sb.WriteString("\n")
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// schedulableCapacity sums allocatable resources and pod requests over Ready,
+// schedulable nodes, as analyze_node_capacity does per node.
+type schedulableCapacity struct {
+	Nodes    int
+	CPUAlloc int64
+	MemAlloc int64
+	CPUReq   int64
+	MemReq   int64
+}
+
+// nodeCordonImpact is what cordoning or uncordoning a node changes.
+type nodeCordonImpact struct {
+	Pods          int
+	DaemonSetPods int
+	BarePods      int
+	CPUReq        int64
+	MemReq        int64
+	Before        schedulableCapacity
+	After         schedulableCapacity
+	// PoolSchedulableAfter is the number of schedulable nodes left in the node's pool.
+	PoolSchedulableAfter int
+}
+
+// cordonImpact computes the pods on a node and the cluster's schedulable
+// capacity before and after setting the node's unschedulable flag. nodePods
+// are the pods on the node and otherPods those everywhere else.
+func cordonImpact(node *corev1.Node, nodes []corev1.Node, nodePods, otherPods []corev1.Pod, unschedulable bool) nodeCordonImpact {
+	cpuByNode := make(map[string]int64)
+	memByNode := make(map[string]int64)
+	var impact nodeCordonImpact
+	pods := append(append([]corev1.Pod{}, nodePods...), otherPods...)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		var cpu, mem int64
+		for _, c := range pod.Spec.Containers {
+			cpu += c.Resources.Requests.Cpu().MilliValue()
+			mem += c.Resources.Requests.Memory().Value()
+		}
+		cpuByNode[pod.Spec.NodeName] += cpu
+		memByNode[pod.Spec.NodeName] += mem
+		if i >= len(nodePods) {
+			continue
+		}
+		impact.Pods++
+		impact.CPUReq += cpu
+		impact.MemReq += mem
+		switch {
+		case isDaemonSetPod(pod):
+			impact.DaemonSetPods++
+		case metav1.GetControllerOf(pod) == nil:
+			impact.BarePods++
+		}
+	}
+
+	pool := nodePoolName(node)
+	for i := range nodes {
+		n := &nodes[i]
+		if nodeStatus(n) != "Ready" {
+			continue
+		}
+		add := func(c *schedulableCapacity) {
+			c.Nodes++
+			c.CPUAlloc += n.Status.Allocatable.Cpu().MilliValue()
+			c.MemAlloc += n.Status.Allocatable.Memory().Value()
+			c.CPUReq += cpuByNode[n.Name]
+			c.MemReq += memByNode[n.Name]
+		}
+		if !n.Spec.Unschedulable {
+			add(&impact.Before)
+		}
+		after := !n.Spec.Unschedulable
+		if n.Name == node.Name {
+			after = !unschedulable
+		}
+		if after {
+			add(&impact.After)
+			if nodePoolName(n) == pool {
+				impact.PoolSchedulableAfter++
+			}
+		}
+	}
+	return impact
+}
+
+// writeCordonImpact renders the impact of a cordon or uncordon and returns
+// the finding lines for the warnings it raises.
+func writeCordonImpact(sb *strings.Builder, node *corev1.Node, impact nodeCordonImpact, unschedulable bool) []string {
+	sb.WriteString(util.FormatSubHeader("Node Impact"))
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatKeyValue("Pool", nodePoolName(node)))
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatKeyValue("Pods on node", fmt.Sprintf("%d (%d DaemonSet, %d without a controller)", impact.Pods, impact.DaemonSetPods, impact.BarePods)))
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatKeyValue("Requests on node", fmt.Sprintf("%dm CPU, %s memory", impact.CPUReq, formatBytes(impact.MemReq))))
+	sb.WriteString("\n")
+	if unschedulable {
+		sb.WriteString("  Running pods stay where they are; cordoning only stops new pods from being scheduled here.\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(util.FormatSubHeader("Schedulable Capacity"))
+	sb.WriteString("\n")
+	row := func(label string, f func(c schedulableCapacity) string) []string {
+		return []string{label, f(impact.Before), f(impact.After)}
+	}
+	rows := [][]string{
+		row("Schedulable nodes", func(c schedulableCapacity) string { return fmt.Sprintf("%d", c.Nodes) }),
+		row("Allocatable CPU", func(c schedulableCapacity) string { return fmt.Sprintf("%dm", c.CPUAlloc) }),
+		row("Allocatable memory", func(c schedulableCapacity) string { return formatBytes(c.MemAlloc) }),
+		row("CPU headroom", func(c schedulableCapacity) string { return fmt.Sprintf("%dm", c.CPUAlloc-c.CPUReq) }),
+		row("Memory headroom", func(c schedulableCapacity) string { return formatBytes(c.MemAlloc - c.MemReq) }),
+	}
+	sb.WriteString(util.FormatTable([]string{"", "BEFORE", "AFTER"}, rows))
+
+	if !unschedulable {
+		return nil
+	}
+	var warnings []string
+	if impact.PoolSchedulableAfter == 0 {
+		warnings = append(warnings, findings.Format("KD-SYS-015", "WARNING", fmt.Sprintf("%s is the last schedulable node in pool %s; pods that require this pool will stay Pending", node.Name, nodePoolName(node))))
+	}
+	if impact.After.CPUAlloc-impact.After.CPUReq < impact.CPUReq || impact.After.MemAlloc-impact.After.MemReq < impact.MemReq {
+		warnings = append(warnings, findings.Format("KD-SYS-016", "WARNING", fmt.Sprintf("The remaining schedulable nodes lack headroom for this node's %dm CPU / %s memory of requests; draining it would leave pods Pending",
+			impact.CPUReq, formatBytes(impact.MemReq))))
+	}
+	if impact.BarePods > 0 {
+		warnings = append(warnings, findings.Format("KD-SYS-017", "WARNING", fmt.Sprintf("%d pods on this node have no controller and would not be recreated if the node is drained", impact.BarePods)))
+	}
+	return warnings
+}
+
+// podDeleteBlockers returns the reasons a pod must not be deleted: it has no
+// controller to replace it, or deleting it would break a PodDisruptionBudget
+// that allows no more disruptions. Pods already terminating and pods that are
+// not Ready do not count against a budget.
+func podDeleteBlockers(pod *corev1.Pod, pdbs []policyv1.PodDisruptionBudget) []string {
+	var blockers []string
+	if metav1.GetControllerOf(pod) == nil {
+		blockers = append(blockers, fmt.Sprintf("pod %s is not owned by a controller, so nothing would recreate it", pod.Name))
+	}
+	if pod.DeletionTimestamp != nil || !isPodReady(pod) {
+		return blockers
+	}
+	for _, pdb := range pdbs {
+		if !labelSelectorMatches(pdb.Spec.Selector, pod.Labels) {
+			continue
+		}
+		if pdb.Status.DisruptionsAllowed < 1 {
+			blockers = append(blockers, fmt.Sprintf("PodDisruptionBudget %s allows no disruptions (%d/%d healthy pods, %d desired)",
+				pdb.Name, pdb.Status.CurrentHealthy, pdb.Status.ExpectedPods, pdb.Status.DesiredHealthy))
+		}
+	}
+	return blockers
+}
+
+// isPodReady reports whether a pod's Ready condition is true.
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// dryRunSuffix marks report headers for dry runs.
+func dryRunSuffix(dryRun bool) string {
+	if dryRun {
+		return " (dry run)"
+	}
+	return ""
+}
+
+// writeResult describes the outcome of a write that the API server accepted.
+func writeResult(dryRun bool) string {
+	if dryRun {
+		return "Validated by the API server (dry run) — nothing was changed"
+	}
+	return "Applied"
+}
+
+// restartRolloutSummary describes how a restart will replace a Deployment's pods.
+func restartRolloutSummary(d *appsv1.Deployment) string {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	if d.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType {
+		return fmt.Sprintf("Recreate strategy — all %d pods stop before new ones start (brief outage)", replicas)
+	}
+	surge, unavailable := "25%", "25%"
+	if ru := d.Spec.Strategy.RollingUpdate; ru != nil {
+		if ru.MaxSurge != nil {
+			surge = ru.MaxSurge.String()
+		}
+		if ru.MaxUnavailable != nil {
+			unavailable = ru.MaxUnavailable.String()
+		}
+	}
+	return fmt.Sprintf("RollingUpdate of %d pods (maxSurge %s, maxUnavailable %s)", replicas, surge, unavailable)
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestRestartRolloutSummary(t *testing.T) {
+	replicas := int32(3)
+	d := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &replicas}}
+	if got := restartRolloutSummary(d); got != "RollingUpdate of 3 pods (maxSurge 25%, maxUnavailable 25%)" {
+		t.Errorf("unexpected default summary %q", got)
+	}
+
+	zero := intstr.FromInt32(0)
+	d.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{MaxUnavailable: &zero}
+	if got := restartRolloutSummary(d); !strings.Contains(got, "maxUnavailable 0") {
+		t.Errorf("expected explicit maxUnavailable, got %q", got)
+	}
+
+	d.Spec.Strategy.Type = appsv1.RecreateDeploymentStrategyType
+	if got := restartRolloutSummary(d); !strings.Contains(got, "brief outage") {
+		t.Errorf("expected Recreate outage warning, got %q", got)
+	}
+}
+
+func TestPodDeleteBlockers(t *testing.T) {
+	p := withReady(withOwner(testPod("", "web-abc", "", map[string]string{"app": "web"}), "ReplicaSet", "web-7d9f"), true)
+	pod := &p
+	pdb := policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-pdb"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0, CurrentHealthy: 2, DesiredHealthy: 2, ExpectedPods: 2},
+	}
+
+	if b := podDeleteBlockers(pod, []policyv1.PodDisruptionBudget{pdb}); len(b) != 1 || !strings.Contains(b[0], "web-pdb") {
+		t.Errorf("expected exhausted PDB to block a Ready pod, got %v", b)
+	}
+	pdb.Status.DisruptionsAllowed = 1
+	if b := podDeleteBlockers(pod, []policyv1.PodDisruptionBudget{pdb}); len(b) != 0 {
+		t.Errorf("expected delete to be allowed, got %v", b)
+	}
+
+	pdb.Status.DisruptionsAllowed = 0
+	pod.Status.Conditions[0].Status = corev1.ConditionFalse
+	if b := podDeleteBlockers(pod, []policyv1.PodDisruptionBudget{pdb}); len(b) != 0 {
+		t.Errorf("expected an unready pod to be deletable despite the PDB, got %v", b)
+	}
+
+	pod.OwnerReferences = nil
+	if b := podDeleteBlockers(pod, nil); len(b) != 1 || !strings.Contains(b[0], "not owned by a controller") {
+		t.Errorf("expected bare pod to be refused, got %v", b)
+	}
+}
+
+func TestCordonImpact(t *testing.T) {
+	node := func(name, pool string) corev1.Node {
+		return testNode(name, "2", "4Gi", map[string]string{"agentpool": pool})
+	}
+	nodes := []corev1.Node{node("n1", "user"), node("n2", "user"), node("s1", "system")}
+	pod := func(name, nodeName, cpu string) corev1.Pod {
+		return withRequests(testPod("", name, nodeName, nil), cpu, "")
+	}
+	onN1 := []corev1.Pod{pod("a", "n1", "1950m")}
+	elsewhere := []corev1.Pod{pod("b", "n2", "1"), pod("c", "s1", "100m")}
+
+	impact := cordonImpact(&nodes[0], nodes, onN1, elsewhere, true)
+	if impact.Pods != 1 || impact.BarePods != 1 || impact.CPUReq != 1950 {
+		t.Errorf("unexpected pods on node: %+v", impact)
+	}
+	if impact.Before.Nodes != 3 || impact.After.Nodes != 2 || impact.After.CPUAlloc != 4000 || impact.After.CPUReq != 1100 {
+		t.Errorf("unexpected capacity before/after: %+v / %+v", impact.Before, impact.After)
+	}
+	if impact.PoolSchedulableAfter != 1 {
+		t.Errorf("expected n2 to remain in the user pool, got %d", impact.PoolSchedulableAfter)
+	}
+
+	var sb strings.Builder
+	if w := writeCordonImpact(&sb, &nodes[0], impact, true); len(w) != 1 || !strings.Contains(w[0], "no controller") {
+		t.Errorf("expected only the bare-pod warning, got %v", w)
+	}
+
+	nodes[1].Spec.Unschedulable = true
+	impact = cordonImpact(&nodes[0], nodes, onN1, elsewhere, true)
+	sb.Reset()
+	if w := writeCordonImpact(&sb, &nodes[0], impact, true); len(w) != 3 || !strings.Contains(w[0], "last schedulable node in pool user") {
+		t.Errorf("expected last-in-pool and headroom warnings, got %v", w)
+	}
+
+	impact = cordonImpact(&nodes[1], nodes, elsewhere[:1], []corev1.Pod{elsewhere[1], onN1[0]}, false)
+	if impact.Before.Nodes != 2 || impact.After.Nodes != 3 {
+		t.Errorf("expected uncordon to add n2 back, got %+v / %+v", impact.Before, impact.After)
+	}
+}
@@ -0,0 +1,281 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// Gateway timeouts the controllers use when no annotation overrides them.
+const (
+	agicDefaultRequestTimeout  = 30
+	nginxDefaultProxyTimeout   = 60
+	nginxDefaultConnectTimeout = 5
+)
+
+// timeoutSetting is one timeout along the request path, in seconds.
+type timeoutSetting struct {
+	Seconds int
+	Source  string
+}
+
+func (s timeoutSetting) known() bool { return s.Seconds > 0 }
+
+// timeoutChain is every timeout a request passes through from the ingress
+// controller to the backend pod.
+type timeoutChain struct {
+	Gateway string // "AGIC", "nginx", or "" when the controller is unknown
+	// GatewayTimeout is how long the controller waits for a response
+	// before answering 504 itself.
+	GatewayTimeout timeoutSetting
+	ConnectTimeout timeoutSetting
+	// GatewayAffinity is the controller's cookie affinity, "" when off.
+	GatewayAffinity string
+	// AppTimeout is the longest request timeout found in the backend
+	// containers' env or args.
+	AppTimeout timeoutSetting
+	// ReadinessWindow is the longest time a hung pod stays in rotation:
+	// periodSeconds × failureThreshold of the slowest readiness probe.
+	ReadinessWindow timeoutSetting
+	// ProbeTimeout is the longest readiness probe timeoutSeconds.
+	ProbeTimeout timeoutSetting
+	Affinity     corev1.ServiceAffinity
+}
+
+// timeoutFinding is one mismatch in the timeout chain.
+type timeoutFinding struct {
+	severity string
+	message  string
+	action   string
+}
+
+// appTimeoutEnv matches env var names that usually hold a server-side
+// request timeout.
+var appTimeoutEnv = regexp.MustCompile(`(REQUEST|READ|WRITE|SERVER|HTTP|RESPONSE|HANDLER|GUNICORN|WORKER).*TIMEOUT|TIMEOUT.*(REQUEST|READ|WRITE|SERVER|HTTP|RESPONSE|HANDLER)`)
+
+// appTimeoutFlag matches command-line flags that set a request timeout,
+// e.g. gunicorn's --timeout 120 or --read-timeout=30s.
+var appTimeoutFlag = regexp.MustCompile(`^--?(timeout|request-timeout|read-timeout|write-timeout|server-timeout)(=(.+))?$`)
+
+// buildTimeoutChain gathers the timeouts on the path from ing to the pods
+// behind svc.
+func buildTimeoutChain(ing *networkingv1.Ingress, svc *corev1.Service, pods []corev1.Pod) timeoutChain {
+	chain := timeoutChain{Gateway: ingressGateway(ing), Affinity: svc.Spec.SessionAffinity}
+	switch chain.Gateway {
+	case "AGIC":
+		chain.GatewayTimeout = annotationSeconds(ing, k8s.AGICAnnotationPrefix+"request-timeout", agicDefaultRequestTimeout)
+		if ing.Annotations[k8s.AGICAnnotationPrefix+"cookie-based-affinity"] == "true" {
+			chain.GatewayAffinity = "cookie-based-affinity"
+		}
+	case "nginx":
+		chain.GatewayTimeout = annotationSeconds(ing, nginxAnnotationPrefix+"proxy-read-timeout", nginxDefaultProxyTimeout)
+		chain.ConnectTimeout = annotationSeconds(ing, nginxAnnotationPrefix+"proxy-connect-timeout", nginxDefaultConnectTimeout)
+		if ing.Annotations[nginxAnnotationPrefix+"affinity"] == "cookie" {
+			chain.GatewayAffinity = "affinity=cookie"
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range pods {
+		for _, c := range pods[i].Spec.Containers {
+			if seen[c.Name] {
+				continue
+			}
+			seen[c.Name] = true
+			if t := containerAppTimeout(c); t.Seconds > chain.AppTimeout.Seconds {
+				chain.AppTimeout = t
+			}
+			if c.ReadinessProbe == nil {
+				continue
+			}
+			period, timeout, failure := probeTimings(c.ReadinessProbe)
+			if window := int(period * failure); window > chain.ReadinessWindow.Seconds {
+				chain.ReadinessWindow = timeoutSetting{window, fmt.Sprintf("%s readinessProbe: %ds period × %d failures", c.Name, period, failure)}
+			}
+			if int(timeout) > chain.ProbeTimeout.Seconds {
+				chain.ProbeTimeout = timeoutSetting{int(timeout), fmt.Sprintf("%s readinessProbe timeoutSeconds", c.Name)}
+			}
+		}
+	}
+	return chain
+}
+
+// ingressGateway names the controller serving ing from its class and
+// annotations, "" when it is neither AGIC nor ingress-nginx.
+func ingressGateway(ing *networkingv1.Ingress) string {
+	class := ingressClassName(ing)
+	switch {
+	case class == "azure-application-gateway" || len(k8s.ParseAGICAnnotations(ing)) > 0:
+		return "AGIC"
+	case strings.Contains(class, "nginx"):
+		return "nginx"
+	}
+	for k := range ing.Annotations {
+		if strings.HasPrefix(k, nginxAnnotationPrefix) {
+			return "nginx"
+		}
+	}
+	return ""
+}
+
+// annotationSeconds reads an integer-seconds annotation, falling back to the
+// controller default when it is unset or invalid.
+func annotationSeconds(ing *networkingv1.Ingress, key string, def int) timeoutSetting {
+	name := key[strings.LastIndex(key, "/")+1:]
+	if v, ok := ing.Annotations[key]; ok {
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && n > 0 {
+			return timeoutSetting{n, "annotation " + name}
+		}
+	}
+	return timeoutSetting{def, "default " + name}
+}
+
+// containerAppTimeout returns the longest request timeout set on a
+// container through env vars or command-line flags.
+func containerAppTimeout(c corev1.Container) timeoutSetting {
+	var best timeoutSetting
+	for _, e := range c.Env {
+		name := strings.ToUpper(e.Name)
+		if e.Value == "" || !appTimeoutEnv.MatchString(name) {
+			continue
+		}
+		if n := parseTimeoutSeconds(e.Value, strings.HasSuffix(name, "_MS")); n > best.Seconds {
+			best = timeoutSetting{n, fmt.Sprintf("%s env %s", c.Name, e.Name)}
+		}
+	}
+	args := append(append([]string{}, c.Command...), c.Args...)
+	for i, arg := range args {
+		m := appTimeoutFlag.FindStringSubmatch(arg)
+		if m == nil {
+			continue
+		}
+		value := m[3]
+		if m[2] == "" && i+1 < len(args) {
+			value = args[i+1]
+		}
+		if n := parseTimeoutSeconds(value, false); n > best.Seconds {
+			best = timeoutSetting{n, fmt.Sprintf("%s arg --%s", c.Name, m[1])}
+		}
+	}
+	return best
+}
+
+// parseTimeoutSeconds parses "30", "30s", "2m", or milliseconds when ms is
+// set, rounding up to whole seconds. It returns 0 for anything else.
+func parseTimeoutSeconds(v string, ms bool) int {
+	v = strings.TrimSpace(v)
+	if n, err := strconv.Atoi(v); err == nil && n > 0 {
+		if ms {
+			return (n + 999) / 1000
+		}
+		return n
+	}
+	if d, err := time.ParseDuration(v); err == nil && d > 0 {
+		return int((d + time.Second - 1) / time.Second)
+	}
+	return 0
+}
+
+// effective is the timeout the client actually experiences: the shortest
+// known timeout in the chain.
+func (c timeoutChain) effective() (timeoutSetting, string) {
+	switch {
+	case c.GatewayTimeout.known() && (!c.AppTimeout.known() || c.GatewayTimeout.Seconds <= c.AppTimeout.Seconds):
+		return c.GatewayTimeout, "gateway"
+	case c.AppTimeout.known():
+		return c.AppTimeout, "app"
+	}
+	return timeoutSetting{}, ""
+}
+
+// findings flags timeout combinations that show up as intermittent 504s.
+func (c timeoutChain) findings() []timeoutFinding {
+	var out []timeoutFinding
+	gw := c.GatewayTimeout
+	if gw.known() && c.AppTimeout.known() && c.AppTimeout.Seconds > gw.Seconds {
+		out = append(out, timeoutFinding{"WARNING",
+			fmt.Sprintf("App timeout %ds (%s) is longer than the %s gateway timeout %ds — slow requests get a 504 from the gateway while the app is still working", c.AppTimeout.Seconds, c.AppTimeout.Source, c.Gateway, gw.Seconds),
+			fmt.Sprintf("Raise the %s timeout to at least %ds or lower the app timeout below %ds", c.Gateway, c.AppTimeout.Seconds, gw.Seconds)})
+	}
+	if gw.known() && c.ProbeTimeout.known() && c.ProbeTimeout.Seconds >= gw.Seconds {
+		out = append(out, timeoutFinding{"WARNING",
+			fmt.Sprintf("Readiness probe timeout %ds (%s) is not shorter than the %ds gateway timeout — a pod too slow to answer the gateway still passes readiness and keeps getting traffic", c.ProbeTimeout.Seconds, c.ProbeTimeout.Source, gw.Seconds),
+			fmt.Sprintf("Lower readinessProbe timeoutSeconds below %ds so slow pods leave the endpoints", gw.Seconds)})
+	}
+	if gw.known() && c.ReadinessWindow.known() && c.ReadinessWindow.Seconds > gw.Seconds {
+		out = append(out, timeoutFinding{"WARNING",
+			fmt.Sprintf("A hung pod stays in rotation for up to %ds (%s), longer than the %ds gateway timeout — requests routed to it in that window end in 504s", c.ReadinessWindow.Seconds, c.ReadinessWindow.Source, gw.Seconds),
+			fmt.Sprintf("Tighten readinessProbe periodSeconds/failureThreshold so a hung pod is removed within %ds", gw.Seconds)})
+	}
+	if c.Affinity == corev1.ServiceAffinityClientIP && c.Gateway != "" && c.GatewayAffinity == "" {
+		out = append(out, timeoutFinding{"INFO",
+			fmt.Sprintf("Service sessionAffinity is ClientIP, but %s sends traffic straight to pod IPs and ignores it — requests are not sticky", c.Gateway),
+			fmt.Sprintf("Enable cookie affinity on the %s Ingress if the app needs sticky sessions", c.Gateway)})
+	}
+	return out
+}
+
+// writeRequestPathTimeouts appends the TIMEOUT CHAIN section of
+// diagnose_request_path and returns its finding count and actions.
+func writeRequestPathTimeouts(sb *strings.Builder, ing *networkingv1.Ingress, svc *corev1.Service, pods []corev1.Pod) (int, []string) {
+	chain := buildTimeoutChain(ing, svc, pods)
+	sb.WriteString("\n[TIMEOUT CHAIN]\n")
+
+	gateway := chain.Gateway
+	if gateway == "" {
+		gateway = fmt.Sprintf("ingress class %s", ingressClassName(ing))
+	}
+	setting := func(s timeoutSetting) (string, string) {
+		if !s.known() {
+			return "unknown", "not set"
+		}
+		return fmt.Sprintf("%ds", s.Seconds), s.Source
+	}
+	var rows [][]string
+	addRow := func(layer, name string, s timeoutSetting) {
+		value, source := setting(s)
+		rows = append(rows, []string{layer, name, value, source})
+	}
+	addRow(gateway, "response timeout", chain.GatewayTimeout)
+	if chain.ConnectTimeout.known() {
+		addRow(gateway, "connect timeout", chain.ConnectTimeout)
+	}
+	affinity := string(chain.Affinity)
+	if affinity == "" {
+		affinity = string(corev1.ServiceAffinityNone)
+	}
+	stickiness := "no cookie affinity at the gateway"
+	if chain.GatewayAffinity != "" {
+		stickiness = "gateway " + chain.GatewayAffinity
+	}
+	rows = append(rows, []string{"Service " + svc.Name, "sessionAffinity", affinity, stickiness})
+	addRow("App", "request timeout", chain.AppTimeout)
+	addRow("Readiness", "hung pod removed after", chain.ReadinessWindow)
+	addRow("Readiness", "probe timeout", chain.ProbeTimeout)
+	sb.WriteString("    ")
+	sb.WriteString(strings.ReplaceAll(util.FormatTable([]string{"LAYER", "SETTING", "VALUE", "SOURCE"}, rows), "\n", "\n    "))
+	sb.WriteString("\n")
+
+	if eff, layer := chain.effective(); eff.known() {
+		sb.WriteString(fmt.Sprintf("    Effective timeout: %ds (set by the %s)\n", eff.Seconds, layer))
+	} else {
+		sb.WriteString("    Effective timeout: unknown (controller not recognised and no app timeout found)\n")
+	}
+
+	var actions []string
+	found := chain.findings()
+	for _, f := range found {
+		sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-NET-008", f.severity, f.message)))
+		actions = append(actions, f.action)
+	}
+	return len(found), actions
+}
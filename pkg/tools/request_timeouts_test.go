@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func timeoutChainPod(env []corev1.EnvVar, args []string, probe *corev1.Probe) corev1.Pod {
+	pod := testPod("shop", "api", "", nil)
+	c := &pod.Spec.Containers[0]
+	c.Env, c.Args, c.ReadinessProbe = env, args, probe
+	return pod
+}
+
+func TestBuildTimeoutChainAGIC(t *testing.T) {
+	ing := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		"appgw.ingress.kubernetes.io/backend-protocol": "http",
+	}}}
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "api"}, Spec: corev1.ServiceSpec{SessionAffinity: corev1.ServiceAffinityClientIP}}
+	pod := timeoutChainPod(
+		[]corev1.EnvVar{{Name: "HTTP_REQUEST_TIMEOUT_MS", Value: "90000"}, {Name: "DB_TIMEOUT", Value: "600"}},
+		nil,
+		&corev1.Probe{PeriodSeconds: 15, FailureThreshold: 3, TimeoutSeconds: 30},
+	)
+
+	chain := buildTimeoutChain(ing, svc, []corev1.Pod{pod})
+	if chain.Gateway != "AGIC" || chain.GatewayTimeout.Seconds != 30 || !strings.HasPrefix(chain.GatewayTimeout.Source, "default") {
+		t.Errorf("gateway = %q %+v, want AGIC default 30s", chain.Gateway, chain.GatewayTimeout)
+	}
+	if chain.AppTimeout.Seconds != 90 || !strings.Contains(chain.AppTimeout.Source, "HTTP_REQUEST_TIMEOUT_MS") {
+		t.Errorf("app timeout = %+v, want 90s from the request timeout env (not DB_TIMEOUT)", chain.AppTimeout)
+	}
+	if chain.ReadinessWindow.Seconds != 45 || chain.ProbeTimeout.Seconds != 30 {
+		t.Errorf("readiness = %+v / %+v", chain.ReadinessWindow, chain.ProbeTimeout)
+	}
+	if eff, layer := chain.effective(); eff.Seconds != 30 || layer != "gateway" {
+		t.Errorf("effective = %+v from %s", eff, layer)
+	}
+
+	var msgs []string
+	for _, f := range chain.findings() {
+		msgs = append(msgs, f.severity+" "+f.message)
+	}
+	got := strings.Join(msgs, "\n")
+	for _, want := range []string{"App timeout 90s", "Readiness probe timeout 30s", "stays in rotation for up to 45s", "INFO Service sessionAffinity is ClientIP"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("findings missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestBuildTimeoutChainNginx(t *testing.T) {
+	class := "nginx"
+	ing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			"nginx.ingress.kubernetes.io/proxy-read-timeout": "120",
+			"nginx.ingress.kubernetes.io/affinity":           "cookie",
+		}},
+		Spec: networkingv1.IngressSpec{IngressClassName: &class},
+	}
+	svc := &corev1.Service{Spec: corev1.ServiceSpec{SessionAffinity: corev1.ServiceAffinityClientIP}}
+	pod := timeoutChainPod(nil, []string{"app:wsgi", "--timeout", "100"}, &corev1.Probe{})
+
+	chain := buildTimeoutChain(ing, svc, []corev1.Pod{pod})
+	if chain.GatewayTimeout.Seconds != 120 || chain.ConnectTimeout.Seconds != 5 || chain.AppTimeout.Seconds != 100 {
+		t.Errorf("chain = %+v", chain)
+	}
+	if f := chain.findings(); len(f) != 0 {
+		t.Errorf("expected a consistent chain, got %+v", f)
+	}
+}
+
+func TestParseTimeoutSeconds(t *testing.T) {
+	tests := []struct {
+		value string
+		ms    bool
+		want  int
+	}{
+		{"30", false, 30},
+		{"1500", true, 2},
+		{"2m", false, 120},
+		{"250ms", false, 1},
+		{"soon", false, 0},
+	}
+	for _, tt := range tests {
+		if got := parseTimeoutSeconds(tt.value, tt.ms); got != tt.want {
+			t.Errorf("parseTimeoutSeconds(%q, %v) = %d, want %d", tt.value, tt.ms, got, tt.want)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/mermaid"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
@@ -20,17 +21,31 @@ import (
 // --- Input structs ---
 
 type analyzeResourceUsageInput struct {
-	Namespace string `json:"namespace" jsonschema:"required,Kubernetes namespace to analyze resource usage in"`
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace to analyze resource usage in"`
+	PerContainer   bool   `json:"per_container,omitempty" jsonschema:"Add a per-container breakdown (app, sidecar, native sidecar, and init containers) showing which container is hot or missing limits"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
 }
 
-type analyzeNodeCapacityInput struct{}
+type analyzeNodeCapacityInput struct {
+	PlanWorkload    string `json:"plan_workload,omitempty" jsonschema:"Deployment or StatefulSet to simulate adding replicas of (requires plan_namespace and plan_add_replicas)"`
+	PlanKind        string `json:"plan_kind,omitempty" jsonschema:"Kind of plan_workload: Deployment (default) or StatefulSet"`
+	PlanNamespace   string `json:"plan_namespace,omitempty" jsonschema:"Namespace of plan_workload"`
+	PlanAddReplicas int    `json:"plan_add_replicas,omitempty" jsonschema:"Number of replicas of plan_workload to add in the simulation"`
+	TimeoutSeconds  int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// efficiencyStages is the number of progress steps analyze_resource_efficiency reports.
+const efficiencyStages = 5
 
 type analyzeResourceEfficiencyInput struct {
-	Namespace string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for cluster-wide analysis)"`
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for cluster-wide analysis)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
 }
 
 type analyzeNetworkPoliciesInput struct {
-	Namespace string `json:"namespace" jsonschema:"required,Kubernetes namespace to analyze network policies in"`
+	Namespace       string `json:"namespace" jsonschema:"required,Kubernetes namespace to analyze network policies in"`
+	MaxDiagramNodes int    `json:"max_diagram_nodes,omitempty" jsonschema:"Maximum nodes in the policy diagram before the rest are collapsed into '+N more' nodes (default 80)"`
+	TimeoutSeconds  int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
 }
 
 type checkDNSHealthInput struct{}
@@ -44,8 +59,10 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		Description: "Analyze actual CPU/memory usage vs requests and limits for every pod in a namespace. " +
 			"Categories: CRITICAL (>90% of limit), WARNING (>70%), OVERPROVISIONED (<30% of request), " +
 			"MISSING LIMITS. Includes namespace totals and a Mermaid xychart of top pods by CPU usage % of limit. " +
-			"Requires metrics-server.",
+			"Pod totals include native sidecars (restartPolicy: Always init containers). Set per_container=true for a " +
+			"per-container breakdown. Requires metrics-server, or uses 7-day p95 usage when Prometheus is configured.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeResourceUsageInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
 		ns := input.Namespace
 
 		// Get pods
@@ -75,12 +92,22 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 			}
 		}
 
+		// Prefer p95 usage history over the single metrics-server sample
+		history, historyErr := podUsageHistory(ctx, client, ns)
+		for _, u := range history {
+			metricsMap[u.Pod] = metricsData{cpuMillis: u.CPUMillis, memBytes: u.MemoryBytes}
+		}
+		if len(history) > 0 {
+			metricsAvailable = true
+		}
+
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Resource Usage Analysis (namespace: %s)", ns)))
 		sb.WriteString("\n\n")
+		sb.WriteString(usageSourceNote(history, historyErr))
 
 		if !metricsAvailable {
-			sb.WriteString(util.FormatFinding("WARNING", "Metrics server not available or returned no data. Usage data will be unavailable."))
+			sb.WriteString(findings.Format("KD-SYS-003", "WARNING", "Metrics server not available or returned no data. Usage data will be unavailable."))
 			sb.WriteString("\n\n")
 		}
 
@@ -107,6 +134,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		var nsTotalCPUReq, nsTotalCPULim, nsTotalMemReq, nsTotalMemLim int64
 		var nsTotalCPUUsage, nsTotalMemUsage int64
 		criticalCount, warningCount, overprovisionedCount, missingLimitsCount := 0, 0, 0, 0
+		nativeSidecars := 0
 
 		for _, pod := range pods {
 			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
@@ -115,8 +143,14 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 
 			pa := podAnalysis{name: pod.Name}
 
-			// Sum requests/limits across all containers
-			for _, c := range pod.Spec.Containers {
+			// Sum requests/limits across all containers, including native sidecars
+			for _, c := range podContainers(&pod) {
+				if !c.runsWithPod() {
+					continue
+				}
+				if c.Role == containerRoleNativeSidecar {
+					nativeSidecars++
+				}
 				if c.Resources.Requests != nil {
 					pa.cpuRequest += c.Resources.Requests.Cpu().MilliValue()
 					pa.memRequest += c.Resources.Requests.Memory().Value()
@@ -190,6 +224,9 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		sb.WriteString(fmt.Sprintf("  WARNING (>70%% of limit):  %d\n", warningCount))
 		sb.WriteString(fmt.Sprintf("  OVERPROVISIONED (<30%% of request): %d\n", overprovisionedCount))
 		sb.WriteString(fmt.Sprintf("  MISSING LIMITS/REQUESTS: %d\n", missingLimitsCount))
+		if nativeSidecars > 0 {
+			sb.WriteString(fmt.Sprintf("  Native sidecars (restartPolicy: Always init containers): %d — included in pod totals\n", nativeSidecars))
+		}
 
 		// Namespace totals
 		sb.WriteString(fmt.Sprintf("\n  Namespace Totals:\n"))
@@ -226,40 +263,82 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		}
 		sb.WriteString(util.FormatTable(headers, rows))
 
+		// Per-container breakdown: app and sidecar containers, native sidecars, and init containers
+		var containerFindings []containerFinding
+		if input.PerContainer {
+			usageByPod := containerUsageByPod(podMetrics)
+			tables := map[string][][]string{}
+			for i := range pods {
+				pod := &pods[i]
+				if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+					continue
+				}
+				for _, c := range podContainers(pod) {
+					usage, hasUsage := usageByPod[pod.Name][c.Name]
+					row, cf := containerResourceRow(pod.Name, c, usage, hasUsage)
+					section := c.Role
+					if section == containerRoleSidecar {
+						section = containerRoleApp
+					}
+					tables[section] = append(tables[section], row)
+					containerFindings = append(containerFindings, cf...)
+				}
+			}
+			containerHeaders := []string{"POD", "CONTAINER", "ROLE", "CPU USE/REQ/LIM", "MEM USE/REQ/LIM"}
+			for _, section := range []struct{ key, title string }{
+				{containerRoleApp, "Container Resource Details (app and sidecar containers)"},
+				{containerRoleNativeSidecar, "Native Sidecars (init containers with restartPolicy: Always)"},
+				{containerRoleInit, "Init Containers (run to completion before the app starts)"},
+			} {
+				if len(tables[section.key]) == 0 {
+					continue
+				}
+				sb.WriteString("\n")
+				sb.WriteString(util.FormatSubHeader(section.title))
+				sb.WriteString("\n")
+				sb.WriteString(util.FormatTable(containerHeaders, tables[section.key]))
+			}
+		}
+
 		// Findings
 		sb.WriteString("\nFINDINGS:\n")
 		findingsCount := 0
+		for _, cf := range containerFindings {
+			sb.WriteString(findings.Format(cf.rule, cf.severity, cf.message))
+			sb.WriteString("\n")
+			findingsCount++
+		}
 
 		for _, pa := range analyses {
 			switch pa.category {
 			case "CRITICAL":
 				if pa.cpuPctOfLimit > 90 {
-					sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Pod '%s' CPU usage at %.1f%% of limit (%dm/%dm)", pa.name, pa.cpuPctOfLimit, pa.cpuUsage, pa.cpuLimit)))
+					sb.WriteString(findings.Format("KD-RES-006", "CRITICAL", fmt.Sprintf("Pod '%s' CPU usage at %.1f%% of limit (%dm/%dm)", pa.name, pa.cpuPctOfLimit, pa.cpuUsage, pa.cpuLimit)))
 					sb.WriteString("\n")
 					findingsCount++
 				}
 				if pa.memPctOfLimit > 90 {
-					sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Pod '%s' memory usage at %.1f%% of limit (%s/%s) - OOM risk", pa.name, pa.memPctOfLimit, formatBytes(pa.memUsage), formatBytes(pa.memLimit))))
+					sb.WriteString(findings.Format("KD-RES-006", "CRITICAL", fmt.Sprintf("Pod '%s' memory usage at %.1f%% of limit (%s/%s) - OOM risk", pa.name, pa.memPctOfLimit, formatBytes(pa.memUsage), formatBytes(pa.memLimit))))
 					sb.WriteString("\n")
 					findingsCount++
 				}
 			case "WARNING":
 				if pa.cpuPctOfLimit > 70 {
-					sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Pod '%s' CPU usage at %.1f%% of limit", pa.name, pa.cpuPctOfLimit)))
+					sb.WriteString(findings.Format("KD-RES-006", "WARNING", fmt.Sprintf("Pod '%s' CPU usage at %.1f%% of limit", pa.name, pa.cpuPctOfLimit)))
 					sb.WriteString("\n")
 					findingsCount++
 				}
 				if pa.memPctOfLimit > 70 {
-					sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Pod '%s' memory usage at %.1f%% of limit", pa.name, pa.memPctOfLimit)))
+					sb.WriteString(findings.Format("KD-RES-006", "WARNING", fmt.Sprintf("Pod '%s' memory usage at %.1f%% of limit", pa.name, pa.memPctOfLimit)))
 					sb.WriteString("\n")
 					findingsCount++
 				}
 			case "OVERPROVISIONED":
-				sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("Pod '%s' is overprovisioned: CPU %.1f%% of request, memory %.1f%% of request — consider reducing requests", pa.name, pa.cpuPctOfReq, pa.memPctOfReq)))
+				sb.WriteString(findings.Format("KD-RES-004", "INFO", fmt.Sprintf("Pod '%s' is overprovisioned: CPU %.1f%% of request, memory %.1f%% of request — consider reducing requests", pa.name, pa.cpuPctOfReq, pa.memPctOfReq)))
 				sb.WriteString("\n")
 				findingsCount++
 			case "MISSING LIMITS":
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Pod '%s' is missing resource limits/requests", pa.name)))
+				sb.WriteString(findings.Format("KD-RES-002", "WARNING", fmt.Sprintf("Pod '%s' is missing resource limits/requests", pa.name)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
@@ -323,8 +402,12 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		Description: "Analyze capacity, allocatable resources, actual usage (from metrics), and pod request sums for every node. " +
 			"Calculates allocatable utilization, actual utilization, and scheduling headroom. " +
 			"Checks node conditions. Includes a Mermaid xychart of per-node CPU utilization. " +
+			"Reports ephemeral-storage allocatable, requests, and limits per node, with node and image filesystem use from the kubelet stats summary (needs get on nodes/proxy). " +
+			"Pass plan_workload, plan_namespace, and plan_add_replicas to simulate scheduling extra replicas against current requests: " +
+			"whether they fit, which nodes would take them, and the headroom left. " +
 			"Requires metrics-server for actual usage data.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeNodeCapacityInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
 		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
 		if err != nil {
 			return util.HandleK8sError("listing nodes", err), nil, nil
@@ -386,7 +469,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		sb.WriteString("\n\n")
 
 		if !metricsAvailable {
-			sb.WriteString(util.FormatFinding("WARNING", "Metrics server not available. Actual usage data will be unavailable."))
+			sb.WriteString(findings.Format("KD-SYS-003", "WARNING", "Metrics server not available. Actual usage data will be unavailable."))
 			sb.WriteString("\n\n")
 		}
 
@@ -522,56 +605,84 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		}
 		sb.WriteString(util.FormatTable(headroomHeaders, headroomRows))
 
+		// Ephemeral storage: requests from pod specs, filesystem use from the kubelet
+		statsNodes := make([]string, 0, min(len(nodes), diskStatsMaxNodes))
+		for _, node := range nodes[:min(len(nodes), diskStatsMaxNodes)] {
+			statsNodes = append(statsNodes, node.Name)
+		}
+		nodeStats, statsErr := fetchNodeStats(ctx, client, statsNodes)
+		diskFindings := writeEphemeralStorage(&sb, nodeDisks(nodes, allPods, nodeStats), statsErr)
+
+		// Capacity plan
+		var planFinding string
+		if input.PlanWorkload != "" {
+			var errResult *mcp.CallToolResult
+			planFinding, errResult = writeCapacityPlan(ctx, &sb, client, input, nodes, allPods)
+			if errResult != nil {
+				return errResult, nil, nil
+			}
+		}
+
 		// Findings
 		sb.WriteString("\nFINDINGS:\n")
 		findingsCount := 0
+		if planFinding != "" {
+			sb.WriteString(planFinding)
+			sb.WriteString("\n")
+			findingsCount++
+		}
+		for _, f := range diskFindings {
+			sb.WriteString(f)
+			sb.WriteString("\n")
+			findingsCount++
+		}
 		for _, na := range nodeAnalyses {
 			for _, issue := range na.conditionIssues {
 				if issue == "NotReady" {
-					sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Node '%s' is NotReady", na.name)))
+					sb.WriteString(findings.Format("KD-NODE-001", "CRITICAL", fmt.Sprintf("Node '%s' is NotReady", na.name)))
 					sb.WriteString("\n")
 					findingsCount++
 				} else {
-					sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Node '%s' has %s", na.name, issue)))
+					sb.WriteString(findings.Format("KD-NODE-002", "WARNING", fmt.Sprintf("Node '%s' has %s", na.name, issue)))
 					sb.WriteString("\n")
 					findingsCount++
 				}
 			}
 			if na.requestUtilCPU > 90 {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Node '%s' CPU requests at %.1f%% of allocatable — scheduling may fail", na.name, na.requestUtilCPU)))
+				sb.WriteString(findings.Format("KD-NODE-005", "CRITICAL", fmt.Sprintf("Node '%s' CPU requests at %.1f%% of allocatable — scheduling may fail", na.name, na.requestUtilCPU)))
 				sb.WriteString("\n")
 				findingsCount++
 			} else if na.requestUtilCPU > 80 {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Node '%s' CPU requests at %.1f%% of allocatable", na.name, na.requestUtilCPU)))
+				sb.WriteString(findings.Format("KD-NODE-005", "WARNING", fmt.Sprintf("Node '%s' CPU requests at %.1f%% of allocatable", na.name, na.requestUtilCPU)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
 			if na.requestUtilMem > 90 {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Node '%s' memory requests at %.1f%% of allocatable — scheduling may fail", na.name, na.requestUtilMem)))
+				sb.WriteString(findings.Format("KD-NODE-005", "CRITICAL", fmt.Sprintf("Node '%s' memory requests at %.1f%% of allocatable — scheduling may fail", na.name, na.requestUtilMem)))
 				sb.WriteString("\n")
 				findingsCount++
 			} else if na.requestUtilMem > 80 {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Node '%s' memory requests at %.1f%% of allocatable", na.name, na.requestUtilMem)))
+				sb.WriteString(findings.Format("KD-NODE-005", "WARNING", fmt.Sprintf("Node '%s' memory requests at %.1f%% of allocatable", na.name, na.requestUtilMem)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
 			if na.hasMetrics && na.allocUtilCPU > 90 {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Node '%s' actual CPU utilization at %.1f%%", na.name, na.allocUtilCPU)))
+				sb.WriteString(findings.Format("KD-NODE-005", "CRITICAL", fmt.Sprintf("Node '%s' actual CPU utilization at %.1f%%", na.name, na.allocUtilCPU)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
 			if na.hasMetrics && na.allocUtilMem > 90 {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Node '%s' actual memory utilization at %.1f%%", na.name, na.allocUtilMem)))
+				sb.WriteString(findings.Format("KD-NODE-005", "CRITICAL", fmt.Sprintf("Node '%s' actual memory utilization at %.1f%%", na.name, na.allocUtilMem)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
 			if na.headroomCPU < 0 {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Node '%s' is overcommitted on CPU by %dm", na.name, -na.headroomCPU)))
+				sb.WriteString(findings.Format("KD-NODE-005", "WARNING", fmt.Sprintf("Node '%s' is overcommitted on CPU by %dm", na.name, -na.headroomCPU)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
 			if na.headroomMem < 0 {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Node '%s' is overcommitted on memory by %s", na.name, formatBytes(-na.headroomMem))))
+				sb.WriteString(findings.Format("KD-NODE-005", "WARNING", fmt.Sprintf("Node '%s' is overcommitted on memory by %s", na.name, formatBytes(-na.headroomMem))))
 				sb.WriteString("\n")
 				findingsCount++
 			}
@@ -617,20 +728,25 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 	mcp.AddTool(server, &mcp.Tool{
 		Name: "analyze_resource_efficiency",
 		Description: "Analyze resource efficiency cluster-wide or per namespace. Calculates waste (requests - actual usage), " +
-			"bin packing efficiency per node, identifies right-sizing opportunities, and flags pods with no requests/limits. " +
-			"Requires metrics-server for waste calculations.",
+			"bin packing efficiency per node, fragmentation (the largest pod each node can still take and how much free capacity is stranded " +
+			"in slivers too small for typical pods), identifies right-sizing opportunities, and flags pods with no requests/limits. " +
+			"Requires metrics-server for waste calculations, or uses 7-day p95 usage when Prometheus is configured.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeResourceEfficiencyInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
 		ns := util.NamespaceOrAll(input.Namespace)
 		scope := displayNS(input.Namespace)
+		progress := util.NewProgress(req)
 
 		pods, err := client.ListPods(ctx, ns, metav1.ListOptions{})
 		if err != nil {
 			return util.HandleK8sError("listing pods", err), nil, nil
 		}
+		progress.Report(ctx, 1, efficiencyStages, fmt.Sprintf("Listed %d pods", len(pods)))
 
 		// Get metrics
 		podMetrics, err := client.GetPodMetrics(ctx, ns, metav1.ListOptions{})
 		metricsAvailable := err == nil && len(podMetrics) > 0
+		progress.Report(ctx, 2, efficiencyStages, "Collected pod metrics")
 
 		// Build metrics lookup
 		type metricsData struct {
@@ -650,12 +766,26 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 			}
 		}
 
+		// Prefer p95 usage history over the single metrics-server sample
+		history, historyErr := podUsageHistory(ctx, client, ns)
+		for _, u := range history {
+			metricsMap[u.Namespace+"/"+u.Pod] = metricsData{cpuMillis: u.CPUMillis, memBytes: u.MemoryBytes}
+		}
+		if len(history) > 0 {
+			metricsAvailable = true
+		}
+		if ctx.Err() != nil {
+			return util.HandleK8sError("collecting usage history", ctx.Err()), nil, nil
+		}
+		progress.Report(ctx, 3, efficiencyStages, "Collected usage history")
+
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Resource Efficiency Report (scope: %s)", scope)))
 		sb.WriteString("\n\n")
+		sb.WriteString(usageSourceNote(history, historyErr))
 
 		if !metricsAvailable {
-			sb.WriteString(util.FormatFinding("WARNING", "Metrics server not available. Waste calculations require metrics data."))
+			sb.WriteString(findings.Format("KD-SYS-003", "WARNING", "Metrics server not available. Waste calculations require metrics data."))
 			sb.WriteString("\n\n")
 		}
 
@@ -775,6 +905,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		}
 
 		// Bin packing efficiency per node
+		var fragFinding, fragRecommendation string
 		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
 		if err == nil && len(nodes) > 0 {
 			sb.WriteString("\n")
@@ -818,7 +949,8 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 
 			binHeaders := []string{"NODE", "PODS", "CPU PACKING", "MEMORY PACKING"}
 			binRows := make([][]string, 0, len(nodes))
-			for _, n := range nodes {
+			for i, n := range nodes {
+				progress.Report(ctx, 3+float64(i)/float64(len(nodes)), efficiencyStages, fmt.Sprintf("Nodes analyzed %d/%d", i, len(nodes)))
 				nu := nodeMap[n.Name]
 				cpuPacking := float64(0)
 				memPacking := float64(0)
@@ -836,7 +968,15 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 				})
 			}
 			sb.WriteString(util.FormatTable(binHeaders, binRows))
+
+			// Fragmentation needs every pod on the nodes, not one namespace's
+			if input.Namespace == "" {
+				fragFinding, fragRecommendation = writeFragmentation(&sb, analyzeFragmentation(nodes, pods))
+			} else {
+				sb.WriteString("  Run without a namespace for the fragmentation analysis (largest pod that fits per node, stranded capacity).\n")
+			}
 		}
+		progress.Report(ctx, 4, efficiencyStages, fmt.Sprintf("Nodes analyzed %d/%d", len(nodes), len(nodes)))
 
 		// Right-sizing opportunities: pods where usage < 30% of requests
 		if metricsAvailable {
@@ -892,6 +1032,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 					})
 				}
 				sb.WriteString(util.FormatTable(rsHeaders, rsRows))
+				sb.WriteString("  Use recommend_resources on a namespace for per-container requests/limits with ready-to-apply kubectl patch commands.\n")
 			}
 		}
 
@@ -900,7 +1041,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		findingsCount := 0
 
 		if len(noRequestsPods) > 0 {
-			sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("%d pods have no resource requests set", len(noRequestsPods))))
+			sb.WriteString(findings.Format("KD-RES-002", "WARNING", fmt.Sprintf("%d pods have no resource requests set", len(noRequestsPods))))
 			sb.WriteString("\n")
 			for _, p := range noRequestsPods {
 				sb.WriteString(fmt.Sprintf("  - %s\n", p))
@@ -908,7 +1049,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 			findingsCount++
 		}
 		if len(noLimitsPods) > 0 {
-			sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("%d pods have no resource limits set", len(noLimitsPods))))
+			sb.WriteString(findings.Format("KD-RES-001", "WARNING", fmt.Sprintf("%d pods have no resource limits set", len(noLimitsPods))))
 			sb.WriteString("\n")
 			for _, p := range noLimitsPods {
 				sb.WriteString(fmt.Sprintf("  - %s\n", p))
@@ -919,11 +1060,11 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		if metricsAvailable && totalCPUReq > 0 {
 			cpuEff := float64(totalCPUUsage) / float64(totalCPUReq) * 100
 			if cpuEff < 30 {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Overall CPU efficiency is only %.1f%% — significant overprovisioning", cpuEff)))
+				sb.WriteString(findings.Format("KD-RES-004", "WARNING", fmt.Sprintf("Overall CPU efficiency is only %.1f%% — significant overprovisioning", cpuEff)))
 				sb.WriteString("\n")
 				findingsCount++
 			} else if cpuEff < 50 {
-				sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("CPU efficiency at %.1f%% — consider right-sizing workloads", cpuEff)))
+				sb.WriteString(findings.Format("KD-RES-004", "INFO", fmt.Sprintf("CPU efficiency at %.1f%% — consider right-sizing workloads", cpuEff)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
@@ -931,16 +1072,22 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		if metricsAvailable && totalMemReq > 0 {
 			memEff := float64(totalMemUsage) / float64(totalMemReq) * 100
 			if memEff < 30 {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Overall memory efficiency is only %.1f%% — significant overprovisioning", memEff)))
+				sb.WriteString(findings.Format("KD-RES-004", "WARNING", fmt.Sprintf("Overall memory efficiency is only %.1f%% — significant overprovisioning", memEff)))
 				sb.WriteString("\n")
 				findingsCount++
 			} else if memEff < 50 {
-				sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("Memory efficiency at %.1f%% — consider right-sizing workloads", memEff)))
+				sb.WriteString(findings.Format("KD-RES-004", "INFO", fmt.Sprintf("Memory efficiency at %.1f%% — consider right-sizing workloads", memEff)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
 		}
 
+		if fragFinding != "" {
+			sb.WriteString(fragFinding)
+			sb.WriteString("\n")
+			findingsCount++
+		}
+
 		if findingsCount == 0 {
 			sb.WriteString("  Resource efficiency appears healthy.\n")
 		}
@@ -970,10 +1117,15 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 				recNum++
 			}
 		}
+		if fragRecommendation != "" {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", recNum, fragRecommendation))
+			recNum++
+		}
 		if recNum == 1 {
 			sb.WriteString("  No specific recommendations — resource configuration looks good.\n")
 		}
 
+		progress.Report(ctx, efficiencyStages, efficiencyStages, "Done")
 		return util.SuccessResult(sb.String()), nil, nil
 	})
 
@@ -986,6 +1138,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 			"flags pods with no matching policy, and generates a Mermaid flowchart showing allowed flows (solid arrows) " +
 			"and denied flows (dotted red arrows).",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeNetworkPoliciesInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
 		ns := input.Namespace
 
 		policies, err := client.ListNetworkPolicies(ctx, ns, metav1.ListOptions{})
@@ -1003,13 +1156,13 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		sb.WriteString("\n\n")
 
 		if len(policies) == 0 {
-			sb.WriteString(util.FormatFinding("WARNING", "No network policies found in this namespace — all traffic is allowed by default"))
+			sb.WriteString(findings.Format("KD-NET-001", "WARNING", "No network policies found in this namespace — all traffic is allowed by default"))
 			sb.WriteString("\n")
 			sb.WriteString(fmt.Sprintf("  %d pods are running without any network policy protection.\n", len(pods)))
 
 			// Simple diagram for no-policy case
 			sb.WriteString("\nNETWORK FLOW DIAGRAM:\n")
-			fc := mermaid.NewFlowchart(mermaid.DirectionLR)
+			fc := newBudgetedFlowchart(mermaid.DirectionLR, input.MaxDiagramNodes)
 			fc.AddNode("ANY_SRC", "Any Source", mermaid.ShapeStadium)
 			fc.AddNode("NS", fmt.Sprintf("All Pods in %s", ns), mermaid.ShapeRect)
 			fc.AddNode("ANY_DST", "Any Destination", mermaid.ShapeStadium)
@@ -1132,7 +1285,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		findingsCount := 0
 
 		if len(uncoveredPods) > 0 {
-			sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("%d pods have no matching network policy — all traffic allowed by default", len(uncoveredPods))))
+			sb.WriteString(findings.Format("KD-NET-001", "WARNING", fmt.Sprintf("%d pods have no matching network policy — all traffic allowed by default", len(uncoveredPods))))
 			sb.WriteString("\n")
 			for _, p := range uncoveredPods {
 				sb.WriteString(fmt.Sprintf("  - %s\n", p))
@@ -1156,19 +1309,19 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 			}
 
 			if hasIngress && len(np.Spec.Ingress) == 0 {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Policy '%s' has Ingress type but no ingress rules — all inbound traffic DENIED to matched pods", np.Name)))
+				sb.WriteString(findings.Format("KD-NET-003", "CRITICAL", fmt.Sprintf("Policy '%s' has Ingress type but no ingress rules — all inbound traffic DENIED to matched pods", np.Name)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
 			if hasEgress && len(np.Spec.Egress) == 0 {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("Policy '%s' has Egress type but no egress rules — all outbound traffic DENIED from matched pods (including DNS)", np.Name)))
+				sb.WriteString(findings.Format("KD-NET-003", "CRITICAL", fmt.Sprintf("Policy '%s' has Egress type but no egress rules — all outbound traffic DENIED from matched pods (including DNS)", np.Name)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
 
 			// Check for overly broad policies (select all pods)
 			if len(np.Spec.PodSelector.MatchLabels) == 0 && len(np.Spec.PodSelector.MatchExpressions) == 0 {
-				sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("Policy '%s' selects ALL pods in namespace", np.Name)))
+				sb.WriteString(findings.Format("KD-NET-003", "INFO", fmt.Sprintf("Policy '%s' selects ALL pods in namespace", np.Name)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
@@ -1180,7 +1333,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 
 		// Mermaid flowchart
 		sb.WriteString("\nNETWORK FLOW DIAGRAM:\n")
-		fc := mermaid.NewFlowchart(mermaid.DirectionLR)
+		fc := newBudgetedFlowchart(mermaid.DirectionLR, input.MaxDiagramNodes)
 
 		// Add subgraph for allowed traffic
 		fc.AddSubgraph("allowed_traffic", "Allowed Traffic", func(sg *mermaid.Subgraph) {
@@ -1328,37 +1481,13 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		sb.WriteString("\n\n")
 
 		// Find CoreDNS pods
-		coreDNSPods, err := client.ListPods(ctx, "kube-system", metav1.ListOptions{
-			LabelSelector: "k8s-app=kube-dns",
-		})
+		coreDNSPods, err := findCoreDNSPods(ctx, client)
 		if err != nil {
 			return util.HandleK8sError("listing CoreDNS pods", err), nil, nil
 		}
 
-		// If label selector didn't find them, try alternative labels
-		if len(coreDNSPods) == 0 {
-			coreDNSPods, err = client.ListPods(ctx, "kube-system", metav1.ListOptions{
-				LabelSelector: "app.kubernetes.io/name=coredns",
-			})
-			if err != nil {
-				return util.HandleK8sError("listing CoreDNS pods", err), nil, nil
-			}
-		}
-
-		// Last resort: find by name prefix
-		if len(coreDNSPods) == 0 {
-			allKubeSystemPods, listErr := client.ListPods(ctx, "kube-system", metav1.ListOptions{})
-			if listErr == nil {
-				for _, pod := range allKubeSystemPods {
-					if strings.HasPrefix(pod.Name, "coredns-") {
-						coreDNSPods = append(coreDNSPods, pod)
-					}
-				}
-			}
-		}
-
 		if len(coreDNSPods) == 0 {
-			sb.WriteString(util.FormatFinding("CRITICAL", "No CoreDNS pods found in kube-system namespace"))
+			sb.WriteString(findings.Format("KD-NET-002", "CRITICAL", "No CoreDNS pods found in kube-system namespace"))
 			sb.WriteString("\n")
 			sb.WriteString("  DNS resolution will not work in the cluster.\n")
 			sb.WriteString("  Check if CoreDNS is deployed with: list_pods namespace=kube-system\n")
@@ -1368,7 +1497,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		// CoreDNS service check
 		coreDNSSvc, svcErr := client.GetService(ctx, "kube-system", "kube-dns")
 		if svcErr != nil {
-			sb.WriteString(util.FormatFinding("WARNING", "CoreDNS service 'kube-dns' not found in kube-system"))
+			sb.WriteString(findings.Format("KD-NET-002", "WARNING", "CoreDNS service 'kube-dns' not found in kube-system"))
 			sb.WriteString("\n\n")
 		} else {
 			sb.WriteString(util.FormatKeyValue("Service", fmt.Sprintf("%s (ClusterIP: %s)", coreDNSSvc.Name, coreDNSSvc.Spec.ClusterIP)))
@@ -1405,7 +1534,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 
 			// Check phase
 			if p.Status.Phase != corev1.PodRunning {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("CoreDNS pod '%s' is %s (not Running)", p.Name, string(p.Status.Phase))))
+				sb.WriteString(findings.Format("KD-NET-002", "CRITICAL", fmt.Sprintf("CoreDNS pod '%s' is %s (not Running)", p.Name, string(p.Status.Phase))))
 				sb.WriteString("\n")
 				findingsCount++
 			} else {
@@ -1415,11 +1544,11 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 			// Check restarts
 			_, _, restarts := podContainerSummary(p)
 			if restarts > util.HighRestartThreshold {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("CoreDNS pod '%s' has %d restarts", p.Name, restarts)))
+				sb.WriteString(findings.Format("KD-NET-002", "WARNING", fmt.Sprintf("CoreDNS pod '%s' has %d restarts", p.Name, restarts)))
 				sb.WriteString("\n")
 				findingsCount++
 			} else if restarts > 0 {
-				sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("CoreDNS pod '%s' has %d restart(s)", p.Name, restarts)))
+				sb.WriteString(findings.Format("KD-NET-002", "INFO", fmt.Sprintf("CoreDNS pod '%s' has %d restart(s)", p.Name, restarts)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
@@ -1427,7 +1556,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 			// Check readiness
 			for _, cond := range p.Status.Conditions {
 				if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue {
-					sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("CoreDNS pod '%s' is not ready: %s", p.Name, cond.Message)))
+					sb.WriteString(findings.Format("KD-NET-002", "CRITICAL", fmt.Sprintf("CoreDNS pod '%s' is not ready: %s", p.Name, cond.Message)))
 					sb.WriteString("\n")
 					findingsCount++
 				}
@@ -1436,12 +1565,12 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 			// Check container states for CrashLoop etc.
 			for _, cs := range p.Status.ContainerStatuses {
 				if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
-					sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("CoreDNS container '%s' in pod '%s' is in CrashLoopBackOff", cs.Name, p.Name)))
+					sb.WriteString(findings.Format("KD-NET-002", "CRITICAL", fmt.Sprintf("CoreDNS container '%s' in pod '%s' is in CrashLoopBackOff", cs.Name, p.Name)))
 					sb.WriteString("\n")
 					findingsCount++
 				}
 				if cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.Reason == "OOMKilled" {
-					sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("CoreDNS container '%s' in pod '%s' was OOMKilled — increase memory limit", cs.Name, p.Name)))
+					sb.WriteString(findings.Format("KD-NET-002", "CRITICAL", fmt.Sprintf("CoreDNS container '%s' in pod '%s' was OOMKilled — increase memory limit", cs.Name, p.Name)))
 					sb.WriteString("\n")
 					findingsCount++
 				}
@@ -1450,7 +1579,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 
 		// Replica count check
 		if len(coreDNSPods) < 2 {
-			sb.WriteString(util.FormatFinding("WARNING", "Only 1 CoreDNS pod running — no DNS redundancy. Consider scaling to at least 2 replicas."))
+			sb.WriteString(findings.Format("KD-WL-002", "WARNING", "Only 1 CoreDNS pod running — no DNS redundancy. Consider scaling to at least 2 replicas."))
 			sb.WriteString("\n")
 			findingsCount++
 		}
@@ -1479,19 +1608,7 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		for i := range coreDNSPods {
 			p := &coreDNSPods[i]
 
-			// Get container name (usually "coredns")
-			containerName := ""
-			for _, c := range p.Spec.Containers {
-				if strings.Contains(c.Name, "coredns") || strings.Contains(c.Name, "dns") {
-					containerName = c.Name
-					break
-				}
-			}
-			if containerName == "" && len(p.Spec.Containers) > 0 {
-				containerName = p.Spec.Containers[0].Name
-			}
-
-			logs, logErr := client.GetPodLogs(ctx, "kube-system", p.Name, containerName, 500, false, "1h")
+			logs, logErr := client.GetPodLogs(ctx, "kube-system", p.Name, coreDNSContainer(p), 500, false, coreDNSLogWindow)
 			if logErr != nil {
 				sb.WriteString(fmt.Sprintf("  Pod '%s': could not fetch logs: %v\n", p.Name, logErr))
 				continue
@@ -1529,21 +1646,21 @@ func registerResourceAnalysisTools(server *mcp.Server, client *k8s.ClusterClient
 		// Log findings
 		for _, ep := range errorPatterns {
 			if ep.count > 50 && ep.pattern == "SERVFAIL" {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("High SERVFAIL rate: %d occurrences — DNS resolution is failing", ep.count)))
+				sb.WriteString(findings.Format("KD-NET-002", "CRITICAL", fmt.Sprintf("High SERVFAIL rate: %d occurrences — DNS resolution is failing", ep.count)))
 				sb.WriteString("\n")
 				findingsCount++
 			} else if ep.count > 10 && ep.pattern == "SERVFAIL" {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Elevated SERVFAIL count: %d — some DNS queries are failing", ep.count)))
+				sb.WriteString(findings.Format("KD-NET-002", "WARNING", fmt.Sprintf("Elevated SERVFAIL count: %d — some DNS queries are failing", ep.count)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
 			if ep.count > 100 && ep.pattern == "NXDOMAIN" {
-				sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("High NXDOMAIN count: %d — check if services have correct DNS names", ep.count)))
+				sb.WriteString(findings.Format("KD-NET-007", "INFO", fmt.Sprintf("High NXDOMAIN count: %d — check if services have correct DNS names, and run audit_pod_dns_config to find pods whose ndots search-path expansion causes them", ep.count)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
 			if ep.count > 0 && (ep.pattern == "i/o timeout" || ep.pattern == "connection refused") {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("Upstream DNS connectivity issues: %d '%s' errors", ep.count, ep.pattern)))
+				sb.WriteString(findings.Format("KD-NET-002", "WARNING", fmt.Sprintf("Upstream DNS connectivity issues: %d '%s' errors", ep.count, ep.pattern)))
 				sb.WriteString("\n")
 				findingsCount++
 			}
@@ -9,6 +9,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
 )
@@ -18,7 +19,9 @@ type analyzeResourceAllocationInput struct {
 }
 
 type listLimitRangesInput struct {
-	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type getWorkloadDependenciesInput struct {
@@ -75,6 +78,10 @@ func registerResourceTools(server *mcp.Server, client *k8s.ClusterClient) {
 		// Node capacity (cluster-wide or all nodes)
 		var cpuAllocatable, memAllocatable int64
 		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+		if util.IsScopeError(err) {
+			sb.WriteString("\n")
+			writeScopeSkipped(&sb, err)
+		}
 		if err == nil && len(nodes) > 0 {
 			for _, n := range nodes {
 				cpuAllocatable += n.Status.Allocatable.Cpu().MilliValue()
@@ -89,11 +96,11 @@ func registerResourceTools(server *mcp.Server, client *k8s.ClusterClient) {
 				cpuLimPct := float64(cpuLimits) / float64(cpuAllocatable) * 100
 				sb.WriteString(fmt.Sprintf("\n  CPU Utilization: requests=%.1f%%, limits=%.1f%%\n", cpuReqPct, cpuLimPct))
 				if cpuReqPct > float64(util.ResourceUsageWarningPercent) {
-					sb.WriteString(util.FormatFinding("WARNING", "CPU requests exceed 80% of allocatable capacity"))
+					sb.WriteString(findings.Format("KD-NODE-005", "WARNING", "CPU requests exceed 80% of allocatable capacity"))
 					sb.WriteString("\n")
 				}
 				if cpuLimPct > 200 {
-					sb.WriteString(util.FormatFinding("WARNING", "CPU limits are >200% of allocatable (heavy overcommit)"))
+					sb.WriteString(findings.Format("KD-NODE-005", "WARNING", "CPU limits are >200% of allocatable (heavy overcommit)"))
 					sb.WriteString("\n")
 				}
 			}
@@ -102,7 +109,7 @@ func registerResourceTools(server *mcp.Server, client *k8s.ClusterClient) {
 				memLimPct := float64(memLimits) / float64(memAllocatable) * 100
 				sb.WriteString(fmt.Sprintf("  Memory Utilization: requests=%.1f%%, limits=%.1f%%\n", memReqPct, memLimPct))
 				if memReqPct > float64(util.ResourceUsageWarningPercent) {
-					sb.WriteString(util.FormatFinding("WARNING", "Memory requests exceed 80% of allocatable capacity"))
+					sb.WriteString(findings.Format("KD-NODE-005", "WARNING", "Memory requests exceed 80% of allocatable capacity"))
 					sb.WriteString("\n")
 				}
 			}
@@ -152,10 +159,11 @@ func registerResourceTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Description: "List LimitRange rules in a namespace showing type, resource, default/defaultRequest, min, and max values. Use namespace='all' for all namespaces.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listLimitRangesInput) (*mcp.CallToolResult, any, error) {
 		ns := util.NamespaceOrAll(input.Namespace)
-		limitRanges, err := client.ListLimitRanges(ctx, ns, metav1.ListOptions{})
+		page, err := client.ListLimitRangesPage(ctx, ns, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing limit ranges", err), nil, nil
 		}
+		limitRanges := page.Items
 
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Limit Ranges (namespace: %s)", displayNS(input.Namespace))))
@@ -163,6 +171,7 @@ func registerResourceTools(server *mcp.Server, client *k8s.ClusterClient) {
 
 		if len(limitRanges) == 0 {
 			sb.WriteString("(none)\n")
+			sb.WriteString(pageFooter("list_limit_ranges", page.Continue, page.Remaining))
 			return util.SuccessResult(sb.String()), nil, nil
 		}
 
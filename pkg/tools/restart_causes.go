@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+// Causes of a container's last restart, as told apart by attributeRestart.
+const (
+	restartCauseLiveness = "liveness probe"
+	restartCauseOOM      = "OOMKilled"
+	restartCauseCrash    = "application crash"
+	restartCauseExit     = "clean exit"
+)
+
+// restartAttribution explains what ended a container's last run.
+type restartAttribution struct {
+	Cause string
+	// Kills is how many times the kubelet killed the container for failing
+	// its liveness probe within the events' retention (about an hour).
+	Kills    int32
+	Failures int32
+	// LastProbeMessage is the most recent liveness failure, e.g. the HTTP
+	// status or timeout the probe saw.
+	LastProbeMessage string
+	ExitCode         int32
+	Reason           string
+}
+
+// Detail describes the attribution in one line.
+func (a restartAttribution) Detail() string {
+	exit := formatExitCode(a.ExitCode, a.Reason)
+	switch a.Cause {
+	case restartCauseLiveness:
+		detail := fmt.Sprintf("killed by the kubelet %d time(s) after failing its liveness probe, then exited %s", a.Kills, exit)
+		if a.LastProbeMessage != "" {
+			detail += "; last failure: " + truncateName(a.LastProbeMessage, 120)
+		}
+		return detail
+	case restartCauseOOM:
+		return "killed by the kernel for exceeding its memory limit"
+	case restartCauseCrash:
+		if a.Kills > 0 {
+			return fmt.Sprintf("the process exited on its own with %s (it was also killed %d time(s) by its liveness probe)", exit, a.Kills)
+		}
+		return fmt.Sprintf("the process exited on its own with %s; no liveness probe kills in events", exit)
+	}
+	return "the process exited with code 0 and was restarted by the pod's restartPolicy"
+}
+
+// Remediation returns what to change for this cause; it differs completely
+// between a probe that kills a working app and an app that crashes.
+func (a restartAttribution) Remediation(container string) string {
+	switch a.Cause {
+	case restartCauseLiveness:
+		return fmt.Sprintf("Container '%s' did not crash: its liveness probe failed and the kubelet restarted it. Fix the probe or the endpoint's latency "+
+			"(raise timeoutSeconds/failureThreshold, add a startupProbe, keep the endpoint free of dependency checks) rather than the application's error handling", container)
+	case restartCauseOOM:
+		return fmt.Sprintf("Raise the memory limit of container '%s' or reduce its memory use; probe tuning will not help", container)
+	case restartCauseCrash:
+		return fmt.Sprintf("Container '%s' crashes on its own: read its previous logs for the error (get_pod_logs with previous=true); probe tuning will not help", container)
+	}
+	return fmt.Sprintf("Container '%s' exits with code 0: make its main process stay in the foreground, or run it as a Job if it is meant to finish", container)
+}
+
+// attributeRestart tells apart a restart caused by a failing liveness probe
+// from an application crash, using the last termination and the pod's
+// events. A probe kill sends SIGTERM and then SIGKILL, so the container exits
+// 0 or 143 when it shuts down gracefully and 137 when it does not; any other
+// exit code means the process failed on its own even if probes also failed.
+// It reports false when the container has not terminated yet.
+func attributeRestart(cs corev1.ContainerStatus, events []corev1.Event) (restartAttribution, bool) {
+	t := crashTermination(cs)
+	if t == nil {
+		return restartAttribution{}, false
+	}
+	a := restartAttribution{ExitCode: t.ExitCode, Reason: t.Reason}
+	a.Failures, a.Kills, a.LastProbeMessage = livenessEventCounts(events, cs.Name)
+	switch {
+	case t.Reason == "OOMKilled":
+		a.Cause = restartCauseOOM
+	case a.Kills > 0 && (t.ExitCode == 0 || t.ExitCode == 137 || t.ExitCode == 143):
+		a.Cause = restartCauseLiveness
+	case t.ExitCode != 0:
+		a.Cause = restartCauseCrash
+	default:
+		a.Cause = restartCauseExit
+	}
+	return a, true
+}
+
+// livenessEventCounts counts liveness probe failures and the restarts they
+// caused for one container in a pod's events, and returns the most recent
+// failure message.
+func livenessEventCounts(events []corev1.Event, container string) (failures, kills int32, lastMessage string) {
+	var last *corev1.Event
+	for i := range events {
+		e := &events[i]
+		if name := eventContainer(e); name != "" && name != container {
+			continue
+		}
+		switch {
+		case e.Reason == "Unhealthy" && strings.HasPrefix(e.Message, "Liveness probe failed"):
+			failures += eventOccurrences(e)
+			if last == nil || k8s.EventTime(e).After(k8s.EventTime(last)) {
+				last = e
+			}
+		case isLivenessKill(e):
+			kills += eventOccurrences(e)
+		}
+	}
+	if last != nil {
+		lastMessage = strings.TrimSpace(strings.TrimPrefix(last.Message, "Liveness probe failed:"))
+	}
+	return failures, kills, lastMessage
+}
+
+// isLivenessKill reports whether an event is the kubelet killing a container
+// because its liveness probe failed ("Container api failed liveness probe,
+// will be restarted"; older kubelets say the container is unhealthy).
+func isLivenessKill(e *corev1.Event) bool {
+	if e.Reason != "Killing" {
+		return false
+	}
+	msg := strings.ToLower(e.Message)
+	return strings.Contains(msg, "liveness probe") || strings.Contains(msg, "unhealthy")
+}
+
+// eventContainer returns the container a kubelet event is about, from its
+// field path (spec.containers{api}) or, failing that, its "Container api"
+// message prefix; "" when the event names no container.
+func eventContainer(e *corev1.Event) string {
+	if fp := e.InvolvedObject.FieldPath; fp != "" {
+		if _, rest, ok := strings.Cut(fp, "{"); ok {
+			return strings.TrimSuffix(rest, "}")
+		}
+	}
+	if rest, ok := strings.CutPrefix(e.Message, "Container "); ok {
+		if name, _, ok := strings.Cut(rest, " "); ok {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func probeEvent(reason, container, message string, count int32, at time.Time) corev1.Event {
+	return corev1.Event{
+		Reason:         reason,
+		Message:        message,
+		Count:          count,
+		LastTimestamp:  metav1.NewTime(at),
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "api-1", FieldPath: "spec.containers{" + container + "}"},
+	}
+}
+
+func TestAttributeRestart(t *testing.T) {
+	now := time.Now()
+	events := []corev1.Event{
+		probeEvent("Unhealthy", "api", "Liveness probe failed: Get \"http://10.0.0.5:8080/healthz\": context deadline exceeded", 6, now.Add(-time.Minute)),
+		probeEvent("Unhealthy", "api", "Liveness probe failed: HTTP probe failed with statuscode: 503", 3, now),
+		probeEvent("Unhealthy", "api", "Readiness probe failed: HTTP probe failed with statuscode: 503", 9, now),
+		probeEvent("Killing", "api", "Container api failed liveness probe, will be restarted", 2, now),
+		probeEvent("Killing", "sidecar", "Container sidecar failed liveness probe, will be restarted", 5, now),
+	}
+	tests := []struct {
+		name     string
+		exitCode int32
+		reason   string
+		events   []corev1.Event
+		want     string
+	}{
+		{"probe kill, graceful shutdown", 0, "Completed", events, restartCauseLiveness},
+		{"probe kill, SIGTERM", 143, "Error", events, restartCauseLiveness},
+		{"probe kill, SIGKILL after grace", 137, "Error", events, restartCauseLiveness},
+		{"crash despite probe kills", 1, "Error", events, restartCauseCrash},
+		{"oom beats probe kills", 137, "OOMKilled", events, restartCauseOOM},
+		{"sigkill without probe kills", 137, "Error", nil, restartCauseCrash},
+		{"clean exit", 0, "Completed", nil, restartCauseExit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, ok := attributeRestart(crashedStatus(tt.exitCode, tt.reason), tt.events)
+			if !ok || a.Cause != tt.want {
+				t.Errorf("attributeRestart = %q (%v), want %q", a.Cause, ok, tt.want)
+			}
+		})
+	}
+
+	a, _ := attributeRestart(crashedStatus(143, "Error"), events)
+	if a.Kills != 2 || a.Failures != 9 || a.LastProbeMessage != "HTTP probe failed with statuscode: 503" {
+		t.Errorf("attribution = %+v", a)
+	}
+	if !strings.Contains(a.Detail(), "killed by the kubelet 2 time(s)") || !strings.Contains(a.Remediation("api"), "did not crash") {
+		t.Errorf("detail %q, remediation %q", a.Detail(), a.Remediation("api"))
+	}
+
+	running := corev1.ContainerStatus{Name: "api", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+	if _, ok := attributeRestart(running, events); ok {
+		t.Error("a container that never terminated has no restart to attribute")
+	}
+}
+
+func TestEventContainer(t *testing.T) {
+	tests := []struct {
+		event corev1.Event
+		want  string
+	}{
+		{corev1.Event{InvolvedObject: corev1.ObjectReference{FieldPath: "spec.containers{api}"}}, "api"},
+		{corev1.Event{InvolvedObject: corev1.ObjectReference{FieldPath: "spec.initContainers{migrate}"}}, "migrate"},
+		{corev1.Event{Message: "Container web failed liveness probe, will be restarted"}, "web"},
+		{corev1.Event{Message: "Stopping container web"}, ""},
+	}
+	for _, tt := range tests {
+		if got := eventContainer(&tt.event); got != tt.want {
+			t.Errorf("eventContainer(%+v) = %q, want %q", tt.event, got, tt.want)
+		}
+	}
+	legacy := corev1.Event{Reason: "Killing", Message: "Killing container with id docker://api:Container failed liveness probe.. Container will be killed and recreated."}
+	unhealthy := corev1.Event{Reason: "Killing", Message: "Container api is unhealthy, it will be killed and re-created."}
+	evicted := corev1.Event{Reason: "Killing", Message: "Stopping container api"}
+	if !isLivenessKill(&legacy) || !isLivenessKill(&unhealthy) || isLivenessKill(&evicted) {
+		t.Error("isLivenessKill should match liveness and unhealthy kills only")
+	}
+}
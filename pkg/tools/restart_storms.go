@@ -0,0 +1,447 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// restartStormDefaultWindow is how close together restarts must be to count as one storm.
+	restartStormDefaultWindow = 5 * time.Minute
+
+	// restartStormDefaultLookback is how far back container restarts are considered.
+	restartStormDefaultLookback = 6 * time.Hour
+
+	// restartStormDefaultMinPods is the number of distinct pods that makes a burst a storm.
+	restartStormDefaultMinPods = 3
+
+	// restartStormNodeSlack widens a storm's time range when correlating node events.
+	restartStormNodeSlack = 5 * time.Minute
+)
+
+type detectRestartStormsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace (empty for all namespaces)"`
+	Window         string `json:"window,omitempty" jsonschema:"Maximum spread of restarts within one storm, as a Go duration (default 5m)"`
+	Lookback       string `json:"lookback,omitempty" jsonschema:"How far back to look for restarts, as a Go duration (default 6h)"`
+	MinPods        int    `json:"min_pods,omitempty" jsonschema:"Distinct pods that must restart within the window to count as a storm (default 3)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// containerRestart is the most recent restart of one container.
+type containerRestart struct {
+	Pod       *corev1.Pod
+	Container string
+	At        time.Time
+	ExitCode  int32
+	Reason    string
+	// Cause is the restartCause* constant from attributeRestart, or "" when
+	// the pod's events could not be read.
+	Cause string
+}
+
+// restartStorm is a burst of restarts across several pods in one namespace.
+type restartStorm struct {
+	Namespace string
+	Start     time.Time
+	End       time.Time
+	Restarts  []containerRestart
+}
+
+// pods returns the distinct pods in the storm, in restart order.
+func (s restartStorm) pods() []*corev1.Pod {
+	seen := make(map[string]bool)
+	var out []*corev1.Pod
+	for _, r := range s.Restarts {
+		if !seen[r.Pod.Name] {
+			seen[r.Pod.Name] = true
+			out = append(out, r.Pod)
+		}
+	}
+	return out
+}
+
+// count returns how many restarts in the storm satisfy key, grouped by key value.
+func (s restartStorm) count(key func(containerRestart) string) map[string]int {
+	out := make(map[string]int)
+	for _, r := range s.Restarts {
+		if k := key(r); k != "" {
+			out[k]++
+		}
+	}
+	return out
+}
+
+func registerRestartStormTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// detect_restart_storms
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "detect_restart_storms",
+		Description: "Find restart storms: many pods in a namespace restarting within minutes of each other. Each storm is " +
+			"correlated with node events (pressure, kubelet restarts, image GC failures), shared nodes, shared ConfigMaps/Secrets/PVCs, " +
+			"and shared exit reasons to name the likely shared cause instead of listing every pod. Restarts are attributed to liveness probe kills, " +
+			"OOM kills, or application crashes using the pods' Killing events, since each needs a different fix.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input detectRestartStormsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		window := restartStormDefaultWindow
+		if input.Window != "" {
+			d, err := time.ParseDuration(input.Window)
+			if err != nil || d <= 0 {
+				return util.ErrorResult("invalid window %q: use a Go duration such as 5m", input.Window), nil, nil
+			}
+			window = d
+		}
+		lookback := restartStormDefaultLookback
+		if input.Lookback != "" {
+			d, err := time.ParseDuration(input.Lookback)
+			if err != nil || d <= 0 {
+				return util.ErrorResult("invalid lookback %q: use a Go duration such as 6h", input.Lookback), nil, nil
+			}
+			lookback = d
+		}
+		minPods := input.MinPods
+		if minPods <= 0 {
+			minPods = restartStormDefaultMinPods
+		}
+
+		pods, err := client.ListPods(ctx, util.NamespaceOrAll(input.Namespace), metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		restarts := collectContainerRestarts(pods, time.Now().Add(-lookback))
+		killEvents, killErr := client.ListEvents(ctx, util.NamespaceOrAll(input.Namespace), metav1.ListOptions{FieldSelector: "reason=Killing"})
+		if killErr == nil {
+			attributeContainerRestarts(restarts, killEvents)
+		}
+		storms := findRestartStorms(restarts, window, minPods)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Restart Storms (namespace: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+		sb.WriteString(fmt.Sprintf("%d container restart(s) in the last %s; storms are %d+ pods restarting within %s.\n",
+			len(restarts), lookback, minPods, window))
+		sb.WriteString("Only the most recent restart of each container is visible in pod status.\n")
+		if killErr != nil {
+			sb.WriteString(fmt.Sprintf("(could not list Killing events: %v; liveness probe kills cannot be told apart from crashes)\n", killErr))
+		} else if len(restarts) > 0 {
+			sb.WriteString(util.FormatKeyValue("Restart causes", formatCounts(countRestartCauses(restarts))))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+
+		if len(storms) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "No restart storms found"))
+			sb.WriteString("\n")
+			return util.WithNextSteps(util.SuccessResult(sb.String()), nil), nil, nil
+		}
+
+		// Node events for every storm, fetched once
+		earliest := storms[0].Start
+		for _, s := range storms {
+			if s.Start.Before(earliest) {
+				earliest = s.Start
+			}
+		}
+		nodeEvents, nodeErr := client.ListEventsMatching(ctx, "", metav1.ListOptions{FieldSelector: "involvedObject.kind=Node"},
+			k8s.EventFilter{Since: earliest.Add(-restartStormNodeSlack), Limit: 1000})
+
+		var actions []string
+		var steps []util.NextStep
+		for i, s := range storms {
+			stormPods := s.pods()
+			sb.WriteString(util.FormatSubHeader(fmt.Sprintf("Storm %d: %d pods in %s", i+1, len(stormPods), s.Namespace)))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatKeyValue("Window", fmt.Sprintf("%s - %s (%s)",
+				s.Start.UTC().Format(time.RFC3339), s.End.UTC().Format("15:04:05"), s.End.Sub(s.Start).Round(time.Second))))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatKeyValue("Workloads", formatCounts(s.count(func(r containerRestart) string { return podWorkloadName(r.Pod) }))))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatKeyValue("Nodes", formatCounts(s.count(func(r containerRestart) string { return r.Pod.Spec.NodeName }))))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatKeyValue("Exit", formatCounts(s.count(func(r containerRestart) string { return formatExitCode(r.ExitCode, r.Reason) }))))
+			sb.WriteString("\n")
+			if killErr == nil {
+				sb.WriteString(util.FormatKeyValue("Cause", formatCounts(countRestartCauses(s.Restarts))))
+				sb.WriteString("\n")
+			}
+
+			var related []string
+			if nodeErr == nil {
+				related = stormNodeEvents(s, nodeEvents)
+			}
+			for _, e := range related {
+				sb.WriteString(fmt.Sprintf("  - node event: %s\n", e))
+			}
+
+			cause, stormActions := restartStormCause(s, related)
+			sb.WriteString(findings.Format("KD-EVT-002", "CRITICAL", fmt.Sprintf("%d pods restarted within %s - likely shared cause: %s",
+				len(stormPods), s.End.Sub(s.Start).Round(time.Second), cause)))
+			sb.WriteString("\n\n")
+			actions = append(actions, stormActions...)
+			steps = append(steps, restartStormSteps(s, related)...)
+		}
+		if nodeErr != nil {
+			sb.WriteString(fmt.Sprintf("(could not list node events: %v)\n\n", nodeErr))
+		}
+
+		sb.WriteString("SUGGESTED ACTIONS:\n")
+		for i, a := range dedupe(actions) {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// collectContainerRestarts returns the last restart of every container that
+// restarted after since, oldest first.
+func collectContainerRestarts(pods []corev1.Pod, since time.Time) []containerRestart {
+	var out []containerRestart
+	for i := range pods {
+		p := &pods[i]
+		for _, cs := range p.Status.ContainerStatuses {
+			t := cs.LastTerminationState.Terminated
+			if cs.RestartCount == 0 || t == nil || t.FinishedAt.Time.Before(since) {
+				continue
+			}
+			out = append(out, containerRestart{Pod: p, Container: cs.Name, At: t.FinishedAt.Time, ExitCode: t.ExitCode, Reason: t.Reason})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out
+}
+
+// findRestartStorms groups restarts by namespace and returns each run of
+// restarts no more than window apart from its first that spans at least
+// minPods distinct pods.
+func findRestartStorms(restarts []containerRestart, window time.Duration, minPods int) []restartStorm {
+	byNS := make(map[string][]containerRestart)
+	var namespaces []string
+	for _, r := range restarts {
+		if _, ok := byNS[r.Pod.Namespace]; !ok {
+			namespaces = append(namespaces, r.Pod.Namespace)
+		}
+		byNS[r.Pod.Namespace] = append(byNS[r.Pod.Namespace], r)
+	}
+	sort.Strings(namespaces)
+
+	var storms []restartStorm
+	for _, ns := range namespaces {
+		rs := byNS[ns]
+		for i := 0; i < len(rs); {
+			j := i
+			for j+1 < len(rs) && rs[j+1].At.Sub(rs[i].At) <= window {
+				j++
+			}
+			s := restartStorm{Namespace: ns, Start: rs[i].At, End: rs[j].At, Restarts: rs[i : j+1]}
+			if len(s.pods()) >= minPods {
+				storms = append(storms, s)
+				i = j + 1
+				continue
+			}
+			i++
+		}
+	}
+	sort.SliceStable(storms, func(i, j int) bool { return len(storms[i].pods()) > len(storms[j].pods()) })
+	return storms
+}
+
+// stormNodeEvents describes problem events on the storm's nodes around the storm.
+func stormNodeEvents(s restartStorm, events []corev1.Event) []string {
+	nodes := s.count(func(r containerRestart) string { return r.Pod.Spec.NodeName })
+	var out []string
+	for i := range events {
+		e := &events[i]
+		at := k8s.EventTime(e)
+		if nodes[e.InvolvedObject.Name] == 0 || at.Before(s.Start.Add(-restartStormNodeSlack)) || at.After(s.End.Add(restartStormNodeSlack)) {
+			continue
+		}
+		meaning, known := nodeProblemEventReasons[e.Reason]
+		if !known && e.Type != corev1.EventTypeWarning {
+			continue
+		}
+		line := fmt.Sprintf("%s %s on %s: %s", at.UTC().Format("15:04:05"), e.Reason, e.InvolvedObject.Name, truncateName(e.Message, 120))
+		if meaning != "" {
+			line += fmt.Sprintf(" [%s]", meaning)
+		}
+		out = append(out, line)
+	}
+	return dedupe(out)
+}
+
+// sharedStormDependencies returns the ConfigMaps, Secrets, and PVCs that
+// every pod in the storm consumes.
+func sharedStormDependencies(pods []*corev1.Pod) []string {
+	counts := make(map[string]int)
+	for _, p := range pods {
+		deps := make(map[string]bool)
+		for _, ref := range podConfigRefs(p.Spec) {
+			if ref.Name != "kube-root-ca.crt" {
+				deps[ref.Key()] = true
+			}
+		}
+		for _, v := range p.Spec.Volumes {
+			if v.PersistentVolumeClaim != nil {
+				deps["PersistentVolumeClaim/"+v.PersistentVolumeClaim.ClaimName] = true
+			}
+		}
+		for d := range deps {
+			counts[d]++
+		}
+	}
+	var out []string
+	for d, n := range counts {
+		if n == len(pods) {
+			out = append(out, d)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// restartStormCause names the most likely shared cause of a storm and the
+// actions that address it.
+func restartStormCause(s restartStorm, nodeEvents []string) (string, []string) {
+	pods := s.pods()
+	nodes := s.count(func(r containerRestart) string { return r.Pod.Spec.NodeName })
+	workloads := s.count(func(r containerRestart) string { return podWorkloadName(r.Pod) })
+	reasons := s.count(func(r containerRestart) string { return r.Reason })
+	total := len(s.Restarts)
+
+	if len(nodeEvents) > 0 {
+		names := strings.Join(sortedKeysInt(nodes), ", ")
+		return fmt.Sprintf("node problems on %s at the same time", names), []string{
+			fmt.Sprintf("Run get_node_detail on %s to check pressure conditions and kubelet health", names),
+			"Check node disk usage and image garbage collection if FreeDiskSpaceFailed or ImageGCFailed events appear",
+		}
+	}
+	if len(nodes) == 1 && len(workloads) > 1 {
+		for node := range nodes {
+			return fmt.Sprintf("all restarted pods of %d workloads share node %s", len(workloads), node), []string{
+				fmt.Sprintf("Run get_node_detail on %s; consider cordoning it while investigating", node),
+			}
+		}
+	}
+	if reasons["OOMKilled"]*2 > total {
+		return fmt.Sprintf("%d of %d restarts were OOMKilled - memory limits too low or a shared load spike", reasons["OOMKilled"], total), []string{
+			fmt.Sprintf("Run analyze_resource_usage on namespace %s to compare memory usage with limits", s.Namespace),
+		}
+	}
+	causes := countRestartCauses(s.Restarts)
+	if causes[restartCauseLiveness]*2 > total {
+		return fmt.Sprintf("%d of %d restarts were liveness probe kills, not crashes - probes failing together usually time out under load or call a shared dependency",
+			causes[restartCauseLiveness], total), []string{
+			fmt.Sprintf("Run analyze_probes on namespace %s; raise liveness timeoutSeconds/failureThreshold and keep liveness endpoints free of dependency checks", s.Namespace),
+			"Do not chase application errors in the logs first: the kubelet restarted these containers, they did not crash",
+		}
+	}
+	if shared := sharedStormDependencies(pods); len(shared) > 0 && len(workloads) > 1 {
+		return fmt.Sprintf("%d workloads share %s", len(workloads), strings.Join(shared, ", ")), []string{
+			fmt.Sprintf("Check recent changes to %s (run check_config_drift on namespace %s)", strings.Join(shared, ", "), s.Namespace),
+			"If the shared dependency is a backing service, check its health; liveness probes that call dependencies cause cascading restarts",
+		}
+	}
+	exits := s.count(func(r containerRestart) string {
+		if r.ExitCode == 137 || r.ExitCode == 143 {
+			return "killed"
+		}
+		return ""
+	})
+	if exits["killed"]*2 > total {
+		return "containers were killed (exit 137/143) together - usually liveness probes failing on a shared dependency", []string{
+			fmt.Sprintf("Run analyze_probes on namespace %s; liveness probes should not depend on databases or other services", s.Namespace),
+		}
+	}
+	if len(workloads) == 1 {
+		for w := range workloads {
+			return fmt.Sprintf("all restarts are in workload %s - an application-level failure", w), []string{
+				fmt.Sprintf("Run cluster_crashloops on namespace %s to fingerprint the failure", s.Namespace),
+			}
+		}
+	}
+	return fmt.Sprintf("%d workloads failed together - check a shared upstream dependency", len(workloads)), []string{
+		fmt.Sprintf("Run get_events on namespace %s around %s", s.Namespace, s.Start.UTC().Format(time.RFC3339)),
+	}
+}
+
+// attributeContainerRestarts sets the cause of each restart from the
+// Killing events of its pod.
+func attributeContainerRestarts(restarts []containerRestart, events []corev1.Event) {
+	byPod := make(map[string][]corev1.Event)
+	for _, e := range events {
+		if e.InvolvedObject.Kind == "Pod" {
+			key := e.InvolvedObject.Namespace + "/" + e.InvolvedObject.Name
+			byPod[key] = append(byPod[key], e)
+		}
+	}
+	for i := range restarts {
+		r := &restarts[i]
+		for _, cs := range r.Pod.Status.ContainerStatuses {
+			if cs.Name != r.Container {
+				continue
+			}
+			if a, ok := attributeRestart(cs, byPod[r.Pod.Namespace+"/"+r.Pod.Name]); ok {
+				r.Cause = a.Cause
+			}
+		}
+	}
+}
+
+// countRestartCauses counts restarts by attributed cause.
+func countRestartCauses(restarts []containerRestart) map[string]int {
+	counts := make(map[string]int)
+	for _, r := range restarts {
+		if r.Cause != "" {
+			counts[r.Cause]++
+		}
+	}
+	return counts
+}
+
+// restartStormSteps returns the follow-up tool calls for a storm: the nodes
+// when node events coincide with it, otherwise a representative pod.
+func restartStormSteps(s restartStorm, nodeEvents []string) []util.NextStep {
+	if len(nodeEvents) > 0 {
+		var steps []util.NextStep
+		for _, node := range sortedKeysInt(s.count(func(r containerRestart) string { return r.Pod.Spec.NodeName })) {
+			steps = append(steps, nextStep("get_node_detail", "Node events coincide with the restart storm", "name", node))
+		}
+		return steps
+	}
+	first := s.Restarts[0]
+	return []util.NextStep{nextStep("diagnose_pod", fmt.Sprintf("Representative of %d pods restarting together", len(s.pods())),
+		"namespace", first.Pod.Namespace, "name", first.Pod.Name)}
+}
+
+// formatCounts renders value counts as "a (3), b (1)", most frequent first.
+func formatCounts(counts map[string]int) string {
+	keys := sortedKeysInt(counts)
+	sort.SliceStable(keys, func(i, j int) bool { return counts[keys[i]] > counts[keys[j]] })
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s (%d)", k, counts[k]))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sortedKeysInt returns the keys of a count map in sorted order.
+func sortedKeysInt(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func restartedPod(name, node, configMap string, at time.Time, exitCode int32) corev1.Pod {
+	pod := withVolumes(testPod("shop", name, node, nil), corev1.Volume{Name: "cfg", VolumeSource: corev1.VolumeSource{
+		ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: configMap}},
+	}})
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:                 "app",
+		RestartCount:         1,
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, FinishedAt: metav1.NewTime(at)}},
+	}}
+	return pod
+}
+
+func TestFindRestartStorms(t *testing.T) {
+	now := time.Now()
+	var pods []corev1.Pod
+	for i := 0; i < 4; i++ {
+		pods = append(pods, restartedPod(fmt.Sprintf("svc%d-abc", i), fmt.Sprintf("n%d", i), "shared-config", now.Add(-time.Hour+time.Duration(i)*30*time.Second), 1))
+	}
+	// An isolated restart hours earlier is not part of the storm
+	pods = append(pods, restartedPod("lonely", "n9", "other", now.Add(-3*time.Hour), 1))
+
+	storms := findRestartStorms(collectContainerRestarts(pods, now.Add(-6*time.Hour)), 5*time.Minute, 3)
+	if len(storms) != 1 {
+		t.Fatalf("expected 1 storm, got %d", len(storms))
+	}
+	if n := len(storms[0].pods()); n != 4 {
+		t.Errorf("expected 4 pods in the storm, got %d", n)
+	}
+
+	cause, actions := restartStormCause(storms[0], nil)
+	if !strings.Contains(cause, "ConfigMap/shared-config") || len(actions) == 0 {
+		t.Errorf("expected shared ConfigMap cause with actions, got %q %v", cause, actions)
+	}
+
+	cause, _ = restartStormCause(storms[0], []string{"NodeNotReady on n1"})
+	if !strings.Contains(cause, "node problems") {
+		t.Errorf("expected node events to take precedence, got %q", cause)
+	}
+}
+
+func TestRestartStormLivenessCause(t *testing.T) {
+	now := time.Now()
+	var pods []corev1.Pod
+	var events []corev1.Event
+	for i := 0; i < 3; i++ {
+		p := restartedPod(fmt.Sprintf("api-%d", i), fmt.Sprintf("n%d", i), "shared-config", now.Add(-time.Hour+time.Duration(i)*10*time.Second), 137)
+		pods = append(pods, p)
+		events = append(events, corev1.Event{
+			Reason: "Killing", Message: "Container app failed liveness probe, will be restarted", Count: 1,
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: p.Name, FieldPath: "spec.containers{app}"},
+		})
+	}
+	pods = append(pods, restartedPod("worker-0", "n3", "shared-config", now.Add(-time.Hour+30*time.Second), 1))
+
+	restarts := collectContainerRestarts(pods, now.Add(-6*time.Hour))
+	attributeContainerRestarts(restarts, events)
+	if counts := countRestartCauses(restarts); counts[restartCauseLiveness] != 3 || counts[restartCauseCrash] != 1 {
+		t.Errorf("causes = %v", counts)
+	}
+
+	storms := findRestartStorms(restarts, 5*time.Minute, 3)
+	if len(storms) != 1 {
+		t.Fatalf("expected 1 storm, got %d", len(storms))
+	}
+	cause, actions := restartStormCause(storms[0], nil)
+	if !strings.Contains(cause, "3 of 4 restarts were liveness probe kills") || !strings.Contains(strings.Join(actions, "\n"), "analyze_probes") {
+		t.Errorf("expected liveness kills to be named before the shared ConfigMap, got %q %v", cause, actions)
+	}
+}
@@ -0,0 +1,274 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// rightSizeRequestHeadroom is added to observed usage for recommended requests.
+	rightSizeRequestHeadroom = 1.15
+
+	// rightSizeLimitHeadroom is added to observed usage for recommended limits.
+	rightSizeLimitHeadroom = 1.5
+
+	// rightSizeMinChange is the relative change below which a container is
+	// considered already right-sized.
+	rightSizeMinChange = 0.10
+
+	// rightSizeMinCPU and rightSizeMinMemory are the smallest requests recommended.
+	rightSizeMinCPU    = 10
+	rightSizeMinMemory = 16 << 20
+)
+
+type recommendResourcesInput struct {
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Workload       string `json:"workload,omitempty" jsonschema:"Only recommend resources for this workload (Deployment, StatefulSet, or DaemonSet name)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// resourceRecommendation is the recommended requests and limits for one
+// container. A zero limit means none is recommended.
+type resourceRecommendation struct {
+	Usage      workloadUsage
+	CPURequest int64
+	CPULimit   int64
+	MemRequest int64
+	MemLimit   int64
+}
+
+func registerRightSizingTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "recommend_resources",
+		Description: "VPA-style right-sizing: recommend CPU/memory requests and limits per container from observed usage plus headroom " +
+			"(requests = usage + 15%, memory limit = usage + 50%; CPU limits only where one is already set), and print ready-to-apply " +
+			"resources YAML and `kubectl patch` commands per workload. Uses 7-day p95 usage when Prometheus is configured, " +
+			"otherwise a single metrics-server sample.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input recommendResourcesInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if input.Namespace == "" {
+			return util.ErrorResult("namespace is required"), nil, nil
+		}
+
+		pods, err := client.ListPods(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Resource Recommendations: %s", input.Namespace)))
+		sb.WriteString("\n\n")
+
+		source := fmt.Sprintf("p95 over %dd from Prometheus", usageHistoryDays)
+		var usage []k8s.Usage
+		if client.Prometheus != nil {
+			usage, err = client.GetUsageHistory(ctx, k8s.UsageQuery{Namespace: input.Namespace, PerContainer: true})
+			if err != nil {
+				sb.WriteString(findings.Format("KD-SYS-008", "WARNING", fmt.Sprintf("Prometheus usage history unavailable (%v); falling back to metrics-server", err)))
+				sb.WriteString("\n\n")
+			}
+		}
+		if len(usage) == 0 {
+			podMetrics, err := client.GetPodMetrics(ctx, input.Namespace, metav1.ListOptions{})
+			if err != nil {
+				return util.ErrorResult("no usage data: configure --prometheus-url or install metrics-server (%v)", err), nil, nil
+			}
+			for _, pm := range podMetrics {
+				for _, c := range pm.Containers {
+					usage = append(usage, k8s.Usage{
+						Namespace:   pm.Namespace,
+						Pod:         pm.Name,
+						Container:   c.Name,
+						CPUMillis:   c.Usage.Cpu().MilliValue(),
+						MemoryBytes: c.Usage.Memory().Value(),
+					})
+				}
+			}
+			source = "single metrics-server sample — verify against peak load before applying"
+		}
+		sb.WriteString(fmt.Sprintf("Usage source: %s\n\n", source))
+
+		var recs []resourceRecommendation
+		rightSized := 0
+		for _, u := range aggregateWorkloadUsage(usage, pods, input.Workload) {
+			if !u.HasSpec {
+				continue
+			}
+			r := recommendResources(u)
+			if !needsResize(r) {
+				rightSized++
+				continue
+			}
+			recs = append(recs, r)
+		}
+
+		if len(recs) == 0 {
+			sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("%d containers are within %.0f%% of their recommended requests and limits", rightSized, rightSizeMinChange*100)))
+			sb.WriteString("\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		headers := []string{"WORKLOAD", "CONTAINER", "USAGE CPU/MEM", "CPU REQ", "CPU LIMIT", "MEM REQ", "MEM LIMIT"}
+		rows := make([][]string, 0, len(recs))
+		for _, r := range recs {
+			u := r.Usage
+			rows = append(rows, []string{
+				truncateName(u.Workload, 40), u.Container,
+				fmt.Sprintf("%dm/%s", u.CPUMillis, formatBytes(u.MemoryBytes)),
+				resizeChange(formatMillis(u.CPURequest), formatMillis(r.CPURequest)),
+				resizeChange(formatMillis(u.CPULimit), formatMillis(r.CPULimit)),
+				resizeChange(formatBytesOrNone(u.MemRequest), formatMemQuantity(r.MemRequest)),
+				resizeChange(formatBytesOrNone(u.MemLimit), formatMemQuantity(r.MemLimit)),
+			})
+		}
+		sb.WriteString(util.FormatTable(headers, rows))
+		if rightSized > 0 {
+			sb.WriteString(fmt.Sprintf("\n%d other containers are already right-sized.\n", rightSized))
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Patches"))
+		sb.WriteString("\n")
+		for _, group := range groupRecommendations(recs) {
+			u := group[0].Usage
+			sb.WriteString(fmt.Sprintf("# %s/%s\n", u.Kind, u.Workload))
+			for _, r := range group {
+				sb.WriteString(fmt.Sprintf("# container %s:\n", r.Usage.Container))
+				sb.WriteString(resourcesYAML(r))
+			}
+			if cmd := rightSizePatchCommand(input.Namespace, group); cmd != "" {
+				sb.WriteString(cmd + "\n\n")
+			} else {
+				sb.WriteString(fmt.Sprintf("# %s pods are not managed by a patchable workload; update the owning manifest instead.\n\n", u.Workload))
+			}
+		}
+
+		sb.WriteString("SUGGESTED ACTIONS:\n")
+		sb.WriteString("  1. Apply the patches through your GitOps source or Helm values where the workload is managed there; direct patches are reverted on the next sync\n")
+		sb.WriteString("  2. Patching a workload's resources triggers a rolling restart; apply during a quiet period\n")
+		sb.WriteString("  3. Re-run after a week of traffic to confirm the new requests hold at peak\n")
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// recommendResources derives requests and limits from observed usage. CPU
+// limits are only recommended for containers that already have one, since
+// CPU limits throttle rather than protect.
+func recommendResources(u workloadUsage) resourceRecommendation {
+	r := resourceRecommendation{Usage: u}
+	r.CPURequest = roundUpTo(max(int64(float64(u.CPUMillis)*rightSizeRequestHeadroom), rightSizeMinCPU), 5)
+	r.MemRequest = roundUpTo(max(int64(float64(u.MemoryBytes)*rightSizeRequestHeadroom), rightSizeMinMemory), 1<<20)
+	r.MemLimit = roundUpTo(max(int64(float64(u.MemoryBytes)*rightSizeLimitHeadroom), r.MemRequest), 1<<20)
+	if u.CPULimit > 0 {
+		r.CPULimit = roundUpTo(max(int64(float64(u.CPUMillis)*rightSizeLimitHeadroom), r.CPURequest*2), 5)
+	}
+	return r
+}
+
+// needsResize reports whether any recommended value differs from the current
+// one by more than rightSizeMinChange, or sets a value that is missing.
+func needsResize(r resourceRecommendation) bool {
+	differs := func(current, recommended int64) bool {
+		if recommended == 0 {
+			return false
+		}
+		if current == 0 {
+			return true
+		}
+		diff := float64(recommended-current) / float64(current)
+		return diff > rightSizeMinChange || diff < -rightSizeMinChange
+	}
+	u := r.Usage
+	return differs(u.CPURequest, r.CPURequest) || differs(u.CPULimit, r.CPULimit) ||
+		differs(u.MemRequest, r.MemRequest) || differs(u.MemLimit, r.MemLimit)
+}
+
+// groupRecommendations groups recommendations by workload, preserving order.
+func groupRecommendations(recs []resourceRecommendation) [][]resourceRecommendation {
+	var groups [][]resourceRecommendation
+	index := make(map[string]int)
+	for _, r := range recs {
+		key := r.Usage.Kind + "/" + r.Usage.Workload
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], r)
+	}
+	return groups
+}
+
+// resourcesYAML renders a container resources block.
+func resourcesYAML(r resourceRecommendation) string {
+	var sb strings.Builder
+	sb.WriteString("resources:\n  requests:\n")
+	sb.WriteString(fmt.Sprintf("    cpu: %dm\n    memory: %s\n", r.CPURequest, formatMemQuantity(r.MemRequest)))
+	sb.WriteString("  limits:\n")
+	if r.CPULimit > 0 {
+		sb.WriteString(fmt.Sprintf("    cpu: %dm\n", r.CPULimit))
+	}
+	sb.WriteString(fmt.Sprintf("    memory: %s\n", formatMemQuantity(r.MemLimit)))
+	return sb.String()
+}
+
+// rightSizePatchCommand returns a strategic-merge `kubectl patch` command that
+// applies every recommendation for one workload, or "" when the pods are not
+// owned by a Deployment, StatefulSet, or DaemonSet.
+func rightSizePatchCommand(namespace string, group []resourceRecommendation) string {
+	u := group[0].Usage
+	switch u.Kind {
+	case "Deployment", "StatefulSet", "DaemonSet":
+	default:
+		return ""
+	}
+	containers := make([]map[string]any, 0, len(group))
+	for _, r := range group {
+		limits := map[string]string{"memory": formatMemQuantity(r.MemLimit)}
+		if r.CPULimit > 0 {
+			limits["cpu"] = fmt.Sprintf("%dm", r.CPULimit)
+		}
+		containers = append(containers, map[string]any{
+			"name": r.Usage.Container,
+			"resources": map[string]any{
+				"requests": map[string]string{"cpu": fmt.Sprintf("%dm", r.CPURequest), "memory": formatMemQuantity(r.MemRequest)},
+				"limits":   limits,
+			},
+		})
+	}
+	patch, _ := json.Marshal(map[string]any{
+		"spec": map[string]any{"template": map[string]any{"spec": map[string]any{"containers": containers}}},
+	})
+	return fmt.Sprintf("kubectl -n %s patch %s %s --type=strategic -p '%s'", namespace, strings.ToLower(u.Kind), u.Workload, patch)
+}
+
+// formatMemQuantity formats bytes as a Kubernetes quantity in Mi, or "none" when unset.
+func formatMemQuantity(b int64) string {
+	if b == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%dMi", (b+(1<<20)-1)>>20)
+}
+
+// resizeChange renders a current → recommended change, or the value alone when unchanged.
+func resizeChange(current, recommended string) string {
+	if current == recommended {
+		return current
+	}
+	return current + " → " + recommended
+}
+
+// roundUpTo rounds v up to a multiple of step.
+func roundUpTo(v, step int64) int64 {
+	return (v + step - 1) / step * step
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecommendResources(t *testing.T) {
+	u := workloadUsage{
+		Kind: "Deployment", Workload: "web", Container: "app", HasSpec: true,
+		CPUMillis: 100, MemoryBytes: 200 << 20,
+		CPURequest: 1000, MemRequest: 1 << 30, MemLimit: 2 << 30,
+	}
+	r := recommendResources(u)
+	if r.CPURequest != 115 || r.MemRequest != 230<<20 || r.MemLimit != 300<<20 {
+		t.Errorf("unexpected recommendation %+v", r)
+	}
+	if r.CPULimit != 0 {
+		t.Errorf("expected no CPU limit for a container without one, got %dm", r.CPULimit)
+	}
+	if !needsResize(r) {
+		t.Error("expected an overprovisioned container to need resizing")
+	}
+
+	u.CPULimit = 2000
+	if r := recommendResources(u); r.CPULimit != 230 {
+		t.Errorf("expected CPU limit of twice the request, got %dm", r.CPULimit)
+	}
+
+	fit := recommendResources(workloadUsage{CPUMillis: 100, MemoryBytes: 200 << 20})
+	fit.Usage.CPURequest, fit.Usage.MemRequest, fit.Usage.MemLimit = 120, 225<<20, 310<<20
+	if needsResize(fit) {
+		t.Error("expected values within 10% to be left alone")
+	}
+
+	cmd := rightSizePatchCommand("shop", []resourceRecommendation{r})
+	want := `kubectl -n shop patch deployment web --type=strategic -p '{"spec":{"template":{"spec":{"containers":[{"name":"app","resources":{"limits":{"memory":"300Mi"},"requests":{"cpu":"115m","memory":"230Mi"}}}]}}}}'`
+	if cmd != want {
+		t.Errorf("unexpected patch command:\n got %s\nwant %s", cmd, want)
+	}
+	if !strings.Contains(resourcesYAML(r), "    cpu: 115m\n    memory: 230Mi\n") {
+		t.Errorf("unexpected YAML:\n%s", resourcesYAML(r))
+	}
+
+	r.Usage.Kind = "Job"
+	if cmd := rightSizePatchCommand("shop", []resourceRecommendation{r}); cmd != "" {
+		t.Errorf("expected no patch for Job pods, got %s", cmd)
+	}
+}
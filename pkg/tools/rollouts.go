@@ -0,0 +1,349 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// rolloutCorrelationWindow is how far before and after a rollout warning events are compared.
+	rolloutCorrelationWindow = 15 * time.Minute
+
+	// rolloutSpikeBucket is the bucket size used to find warning-event spikes.
+	rolloutSpikeBucket = 5 * time.Minute
+
+	// rolloutSpikeLookback is how long before a spike a rollout can start and still be blamed.
+	rolloutSpikeLookback = 30 * time.Minute
+
+	// rolloutLogErrorThreshold is the error lines per pod since a rollout that counts as an error-log spike.
+	rolloutLogErrorThreshold = 10
+
+	// revisionAnnotation is the Deployment revision recorded on each ReplicaSet.
+	revisionAnnotation = "deployment.kubernetes.io/revision"
+)
+
+type correlateRolloutsInput struct {
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Deployment     string `json:"deployment,omitempty" jsonschema:"Only consider rollouts of this Deployment"`
+	Window         string `json:"window,omitempty" jsonschema:"How far back to look for rollouts, as a Go duration (default 6h)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// rollout is a Deployment revision that started within the analysis window.
+type rollout struct {
+	Deployment string
+	Revision   string
+	ReplicaSet string
+	Started    time.Time
+	Image      string
+
+	WarningsBefore int
+	WarningsAfter  int
+	Related        int
+	LogErrors      int
+	LogPods        int
+	Verdict        string
+}
+
+// warningSpike is a bucket of warning events well above the preceding baseline.
+type warningSpike struct {
+	Start time.Time
+	Count int
+}
+
+func registerRolloutTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// correlate_rollouts
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "correlate_rollouts",
+		Description: "Correlate recent Deployment rollouts with warning-event spikes and error-log spikes in a namespace. For each revision rolled out in the window, compares warning events before vs after the rollout and error lines in the new pods, and reports whether the rollout is a likely cause. Answers questions like 'did the 14:32 deploy cause this?'.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input correlateRolloutsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if input.Namespace == "" {
+			return util.ErrorResult("namespace is required"), nil, nil
+		}
+		window := 6 * time.Hour
+		if input.Window != "" {
+			d, err := time.ParseDuration(input.Window)
+			if err != nil || d <= 0 {
+				return util.ErrorResult("invalid window %q: use a Go duration such as 2h or 30m", input.Window), nil, nil
+			}
+			window = d
+		}
+		now := time.Now()
+
+		replicaSets, err := client.ListReplicaSets(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing replicasets", err), nil, nil
+		}
+		rollouts := findRollouts(replicaSets, input.Deployment, now.Add(-window))
+
+		events, err := client.ListEventsMatching(ctx, input.Namespace, metav1.ListOptions{FieldSelector: "type=Warning"},
+			k8s.EventFilter{Since: now.Add(-window - rolloutCorrelationWindow), Limit: 1000})
+		if err != nil {
+			return util.HandleK8sError("listing events", err), nil, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Rollout Correlation: %s (last %s)", input.Namespace, window)))
+		sb.WriteString("\n\n")
+
+		if len(rollouts) == 0 {
+			sb.WriteString(fmt.Sprintf("No Deployment rollouts in the last %s.\n", window))
+			sb.WriteString(fmt.Sprintf("%s in the same period — recent problems are not explained by a rollout.\n", util.FormatCount("warning events", len(events))))
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		pods, podErr := client.ListPods(ctx, input.Namespace, metav1.ListOptions{})
+		for i := range rollouts {
+			r := &rollouts[i]
+			r.WarningsBefore, r.WarningsAfter, r.Related = countWarningsAroundRollout(*r, events)
+			if podErr == nil {
+				r.LogErrors, r.LogPods = countRolloutLogErrors(ctx, client, pods, *r, now)
+			}
+			r.Verdict = rolloutVerdict(*r)
+		}
+
+		// --- Rollout timeline ---
+		sb.WriteString(util.FormatSubHeader("Rollout Timeline"))
+		sb.WriteString("\n")
+		headers := []string{"STARTED", "DEPLOYMENT", "REV", "IMAGE", "WARN -15m", "WARN +15m", "RELATED", "LOG ERR/POD", "VERDICT"}
+		rows := make([][]string, 0, len(rollouts))
+		for _, r := range rollouts {
+			logCol := "-"
+			if r.LogPods > 0 {
+				logCol = fmt.Sprintf("%.1f", float64(r.LogErrors)/float64(r.LogPods))
+			}
+			rows = append(rows, []string{
+				r.Started.Local().Format("01-02 15:04"),
+				r.Deployment,
+				r.Revision,
+				truncateName(r.Image, 40),
+				fmt.Sprintf("%d", r.WarningsBefore),
+				fmt.Sprintf("%d", r.WarningsAfter),
+				fmt.Sprintf("%d", r.Related),
+				logCol,
+				r.Verdict,
+			})
+		}
+		sb.WriteString(util.FormatTable(headers, rows))
+
+		// --- Warning spikes ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Warning Event Spikes"))
+		sb.WriteString("\n")
+		spikes := findWarningSpikes(events, now.Add(-window), now)
+		if len(spikes) == 0 {
+			sb.WriteString("  No warning-event spikes detected.\n")
+		}
+		for _, s := range spikes {
+			line := fmt.Sprintf("%d warning events in the %s starting %s", s.Count, rolloutSpikeBucket, s.Start.Local().Format("01-02 15:04"))
+			if r := rolloutBeforeSpike(rollouts, s); r != nil {
+				sb.WriteString(findings.Format("KD-WL-007", "WARNING", fmt.Sprintf("%s — began %s after %s revision %s rolled out",
+					line, s.Start.Sub(r.Started).Round(time.Minute), r.Deployment, r.Revision)))
+			} else {
+				sb.WriteString(findings.Format("KD-WL-007", "INFO", fmt.Sprintf("%s — no rollout in the preceding %s", line, rolloutSpikeLookback)))
+			}
+			sb.WriteString("\n")
+		}
+
+		// --- Findings ---
+		var actions []string
+		sb.WriteString("\nFINDINGS:\n")
+		likely := 0
+		for _, r := range rollouts {
+			switch r.Verdict {
+			case "LIKELY CAUSE":
+				likely++
+				sb.WriteString(findings.Format("KD-WL-007", "CRITICAL", fmt.Sprintf("%s revision %s (%s) is a likely cause: warnings went from %d to %d in the %s after rollout, %d from its own pods",
+					r.Deployment, r.Revision, r.Started.Local().Format("15:04"), r.WarningsBefore, r.WarningsAfter, rolloutCorrelationWindow, r.Related)))
+				sb.WriteString("\n")
+				actions = append(actions, fmt.Sprintf("Compare %s revision %s with the previous revision (get_deployment_detail) and consider `kubectl rollout undo deployment/%s -n %s`",
+					r.Deployment, r.Revision, r.Deployment, input.Namespace))
+			case "POSSIBLE":
+				sb.WriteString(findings.Format("KD-WL-007", "WARNING", fmt.Sprintf("%s revision %s (%s) may be related: %d→%d warnings, %d error log lines across %d new pod(s)",
+					r.Deployment, r.Revision, r.Started.Local().Format("15:04"), r.WarningsBefore, r.WarningsAfter, r.LogErrors, r.LogPods)))
+				sb.WriteString("\n")
+				actions = append(actions, fmt.Sprintf("Review logs of the new %s pods (ReplicaSet %s)", r.Deployment, r.ReplicaSet))
+			}
+		}
+		if likely == 0 && len(actions) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "No rollout in the window correlates with a rise in warnings or errors"))
+			sb.WriteString("\n")
+		}
+
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// findRollouts returns Deployment-owned ReplicaSets created since the cutoff,
+// oldest first. If deployment is set, only its ReplicaSets are returned.
+func findRollouts(replicaSets []appsv1.ReplicaSet, deployment string, since time.Time) []rollout {
+	var out []rollout
+	for _, rs := range replicaSets {
+		if rs.CreationTimestamp.Time.Before(since) {
+			continue
+		}
+		owner := ""
+		for _, ref := range rs.OwnerReferences {
+			if ref.Kind == "Deployment" {
+				owner = ref.Name
+			}
+		}
+		if owner == "" || (deployment != "" && owner != deployment) {
+			continue
+		}
+		image := ""
+		if len(rs.Spec.Template.Spec.Containers) > 0 {
+			image = rs.Spec.Template.Spec.Containers[0].Image
+		}
+		out = append(out, rollout{
+			Deployment: owner,
+			Revision:   rs.Annotations[revisionAnnotation],
+			ReplicaSet: rs.Name,
+			Started:    rs.CreationTimestamp.Time,
+			Image:      image,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Started.Before(out[j].Started) })
+	return out
+}
+
+// eventFirstSeen returns when an event series started.
+func eventFirstSeen(e *corev1.Event) time.Time {
+	if !e.FirstTimestamp.IsZero() {
+		return e.FirstTimestamp.Time
+	}
+	return k8s.EventTime(e)
+}
+
+// countWarningsAroundRollout counts warning event series that started in the
+// correlation window before and after the rollout, and how many of the later
+// ones involve the rollout's own ReplicaSet or pods.
+func countWarningsAroundRollout(r rollout, events []corev1.Event) (before, after, related int) {
+	for i := range events {
+		e := &events[i]
+		t := eventFirstSeen(e)
+		switch {
+		case !t.Before(r.Started.Add(-rolloutCorrelationWindow)) && t.Before(r.Started):
+			before++
+		case !t.Before(r.Started) && t.Before(r.Started.Add(rolloutCorrelationWindow)):
+			after++
+			if strings.HasPrefix(e.InvolvedObject.Name, r.ReplicaSet) || e.InvolvedObject.Name == r.Deployment {
+				related++
+			}
+		}
+	}
+	return before, after, related
+}
+
+// countRolloutLogErrors counts error lines logged since the rollout by up to
+// three pods of the rollout's ReplicaSet.
+func countRolloutLogErrors(ctx context.Context, client *k8s.ClusterClient, pods []corev1.Pod, r rollout, now time.Time) (int, int) {
+	errors, sampled := 0, 0
+	since := now.Sub(r.Started).Round(time.Second).String()
+	for i := range pods {
+		p := &pods[i]
+		if sampled >= 3 || p.Status.Phase != corev1.PodRunning || len(p.Spec.Containers) == 0 {
+			continue
+		}
+		owned := false
+		for _, ref := range p.OwnerReferences {
+			if ref.Kind == "ReplicaSet" && ref.Name == r.ReplicaSet {
+				owned = true
+			}
+		}
+		if !owned {
+			continue
+		}
+		logs, err := client.GetPodLogs(ctx, p.Namespace, p.Name, p.Spec.Containers[0].Name, 500, false, since)
+		if err != nil {
+			continue
+		}
+		errors += len(extractLogErrors(logs))
+		sampled++
+	}
+	return errors, sampled
+}
+
+// rolloutVerdict classifies how likely a rollout is to have caused the observed problems.
+func rolloutVerdict(r rollout) string {
+	baseline := r.WarningsBefore
+	if baseline < 1 {
+		baseline = 1
+	}
+	switch {
+	case r.WarningsAfter >= 3 && r.WarningsAfter >= 3*baseline:
+		return "LIKELY CAUSE"
+	case r.Related > 0 && r.WarningsAfter > r.WarningsBefore:
+		return "LIKELY CAUSE"
+	case r.WarningsAfter > r.WarningsBefore:
+		return "POSSIBLE"
+	case r.LogPods > 0 && r.LogErrors >= rolloutLogErrorThreshold*r.LogPods:
+		return "POSSIBLE"
+	}
+	return "UNLIKELY"
+}
+
+// findWarningSpikes buckets warning events by start time and returns buckets
+// with at least 3 events and 3x the average of all preceding buckets.
+func findWarningSpikes(events []corev1.Event, from, to time.Time) []warningSpike {
+	n := int(to.Sub(from)/rolloutSpikeBucket) + 1
+	buckets := make([]int, n)
+	for i := range events {
+		t := eventFirstSeen(&events[i])
+		if t.Before(from) || t.After(to) {
+			continue
+		}
+		buckets[int(t.Sub(from)/rolloutSpikeBucket)]++
+	}
+
+	var spikes []warningSpike
+	total := 0
+	for i, c := range buckets {
+		baseline := 1.0
+		if i > 0 && float64(total)/float64(i) > baseline {
+			baseline = float64(total) / float64(i)
+		}
+		if c >= 3 && float64(c) >= 3*baseline {
+			spikes = append(spikes, warningSpike{Start: from.Add(time.Duration(i) * rolloutSpikeBucket), Count: c})
+		}
+		total += c
+	}
+	return spikes
+}
+
+// rolloutBeforeSpike returns the most recent rollout that started within
+// rolloutSpikeLookback before the spike bucket ended.
+func rolloutBeforeSpike(rollouts []rollout, s warningSpike) *rollout {
+	var best *rollout
+	end := s.Start.Add(rolloutSpikeBucket)
+	for i := range rollouts {
+		r := &rollouts[i]
+		if r.Started.After(end) || r.Started.Before(s.Start.Add(-rolloutSpikeLookback)) {
+			continue
+		}
+		if best == nil || r.Started.After(best.Started) {
+			best = r
+		}
+	}
+	return best
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCorrelateRollout(t *testing.T) {
+	deployAt := time.Date(2024, 5, 1, 14, 32, 0, 0, time.UTC)
+
+	replicaSets := []appsv1.ReplicaSet{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "api-7f9c",
+				CreationTimestamp: metav1.NewTime(deployAt),
+				Annotations:       map[string]string{revisionAnnotation: "7"},
+				OwnerReferences:   []metav1.OwnerReference{{Kind: "Deployment", Name: "api"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "api-old",
+				CreationTimestamp: metav1.NewTime(deployAt.Add(-48 * time.Hour)),
+				OwnerReferences:   []metav1.OwnerReference{{Kind: "Deployment", Name: "api"}},
+			},
+		},
+	}
+	rollouts := findRollouts(replicaSets, "", deployAt.Add(-time.Hour))
+	if len(rollouts) != 1 || rollouts[0].Revision != "7" {
+		t.Fatalf("expected one rollout at revision 7, got %+v", rollouts)
+	}
+
+	warning := func(name string, at time.Time) corev1.Event {
+		return corev1.Event{
+			Type:           "Warning",
+			FirstTimestamp: metav1.NewTime(at),
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: name},
+		}
+	}
+	events := []corev1.Event{
+		warning("other-1", deployAt.Add(-10*time.Minute)),
+		warning("api-7f9c-abcde", deployAt.Add(2*time.Minute)),
+		warning("api-7f9c-fghij", deployAt.Add(3*time.Minute)),
+		warning("api-7f9c-klmno", deployAt.Add(3*time.Minute)),
+		warning("other-2", deployAt.Add(4*time.Minute)),
+	}
+
+	r := rollouts[0]
+	r.WarningsBefore, r.WarningsAfter, r.Related = countWarningsAroundRollout(r, events)
+	if r.WarningsBefore != 1 || r.WarningsAfter != 4 || r.Related != 3 {
+		t.Errorf("got before=%d after=%d related=%d, want 1/4/3", r.WarningsBefore, r.WarningsAfter, r.Related)
+	}
+	if v := rolloutVerdict(r); v != "LIKELY CAUSE" {
+		t.Errorf("rolloutVerdict() = %q, want LIKELY CAUSE", v)
+	}
+
+	spikes := findWarningSpikes(events, deployAt.Add(-time.Hour), deployAt.Add(time.Hour))
+	if len(spikes) != 1 {
+		t.Fatalf("expected 1 spike, got %+v", spikes)
+	}
+	if got := rolloutBeforeSpike(rollouts, spikes[0]); got == nil || got.Revision != "7" {
+		t.Errorf("expected spike to be attributed to revision 7, got %+v", got)
+	}
+}
+
+func TestRolloutVerdictQuiet(t *testing.T) {
+	if v := rolloutVerdict(rollout{WarningsBefore: 2, WarningsAfter: 1}); v != "UNLIKELY" {
+		t.Errorf("rolloutVerdict() = %q, want UNLIKELY", v)
+	}
+	if v := rolloutVerdict(rollout{LogErrors: 40, LogPods: 2}); v != "POSSIBLE" {
+		t.Errorf("rolloutVerdict() = %q, want POSSIBLE for an error-log spike", v)
+	}
+}
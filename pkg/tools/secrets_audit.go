@@ -0,0 +1,424 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// secretLargeBytes is the size above which a Secret is reported as very large.
+	secretLargeBytes = 256 << 10
+
+	// secretCopyNamespaces is the number of namespaces holding a same-named
+	// Secret that counts as copy sprawl.
+	secretCopyNamespaces = 3
+
+	// tlsExpiryWarning and tlsExpiryInfo are how close to expiry a TLS
+	// certificate must be to raise a WARNING or INFO finding.
+	tlsExpiryWarning = 14 * 24 * time.Hour
+	tlsExpiryInfo    = 30 * 24 * time.Hour
+
+	// ecrTokenLifetime and gcrTokenLifetime are how long ECR and GCR access
+	// tokens stay valid after they are issued.
+	ecrTokenLifetime = 12 * time.Hour
+	gcrTokenLifetime = time.Hour
+)
+
+// secretAuditSkippedTypes are Secret types managed by Kubernetes or tooling
+// that are not consumed through pod references.
+var secretAuditSkippedTypes = map[corev1.SecretType]bool{
+	corev1.SecretTypeServiceAccountToken: true,
+	corev1.SecretTypeBootstrapToken:      true,
+	"helm.sh/release.v1":                 true,
+}
+
+// ecrRegistry matches Amazon ECR registry hosts.
+var ecrRegistry = regexp.MustCompile(`\.dkr\.ecr\.[a-z0-9-]+\.amazonaws\.com`)
+
+type auditSecretsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces)"`
+	IncludeSystem  bool   `json:"include_system,omitempty" jsonschema:"Also report unused Secrets in kube-system, kube-public, and kube-node-lease"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// secretIssue is one hygiene problem with a Secret. Category is one of
+// unused, large, copies, tls, or registry.
+type secretIssue struct {
+	Category  string
+	Severity  string
+	Namespace string
+	Name      string
+	Message   string
+}
+
+func registerSecretAuditTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "audit_secrets",
+		Description: "Audit Secret hygiene from metadata and certificate/registry parsing — values are never printed. Reports Secrets no pod, " +
+			"workload, ServiceAccount, or Ingress references; very large Secrets (>256KiB); Secrets copied under the same name into many " +
+			"namespaces (with how many distinct versions exist); dockerconfigjson credentials whose registry tokens have expired " +
+			"(JWT exp, ECR 12h and GCR 1h access tokens); and TLS certificates that are expired or expire within 30 days.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input auditSecretsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+
+		secrets, err := client.ListSecrets(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing secrets", err), nil, nil
+		}
+		consumers, err := collectConfigConsumers(ctx, client, ns)
+		if err != nil {
+			return util.HandleK8sError("listing secret consumers", err), nil, nil
+		}
+		if sas, err := client.ListServiceAccounts(ctx, ns, metav1.ListOptions{}); err == nil {
+			for _, sa := range sas {
+				for _, ref := range sa.ImagePullSecrets {
+					consumers.addSecret(sa.Namespace, ref.Name, "ServiceAccount/"+sa.Name+" (imagePullSecrets)")
+				}
+				for _, ref := range sa.Secrets {
+					consumers.addSecret(sa.Namespace, ref.Name, "ServiceAccount/"+sa.Name)
+				}
+			}
+		}
+		if ingresses, err := client.ListIngresses(ctx, ns, metav1.ListOptions{}); err == nil {
+			for _, ing := range ingresses {
+				for _, tls := range ing.Spec.TLS {
+					consumers.addSecret(ing.Namespace, tls.SecretName, "Ingress/"+ing.Name+" (tls)")
+				}
+				for k, v := range ing.Annotations {
+					if strings.Contains(strings.ToLower(k), "secret") {
+						refNS, refName := ing.Namespace, v
+						if before, after, ok := strings.Cut(v, "/"); ok {
+							refNS, refName = before, after
+						}
+						consumers.addSecret(refNS, refName, "Ingress/"+ing.Name+" ("+k+")")
+					}
+				}
+			}
+		}
+
+		issues := auditSecrets(secrets, consumers, input.IncludeSystem || configMapSystemNamespaces[ns], time.Now())
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Secret Hygiene Audit (scope: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+		sb.WriteString(fmt.Sprintf("Audited %d Secrets. Secret values are never included in this report.\n\n", len(secrets)))
+
+		sb.WriteString("FINDINGS:\n")
+		if len(issues) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "No unused, oversized, sprawling, expired, or expiring Secrets found"))
+			sb.WriteString("\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+		counts := make(map[string]int)
+		for _, is := range issues {
+			counts[is.Severity]++
+			sb.WriteString(findings.Format("KD-SEC-013", is.Severity, fmt.Sprintf("%s/%s: %s", is.Namespace, is.Name, is.Message)))
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("\nSUGGESTED ACTIONS:\n")
+		actions := []string{}
+		if counts["CRITICAL"] > 0 {
+			actions = append(actions, "Renew expired certificates and registry credentials first: pods using them fail TLS handshakes or ImagePullBackOff on the next pull")
+		}
+		for _, is := range issues {
+			switch is.Category {
+			case "tls":
+				actions = append(actions, "Check cert-manager Certificate status (kubectl get certificate -A) or renew manually; a renewal that never happened usually means a failing ACME challenge or issuer")
+			case "registry":
+				actions = append(actions, "Replace short-lived registry tokens with a refresher (ECR credential helper CronJob, workload identity, or kubelet credential providers)")
+			case "copies":
+				actions = append(actions, "Replace hand-copied Secrets with a single source synced by External Secrets Operator or a reflector, so rotations reach every copy")
+			case "unused":
+				actions = append(actions, "Confirm unused Secrets are not read through the API (operators, Flux/Argo source credentials) before deleting them")
+			case "large":
+				actions = append(actions, "Move large blobs out of Secrets (they are held in etcd and every watching client's memory); mount them from a volume or object storage")
+			}
+		}
+		for i, a := range dedupe(actions) {
+			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// addSecret records a consumer of a Secret outside pod specs.
+func (c *configConsumers) addSecret(namespace, name, consumer string) {
+	if name == "" {
+		return
+	}
+	key := namespace + "/Secret/" + name
+	c.refs[key] = append(c.refs[key], consumer)
+}
+
+// auditSecrets returns the hygiene issues of a set of Secrets, most severe first.
+func auditSecrets(secrets []corev1.Secret, consumers configConsumers, includeSystem bool, now time.Time) []secretIssue {
+	var issues []secretIssue
+	copies := make(map[string]map[string]string) // name -> namespace -> content hash
+
+	for i := range secrets {
+		s := &secrets[i]
+		if secretAuditSkippedTypes[s.Type] {
+			continue
+		}
+		add := func(category, severity, msg string) {
+			issues = append(issues, secretIssue{Category: category, Severity: severity, Namespace: s.Namespace, Name: s.Name, Message: msg})
+		}
+
+		size := secretSize(s)
+		if size > secretLargeBytes {
+			add("large", "WARNING", fmt.Sprintf("very large Secret (%s across %d keys)", formatBytes(size), len(s.Data)))
+		}
+
+		if !configMapSystemNamespaces[s.Namespace] || includeSystem {
+			if len(s.OwnerReferences) == 0 && len(consumers.of(s.Namespace, "Secret", s.Name)) == 0 {
+				add("unused", "INFO", fmt.Sprintf("%s Secret is not referenced by any pod, workload, ServiceAccount, or Ingress", secretTypeLabel(s.Type)))
+			}
+		}
+
+		switch s.Type {
+		case corev1.SecretTypeTLS:
+			if notAfter, subject, err := certificateExpiry(s.Data[corev1.TLSCertKey]); err != nil {
+				add("tls", "WARNING", fmt.Sprintf("tls.crt could not be parsed: %v", err))
+			} else if msg, severity := certificateExpiryMessage(subject, notAfter, now); severity != "" {
+				add("tls", severity, msg)
+			}
+		case corev1.SecretTypeDockerConfigJson, corev1.SecretTypeDockercfg:
+			for _, exp := range registryCredentialExpiries(s, now) {
+				add("registry", exp.severity, exp.message)
+			}
+		}
+
+		if copies[s.Name] == nil {
+			copies[s.Name] = make(map[string]string)
+		}
+		copies[s.Name][s.Namespace] = secretContentHash(s)
+	}
+
+	for _, name := range sortedCopyNames(copies) {
+		byNS := copies[name]
+		if len(byNS) < secretCopyNamespaces {
+			continue
+		}
+		versions := make(map[string]bool)
+		namespaces := make([]string, 0, len(byNS))
+		for ns, h := range byNS {
+			versions[h] = true
+			namespaces = append(namespaces, ns)
+		}
+		sort.Strings(namespaces)
+		severity, drift := "INFO", ""
+		if len(versions) > 1 {
+			severity, drift = "WARNING", fmt.Sprintf("; the copies hold %d different versions, so some namespaces missed a rotation", len(versions))
+		}
+		issues = append(issues, secretIssue{Category: "copies", Severity: severity, Namespace: "*", Name: name,
+			Message: fmt.Sprintf("copied into %d namespaces (%s)%s", len(byNS), joinLimited(namespaces, 5), drift)})
+	}
+
+	rank := map[string]int{"CRITICAL": 0, "WARNING": 1, "INFO": 2}
+	sort.SliceStable(issues, func(i, j int) bool { return rank[issues[i].Severity] < rank[issues[j].Severity] })
+	return issues
+}
+
+// certificateExpiry returns the expiry and subject of the first certificate in PEM data.
+func certificateExpiry(data []byte) (time.Time, string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, "", fmt.Errorf("no PEM certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	subject := cert.Subject.CommonName
+	if len(cert.DNSNames) > 0 {
+		subject = cert.DNSNames[0]
+		if len(cert.DNSNames) > 1 {
+			subject += fmt.Sprintf(" (+%d SANs)", len(cert.DNSNames)-1)
+		}
+	}
+	return cert.NotAfter, subject, nil
+}
+
+// certificateExpiryMessage describes a certificate that is expired or close to
+// expiry, or returns an empty severity when it has more than 30 days left.
+func certificateExpiryMessage(subject string, notAfter, now time.Time) (string, string) {
+	left := notAfter.Sub(now)
+	switch {
+	case left <= 0:
+		return fmt.Sprintf("TLS certificate for %s expired %d days ago (%s)", subject, int(-left.Hours()/24), notAfter.Format("2006-01-02")), "CRITICAL"
+	case left < tlsExpiryWarning:
+		return fmt.Sprintf("TLS certificate for %s will expire in %d days (%s)", subject, int(left.Hours()/24), notAfter.Format("2006-01-02")), "WARNING"
+	case left < tlsExpiryInfo:
+		return fmt.Sprintf("TLS certificate for %s will expire in %d days (%s)", subject, int(left.Hours()/24), notAfter.Format("2006-01-02")), "INFO"
+	}
+	return "", ""
+}
+
+// registryExpiry is an expired or short-lived registry credential.
+type registryExpiry struct {
+	severity string
+	message  string
+}
+
+// dockerAuth is one registry entry of a docker config Secret.
+type dockerAuth struct {
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	Auth          string `json:"auth"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+// registryCredentialExpiries returns the registries in a docker config Secret
+// whose credentials have expired: JWT passwords or identity tokens past their
+// exp claim, and ECR or GCR access tokens older than their fixed lifetime.
+func registryCredentialExpiries(s *corev1.Secret, now time.Time) []registryExpiry {
+	auths := make(map[string]dockerAuth)
+	if raw, ok := s.Data[corev1.DockerConfigJsonKey]; ok {
+		var cfg struct {
+			Auths map[string]dockerAuth `json:"auths"`
+		}
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return []registryExpiry{{"WARNING", "docker config JSON could not be parsed; image pulls using it will fail"}}
+		}
+		auths = cfg.Auths
+	} else if raw, ok := s.Data[corev1.DockerConfigKey]; ok {
+		if err := json.Unmarshal(raw, &auths); err != nil {
+			return []registryExpiry{{"WARNING", "docker config could not be parsed; image pulls using it will fail"}}
+		}
+	}
+
+	updated := objectLastModified(s.ObjectMeta)
+	var out []registryExpiry
+	for _, registry := range sortedAuthRegistries(auths) {
+		a := auths[registry]
+		if a.Username == "" && a.Auth != "" {
+			if decoded, err := base64.StdEncoding.DecodeString(a.Auth); err == nil {
+				a.Username, a.Password, _ = strings.Cut(string(decoded), ":")
+			}
+		}
+
+		var expires time.Time
+		kind := "token"
+		for _, tok := range []string{a.IdentityToken, a.Password} {
+			if exp, ok := jwtExpiry(tok); ok {
+				expires, kind = exp, "JWT token"
+				break
+			}
+		}
+		if expires.IsZero() {
+			switch {
+			case a.Username == "AWS" && ecrRegistry.MatchString(registry):
+				expires, kind = updated.Add(ecrTokenLifetime), "ECR token (12h lifetime)"
+			case a.Username == "oauth2accesstoken":
+				expires, kind = updated.Add(gcrTokenLifetime), "GCR access token (1h lifetime)"
+			default:
+				continue
+			}
+		}
+		if now.After(expires) {
+			out = append(out, registryExpiry{"CRITICAL", fmt.Sprintf("%s for registry %s expired %s (%s); new image pulls will fail",
+				kind, registry, expires.Format(time.RFC3339), now.Sub(expires).Round(time.Minute))})
+		} else if expires.Sub(now) < 24*time.Hour {
+			out = append(out, registryExpiry{"WARNING", fmt.Sprintf("%s for registry %s will expire at %s", kind, registry, expires.Format(time.RFC3339))})
+		}
+	}
+	return out
+}
+
+// jwtExpiry returns the exp claim of a JWT, or false if tok is not a JWT with one.
+func jwtExpiry(tok string) (time.Time, bool) {
+	parts := strings.Split(tok, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(claims.Exp), 0), true
+}
+
+// secretSize returns the bytes of keys and values stored in a Secret.
+func secretSize(s *corev1.Secret) int64 {
+	var size int64
+	for k, v := range s.Data {
+		size += int64(len(k) + len(v))
+	}
+	return size
+}
+
+// secretContentHash fingerprints a Secret's data so copies can be compared
+// without revealing values.
+func secretContentHash(s *corev1.Secret) string {
+	keys := make([]string, 0, len(s.Data))
+	for k := range s.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(s.Data[k])
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// secretTypeLabel shortens a Secret type for messages.
+func secretTypeLabel(t corev1.SecretType) string {
+	switch t {
+	case "", corev1.SecretTypeOpaque:
+		return "Opaque"
+	case corev1.SecretTypeTLS:
+		return "TLS"
+	case corev1.SecretTypeDockerConfigJson, corev1.SecretTypeDockercfg:
+		return "Registry"
+	}
+	return string(t)
+}
+
+// sortedCopyNames returns the Secret names of a copies map in sorted order.
+func sortedCopyNames(m map[string]map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedAuthRegistries returns the registries of a docker config in sorted order.
+func sortedAuthRegistries(m map[string]dockerAuth) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testCertPEM(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "shop.example.com"},
+		DNSNames:     []string{"shop.example.com"},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestAuditSecrets(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	consumers := configConsumers{refs: make(map[string][]string), args: make(map[string][]string)}
+	consumers.addSecret("shop", "shop-tls", "Ingress/shop (tls)")
+	consumers.addSecret("shop", "ecr-pull", "ServiceAccount/default (imagePullSecrets)")
+
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, now.Add(-time.Hour).Unix())))
+	acrAuth := fmt.Sprintf(`{"auths":{"shop.azurecr.io":{"username":"00000000-0000-0000-0000-000000000000","password":"eyJhbGciOiJSUzI1NiJ9.%s.sig"}}}`, claims)
+	ecrAuth := base64.StdEncoding.EncodeToString([]byte("AWS:token"))
+
+	secret := func(ns, name string, typ corev1.SecretType, data map[string][]byte) corev1.Secret {
+		return corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour))},
+			Type:       typ,
+			Data:       data,
+		}
+	}
+	secrets := []corev1.Secret{
+		secret("shop", "shop-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: testCertPEM(t, now.Add(5*24*time.Hour))}),
+		secret("shop", "acr-pull", corev1.SecretTypeDockerConfigJson, map[string][]byte{corev1.DockerConfigJsonKey: []byte(acrAuth)}),
+		secret("shop", "ecr-pull", corev1.SecretTypeDockerConfigJson, map[string][]byte{corev1.DockerConfigJsonKey: []byte(
+			`{"auths":{"123456789012.dkr.ecr.us-east-1.amazonaws.com":{"auth":"` + ecrAuth + `"}}}`)}),
+		secret("shop", "sa-token", corev1.SecretTypeServiceAccountToken, nil),
+		secret("a", "shared", corev1.SecretTypeOpaque, map[string][]byte{"k": []byte("v1")}),
+		secret("b", "shared", corev1.SecretTypeOpaque, map[string][]byte{"k": []byte("v1")}),
+		secret("c", "shared", corev1.SecretTypeOpaque, map[string][]byte{"k": []byte("v2")}),
+	}
+	for i := 4; i < 7; i++ {
+		consumers.addSecret(secrets[i].Namespace, "shared", "Pod/app")
+	}
+
+	issues := auditSecrets(secrets, consumers, false, now)
+	byKey := make(map[string]secretIssue)
+	for _, is := range issues {
+		byKey[is.Category+":"+is.Name] = is
+	}
+
+	if is, ok := byKey["tls:shop-tls"]; !ok || is.Severity != "WARNING" || !strings.Contains(is.Message, "expire in 5 days") {
+		t.Errorf("expected TLS expiry warning, got %+v", is)
+	}
+	if is, ok := byKey["registry:acr-pull"]; !ok || is.Severity != "CRITICAL" || !strings.Contains(is.Message, "JWT token") {
+		t.Errorf("expected expired JWT registry credential, got %+v", is)
+	}
+	if is, ok := byKey["registry:ecr-pull"]; !ok || !strings.Contains(is.Message, "ECR token") {
+		t.Errorf("expected expired ECR token, got %+v", is)
+	}
+	if _, ok := byKey["unused:acr-pull"]; !ok {
+		t.Error("expected unreferenced acr-pull to be reported unused")
+	}
+	if _, ok := byKey["unused:sa-token"]; ok {
+		t.Error("expected service account tokens to be skipped")
+	}
+	if is, ok := byKey["copies:shared"]; !ok || is.Severity != "WARNING" || !strings.Contains(is.Message, "2 different versions") {
+		t.Errorf("expected copy sprawl with drift, got %+v", is)
+	}
+	if issues[0].Severity != "CRITICAL" {
+		t.Errorf("expected issues sorted by severity, got %s first", issues[0].Severity)
+	}
+	for _, is := range issues {
+		if strings.Contains(is.Message, "token\"") || strings.Contains(is.Message, "v1") {
+			t.Errorf("secret value leaked into %q", is.Message)
+		}
+	}
+}
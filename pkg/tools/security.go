@@ -8,6 +8,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
 )
@@ -20,10 +21,13 @@ type analyzePodSecurityInput struct {
 type listRBACBindingsInput struct {
 	Namespace     string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
 	SubjectFilter string `json:"subject_filter,omitempty" jsonschema:"Filter by subject name (user, group, or service account)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum bindings per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type auditNamespaceSecurityInput struct {
-	Namespace string `json:"namespace" jsonschema:"required,Kubernetes namespace to audit"`
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace to audit"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
 }
 
 func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
@@ -41,7 +45,7 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Pod Security Analysis: %s (namespace: %s)", pod.Name, pod.Namespace)))
 		sb.WriteString("\n\n")
 
-		findings := 0
+		findingCount := 0
 		var actions []string
 
 		// Pod-level security context
@@ -49,48 +53,48 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 
 		if pod.Spec.HostNetwork {
-			sb.WriteString(util.FormatFinding("CRITICAL", "Pod uses hostNetwork — shares node's network namespace"))
+			sb.WriteString(findings.Format("KD-SEC-002", "CRITICAL", "Pod uses hostNetwork — shares node's network namespace"))
 			sb.WriteString("\n")
 			actions = append(actions, "Remove hostNetwork unless absolutely required (e.g., CNI plugins)")
-			findings++
+			findingCount++
 		}
 		if pod.Spec.HostPID {
-			sb.WriteString(util.FormatFinding("CRITICAL", "Pod uses hostPID — can see all processes on the node"))
+			sb.WriteString(findings.Format("KD-SEC-002", "CRITICAL", "Pod uses hostPID — can see all processes on the node"))
 			sb.WriteString("\n")
 			actions = append(actions, "Remove hostPID to prevent process visibility across the node")
-			findings++
+			findingCount++
 		}
 		if pod.Spec.HostIPC {
-			sb.WriteString(util.FormatFinding("WARNING", "Pod uses hostIPC — shares node's IPC namespace"))
+			sb.WriteString(findings.Format("KD-SEC-002", "WARNING", "Pod uses hostIPC — shares node's IPC namespace"))
 			sb.WriteString("\n")
 			actions = append(actions, "Remove hostIPC unless inter-process communication with host is required")
-			findings++
+			findingCount++
 		}
 
 		podSC := pod.Spec.SecurityContext
 		if podSC != nil {
 			if podSC.RunAsUser != nil && *podSC.RunAsUser == 0 {
-				sb.WriteString(util.FormatFinding("CRITICAL", "Pod runAsUser is 0 (root)"))
+				sb.WriteString(findings.Format("KD-SEC-003", "CRITICAL", "Pod runAsUser is 0 (root)"))
 				sb.WriteString("\n")
 				actions = append(actions, "Set runAsUser to a non-zero UID (e.g., 1000)")
-				findings++
+				findingCount++
 			}
 			if podSC.RunAsNonRoot != nil && !*podSC.RunAsNonRoot {
-				sb.WriteString(util.FormatFinding("WARNING", "Pod runAsNonRoot is explicitly set to false"))
+				sb.WriteString(findings.Format("KD-SEC-003", "WARNING", "Pod runAsNonRoot is explicitly set to false"))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			}
 			if podSC.SeccompProfile != nil {
 				sb.WriteString(fmt.Sprintf("  Seccomp Profile: %s\n", podSC.SeccompProfile.Type))
 			} else {
-				sb.WriteString(util.FormatFinding("INFO", "No seccomp profile set at pod level"))
+				sb.WriteString(findings.Format("KD-SEC-014", "INFO", "No seccomp profile set at pod level"))
 				sb.WriteString("\n")
-				findings++
+				findingCount++
 			}
 		} else {
-			sb.WriteString(util.FormatFinding("INFO", "No pod-level SecurityContext defined"))
+			sb.WriteString(findings.Format("KD-SEC-007", "INFO", "No pod-level SecurityContext defined"))
 			sb.WriteString("\n")
-			findings++
+			findingCount++
 		}
 
 		// Container-level security contexts
@@ -103,28 +107,28 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 			sb.WriteString(fmt.Sprintf("\n  Container: %s\n", c.Name))
 			sc := c.SecurityContext
 			if sc == nil {
-				sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("WARNING", "No SecurityContext defined")))
+				sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-SEC-015", "WARNING", "No SecurityContext defined")))
 				actions = append(actions, fmt.Sprintf("Add SecurityContext to container '%s'", c.Name))
-				findings++
+				findingCount++
 				continue
 			}
 
 			if sc.Privileged != nil && *sc.Privileged {
-				sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("CRITICAL", "Container runs in privileged mode")))
+				sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-SEC-001", "CRITICAL", "Container runs in privileged mode")))
 				actions = append(actions, fmt.Sprintf("Remove privileged mode from container '%s'", c.Name))
-				findings++
+				findingCount++
 			}
 			if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
-				sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("WARNING", "allowPrivilegeEscalation is not explicitly disabled")))
-				findings++
+				sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-SEC-004", "WARNING", "allowPrivilegeEscalation is not explicitly disabled")))
+				findingCount++
 			}
 			if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
-				sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("CRITICAL", "Container runAsUser is 0 (root)")))
-				findings++
+				sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-SEC-003", "CRITICAL", "Container runAsUser is 0 (root)")))
+				findingCount++
 			}
 			if sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
-				sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("INFO", "readOnlyRootFilesystem is not enabled")))
-				findings++
+				sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-SEC-016", "INFO", "readOnlyRootFilesystem is not enabled")))
+				findingCount++
 			}
 			if sc.Capabilities != nil {
 				if len(sc.Capabilities.Add) > 0 {
@@ -139,8 +143,8 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 							break
 						}
 					}
-					sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding(severity, fmt.Sprintf("Added capabilities: %s", strings.Join(caps, ", ")))))
-					findings++
+					sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-SEC-017", severity, fmt.Sprintf("Added capabilities: %s", strings.Join(caps, ", ")))))
+					findingCount++
 				}
 				if len(sc.Capabilities.Drop) > 0 {
 					caps := make([]string, 0, len(sc.Capabilities.Drop))
@@ -150,8 +154,8 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 					sb.WriteString(fmt.Sprintf("    Dropped capabilities: %s\n", strings.Join(caps, ", ")))
 				}
 			} else {
-				sb.WriteString(fmt.Sprintf("    %s\n", util.FormatFinding("INFO", "No capabilities configuration (consider dropping ALL and adding only needed)")))
-				findings++
+				sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-SEC-018", "INFO", "No capabilities configuration (consider dropping ALL and adding only needed)")))
+				findingCount++
 			}
 		}
 
@@ -159,10 +163,10 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Summary"))
 		sb.WriteString("\n")
-		if findings == 0 {
+		if findingCount == 0 {
 			sb.WriteString("  Pod security posture looks good. No issues found.\n")
 		} else {
-			sb.WriteString(fmt.Sprintf("  %d security finding(s) identified.\n", findings))
+			sb.WriteString(fmt.Sprintf("  %d security finding(s) identified.\n", findingCount))
 		}
 
 		if len(actions) > 0 {
@@ -180,53 +184,64 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Name:        "list_rbac_bindings",
 		Description: "List RBAC role bindings in a namespace showing subject → role mapping. Includes both RoleBindings and ClusterRoleBindings that apply. Optional subject filter to find bindings for a specific user, group, or service account.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listRBACBindingsInput) (*mcp.CallToolResult, any, error) {
-		// Get namespace-scoped role bindings
-		roleBindings, err := client.ListRoleBindings(ctx, input.Namespace, metav1.ListOptions{})
-		if err != nil {
-			return util.HandleK8sError("listing role bindings", err), nil, nil
-		}
-
 		headers := []string{"BINDING", "SCOPE", "ROLE", "SUBJECT-KIND", "SUBJECT-NAME", "SUBJECT-NS"}
 		rows := make([][]string, 0)
 
-		for _, rb := range roleBindings {
-			for _, subject := range rb.Subjects {
-				if input.SubjectFilter != "" && !strings.Contains(strings.ToLower(subject.Name), strings.ToLower(input.SubjectFilter)) {
-					continue
+		// Page through the namespace's RoleBindings, then the
+		// ClusterRoleBindings that may grant permissions in it.
+		next, remaining, err := pagePhases(util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken), []pagePhase{
+			{name: "namespace", list: func(opts metav1.ListOptions) (int, string, *int64, error) {
+				page, err := client.ListRoleBindingsPage(ctx, input.Namespace, opts)
+				if err != nil {
+					return 0, "", nil, err
 				}
-				rows = append(rows, []string{
-					rb.Name,
-					"Namespace",
-					fmt.Sprintf("%s/%s", rb.RoleRef.Kind, rb.RoleRef.Name),
-					subject.Kind,
-					subject.Name,
-					subject.Namespace,
-				})
-			}
-		}
-
-		// Also get cluster role bindings that may grant permissions in this namespace
-		clusterBindings, err := client.ListClusterRoleBindings(ctx, metav1.ListOptions{})
-		if err == nil {
-			for _, crb := range clusterBindings {
-				for _, subject := range crb.Subjects {
-					if input.SubjectFilter != "" && !strings.Contains(strings.ToLower(subject.Name), strings.ToLower(input.SubjectFilter)) {
-						continue
+				for _, rb := range page.Items {
+					for _, subject := range rb.Subjects {
+						if input.SubjectFilter != "" && !strings.Contains(strings.ToLower(subject.Name), strings.ToLower(input.SubjectFilter)) {
+							continue
+						}
+						rows = append(rows, []string{
+							rb.Name,
+							"Namespace",
+							fmt.Sprintf("%s/%s", rb.RoleRef.Kind, rb.RoleRef.Name),
+							subject.Kind,
+							subject.Name,
+							subject.Namespace,
+						})
 					}
-					// Include ClusterRoleBindings that reference ServiceAccounts in this namespace
-					if subject.Kind == "ServiceAccount" && subject.Namespace != "" && subject.Namespace != input.Namespace {
-						continue
+				}
+				return len(page.Items), page.Continue, page.Remaining, nil
+			}},
+			{name: "cluster", list: func(opts metav1.ListOptions) (int, string, *int64, error) {
+				page, err := client.ListClusterRoleBindingsPage(ctx, opts)
+				if err != nil {
+					// Supplementary: skipped when cluster-scoped reads are not allowed.
+					return 0, "", nil, nil
+				}
+				for _, crb := range page.Items {
+					for _, subject := range crb.Subjects {
+						if input.SubjectFilter != "" && !strings.Contains(strings.ToLower(subject.Name), strings.ToLower(input.SubjectFilter)) {
+							continue
+						}
+						// Include ClusterRoleBindings that reference ServiceAccounts in this namespace
+						if subject.Kind == "ServiceAccount" && subject.Namespace != "" && subject.Namespace != input.Namespace {
+							continue
+						}
+						rows = append(rows, []string{
+							crb.Name,
+							"Cluster",
+							fmt.Sprintf("%s/%s", crb.RoleRef.Kind, crb.RoleRef.Name),
+							subject.Kind,
+							subject.Name,
+							subject.Namespace,
+						})
 					}
-					rows = append(rows, []string{
-						crb.Name,
-						"Cluster",
-						fmt.Sprintf("%s/%s", crb.RoleRef.Kind, crb.RoleRef.Name),
-						subject.Kind,
-						subject.Name,
-						subject.Namespace,
-					})
 				}
-			}
+				return len(page.Items), page.Continue, page.Remaining, nil
+			}},
+		})
+		if err != nil {
+			return util.HandleK8sError("listing role bindings", err), nil, nil
 		}
 
 		var sb strings.Builder
@@ -237,6 +252,7 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 		}
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("bindings", len(rows))))
+		sb.WriteString(pageFooter("list_rbac_bindings", next, remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -246,12 +262,13 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Name:        "audit_namespace_security",
 		Description: "Comprehensive security audit for a namespace. Checks network policies, pod disruption budgets, pod security contexts, RBAC bindings, and resource quotas. Returns an overall security score and a Mermaid policy coverage diagram.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input auditNamespaceSecurityInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
 		var sb strings.Builder
 		sb.WriteString(util.FormatHeader(fmt.Sprintf("Namespace Security Audit: %s", input.Namespace)))
 		sb.WriteString("\n\n")
 
 		score := 100
-		findings := 0
+		findingCount := 0
 
 		// 1. Network Policies
 		sb.WriteString(util.FormatSubHeader("Network Policies"))
@@ -261,10 +278,10 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 		if err != nil {
 			sb.WriteString("  (could not check network policies)\n")
 		} else if len(netPols) == 0 {
-			sb.WriteString(util.FormatFinding("WARNING", "No network policies — all pod traffic is unrestricted"))
+			sb.WriteString(findings.Format("KD-NET-001", "WARNING", "No network policies — all pod traffic is unrestricted"))
 			sb.WriteString("\n")
 			score -= 20
-			findings++
+			findingCount++
 		} else {
 			sb.WriteString(fmt.Sprintf("  %d network policies defined\n", len(netPols)))
 			hasNetPol = true
@@ -279,18 +296,18 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 		if err != nil {
 			sb.WriteString("  (could not check PDBs)\n")
 		} else if len(pdbs) == 0 {
-			sb.WriteString(util.FormatFinding("INFO", "No PDBs — workloads have no disruption protection"))
+			sb.WriteString(findings.Format("KD-WL-003", "INFO", "No PDBs — workloads have no disruption protection"))
 			sb.WriteString("\n")
 			score -= 5
-			findings++
+			findingCount++
 		} else {
 			sb.WriteString(fmt.Sprintf("  %d PDBs defined\n", len(pdbs)))
 			hasPDB = true
 			for _, pdb := range pdbs {
 				if pdb.Status.DisruptionsAllowed == 0 && pdb.Status.ExpectedPods > 0 {
-					sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("PDB '%s' has 0 disruptions allowed", pdb.Name)))
+					sb.WriteString(findings.Format("KD-WL-003", "WARNING", fmt.Sprintf("PDB '%s' has 0 disruptions allowed", pdb.Name)))
 					sb.WriteString("\n")
-					findings++
+					findingCount++
 				}
 			}
 		}
@@ -333,22 +350,22 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 			}
 			sb.WriteString(fmt.Sprintf("  %d pods scanned\n", len(pods)))
 			if privilegedPods > 0 {
-				sb.WriteString(util.FormatFinding("CRITICAL", fmt.Sprintf("%d pod(s) running in privileged mode", privilegedPods)))
+				sb.WriteString(findings.Format("KD-SEC-001", "CRITICAL", fmt.Sprintf("%d pod(s) running in privileged mode", privilegedPods)))
 				sb.WriteString("\n")
 				score -= 15
-				findings++
+				findingCount++
 			}
 			if rootPods > 0 {
-				sb.WriteString(util.FormatFinding("WARNING", fmt.Sprintf("%d pod(s) running as root", rootPods)))
+				sb.WriteString(findings.Format("KD-SEC-003", "WARNING", fmt.Sprintf("%d pod(s) running as root", rootPods)))
 				sb.WriteString("\n")
 				score -= 10
-				findings++
+				findingCount++
 			}
 			if noSecCtxPods > 0 {
-				sb.WriteString(util.FormatFinding("INFO", fmt.Sprintf("%d pod(s) with no SecurityContext", noSecCtxPods)))
+				sb.WriteString(findings.Format("KD-SEC-007", "INFO", fmt.Sprintf("%d pod(s) with no SecurityContext", noSecCtxPods)))
 				sb.WriteString("\n")
 				score -= 5
-				findings++
+				findingCount++
 			}
 		}
 
@@ -376,10 +393,10 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 		if err != nil {
 			sb.WriteString("  (could not check resource quotas)\n")
 		} else if len(quotas) == 0 {
-			sb.WriteString(util.FormatFinding("INFO", "No resource quotas — resource consumption is unrestricted"))
+			sb.WriteString(findings.Format("KD-RES-005", "INFO", "No resource quotas — resource consumption is unrestricted"))
 			sb.WriteString("\n")
 			score -= 5
-			findings++
+			findingCount++
 		} else {
 			sb.WriteString(fmt.Sprintf("  %d resource quotas defined\n", len(quotas)))
 			hasQuota = true
@@ -394,21 +411,8 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatSubHeader("Overall Security Score"))
 		sb.WriteString("\n")
-		grade := "A"
-		switch {
-		case score >= 90:
-			grade = "A"
-		case score >= 80:
-			grade = "B"
-		case score >= 70:
-			grade = "C"
-		case score >= 60:
-			grade = "D"
-		default:
-			grade = "F"
-		}
-		sb.WriteString(fmt.Sprintf("  Score: %d/100 (Grade: %s)\n", score, grade))
-		sb.WriteString(fmt.Sprintf("  %d finding(s) identified\n", findings))
+		sb.WriteString(fmt.Sprintf("  Score: %d/100 (Grade: %s)\n", score, scoreGrade(score)))
+		sb.WriteString(fmt.Sprintf("  %d finding(s) identified\n", findingCount))
 
 		// Mermaid policy coverage diagram
 		sb.WriteString("\nPOLICY COVERAGE:\n")
@@ -465,4 +469,6 @@ func registerSecurityTools(server *mcp.Server, client *k8s.ClusterClient) {
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
+
+	registerCheckPodSecurityStandards(server, client)
 }
@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+func TestListRBACBindingsPaging(t *testing.T) {
+	subjects := []rbacv1.Subject{{Kind: "ServiceAccount", Name: "deployer", Namespace: "shop"}}
+	fakeClient := fake.NewSimpleClientset(
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "edit", Namespace: "shop"}, RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "edit"}, Subjects: subjects},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "view", Namespace: "shop"}, RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"}, Subjects: subjects},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "deployer-admin"}, RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"}, Subjects: subjects},
+	)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "test"}, nil)
+	registerSecurityTools(server, k8s.NewClusterClientForTesting(fakeClient, nil))
+
+	ctx := context.Background()
+	t1, t2 := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, t1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer serverSession.Close()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "test"}, nil).Connect(ctx, t2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	call := func(args map[string]any) string {
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "list_rbac_bindings", Arguments: args})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Content[0].(*mcp.TextContent).Text
+	}
+
+	// The RoleBindings fill the first page, so the ClusterRoleBindings follow on the next.
+	first := call(map[string]any{"namespace": "shop", "limit": 2})
+	if strings.Contains(first, "deployer-admin") || !strings.Contains(first, `continue_token="cluster:"`) {
+		t.Errorf("first page should hold only the RoleBindings and point at the cluster phase:\n%s", first)
+	}
+	second := call(map[string]any{"namespace": "shop", "limit": 2, "continue_token": "cluster:"})
+	if !strings.Contains(second, "deployer-admin") || strings.Contains(second, "view") || strings.Contains(second, "TRUNCATED") {
+		t.Errorf("second page should hold only the ClusterRoleBinding:\n%s", second)
+	}
+
+	all := call(map[string]any{"namespace": "shop"})
+	if !strings.Contains(all, "edit") || !strings.Contains(all, "deployer-admin") || strings.Contains(all, "TRUNCATED") {
+		t.Errorf("default page should hold every binding:\n%s", all)
+	}
+}
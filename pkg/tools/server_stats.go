@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// heavyToolCalls is the average API calls per invocation above which
+// server_stats points a tool out as heavy.
+const heavyToolCalls = 50
+
+type serverStatsInput struct{}
+
+// apiAccountingMiddleware counts the Kubernetes API requests each tool call
+// makes, records them in usage, and appends a footer such as
+// "12 API calls, 1.4Mi, 1.4s" to results that made any.
+func apiAccountingMiddleware(usage *k8s.APIUsage) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			call, ok := req.(*mcp.CallToolRequest)
+			if !ok || method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			ctx, stats := k8s.WithCallStats(ctx)
+			start := time.Now()
+			res, err := next(ctx, method, req)
+			elapsed := time.Since(start)
+
+			result, _ := res.(*mcp.CallToolResult)
+			usage.Record(call.Params.Name, stats, elapsed, err != nil || result == nil || result.IsError)
+			if result != nil && stats.Calls() > 0 && len(result.Content) > 0 {
+				if text, ok := result.Content[0].(*mcp.TextContent); ok {
+					text.Text = strings.TrimRight(text.Text, "\n") + "\n\n" + callFooter(stats.Calls(), stats.Retries(), stats.Bytes(), elapsed) + "\n"
+				}
+			}
+			return res, err
+		}
+	}
+}
+
+// callFooter summarizes the API usage of one tool call, including requests
+// retried after transient errors.
+func callFooter(calls, retries, bytes int64, elapsed time.Duration) string {
+	noun := "API calls"
+	if calls == 1 {
+		noun = "API call"
+	}
+	if retries > 0 {
+		noun += fmt.Sprintf(" (%d retried after transient errors)", retries)
+	}
+	return fmt.Sprintf("(%d %s, %s, %s)", calls, noun, formatBytes(bytes), elapsed.Round(100*time.Millisecond))
+}
+
+func registerServerStatsTools(server *mcp.Server, usage *k8s.APIUsage) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "server_stats",
+		Description: "Show how many Kubernetes API requests, response bytes, and time each kube-doctor tool has used since the server started, " +
+			"heaviest first. Use it to find expensive tools and gauge the server's load on the API server.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input serverStatsInput) (*mcp.CallToolResult, any, error) {
+		tools := usage.Tools()
+		var invocations int
+		var calls, bytes int64
+		for _, t := range tools {
+			invocations += t.Invocations
+			calls += t.APICalls
+			bytes += t.Bytes
+		}
+		uptime := time.Since(usage.Started())
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Server Stats"))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Uptime", uptime.Round(time.Second).String()) + "\n")
+		sb.WriteString(util.FormatKeyValue("Tool Calls", fmt.Sprintf("%d", invocations)) + "\n")
+		sb.WriteString(util.FormatKeyValue("API Calls", fmt.Sprintf("%d (%s read)", calls, formatBytes(bytes))) + "\n")
+		if minutes := uptime.Minutes(); minutes >= 1 {
+			sb.WriteString(util.FormatKeyValue("API Rate", fmt.Sprintf("%.1f calls/min", float64(calls)/minutes)) + "\n")
+		}
+		sb.WriteString("\n")
+
+		if len(tools) == 0 {
+			sb.WriteString("No tool calls yet.\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+		rows := make([][]string, 0, len(tools))
+		for _, t := range tools {
+			rows = append(rows, []string{
+				t.Tool,
+				fmt.Sprintf("%d", t.Invocations),
+				fmt.Sprintf("%d", t.Errors),
+				fmt.Sprintf("%d", t.APICalls),
+				fmt.Sprintf("%d", t.Retries),
+				fmt.Sprintf("%.1f", float64(t.APICalls)/float64(t.Invocations)),
+				formatBytes(t.Bytes),
+				(t.Elapsed / time.Duration(t.Invocations)).Round(10 * time.Millisecond).String(),
+				t.MaxElapsed.Round(10 * time.Millisecond).String(),
+			})
+		}
+		sb.WriteString(util.FormatTable([]string{"TOOL", "CALLS", "ERRORS", "API CALLS", "RETRIES", "API/CALL", "BYTES", "AVG TIME", "MAX TIME"}, rows))
+
+		var heavy []string
+		for _, t := range tools {
+			if t.APICalls/int64(t.Invocations) > heavyToolCalls {
+				heavy = append(heavy, fmt.Sprintf("%s (%d API calls per call)", t.Tool, t.APICalls/int64(t.Invocations)))
+			}
+		}
+		if len(heavy) > 0 {
+			sb.WriteString("\nFINDINGS:\n")
+			sb.WriteString(findings.Format("KD-SYS-012", "INFO", "Heavy tools: "+strings.Join(heavy, ", ")+"; pass a namespace to narrow them on large clusters"))
+			sb.WriteString("\n")
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
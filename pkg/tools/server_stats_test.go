@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+func TestAPIAccountingMiddleware(t *testing.T) {
+	usage := k8s.NewAPIUsage()
+	handler := apiAccountingMiddleware(usage)(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return util.SuccessResult("report\n"), nil
+	})
+
+	call := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "get_pod"}}
+	res, err := handler(context.Background(), "tools/call", call)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// No API requests were made, so no footer is added.
+	if text := res.(*mcp.CallToolResult).Content[0].(*mcp.TextContent).Text; text != "report\n" {
+		t.Errorf("text = %q, want it unchanged", text)
+	}
+	got := usage.Tools()
+	if len(got) != 1 || got[0].Tool != "get_pod" || got[0].Invocations != 1 {
+		t.Errorf("usage = %+v, want one get_pod call", got)
+	}
+}
+
+func TestCallFooter(t *testing.T) {
+	if got := callFooter(12, 0, 1536, 1420*time.Millisecond); got != "(12 API calls, 1.5Ki, 1.4s)" {
+		t.Errorf("callFooter = %q", got)
+	}
+	if got := callFooter(1, 0, 10, 50*time.Millisecond); !strings.HasPrefix(got, "(1 API call,") {
+		t.Errorf("callFooter = %q, want singular", got)
+	}
+	if got := callFooter(5, 2, 0, time.Second); !strings.Contains(got, "5 API calls (2 retried after transient errors)") {
+		t.Errorf("callFooter = %q, want retries", got)
+	}
+}
@@ -0,0 +1,341 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// Azure Workload Identity pod label, ServiceAccount annotation, and the
+	// projected token volume its mutating webhook injects.
+	workloadIdentityUseLabel      = "azure.workload.identity/use"
+	workloadIdentityClientIDAnnot = "azure.workload.identity/client-id"
+	workloadIdentityTokenVolume   = "azure-identity-token"
+
+	// aadPodIdentityLabel marks pods using the deprecated AAD Pod Identity.
+	aadPodIdentityLabel = "aadpodidbinding"
+
+	// legacyTokenLastUsedLabel is set on token Secrets by the API server
+	// (Kubernetes 1.29+) with the date the token last authenticated.
+	legacyTokenLastUsedLabel = "kubernetes.io/legacy-token-last-used"
+)
+
+// azureClientID matches the GUID format of a managed identity client ID.
+var azureClientID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type auditServiceAccountsInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces)"`
+	IncludeSystem  bool   `json:"include_system,omitempty" jsonschema:"Also audit kube-system, kube-public, and kube-node-lease"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// saIssue is one problem found by audit_service_accounts. Category is one of
+// automount, default, token, or identity.
+type saIssue struct {
+	Category  string
+	Rule      string
+	Severity  string
+	Namespace string
+	Name      string
+	Message   string
+}
+
+func registerServiceAccountTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "audit_service_accounts",
+		Description: "Audit ServiceAccount tokens and workload identity: ServiceAccounts whose API token is automounted into pods although no RoleBinding " +
+			"or ClusterRoleBinding grants them anything (so the app likely never calls the API), pods running as the namespace's default ServiceAccount, " +
+			"long-lived kubernetes.io/service-account-token Secrets (with their last-used date on 1.29+), and Azure Workload Identity mistakes: " +
+			"pods labeled azure.workload.identity/use without a client-id on their ServiceAccount, annotated ServiceAccounts whose pods lack the label, " +
+			"malformed client IDs, pods the webhook never mutated, and pods still on deprecated AAD Pod Identity.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input auditServiceAccountsInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+
+		sas, err := client.ListServiceAccounts(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing service accounts", err), nil, nil
+		}
+		pods, err := client.ListPods(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		var gaps dataGaps
+		secrets, err := client.ListSecrets(ctx, ns, metav1.ListOptions{FieldSelector: "type=" + string(corev1.SecretTypeServiceAccountToken)})
+		gaps.record("Token Secrets", err)
+		roleBindings, err := client.ListRoleBindings(ctx, ns, metav1.ListOptions{})
+		gaps.record("RoleBindings", err)
+		clusterBindings, err := client.ListClusterRoleBindings(ctx, metav1.ListOptions{})
+		gaps.record("ClusterRoleBindings", err)
+
+		bindings := serviceAccountBindings(roleBindings, clusterBindings)
+		issues := auditServiceAccounts(sas, pods, secrets, bindings, input.IncludeSystem || configMapSystemNamespaces[ns], time.Now())
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("ServiceAccount Audit (scope: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+		sb.WriteString(util.FormatKeyValue("ServiceAccounts", fmt.Sprintf("%d", len(sas))) + "\n")
+		sb.WriteString(util.FormatKeyValue("Pods", fmt.Sprintf("%d", len(pods))) + "\n")
+		sb.WriteString(util.FormatKeyValue("Token Secrets", fmt.Sprintf("%d", countTokenSecrets(secrets))) + "\n")
+		if len(gaps) > 0 {
+			sb.WriteString("\n" + gaps.assessment())
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(issues) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "No unneeded tokens, default ServiceAccount use, long-lived token Secrets, or workload identity problems found"))
+			sb.WriteString("\n")
+			gaps.write(&sb)
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+		for _, is := range issues {
+			sb.WriteString(findings.Format(is.Rule, is.Severity, fmt.Sprintf("%s/%s: %s", is.Namespace, is.Name, is.Message)))
+			sb.WriteString("\n")
+		}
+		gaps.write(&sb)
+
+		sb.WriteString("\nSUGGESTED ACTIONS:\n")
+		var actions []string
+		for _, is := range issues {
+			switch is.Category {
+			case "identity":
+				actions = append(actions, "Fix workload identity: label pod templates azure.workload.identity/use: \"true\", annotate their ServiceAccount with azure.workload.identity/client-id, and recreate the pods so the webhook injects the federated token")
+			case "token":
+				actions = append(actions, "Delete long-lived token Secrets nothing uses; give remaining clients expiring tokens (kubectl create token, TokenRequest API)")
+			case "default":
+				actions = append(actions, "Give each workload its own ServiceAccount via serviceAccountName and keep the default ServiceAccount without bindings")
+			case "automount":
+				actions = append(actions, "Set automountServiceAccountToken: false on ServiceAccounts whose pods do not call the Kubernetes API")
+			}
+		}
+		for i, a := range dedupe(actions) {
+			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// serviceAccountBindings maps "namespace/name" of each ServiceAccount
+// granted a role to the roles it holds. Grants to the system:serviceaccounts
+// groups are recorded under "namespace/*" and "*/*", except the built-in
+// system: roles every cluster grants them (such as OIDC discovery).
+func serviceAccountBindings(roleBindings []rbacv1.RoleBinding, clusterBindings []rbacv1.ClusterRoleBinding) map[string][]string {
+	out := make(map[string][]string)
+	add := func(subjects []rbacv1.Subject, bindingNS string, role rbacv1.RoleRef) {
+		builtin := strings.HasPrefix(role.Name, "system:")
+		for _, s := range subjects {
+			switch {
+			case s.Kind == rbacv1.GroupKind && builtin:
+			case s.Kind == rbacv1.ServiceAccountKind:
+				ns := s.Namespace
+				if ns == "" {
+					ns = bindingNS
+				}
+				out[ns+"/"+s.Name] = append(out[ns+"/"+s.Name], role.Kind+"/"+role.Name)
+			case s.Kind == rbacv1.GroupKind && s.Name == "system:serviceaccounts":
+				out["*/*"] = append(out["*/*"], role.Kind+"/"+role.Name)
+			case s.Kind == rbacv1.GroupKind && strings.HasPrefix(s.Name, "system:serviceaccounts:"):
+				ns := strings.TrimPrefix(s.Name, "system:serviceaccounts:")
+				out[ns+"/*"] = append(out[ns+"/*"], role.Kind+"/"+role.Name)
+			}
+		}
+	}
+	for _, rb := range roleBindings {
+		add(rb.Subjects, rb.Namespace, rb.RoleRef)
+	}
+	for _, crb := range clusterBindings {
+		add(crb.Subjects, "", crb.RoleRef)
+	}
+	return out
+}
+
+// saRoles returns the roles granted to a ServiceAccount directly or through
+// its groups.
+func saRoles(bindings map[string][]string, namespace, name string) []string {
+	var roles []string
+	for _, key := range []string{namespace + "/" + name, namespace + "/*", "*/*"} {
+		roles = append(roles, bindings[key]...)
+	}
+	return dedupe(roles)
+}
+
+// podAutomountsToken reports whether the API token is mounted into a pod: the
+// pod's setting wins over its ServiceAccount's, and both default to true.
+func podAutomountsToken(pod *corev1.Pod, sa *corev1.ServiceAccount) bool {
+	if pod.Spec.AutomountServiceAccountToken != nil {
+		return *pod.Spec.AutomountServiceAccountToken
+	}
+	if sa != nil && sa.AutomountServiceAccountToken != nil {
+		return *sa.AutomountServiceAccountToken
+	}
+	return true
+}
+
+// podServiceAccount returns the ServiceAccount a pod runs as.
+func podServiceAccount(pod *corev1.Pod) string {
+	if pod.Spec.ServiceAccountName == "" {
+		return "default"
+	}
+	return pod.Spec.ServiceAccountName
+}
+
+// hasVolume reports whether a pod has a volume named name.
+func hasVolume(pod *corev1.Pod, name string) bool {
+	for _, v := range pod.Spec.Volumes {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// countTokenSecrets counts ServiceAccount token Secrets; the type field
+// selector is not honoured by every client.
+func countTokenSecrets(secrets []corev1.Secret) int {
+	n := 0
+	for _, s := range secrets {
+		if s.Type == corev1.SecretTypeServiceAccountToken {
+			n++
+		}
+	}
+	return n
+}
+
+// auditServiceAccounts returns the ServiceAccount issues in a set of
+// ServiceAccounts, their pods, and token Secrets, most severe first.
+// bindings is the result of serviceAccountBindings.
+func auditServiceAccounts(sas []corev1.ServiceAccount, pods []corev1.Pod, secrets []corev1.Secret, bindings map[string][]string, includeSystem bool, now time.Time) []saIssue {
+	var issues []saIssue
+	skip := func(ns string) bool { return configMapSystemNamespaces[ns] && !includeSystem }
+
+	saByKey := make(map[string]*corev1.ServiceAccount, len(sas))
+	for i := range sas {
+		saByKey[sas[i].Namespace+"/"+sas[i].Name] = &sas[i]
+	}
+
+	// Group running pods by ServiceAccount, and default-SA workloads by namespace.
+	podsBySA := make(map[string][]*corev1.Pod)
+	mountedBySA := make(map[string][]string)
+	defaultWorkloads := make(map[string][]string)
+	for i := range pods {
+		p := &pods[i]
+		if skip(p.Namespace) || p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		saName := podServiceAccount(p)
+		key := p.Namespace + "/" + saName
+		podsBySA[key] = append(podsBySA[key], p)
+		if podAutomountsToken(p, saByKey[key]) {
+			mountedBySA[key] = append(mountedBySA[key], podWorkloadName(p))
+		}
+		if saName == "default" {
+			defaultWorkloads[p.Namespace] = append(defaultWorkloads[p.Namespace], podWorkloadName(p))
+		}
+
+		switch {
+		case p.Labels[workloadIdentityUseLabel] == "true":
+			sa := saByKey[key]
+			if sa == nil || sa.Annotations[workloadIdentityClientIDAnnot] == "" {
+				issues = append(issues, saIssue{"identity", "KD-SEC-012", "WARNING", p.Namespace, p.Name,
+					fmt.Sprintf("labeled %s=true but ServiceAccount %s has no %s annotation; the Azure SDK gets no AZURE_CLIENT_ID unless the pod sets one", workloadIdentityUseLabel, saName, workloadIdentityClientIDAnnot)})
+			} else if !hasVolume(p, workloadIdentityTokenVolume) {
+				issues = append(issues, saIssue{"identity", "KD-SEC-012", "WARNING", p.Namespace, p.Name,
+					fmt.Sprintf("labeled %s=true but has no %s volume; the workload identity webhook did not mutate it (not installed, or the pod predates the label)", workloadIdentityUseLabel, workloadIdentityTokenVolume)})
+			}
+		case p.Labels[aadPodIdentityLabel] != "":
+			issues = append(issues, saIssue{"identity", "KD-SEC-012", "INFO", p.Namespace, p.Name,
+				fmt.Sprintf("uses deprecated AAD Pod Identity (%s=%s); migrate to Azure Workload Identity", aadPodIdentityLabel, p.Labels[aadPodIdentityLabel])})
+		}
+	}
+
+	for i := range sas {
+		sa := &sas[i]
+		if skip(sa.Namespace) {
+			continue
+		}
+		key := sa.Namespace + "/" + sa.Name
+		add := func(category, rule, severity, msg string) {
+			issues = append(issues, saIssue{category, rule, severity, sa.Namespace, sa.Name, msg})
+		}
+
+		if workloads := dedupe(mountedBySA[key]); len(workloads) > 0 && len(saRoles(bindings, sa.Namespace, sa.Name)) == 0 {
+			add("automount", "KD-SEC-009", "INFO", fmt.Sprintf("API token is mounted into %d pod(s) (%s) but no RoleBinding or ClusterRoleBinding grants this ServiceAccount anything; set automountServiceAccountToken: false",
+				len(mountedBySA[key]), summarizeNames(workloads, 5)))
+		}
+
+		if clientID := sa.Annotations[workloadIdentityClientIDAnnot]; clientID != "" {
+			if !azureClientID.MatchString(clientID) {
+				add("identity", "KD-SEC-012", "WARNING", fmt.Sprintf("%s %q is not a GUID; token exchange with Microsoft Entra ID will fail", workloadIdentityClientIDAnnot, clientID))
+			}
+			labeled := 0
+			for _, p := range podsBySA[key] {
+				if p.Labels[workloadIdentityUseLabel] == "true" {
+					labeled++
+				}
+			}
+			if n := len(podsBySA[key]); n > 0 && labeled < n {
+				add("identity", "KD-SEC-012", "WARNING", fmt.Sprintf("has a workload identity client ID but %d of %d pod(s) using it lack the %s=true label, so the webhook does not inject the federated token into them",
+					n-labeled, n, workloadIdentityUseLabel))
+			}
+		}
+		if sa.Labels[workloadIdentityUseLabel] == "true" && sa.Annotations[workloadIdentityClientIDAnnot] == "" {
+			add("identity", "KD-SEC-012", "INFO", fmt.Sprintf("has the %s label, which only takes effect on pods, and no client-id annotation", workloadIdentityUseLabel))
+		}
+	}
+
+	namespaces := make([]string, 0, len(defaultWorkloads))
+	for ns := range defaultWorkloads {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		workloads := dedupe(defaultWorkloads[ns])
+		severity, granted := "INFO", ""
+		if roles := saRoles(bindings, ns, "default"); len(roles) > 0 {
+			severity, granted = "WARNING", fmt.Sprintf("; it is bound to %s, which all of them share", summarizeNames(roles, 3))
+		}
+		issues = append(issues, saIssue{"default", "KD-SEC-010", severity, ns, "default",
+			fmt.Sprintf("%d workload(s) run as the default ServiceAccount (%s)%s", len(workloads), summarizeNames(workloads, 5), granted)})
+	}
+
+	for i := range secrets {
+		s := &secrets[i]
+		if s.Type != corev1.SecretTypeServiceAccountToken || skip(s.Namespace) {
+			continue
+		}
+		saName := s.Annotations[corev1.ServiceAccountNameKey]
+		age := now.Sub(s.CreationTimestamp.Time)
+		msg := fmt.Sprintf("long-lived token for ServiceAccount %s, created %d days ago", valueOrNone(saName), int(age.Hours()/24))
+		if lastUsed := s.Labels[legacyTokenLastUsedLabel]; lastUsed != "" {
+			msg += ", last used " + lastUsed
+		} else {
+			msg += ", no recorded use"
+		}
+		severity := "INFO"
+		switch {
+		case saByKey[s.Namespace+"/"+saName] == nil:
+			msg += "; its ServiceAccount no longer exists"
+		case len(saRoles(bindings, s.Namespace, saName)) > 0:
+			severity = "WARNING"
+			msg += fmt.Sprintf("; it never expires and carries the ServiceAccount's roles (%s)", summarizeNames(saRoles(bindings, s.Namespace, saName), 3))
+		}
+		issues = append(issues, saIssue{"token", "KD-SEC-011", severity, s.Namespace, s.Name, msg})
+	}
+
+	rank := map[string]int{"CRITICAL": 0, "WARNING": 1, "INFO": 2}
+	sort.SliceStable(issues, func(i, j int) bool { return rank[issues[i].Severity] < rank[issues[j].Severity] })
+	return issues
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceAccountBindings(t *testing.T) {
+	rbs := []rbacv1.RoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "reader"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "api"}},
+		RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "pod-reader"},
+	}}
+	crbs := []rbacv1.ClusterRoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "system:service-account-issuer-discovery"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "system:service-account-issuer-discovery"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "batch-view"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:batch"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		},
+	}
+	b := serviceAccountBindings(rbs, crbs)
+	if roles := saRoles(b, "shop", "api"); len(roles) != 1 || roles[0] != "Role/pod-reader" {
+		t.Errorf("expected shop/api to hold Role/pod-reader, got %v", roles)
+	}
+	if roles := saRoles(b, "shop", "web"); len(roles) != 0 {
+		t.Errorf("expected built-in discovery grant to be ignored, got %v", roles)
+	}
+	if roles := saRoles(b, "batch", "worker"); len(roles) != 1 || roles[0] != "ClusterRole/view" {
+		t.Errorf("expected namespace group grant for batch/worker, got %v", roles)
+	}
+}
+
+func TestAuditServiceAccounts(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	off := false
+	pod := func(ns, name, sa string, labels map[string]string) corev1.Pod {
+		p := testPod(ns, name, "", labels)
+		p.Spec.ServiceAccountName = sa
+		return p
+	}
+	sas := []corev1.ServiceAccount{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "quiet"}, AutomountServiceAccountToken: &off},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "blob", Annotations: map[string]string{workloadIdentityClientIDAnnot: "not-a-guid"}}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "coredns"}},
+	}
+	injected := pod("shop", "blob-ok", "blob", map[string]string{workloadIdentityUseLabel: "true"})
+	injected.Spec.Volumes = []corev1.Volume{{Name: workloadIdentityTokenVolume}}
+	pods := []corev1.Pod{
+		pod("shop", "legacy", "", nil),
+		pod("shop", "web-1", "web", nil),
+		pod("shop", "quiet-1", "quiet", nil),
+		pod("shop", "vault", "web", map[string]string{workloadIdentityUseLabel: "true"}),
+		injected,
+		pod("shop", "blob-unlabeled", "blob", nil),
+		pod("kube-system", "coredns-1", "coredns", nil),
+	}
+	secrets := []corev1.Secret{{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "shop", Name: "ci-token", CreationTimestamp: metav1.NewTime(now.Add(-400 * 24 * time.Hour)),
+			Annotations: map[string]string{corev1.ServiceAccountNameKey: "default"},
+			Labels:      map[string]string{legacyTokenLastUsedLabel: "2025-05-30"},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}}
+	bindings := map[string][]string{"shop/default": {"ClusterRole/edit"}}
+
+	issues := auditServiceAccounts(sas, pods, secrets, bindings, false, now)
+	byKey := make(map[string]saIssue)
+	for _, is := range issues {
+		if is.Namespace == "kube-system" {
+			t.Errorf("expected system namespaces to be skipped, got %+v", is)
+		}
+		byKey[is.Category+":"+is.Name] = is
+	}
+
+	if is, ok := byKey["default:default"]; !ok || is.Severity != "WARNING" || !strings.Contains(is.Message, "ClusterRole/edit") {
+		t.Errorf("expected default ServiceAccount warning naming its role, got %+v", is)
+	}
+	if is, ok := byKey["automount:web"]; !ok || is.Rule != "KD-SEC-009" {
+		t.Errorf("expected unneeded token finding for web, got %+v", is)
+	}
+	if _, ok := byKey["automount:quiet"]; ok {
+		t.Error("expected no token finding for a ServiceAccount with automount disabled")
+	}
+	if _, ok := byKey["automount:default"]; ok {
+		t.Error("expected no token finding for a ServiceAccount with bindings")
+	}
+	if is, ok := byKey["token:ci-token"]; !ok || is.Severity != "WARNING" || !strings.Contains(is.Message, "last used 2025-05-30") {
+		t.Errorf("expected long-lived token warning, got %+v", is)
+	}
+	if is, ok := byKey["identity:vault"]; !ok || !strings.Contains(is.Message, "no "+workloadIdentityClientIDAnnot) {
+		t.Errorf("expected missing client-id finding for vault, got %+v", is)
+	}
+	if _, ok := byKey["identity:blob-ok"]; ok {
+		t.Error("expected no identity finding for a mutated pod")
+	}
+	var blob []string
+	for _, is := range issues {
+		if is.Category == "identity" && is.Name == "blob" {
+			blob = append(blob, is.Message)
+		}
+	}
+	if len(blob) != 2 || !strings.Contains(strings.Join(blob, "|"), "not a GUID") || !strings.Contains(strings.Join(blob, "|"), "1 of 2 pod(s)") {
+		t.Errorf("expected malformed client ID and unlabeled pod findings for blob, got %v", blob)
+	}
+}
@@ -0,0 +1,640 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	meshIstio   = "Istio"
+	meshLinkerd = "Linkerd"
+)
+
+var (
+	peerAuthenticationGVR = schema.GroupVersionResource{Group: "security.istio.io", Version: "v1beta1", Resource: "peerauthentications"}
+	destinationRuleGVR    = schema.GroupVersionResource{Group: "networking.istio.io", Version: "v1beta1", Resource: "destinationrules"}
+)
+
+type checkServiceMeshHealthInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Only check sidecar coverage and mTLS policies in this namespace (default: all namespaces)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// meshType describes a service mesh kube-doctor can detect.
+type meshType struct {
+	Name         string
+	Sidecar      string   // injected proxy container name
+	PodSelectors []string // label selectors for control-plane pods
+}
+
+// knownMeshes lists the service meshes kube-doctor can detect.
+var knownMeshes = []meshType{
+	{Name: meshIstio, Sidecar: "istio-proxy", PodSelectors: []string{"app=istiod", "istio=pilot"}},
+	{Name: meshLinkerd, Sidecar: "linkerd-proxy", PodSelectors: []string{"linkerd.io/control-plane-component"}},
+}
+
+// detectedMesh is a mesh whose control plane was found in the cluster.
+type detectedMesh struct {
+	Type         meshType
+	ControlPlane []corev1.Pod
+}
+
+// peerAuthentication is the part of an Istio PeerAuthentication that decides
+// which mTLS mode a workload accepts.
+type peerAuthentication struct {
+	Namespace string
+	Name      string
+	Selector  map[string]string
+	Mode      string // STRICT, PERMISSIVE, DISABLE, or "" (inherit)
+}
+
+// destinationRule is the part of an Istio DestinationRule that decides how
+// clients originate TLS to a host.
+type destinationRule struct {
+	Namespace string
+	Name      string
+	Host      string
+	TLSMode   string // DISABLE, SIMPLE, MUTUAL, ISTIO_MUTUAL, or "" (auto mTLS)
+}
+
+// meshNamespaceCoverage is the sidecar coverage of running pods in one namespace.
+type meshNamespaceCoverage struct {
+	Namespace   string
+	Injection   string // mesh that auto-injects the namespace, or ""
+	Pods        int
+	WithSidecar int
+	Missing     []string
+}
+
+func registerServiceMeshTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// check_service_mesh_health
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "check_service_mesh_health",
+		Description: "Detect Istio or Linkerd and check the mesh: control-plane pod health, sidecar injection coverage per namespace, pods missing sidecars in injected namespaces, and Istio mTLS policy conflicts (PeerAuthentication vs DestinationRule) that commonly cause 503s.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkServiceMeshHealthInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		meshes, err := detectMeshes(ctx, client)
+		if err != nil {
+			return util.HandleK8sError("listing mesh control-plane pods", err), nil, nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Service Mesh Health"))
+		if len(meshes) == 0 {
+			sb.WriteString("No Istio or Linkerd control plane detected (looked for istiod and linkerd control-plane pods).\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+		var names []string
+		for _, m := range meshes {
+			names = append(names, fmt.Sprintf("%s (%s)", m.Type.Name, m.ControlPlane[0].Namespace))
+		}
+		sb.WriteString(util.FormatKeyValue("Mesh", strings.Join(names, ", ")))
+		sb.WriteString(util.FormatKeyValue("Namespace", displayNS(input.Namespace)))
+		sb.WriteString("\n")
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+
+		// --- Control plane ---
+		sb.WriteString(util.FormatSubHeader("CONTROL PLANE"))
+		var cpRows [][]string
+		for _, m := range meshes {
+			for i := range m.ControlPlane {
+				p := &m.ControlPlane[i]
+				_, _, restarts := podContainerSummary(p)
+				cpRows = append(cpRows, []string{m.Type.Name, p.Namespace + "/" + p.Name, podPhaseReason(p), fmt.Sprintf("%d", restarts)})
+				if !isPodHealthy(p) {
+					findingLines = append(findingLines, findings.Format("KD-NET-006", "CRITICAL",
+						fmt.Sprintf("%s control-plane pod %s/%s is not healthy: %s; sidecars cannot receive config or certificates", m.Type.Name, p.Namespace, p.Name, podPhaseReason(p))))
+					actions = append(actions, fmt.Sprintf("Run diagnose_pod on the unhealthy %s control-plane pods", m.Type.Name))
+					steps = append(steps, nextStep("diagnose_pod", fmt.Sprintf("%s control plane is %s", m.Type.Name, podPhaseReason(p)), "namespace", p.Namespace, "name", p.Name))
+				} else if restarts > util.HighRestartThreshold {
+					findingLines = append(findingLines, findings.Format("KD-NET-006", "WARNING",
+						fmt.Sprintf("%s control-plane pod %s/%s has restarted %d time(s)", m.Type.Name, p.Namespace, p.Name, restarts)))
+				}
+			}
+		}
+		sb.WriteString(util.FormatTable([]string{"MESH", "POD", "STATUS", "RESTARTS"}, cpRows))
+		sb.WriteString("\n")
+
+		// --- Sidecar injection coverage ---
+		namespaces, err := client.ListNamespaces(ctx)
+		if err != nil {
+			return util.HandleK8sError("listing namespaces", err), nil, nil
+		}
+		pods, err := client.ListPods(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		coverage := meshCoverage(namespaces, pods, input.Namespace)
+		sb.WriteString(util.FormatSubHeader("SIDECAR INJECTION"))
+		if len(coverage) == 0 {
+			sb.WriteString("  No namespaces have sidecar injection enabled or meshed pods.\n")
+			findingLines = append(findingLines, findings.Format("KD-NET-006", "WARNING", "A mesh control plane is installed but no namespace has sidecar injection enabled"))
+			actions = append(actions, "Label application namespaces for injection (istio-injection=enabled or the linkerd.io/inject=enabled annotation) and restart their workloads")
+		} else {
+			var rows [][]string
+			for _, c := range coverage {
+				injection := c.Injection
+				if injection == "" {
+					injection = "-"
+				}
+				rows = append(rows, []string{c.Namespace, injection, fmt.Sprintf("%d", c.Pods), fmt.Sprintf("%d", c.WithSidecar), fmt.Sprintf("%d", len(c.Missing))})
+				if c.Injection != "" && len(c.Missing) > 0 {
+					findingLines = append(findingLines, findings.Format("KD-NET-006", "WARNING",
+						fmt.Sprintf("%d pod(s) in %s-injected namespace %s have no sidecar: %s", len(c.Missing), c.Injection, c.Namespace, joinLimited(c.Missing, 5))))
+					actions = append(actions, fmt.Sprintf("Restart workloads in namespace %s so pods created before injection was enabled get a sidecar (or check the injector webhook with list_webhook_configs)", c.Namespace))
+				}
+			}
+			sb.WriteString(util.FormatTable([]string{"NAMESPACE", "INJECTION", "PODS", "WITH SIDECAR", "MISSING"}, rows))
+		}
+		sb.WriteString("\n")
+
+		// --- Istio mTLS policies ---
+		for _, m := range meshes {
+			if m.Type.Name != meshIstio {
+				continue
+			}
+			root := m.ControlPlane[0].Namespace
+			pas, drs, err := listIstioPolicies(ctx, client, input.Namespace, root)
+			if err != nil {
+				return util.HandleK8sError("listing Istio mTLS policies", err), nil, nil
+			}
+			services, err := client.ListServices(ctx, input.Namespace, metav1.ListOptions{})
+			if err != nil {
+				return util.HandleK8sError("listing services", err), nil, nil
+			}
+
+			sb.WriteString(util.FormatSubHeader("MTLS POLICIES"))
+			if len(pas)+len(drs) == 0 {
+				sb.WriteString("  No PeerAuthentication or DestinationRule TLS settings; Istio defaults to PERMISSIVE with auto mTLS.\n")
+			} else {
+				var rows [][]string
+				for _, pa := range pas {
+					rows = append(rows, []string{"PeerAuthentication", pa.Namespace + "/" + pa.Name, peerAuthScope(pa, root), modeOrInherit(pa.Mode)})
+				}
+				for _, dr := range drs {
+					if dr.TLSMode != "" {
+						rows = append(rows, []string{"DestinationRule", dr.Namespace + "/" + dr.Name, "host " + dr.Host, dr.TLSMode})
+					}
+				}
+				sb.WriteString(util.FormatTable([]string{"KIND", "NAME", "SCOPE", "MODE"}, rows))
+			}
+			sb.WriteString("\n")
+
+			findingLines = append(findingLines, peerAuthConflicts(pas)...)
+			findingLines = append(findingLines, strictWithoutSidecar(pas, root, pods)...)
+			if conflicts := destinationRuleConflicts(pas, drs, root, services, pods); len(conflicts) > 0 {
+				findingLines = append(findingLines, conflicts...)
+				actions = append(actions, "Align DestinationRule trafficPolicy.tls with the destination's PeerAuthentication (remove tls.mode DISABLE towards STRICT workloads, and use ISTIO_MUTUAL only for hosts whose pods have sidecars)")
+			}
+		}
+
+		sb.WriteString("FINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString("  Mesh control plane is healthy, sidecars are injected everywhere expected, and no mTLS conflicts were found.\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// detectMeshes finds installed meshes from their control-plane pods.
+func detectMeshes(ctx context.Context, client *k8s.ClusterClient) ([]detectedMesh, error) {
+	var meshes []detectedMesh
+	for _, t := range knownMeshes {
+		pods, err := listPodsBySelectors(ctx, client, t.PodSelectors)
+		if err != nil {
+			return nil, err
+		}
+		if len(pods) > 0 {
+			meshes = append(meshes, detectedMesh{Type: t, ControlPlane: pods})
+		}
+	}
+	return meshes, nil
+}
+
+// podMeshSidecar returns the mesh whose proxy is injected into the pod, or "".
+// Native sidecars run as restartable init containers, so those count too.
+func podMeshSidecar(p *corev1.Pod) string {
+	for _, c := range allContainers(p.Spec) {
+		for _, m := range knownMeshes {
+			if c.Name == m.Sidecar {
+				return m.Name
+			}
+		}
+	}
+	return ""
+}
+
+// namespaceMeshInjection returns the mesh that auto-injects sidecars into new
+// pods in the namespace, or "".
+func namespaceMeshInjection(ns *corev1.Namespace) string {
+	switch istio := ns.Labels["istio-injection"]; {
+	case istio == "enabled":
+		return meshIstio
+	case istio != "disabled" && ns.Labels["istio.io/rev"] != "":
+		return meshIstio
+	}
+	if ns.Annotations["linkerd.io/inject"] == "enabled" {
+		return meshLinkerd
+	}
+	return ""
+}
+
+// podOptsOutOfInjection reports whether the pod is excluded from sidecar
+// injection on purpose.
+func podOptsOutOfInjection(p *corev1.Pod) bool {
+	if p.Spec.HostNetwork {
+		return true
+	}
+	if p.Labels["sidecar.istio.io/inject"] == "false" || p.Annotations["sidecar.istio.io/inject"] == "false" {
+		return true
+	}
+	return p.Annotations["linkerd.io/inject"] == "disabled"
+}
+
+// meshCoverage summarizes sidecar coverage of running pods for every namespace
+// that has injection enabled or contains meshed pods. A non-empty only limits
+// the result to that namespace.
+func meshCoverage(namespaces []corev1.Namespace, pods []corev1.Pod, only string) []meshNamespaceCoverage {
+	byNS := make(map[string]*meshNamespaceCoverage)
+	for i := range namespaces {
+		ns := &namespaces[i]
+		if only != "" && ns.Name != only {
+			continue
+		}
+		byNS[ns.Name] = &meshNamespaceCoverage{Namespace: ns.Name, Injection: namespaceMeshInjection(ns)}
+	}
+	for i := range pods {
+		p := &pods[i]
+		if p.Status.Phase != corev1.PodRunning && p.Status.Phase != corev1.PodPending {
+			continue
+		}
+		c, ok := byNS[p.Namespace]
+		if !ok {
+			continue
+		}
+		c.Pods++
+		if podMeshSidecar(p) != "" {
+			c.WithSidecar++
+		} else if !podOptsOutOfInjection(p) {
+			c.Missing = append(c.Missing, p.Name)
+		}
+	}
+
+	var out []meshNamespaceCoverage
+	for _, c := range byNS {
+		if c.Injection != "" || c.WithSidecar > 0 {
+			sort.Strings(c.Missing)
+			out = append(out, *c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Namespace < out[j].Namespace })
+	return out
+}
+
+// listIstioPolicies reads PeerAuthentications and DestinationRules in the
+// namespace plus mesh-wide PeerAuthentications from the root namespace.
+// Missing Istio CRDs yield no policies rather than an error.
+func listIstioPolicies(ctx context.Context, client *k8s.ClusterClient, namespace, root string) ([]peerAuthentication, []destinationRule, error) {
+	list := func(gvr schema.GroupVersionResource, ns string) ([]unstructured.Unstructured, error) {
+		items, err := client.ListCustomResources(ctx, gvr, ns, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return items, err
+	}
+
+	paItems, err := list(peerAuthenticationGVR, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if namespace != "" && namespace != root {
+		rootItems, err := list(peerAuthenticationGVR, root)
+		if err != nil && !apierrors.IsForbidden(err) {
+			return nil, nil, err
+		}
+		paItems = append(paItems, rootItems...)
+	}
+	var pas []peerAuthentication
+	for i := range paItems {
+		pas = append(pas, parsePeerAuthentication(&paItems[i]))
+	}
+
+	drItems, err := list(destinationRuleGVR, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	var drs []destinationRule
+	for i := range drItems {
+		drs = append(drs, parseDestinationRule(&drItems[i]))
+	}
+	return pas, drs, nil
+}
+
+func parsePeerAuthentication(u *unstructured.Unstructured) peerAuthentication {
+	pa := peerAuthentication{Namespace: u.GetNamespace(), Name: u.GetName()}
+	pa.Selector, _, _ = unstructured.NestedStringMap(u.Object, "spec", "selector", "matchLabels")
+	pa.Mode, _, _ = unstructured.NestedString(u.Object, "spec", "mtls", "mode")
+	if pa.Mode == "UNSET" {
+		pa.Mode = ""
+	}
+	return pa
+}
+
+func parseDestinationRule(u *unstructured.Unstructured) destinationRule {
+	dr := destinationRule{Namespace: u.GetNamespace(), Name: u.GetName()}
+	dr.Host, _, _ = unstructured.NestedString(u.Object, "spec", "host")
+	dr.TLSMode, _, _ = unstructured.NestedString(u.Object, "spec", "trafficPolicy", "tls", "mode")
+	return dr
+}
+
+// effectiveMTLSMode resolves the mTLS mode Istio enforces for a workload:
+// a matching workload policy overrides the namespace policy, which overrides
+// the mesh-wide policy in the root namespace. The default is PERMISSIVE.
+func effectiveMTLSMode(pas []peerAuthentication, root, namespace string, podLabels map[string]string) string {
+	mode := "PERMISSIVE"
+	for _, pa := range pas {
+		if pa.Namespace == root && len(pa.Selector) == 0 && pa.Mode != "" {
+			mode = pa.Mode
+		}
+	}
+	for _, pa := range pas {
+		if pa.Namespace == namespace && len(pa.Selector) == 0 && pa.Mode != "" {
+			mode = pa.Mode
+		}
+	}
+	for _, pa := range pas {
+		if pa.Namespace == namespace && len(pa.Selector) > 0 && pa.Mode != "" &&
+			labels.SelectorFromSet(pa.Selector).Matches(labels.Set(podLabels)) {
+			mode = pa.Mode
+		}
+	}
+	return mode
+}
+
+// destinationRuleService resolves a DestinationRule host to the in-cluster
+// Service it targets. Wildcard and external hosts do not resolve.
+func destinationRuleService(host, drNamespace string) (name, namespace string, ok bool) {
+	if host == "" || strings.Contains(host, "*") {
+		return "", "", false
+	}
+	host = strings.TrimSuffix(strings.TrimSuffix(host, ".cluster.local"), ".svc")
+	switch parts := strings.Split(host, "."); len(parts) {
+	case 1:
+		return parts[0], drNamespace, true
+	case 2:
+		return parts[0], parts[1], true
+	}
+	return "", "", false
+}
+
+// peerAuthConflicts returns findings for namespaces with several
+// namespace-wide PeerAuthentications, of which Istio only honours the oldest.
+func peerAuthConflicts(pas []peerAuthentication) []string {
+	var findingLines []string
+	nsWide := make(map[string][]string)
+	for _, pa := range pas {
+		if len(pa.Selector) == 0 {
+			nsWide[pa.Namespace] = append(nsWide[pa.Namespace], pa.Name)
+		}
+	}
+	for _, ns := range sortedListKeys(nsWide) {
+		if names := nsWide[ns]; len(names) > 1 {
+			sort.Strings(names)
+			findingLines = append(findingLines, findings.Format("KD-NET-006", "WARNING",
+				fmt.Sprintf("Namespace %s has %d namespace-wide PeerAuthentications (%s); Istio applies only the oldest", ns, len(names), strings.Join(names, ", "))))
+		}
+	}
+	return findingLines
+}
+
+// strictWithoutSidecar returns findings for running pods that fall under
+// STRICT mTLS but have no sidecar: the policy is not enforced for them, and as
+// clients they cannot reach STRICT peers.
+func strictWithoutSidecar(pas []peerAuthentication, root string, pods []corev1.Pod) []string {
+	var findingLines []string
+	unmeshed := make(map[string][]string)
+	for i := range pods {
+		p := &pods[i]
+		if p.Status.Phase != corev1.PodRunning || podMeshSidecar(p) != "" || p.Spec.HostNetwork {
+			continue
+		}
+		if effectiveMTLSMode(pas, root, p.Namespace, p.Labels) == "STRICT" {
+			unmeshed[p.Namespace] = append(unmeshed[p.Namespace], p.Name)
+		}
+	}
+	for _, ns := range sortedListKeys(unmeshed) {
+		names := unmeshed[ns]
+		findingLines = append(findingLines, findings.Format("KD-NET-006", "WARNING",
+			fmt.Sprintf("%d pod(s) in namespace %s fall under STRICT mTLS but have no sidecar; their calls to STRICT workloads are reset: %s", len(names), ns, joinLimited(names, 5))))
+	}
+	return findingLines
+}
+
+// destinationRuleConflicts returns findings for DestinationRules whose
+// client-side TLS contradicts what the destination pods accept. Both show up
+// as 503 "upstream connect error or disconnect/reset before headers".
+func destinationRuleConflicts(pas []peerAuthentication, drs []destinationRule, root string, services []corev1.Service, pods []corev1.Pod) []string {
+	var findingLines []string
+	svcByKey := make(map[string]*corev1.Service)
+	for i := range services {
+		svcByKey[services[i].Namespace+"/"+services[i].Name] = &services[i]
+	}
+	for _, dr := range drs {
+		if dr.TLSMode != "DISABLE" && dr.TLSMode != "ISTIO_MUTUAL" {
+			continue
+		}
+		name, ns, ok := destinationRuleService(dr.Host, dr.Namespace)
+		if !ok {
+			continue
+		}
+		svc, ok := svcByKey[ns+"/"+name]
+		if !ok || len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		sel := labels.SelectorFromSet(svc.Spec.Selector)
+		strict, noSidecar := 0, 0
+		for i := range pods {
+			p := &pods[i]
+			if p.Namespace != ns || p.Status.Phase != corev1.PodRunning || !sel.Matches(labels.Set(p.Labels)) {
+				continue
+			}
+			if podMeshSidecar(p) == "" {
+				noSidecar++
+			} else if effectiveMTLSMode(pas, root, ns, p.Labels) == "STRICT" {
+				strict++
+			}
+		}
+		switch {
+		case dr.TLSMode == "DISABLE" && strict > 0:
+			findingLines = append(findingLines, findings.Format("KD-NET-006", "CRITICAL",
+				fmt.Sprintf("DestinationRule %s/%s disables TLS to %s, but %d of its pods require STRICT mTLS; requests fail with 503 (connection reset)", dr.Namespace, dr.Name, dr.Host, strict)))
+		case dr.TLSMode == "ISTIO_MUTUAL" && noSidecar > 0:
+			findingLines = append(findingLines, findings.Format("KD-NET-006", "CRITICAL",
+				fmt.Sprintf("DestinationRule %s/%s forces ISTIO_MUTUAL to %s, but %d of its pods have no sidecar to terminate mTLS; requests fail with 503", dr.Namespace, dr.Name, dr.Host, noSidecar)))
+		}
+	}
+	return findingLines
+}
+
+func sortedListKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func peerAuthScope(pa peerAuthentication, root string) string {
+	switch {
+	case len(pa.Selector) > 0:
+		return "workload " + util.FormatLabels(pa.Selector)
+	case pa.Namespace == root:
+		return "mesh-wide"
+	default:
+		return "namespace"
+	}
+}
+
+func modeOrInherit(mode string) string {
+	if mode == "" {
+		return "(inherit)"
+	}
+	return mode
+}
+
+// writeRequestPathMesh adds the mesh layer to diagnose_request_path: sidecar
+// coverage of the backend pods, whether the ingress controller can reach them
+// under STRICT mTLS, and DestinationRule conflicts for the backend Service.
+// It returns the number of findings and suggested actions.
+func writeRequestPathMesh(ctx context.Context, client *k8s.ClusterClient, sb *strings.Builder, ing *networkingv1.Ingress, svc *corev1.Service, pods []corev1.Pod) (int, []string) {
+	meshes, err := detectMeshes(ctx, client)
+	if err != nil || len(meshes) == 0 {
+		return 0, nil
+	}
+	findingCount := 0
+	var actions []string
+	sb.WriteString("\n[MESH]\n")
+
+	meshed := 0
+	var missing []string
+	for i := range pods {
+		if podMeshSidecar(&pods[i]) != "" {
+			meshed++
+		} else {
+			missing = append(missing, pods[i].Name)
+		}
+	}
+	injection := ""
+	if ns, err := client.GetNamespace(ctx, svc.Namespace); err == nil {
+		injection = namespaceMeshInjection(ns)
+	}
+	if injection == "" {
+		injection = "disabled"
+	}
+	sb.WriteString(fmt.Sprintf("    Sidecars: %d/%d backend pods (namespace injection: %s)\n", meshed, len(pods), injection))
+	if len(missing) > 0 && (meshed > 0 || injection != "disabled") {
+		sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-NET-006", "WARNING", fmt.Sprintf("Backend pods without a sidecar: %s", joinLimited(missing, 5)))))
+		findingCount++
+		actions = append(actions, fmt.Sprintf("Restart the backend workload in %s so every pod gets a sidecar", svc.Namespace))
+	}
+
+	for _, m := range meshes {
+		if m.Type.Name != meshIstio {
+			continue
+		}
+		root := m.ControlPlane[0].Namespace
+		pas, drs, err := listIstioPolicies(ctx, client, svc.Namespace, root)
+		if err != nil {
+			sb.WriteString(fmt.Sprintf("    (could not read Istio policies: %v)\n", err))
+			continue
+		}
+		strict := false
+		for i := range pods {
+			if podMeshSidecar(&pods[i]) != "" && effectiveMTLSMode(pas, root, svc.Namespace, pods[i].Labels) == "STRICT" {
+				strict = true
+			}
+		}
+		if strict {
+			sb.WriteString("    mTLS: STRICT for backend pods\n")
+			if controllerPods, known := ingressControllerPods(ctx, client, ing); known {
+				inMesh := len(controllerPods) > 0
+				for i := range controllerPods {
+					if podMeshSidecar(&controllerPods[i]) == "" {
+						inMesh = false
+					}
+				}
+				if !inMesh {
+					sb.WriteString(fmt.Sprintf("    %s\n", findings.Format("KD-NET-006", "CRITICAL",
+						"Backend requires STRICT mTLS but the ingress controller pods have no sidecar; their plaintext requests are reset (502/503)")))
+					findingCount++
+					actions = append(actions, "Inject a sidecar into the ingress controller, use the Istio ingress gateway, or add a PERMISSIVE PeerAuthentication for the backend port")
+				}
+			}
+		}
+		var own []destinationRule
+		for _, dr := range drs {
+			if name, ns, ok := destinationRuleService(dr.Host, dr.Namespace); ok && name == svc.Name && ns == svc.Namespace {
+				own = append(own, dr)
+			}
+		}
+		for _, f := range destinationRuleConflicts(pas, own, root, []corev1.Service{*svc}, pods) {
+			sb.WriteString("    " + f + "\n")
+			findingCount++
+			actions = append(actions, fmt.Sprintf("Fix trafficPolicy.tls in the DestinationRule for %s to match what its pods accept", svc.Name))
+		}
+	}
+	return findingCount, actions
+}
+
+// ingressControllerPods returns the pods of the controller serving the
+// Ingress's class. known is false when the controller cannot be identified.
+func ingressControllerPods(ctx context.Context, client *k8s.ClusterClient, ing *networkingv1.Ingress) ([]corev1.Pod, bool) {
+	classes, err := client.ListIngressClasses(ctx)
+	if err != nil {
+		return nil, false
+	}
+	detected, err := detectIngressControllers(ctx, client, classes)
+	if err != nil {
+		return nil, false
+	}
+	className := ""
+	if ing.Spec.IngressClassName != nil {
+		className = *ing.Spec.IngressClassName
+	}
+	for _, d := range detected {
+		for _, c := range d.Classes {
+			if c == className {
+				return d.Pods, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func meshTestPod(ns, name string, labels map[string]string, sidecar bool) corev1.Pod {
+	p := testPod(ns, name, "", labels)
+	if sidecar {
+		p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Name: "istio-proxy"})
+	}
+	return p
+}
+
+func TestMeshCoverage(t *testing.T) {
+	namespaces := []corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"istio-injection": "enabled"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"istio.io/rev": "1-22"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "legacy"}},
+	}
+	optOut := meshTestPod("shop", "migrate", nil, false)
+	optOut.Annotations = map[string]string{"sidecar.istio.io/inject": "false"}
+	pods := []corev1.Pod{
+		meshTestPod("shop", "web-1", nil, true),
+		meshTestPod("shop", "web-2", nil, false),
+		optOut,
+		meshTestPod("payments", "api-1", nil, true),
+		meshTestPod("legacy", "cron", nil, false),
+	}
+
+	coverage := meshCoverage(namespaces, pods, "")
+	if len(coverage) != 2 {
+		t.Fatalf("expected only injected namespaces, got %+v", coverage)
+	}
+	if c := coverage[1]; c.Namespace != "shop" || c.Pods != 3 || c.WithSidecar != 1 || len(c.Missing) != 1 || c.Missing[0] != "web-2" {
+		t.Errorf("unexpected shop coverage: %+v", c)
+	}
+	if coverage[0].Injection != meshIstio {
+		t.Errorf("expected istio.io/rev to count as Istio injection, got %q", coverage[0].Injection)
+	}
+}
+
+func TestEffectiveMTLSMode(t *testing.T) {
+	pas := []peerAuthentication{
+		{Namespace: "istio-system", Name: "default", Mode: "STRICT"},
+		{Namespace: "legacy", Name: "default", Mode: "PERMISSIVE"},
+		{Namespace: "legacy", Name: "api", Selector: map[string]string{"app": "api"}, Mode: "STRICT"},
+		{Namespace: "shop", Name: "inherit"},
+	}
+	cases := []struct {
+		ns     string
+		labels map[string]string
+		want   string
+	}{
+		{"shop", nil, "STRICT"},
+		{"legacy", map[string]string{"app": "web"}, "PERMISSIVE"},
+		{"legacy", map[string]string{"app": "api"}, "STRICT"},
+	}
+	for _, c := range cases {
+		if got := effectiveMTLSMode(pas, "istio-system", c.ns, c.labels); got != c.want {
+			t.Errorf("effectiveMTLSMode(%s, %v) = %s, want %s", c.ns, c.labels, got, c.want)
+		}
+	}
+	if got := effectiveMTLSMode(nil, "istio-system", "shop", nil); got != "PERMISSIVE" {
+		t.Errorf("expected PERMISSIVE default, got %s", got)
+	}
+}
+
+func TestDestinationRuleService(t *testing.T) {
+	cases := []struct {
+		host, name, ns string
+		ok             bool
+	}{
+		{"reviews", "reviews", "shop", true},
+		{"reviews.bookinfo", "reviews", "bookinfo", true},
+		{"reviews.bookinfo.svc.cluster.local", "reviews", "bookinfo", true},
+		{"*.bookinfo.svc.cluster.local", "", "", false},
+		{"api.example.com", "", "", false},
+	}
+	for _, c := range cases {
+		name, ns, ok := destinationRuleService(c.host, "shop")
+		if name != c.name || ns != c.ns || ok != c.ok {
+			t.Errorf("destinationRuleService(%q) = %q, %q, %v", c.host, name, ns, ok)
+		}
+	}
+}
+
+func TestMTLSConflicts(t *testing.T) {
+	pa := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"namespace": "shop", "name": "strict"},
+		"spec":     map[string]any{"mtls": map[string]any{"mode": "STRICT"}},
+	}}
+	dr := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"namespace": "shop", "name": "cart"},
+		"spec": map[string]any{
+			"host":          "cart.shop.svc.cluster.local",
+			"trafficPolicy": map[string]any{"tls": map[string]any{"mode": "DISABLE"}},
+		},
+	}}
+	pas := []peerAuthentication{parsePeerAuthentication(pa)}
+	drs := []destinationRule{
+		parseDestinationRule(dr),
+		{Namespace: "shop", Name: "legacy", Host: "legacy", TLSMode: "ISTIO_MUTUAL"},
+	}
+	services := []corev1.Service{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "cart"}, Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "cart"}}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "legacy"}, Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "legacy"}}},
+	}
+	pods := []corev1.Pod{
+		meshTestPod("shop", "cart-1", map[string]string{"app": "cart"}, true),
+		meshTestPod("shop", "legacy-1", map[string]string{"app": "legacy"}, false),
+	}
+
+	conflicts := destinationRuleConflicts(pas, drs, "istio-system", services, pods)
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 DestinationRule conflicts, got %v", conflicts)
+	}
+	if !strings.Contains(conflicts[0], "disables TLS") || !strings.Contains(conflicts[1], "ISTIO_MUTUAL") {
+		t.Errorf("unexpected conflicts: %v", conflicts)
+	}
+
+	if got := strictWithoutSidecar(pas, "istio-system", pods); len(got) != 1 || !strings.Contains(got[0], "legacy-1") {
+		t.Errorf("expected legacy-1 to be flagged as STRICT without a sidecar, got %v", got)
+	}
+
+	dup := append(pas, peerAuthentication{Namespace: "shop", Name: "older", Mode: "PERMISSIVE"})
+	if got := peerAuthConflicts(dup); len(got) != 1 || !strings.Contains(got[0], "older, strict") {
+		t.Errorf("expected duplicate namespace-wide policies to be flagged, got %v", got)
+	}
+}
@@ -0,0 +1,327 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// defaultTerminationGracePeriod is the Kubernetes default for
+	// terminationGracePeriodSeconds.
+	defaultTerminationGracePeriod = 30
+	// stuckTerminatingAfter is how far past its deletion deadline a pod must
+	// be before it is reported as stuck.
+	stuckTerminatingAfter = time.Minute
+)
+
+// preStopSleepPattern finds the duration of a `sleep N` in a preStop exec command.
+var preStopSleepPattern = regexp.MustCompile(`\bsleep\s+(\d+)`)
+
+type analyzeShutdownInput struct {
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	WorkloadName   string `json:"workload_name,omitempty" jsonschema:"Name of a single Deployment, StatefulSet, or DaemonSet (empty for all workloads in the namespace)"`
+	WorkloadKind   string `json:"workload_kind,omitempty" jsonschema:"Kind: Deployment, StatefulSet, or DaemonSet (default: Deployment)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// shutdownIssue is a termination problem with one workload or pod.
+type shutdownIssue struct {
+	rule     string
+	severity string
+	message  string
+	action   string
+}
+
+func registerShutdownTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "analyze_shutdown_behavior",
+		Description: "Audit how pods shut down in a namespace. Flags workloads behind Services or Ingresses with terminationGracePeriodSeconds=0 " +
+			"or no preStop hook (requests are dropped during rollouts because endpoint removal lags SIGTERM), preStop delays that exceed the grace period, " +
+			"and pods stuck Terminating past their deadline on finalizers or an unreachable node.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeShutdownInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if input.Namespace == "" {
+			return util.ErrorResult("namespace is required"), nil, nil
+		}
+		workloads, err := collectProbeWorkloads(ctx, client, input.Namespace, input.WorkloadName, input.WorkloadKind)
+		if err != nil {
+			return util.HandleK8sError("listing workloads", err), nil, nil
+		}
+		services, err := client.ListServices(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing services", err), nil, nil
+		}
+		ingresses, err := client.ListIngresses(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing ingresses", err), nil, nil
+		}
+		pods, err := client.ListPods(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+
+		// Services referenced by an Ingress
+		ingressFor := make(map[string][]string)
+		for _, ing := range ingresses {
+			for _, rule := range ing.Spec.Rules {
+				if rule.HTTP == nil {
+					continue
+				}
+				for _, p := range rule.HTTP.Paths {
+					if p.Backend.Service != nil && !slices.Contains(ingressFor[p.Backend.Service.Name], ing.Name) {
+						ingressFor[p.Backend.Service.Name] = append(ingressFor[p.Backend.Service.Name], ing.Name)
+					}
+				}
+			}
+		}
+
+		scope := input.Namespace
+		if input.WorkloadName != "" {
+			scope = fmt.Sprintf("%s/%s", input.Namespace, input.WorkloadName)
+		}
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Shutdown Behavior: %s", scope)))
+		sb.WriteString("\n\n")
+
+		var rows [][]string
+		var findingLines, actions []string
+		for _, w := range workloads {
+			frontedBy := shutdownFrontedBy(w.Template.Labels, services, ingressFor)
+			preStop := "-"
+			for _, c := range w.Template.Spec.Containers {
+				if desc := describePreStop(c); desc != "" {
+					preStop = fmt.Sprintf("%s: %s", c.Name, desc)
+					break
+				}
+			}
+			rows = append(rows, []string{
+				truncateName(w.Kind+"/"+w.Name, 40),
+				fmt.Sprintf("%ds", terminationGracePeriod(&w.Template.Spec)),
+				truncateName(preStop, 30),
+				valueOrNone(strings.Join(frontedBy, ", ")),
+			})
+			for _, issue := range shutdownWorkloadIssues(w.Template.Spec, frontedBy) {
+				findingLines = append(findingLines, findings.Format(issue.rule, issue.severity, fmt.Sprintf("%s/%s %s", w.Kind, w.Name, issue.message)))
+				actions = append(actions, fmt.Sprintf("%s (%s/%s)", issue.action, w.Kind, w.Name))
+			}
+		}
+		if len(rows) > 0 {
+			sb.WriteString(util.FormatSubHeader("Workloads"))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatTable([]string{"WORKLOAD", "GRACE", "PRESTOP", "FRONTED BY"}, rows))
+		} else {
+			sb.WriteString("No Deployments, StatefulSets, or DaemonSets found.\n")
+		}
+
+		// Pods stuck in Terminating
+		now := time.Now()
+		nodeState := nodeStateLookup(ctx, client)
+		var stuckRows [][]string
+		var steps []util.NextStep
+		for i := range pods {
+			p := &pods[i]
+			if p.DeletionTimestamp == nil {
+				continue
+			}
+			overdue := now.Sub(p.DeletionTimestamp.Time)
+			if overdue < stuckTerminatingAfter {
+				continue
+			}
+			node, state := "-", nodeState(p.Spec.NodeName)
+			if p.Spec.NodeName != "" {
+				node = fmt.Sprintf("%s (%s)", p.Spec.NodeName, state)
+			}
+			stuckRows = append(stuckRows, []string{truncateName(p.Name, 50), overdue.Round(time.Second).String(), valueOrNone(strings.Join(p.Finalizers, ", ")), node})
+			issue := stuckTerminatingIssue(p, overdue, state)
+			findingLines = append(findingLines, findings.Format(issue.rule, issue.severity, issue.message))
+			actions = append(actions, issue.action)
+			if len(steps) < 3 {
+				steps = append(steps, nextStep("diagnose_pod", "explain why the pod has not finished terminating", "namespace", p.Namespace, "name", p.Name))
+			}
+		}
+		if len(stuckRows) > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("Pods Stuck Terminating"))
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatTable([]string{"POD", "PAST DEADLINE", "FINALIZERS", "NODE"}, stuckRows))
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "Serving workloads shut down gracefully and no pods are stuck terminating"))
+			sb.WriteString("\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString(f)
+			sb.WriteString("\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// terminationGracePeriod returns a pod spec's grace period with the default applied.
+func terminationGracePeriod(spec *corev1.PodSpec) int64 {
+	if spec.TerminationGracePeriodSeconds == nil {
+		return defaultTerminationGracePeriod
+	}
+	return *spec.TerminationGracePeriodSeconds
+}
+
+// preStopSeconds returns how long a container's preStop hook delays SIGTERM,
+// when that can be read from the spec: a sleep action or an exec `sleep N`.
+func preStopSeconds(c corev1.Container) (int64, bool) {
+	if c.Lifecycle == nil || c.Lifecycle.PreStop == nil {
+		return 0, false
+	}
+	h := c.Lifecycle.PreStop
+	if h.Sleep != nil {
+		return h.Sleep.Seconds, true
+	}
+	if h.Exec != nil {
+		if m := preStopSleepPattern.FindStringSubmatch(strings.Join(h.Exec.Command, " ")); m != nil {
+			n, err := strconv.ParseInt(m[1], 10, 64)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// describePreStop summarizes a container's preStop hook, "" if it has none.
+func describePreStop(c corev1.Container) string {
+	if c.Lifecycle == nil || c.Lifecycle.PreStop == nil {
+		return ""
+	}
+	h := c.Lifecycle.PreStop
+	switch {
+	case h.Sleep != nil:
+		return fmt.Sprintf("sleep %ds", h.Sleep.Seconds)
+	case h.Exec != nil:
+		return "exec " + strings.Join(h.Exec.Command, " ")
+	case h.HTTPGet != nil:
+		return "http " + h.HTTPGet.Path
+	}
+	return "hook"
+}
+
+// shutdownFrontedBy names the Services (and Ingresses routing to them) that
+// send traffic to pods with these labels.
+func shutdownFrontedBy(podLabels map[string]string, services []corev1.Service, ingressFor map[string][]string) []string {
+	var out []string
+	for _, svc := range services {
+		if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(podLabels)) {
+			continue
+		}
+		entry := "svc/" + svc.Name
+		if ings := ingressFor[svc.Name]; len(ings) > 0 {
+			entry += fmt.Sprintf(" (ingress %s)", strings.Join(ings, ", "))
+		}
+		out = append(out, entry)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// shutdownWorkloadIssues checks a pod template's termination settings.
+// Missing preStop hooks only matter for pods that receive Service traffic.
+func shutdownWorkloadIssues(spec corev1.PodSpec, frontedBy []string) []shutdownIssue {
+	var issues []shutdownIssue
+	grace := terminationGracePeriod(&spec)
+	serving := len(frontedBy) > 0
+
+	if grace == 0 {
+		severity := "WARNING"
+		detail := "containers are SIGKILLed immediately and cannot clean up"
+		if serving {
+			severity = "CRITICAL"
+			detail = fmt.Sprintf("containers are SIGKILLed immediately while %s still route to them, dropping in-flight requests", strings.Join(frontedBy, ", "))
+		}
+		issues = append(issues, shutdownIssue{"KD-WL-004", severity,
+			"has terminationGracePeriodSeconds=0: " + detail,
+			"Set terminationGracePeriodSeconds to at least the time the app needs to drain (default 30s)"})
+		return issues
+	}
+
+	var longest int64
+	hasPreStop := false
+	for _, c := range spec.Containers {
+		if c.Lifecycle != nil && c.Lifecycle.PreStop != nil {
+			hasPreStop = true
+		}
+		if s, ok := preStopSeconds(c); ok && s > longest {
+			longest = s
+		}
+	}
+	if serving && !hasPreStop {
+		issues = append(issues, shutdownIssue{"KD-WL-004", "WARNING",
+			fmt.Sprintf("serves traffic via %s but has no preStop hook: endpoint removal lags SIGTERM, so requests arriving during rollouts are dropped", strings.Join(frontedBy, ", ")),
+			"Add a preStop hook that sleeps 5-10s (lifecycle.preStop.sleep.seconds, or exec sleep) so endpoint removal propagates before shutdown"})
+	}
+	if longest >= grace {
+		issues = append(issues, shutdownIssue{"KD-WL-004", "WARNING",
+			fmt.Sprintf("has a preStop delay of %ds but terminationGracePeriodSeconds is %ds: the container is SIGKILLed before it can drain", longest, grace),
+			fmt.Sprintf("Raise terminationGracePeriodSeconds above the preStop delay plus drain time (e.g. %ds)", longest+30)})
+	}
+	return issues
+}
+
+// nodeStateLookup returns a function reporting a node's status, reading each
+// node once. It returns "unknown" for nodes that cannot be read and "" for an
+// empty name.
+func nodeStateLookup(ctx context.Context, client *k8s.ClusterClient) func(name string) string {
+	states := make(map[string]string)
+	return func(name string) string {
+		if name == "" {
+			return ""
+		}
+		state, ok := states[name]
+		if !ok {
+			state = "unknown"
+			if n, err := client.GetNode(ctx, name); err == nil {
+				state = nodeStatus(n)
+			}
+			states[name] = state
+		}
+		return state
+	}
+}
+
+// stuckTerminatingIssue explains why a pod is still present past its
+// deletion deadline. nodeState is the hosting node's status, if known.
+func stuckTerminatingIssue(p *corev1.Pod, overdue time.Duration, nodeState string) shutdownIssue {
+	past := overdue.Round(time.Second)
+	switch {
+	case len(p.Finalizers) > 0:
+		return shutdownIssue{"KD-POD-013", "CRITICAL",
+			fmt.Sprintf("Pod %s/%s is %s past its deletion deadline, blocked by finalizer(s) %s", p.Namespace, p.Name, past, strings.Join(p.Finalizers, ", ")),
+			fmt.Sprintf("Check that the controller owning finalizer %s is running; remove the finalizer only if that controller is gone (pod %s)", p.Finalizers[0], p.Name)}
+	case nodeState != "" && nodeState != "Ready" && nodeState != "unknown":
+		return shutdownIssue{"KD-POD-013", "CRITICAL",
+			fmt.Sprintf("Pod %s/%s is %s past its deletion deadline on node %s, which is %s; the kubelet cannot confirm the containers stopped", p.Namespace, p.Name, past, p.Spec.NodeName, nodeState),
+			fmt.Sprintf("Recover node %s, or confirm its VM is gone and force-delete pod %s", p.Spec.NodeName, p.Name)}
+	}
+	return shutdownIssue{"KD-POD-013", "WARNING",
+		fmt.Sprintf("Pod %s/%s is %s past its deletion deadline; its containers have not exited (SIGTERM ignored, a hanging preStop hook, or a volume that cannot unmount)", p.Namespace, p.Name, past),
+		fmt.Sprintf("Check pod %s's events for KillContainer or unmount errors and whether the app handles SIGTERM", p.Name)}
+}
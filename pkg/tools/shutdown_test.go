@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPreStopSeconds(t *testing.T) {
+	tests := []struct {
+		name string
+		hook *corev1.LifecycleHandler
+		want int64
+		ok   bool
+	}{
+		{"none", nil, 0, false},
+		{"sleep action", &corev1.LifecycleHandler{Sleep: &corev1.SleepAction{Seconds: 10}}, 10, true},
+		{"exec sleep", &corev1.LifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"/bin/sh", "-c", "sleep 15 && nginx -s quit"}}}, 15, true},
+		{"exec other", &corev1.LifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"/drain"}}}, 0, false},
+		{"http", &corev1.LifecycleHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/shutdown"}}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := corev1.Container{Name: "app"}
+			if tt.hook != nil {
+				c.Lifecycle = &corev1.Lifecycle{PreStop: tt.hook}
+			}
+			got, ok := preStopSeconds(c)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("preStopSeconds = %d, %v; want %d, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestShutdownFrontedBy(t *testing.T) {
+	services := []corev1.Service{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web"}, Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "web"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other"}, Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "other"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "external"}},
+	}
+	got := shutdownFrontedBy(map[string]string{"app": "web", "tier": "frontend"}, services, map[string][]string{"web": {"public"}})
+	if len(got) != 1 || got[0] != "svc/web (ingress public)" {
+		t.Errorf("shutdownFrontedBy = %v, want [svc/web (ingress public)]", got)
+	}
+}
+
+func TestShutdownWorkloadIssues(t *testing.T) {
+	zero, short := int64(0), int64(10)
+	preStop := &corev1.Lifecycle{PreStop: &corev1.LifecycleHandler{Sleep: &corev1.SleepAction{Seconds: 10}}}
+	fronted := []string{"svc/web"}
+
+	tests := []struct {
+		name      string
+		spec      corev1.PodSpec
+		frontedBy []string
+		severity  []string
+		contains  string
+	}{
+		{"graceful", corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Lifecycle: preStop}}}, fronted, nil, ""},
+		{"zero grace serving", corev1.PodSpec{TerminationGracePeriodSeconds: &zero, Containers: []corev1.Container{{Name: "app"}}}, fronted, []string{"CRITICAL"}, "dropping in-flight requests"},
+		{"zero grace worker", corev1.PodSpec{TerminationGracePeriodSeconds: &zero, Containers: []corev1.Container{{Name: "app"}}}, nil, []string{"WARNING"}, "cannot clean up"},
+		{"no preStop serving", corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}, fronted, []string{"WARNING"}, "no preStop hook"},
+		{"no preStop worker", corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}, nil, nil, ""},
+		{"preStop exceeds grace", corev1.PodSpec{TerminationGracePeriodSeconds: &short, Containers: []corev1.Container{{Name: "app", Lifecycle: preStop}}}, fronted, []string{"WARNING"}, "SIGKILLed before it can drain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := shutdownWorkloadIssues(tt.spec, tt.frontedBy)
+			if len(issues) != len(tt.severity) {
+				t.Fatalf("got %d issues, want %d: %+v", len(issues), len(tt.severity), issues)
+			}
+			for i, issue := range issues {
+				if issue.rule != "KD-WL-004" || issue.severity != tt.severity[i] {
+					t.Errorf("issue %d = %s %s, want KD-WL-004 %s", i, issue.rule, issue.severity, tt.severity[i])
+				}
+			}
+			if tt.contains != "" && !strings.Contains(issues[0].message, tt.contains) {
+				t.Errorf("message %q does not contain %q", issues[0].message, tt.contains)
+			}
+		})
+	}
+}
+
+func TestStuckTerminatingIssue(t *testing.T) {
+	pod := func(finalizers ...string) *corev1.Pod {
+		p := testPod("prod", "web-1", "node-a", nil)
+		p.Finalizers = finalizers
+		return &p
+	}
+
+	tests := []struct {
+		name      string
+		pod       *corev1.Pod
+		nodeState string
+		severity  string
+		contains  string
+	}{
+		{"finalizer", pod("example.com/cleanup"), "Ready", "CRITICAL", "finalizer(s) example.com/cleanup"},
+		{"node not ready", pod(), "NotReady", "CRITICAL", "node node-a, which is NotReady"},
+		{"node unknown", pod(), "unknown", "WARNING", "containers have not exited"},
+		{"ready node", pod(), "Ready", "WARNING", "containers have not exited"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := stuckTerminatingIssue(tt.pod, 5*time.Minute, tt.nodeState)
+			if issue.rule != "KD-POD-013" || issue.severity != tt.severity {
+				t.Errorf("issue = %s %s, want KD-POD-013 %s", issue.rule, issue.severity, tt.severity)
+			}
+			if !strings.Contains(issue.message, tt.contains) {
+				t.Errorf("message %q does not contain %q", issue.message, tt.contains)
+			}
+		})
+	}
+}
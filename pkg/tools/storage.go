@@ -6,16 +6,22 @@ import (
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
 	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
 )
 
 type listPVCsInput struct {
-	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
-type listPVsInput struct{}
+type listPVsInput struct {
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
+}
 
 func registerStorageTools(server *mcp.Server, client *k8s.ClusterClient) {
 	// list_pvcs
@@ -26,10 +32,11 @@ func registerStorageTools(server *mcp.Server, client *k8s.ClusterClient) {
 		ns := util.NamespaceOrAll(input.Namespace)
 		opts := util.ListOptions("", "")
 
-		pvcs, err := client.ListPVCs(ctx, ns, opts)
+		page, err := client.ListPVCsPage(ctx, ns, util.PageOptions(opts, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing PVCs", err), nil, nil
 		}
+		pvcs := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "STATUS", "CAPACITY", "STORAGE CLASS", "ACCESS MODES", "AGE"}
 		rows := make([][]string, 0, len(pvcs))
@@ -67,6 +74,7 @@ func registerStorageTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("PVCs", len(pvcs))))
+		sb.WriteString(pageFooter("list_pvcs", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -76,10 +84,11 @@ func registerStorageTools(server *mcp.Server, client *k8s.ClusterClient) {
 		Name:        "list_pvs",
 		Description: "List PersistentVolumes with status, capacity, reclaim policy, and storage class.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input listPVsInput) (*mcp.CallToolResult, any, error) {
-		pvs, err := client.ListPVs(ctx)
+		page, err := client.ListPVsPage(ctx, util.PageOptions(metav1.ListOptions{}, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing PVs", err), nil, nil
 		}
+		pvs := page.Items
 
 		headers := []string{"NAME", "STATUS", "CAPACITY", "RECLAIM POLICY", "STORAGE CLASS", "CLAIM", "AGE"}
 		rows := make([][]string, 0, len(pvs))
@@ -110,6 +119,7 @@ func registerStorageTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("PVs", len(pvs))))
+		sb.WriteString(pageFooter("list_pvs", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -0,0 +1,382 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// storageStuckThreshold is how long a PVC, PV, or attachment may stay in a
+	// transitional state before it is reported as stuck.
+	storageStuckThreshold = 5 * time.Minute
+
+	// defaultStorageClassAnnotation marks the cluster's default StorageClass.
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+)
+
+// storageMountEventReasons are pod event reasons that indicate a volume could not be attached or mounted.
+var storageMountEventReasons = map[string]bool{
+	"FailedMount":        true,
+	"FailedAttachVolume": true,
+	"FailedMapVolume":    true,
+}
+
+type diagnoseStorageInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace to check PVCs and pods in (empty for all namespaces)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+func registerStorageDiagnosticTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// diagnose_storage
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "diagnose_storage",
+		Description: "Diagnose persistent storage problems: PVCs stuck Pending (with StorageClass/provisioner reason), PVs stuck Released/Failed/Terminating, VolumeAttachment attach/detach errors, pods stuck in ContainerCreating because of mount errors, and CSI driver pod health.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input diagnoseStorageInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+
+		pvcs, err := client.ListPVCs(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing PVCs", err), nil, nil
+		}
+		classes, classErr := client.ListStorageClasses(ctx)
+		if classErr != nil && !util.IsScopeError(classErr) {
+			return util.HandleK8sError("listing storage classes", classErr), nil, nil
+		}
+		classByName := make(map[string]*storagev1.StorageClass, len(classes))
+		defaultClass := ""
+		for i := range classes {
+			classByName[classes[i].Name] = &classes[i]
+			if classes[i].Annotations[defaultStorageClassAnnotation] == "true" {
+				defaultClass = classes[i].Name
+			}
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Storage Diagnosis (scope: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+		findingCount := 0
+		var actions []string
+		var steps []util.NextStep
+		finding := func(rule, sev, msg string) {
+			sb.WriteString(findings.Format(rule, sev, msg))
+			sb.WriteString("\n")
+			findingCount++
+		}
+
+		// --- 1. Pending / terminating PVCs ---
+		sb.WriteString(util.FormatSubHeader("PersistentVolumeClaims"))
+		sb.WriteString("\n")
+		pvcIssues := 0
+		for i := range pvcs {
+			pvc := &pvcs[i]
+			if pvc.DeletionTimestamp != nil && time.Since(pvc.DeletionTimestamp.Time) > storageStuckThreshold {
+				finding("KD-STO-002", "WARNING", fmt.Sprintf("PVC %s/%s has been Terminating for %s (finalizers: %s) — still mounted by a pod?",
+					pvc.Namespace, pvc.Name, util.FormatAge(pvc.DeletionTimestamp.Time), strings.Join(pvc.Finalizers, ", ")))
+				actions = append(actions, fmt.Sprintf("Delete or scale down pods using PVC %s/%s so kubernetes.io/pvc-protection can be released", pvc.Namespace, pvc.Name))
+				pvcIssues++
+				continue
+			}
+			if pvc.Status.Phase != corev1.ClaimPending {
+				continue
+			}
+			pvcIssues++
+			className := defaultClass
+			if pvc.Spec.StorageClassName != nil {
+				className = *pvc.Spec.StorageClassName
+			}
+			sc := classByName[className]
+			if sc == nil && classErr != nil {
+				// StorageClasses are not readable in namespace-scoped mode; judge from events alone.
+				sc = &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: className}, Provisioner: "unknown provisioner"}
+			}
+			var events []corev1.Event
+			if evs, err := client.GetEventsForObject(ctx, pvc.Namespace, pvc.Name); err == nil {
+				events = evs
+			}
+			sev, reason := pendingPVCReason(className, sc, events)
+			finding("KD-STO-001", sev, fmt.Sprintf("PVC %s/%s Pending for %s: %s", pvc.Namespace, pvc.Name, util.FormatAge(pvc.CreationTimestamp.Time), reason))
+			if sev != "INFO" {
+				steps = append(steps, nextStep("get_events", "PVC is Pending", "namespace", pvc.Namespace, "involved_object", pvc.Name))
+			}
+			switch {
+			case className == "":
+				actions = append(actions, "Set storageClassName on Pending PVCs or mark a StorageClass as default")
+			case sc == nil:
+				actions = append(actions, fmt.Sprintf("Create StorageClass %q or fix storageClassName on PVC %s/%s", className, pvc.Namespace, pvc.Name))
+			case sev != "INFO":
+				actions = append(actions, fmt.Sprintf("Check the %s provisioner logs and CSI controller pods", sc.Provisioner))
+			}
+		}
+		if pvcIssues == 0 {
+			sb.WriteString(fmt.Sprintf("  All %d PVC(s) bound.\n", len(pvcs)))
+		}
+
+		// --- 2. PVs ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("PersistentVolumes"))
+		sb.WriteString("\n")
+		pvs, err := client.ListPVs(ctx)
+		if err != nil && !util.IsScopeError(err) {
+			return util.HandleK8sError("listing PVs", err), nil, nil
+		}
+		writeScopeSkipped(&sb, err)
+		pvIssues := 0
+		for i := range pvs {
+			pv := &pvs[i]
+			if !pvInScope(pv, input.Namespace) {
+				continue
+			}
+			sev, msg := pvProblem(pv)
+			if sev == "" {
+				continue
+			}
+			pvIssues++
+			finding("KD-STO-005", sev, msg)
+			if pv.Status.Phase == corev1.VolumeReleased {
+				actions = append(actions, "Delete or recycle Released PVs (clear spec.claimRef to rebind, or delete the PV and its backing disk)")
+			}
+		}
+		if err == nil && pvIssues == 0 {
+			sb.WriteString("  No stuck PVs.\n")
+		}
+
+		// --- 3. VolumeAttachments ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Volume Attachments"))
+		sb.WriteString("\n")
+		attachments, err := client.ListVolumeAttachments(ctx)
+		if util.IsScopeError(err) {
+			writeScopeSkipped(&sb, err)
+		} else if err != nil {
+			sb.WriteString(fmt.Sprintf("  Unable to list VolumeAttachments: %v\n", err))
+		} else {
+			vaIssues := 0
+			for i := range attachments {
+				if sev, msg := volumeAttachmentProblem(&attachments[i]); sev != "" {
+					vaIssues++
+					finding("KD-STO-005", sev, msg)
+				}
+			}
+			if vaIssues > 0 {
+				actions = append(actions, "For attach errors, confirm the disk is not still attached to another node (multi-attach) and that the node's VM has free disk slots")
+			} else {
+				sb.WriteString(fmt.Sprintf("  %d attachment(s), no errors.\n", len(attachments)))
+			}
+		}
+
+		// --- 4. Pods blocked on volume mounts ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Pods Waiting on Volumes"))
+		sb.WriteString("\n")
+		pods, err := client.ListPods(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		mountIssues := 0
+		for i := range pods {
+			p := &pods[i]
+			if !isPodContainerCreating(p) {
+				continue
+			}
+			events, err := client.GetEventsForObject(ctx, p.Namespace, p.Name)
+			if err != nil {
+				continue
+			}
+			if e := latestMountFailure(events); e != nil {
+				mountIssues++
+				finding("KD-STO-005", "CRITICAL", fmt.Sprintf("Pod %s/%s stuck in ContainerCreating: %s: %s", p.Namespace, p.Name, e.Reason, truncateName(e.Message, 200)))
+				steps = append(steps, nextStep("diagnose_pod", "Pod is blocked on "+e.Reason, "namespace", p.Namespace, "name", p.Name))
+			}
+		}
+		if mountIssues > 0 {
+			actions = append(actions, "Resolve the FailedMount/FailedAttachVolume errors above; check the referenced PVC, Secret, or ConfigMap exists and the CSI node plugin runs on the pod's node")
+		} else {
+			sb.WriteString("  No pods blocked on volume mounts.\n")
+		}
+
+		// --- 5. CSI drivers ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("CSI Drivers"))
+		sb.WriteString("\n")
+		drivers, err := client.ListCSIDrivers(ctx)
+		writeScopeSkipped(&sb, err)
+		if err == nil {
+			names := make([]string, 0, len(drivers))
+			for _, d := range drivers {
+				names = append(names, d.Name)
+			}
+			sort.Strings(names)
+			if len(names) > 0 {
+				sb.WriteString(fmt.Sprintf("  Registered: %s\n", strings.Join(names, ", ")))
+			} else {
+				sb.WriteString("  No CSIDrivers registered.\n")
+			}
+		}
+		allPods, err := client.ListPods(ctx, "", metav1.ListOptions{})
+		if err == nil {
+			csiPods, unhealthy := 0, 0
+			for i := range allPods {
+				p := &allPods[i]
+				if !isCSIDriverPod(p) {
+					continue
+				}
+				csiPods++
+				if !isPodHealthy(p) {
+					unhealthy++
+					finding("KD-STO-005", "CRITICAL", fmt.Sprintf("CSI driver pod %s/%s on node %s is not healthy: %s", p.Namespace, p.Name, p.Spec.NodeName, podPhaseReason(p)))
+					steps = append(steps, nextStep("diagnose_pod", "CSI driver pod is "+podPhaseReason(p), "namespace", p.Namespace, "name", p.Name))
+				}
+			}
+			if unhealthy > 0 {
+				actions = append(actions, "Restore CSI driver pods — volumes cannot be provisioned, attached, or mounted on affected nodes until they run")
+			} else if csiPods > 0 {
+				sb.WriteString(fmt.Sprintf("  %d CSI driver pod(s) healthy.\n", csiPods))
+			}
+		}
+
+		// --- Summary ---
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
+		sb.WriteString("\n")
+		if findingCount == 0 {
+			sb.WriteString("  Storage appears healthy. No issues found.\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("  %d issue(s) found. Review findings above.\n", findingCount))
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// pendingPVCReason explains why a PVC is Pending, using its StorageClass and
+// the most recent provisioning event. WaitForFirstConsumer claims are INFO.
+func pendingPVCReason(className string, sc *storagev1.StorageClass, events []corev1.Event) (string, string) {
+	if className == "" {
+		return "CRITICAL", "no storageClassName and no default StorageClass"
+	}
+	if sc == nil {
+		return "CRITICAL", fmt.Sprintf("StorageClass %q does not exist", className)
+	}
+
+	var latest *corev1.Event
+	for i := range events {
+		e := &events[i]
+		if latest == nil || k8s.EventTime(e).After(k8s.EventTime(latest)) {
+			latest = e
+		}
+	}
+	if sc.VolumeBindingMode != nil && *sc.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer &&
+		(latest == nil || latest.Reason == "WaitForFirstConsumer") {
+		return "INFO", fmt.Sprintf("StorageClass %s uses WaitForFirstConsumer; the volume is provisioned once a pod using it is scheduled", className)
+	}
+	if latest == nil {
+		return "WARNING", fmt.Sprintf("no provisioning events from %s", sc.Provisioner)
+	}
+	sev := "WARNING"
+	if latest.Type == corev1.EventTypeWarning {
+		sev = "CRITICAL"
+	}
+	return sev, fmt.Sprintf("%s (%s): %s", latest.Reason, sc.Provisioner, truncateName(latest.Message, 200))
+}
+
+// pvProblem reports a PV stuck Released, Failed, or Terminating.
+func pvProblem(pv *corev1.PersistentVolume) (string, string) {
+	if pv.DeletionTimestamp != nil && time.Since(pv.DeletionTimestamp.Time) > storageStuckThreshold {
+		return "WARNING", fmt.Sprintf("PV %s has been Terminating for %s (finalizers: %s)", pv.Name, util.FormatAge(pv.DeletionTimestamp.Time), strings.Join(pv.Finalizers, ", "))
+	}
+	switch pv.Status.Phase {
+	case corev1.VolumeFailed:
+		return "CRITICAL", fmt.Sprintf("PV %s is Failed: %s", pv.Name, pv.Status.Message)
+	case corev1.VolumeReleased:
+		claim := ""
+		if pv.Spec.ClaimRef != nil {
+			claim = fmt.Sprintf(" (was %s/%s)", pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name)
+		}
+		return "WARNING", fmt.Sprintf("PV %s is Released%s with reclaim policy %s — it will not be reused automatically", pv.Name, claim, pv.Spec.PersistentVolumeReclaimPolicy)
+	}
+	return "", ""
+}
+
+// pvInScope reports whether a PV belongs to the namespace via its claim; every PV is in scope when namespace is empty.
+func pvInScope(pv *corev1.PersistentVolume, namespace string) bool {
+	if namespace == "" || namespace == "all" {
+		return true
+	}
+	return pv.Spec.ClaimRef != nil && pv.Spec.ClaimRef.Namespace == namespace
+}
+
+// volumeAttachmentProblem reports attach/detach errors and attachments stuck un-attached.
+func volumeAttachmentProblem(va *storagev1.VolumeAttachment) (string, string) {
+	pv := "<inline>"
+	if va.Spec.Source.PersistentVolumeName != nil {
+		pv = *va.Spec.Source.PersistentVolumeName
+	}
+	if va.Status.AttachError != nil {
+		return "CRITICAL", fmt.Sprintf("Attaching PV %s to node %s failed (%s): %s", pv, va.Spec.NodeName, va.Spec.Attacher, va.Status.AttachError.Message)
+	}
+	if va.Status.DetachError != nil {
+		return "CRITICAL", fmt.Sprintf("Detaching PV %s from node %s failed (%s): %s", pv, va.Spec.NodeName, va.Spec.Attacher, va.Status.DetachError.Message)
+	}
+	if !va.Status.Attached && va.DeletionTimestamp == nil && time.Since(va.CreationTimestamp.Time) > storageStuckThreshold {
+		return "WARNING", fmt.Sprintf("PV %s has not attached to node %s after %s", pv, va.Spec.NodeName, util.FormatAge(va.CreationTimestamp.Time))
+	}
+	return "", ""
+}
+
+// isPodContainerCreating reports whether a pod is scheduled but still creating containers.
+func isPodContainerCreating(p *corev1.Pod) bool {
+	if p.Status.Phase != corev1.PodPending || p.Spec.NodeName == "" {
+		return false
+	}
+	for _, cs := range append(append([]corev1.ContainerStatus{}, p.Status.InitContainerStatuses...), p.Status.ContainerStatuses...) {
+		if cs.State.Waiting != nil && (cs.State.Waiting.Reason == "ContainerCreating" || cs.State.Waiting.Reason == "PodInitializing") {
+			return true
+		}
+	}
+	// Container statuses are absent until volumes are mounted
+	return len(p.Status.ContainerStatuses) == 0
+}
+
+// latestMountFailure returns the most recent volume attach/mount failure event, if any.
+func latestMountFailure(events []corev1.Event) *corev1.Event {
+	var latest *corev1.Event
+	for i := range events {
+		e := &events[i]
+		if !storageMountEventReasons[e.Reason] {
+			continue
+		}
+		if latest == nil || k8s.EventTime(e).After(k8s.EventTime(latest)) {
+			latest = e
+		}
+	}
+	return latest
+}
+
+// isCSIDriverPod reports whether a pod runs a CSI driver controller or node plugin.
+func isCSIDriverPod(p *corev1.Pod) bool {
+	for _, c := range p.Spec.Containers {
+		if c.Name == "csi-node-driver-registrar" || c.Name == "node-driver-registrar" || c.Name == "csi-provisioner" || c.Name == "csi-attacher" {
+			return true
+		}
+	}
+	return false
+}
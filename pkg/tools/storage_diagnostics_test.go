@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPendingPVCReason(t *testing.T) {
+	wffc := storagev1.VolumeBindingWaitForFirstConsumer
+	immediate := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fast"}, Provisioner: "disk.csi.azure.com"}
+	delayed := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "slow"}, Provisioner: "disk.csi.azure.com", VolumeBindingMode: &wffc}
+	failed := []corev1.Event{{
+		Type: corev1.EventTypeWarning, Reason: "ProvisioningFailed",
+		Message:       "failed to provision volume: QuotaExceeded",
+		LastTimestamp: metav1.NewTime(time.Now()),
+	}}
+
+	tests := []struct {
+		name      string
+		className string
+		sc        *storagev1.StorageClass
+		events    []corev1.Event
+		wantSev   string
+		wantText  string
+	}{
+		{"no class", "", nil, nil, "CRITICAL", "no default StorageClass"},
+		{"missing class", "premium", nil, nil, "CRITICAL", "does not exist"},
+		{"wait for consumer", "slow", delayed, nil, "INFO", "WaitForFirstConsumer"},
+		{"provisioning failed", "fast", immediate, failed, "CRITICAL", "QuotaExceeded"},
+		{"no events", "fast", immediate, nil, "WARNING", "no provisioning events"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sev, reason := pendingPVCReason(tt.className, tt.sc, tt.events)
+			if sev != tt.wantSev || !strings.Contains(reason, tt.wantText) {
+				t.Errorf("pendingPVCReason() = %s, %q; want %s containing %q", sev, reason, tt.wantSev, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestVolumeAttachmentProblem(t *testing.T) {
+	pvName := "pvc-123"
+	va := &storagev1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Now())},
+		Spec: storagev1.VolumeAttachmentSpec{
+			Attacher: "disk.csi.azure.com", NodeName: "node-1",
+			Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+		},
+		Status: storagev1.VolumeAttachmentStatus{AttachError: &storagev1.VolumeError{Message: "Multi-Attach error"}},
+	}
+	if sev, msg := volumeAttachmentProblem(va); sev != "CRITICAL" || !strings.Contains(msg, "pvc-123") {
+		t.Errorf("volumeAttachmentProblem() = %s, %q", sev, msg)
+	}
+
+	va.Status = storagev1.VolumeAttachmentStatus{Attached: true}
+	if sev, _ := volumeAttachmentProblem(va); sev != "" {
+		t.Errorf("expected attached volume to be healthy, got %s", sev)
+	}
+}
+
+func TestPVProblem(t *testing.T) {
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+			ClaimRef:                      &corev1.ObjectReference{Namespace: "db", Name: "data"},
+		},
+		Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeReleased},
+	}
+	if sev, msg := pvProblem(pv); sev != "WARNING" || !strings.Contains(msg, "db/data") {
+		t.Errorf("pvProblem() = %s, %q", sev, msg)
+	}
+	if !pvInScope(pv, "db") || pvInScope(pv, "web") {
+		t.Error("pvInScope() should match the claim namespace only")
+	}
+}
+
+func TestLatestMountFailure(t *testing.T) {
+	now := time.Now()
+	events := []corev1.Event{
+		{Reason: "Scheduled", LastTimestamp: metav1.NewTime(now.Add(-3 * time.Minute))},
+		{Reason: "FailedMount", Message: "old", LastTimestamp: metav1.NewTime(now.Add(-2 * time.Minute))},
+		{Reason: "FailedMount", Message: "new", LastTimestamp: metav1.NewTime(now)},
+	}
+	if e := latestMountFailure(events); e == nil || e.Message != "new" {
+		t.Errorf("latestMountFailure() = %+v, want the newest FailedMount", e)
+	}
+	if e := latestMountFailure(events[:1]); e != nil {
+		t.Errorf("expected no mount failure, got %+v", e)
+	}
+}
@@ -0,0 +1,288 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field names that common JSON loggers (zap, logrus, slog, pino, bunyan,
+// Serilog, ECS, OpenTelemetry) use for the fields analyze_service_logs reads.
+var (
+	jsonLevelFields   = []string{"level", "lvl", "severity", "log.level", "levelname", "@l"}
+	jsonMessageFields = []string{"msg", "message", "@m", "@mt"}
+	jsonErrorFields   = []string{"error", "err", "error.message", "exception", "@x", "stack_trace"}
+	jsonTraceFields   = []string{"trace_id", "traceId", "traceID", "trace.id", "dd.trace_id", "logging.googleapis.com/trace"}
+)
+
+// jsonLogDetectRatio is the share of non-empty lines that must parse as JSON
+// objects before analyze_service_logs switches to structured analysis.
+const jsonLogDetectRatio = 0.5
+
+// jsonErrorLevels are the normalized levels counted as errors.
+var jsonErrorLevels = map[string]bool{"error": true, "fatal": true, "panic": true, "critical": true, "alert": true, "emergency": true}
+
+// jsonLogLine is one parsed JSON log line.
+type jsonLogLine struct {
+	Pod     string
+	Level   string
+	Message string
+	Error   string
+	TraceID string
+	Fields  map[string]any
+}
+
+// IsError reports whether the line is logged at an error level or carries an
+// error field.
+func (l jsonLogLine) IsError() bool {
+	return jsonErrorLevels[l.Level] || l.Error != ""
+}
+
+// logFieldFilter is one condition of analyze_service_logs' filter input, such
+// as level=error or status>=500.
+type logFieldFilter struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// logFilterSyntax splits "field op value" with the two-character operators first.
+var logFilterSyntax = regexp.MustCompile(`^\s*([A-Za-z0-9_.@/-]+)\s*(>=|<=|!=|=|>|<)\s*(.*?)\s*$`)
+
+// parseLogFilter parses a comma-separated list of field conditions.
+func parseLogFilter(s string) ([]logFieldFilter, error) {
+	var filters []logFieldFilter
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		m := logFilterSyntax.FindStringSubmatch(part)
+		if m == nil {
+			return nil, fmt.Errorf("invalid filter %q: use field=value, field!=value, or a numeric comparison like status>=500", strings.TrimSpace(part))
+		}
+		filters = append(filters, logFieldFilter{Field: m[1], Op: m[2], Value: strings.Trim(m[3], `"'`)})
+	}
+	return filters, nil
+}
+
+// Matches reports whether the line satisfies the condition. level compares
+// against the normalized level; ordering operators compare numerically.
+func (f logFieldFilter) Matches(l jsonLogLine) bool {
+	var actual string
+	var ok bool
+	if strings.EqualFold(f.Field, "level") {
+		actual, ok = l.Level, l.Level != ""
+	} else {
+		var v any
+		if v, ok = jsonField(l.Fields, f.Field); ok {
+			actual = jsonString(v)
+		}
+	}
+	switch f.Op {
+	case "=":
+		return ok && strings.EqualFold(actual, f.Value)
+	case "!=":
+		return !ok || !strings.EqualFold(actual, f.Value)
+	}
+	a, errA := strconv.ParseFloat(actual, 64)
+	b, errB := strconv.ParseFloat(f.Value, 64)
+	if !ok || errA != nil || errB != nil {
+		return false
+	}
+	switch f.Op {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	default:
+		return a <= b
+	}
+}
+
+// parseJSONLogLine parses a log line that is a JSON object.
+func parseJSONLogLine(pod, line string) (jsonLogLine, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "{") {
+		return jsonLogLine{}, false
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return jsonLogLine{}, false
+	}
+	l := jsonLogLine{Pod: pod, Fields: fields}
+	if v, ok := firstJSONField(fields, jsonLevelFields); ok {
+		l.Level = normalizeLogLevel(v)
+	}
+	if v, ok := firstJSONField(fields, jsonMessageFields); ok {
+		l.Message = jsonString(v)
+	}
+	if v, ok := firstJSONField(fields, jsonErrorFields); ok {
+		l.Error = jsonString(v)
+	}
+	if v, ok := firstJSONField(fields, jsonTraceFields); ok {
+		l.TraceID = jsonString(v)
+	}
+	return l, true
+}
+
+// jsonField looks up a field by its flat key, then as a dotted path into
+// nested objects (so "log.level" finds both {"log.level": ..} and {"log": {"level": ..}}).
+func jsonField(fields map[string]any, key string) (any, bool) {
+	if v, ok := fields[key]; ok {
+		return v, true
+	}
+	head, rest, nested := strings.Cut(key, ".")
+	if !nested {
+		return nil, false
+	}
+	inner, ok := fields[head].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return jsonField(inner, rest)
+}
+
+func firstJSONField(fields map[string]any, keys []string) (any, bool) {
+	for _, k := range keys {
+		if v, ok := jsonField(fields, k); ok && v != nil && jsonString(v) != "" {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// jsonString renders a decoded JSON value for display and comparison.
+func jsonString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case nil:
+		return ""
+	case map[string]any:
+		// Structured errors such as {"message": "...", "type": "..."}.
+		if m, ok := firstJSONField(t, []string{"message", "msg"}); ok {
+			return jsonString(m)
+		}
+	}
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
+
+// normalizeLogLevel maps level names and the numeric levels of pino and
+// bunyan onto one lowercase vocabulary.
+func normalizeLogLevel(v any) string {
+	if n, ok := v.(float64); ok {
+		switch {
+		case n >= 60:
+			return "fatal"
+		case n >= 50:
+			return "error"
+		case n >= 40:
+			return "warn"
+		case n >= 30:
+			return "info"
+		case n >= 20:
+			return "debug"
+		default:
+			return "trace"
+		}
+	}
+	level := strings.ToLower(strings.TrimSpace(jsonString(v)))
+	switch level {
+	case "warning":
+		return "warn"
+	case "err", "eror":
+		return "error"
+	case "crit":
+		return "critical"
+	case "emerg":
+		return "emergency"
+	case "information":
+		return "info"
+	case "dpanic":
+		return "panic"
+	}
+	return level
+}
+
+// jsonLogSummary aggregates parsed JSON log lines.
+type jsonLogSummary struct {
+	Levels map[string]int
+	Errors []jsonErrorGroup
+}
+
+// jsonErrorGroup is an error message shared by several log lines, with numbers
+// and hashes masked so that one failure reported many times groups together.
+type jsonErrorGroup struct {
+	Message  string
+	Count    int
+	Pods     map[string]bool
+	TraceIDs []string
+}
+
+// summarizeJSONLogs counts lines per level and groups error lines by message.
+func summarizeJSONLogs(lines []jsonLogLine) jsonLogSummary {
+	summary := jsonLogSummary{Levels: make(map[string]int)}
+	groups := make(map[string]*jsonErrorGroup)
+	for _, l := range lines {
+		level := l.Level
+		if level == "" {
+			level = "(none)"
+		}
+		summary.Levels[level]++
+		if !l.IsError() {
+			continue
+		}
+		msg := l.Error
+		if msg == "" {
+			msg = l.Message
+		}
+		key := crMessageVolatile.ReplaceAllString(msg, "*")
+		g, ok := groups[key]
+		if !ok {
+			g = &jsonErrorGroup{Message: msg, Pods: make(map[string]bool)}
+			groups[key] = g
+		}
+		g.Count++
+		g.Pods[l.Pod] = true
+		if l.TraceID != "" && len(g.TraceIDs) < 3 {
+			g.TraceIDs = append(g.TraceIDs, l.TraceID)
+		}
+	}
+	for _, g := range groups {
+		summary.Errors = append(summary.Errors, *g)
+	}
+	sort.Slice(summary.Errors, func(i, j int) bool {
+		if summary.Errors[i].Count != summary.Errors[j].Count {
+			return summary.Errors[i].Count > summary.Errors[j].Count
+		}
+		return summary.Errors[i].Message < summary.Errors[j].Message
+	})
+	return summary
+}
+
+// formatJSONLogLine renders a parsed line compactly: level, message, error, trace.
+func formatJSONLogLine(l jsonLogLine) string {
+	level := "-"
+	if l.Level != "" {
+		level = strings.ToUpper(l.Level)
+	}
+	parts := []string{level}
+	if l.Message != "" {
+		parts = append(parts, truncateName(l.Message, 160))
+	}
+	if l.Error != "" {
+		parts = append(parts, "error="+truncateName(l.Error, 160))
+	}
+	if l.TraceID != "" {
+		parts = append(parts, "trace_id="+l.TraceID)
+	}
+	return strings.Join(parts, " ")
+}
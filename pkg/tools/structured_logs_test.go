@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"testing"
+)
+
+func TestParseJSONLogLine(t *testing.T) {
+	l, ok := parseJSONLogLine("web-1", `{"level":"warning","msg":"slow query","trace_id":"abc123","db":{"ms":812}}`)
+	if !ok || l.Level != "warn" || l.Message != "slow query" || l.TraceID != "abc123" || l.IsError() {
+		t.Errorf("zap-style line = %+v", l)
+	}
+	l, ok = parseJSONLogLine("web-1", `{"level":50,"message":"charge failed","err":{"message":"card declined","type":"PaymentError"}}`)
+	if !ok || l.Level != "error" || l.Error != "card declined" || !l.IsError() {
+		t.Errorf("pino-style line = %+v", l)
+	}
+	l, ok = parseJSONLogLine("web-1", `{"log":{"level":"ERROR"},"message":"boom"}`)
+	if !ok || l.Level != "error" {
+		t.Errorf("ECS nested level = %+v", l)
+	}
+	if _, ok := parseJSONLogLine("web-1", "2026/10/15 plain text error"); ok {
+		t.Error("plain text parsed as JSON")
+	}
+}
+
+func TestLogFieldFilter(t *testing.T) {
+	filters, err := parseLogFilter("level=error, status>=500,path!=/healthz")
+	if err != nil || len(filters) != 3 {
+		t.Fatalf("parseLogFilter() = %+v, %v", filters, err)
+	}
+	matchAll := func(line string) bool {
+		l, _ := parseJSONLogLine("p", line)
+		for _, f := range filters {
+			if !f.Matches(l) {
+				return false
+			}
+		}
+		return true
+	}
+	if !matchAll(`{"level":"ERROR","status":503,"path":"/pay"}`) {
+		t.Error("expected a 503 error on /pay to match")
+	}
+	if matchAll(`{"level":"error","status":404,"path":"/pay"}`) {
+		t.Error("status 404 should not match status>=500")
+	}
+	if matchAll(`{"level":"error","status":500,"path":"/healthz"}`) {
+		t.Error("path /healthz should not match path!=/healthz")
+	}
+	if _, err := parseLogFilter("status~5.."); err == nil {
+		t.Error("expected an error for an unsupported operator")
+	}
+}
+
+func TestSummarizeJSONLogs(t *testing.T) {
+	var lines []jsonLogLine
+	for _, raw := range []string{
+		`{"level":"info","msg":"ok"}`,
+		`{"level":"error","msg":"request failed","error":"dial tcp 10.0.0.12:5432: connection refused","trace_id":"t1"}`,
+		`{"level":"error","msg":"request failed","error":"dial tcp 10.0.0.13:5432: connection refused","trace_id":"t2"}`,
+		`{"level":"error","msg":"invalid token"}`,
+	} {
+		l, _ := parseJSONLogLine("web-1", raw)
+		lines = append(lines, l)
+	}
+	summary := summarizeJSONLogs(lines)
+	if summary.Levels["error"] != 3 || summary.Levels["info"] != 1 {
+		t.Errorf("Levels = %v", summary.Levels)
+	}
+	if len(summary.Errors) != 2 || summary.Errors[0].Count != 2 || len(summary.Errors[0].TraceIDs) != 2 {
+		t.Errorf("Errors = %+v, want the two connection refused lines grouped first", summary.Errors)
+	}
+}
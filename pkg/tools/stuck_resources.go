@@ -0,0 +1,379 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// stuckDeletionAfter is how long an object may stay in Terminating before it
+// is reported as stuck. Pods use stuckTerminatingAfter past their deadline.
+const stuckDeletionAfter = 5 * time.Minute
+
+// knownFinalizerOwners names the controllers behind common finalizers.
+// Entries ending in "/" match any finalizer with that prefix.
+var knownFinalizerOwners = []struct{ finalizer, owner string }{
+	{"kubernetes", "namespace controller (kube-controller-manager), which deletes the namespace's content"},
+	{"kubernetes.io/pvc-protection", "pvc-protection controller (kube-controller-manager), released when no pod uses the claim"},
+	{"kubernetes.io/pv-protection", "pv-protection controller (kube-controller-manager), released when the PV is no longer bound"},
+	{"foregroundDeletion", "garbage collector (kube-controller-manager), released once the dependents are deleted"},
+	{"orphan", "garbage collector (kube-controller-manager), released once the dependents are orphaned"},
+	{"batch.kubernetes.io/job-tracking", "job controller (kube-controller-manager)"},
+	{"service.kubernetes.io/load-balancer-cleanup", "cloud-controller-manager, which deletes the cloud load balancer"},
+	{"external-attacher/", "the CSI driver's external-attacher sidecar"},
+	{"snapshot.storage.kubernetes.io/", "CSI snapshot-controller"},
+	{"finalizers.fluxcd.io", "the Flux controller that reconciles the object"},
+	{"resources-finalizer.argocd.argoproj.io", "Argo CD application controller, which prunes the app's resources"},
+}
+
+type findStuckResourcesInput struct {
+	Namespace           string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for all namespaces)"`
+	SkipCustomResources bool   `json:"skip_custom_resources,omitempty" jsonschema:"Skip scanning every CRD's objects (faster on clusters with many CRDs)"`
+	TimeoutSeconds      int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// stuckResource is an object that was deleted but is still present.
+type stuckResource struct {
+	Kind       string
+	Group      string
+	Namespace  string
+	Name       string
+	Since      time.Time
+	Finalizers []string
+}
+
+func (r stuckResource) ref() string {
+	if r.Namespace == "" {
+		return r.Name
+	}
+	return r.Namespace + "/" + r.Name
+}
+
+func registerStuckResourceTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "find_stuck_resources",
+		Description: "Find namespaces, pods, PVCs, and custom resources stuck in Terminating. Lists each object's pending finalizers and the controller " +
+			"that owns each finalizer, explains what blocks it (content left in a namespace, pods still mounting a PVC, an unreachable node, " +
+			"an uninstalled operator), and suggests next steps.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input findStuckResourcesInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		now := time.Now()
+
+		var namespaces []corev1.Namespace
+		if input.Namespace != "" {
+			ns, err := client.GetNamespace(ctx, input.Namespace)
+			if err != nil {
+				return util.HandleK8sError("getting namespace "+input.Namespace, err), nil, nil
+			}
+			namespaces = []corev1.Namespace{*ns}
+		} else {
+			var err error
+			namespaces, err = client.ListNamespaces(ctx)
+			if err != nil {
+				return util.HandleK8sError("listing namespaces", err), nil, nil
+			}
+		}
+		pods, err := client.ListPods(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		pvcs, err := client.ListPVCs(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing PVCs", err), nil, nil
+		}
+
+		var stuck []stuckResource
+		var findingLines, actions []string
+		var steps []util.NextStep
+		addStep := func(step util.NextStep) {
+			if len(steps) < 5 {
+				steps = append(steps, step)
+			}
+		}
+
+		for i := range namespaces {
+			ns := &namespaces[i]
+			if ns.DeletionTimestamp == nil || now.Sub(ns.DeletionTimestamp.Time) < stuckDeletionAfter {
+				continue
+			}
+			finalizers := dedupe(append(namespaceSpecFinalizers(ns), ns.Finalizers...))
+			stuck = append(stuck, stuckResource{Kind: "Namespace", Name: ns.Name, Since: ns.DeletionTimestamp.Time, Finalizers: finalizers})
+			issue := stuckNamespaceIssue(ns, util.FormatAge(ns.DeletionTimestamp.Time))
+			findingLines = append(findingLines, findings.Format(issue.rule, issue.severity, issue.message))
+			actions = append(actions, issue.action)
+			addStep(nextStep("diagnose_namespace", "see what is still running in the terminating namespace", "namespace", ns.Name))
+		}
+
+		nodeState := nodeStateLookup(ctx, client)
+		for i := range pods {
+			p := &pods[i]
+			if p.DeletionTimestamp == nil {
+				continue
+			}
+			overdue := now.Sub(p.DeletionTimestamp.Time)
+			if overdue < stuckTerminatingAfter {
+				continue
+			}
+			stuck = append(stuck, stuckResource{Kind: "Pod", Namespace: p.Namespace, Name: p.Name, Since: p.DeletionTimestamp.Time, Finalizers: p.Finalizers})
+			issue := stuckTerminatingIssue(p, overdue, nodeState(p.Spec.NodeName))
+			findingLines = append(findingLines, findings.Format(issue.rule, issue.severity, issue.message))
+			actions = append(actions, issue.action)
+			addStep(nextStep("diagnose_pod", "explain why the pod has not finished terminating", "namespace", p.Namespace, "name", p.Name))
+		}
+
+		users := claimUsers(pods)
+		for i := range pvcs {
+			pvc := &pvcs[i]
+			if pvc.DeletionTimestamp == nil || now.Sub(pvc.DeletionTimestamp.Time) < stuckDeletionAfter {
+				continue
+			}
+			stuck = append(stuck, stuckResource{Kind: "PersistentVolumeClaim", Namespace: pvc.Namespace, Name: pvc.Name, Since: pvc.DeletionTimestamp.Time, Finalizers: pvc.Finalizers})
+			issue := stuckPVCIssue(pvc, users[pvc.Namespace+"/"+pvc.Name], util.FormatAge(pvc.DeletionTimestamp.Time))
+			findingLines = append(findingLines, findings.Format(issue.rule, issue.severity, issue.message))
+			actions = append(actions, issue.action)
+			addStep(nextStep("diagnose_storage", "check the claim's volume and attachments", "namespace", pvc.Namespace))
+		}
+
+		// Custom resources: every CRD's objects, reporting progress per CRD.
+		var crNote string
+		if !input.SkipCustomResources {
+			crds, err := client.ListCRDs(ctx)
+			switch {
+			case util.IsScopeError(err):
+				crNote = "Custom resources skipped: " + err.Error()
+			case err != nil:
+				crNote = fmt.Sprintf("Unable to list CRDs: %v", err)
+			default:
+				progress := util.NewProgress(req)
+				failed := 0
+				for i := range crds {
+					crd := &crds[i]
+					progress.Report(ctx, float64(i), float64(len(crds)), "scanning "+crd.Name)
+					items, err := listCRDObjects(ctx, client, crd, input.Namespace)
+					if err != nil {
+						failed++
+						continue
+					}
+					for j := range items {
+						u := &items[j]
+						ts := u.GetDeletionTimestamp()
+						if ts == nil || now.Sub(ts.Time) < stuckDeletionAfter {
+							continue
+						}
+						r := stuckResource{Kind: u.GetKind(), Group: crd.Spec.Group, Namespace: u.GetNamespace(), Name: u.GetName(), Since: ts.Time, Finalizers: u.GetFinalizers()}
+						stuck = append(stuck, r)
+						issue := stuckCustomResourceIssue(r, util.FormatAge(ts.Time))
+						findingLines = append(findingLines, findings.Format(issue.rule, issue.severity, issue.message))
+						actions = append(actions, issue.action)
+						addStep(nextStep("check_crd_conditions", "read the stuck object's status conditions", "kind", r.Kind, "group", r.Group, "namespace", r.Namespace))
+					}
+				}
+				progress.Report(ctx, float64(len(crds)), float64(len(crds)), "custom resources scanned")
+				crNote = fmt.Sprintf("Scanned %d CRD(s) for terminating objects", len(crds))
+				if failed > 0 {
+					crNote += fmt.Sprintf("; %d could not be listed", failed)
+				}
+			}
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Stuck Resources (scope: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n\n")
+		if crNote != "" {
+			sb.WriteString(crNote + "\n\n")
+		}
+
+		if len(stuck) == 0 {
+			sb.WriteString("No resources are stuck in Terminating.\n")
+			sb.WriteString("\nFINDINGS:\n")
+			sb.WriteString(util.FormatFinding("OK", "No namespaces, pods, PVCs, or custom resources are stuck in Terminating"))
+			sb.WriteString("\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		sort.SliceStable(stuck, func(i, j int) bool { return stuck[i].Since.Before(stuck[j].Since) })
+		sb.WriteString(util.FormatSubHeader("Terminating Objects"))
+		sb.WriteString("\n")
+		var rows [][]string
+		for _, r := range stuck {
+			rows = append(rows, []string{r.Kind, truncateName(r.ref(), 60), util.FormatAge(r.Since), valueOrNone(strings.Join(r.Finalizers, ", "))})
+		}
+		sb.WriteString(util.FormatTable([]string{"KIND", "NAME", "TERMINATING", "FINALIZERS"}, rows))
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Finalizer Owners"))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatTable([]string{"FINALIZER", "OWNER", "OBJECTS"}, finalizerOwnerRows(stuck)))
+
+		sb.WriteString("\nFINDINGS:\n")
+		for _, f := range findingLines {
+			sb.WriteString(f)
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\nSUGGESTED ACTIONS:\n")
+		for i, a := range dedupe(actions) {
+			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// listCRDObjects lists a CRD's objects at its storage version. Cluster-scoped
+// kinds are only listed when no namespace is requested.
+func listCRDObjects(ctx context.Context, client *k8s.ClusterClient, crd *apiextensionsv1.CustomResourceDefinition, namespace string) ([]unstructured.Unstructured, error) {
+	version := ""
+	for _, v := range crd.Spec.Versions {
+		if v.Served && (version == "" || v.Storage) {
+			version = v.Name
+		}
+	}
+	if version == "" {
+		return nil, nil
+	}
+	gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: version, Resource: crd.Spec.Names.Plural}
+	if crd.Spec.Scope == apiextensionsv1.ClusterScoped {
+		if namespace != "" {
+			return nil, nil
+		}
+		return client.ListClusterCustomResources(ctx, gvr, metav1.ListOptions{})
+	}
+	return client.ListCustomResources(ctx, gvr, namespace, metav1.ListOptions{})
+}
+
+// finalizerOwner names the controller responsible for removing a finalizer.
+// group is the API group of the object carrying it, used to attribute
+// operator finalizers to the operator that owns the CRD.
+func finalizerOwner(finalizer, group string) string {
+	for _, k := range knownFinalizerOwners {
+		if finalizer == k.finalizer || (strings.HasSuffix(k.finalizer, "/") && strings.HasPrefix(finalizer, k.finalizer)) {
+			return k.owner
+		}
+	}
+	domain, _, found := strings.Cut(finalizer, "/")
+	if group != "" && (domain == group || strings.HasSuffix(domain, "."+group) || strings.HasSuffix(group, "."+domain)) {
+		return fmt.Sprintf("the operator that manages %s resources", group)
+	}
+	if found && strings.Contains(domain, ".") {
+		return "the controller for " + domain
+	}
+	return "unknown controller"
+}
+
+// finalizerOwnerRows lists each pending finalizer once with its owner and
+// how many stuck objects carry it.
+func finalizerOwnerRows(stuck []stuckResource) [][]string {
+	counts := make(map[string]int)
+	owners := make(map[string]string)
+	var order []string
+	for _, r := range stuck {
+		for _, f := range r.Finalizers {
+			if counts[f] == 0 {
+				order = append(order, f)
+				owners[f] = finalizerOwner(f, r.Group)
+			}
+			counts[f]++
+		}
+	}
+	rows := make([][]string, 0, len(order))
+	for _, f := range order {
+		rows = append(rows, []string{f, owners[f], fmt.Sprintf("%d", counts[f])})
+	}
+	if len(rows) == 0 {
+		rows = append(rows, []string{"<none>", "-", "0"})
+	}
+	return rows
+}
+
+// namespaceSpecFinalizers returns the namespace's spec.finalizers, which the
+// namespace controller removes once the namespace is empty.
+func namespaceSpecFinalizers(ns *corev1.Namespace) []string {
+	out := make([]string, 0, len(ns.Spec.Finalizers))
+	for _, f := range ns.Spec.Finalizers {
+		out = append(out, string(f))
+	}
+	return out
+}
+
+// stuckNamespaceIssue explains a namespace stuck in Terminating from the
+// deletion conditions the namespace controller records.
+func stuckNamespaceIssue(ns *corev1.Namespace, age string) shutdownIssue {
+	var blockers []string
+	discovery := false
+	for _, c := range ns.Status.Conditions {
+		if c.Status != corev1.ConditionTrue || c.Message == "" {
+			continue
+		}
+		blockers = append(blockers, c.Message)
+		if c.Type == corev1.NamespaceDeletionDiscoveryFailure {
+			discovery = true
+		}
+	}
+	msg := fmt.Sprintf("Namespace %s has been Terminating for %s", ns.Name, age)
+	if len(blockers) > 0 {
+		msg += ": " + strings.Join(blockers, "; ")
+	}
+	action := fmt.Sprintf("Resolve the finalizers on the objects still in namespace %s (listed above); the namespace finishes deleting once it is empty", ns.Name)
+	if discovery {
+		action = fmt.Sprintf("Fix or delete the unavailable APIService (kubectl get apiservice | grep False) so deletion of namespace %s can enumerate its content", ns.Name)
+	}
+	return shutdownIssue{"KD-SYS-002", "WARNING", msg, action}
+}
+
+// claimUsers maps namespace/claim to the pods that mount it.
+func claimUsers(pods []corev1.Pod) map[string][]string {
+	users := make(map[string][]string)
+	for i := range pods {
+		p := &pods[i]
+		if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, v := range p.Spec.Volumes {
+			if v.PersistentVolumeClaim != nil {
+				key := p.Namespace + "/" + v.PersistentVolumeClaim.ClaimName
+				users[key] = append(users[key], p.Name)
+			}
+		}
+	}
+	return users
+}
+
+// stuckPVCIssue explains a PVC stuck in Terminating; users are the pods that
+// still mount it.
+func stuckPVCIssue(pvc *corev1.PersistentVolumeClaim, users []string, age string) shutdownIssue {
+	msg := fmt.Sprintf("PVC %s/%s has been Terminating for %s (finalizers: %s)", pvc.Namespace, pvc.Name, age, valueOrNone(strings.Join(pvc.Finalizers, ", ")))
+	if len(users) > 0 {
+		return shutdownIssue{"KD-STO-002", "WARNING",
+			fmt.Sprintf("%s: still mounted by pod(s) %s", msg, strings.Join(users, ", ")),
+			fmt.Sprintf("Delete or scale down pod(s) %s so kubernetes.io/pvc-protection releases PVC %s/%s", strings.Join(users, ", "), pvc.Namespace, pvc.Name)}
+	}
+	return shutdownIssue{"KD-STO-002", "WARNING",
+		msg + ": no pod mounts it, so the controller holding the finalizer is not making progress",
+		fmt.Sprintf("Check kube-controller-manager (pvc-protection) and the CSI driver for PVC %s/%s", pvc.Namespace, pvc.Name)}
+}
+
+// stuckCustomResourceIssue explains a custom resource stuck in Terminating.
+func stuckCustomResourceIssue(r stuckResource, age string) shutdownIssue {
+	if len(r.Finalizers) == 0 {
+		return shutdownIssue{"KD-SYS-002", "WARNING",
+			fmt.Sprintf("%s %s (%s) has been Terminating for %s with no finalizers left; its dependents are still being garbage-collected", r.Kind, r.ref(), r.Group, age),
+			fmt.Sprintf("Check kube-controller-manager's garbage collector for %s %s", r.Kind, r.ref())}
+	}
+	owner := finalizerOwner(r.Finalizers[0], r.Group)
+	return shutdownIssue{"KD-SYS-002", "WARNING",
+		fmt.Sprintf("%s %s (%s) has been Terminating for %s, waiting on finalizer(s) %s owned by %s", r.Kind, r.ref(), r.Group, age, strings.Join(r.Finalizers, ", "), owner),
+		fmt.Sprintf("Check that %s is running and can reach what it cleans up; remove finalizer %s from %s %s by hand only if that controller is uninstalled", owner, r.Finalizers[0], r.Kind, r.ref())}
+}
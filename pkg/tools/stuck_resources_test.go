@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFinalizerOwner(t *testing.T) {
+	tests := []struct {
+		finalizer, group, want string
+	}{
+		{"kubernetes", "", "namespace controller"},
+		{"kubernetes.io/pvc-protection", "", "pvc-protection controller"},
+		{"external-attacher/disk-csi-azure-com", "", "external-attacher"},
+		{"resources-finalizer.argocd.argoproj.io", "argoproj.io", "Argo CD"},
+		{"cert-manager.io/finalizer", "cert-manager.io", "the operator that manages cert-manager.io resources"},
+		{"postgres.example.com/cleanup", "db.example.com", "the controller for postgres.example.com"},
+		{"custom", "", "unknown controller"},
+	}
+	for _, tt := range tests {
+		if got := finalizerOwner(tt.finalizer, tt.group); !strings.Contains(got, tt.want) {
+			t.Errorf("finalizerOwner(%q, %q) = %q, want it to contain %q", tt.finalizer, tt.group, got, tt.want)
+		}
+	}
+}
+
+func TestFinalizerOwnerRows(t *testing.T) {
+	stuck := []stuckResource{
+		{Kind: "Certificate", Group: "cert-manager.io", Name: "a", Finalizers: []string{"cert-manager.io/finalizer"}},
+		{Kind: "Certificate", Group: "cert-manager.io", Name: "b", Finalizers: []string{"cert-manager.io/finalizer", "foregroundDeletion"}},
+	}
+	rows := finalizerOwnerRows(stuck)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2: %v", len(rows), rows)
+	}
+	if rows[0][0] != "cert-manager.io/finalizer" || rows[0][2] != "2" {
+		t.Errorf("first row = %v, want cert-manager.io/finalizer x2", rows[0])
+	}
+	if rows[1][0] != "foregroundDeletion" || rows[1][2] != "1" {
+		t.Errorf("second row = %v, want foregroundDeletion x1", rows[1])
+	}
+}
+
+func TestStuckNamespaceIssue(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "old"},
+		Status: corev1.NamespaceStatus{Conditions: []corev1.NamespaceCondition{
+			{Type: corev1.NamespaceDeletionDiscoveryFailure, Status: corev1.ConditionTrue, Message: "Discovery failed for some groups: metrics.k8s.io/v1beta1"},
+			{Type: corev1.NamespaceContentRemaining, Status: corev1.ConditionFalse, Message: "All content successfully removed"},
+		}},
+	}
+	issue := stuckNamespaceIssue(ns, "2h")
+	if issue.rule != "KD-SYS-002" {
+		t.Errorf("rule = %s, want KD-SYS-002", issue.rule)
+	}
+	if !strings.Contains(issue.message, "metrics.k8s.io") || strings.Contains(issue.message, "successfully removed") {
+		t.Errorf("message %q should list only the true deletion conditions", issue.message)
+	}
+	if !strings.Contains(issue.action, "APIService") {
+		t.Errorf("action %q should point at the unavailable APIService", issue.action)
+	}
+}
+
+func TestStuckPVCIssue(t *testing.T) {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "prod"}, Spec: corev1.PodSpec{Volumes: []corev1.Volume{{Name: "data",
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-db-0"}}}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "prod"}, Status: corev1.PodStatus{Phase: corev1.PodSucceeded}, Spec: corev1.PodSpec{Volumes: []corev1.Volume{{Name: "data",
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-db-0"}}}}}},
+	}
+	users := claimUsers(pods)
+	if got := users["prod/data-db-0"]; len(got) != 1 || got[0] != "db-0" {
+		t.Fatalf("claimUsers = %v, want only the running pod db-0", users)
+	}
+
+	now := metav1.NewTime(time.Now().Add(-time.Hour))
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "prod", DeletionTimestamp: &now, Finalizers: []string{"kubernetes.io/pvc-protection"}}}
+	issue := stuckPVCIssue(pvc, users["prod/data-db-0"], "1h")
+	if issue.rule != "KD-STO-002" || !strings.Contains(issue.message, "still mounted by pod(s) db-0") {
+		t.Errorf("issue = %+v, want KD-STO-002 naming db-0", issue)
+	}
+	issue = stuckPVCIssue(pvc, nil, "1h")
+	if !strings.Contains(issue.message, "no pod mounts it") {
+		t.Errorf("message %q should say no pod mounts the claim", issue.message)
+	}
+}
+
+func TestStuckCustomResourceIssue(t *testing.T) {
+	r := stuckResource{Kind: "Application", Group: "argoproj.io", Namespace: "argocd", Name: "shop", Finalizers: []string{"resources-finalizer.argocd.argoproj.io"}}
+	issue := stuckCustomResourceIssue(r, "3d")
+	if issue.rule != "KD-SYS-002" || !strings.Contains(issue.message, "Application argocd/shop") || !strings.Contains(issue.message, "Argo CD") {
+		t.Errorf("issue = %+v, want KD-SYS-002 naming the object and Argo CD", issue)
+	}
+}
@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// suppressionMiddleware drops suppressed findings from tool results and notes
+// how many were hidden, so accepted risks stay visible without repeating in
+// every report.
+func suppressionMiddleware(s *findings.Suppressions) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			res, err := next(ctx, method, req)
+			call, ok := req.(*mcp.CallToolRequest)
+			if err != nil || !ok || method != "tools/call" {
+				return res, err
+			}
+			result, ok := res.(*mcp.CallToolResult)
+			if !ok || result.IsError {
+				return res, err
+			}
+			ns := callNamespace(call.Params.Arguments)
+			for _, c := range result.Content {
+				if text, ok := c.(*mcp.TextContent); ok {
+					text.Text = applySuppressions(text.Text, s, ns)
+				}
+			}
+			return result, nil
+		}
+	}
+}
+
+// callNamespace returns the namespace argument of a tool call, "" for all.
+func callNamespace(args json.RawMessage) string {
+	var fields struct {
+		Namespace string `json:"namespace"`
+	}
+	if len(args) == 0 || json.Unmarshal(args, &fields) != nil {
+		return ""
+	}
+	return util.NamespaceOrAll(strings.TrimSpace(fields.Namespace))
+}
+
+// statusLine matches the severity counts of an executive summary.
+var statusLine = regexp.MustCompile(`^STATUS: \w+ \((\d+) critical, (\d+) warning, (\d+) info\)$`)
+
+// applySuppressions removes suppressed finding lines from a report, corrects
+// an executive summary's STATUS counts, and appends a SUPPRESSED section.
+func applySuppressions(report string, s *findings.Suppressions, namespace string) string {
+	lines := strings.Split(report, "\n")
+	out := make([]string, 0, len(lines))
+	removed := map[string]int{}
+	matched := map[*findings.Suppression]int{}
+	var order []*findings.Suppression
+	for _, line := range lines {
+		if f, ok := findings.Parse(line); ok {
+			if sup := s.Suppressed(f, namespace); sup != nil {
+				removed[f.Severity]++
+				if matched[sup] == 0 {
+					order = append(order, sup)
+				}
+				matched[sup]++
+				continue
+			}
+		}
+		out = append(out, line)
+	}
+	if len(order) == 0 {
+		return report
+	}
+
+	for i, line := range out {
+		m := statusLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		critical, _ := strconv.Atoi(m[1])
+		warning, _ := strconv.Atoi(m[2])
+		info, _ := strconv.Atoi(m[3])
+		critical -= removed["CRITICAL"]
+		warning -= removed["WARNING"]
+		info -= removed["INFO"]
+		status := "OK"
+		if critical > 0 {
+			status = "CRITICAL"
+		} else if warning > 0 {
+			status = "WARNING"
+		}
+		out[i] = fmt.Sprintf("STATUS: %s (%d critical, %d warning, %d info)", status, critical, warning, info)
+	}
+
+	total := removed["CRITICAL"] + removed["WARNING"] + removed["INFO"] + removed["OK"]
+	result := strings.TrimRight(strings.Join(out, "\n"), "\n")
+	result += fmt.Sprintf("\n\nSUPPRESSED (%d finding(s) hidden by suppression rules):\n", total)
+	for _, sup := range order {
+		result += fmt.Sprintf("  %s x%d\n", describeSuppression(sup), matched[sup])
+	}
+	return result
+}
+
+// describeSuppression names a suppression rule's scope and reason.
+func describeSuppression(sup *findings.Suppression) string {
+	desc := sup.Rule
+	if len(sup.Namespaces) > 0 {
+		desc += " in " + strings.Join(sup.Namespaces, ", ")
+	}
+	if sup.Match != "" {
+		desc += fmt.Sprintf(" matching %q", sup.Match)
+	}
+	if sup.Reason != "" {
+		desc += " (" + sup.Reason + ")"
+	}
+	return desc
+}
@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+)
+
+func TestApplySuppressions(t *testing.T) {
+	s := &findings.Suppressions{Rules: []findings.Suppression{{Rule: "KD-NET-001", Reason: "flat network by design"}}}
+	report := strings.Join([]string{
+		"=== Namespace Diagnosis: shop ===",
+		"STATUS: WARNING (0 critical, 1 warning, 1 info)",
+		"[WARNING] KD-NET-001: No network policies — all pod traffic is unrestricted",
+		"[INFO] KD-RES-005: No resource quotas — resource consumption is unrestricted",
+		"",
+	}, "\n")
+
+	got := applySuppressions(report, s, "shop")
+	if strings.Contains(got, "KD-NET-001: No network policies") {
+		t.Errorf("suppressed finding still present:\n%s", got)
+	}
+	if !strings.Contains(got, "STATUS: OK (0 critical, 0 warning, 1 info)") {
+		t.Errorf("STATUS line not corrected:\n%s", got)
+	}
+	if !strings.Contains(got, "SUPPRESSED (1 finding(s) hidden by suppression rules):\n  KD-NET-001 (flat network by design) x1") {
+		t.Errorf("missing SUPPRESSED section:\n%s", got)
+	}
+
+	if unchanged := applySuppressions("[INFO] KD-RES-005: No resource quotas\n", s, "shop"); unchanged != "[INFO] KD-RES-005: No resource quotas\n" {
+		t.Errorf("report without suppressed findings changed: %q", unchanged)
+	}
+}
@@ -23,7 +23,7 @@ func TestToolsLive(t *testing.T) {
 		Version: "test",
 	}, nil)
 
-	RegisterAll(server, client, nil)
+	RegisterAll(server, client, nil, Options{})
 
 	ctx := context.Background()
 
@@ -0,0 +1,437 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/mermaid"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// topologyZoneLabel is the well-known node label for failure zones.
+	topologyZoneLabel = "topology.kubernetes.io/zone"
+	// topologyNoZone groups nodes without a zone label.
+	topologyNoZone = "(no zone)"
+)
+
+type analyzeTopologySpreadInput struct {
+	Namespace       string `json:"namespace,omitempty" jsonschema:"Namespace to analyze (empty for all namespaces)"`
+	Workload        string `json:"workload,omitempty" jsonschema:"Only analyze Deployments and StatefulSets with this name"`
+	MaxDiagramNodes int    `json:"max_diagram_nodes,omitempty" jsonschema:"Maximum nodes in the spread diagram before the rest are collapsed into '+N more' nodes (default 80)"`
+	TimeoutSeconds  int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// workloadSpread is where a workload's running replicas are placed.
+type workloadSpread struct {
+	Target  lintTarget
+	Running int
+	Zones   map[string]int
+	Nodes   map[string]int
+}
+
+// Skew returns the difference between the most and least loaded of the
+// eligible zones, counting eligible zones without replicas as zero.
+func (s workloadSpread) Skew(eligibleZones map[string]bool) int {
+	if len(eligibleZones) < 2 {
+		return 0
+	}
+	lo, hi := -1, 0
+	for zone := range eligibleZones {
+		n := s.Zones[zone]
+		if lo < 0 || n < lo {
+			lo = n
+		}
+		hi = max(hi, n)
+	}
+	return hi - lo
+}
+
+func registerTopologySpreadTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// analyze_topology_spread
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "analyze_topology_spread",
+		Description: "Map the running replicas of Deployments and StatefulSets to nodes and zones, and flag multi-replica workloads concentrated on one node or one zone, " +
+			"workloads without topologySpreadConstraints or pod anti-affinity, zone skew above a constraint's maxSkew, and constraints on topology keys no node carries. " +
+			"Includes a Mermaid diagram of replica placement grouped by zone and node.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input analyzeTopologySpreadInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+
+		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing nodes", err), nil, nil
+		}
+		var targets []lintTarget
+		deployments, err := client.ListDeployments(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing deployments", err), nil, nil
+		}
+		for _, d := range deployments {
+			targets = append(targets, lintTarget{Kind: "Deployment", Namespace: d.Namespace, Name: d.Name,
+				Replicas: replicasOrDefault(d.Spec.Replicas), Selector: d.Spec.Selector, Template: d.Spec.Template})
+		}
+		statefulsets, err := client.ListStatefulSets(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing statefulsets", err), nil, nil
+		}
+		for _, s := range statefulsets {
+			targets = append(targets, lintTarget{Kind: "StatefulSet", Namespace: s.Namespace, Name: s.Name,
+				Replicas: replicasOrDefault(s.Spec.Replicas), Selector: s.Spec.Selector, Template: s.Spec.Template})
+		}
+		pods, err := client.ListPods(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+
+		nodeZones := make(map[string]string, len(nodes))
+		zoneNodes := make(map[string]int)
+		labelKeys := make(map[string]bool)
+		for _, n := range nodes {
+			zone := nodeZone(&n)
+			nodeZones[n.Name] = zone
+			zoneNodes[zone]++
+			for k := range n.Labels {
+				labelKeys[k] = true
+			}
+		}
+		clusterZones := len(zoneNodes)
+		if zoneNodes[topologyNoZone] > 0 {
+			clusterZones--
+		}
+
+		var spreads []workloadSpread
+		for _, t := range targets {
+			if input.Workload != "" && t.Name != input.Workload {
+				continue
+			}
+			spreads = append(spreads, computeSpread(t, pods, nodeZones))
+		}
+		if input.Workload != "" && len(spreads) == 0 {
+			return util.ErrorResult("no Deployment or StatefulSet named %q in namespace %s", input.Workload, displayNS(ns)), nil, nil
+		}
+		sort.Slice(spreads, func(i, j int) bool {
+			a, b := spreads[i].Target, spreads[j].Target
+			if a.Namespace != b.Namespace {
+				return a.Namespace < b.Namespace
+			}
+			return a.Name < b.Name
+		})
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Topology Spread (namespace: %s)", displayNS(ns))))
+		sb.WriteString("\n")
+		var zoneSummary []string
+		for _, zone := range sortedKeysInt(zoneNodes) {
+			zoneSummary = append(zoneSummary, fmt.Sprintf("%s (%d nodes)", zone, zoneNodes[zone]))
+		}
+		sb.WriteString(util.FormatKeyValue("Zones", fmt.Sprintf("%d: %s", clusterZones, strings.Join(zoneSummary, ", "))) + "\n")
+		if clusterZones < 2 {
+			sb.WriteString("  (single-zone cluster: zone spread is not possible; only node spread is checked)\n")
+		}
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		var rows [][]string
+		multi := 0
+		for _, s := range spreads {
+			eligible := eligibleZones(s.Target.Template.Spec, nodes)
+			skew := "-"
+			if len(eligible) > 1 {
+				skew = fmt.Sprintf("%d", s.Skew(eligible))
+			}
+			rows = append(rows, []string{
+				s.Target.Namespace, s.Target.Kind + "/" + s.Target.Name,
+				fmt.Sprintf("%d/%d", s.Running, s.Target.Replicas),
+				formatSpreadCounts(s.Zones), fmt.Sprintf("%d", len(s.Nodes)), skew,
+				spreadPolicySummary(s.Target.Template.Spec),
+			})
+			if s.Target.Replicas < 2 {
+				continue
+			}
+			multi++
+			name := fmt.Sprintf("%s/%s", s.Target.Namespace, s.Target.Name)
+			for _, f := range spreadFindings(s, eligible, labelKeys) {
+				findingLines = append(findingLines, findings.Format(f.rule, f.severity, f.message))
+			}
+			if !hasSpreadConstraints(s.Target.Template.Spec) {
+				actions = append(actions, fmt.Sprintf("Add a topologySpreadConstraint on %s (maxSkew 1, whenUnsatisfiable ScheduleAnyway) and on kubernetes.io/hostname to %s %s.", topologyZoneLabel, s.Target.Kind, name))
+			} else if s.Running > 1 && (len(s.Nodes) == 1 || hasZoneConstraint(s.Target.Template.Spec) && s.Skew(eligible) > zoneMaxSkew(s.Target.Template.Spec)) {
+				actions = append(actions, fmt.Sprintf("Rebalance %s with 'kubectl rollout restart' once every zone has capacity; the scheduler never moves running pods (the descheduler's RemovePodsViolatingTopologySpreadConstraint can automate this).", name))
+			}
+			if s.Running > 1 && len(s.Nodes) == 1 && len(steps) < 3 {
+				steps = append(steps, nextStep("list_pdbs", "check whether a drain of the shared node would take every replica down", "namespace", s.Target.Namespace))
+			}
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("WORKLOADS"))
+		sb.WriteString("\n")
+		if len(rows) == 0 {
+			sb.WriteString("  No Deployments or StatefulSets found.\n")
+		} else {
+			sb.WriteString(util.FormatTable([]string{"NAMESPACE", "WORKLOAD", "RUNNING", "ZONES", "NODES", "ZONE SKEW", "SPREAD POLICY"}, rows))
+		}
+
+		if multi > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("PLACEMENT"))
+			sb.WriteString("\n")
+			sb.WriteString(topologySpreadDiagram(spreads, nodeZones, input.MaxDiagramNodes))
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString(fmt.Sprintf("  All %d multi-replica workloads are spread across nodes and zones.\n", multi))
+		}
+		for _, f := range dedupe(findingLines) {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+func nodeZone(n *corev1.Node) string {
+	if zone := n.Labels[topologyZoneLabel]; zone != "" {
+		return zone
+	}
+	if zone := n.Labels["failure-domain.beta.kubernetes.io/zone"]; zone != "" {
+		return zone
+	}
+	return topologyNoZone
+}
+
+// computeSpread counts a workload's scheduled, non-terminated pods per zone and node.
+func computeSpread(t lintTarget, pods []corev1.Pod, nodeZones map[string]string) workloadSpread {
+	s := workloadSpread{Target: t, Zones: make(map[string]int), Nodes: make(map[string]int)}
+	if t.Selector == nil || (len(t.Selector.MatchLabels) == 0 && len(t.Selector.MatchExpressions) == 0) {
+		return s
+	}
+	for i := range pods {
+		p := &pods[i]
+		if p.Namespace != t.Namespace || p.Spec.NodeName == "" || p.DeletionTimestamp != nil ||
+			p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed ||
+			!labelSelectorMatches(t.Selector, p.Labels) {
+			continue
+		}
+		zone, ok := nodeZones[p.Spec.NodeName]
+		if !ok {
+			zone = topologyNoZone
+		}
+		s.Running++
+		s.Zones[zone]++
+		s.Nodes[p.Spec.NodeName]++
+	}
+	return s
+}
+
+// eligibleZones returns the labeled zones with at least one node that matches
+// the pod's nodeSelector. Node affinity is not evaluated.
+func eligibleZones(spec corev1.PodSpec, nodes []corev1.Node) map[string]bool {
+	zones := make(map[string]bool)
+	for i := range nodes {
+		n := &nodes[i]
+		zone := nodeZone(n)
+		if zone == topologyNoZone || n.Spec.Unschedulable {
+			continue
+		}
+		matches := true
+		for k, v := range spec.NodeSelector {
+			if n.Labels[k] != v {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			zones[zone] = true
+		}
+	}
+	return zones
+}
+
+// zoneMaxSkew returns the maxSkew of the workload's zone spread constraint,
+// or 1 when it has none.
+func zoneMaxSkew(spec corev1.PodSpec) int {
+	for _, c := range spec.TopologySpreadConstraints {
+		if c.TopologyKey == topologyZoneLabel {
+			return int(c.MaxSkew)
+		}
+	}
+	return 1
+}
+
+// spreadFindings flags replica concentration and ineffective spread
+// constraints for a workload with two or more desired replicas.
+func spreadFindings(s workloadSpread, eligible map[string]bool, nodeLabelKeys map[string]bool) []containerFinding {
+	var found []containerFinding
+	name := fmt.Sprintf("%s %s/%s", s.Target.Kind, s.Target.Namespace, s.Target.Name)
+	spec := s.Target.Template.Spec
+
+	switch {
+	case s.Running > 1 && len(s.Nodes) == 1:
+		var node string
+		for n := range s.Nodes {
+			node = n
+		}
+		found = append(found, containerFinding{"KD-WL-008", "CRITICAL", fmt.Sprintf("%s runs all %d replicas on node %s; losing that node takes the workload down.", name, s.Running, node)})
+	case s.Running > 1 && len(s.Zones) == 1 && len(eligible) > 1:
+		var zone string
+		for z := range s.Zones {
+			zone = z
+		}
+		found = append(found, containerFinding{"KD-WL-008", "WARNING", fmt.Sprintf("%s runs all %d replicas in zone %s although %d zones are available; a zone outage takes the workload down.", name, s.Running, zone, len(eligible))})
+	case len(eligible) > 1 && s.Running > 1:
+		if skew, maxSkew := s.Skew(eligible), zoneMaxSkew(spec); skew > maxSkew && hasZoneConstraint(spec) {
+			found = append(found, containerFinding{"KD-WL-008", "WARNING", fmt.Sprintf("%s has a zone skew of %d, above its maxSkew of %d; the scheduler does not rebalance running pods.", name, skew, maxSkew)})
+		}
+	}
+
+	if !hasSpreadConstraints(spec) {
+		found = append(found, containerFinding{"KD-WL-008", "WARNING", fmt.Sprintf("%s has %d replicas but no topologySpreadConstraints or pod anti-affinity; the scheduler may pack them together.", name, s.Target.Replicas)})
+	}
+	for _, c := range spec.TopologySpreadConstraints {
+		if nodeLabelKeys[c.TopologyKey] {
+			continue
+		}
+		effect := "it has no effect"
+		if c.WhenUnsatisfiable == corev1.DoNotSchedule {
+			effect = "new pods cannot schedule"
+		}
+		found = append(found, containerFinding{"KD-WL-008", "WARNING", fmt.Sprintf("%s spreads on topology key %s, which no node carries; %s.", name, c.TopologyKey, effect)})
+	}
+	return found
+}
+
+func hasZoneConstraint(spec corev1.PodSpec) bool {
+	for _, c := range spec.TopologySpreadConstraints {
+		if c.TopologyKey == topologyZoneLabel {
+			return true
+		}
+	}
+	return false
+}
+
+// spreadPolicySummary describes the spread rules of a pod template.
+func spreadPolicySummary(spec corev1.PodSpec) string {
+	var parts []string
+	for _, c := range spec.TopologySpreadConstraints {
+		key := c.TopologyKey
+		switch key {
+		case topologyZoneLabel:
+			key = "zone"
+		case "kubernetes.io/hostname":
+			key = "node"
+		}
+		parts = append(parts, fmt.Sprintf("spread %s skew<=%d (%s)", key, c.MaxSkew, c.WhenUnsatisfiable))
+	}
+	if a := spec.Affinity; a != nil && a.PodAntiAffinity != nil {
+		if len(a.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) > 0 {
+			parts = append(parts, "anti-affinity (required)")
+		}
+		if len(a.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) > 0 {
+			parts = append(parts, "anti-affinity (preferred)")
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatSpreadCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(counts))
+	for _, k := range sortedKeysInt(counts) {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, counts[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// topologySpreadDiagram draws multi-replica workloads' replicas grouped by
+// zone and node; workloads concentrated on one node or zone are highlighted.
+func topologySpreadDiagram(spreads []workloadSpread, nodeZones map[string]string, maxNodes int) string {
+	type replicaGroup struct {
+		id, label string
+		severity  mermaid.Severity
+	}
+	byZoneNode := make(map[string]map[string][]replicaGroup)
+	for _, s := range spreads {
+		if s.Target.Replicas < 2 {
+			continue
+		}
+		severity := mermaid.SeverityHealthy
+		switch {
+		case s.Running > 1 && len(s.Nodes) == 1:
+			severity = mermaid.SeverityCritical
+		case s.Running > 1 && len(s.Zones) == 1:
+			severity = mermaid.SeverityWarning
+		}
+		for node, count := range s.Nodes {
+			zone := nodeZones[node]
+			if zone == "" {
+				zone = topologyNoZone
+			}
+			if byZoneNode[zone] == nil {
+				byZoneNode[zone] = make(map[string][]replicaGroup)
+			}
+			byZoneNode[zone][node] = append(byZoneNode[zone][node], replicaGroup{
+				id:       mermaid.SafeID(fmt.Sprintf("r_%s_%s_%s", node, s.Target.Namespace, s.Target.Name)),
+				label:    fmt.Sprintf("%s/%s x%d", s.Target.Namespace, s.Target.Name, count),
+				severity: severity,
+			})
+		}
+	}
+
+	fc := newBudgetedFlowchart(mermaid.DirectionTB, maxNodes)
+	var styled []replicaGroup
+	zones := make([]string, 0, len(byZoneNode))
+	for z := range byZoneNode {
+		zones = append(zones, z)
+	}
+	sort.Strings(zones)
+	for _, zone := range zones {
+		fc.AddSubgraph(mermaid.SafeID("zone_"+zone), "Zone "+zone, func(sg *mermaid.Subgraph) {
+			sg.SetOverflowNoun("nodes")
+			nodeNames := make([]string, 0, len(byZoneNode[zone]))
+			for n := range byZoneNode[zone] {
+				nodeNames = append(nodeNames, n)
+			}
+			sort.Strings(nodeNames)
+			for _, node := range nodeNames {
+				groups := byZoneNode[zone][node]
+				sort.Slice(groups, func(i, j int) bool { return groups[i].label < groups[j].label })
+				sg.AddNestedSubgraph(mermaid.SafeID("node_"+node), node, func(nested *mermaid.Subgraph) {
+					nested.SetOverflowNoun("replica groups")
+					for _, g := range groups {
+						nested.AddNode(g.id, g.label, mermaid.ShapeRound)
+						styled = append(styled, g)
+					}
+				})
+			}
+		})
+	}
+	for _, g := range styled {
+		if g.severity != mermaid.SeverityHealthy {
+			fc.AddStyle(g.id, g.severity)
+		}
+	}
+	return fc.RenderBlock()
+}
@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func spreadTestNodes() []corev1.Node {
+	node := func(name, zone string) corev1.Node {
+		return testNode(name, "4", "16Gi", map[string]string{topologyZoneLabel: zone})
+	}
+	return []corev1.Node{node("a1", "zone-a"), node("a2", "zone-a"), node("b1", "zone-b"), node("c1", "zone-c")}
+}
+
+func spreadTestPods(nodes ...string) []corev1.Pod {
+	var pods []corev1.Pod
+	for i, n := range nodes {
+		pods = append(pods, testPod("shop", "web-"+string(rune('a'+i)), n, map[string]string{"app": "web"}))
+	}
+	return pods
+}
+
+func spreadTestTarget(replicas int32, spec corev1.PodSpec) lintTarget {
+	return lintTarget{Kind: "Deployment", Namespace: "shop", Name: "web", Replicas: replicas,
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		Template: corev1.PodTemplateSpec{Spec: spec}}
+}
+
+func spreadTestZones(nodes []corev1.Node) map[string]string {
+	zones := make(map[string]string)
+	for i := range nodes {
+		zones[nodes[i].Name] = nodeZone(&nodes[i])
+	}
+	return zones
+}
+
+func TestComputeSpread(t *testing.T) {
+	nodes := spreadTestNodes()
+	pods := spreadTestPods("a1", "a2", "b1")
+	pods = append(pods, testPod("shop", "other", "c1", map[string]string{"app": "api"}))
+
+	s := computeSpread(spreadTestTarget(3, corev1.PodSpec{}), pods, spreadTestZones(nodes))
+	if s.Running != 3 || s.Zones["zone-a"] != 2 || s.Zones["zone-b"] != 1 || len(s.Nodes) != 3 {
+		t.Errorf("computeSpread() = running %d zones %v nodes %v", s.Running, s.Zones, s.Nodes)
+	}
+	if skew := s.Skew(eligibleZones(corev1.PodSpec{}, nodes)); skew != 2 {
+		t.Errorf("Skew() = %d, want 2 (zone-a has 2, zone-c has 0)", skew)
+	}
+}
+
+func TestEligibleZones(t *testing.T) {
+	nodes := spreadTestNodes()
+	nodes[2].Labels["pool"] = "gpu"
+	nodes[3].Spec.Unschedulable = true
+
+	if got := eligibleZones(corev1.PodSpec{}, nodes); len(got) != 2 || !got["zone-a"] || !got["zone-b"] {
+		t.Errorf("eligibleZones() = %v, want zone-a and zone-b (c1 is cordoned)", got)
+	}
+	if got := eligibleZones(corev1.PodSpec{NodeSelector: map[string]string{"pool": "gpu"}}, nodes); len(got) != 1 || !got["zone-b"] {
+		t.Errorf("eligibleZones(pool=gpu) = %v, want only zone-b", got)
+	}
+}
+
+func TestSpreadFindings(t *testing.T) {
+	nodes := spreadTestNodes()
+	labelKeys := map[string]bool{topologyZoneLabel: true, "kubernetes.io/hostname": true}
+	eligible := eligibleZones(corev1.PodSpec{}, nodes)
+
+	tests := []struct {
+		name  string
+		spec  corev1.PodSpec
+		nodes []string
+		want  []string
+	}{
+		{
+			name:  "all on one node without constraints",
+			nodes: []string{"a1", "a1", "a1"},
+			want:  []string{"[CRITICAL] runs all 3 replicas on node a1", "[WARNING] has 3 replicas but no topologySpreadConstraints"},
+		},
+		{
+			name:  "one zone",
+			spec:  corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway}}},
+			nodes: []string{"a1", "a2"},
+			want:  []string{"[WARNING] runs all 2 replicas in zone zone-a"},
+		},
+		{
+			name:  "zone skew above maxSkew",
+			spec:  corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: topologyZoneLabel, WhenUnsatisfiable: corev1.DoNotSchedule}}},
+			nodes: []string{"a1", "a2", "a1", "b1"},
+			want:  []string{"[WARNING] has a zone skew of 3, above its maxSkew of 1"},
+		},
+		{
+			name:  "unknown topology key",
+			spec:  corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "example.com/rack", WhenUnsatisfiable: corev1.DoNotSchedule}}},
+			nodes: []string{"a1", "b1", "c1"},
+			want:  []string{"[WARNING] spreads on topology key example.com/rack, which no node carries; new pods cannot schedule"},
+		},
+		{
+			name:  "spread",
+			spec:  corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: topologyZoneLabel, WhenUnsatisfiable: corev1.ScheduleAnyway}}},
+			nodes: []string{"a1", "b1", "c1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := computeSpread(spreadTestTarget(int32(len(tt.nodes)), tt.spec), spreadTestPods(tt.nodes...), spreadTestZones(nodes))
+			var got []string
+			for _, f := range spreadFindings(s, eligible, labelKeys) {
+				got = append(got, "["+f.severity+"] "+f.message)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("spreadFindings() = %q, want %d findings", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				sev, msg, _ := strings.Cut(want, " ")
+				if !strings.HasPrefix(got[i], sev) || !strings.Contains(got[i], msg) {
+					t.Errorf("finding %d = %q, want %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestTopologySpreadDiagram(t *testing.T) {
+	nodes := spreadTestNodes()
+	zones := spreadTestZones(nodes)
+	spreads := []workloadSpread{
+		computeSpread(spreadTestTarget(2, corev1.PodSpec{}), spreadTestPods("a1", "a1"), zones),
+		computeSpread(lintTarget{Kind: "Deployment", Namespace: "shop", Name: "single", Replicas: 1}, nil, zones),
+	}
+
+	out := topologySpreadDiagram(spreads, zones, 0)
+	for _, want := range []string{"Zone zone-a", `"a1"`, "shop/web x2", "style r_a1_shop_web fill:#ffcccc"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diagram missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "single") {
+		t.Errorf("diagram includes a single-replica workload:\n%s", out)
+	}
+}
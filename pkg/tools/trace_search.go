@@ -0,0 +1,370 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/mermaid"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// traceSearchDefaultTail and traceSearchMaxTail bound the lines read per container.
+	traceSearchDefaultTail int64 = 500
+	traceSearchMaxTail     int64 = 5000
+	// traceSearchDefaultSince is how far back search_logs_by_trace looks by default.
+	traceSearchDefaultSince = time.Hour
+	// traceSearchMinIDLength rejects IDs short enough to match unrelated lines.
+	traceSearchMinIDLength = 6
+	// traceSearchConcurrency caps concurrent log requests.
+	traceSearchConcurrency = 8
+	// traceSearchMaxTimeline caps the timeline lines printed.
+	traceSearchMaxTimeline = 100
+)
+
+type searchLogsByTraceInput struct {
+	Namespace      string `json:"namespace" jsonschema:"required,Kubernetes namespace whose Deployments to search"`
+	TraceID        string `json:"trace_id" jsonschema:"required,Trace or correlation ID to search for (at least 6 characters)"`
+	Deployments    string `json:"deployments,omitempty" jsonschema:"Comma-separated Deployment names to search (default: all Deployments in the namespace)"`
+	TailLines      int64  `json:"tail_lines,omitempty" jsonschema:"Lines to scan per container (default 500, max 5000)"`
+	Since          string `json:"since,omitempty" jsonschema:"Only scan logs newer than this duration (default 1h)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// traceHit is one log line containing the trace ID.
+type traceHit struct {
+	Time       time.Time
+	Deployment string
+	Pod        string
+	Container  string
+	Text       string
+	Error      bool
+}
+
+// traceHop is a run of consecutive hits in one Deployment.
+type traceHop struct {
+	Deployment string
+	First      time.Time
+	Last       time.Time
+	Lines      int
+	Errors     int
+}
+
+// traceTarget is a container whose logs search_logs_by_trace scans.
+type traceTarget struct {
+	Deployment string
+	Pod        string
+	Container  string
+}
+
+func registerTraceSearchTools(server *mcp.Server, client *k8s.ClusterClient) {
+	// search_logs_by_trace
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "search_logs_by_trace",
+		Description: "Search the recent logs of every Deployment in a namespace for a trace or correlation ID and reconstruct the request's path: " +
+			"a timestamped timeline of matching lines, the hop-by-hop order of services with latency between them, the hops that logged errors, " +
+			"and a Mermaid sequence diagram. Scans a bounded tail per container.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input searchLogsByTraceInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		traceID := strings.TrimSpace(input.TraceID)
+		if input.Namespace == "" || traceID == "" {
+			return util.ErrorResult("namespace and trace_id are required"), nil, nil
+		}
+		if len(traceID) < traceSearchMinIDLength {
+			return util.ErrorResult("trace_id %q is too short; use at least %d characters to avoid matching unrelated lines", traceID, traceSearchMinIDLength), nil, nil
+		}
+		tail := input.TailLines
+		switch {
+		case tail <= 0:
+			tail = traceSearchDefaultTail
+		case tail > traceSearchMaxTail:
+			tail = traceSearchMaxTail
+		}
+		since := traceSearchDefaultSince
+		if input.Since != "" {
+			d, err := time.ParseDuration(input.Since)
+			if err != nil || d <= 0 {
+				return util.ErrorResult("invalid since %q: use a duration like 30m or 6h", input.Since), nil, nil
+			}
+			since = d
+		}
+
+		deployments, err := client.ListDeployments(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing deployments", err), nil, nil
+		}
+		deployments = filterDeploymentsByName(deployments, util.SplitList(input.Deployments))
+		pods, err := client.ListPods(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+		targets := traceSearchTargets(deployments, pods)
+
+		hits, failed := searchTraceLogs(ctx, client, input.Namespace, targets, tail, since, traceID)
+		if err := ctx.Err(); err != nil {
+			return util.HandleK8sError("searching logs", err), nil, nil
+		}
+		hops := traceHops(hits)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Trace Search: %s (namespace: %s)", traceID, input.Namespace)))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Searched", fmt.Sprintf("%d deployments, %d containers, last %d lines within %s", len(deployments), len(targets), tail, since)) + "\n")
+		sb.WriteString(util.FormatKeyValue("Hits", fmt.Sprintf("%d lines in %d hops", len(hits), len(hops))) + "\n\n")
+
+		var findingLines, actions []string
+		var steps []util.NextStep
+		if len(failed) > 0 {
+			findingLines = append(findingLines, findings.Format("KD-SYS-008", "INFO", fmt.Sprintf("Could not read logs from %d containers: %s", len(failed), joinLimited(failed, 5))))
+		}
+
+		if len(hits) == 0 {
+			findingLines = append(findingLines, findings.Format("KD-EVT-003", "INFO", fmt.Sprintf("No log line in the last %s contains %s.", since, traceID)))
+			actions = append(actions,
+				"Widen the search with a longer since and a larger tail_lines; busy services rotate the lines out of the tail quickly.",
+				"Check that services log the trace ID at all; an ID that only appears at the edge usually means trace context headers are not propagated.")
+		} else {
+			start := hits[0].Time
+			sb.WriteString(util.FormatSubHeader("TIMELINE"))
+			sb.WriteString("\n")
+			for i, h := range hits {
+				if i == traceSearchMaxTimeline {
+					sb.WriteString(fmt.Sprintf("  ... and %d more lines\n", len(hits)-i))
+					break
+				}
+				marker := "  "
+				if h.Error {
+					marker = "!!"
+				}
+				sb.WriteString(fmt.Sprintf("%s %-9s %s [%s/%s] %s\n", marker, traceOffset(start, h.Time), h.Time.UTC().Format("15:04:05.000"),
+					h.Deployment, strings.TrimPrefix(h.Pod, h.Deployment+"-"), truncateName(h.Text, 200)))
+			}
+
+			sb.WriteString("\n")
+			sb.WriteString(util.FormatSubHeader("HOP PATH"))
+			sb.WriteString("\n")
+			rows := make([][]string, 0, len(hops))
+			for i, hop := range hops {
+				gap := "-"
+				if i > 0 {
+					gap = "+" + formatTraceDuration(hop.First.Sub(hops[i-1].Last))
+				}
+				rows = append(rows, []string{fmt.Sprintf("%d", i+1), hop.Deployment, traceOffset(start, hop.First), gap,
+					formatTraceDuration(hop.Last.Sub(hop.First)), fmt.Sprintf("%d", hop.Lines), fmt.Sprintf("%d", hop.Errors)})
+			}
+			sb.WriteString(util.FormatTable([]string{"#", "DEPLOYMENT", "START", "GAP", "SPAN", "LINES", "ERRORS"}, rows))
+			sb.WriteString("\n")
+			sb.WriteString(traceSequenceDiagram(hops).RenderBlock())
+			sb.WriteString("\n")
+
+			for _, hop := range hops {
+				if hop.Errors == 0 {
+					continue
+				}
+				findingLines = append(findingLines, findings.Format("KD-EVT-003", "WARNING", fmt.Sprintf("%s logged %d error lines for this trace.", hop.Deployment, hop.Errors)))
+				actions = append(actions, fmt.Sprintf("Read the error lines from %s in the timeline above; the first failing hop is usually the root cause.", hop.Deployment))
+				steps = append(steps, nextStep("analyze_service_logs", "see whether the same errors affect other requests", "namespace", input.Namespace, "deployment_name", hop.Deployment))
+			}
+			if slow := slowestTraceGap(hops); slow >= 0 {
+				findingLines = append(findingLines, findings.Format("KD-EVT-003", "INFO", fmt.Sprintf("Largest gap: %s between %s and %s.",
+					formatTraceDuration(hops[slow].First.Sub(hops[slow-1].Last)), hops[slow-1].Deployment, hops[slow].Deployment)))
+			}
+			if len(findingLines) == 0 {
+				findingLines = append(findingLines, util.FormatFinding("OK", fmt.Sprintf("The trace passed through %d hops without logging errors.", len(hops))))
+			}
+		}
+
+		sb.WriteString("FINDINGS:\n")
+		for _, f := range dedupe(findingLines) {
+			sb.WriteString("  " + f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// filterDeploymentsByName keeps the named Deployments, or all of them when
+// names is empty.
+func filterDeploymentsByName(deployments []appsv1.Deployment, names []string) []appsv1.Deployment {
+	if len(names) == 0 {
+		return deployments
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, n := range names {
+		wanted[n] = true
+	}
+	var out []appsv1.Deployment
+	for _, d := range deployments {
+		if wanted[d.Name] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// traceSearchTargets lists the containers of each Deployment's running pods.
+func traceSearchTargets(deployments []appsv1.Deployment, pods []corev1.Pod) []traceTarget {
+	var targets []traceTarget
+	for _, d := range deployments {
+		if d.Spec.Selector == nil || len(d.Spec.Selector.MatchLabels)+len(d.Spec.Selector.MatchExpressions) == 0 {
+			continue
+		}
+		for _, p := range pods {
+			if p.Status.Phase != corev1.PodRunning || !labelSelectorMatches(d.Spec.Selector, p.Labels) {
+				continue
+			}
+			for _, c := range p.Spec.Containers {
+				targets = append(targets, traceTarget{Deployment: d.Name, Pod: p.Name, Container: c.Name})
+			}
+		}
+	}
+	return targets
+}
+
+// searchTraceLogs scans the targets' logs concurrently and returns the hits in
+// time order, plus the containers whose logs could not be read.
+func searchTraceLogs(ctx context.Context, client *k8s.ClusterClient, namespace string, targets []traceTarget, tail int64, since time.Duration, traceID string) ([]traceHit, []string) {
+	var (
+		mu     sync.Mutex
+		hits   []traceHit
+		failed []string
+		wg     sync.WaitGroup
+	)
+	sem := make(chan struct{}, traceSearchConcurrency)
+	for _, t := range targets {
+		wg.Add(1)
+		go func(t traceTarget) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			lines, err := client.SearchPodLogs(ctx, namespace, t.Pod, t.Container, tail, since, traceID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed = append(failed, t.Pod+"/"+t.Container)
+				return
+			}
+			for _, line := range lines {
+				hits = append(hits, parseTraceHit(t, line))
+			}
+		}(t)
+	}
+	wg.Wait()
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Time.Before(hits[j].Time) })
+	sort.Strings(failed)
+	return hits, failed
+}
+
+// parseTraceHit splits the API server's timestamp from a log line and
+// condenses JSON lines to their level, message, and error.
+func parseTraceHit(t traceTarget, line string) traceHit {
+	h := traceHit{Deployment: t.Deployment, Pod: t.Pod, Container: t.Container, Text: line}
+	if stamp, text, ok := strings.Cut(line, " "); ok {
+		if ts, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+			h.Time, h.Text = ts, text
+		}
+	}
+	if l, ok := parseJSONLogLine(t.Pod, h.Text); ok {
+		h.Error = l.IsError()
+		h.Text = formatJSONLogLine(l)
+	} else {
+		h.Error = logErrorPattern.MatchString(h.Text)
+	}
+	return h
+}
+
+// traceHops groups time-ordered hits into runs per Deployment.
+func traceHops(hits []traceHit) []traceHop {
+	var hops []traceHop
+	for _, h := range hits {
+		if n := len(hops); n == 0 || hops[n-1].Deployment != h.Deployment {
+			hops = append(hops, traceHop{Deployment: h.Deployment, First: h.Time})
+		}
+		hop := &hops[len(hops)-1]
+		hop.Last = h.Time
+		hop.Lines++
+		if h.Error {
+			hop.Errors++
+		}
+	}
+	return hops
+}
+
+// slowestTraceGap returns the index of the hop entered after the longest gap,
+// or -1 when there are fewer than two hops.
+func slowestTraceGap(hops []traceHop) int {
+	slow := -1
+	var longest time.Duration
+	for i := 1; i < len(hops); i++ {
+		if gap := hops[i].First.Sub(hops[i-1].Last); slow < 0 || gap > longest {
+			slow, longest = i, gap
+		}
+	}
+	return slow
+}
+
+// traceSequenceDiagram renders the hops as calls between services, with
+// notes on hops that logged errors.
+func traceSequenceDiagram(hops []traceHop) *mermaid.Sequence {
+	seq := mermaid.NewSequence()
+	seq.AddActor("client", "Client")
+	seen := make(map[string]bool)
+	for _, hop := range hops {
+		if !seen[hop.Deployment] {
+			seen[hop.Deployment] = true
+			seq.AddParticipant(mermaid.SafeID(hop.Deployment), hop.Deployment)
+		}
+	}
+	from := "client"
+	for i, hop := range hops {
+		to := mermaid.SafeID(hop.Deployment)
+		label := "request"
+		if i > 0 {
+			label = "+" + formatTraceDuration(hop.First.Sub(hops[i-1].Last))
+		}
+		seq.AddMessage(from, to, label, mermaid.MsgSolid)
+		if hop.Errors > 0 {
+			seq.AddNote(to, fmt.Sprintf("%d error lines", hop.Errors), mermaid.NoteRight)
+		}
+		from = to
+	}
+	return seq
+}
+
+// traceOffset formats a hit's time relative to the first hit.
+func traceOffset(start, t time.Time) string {
+	if start.IsZero() || t.IsZero() {
+		return "?"
+	}
+	return "+" + formatTraceDuration(t.Sub(start))
+}
+
+func formatTraceDuration(d time.Duration) string {
+	switch {
+	case d < 0:
+		return "-" + formatTraceDuration(-d)
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	default:
+		return d.Round(time.Second).String()
+	}
+}
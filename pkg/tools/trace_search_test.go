@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTraceHops(t *testing.T) {
+	var hits []traceHit
+	for _, h := range []struct {
+		deploy, line string
+	}{
+		{"frontend", `2026-10-15T10:00:00.000Z GET /checkout trace=abc123`},
+		{"frontend", `2026-10-15T10:00:00.010Z calling checkout trace=abc123`},
+		{"checkout", `2026-10-15T10:00:00.050Z {"level":"info","msg":"order received","trace_id":"abc123"}`},
+		{"payments", `2026-10-15T10:00:01.550Z {"level":"error","msg":"charge failed","error":"card declined","trace_id":"abc123"}`},
+		{"checkout", `2026-10-15T10:00:01.600Z {"level":"warn","msg":"payment failed","trace_id":"abc123"}`},
+	} {
+		hits = append(hits, parseTraceHit(traceTarget{Deployment: h.deploy, Pod: h.deploy + "-1"}, h.line))
+	}
+
+	hops := traceHops(hits)
+	want := []string{"frontend", "checkout", "payments", "checkout"}
+	if len(hops) != len(want) {
+		t.Fatalf("got %d hops, want %d", len(hops), len(want))
+	}
+	for i, hop := range hops {
+		if hop.Deployment != want[i] {
+			t.Errorf("hop %d = %s, want %s", i, hop.Deployment, want[i])
+		}
+	}
+	if hops[0].Lines != 2 || hops[2].Errors != 1 || hops[3].Errors != 0 {
+		t.Errorf("hops = %+v", hops)
+	}
+	if hits[3].Text != "ERROR charge failed error=card declined trace_id=abc123" {
+		t.Errorf("JSON hit text = %q", hits[3].Text)
+	}
+	if slow := slowestTraceGap(hops); slow != 2 {
+		t.Errorf("slowestTraceGap() = %d, want 2 (checkout -> payments)", slow)
+	}
+
+	diagram := traceSequenceDiagram(hops).Render()
+	for _, s := range []string{"client->>frontend: request", "checkout->>payments: +1.50s", "Note right of payments: 1 error lines"} {
+		if !strings.Contains(diagram, s) {
+			t.Errorf("diagram missing %q:\n%s", s, diagram)
+		}
+	}
+}
+
+func TestTraceSearchTargets(t *testing.T) {
+	deployments := []appsv1.Deployment{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web"}, Spec: appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "everything"}, Spec: appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{}}},
+	}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Labels: map[string]string{"app": "web"}}, Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: "proxy"}}}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Labels: map[string]string{"app": "web"}}, Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}, Status: corev1.PodStatus{Phase: corev1.PodPending}},
+	}
+	targets := traceSearchTargets(deployments, pods)
+	if len(targets) != 2 || targets[0].Container != "app" || targets[1].Container != "proxy" {
+		t.Errorf("targets = %+v, want both containers of the running web pod", targets)
+	}
+	if got := filterDeploymentsByName(deployments, []string{"web"}); len(got) != 1 || got[0].Name != "web" {
+		t.Errorf("filterDeploymentsByName() = %v", got)
+	}
+}
+
+func TestFormatTraceDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		40 * time.Millisecond:   "40ms",
+		1500 * time.Millisecond: "1.50s",
+		-20 * time.Millisecond:  "-20ms",
+		90 * time.Second:        "1m30s",
+	} {
+		if got := formatTraceDuration(d); got != want {
+			t.Errorf("formatTraceDuration(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,410 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// triageEventWindow is how far back triage looks for warning events.
+const triageEventWindow = 15 * time.Minute
+
+type triageInput struct {
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace to focus on (empty = all namespaces; node and DNS checks always run cluster-wide)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+	DetailLevel    string `json:"detail_level,omitempty" jsonschema:"How much to return: summary (about 10 lines: status, top findings, next action), standard (the report without Mermaid diagrams), or full (everything, the default)"`
+}
+
+// triageItem is one finding from a triage check, under its catalog rule, with
+// the action that addresses it and the matching follow-up tool call.
+type triageItem struct {
+	Rule     string
+	Severity string
+	Check    string
+	Message  string
+	Action   string
+	Step     util.NextStep
+}
+
+// triageCheck is one step of the first-responder sequence.
+type triageCheck struct {
+	Name string
+	Run  func(ctx context.Context) ([]triageItem, error)
+}
+
+// triageResult is the outcome of one check.
+type triageResult struct {
+	Name     string
+	Items    []triageItem
+	Err      error
+	Duration time.Duration
+}
+
+func registerTriageTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "triage",
+		Description: "First-five-minutes incident triage. Runs the standard first-responder checks concurrently — node health, " +
+			"unhealthy pods, services with no ready endpoints, recent warning events, and CoreDNS health — and returns one " +
+			"prioritized action list. Use this as the first tool call of an incident, then follow the suggested tools.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input triageInput) (*mcp.CallToolResult, any, error) {
+		detail, err := util.ParseDetailLevel(input.DetailLevel)
+		if err != nil {
+			return util.ErrorResult("%v", err), nil, nil
+		}
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		ns := util.NamespaceOrAll(input.Namespace)
+		results := runTriageChecks(ctx, triageChecks(client, ns))
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Incident Triage (scope: %s)", displayNS(ns))))
+		sb.WriteString("\n\n")
+
+		rows := make([][]string, 0, len(results))
+		var items []triageItem
+		for _, r := range results {
+			rows = append(rows, []string{r.Name, triageStatus(r), r.Duration.Round(time.Millisecond).String()})
+			items = append(items, r.Items...)
+		}
+		sb.WriteString(util.FormatTable([]string{"CHECK", "STATUS", "TOOK"}, rows))
+		sb.WriteString("\n")
+
+		items = prioritizeTriageItems(items)
+		counts := make(map[string]int)
+		sb.WriteString("FINDINGS:\n")
+		if len(items) == 0 {
+			sb.WriteString("  No issues found by the triage checks.\n")
+		}
+		for _, it := range items {
+			counts[it.Severity]++
+			sb.WriteString(findings.Format(it.Rule, it.Severity, fmt.Sprintf("[%s] %s", it.Check, it.Message)))
+			sb.WriteString("\n")
+		}
+
+		var actions []string
+		var steps []util.NextStep
+		for _, it := range items {
+			if it.Action != "" {
+				actions = append(actions, it.Action)
+			}
+			if it.Step.Tool != "" {
+				steps = append(steps, it.Step)
+			}
+		}
+		if actions = dedupe(actions); len(actions) > 0 {
+			sb.WriteString("\nPRIORITIZED ACTIONS:\n")
+			for i, a := range actions {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("Overall Assessment"))
+		sb.WriteString("\n")
+		switch {
+		case counts["CRITICAL"] > 0:
+			sb.WriteString(fmt.Sprintf("  %d critical and %d warning finding(s). Work through the actions in order.\n", counts["CRITICAL"], counts["WARNING"]))
+		case counts["WARNING"] > 0:
+			sb.WriteString(fmt.Sprintf("  No critical findings; %d warning(s) to review.\n", counts["WARNING"]))
+		default:
+			sb.WriteString("  No problems detected by the first-responder checks. Narrow down with diagnose_service or diagnose_request_path for the affected application.\n")
+		}
+
+		return util.WithNextSteps(finishReport(sb.String(), detail), steps), nil, nil
+	})
+}
+
+// triageChecks returns the first-responder sequence in priority order.
+func triageChecks(client *k8s.ClusterClient, ns string) []triageCheck {
+	return []triageCheck{
+		{Name: "Nodes", Run: func(ctx context.Context) ([]triageItem, error) { return triageNodes(ctx, client) }},
+		{Name: "Pods", Run: func(ctx context.Context) ([]triageItem, error) { return triagePods(ctx, client, ns) }},
+		{Name: "Endpoints", Run: func(ctx context.Context) ([]triageItem, error) { return triageEndpoints(ctx, client, ns) }},
+		{Name: "Events", Run: func(ctx context.Context) ([]triageItem, error) { return triageEvents(ctx, client, ns) }},
+		{Name: "DNS", Run: func(ctx context.Context) ([]triageItem, error) { return triageDNS(ctx, client) }},
+	}
+}
+
+// runTriageChecks runs all checks concurrently and returns their results in check order.
+func runTriageChecks(ctx context.Context, checks []triageCheck) []triageResult {
+	results := make([]triageResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			items, err := c.Run(ctx)
+			for j := range items {
+				items[j].Check = c.Name
+			}
+			results[i] = triageResult{Name: c.Name, Items: items, Err: err, Duration: time.Since(start)}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// triageStatus summarizes a check result for the status table.
+func triageStatus(r triageResult) string {
+	switch {
+	case util.IsScopeError(r.Err):
+		return "SKIPPED (namespace-scoped mode)"
+	case apierrors.IsForbidden(r.Err):
+		return "SKIPPED (forbidden)"
+	case r.Err != nil:
+		return "ERROR: " + truncateName(r.Err.Error(), 80)
+	case len(r.Items) == 0:
+		return "OK"
+	}
+	return fmt.Sprintf("%d issue(s)", len(r.Items))
+}
+
+// prioritizeTriageItems orders items by severity, keeping check order within a severity.
+func prioritizeTriageItems(items []triageItem) []triageItem {
+	rank := map[string]int{"CRITICAL": 0, "WARNING": 1, "INFO": 2}
+	sort.SliceStable(items, func(i, j int) bool {
+		return rank[items[i].Severity] < rank[items[j].Severity]
+	})
+	return items
+}
+
+// triageNodes reports NotReady nodes and nodes under resource pressure.
+func triageNodes(ctx context.Context, client *k8s.ClusterClient) ([]triageItem, error) {
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var items []triageItem
+	for i := range nodes {
+		n := &nodes[i]
+		if status := nodeStatus(n); status != "Ready" {
+			items = append(items, triageItem{
+				Rule:     "KD-NODE-001",
+				Severity: "CRITICAL",
+				Message:  fmt.Sprintf("Node %s is %s", n.Name, status),
+				Action:   fmt.Sprintf("Inspect node %s conditions and kubelet events: get_node_detail name=%s", n.Name, n.Name),
+				Step:     nextStep("get_node_detail", fmt.Sprintf("Node is %s", status), "name", n.Name),
+			})
+		}
+		for _, cond := range n.Status.Conditions {
+			if (cond.Type == corev1.NodeMemoryPressure || cond.Type == corev1.NodeDiskPressure || cond.Type == corev1.NodePIDPressure) && cond.Status == corev1.ConditionTrue {
+				items = append(items, triageItem{
+					Rule:     "KD-NODE-002",
+					Severity: "WARNING",
+					Message:  fmt.Sprintf("Node %s has %s", n.Name, cond.Type),
+					Action:   "Find the heaviest pods on pressured nodes: top_resource_consumers",
+					Step:     nextStep("top_resource_consumers", fmt.Sprintf("Node %s has %s", n.Name, cond.Type), "resource", triagePressureResource(cond.Type)),
+				})
+			}
+		}
+	}
+	return items, nil
+}
+
+// triagePods groups unhealthy pods by workload and status so a failing
+// Deployment produces one finding rather than one per replica.
+func triagePods(ctx context.Context, client *k8s.ClusterClient, ns string) ([]triageItem, error) {
+	pods, err := client.ListPods(ctx, ns, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	type group struct {
+		namespace, workload, reason, example string
+		count                                int
+	}
+	groups := make(map[string]*group)
+	var order []string
+	for i := range pods {
+		p := &pods[i]
+		if isPodHealthy(p) || p.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+		reason := podPhaseReason(p)
+		key := p.Namespace + "/" + podWorkloadName(p) + "/" + reason
+		g, ok := groups[key]
+		if !ok {
+			g = &group{namespace: p.Namespace, workload: podWorkloadName(p), reason: reason, example: p.Name}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.count++
+	}
+
+	items := make([]triageItem, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		items = append(items, triageItem{
+			Rule:     triagePodRule(g.reason),
+			Severity: triagePodSeverity(g.reason),
+			Message:  fmt.Sprintf("%s/%s: %d pod(s) %s (e.g. %s)", g.namespace, g.workload, g.count, g.reason, g.example),
+			Action:   triagePodAction(g.reason, g.namespace, g.example),
+			Step:     triagePodStep(g.reason, g.namespace, g.example),
+		})
+	}
+	return items, nil
+}
+
+// triagePodRule returns the catalog rule for an unhealthy pod status.
+func triagePodRule(reason string) string {
+	switch strings.TrimPrefix(reason, "Init:") {
+	case "CrashLoopBackOff":
+		return "KD-POD-001"
+	case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+		return "KD-POD-002"
+	case "OOMKilled":
+		return "KD-POD-003"
+	}
+	return "KD-POD-010"
+}
+
+// triagePodSeverity rates an unhealthy pod status.
+func triagePodSeverity(reason string) string {
+	if criticalWaitingReasons[strings.TrimPrefix(reason, "Init:")] || reason == "OOMKilled" || reason == "Evicted" {
+		return "CRITICAL"
+	}
+	return "WARNING"
+}
+
+// triagePodAction returns the next tool call for an unhealthy pod status.
+func triagePodAction(reason, namespace, pod string) string {
+	switch strings.TrimPrefix(reason, "Init:") {
+	case "CrashLoopBackOff", "Error":
+		return fmt.Sprintf("Read the crashing container's previous logs: get_pod_logs namespace=%s name=%s previous=true", namespace, pod)
+	case "OOMKilled":
+		return fmt.Sprintf("Compare memory usage with limits in %s: get_pod_metrics namespace=%s", namespace, namespace)
+	case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+		return fmt.Sprintf("Check the image reference and pull secrets: diagnose_pod namespace=%s name=%s", namespace, pod)
+	}
+	return fmt.Sprintf("Diagnose the pod: diagnose_pod namespace=%s name=%s", namespace, pod)
+}
+
+// triagePodStep returns the follow-up tool call matching triagePodAction.
+func triagePodStep(reason, namespace, pod string) util.NextStep {
+	switch strings.TrimPrefix(reason, "Init:") {
+	case "CrashLoopBackOff", "Error":
+		return nextStep("get_pod_logs", "Read the crashing container's previous logs", "namespace", namespace, "name", pod, "previous", true)
+	case "OOMKilled":
+		return nextStep("get_pod_metrics", "Compare memory usage with limits", "namespace", namespace)
+	}
+	return nextStep("diagnose_pod", fmt.Sprintf("Pod is %s", reason), "namespace", namespace, "name", pod)
+}
+
+// triagePressureResource maps a node pressure condition to the resource
+// top_resource_consumers should sort by.
+func triagePressureResource(cond corev1.NodeConditionType) string {
+	if cond == corev1.NodeMemoryPressure {
+		return "memory"
+	}
+	return "cpu"
+}
+
+// triageEndpoints reports selector-based services with no ready endpoints.
+func triageEndpoints(ctx context.Context, client *k8s.ClusterClient, ns string) ([]triageItem, error) {
+	services, err := client.ListServices(ctx, ns, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var items []triageItem
+	for _, svc := range services {
+		if svc.Spec.Type == corev1.ServiceTypeExternalName || len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		health, err := client.GetServiceEndpointHealth(ctx, svc.Namespace, svc.Name)
+		if err != nil || health.ReadyCount > 0 {
+			continue
+		}
+		msg := fmt.Sprintf("Service %s/%s has 0 endpoints", svc.Namespace, svc.Name)
+		if health.NotReadyCount > 0 {
+			msg = fmt.Sprintf("Service %s/%s has 0 ready endpoints (%d not ready)", svc.Namespace, svc.Name, health.NotReadyCount)
+		}
+		items = append(items, triageItem{
+			Rule:     "KD-SVC-001",
+			Severity: "CRITICAL",
+			Message:  msg,
+			Action:   fmt.Sprintf("Trace why the service has no backends: diagnose_service namespace=%s service_name=%s", svc.Namespace, svc.Name),
+			Step:     nextStep("diagnose_service", "Service has no ready endpoints", "namespace", svc.Namespace, "service_name", svc.Name),
+		})
+	}
+	return items, nil
+}
+
+// triageEvents summarizes warning events from the last triageEventWindow by reason.
+func triageEvents(ctx context.Context, client *k8s.ClusterClient, ns string) ([]triageItem, error) {
+	events, err := client.ListEvents(ctx, ns, metav1.ListOptions{FieldSelector: "type=Warning"})
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().Add(-triageEventWindow)
+	byReason := make(map[string]int)
+	example := make(map[string]string)
+	for i := range events {
+		e := &events[i]
+		if e.Type != corev1.EventTypeWarning || k8s.EventTime(e).Before(cutoff) {
+			continue
+		}
+		byReason[e.Reason] += int(max(e.Count, 1))
+		if _, ok := example[e.Reason]; !ok {
+			example[e.Reason] = fmt.Sprintf("%s %s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Namespace, e.InvolvedObject.Name)
+		}
+	}
+
+	scope := ""
+	if ns != "" {
+		scope = " namespace=" + ns
+	}
+	var items []triageItem
+	for _, kc := range topCounts(byReason, 5) {
+		items = append(items, triageItem{
+			Rule:     "KD-EVT-001",
+			Severity: "WARNING",
+			Message:  fmt.Sprintf("%d %s warning event(s) in the last %s (e.g. %s)", kc.count, kc.key, triageEventWindow, example[kc.key]),
+			Action:   fmt.Sprintf("Review recent %s events: get_events%s event_type=Warning reasons=%s", kc.key, scope, kc.key),
+			Step:     nextStep("get_events", fmt.Sprintf("%d recent %s warnings", kc.count, kc.key), "namespace", ns, "event_type", "Warning", "reasons", kc.key),
+		})
+	}
+	return items, nil
+}
+
+// triageDNS checks that CoreDNS is running and the kube-dns service has ready endpoints.
+func triageDNS(ctx context.Context, client *k8s.ClusterClient) ([]triageItem, error) {
+	pods, err := client.ListPods(ctx, "kube-system", metav1.ListOptions{LabelSelector: "k8s-app=kube-dns"})
+	if err != nil {
+		return nil, err
+	}
+	ready := 0
+	for i := range pods {
+		if isPodHealthy(&pods[i]) {
+			ready++
+		}
+	}
+
+	action := "Check CoreDNS pods and logs: check_dns_health"
+	step := nextStep("check_dns_health", "CoreDNS is degraded")
+	switch {
+	case len(pods) == 0:
+		return []triageItem{{Rule: "KD-NET-002", Severity: "CRITICAL", Message: "No CoreDNS pods (k8s-app=kube-dns) found in kube-system", Action: action, Step: step}}, nil
+	case ready == 0:
+		return []triageItem{{Rule: "KD-NET-002", Severity: "CRITICAL", Message: fmt.Sprintf("0/%d CoreDNS pods healthy — in-cluster DNS resolution is failing", len(pods)), Action: action, Step: step}}, nil
+	case ready < len(pods):
+		return []triageItem{{Rule: "KD-NET-002", Severity: "WARNING", Message: fmt.Sprintf("%d/%d CoreDNS pods healthy", ready, len(pods)), Action: action, Step: step}}, nil
+	}
+
+	health, err := client.GetServiceEndpointHealth(ctx, "kube-system", "kube-dns")
+	if err == nil && health.ReadyCount == 0 {
+		return []triageItem{{Rule: "KD-NET-002", Severity: "CRITICAL", Message: "Service kube-system/kube-dns has 0 ready endpoints", Action: action, Step: step}}, nil
+	}
+	return nil, nil
+}
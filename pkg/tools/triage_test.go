@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+func TestTriageChecks(t *testing.T) {
+	crashing := func(name string) *corev1.Pod {
+		p := withOwner(testPod("shop", name, "", map[string]string{"pod-template-hash": "abc"}), "ReplicaSet", "cart-abc")
+		p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  "app",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}
+		return &p
+	}
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+			}},
+		},
+		crashing("cart-abc-1"),
+		crashing("cart-abc-2"),
+	)
+	client := k8s.NewClusterClientForTesting(fakeClient, nil)
+
+	results := runTriageChecks(context.Background(), triageChecks(client, ""))
+	if len(results) != 5 || results[0].Name != "Nodes" || results[4].Name != "DNS" {
+		t.Fatalf("expected 5 results in check order, got %+v", results)
+	}
+
+	var items []triageItem
+	for _, r := range results {
+		items = append(items, r.Items...)
+	}
+	items = prioritizeTriageItems(items)
+
+	// Both crashing replicas collapse into one finding, and the missing
+	// CoreDNS and crash loop outrank the disk-pressure warning.
+	pods := 0
+	for _, it := range items {
+		if it.Check == "Pods" {
+			pods++
+		}
+	}
+	if pods != 1 {
+		t.Errorf("expected 1 grouped pod finding, got %d", pods)
+	}
+	if items[0].Severity != "CRITICAL" || items[len(items)-1].Severity != "WARNING" {
+		t.Errorf("expected CRITICAL items first, got %+v", items)
+	}
+}
+
+func TestTriagePodStep(t *testing.T) {
+	step := triagePodStep("CrashLoopBackOff", "shop", "cart-abc")
+	if step.Tool != "get_pod_logs" || step.Arguments["previous"] != true || step.Arguments["name"] != "cart-abc" {
+		t.Errorf("expected previous logs step for a crash loop, got %+v", step)
+	}
+	step = triagePodStep("Pending", "shop", "cart-abc")
+	if step.Tool != "diagnose_pod" || step.Arguments["namespace"] != "shop" {
+		t.Errorf("expected diagnose_pod step for a pending pod, got %+v", step)
+	}
+	if step := nextStep("get_events", "why", "namespace", "", "event_type", "Warning"); len(step.Arguments) != 1 {
+		t.Errorf("expected empty arguments to be omitted, got %v", step.Arguments)
+	}
+}
@@ -0,0 +1,314 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// usageHistoryDays is the default look-back window for historical usage.
+	usageHistoryDays = 7
+
+	// usageHistoryQuantile is the default usage quantile for right-sizing.
+	usageHistoryQuantile = 0.95
+)
+
+type queryUsageHistoryInput struct {
+	Namespace      string  `json:"namespace" jsonschema:"required,Kubernetes namespace"`
+	Workload       string  `json:"workload,omitempty" jsonschema:"Only report this workload (Deployment, StatefulSet, DaemonSet, or Job name)"`
+	WindowDays     int     `json:"window_days,omitempty" jsonschema:"Days of history to analyze (default 7, max 90)"`
+	Quantile       float64 `json:"quantile,omitempty" jsonschema:"Usage quantile between 0 and 1 (default 0.95)"`
+	TimeoutSeconds int     `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// workloadUsage is the historical usage of one container across every pod of
+// a workload, next to its current requests and limits.
+type workloadUsage struct {
+	Kind        string
+	Workload    string
+	Container   string
+	Pods        int
+	CPUMillis   int64
+	MemoryBytes int64
+	CPURequest  int64
+	CPULimit    int64
+	MemRequest  int64
+	MemLimit    int64
+	HasSpec     bool
+}
+
+func registerUsageHistoryTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "query_usage_history",
+		Description: "Report historical CPU and memory usage per workload container from Prometheus (default p95 over 7 days) " +
+			"next to current requests and limits, flagging under-requested, near-limit, and overprovisioned containers. " +
+			"Uses the highest quantile across all pods the workload ran in the window, including replaced pods. " +
+			"Requires the server to be started with --prometheus-url.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input queryUsageHistoryInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if input.Namespace == "" {
+			return util.ErrorResult("namespace is required"), nil, nil
+		}
+		if client.Prometheus == nil {
+			return util.ErrorResult("Prometheus is not configured; start the server with --prometheus-url to query usage history"), nil, nil
+		}
+		days := input.WindowDays
+		if days <= 0 {
+			days = usageHistoryDays
+		}
+		if days > 90 {
+			days = 90
+		}
+		quantile := input.Quantile
+		if quantile <= 0 || quantile > 1 {
+			quantile = usageHistoryQuantile
+		}
+
+		history, err := client.GetUsageHistory(ctx, k8s.UsageQuery{
+			Namespace:    input.Namespace,
+			Window:       time.Duration(days) * 24 * time.Hour,
+			Quantile:     quantile,
+			PerContainer: true,
+		})
+		if err != nil {
+			return util.ErrorResult("querying usage history: %v", err), nil, nil
+		}
+		pods, err := client.ListPods(ctx, input.Namespace, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing pods", err), nil, nil
+		}
+
+		usage := aggregateWorkloadUsage(history, pods, input.Workload)
+		label := fmt.Sprintf("p%g", quantile*100)
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Usage History: %s (%s over %dd)", input.Namespace, label, days)))
+		sb.WriteString("\n\n")
+
+		if len(usage) == 0 {
+			sb.WriteString("No usage history found. Check that Prometheus scrapes cAdvisor metrics (container_cpu_usage_seconds_total) for this namespace.\n")
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		headers := []string{"WORKLOAD", "CONTAINER", "PODS", label + " CPU", "CPU REQ", label + " MEM", "MEM REQ", "MEM LIMIT"}
+		rows := make([][]string, 0, len(usage))
+		var findingLines []string
+		var actions []string
+		for _, u := range usage {
+			cpuReq, memReq, memLim := "-", "-", "-"
+			if u.HasSpec {
+				cpuReq, memReq, memLim = formatMillis(u.CPURequest), formatBytesOrNone(u.MemRequest), formatBytesOrNone(u.MemLimit)
+			}
+			rows = append(rows, []string{
+				truncateName(u.Workload, 40), u.Container, fmt.Sprintf("%d", u.Pods),
+				fmt.Sprintf("%dm", u.CPUMillis), cpuReq,
+				formatBytes(u.MemoryBytes), memReq, memLim,
+			})
+
+			if !u.HasSpec {
+				continue
+			}
+			name := u.Workload + "/" + u.Container
+			switch {
+			case u.MemLimit > 0 && u.MemoryBytes > u.MemLimit*9/10:
+				findingLines = append(findingLines, findings.Format("KD-RES-006", "CRITICAL",
+					fmt.Sprintf("%s: %s memory %s is over 90%% of its %s limit — OOMKill risk", name, label, formatBytes(u.MemoryBytes), formatBytes(u.MemLimit))))
+				actions = append(actions, fmt.Sprintf("Raise the memory limit of %s above %s", name, formatBytes(u.MemoryBytes*12/10)))
+			case u.MemRequest > 0 && u.MemoryBytes > u.MemRequest:
+				findingLines = append(findingLines, findings.Format("KD-RES-006", "WARNING",
+					fmt.Sprintf("%s: %s memory %s exceeds its %s request", name, label, formatBytes(u.MemoryBytes), formatBytes(u.MemRequest))))
+				actions = append(actions, fmt.Sprintf("Raise the memory request of %s to about %s", name, formatBytes(u.MemoryBytes*115/100)))
+			}
+			if u.CPURequest > 0 && u.CPUMillis > u.CPURequest {
+				findingLines = append(findingLines, findings.Format("KD-RES-006", "WARNING",
+					fmt.Sprintf("%s: %s CPU %dm exceeds its %dm request", name, label, u.CPUMillis, u.CPURequest)))
+				actions = append(actions, fmt.Sprintf("Raise the CPU request of %s to about %dm", name, u.CPUMillis*115/100))
+			}
+			if u.CPURequest > 0 && u.MemRequest > 0 && u.CPUMillis*10 < u.CPURequest*3 && u.MemoryBytes*10 < u.MemRequest*3 {
+				findingLines = append(findingLines, findings.Format("KD-RES-004", "INFO",
+					fmt.Sprintf("%s: %s usage is under 30%% of requests (CPU %dm of %dm, memory %s of %s)",
+						name, label, u.CPUMillis, u.CPURequest, formatBytes(u.MemoryBytes), formatBytes(u.MemRequest))))
+				actions = append(actions, fmt.Sprintf("Right-size %s requests to about %dm CPU and %s memory (%s plus 15%% headroom); recommend_resources prints the patch",
+					name, max(u.CPUMillis*115/100, 10), formatBytes(u.MemoryBytes*115/100), label))
+			}
+		}
+		sb.WriteString(util.FormatTable(headers, rows))
+
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("Requests and limits fit the %s usage of every container", label)))
+			sb.WriteString("\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString(f + "\n")
+		}
+		if len(actions) > 0 {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// aggregateWorkloadUsage groups per-pod container usage by workload, keeping
+// the highest value across pods, and joins it with the requests and limits of
+// the workload's current pods. An empty workload filter keeps every workload.
+func aggregateWorkloadUsage(history []k8s.Usage, pods []corev1.Pod, workload string) []workloadUsage {
+	byKey := make(map[string]*workloadUsage)
+	for _, h := range history {
+		name := workloadFromPodName(h.Pod)
+		if workload != "" && name != workload {
+			continue
+		}
+		key := name + "/" + h.Container
+		u, ok := byKey[key]
+		if !ok {
+			u = &workloadUsage{Workload: name, Container: h.Container}
+			byKey[key] = u
+		}
+		u.Pods++
+		u.CPUMillis = max(u.CPUMillis, h.CPUMillis)
+		u.MemoryBytes = max(u.MemoryBytes, h.MemoryBytes)
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		name := podWorkloadName(pod)
+		for _, c := range pod.Spec.Containers {
+			u, ok := byKey[name+"/"+c.Name]
+			if !ok || u.HasSpec {
+				continue
+			}
+			u.HasSpec = true
+			u.Kind = podWorkloadKind(pod)
+			u.CPURequest = c.Resources.Requests.Cpu().MilliValue()
+			u.CPULimit = c.Resources.Limits.Cpu().MilliValue()
+			u.MemRequest = c.Resources.Requests.Memory().Value()
+			u.MemLimit = c.Resources.Limits.Memory().Value()
+		}
+	}
+
+	out := make([]workloadUsage, 0, len(byKey))
+	for _, u := range byKey {
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Workload != out[j].Workload {
+			return out[i].Workload < out[j].Workload
+		}
+		return out[i].Container < out[j].Container
+	})
+	return out
+}
+
+// podWorkloadKind returns the kind of the workload named by podWorkloadName:
+// Deployment for ReplicaSet-owned pods, the controller kind otherwise, and
+// "" for standalone pods.
+func podWorkloadKind(pod *corev1.Pod) string {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if ref.Kind == "ReplicaSet" && pod.Labels["pod-template-hash"] != "" {
+			return "Deployment"
+		}
+		return ref.Kind
+	}
+	return ""
+}
+
+// podNameSuffixChars are the characters Kubernetes uses for generated name
+// suffixes and pod-template-hashes; they contain no vowels.
+const podNameSuffixChars = "bcdfghjklmnpqrstvwxz2456789"
+
+// workloadFromPodName guesses the owning workload from a pod name, for pods
+// in usage history that no longer exist. It strips the random suffix and
+// pod-template-hash of Deployment pods, the suffix of DaemonSet and Job pods,
+// and the ordinal of StatefulSet pods.
+func workloadFromPodName(pod string) string {
+	parts := strings.Split(pod, "-")
+	isGenerated := func(s string, minLen, maxLen int) bool {
+		if len(s) < minLen || len(s) > maxLen {
+			return false
+		}
+		for _, r := range s {
+			if !strings.ContainsRune(podNameSuffixChars, r) {
+				return false
+			}
+		}
+		return true
+	}
+	isOrdinal := func(s string) bool {
+		for _, r := range s {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+		return s != ""
+	}
+
+	switch last := parts[len(parts)-1]; {
+	case len(parts) > 1 && isGenerated(last, 5, 5):
+		parts = parts[:len(parts)-1]
+		if len(parts) > 1 && isGenerated(parts[len(parts)-1], 6, 10) {
+			parts = parts[:len(parts)-1]
+		}
+	case len(parts) > 1 && isOrdinal(last):
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, "-")
+}
+
+// podUsageHistory returns each pod's p95 usage over the default window, or
+// nil when Prometheus is not configured.
+func podUsageHistory(ctx context.Context, client *k8s.ClusterClient, namespace string) ([]k8s.Usage, error) {
+	if client.Prometheus == nil {
+		return nil, nil
+	}
+	return client.GetUsageHistory(ctx, k8s.UsageQuery{
+		Namespace: namespace,
+		Window:    usageHistoryDays * 24 * time.Hour,
+		Quantile:  usageHistoryQuantile,
+	})
+}
+
+// usageSourceNote states where usage figures in a report come from.
+func usageSourceNote(history []k8s.Usage, err error) string {
+	switch {
+	case err != nil:
+		return findings.Format("KD-SYS-008", "WARNING", fmt.Sprintf("Prometheus usage history unavailable (%v); using a single metrics-server sample", err)) + "\n\n"
+	case len(history) > 0:
+		return fmt.Sprintf("Usage source: p95 over %dd from Prometheus (metrics-server sample for pods without history)\n\n", usageHistoryDays)
+	default:
+		return ""
+	}
+}
+
+// formatMillis formats a CPU quantity in millicores, or "none" when unset.
+func formatMillis(m int64) string {
+	if m == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%dm", m)
+}
+
+// formatBytesOrNone formats a memory quantity, or "none" when unset.
+func formatBytesOrNone(b int64) string {
+	if b == 0 {
+		return "none"
+	}
+	return formatBytes(b)
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+)
+
+func TestWorkloadFromPodName(t *testing.T) {
+	cases := map[string]string{
+		"web-7d9f8b6c5d-x2k4q": "web",
+		"kube-proxy-bz7xw":     "kube-proxy",
+		"postgres-0":           "postgres",
+		"web-backend-abcde":    "web-backend-abcde",
+		"standalone":           "standalone",
+	}
+	for pod, want := range cases {
+		if got := workloadFromPodName(pod); got != want {
+			t.Errorf("workloadFromPodName(%q) = %q, want %q", pod, got, want)
+		}
+	}
+}
+
+func TestAggregateWorkloadUsage(t *testing.T) {
+	history := []k8s.Usage{
+		{Namespace: "shop", Pod: "web-7d9f8b6c5d-x2k4q", Container: "app", CPUMillis: 120, MemoryBytes: 200 << 20},
+		{Namespace: "shop", Pod: "web-6b8c9d7f4-pq2zr", Container: "app", CPUMillis: 300, MemoryBytes: 100 << 20},
+		{Namespace: "shop", Pod: "db-0", Container: "postgres", CPUMillis: 50, MemoryBytes: 1 << 30},
+	}
+	controller := true
+	pods := []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-7d9f8b6c5d-x2k4q",
+			Labels:          map[string]string{"pod-template-hash": "7d9f8b6c5d"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-7d9f8b6c5d", Controller: &controller}},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "app",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+		}}},
+	}}
+
+	usage := aggregateWorkloadUsage(history, pods, "")
+	if len(usage) != 2 {
+		t.Fatalf("expected 2 workload containers, got %+v", usage)
+	}
+	web := usage[1]
+	if web.Workload != "web" || web.Pods != 2 || web.CPUMillis != 300 || web.MemoryBytes != 200<<20 {
+		t.Errorf("expected max usage across web pods, got %+v", web)
+	}
+	if !web.HasSpec || web.CPURequest != 1000 || web.MemRequest != 1<<30 {
+		t.Errorf("expected current requests joined to web/app, got %+v", web)
+	}
+	if usage[0].HasSpec {
+		t.Errorf("expected db without current pods to have no spec, got %+v", usage[0])
+	}
+
+	if only := aggregateWorkloadUsage(history, pods, "db"); len(only) != 1 || only[0].Workload != "db" {
+		t.Errorf("expected workload filter to keep only db, got %+v", only)
+	}
+}
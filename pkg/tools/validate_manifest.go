@@ -0,0 +1,311 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// maxManifestDocs caps how many objects one validate_manifest call submits.
+const maxManifestDocs = 50
+
+type validateManifestInput struct {
+	Manifest       string `json:"manifest" jsonschema:"required,YAML or JSON manifest; several documents separated by --- and List objects are accepted (e.g. kustomize build output)"`
+	Namespace      string `json:"namespace,omitempty" jsonschema:"Namespace for namespaced objects that do not set metadata.namespace (default: default)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// manifestDoc is one object of a manifest with its 1-based position.
+type manifestDoc struct {
+	Index int
+	Obj   *unstructured.Unstructured
+}
+
+// manifestResult is the dry-run outcome of one manifest object. Category is
+// empty when the object passed.
+type manifestResult struct {
+	Doc      manifestDoc
+	Category string
+	Details  []string
+	Warnings []string
+}
+
+func registerValidateManifestTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "validate_manifest",
+		Description: "Validate a proposed manifest before anyone applies it: each object is sent as a server-side apply with dryRun=All and strict field validation, " +
+			"so the API server runs schema validation, defaulting, admission webhooks (Gatekeeper, Kyverno), ValidatingAdmissionPolicies, Pod Security, and quota " +
+			"without persisting anything. Reports per object whether it would be accepted, the field errors or denial messages, unknown kinds (missing CRDs), " +
+			"and API warnings such as deprecated versions. Accepts multi-document YAML and kustomize build output. Needs RBAC to patch the objects.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input validateManifestInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		if strings.TrimSpace(input.Manifest) == "" {
+			return util.ErrorResult("manifest is required"), nil, nil
+		}
+		docs, err := parseManifest(input.Manifest)
+		if err != nil {
+			return util.ErrorResult("cannot parse manifest: %v", err), nil, nil
+		}
+		if len(docs) == 0 {
+			return util.ErrorResult("manifest contains no objects"), nil, nil
+		}
+		if len(docs) > maxManifestDocs {
+			return util.ErrorResult("manifest has %d objects; validate at most %d per call", len(docs), maxManifestDocs), nil, nil
+		}
+		defaultNS := input.Namespace
+		if defaultNS == "" {
+			defaultNS = "default"
+		}
+
+		ctx, warnings := k8s.WithWarnings(ctx)
+		refs := make(map[schema.GroupVersionKind]k8s.APIResourceRef)
+		results := make([]manifestResult, 0, len(docs))
+		for _, doc := range docs {
+			res := manifestResult{Doc: doc}
+			if problems := manifestObjectProblems(doc.Obj); len(problems) > 0 {
+				res.Category, res.Details = "malformed", problems
+				results = append(results, res)
+				continue
+			}
+			gvk := doc.Obj.GroupVersionKind()
+			ref, ok := refs[gvk]
+			if !ok {
+				var err error
+				if ref, err = client.ResolveResource(ctx, gvk.Group, gvk.Version, gvk.Kind); err != nil {
+					res.Category, res.Details = dryRunProblem(err)
+					if strings.Contains(err.Error(), "no API resource matches") {
+						res.Category, res.Details = "unknown kind", []string{fmt.Sprintf("%s %s is not served by this cluster; install its CRD or fix apiVersion", doc.Obj.GetAPIVersion(), gvk.Kind)}
+					}
+					results = append(results, res)
+					continue
+				}
+				refs[gvk] = ref
+			}
+			if ref.Namespaced {
+				if doc.Obj.GetNamespace() == "" {
+					doc.Obj.SetNamespace(defaultNS)
+				}
+			} else {
+				doc.Obj.SetNamespace("")
+			}
+			_, err := client.DryRunApply(ctx, ref, doc.Obj.GetNamespace(), doc.Obj)
+			res.Warnings = warnings.Take()
+			if err != nil {
+				res.Category, res.Details = dryRunProblem(err)
+			}
+			results = append(results, res)
+		}
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Manifest Validation (server-side dry run)"))
+		sb.WriteString("\n\n")
+		failed, warned := 0, 0
+		rows := make([][]string, 0, len(results))
+		for _, r := range results {
+			result := "Accepted"
+			if r.Category != "" {
+				failed++
+				result = "Rejected: " + r.Category
+			} else if len(r.Warnings) > 0 {
+				warned++
+				result = "Accepted with warnings"
+			}
+			rows = append(rows, []string{fmt.Sprintf("%d", r.Doc.Index), r.Doc.Obj.GetKind(), manifestObjectName(r.Doc.Obj), result})
+		}
+		sb.WriteString(util.FormatKeyValue("Objects", fmt.Sprintf("%d (%d accepted, %d rejected)", len(results), len(results)-failed, failed)) + "\n\n")
+		sb.WriteString(util.FormatTable([]string{"#", "KIND", "NAME", "RESULT"}, rows))
+
+		if failed+warned > 0 {
+			sb.WriteString("\nDETAILS:\n")
+			for _, r := range results {
+				if r.Category == "" && len(r.Warnings) == 0 {
+					continue
+				}
+				sb.WriteString(fmt.Sprintf("  [%d] %s %s\n", r.Doc.Index, r.Doc.Obj.GetKind(), manifestObjectName(r.Doc.Obj)))
+				for _, d := range r.Details {
+					sb.WriteString(fmt.Sprintf("      - %s\n", d))
+				}
+				for _, w := range r.Warnings {
+					sb.WriteString(fmt.Sprintf("      ! warning: %s\n", w))
+				}
+			}
+		}
+
+		sb.WriteString("\nFINDINGS:\n")
+		var actions []string
+		for _, r := range results {
+			name := fmt.Sprintf("%s %s", r.Doc.Obj.GetKind(), manifestObjectName(r.Doc.Obj))
+			if r.Category != "" {
+				sb.WriteString(findings.Format("KD-CFG-005", "CRITICAL", fmt.Sprintf("%s would be rejected (%s): %s", name, r.Category, truncateName(r.Details[0], 200))))
+				sb.WriteString("\n")
+				actions = append(actions, manifestAction(r.Category))
+			}
+			if len(r.Warnings) > 0 {
+				sb.WriteString(findings.Format("KD-CFG-005", "WARNING", fmt.Sprintf("%s is accepted with %d API warning(s): %s", name, len(r.Warnings), truncateName(r.Warnings[0], 200))))
+				sb.WriteString("\n")
+				actions = append(actions, "Resolve API warnings before they become errors: move deprecated apiVersions to their replacements and fix Pod Security warn-mode violations")
+			}
+		}
+		if failed+warned == 0 {
+			sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("All %d object(s) passed schema validation and admission; nothing was persisted", len(results))))
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+		return util.SuccessResult(sb.String()), nil, nil
+	})
+}
+
+// parseManifest splits YAML or JSON into objects, expanding List kinds.
+// Empty documents are skipped.
+func parseManifest(manifest string) ([]manifestDoc, error) {
+	dec := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	var docs []manifestDoc
+	for n := 1; ; n++ {
+		var raw map[string]any
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return docs, nil
+			}
+			return nil, fmt.Errorf("document %d: %w", n, err)
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: raw}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("document %d: %w", n, err)
+			}
+			for i := range list.Items {
+				docs = append(docs, manifestDoc{Index: len(docs) + 1, Obj: &list.Items[i]})
+			}
+			continue
+		}
+		docs = append(docs, manifestDoc{Index: len(docs) + 1, Obj: obj})
+	}
+}
+
+// manifestObjectProblems returns what an object lacks to be applied at all.
+func manifestObjectProblems(obj *unstructured.Unstructured) []string {
+	var problems []string
+	if obj.GetAPIVersion() == "" {
+		problems = append(problems, "apiVersion is missing")
+	} else if _, err := schema.ParseGroupVersion(obj.GetAPIVersion()); err != nil {
+		problems = append(problems, fmt.Sprintf("apiVersion %q is invalid", obj.GetAPIVersion()))
+	}
+	if obj.GetKind() == "" {
+		problems = append(problems, "kind is missing")
+	}
+	if obj.GetName() == "" {
+		if obj.GetGenerateName() != "" {
+			problems = append(problems, "metadata.generateName cannot be applied; set metadata.name")
+		} else {
+			problems = append(problems, "metadata.name is missing")
+		}
+	}
+	return problems
+}
+
+// manifestObjectName returns namespace/name, or name for cluster-scoped objects.
+func manifestObjectName(obj *unstructured.Unstructured) string {
+	name := valueOrNone(obj.GetName())
+	if ns := obj.GetNamespace(); ns != "" {
+		return ns + "/" + name
+	}
+	return name
+}
+
+// dryRunProblem classifies a dry-run apply error and lists its details, one
+// per field error when the API server names the fields.
+func dryRunProblem(err error) (string, []string) {
+	msg := err.Error()
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		msg = status.Status().Message
+	}
+	lower := strings.ToLower(msg)
+
+	category := "error"
+	switch {
+	case util.IsScopeError(err) || strings.Contains(msg, "outside this server's namespaces"):
+		category = "out of scope"
+	case strings.Contains(lower, "failed calling webhook"):
+		category = "webhook unavailable"
+	case strings.Contains(lower, "admission webhook"):
+		category = "webhook denial"
+	case strings.Contains(lower, "validatingadmissionpolic"):
+		category = "admission policy"
+	case strings.Contains(lower, "violates podsecurity"):
+		category = "pod security"
+	case strings.Contains(lower, "exceeded quota") || strings.Contains(lower, "must specify limits") || strings.Contains(lower, "must specify requests"):
+		category = "quota"
+	case apierrors.IsInvalid(err) || strings.Contains(lower, "strict decoding error") || strings.Contains(lower, "unknown field"):
+		category = "schema"
+	case apierrors.IsNotFound(err) && strings.Contains(lower, "namespace"):
+		category = "missing namespace"
+	case apierrors.IsForbidden(err):
+		category = "permission"
+	case apierrors.IsBadRequest(err):
+		category = "schema"
+	}
+
+	var details []string
+	if status != nil {
+		if d := status.Status().Details; d != nil {
+			for _, c := range d.Causes {
+				switch {
+				case c.Field != "" && !strings.Contains(c.Message, c.Field):
+					details = append(details, fmt.Sprintf("%s: %s", c.Field, c.Message))
+				case c.Message != "":
+					details = append(details, c.Message)
+				}
+			}
+		}
+	}
+	if len(details) == 0 {
+		details = []string{msg}
+	}
+	return category, details
+}
+
+// manifestAction suggests how to fix an object rejected for category.
+func manifestAction(category string) string {
+	switch category {
+	case "schema", "malformed":
+		return "Fix the listed fields against the API schema (kubectl explain shows valid fields); strict validation also rejects unknown and duplicate fields"
+	case "unknown kind":
+		return "Install the CRD that serves the kind, or correct apiVersion/kind (get_api_resources lists what the cluster serves)"
+	case "webhook denial", "admission policy":
+		return "Change the object to satisfy the policy named in the denial, or request an exception from the policy owner; the dry run hit the same admission chain a real apply would"
+	case "webhook unavailable":
+		return "A webhook the object must pass is unreachable; check the webhook's Service and pods (list_webhook_configs) before applying anything it intercepts"
+	case "pod security":
+		return "Adjust the pod's securityContext to the namespace's enforced Pod Security level (check_pod_security_standards lists the violations)"
+	case "quota":
+		return "Set requests/limits required by the namespace's LimitRange, or free ResourceQuota headroom (analyze_quota_pressure)"
+	case "missing namespace":
+		return "Create the target namespace first, or apply the Namespace object before the rest of the manifest"
+	case "permission":
+		return "The server's identity cannot patch this resource; the dry run needs the same RBAC as a real apply"
+	case "out of scope":
+		return "Validate only objects in the namespaces this server is restricted to"
+	}
+	return "Read the API server's error for the rejected object and retry the dry run after fixing it"
+}
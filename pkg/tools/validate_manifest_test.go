@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestParseManifest(t *testing.T) {
+	manifest := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+---
+# comment-only document
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: web
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    generateName: web-
+`
+	docs, err := parseManifest(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("expected 3 objects (List expanded, empty doc skipped), got %d", len(docs))
+	}
+	if docs[1].Obj.GetKind() != "Service" || docs[2].Index != 3 {
+		t.Errorf("unexpected documents: %+v", docs)
+	}
+	if problems := manifestObjectProblems(docs[2].Obj); len(problems) != 1 || !strings.Contains(problems[0], "generateName") {
+		t.Errorf("expected generateName problem, got %v", problems)
+	}
+	if problems := manifestObjectProblems(docs[0].Obj); len(problems) != 0 {
+		t.Errorf("expected no problems for the ConfigMap, got %v", problems)
+	}
+
+	if _, err := parseManifest("kind: [unclosed"); err == nil {
+		t.Error("expected a YAML syntax error")
+	}
+}
+
+func TestDryRunProblem(t *testing.T) {
+	gk := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	invalid := apierrors.NewInvalid(gk, "web", field.ErrorList{
+		field.Required(field.NewPath("spec", "selector"), ""),
+		field.Invalid(field.NewPath("spec", "replicas"), -1, "must be greater than or equal to 0"),
+	})
+	category, details := dryRunProblem(invalid)
+	if category != "schema" || len(details) != 2 || !strings.Contains(details[1], "spec.replicas") {
+		t.Errorf("expected two schema field errors, got %s %v", category, details)
+	}
+
+	gr := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	cases := map[string]error{
+		"webhook denial":      apierrors.NewForbidden(gr, "web", fmt.Errorf(`admission webhook "validation.gatekeeper.sh" denied the request: [required-labels] missing label team`)),
+		"webhook unavailable": apierrors.NewInternalError(fmt.Errorf(`failed calling webhook "validate.kyverno.svc": connection refused`)),
+		"pod security":        apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "web", fmt.Errorf(`violates PodSecurity "restricted:latest": privileged`)),
+		"permission":          apierrors.NewForbidden(gr, "web", fmt.Errorf(`User "kube-doctor" cannot patch resource`)),
+		"missing namespace":   apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "shop"),
+		"schema":              apierrors.NewBadRequest(`.spec.template.spec.containers[0].imagePullPolicey: field not declared in schema`),
+	}
+	for want, err := range cases {
+		if got, _ := dryRunProblem(err); got != want {
+			t.Errorf("%v: got category %q, want %q", err, got, want)
+		}
+	}
+	if _, details := dryRunProblem(&apierrors.StatusError{ErrStatus: metav1.Status{Message: "plain"}}); details[0] != "plain" {
+		t.Errorf("expected the status message as the detail, got %v", details)
+	}
+}
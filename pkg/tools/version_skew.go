@@ -0,0 +1,257 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// maxMinorSkew is how many minor versions node components may trail the API
+// server. Kubernetes supports three since 1.28 (two before); kube-doctor
+// flags anything beyond two so there is headroom for the next control-plane
+// upgrade.
+const maxMinorSkew = 2
+
+// kubeProxySelector matches the kube-proxy DaemonSet pods on most distributions.
+const kubeProxySelector = "k8s-app=kube-proxy"
+
+type checkVersionSkewInput struct {
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" jsonschema:"Timeout in seconds for each Kubernetes API request in this call (default 30, max 600); lower it for speed on large clusters, raise it for deep audits"`
+}
+
+// nodeVersions are the component versions running on one node.
+type nodeVersions struct {
+	Node      string
+	Kubelet   string
+	KubeProxy string
+	Runtime   string
+}
+
+func registerVersionSkewTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "check_version_skew",
+		Description: "Compare the control-plane version with each node's kubelet and kube-proxy versions and container runtime. Flags components " +
+			"newer than the API server or more than 2 minor versions behind it (outside the version skew policy), and nodes running mixed " +
+			"container runtimes or runtime versions.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input checkVersionSkewInput) (*mcp.CallToolResult, any, error) {
+		ctx = util.WithTimeoutSeconds(ctx, input.TimeoutSeconds)
+		serverVersion, err := client.Clientset.Discovery().ServerVersion()
+		if err != nil {
+			return util.HandleK8sError("getting server version", err), nil, nil
+		}
+		nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+		if err != nil {
+			return util.HandleK8sError("listing nodes", err), nil, nil
+		}
+		// kube-proxy no longer reports its version in node status, so read it
+		// from the DaemonSet's image tags; without access, fall back to status.
+		proxyPods, _ := client.ListPods(ctx, "kube-system", metav1.ListOptions{LabelSelector: kubeProxySelector})
+		versions := collectNodeVersions(nodes, proxyPods)
+		control := serverVersion.GitVersion
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader("Version Skew"))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Control Plane", control) + "\n")
+		sb.WriteString(util.FormatKeyValue("Nodes", fmt.Sprintf("%d", len(versions))) + "\n")
+		sb.WriteString(util.FormatKeyValue("Kubelet Versions", countVersions(versions, func(v nodeVersions) string { return v.Kubelet })) + "\n")
+		sb.WriteString(util.FormatKeyValue("Runtimes", countVersions(versions, func(v nodeVersions) string { return v.Runtime })) + "\n\n")
+
+		var rows [][]string
+		for _, v := range versions {
+			rows = append(rows, []string{v.Node, v.Kubelet, valueOrNone(v.KubeProxy), v.Runtime, formatSkew(control, v.Kubelet)})
+		}
+		sb.WriteString(util.FormatTable([]string{"NODE", "KUBELET", "KUBE-PROXY", "RUNTIME", "KUBELET SKEW"}, rows))
+
+		skewFindings := versionSkewFindings(control, versions)
+		sb.WriteString("\nFINDINGS:\n")
+		if len(skewFindings) == 0 {
+			sb.WriteString(util.FormatFinding("OK", fmt.Sprintf("All node components are within %d minor versions of the control plane and run one container runtime", maxMinorSkew)))
+			sb.WriteString("\n")
+		}
+		var actions []string
+		var steps []util.NextStep
+		for _, f := range skewFindings {
+			sb.WriteString(f.String())
+			sb.WriteString("\n")
+			switch {
+			case f.ID == "KD-NODE-007":
+				actions = append(actions, "Upgrade the remaining node pools to the current node image so every node runs the same container runtime")
+			case strings.Contains(f.Message, "kube-proxy"):
+				actions = append(actions, fmt.Sprintf("Update the kube-proxy DaemonSet image to %s (managed clusters do this with the control-plane upgrade)", control))
+			default:
+				actions = append(actions, fmt.Sprintf("Upgrade the lagging node pools to %s one minor version at a time before the next control-plane upgrade", control))
+			}
+		}
+		if len(skewFindings) > 0 {
+			steps = append(steps, nextStep("analyze_node_pools", "see which pools the skewed nodes belong to"))
+			sb.WriteString("\nSUGGESTED ACTIONS:\n")
+			for i, a := range dedupe(actions) {
+				sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, a))
+			}
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// collectNodeVersions reads each node's component versions, taking
+// kube-proxy's from its pod's image tag when available.
+func collectNodeVersions(nodes []corev1.Node, proxyPods []corev1.Pod) []nodeVersions {
+	proxyByNode := make(map[string]string)
+	for i := range proxyPods {
+		p := &proxyPods[i]
+		for _, c := range p.Spec.Containers {
+			if c.Name == "kube-proxy" {
+				proxyByNode[p.Spec.NodeName] = imageTag(c.Image)
+			}
+		}
+	}
+	out := make([]nodeVersions, 0, len(nodes))
+	for i := range nodes {
+		n := &nodes[i]
+		proxy := proxyByNode[n.Name]
+		if proxy == "" {
+			proxy = n.Status.NodeInfo.KubeProxyVersion
+		}
+		out = append(out, nodeVersions{
+			Node:      n.Name,
+			Kubelet:   n.Status.NodeInfo.KubeletVersion,
+			KubeProxy: proxy,
+			Runtime:   n.Status.NodeInfo.ContainerRuntimeVersion,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Node < out[j].Node })
+	return out
+}
+
+// minorSkew returns how many minor versions component trails control; it is
+// negative when the component is newer. ok is false when either version
+// cannot be parsed or the major versions differ.
+func minorSkew(control, component string) (int, bool) {
+	cv, err := version.ParseGeneric(control)
+	if err != nil {
+		return 0, false
+	}
+	v, err := version.ParseGeneric(component)
+	if err != nil || v.Major() != cv.Major() {
+		return 0, false
+	}
+	return int(cv.Minor()) - int(v.Minor()), true
+}
+
+// formatSkew renders a component's skew for the node table.
+func formatSkew(control, component string) string {
+	skew, ok := minorSkew(control, component)
+	switch {
+	case !ok:
+		return "?"
+	case skew < 0:
+		return fmt.Sprintf("+%d (newer)", -skew)
+	case skew > maxMinorSkew:
+		return fmt.Sprintf("-%d (out of policy)", skew)
+	case skew > 0:
+		return fmt.Sprintf("-%d", skew)
+	}
+	return "0"
+}
+
+// versionSkewFindings checks kubelet and kube-proxy versions against the
+// control plane, one finding per component version, and looks for mixed
+// container runtimes.
+func versionSkewFindings(control string, nodes []nodeVersions) []findings.Finding {
+	var out []findings.Finding
+	components := []struct {
+		name    string
+		version func(nodeVersions) string
+	}{
+		{"kubelet", func(v nodeVersions) string { return v.Kubelet }},
+		{"kube-proxy", func(v nodeVersions) string { return v.KubeProxy }},
+	}
+	for _, c := range components {
+		byVersion := make(map[string][]string)
+		for _, n := range nodes {
+			if v := c.version(n); v != "" {
+				byVersion[v] = append(byVersion[v], n.Node)
+			}
+		}
+		for _, v := range sortedMapKeys(byVersion) {
+			skew, ok := minorSkew(control, v)
+			if !ok {
+				continue
+			}
+			who := fmt.Sprintf("%d node(s) run %s %s", len(byVersion[v]), c.name, v)
+			names := truncateName(strings.Join(byVersion[v], ", "), 120)
+			switch {
+			case skew < 0:
+				out = append(out, findings.New("KD-NODE-006", fmt.Sprintf("%s, newer than the control plane (%s); node components must not be newer than the API server: %s", who, control, names)))
+			case skew > maxMinorSkew:
+				out = append(out, findings.New("KD-NODE-006", fmt.Sprintf("%s, %d minor versions behind the control plane (%s), outside the supported skew: %s", who, skew, control, names)))
+			case skew == maxMinorSkew:
+				out = append(out, findings.New("KD-NODE-006", fmt.Sprintf("%s, %d minor versions behind the control plane (%s); upgrade them before the next control-plane upgrade: %s", who, skew, control, names)).WithSeverity("INFO"))
+			}
+		}
+	}
+
+	runtimes := make(map[string]map[string]int)
+	for _, n := range nodes {
+		name, ver, _ := strings.Cut(n.Runtime, "://")
+		if runtimes[name] == nil {
+			runtimes[name] = make(map[string]int)
+		}
+		runtimes[name][ver]++
+	}
+	names := sortedMapKeys(runtimes)
+	switch {
+	case len(names) > 1:
+		parts := make([]string, 0, len(names))
+		for _, name := range names {
+			count := 0
+			for _, c := range runtimes[name] {
+				count += c
+			}
+			parts = append(parts, fmt.Sprintf("%s (%d node(s))", name, count))
+		}
+		out = append(out, findings.New("KD-NODE-007", "Nodes run mixed container runtimes: "+strings.Join(parts, ", ")))
+	case len(names) == 1 && len(runtimes[names[0]]) > 1:
+		parts := make([]string, 0, len(runtimes[names[0]]))
+		for _, ver := range sortedMapKeys(runtimes[names[0]]) {
+			parts = append(parts, fmt.Sprintf("%s (%d node(s))", ver, runtimes[names[0]][ver]))
+		}
+		out = append(out, findings.New("KD-NODE-007", fmt.Sprintf("Nodes run %d %s versions: %s", len(parts), names[0], strings.Join(parts, ", "))).WithSeverity("INFO"))
+	}
+	return out
+}
+
+// countVersions summarizes how many nodes run each value, e.g. "v1.30.4 (3), v1.29.7 (1)".
+func countVersions(nodes []nodeVersions, value func(nodeVersions) string) string {
+	counts := make(map[string]int)
+	for _, n := range nodes {
+		counts[valueOrNone(value(n))]++
+	}
+	parts := make([]string, 0, len(counts))
+	for _, v := range sortedMapKeys(counts) {
+		parts = append(parts, fmt.Sprintf("%s (%d)", v, counts[v]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sortedMapKeys returns a map's keys in sorted order.
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMinorSkew(t *testing.T) {
+	tests := []struct {
+		control, component string
+		want               int
+		ok                 bool
+	}{
+		{"v1.31.2", "v1.31.0", 0, true},
+		{"v1.31.2", "v1.28.9-hotfix.20240801", 3, true},
+		{"v1.30.4-eks-a737599", "v1.31.1", -1, true},
+		{"v1.31.2", "sha256:abc", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := minorSkew(tt.control, tt.component)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("minorSkew(%q, %q) = %d, %v; want %d, %v", tt.control, tt.component, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCollectNodeVersions(t *testing.T) {
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "b"}, Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.30.4", ContainerRuntimeVersion: "containerd://1.7.15", KubeProxyVersion: "v1.30.4"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.30.4", ContainerRuntimeVersion: "containerd://1.7.15"}}},
+	}
+	proxies := []corev1.Pod{{Spec: corev1.PodSpec{NodeName: "a", Containers: []corev1.Container{{Name: "kube-proxy", Image: "registry.k8s.io/kube-proxy:v1.29.7"}}}}}
+	got := collectNodeVersions(nodes, proxies)
+	if len(got) != 2 || got[0].Node != "a" {
+		t.Fatalf("collectNodeVersions = %+v, want nodes sorted by name", got)
+	}
+	if got[0].KubeProxy != "v1.29.7" {
+		t.Errorf("node a kube-proxy = %q, want the pod image tag v1.29.7", got[0].KubeProxy)
+	}
+	if got[1].KubeProxy != "v1.30.4" {
+		t.Errorf("node b kube-proxy = %q, want the node status fallback v1.30.4", got[1].KubeProxy)
+	}
+}
+
+func TestVersionSkewFindings(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []nodeVersions
+		want  []string // "ID SEVERITY substring"
+	}{
+		{
+			name:  "in policy",
+			nodes: []nodeVersions{{Node: "a", Kubelet: "v1.31.0", KubeProxy: "v1.31.0", Runtime: "containerd://1.7.15"}, {Node: "b", Kubelet: "v1.30.4", Runtime: "containerd://1.7.15"}},
+		},
+		{
+			name:  "kubelet out of policy",
+			nodes: []nodeVersions{{Node: "a", Kubelet: "v1.27.3", Runtime: "containerd://1.7.15"}, {Node: "b", Kubelet: "v1.27.3", Runtime: "containerd://1.7.15"}},
+			want:  []string{"KD-NODE-006 CRITICAL 2 node(s) run kubelet v1.27.3, 4 minor versions behind"},
+		},
+		{
+			name:  "kube-proxy newer",
+			nodes: []nodeVersions{{Node: "a", Kubelet: "v1.31.0", KubeProxy: "v1.32.0", Runtime: "containerd://1.7.15"}},
+			want:  []string{"KD-NODE-006 CRITICAL 1 node(s) run kube-proxy v1.32.0, newer than the control plane"},
+		},
+		{
+			name:  "at the limit",
+			nodes: []nodeVersions{{Node: "a", Kubelet: "v1.29.1", Runtime: "containerd://1.7.15"}},
+			want:  []string{"KD-NODE-006 INFO upgrade them before the next control-plane upgrade"},
+		},
+		{
+			name:  "mixed runtimes",
+			nodes: []nodeVersions{{Node: "a", Kubelet: "v1.31.0", Runtime: "containerd://1.7.15"}, {Node: "b", Kubelet: "v1.31.0", Runtime: "cri-o://1.31.0"}},
+			want:  []string{"KD-NODE-007 WARNING containerd (1 node(s)), cri-o (1 node(s))"},
+		},
+		{
+			name:  "mixed runtime versions",
+			nodes: []nodeVersions{{Node: "a", Kubelet: "v1.31.0", Runtime: "containerd://1.7.15"}, {Node: "b", Kubelet: "v1.31.0", Runtime: "containerd://1.6.28"}},
+			want:  []string{"KD-NODE-007 INFO Nodes run 2 containerd versions"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := versionSkewFindings("v1.31.2", tt.nodes)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d findings, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				parts := strings.SplitN(w, " ", 3)
+				if got[i].ID != parts[0] || got[i].Severity != parts[1] || !strings.Contains(got[i].Message, parts[2]) {
+					t.Errorf("finding %d = %s %s %q, want %s", i, got[i].ID, got[i].Severity, got[i].Message, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/findings"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+const (
+	// watchEventsDefaultSeconds is the default capture window of watch_events.
+	watchEventsDefaultSeconds = 60
+	// watchEventsMaxRows caps the timeline; the summary still counts every event.
+	watchEventsMaxRows = 100
+)
+
+type watchEventsInput struct {
+	Namespace       string `json:"namespace,omitempty" jsonschema:"Namespace to watch (empty for all namespaces)"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" jsonschema:"How long to watch (default 60, max 300)"`
+	EventType       string `json:"event_type,omitempty" jsonschema:"Only capture events of this type: Normal or Warning"`
+	InvolvedKind    string `json:"involved_kind,omitempty" jsonschema:"Only capture events for this object kind (e.g. Pod, Deployment, Node)"`
+	InvolvedObject  string `json:"involved_object,omitempty" jsonschema:"Only capture events for objects with this name"`
+}
+
+func registerWatchEventsTools(server *mcp.Server, client *k8s.ClusterClient) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "watch_events",
+		Description: "Watch Kubernetes events in a namespace for a bounded window (default 60s, max 300s) and return everything that happened, " +
+			"as a timeline relative to the start of the watch plus a per-object summary. Start it, then roll out, scale, or send traffic " +
+			"(\"do X while kube-doctor watches\") to see exactly which events the action caused. Reports progress while watching.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input watchEventsInput) (*mcp.CallToolResult, any, error) {
+		seconds := input.DurationSeconds
+		if seconds <= 0 {
+			seconds = watchEventsDefaultSeconds
+		}
+		if seconds > maxEventFollowSeconds {
+			seconds = maxEventFollowSeconds
+		}
+		ns := util.NamespaceOrAll(input.Namespace)
+
+		var selectors []string
+		if input.InvolvedObject != "" {
+			selectors = append(selectors, "involvedObject.name="+input.InvolvedObject)
+		}
+		if input.EventType != "" {
+			selectors = append(selectors, "type="+input.EventType)
+		}
+		opts := metav1.ListOptions{FieldSelector: strings.Join(selectors, ",")}
+		filter := k8s.EventFilter{Kind: input.InvolvedKind}
+
+		// Report progress through the window; FollowEvents blocks until it ends.
+		progress := util.NewProgress(req)
+		window := time.Duration(seconds) * time.Second
+		start := time.Now()
+		done := make(chan struct{})
+		go func() {
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					elapsed := time.Since(start).Round(time.Second)
+					progress.Report(ctx, elapsed.Seconds(), window.Seconds(), fmt.Sprintf("watching events for %s of %s", elapsed, window))
+				}
+			}
+		}()
+		events, err := client.FollowEvents(ctx, ns, opts, filter, window)
+		close(done)
+		if err != nil {
+			return util.HandleK8sError("watching events", err), nil, nil
+		}
+		watched := time.Since(start).Round(time.Second)
+		progress.Report(ctx, window.Seconds(), window.Seconds(), "watch finished")
+
+		var sb strings.Builder
+		sb.WriteString(util.FormatHeader(fmt.Sprintf("Event Watch (namespace: %s)", displayNS(input.Namespace))))
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatKeyValue("Window", fmt.Sprintf("%s from %s", watched, start.UTC().Format(time.RFC3339))) + "\n")
+		sb.WriteString(util.FormatKeyValue("Events", fmt.Sprintf("%d", len(events))) + "\n")
+
+		if len(events) == 0 {
+			sb.WriteString("\nNo events occurred during the window.\n")
+			if watched < window {
+				sb.WriteString("  (the watch ended early because the request was cancelled)\n")
+			}
+			return util.SuccessResult(sb.String()), nil, nil
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("TIMELINE"))
+		sb.WriteString("\n")
+		rows := watchEventRows(events, start)
+		if len(rows) > watchEventsMaxRows {
+			sb.WriteString(fmt.Sprintf("  (showing the last %d of %d events)\n", watchEventsMaxRows, len(rows)))
+			rows = rows[len(rows)-watchEventsMaxRows:]
+		}
+		sb.WriteString(util.FormatTable([]string{"AT", "TYPE", "REASON", "OBJECT", "MESSAGE", "COUNT"}, rows))
+
+		objects := summarizeWatchedEvents(events)
+		sb.WriteString("\n")
+		sb.WriteString(util.FormatSubHeader("BY OBJECT"))
+		sb.WriteString("\n")
+		var objectRows [][]string
+		for _, o := range objects {
+			objectRows = append(objectRows, []string{o.Object, fmt.Sprintf("%d", o.Normal), fmt.Sprintf("%d", o.Warning), strings.Join(o.Reasons, ", ")})
+		}
+		sb.WriteString(util.FormatTable([]string{"OBJECT", "NORMAL", "WARNING", "REASONS"}, objectRows))
+
+		var findingLines []string
+		var steps []util.NextStep
+		for _, o := range objects {
+			if o.Warning == 0 {
+				continue
+			}
+			findingLines = append(findingLines, findings.Format("KD-EVT-001", "WARNING", fmt.Sprintf("%s had %d Warning event(s) during the window: %s", o.Object, o.Warning, o.LastWarning)))
+			if step, ok := watchEventNextStep(o); ok && len(steps) < 3 {
+				steps = append(steps, step)
+			}
+		}
+		sb.WriteString("\nFINDINGS:\n")
+		if len(findingLines) == 0 {
+			sb.WriteString(util.FormatFinding("OK", "Only Normal events occurred during the window"))
+			sb.WriteString("\n")
+		}
+		for _, f := range findingLines {
+			sb.WriteString(f)
+			sb.WriteString("\n")
+		}
+		if len(findingLines) > 0 {
+			steps = append(steps, nextStep("analyze_events", "group the namespace's recent warnings by root cause", "namespace", ns))
+		}
+
+		return util.WithNextSteps(util.SuccessResult(sb.String()), steps), nil, nil
+	})
+}
+
+// watchEventRows renders events in arrival order, timed relative to start.
+func watchEventRows(events []corev1.Event, start time.Time) [][]string {
+	rows := make([][]string, 0, len(events))
+	for i := range events {
+		e := &events[i]
+		at := k8s.EventTime(e).Sub(start)
+		if at < 0 {
+			at = 0
+		}
+		rows = append(rows, []string{
+			"+" + at.Round(time.Second).String(),
+			e.Type,
+			e.Reason,
+			watchedObject(e),
+			truncateName(e.Message, 80),
+			fmt.Sprintf("%d", max(e.Count, 1)),
+		})
+	}
+	return rows
+}
+
+// watchedObject names an event's involved object as namespace/kind/name.
+func watchedObject(e *corev1.Event) string {
+	obj := fmt.Sprintf("%s/%s", strings.ToLower(e.InvolvedObject.Kind), e.InvolvedObject.Name)
+	if e.InvolvedObject.Namespace != "" {
+		obj = e.InvolvedObject.Namespace + "/" + obj
+	}
+	return obj
+}
+
+// watchedObjectSummary counts the events one object received during a watch.
+type watchedObjectSummary struct {
+	Object      string
+	Kind        string
+	Namespace   string
+	Name        string
+	Normal      int
+	Warning     int
+	Reasons     []string
+	LastWarning string
+}
+
+// summarizeWatchedEvents groups events by involved object, objects with the
+// most warnings first.
+func summarizeWatchedEvents(events []corev1.Event) []watchedObjectSummary {
+	byObject := make(map[string]*watchedObjectSummary)
+	var order []string
+	for i := range events {
+		e := &events[i]
+		key := watchedObject(e)
+		s, ok := byObject[key]
+		if !ok {
+			s = &watchedObjectSummary{Object: key, Kind: e.InvolvedObject.Kind, Namespace: e.InvolvedObject.Namespace, Name: e.InvolvedObject.Name}
+			byObject[key] = s
+			order = append(order, key)
+		}
+		if e.Type == corev1.EventTypeWarning {
+			s.Warning++
+			s.LastWarning = fmt.Sprintf("%s: %s", e.Reason, truncateName(e.Message, 120))
+		} else {
+			s.Normal++
+		}
+		if !slices.Contains(s.Reasons, e.Reason) {
+			s.Reasons = append(s.Reasons, e.Reason)
+		}
+	}
+
+	out := make([]watchedObjectSummary, 0, len(order))
+	for _, key := range order {
+		out = append(out, *byObject[key])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Warning > out[j].Warning })
+	return out
+}
+
+// watchEventNextStep suggests a diagnosis for an object that received warnings.
+func watchEventNextStep(o watchedObjectSummary) (util.NextStep, bool) {
+	switch o.Kind {
+	case "Pod":
+		return nextStep("diagnose_pod", fmt.Sprintf("%s received warnings during the watch", o.Object), "namespace", o.Namespace, "name", o.Name), true
+	case "Node":
+		return nextStep("diagnose_node", fmt.Sprintf("%s received warnings during the watch", o.Object), "name", o.Name), true
+	}
+	return util.NextStep{}, false
+}
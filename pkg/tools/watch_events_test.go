@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWatchEventSummary(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := func(kind, name, typ, reason string, after time.Duration) corev1.Event {
+		return corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Namespace: "shop", Name: name},
+			Type:           typ, Reason: reason, Message: reason + " happened",
+			LastTimestamp: metav1.NewTime(start.Add(after)),
+		}
+	}
+	events := []corev1.Event{
+		event("Deployment", "web", "Normal", "ScalingReplicaSet", 2*time.Second),
+		event("Pod", "web-1", "Normal", "Scheduled", 3*time.Second),
+		event("Pod", "web-1", "Warning", "Unhealthy", 20*time.Second),
+		event("Pod", "web-1", "Warning", "BackOff", 31*time.Second),
+	}
+
+	rows := watchEventRows(events, start)
+	if len(rows) != 4 || rows[0][0] != "+2s" || rows[3][0] != "+31s" || rows[3][3] != "shop/pod/web-1" {
+		t.Errorf("watchEventRows() = %v", rows)
+	}
+
+	objects := summarizeWatchedEvents(events)
+	if len(objects) != 2 {
+		t.Fatalf("summarizeWatchedEvents() returned %d objects, want 2", len(objects))
+	}
+	pod := objects[0]
+	if pod.Object != "shop/pod/web-1" || pod.Normal != 1 || pod.Warning != 2 || pod.LastWarning != "BackOff: BackOff happened" {
+		t.Errorf("pod summary = %+v, want it first with 2 warnings", pod)
+	}
+	if step, ok := watchEventNextStep(pod); !ok || step.Tool != "diagnose_pod" {
+		t.Errorf("watchEventNextStep(pod) = %+v, %v", step, ok)
+	}
+	if _, ok := watchEventNextStep(objects[1]); ok {
+		t.Error("expected no next step for a Deployment")
+	}
+}
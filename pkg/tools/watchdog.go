@@ -0,0 +1,167 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/notify"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/util"
+)
+
+// criticalWaitingReasons are container waiting reasons a sweep reports as CRITICAL.
+var criticalWaitingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"CreateContainerConfigError": true,
+	"InvalidImageName":           true,
+}
+
+// RunWatchdog sweeps the cluster every interval until ctx is cancelled and
+// sends CRITICAL findings that were not reported by a previous sweep to the notifier.
+func RunWatchdog(ctx context.Context, client *k8s.ClusterClient, interval time.Duration, notifier notify.Notifier) {
+	cluster := client.ContextName
+	if cluster == "" {
+		cluster = "current-context"
+	}
+	dedup := notify.NewDeduper()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		findings, err := sweepCluster(ctx, client)
+		if err != nil {
+			log.Printf("watchdog: sweep failed: %v", err)
+		} else {
+			var critical []notify.Finding
+			for _, f := range findings {
+				if f.Severity == "CRITICAL" {
+					critical = append(critical, f)
+				}
+			}
+			if fresh := dedup.Filter(critical); len(fresh) > 0 {
+				if err := notifier.Notify(ctx, cluster, fresh); err != nil {
+					log.Printf("watchdog: notification failed: %v", err)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweepCluster runs the background health checks: node readiness, failing
+// containers, services with no endpoints, and Deployments with no available
+// replicas.
+func sweepCluster(ctx context.Context, client *k8s.ClusterClient) ([]notify.Finding, error) {
+	var findings []notify.Finding
+
+	// Node checks are skipped in namespace-scoped mode.
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+	if err != nil && !util.IsScopeError(err) {
+		return nil, err
+	}
+	for i := range nodes {
+		n := &nodes[i]
+		if status := nodeStatus(n); status != "Ready" {
+			findings = append(findings, notify.Finding{
+				Severity:    "CRITICAL",
+				Fingerprint: "node-notready/" + n.Name,
+				Message:     fmt.Sprintf("Node '%s' is %s", n.Name, status),
+			})
+		}
+		for _, cond := range n.Status.Conditions {
+			if (cond.Type == corev1.NodeMemoryPressure || cond.Type == corev1.NodeDiskPressure || cond.Type == corev1.NodePIDPressure) && cond.Status == corev1.ConditionTrue {
+				findings = append(findings, notify.Finding{
+					Severity:    "WARNING",
+					Fingerprint: fmt.Sprintf("node-pressure/%s/%s", n.Name, cond.Type),
+					Message:     fmt.Sprintf("Node '%s' has %s", n.Name, cond.Type),
+				})
+			}
+		}
+	}
+
+	pods, err := client.ListPods(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, sweepPodFindings(pods)...)
+
+	services, err := client.ListServices(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range services {
+		if svc.Spec.Type == corev1.ServiceTypeExternalName || len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		health, err := client.GetServiceEndpointHealth(ctx, svc.Namespace, svc.Name)
+		if err != nil || health.TotalEndpoints > 0 {
+			continue
+		}
+		findings = append(findings, notify.Finding{
+			Severity:    "CRITICAL",
+			Fingerprint: fmt.Sprintf("service-no-endpoints/%s/%s", svc.Namespace, svc.Name),
+			Message:     fmt.Sprintf("Service %s/%s has 0 endpoints", svc.Namespace, svc.Name),
+		})
+	}
+
+	deployments, err := client.ListDeployments(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deployments {
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		if desired == 0 || d.Status.AvailableReplicas > 0 {
+			continue
+		}
+		findings = append(findings, notify.Finding{
+			Severity:    "CRITICAL",
+			Fingerprint: fmt.Sprintf("deployment-unavailable/%s/%s", d.Namespace, d.Name),
+			Message:     fmt.Sprintf("Deployment %s/%s has 0 of %d replicas available", d.Namespace, d.Name, desired),
+		})
+	}
+
+	return findings, nil
+}
+
+// sweepPodFindings reports containers stuck in a critical waiting state or
+// last killed by the OOM killer. Fingerprints use the owning workload so
+// replacement pods do not trigger new notifications.
+func sweepPodFindings(pods []corev1.Pod) []notify.Finding {
+	var findings []notify.Finding
+	for i := range pods {
+		p := &pods[i]
+		workload := podWorkloadName(p)
+		for _, cs := range p.Status.ContainerStatuses {
+			reason := ""
+			if cs.State.Waiting != nil && criticalWaitingReasons[cs.State.Waiting.Reason] {
+				reason = cs.State.Waiting.Reason
+			} else if cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.Reason == "OOMKilled" && !cs.Ready {
+				reason = "OOMKilled"
+			}
+			if reason == "" {
+				continue
+			}
+			findings = append(findings, notify.Finding{
+				Severity:    "CRITICAL",
+				Fingerprint: fmt.Sprintf("container/%s/%s/%s/%s", p.Namespace, workload, cs.Name, reason),
+				Message:     fmt.Sprintf("Container '%s' in pod %s/%s is %s", cs.Name, p.Namespace, p.Name, reason),
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/k8s"
+	"github.com/pat-nel87/kube-doctor-mcp/pkg/notify"
+)
+
+type recordingNotifier struct {
+	mu    sync.Mutex
+	calls [][]notify.Finding
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, cluster string, findings []notify.Finding) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, findings)
+	return nil
+}
+
+func TestSweepPodFindings(t *testing.T) {
+	crashing := func(name string) corev1.Pod {
+		p := withOwner(testPod("default", name, "", map[string]string{"pod-template-hash": "abc"}), "ReplicaSet", "web-abc")
+		p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  "app",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}
+		return p
+	}
+
+	findings := sweepPodFindings([]corev1.Pod{crashing("web-abc-1"), crashing("web-abc-2")})
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+	if findings[0].Fingerprint != findings[1].Fingerprint {
+		t.Errorf("expected pods of the same workload to share a fingerprint, got %q and %q", findings[0].Fingerprint, findings[1].Fingerprint)
+	}
+	if findings[0].Severity != "CRITICAL" {
+		t.Errorf("expected CRITICAL, got %s", findings[0].Severity)
+	}
+}
+
+func TestRunWatchdogNotifiesOnce(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionFalse},
+		}},
+	})
+	client := k8s.NewClusterClientForTesting(fakeClient, nil)
+	notifier := &recordingNotifier{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	RunWatchdog(ctx, client, 10*time.Millisecond, notifier)
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	if len(notifier.calls) != 1 {
+		t.Fatalf("expected 1 notification across sweeps, got %d", len(notifier.calls))
+	}
+	if got := notifier.calls[0]; len(got) != 1 || got[0].Fingerprint != "node-notready/n1" {
+		t.Errorf("unexpected findings %+v", got)
+	}
+}
@@ -15,6 +15,8 @@ import (
 type listDeploymentsInput struct {
 	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
 	LabelSelector string `json:"label_selector,omitempty" jsonschema:"Label selector filter (e.g. app=nginx)"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type getDeploymentDetailInput struct {
@@ -25,16 +27,22 @@ type getDeploymentDetailInput struct {
 type listStatefulSetsInput struct {
 	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
 	LabelSelector string `json:"label_selector,omitempty" jsonschema:"Label selector filter"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type listDaemonSetsInput struct {
 	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
 	LabelSelector string `json:"label_selector,omitempty" jsonschema:"Label selector filter"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 type listJobsInput struct {
 	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (use 'all' for all namespaces)"`
 	LabelSelector string `json:"label_selector,omitempty" jsonschema:"Label selector filter"`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum items per page (default 200, max 1000)"`
+	ContinueToken string `json:"continue_token,omitempty" jsonschema:"continue_token from a truncated previous response, to fetch the next page"`
 }
 
 func registerWorkloadTools(server *mcp.Server, client *k8s.ClusterClient) {
@@ -46,10 +54,11 @@ func registerWorkloadTools(server *mcp.Server, client *k8s.ClusterClient) {
 		ns := util.NamespaceOrAll(input.Namespace)
 		opts := util.ListOptions(input.LabelSelector, "")
 
-		deployments, err := client.ListDeployments(ctx, ns, opts)
+		page, err := client.ListDeploymentsPage(ctx, ns, util.PageOptions(opts, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing deployments", err), nil, nil
 		}
+		deployments := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "READY", "UP-TO-DATE", "AVAILABLE", "AGE", "STRATEGY"}
 		rows := make([][]string, 0, len(deployments))
@@ -78,6 +87,7 @@ func registerWorkloadTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("deployments", len(deployments))))
+		sb.WriteString(pageFooter("list_deployments", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -184,10 +194,11 @@ func registerWorkloadTools(server *mcp.Server, client *k8s.ClusterClient) {
 		ns := util.NamespaceOrAll(input.Namespace)
 		opts := util.ListOptions(input.LabelSelector, "")
 
-		sets, err := client.ListStatefulSets(ctx, ns, opts)
+		page, err := client.ListStatefulSetsPage(ctx, ns, util.PageOptions(opts, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing statefulsets", err), nil, nil
 		}
+		sets := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "READY", "AGE"}
 		rows := make([][]string, 0, len(sets))
@@ -209,6 +220,7 @@ func registerWorkloadTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("statefulsets", len(sets))))
+		sb.WriteString(pageFooter("list_statefulsets", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -221,10 +233,11 @@ func registerWorkloadTools(server *mcp.Server, client *k8s.ClusterClient) {
 		ns := util.NamespaceOrAll(input.Namespace)
 		opts := util.ListOptions(input.LabelSelector, "")
 
-		sets, err := client.ListDaemonSets(ctx, ns, opts)
+		page, err := client.ListDaemonSetsPage(ctx, ns, util.PageOptions(opts, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing daemonsets", err), nil, nil
 		}
+		sets := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "DESIRED", "READY", "UP-TO-DATE", "AVAILABLE", "AGE"}
 		rows := make([][]string, 0, len(sets))
@@ -245,6 +258,7 @@ func registerWorkloadTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("daemonsets", len(sets))))
+		sb.WriteString(pageFooter("list_daemonsets", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
@@ -257,10 +271,11 @@ func registerWorkloadTools(server *mcp.Server, client *k8s.ClusterClient) {
 		ns := util.NamespaceOrAll(input.Namespace)
 		opts := util.ListOptions(input.LabelSelector, "")
 
-		jobs, err := client.ListJobs(ctx, ns, opts)
+		page, err := client.ListJobsPage(ctx, ns, util.PageOptions(opts, input.Limit, input.ContinueToken))
 		if err != nil {
 			return util.HandleK8sError("listing jobs", err), nil, nil
 		}
+		jobs := page.Items
 
 		headers := []string{"NAME", "NAMESPACE", "COMPLETIONS", "ACTIVE", "SUCCEEDED", "FAILED", "AGE"}
 		rows := make([][]string, 0, len(jobs))
@@ -285,6 +300,7 @@ func registerWorkloadTools(server *mcp.Server, client *k8s.ClusterClient) {
 		sb.WriteString("\n")
 		sb.WriteString(util.FormatTable(headers, rows))
 		sb.WriteString(fmt.Sprintf("\n%s\n", util.FormatCount("jobs", len(jobs))))
+		sb.WriteString(pageFooter("list_jobs", page.Continue, page.Remaining))
 
 		return util.SuccessResult(sb.String()), nil, nil
 	})
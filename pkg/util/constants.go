@@ -6,15 +6,33 @@ const (
 	// DefaultTimeout is the default timeout for Kubernetes API calls.
 	DefaultTimeout = 30 * time.Second
 
+	// MaxTimeout caps the per-call timeout_seconds override of DefaultTimeout.
+	MaxTimeout = 10 * time.Minute
+
 	// MaxLogBytes is the maximum size of pod logs to return (50KB).
 	MaxLogBytes = 50 * 1024
 
+	// DefaultLogFollowWindow is how long log tailing tools follow a stream by default.
+	DefaultLogFollowWindow = 15 * time.Second
+
+	// MaxLogFollowWindow caps the follow window of log tailing tools.
+	MaxLogFollowWindow = 2 * time.Minute
+
+	// MaxLogFollowBytes caps the byte budget of log tailing tools (256KB).
+	MaxLogFollowBytes = 256 * 1024
+
 	// MaxEvents is the maximum number of events to return.
 	MaxEvents = 50
 
 	// MaxPods is the maximum number of pods to return in a list.
 	MaxPods = 200
 
+	// DefaultPageSize is the default number of items per page in list tools.
+	DefaultPageSize int64 = 200
+
+	// MaxPageSize caps the limit input of list tools.
+	MaxPageSize int64 = 1000
+
 	// DefaultTailLines is the default number of log lines to tail.
 	DefaultTailLines int64 = 100
 
@@ -35,4 +53,8 @@ const (
 
 	// MaxFluxResources is the maximum number of Flux resources to return in a list.
 	MaxFluxResources = 200
+
+	// DiagramPodCollapseThreshold is the number of pods per workload above which
+	// healthy pods are collapsed into a single node in Mermaid diagrams.
+	DiagramPodCollapseThreshold = 5
 )
@@ -1,7 +1,10 @@
 package util
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -26,8 +29,34 @@ func SuccessResult(text string) *mcp.CallToolResult {
 	}
 }
 
+// ScopeError is returned for cluster-scoped queries when kube-doctor runs in
+// namespace-scoped mode and has no access outside specific namespaces.
+type ScopeError struct {
+	Resource   string
+	Namespaces []string
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("%s are cluster-scoped and not available in namespace-scoped mode (namespaces: %s)", e.Resource, strings.Join(e.Namespaces, ", "))
+}
+
+// IsScopeError reports whether err is a ScopeError.
+func IsScopeError(err error) bool {
+	var se *ScopeError
+	return errors.As(err, &se)
+}
+
 // HandleK8sError converts a Kubernetes API error into a user-friendly MCP error result.
 func HandleK8sError(action string, err error) *mcp.CallToolResult {
+	if IsScopeError(err) {
+		return ErrorResult("Skipped %s: %v. Use a namespaced tool instead.", action, err)
+	}
+	if errors.Is(err, context.Canceled) {
+		return ErrorResult("Cancelled: %s. The client aborted the call.", action)
+	}
+	if apierrors.IsResourceExpired(err) {
+		return ErrorResult("Continue token expired: %s. List again without continue_token to start from the first page.", action)
+	}
 	if apierrors.IsNotFound(err) {
 		return ErrorResult("Not found: %s", action)
 	}
@@ -35,7 +64,7 @@ func HandleK8sError(action string, err error) *mcp.CallToolResult {
 		return ErrorResult("Permission denied: %s. Check RBAC permissions.", action)
 	}
 	if apierrors.IsUnauthorized(err) {
-		return ErrorResult("Unauthorized: %s. Check cluster credentials.", action)
+		return ErrorResult("Unauthorized: %s. Credentials were rejected even after a refresh; check cluster credentials (for AKS with Azure AD, run `az login` and confirm kubelogin is installed).", action)
 	}
 	if apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) {
 		return ErrorResult("Timeout: %s. The cluster may be unreachable.", action)
@@ -1,6 +1,10 @@
 package util
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // ListOptions builds metav1.ListOptions from label and field selectors.
 func ListOptions(labelSelector, fieldSelector string) metav1.ListOptions {
@@ -14,6 +18,20 @@ func ListOptions(labelSelector, fieldSelector string) metav1.ListOptions {
 	return opts
 }
 
+// PageOptions sets the page size and continue token on opts. A limit of zero or
+// less uses DefaultPageSize; larger limits are capped at MaxPageSize.
+func PageOptions(opts metav1.ListOptions, limit int64, continueToken string) metav1.ListOptions {
+	switch {
+	case limit <= 0:
+		limit = DefaultPageSize
+	case limit > MaxPageSize:
+		limit = MaxPageSize
+	}
+	opts.Limit = limit
+	opts.Continue = strings.TrimSpace(continueToken)
+	return opts
+}
+
 // NamespaceOrAll returns the namespace or empty string for all namespaces.
 func NamespaceOrAll(ns string) string {
 	if ns == "all" || ns == "*" || ns == "" {
@@ -21,3 +39,14 @@ func NamespaceOrAll(ns string) string {
 	}
 	return ns
 }
+
+// SplitList splits a comma-separated list, trimming whitespace and dropping empty entries.
+func SplitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
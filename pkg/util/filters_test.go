@@ -35,3 +35,26 @@ func TestListOptions(t *testing.T) {
 		t.Error("empty selectors should produce empty options")
 	}
 }
+
+func TestPageOptions(t *testing.T) {
+	opts := PageOptions(ListOptions("app=nginx", ""), 0, " tok ")
+	if opts.Limit != DefaultPageSize || opts.Continue != "tok" || opts.LabelSelector != "app=nginx" {
+		t.Errorf("PageOptions() = %+v, want default limit, trimmed token, and the label selector kept", opts)
+	}
+	if got := PageOptions(ListOptions("", ""), 5000, "").Limit; got != MaxPageSize {
+		t.Errorf("Limit = %d, want %d", got, MaxPageSize)
+	}
+	if got := PageOptions(ListOptions("", ""), 50, "").Limit; got != 50 {
+		t.Errorf("Limit = %d, want 50", got)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := SplitList(" team-a, ,team-b,")
+	if len(got) != 2 || got[0] != "team-a" || got[1] != "team-b" {
+		t.Errorf("SplitList() = %q, want [team-a team-b]", got)
+	}
+	if got := SplitList(""); got != nil {
+		t.Errorf("SplitList(\"\") = %q, want nil", got)
+	}
+}
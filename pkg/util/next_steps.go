@@ -0,0 +1,69 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MaxNextSteps caps the follow-up tool calls attached to one result.
+const MaxNextSteps = 10
+
+// NextStep is a follow-up tool call recommended by a diagnostic result, so
+// agents can chain investigations without guessing tool names or arguments.
+type NextStep struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Reason    string         `json:"reason,omitempty"`
+}
+
+// String renders the step as a tool call, e.g. diagnose_pod {"name":"web","namespace":"shop"}.
+func (s NextStep) String() string {
+	if len(s.Arguments) == 0 {
+		return s.Tool
+	}
+	args, _ := json.Marshal(s.Arguments)
+	return s.Tool + " " + string(args)
+}
+
+// WithNextSteps attaches next steps to a result as structured content of the
+// form {"next_steps": [...]} and appends a NEXT STEPS section to its text.
+// Duplicate calls are dropped and at most MaxNextSteps are kept. The
+// next_steps field is always present, empty when no follow-up is needed.
+func WithNextSteps(res *mcp.CallToolResult, steps []NextStep) *mcp.CallToolResult {
+	kept := make([]NextStep, 0, len(steps))
+	seen := make(map[string]bool)
+	for _, s := range steps {
+		key := s.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, s)
+		if len(kept) == MaxNextSteps {
+			break
+		}
+	}
+	res.StructuredContent = map[string]any{"next_steps": kept}
+	if len(kept) == 0 || len(res.Content) == 0 {
+		return res
+	}
+	text, ok := res.Content[0].(*mcp.TextContent)
+	if !ok {
+		return res
+	}
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(text.Text, "\n"))
+	sb.WriteString("\n\nNEXT STEPS:\n")
+	for _, s := range kept {
+		sb.WriteString(fmt.Sprintf("  - %s", s))
+		if s.Reason != "" {
+			sb.WriteString(" — " + s.Reason)
+		}
+		sb.WriteString("\n")
+	}
+	text.Text = sb.String()
+	return res
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWithNextSteps(t *testing.T) {
+	pod := NextStep{Tool: "diagnose_pod", Arguments: map[string]any{"namespace": "shop", "name": "web"}, Reason: "CrashLoopBackOff"}
+	res := WithNextSteps(SuccessResult("=== Report ===\n"), []NextStep{pod, pod, {Tool: "triage"}})
+
+	structured, ok := res.StructuredContent.(map[string]any)
+	if !ok {
+		t.Fatalf("expected structured content map, got %T", res.StructuredContent)
+	}
+	steps := structured["next_steps"].([]NextStep)
+	if len(steps) != 2 {
+		t.Errorf("expected duplicate step to be dropped, got %v", steps)
+	}
+	text := res.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, `NEXT STEPS:`) || !strings.Contains(text, `diagnose_pod {"name":"web","namespace":"shop"} — CrashLoopBackOff`) {
+		t.Errorf("expected NEXT STEPS section with the tool call, got:\n%s", text)
+	}
+
+	res = WithNextSteps(SuccessResult("healthy"), nil)
+	if steps := res.StructuredContent.(map[string]any)["next_steps"].([]NextStep); steps == nil || len(steps) != 0 {
+		t.Errorf("expected an empty, non-nil next_steps list, got %v", steps)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; text != "healthy" {
+		t.Errorf("expected text to be unchanged without steps, got %q", text)
+	}
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// progressInterval is the minimum time between progress notifications for
+// one call, so per-item loops over thousands of objects do not flood the client.
+const progressInterval = 250 * time.Millisecond
+
+// Progress sends MCP progress notifications for a tool call whose client
+// supplied a progress token. Methods on a nil Progress do nothing, so tools
+// report unconditionally.
+type Progress struct {
+	session *mcp.ServerSession
+	token   any
+
+	mu   sync.Mutex
+	last float64
+	sent time.Time
+}
+
+// NewProgress returns a Progress for the call, or nil when the client did not
+// ask for progress.
+func NewProgress(req *mcp.CallToolRequest) *Progress {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
+	return &Progress{session: req.Session, token: token}
+}
+
+// Report notifies the client that done of total units of work are finished.
+// Progress never moves backwards, and intermediate updates are throttled;
+// the first and final (done >= total) updates are always sent.
+func (p *Progress) Report(ctx context.Context, done, total float64, message string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	if done < p.last || (!p.sent.IsZero() && done < total && time.Since(p.sent) < progressInterval) {
+		p.mu.Unlock()
+		return
+	}
+	p.last, p.sent = done, time.Now()
+	p.mu.Unlock()
+
+	_ = p.session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+		ProgressToken: p.token,
+		Progress:      done,
+		Total:         total,
+		Message:       message,
+	})
+}
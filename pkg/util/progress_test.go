@@ -0,0 +1,76 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProgress(t *testing.T) {
+	var nilProgress *Progress
+	nilProgress.Report(context.Background(), 1, 2, "no-op")
+	if NewProgress(&mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{}}) != nil {
+		t.Error("expected no Progress without a session and progress token")
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "test"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "scan"}, func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+		progress := NewProgress(req)
+		for i := range 100 {
+			progress.Report(ctx, float64(i), 100, "scanning")
+		}
+		progress.Report(ctx, 50, 100, "backwards")
+		progress.Report(ctx, 100, 100, "done")
+		return SuccessResult("ok"), nil, nil
+	})
+
+	ctx := context.Background()
+	t1, t2 := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, t1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer serverSession.Close()
+
+	var mu sync.Mutex
+	var got []*mcp.ProgressNotificationParams
+	done := make(chan struct{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "test"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, req.Params)
+			if req.Params.Progress == req.Params.Total {
+				close(done)
+			}
+		},
+	})
+	clientSession, err := client.Connect(ctx, t2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientSession.Close()
+
+	params := &mcp.CallToolParams{Meta: mcp.Meta{"progressToken": "tok-1"}, Name: "scan", Arguments: map[string]any{}}
+	if _, err := clientSession.CallTool(ctx, params); err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no final progress update")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The tight loop is throttled to its first update; the final one always goes out.
+	if len(got) != 2 || got[0].Progress != 0 || got[1].Message != "done" || got[1].ProgressToken != "tok-1" {
+		for _, p := range got {
+			t.Logf("progress %v/%v %q", p.Progress, p.Total, p.Message)
+		}
+		t.Errorf("expected the first and final progress updates, got %d", len(got))
+	}
+}
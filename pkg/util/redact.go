@@ -0,0 +1,62 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"sync/atomic"
+)
+
+// Redacted replaces a value hidden by the redaction policy.
+const Redacted = "<redacted>"
+
+// lastAppliedAnnotation holds an object's last applied manifest verbatim,
+// including any env values and ConfigMap data it set.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// SensitiveName matches names of env vars, annotations, ConfigMap keys, and
+// values keys whose values look like credentials. auth and key only count as
+// the last word of a name, so AUTHOR and auth-type are not hidden.
+var SensitiveName = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private_?key|credential|authorization|(^|[_.\-/])(auth|key)$)`)
+
+// sensitiveValue matches values that are credentials whatever their name:
+// PEM private keys, JWTs, and URLs with an embedded password.
+var sensitiveValue = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----|\beyJ[\w-]{10,}\.eyJ[\w-]{10,}\.[\w-]+|://[^/\s:@]+:[^/\s@]+@`)
+
+// redactionDisabled turns the redaction policy off (--no-redact).
+var redactionDisabled atomic.Bool
+
+// SetRedaction enables or disables the output redaction policy. It is on by
+// default; turn it off only when tool output stays with trusted users.
+func SetRedaction(enabled bool) {
+	redactionDisabled.Store(!enabled)
+}
+
+// RedactionEnabled reports whether tool output is redacted.
+func RedactionEnabled() bool {
+	return !redactionDisabled.Load()
+}
+
+// IsSensitive reports whether the value stored under name, or the value
+// itself, must be redacted under the current policy.
+func IsSensitive(name, value string) bool {
+	return RedactionEnabled() && (SensitiveName.MatchString(name) || sensitiveValue.MatchString(value))
+}
+
+// RedactValue returns value, or Redacted when an env var, ConfigMap key, or
+// other setting called name holds a credential.
+func RedactValue(name, value string) string {
+	if IsSensitive(name, value) {
+		return Redacted
+	}
+	return value
+}
+
+// RedactAnnotation returns an annotation value for display. Besides the
+// credential checks of RedactValue, the last-applied-configuration
+// annotation is reduced to its size since it repeats the whole manifest.
+func RedactAnnotation(key, value string) string {
+	if key == lastAppliedAnnotation && RedactionEnabled() {
+		return fmt.Sprintf("<redacted, %d chars>", len(value))
+	}
+	return RedactValue(key, value)
+}
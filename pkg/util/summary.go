@@ -0,0 +1,195 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ExecutiveSummaryLines caps the length of a detail_level=summary report.
+const ExecutiveSummaryLines = 10
+
+// DetailLevel selects how much of a report a tool returns.
+type DetailLevel string
+
+const (
+	// DetailSummary is a short executive summary for chat: status, top findings, next action.
+	DetailSummary DetailLevel = "summary"
+	// DetailStandard is the report without Mermaid diagrams.
+	DetailStandard DetailLevel = "standard"
+	// DetailFull is the complete report, including diagrams.
+	DetailFull DetailLevel = "full"
+)
+
+// ParseDetailLevel validates a detail_level input. Empty means DetailFull.
+func ParseDetailLevel(s string) (DetailLevel, error) {
+	switch level := DetailLevel(strings.ToLower(strings.TrimSpace(s))); level {
+	case "":
+		return DetailFull, nil
+	case DetailSummary, DetailStandard, DetailFull:
+		return level, nil
+	}
+	return "", fmt.Errorf("detail_level must be summary, standard, or full (got %q)", s)
+}
+
+// ApplyDetailLevel reduces a full report to the requested level.
+func ApplyDetailLevel(report string, level DetailLevel) string {
+	switch level {
+	case DetailSummary:
+		return ExecutiveSummary(report)
+	case DetailStandard:
+		return StripDiagrams(report)
+	}
+	return report
+}
+
+// findingLineRegexp matches a severity-tagged finding line produced by FormatFinding.
+var findingLineRegexp = regexp.MustCompile(`^\s*\[(CRITICAL|WARNING|INFO|OK)\]\s`)
+
+// actionLineRegexp matches a numbered suggested action.
+var actionLineRegexp = regexp.MustCompile(`^\s*\d+\.\s+(.*)$`)
+
+// reportDigest is what a summary keeps from a full report.
+type reportDigest struct {
+	header   string
+	counts   map[string]int
+	findings []string // CRITICAL and WARNING, critical first, deduplicated
+	actions  []string
+}
+
+func (d reportDigest) status() string {
+	status := "OK"
+	if d.counts["CRITICAL"] > 0 {
+		status = "CRITICAL"
+	} else if d.counts["WARNING"] > 0 {
+		status = "WARNING"
+	}
+	return fmt.Sprintf("STATUS: %s (%d critical, %d warning, %d info)",
+		status, d.counts["CRITICAL"], d.counts["WARNING"], d.counts["INFO"])
+}
+
+// digestReport extracts the header, finding counts, findings, and suggested
+// actions from a report, skipping Mermaid diagrams.
+func digestReport(report string) reportDigest {
+	lines := strings.Split(report, "\n")
+	d := reportDigest{counts: map[string]int{}}
+	var findings []string
+	seen := make(map[string]bool)
+
+	inDiagram := false
+	inActions := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inDiagram = !inDiagram
+			continue
+		}
+		if inDiagram {
+			continue
+		}
+
+		if d.header == "" && i == 0 && strings.HasPrefix(trimmed, "=== ") {
+			d.header = trimmed
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "--- ") && strings.HasSuffix(trimmed, " ---") {
+			inActions = false
+			continue
+		}
+
+		if strings.HasSuffix(trimmed, ":") && strings.ToUpper(trimmed) == trimmed {
+			inActions = strings.Contains(trimmed, "ACTIONS")
+			continue
+		}
+		if inActions {
+			if m := actionLineRegexp.FindStringSubmatch(line); m != nil {
+				d.actions = append(d.actions, m[1])
+			}
+		}
+
+		if m := findingLineRegexp.FindStringSubmatch(line); m != nil {
+			d.counts[m[1]]++
+			if (m[1] == "CRITICAL" || m[1] == "WARNING") && !seen[trimmed] {
+				seen[trimmed] = true
+				findings = append(findings, trimmed)
+			}
+		}
+	}
+
+	// Critical findings first
+	for _, f := range findings {
+		if strings.HasPrefix(f, "[CRITICAL]") {
+			d.findings = append(d.findings, f)
+		}
+	}
+	for _, f := range findings {
+		if !strings.HasPrefix(f, "[CRITICAL]") {
+			d.findings = append(d.findings, f)
+		}
+	}
+	return d
+}
+
+// ExecutiveSummary reduces a report to at most ExecutiveSummaryLines lines:
+// the header, a severity count line, the most severe findings, and the first
+// suggested action.
+func ExecutiveSummary(report string) string {
+	d := digestReport(report)
+
+	var lines []string
+	if d.header != "" {
+		lines = append(lines, d.header)
+	}
+	lines = append(lines, d.status())
+	budget := ExecutiveSummaryLines - len(lines)
+	if len(d.actions) > 0 {
+		budget--
+	}
+	for i, f := range d.findings {
+		if i == budget-1 && len(d.findings) > budget {
+			lines = append(lines, fmt.Sprintf("... and %d more findings", len(d.findings)-i))
+			break
+		}
+		lines = append(lines, f)
+	}
+	if len(d.actions) > 0 {
+		lines = append(lines, "NEXT: "+d.actions[0])
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// StripDiagrams removes fenced Mermaid blocks and the label line introducing
+// each one (e.g. "TOPOLOGY:").
+func StripDiagrams(report string) string {
+	lines := strings.Split(report, "\n")
+	out := make([]string, 0, len(lines))
+	inDiagram := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			if !inDiagram {
+				// Drop the diagram's label and the blank lines around it.
+				for len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+					out = out[:len(out)-1]
+				}
+				if n := len(out); n > 0 {
+					if last := strings.TrimSpace(out[n-1]); strings.HasSuffix(last, ":") && strings.ToUpper(last) == last {
+						out = out[:n-1]
+					}
+				}
+			}
+			inDiagram = !inDiagram
+			continue
+		}
+		if inDiagram {
+			continue
+		}
+		if trimmed == "" && len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
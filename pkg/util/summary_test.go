@@ -0,0 +1,82 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseDetailLevel(t *testing.T) {
+	for in, want := range map[string]DetailLevel{"": DetailFull, "summary": DetailSummary, " Standard ": DetailStandard, "FULL": DetailFull} {
+		if got, err := ParseDetailLevel(in); err != nil || got != want {
+			t.Errorf("ParseDetailLevel(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseDetailLevel("verbose"); err == nil {
+		t.Error("expected error for an unknown detail level")
+	}
+}
+
+func TestExecutiveSummary(t *testing.T) {
+	lines := []string{FormatHeader("Request Path: https://shop.example.com/"), ""}
+	for i := 0; i < 12; i++ {
+		lines = append(lines, FormatFinding("WARNING", fmt.Sprintf("Pod web-%d has restarted", i)))
+	}
+	lines = append(lines, FormatFinding("CRITICAL", "Service web has no ready endpoints"), "",
+		"SUGGESTED ACTIONS:", "1. Fix the readiness probe of web", "2. Check the ingress class")
+
+	got := ExecutiveSummary(strings.Join(lines, "\n"))
+	out := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(out) != ExecutiveSummaryLines {
+		t.Fatalf("ExecutiveSummary() has %d lines, want %d:\n%s", len(out), ExecutiveSummaryLines, got)
+	}
+	if out[1] != "STATUS: CRITICAL (1 critical, 12 warning, 0 info)" || !strings.HasPrefix(out[2], "[CRITICAL]") {
+		t.Errorf("expected status then the critical finding first:\n%s", got)
+	}
+	if out[8] != "... and 7 more findings" || out[9] != "NEXT: Fix the readiness probe of web" {
+		t.Errorf("expected an overflow line and the first action:\n%s", got)
+	}
+}
+
+func TestExecutiveSummarySkipsDiagramsAndDuplicates(t *testing.T) {
+	got := ExecutiveSummary(strings.Join([]string{
+		FormatHeader("Cluster Health Overview"),
+		FormatFinding("CRITICAL", "Node 'node-2' is NotReady"),
+		"    " + FormatFinding("WARNING", "Pod 'web' has 7 restarts"),
+		FormatFinding("CRITICAL", "Node 'node-2' is NotReady"),
+		"",
+		"CLUSTER TOPOLOGY:",
+		"```mermaid",
+		"  n1[\"[CRITICAL] inside diagram\"]",
+		"```",
+	}, "\n"))
+	if !strings.Contains(got, "STATUS: CRITICAL (2 critical, 1 warning, 0 info)") || strings.Count(got, "node-2") != 1 {
+		t.Errorf("expected one node-2 finding and counts that skip the diagram:\n%s", got)
+	}
+	if strings.Contains(got, "inside diagram") {
+		t.Errorf("summary should not include diagram content:\n%s", got)
+	}
+}
+
+func TestStripDiagrams(t *testing.T) {
+	report := strings.Join([]string{
+		FormatHeader("Service Diagnosis: web"),
+		FormatFinding("WARNING", "Pod web-1 has restarted"),
+		"",
+		"SERVICE CONTEXT:",
+		"```mermaid",
+		"graph LR",
+		"```",
+		"",
+		"SUGGESTED ACTIONS:",
+		"1. Check web-1",
+	}, "\n")
+
+	got := StripDiagrams(report)
+	if strings.Contains(got, "mermaid") || strings.Contains(got, "SERVICE CONTEXT") {
+		t.Errorf("diagram not removed:\n%s", got)
+	}
+	if !strings.Contains(got, "has restarted\n\nSUGGESTED ACTIONS:\n1. Check web-1") {
+		t.Errorf("surrounding report not preserved:\n%s", got)
+	}
+}
@@ -0,0 +1,31 @@
+package util
+
+import (
+	"context"
+	"time"
+)
+
+// timeoutKey is the context key for a per-call API timeout override.
+type timeoutKey struct{}
+
+// WithTimeoutSeconds returns a context whose Kubernetes API calls use the
+// given timeout instead of DefaultTimeout. Non-positive values keep the
+// default and values above MaxTimeout are capped.
+func WithTimeoutSeconds(ctx context.Context, seconds int) context.Context {
+	if seconds <= 0 {
+		return ctx
+	}
+	d := time.Duration(seconds) * time.Second
+	if d > MaxTimeout {
+		d = MaxTimeout
+	}
+	return context.WithValue(ctx, timeoutKey{}, d)
+}
+
+// Timeout returns the Kubernetes API call timeout for ctx.
+func Timeout(ctx context.Context) time.Duration {
+	if d, ok := ctx.Value(timeoutKey{}).(time.Duration); ok {
+		return d
+	}
+	return DefaultTimeout
+}
@@ -0,0 +1,26 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		seconds int
+		want    time.Duration
+	}{
+		{0, DefaultTimeout},
+		{-5, DefaultTimeout},
+		{5, 5 * time.Second},
+		{120, 2 * time.Minute},
+		{100000, MaxTimeout},
+	}
+	for _, tt := range tests {
+		if got := Timeout(WithTimeoutSeconds(ctx, tt.seconds)); got != tt.want {
+			t.Errorf("Timeout(WithTimeoutSeconds(%d)) = %v, want %v", tt.seconds, got, tt.want)
+		}
+	}
+}